@@ -31,6 +31,14 @@ const (
 	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT       PaymentResultStatus = 2
 	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS PaymentResultStatus = 3
 	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_INTERNAL         PaymentResultStatus = 4
+	// Declined by fraud screening before any funds were moved.
+	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED PaymentResultStatus = 5
+	// Above the single payment limit of the account type.
+	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED PaymentResultStatus = 6
+	// Declined by the payment method, or the method is not supported.
+	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED PaymentResultStatus = 7
+	// The user did not confirm the payment (PaymentChallengeRequired) in time.
+	PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED PaymentResultStatus = 8
 )
 
 // Enum value maps for PaymentResultStatus.
@@ -41,13 +49,21 @@ var (
 		2: "PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT",
 		3: "PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS",
 		4: "PAYMENT_RESULT_STATUS_FAIL_INTERNAL",
+		5: "PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED",
+		6: "PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED",
+		7: "PAYMENT_RESULT_STATUS_FAIL_DECLINED",
+		8: "PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED",
 	}
 	PaymentResultStatus_value = map[string]int32{
-		"PAYMENT_RESULT_STATUS_UNSPECIFIED":           0,
-		"PAYMENT_RESULT_STATUS_SUCCESS":               1,
-		"PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT":       2,
-		"PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS": 3,
-		"PAYMENT_RESULT_STATUS_FAIL_INTERNAL":         4,
+		"PAYMENT_RESULT_STATUS_UNSPECIFIED":            0,
+		"PAYMENT_RESULT_STATUS_SUCCESS":                1,
+		"PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT":        2,
+		"PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS":  3,
+		"PAYMENT_RESULT_STATUS_FAIL_INTERNAL":          4,
+		"PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED":   5,
+		"PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED":    6,
+		"PAYMENT_RESULT_STATUS_FAIL_DECLINED":          7,
+		"PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED": 8,
 	}
 )
 
@@ -78,15 +94,79 @@ func (PaymentResultStatus) EnumDescriptor() ([]byte, []int) {
 	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{0}
 }
 
+type DisputeStatus int32
+
+const (
+	DisputeStatus_DISPUTE_STATUS_UNSPECIFIED DisputeStatus = 0
+	// The payments of the order are frozen until the dispute is resolved.
+	DisputeStatus_DISPUTE_STATUS_OPEN DisputeStatus = 1
+	// Resolved for the payer: amount went back to their balance.
+	DisputeStatus_DISPUTE_STATUS_REFUNDED DisputeStatus = 2
+	// Resolved for the merchant: the payments stand.
+	DisputeStatus_DISPUTE_STATUS_UPHELD DisputeStatus = 3
+)
+
+// Enum value maps for DisputeStatus.
+var (
+	DisputeStatus_name = map[int32]string{
+		0: "DISPUTE_STATUS_UNSPECIFIED",
+		1: "DISPUTE_STATUS_OPEN",
+		2: "DISPUTE_STATUS_REFUNDED",
+		3: "DISPUTE_STATUS_UPHELD",
+	}
+	DisputeStatus_value = map[string]int32{
+		"DISPUTE_STATUS_UNSPECIFIED": 0,
+		"DISPUTE_STATUS_OPEN":        1,
+		"DISPUTE_STATUS_REFUNDED":    2,
+		"DISPUTE_STATUS_UPHELD":      3,
+	}
+)
+
+func (x DisputeStatus) Enum() *DisputeStatus {
+	p := new(DisputeStatus)
+	*p = x
+	return p
+}
+
+func (x DisputeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_events_v1_payments_events_proto_enumTypes[1].Descriptor()
+}
+
+func (DisputeStatus) Type() protoreflect.EnumType {
+	return &file_events_v1_payments_events_proto_enumTypes[1]
+}
+
+func (x DisputeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeStatus.Descriptor instead.
+func (DisputeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{1}
+}
+
 // Sent by Orders -> consumed by Payments
 type PaymentRequested struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
 	// For idempotency/inbox: unique id for this event (uuid/ulid).
-	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
-	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
-	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId     string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount     int64                  `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Identifies a single installment of the order. Empty for the order's
+	// initial full payment, which is keyed by order_id.
+	PaymentId string `protobuf:"bytes,6,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	// Request id of the API call that caused the payment (X-Request-Id at the
+	// gateway); empty for payments no request started.
+	CorrelationId string `protobuf:"bytes,7,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Provider that pays what bonus grants do not cover, e.g. "card"; empty
+	// means the internal balance.
+	PaymentMethod string `protobuf:"bytes,8,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -156,6 +236,27 @@ func (x *PaymentRequested) GetAmount() int64 {
 	return 0
 }
 
+func (x *PaymentRequested) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentRequested) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *PaymentRequested) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
 type PaymentResult struct {
 	state      protoimpl.MessageState `protogen:"open.v1"`
 	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
@@ -164,7 +265,14 @@ type PaymentResult struct {
 	UserId     string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Status     PaymentResultStatus    `protobuf:"varint,5,opt,name=status,proto3,enum=events.v1.PaymentResultStatus" json:"status,omitempty"`
 	// Optional: debug/human-readable reason
-	Reason        string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	Reason string `protobuf:"bytes,6,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Echoed from PaymentRequested.
+	PaymentId string `protobuf:"bytes,7,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount    int64  `protobuf:"varint,8,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Fee charged on top of amount by the account type's policy.
+	Fee int64 `protobuf:"varint,9,opt,name=fee,proto3" json:"fee,omitempty"`
+	// Echoed from PaymentRequested.
+	CorrelationId string `protobuf:"bytes,10,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -241,18 +349,633 @@ func (x *PaymentResult) GetReason() string {
 	return ""
 }
 
+func (x *PaymentResult) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentResult) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentResult) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *PaymentResult) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+// Sent by Payments when the payment method needs the user to confirm a
+// payment before charging it (3-D Secure and the like). The payment is
+// completed by PaymentsService.ConfirmPayment; one not confirmed by
+// expires_at fails with FAIL_CHALLENGE_EXPIRED. Either way the outcome is a
+// PaymentResult.
+type PaymentChallengeRequired struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId     string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Echoed from PaymentRequested.
+	PaymentId     string                 `protobuf:"bytes,5,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount        int64                  `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaymentMethod string                 `protobuf:"bytes,7,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Echoed from PaymentRequested.
+	CorrelationId string `protobuf:"bytes,9,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentChallengeRequired) Reset() {
+	*x = PaymentChallengeRequired{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentChallengeRequired) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentChallengeRequired) ProtoMessage() {}
+
+func (x *PaymentChallengeRequired) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentChallengeRequired.ProtoReflect.Descriptor instead.
+func (*PaymentChallengeRequired) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *PaymentChallengeRequired) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *PaymentChallengeRequired) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *PaymentChallengeRequired) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentChallengeRequired) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PaymentChallengeRequired) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *PaymentChallengeRequired) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentChallengeRequired) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+func (x *PaymentChallengeRequired) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *PaymentChallengeRequired) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+// Sent by Payments -> consumed by Orders
+type AccountCreated struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	UserId        string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountCreated) Reset() {
+	*x = AccountCreated{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountCreated) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountCreated) ProtoMessage() {}
+
+func (x *AccountCreated) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountCreated.ProtoReflect.Descriptor instead.
+func (*AccountCreated) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AccountCreated) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *AccountCreated) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *AccountCreated) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// Sent by Orders when an accepted transfer moves an order to another user.
+// Transfers wait for in-flight payments, so every PaymentRequested of the
+// order after this event carries to_user_id.
+type OrderTransferred struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventId       string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId       string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	FromUserId    string                 `protobuf:"bytes,4,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToUserId      string                 `protobuf:"bytes,5,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	TransferId    string                 `protobuf:"bytes,6,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderTransferred) Reset() {
+	*x = OrderTransferred{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderTransferred) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderTransferred) ProtoMessage() {}
+
+func (x *OrderTransferred) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderTransferred.ProtoReflect.Descriptor instead.
+func (*OrderTransferred) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderTransferred) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderTransferred) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *OrderTransferred) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderTransferred) GetFromUserId() string {
+	if x != nil {
+		return x.FromUserId
+	}
+	return ""
+}
+
+func (x *OrderTransferred) GetToUserId() string {
+	if x != nil {
+		return x.ToUserId
+	}
+	return ""
+}
+
+func (x *OrderTransferred) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+// Sent by Orders whenever the status of an order changes: by a payment
+// result or by an operator. Statuses are the names stored by orders-service,
+// e.g. "PARTIALLY_PAID".
+type OrderStatusChanged struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Owner of the order at the time of the change.
+	UserId         string `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PreviousStatus string `protobuf:"bytes,5,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	Status         string `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Amount         int64  `protobuf:"varint,7,opt,name=amount,proto3" json:"amount,omitempty"`
+	PaidAmount     int64  `protobuf:"varint,8,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	// Failure reason of a cancelled order; empty otherwise.
+	Reason string `protobuf:"bytes,9,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Request id of the API call behind the change, if any.
+	CorrelationId string `protobuf:"bytes,10,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderStatusChanged) Reset() {
+	*x = OrderStatusChanged{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChanged) ProtoMessage() {}
+
+func (x *OrderStatusChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChanged.ProtoReflect.Descriptor instead.
+func (*OrderStatusChanged) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *OrderStatusChanged) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *OrderStatusChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderStatusChanged) GetPaidAmount() int64 {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return 0
+}
+
+func (x *OrderStatusChanged) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusChanged) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+// Sent by Orders after any change of an order: creation, an edit, a status
+// change, a payment or a transfer. It only names the order; consumers read
+// the order's current state.
+type OrderChanged struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Owner of the order after the change.
+	UserId string `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Set when the change moved the order away from this user.
+	PreviousUserId string `protobuf:"bytes,5,opt,name=previous_user_id,json=previousUserId,proto3" json:"previous_user_id,omitempty"`
+	// Request id of the API call behind the change, if any.
+	CorrelationId string `protobuf:"bytes,6,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderChanged) Reset() {
+	*x = OrderChanged{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderChanged) ProtoMessage() {}
+
+func (x *OrderChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderChanged.ProtoReflect.Descriptor instead.
+func (*OrderChanged) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *OrderChanged) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderChanged) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *OrderChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderChanged) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *OrderChanged) GetPreviousUserId() string {
+	if x != nil {
+		return x.PreviousUserId
+	}
+	return ""
+}
+
+func (x *OrderChanged) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+// Sent by Payments when an operator opens or resolves a dispute (a
+// chargeback) of an order's payments -> consumed by Orders, which moves the
+// order to DISPUTED and then to REFUNDED or back to FINISHED.
+type PaymentDisputeChanged struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	EventId    string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	OccurredAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	OrderId    string                 `protobuf:"bytes,3,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// The user whose payments are disputed.
+	UserId string        `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status DisputeStatus `protobuf:"varint,5,opt,name=status,proto3,enum=events.v1.DisputeStatus" json:"status,omitempty"`
+	// What the payer paid for the order, fees included and bonus spent on it
+	// not; credited to their balance when the dispute resolves as REFUNDED.
+	Amount int64 `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	// The operator's reason for opening the dispute.
+	Reason string `protobuf:"bytes,7,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Request id of the admin call behind the change.
+	CorrelationId string `protobuf:"bytes,8,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentDisputeChanged) Reset() {
+	*x = PaymentDisputeChanged{}
+	mi := &file_events_v1_payments_events_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentDisputeChanged) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentDisputeChanged) ProtoMessage() {}
+
+func (x *PaymentDisputeChanged) ProtoReflect() protoreflect.Message {
+	mi := &file_events_v1_payments_events_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentDisputeChanged.ProtoReflect.Descriptor instead.
+func (*PaymentDisputeChanged) Descriptor() ([]byte, []int) {
+	return file_events_v1_payments_events_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PaymentDisputeChanged) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *PaymentDisputeChanged) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+func (x *PaymentDisputeChanged) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PaymentDisputeChanged) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PaymentDisputeChanged) GetStatus() DisputeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+}
+
+func (x *PaymentDisputeChanged) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PaymentDisputeChanged) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *PaymentDisputeChanged) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
 var File_events_v1_payments_events_proto protoreflect.FileDescriptor
 
 const file_events_v1_payments_events_proto_rawDesc = "" +
 	"\n" +
-	"\x1fevents/v1/payments_events.proto\x12\tevents.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb6\x01\n" +
+	"\x1fevents/v1/payments_events.proto\x12\tevents.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa3\x02\n" +
 	"\x10PaymentRequested\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
 	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
 	"occurredAt\x12\x19\n" +
 	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x16\n" +
-	"\x06amount\x18\x05 \x01(\x03R\x06amount\"\xeb\x01\n" +
+	"\x06amount\x18\x05 \x01(\x03R\x06amount\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x06 \x01(\tR\tpaymentId\x12%\n" +
+	"\x0ecorrelation_id\x18\a \x01(\tR\rcorrelationId\x12%\n" +
+	"\x0epayment_method\x18\b \x01(\tR\rpaymentMethod\"\xdb\x02\n" +
 	"\rPaymentResult\x12\x19\n" +
 	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
 	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
@@ -260,13 +983,89 @@ const file_events_v1_payments_events_proto_rawDesc = "" +
 	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x04 \x01(\tR\x06userId\x126\n" +
 	"\x06status\x18\x05 \x01(\x0e2\x1e.events.v1.PaymentResultStatusR\x06status\x12\x16\n" +
-	"\x06reason\x18\x06 \x01(\tR\x06reason*\xe4\x01\n" +
+	"\x06reason\x18\x06 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\a \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\b \x01(\x03R\x06amount\x12\x10\n" +
+	"\x03fee\x18\t \x01(\x03R\x03fee\x12%\n" +
+	"\x0ecorrelation_id\x18\n" +
+	" \x01(\tR\rcorrelationId\"\xe6\x02\n" +
+	"\x18PaymentChallengeRequired\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x05 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12%\n" +
+	"\x0epayment_method\x18\a \x01(\tR\rpaymentMethod\x129\n" +
+	"\n" +
+	"expires_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12%\n" +
+	"\x0ecorrelation_id\x18\t \x01(\tR\rcorrelationId\"\x81\x01\n" +
+	"\x0eAccountCreated\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\"\xe6\x01\n" +
+	"\x10OrderTransferred\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12 \n" +
+	"\ffrom_user_id\x18\x04 \x01(\tR\n" +
+	"fromUserId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x05 \x01(\tR\btoUserId\x12\x1f\n" +
+	"\vtransfer_id\x18\x06 \x01(\tR\n" +
+	"transferId\"\xd9\x02\n" +
+	"\x12OrderStatusChanged\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12'\n" +
+	"\x0fprevious_status\x18\x05 \x01(\tR\x0epreviousStatus\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x16\n" +
+	"\x06amount\x18\a \x01(\x03R\x06amount\x12\x1f\n" +
+	"\vpaid_amount\x18\b \x01(\x03R\n" +
+	"paidAmount\x12\x16\n" +
+	"\x06reason\x18\t \x01(\tR\x06reason\x12%\n" +
+	"\x0ecorrelation_id\x18\n" +
+	" \x01(\tR\rcorrelationId\"\xeb\x01\n" +
+	"\fOrderChanged\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12(\n" +
+	"\x10previous_user_id\x18\x05 \x01(\tR\x0epreviousUserId\x12%\n" +
+	"\x0ecorrelation_id\x18\x06 \x01(\tR\rcorrelationId\"\xac\x02\n" +
+	"\x15PaymentDisputeChanged\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12;\n" +
+	"\voccurred_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\x12\x19\n" +
+	"\border_id\x18\x03 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x120\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x18.events.v1.DisputeStatusR\x06status\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06reason\x18\a \x01(\tR\x06reason\x12%\n" +
+	"\x0ecorrelation_id\x18\b \x01(\tR\rcorrelationId*\x9e\x03\n" +
 	"\x13PaymentResultStatus\x12%\n" +
 	"!PAYMENT_RESULT_STATUS_UNSPECIFIED\x10\x00\x12!\n" +
 	"\x1dPAYMENT_RESULT_STATUS_SUCCESS\x10\x01\x12)\n" +
 	"%PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT\x10\x02\x12/\n" +
 	"+PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS\x10\x03\x12'\n" +
-	"#PAYMENT_RESULT_STATUS_FAIL_INTERNAL\x10\x04BBZ@github.com/ilyaytrewq/payments-service/gen/go/events/v1;eventsv1b\x06proto3"
+	"#PAYMENT_RESULT_STATUS_FAIL_INTERNAL\x10\x04\x12.\n" +
+	"*PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED\x10\x05\x12-\n" +
+	")PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED\x10\x06\x12'\n" +
+	"#PAYMENT_RESULT_STATUS_FAIL_DECLINED\x10\a\x120\n" +
+	",PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED\x10\b*\x80\x01\n" +
+	"\rDisputeStatus\x12\x1e\n" +
+	"\x1aDISPUTE_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DISPUTE_STATUS_OPEN\x10\x01\x12\x1b\n" +
+	"\x17DISPUTE_STATUS_REFUNDED\x10\x02\x12\x19\n" +
+	"\x15DISPUTE_STATUS_UPHELD\x10\x03BBZ@github.com/ilyaytrewq/payments-service/gen/go/events/v1;eventsv1b\x06proto3"
 
 var (
 	file_events_v1_payments_events_proto_rawDescOnce sync.Once
@@ -280,23 +1079,38 @@ func file_events_v1_payments_events_proto_rawDescGZIP() []byte {
 	return file_events_v1_payments_events_proto_rawDescData
 }
 
-var file_events_v1_payments_events_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_events_v1_payments_events_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_events_v1_payments_events_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_events_v1_payments_events_proto_msgTypes = make([]protoimpl.MessageInfo, 8)
 var file_events_v1_payments_events_proto_goTypes = []any{
-	(PaymentResultStatus)(0),      // 0: events.v1.PaymentResultStatus
-	(*PaymentRequested)(nil),      // 1: events.v1.PaymentRequested
-	(*PaymentResult)(nil),         // 2: events.v1.PaymentResult
-	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+	(PaymentResultStatus)(0),         // 0: events.v1.PaymentResultStatus
+	(DisputeStatus)(0),               // 1: events.v1.DisputeStatus
+	(*PaymentRequested)(nil),         // 2: events.v1.PaymentRequested
+	(*PaymentResult)(nil),            // 3: events.v1.PaymentResult
+	(*PaymentChallengeRequired)(nil), // 4: events.v1.PaymentChallengeRequired
+	(*AccountCreated)(nil),           // 5: events.v1.AccountCreated
+	(*OrderTransferred)(nil),         // 6: events.v1.OrderTransferred
+	(*OrderStatusChanged)(nil),       // 7: events.v1.OrderStatusChanged
+	(*OrderChanged)(nil),             // 8: events.v1.OrderChanged
+	(*PaymentDisputeChanged)(nil),    // 9: events.v1.PaymentDisputeChanged
+	(*timestamppb.Timestamp)(nil),    // 10: google.protobuf.Timestamp
 }
 var file_events_v1_payments_events_proto_depIdxs = []int32{
-	3, // 0: events.v1.PaymentRequested.occurred_at:type_name -> google.protobuf.Timestamp
-	3, // 1: events.v1.PaymentResult.occurred_at:type_name -> google.protobuf.Timestamp
-	0, // 2: events.v1.PaymentResult.status:type_name -> events.v1.PaymentResultStatus
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	10, // 0: events.v1.PaymentRequested.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 1: events.v1.PaymentResult.occurred_at:type_name -> google.protobuf.Timestamp
+	0,  // 2: events.v1.PaymentResult.status:type_name -> events.v1.PaymentResultStatus
+	10, // 3: events.v1.PaymentChallengeRequired.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 4: events.v1.PaymentChallengeRequired.expires_at:type_name -> google.protobuf.Timestamp
+	10, // 5: events.v1.AccountCreated.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 6: events.v1.OrderTransferred.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 7: events.v1.OrderStatusChanged.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 8: events.v1.OrderChanged.occurred_at:type_name -> google.protobuf.Timestamp
+	10, // 9: events.v1.PaymentDisputeChanged.occurred_at:type_name -> google.protobuf.Timestamp
+	1,  // 10: events.v1.PaymentDisputeChanged.status:type_name -> events.v1.DisputeStatus
+	11, // [11:11] is the sub-list for method output_type
+	11, // [11:11] is the sub-list for method input_type
+	11, // [11:11] is the sub-list for extension type_name
+	11, // [11:11] is the sub-list for extension extendee
+	0,  // [0:11] is the sub-list for field type_name
 }
 
 func init() { file_events_v1_payments_events_proto_init() }
@@ -309,8 +1123,8 @@ func file_events_v1_payments_events_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_events_v1_payments_events_proto_rawDesc), len(file_events_v1_payments_events_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   2,
+			NumEnums:      2,
+			NumMessages:   8,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
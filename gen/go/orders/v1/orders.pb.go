@@ -7,8 +7,11 @@
 package ordersv1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -25,10 +28,17 @@ const (
 type OrderStatus int32
 
 const (
-	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
-	OrderStatus_ORDER_STATUS_NEW         OrderStatus = 1
-	OrderStatus_ORDER_STATUS_FINISHED    OrderStatus = 2
-	OrderStatus_ORDER_STATUS_CANCELLED   OrderStatus = 3
+	OrderStatus_ORDER_STATUS_UNSPECIFIED    OrderStatus = 0
+	OrderStatus_ORDER_STATUS_NEW            OrderStatus = 1
+	OrderStatus_ORDER_STATUS_FINISHED       OrderStatus = 2
+	OrderStatus_ORDER_STATUS_CANCELLED      OrderStatus = 3
+	OrderStatus_ORDER_STATUS_PARTIALLY_PAID OrderStatus = 4
+	// Created with pay_at; becomes NEW when its payment is requested.
+	OrderStatus_ORDER_STATUS_SCHEDULED OrderStatus = 5
+	// A payment of the finished order is disputed; see Order.dispute.
+	OrderStatus_ORDER_STATUS_DISPUTED OrderStatus = 6
+	// The dispute was resolved with a refund to the payer.
+	OrderStatus_ORDER_STATUS_REFUNDED OrderStatus = 7
 )
 
 // Enum value maps for OrderStatus.
@@ -38,12 +48,20 @@ var (
 		1: "ORDER_STATUS_NEW",
 		2: "ORDER_STATUS_FINISHED",
 		3: "ORDER_STATUS_CANCELLED",
+		4: "ORDER_STATUS_PARTIALLY_PAID",
+		5: "ORDER_STATUS_SCHEDULED",
+		6: "ORDER_STATUS_DISPUTED",
+		7: "ORDER_STATUS_REFUNDED",
 	}
 	OrderStatus_value = map[string]int32{
-		"ORDER_STATUS_UNSPECIFIED": 0,
-		"ORDER_STATUS_NEW":         1,
-		"ORDER_STATUS_FINISHED":    2,
-		"ORDER_STATUS_CANCELLED":   3,
+		"ORDER_STATUS_UNSPECIFIED":    0,
+		"ORDER_STATUS_NEW":            1,
+		"ORDER_STATUS_FINISHED":       2,
+		"ORDER_STATUS_CANCELLED":      3,
+		"ORDER_STATUS_PARTIALLY_PAID": 4,
+		"ORDER_STATUS_SCHEDULED":      5,
+		"ORDER_STATUS_DISPUTED":       6,
+		"ORDER_STATUS_REFUNDED":       7,
 	}
 )
 
@@ -74,33 +92,4476 @@ func (OrderStatus) EnumDescriptor() ([]byte, []int) {
 	return file_orders_v1_orders_proto_rawDescGZIP(), []int{0}
 }
 
+type DisputeStatus int32
+
+const (
+	DisputeStatus_DISPUTE_STATUS_UNSPECIFIED DisputeStatus = 0
+	DisputeStatus_DISPUTE_STATUS_OPEN        DisputeStatus = 1
+	DisputeStatus_DISPUTE_STATUS_REFUNDED    DisputeStatus = 2
+	DisputeStatus_DISPUTE_STATUS_UPHELD      DisputeStatus = 3
+)
+
+// Enum value maps for DisputeStatus.
+var (
+	DisputeStatus_name = map[int32]string{
+		0: "DISPUTE_STATUS_UNSPECIFIED",
+		1: "DISPUTE_STATUS_OPEN",
+		2: "DISPUTE_STATUS_REFUNDED",
+		3: "DISPUTE_STATUS_UPHELD",
+	}
+	DisputeStatus_value = map[string]int32{
+		"DISPUTE_STATUS_UNSPECIFIED": 0,
+		"DISPUTE_STATUS_OPEN":        1,
+		"DISPUTE_STATUS_REFUNDED":    2,
+		"DISPUTE_STATUS_UPHELD":      3,
+	}
+)
+
+func (x DisputeStatus) Enum() *DisputeStatus {
+	p := new(DisputeStatus)
+	*p = x
+	return p
+}
+
+func (x DisputeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_orders_v1_orders_proto_enumTypes[1].Descriptor()
+}
+
+func (DisputeStatus) Type() protoreflect.EnumType {
+	return &file_orders_v1_orders_proto_enumTypes[1]
+}
+
+func (x DisputeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeStatus.Descriptor instead.
+func (DisputeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{1}
+}
+
+type OrderTransferStatus int32
+
+const (
+	OrderTransferStatus_ORDER_TRANSFER_STATUS_UNSPECIFIED OrderTransferStatus = 0
+	OrderTransferStatus_ORDER_TRANSFER_STATUS_PENDING     OrderTransferStatus = 1
+	OrderTransferStatus_ORDER_TRANSFER_STATUS_ACCEPTED    OrderTransferStatus = 2
+	// Replaced by a newer offer for the same order.
+	OrderTransferStatus_ORDER_TRANSFER_STATUS_CANCELLED OrderTransferStatus = 3
+)
+
+// Enum value maps for OrderTransferStatus.
+var (
+	OrderTransferStatus_name = map[int32]string{
+		0: "ORDER_TRANSFER_STATUS_UNSPECIFIED",
+		1: "ORDER_TRANSFER_STATUS_PENDING",
+		2: "ORDER_TRANSFER_STATUS_ACCEPTED",
+		3: "ORDER_TRANSFER_STATUS_CANCELLED",
+	}
+	OrderTransferStatus_value = map[string]int32{
+		"ORDER_TRANSFER_STATUS_UNSPECIFIED": 0,
+		"ORDER_TRANSFER_STATUS_PENDING":     1,
+		"ORDER_TRANSFER_STATUS_ACCEPTED":    2,
+		"ORDER_TRANSFER_STATUS_CANCELLED":   3,
+	}
+)
+
+func (x OrderTransferStatus) Enum() *OrderTransferStatus {
+	p := new(OrderTransferStatus)
+	*p = x
+	return p
+}
+
+func (x OrderTransferStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderTransferStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_orders_v1_orders_proto_enumTypes[2].Descriptor()
+}
+
+func (OrderTransferStatus) Type() protoreflect.EnumType {
+	return &file_orders_v1_orders_proto_enumTypes[2]
+}
+
+func (x OrderTransferStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderTransferStatus.Descriptor instead.
+func (OrderTransferStatus) EnumDescriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{2}
+}
+
+// Which outbox events ListOutbox returns.
+type OutboxState int32
+
+const (
+	OutboxState_OUTBOX_STATE_UNSPECIFIED OutboxState = 0
+	// Not published yet: waiting for the publisher or backing off after
+	// failed publishes; dead-lettered events excluded.
+	OutboxState_OUTBOX_STATE_UNSENT OutboxState = 1
+	// Backing off after at least one failed publish; last_error tells why.
+	OutboxState_OUTBOX_STATE_FAILED OutboxState = 2
+	// Dead-lettered after OUTBOX_MAX_ATTEMPTS failed publishes.
+	OutboxState_OUTBOX_STATE_DEAD OutboxState = 3
+)
+
+// Enum value maps for OutboxState.
+var (
+	OutboxState_name = map[int32]string{
+		0: "OUTBOX_STATE_UNSPECIFIED",
+		1: "OUTBOX_STATE_UNSENT",
+		2: "OUTBOX_STATE_FAILED",
+		3: "OUTBOX_STATE_DEAD",
+	}
+	OutboxState_value = map[string]int32{
+		"OUTBOX_STATE_UNSPECIFIED": 0,
+		"OUTBOX_STATE_UNSENT":      1,
+		"OUTBOX_STATE_FAILED":      2,
+		"OUTBOX_STATE_DEAD":        3,
+	}
+)
+
+func (x OutboxState) Enum() *OutboxState {
+	p := new(OutboxState)
+	*p = x
+	return p
+}
+
+func (x OutboxState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutboxState) Descriptor() protoreflect.EnumDescriptor {
+	return file_orders_v1_orders_proto_enumTypes[3].Descriptor()
+}
+
+func (OutboxState) Type() protoreflect.EnumType {
+	return &file_orders_v1_orders_proto_enumTypes[3]
+}
+
+func (x OutboxState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutboxState.Descriptor instead.
+func (OutboxState) EnumDescriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{3}
+}
+
+type ProjectionReplayStatus int32
+
+const (
+	ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_UNSPECIFIED ProjectionReplayStatus = 0
+	ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_RUNNING     ProjectionReplayStatus = 1
+	// Stopped with StopProjectionReplay.
+	ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_STOPPED ProjectionReplayStatus = 2
+	// Read the topic up to its end offsets.
+	ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_COMPLETED ProjectionReplayStatus = 3
+	// Gave up on an error; see error.
+	ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_FAILED ProjectionReplayStatus = 4
+)
+
+// Enum value maps for ProjectionReplayStatus.
+var (
+	ProjectionReplayStatus_name = map[int32]string{
+		0: "PROJECTION_REPLAY_STATUS_UNSPECIFIED",
+		1: "PROJECTION_REPLAY_STATUS_RUNNING",
+		2: "PROJECTION_REPLAY_STATUS_STOPPED",
+		3: "PROJECTION_REPLAY_STATUS_COMPLETED",
+		4: "PROJECTION_REPLAY_STATUS_FAILED",
+	}
+	ProjectionReplayStatus_value = map[string]int32{
+		"PROJECTION_REPLAY_STATUS_UNSPECIFIED": 0,
+		"PROJECTION_REPLAY_STATUS_RUNNING":     1,
+		"PROJECTION_REPLAY_STATUS_STOPPED":     2,
+		"PROJECTION_REPLAY_STATUS_COMPLETED":   3,
+		"PROJECTION_REPLAY_STATUS_FAILED":      4,
+	}
+)
+
+func (x ProjectionReplayStatus) Enum() *ProjectionReplayStatus {
+	p := new(ProjectionReplayStatus)
+	*p = x
+	return p
+}
+
+func (x ProjectionReplayStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProjectionReplayStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_orders_v1_orders_proto_enumTypes[4].Descriptor()
+}
+
+func (ProjectionReplayStatus) Type() protoreflect.EnumType {
+	return &file_orders_v1_orders_proto_enumTypes[4]
+}
+
+func (x ProjectionReplayStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProjectionReplayStatus.Descriptor instead.
+func (ProjectionReplayStatus) EnumDescriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{4}
+}
+
+// A dispute of the order's payments, opened and resolved by an operator in
+// payments-service.
+type OrderDispute struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Status DisputeStatus          `protobuf:"varint,1,opt,name=status,proto3,enum=orders.v1.DisputeStatus" json:"status,omitempty"`
+	// Paid for the order, fees included; credited back to the payer's balance
+	// when the dispute is refunded.
+	Amount   int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason   string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	OpenedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	// Unset while the dispute is open.
+	ResolvedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderDispute) Reset() {
+	*x = OrderDispute{}
+	mi := &file_orders_v1_orders_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderDispute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderDispute) ProtoMessage() {}
+
+func (x *OrderDispute) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderDispute.ProtoReflect.Descriptor instead.
+func (*OrderDispute) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderDispute) GetStatus() DisputeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+}
+
+func (x *OrderDispute) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderDispute) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderDispute) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *OrderDispute) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
 type Order struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	OrderId     string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId      string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount      int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"` // minimal currency units (e.g. cents/kopecks)
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Status      OrderStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Human-readable reason from PaymentResult; set only for CANCELLED orders.
+	PaymentFailureReason string `protobuf:"bytes,7,opt,name=payment_failure_reason,json=paymentFailureReason,proto3" json:"payment_failure_reason,omitempty"`
+	// Sum of successful installments, in minimal currency units.
+	PaidAmount int64 `protobuf:"varint,8,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	// ISO 4217 code the amounts are in.
+	Currency string `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Integrator-supplied references, e.g. an invoice number.
+	Metadata map[string]string `protobuf:"bytes,10,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags     []string          `protobuf:"bytes,11,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Grows with every change of the order; pass it to UpdateOrder to detect
+	// concurrent changes.
+	Version   int64                  `protobuf:"varint,12,opt,name=version,proto3" json:"version,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Fees payments-service charged on top of the successful payments, in
+	// minimal currency units; not part of paid_amount.
+	FeeAmount int64 `protobuf:"varint,14,opt,name=fee_amount,json=feeAmount,proto3" json:"fee_amount,omitempty"`
+	// Set for finished and cancelled orders moved to the archive table.
+	Archived bool `protobuf:"varint,15,opt,name=archived,proto3" json:"archived,omitempty"`
+	// When the payment of a scheduled order is (or was) requested; unset for
+	// orders paid right away.
+	PayAt *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=pay_at,json=payAt,proto3" json:"pay_at,omitempty"`
+	// Set by GetOrder and InspectOrder for orders that were disputed, whatever
+	// the outcome.
+	Dispute *OrderDispute `protobuf:"bytes,17,opt,name=dispute,proto3" json:"dispute,omitempty"`
+	// Set by ListOrders: the failure reason of the latest payment (the latest
+	// installment for orders paid with PayOrder), empty if it did not fail.
+	LastPaymentReason string `protobuf:"bytes,18,opt,name=last_payment_reason,json=lastPaymentReason,proto3" json:"last_payment_reason,omitempty"`
+	// Set by ListOrders: the number of items the order was created from, 1
+	// for an order created without items.
+	ItemCount     int32 `protobuf:"varint,19,opt,name=item_count,json=itemCount,proto3" json:"item_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_orders_v1_orders_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Order) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Order) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Order) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Order) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetPaymentFailureReason() string {
+	if x != nil {
+		return x.PaymentFailureReason
+	}
+	return ""
+}
+
+func (x *Order) GetPaidAmount() int64 {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return 0
+}
+
+func (x *Order) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Order) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Order) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Order) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetFeeAmount() int64 {
+	if x != nil {
+		return x.FeeAmount
+	}
+	return 0
+}
+
+func (x *Order) GetArchived() bool {
+	if x != nil {
+		return x.Archived
+	}
+	return false
+}
+
+func (x *Order) GetPayAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PayAt
+	}
+	return nil
+}
+
+func (x *Order) GetDispute() *OrderDispute {
+	if x != nil {
+		return x.Dispute
+	}
+	return nil
+}
+
+func (x *Order) GetLastPaymentReason() string {
+	if x != nil {
+		return x.LastPaymentReason
+	}
+	return ""
+}
+
+func (x *Order) GetItemCount() int32 {
+	if x != nil {
+		return x.ItemCount
+	}
+	return 0
+}
+
+type CreateOrderRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount      int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	// Optional: forwarded from REST Idempotency-Key
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// When set, no payment is requested on creation; the order is paid via PayOrder.
+	PayInInstallments bool `protobuf:"varint,5,opt,name=pay_in_installments,json=payInInstallments,proto3" json:"pay_in_installments,omitempty"`
+	// Optional: when set, amount must be 0 and is resolved from the product catalog.
+	Items []*OrderItem `protobuf:"bytes,6,rep,name=items,proto3" json:"items,omitempty"`
+	// Optional ISO 4217 code; must match the service currency when set.
+	Currency string `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Optional, stored as-is and returned with the order. Size limits are
+	// enforced by the service.
+	Metadata map[string]string `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags     []string          `protobuf:"bytes,9,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Creates the order even if it looks like a duplicate of a recent one.
+	// Duplicates are rejected with ALREADY_EXISTS carrying a google.rpc.ErrorInfo
+	// with reason DUPLICATE_ORDER and the existing order id in metadata.order_id.
+	Force bool `protobuf:"varint,10,opt,name=force,proto3" json:"force,omitempty"`
+	// Optional: requests the payment at this time instead of right away. The
+	// order stays SCHEDULED until then and can be cancelled with CancelOrder.
+	// Must be in the future; not allowed with pay_in_installments.
+	PayAt *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=pay_at,json=payAt,proto3" json:"pay_at,omitempty"`
+	// Optional, case-insensitive: a promo code whose discount is taken off
+	// the amount. Codes that cannot be used are rejected with a
+	// google.rpc.ErrorInfo whose reason is one of the PROMO_CODE_* reasons.
+	PromoCode string `protobuf:"bytes,12,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	// Optional: how the part not covered by bonus grants is paid, e.g.
+	// "balance" (the default) or "card". Payments-service declines methods it
+	// has no provider for.
+	PaymentMethod string `protobuf:"bytes,13,opt,name=payment_method,json=paymentMethod,proto3" json:"payment_method,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateOrderRequest) Reset() {
+	*x = CreateOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderRequest) ProtoMessage() {}
+
+func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
+func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreateOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *CreateOrderRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetPayInInstallments() bool {
+	if x != nil {
+		return x.PayInInstallments
+	}
+	return false
+}
+
+func (x *CreateOrderRequest) GetItems() []*OrderItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetForce() bool {
+	if x != nil {
+		return x.Force
+	}
+	return false
+}
+
+func (x *CreateOrderRequest) GetPayAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PayAt
+	}
+	return nil
+}
+
+func (x *CreateOrderRequest) GetPromoCode() string {
+	if x != nil {
+		return x.PromoCode
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetPaymentMethod() string {
+	if x != nil {
+		return x.PaymentMethod
+	}
+	return ""
+}
+
+type ValidateOrderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The amount the order would be created with, resolved from items if set
+	// and less the promo code discount.
+	Amount   int64  `protobuf:"varint,1,opt,name=amount,proto3" json:"amount,omitempty"`
+	Currency string `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// What the promo code takes off; 0 without one.
+	DiscountAmount int64 `protobuf:"varint,3,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ValidateOrderResponse) Reset() {
+	*x = ValidateOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValidateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValidateOrderResponse) ProtoMessage() {}
+
+func (x *ValidateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValidateOrderResponse.ProtoReflect.Descriptor instead.
+func (*ValidateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ValidateOrderResponse) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ValidateOrderResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ValidateOrderResponse) GetDiscountAmount() int64 {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return 0
+}
+
+type OrderItem struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int64                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderItem) Reset() {
+	*x = OrderItem{}
+	mi := &file_orders_v1_orders_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderItem) ProtoMessage() {}
+
+func (x *OrderItem) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderItem.ProtoReflect.Descriptor instead.
+func (*OrderItem) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *OrderItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+func (x *OrderItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type CreateOrderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// order.amount is what is charged, after the promo code discount.
+	Order *Order `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	// What the promo code took off; 0 without one.
+	DiscountAmount int64 `protobuf:"varint,2,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateOrderResponse) Reset() {
+	*x = CreateOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateOrderResponse) ProtoMessage() {}
+
+func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
+func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *CreateOrderResponse) GetDiscountAmount() int64 {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return 0
+}
+
+type ListOrdersRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Optional pagination
+	Limit     int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	PageToken string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Optional: only orders carrying this tag.
+	Tag string `protobuf:"bytes,4,opt,name=tag,proto3" json:"tag,omitempty"`
+	// Also list archived orders; by default they are left out.
+	IncludeArchived bool `protobuf:"varint,5,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
+	// Optional: the Order fields to return in every order, e.g.
+	// ["order_id", "status"]; top-level fields only. Unset returns all.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,6,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersRequest) Reset() {
+	*x = ListOrdersRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersRequest) ProtoMessage() {}
+
+func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
+func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetTag() string {
+	if x != nil {
+		return x.Tag
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+func (x *ListOrdersRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type ListOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOrdersResponse) Reset() {
+	*x = ListOrdersResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOrdersResponse) ProtoMessage() {}
+
+func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
+func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type GetOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Optional: the Order fields to return, as in ListOrdersRequest.read_mask.
+	ReadMask      *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=read_mask,json=readMask,proto3" json:"read_mask,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderRequest) Reset() {
+	*x = GetOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderRequest) ProtoMessage() {}
+
+func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *GetOrderRequest) GetReadMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.ReadMask
+	}
+	return nil
+}
+
+type GetOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderResponse) Reset() {
+	*x = GetOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderResponse) ProtoMessage() {}
+
+func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type GetOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderIds      []string               `protobuf:"bytes,2,rep,name=order_ids,json=orderIds,proto3" json:"order_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrdersRequest) Reset() {
+	*x = GetOrdersRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrdersRequest) ProtoMessage() {}
+
+func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetOrdersRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrdersRequest) GetOrderIds() []string {
+	if x != nil {
+		return x.OrderIds
+	}
+	return nil
+}
+
+type GetOrdersResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// In the order of the request, duplicates removed.
+	Orders          []*Order `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	MissingOrderIds []string `protobuf:"bytes,2,rep,name=missing_order_ids,json=missingOrderIds,proto3" json:"missing_order_ids,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetOrdersResponse) Reset() {
+	*x = GetOrdersResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrdersResponse) ProtoMessage() {}
+
+func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+func (x *GetOrdersResponse) GetMissingOrderIds() []string {
+	if x != nil {
+		return x.MissingOrderIds
+	}
+	return nil
+}
+
+type GetOrderReceiptRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Optional: also render the receipt in this format, e.g. "pdf". Formats
+	// the service has no renderer for are rejected with INVALID_ARGUMENT.
+	Format        string `protobuf:"bytes,3,opt,name=format,proto3" json:"format,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderReceiptRequest) Reset() {
+	*x = GetOrderReceiptRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderReceiptRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderReceiptRequest) ProtoMessage() {}
+
+func (x *GetOrderReceiptRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderReceiptRequest.ProtoReflect.Descriptor instead.
+func (*GetOrderReceiptRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetOrderReceiptRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetOrderReceiptRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *GetOrderReceiptRequest) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+type GetOrderReceiptResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Receipt *Receipt               `protobuf:"bytes,1,opt,name=receipt,proto3" json:"receipt,omitempty"`
+	// The rendered receipt when a format was requested.
+	Document      []byte `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`
+	ContentType   string `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrderReceiptResponse) Reset() {
+	*x = GetOrderReceiptResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrderReceiptResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrderReceiptResponse) ProtoMessage() {}
+
+func (x *GetOrderReceiptResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrderReceiptResponse.ProtoReflect.Descriptor instead.
+func (*GetOrderReceiptResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GetOrderReceiptResponse) GetReceipt() *Receipt {
+	if x != nil {
+		return x.Receipt
+	}
+	return nil
+}
+
+func (x *GetOrderReceiptResponse) GetDocument() []byte {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+func (x *GetOrderReceiptResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// Receipt issued when an order is FINISHED. Amounts are in minimal currency
+// units.
+type Receipt struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Unique and increasing, e.g. R-20260115-000042; not gapless.
+	Number    string         `protobuf:"bytes,1,opt,name=number,proto3" json:"number,omitempty"`
+	OrderId   string         `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	UserId    string         `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Currency  string         `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	Items     []*ReceiptItem `protobuf:"bytes,5,rep,name=items,proto3" json:"items,omitempty"`
+	Amount    int64          `protobuf:"varint,6,opt,name=amount,proto3" json:"amount,omitempty"`
+	FeeAmount int64          `protobuf:"varint,7,opt,name=fee_amount,json=feeAmount,proto3" json:"fee_amount,omitempty"`
+	// amount plus fee_amount.
+	TotalAmount    int64                  `protobuf:"varint,8,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	OrderCreatedAt *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=order_created_at,json=orderCreatedAt,proto3" json:"order_created_at,omitempty"`
+	PaidAt         *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=paid_at,json=paidAt,proto3" json:"paid_at,omitempty"`
+	IssuedAt       *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=issued_at,json=issuedAt,proto3" json:"issued_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *Receipt) Reset() {
+	*x = Receipt{}
+	mi := &file_orders_v1_orders_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Receipt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Receipt) ProtoMessage() {}
+
+func (x *Receipt) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Receipt.ProtoReflect.Descriptor instead.
+func (*Receipt) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Receipt) GetNumber() string {
+	if x != nil {
+		return x.Number
+	}
+	return ""
+}
+
+func (x *Receipt) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Receipt) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Receipt) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Receipt) GetItems() []*ReceiptItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Receipt) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Receipt) GetFeeAmount() int64 {
+	if x != nil {
+		return x.FeeAmount
+	}
+	return 0
+}
+
+func (x *Receipt) GetTotalAmount() int64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *Receipt) GetOrderCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OrderCreatedAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetPaidAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PaidAt
+	}
+	return nil
+}
+
+func (x *Receipt) GetIssuedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.IssuedAt
+	}
+	return nil
+}
+
+type ReceiptItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// "product" for a catalog product of an order created from items, "order"
+	// for an order created with an amount (before the discount), "discount"
+	// for the promo code discount, "fee" for the payment fee.
+	Kind        string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	Description string `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Quantity    int64  `protobuf:"varint,3,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// The catalog price when the order was created; negative on the discount
+	// line, as is amount.
+	UnitPrice int64 `protobuf:"varint,4,opt,name=unit_price,json=unitPrice,proto3" json:"unit_price,omitempty"`
+	Amount    int64 `protobuf:"varint,5,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Set on product lines.
+	ProductId     string `protobuf:"bytes,6,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReceiptItem) Reset() {
+	*x = ReceiptItem{}
+	mi := &file_orders_v1_orders_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReceiptItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReceiptItem) ProtoMessage() {}
+
+func (x *ReceiptItem) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReceiptItem.ProtoReflect.Descriptor instead.
+func (*ReceiptItem) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReceiptItem) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *ReceiptItem) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *ReceiptItem) GetQuantity() int64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *ReceiptItem) GetUnitPrice() int64 {
+	if x != nil {
+		return x.UnitPrice
+	}
+	return 0
+}
+
+func (x *ReceiptItem) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *ReceiptItem) GetProductId() string {
+	if x != nil {
+		return x.ProductId
+	}
+	return ""
+}
+
+type WaitOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// How long to hold the call; 30s when unset, at most 60s. The call's
+	// deadline, if shorter, wins.
+	Timeout       *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitOrderRequest) Reset() {
+	*x = WaitOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitOrderRequest) ProtoMessage() {}
+
+func (x *WaitOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitOrderRequest.ProtoReflect.Descriptor instead.
+func (*WaitOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *WaitOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *WaitOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *WaitOrderRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type WaitOrderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The order as of the answer; still NEW when timed_out is set.
+	Order         *Order `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	TimedOut      bool   `protobuf:"varint,2,opt,name=timed_out,json=timedOut,proto3" json:"timed_out,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WaitOrderResponse) Reset() {
+	*x = WaitOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WaitOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WaitOrderResponse) ProtoMessage() {}
+
+func (x *WaitOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WaitOrderResponse.ProtoReflect.Descriptor instead.
+func (*WaitOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *WaitOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *WaitOrderResponse) GetTimedOut() bool {
+	if x != nil {
+		return x.TimedOut
+	}
+	return false
+}
+
+type PayOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Amount  int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Optional: forwarded from REST Idempotency-Key
+	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	// Optional ISO 4217 code; must match the service currency when set.
+	Currency      string `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayOrderRequest) Reset() {
+	*x = PayOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayOrderRequest) ProtoMessage() {}
+
+func (x *PayOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayOrderRequest.ProtoReflect.Descriptor instead.
+func (*PayOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *PayOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *PayOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *PayOrderRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *PayOrderRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+func (x *PayOrderRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type PayOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	PaymentId     string                 `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PayOrderResponse) Reset() {
+	*x = PayOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PayOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PayOrderResponse) ProtoMessage() {}
+
+func (x *PayOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PayOrderResponse.ProtoReflect.Descriptor instead.
+func (*PayOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *PayOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *PayOrderResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type UpdateOrderRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Fields to change: description, metadata, tags. metadata and tags are
+	// replaced as a whole.
+	UpdateMask  *fieldmaskpb.FieldMask `protobuf:"bytes,3,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	Description string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	Metadata    map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Tags        []string               `protobuf:"bytes,6,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Optional: the Order.version the change is based on. When set and the
+	// order has changed since, the call fails with ABORTED.
+	Version       int64 `protobuf:"varint,7,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderRequest) Reset() {
+	*x = UpdateOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderRequest) ProtoMessage() {}
+
+func (x *UpdateOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UpdateOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateOrderRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *UpdateOrderRequest) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type UpdateOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateOrderResponse) Reset() {
+	*x = UpdateOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateOrderResponse) ProtoMessage() {}
+
+func (x *UpdateOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateOrderResponse.ProtoReflect.Descriptor instead.
+func (*UpdateOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type OrderTransfer struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TransferId    string                 `protobuf:"bytes,1,opt,name=transfer_id,json=transferId,proto3" json:"transfer_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	FromUserId    string                 `protobuf:"bytes,3,opt,name=from_user_id,json=fromUserId,proto3" json:"from_user_id,omitempty"`
+	ToUserId      string                 `protobuf:"bytes,4,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	Status        OrderTransferStatus    `protobuf:"varint,5,opt,name=status,proto3,enum=orders.v1.OrderTransferStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderTransfer) Reset() {
+	*x = OrderTransfer{}
+	mi := &file_orders_v1_orders_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderTransfer) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderTransfer) ProtoMessage() {}
+
+func (x *OrderTransfer) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderTransfer.ProtoReflect.Descriptor instead.
+func (*OrderTransfer) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *OrderTransfer) GetTransferId() string {
+	if x != nil {
+		return x.TransferId
+	}
+	return ""
+}
+
+func (x *OrderTransfer) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OrderTransfer) GetFromUserId() string {
+	if x != nil {
+		return x.FromUserId
+	}
+	return ""
+}
+
+func (x *OrderTransfer) GetToUserId() string {
+	if x != nil {
+		return x.ToUserId
+	}
+	return ""
+}
+
+func (x *OrderTransfer) GetStatus() OrderTransferStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderTransferStatus_ORDER_TRANSFER_STATUS_UNSPECIFIED
+}
+
+func (x *OrderTransfer) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type TransferOrderRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The current owner.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ToUserId      string `protobuf:"bytes,3,opt,name=to_user_id,json=toUserId,proto3" json:"to_user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOrderRequest) Reset() {
+	*x = TransferOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOrderRequest) ProtoMessage() {}
+
+func (x *TransferOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOrderRequest.ProtoReflect.Descriptor instead.
+func (*TransferOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *TransferOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *TransferOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *TransferOrderRequest) GetToUserId() string {
+	if x != nil {
+		return x.ToUserId
+	}
+	return ""
+}
+
+type TransferOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Transfer      *OrderTransfer         `protobuf:"bytes,1,opt,name=transfer,proto3" json:"transfer,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferOrderResponse) Reset() {
+	*x = TransferOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferOrderResponse) ProtoMessage() {}
+
+func (x *TransferOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferOrderResponse.ProtoReflect.Descriptor instead.
+func (*TransferOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *TransferOrderResponse) GetTransfer() *OrderTransfer {
+	if x != nil {
+		return x.Transfer
+	}
+	return nil
+}
+
+type AcceptOrderTransferRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The recipient named in the pending transfer.
+	UserId        string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptOrderTransferRequest) Reset() {
+	*x = AcceptOrderTransferRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptOrderTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptOrderTransferRequest) ProtoMessage() {}
+
+func (x *AcceptOrderTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptOrderTransferRequest.ProtoReflect.Descriptor instead.
+func (*AcceptOrderTransferRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AcceptOrderTransferRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AcceptOrderTransferRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type AcceptOrderTransferResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AcceptOrderTransferResponse) Reset() {
+	*x = AcceptOrderTransferResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AcceptOrderTransferResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AcceptOrderTransferResponse) ProtoMessage() {}
+
+func (x *AcceptOrderTransferResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AcceptOrderTransferResponse.ProtoReflect.Descriptor instead.
+func (*AcceptOrderTransferResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *AcceptOrderTransferResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CancelOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *CancelOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+type RetryPaymentRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPaymentRequest) Reset() {
+	*x = RetryPaymentRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPaymentRequest) ProtoMessage() {}
+
+func (x *RetryPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPaymentRequest.ProtoReflect.Descriptor instead.
+func (*RetryPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RetryPaymentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RetryPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type RetryPaymentResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Order *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	// How many more times the payment of this order can be retried.
+	RetriesLeft   int32 `protobuf:"varint,2,opt,name=retries_left,json=retriesLeft,proto3" json:"retries_left,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RetryPaymentResponse) Reset() {
+	*x = RetryPaymentResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RetryPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RetryPaymentResponse) ProtoMessage() {}
+
+func (x *RetryPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RetryPaymentResponse.ProtoReflect.Descriptor instead.
+func (*RetryPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RetryPaymentResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *RetryPaymentResponse) GetRetriesLeft() int32 {
+	if x != nil {
+		return x.RetriesLeft
+	}
+	return 0
+}
+
+type ReplayOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events created in [from, to) are replayed; both are required.
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Only count the matching events.
+	DryRun        bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayOutboxRequest) Reset() {
+	*x = ReplayOutboxRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayOutboxRequest) ProtoMessage() {}
+
+func (x *ReplayOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ReplayOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ReplayOutboxRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReplayOutboxRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReplayOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ReplayOutboxRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ReplayOutboxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matched       int64                  `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	Replayed      int64                  `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayOutboxResponse) Reset() {
+	*x = ReplayOutboxResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayOutboxResponse) ProtoMessage() {}
+
+func (x *ReplayOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ReplayOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReplayOutboxResponse) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *ReplayOutboxResponse) GetReplayed() int64 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *ReplayOutboxResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ListDeadOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Default 50, max 500.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Returns events with id greater than this; pass next_after_id of the
+	// previous page.
+	AfterId       int64 `protobuf:"varint,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadOutboxRequest) Reset() {
+	*x = ListDeadOutboxRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadOutboxRequest) ProtoMessage() {}
+
+func (x *ListDeadOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListDeadOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListDeadOutboxRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeadOutboxRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+type DeadOutboxEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic         string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey      string                 `protobuf:"bytes,3,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadOutboxEvent) Reset() {
+	*x = DeadOutboxEvent{}
+	mi := &file_orders_v1_orders_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadOutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadOutboxEvent) ProtoMessage() {}
+
+func (x *DeadOutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadOutboxEvent.ProtoReflect.Descriptor instead.
+func (*DeadOutboxEvent) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *DeadOutboxEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeadOutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadOutboxEvent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListDeadOutboxResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*DeadOutboxEvent     `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// 0 when there are no more events.
+	NextAfterId   int64 `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadOutboxResponse) Reset() {
+	*x = ListDeadOutboxResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadOutboxResponse) ProtoMessage() {}
+
+func (x *ListDeadOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *ListDeadOutboxResponse) GetEvents() []*DeadOutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListDeadOutboxResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+type ListOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required.
+	State OutboxState `protobuf:"varint,1,opt,name=state,proto3,enum=orders.v1.OutboxState" json:"state,omitempty"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Optional: only events created in [created_from, created_to); either
+	// bound may be left unset.
+	CreatedFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	// Default 50, max 500.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Returns events with id greater than this; pass next_after_id of the
+	// previous page.
+	AfterId       int64 `protobuf:"varint,6,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOutboxRequest) Reset() {
+	*x = ListOutboxRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutboxRequest) ProtoMessage() {}
+
+func (x *ListOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ListOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *ListOutboxRequest) GetState() OutboxState {
+	if x != nil {
+		return x.State
+	}
+	return OutboxState_OUTBOX_STATE_UNSPECIFIED
+}
+
+func (x *ListOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListOutboxRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListOutboxRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListOutboxRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOutboxRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+type OutboxEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic    string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey string                 `protobuf:"bytes,3,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	// PENDING, FAILED or DEAD.
+	Status    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts  int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the publisher tries again; unset for dead and never failed events.
+	NextRetryAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=next_retry_at,json=nextRetryAt,proto3" json:"next_retry_at,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,9,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboxEvent) Reset() {
+	*x = OutboxEvent{}
+	mi := &file_orders_v1_orders_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxEvent) ProtoMessage() {}
+
+func (x *OutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxEvent.ProtoReflect.Descriptor instead.
+func (*OutboxEvent) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *OutboxEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OutboxEvent) GetNextRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryAt
+	}
+	return nil
+}
+
+func (x *OutboxEvent) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+type ListOutboxResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*OutboxEvent         `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// 0 when there are no more events.
+	NextAfterId   int64 `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOutboxResponse) Reset() {
+	*x = ListOutboxResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutboxResponse) ProtoMessage() {}
+
+func (x *ListOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ListOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *ListOutboxResponse) GetEvents() []*OutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListOutboxResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+type RequeueDeadOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events to requeue; ignored when all is set.
+	Ids []int64 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	// Requeue every dead event, of topic when it is set.
+	All           bool   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	Topic         string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadOutboxRequest) Reset() {
+	*x = RequeueDeadOutboxRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadOutboxRequest) ProtoMessage() {}
+
+func (x *RequeueDeadOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadOutboxRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RequeueDeadOutboxRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *RequeueDeadOutboxRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *RequeueDeadOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+type RequeueDeadOutboxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requeued      int64                  `protobuf:"varint,1,opt,name=requeued,proto3" json:"requeued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadOutboxResponse) Reset() {
+	*x = RequeueDeadOutboxResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadOutboxResponse) ProtoMessage() {}
+
+func (x *RequeueDeadOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadOutboxResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RequeueDeadOutboxResponse) GetRequeued() int64 {
+	if x != nil {
+		return x.Requeued
+	}
+	return 0
+}
+
+type InspectOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectOrderRequest) Reset() {
+	*x = InspectOrderRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectOrderRequest) ProtoMessage() {}
+
+func (x *InspectOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectOrderRequest.ProtoReflect.Descriptor instead.
+func (*InspectOrderRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *InspectOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+// An installment payment of the order.
+type OrderPaymentStep struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PaymentId string                 `protobuf:"bytes,1,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Amount    int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// PENDING, SUCCEEDED or FAILED.
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	FailureReason string                 `protobuf:"bytes,4,opt,name=failure_reason,json=failureReason,proto3" json:"failure_reason,omitempty"`
+	Fee           int64                  `protobuf:"varint,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderPaymentStep) Reset() {
+	*x = OrderPaymentStep{}
+	mi := &file_orders_v1_orders_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderPaymentStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderPaymentStep) ProtoMessage() {}
+
+func (x *OrderPaymentStep) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderPaymentStep.ProtoReflect.Descriptor instead.
+func (*OrderPaymentStep) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *OrderPaymentStep) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *OrderPaymentStep) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *OrderPaymentStep) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderPaymentStep) GetFailureReason() string {
+	if x != nil {
+		return x.FailureReason
+	}
+	return ""
+}
+
+func (x *OrderPaymentStep) GetFee() int64 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *OrderPaymentStep) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+// A PaymentRequested re-publish scheduled after FAIL_INTERNAL results.
+type PaymentRetryStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The installment payment_id, or the order_id for up-front payments.
+	RetryKey string `protobuf:"bytes,1,opt,name=retry_key,json=retryKey,proto3" json:"retry_key,omitempty"`
+	Attempts int32  `protobuf:"varint,2,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	// Unset once the retry was published.
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastReason    string                 `protobuf:"bytes,4,opt,name=last_reason,json=lastReason,proto3" json:"last_reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PaymentRetryStep) Reset() {
+	*x = PaymentRetryStep{}
+	mi := &file_orders_v1_orders_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentRetryStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentRetryStep) ProtoMessage() {}
+
+func (x *PaymentRetryStep) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentRetryStep.ProtoReflect.Descriptor instead.
+func (*PaymentRetryStep) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *PaymentRetryStep) GetRetryKey() string {
+	if x != nil {
+		return x.RetryKey
+	}
+	return ""
+}
+
+func (x *PaymentRetryStep) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *PaymentRetryStep) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *PaymentRetryStep) GetLastReason() string {
+	if x != nil {
+		return x.LastReason
+	}
+	return ""
+}
+
+// An outbox event keyed by the order.
+type OutboxEventStep struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	// PENDING, SENT, FAILED or DEAD.
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,8,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboxEventStep) Reset() {
+	*x = OutboxEventStep{}
+	mi := &file_orders_v1_orders_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboxEventStep) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxEventStep) ProtoMessage() {}
+
+func (x *OutboxEventStep) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxEventStep.ProtoReflect.Descriptor instead.
+func (*OutboxEventStep) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *OutboxEventStep) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OutboxEventStep) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *OutboxEventStep) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OutboxEventStep) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OutboxEventStep) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OutboxEventStep) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OutboxEventStep) GetSentAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.SentAt
+	}
+	return nil
+}
+
+func (x *OutboxEventStep) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+type InspectOrderResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Order *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	// Oldest first.
+	Payments []*OrderPaymentStep `protobuf:"bytes,2,rep,name=payments,proto3" json:"payments,omitempty"`
+	Retries  []*PaymentRetryStep `protobuf:"bytes,3,rep,name=retries,proto3" json:"retries,omitempty"`
+	// Oldest first, at most the last 100.
+	Events []*OutboxEventStep `protobuf:"bytes,4,rep,name=events,proto3" json:"events,omitempty"`
+	// Status changes of the order from the order_status_history projection,
+	// oldest first, at most 100.
+	StatusHistory []*OrderStatusChange `protobuf:"bytes,5,rep,name=status_history,json=statusHistory,proto3" json:"status_history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InspectOrderResponse) Reset() {
+	*x = InspectOrderResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InspectOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InspectOrderResponse) ProtoMessage() {}
+
+func (x *InspectOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InspectOrderResponse.ProtoReflect.Descriptor instead.
+func (*InspectOrderResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *InspectOrderResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *InspectOrderResponse) GetPayments() []*OrderPaymentStep {
+	if x != nil {
+		return x.Payments
+	}
+	return nil
+}
+
+func (x *InspectOrderResponse) GetRetries() []*PaymentRetryStep {
+	if x != nil {
+		return x.Retries
+	}
+	return nil
+}
+
+func (x *InspectOrderResponse) GetEvents() []*OutboxEventStep {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *InspectOrderResponse) GetStatusHistory() []*OrderStatusChange {
+	if x != nil {
+		return x.StatusHistory
+	}
+	return nil
+}
+
+type OrderStatusChange struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	EventId        string                 `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	PreviousStatus string                 `protobuf:"bytes,2,opt,name=previous_status,json=previousStatus,proto3" json:"previous_status,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Reason         string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	OccurredAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *OrderStatusChange) Reset() {
+	*x = OrderStatusChange{}
+	mi := &file_orders_v1_orders_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderStatusChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderStatusChange) ProtoMessage() {}
+
+func (x *OrderStatusChange) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderStatusChange.ProtoReflect.Descriptor instead.
+func (*OrderStatusChange) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *OrderStatusChange) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetPreviousStatus() string {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *OrderStatusChange) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type ForceOrderStatusRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Required.
+	Status OrderStatus `protobuf:"varint,2,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
+	// Required; stored in the audit log.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ForceOrderStatusRequest) Reset() {
+	*x = ForceOrderStatusRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceOrderStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceOrderStatusRequest) ProtoMessage() {}
+
+func (x *ForceOrderStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*ForceOrderStatusRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ForceOrderStatusRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ForceOrderStatusRequest) GetStatus() OrderStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *ForceOrderStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ForceOrderStatusResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Order          *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	PreviousStatus OrderStatus            `protobuf:"varint,2,opt,name=previous_status,json=previousStatus,proto3,enum=orders.v1.OrderStatus" json:"previous_status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ForceOrderStatusResponse) Reset() {
+	*x = ForceOrderStatusResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ForceOrderStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ForceOrderStatusResponse) ProtoMessage() {}
+
+func (x *ForceOrderStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ForceOrderStatusResponse.ProtoReflect.Descriptor instead.
+func (*ForceOrderStatusResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *ForceOrderStatusResponse) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
+func (x *ForceOrderStatusResponse) GetPreviousStatus() OrderStatus {
+	if x != nil {
+		return x.PreviousStatus
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+type DryRunInboxMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Inbox consumer, e.g. payment_result.
+	Consumer string `protobuf:"bytes,1,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	// Event id of the message.
+	MessageId     string `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunInboxMessageRequest) Reset() {
+	*x = DryRunInboxMessageRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunInboxMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunInboxMessageRequest) ProtoMessage() {}
+
+func (x *DryRunInboxMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunInboxMessageRequest.ProtoReflect.Descriptor instead.
+func (*DryRunInboxMessageRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DryRunInboxMessageRequest) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type InboxMessageHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxMessageHeader) Reset() {
+	*x = InboxMessageHeader{}
+	mi := &file_orders_v1_orders_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxMessageHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxMessageHeader) ProtoMessage() {}
+
+func (x *InboxMessageHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxMessageHeader.ProtoReflect.Descriptor instead.
+func (*InboxMessageHeader) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *InboxMessageHeader) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *InboxMessageHeader) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type InboxMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consumer      string                 `protobuf:"bytes,1,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Topic         string                 `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition     int32                  `protobuf:"varint,5,opt,name=partition,proto3" json:"partition,omitempty"`
+	Offset        int64                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	Key           []byte                 `protobuf:"bytes,7,opt,name=key,proto3" json:"key,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	Headers       []*InboxMessageHeader  `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	// Unset while the message is not processed.
+	ProcessedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxMessage) Reset() {
+	*x = InboxMessage{}
+	mi := &file_orders_v1_orders_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxMessage) ProtoMessage() {}
+
+func (x *InboxMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxMessage.ProtoReflect.Descriptor instead.
+func (*InboxMessage) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *InboxMessage) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *InboxMessage) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *InboxMessage) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetHeaders() []*InboxMessageHeader {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+type DryRunOutboxEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey      string                 `protobuf:"bytes,2,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunOutboxEvent) Reset() {
+	*x = DryRunOutboxEvent{}
+	mi := &file_orders_v1_orders_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunOutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunOutboxEvent) ProtoMessage() {}
+
+func (x *DryRunOutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunOutboxEvent.ProtoReflect.Descriptor instead.
+func (*DryRunOutboxEvent) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *DryRunOutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *DryRunOutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *DryRunOutboxEvent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type DryRunInboxMessageResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message *InboxMessage          `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The decoded event as JSON; empty when the payload does not decode.
+	EventJson string `protobuf:"bytes,2,opt,name=event_json,json=eventJson,proto3" json:"event_json,omitempty"`
+	// Why the consumer would drop the message without effects.
+	Skipped string `protobuf:"bytes,3,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// The error the consumer failed with; the message would be redelivered.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Outbox events the consumer would have written, in order.
+	WouldPublish  []*DryRunOutboxEvent `protobuf:"bytes,5,rep,name=would_publish,json=wouldPublish,proto3" json:"would_publish,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunInboxMessageResponse) Reset() {
+	*x = DryRunInboxMessageResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunInboxMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunInboxMessageResponse) ProtoMessage() {}
+
+func (x *DryRunInboxMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunInboxMessageResponse.ProtoReflect.Descriptor instead.
+func (*DryRunInboxMessageResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *DryRunInboxMessageResponse) GetMessage() *InboxMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *DryRunInboxMessageResponse) GetEventJson() string {
+	if x != nil {
+		return x.EventJson
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetSkipped() string {
+	if x != nil {
+		return x.Skipped
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetWouldPublish() []*DryRunOutboxEvent {
+	if x != nil {
+		return x.WouldPublish
+	}
+	return nil
+}
+
+type PromoCode struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// Exactly one of percent_off (1-99) and amount_off is set. The discount
+	// is rounded down and never makes an order free.
+	PercentOff int32 `protobuf:"varint,2,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	AmountOff  int64 `protobuf:"varint,3,opt,name=amount_off,json=amountOff,proto3" json:"amount_off,omitempty"`
+	// 0 is unlimited.
+	MaxRedemptions        int32 `protobuf:"varint,4,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	MaxRedemptionsPerUser int32 `protobuf:"varint,5,opt,name=max_redemptions_per_user,json=maxRedemptionsPerUser,proto3" json:"max_redemptions_per_user,omitempty"`
+	// Optional bounds of when the code is accepted: [starts_at, expires_at).
+	StartsAt  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=starts_at,json=startsAt,proto3" json:"starts_at,omitempty"`
+	ExpiresAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	// Redemptions counted against max_redemptions; cancelled orders give
+	// theirs back. Output only.
+	RedeemedCount int32 `protobuf:"varint,8,opt,name=redeemed_count,json=redeemedCount,proto3" json:"redeemed_count,omitempty"`
+	// Output only.
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PromoCode) Reset() {
+	*x = PromoCode{}
+	mi := &file_orders_v1_orders_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PromoCode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PromoCode) ProtoMessage() {}
+
+func (x *PromoCode) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PromoCode.ProtoReflect.Descriptor instead.
+func (*PromoCode) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *PromoCode) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *PromoCode) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *PromoCode) GetAmountOff() int64 {
+	if x != nil {
+		return x.AmountOff
+	}
+	return 0
+}
+
+func (x *PromoCode) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *PromoCode) GetMaxRedemptionsPerUser() int32 {
+	if x != nil {
+		return x.MaxRedemptionsPerUser
+	}
+	return 0
+}
+
+func (x *PromoCode) GetStartsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartsAt
+	}
+	return nil
+}
+
+func (x *PromoCode) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *PromoCode) GetRedeemedCount() int32 {
+	if x != nil {
+		return x.RedeemedCount
+	}
+	return 0
+}
+
+func (x *PromoCode) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type CreatePromoCodeRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount        int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"` // minimal currency units (e.g. cents/kopecks)
-	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
-	Status        OrderStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=orders.v1.OrderStatus" json:"status,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	PromoCode     *PromoCode             `protobuf:"bytes,1,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromoCodeRequest) Reset() {
+	*x = CreatePromoCodeRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromoCodeRequest) ProtoMessage() {}
+
+func (x *CreatePromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*CreatePromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *CreatePromoCodeRequest) GetPromoCode() *PromoCode {
+	if x != nil {
+		return x.PromoCode
+	}
+	return nil
+}
+
+type CreatePromoCodeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PromoCode     *PromoCode             `protobuf:"bytes,1,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePromoCodeResponse) Reset() {
+	*x = CreatePromoCodeResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePromoCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePromoCodeResponse) ProtoMessage() {}
+
+func (x *CreatePromoCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePromoCodeResponse.ProtoReflect.Descriptor instead.
+func (*CreatePromoCodeResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *CreatePromoCodeResponse) GetPromoCode() *PromoCode {
+	if x != nil {
+		return x.PromoCode
+	}
+	return nil
+}
+
+type GetPromoCodeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPromoCodeRequest) Reset() {
+	*x = GetPromoCodeRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPromoCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPromoCodeRequest) ProtoMessage() {}
+
+func (x *GetPromoCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPromoCodeRequest.ProtoReflect.Descriptor instead.
+func (*GetPromoCodeRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetPromoCodeRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type GetPromoCodeResponse struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	PromoCode *PromoCode             `protobuf:"bytes,1,opt,name=promo_code,json=promoCode,proto3" json:"promo_code,omitempty"`
+	// Orders created with the code and not cancelled.
+	Redemptions int64 `protobuf:"varint,2,opt,name=redemptions,proto3" json:"redemptions,omitempty"`
+	// Orders created with the code and cancelled since.
+	Released int64 `protobuf:"varint,3,opt,name=released,proto3" json:"released,omitempty"`
+	// Sums over the redemptions: discounts given and order amounts before
+	// them.
+	DiscountTotal int64 `protobuf:"varint,4,opt,name=discount_total,json=discountTotal,proto3" json:"discount_total,omitempty"`
+	AmountTotal   int64 `protobuf:"varint,5,opt,name=amount_total,json=amountTotal,proto3" json:"amount_total,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPromoCodeResponse) Reset() {
+	*x = GetPromoCodeResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPromoCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPromoCodeResponse) ProtoMessage() {}
+
+func (x *GetPromoCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPromoCodeResponse.ProtoReflect.Descriptor instead.
+func (*GetPromoCodeResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetPromoCodeResponse) GetPromoCode() *PromoCode {
+	if x != nil {
+		return x.PromoCode
+	}
+	return nil
+}
+
+func (x *GetPromoCodeResponse) GetRedemptions() int64 {
+	if x != nil {
+		return x.Redemptions
+	}
+	return 0
+}
+
+func (x *GetPromoCodeResponse) GetReleased() int64 {
+	if x != nil {
+		return x.Released
+	}
+	return 0
+}
+
+func (x *GetPromoCodeResponse) GetDiscountTotal() int64 {
+	if x != nil {
+		return x.DiscountTotal
+	}
+	return 0
+}
+
+func (x *GetPromoCodeResponse) GetAmountTotal() int64 {
+	if x != nil {
+		return x.AmountTotal
+	}
+	return 0
+}
+
+type ProjectionReplayPartition struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Partition int32                  `protobuf:"varint,1,opt,name=partition,proto3" json:"partition,omitempty"`
+	// The replay reads [start_offset, end_offset); next_offset is the first
+	// message not applied yet.
+	StartOffset   int64 `protobuf:"varint,2,opt,name=start_offset,json=startOffset,proto3" json:"start_offset,omitempty"`
+	NextOffset    int64 `protobuf:"varint,3,opt,name=next_offset,json=nextOffset,proto3" json:"next_offset,omitempty"`
+	EndOffset     int64 `protobuf:"varint,4,opt,name=end_offset,json=endOffset,proto3" json:"end_offset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProjectionReplayPartition) Reset() {
+	*x = ProjectionReplayPartition{}
+	mi := &file_orders_v1_orders_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProjectionReplayPartition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProjectionReplayPartition) ProtoMessage() {}
+
+func (x *ProjectionReplayPartition) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProjectionReplayPartition.ProtoReflect.Descriptor instead.
+func (*ProjectionReplayPartition) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *ProjectionReplayPartition) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *ProjectionReplayPartition) GetStartOffset() int64 {
+	if x != nil {
+		return x.StartOffset
+	}
+	return 0
+}
+
+func (x *ProjectionReplayPartition) GetNextOffset() int64 {
+	if x != nil {
+		return x.NextOffset
+	}
+	return 0
+}
+
+func (x *ProjectionReplayPartition) GetEndOffset() int64 {
+	if x != nil {
+		return x.EndOffset
+	}
+	return 0
+}
+
+type ProjectionReplay struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Id         int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Projection string                 `protobuf:"bytes,2,opt,name=projection,proto3" json:"projection,omitempty"`
+	Topic      string                 `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	Status     ProjectionReplayStatus `protobuf:"varint,4,opt,name=status,proto3,enum=orders.v1.ProjectionReplayStatus" json:"status,omitempty"`
+	// Messages per second; 0 is unlimited.
+	RateLimit int32 `protobuf:"varint,5,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Messages applied so far.
+	Applied int64 `protobuf:"varint,6,opt,name=applied,proto3" json:"applied,omitempty"`
+	// Messages left up to the end offsets.
+	Remaining   int64                  `protobuf:"varint,7,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	Error       string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	RequestedBy string                 `protobuf:"bytes,9,opt,name=requested_by,json=requestedBy,proto3" json:"requested_by,omitempty"`
+	Reason      string                 `protobuf:"bytes,10,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// Last progress of the replica running the replay.
+	HeartbeatAt *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=heartbeat_at,json=heartbeatAt,proto3" json:"heartbeat_at,omitempty"`
+	FinishedAt  *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	// Empty until a replica has taken the replay and read the offsets.
+	Partitions    []*ProjectionReplayPartition `protobuf:"bytes,14,rep,name=partitions,proto3" json:"partitions,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Order) Reset() {
-	*x = Order{}
-	mi := &file_orders_v1_orders_proto_msgTypes[0]
+func (x *ProjectionReplay) Reset() {
+	*x = ProjectionReplay{}
+	mi := &file_orders_v1_orders_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Order) String() string {
+func (x *ProjectionReplay) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Order) ProtoMessage() {}
+func (*ProjectionReplay) ProtoMessage() {}
 
-func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[0]
+func (x *ProjectionReplay) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -111,79 +4572,136 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Order.ProtoReflect.Descriptor instead.
-func (*Order) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{0}
+// Deprecated: Use ProjectionReplay.ProtoReflect.Descriptor instead.
+func (*ProjectionReplay) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *Order) GetOrderId() string {
+func (x *ProjectionReplay) GetId() int64 {
 	if x != nil {
-		return x.OrderId
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ProjectionReplay) GetProjection() string {
+	if x != nil {
+		return x.Projection
 	}
 	return ""
 }
 
-func (x *Order) GetUserId() string {
+func (x *ProjectionReplay) GetTopic() string {
 	if x != nil {
-		return x.UserId
+		return x.Topic
 	}
 	return ""
 }
 
-func (x *Order) GetAmount() int64 {
+func (x *ProjectionReplay) GetStatus() ProjectionReplayStatus {
 	if x != nil {
-		return x.Amount
+		return x.Status
+	}
+	return ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_UNSPECIFIED
+}
+
+func (x *ProjectionReplay) GetRateLimit() int32 {
+	if x != nil {
+		return x.RateLimit
 	}
 	return 0
 }
 
-func (x *Order) GetDescription() string {
+func (x *ProjectionReplay) GetApplied() int64 {
 	if x != nil {
-		return x.Description
+		return x.Applied
+	}
+	return 0
+}
+
+func (x *ProjectionReplay) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *ProjectionReplay) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
 
-func (x *Order) GetStatus() OrderStatus {
+func (x *ProjectionReplay) GetRequestedBy() string {
 	if x != nil {
-		return x.Status
+		return x.RequestedBy
 	}
-	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+	return ""
 }
 
-func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+func (x *ProjectionReplay) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *ProjectionReplay) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
 		return x.CreatedAt
 	}
 	return nil
 }
 
-type CreateOrderRequest struct {
-	state       protoimpl.MessageState `protogen:"open.v1"`
-	UserId      string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Amount      int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
-	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	// Optional: forwarded from REST Idempotency-Key
-	IdempotencyKey string `protobuf:"bytes,4,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+func (x *ProjectionReplay) GetHeartbeatAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.HeartbeatAt
+	}
+	return nil
 }
 
-func (x *CreateOrderRequest) Reset() {
-	*x = CreateOrderRequest{}
-	mi := &file_orders_v1_orders_proto_msgTypes[1]
+func (x *ProjectionReplay) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *ProjectionReplay) GetPartitions() []*ProjectionReplayPartition {
+	if x != nil {
+		return x.Partitions
+	}
+	return nil
+}
+
+type StartProjectionReplayRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Projection to rebuild, e.g. order_status_history.
+	Projection string `protobuf:"bytes,1,opt,name=projection,proto3" json:"projection,omitempty"`
+	// Messages per second; 0 is unlimited.
+	RateLimit int32 `protobuf:"varint,2,opt,name=rate_limit,json=rateLimit,proto3" json:"rate_limit,omitempty"`
+	// Required; stored in the audit log.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartProjectionReplayRequest) Reset() {
+	*x = StartProjectionReplayRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderRequest) String() string {
+func (x *StartProjectionReplayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderRequest) ProtoMessage() {}
+func (*StartProjectionReplayRequest) ProtoMessage() {}
 
-func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[1]
+func (x *StartProjectionReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -194,61 +4712,54 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
-func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{1}
+// Deprecated: Use StartProjectionReplayRequest.ProtoReflect.Descriptor instead.
+func (*StartProjectionReplayRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *CreateOrderRequest) GetUserId() string {
+func (x *StartProjectionReplayRequest) GetProjection() string {
 	if x != nil {
-		return x.UserId
+		return x.Projection
 	}
 	return ""
 }
 
-func (x *CreateOrderRequest) GetAmount() int64 {
+func (x *StartProjectionReplayRequest) GetRateLimit() int32 {
 	if x != nil {
-		return x.Amount
+		return x.RateLimit
 	}
 	return 0
 }
 
-func (x *CreateOrderRequest) GetDescription() string {
-	if x != nil {
-		return x.Description
-	}
-	return ""
-}
-
-func (x *CreateOrderRequest) GetIdempotencyKey() string {
+func (x *StartProjectionReplayRequest) GetReason() string {
 	if x != nil {
-		return x.IdempotencyKey
+		return x.Reason
 	}
 	return ""
 }
 
-type CreateOrderResponse struct {
+type StartProjectionReplayResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Replay        *ProjectionReplay      `protobuf:"bytes,1,opt,name=replay,proto3" json:"replay,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateOrderResponse) Reset() {
-	*x = CreateOrderResponse{}
-	mi := &file_orders_v1_orders_proto_msgTypes[2]
+func (x *StartProjectionReplayResponse) Reset() {
+	*x = StartProjectionReplayResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateOrderResponse) String() string {
+func (x *StartProjectionReplayResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateOrderResponse) ProtoMessage() {}
+func (*StartProjectionReplayResponse) ProtoMessage() {}
 
-func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[2]
+func (x *StartProjectionReplayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -259,43 +4770,42 @@ func (x *CreateOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateOrderResponse.ProtoReflect.Descriptor instead.
-func (*CreateOrderResponse) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{2}
+// Deprecated: Use StartProjectionReplayResponse.ProtoReflect.Descriptor instead.
+func (*StartProjectionReplayResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *CreateOrderResponse) GetOrder() *Order {
+func (x *StartProjectionReplayResponse) GetReplay() *ProjectionReplay {
 	if x != nil {
-		return x.Order
+		return x.Replay
 	}
 	return nil
 }
 
-type ListOrdersRequest struct {
-	state  protoimpl.MessageState `protogen:"open.v1"`
-	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	// Optional pagination
-	Limit         int32  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	PageToken     string `protobuf:"bytes,3,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+type StopProjectionReplayRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ReplayId int64                  `protobuf:"varint,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
+	// Required; stored in the audit log.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrdersRequest) Reset() {
-	*x = ListOrdersRequest{}
-	mi := &file_orders_v1_orders_proto_msgTypes[3]
+func (x *StopProjectionReplayRequest) Reset() {
+	*x = StopProjectionReplayRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrdersRequest) String() string {
+func (x *StopProjectionReplayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrdersRequest) ProtoMessage() {}
+func (*StopProjectionReplayRequest) ProtoMessage() {}
 
-func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[3]
+func (x *StopProjectionReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -306,55 +4816,47 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
-func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{3}
-}
-
-func (x *ListOrdersRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use StopProjectionReplayRequest.ProtoReflect.Descriptor instead.
+func (*StopProjectionReplayRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *ListOrdersRequest) GetLimit() int32 {
+func (x *StopProjectionReplayRequest) GetReplayId() int64 {
 	if x != nil {
-		return x.Limit
+		return x.ReplayId
 	}
 	return 0
 }
 
-func (x *ListOrdersRequest) GetPageToken() string {
+func (x *StopProjectionReplayRequest) GetReason() string {
 	if x != nil {
-		return x.PageToken
+		return x.Reason
 	}
 	return ""
 }
 
-type ListOrdersResponse struct {
+type StopProjectionReplayResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
-	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	Replay        *ProjectionReplay      `protobuf:"bytes,1,opt,name=replay,proto3" json:"replay,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListOrdersResponse) Reset() {
-	*x = ListOrdersResponse{}
-	mi := &file_orders_v1_orders_proto_msgTypes[4]
+func (x *StopProjectionReplayResponse) Reset() {
+	*x = StopProjectionReplayResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[64]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListOrdersResponse) String() string {
+func (x *StopProjectionReplayResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListOrdersResponse) ProtoMessage() {}
+func (*StopProjectionReplayResponse) ProtoMessage() {}
 
-func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[4]
+func (x *StopProjectionReplayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[64]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -365,48 +4867,40 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
-func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{4}
+// Deprecated: Use StopProjectionReplayResponse.ProtoReflect.Descriptor instead.
+func (*StopProjectionReplayResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{64}
 }
 
-func (x *ListOrdersResponse) GetOrders() []*Order {
+func (x *StopProjectionReplayResponse) GetReplay() *ProjectionReplay {
 	if x != nil {
-		return x.Orders
+		return x.Replay
 	}
 	return nil
 }
 
-func (x *ListOrdersResponse) GetNextPageToken() string {
-	if x != nil {
-		return x.NextPageToken
-	}
-	return ""
-}
-
-type GetOrderRequest struct {
+type GetProjectionReplayRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	ReplayId      int64                  `protobuf:"varint,1,opt,name=replay_id,json=replayId,proto3" json:"replay_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderRequest) Reset() {
-	*x = GetOrderRequest{}
-	mi := &file_orders_v1_orders_proto_msgTypes[5]
+func (x *GetProjectionReplayRequest) Reset() {
+	*x = GetProjectionReplayRequest{}
+	mi := &file_orders_v1_orders_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderRequest) String() string {
+func (x *GetProjectionReplayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderRequest) ProtoMessage() {}
+func (*GetProjectionReplayRequest) ProtoMessage() {}
 
-func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[5]
+func (x *GetProjectionReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -417,47 +4911,40 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
-func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{5}
-}
-
-func (x *GetOrderRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
+// Deprecated: Use GetProjectionReplayRequest.ProtoReflect.Descriptor instead.
+func (*GetProjectionReplayRequest) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{65}
 }
 
-func (x *GetOrderRequest) GetOrderId() string {
+func (x *GetProjectionReplayRequest) GetReplayId() int64 {
 	if x != nil {
-		return x.OrderId
+		return x.ReplayId
 	}
-	return ""
+	return 0
 }
 
-type GetOrderResponse struct {
+type GetProjectionReplayResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
+	Replay        *ProjectionReplay      `protobuf:"bytes,1,opt,name=replay,proto3" json:"replay,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetOrderResponse) Reset() {
-	*x = GetOrderResponse{}
-	mi := &file_orders_v1_orders_proto_msgTypes[6]
+func (x *GetProjectionReplayResponse) Reset() {
+	*x = GetProjectionReplayResponse{}
+	mi := &file_orders_v1_orders_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetOrderResponse) String() string {
+func (x *GetProjectionReplayResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetOrderResponse) ProtoMessage() {}
+func (*GetProjectionReplayResponse) ProtoMessage() {}
 
-func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_orders_v1_orders_proto_msgTypes[6]
+func (x *GetProjectionReplayResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_orders_v1_orders_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -468,14 +4955,14 @@ func (x *GetOrderResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetOrderResponse.ProtoReflect.Descriptor instead.
-func (*GetOrderResponse) Descriptor() ([]byte, []int) {
-	return file_orders_v1_orders_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use GetProjectionReplayResponse.ProtoReflect.Descriptor instead.
+func (*GetProjectionReplayResponse) Descriptor() ([]byte, []int) {
+	return file_orders_v1_orders_proto_rawDescGZIP(), []int{66}
 }
 
-func (x *GetOrderResponse) GetOrder() *Order {
+func (x *GetProjectionReplayResponse) GetReplay() *ProjectionReplay {
 	if x != nil {
-		return x.Order
+		return x.Replay
 	}
 	return nil
 }
@@ -484,7 +4971,14 @@ var File_orders_v1_orders_proto protoreflect.FileDescriptor
 
 const file_orders_v1_orders_proto_rawDesc = "" +
 	"\n" +
-	"\x16orders/v1/orders.proto\x12\torders.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe0\x01\n" +
+	"\x16orders/v1/orders.proto\x12\torders.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1egoogle/protobuf/duration.proto\x1a google/protobuf/field_mask.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xe6\x01\n" +
+	"\fOrderDispute\x120\n" +
+	"\x06status\x18\x01 \x01(\x0e2\x18.orders.v1.DisputeStatusR\x06status\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x127\n" +
+	"\topened_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x12;\n" +
+	"\vresolved_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\"\xa5\x06\n" +
 	"\x05Order\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
@@ -492,37 +4986,448 @@ const file_orders_v1_orders_proto_rawDesc = "" +
 	"\vdescription\x18\x04 \x01(\tR\vdescription\x12.\n" +
 	"\x06status\x18\x05 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x129\n" +
 	"\n" +
-	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\x90\x01\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x124\n" +
+	"\x16payment_failure_reason\x18\a \x01(\tR\x14paymentFailureReason\x12\x1f\n" +
+	"\vpaid_amount\x18\b \x01(\x03R\n" +
+	"paidAmount\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12:\n" +
+	"\bmetadata\x18\n" +
+	" \x03(\v2\x1e.orders.v1.Order.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\v \x03(\tR\x04tags\x12\x18\n" +
+	"\aversion\x18\f \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x1d\n" +
+	"\n" +
+	"fee_amount\x18\x0e \x01(\x03R\tfeeAmount\x12\x1a\n" +
+	"\barchived\x18\x0f \x01(\bR\barchived\x121\n" +
+	"\x06pay_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\x05payAt\x121\n" +
+	"\adispute\x18\x11 \x01(\v2\x17.orders.v1.OrderDisputeR\adispute\x12.\n" +
+	"\x13last_payment_reason\x18\x12 \x01(\tR\x11lastPaymentReason\x12\x1d\n" +
+	"\n" +
+	"item_count\x18\x13 \x01(\x05R\titemCount\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xb1\x04\n" +
 	"\x12CreateOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12 \n" +
 	"\vdescription\x18\x03 \x01(\tR\vdescription\x12'\n" +
-	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\"=\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12.\n" +
+	"\x13pay_in_installments\x18\x05 \x01(\bR\x11payInInstallments\x12*\n" +
+	"\x05items\x18\x06 \x03(\v2\x14.orders.v1.OrderItemR\x05items\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12G\n" +
+	"\bmetadata\x18\b \x03(\v2+.orders.v1.CreateOrderRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\t \x03(\tR\x04tags\x12\x14\n" +
+	"\x05force\x18\n" +
+	" \x01(\bR\x05force\x121\n" +
+	"\x06pay_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\x05payAt\x12\x1d\n" +
+	"\n" +
+	"promo_code\x18\f \x01(\tR\tpromoCode\x12%\n" +
+	"\x0epayment_method\x18\r \x01(\tR\rpaymentMethod\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"t\n" +
+	"\x15ValidateOrderResponse\x12\x16\n" +
+	"\x06amount\x18\x01 \x01(\x03R\x06amount\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12'\n" +
+	"\x0fdiscount_amount\x18\x03 \x01(\x03R\x0ediscountAmount\"F\n" +
+	"\tOrderItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x03R\bquantity\"f\n" +
 	"\x13CreateOrderResponse\x12&\n" +
-	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\"a\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x12'\n" +
+	"\x0fdiscount_amount\x18\x02 \x01(\x03R\x0ediscountAmount\"\xd7\x01\n" +
 	"\x11ListOrdersRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x1d\n" +
 	"\n" +
-	"page_token\x18\x03 \x01(\tR\tpageToken\"f\n" +
+	"page_token\x18\x03 \x01(\tR\tpageToken\x12\x10\n" +
+	"\x03tag\x18\x04 \x01(\tR\x03tag\x12)\n" +
+	"\x10include_archived\x18\x05 \x01(\bR\x0fincludeArchived\x127\n" +
+	"\tread_mask\x18\x06 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\"f\n" +
 	"\x12ListOrdersResponse\x12(\n" +
 	"\x06orders\x18\x01 \x03(\v2\x10.orders.v1.OrderR\x06orders\x12&\n" +
-	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"E\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"~\n" +
 	"\x0fGetOrderRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
-	"\border_id\x18\x02 \x01(\tR\aorderId\":\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x127\n" +
+	"\tread_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\breadMask\":\n" +
 	"\x10GetOrderResponse\x12&\n" +
-	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order*x\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\"H\n" +
+	"\x10GetOrdersRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\torder_ids\x18\x02 \x03(\tR\borderIds\"i\n" +
+	"\x11GetOrdersResponse\x12(\n" +
+	"\x06orders\x18\x01 \x03(\v2\x10.orders.v1.OrderR\x06orders\x12*\n" +
+	"\x11missing_order_ids\x18\x02 \x03(\tR\x0fmissingOrderIds\"d\n" +
+	"\x16GetOrderReceiptRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06format\x18\x03 \x01(\tR\x06format\"\x86\x01\n" +
+	"\x17GetOrderReceiptResponse\x12,\n" +
+	"\areceipt\x18\x01 \x01(\v2\x12.orders.v1.ReceiptR\areceipt\x12\x1a\n" +
+	"\bdocument\x18\x02 \x01(\fR\bdocument\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\"\xad\x03\n" +
+	"\aReceipt\x12\x16\n" +
+	"\x06number\x18\x01 \x01(\tR\x06number\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12,\n" +
+	"\x05items\x18\x05 \x03(\v2\x16.orders.v1.ReceiptItemR\x05items\x12\x16\n" +
+	"\x06amount\x18\x06 \x01(\x03R\x06amount\x12\x1d\n" +
+	"\n" +
+	"fee_amount\x18\a \x01(\x03R\tfeeAmount\x12!\n" +
+	"\ftotal_amount\x18\b \x01(\x03R\vtotalAmount\x12D\n" +
+	"\x10order_created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\x0eorderCreatedAt\x123\n" +
+	"\apaid_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x06paidAt\x127\n" +
+	"\tissued_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\bissuedAt\"\xb5\x01\n" +
+	"\vReceiptItem\x12\x12\n" +
+	"\x04kind\x18\x01 \x01(\tR\x04kind\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1a\n" +
+	"\bquantity\x18\x03 \x01(\x03R\bquantity\x12\x1d\n" +
+	"\n" +
+	"unit_price\x18\x04 \x01(\x03R\tunitPrice\x12\x16\n" +
+	"\x06amount\x18\x05 \x01(\x03R\x06amount\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x06 \x01(\tR\tproductId\"{\n" +
+	"\x10WaitOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x123\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeout\"X\n" +
+	"\x11WaitOrderResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x12\x1b\n" +
+	"\ttimed_out\x18\x02 \x01(\bR\btimedOut\"\xa2\x01\n" +
+	"\x0fPayOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x04 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\"Y\n" +
+	"\x10PayOrderResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\"\xdb\x02\n" +
+	"\x12UpdateOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12;\n" +
+	"\vupdate_mask\x18\x03 \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12G\n" +
+	"\bmetadata\x18\x05 \x03(\v2+.orders.v1.UpdateOrderRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04tags\x18\x06 \x03(\tR\x04tags\x12\x18\n" +
+	"\aversion\x18\a \x01(\x03R\aversion\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"=\n" +
+	"\x13UpdateOrderResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\"\xfe\x01\n" +
+	"\rOrderTransfer\x12\x1f\n" +
+	"\vtransfer_id\x18\x01 \x01(\tR\n" +
+	"transferId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12 \n" +
+	"\ffrom_user_id\x18\x03 \x01(\tR\n" +
+	"fromUserId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x04 \x01(\tR\btoUserId\x126\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x1e.orders.v1.OrderTransferStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"h\n" +
+	"\x14TransferOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1c\n" +
+	"\n" +
+	"to_user_id\x18\x03 \x01(\tR\btoUserId\"M\n" +
+	"\x15TransferOrderResponse\x124\n" +
+	"\btransfer\x18\x01 \x01(\v2\x18.orders.v1.OrderTransferR\btransfer\"P\n" +
+	"\x1aAcceptOrderTransferRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"E\n" +
+	"\x1bAcceptOrderTransferResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\"H\n" +
+	"\x12CancelOrderRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"=\n" +
+	"\x13CancelOrderResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\"I\n" +
+	"\x13RetryPaymentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"a\n" +
+	"\x14RetryPaymentResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x12!\n" +
+	"\fretries_left\x18\x02 \x01(\x05R\vretriesLeft\"\xa0\x01\n" +
+	"\x13ReplayOutboxRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"e\n" +
+	"\x14ReplayOutboxResponse\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x03R\amatched\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\x03R\breplayed\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"e\n" +
+	"\x15ListDeadOutboxRequest\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\x03R\aafterId\"\xca\x01\n" +
+	"\x0fDeadOutboxEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x03 \x01(\tR\bkafkaKey\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"p\n" +
+	"\x16ListDeadOutboxResponse\x122\n" +
+	"\x06events\x18\x01 \x03(\v2\x1a.orders.v1.DeadOutboxEventR\x06events\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"\x89\x02\n" +
+	"\x11ListOutboxRequest\x12,\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x16.orders.v1.OutboxStateR\x05state\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12=\n" +
+	"\fcreated_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\bafter_id\x18\x06 \x01(\x03R\aafterId\"\xc5\x02\n" +
+	"\vOutboxEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x03 \x01(\tR\bkafkaKey\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12>\n" +
+	"\rnext_retry_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vnextRetryAt\x12%\n" +
+	"\x0ecorrelation_id\x18\t \x01(\tR\rcorrelationId\"h\n" +
+	"\x12ListOutboxResponse\x12.\n" +
+	"\x06events\x18\x01 \x03(\v2\x16.orders.v1.OutboxEventR\x06events\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"T\n" +
+	"\x18RequeueDeadOutboxRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\"7\n" +
+	"\x19RequeueDeadOutboxResponse\x12\x1a\n" +
+	"\brequeued\x18\x01 \x01(\x03R\brequeued\"0\n" +
+	"\x13InspectOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"\xd5\x01\n" +
+	"\x10OrderPaymentStep\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x01 \x01(\tR\tpaymentId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12%\n" +
+	"\x0efailure_reason\x18\x04 \x01(\tR\rfailureReason\x12\x10\n" +
+	"\x03fee\x18\x05 \x01(\x03R\x03fee\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"\xb0\x01\n" +
+	"\x10PaymentRetryStep\x12\x1b\n" +
+	"\tretry_key\x18\x01 \x01(\tR\bretryKey\x12\x1a\n" +
+	"\battempts\x18\x02 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1f\n" +
+	"\vlast_reason\x18\x04 \x01(\tR\n" +
+	"lastReason\"\xa1\x02\n" +
+	"\x0fOutboxEventStep\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x123\n" +
+	"\asent_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\x12%\n" +
+	"\x0ecorrelation_id\x18\b \x01(\tR\rcorrelationId\"\xa7\x02\n" +
+	"\x14InspectOrderResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x127\n" +
+	"\bpayments\x18\x02 \x03(\v2\x1b.orders.v1.OrderPaymentStepR\bpayments\x125\n" +
+	"\aretries\x18\x03 \x03(\v2\x1b.orders.v1.PaymentRetryStepR\aretries\x122\n" +
+	"\x06events\x18\x04 \x03(\v2\x1a.orders.v1.OutboxEventStepR\x06events\x12C\n" +
+	"\x0estatus_history\x18\x05 \x03(\v2\x1c.orders.v1.OrderStatusChangeR\rstatusHistory\"\xc4\x01\n" +
+	"\x11OrderStatusChange\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12'\n" +
+	"\x0fprevious_status\x18\x02 \x01(\tR\x0epreviousStatus\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x12;\n" +
+	"\voccurred_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"|\n" +
+	"\x17ForceOrderStatusRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12.\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x16.orders.v1.OrderStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"\x83\x01\n" +
+	"\x18ForceOrderStatusResponse\x12&\n" +
+	"\x05order\x18\x01 \x01(\v2\x10.orders.v1.OrderR\x05order\x12?\n" +
+	"\x0fprevious_status\x18\x02 \x01(\x0e2\x16.orders.v1.OrderStatusR\x0epreviousStatus\"V\n" +
+	"\x19DryRunInboxMessageRequest\x12\x1a\n" +
+	"\bconsumer\x18\x01 \x01(\tR\bconsumer\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"<\n" +
+	"\x12InboxMessageHeader\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\"\x9d\x03\n" +
+	"\fInboxMessage\x12\x1a\n" +
+	"\bconsumer\x18\x01 \x01(\tR\bconsumer\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12%\n" +
+	"\x0ecorrelation_id\x18\x03 \x01(\tR\rcorrelationId\x12\x14\n" +
+	"\x05topic\x18\x04 \x01(\tR\x05topic\x12\x1c\n" +
+	"\tpartition\x18\x05 \x01(\x05R\tpartition\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12\x10\n" +
+	"\x03key\x18\a \x01(\fR\x03key\x12\x18\n" +
+	"\apayload\x18\b \x01(\fR\apayload\x127\n" +
+	"\aheaders\x18\t \x03(\v2\x1d.orders.v1.InboxMessageHeaderR\aheaders\x12;\n" +
+	"\vreceived_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x12=\n" +
+	"\fprocessed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\"`\n" +
+	"\x11DryRunOutboxEvent\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x02 \x01(\tR\bkafkaKey\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\"\xe1\x01\n" +
+	"\x1aDryRunInboxMessageResponse\x121\n" +
+	"\amessage\x18\x01 \x01(\v2\x17.orders.v1.InboxMessageR\amessage\x12\x1d\n" +
+	"\n" +
+	"event_json\x18\x02 \x01(\tR\teventJson\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\tR\askipped\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12A\n" +
+	"\rwould_publish\x18\x05 \x03(\v2\x1c.orders.v1.DryRunOutboxEventR\fwouldPublish\"\x97\x03\n" +
+	"\tPromoCode\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1f\n" +
+	"\vpercent_off\x18\x02 \x01(\x05R\n" +
+	"percentOff\x12\x1d\n" +
+	"\n" +
+	"amount_off\x18\x03 \x01(\x03R\tamountOff\x12'\n" +
+	"\x0fmax_redemptions\x18\x04 \x01(\x05R\x0emaxRedemptions\x127\n" +
+	"\x18max_redemptions_per_user\x18\x05 \x01(\x05R\x15maxRedemptionsPerUser\x127\n" +
+	"\tstarts_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\bstartsAt\x129\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12%\n" +
+	"\x0eredeemed_count\x18\b \x01(\x05R\rredeemedCount\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"M\n" +
+	"\x16CreatePromoCodeRequest\x123\n" +
+	"\n" +
+	"promo_code\x18\x01 \x01(\v2\x14.orders.v1.PromoCodeR\tpromoCode\"N\n" +
+	"\x17CreatePromoCodeResponse\x123\n" +
+	"\n" +
+	"promo_code\x18\x01 \x01(\v2\x14.orders.v1.PromoCodeR\tpromoCode\")\n" +
+	"\x13GetPromoCodeRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\xd3\x01\n" +
+	"\x14GetPromoCodeResponse\x123\n" +
+	"\n" +
+	"promo_code\x18\x01 \x01(\v2\x14.orders.v1.PromoCodeR\tpromoCode\x12 \n" +
+	"\vredemptions\x18\x02 \x01(\x03R\vredemptions\x12\x1a\n" +
+	"\breleased\x18\x03 \x01(\x03R\breleased\x12%\n" +
+	"\x0ediscount_total\x18\x04 \x01(\x03R\rdiscountTotal\x12!\n" +
+	"\famount_total\x18\x05 \x01(\x03R\vamountTotal\"\x9c\x01\n" +
+	"\x19ProjectionReplayPartition\x12\x1c\n" +
+	"\tpartition\x18\x01 \x01(\x05R\tpartition\x12!\n" +
+	"\fstart_offset\x18\x02 \x01(\x03R\vstartOffset\x12\x1f\n" +
+	"\vnext_offset\x18\x03 \x01(\x03R\n" +
+	"nextOffset\x12\x1d\n" +
+	"\n" +
+	"end_offset\x18\x04 \x01(\x03R\tendOffset\"\xb8\x04\n" +
+	"\x10ProjectionReplay\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1e\n" +
+	"\n" +
+	"projection\x18\x02 \x01(\tR\n" +
+	"projection\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x129\n" +
+	"\x06status\x18\x04 \x01(\x0e2!.orders.v1.ProjectionReplayStatusR\x06status\x12\x1d\n" +
+	"\n" +
+	"rate_limit\x18\x05 \x01(\x05R\trateLimit\x12\x18\n" +
+	"\aapplied\x18\x06 \x01(\x03R\aapplied\x12\x1c\n" +
+	"\tremaining\x18\a \x01(\x03R\tremaining\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12!\n" +
+	"\frequested_by\x18\t \x01(\tR\vrequestedBy\x12\x16\n" +
+	"\x06reason\x18\n" +
+	" \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"created_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fheartbeat_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\vheartbeatAt\x12;\n" +
+	"\vfinished_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12D\n" +
+	"\n" +
+	"partitions\x18\x0e \x03(\v2$.orders.v1.ProjectionReplayPartitionR\n" +
+	"partitions\"u\n" +
+	"\x1cStartProjectionReplayRequest\x12\x1e\n" +
+	"\n" +
+	"projection\x18\x01 \x01(\tR\n" +
+	"projection\x12\x1d\n" +
+	"\n" +
+	"rate_limit\x18\x02 \x01(\x05R\trateLimit\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"T\n" +
+	"\x1dStartProjectionReplayResponse\x123\n" +
+	"\x06replay\x18\x01 \x01(\v2\x1b.orders.v1.ProjectionReplayR\x06replay\"R\n" +
+	"\x1bStopProjectionReplayRequest\x12\x1b\n" +
+	"\treplay_id\x18\x01 \x01(\x03R\breplayId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"S\n" +
+	"\x1cStopProjectionReplayResponse\x123\n" +
+	"\x06replay\x18\x01 \x01(\v2\x1b.orders.v1.ProjectionReplayR\x06replay\"9\n" +
+	"\x1aGetProjectionReplayRequest\x12\x1b\n" +
+	"\treplay_id\x18\x01 \x01(\x03R\breplayId\"R\n" +
+	"\x1bGetProjectionReplayResponse\x123\n" +
+	"\x06replay\x18\x01 \x01(\v2\x1b.orders.v1.ProjectionReplayR\x06replay*\xeb\x01\n" +
 	"\vOrderStatus\x12\x1c\n" +
 	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\x14\n" +
 	"\x10ORDER_STATUS_NEW\x10\x01\x12\x19\n" +
 	"\x15ORDER_STATUS_FINISHED\x10\x02\x12\x1a\n" +
-	"\x16ORDER_STATUS_CANCELLED\x10\x032\xed\x01\n" +
-	"\rOrdersService\x12L\n" +
-	"\vCreateOrder\x12\x1d.orders.v1.CreateOrderRequest\x1a\x1e.orders.v1.CreateOrderResponse\x12I\n" +
+	"\x16ORDER_STATUS_CANCELLED\x10\x03\x12\x1f\n" +
+	"\x1bORDER_STATUS_PARTIALLY_PAID\x10\x04\x12\x1a\n" +
+	"\x16ORDER_STATUS_SCHEDULED\x10\x05\x12\x19\n" +
+	"\x15ORDER_STATUS_DISPUTED\x10\x06\x12\x19\n" +
+	"\x15ORDER_STATUS_REFUNDED\x10\a*\x80\x01\n" +
+	"\rDisputeStatus\x12\x1e\n" +
+	"\x1aDISPUTE_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DISPUTE_STATUS_OPEN\x10\x01\x12\x1b\n" +
+	"\x17DISPUTE_STATUS_REFUNDED\x10\x02\x12\x19\n" +
+	"\x15DISPUTE_STATUS_UPHELD\x10\x03*\xa8\x01\n" +
+	"\x13OrderTransferStatus\x12%\n" +
+	"!ORDER_TRANSFER_STATUS_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dORDER_TRANSFER_STATUS_PENDING\x10\x01\x12\"\n" +
+	"\x1eORDER_TRANSFER_STATUS_ACCEPTED\x10\x02\x12#\n" +
+	"\x1fORDER_TRANSFER_STATUS_CANCELLED\x10\x03*t\n" +
+	"\vOutboxState\x12\x1c\n" +
+	"\x18OUTBOX_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13OUTBOX_STATE_UNSENT\x10\x01\x12\x17\n" +
+	"\x13OUTBOX_STATE_FAILED\x10\x02\x12\x15\n" +
+	"\x11OUTBOX_STATE_DEAD\x10\x03*\xdb\x01\n" +
+	"\x16ProjectionReplayStatus\x12(\n" +
+	"$PROJECTION_REPLAY_STATUS_UNSPECIFIED\x10\x00\x12$\n" +
+	" PROJECTION_REPLAY_STATUS_RUNNING\x10\x01\x12$\n" +
+	" PROJECTION_REPLAY_STATUS_STOPPED\x10\x02\x12&\n" +
+	"\"PROJECTION_REPLAY_STATUS_COMPLETED\x10\x03\x12#\n" +
+	"\x1fPROJECTION_REPLAY_STATUS_FAILED\x10\x042\x93\r\n" +
+	"\rOrdersService\x12s\n" +
+	"\vCreateOrder\x12\x1d.orders.v1.CreateOrderRequest\x1a\x1e.orders.v1.CreateOrderResponse\"%\x82\xd3\xe4\x93\x02\x1f:\x01*\"\x1a/v1/users/{user_id}/orders\x12\x80\x01\n" +
+	"\rValidateOrder\x12\x1d.orders.v1.CreateOrderRequest\x1a .orders.v1.ValidateOrderResponse\".\x82\xd3\xe4\x93\x02(:\x01*\"#/v1/users/{user_id}/orders:validate\x12m\n" +
+	"\n" +
+	"ListOrders\x12\x1c.orders.v1.ListOrdersRequest\x1a\x1d.orders.v1.ListOrdersResponse\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/users/{user_id}/orders\x12r\n" +
+	"\bGetOrder\x12\x1a.orders.v1.GetOrderRequest\x1a\x1b.orders.v1.GetOrderResponse\"-\x82\xd3\xe4\x93\x02'\x12%/v1/users/{user_id}/orders/{order_id}\x12F\n" +
+	"\tGetOrders\x12\x1b.orders.v1.GetOrdersRequest\x1a\x1c.orders.v1.GetOrdersResponse\x12z\n" +
+	"\tWaitOrder\x12\x1b.orders.v1.WaitOrderRequest\x1a\x1c.orders.v1.WaitOrderResponse\"2\x82\xd3\xe4\x93\x02,\x12*/v1/users/{user_id}/orders/{order_id}/wait\x12~\n" +
+	"\bPayOrder\x12\x1a.orders.v1.PayOrderRequest\x1a\x1b.orders.v1.PayOrderResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./v1/users/{user_id}/orders/{order_id}/payments\x12~\n" +
+	"\vUpdateOrder\x12\x1d.orders.v1.UpdateOrderRequest\x1a\x1e.orders.v1.UpdateOrderResponse\"0\x82\xd3\xe4\x93\x02*:\x01*2%/v1/users/{user_id}/orders/{order_id}\x12\x8d\x01\n" +
+	"\rTransferOrder\x12\x1f.orders.v1.TransferOrderRequest\x1a .orders.v1.TransferOrderResponse\"9\x82\xd3\xe4\x93\x023:\x01*\"./v1/users/{user_id}/orders/{order_id}/transfer\x12\xa6\x01\n" +
+	"\x13AcceptOrderTransfer\x12%.orders.v1.AcceptOrderTransferRequest\x1a&.orders.v1.AcceptOrderTransferResponse\"@\x82\xd3\xe4\x93\x02::\x01*\"5/v1/users/{user_id}/orders/{order_id}/transfer/accept\x12\x85\x01\n" +
+	"\vCancelOrder\x12\x1d.orders.v1.CancelOrderRequest\x1a\x1e.orders.v1.CancelOrderResponse\"7\x82\xd3\xe4\x93\x021:\x01*\",/v1/users/{user_id}/orders/{order_id}/cancel\x12\x8f\x01\n" +
+	"\fRetryPayment\x12\x1e.orders.v1.RetryPaymentRequest\x1a\x1f.orders.v1.RetryPaymentResponse\">\x82\xd3\xe4\x93\x028:\x01*\"3/v1/users/{user_id}/orders/{order_id}/retry-payment\x12\x8f\x01\n" +
+	"\x0fGetOrderReceipt\x12!.orders.v1.GetOrderReceiptRequest\x1a\".orders.v1.GetOrderReceiptResponse\"5\x82\xd3\xe4\x93\x02/\x12-/v1/users/{user_id}/orders/{order_id}/receipt2\xde\b\n" +
+	"\x12OrdersAdminService\x12O\n" +
+	"\fReplayOutbox\x12\x1e.orders.v1.ReplayOutboxRequest\x1a\x1f.orders.v1.ReplayOutboxResponse\x12U\n" +
+	"\x0eListDeadOutbox\x12 .orders.v1.ListDeadOutboxRequest\x1a!.orders.v1.ListDeadOutboxResponse\x12I\n" +
 	"\n" +
-	"ListOrders\x12\x1c.orders.v1.ListOrdersRequest\x1a\x1d.orders.v1.ListOrdersResponse\x12C\n" +
-	"\bGetOrder\x12\x1a.orders.v1.GetOrderRequest\x1a\x1b.orders.v1.GetOrderResponseBBZ@github.com/ilyaytrewq/payments-service/gen/go/orders/v1;ordersv1b\x06proto3"
+	"ListOutbox\x12\x1c.orders.v1.ListOutboxRequest\x1a\x1d.orders.v1.ListOutboxResponse\x12^\n" +
+	"\x11RequeueDeadOutbox\x12#.orders.v1.RequeueDeadOutboxRequest\x1a$.orders.v1.RequeueDeadOutboxResponse\x12O\n" +
+	"\fInspectOrder\x12\x1e.orders.v1.InspectOrderRequest\x1a\x1f.orders.v1.InspectOrderResponse\x12[\n" +
+	"\x10ForceOrderStatus\x12\".orders.v1.ForceOrderStatusRequest\x1a#.orders.v1.ForceOrderStatusResponse\x12a\n" +
+	"\x12DryRunInboxMessage\x12$.orders.v1.DryRunInboxMessageRequest\x1a%.orders.v1.DryRunInboxMessageResponse\x12X\n" +
+	"\x0fCreatePromoCode\x12!.orders.v1.CreatePromoCodeRequest\x1a\".orders.v1.CreatePromoCodeResponse\x12O\n" +
+	"\fGetPromoCode\x12\x1e.orders.v1.GetPromoCodeRequest\x1a\x1f.orders.v1.GetPromoCodeResponse\x12j\n" +
+	"\x15StartProjectionReplay\x12'.orders.v1.StartProjectionReplayRequest\x1a(.orders.v1.StartProjectionReplayResponse\x12g\n" +
+	"\x14StopProjectionReplay\x12&.orders.v1.StopProjectionReplayRequest\x1a'.orders.v1.StopProjectionReplayResponse\x12d\n" +
+	"\x13GetProjectionReplay\x12%.orders.v1.GetProjectionReplayRequest\x1a&.orders.v1.GetProjectionReplayResponseBBZ@github.com/ilyaytrewq/payments-service/gen/go/orders/v1;ordersv1b\x06proto3"
 
 var (
 	file_orders_v1_orders_proto_rawDescOnce sync.Once
@@ -536,36 +5441,221 @@ func file_orders_v1_orders_proto_rawDescGZIP() []byte {
 	return file_orders_v1_orders_proto_rawDescData
 }
 
-var file_orders_v1_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_orders_v1_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_orders_v1_orders_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
+var file_orders_v1_orders_proto_msgTypes = make([]protoimpl.MessageInfo, 70)
 var file_orders_v1_orders_proto_goTypes = []any{
-	(OrderStatus)(0),              // 0: orders.v1.OrderStatus
-	(*Order)(nil),                 // 1: orders.v1.Order
-	(*CreateOrderRequest)(nil),    // 2: orders.v1.CreateOrderRequest
-	(*CreateOrderResponse)(nil),   // 3: orders.v1.CreateOrderResponse
-	(*ListOrdersRequest)(nil),     // 4: orders.v1.ListOrdersRequest
-	(*ListOrdersResponse)(nil),    // 5: orders.v1.ListOrdersResponse
-	(*GetOrderRequest)(nil),       // 6: orders.v1.GetOrderRequest
-	(*GetOrderResponse)(nil),      // 7: orders.v1.GetOrderResponse
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(OrderStatus)(0),                      // 0: orders.v1.OrderStatus
+	(DisputeStatus)(0),                    // 1: orders.v1.DisputeStatus
+	(OrderTransferStatus)(0),              // 2: orders.v1.OrderTransferStatus
+	(OutboxState)(0),                      // 3: orders.v1.OutboxState
+	(ProjectionReplayStatus)(0),           // 4: orders.v1.ProjectionReplayStatus
+	(*OrderDispute)(nil),                  // 5: orders.v1.OrderDispute
+	(*Order)(nil),                         // 6: orders.v1.Order
+	(*CreateOrderRequest)(nil),            // 7: orders.v1.CreateOrderRequest
+	(*ValidateOrderResponse)(nil),         // 8: orders.v1.ValidateOrderResponse
+	(*OrderItem)(nil),                     // 9: orders.v1.OrderItem
+	(*CreateOrderResponse)(nil),           // 10: orders.v1.CreateOrderResponse
+	(*ListOrdersRequest)(nil),             // 11: orders.v1.ListOrdersRequest
+	(*ListOrdersResponse)(nil),            // 12: orders.v1.ListOrdersResponse
+	(*GetOrderRequest)(nil),               // 13: orders.v1.GetOrderRequest
+	(*GetOrderResponse)(nil),              // 14: orders.v1.GetOrderResponse
+	(*GetOrdersRequest)(nil),              // 15: orders.v1.GetOrdersRequest
+	(*GetOrdersResponse)(nil),             // 16: orders.v1.GetOrdersResponse
+	(*GetOrderReceiptRequest)(nil),        // 17: orders.v1.GetOrderReceiptRequest
+	(*GetOrderReceiptResponse)(nil),       // 18: orders.v1.GetOrderReceiptResponse
+	(*Receipt)(nil),                       // 19: orders.v1.Receipt
+	(*ReceiptItem)(nil),                   // 20: orders.v1.ReceiptItem
+	(*WaitOrderRequest)(nil),              // 21: orders.v1.WaitOrderRequest
+	(*WaitOrderResponse)(nil),             // 22: orders.v1.WaitOrderResponse
+	(*PayOrderRequest)(nil),               // 23: orders.v1.PayOrderRequest
+	(*PayOrderResponse)(nil),              // 24: orders.v1.PayOrderResponse
+	(*UpdateOrderRequest)(nil),            // 25: orders.v1.UpdateOrderRequest
+	(*UpdateOrderResponse)(nil),           // 26: orders.v1.UpdateOrderResponse
+	(*OrderTransfer)(nil),                 // 27: orders.v1.OrderTransfer
+	(*TransferOrderRequest)(nil),          // 28: orders.v1.TransferOrderRequest
+	(*TransferOrderResponse)(nil),         // 29: orders.v1.TransferOrderResponse
+	(*AcceptOrderTransferRequest)(nil),    // 30: orders.v1.AcceptOrderTransferRequest
+	(*AcceptOrderTransferResponse)(nil),   // 31: orders.v1.AcceptOrderTransferResponse
+	(*CancelOrderRequest)(nil),            // 32: orders.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),           // 33: orders.v1.CancelOrderResponse
+	(*RetryPaymentRequest)(nil),           // 34: orders.v1.RetryPaymentRequest
+	(*RetryPaymentResponse)(nil),          // 35: orders.v1.RetryPaymentResponse
+	(*ReplayOutboxRequest)(nil),           // 36: orders.v1.ReplayOutboxRequest
+	(*ReplayOutboxResponse)(nil),          // 37: orders.v1.ReplayOutboxResponse
+	(*ListDeadOutboxRequest)(nil),         // 38: orders.v1.ListDeadOutboxRequest
+	(*DeadOutboxEvent)(nil),               // 39: orders.v1.DeadOutboxEvent
+	(*ListDeadOutboxResponse)(nil),        // 40: orders.v1.ListDeadOutboxResponse
+	(*ListOutboxRequest)(nil),             // 41: orders.v1.ListOutboxRequest
+	(*OutboxEvent)(nil),                   // 42: orders.v1.OutboxEvent
+	(*ListOutboxResponse)(nil),            // 43: orders.v1.ListOutboxResponse
+	(*RequeueDeadOutboxRequest)(nil),      // 44: orders.v1.RequeueDeadOutboxRequest
+	(*RequeueDeadOutboxResponse)(nil),     // 45: orders.v1.RequeueDeadOutboxResponse
+	(*InspectOrderRequest)(nil),           // 46: orders.v1.InspectOrderRequest
+	(*OrderPaymentStep)(nil),              // 47: orders.v1.OrderPaymentStep
+	(*PaymentRetryStep)(nil),              // 48: orders.v1.PaymentRetryStep
+	(*OutboxEventStep)(nil),               // 49: orders.v1.OutboxEventStep
+	(*InspectOrderResponse)(nil),          // 50: orders.v1.InspectOrderResponse
+	(*OrderStatusChange)(nil),             // 51: orders.v1.OrderStatusChange
+	(*ForceOrderStatusRequest)(nil),       // 52: orders.v1.ForceOrderStatusRequest
+	(*ForceOrderStatusResponse)(nil),      // 53: orders.v1.ForceOrderStatusResponse
+	(*DryRunInboxMessageRequest)(nil),     // 54: orders.v1.DryRunInboxMessageRequest
+	(*InboxMessageHeader)(nil),            // 55: orders.v1.InboxMessageHeader
+	(*InboxMessage)(nil),                  // 56: orders.v1.InboxMessage
+	(*DryRunOutboxEvent)(nil),             // 57: orders.v1.DryRunOutboxEvent
+	(*DryRunInboxMessageResponse)(nil),    // 58: orders.v1.DryRunInboxMessageResponse
+	(*PromoCode)(nil),                     // 59: orders.v1.PromoCode
+	(*CreatePromoCodeRequest)(nil),        // 60: orders.v1.CreatePromoCodeRequest
+	(*CreatePromoCodeResponse)(nil),       // 61: orders.v1.CreatePromoCodeResponse
+	(*GetPromoCodeRequest)(nil),           // 62: orders.v1.GetPromoCodeRequest
+	(*GetPromoCodeResponse)(nil),          // 63: orders.v1.GetPromoCodeResponse
+	(*ProjectionReplayPartition)(nil),     // 64: orders.v1.ProjectionReplayPartition
+	(*ProjectionReplay)(nil),              // 65: orders.v1.ProjectionReplay
+	(*StartProjectionReplayRequest)(nil),  // 66: orders.v1.StartProjectionReplayRequest
+	(*StartProjectionReplayResponse)(nil), // 67: orders.v1.StartProjectionReplayResponse
+	(*StopProjectionReplayRequest)(nil),   // 68: orders.v1.StopProjectionReplayRequest
+	(*StopProjectionReplayResponse)(nil),  // 69: orders.v1.StopProjectionReplayResponse
+	(*GetProjectionReplayRequest)(nil),    // 70: orders.v1.GetProjectionReplayRequest
+	(*GetProjectionReplayResponse)(nil),   // 71: orders.v1.GetProjectionReplayResponse
+	nil,                                   // 72: orders.v1.Order.MetadataEntry
+	nil,                                   // 73: orders.v1.CreateOrderRequest.MetadataEntry
+	nil,                                   // 74: orders.v1.UpdateOrderRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil),         // 75: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),         // 76: google.protobuf.FieldMask
+	(*durationpb.Duration)(nil),           // 77: google.protobuf.Duration
 }
 var file_orders_v1_orders_proto_depIdxs = []int32{
-	0, // 0: orders.v1.Order.status:type_name -> orders.v1.OrderStatus
-	8, // 1: orders.v1.Order.created_at:type_name -> google.protobuf.Timestamp
-	1, // 2: orders.v1.CreateOrderResponse.order:type_name -> orders.v1.Order
-	1, // 3: orders.v1.ListOrdersResponse.orders:type_name -> orders.v1.Order
-	1, // 4: orders.v1.GetOrderResponse.order:type_name -> orders.v1.Order
-	2, // 5: orders.v1.OrdersService.CreateOrder:input_type -> orders.v1.CreateOrderRequest
-	4, // 6: orders.v1.OrdersService.ListOrders:input_type -> orders.v1.ListOrdersRequest
-	6, // 7: orders.v1.OrdersService.GetOrder:input_type -> orders.v1.GetOrderRequest
-	3, // 8: orders.v1.OrdersService.CreateOrder:output_type -> orders.v1.CreateOrderResponse
-	5, // 9: orders.v1.OrdersService.ListOrders:output_type -> orders.v1.ListOrdersResponse
-	7, // 10: orders.v1.OrdersService.GetOrder:output_type -> orders.v1.GetOrderResponse
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	1,   // 0: orders.v1.OrderDispute.status:type_name -> orders.v1.DisputeStatus
+	75,  // 1: orders.v1.OrderDispute.opened_at:type_name -> google.protobuf.Timestamp
+	75,  // 2: orders.v1.OrderDispute.resolved_at:type_name -> google.protobuf.Timestamp
+	0,   // 3: orders.v1.Order.status:type_name -> orders.v1.OrderStatus
+	75,  // 4: orders.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	72,  // 5: orders.v1.Order.metadata:type_name -> orders.v1.Order.MetadataEntry
+	75,  // 6: orders.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	75,  // 7: orders.v1.Order.pay_at:type_name -> google.protobuf.Timestamp
+	5,   // 8: orders.v1.Order.dispute:type_name -> orders.v1.OrderDispute
+	9,   // 9: orders.v1.CreateOrderRequest.items:type_name -> orders.v1.OrderItem
+	73,  // 10: orders.v1.CreateOrderRequest.metadata:type_name -> orders.v1.CreateOrderRequest.MetadataEntry
+	75,  // 11: orders.v1.CreateOrderRequest.pay_at:type_name -> google.protobuf.Timestamp
+	6,   // 12: orders.v1.CreateOrderResponse.order:type_name -> orders.v1.Order
+	76,  // 13: orders.v1.ListOrdersRequest.read_mask:type_name -> google.protobuf.FieldMask
+	6,   // 14: orders.v1.ListOrdersResponse.orders:type_name -> orders.v1.Order
+	76,  // 15: orders.v1.GetOrderRequest.read_mask:type_name -> google.protobuf.FieldMask
+	6,   // 16: orders.v1.GetOrderResponse.order:type_name -> orders.v1.Order
+	6,   // 17: orders.v1.GetOrdersResponse.orders:type_name -> orders.v1.Order
+	19,  // 18: orders.v1.GetOrderReceiptResponse.receipt:type_name -> orders.v1.Receipt
+	20,  // 19: orders.v1.Receipt.items:type_name -> orders.v1.ReceiptItem
+	75,  // 20: orders.v1.Receipt.order_created_at:type_name -> google.protobuf.Timestamp
+	75,  // 21: orders.v1.Receipt.paid_at:type_name -> google.protobuf.Timestamp
+	75,  // 22: orders.v1.Receipt.issued_at:type_name -> google.protobuf.Timestamp
+	77,  // 23: orders.v1.WaitOrderRequest.timeout:type_name -> google.protobuf.Duration
+	6,   // 24: orders.v1.WaitOrderResponse.order:type_name -> orders.v1.Order
+	6,   // 25: orders.v1.PayOrderResponse.order:type_name -> orders.v1.Order
+	76,  // 26: orders.v1.UpdateOrderRequest.update_mask:type_name -> google.protobuf.FieldMask
+	74,  // 27: orders.v1.UpdateOrderRequest.metadata:type_name -> orders.v1.UpdateOrderRequest.MetadataEntry
+	6,   // 28: orders.v1.UpdateOrderResponse.order:type_name -> orders.v1.Order
+	2,   // 29: orders.v1.OrderTransfer.status:type_name -> orders.v1.OrderTransferStatus
+	75,  // 30: orders.v1.OrderTransfer.created_at:type_name -> google.protobuf.Timestamp
+	27,  // 31: orders.v1.TransferOrderResponse.transfer:type_name -> orders.v1.OrderTransfer
+	6,   // 32: orders.v1.AcceptOrderTransferResponse.order:type_name -> orders.v1.Order
+	6,   // 33: orders.v1.CancelOrderResponse.order:type_name -> orders.v1.Order
+	6,   // 34: orders.v1.RetryPaymentResponse.order:type_name -> orders.v1.Order
+	75,  // 35: orders.v1.ReplayOutboxRequest.from:type_name -> google.protobuf.Timestamp
+	75,  // 36: orders.v1.ReplayOutboxRequest.to:type_name -> google.protobuf.Timestamp
+	75,  // 37: orders.v1.DeadOutboxEvent.created_at:type_name -> google.protobuf.Timestamp
+	39,  // 38: orders.v1.ListDeadOutboxResponse.events:type_name -> orders.v1.DeadOutboxEvent
+	3,   // 39: orders.v1.ListOutboxRequest.state:type_name -> orders.v1.OutboxState
+	75,  // 40: orders.v1.ListOutboxRequest.created_from:type_name -> google.protobuf.Timestamp
+	75,  // 41: orders.v1.ListOutboxRequest.created_to:type_name -> google.protobuf.Timestamp
+	75,  // 42: orders.v1.OutboxEvent.created_at:type_name -> google.protobuf.Timestamp
+	75,  // 43: orders.v1.OutboxEvent.next_retry_at:type_name -> google.protobuf.Timestamp
+	42,  // 44: orders.v1.ListOutboxResponse.events:type_name -> orders.v1.OutboxEvent
+	75,  // 45: orders.v1.OrderPaymentStep.created_at:type_name -> google.protobuf.Timestamp
+	75,  // 46: orders.v1.PaymentRetryStep.next_attempt_at:type_name -> google.protobuf.Timestamp
+	75,  // 47: orders.v1.OutboxEventStep.created_at:type_name -> google.protobuf.Timestamp
+	75,  // 48: orders.v1.OutboxEventStep.sent_at:type_name -> google.protobuf.Timestamp
+	6,   // 49: orders.v1.InspectOrderResponse.order:type_name -> orders.v1.Order
+	47,  // 50: orders.v1.InspectOrderResponse.payments:type_name -> orders.v1.OrderPaymentStep
+	48,  // 51: orders.v1.InspectOrderResponse.retries:type_name -> orders.v1.PaymentRetryStep
+	49,  // 52: orders.v1.InspectOrderResponse.events:type_name -> orders.v1.OutboxEventStep
+	51,  // 53: orders.v1.InspectOrderResponse.status_history:type_name -> orders.v1.OrderStatusChange
+	75,  // 54: orders.v1.OrderStatusChange.occurred_at:type_name -> google.protobuf.Timestamp
+	0,   // 55: orders.v1.ForceOrderStatusRequest.status:type_name -> orders.v1.OrderStatus
+	6,   // 56: orders.v1.ForceOrderStatusResponse.order:type_name -> orders.v1.Order
+	0,   // 57: orders.v1.ForceOrderStatusResponse.previous_status:type_name -> orders.v1.OrderStatus
+	55,  // 58: orders.v1.InboxMessage.headers:type_name -> orders.v1.InboxMessageHeader
+	75,  // 59: orders.v1.InboxMessage.received_at:type_name -> google.protobuf.Timestamp
+	75,  // 60: orders.v1.InboxMessage.processed_at:type_name -> google.protobuf.Timestamp
+	56,  // 61: orders.v1.DryRunInboxMessageResponse.message:type_name -> orders.v1.InboxMessage
+	57,  // 62: orders.v1.DryRunInboxMessageResponse.would_publish:type_name -> orders.v1.DryRunOutboxEvent
+	75,  // 63: orders.v1.PromoCode.starts_at:type_name -> google.protobuf.Timestamp
+	75,  // 64: orders.v1.PromoCode.expires_at:type_name -> google.protobuf.Timestamp
+	75,  // 65: orders.v1.PromoCode.created_at:type_name -> google.protobuf.Timestamp
+	59,  // 66: orders.v1.CreatePromoCodeRequest.promo_code:type_name -> orders.v1.PromoCode
+	59,  // 67: orders.v1.CreatePromoCodeResponse.promo_code:type_name -> orders.v1.PromoCode
+	59,  // 68: orders.v1.GetPromoCodeResponse.promo_code:type_name -> orders.v1.PromoCode
+	4,   // 69: orders.v1.ProjectionReplay.status:type_name -> orders.v1.ProjectionReplayStatus
+	75,  // 70: orders.v1.ProjectionReplay.created_at:type_name -> google.protobuf.Timestamp
+	75,  // 71: orders.v1.ProjectionReplay.heartbeat_at:type_name -> google.protobuf.Timestamp
+	75,  // 72: orders.v1.ProjectionReplay.finished_at:type_name -> google.protobuf.Timestamp
+	64,  // 73: orders.v1.ProjectionReplay.partitions:type_name -> orders.v1.ProjectionReplayPartition
+	65,  // 74: orders.v1.StartProjectionReplayResponse.replay:type_name -> orders.v1.ProjectionReplay
+	65,  // 75: orders.v1.StopProjectionReplayResponse.replay:type_name -> orders.v1.ProjectionReplay
+	65,  // 76: orders.v1.GetProjectionReplayResponse.replay:type_name -> orders.v1.ProjectionReplay
+	7,   // 77: orders.v1.OrdersService.CreateOrder:input_type -> orders.v1.CreateOrderRequest
+	7,   // 78: orders.v1.OrdersService.ValidateOrder:input_type -> orders.v1.CreateOrderRequest
+	11,  // 79: orders.v1.OrdersService.ListOrders:input_type -> orders.v1.ListOrdersRequest
+	13,  // 80: orders.v1.OrdersService.GetOrder:input_type -> orders.v1.GetOrderRequest
+	15,  // 81: orders.v1.OrdersService.GetOrders:input_type -> orders.v1.GetOrdersRequest
+	21,  // 82: orders.v1.OrdersService.WaitOrder:input_type -> orders.v1.WaitOrderRequest
+	23,  // 83: orders.v1.OrdersService.PayOrder:input_type -> orders.v1.PayOrderRequest
+	25,  // 84: orders.v1.OrdersService.UpdateOrder:input_type -> orders.v1.UpdateOrderRequest
+	28,  // 85: orders.v1.OrdersService.TransferOrder:input_type -> orders.v1.TransferOrderRequest
+	30,  // 86: orders.v1.OrdersService.AcceptOrderTransfer:input_type -> orders.v1.AcceptOrderTransferRequest
+	32,  // 87: orders.v1.OrdersService.CancelOrder:input_type -> orders.v1.CancelOrderRequest
+	34,  // 88: orders.v1.OrdersService.RetryPayment:input_type -> orders.v1.RetryPaymentRequest
+	17,  // 89: orders.v1.OrdersService.GetOrderReceipt:input_type -> orders.v1.GetOrderReceiptRequest
+	36,  // 90: orders.v1.OrdersAdminService.ReplayOutbox:input_type -> orders.v1.ReplayOutboxRequest
+	38,  // 91: orders.v1.OrdersAdminService.ListDeadOutbox:input_type -> orders.v1.ListDeadOutboxRequest
+	41,  // 92: orders.v1.OrdersAdminService.ListOutbox:input_type -> orders.v1.ListOutboxRequest
+	44,  // 93: orders.v1.OrdersAdminService.RequeueDeadOutbox:input_type -> orders.v1.RequeueDeadOutboxRequest
+	46,  // 94: orders.v1.OrdersAdminService.InspectOrder:input_type -> orders.v1.InspectOrderRequest
+	52,  // 95: orders.v1.OrdersAdminService.ForceOrderStatus:input_type -> orders.v1.ForceOrderStatusRequest
+	54,  // 96: orders.v1.OrdersAdminService.DryRunInboxMessage:input_type -> orders.v1.DryRunInboxMessageRequest
+	60,  // 97: orders.v1.OrdersAdminService.CreatePromoCode:input_type -> orders.v1.CreatePromoCodeRequest
+	62,  // 98: orders.v1.OrdersAdminService.GetPromoCode:input_type -> orders.v1.GetPromoCodeRequest
+	66,  // 99: orders.v1.OrdersAdminService.StartProjectionReplay:input_type -> orders.v1.StartProjectionReplayRequest
+	68,  // 100: orders.v1.OrdersAdminService.StopProjectionReplay:input_type -> orders.v1.StopProjectionReplayRequest
+	70,  // 101: orders.v1.OrdersAdminService.GetProjectionReplay:input_type -> orders.v1.GetProjectionReplayRequest
+	10,  // 102: orders.v1.OrdersService.CreateOrder:output_type -> orders.v1.CreateOrderResponse
+	8,   // 103: orders.v1.OrdersService.ValidateOrder:output_type -> orders.v1.ValidateOrderResponse
+	12,  // 104: orders.v1.OrdersService.ListOrders:output_type -> orders.v1.ListOrdersResponse
+	14,  // 105: orders.v1.OrdersService.GetOrder:output_type -> orders.v1.GetOrderResponse
+	16,  // 106: orders.v1.OrdersService.GetOrders:output_type -> orders.v1.GetOrdersResponse
+	22,  // 107: orders.v1.OrdersService.WaitOrder:output_type -> orders.v1.WaitOrderResponse
+	24,  // 108: orders.v1.OrdersService.PayOrder:output_type -> orders.v1.PayOrderResponse
+	26,  // 109: orders.v1.OrdersService.UpdateOrder:output_type -> orders.v1.UpdateOrderResponse
+	29,  // 110: orders.v1.OrdersService.TransferOrder:output_type -> orders.v1.TransferOrderResponse
+	31,  // 111: orders.v1.OrdersService.AcceptOrderTransfer:output_type -> orders.v1.AcceptOrderTransferResponse
+	33,  // 112: orders.v1.OrdersService.CancelOrder:output_type -> orders.v1.CancelOrderResponse
+	35,  // 113: orders.v1.OrdersService.RetryPayment:output_type -> orders.v1.RetryPaymentResponse
+	18,  // 114: orders.v1.OrdersService.GetOrderReceipt:output_type -> orders.v1.GetOrderReceiptResponse
+	37,  // 115: orders.v1.OrdersAdminService.ReplayOutbox:output_type -> orders.v1.ReplayOutboxResponse
+	40,  // 116: orders.v1.OrdersAdminService.ListDeadOutbox:output_type -> orders.v1.ListDeadOutboxResponse
+	43,  // 117: orders.v1.OrdersAdminService.ListOutbox:output_type -> orders.v1.ListOutboxResponse
+	45,  // 118: orders.v1.OrdersAdminService.RequeueDeadOutbox:output_type -> orders.v1.RequeueDeadOutboxResponse
+	50,  // 119: orders.v1.OrdersAdminService.InspectOrder:output_type -> orders.v1.InspectOrderResponse
+	53,  // 120: orders.v1.OrdersAdminService.ForceOrderStatus:output_type -> orders.v1.ForceOrderStatusResponse
+	58,  // 121: orders.v1.OrdersAdminService.DryRunInboxMessage:output_type -> orders.v1.DryRunInboxMessageResponse
+	61,  // 122: orders.v1.OrdersAdminService.CreatePromoCode:output_type -> orders.v1.CreatePromoCodeResponse
+	63,  // 123: orders.v1.OrdersAdminService.GetPromoCode:output_type -> orders.v1.GetPromoCodeResponse
+	67,  // 124: orders.v1.OrdersAdminService.StartProjectionReplay:output_type -> orders.v1.StartProjectionReplayResponse
+	69,  // 125: orders.v1.OrdersAdminService.StopProjectionReplay:output_type -> orders.v1.StopProjectionReplayResponse
+	71,  // 126: orders.v1.OrdersAdminService.GetProjectionReplay:output_type -> orders.v1.GetProjectionReplayResponse
+	102, // [102:127] is the sub-list for method output_type
+	77,  // [77:102] is the sub-list for method input_type
+	77,  // [77:77] is the sub-list for extension type_name
+	77,  // [77:77] is the sub-list for extension extendee
+	0,   // [0:77] is the sub-list for field type_name
 }
 
 func init() { file_orders_v1_orders_proto_init() }
@@ -578,10 +5668,10 @@ func file_orders_v1_orders_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_orders_v1_orders_proto_rawDesc), len(file_orders_v1_orders_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   7,
+			NumEnums:      5,
+			NumMessages:   70,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_orders_v1_orders_proto_goTypes,
 		DependencyIndexes: file_orders_v1_orders_proto_depIdxs,
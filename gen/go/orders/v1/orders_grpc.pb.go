@@ -19,18 +19,61 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	OrdersService_CreateOrder_FullMethodName = "/orders.v1.OrdersService/CreateOrder"
-	OrdersService_ListOrders_FullMethodName  = "/orders.v1.OrdersService/ListOrders"
-	OrdersService_GetOrder_FullMethodName    = "/orders.v1.OrdersService/GetOrder"
+	OrdersService_CreateOrder_FullMethodName         = "/orders.v1.OrdersService/CreateOrder"
+	OrdersService_ValidateOrder_FullMethodName       = "/orders.v1.OrdersService/ValidateOrder"
+	OrdersService_ListOrders_FullMethodName          = "/orders.v1.OrdersService/ListOrders"
+	OrdersService_GetOrder_FullMethodName            = "/orders.v1.OrdersService/GetOrder"
+	OrdersService_GetOrders_FullMethodName           = "/orders.v1.OrdersService/GetOrders"
+	OrdersService_WaitOrder_FullMethodName           = "/orders.v1.OrdersService/WaitOrder"
+	OrdersService_PayOrder_FullMethodName            = "/orders.v1.OrdersService/PayOrder"
+	OrdersService_UpdateOrder_FullMethodName         = "/orders.v1.OrdersService/UpdateOrder"
+	OrdersService_TransferOrder_FullMethodName       = "/orders.v1.OrdersService/TransferOrder"
+	OrdersService_AcceptOrderTransfer_FullMethodName = "/orders.v1.OrdersService/AcceptOrderTransfer"
+	OrdersService_CancelOrder_FullMethodName         = "/orders.v1.OrdersService/CancelOrder"
+	OrdersService_RetryPayment_FullMethodName        = "/orders.v1.OrdersService/RetryPayment"
+	OrdersService_GetOrderReceipt_FullMethodName     = "/orders.v1.OrdersService/GetOrderReceipt"
 )
 
 // OrdersServiceClient is the client API for OrdersService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The google.api.http options expose the service over REST through
+// grpc-gateway when the service runs without the separate api-gateway.
 type OrdersServiceClient interface {
 	CreateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*CreateOrderResponse, error)
+	// Runs every CreateOrder check, including the payment account pre-check,
+	// and persists nothing. Fails with the error CreateOrder would return.
+	ValidateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*ValidateOrderResponse, error)
+	// Reads the orders_read projection, which follows the orders with a short
+	// lag: a change may take a moment to show up. GetOrder is always current.
 	ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error)
 	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*GetOrderResponse, error)
+	// GetOrder for up to 100 orders of one user in one call, for callers that
+	// compose views of many orders. Orders that do not exist or belong to
+	// another user, and ids that are not UUIDs, are listed in missing_order_ids.
+	GetOrders(ctx context.Context, in *GetOrdersRequest, opts ...grpc.CallOption) (*GetOrdersResponse, error)
+	// Long poll: answers once the order has left NEW or the timeout expired,
+	// whichever comes first.
+	WaitOrder(ctx context.Context, in *WaitOrderRequest, opts ...grpc.CallOption) (*WaitOrderResponse, error)
+	PayOrder(ctx context.Context, in *PayOrderRequest, opts ...grpc.CallOption) (*PayOrderResponse, error)
+	// Changes the description, metadata or tags of an order that is still NEW.
+	UpdateOrder(ctx context.Context, in *UpdateOrderRequest, opts ...grpc.CallOption) (*UpdateOrderResponse, error)
+	// Offers an unfinished order to another user. Ownership moves only when
+	// the recipient calls AcceptOrderTransfer; a new offer replaces a pending one.
+	TransferOrder(ctx context.Context, in *TransferOrderRequest, opts ...grpc.CallOption) (*TransferOrderResponse, error)
+	// Called by the recipient, as user_id, to take over an offered order.
+	AcceptOrderTransfer(ctx context.Context, in *AcceptOrderTransferRequest, opts ...grpc.CallOption) (*AcceptOrderTransferResponse, error)
+	// Cancels a SCHEDULED order before its payment is due. Orders whose
+	// payment has been requested can no longer be cancelled.
+	CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error)
+	// Requests the payment of an order cancelled for insufficient funds
+	// again: the order goes back to NEW and a new PaymentRequested is
+	// published. Each order can be retried a bounded number of times.
+	RetryPayment(ctx context.Context, in *RetryPaymentRequest, opts ...grpc.CallOption) (*RetryPaymentResponse, error)
+	// Returns the receipt of a FINISHED order, optionally rendered as a
+	// document. Fails with FAILED_PRECONDITION for orders not finished.
+	GetOrderReceipt(ctx context.Context, in *GetOrderReceiptRequest, opts ...grpc.CallOption) (*GetOrderReceiptResponse, error)
 }
 
 type ordersServiceClient struct {
@@ -51,6 +94,16 @@ func (c *ordersServiceClient) CreateOrder(ctx context.Context, in *CreateOrderRe
 	return out, nil
 }
 
+func (c *ordersServiceClient) ValidateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*ValidateOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ValidateOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_ValidateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *ordersServiceClient) ListOrders(ctx context.Context, in *ListOrdersRequest, opts ...grpc.CallOption) (*ListOrdersResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(ListOrdersResponse)
@@ -71,13 +124,136 @@ func (c *ordersServiceClient) GetOrder(ctx context.Context, in *GetOrderRequest,
 	return out, nil
 }
 
+func (c *ordersServiceClient) GetOrders(ctx context.Context, in *GetOrdersRequest, opts ...grpc.CallOption) (*GetOrdersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrdersResponse)
+	err := c.cc.Invoke(ctx, OrdersService_GetOrders_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) WaitOrder(ctx context.Context, in *WaitOrderRequest, opts ...grpc.CallOption) (*WaitOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WaitOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_WaitOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) PayOrder(ctx context.Context, in *PayOrderRequest, opts ...grpc.CallOption) (*PayOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PayOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_PayOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) UpdateOrder(ctx context.Context, in *UpdateOrderRequest, opts ...grpc.CallOption) (*UpdateOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UpdateOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_UpdateOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) TransferOrder(ctx context.Context, in *TransferOrderRequest, opts ...grpc.CallOption) (*TransferOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_TransferOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) AcceptOrderTransfer(ctx context.Context, in *AcceptOrderTransferRequest, opts ...grpc.CallOption) (*AcceptOrderTransferResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AcceptOrderTransferResponse)
+	err := c.cc.Invoke(ctx, OrdersService_AcceptOrderTransfer_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) CancelOrder(ctx context.Context, in *CancelOrderRequest, opts ...grpc.CallOption) (*CancelOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CancelOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersService_CancelOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) RetryPayment(ctx context.Context, in *RetryPaymentRequest, opts ...grpc.CallOption) (*RetryPaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RetryPaymentResponse)
+	err := c.cc.Invoke(ctx, OrdersService_RetryPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersServiceClient) GetOrderReceipt(ctx context.Context, in *GetOrderReceiptRequest, opts ...grpc.CallOption) (*GetOrderReceiptResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetOrderReceiptResponse)
+	err := c.cc.Invoke(ctx, OrdersService_GetOrderReceipt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrdersServiceServer is the server API for OrdersService service.
 // All implementations should embed UnimplementedOrdersServiceServer
 // for forward compatibility.
+//
+// The google.api.http options expose the service over REST through
+// grpc-gateway when the service runs without the separate api-gateway.
 type OrdersServiceServer interface {
 	CreateOrder(context.Context, *CreateOrderRequest) (*CreateOrderResponse, error)
+	// Runs every CreateOrder check, including the payment account pre-check,
+	// and persists nothing. Fails with the error CreateOrder would return.
+	ValidateOrder(context.Context, *CreateOrderRequest) (*ValidateOrderResponse, error)
+	// Reads the orders_read projection, which follows the orders with a short
+	// lag: a change may take a moment to show up. GetOrder is always current.
 	ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error)
 	GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error)
+	// GetOrder for up to 100 orders of one user in one call, for callers that
+	// compose views of many orders. Orders that do not exist or belong to
+	// another user, and ids that are not UUIDs, are listed in missing_order_ids.
+	GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error)
+	// Long poll: answers once the order has left NEW or the timeout expired,
+	// whichever comes first.
+	WaitOrder(context.Context, *WaitOrderRequest) (*WaitOrderResponse, error)
+	PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error)
+	// Changes the description, metadata or tags of an order that is still NEW.
+	UpdateOrder(context.Context, *UpdateOrderRequest) (*UpdateOrderResponse, error)
+	// Offers an unfinished order to another user. Ownership moves only when
+	// the recipient calls AcceptOrderTransfer; a new offer replaces a pending one.
+	TransferOrder(context.Context, *TransferOrderRequest) (*TransferOrderResponse, error)
+	// Called by the recipient, as user_id, to take over an offered order.
+	AcceptOrderTransfer(context.Context, *AcceptOrderTransferRequest) (*AcceptOrderTransferResponse, error)
+	// Cancels a SCHEDULED order before its payment is due. Orders whose
+	// payment has been requested can no longer be cancelled.
+	CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error)
+	// Requests the payment of an order cancelled for insufficient funds
+	// again: the order goes back to NEW and a new PaymentRequested is
+	// published. Each order can be retried a bounded number of times.
+	RetryPayment(context.Context, *RetryPaymentRequest) (*RetryPaymentResponse, error)
+	// Returns the receipt of a FINISHED order, optionally rendered as a
+	// document. Fails with FAILED_PRECONDITION for orders not finished.
+	GetOrderReceipt(context.Context, *GetOrderReceiptRequest) (*GetOrderReceiptResponse, error)
 }
 
 // UnimplementedOrdersServiceServer should be embedded to have
@@ -90,12 +266,42 @@ type UnimplementedOrdersServiceServer struct{}
 func (UnimplementedOrdersServiceServer) CreateOrder(context.Context, *CreateOrderRequest) (*CreateOrderResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method CreateOrder not implemented")
 }
+func (UnimplementedOrdersServiceServer) ValidateOrder(context.Context, *CreateOrderRequest) (*ValidateOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ValidateOrder not implemented")
+}
 func (UnimplementedOrdersServiceServer) ListOrders(context.Context, *ListOrdersRequest) (*ListOrdersResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method ListOrders not implemented")
 }
 func (UnimplementedOrdersServiceServer) GetOrder(context.Context, *GetOrderRequest) (*GetOrderResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
 }
+func (UnimplementedOrdersServiceServer) GetOrders(context.Context, *GetOrdersRequest) (*GetOrdersResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrders not implemented")
+}
+func (UnimplementedOrdersServiceServer) WaitOrder(context.Context, *WaitOrderRequest) (*WaitOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method WaitOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) PayOrder(context.Context, *PayOrderRequest) (*PayOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method PayOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) UpdateOrder(context.Context, *UpdateOrderRequest) (*UpdateOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) TransferOrder(context.Context, *TransferOrderRequest) (*TransferOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method TransferOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) AcceptOrderTransfer(context.Context, *AcceptOrderTransferRequest) (*AcceptOrderTransferResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AcceptOrderTransfer not implemented")
+}
+func (UnimplementedOrdersServiceServer) CancelOrder(context.Context, *CancelOrderRequest) (*CancelOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CancelOrder not implemented")
+}
+func (UnimplementedOrdersServiceServer) RetryPayment(context.Context, *RetryPaymentRequest) (*RetryPaymentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RetryPayment not implemented")
+}
+func (UnimplementedOrdersServiceServer) GetOrderReceipt(context.Context, *GetOrderReceiptRequest) (*GetOrderReceiptResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetOrderReceipt not implemented")
+}
 func (UnimplementedOrdersServiceServer) testEmbeddedByValue() {}
 
 // UnsafeOrdersServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -134,6 +340,24 @@ func _OrdersService_CreateOrder_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrdersService_ValidateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).ValidateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_ValidateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).ValidateOrder(ctx, req.(*CreateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrdersService_ListOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(ListOrdersRequest)
 	if err := dec(in); err != nil {
@@ -170,6 +394,168 @@ func _OrdersService_GetOrder_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrdersService_GetOrders_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrdersRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).GetOrders(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_GetOrders_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).GetOrders(ctx, req.(*GetOrdersRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_WaitOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WaitOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).WaitOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_WaitOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).WaitOrder(ctx, req.(*WaitOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_PayOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PayOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).PayOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_PayOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).PayOrder(ctx, req.(*PayOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_UpdateOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).UpdateOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_UpdateOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).UpdateOrder(ctx, req.(*UpdateOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_TransferOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).TransferOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_TransferOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).TransferOrder(ctx, req.(*TransferOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_AcceptOrderTransfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AcceptOrderTransferRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).AcceptOrderTransfer(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_AcceptOrderTransfer_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).AcceptOrderTransfer(ctx, req.(*AcceptOrderTransferRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_CancelOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).CancelOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_CancelOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).CancelOrder(ctx, req.(*CancelOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_RetryPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RetryPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).RetryPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_RetryPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).RetryPayment(ctx, req.(*RetryPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersService_GetOrderReceipt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetOrderReceiptRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersServiceServer).GetOrderReceipt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersService_GetOrderReceipt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersServiceServer).GetOrderReceipt(ctx, req.(*GetOrderReceiptRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrdersService_ServiceDesc is the grpc.ServiceDesc for OrdersService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -181,6 +567,10 @@ var OrdersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "CreateOrder",
 			Handler:    _OrdersService_CreateOrder_Handler,
 		},
+		{
+			MethodName: "ValidateOrder",
+			Handler:    _OrdersService_ValidateOrder_Handler,
+		},
 		{
 			MethodName: "ListOrders",
 			Handler:    _OrdersService_ListOrders_Handler,
@@ -189,6 +579,632 @@ var OrdersService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetOrder",
 			Handler:    _OrdersService_GetOrder_Handler,
 		},
+		{
+			MethodName: "GetOrders",
+			Handler:    _OrdersService_GetOrders_Handler,
+		},
+		{
+			MethodName: "WaitOrder",
+			Handler:    _OrdersService_WaitOrder_Handler,
+		},
+		{
+			MethodName: "PayOrder",
+			Handler:    _OrdersService_PayOrder_Handler,
+		},
+		{
+			MethodName: "UpdateOrder",
+			Handler:    _OrdersService_UpdateOrder_Handler,
+		},
+		{
+			MethodName: "TransferOrder",
+			Handler:    _OrdersService_TransferOrder_Handler,
+		},
+		{
+			MethodName: "AcceptOrderTransfer",
+			Handler:    _OrdersService_AcceptOrderTransfer_Handler,
+		},
+		{
+			MethodName: "CancelOrder",
+			Handler:    _OrdersService_CancelOrder_Handler,
+		},
+		{
+			MethodName: "RetryPayment",
+			Handler:    _OrdersService_RetryPayment_Handler,
+		},
+		{
+			MethodName: "GetOrderReceipt",
+			Handler:    _OrdersService_GetOrderReceipt_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "orders/v1/orders.proto",
+}
+
+const (
+	OrdersAdminService_ReplayOutbox_FullMethodName          = "/orders.v1.OrdersAdminService/ReplayOutbox"
+	OrdersAdminService_ListDeadOutbox_FullMethodName        = "/orders.v1.OrdersAdminService/ListDeadOutbox"
+	OrdersAdminService_ListOutbox_FullMethodName            = "/orders.v1.OrdersAdminService/ListOutbox"
+	OrdersAdminService_RequeueDeadOutbox_FullMethodName     = "/orders.v1.OrdersAdminService/RequeueDeadOutbox"
+	OrdersAdminService_InspectOrder_FullMethodName          = "/orders.v1.OrdersAdminService/InspectOrder"
+	OrdersAdminService_ForceOrderStatus_FullMethodName      = "/orders.v1.OrdersAdminService/ForceOrderStatus"
+	OrdersAdminService_DryRunInboxMessage_FullMethodName    = "/orders.v1.OrdersAdminService/DryRunInboxMessage"
+	OrdersAdminService_CreatePromoCode_FullMethodName       = "/orders.v1.OrdersAdminService/CreatePromoCode"
+	OrdersAdminService_GetPromoCode_FullMethodName          = "/orders.v1.OrdersAdminService/GetPromoCode"
+	OrdersAdminService_StartProjectionReplay_FullMethodName = "/orders.v1.OrdersAdminService/StartProjectionReplay"
+	OrdersAdminService_StopProjectionReplay_FullMethodName  = "/orders.v1.OrdersAdminService/StopProjectionReplay"
+	OrdersAdminService_GetProjectionReplay_FullMethodName   = "/orders.v1.OrdersAdminService/GetProjectionReplay"
+)
+
+// OrdersAdminServiceClient is the client API for OrdersAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Operator RPCs. Registered only when ENABLE_ADMIN_API is set and, with RBAC
+// on, callable by the admin role only.
+type OrdersAdminServiceClient interface {
+	// Re-emits already sent outbox events by inserting copies, e.g. after a
+	// topic was recreated. Copies are published like any new event.
+	ReplayOutbox(ctx context.Context, in *ReplayOutboxRequest, opts ...grpc.CallOption) (*ReplayOutboxResponse, error)
+	// Lists outbox events that were dead-lettered after OUTBOX_MAX_ATTEMPTS
+	// failed publishes, oldest first.
+	ListDeadOutbox(ctx context.Context, in *ListDeadOutboxRequest, opts ...grpc.CallOption) (*ListDeadOutboxResponse, error)
+	// Lists outbox events in one state, oldest first, so stuck events can be
+	// diagnosed without database access. Read-only;
+	// with RBAC on, the support role may call it too.
+	ListOutbox(ctx context.Context, in *ListOutboxRequest, opts ...grpc.CallOption) (*ListOutboxResponse, error)
+	// Puts dead-lettered outbox events back in the queue with their attempts
+	// reset. Later events of the same key may already have been sent, so a
+	// requeued event can arrive after them.
+	RequeueDeadOutbox(ctx context.Context, in *RequeueDeadOutboxRequest, opts ...grpc.CallOption) (*RequeueDeadOutboxResponse, error)
+	// Returns an order of any user, archived ones included, with the state of
+	// its payment saga: installments, scheduled retries and the outbox events
+	// keyed by the order.
+	InspectOrder(ctx context.Context, in *InspectOrderRequest, opts ...grpc.CallOption) (*InspectOrderResponse, error)
+	// Sets the status of an order regardless of its payments, e.g. after a
+	// payment was settled by hand. Recorded in admin_audit_log with the
+	// operator and reason. SCHEDULED, DISPUTED and REFUNDED cannot be forced;
+	// disputes go through PaymentsAdminService.
+	ForceOrderStatus(ctx context.Context, in *ForceOrderStatusRequest, opts ...grpc.CallOption) (*ForceOrderStatusResponse, error)
+	// Runs a message kept in the inbox with INBOX_STORE_PAYLOADS through its
+	// consumer again, processed or not, and rolls everything back. Returns
+	// what the consumer did: why it skipped the message, the error it failed
+	// with, or the events it would have published.
+	DryRunInboxMessage(ctx context.Context, in *DryRunInboxMessageRequest, opts ...grpc.CallOption) (*DryRunInboxMessageResponse, error)
+	// Creates a promo code for CreateOrder. Codes are stored upper-cased and
+	// cannot be changed or deleted; let one expire instead.
+	CreatePromoCode(ctx context.Context, in *CreatePromoCodeRequest, opts ...grpc.CallOption) (*CreatePromoCodeResponse, error)
+	// Returns a promo code with a report of its redemptions. Read-only; with
+	// RBAC on, the support role may call it too.
+	GetPromoCode(ctx context.Context, in *GetPromoCodeRequest, opts ...grpc.CallOption) (*GetPromoCodeResponse, error)
+	// Rebuilds a projection from its topic: empties it and reads the topic from
+	// the earliest offset up to the end offsets at the start, in the
+	// background. Recorded in admin_audit_log. A projection has at most one
+	// running replay.
+	StartProjectionReplay(ctx context.Context, in *StartProjectionReplayRequest, opts ...grpc.CallOption) (*StartProjectionReplayResponse, error)
+	// Stops a running replay; the projection stays partly rebuilt until the
+	// next replay. Recorded in admin_audit_log.
+	StopProjectionReplay(ctx context.Context, in *StopProjectionReplayRequest, opts ...grpc.CallOption) (*StopProjectionReplayResponse, error)
+	// Returns a replay with its progress per partition. Read-only; with RBAC
+	// on, the support role may call it too.
+	GetProjectionReplay(ctx context.Context, in *GetProjectionReplayRequest, opts ...grpc.CallOption) (*GetProjectionReplayResponse, error)
+}
+
+type ordersAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOrdersAdminServiceClient(cc grpc.ClientConnInterface) OrdersAdminServiceClient {
+	return &ordersAdminServiceClient{cc}
+}
+
+func (c *ordersAdminServiceClient) ReplayOutbox(ctx context.Context, in *ReplayOutboxRequest, opts ...grpc.CallOption) (*ReplayOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayOutboxResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_ReplayOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) ListDeadOutbox(ctx context.Context, in *ListDeadOutboxRequest, opts ...grpc.CallOption) (*ListDeadOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadOutboxResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_ListDeadOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) ListOutbox(ctx context.Context, in *ListOutboxRequest, opts ...grpc.CallOption) (*ListOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOutboxResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_ListOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) RequeueDeadOutbox(ctx context.Context, in *RequeueDeadOutboxRequest, opts ...grpc.CallOption) (*RequeueDeadOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequeueDeadOutboxResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_RequeueDeadOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) InspectOrder(ctx context.Context, in *InspectOrderRequest, opts ...grpc.CallOption) (*InspectOrderResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(InspectOrderResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_InspectOrder_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) ForceOrderStatus(ctx context.Context, in *ForceOrderStatusRequest, opts ...grpc.CallOption) (*ForceOrderStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ForceOrderStatusResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_ForceOrderStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) DryRunInboxMessage(ctx context.Context, in *DryRunInboxMessageRequest, opts ...grpc.CallOption) (*DryRunInboxMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DryRunInboxMessageResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_DryRunInboxMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) CreatePromoCode(ctx context.Context, in *CreatePromoCodeRequest, opts ...grpc.CallOption) (*CreatePromoCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreatePromoCodeResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_CreatePromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) GetPromoCode(ctx context.Context, in *GetPromoCodeRequest, opts ...grpc.CallOption) (*GetPromoCodeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPromoCodeResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_GetPromoCode_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) StartProjectionReplay(ctx context.Context, in *StartProjectionReplayRequest, opts ...grpc.CallOption) (*StartProjectionReplayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StartProjectionReplayResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_StartProjectionReplay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) StopProjectionReplay(ctx context.Context, in *StopProjectionReplayRequest, opts ...grpc.CallOption) (*StopProjectionReplayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(StopProjectionReplayResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_StopProjectionReplay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *ordersAdminServiceClient) GetProjectionReplay(ctx context.Context, in *GetProjectionReplayRequest, opts ...grpc.CallOption) (*GetProjectionReplayResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetProjectionReplayResponse)
+	err := c.cc.Invoke(ctx, OrdersAdminService_GetProjectionReplay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OrdersAdminServiceServer is the server API for OrdersAdminService service.
+// All implementations should embed UnimplementedOrdersAdminServiceServer
+// for forward compatibility.
+//
+// Operator RPCs. Registered only when ENABLE_ADMIN_API is set and, with RBAC
+// on, callable by the admin role only.
+type OrdersAdminServiceServer interface {
+	// Re-emits already sent outbox events by inserting copies, e.g. after a
+	// topic was recreated. Copies are published like any new event.
+	ReplayOutbox(context.Context, *ReplayOutboxRequest) (*ReplayOutboxResponse, error)
+	// Lists outbox events that were dead-lettered after OUTBOX_MAX_ATTEMPTS
+	// failed publishes, oldest first.
+	ListDeadOutbox(context.Context, *ListDeadOutboxRequest) (*ListDeadOutboxResponse, error)
+	// Lists outbox events in one state, oldest first, so stuck events can be
+	// diagnosed without database access. Read-only;
+	// with RBAC on, the support role may call it too.
+	ListOutbox(context.Context, *ListOutboxRequest) (*ListOutboxResponse, error)
+	// Puts dead-lettered outbox events back in the queue with their attempts
+	// reset. Later events of the same key may already have been sent, so a
+	// requeued event can arrive after them.
+	RequeueDeadOutbox(context.Context, *RequeueDeadOutboxRequest) (*RequeueDeadOutboxResponse, error)
+	// Returns an order of any user, archived ones included, with the state of
+	// its payment saga: installments, scheduled retries and the outbox events
+	// keyed by the order.
+	InspectOrder(context.Context, *InspectOrderRequest) (*InspectOrderResponse, error)
+	// Sets the status of an order regardless of its payments, e.g. after a
+	// payment was settled by hand. Recorded in admin_audit_log with the
+	// operator and reason. SCHEDULED, DISPUTED and REFUNDED cannot be forced;
+	// disputes go through PaymentsAdminService.
+	ForceOrderStatus(context.Context, *ForceOrderStatusRequest) (*ForceOrderStatusResponse, error)
+	// Runs a message kept in the inbox with INBOX_STORE_PAYLOADS through its
+	// consumer again, processed or not, and rolls everything back. Returns
+	// what the consumer did: why it skipped the message, the error it failed
+	// with, or the events it would have published.
+	DryRunInboxMessage(context.Context, *DryRunInboxMessageRequest) (*DryRunInboxMessageResponse, error)
+	// Creates a promo code for CreateOrder. Codes are stored upper-cased and
+	// cannot be changed or deleted; let one expire instead.
+	CreatePromoCode(context.Context, *CreatePromoCodeRequest) (*CreatePromoCodeResponse, error)
+	// Returns a promo code with a report of its redemptions. Read-only; with
+	// RBAC on, the support role may call it too.
+	GetPromoCode(context.Context, *GetPromoCodeRequest) (*GetPromoCodeResponse, error)
+	// Rebuilds a projection from its topic: empties it and reads the topic from
+	// the earliest offset up to the end offsets at the start, in the
+	// background. Recorded in admin_audit_log. A projection has at most one
+	// running replay.
+	StartProjectionReplay(context.Context, *StartProjectionReplayRequest) (*StartProjectionReplayResponse, error)
+	// Stops a running replay; the projection stays partly rebuilt until the
+	// next replay. Recorded in admin_audit_log.
+	StopProjectionReplay(context.Context, *StopProjectionReplayRequest) (*StopProjectionReplayResponse, error)
+	// Returns a replay with its progress per partition. Read-only; with RBAC
+	// on, the support role may call it too.
+	GetProjectionReplay(context.Context, *GetProjectionReplayRequest) (*GetProjectionReplayResponse, error)
+}
+
+// UnimplementedOrdersAdminServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOrdersAdminServiceServer struct{}
+
+func (UnimplementedOrdersAdminServiceServer) ReplayOutbox(context.Context, *ReplayOutboxRequest) (*ReplayOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReplayOutbox not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) ListDeadOutbox(context.Context, *ListDeadOutboxRequest) (*ListDeadOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDeadOutbox not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) ListOutbox(context.Context, *ListOutboxRequest) (*ListOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOutbox not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) RequeueDeadOutbox(context.Context, *RequeueDeadOutboxRequest) (*RequeueDeadOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequeueDeadOutbox not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) InspectOrder(context.Context, *InspectOrderRequest) (*InspectOrderResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method InspectOrder not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) ForceOrderStatus(context.Context, *ForceOrderStatusRequest) (*ForceOrderStatusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ForceOrderStatus not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) DryRunInboxMessage(context.Context, *DryRunInboxMessageRequest) (*DryRunInboxMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DryRunInboxMessage not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) CreatePromoCode(context.Context, *CreatePromoCodeRequest) (*CreatePromoCodeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreatePromoCode not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) GetPromoCode(context.Context, *GetPromoCodeRequest) (*GetPromoCodeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetPromoCode not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) StartProjectionReplay(context.Context, *StartProjectionReplayRequest) (*StartProjectionReplayResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StartProjectionReplay not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) StopProjectionReplay(context.Context, *StopProjectionReplayRequest) (*StopProjectionReplayResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method StopProjectionReplay not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) GetProjectionReplay(context.Context, *GetProjectionReplayRequest) (*GetProjectionReplayResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetProjectionReplay not implemented")
+}
+func (UnimplementedOrdersAdminServiceServer) testEmbeddedByValue() {}
+
+// UnsafeOrdersAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OrdersAdminServiceServer will
+// result in compilation errors.
+type UnsafeOrdersAdminServiceServer interface {
+	mustEmbedUnimplementedOrdersAdminServiceServer()
+}
+
+func RegisterOrdersAdminServiceServer(s grpc.ServiceRegistrar, srv OrdersAdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedOrdersAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OrdersAdminService_ServiceDesc, srv)
+}
+
+func _OrdersAdminService_ReplayOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).ReplayOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_ReplayOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).ReplayOutbox(ctx, req.(*ReplayOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_ListDeadOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).ListDeadOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_ListDeadOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).ListDeadOutbox(ctx, req.(*ListDeadOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_ListOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).ListOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_ListOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).ListOutbox(ctx, req.(*ListOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_RequeueDeadOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueDeadOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).RequeueDeadOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_RequeueDeadOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).RequeueDeadOutbox(ctx, req.(*RequeueDeadOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_InspectOrder_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InspectOrderRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).InspectOrder(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_InspectOrder_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).InspectOrder(ctx, req.(*InspectOrderRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_ForceOrderStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ForceOrderStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).ForceOrderStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_ForceOrderStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).ForceOrderStatus(ctx, req.(*ForceOrderStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_DryRunInboxMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunInboxMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).DryRunInboxMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_DryRunInboxMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).DryRunInboxMessage(ctx, req.(*DryRunInboxMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_CreatePromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).CreatePromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_CreatePromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).CreatePromoCode(ctx, req.(*CreatePromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_GetPromoCode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPromoCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).GetPromoCode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_GetPromoCode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).GetPromoCode(ctx, req.(*GetPromoCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_StartProjectionReplay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartProjectionReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).StartProjectionReplay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_StartProjectionReplay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).StartProjectionReplay(ctx, req.(*StartProjectionReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_StopProjectionReplay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StopProjectionReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).StopProjectionReplay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_StopProjectionReplay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).StopProjectionReplay(ctx, req.(*StopProjectionReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrdersAdminService_GetProjectionReplay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetProjectionReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrdersAdminServiceServer).GetProjectionReplay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrdersAdminService_GetProjectionReplay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrdersAdminServiceServer).GetProjectionReplay(ctx, req.(*GetProjectionReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// OrdersAdminService_ServiceDesc is the grpc.ServiceDesc for OrdersAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OrdersAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "orders.v1.OrdersAdminService",
+	HandlerType: (*OrdersAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReplayOutbox",
+			Handler:    _OrdersAdminService_ReplayOutbox_Handler,
+		},
+		{
+			MethodName: "ListDeadOutbox",
+			Handler:    _OrdersAdminService_ListDeadOutbox_Handler,
+		},
+		{
+			MethodName: "ListOutbox",
+			Handler:    _OrdersAdminService_ListOutbox_Handler,
+		},
+		{
+			MethodName: "RequeueDeadOutbox",
+			Handler:    _OrdersAdminService_RequeueDeadOutbox_Handler,
+		},
+		{
+			MethodName: "InspectOrder",
+			Handler:    _OrdersAdminService_InspectOrder_Handler,
+		},
+		{
+			MethodName: "ForceOrderStatus",
+			Handler:    _OrdersAdminService_ForceOrderStatus_Handler,
+		},
+		{
+			MethodName: "DryRunInboxMessage",
+			Handler:    _OrdersAdminService_DryRunInboxMessage_Handler,
+		},
+		{
+			MethodName: "CreatePromoCode",
+			Handler:    _OrdersAdminService_CreatePromoCode_Handler,
+		},
+		{
+			MethodName: "GetPromoCode",
+			Handler:    _OrdersAdminService_GetPromoCode_Handler,
+		},
+		{
+			MethodName: "StartProjectionReplay",
+			Handler:    _OrdersAdminService_StartProjectionReplay_Handler,
+		},
+		{
+			MethodName: "StopProjectionReplay",
+			Handler:    _OrdersAdminService_StopProjectionReplay_Handler,
+		},
+		{
+			MethodName: "GetProjectionReplay",
+			Handler:    _OrdersAdminService_GetProjectionReplay_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "orders/v1/orders.proto",
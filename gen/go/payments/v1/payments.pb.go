@@ -7,8 +7,10 @@
 package paymentsv1
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 	unsafe "unsafe"
@@ -21,10 +23,347 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Account tier; the per-type limits, overdraft and fees are service config.
+type AccountType int32
+
+const (
+	AccountType_ACCOUNT_TYPE_UNSPECIFIED AccountType = 0
+	AccountType_ACCOUNT_TYPE_BASIC       AccountType = 1
+	AccountType_ACCOUNT_TYPE_PREMIUM     AccountType = 2
+	AccountType_ACCOUNT_TYPE_BUSINESS    AccountType = 3
+)
+
+// Enum value maps for AccountType.
+var (
+	AccountType_name = map[int32]string{
+		0: "ACCOUNT_TYPE_UNSPECIFIED",
+		1: "ACCOUNT_TYPE_BASIC",
+		2: "ACCOUNT_TYPE_PREMIUM",
+		3: "ACCOUNT_TYPE_BUSINESS",
+	}
+	AccountType_value = map[string]int32{
+		"ACCOUNT_TYPE_UNSPECIFIED": 0,
+		"ACCOUNT_TYPE_BASIC":       1,
+		"ACCOUNT_TYPE_PREMIUM":     2,
+		"ACCOUNT_TYPE_BUSINESS":    3,
+	}
+)
+
+func (x AccountType) Enum() *AccountType {
+	p := new(AccountType)
+	*p = x
+	return p
+}
+
+func (x AccountType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (AccountType) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[0].Descriptor()
+}
+
+func (AccountType) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[0]
+}
+
+func (x AccountType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use AccountType.Descriptor instead.
+func (AccountType) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{0}
+}
+
+type TransactionKind int32
+
+const (
+	TransactionKind_TRANSACTION_KIND_UNSPECIFIED TransactionKind = 0
+	// Credits not tied to a payment: top-ups and opening balances.
+	TransactionKind_TRANSACTION_KIND_TOP_UP  TransactionKind = 1
+	TransactionKind_TRANSACTION_KIND_PAYMENT TransactionKind = 2
+	TransactionKind_TRANSACTION_KIND_FEE     TransactionKind = 3
+	// Bonus grants (positive) and bonus spent on payments (negative); they
+	// move bonus_balance, not balance.
+	TransactionKind_TRANSACTION_KIND_BONUS TransactionKind = 4
+	// Operator corrections made with PaymentsAdminService.AdjustBalance.
+	TransactionKind_TRANSACTION_KIND_ADJUSTMENT TransactionKind = 5
+)
+
+// Enum value maps for TransactionKind.
+var (
+	TransactionKind_name = map[int32]string{
+		0: "TRANSACTION_KIND_UNSPECIFIED",
+		1: "TRANSACTION_KIND_TOP_UP",
+		2: "TRANSACTION_KIND_PAYMENT",
+		3: "TRANSACTION_KIND_FEE",
+		4: "TRANSACTION_KIND_BONUS",
+		5: "TRANSACTION_KIND_ADJUSTMENT",
+	}
+	TransactionKind_value = map[string]int32{
+		"TRANSACTION_KIND_UNSPECIFIED": 0,
+		"TRANSACTION_KIND_TOP_UP":      1,
+		"TRANSACTION_KIND_PAYMENT":     2,
+		"TRANSACTION_KIND_FEE":         3,
+		"TRANSACTION_KIND_BONUS":       4,
+		"TRANSACTION_KIND_ADJUSTMENT":  5,
+	}
+)
+
+func (x TransactionKind) Enum() *TransactionKind {
+	p := new(TransactionKind)
+	*p = x
+	return p
+}
+
+func (x TransactionKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TransactionKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[1].Descriptor()
+}
+
+func (TransactionKind) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[1]
+}
+
+func (x TransactionKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TransactionKind.Descriptor instead.
+func (TransactionKind) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{1}
+}
+
+type ConfirmPaymentStatus int32
+
+const (
+	ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_UNSPECIFIED ConfirmPaymentStatus = 0
+	ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_SUCCEEDED   ConfirmPaymentStatus = 1
+	// The payment method declined the payment or the funds were short; reason
+	// says which.
+	ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_FAILED ConfirmPaymentStatus = 2
+	// The payment method settles the payment later.
+	ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_PENDING ConfirmPaymentStatus = 3
+)
+
+// Enum value maps for ConfirmPaymentStatus.
+var (
+	ConfirmPaymentStatus_name = map[int32]string{
+		0: "CONFIRM_PAYMENT_STATUS_UNSPECIFIED",
+		1: "CONFIRM_PAYMENT_STATUS_SUCCEEDED",
+		2: "CONFIRM_PAYMENT_STATUS_FAILED",
+		3: "CONFIRM_PAYMENT_STATUS_PENDING",
+	}
+	ConfirmPaymentStatus_value = map[string]int32{
+		"CONFIRM_PAYMENT_STATUS_UNSPECIFIED": 0,
+		"CONFIRM_PAYMENT_STATUS_SUCCEEDED":   1,
+		"CONFIRM_PAYMENT_STATUS_FAILED":      2,
+		"CONFIRM_PAYMENT_STATUS_PENDING":     3,
+	}
+)
+
+func (x ConfirmPaymentStatus) Enum() *ConfirmPaymentStatus {
+	p := new(ConfirmPaymentStatus)
+	*p = x
+	return p
+}
+
+func (x ConfirmPaymentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ConfirmPaymentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[2].Descriptor()
+}
+
+func (ConfirmPaymentStatus) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[2]
+}
+
+func (x ConfirmPaymentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ConfirmPaymentStatus.Descriptor instead.
+func (ConfirmPaymentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{2}
+}
+
+// Which outbox events ListOutbox returns.
+type OutboxState int32
+
+const (
+	OutboxState_OUTBOX_STATE_UNSPECIFIED OutboxState = 0
+	// Not published yet: waiting for the publisher or backing off after
+	// failed publishes; dead-lettered events excluded.
+	OutboxState_OUTBOX_STATE_UNSENT OutboxState = 1
+	// Backing off after at least one failed publish; last_error tells why.
+	OutboxState_OUTBOX_STATE_FAILED OutboxState = 2
+	// Dead-lettered after OUTBOX_MAX_ATTEMPTS failed publishes.
+	OutboxState_OUTBOX_STATE_DEAD OutboxState = 3
+)
+
+// Enum value maps for OutboxState.
+var (
+	OutboxState_name = map[int32]string{
+		0: "OUTBOX_STATE_UNSPECIFIED",
+		1: "OUTBOX_STATE_UNSENT",
+		2: "OUTBOX_STATE_FAILED",
+		3: "OUTBOX_STATE_DEAD",
+	}
+	OutboxState_value = map[string]int32{
+		"OUTBOX_STATE_UNSPECIFIED": 0,
+		"OUTBOX_STATE_UNSENT":      1,
+		"OUTBOX_STATE_FAILED":      2,
+		"OUTBOX_STATE_DEAD":        3,
+	}
+)
+
+func (x OutboxState) Enum() *OutboxState {
+	p := new(OutboxState)
+	*p = x
+	return p
+}
+
+func (x OutboxState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OutboxState) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[3].Descriptor()
+}
+
+func (OutboxState) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[3]
+}
+
+func (x OutboxState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OutboxState.Descriptor instead.
+func (OutboxState) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{3}
+}
+
+type DisputeStatus int32
+
+const (
+	DisputeStatus_DISPUTE_STATUS_UNSPECIFIED DisputeStatus = 0
+	DisputeStatus_DISPUTE_STATUS_OPEN        DisputeStatus = 1
+	DisputeStatus_DISPUTE_STATUS_REFUNDED    DisputeStatus = 2
+	DisputeStatus_DISPUTE_STATUS_UPHELD      DisputeStatus = 3
+)
+
+// Enum value maps for DisputeStatus.
+var (
+	DisputeStatus_name = map[int32]string{
+		0: "DISPUTE_STATUS_UNSPECIFIED",
+		1: "DISPUTE_STATUS_OPEN",
+		2: "DISPUTE_STATUS_REFUNDED",
+		3: "DISPUTE_STATUS_UPHELD",
+	}
+	DisputeStatus_value = map[string]int32{
+		"DISPUTE_STATUS_UNSPECIFIED": 0,
+		"DISPUTE_STATUS_OPEN":        1,
+		"DISPUTE_STATUS_REFUNDED":    2,
+		"DISPUTE_STATUS_UPHELD":      3,
+	}
+)
+
+func (x DisputeStatus) Enum() *DisputeStatus {
+	p := new(DisputeStatus)
+	*p = x
+	return p
+}
+
+func (x DisputeStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[4].Descriptor()
+}
+
+func (DisputeStatus) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[4]
+}
+
+func (x DisputeStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeStatus.Descriptor instead.
+func (DisputeStatus) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{4}
+}
+
+type DisputeResolution int32
+
+const (
+	DisputeResolution_DISPUTE_RESOLUTION_UNSPECIFIED DisputeResolution = 0
+	DisputeResolution_DISPUTE_RESOLUTION_REFUND      DisputeResolution = 1
+	DisputeResolution_DISPUTE_RESOLUTION_UPHOLD      DisputeResolution = 2
+)
+
+// Enum value maps for DisputeResolution.
+var (
+	DisputeResolution_name = map[int32]string{
+		0: "DISPUTE_RESOLUTION_UNSPECIFIED",
+		1: "DISPUTE_RESOLUTION_REFUND",
+		2: "DISPUTE_RESOLUTION_UPHOLD",
+	}
+	DisputeResolution_value = map[string]int32{
+		"DISPUTE_RESOLUTION_UNSPECIFIED": 0,
+		"DISPUTE_RESOLUTION_REFUND":      1,
+		"DISPUTE_RESOLUTION_UPHOLD":      2,
+	}
+)
+
+func (x DisputeResolution) Enum() *DisputeResolution {
+	p := new(DisputeResolution)
+	*p = x
+	return p
+}
+
+func (x DisputeResolution) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DisputeResolution) Descriptor() protoreflect.EnumDescriptor {
+	return file_payments_v1_payments_proto_enumTypes[5].Descriptor()
+}
+
+func (DisputeResolution) Type() protoreflect.EnumType {
+	return &file_payments_v1_payments_proto_enumTypes[5]
+}
+
+func (x DisputeResolution) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DisputeResolution.Descriptor instead.
+func (DisputeResolution) EnumDescriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{5}
+}
+
 type Account struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Balance       int64                  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"` // minimal currency units
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	UserId   string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance  int64                  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`  // minimal currency units
+	Currency string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217
+	// Bumped by every balance change; pass it as TopUpRequest.expected_version
+	// for a conditional top-up.
+	Version int64 `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	// How far below zero deductions may take the balance; 0 for most accounts.
+	OverdraftLimit int64       `protobuf:"varint,5,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	AccountType    AccountType `protobuf:"varint,6,opt,name=account_type,json=accountType,proto3,enum=payments.v1.AccountType" json:"account_type,omitempty"`
+	// Unexpired promotional credit; payments spend it before balance.
+	BonusBalance  int64 `protobuf:"varint,7,opt,name=bonus_balance,json=bonusBalance,proto3" json:"bonus_balance,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +412,41 @@ func (x *Account) GetBalance() int64 {
 	return 0
 }
 
+func (x *Account) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Account) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Account) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+func (x *Account) GetAccountType() AccountType {
+	if x != nil {
+		return x.AccountType
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *Account) GetBonusBalance() int64 {
+	if x != nil {
+		return x.BonusBalance
+	}
+	return 0
+}
+
 type CreateAccountRequest struct {
 	state  protoimpl.MessageState `protogen:"open.v1"`
 	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
@@ -176,8 +550,13 @@ type TopUpRequest struct {
 	Amount int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
 	// Optional: forwarded from REST Idempotency-Key
 	IdempotencyKey string `protobuf:"bytes,3,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	// Optional ISO 4217 code; must match the service currency when set.
+	Currency string `protobuf:"bytes,4,opt,name=currency,proto3" json:"currency,omitempty"`
+	// Optional: when set, the top-up applies only if the account is still at
+	// this version, otherwise it fails with ABORTED.
+	ExpectedVersion int64 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *TopUpRequest) Reset() {
@@ -231,6 +610,20 @@ func (x *TopUpRequest) GetIdempotencyKey() string {
 	return ""
 }
 
+func (x *TopUpRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *TopUpRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
 type TopUpResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
@@ -320,8 +713,13 @@ func (x *GetBalanceRequest) GetUserId() string {
 }
 
 type GetBalanceResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Balance       int64                  `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Balance     int64                  `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	Currency    string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	Version     int64                  `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	AccountType AccountType            `protobuf:"varint,4,opt,name=account_type,json=accountType,proto3,enum=payments.v1.AccountType" json:"account_type,omitempty"`
+	// Unexpired promotional credit; payments spend it before balance.
+	BonusBalance  int64 `protobuf:"varint,5,opt,name=bonus_balance,json=bonusBalance,proto3" json:"bonus_balance,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -363,34 +761,3200 @@ func (x *GetBalanceResponse) GetBalance() int64 {
 	return 0
 }
 
-var File_payments_v1_payments_proto protoreflect.FileDescriptor
+func (x *GetBalanceResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
 
-const file_payments_v1_payments_proto_rawDesc = "" +
-	"\n" +
-	"\x1apayments/v1/payments.proto\x12\vpayments.v1\"<\n" +
-	"\aAccount\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
-	"\abalance\x18\x02 \x01(\x03R\abalance\"X\n" +
-	"\x14CreateAccountRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
-	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"G\n" +
-	"\x15CreateAccountResponse\x12.\n" +
-	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\"h\n" +
-	"\fTopUpRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
-	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
-	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\"?\n" +
-	"\rTopUpResponse\x12.\n" +
-	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\",\n" +
-	"\x11GetBalanceRequest\x12\x17\n" +
-	"\auser_id\x18\x01 \x01(\tR\x06userId\".\n" +
-	"\x12GetBalanceResponse\x12\x18\n" +
-	"\abalance\x18\x01 \x01(\x03R\abalance2\xf8\x01\n" +
-	"\x0fPaymentsService\x12V\n" +
-	"\rCreateAccount\x12!.payments.v1.CreateAccountRequest\x1a\".payments.v1.CreateAccountResponse\x12>\n" +
-	"\x05TopUp\x12\x19.payments.v1.TopUpRequest\x1a\x1a.payments.v1.TopUpResponse\x12M\n" +
+func (x *GetBalanceResponse) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *GetBalanceResponse) GetAccountType() AccountType {
+	if x != nil {
+		return x.AccountType
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *GetBalanceResponse) GetBonusBalance() int64 {
+	if x != nil {
+		return x.BonusBalance
+	}
+	return 0
+}
+
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserIds       []string               `protobuf:"bytes,1,rep,name=user_ids,json=userIds,proto3" json:"user_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetBalancesRequest) GetUserIds() []string {
+	if x != nil {
+		return x.UserIds
+	}
+	return nil
+}
+
+type AccountBalance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Balance       int64                  `protobuf:"varint,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	AccountType   AccountType            `protobuf:"varint,5,opt,name=account_type,json=accountType,proto3,enum=payments.v1.AccountType" json:"account_type,omitempty"`
+	BonusBalance  int64                  `protobuf:"varint,6,opt,name=bonus_balance,json=bonusBalance,proto3" json:"bonus_balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountBalance) Reset() {
+	*x = AccountBalance{}
+	mi := &file_payments_v1_payments_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountBalance) ProtoMessage() {}
+
+func (x *AccountBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountBalance.ProtoReflect.Descriptor instead.
+func (*AccountBalance) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AccountBalance) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AccountBalance) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *AccountBalance) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *AccountBalance) GetAccountType() AccountType {
+	if x != nil {
+		return x.AccountType
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+func (x *AccountBalance) GetBonusBalance() int64 {
+	if x != nil {
+		return x.BonusBalance
+	}
+	return 0
+}
+
+type GetBalancesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// In the order of the request, duplicates removed.
+	Balances       []*AccountBalance `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	MissingUserIds []string          `protobuf:"bytes,2,rep,name=missing_user_ids,json=missingUserIds,proto3" json:"missing_user_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetBalancesResponse) GetBalances() []*AccountBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *GetBalancesResponse) GetMissingUserIds() []string {
+	if x != nil {
+		return x.MissingUserIds
+	}
+	return nil
+}
+
+type GetBalanceAtRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceAtRequest) Reset() {
+	*x = GetBalanceAtRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceAtRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceAtRequest) ProtoMessage() {}
+
+func (x *GetBalanceAtRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceAtRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceAtRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetBalanceAtRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetBalanceAtRequest) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+type GetBalanceAtResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balance       int64                  `protobuf:"varint,1,opt,name=balance,proto3" json:"balance,omitempty"`
+	At            *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	Currency      string                 `protobuf:"bytes,3,opt,name=currency,proto3" json:"currency,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceAtResponse) Reset() {
+	*x = GetBalanceAtResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceAtResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceAtResponse) ProtoMessage() {}
+
+func (x *GetBalanceAtResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceAtResponse.ProtoReflect.Descriptor instead.
+func (*GetBalanceAtResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetBalanceAtResponse) GetBalance() int64 {
+	if x != nil {
+		return x.Balance
+	}
+	return 0
+}
+
+func (x *GetBalanceAtResponse) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *GetBalanceAtResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type Transaction struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Signed change in minimal currency units.
+	Amount int64           `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	Kind   TransactionKind `protobuf:"varint,3,opt,name=kind,proto3,enum=payments.v1.TransactionKind" json:"kind,omitempty"`
+	// Set for entries booked by a payment.
+	PaymentId string                 `protobuf:"bytes,4,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	OrderId   string                 `protobuf:"bytes,5,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// amount converted to ListTransactionsResponse.display_currency at the
+	// rate recorded at created_at, and that rate as the decimal price of one
+	// unit of currency in display_currency. Set only when a display currency
+	// was requested and a rate is recorded for the time of the entry.
+	DisplayAmount int64  `protobuf:"varint,7,opt,name=display_amount,json=displayAmount,proto3" json:"display_amount,omitempty"`
+	DisplayRate   string `protobuf:"bytes,8,opt,name=display_rate,json=displayRate,proto3" json:"display_rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Transaction) Reset() {
+	*x = Transaction{}
+	mi := &file_payments_v1_payments_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Transaction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Transaction) ProtoMessage() {}
+
+func (x *Transaction) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Transaction.ProtoReflect.Descriptor instead.
+func (*Transaction) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Transaction) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Transaction) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Transaction) GetKind() TransactionKind {
+	if x != nil {
+		return x.Kind
+	}
+	return TransactionKind_TRANSACTION_KIND_UNSPECIFIED
+}
+
+func (x *Transaction) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *Transaction) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Transaction) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Transaction) GetDisplayAmount() int64 {
+	if x != nil {
+		return x.DisplayAmount
+	}
+	return 0
+}
+
+func (x *Transaction) GetDisplayRate() string {
+	if x != nil {
+		return x.DisplayRate
+	}
+	return ""
+}
+
+type ListTransactionsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Default 50, max 500.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Returns entries with id less than this; pass next_before_id of the
+	// previous page. 0 starts from the newest entry.
+	BeforeId int64 `protobuf:"varint,3,opt,name=before_id,json=beforeId,proto3" json:"before_id,omitempty"`
+	// Optional ISO 4217 code to also show amounts in, at the rate of the time
+	// of each entry.
+	DisplayCurrency string `protobuf:"bytes,4,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListTransactionsRequest) Reset() {
+	*x = ListTransactionsRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsRequest) ProtoMessage() {}
+
+func (x *ListTransactionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsRequest.ProtoReflect.Descriptor instead.
+func (*ListTransactionsRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ListTransactionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListTransactionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetBeforeId() int64 {
+	if x != nil {
+		return x.BeforeId
+	}
+	return 0
+}
+
+func (x *ListTransactionsRequest) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+type ListTransactionsResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Transactions []*Transaction         `protobuf:"bytes,1,rep,name=transactions,proto3" json:"transactions,omitempty"`
+	Currency     string                 `protobuf:"bytes,2,opt,name=currency,proto3" json:"currency,omitempty"`
+	// 0 when there are no more entries.
+	NextBeforeId int64 `protobuf:"varint,3,opt,name=next_before_id,json=nextBeforeId,proto3" json:"next_before_id,omitempty"`
+	// Echo of the request; display_rate is the current decimal price of one
+	// unit of currency in display_currency. Each transaction carries the rate
+	// it was converted at.
+	DisplayCurrency string `protobuf:"bytes,4,opt,name=display_currency,json=displayCurrency,proto3" json:"display_currency,omitempty"`
+	DisplayRate     string `protobuf:"bytes,5,opt,name=display_rate,json=displayRate,proto3" json:"display_rate,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListTransactionsResponse) Reset() {
+	*x = ListTransactionsResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTransactionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTransactionsResponse) ProtoMessage() {}
+
+func (x *ListTransactionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTransactionsResponse.ProtoReflect.Descriptor instead.
+func (*ListTransactionsResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *ListTransactionsResponse) GetTransactions() []*Transaction {
+	if x != nil {
+		return x.Transactions
+	}
+	return nil
+}
+
+func (x *ListTransactionsResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ListTransactionsResponse) GetNextBeforeId() int64 {
+	if x != nil {
+		return x.NextBeforeId
+	}
+	return 0
+}
+
+func (x *ListTransactionsResponse) GetDisplayCurrency() string {
+	if x != nil {
+		return x.DisplayCurrency
+	}
+	return ""
+}
+
+func (x *ListTransactionsResponse) GetDisplayRate() string {
+	if x != nil {
+		return x.DisplayRate
+	}
+	return ""
+}
+
+type ConfirmPaymentRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	UserId  string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	OrderId string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Optional: the installment to confirm; empty confirms the oldest payment
+	// of the order waiting for confirmation.
+	PaymentId     string `protobuf:"bytes,3,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPaymentRequest) Reset() {
+	*x = ConfirmPaymentRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPaymentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPaymentRequest) ProtoMessage() {}
+
+func (x *ConfirmPaymentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPaymentRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmPaymentRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ConfirmPaymentRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ConfirmPaymentRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ConfirmPaymentRequest) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+type ConfirmPaymentResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Empty for the initial full payment of the order.
+	PaymentId     string               `protobuf:"bytes,2,opt,name=payment_id,json=paymentId,proto3" json:"payment_id,omitempty"`
+	Status        ConfirmPaymentStatus `protobuf:"varint,3,opt,name=status,proto3,enum=payments.v1.ConfirmPaymentStatus" json:"status,omitempty"`
+	Reason        string               `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmPaymentResponse) Reset() {
+	*x = ConfirmPaymentResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmPaymentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmPaymentResponse) ProtoMessage() {}
+
+func (x *ConfirmPaymentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmPaymentResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmPaymentResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ConfirmPaymentResponse) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ConfirmPaymentResponse) GetPaymentId() string {
+	if x != nil {
+		return x.PaymentId
+	}
+	return ""
+}
+
+func (x *ConfirmPaymentResponse) GetStatus() ConfirmPaymentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_UNSPECIFIED
+}
+
+func (x *ConfirmPaymentResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type GetRatesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesRequest) Reset() {
+	*x = GetRatesRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesRequest) ProtoMessage() {}
+
+func (x *GetRatesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesRequest.ProtoReflect.Descriptor instead.
+func (*GetRatesRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{17}
+}
+
+type ExchangeRate struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Currency string                 `protobuf:"bytes,1,opt,name=currency,proto3" json:"currency,omitempty"` // ISO 4217
+	// Decimal price of one unit of currency in GetRatesResponse.base.
+	Rate          string `protobuf:"bytes,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExchangeRate) Reset() {
+	*x = ExchangeRate{}
+	mi := &file_payments_v1_payments_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeRate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeRate) ProtoMessage() {}
+
+func (x *ExchangeRate) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeRate.ProtoReflect.Descriptor instead.
+func (*ExchangeRate) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ExchangeRate) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *ExchangeRate) GetRate() string {
+	if x != nil {
+		return x.Rate
+	}
+	return ""
+}
+
+type GetRatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Base          string                 `protobuf:"bytes,1,opt,name=base,proto3" json:"base,omitempty"`
+	Rates         []*ExchangeRate        `protobuf:"bytes,2,rep,name=rates,proto3" json:"rates,omitempty"`
+	AsOf          *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=as_of,json=asOf,proto3" json:"as_of,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRatesResponse) Reset() {
+	*x = GetRatesResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRatesResponse) ProtoMessage() {}
+
+func (x *GetRatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRatesResponse.ProtoReflect.Descriptor instead.
+func (*GetRatesResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetRatesResponse) GetBase() string {
+	if x != nil {
+		return x.Base
+	}
+	return ""
+}
+
+func (x *GetRatesResponse) GetRates() []*ExchangeRate {
+	if x != nil {
+		return x.Rates
+	}
+	return nil
+}
+
+func (x *GetRatesResponse) GetAsOf() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AsOf
+	}
+	return nil
+}
+
+type ReplayOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events created in [from, to) are replayed; both are required.
+	From *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=from,proto3" json:"from,omitempty"`
+	To   *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=to,proto3" json:"to,omitempty"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Only count the matching events.
+	DryRun        bool `protobuf:"varint,4,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayOutboxRequest) Reset() {
+	*x = ReplayOutboxRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayOutboxRequest) ProtoMessage() {}
+
+func (x *ReplayOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ReplayOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ReplayOutboxRequest) GetFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.From
+	}
+	return nil
+}
+
+func (x *ReplayOutboxRequest) GetTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.To
+	}
+	return nil
+}
+
+func (x *ReplayOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ReplayOutboxRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ReplayOutboxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matched       int64                  `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	Replayed      int64                  `protobuf:"varint,2,opt,name=replayed,proto3" json:"replayed,omitempty"`
+	DryRun        bool                   `protobuf:"varint,3,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayOutboxResponse) Reset() {
+	*x = ReplayOutboxResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayOutboxResponse) ProtoMessage() {}
+
+func (x *ReplayOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ReplayOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReplayOutboxResponse) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *ReplayOutboxResponse) GetReplayed() int64 {
+	if x != nil {
+		return x.Replayed
+	}
+	return 0
+}
+
+func (x *ReplayOutboxResponse) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type ListDeadOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Default 50, max 500.
+	PageSize int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Returns events with id greater than this; pass next_after_id of the
+	// previous page.
+	AfterId int64 `protobuf:"varint,3,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// Which shard's outbox to list when accounts are sharded; ids are per
+	// shard. 0, the only shard, by default.
+	Shard         int32 `protobuf:"varint,4,opt,name=shard,proto3" json:"shard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadOutboxRequest) Reset() {
+	*x = ListDeadOutboxRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadOutboxRequest) ProtoMessage() {}
+
+func (x *ListDeadOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ListDeadOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListDeadOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListDeadOutboxRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeadOutboxRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *ListDeadOutboxRequest) GetShard() int32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+type DeadOutboxEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic         string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey      string                 `protobuf:"bytes,3,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError     string                 `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadOutboxEvent) Reset() {
+	*x = DeadOutboxEvent{}
+	mi := &file_payments_v1_payments_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadOutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadOutboxEvent) ProtoMessage() {}
+
+func (x *DeadOutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadOutboxEvent.ProtoReflect.Descriptor instead.
+func (*DeadOutboxEvent) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeadOutboxEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeadOutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *DeadOutboxEvent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *DeadOutboxEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type ListDeadOutboxResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*DeadOutboxEvent     `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// 0 when there are no more events.
+	NextAfterId   int64 `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeadOutboxResponse) Reset() {
+	*x = ListDeadOutboxResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeadOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeadOutboxResponse) ProtoMessage() {}
+
+func (x *ListDeadOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeadOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ListDeadOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListDeadOutboxResponse) GetEvents() []*DeadOutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListDeadOutboxResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+type ListOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Required.
+	State OutboxState `protobuf:"varint,1,opt,name=state,proto3,enum=payments.v1.OutboxState" json:"state,omitempty"`
+	// Optional: only events of this topic.
+	Topic string `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	// Optional: only events created in [created_from, created_to); either
+	// bound may be left unset.
+	CreatedFrom *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_from,json=createdFrom,proto3" json:"created_from,omitempty"`
+	CreatedTo   *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_to,json=createdTo,proto3" json:"created_to,omitempty"`
+	// Default 50, max 500.
+	PageSize int32 `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	// Returns events with id greater than this; pass next_after_id of the
+	// previous page.
+	AfterId int64 `protobuf:"varint,6,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`
+	// As in ListDeadOutboxRequest.
+	Shard         int32 `protobuf:"varint,7,opt,name=shard,proto3" json:"shard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOutboxRequest) Reset() {
+	*x = ListOutboxRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutboxRequest) ProtoMessage() {}
+
+func (x *ListOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutboxRequest.ProtoReflect.Descriptor instead.
+func (*ListOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *ListOutboxRequest) GetState() OutboxState {
+	if x != nil {
+		return x.State
+	}
+	return OutboxState_OUTBOX_STATE_UNSPECIFIED
+}
+
+func (x *ListOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *ListOutboxRequest) GetCreatedFrom() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedFrom
+	}
+	return nil
+}
+
+func (x *ListOutboxRequest) GetCreatedTo() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedTo
+	}
+	return nil
+}
+
+func (x *ListOutboxRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListOutboxRequest) GetAfterId() int64 {
+	if x != nil {
+		return x.AfterId
+	}
+	return 0
+}
+
+func (x *ListOutboxRequest) GetShard() int32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+type OutboxEvent struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Topic    string                 `protobuf:"bytes,2,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey string                 `protobuf:"bytes,3,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	// PENDING, FAILED or DEAD.
+	Status    string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts  int32                  `protobuf:"varint,5,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	LastError string                 `protobuf:"bytes,6,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	// When the publisher tries again; unset for dead and never failed events.
+	NextRetryAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=next_retry_at,json=nextRetryAt,proto3" json:"next_retry_at,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,9,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OutboxEvent) Reset() {
+	*x = OutboxEvent{}
+	mi := &file_payments_v1_payments_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OutboxEvent) ProtoMessage() {}
+
+func (x *OutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OutboxEvent.ProtoReflect.Descriptor instead.
+func (*OutboxEvent) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *OutboxEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *OutboxEvent) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *OutboxEvent) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *OutboxEvent) GetNextRetryAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextRetryAt
+	}
+	return nil
+}
+
+func (x *OutboxEvent) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+type ListOutboxResponse struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Events []*OutboxEvent         `protobuf:"bytes,1,rep,name=events,proto3" json:"events,omitempty"`
+	// 0 when there are no more events.
+	NextAfterId   int64 `protobuf:"varint,2,opt,name=next_after_id,json=nextAfterId,proto3" json:"next_after_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListOutboxResponse) Reset() {
+	*x = ListOutboxResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListOutboxResponse) ProtoMessage() {}
+
+func (x *ListOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListOutboxResponse.ProtoReflect.Descriptor instead.
+func (*ListOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *ListOutboxResponse) GetEvents() []*OutboxEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+func (x *ListOutboxResponse) GetNextAfterId() int64 {
+	if x != nil {
+		return x.NextAfterId
+	}
+	return 0
+}
+
+type RequeueDeadOutboxRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Events to requeue; ignored when all is set.
+	Ids []int64 `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	// Requeue every dead event of the shard, of topic when it is set.
+	All   bool   `protobuf:"varint,2,opt,name=all,proto3" json:"all,omitempty"`
+	Topic string `protobuf:"bytes,3,opt,name=topic,proto3" json:"topic,omitempty"`
+	// As in ListDeadOutboxRequest.
+	Shard         int32 `protobuf:"varint,4,opt,name=shard,proto3" json:"shard,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadOutboxRequest) Reset() {
+	*x = RequeueDeadOutboxRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadOutboxRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadOutboxRequest) ProtoMessage() {}
+
+func (x *RequeueDeadOutboxRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadOutboxRequest.ProtoReflect.Descriptor instead.
+func (*RequeueDeadOutboxRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *RequeueDeadOutboxRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *RequeueDeadOutboxRequest) GetAll() bool {
+	if x != nil {
+		return x.All
+	}
+	return false
+}
+
+func (x *RequeueDeadOutboxRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *RequeueDeadOutboxRequest) GetShard() int32 {
+	if x != nil {
+		return x.Shard
+	}
+	return 0
+}
+
+type RequeueDeadOutboxResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Requeued      int64                  `protobuf:"varint,1,opt,name=requeued,proto3" json:"requeued,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RequeueDeadOutboxResponse) Reset() {
+	*x = RequeueDeadOutboxResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RequeueDeadOutboxResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequeueDeadOutboxResponse) ProtoMessage() {}
+
+func (x *RequeueDeadOutboxResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequeueDeadOutboxResponse.ProtoReflect.Descriptor instead.
+func (*RequeueDeadOutboxResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *RequeueDeadOutboxResponse) GetRequeued() int64 {
+	if x != nil {
+		return x.Requeued
+	}
+	return 0
+}
+
+type SetOverdraftLimitRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// 0 disables overdraft. Lowering it below the current debt fails with
+	// FAILED_PRECONDITION.
+	OverdraftLimit int64 `protobuf:"varint,2,opt,name=overdraft_limit,json=overdraftLimit,proto3" json:"overdraft_limit,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitRequest) Reset() {
+	*x = SetOverdraftLimitRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitRequest) ProtoMessage() {}
+
+func (x *SetOverdraftLimitRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitRequest.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *SetOverdraftLimitRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetOverdraftLimitRequest) GetOverdraftLimit() int64 {
+	if x != nil {
+		return x.OverdraftLimit
+	}
+	return 0
+}
+
+type SetOverdraftLimitResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetOverdraftLimitResponse) Reset() {
+	*x = SetOverdraftLimitResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetOverdraftLimitResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetOverdraftLimitResponse) ProtoMessage() {}
+
+func (x *SetOverdraftLimitResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetOverdraftLimitResponse.ProtoReflect.Descriptor instead.
+func (*SetOverdraftLimitResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SetOverdraftLimitResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type SetAccountTypeRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Required. Lowering the overdraft limit below the current debt fails
+	// with FAILED_PRECONDITION.
+	AccountType   AccountType `protobuf:"varint,2,opt,name=account_type,json=accountType,proto3,enum=payments.v1.AccountType" json:"account_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAccountTypeRequest) Reset() {
+	*x = SetAccountTypeRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAccountTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAccountTypeRequest) ProtoMessage() {}
+
+func (x *SetAccountTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAccountTypeRequest.ProtoReflect.Descriptor instead.
+func (*SetAccountTypeRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *SetAccountTypeRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *SetAccountTypeRequest) GetAccountType() AccountType {
+	if x != nil {
+		return x.AccountType
+	}
+	return AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+type SetAccountTypeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetAccountTypeResponse) Reset() {
+	*x = SetAccountTypeResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetAccountTypeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetAccountTypeResponse) ProtoMessage() {}
+
+func (x *SetAccountTypeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetAccountTypeResponse.ProtoReflect.Descriptor instead.
+func (*SetAccountTypeResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *SetAccountTypeResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type GrantBonusRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount int64                  `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"` // minimal currency units
+	// Required, in the future.
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantBonusRequest) Reset() {
+	*x = GrantBonusRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantBonusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantBonusRequest) ProtoMessage() {}
+
+func (x *GrantBonusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantBonusRequest.ProtoReflect.Descriptor instead.
+func (*GrantBonusRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *GrantBonusRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GrantBonusRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *GrantBonusRequest) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+type BonusGrant struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Amount int64                  `protobuf:"varint,3,opt,name=amount,proto3" json:"amount,omitempty"`
+	// What payments have not spent yet.
+	Remaining     int64                  `protobuf:"varint,4,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BonusGrant) Reset() {
+	*x = BonusGrant{}
+	mi := &file_payments_v1_payments_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BonusGrant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BonusGrant) ProtoMessage() {}
+
+func (x *BonusGrant) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BonusGrant.ProtoReflect.Descriptor instead.
+func (*BonusGrant) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *BonusGrant) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BonusGrant) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *BonusGrant) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *BonusGrant) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+func (x *BonusGrant) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+func (x *BonusGrant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+type GrantBonusResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Grant         *BonusGrant            `protobuf:"bytes,1,opt,name=grant,proto3" json:"grant,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GrantBonusResponse) Reset() {
+	*x = GrantBonusResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GrantBonusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GrantBonusResponse) ProtoMessage() {}
+
+func (x *GrantBonusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GrantBonusResponse.ProtoReflect.Descriptor instead.
+func (*GrantBonusResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *GrantBonusResponse) GetGrant() *BonusGrant {
+	if x != nil {
+		return x.Grant
+	}
+	return nil
+}
+
+type AdjustBalanceRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Signed, non-zero. A debit that takes the balance below the overdraft
+	// limit fails with FAILED_PRECONDITION.
+	Amount int64 `protobuf:"varint,2,opt,name=amount,proto3" json:"amount,omitempty"`
+	// Required; stored in the audit log.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Required. A retry with the same request_id replays the response of the
+	// first adjustment instead of applying it again; the same request_id with
+	// other parameters fails with FAILED_PRECONDITION.
+	RequestId     string `protobuf:"bytes,4,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustBalanceRequest) Reset() {
+	*x = AdjustBalanceRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustBalanceRequest) ProtoMessage() {}
+
+func (x *AdjustBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustBalanceRequest.ProtoReflect.Descriptor instead.
+func (*AdjustBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *AdjustBalanceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdjustBalanceRequest) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *AdjustBalanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *AdjustBalanceRequest) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+type AdjustBalanceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Account       *Account               `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdjustBalanceResponse) Reset() {
+	*x = AdjustBalanceResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdjustBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdjustBalanceResponse) ProtoMessage() {}
+
+func (x *AdjustBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdjustBalanceResponse.ProtoReflect.Descriptor instead.
+func (*AdjustBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *AdjustBalanceResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type RematerializeBalanceRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Required; stored in the audit log.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RematerializeBalanceRequest) Reset() {
+	*x = RematerializeBalanceRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RematerializeBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RematerializeBalanceRequest) ProtoMessage() {}
+
+func (x *RematerializeBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RematerializeBalanceRequest.ProtoReflect.Descriptor instead.
+func (*RematerializeBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *RematerializeBalanceRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *RematerializeBalanceRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type RematerializeBalanceResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The account with the rematerialized balance. A ledger balance below the
+	// overdraft limit fails with FAILED_PRECONDITION.
+	Account *Account `protobuf:"bytes,1,opt,name=account,proto3" json:"account,omitempty"`
+	// The stored balance before it was replaced.
+	PreviousBalance int64 `protobuf:"varint,2,opt,name=previous_balance,json=previousBalance,proto3" json:"previous_balance,omitempty"`
+	// How many open balance anomalies of the account were resolved.
+	ResolvedAnomalies int64 `protobuf:"varint,3,opt,name=resolved_anomalies,json=resolvedAnomalies,proto3" json:"resolved_anomalies,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RematerializeBalanceResponse) Reset() {
+	*x = RematerializeBalanceResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RematerializeBalanceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RematerializeBalanceResponse) ProtoMessage() {}
+
+func (x *RematerializeBalanceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RematerializeBalanceResponse.ProtoReflect.Descriptor instead.
+func (*RematerializeBalanceResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *RematerializeBalanceResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+func (x *RematerializeBalanceResponse) GetPreviousBalance() int64 {
+	if x != nil {
+		return x.PreviousBalance
+	}
+	return 0
+}
+
+func (x *RematerializeBalanceResponse) GetResolvedAnomalies() int64 {
+	if x != nil {
+		return x.ResolvedAnomalies
+	}
+	return 0
+}
+
+type Dispute struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// The user who paid for the order.
+	UserId string        `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status DisputeStatus `protobuf:"varint,3,opt,name=status,proto3,enum=payments.v1.DisputeStatus" json:"status,omitempty"`
+	// Paid for the order, fees included and bonus spent on it not; a refund
+	// credits it to the balance whatever the payment method.
+	Amount int64  `protobuf:"varint,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	Reason string `protobuf:"bytes,5,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Set once resolved.
+	ResolutionReason string                 `protobuf:"bytes,6,opt,name=resolution_reason,json=resolutionReason,proto3" json:"resolution_reason,omitempty"`
+	OpenedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=opened_at,json=openedAt,proto3" json:"opened_at,omitempty"`
+	ResolvedAt       *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=resolved_at,json=resolvedAt,proto3" json:"resolved_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *Dispute) Reset() {
+	*x = Dispute{}
+	mi := &file_payments_v1_payments_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Dispute) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Dispute) ProtoMessage() {}
+
+func (x *Dispute) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Dispute.ProtoReflect.Descriptor instead.
+func (*Dispute) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *Dispute) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *Dispute) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Dispute) GetStatus() DisputeStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+}
+
+func (x *Dispute) GetAmount() int64 {
+	if x != nil {
+		return x.Amount
+	}
+	return 0
+}
+
+func (x *Dispute) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *Dispute) GetResolutionReason() string {
+	if x != nil {
+		return x.ResolutionReason
+	}
+	return ""
+}
+
+func (x *Dispute) GetOpenedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OpenedAt
+	}
+	return nil
+}
+
+func (x *Dispute) GetResolvedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ResolvedAt
+	}
+	return nil
+}
+
+type OpenDisputeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Required; stored with the dispute and in the audit log.
+	Reason        string `protobuf:"bytes,2,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenDisputeRequest) Reset() {
+	*x = OpenDisputeRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenDisputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenDisputeRequest) ProtoMessage() {}
+
+func (x *OpenDisputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenDisputeRequest.ProtoReflect.Descriptor instead.
+func (*OpenDisputeRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *OpenDisputeRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *OpenDisputeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type OpenDisputeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dispute       *Dispute               `protobuf:"bytes,1,opt,name=dispute,proto3" json:"dispute,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OpenDisputeResponse) Reset() {
+	*x = OpenDisputeResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OpenDisputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OpenDisputeResponse) ProtoMessage() {}
+
+func (x *OpenDisputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OpenDisputeResponse.ProtoReflect.Descriptor instead.
+func (*OpenDisputeResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *OpenDisputeResponse) GetDispute() *Dispute {
+	if x != nil {
+		return x.Dispute
+	}
+	return nil
+}
+
+type ResolveDisputeRequest struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	OrderId string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Required.
+	Resolution DisputeResolution `protobuf:"varint,2,opt,name=resolution,proto3,enum=payments.v1.DisputeResolution" json:"resolution,omitempty"`
+	// Required; stored with the dispute and in the audit log.
+	Reason        string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveDisputeRequest) Reset() {
+	*x = ResolveDisputeRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveDisputeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDisputeRequest) ProtoMessage() {}
+
+func (x *ResolveDisputeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDisputeRequest.ProtoReflect.Descriptor instead.
+func (*ResolveDisputeRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *ResolveDisputeRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *ResolveDisputeRequest) GetResolution() DisputeResolution {
+	if x != nil {
+		return x.Resolution
+	}
+	return DisputeResolution_DISPUTE_RESOLUTION_UNSPECIFIED
+}
+
+func (x *ResolveDisputeRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type ResolveDisputeResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Dispute *Dispute               `protobuf:"bytes,1,opt,name=dispute,proto3" json:"dispute,omitempty"`
+	// The payer's account after a refund; unset when the dispute is upheld.
+	Account       *Account `protobuf:"bytes,2,opt,name=account,proto3" json:"account,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResolveDisputeResponse) Reset() {
+	*x = ResolveDisputeResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResolveDisputeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResolveDisputeResponse) ProtoMessage() {}
+
+func (x *ResolveDisputeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResolveDisputeResponse.ProtoReflect.Descriptor instead.
+func (*ResolveDisputeResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *ResolveDisputeResponse) GetDispute() *Dispute {
+	if x != nil {
+		return x.Dispute
+	}
+	return nil
+}
+
+func (x *ResolveDisputeResponse) GetAccount() *Account {
+	if x != nil {
+		return x.Account
+	}
+	return nil
+}
+
+type DryRunInboxMessageRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Inbox consumer, e.g. payment_requested.
+	Consumer string `protobuf:"bytes,1,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	// Event id of the message.
+	MessageId     string `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunInboxMessageRequest) Reset() {
+	*x = DryRunInboxMessageRequest{}
+	mi := &file_payments_v1_payments_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunInboxMessageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunInboxMessageRequest) ProtoMessage() {}
+
+func (x *DryRunInboxMessageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunInboxMessageRequest.ProtoReflect.Descriptor instead.
+func (*DryRunInboxMessageRequest) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *DryRunInboxMessageRequest) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageRequest) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+type InboxMessageHeader struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Key           string                 `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Value         []byte                 `protobuf:"bytes,2,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxMessageHeader) Reset() {
+	*x = InboxMessageHeader{}
+	mi := &file_payments_v1_payments_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxMessageHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxMessageHeader) ProtoMessage() {}
+
+func (x *InboxMessageHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxMessageHeader.ProtoReflect.Descriptor instead.
+func (*InboxMessageHeader) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *InboxMessageHeader) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *InboxMessageHeader) GetValue() []byte {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type InboxMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Consumer      string                 `protobuf:"bytes,1,opt,name=consumer,proto3" json:"consumer,omitempty"`
+	MessageId     string                 `protobuf:"bytes,2,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	CorrelationId string                 `protobuf:"bytes,3,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	Topic         string                 `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Partition     int32                  `protobuf:"varint,5,opt,name=partition,proto3" json:"partition,omitempty"`
+	Offset        int64                  `protobuf:"varint,6,opt,name=offset,proto3" json:"offset,omitempty"`
+	Key           []byte                 `protobuf:"bytes,7,opt,name=key,proto3" json:"key,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,8,opt,name=payload,proto3" json:"payload,omitempty"`
+	Headers       []*InboxMessageHeader  `protobuf:"bytes,9,rep,name=headers,proto3" json:"headers,omitempty"`
+	ReceivedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=received_at,json=receivedAt,proto3" json:"received_at,omitempty"`
+	// Unset while the message is not processed.
+	ProcessedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=processed_at,json=processedAt,proto3" json:"processed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InboxMessage) Reset() {
+	*x = InboxMessage{}
+	mi := &file_payments_v1_payments_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InboxMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InboxMessage) ProtoMessage() {}
+
+func (x *InboxMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InboxMessage.ProtoReflect.Descriptor instead.
+func (*InboxMessage) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *InboxMessage) GetConsumer() string {
+	if x != nil {
+		return x.Consumer
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *InboxMessage) GetPartition() int32 {
+	if x != nil {
+		return x.Partition
+	}
+	return 0
+}
+
+func (x *InboxMessage) GetOffset() int64 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *InboxMessage) GetKey() []byte {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetHeaders() []*InboxMessageHeader {
+	if x != nil {
+		return x.Headers
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetReceivedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ReceivedAt
+	}
+	return nil
+}
+
+func (x *InboxMessage) GetProcessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ProcessedAt
+	}
+	return nil
+}
+
+type DryRunOutboxEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topic         string                 `protobuf:"bytes,1,opt,name=topic,proto3" json:"topic,omitempty"`
+	KafkaKey      string                 `protobuf:"bytes,2,opt,name=kafka_key,json=kafkaKey,proto3" json:"kafka_key,omitempty"`
+	Payload       []byte                 `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunOutboxEvent) Reset() {
+	*x = DryRunOutboxEvent{}
+	mi := &file_payments_v1_payments_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunOutboxEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunOutboxEvent) ProtoMessage() {}
+
+func (x *DryRunOutboxEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunOutboxEvent.ProtoReflect.Descriptor instead.
+func (*DryRunOutboxEvent) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *DryRunOutboxEvent) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *DryRunOutboxEvent) GetKafkaKey() string {
+	if x != nil {
+		return x.KafkaKey
+	}
+	return ""
+}
+
+func (x *DryRunOutboxEvent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type DryRunInboxMessageResponse struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message *InboxMessage          `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	// The decoded event as JSON; empty when the payload does not decode.
+	EventJson string `protobuf:"bytes,2,opt,name=event_json,json=eventJson,proto3" json:"event_json,omitempty"`
+	// Why the consumer would drop the message without effects.
+	Skipped string `protobuf:"bytes,3,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	// The error the consumer failed with; the message would be redelivered.
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	// Outbox events the consumer would have written, in order.
+	WouldPublish  []*DryRunOutboxEvent `protobuf:"bytes,5,rep,name=would_publish,json=wouldPublish,proto3" json:"would_publish,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DryRunInboxMessageResponse) Reset() {
+	*x = DryRunInboxMessageResponse{}
+	mi := &file_payments_v1_payments_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DryRunInboxMessageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DryRunInboxMessageResponse) ProtoMessage() {}
+
+func (x *DryRunInboxMessageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_payments_v1_payments_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DryRunInboxMessageResponse.ProtoReflect.Descriptor instead.
+func (*DryRunInboxMessageResponse) Descriptor() ([]byte, []int) {
+	return file_payments_v1_payments_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *DryRunInboxMessageResponse) GetMessage() *InboxMessage {
+	if x != nil {
+		return x.Message
+	}
+	return nil
+}
+
+func (x *DryRunInboxMessageResponse) GetEventJson() string {
+	if x != nil {
+		return x.EventJson
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetSkipped() string {
+	if x != nil {
+		return x.Skipped
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *DryRunInboxMessageResponse) GetWouldPublish() []*DryRunOutboxEvent {
+	if x != nil {
+		return x.WouldPublish
+	}
+	return nil
+}
+
+var File_payments_v1_payments_proto protoreflect.FileDescriptor
+
+const file_payments_v1_payments_proto_rawDesc = "" +
+	"\n" +
+	"\x1apayments/v1/payments.proto\x12\vpayments.v1\x1a\x1cgoogle/api/annotations.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xfd\x01\n" +
+	"\aAccount\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12'\n" +
+	"\x0foverdraft_limit\x18\x05 \x01(\x03R\x0eoverdraftLimit\x12;\n" +
+	"\faccount_type\x18\x06 \x01(\x0e2\x18.payments.v1.AccountTypeR\vaccountType\x12#\n" +
+	"\rbonus_balance\x18\a \x01(\x03R\fbonusBalance\"X\n" +
+	"\x14CreateAccountRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0fidempotency_key\x18\x02 \x01(\tR\x0eidempotencyKey\"G\n" +
+	"\x15CreateAccountResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\"\xaf\x01\n" +
+	"\fTopUpRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12'\n" +
+	"\x0fidempotency_key\x18\x03 \x01(\tR\x0eidempotencyKey\x12\x1a\n" +
+	"\bcurrency\x18\x04 \x01(\tR\bcurrency\x12)\n" +
+	"\x10expected_version\x18\x05 \x01(\x03R\x0fexpectedVersion\"?\n" +
+	"\rTopUpResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\",\n" +
+	"\x11GetBalanceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc6\x01\n" +
+	"\x12GetBalanceResponse\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\x03R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\x12;\n" +
+	"\faccount_type\x18\x04 \x01(\x0e2\x18.payments.v1.AccountTypeR\vaccountType\x12#\n" +
+	"\rbonus_balance\x18\x05 \x01(\x03R\fbonusBalance\"/\n" +
+	"\x12GetBalancesRequest\x12\x19\n" +
+	"\buser_ids\x18\x01 \x03(\tR\auserIds\"\xdb\x01\n" +
+	"\x0eAccountBalance\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\x03R\abalance\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion\x12;\n" +
+	"\faccount_type\x18\x05 \x01(\x0e2\x18.payments.v1.AccountTypeR\vaccountType\x12#\n" +
+	"\rbonus_balance\x18\x06 \x01(\x03R\fbonusBalance\"x\n" +
+	"\x13GetBalancesResponse\x127\n" +
+	"\bbalances\x18\x01 \x03(\v2\x1b.payments.v1.AccountBalanceR\bbalances\x12(\n" +
+	"\x10missing_user_ids\x18\x02 \x03(\tR\x0emissingUserIds\"Z\n" +
+	"\x13GetBalanceAtRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\"x\n" +
+	"\x14GetBalanceAtResponse\x12\x18\n" +
+	"\abalance\x18\x01 \x01(\x03R\abalance\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12\x1a\n" +
+	"\bcurrency\x18\x03 \x01(\tR\bcurrency\"\xa6\x02\n" +
+	"\vTransaction\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x120\n" +
+	"\x04kind\x18\x03 \x01(\x0e2\x1c.payments.v1.TransactionKindR\x04kind\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x04 \x01(\tR\tpaymentId\x12\x19\n" +
+	"\border_id\x18\x05 \x01(\tR\aorderId\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12%\n" +
+	"\x0edisplay_amount\x18\a \x01(\x03R\rdisplayAmount\x12!\n" +
+	"\fdisplay_rate\x18\b \x01(\tR\vdisplayRate\"\x97\x01\n" +
+	"\x17ListTransactionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1b\n" +
+	"\tbefore_id\x18\x03 \x01(\x03R\bbeforeId\x12)\n" +
+	"\x10display_currency\x18\x04 \x01(\tR\x0fdisplayCurrency\"\xe8\x01\n" +
+	"\x18ListTransactionsResponse\x12<\n" +
+	"\ftransactions\x18\x01 \x03(\v2\x18.payments.v1.TransactionR\ftransactions\x12\x1a\n" +
+	"\bcurrency\x18\x02 \x01(\tR\bcurrency\x12$\n" +
+	"\x0enext_before_id\x18\x03 \x01(\x03R\fnextBeforeId\x12)\n" +
+	"\x10display_currency\x18\x04 \x01(\tR\x0fdisplayCurrency\x12!\n" +
+	"\fdisplay_rate\x18\x05 \x01(\tR\vdisplayRate\"j\n" +
+	"\x15ConfirmPaymentRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x03 \x01(\tR\tpaymentId\"\xa5\x01\n" +
+	"\x16ConfirmPaymentResponse\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"payment_id\x18\x02 \x01(\tR\tpaymentId\x129\n" +
+	"\x06status\x18\x03 \x01(\x0e2!.payments.v1.ConfirmPaymentStatusR\x06status\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\x11\n" +
+	"\x0fGetRatesRequest\">\n" +
+	"\fExchangeRate\x12\x1a\n" +
+	"\bcurrency\x18\x01 \x01(\tR\bcurrency\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\tR\x04rate\"\x88\x01\n" +
+	"\x10GetRatesResponse\x12\x12\n" +
+	"\x04base\x18\x01 \x01(\tR\x04base\x12/\n" +
+	"\x05rates\x18\x02 \x03(\v2\x19.payments.v1.ExchangeRateR\x05rates\x12/\n" +
+	"\x05as_of\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\x04asOf\"\xa0\x01\n" +
+	"\x13ReplayOutboxRequest\x12.\n" +
+	"\x04from\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04from\x12*\n" +
+	"\x02to\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02to\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x17\n" +
+	"\adry_run\x18\x04 \x01(\bR\x06dryRun\"e\n" +
+	"\x14ReplayOutboxResponse\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x03R\amatched\x12\x1a\n" +
+	"\breplayed\x18\x02 \x01(\x03R\breplayed\x12\x17\n" +
+	"\adry_run\x18\x03 \x01(\bR\x06dryRun\"{\n" +
+	"\x15ListDeadOutboxRequest\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\bafter_id\x18\x03 \x01(\x03R\aafterId\x12\x14\n" +
+	"\x05shard\x18\x04 \x01(\x05R\x05shard\"\xca\x01\n" +
+	"\x0fDeadOutboxEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x03 \x01(\tR\bkafkaKey\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x05 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"r\n" +
+	"\x16ListDeadOutboxResponse\x124\n" +
+	"\x06events\x18\x01 \x03(\v2\x1c.payments.v1.DeadOutboxEventR\x06events\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"\xa1\x02\n" +
+	"\x11ListOutboxRequest\x12.\n" +
+	"\x05state\x18\x01 \x01(\x0e2\x18.payments.v1.OutboxStateR\x05state\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12=\n" +
+	"\fcreated_from\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\vcreatedFrom\x129\n" +
+	"\n" +
+	"created_to\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedTo\x12\x1b\n" +
+	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x19\n" +
+	"\bafter_id\x18\x06 \x01(\x03R\aafterId\x12\x14\n" +
+	"\x05shard\x18\a \x01(\x05R\x05shard\"\xc5\x02\n" +
+	"\vOutboxEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05topic\x18\x02 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x03 \x01(\tR\bkafkaKey\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x05 \x01(\x05R\battempts\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\x06 \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12>\n" +
+	"\rnext_retry_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\vnextRetryAt\x12%\n" +
+	"\x0ecorrelation_id\x18\t \x01(\tR\rcorrelationId\"j\n" +
+	"\x12ListOutboxResponse\x120\n" +
+	"\x06events\x18\x01 \x03(\v2\x18.payments.v1.OutboxEventR\x06events\x12\"\n" +
+	"\rnext_after_id\x18\x02 \x01(\x03R\vnextAfterId\"j\n" +
+	"\x18RequeueDeadOutboxRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\x12\x10\n" +
+	"\x03all\x18\x02 \x01(\bR\x03all\x12\x14\n" +
+	"\x05topic\x18\x03 \x01(\tR\x05topic\x12\x14\n" +
+	"\x05shard\x18\x04 \x01(\x05R\x05shard\"7\n" +
+	"\x19RequeueDeadOutboxResponse\x12\x1a\n" +
+	"\brequeued\x18\x01 \x01(\x03R\brequeued\"\\\n" +
+	"\x18SetOverdraftLimitRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12'\n" +
+	"\x0foverdraft_limit\x18\x02 \x01(\x03R\x0eoverdraftLimit\"K\n" +
+	"\x19SetOverdraftLimitResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\"m\n" +
+	"\x15SetAccountTypeRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12;\n" +
+	"\faccount_type\x18\x02 \x01(\x0e2\x18.payments.v1.AccountTypeR\vaccountType\"H\n" +
+	"\x16SetAccountTypeResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\"\x7f\n" +
+	"\x11GrantBonusRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"\xe1\x01\n" +
+	"\n" +
+	"BonusGrant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\x03R\x06amount\x12\x1c\n" +
+	"\tremaining\x18\x04 \x01(\x03R\tremaining\x129\n" +
+	"\n" +
+	"expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\"C\n" +
+	"\x12GrantBonusResponse\x12-\n" +
+	"\x05grant\x18\x01 \x01(\v2\x17.payments.v1.BonusGrantR\x05grant\"~\n" +
+	"\x14AdjustBalanceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\x04 \x01(\tR\trequestId\"G\n" +
+	"\x15AdjustBalanceResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\"N\n" +
+	"\x1bRematerializeBalanceRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"\xa8\x01\n" +
+	"\x1cRematerializeBalanceResponse\x12.\n" +
+	"\aaccount\x18\x01 \x01(\v2\x14.payments.v1.AccountR\aaccount\x12)\n" +
+	"\x10previous_balance\x18\x02 \x01(\x03R\x0fpreviousBalance\x12-\n" +
+	"\x12resolved_anomalies\x18\x03 \x01(\x03R\x11resolvedAnomalies\"\xc4\x02\n" +
+	"\aDispute\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x122\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1a.payments.v1.DisputeStatusR\x06status\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\x03R\x06amount\x12\x16\n" +
+	"\x06reason\x18\x05 \x01(\tR\x06reason\x12+\n" +
+	"\x11resolution_reason\x18\x06 \x01(\tR\x10resolutionReason\x127\n" +
+	"\topened_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\bopenedAt\x12;\n" +
+	"\vresolved_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"resolvedAt\"G\n" +
+	"\x12OpenDisputeRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
+	"\x06reason\x18\x02 \x01(\tR\x06reason\"E\n" +
+	"\x13OpenDisputeResponse\x12.\n" +
+	"\adispute\x18\x01 \x01(\v2\x14.payments.v1.DisputeR\adispute\"\x8a\x01\n" +
+	"\x15ResolveDisputeRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12>\n" +
+	"\n" +
+	"resolution\x18\x02 \x01(\x0e2\x1e.payments.v1.DisputeResolutionR\n" +
+	"resolution\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"x\n" +
+	"\x16ResolveDisputeResponse\x12.\n" +
+	"\adispute\x18\x01 \x01(\v2\x14.payments.v1.DisputeR\adispute\x12.\n" +
+	"\aaccount\x18\x02 \x01(\v2\x14.payments.v1.AccountR\aaccount\"V\n" +
+	"\x19DryRunInboxMessageRequest\x12\x1a\n" +
+	"\bconsumer\x18\x01 \x01(\tR\bconsumer\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\"<\n" +
+	"\x12InboxMessageHeader\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\fR\x05value\"\x9f\x03\n" +
+	"\fInboxMessage\x12\x1a\n" +
+	"\bconsumer\x18\x01 \x01(\tR\bconsumer\x12\x1d\n" +
+	"\n" +
+	"message_id\x18\x02 \x01(\tR\tmessageId\x12%\n" +
+	"\x0ecorrelation_id\x18\x03 \x01(\tR\rcorrelationId\x12\x14\n" +
+	"\x05topic\x18\x04 \x01(\tR\x05topic\x12\x1c\n" +
+	"\tpartition\x18\x05 \x01(\x05R\tpartition\x12\x16\n" +
+	"\x06offset\x18\x06 \x01(\x03R\x06offset\x12\x10\n" +
+	"\x03key\x18\a \x01(\fR\x03key\x12\x18\n" +
+	"\apayload\x18\b \x01(\fR\apayload\x129\n" +
+	"\aheaders\x18\t \x03(\v2\x1f.payments.v1.InboxMessageHeaderR\aheaders\x12;\n" +
+	"\vreceived_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"receivedAt\x12=\n" +
+	"\fprocessed_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vprocessedAt\"`\n" +
+	"\x11DryRunOutboxEvent\x12\x14\n" +
+	"\x05topic\x18\x01 \x01(\tR\x05topic\x12\x1b\n" +
+	"\tkafka_key\x18\x02 \x01(\tR\bkafkaKey\x12\x18\n" +
+	"\apayload\x18\x03 \x01(\fR\apayload\"\xe5\x01\n" +
+	"\x1aDryRunInboxMessageResponse\x123\n" +
+	"\amessage\x18\x01 \x01(\v2\x19.payments.v1.InboxMessageR\amessage\x12\x1d\n" +
+	"\n" +
+	"event_json\x18\x02 \x01(\tR\teventJson\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\tR\askipped\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\x12C\n" +
+	"\rwould_publish\x18\x05 \x03(\v2\x1e.payments.v1.DryRunOutboxEventR\fwouldPublish*x\n" +
+	"\vAccountType\x12\x1c\n" +
+	"\x18ACCOUNT_TYPE_UNSPECIFIED\x10\x00\x12\x16\n" +
+	"\x12ACCOUNT_TYPE_BASIC\x10\x01\x12\x18\n" +
+	"\x14ACCOUNT_TYPE_PREMIUM\x10\x02\x12\x19\n" +
+	"\x15ACCOUNT_TYPE_BUSINESS\x10\x03*\xc5\x01\n" +
+	"\x0fTransactionKind\x12 \n" +
+	"\x1cTRANSACTION_KIND_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17TRANSACTION_KIND_TOP_UP\x10\x01\x12\x1c\n" +
+	"\x18TRANSACTION_KIND_PAYMENT\x10\x02\x12\x18\n" +
+	"\x14TRANSACTION_KIND_FEE\x10\x03\x12\x1a\n" +
+	"\x16TRANSACTION_KIND_BONUS\x10\x04\x12\x1f\n" +
+	"\x1bTRANSACTION_KIND_ADJUSTMENT\x10\x05*\xab\x01\n" +
+	"\x14ConfirmPaymentStatus\x12&\n" +
+	"\"CONFIRM_PAYMENT_STATUS_UNSPECIFIED\x10\x00\x12$\n" +
+	" CONFIRM_PAYMENT_STATUS_SUCCEEDED\x10\x01\x12!\n" +
+	"\x1dCONFIRM_PAYMENT_STATUS_FAILED\x10\x02\x12\"\n" +
+	"\x1eCONFIRM_PAYMENT_STATUS_PENDING\x10\x03*t\n" +
+	"\vOutboxState\x12\x1c\n" +
+	"\x18OUTBOX_STATE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13OUTBOX_STATE_UNSENT\x10\x01\x12\x17\n" +
+	"\x13OUTBOX_STATE_FAILED\x10\x02\x12\x15\n" +
+	"\x11OUTBOX_STATE_DEAD\x10\x03*\x80\x01\n" +
+	"\rDisputeStatus\x12\x1e\n" +
+	"\x1aDISPUTE_STATUS_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13DISPUTE_STATUS_OPEN\x10\x01\x12\x1b\n" +
+	"\x17DISPUTE_STATUS_REFUNDED\x10\x02\x12\x19\n" +
+	"\x15DISPUTE_STATUS_UPHELD\x10\x03*u\n" +
+	"\x11DisputeResolution\x12\"\n" +
+	"\x1eDISPUTE_RESOLUTION_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19DISPUTE_RESOLUTION_REFUND\x10\x01\x12\x1d\n" +
+	"\x19DISPUTE_RESOLUTION_UPHOLD\x10\x022\xfc\a\n" +
+	"\x0fPaymentsService\x12~\n" +
+	"\rCreateAccount\x12!.payments.v1.CreateAccountRequest\x1a\".payments.v1.CreateAccountResponse\"&\x82\xd3\xe4\x93\x02 :\x01*\"\x1b/v1/users/{user_id}/account\x12l\n" +
+	"\x05TopUp\x12\x19.payments.v1.TopUpRequest\x1a\x1a.payments.v1.TopUpResponse\",\x82\xd3\xe4\x93\x02&:\x01*\"!/v1/users/{user_id}/account/topup\x12z\n" +
+	"\n" +
+	"GetBalance\x12\x1e.payments.v1.GetBalanceRequest\x1a\x1f.payments.v1.GetBalanceResponse\"+\x82\xd3\xe4\x93\x02%\x12#/v1/users/{user_id}/account/balance\x12n\n" +
+	"\vGetBalances\x12\x1f.payments.v1.GetBalancesRequest\x1a .payments.v1.GetBalancesResponse\"\x1c\x82\xd3\xe4\x93\x02\x16\x12\x14/v1/support/balances\x12\x80\x01\n" +
+	"\fGetBalanceAt\x12 .payments.v1.GetBalanceAtRequest\x1a!.payments.v1.GetBalanceAtResponse\"+\x82\xd3\xe4\x93\x02%\x12#/v1/support/users/{user_id}/balance\x12\x91\x01\n" +
+	"\x10ListTransactions\x12$.payments.v1.ListTransactionsRequest\x1a%.payments.v1.ListTransactionsResponse\"0\x82\xd3\xe4\x93\x02*\x12(/v1/users/{user_id}/account/transactions\x12Z\n" +
+	"\bGetRates\x12\x1c.payments.v1.GetRatesRequest\x1a\x1d.payments.v1.GetRatesResponse\"\x11\x82\xd3\xe4\x93\x02\v\x12\t/v1/rates\x12\x9b\x01\n" +
+	"\x0eConfirmPayment\x12\".payments.v1.ConfirmPaymentRequest\x1a#.payments.v1.ConfirmPaymentResponse\"@\x82\xd3\xe4\x93\x02::\x01*\"5/v1/users/{user_id}/orders/{order_id}/confirm-payment2\xe0\b\n" +
+	"\x14PaymentsAdminService\x12S\n" +
+	"\fReplayOutbox\x12 .payments.v1.ReplayOutboxRequest\x1a!.payments.v1.ReplayOutboxResponse\x12Y\n" +
+	"\x0eListDeadOutbox\x12\".payments.v1.ListDeadOutboxRequest\x1a#.payments.v1.ListDeadOutboxResponse\x12M\n" +
+	"\n" +
+	"ListOutbox\x12\x1e.payments.v1.ListOutboxRequest\x1a\x1f.payments.v1.ListOutboxResponse\x12b\n" +
+	"\x11RequeueDeadOutbox\x12%.payments.v1.RequeueDeadOutboxRequest\x1a&.payments.v1.RequeueDeadOutboxResponse\x12b\n" +
+	"\x11SetOverdraftLimit\x12%.payments.v1.SetOverdraftLimitRequest\x1a&.payments.v1.SetOverdraftLimitResponse\x12Y\n" +
+	"\x0eSetAccountType\x12\".payments.v1.SetAccountTypeRequest\x1a#.payments.v1.SetAccountTypeResponse\x12M\n" +
 	"\n" +
-	"GetBalance\x12\x1e.payments.v1.GetBalanceRequest\x1a\x1f.payments.v1.GetBalanceResponseBFZDgithub.com/ilyaytrewq/payments-service/gen/go/payments/v1;paymentsv1b\x06proto3"
+	"GrantBonus\x12\x1e.payments.v1.GrantBonusRequest\x1a\x1f.payments.v1.GrantBonusResponse\x12V\n" +
+	"\rAdjustBalance\x12!.payments.v1.AdjustBalanceRequest\x1a\".payments.v1.AdjustBalanceResponse\x12k\n" +
+	"\x14RematerializeBalance\x12(.payments.v1.RematerializeBalanceRequest\x1a).payments.v1.RematerializeBalanceResponse\x12P\n" +
+	"\vOpenDispute\x12\x1f.payments.v1.OpenDisputeRequest\x1a .payments.v1.OpenDisputeResponse\x12Y\n" +
+	"\x0eResolveDispute\x12\".payments.v1.ResolveDisputeRequest\x1a#.payments.v1.ResolveDisputeResponse\x12e\n" +
+	"\x12DryRunInboxMessage\x12&.payments.v1.DryRunInboxMessageRequest\x1a'.payments.v1.DryRunInboxMessageResponseBFZDgithub.com/ilyaytrewq/payments-service/gen/go/payments/v1;paymentsv1b\x06proto3"
 
 var (
 	file_payments_v1_payments_proto_rawDescOnce sync.Once
@@ -404,30 +3968,159 @@ func file_payments_v1_payments_proto_rawDescGZIP() []byte {
 	return file_payments_v1_payments_proto_rawDescData
 }
 
-var file_payments_v1_payments_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_payments_v1_payments_proto_enumTypes = make([]protoimpl.EnumInfo, 6)
+var file_payments_v1_payments_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
 var file_payments_v1_payments_proto_goTypes = []any{
-	(*Account)(nil),               // 0: payments.v1.Account
-	(*CreateAccountRequest)(nil),  // 1: payments.v1.CreateAccountRequest
-	(*CreateAccountResponse)(nil), // 2: payments.v1.CreateAccountResponse
-	(*TopUpRequest)(nil),          // 3: payments.v1.TopUpRequest
-	(*TopUpResponse)(nil),         // 4: payments.v1.TopUpResponse
-	(*GetBalanceRequest)(nil),     // 5: payments.v1.GetBalanceRequest
-	(*GetBalanceResponse)(nil),    // 6: payments.v1.GetBalanceResponse
+	(AccountType)(0),                     // 0: payments.v1.AccountType
+	(TransactionKind)(0),                 // 1: payments.v1.TransactionKind
+	(ConfirmPaymentStatus)(0),            // 2: payments.v1.ConfirmPaymentStatus
+	(OutboxState)(0),                     // 3: payments.v1.OutboxState
+	(DisputeStatus)(0),                   // 4: payments.v1.DisputeStatus
+	(DisputeResolution)(0),               // 5: payments.v1.DisputeResolution
+	(*Account)(nil),                      // 6: payments.v1.Account
+	(*CreateAccountRequest)(nil),         // 7: payments.v1.CreateAccountRequest
+	(*CreateAccountResponse)(nil),        // 8: payments.v1.CreateAccountResponse
+	(*TopUpRequest)(nil),                 // 9: payments.v1.TopUpRequest
+	(*TopUpResponse)(nil),                // 10: payments.v1.TopUpResponse
+	(*GetBalanceRequest)(nil),            // 11: payments.v1.GetBalanceRequest
+	(*GetBalanceResponse)(nil),           // 12: payments.v1.GetBalanceResponse
+	(*GetBalancesRequest)(nil),           // 13: payments.v1.GetBalancesRequest
+	(*AccountBalance)(nil),               // 14: payments.v1.AccountBalance
+	(*GetBalancesResponse)(nil),          // 15: payments.v1.GetBalancesResponse
+	(*GetBalanceAtRequest)(nil),          // 16: payments.v1.GetBalanceAtRequest
+	(*GetBalanceAtResponse)(nil),         // 17: payments.v1.GetBalanceAtResponse
+	(*Transaction)(nil),                  // 18: payments.v1.Transaction
+	(*ListTransactionsRequest)(nil),      // 19: payments.v1.ListTransactionsRequest
+	(*ListTransactionsResponse)(nil),     // 20: payments.v1.ListTransactionsResponse
+	(*ConfirmPaymentRequest)(nil),        // 21: payments.v1.ConfirmPaymentRequest
+	(*ConfirmPaymentResponse)(nil),       // 22: payments.v1.ConfirmPaymentResponse
+	(*GetRatesRequest)(nil),              // 23: payments.v1.GetRatesRequest
+	(*ExchangeRate)(nil),                 // 24: payments.v1.ExchangeRate
+	(*GetRatesResponse)(nil),             // 25: payments.v1.GetRatesResponse
+	(*ReplayOutboxRequest)(nil),          // 26: payments.v1.ReplayOutboxRequest
+	(*ReplayOutboxResponse)(nil),         // 27: payments.v1.ReplayOutboxResponse
+	(*ListDeadOutboxRequest)(nil),        // 28: payments.v1.ListDeadOutboxRequest
+	(*DeadOutboxEvent)(nil),              // 29: payments.v1.DeadOutboxEvent
+	(*ListDeadOutboxResponse)(nil),       // 30: payments.v1.ListDeadOutboxResponse
+	(*ListOutboxRequest)(nil),            // 31: payments.v1.ListOutboxRequest
+	(*OutboxEvent)(nil),                  // 32: payments.v1.OutboxEvent
+	(*ListOutboxResponse)(nil),           // 33: payments.v1.ListOutboxResponse
+	(*RequeueDeadOutboxRequest)(nil),     // 34: payments.v1.RequeueDeadOutboxRequest
+	(*RequeueDeadOutboxResponse)(nil),    // 35: payments.v1.RequeueDeadOutboxResponse
+	(*SetOverdraftLimitRequest)(nil),     // 36: payments.v1.SetOverdraftLimitRequest
+	(*SetOverdraftLimitResponse)(nil),    // 37: payments.v1.SetOverdraftLimitResponse
+	(*SetAccountTypeRequest)(nil),        // 38: payments.v1.SetAccountTypeRequest
+	(*SetAccountTypeResponse)(nil),       // 39: payments.v1.SetAccountTypeResponse
+	(*GrantBonusRequest)(nil),            // 40: payments.v1.GrantBonusRequest
+	(*BonusGrant)(nil),                   // 41: payments.v1.BonusGrant
+	(*GrantBonusResponse)(nil),           // 42: payments.v1.GrantBonusResponse
+	(*AdjustBalanceRequest)(nil),         // 43: payments.v1.AdjustBalanceRequest
+	(*AdjustBalanceResponse)(nil),        // 44: payments.v1.AdjustBalanceResponse
+	(*RematerializeBalanceRequest)(nil),  // 45: payments.v1.RematerializeBalanceRequest
+	(*RematerializeBalanceResponse)(nil), // 46: payments.v1.RematerializeBalanceResponse
+	(*Dispute)(nil),                      // 47: payments.v1.Dispute
+	(*OpenDisputeRequest)(nil),           // 48: payments.v1.OpenDisputeRequest
+	(*OpenDisputeResponse)(nil),          // 49: payments.v1.OpenDisputeResponse
+	(*ResolveDisputeRequest)(nil),        // 50: payments.v1.ResolveDisputeRequest
+	(*ResolveDisputeResponse)(nil),       // 51: payments.v1.ResolveDisputeResponse
+	(*DryRunInboxMessageRequest)(nil),    // 52: payments.v1.DryRunInboxMessageRequest
+	(*InboxMessageHeader)(nil),           // 53: payments.v1.InboxMessageHeader
+	(*InboxMessage)(nil),                 // 54: payments.v1.InboxMessage
+	(*DryRunOutboxEvent)(nil),            // 55: payments.v1.DryRunOutboxEvent
+	(*DryRunInboxMessageResponse)(nil),   // 56: payments.v1.DryRunInboxMessageResponse
+	(*timestamppb.Timestamp)(nil),        // 57: google.protobuf.Timestamp
 }
 var file_payments_v1_payments_proto_depIdxs = []int32{
-	0, // 0: payments.v1.CreateAccountResponse.account:type_name -> payments.v1.Account
-	0, // 1: payments.v1.TopUpResponse.account:type_name -> payments.v1.Account
-	1, // 2: payments.v1.PaymentsService.CreateAccount:input_type -> payments.v1.CreateAccountRequest
-	3, // 3: payments.v1.PaymentsService.TopUp:input_type -> payments.v1.TopUpRequest
-	5, // 4: payments.v1.PaymentsService.GetBalance:input_type -> payments.v1.GetBalanceRequest
-	2, // 5: payments.v1.PaymentsService.CreateAccount:output_type -> payments.v1.CreateAccountResponse
-	4, // 6: payments.v1.PaymentsService.TopUp:output_type -> payments.v1.TopUpResponse
-	6, // 7: payments.v1.PaymentsService.GetBalance:output_type -> payments.v1.GetBalanceResponse
-	5, // [5:8] is the sub-list for method output_type
-	2, // [2:5] is the sub-list for method input_type
-	2, // [2:2] is the sub-list for extension type_name
-	2, // [2:2] is the sub-list for extension extendee
-	0, // [0:2] is the sub-list for field type_name
+	0,  // 0: payments.v1.Account.account_type:type_name -> payments.v1.AccountType
+	6,  // 1: payments.v1.CreateAccountResponse.account:type_name -> payments.v1.Account
+	6,  // 2: payments.v1.TopUpResponse.account:type_name -> payments.v1.Account
+	0,  // 3: payments.v1.GetBalanceResponse.account_type:type_name -> payments.v1.AccountType
+	0,  // 4: payments.v1.AccountBalance.account_type:type_name -> payments.v1.AccountType
+	14, // 5: payments.v1.GetBalancesResponse.balances:type_name -> payments.v1.AccountBalance
+	57, // 6: payments.v1.GetBalanceAtRequest.at:type_name -> google.protobuf.Timestamp
+	57, // 7: payments.v1.GetBalanceAtResponse.at:type_name -> google.protobuf.Timestamp
+	1,  // 8: payments.v1.Transaction.kind:type_name -> payments.v1.TransactionKind
+	57, // 9: payments.v1.Transaction.created_at:type_name -> google.protobuf.Timestamp
+	18, // 10: payments.v1.ListTransactionsResponse.transactions:type_name -> payments.v1.Transaction
+	2,  // 11: payments.v1.ConfirmPaymentResponse.status:type_name -> payments.v1.ConfirmPaymentStatus
+	24, // 12: payments.v1.GetRatesResponse.rates:type_name -> payments.v1.ExchangeRate
+	57, // 13: payments.v1.GetRatesResponse.as_of:type_name -> google.protobuf.Timestamp
+	57, // 14: payments.v1.ReplayOutboxRequest.from:type_name -> google.protobuf.Timestamp
+	57, // 15: payments.v1.ReplayOutboxRequest.to:type_name -> google.protobuf.Timestamp
+	57, // 16: payments.v1.DeadOutboxEvent.created_at:type_name -> google.protobuf.Timestamp
+	29, // 17: payments.v1.ListDeadOutboxResponse.events:type_name -> payments.v1.DeadOutboxEvent
+	3,  // 18: payments.v1.ListOutboxRequest.state:type_name -> payments.v1.OutboxState
+	57, // 19: payments.v1.ListOutboxRequest.created_from:type_name -> google.protobuf.Timestamp
+	57, // 20: payments.v1.ListOutboxRequest.created_to:type_name -> google.protobuf.Timestamp
+	57, // 21: payments.v1.OutboxEvent.created_at:type_name -> google.protobuf.Timestamp
+	57, // 22: payments.v1.OutboxEvent.next_retry_at:type_name -> google.protobuf.Timestamp
+	32, // 23: payments.v1.ListOutboxResponse.events:type_name -> payments.v1.OutboxEvent
+	6,  // 24: payments.v1.SetOverdraftLimitResponse.account:type_name -> payments.v1.Account
+	0,  // 25: payments.v1.SetAccountTypeRequest.account_type:type_name -> payments.v1.AccountType
+	6,  // 26: payments.v1.SetAccountTypeResponse.account:type_name -> payments.v1.Account
+	57, // 27: payments.v1.GrantBonusRequest.expires_at:type_name -> google.protobuf.Timestamp
+	57, // 28: payments.v1.BonusGrant.expires_at:type_name -> google.protobuf.Timestamp
+	57, // 29: payments.v1.BonusGrant.created_at:type_name -> google.protobuf.Timestamp
+	41, // 30: payments.v1.GrantBonusResponse.grant:type_name -> payments.v1.BonusGrant
+	6,  // 31: payments.v1.AdjustBalanceResponse.account:type_name -> payments.v1.Account
+	6,  // 32: payments.v1.RematerializeBalanceResponse.account:type_name -> payments.v1.Account
+	4,  // 33: payments.v1.Dispute.status:type_name -> payments.v1.DisputeStatus
+	57, // 34: payments.v1.Dispute.opened_at:type_name -> google.protobuf.Timestamp
+	57, // 35: payments.v1.Dispute.resolved_at:type_name -> google.protobuf.Timestamp
+	47, // 36: payments.v1.OpenDisputeResponse.dispute:type_name -> payments.v1.Dispute
+	5,  // 37: payments.v1.ResolveDisputeRequest.resolution:type_name -> payments.v1.DisputeResolution
+	47, // 38: payments.v1.ResolveDisputeResponse.dispute:type_name -> payments.v1.Dispute
+	6,  // 39: payments.v1.ResolveDisputeResponse.account:type_name -> payments.v1.Account
+	53, // 40: payments.v1.InboxMessage.headers:type_name -> payments.v1.InboxMessageHeader
+	57, // 41: payments.v1.InboxMessage.received_at:type_name -> google.protobuf.Timestamp
+	57, // 42: payments.v1.InboxMessage.processed_at:type_name -> google.protobuf.Timestamp
+	54, // 43: payments.v1.DryRunInboxMessageResponse.message:type_name -> payments.v1.InboxMessage
+	55, // 44: payments.v1.DryRunInboxMessageResponse.would_publish:type_name -> payments.v1.DryRunOutboxEvent
+	7,  // 45: payments.v1.PaymentsService.CreateAccount:input_type -> payments.v1.CreateAccountRequest
+	9,  // 46: payments.v1.PaymentsService.TopUp:input_type -> payments.v1.TopUpRequest
+	11, // 47: payments.v1.PaymentsService.GetBalance:input_type -> payments.v1.GetBalanceRequest
+	13, // 48: payments.v1.PaymentsService.GetBalances:input_type -> payments.v1.GetBalancesRequest
+	16, // 49: payments.v1.PaymentsService.GetBalanceAt:input_type -> payments.v1.GetBalanceAtRequest
+	19, // 50: payments.v1.PaymentsService.ListTransactions:input_type -> payments.v1.ListTransactionsRequest
+	23, // 51: payments.v1.PaymentsService.GetRates:input_type -> payments.v1.GetRatesRequest
+	21, // 52: payments.v1.PaymentsService.ConfirmPayment:input_type -> payments.v1.ConfirmPaymentRequest
+	26, // 53: payments.v1.PaymentsAdminService.ReplayOutbox:input_type -> payments.v1.ReplayOutboxRequest
+	28, // 54: payments.v1.PaymentsAdminService.ListDeadOutbox:input_type -> payments.v1.ListDeadOutboxRequest
+	31, // 55: payments.v1.PaymentsAdminService.ListOutbox:input_type -> payments.v1.ListOutboxRequest
+	34, // 56: payments.v1.PaymentsAdminService.RequeueDeadOutbox:input_type -> payments.v1.RequeueDeadOutboxRequest
+	36, // 57: payments.v1.PaymentsAdminService.SetOverdraftLimit:input_type -> payments.v1.SetOverdraftLimitRequest
+	38, // 58: payments.v1.PaymentsAdminService.SetAccountType:input_type -> payments.v1.SetAccountTypeRequest
+	40, // 59: payments.v1.PaymentsAdminService.GrantBonus:input_type -> payments.v1.GrantBonusRequest
+	43, // 60: payments.v1.PaymentsAdminService.AdjustBalance:input_type -> payments.v1.AdjustBalanceRequest
+	45, // 61: payments.v1.PaymentsAdminService.RematerializeBalance:input_type -> payments.v1.RematerializeBalanceRequest
+	48, // 62: payments.v1.PaymentsAdminService.OpenDispute:input_type -> payments.v1.OpenDisputeRequest
+	50, // 63: payments.v1.PaymentsAdminService.ResolveDispute:input_type -> payments.v1.ResolveDisputeRequest
+	52, // 64: payments.v1.PaymentsAdminService.DryRunInboxMessage:input_type -> payments.v1.DryRunInboxMessageRequest
+	8,  // 65: payments.v1.PaymentsService.CreateAccount:output_type -> payments.v1.CreateAccountResponse
+	10, // 66: payments.v1.PaymentsService.TopUp:output_type -> payments.v1.TopUpResponse
+	12, // 67: payments.v1.PaymentsService.GetBalance:output_type -> payments.v1.GetBalanceResponse
+	15, // 68: payments.v1.PaymentsService.GetBalances:output_type -> payments.v1.GetBalancesResponse
+	17, // 69: payments.v1.PaymentsService.GetBalanceAt:output_type -> payments.v1.GetBalanceAtResponse
+	20, // 70: payments.v1.PaymentsService.ListTransactions:output_type -> payments.v1.ListTransactionsResponse
+	25, // 71: payments.v1.PaymentsService.GetRates:output_type -> payments.v1.GetRatesResponse
+	22, // 72: payments.v1.PaymentsService.ConfirmPayment:output_type -> payments.v1.ConfirmPaymentResponse
+	27, // 73: payments.v1.PaymentsAdminService.ReplayOutbox:output_type -> payments.v1.ReplayOutboxResponse
+	30, // 74: payments.v1.PaymentsAdminService.ListDeadOutbox:output_type -> payments.v1.ListDeadOutboxResponse
+	33, // 75: payments.v1.PaymentsAdminService.ListOutbox:output_type -> payments.v1.ListOutboxResponse
+	35, // 76: payments.v1.PaymentsAdminService.RequeueDeadOutbox:output_type -> payments.v1.RequeueDeadOutboxResponse
+	37, // 77: payments.v1.PaymentsAdminService.SetOverdraftLimit:output_type -> payments.v1.SetOverdraftLimitResponse
+	39, // 78: payments.v1.PaymentsAdminService.SetAccountType:output_type -> payments.v1.SetAccountTypeResponse
+	42, // 79: payments.v1.PaymentsAdminService.GrantBonus:output_type -> payments.v1.GrantBonusResponse
+	44, // 80: payments.v1.PaymentsAdminService.AdjustBalance:output_type -> payments.v1.AdjustBalanceResponse
+	46, // 81: payments.v1.PaymentsAdminService.RematerializeBalance:output_type -> payments.v1.RematerializeBalanceResponse
+	49, // 82: payments.v1.PaymentsAdminService.OpenDispute:output_type -> payments.v1.OpenDisputeResponse
+	51, // 83: payments.v1.PaymentsAdminService.ResolveDispute:output_type -> payments.v1.ResolveDisputeResponse
+	56, // 84: payments.v1.PaymentsAdminService.DryRunInboxMessage:output_type -> payments.v1.DryRunInboxMessageResponse
+	65, // [65:85] is the sub-list for method output_type
+	45, // [45:65] is the sub-list for method input_type
+	45, // [45:45] is the sub-list for extension type_name
+	45, // [45:45] is the sub-list for extension extendee
+	0,  // [0:45] is the sub-list for field type_name
 }
 
 func init() { file_payments_v1_payments_proto_init() }
@@ -440,13 +4133,14 @@ func file_payments_v1_payments_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_payments_v1_payments_proto_rawDesc), len(file_payments_v1_payments_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   7,
+			NumEnums:      6,
+			NumMessages:   51,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   2,
 		},
 		GoTypes:           file_payments_v1_payments_proto_goTypes,
 		DependencyIndexes: file_payments_v1_payments_proto_depIdxs,
+		EnumInfos:         file_payments_v1_payments_proto_enumTypes,
 		MessageInfos:      file_payments_v1_payments_proto_msgTypes,
 	}.Build()
 	File_payments_v1_payments_proto = out.File
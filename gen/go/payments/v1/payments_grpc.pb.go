@@ -19,18 +19,42 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	PaymentsService_CreateAccount_FullMethodName = "/payments.v1.PaymentsService/CreateAccount"
-	PaymentsService_TopUp_FullMethodName         = "/payments.v1.PaymentsService/TopUp"
-	PaymentsService_GetBalance_FullMethodName    = "/payments.v1.PaymentsService/GetBalance"
+	PaymentsService_CreateAccount_FullMethodName    = "/payments.v1.PaymentsService/CreateAccount"
+	PaymentsService_TopUp_FullMethodName            = "/payments.v1.PaymentsService/TopUp"
+	PaymentsService_GetBalance_FullMethodName       = "/payments.v1.PaymentsService/GetBalance"
+	PaymentsService_GetBalances_FullMethodName      = "/payments.v1.PaymentsService/GetBalances"
+	PaymentsService_GetBalanceAt_FullMethodName     = "/payments.v1.PaymentsService/GetBalanceAt"
+	PaymentsService_ListTransactions_FullMethodName = "/payments.v1.PaymentsService/ListTransactions"
+	PaymentsService_GetRates_FullMethodName         = "/payments.v1.PaymentsService/GetRates"
+	PaymentsService_ConfirmPayment_FullMethodName   = "/payments.v1.PaymentsService/ConfirmPayment"
 )
 
 // PaymentsServiceClient is the client API for PaymentsService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// The google.api.http options expose the service over REST through
+// grpc-gateway when the service runs without the separate api-gateway.
 type PaymentsServiceClient interface {
 	CreateAccount(ctx context.Context, in *CreateAccountRequest, opts ...grpc.CallOption) (*CreateAccountResponse, error)
 	TopUp(ctx context.Context, in *TopUpRequest, opts ...grpc.CallOption) (*TopUpResponse, error)
 	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	// Balances of up to 100 accounts in one call, for admin and analytics
+	// callers; support and admin only. Unknown user ids are listed in
+	// missing_user_ids.
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
+	// Balance reconstructed from the ledger as of a past moment; support only.
+	GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResponse, error)
+	// Ledger entries of the account, newest first.
+	ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error)
+	// Exchange rates against the service currency; UNAVAILABLE when no rates
+	// provider is configured.
+	GetRates(ctx context.Context, in *GetRatesRequest, opts ...grpc.CallOption) (*GetRatesResponse, error)
+	// Confirms a payment of the order that waits for the user's confirmation
+	// (PaymentChallengeRequired) and charges it. The order learns the outcome
+	// from the PaymentResult as usual. NOT_FOUND when no payment of the order
+	// waits for confirmation, e.g. because the challenge expired.
+	ConfirmPayment(ctx context.Context, in *ConfirmPaymentRequest, opts ...grpc.CallOption) (*ConfirmPaymentResponse, error)
 }
 
 type paymentsServiceClient struct {
@@ -71,13 +95,82 @@ func (c *paymentsServiceClient) GetBalance(ctx context.Context, in *GetBalanceRe
 	return out, nil
 }
 
+func (c *paymentsServiceClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, PaymentsService_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsServiceClient) GetBalanceAt(ctx context.Context, in *GetBalanceAtRequest, opts ...grpc.CallOption) (*GetBalanceAtResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalanceAtResponse)
+	err := c.cc.Invoke(ctx, PaymentsService_GetBalanceAt_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsServiceClient) ListTransactions(ctx context.Context, in *ListTransactionsRequest, opts ...grpc.CallOption) (*ListTransactionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTransactionsResponse)
+	err := c.cc.Invoke(ctx, PaymentsService_ListTransactions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsServiceClient) GetRates(ctx context.Context, in *GetRatesRequest, opts ...grpc.CallOption) (*GetRatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRatesResponse)
+	err := c.cc.Invoke(ctx, PaymentsService_GetRates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsServiceClient) ConfirmPayment(ctx context.Context, in *ConfirmPaymentRequest, opts ...grpc.CallOption) (*ConfirmPaymentResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmPaymentResponse)
+	err := c.cc.Invoke(ctx, PaymentsService_ConfirmPayment_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PaymentsServiceServer is the server API for PaymentsService service.
 // All implementations should embed UnimplementedPaymentsServiceServer
 // for forward compatibility.
+//
+// The google.api.http options expose the service over REST through
+// grpc-gateway when the service runs without the separate api-gateway.
 type PaymentsServiceServer interface {
 	CreateAccount(context.Context, *CreateAccountRequest) (*CreateAccountResponse, error)
 	TopUp(context.Context, *TopUpRequest) (*TopUpResponse, error)
 	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	// Balances of up to 100 accounts in one call, for admin and analytics
+	// callers; support and admin only. Unknown user ids are listed in
+	// missing_user_ids.
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
+	// Balance reconstructed from the ledger as of a past moment; support only.
+	GetBalanceAt(context.Context, *GetBalanceAtRequest) (*GetBalanceAtResponse, error)
+	// Ledger entries of the account, newest first.
+	ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error)
+	// Exchange rates against the service currency; UNAVAILABLE when no rates
+	// provider is configured.
+	GetRates(context.Context, *GetRatesRequest) (*GetRatesResponse, error)
+	// Confirms a payment of the order that waits for the user's confirmation
+	// (PaymentChallengeRequired) and charges it. The order learns the outcome
+	// from the PaymentResult as usual. NOT_FOUND when no payment of the order
+	// waits for confirmation, e.g. because the challenge expired.
+	ConfirmPayment(context.Context, *ConfirmPaymentRequest) (*ConfirmPaymentResponse, error)
 }
 
 // UnimplementedPaymentsServiceServer should be embedded to have
@@ -96,6 +189,21 @@ func (UnimplementedPaymentsServiceServer) TopUp(context.Context, *TopUpRequest)
 func (UnimplementedPaymentsServiceServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method GetBalance not implemented")
 }
+func (UnimplementedPaymentsServiceServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBalances not implemented")
+}
+func (UnimplementedPaymentsServiceServer) GetBalanceAt(context.Context, *GetBalanceAtRequest) (*GetBalanceAtResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetBalanceAt not implemented")
+}
+func (UnimplementedPaymentsServiceServer) ListTransactions(context.Context, *ListTransactionsRequest) (*ListTransactionsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListTransactions not implemented")
+}
+func (UnimplementedPaymentsServiceServer) GetRates(context.Context, *GetRatesRequest) (*GetRatesResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetRates not implemented")
+}
+func (UnimplementedPaymentsServiceServer) ConfirmPayment(context.Context, *ConfirmPaymentRequest) (*ConfirmPaymentResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ConfirmPayment not implemented")
+}
 func (UnimplementedPaymentsServiceServer) testEmbeddedByValue() {}
 
 // UnsafePaymentsServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -170,6 +278,96 @@ func _PaymentsService_GetBalance_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PaymentsService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsService_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServiceServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsService_GetBalanceAt_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalanceAtRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServiceServer).GetBalanceAt(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsService_GetBalanceAt_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServiceServer).GetBalanceAt(ctx, req.(*GetBalanceAtRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsService_ListTransactions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTransactionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServiceServer).ListTransactions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsService_ListTransactions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServiceServer).ListTransactions(ctx, req.(*ListTransactionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsService_GetRates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRatesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServiceServer).GetRates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsService_GetRates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServiceServer).GetRates(ctx, req.(*GetRatesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsService_ConfirmPayment_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmPaymentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsServiceServer).ConfirmPayment(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsService_ConfirmPayment_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsServiceServer).ConfirmPayment(ctx, req.(*ConfirmPaymentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // PaymentsService_ServiceDesc is the grpc.ServiceDesc for PaymentsService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -189,6 +387,614 @@ var PaymentsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBalance",
 			Handler:    _PaymentsService_GetBalance_Handler,
 		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _PaymentsService_GetBalances_Handler,
+		},
+		{
+			MethodName: "GetBalanceAt",
+			Handler:    _PaymentsService_GetBalanceAt_Handler,
+		},
+		{
+			MethodName: "ListTransactions",
+			Handler:    _PaymentsService_ListTransactions_Handler,
+		},
+		{
+			MethodName: "GetRates",
+			Handler:    _PaymentsService_GetRates_Handler,
+		},
+		{
+			MethodName: "ConfirmPayment",
+			Handler:    _PaymentsService_ConfirmPayment_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "payments/v1/payments.proto",
+}
+
+const (
+	PaymentsAdminService_ReplayOutbox_FullMethodName         = "/payments.v1.PaymentsAdminService/ReplayOutbox"
+	PaymentsAdminService_ListDeadOutbox_FullMethodName       = "/payments.v1.PaymentsAdminService/ListDeadOutbox"
+	PaymentsAdminService_ListOutbox_FullMethodName           = "/payments.v1.PaymentsAdminService/ListOutbox"
+	PaymentsAdminService_RequeueDeadOutbox_FullMethodName    = "/payments.v1.PaymentsAdminService/RequeueDeadOutbox"
+	PaymentsAdminService_SetOverdraftLimit_FullMethodName    = "/payments.v1.PaymentsAdminService/SetOverdraftLimit"
+	PaymentsAdminService_SetAccountType_FullMethodName       = "/payments.v1.PaymentsAdminService/SetAccountType"
+	PaymentsAdminService_GrantBonus_FullMethodName           = "/payments.v1.PaymentsAdminService/GrantBonus"
+	PaymentsAdminService_AdjustBalance_FullMethodName        = "/payments.v1.PaymentsAdminService/AdjustBalance"
+	PaymentsAdminService_RematerializeBalance_FullMethodName = "/payments.v1.PaymentsAdminService/RematerializeBalance"
+	PaymentsAdminService_OpenDispute_FullMethodName          = "/payments.v1.PaymentsAdminService/OpenDispute"
+	PaymentsAdminService_ResolveDispute_FullMethodName       = "/payments.v1.PaymentsAdminService/ResolveDispute"
+	PaymentsAdminService_DryRunInboxMessage_FullMethodName   = "/payments.v1.PaymentsAdminService/DryRunInboxMessage"
+)
+
+// PaymentsAdminServiceClient is the client API for PaymentsAdminService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Operator RPCs. Registered only when ENABLE_ADMIN_API is set and, with RBAC
+// on, callable by the admin role only.
+type PaymentsAdminServiceClient interface {
+	// Re-emits already sent outbox events by inserting copies, e.g. after a
+	// topic was recreated. Copies are published like any new event. With
+	// sharded accounts every shard is replayed and the counts are summed.
+	ReplayOutbox(ctx context.Context, in *ReplayOutboxRequest, opts ...grpc.CallOption) (*ReplayOutboxResponse, error)
+	// Lists outbox events that were dead-lettered after OUTBOX_MAX_ATTEMPTS
+	// failed publishes, oldest first, one shard at a time.
+	ListDeadOutbox(ctx context.Context, in *ListDeadOutboxRequest, opts ...grpc.CallOption) (*ListDeadOutboxResponse, error)
+	// Lists outbox events in one state, oldest first, so stuck events can be
+	// diagnosed without database access, one shard at a time. Read-only;
+	// with RBAC on, the support role may call it too.
+	ListOutbox(ctx context.Context, in *ListOutboxRequest, opts ...grpc.CallOption) (*ListOutboxResponse, error)
+	// Puts dead-lettered outbox events of a shard back in the queue with their
+	// attempts reset.
+	RequeueDeadOutbox(ctx context.Context, in *RequeueDeadOutboxRequest, opts ...grpc.CallOption) (*RequeueDeadOutboxResponse, error)
+	// Sets how far below zero payments may take an account's balance.
+	SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*SetOverdraftLimitResponse, error)
+	// Moves an account to another tier and applies the tier's overdraft limit.
+	SetAccountType(ctx context.Context, in *SetAccountTypeRequest, opts ...grpc.CallOption) (*SetAccountTypeResponse, error)
+	// Credits promotional bonus that expires at expires_at.
+	GrantBonus(ctx context.Context, in *GrantBonusRequest, opts ...grpc.CallOption) (*GrantBonusResponse, error)
+	// Credits or debits an account by hand, e.g. to settle a disputed payment.
+	// Booked in the ledger as TRANSACTION_KIND_ADJUSTMENT and recorded in
+	// admin_audit_log with the operator and reason. Needs an operator token:
+	// without RBAC the operator is unknown and the call is UNAUTHENTICATED.
+	AdjustBalance(ctx context.Context, in *AdjustBalanceRequest, opts ...grpc.CallOption) (*AdjustBalanceResponse, error)
+	// Sets the stored balance of an account to the balance of its ledger
+	// account, e.g. after the balance checker recorded an anomaly, and resolves
+	// the account's open anomalies. Nothing is booked in the ledger. Recorded in
+	// admin_audit_log with the operator and reason.
+	RematerializeBalance(ctx context.Context, in *RematerializeBalanceRequest, opts ...grpc.CallOption) (*RematerializeBalanceResponse, error)
+	// Opens a dispute (chargeback) of an order: its payments are frozen in the
+	// ledger, PaymentDisputeChanged goes out and orders-service moves the
+	// order to DISPUTED. An order is disputed at most once, and only when one
+	// user paid for all of it. Recorded in admin_audit_log.
+	OpenDispute(ctx context.Context, in *OpenDisputeRequest, opts ...grpc.CallOption) (*OpenDisputeResponse, error)
+	// Resolves an open dispute. REFUND credits what the payer paid back to
+	// their balance and the order becomes REFUNDED; UPHOLD unfreezes the
+	// payments and the order is FINISHED again. Recorded in admin_audit_log.
+	ResolveDispute(ctx context.Context, in *ResolveDisputeRequest, opts ...grpc.CallOption) (*ResolveDisputeResponse, error)
+	// Runs a message kept in the inbox with INBOX_STORE_PAYLOADS through its
+	// consumer again, processed or not, and rolls everything back. Returns
+	// what the consumer did: why it skipped the message, the error it failed
+	// with, or the events it would have published.
+	DryRunInboxMessage(ctx context.Context, in *DryRunInboxMessageRequest, opts ...grpc.CallOption) (*DryRunInboxMessageResponse, error)
+}
+
+type paymentsAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPaymentsAdminServiceClient(cc grpc.ClientConnInterface) PaymentsAdminServiceClient {
+	return &paymentsAdminServiceClient{cc}
+}
+
+func (c *paymentsAdminServiceClient) ReplayOutbox(ctx context.Context, in *ReplayOutboxRequest, opts ...grpc.CallOption) (*ReplayOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReplayOutboxResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_ReplayOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) ListDeadOutbox(ctx context.Context, in *ListDeadOutboxRequest, opts ...grpc.CallOption) (*ListDeadOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDeadOutboxResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_ListDeadOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) ListOutbox(ctx context.Context, in *ListOutboxRequest, opts ...grpc.CallOption) (*ListOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListOutboxResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_ListOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) RequeueDeadOutbox(ctx context.Context, in *RequeueDeadOutboxRequest, opts ...grpc.CallOption) (*RequeueDeadOutboxResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RequeueDeadOutboxResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_RequeueDeadOutbox_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) SetOverdraftLimit(ctx context.Context, in *SetOverdraftLimitRequest, opts ...grpc.CallOption) (*SetOverdraftLimitResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetOverdraftLimitResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_SetOverdraftLimit_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) SetAccountType(ctx context.Context, in *SetAccountTypeRequest, opts ...grpc.CallOption) (*SetAccountTypeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SetAccountTypeResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_SetAccountType_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) GrantBonus(ctx context.Context, in *GrantBonusRequest, opts ...grpc.CallOption) (*GrantBonusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GrantBonusResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_GrantBonus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) AdjustBalance(ctx context.Context, in *AdjustBalanceRequest, opts ...grpc.CallOption) (*AdjustBalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdjustBalanceResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_AdjustBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) RematerializeBalance(ctx context.Context, in *RematerializeBalanceRequest, opts ...grpc.CallOption) (*RematerializeBalanceResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RematerializeBalanceResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_RematerializeBalance_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) OpenDispute(ctx context.Context, in *OpenDisputeRequest, opts ...grpc.CallOption) (*OpenDisputeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(OpenDisputeResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_OpenDispute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) ResolveDispute(ctx context.Context, in *ResolveDisputeRequest, opts ...grpc.CallOption) (*ResolveDisputeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ResolveDisputeResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_ResolveDispute_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *paymentsAdminServiceClient) DryRunInboxMessage(ctx context.Context, in *DryRunInboxMessageRequest, opts ...grpc.CallOption) (*DryRunInboxMessageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DryRunInboxMessageResponse)
+	err := c.cc.Invoke(ctx, PaymentsAdminService_DryRunInboxMessage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PaymentsAdminServiceServer is the server API for PaymentsAdminService service.
+// All implementations should embed UnimplementedPaymentsAdminServiceServer
+// for forward compatibility.
+//
+// Operator RPCs. Registered only when ENABLE_ADMIN_API is set and, with RBAC
+// on, callable by the admin role only.
+type PaymentsAdminServiceServer interface {
+	// Re-emits already sent outbox events by inserting copies, e.g. after a
+	// topic was recreated. Copies are published like any new event. With
+	// sharded accounts every shard is replayed and the counts are summed.
+	ReplayOutbox(context.Context, *ReplayOutboxRequest) (*ReplayOutboxResponse, error)
+	// Lists outbox events that were dead-lettered after OUTBOX_MAX_ATTEMPTS
+	// failed publishes, oldest first, one shard at a time.
+	ListDeadOutbox(context.Context, *ListDeadOutboxRequest) (*ListDeadOutboxResponse, error)
+	// Lists outbox events in one state, oldest first, so stuck events can be
+	// diagnosed without database access, one shard at a time. Read-only;
+	// with RBAC on, the support role may call it too.
+	ListOutbox(context.Context, *ListOutboxRequest) (*ListOutboxResponse, error)
+	// Puts dead-lettered outbox events of a shard back in the queue with their
+	// attempts reset.
+	RequeueDeadOutbox(context.Context, *RequeueDeadOutboxRequest) (*RequeueDeadOutboxResponse, error)
+	// Sets how far below zero payments may take an account's balance.
+	SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*SetOverdraftLimitResponse, error)
+	// Moves an account to another tier and applies the tier's overdraft limit.
+	SetAccountType(context.Context, *SetAccountTypeRequest) (*SetAccountTypeResponse, error)
+	// Credits promotional bonus that expires at expires_at.
+	GrantBonus(context.Context, *GrantBonusRequest) (*GrantBonusResponse, error)
+	// Credits or debits an account by hand, e.g. to settle a disputed payment.
+	// Booked in the ledger as TRANSACTION_KIND_ADJUSTMENT and recorded in
+	// admin_audit_log with the operator and reason. Needs an operator token:
+	// without RBAC the operator is unknown and the call is UNAUTHENTICATED.
+	AdjustBalance(context.Context, *AdjustBalanceRequest) (*AdjustBalanceResponse, error)
+	// Sets the stored balance of an account to the balance of its ledger
+	// account, e.g. after the balance checker recorded an anomaly, and resolves
+	// the account's open anomalies. Nothing is booked in the ledger. Recorded in
+	// admin_audit_log with the operator and reason.
+	RematerializeBalance(context.Context, *RematerializeBalanceRequest) (*RematerializeBalanceResponse, error)
+	// Opens a dispute (chargeback) of an order: its payments are frozen in the
+	// ledger, PaymentDisputeChanged goes out and orders-service moves the
+	// order to DISPUTED. An order is disputed at most once, and only when one
+	// user paid for all of it. Recorded in admin_audit_log.
+	OpenDispute(context.Context, *OpenDisputeRequest) (*OpenDisputeResponse, error)
+	// Resolves an open dispute. REFUND credits what the payer paid back to
+	// their balance and the order becomes REFUNDED; UPHOLD unfreezes the
+	// payments and the order is FINISHED again. Recorded in admin_audit_log.
+	ResolveDispute(context.Context, *ResolveDisputeRequest) (*ResolveDisputeResponse, error)
+	// Runs a message kept in the inbox with INBOX_STORE_PAYLOADS through its
+	// consumer again, processed or not, and rolls everything back. Returns
+	// what the consumer did: why it skipped the message, the error it failed
+	// with, or the events it would have published.
+	DryRunInboxMessage(context.Context, *DryRunInboxMessageRequest) (*DryRunInboxMessageResponse, error)
+}
+
+// UnimplementedPaymentsAdminServiceServer should be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPaymentsAdminServiceServer struct{}
+
+func (UnimplementedPaymentsAdminServiceServer) ReplayOutbox(context.Context, *ReplayOutboxRequest) (*ReplayOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ReplayOutbox not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) ListDeadOutbox(context.Context, *ListDeadOutboxRequest) (*ListDeadOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDeadOutbox not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) ListOutbox(context.Context, *ListOutboxRequest) (*ListOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListOutbox not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) RequeueDeadOutbox(context.Context, *RequeueDeadOutboxRequest) (*RequeueDeadOutboxResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RequeueDeadOutbox not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) SetOverdraftLimit(context.Context, *SetOverdraftLimitRequest) (*SetOverdraftLimitResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetOverdraftLimit not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) SetAccountType(context.Context, *SetAccountTypeRequest) (*SetAccountTypeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method SetAccountType not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) GrantBonus(context.Context, *GrantBonusRequest) (*GrantBonusResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method GrantBonus not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) AdjustBalance(context.Context, *AdjustBalanceRequest) (*AdjustBalanceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method AdjustBalance not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) RematerializeBalance(context.Context, *RematerializeBalanceRequest) (*RematerializeBalanceResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method RematerializeBalance not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) OpenDispute(context.Context, *OpenDisputeRequest) (*OpenDisputeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method OpenDispute not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) ResolveDispute(context.Context, *ResolveDisputeRequest) (*ResolveDisputeResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ResolveDispute not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) DryRunInboxMessage(context.Context, *DryRunInboxMessageRequest) (*DryRunInboxMessageResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DryRunInboxMessage not implemented")
+}
+func (UnimplementedPaymentsAdminServiceServer) testEmbeddedByValue() {}
+
+// UnsafePaymentsAdminServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PaymentsAdminServiceServer will
+// result in compilation errors.
+type UnsafePaymentsAdminServiceServer interface {
+	mustEmbedUnimplementedPaymentsAdminServiceServer()
+}
+
+func RegisterPaymentsAdminServiceServer(s grpc.ServiceRegistrar, srv PaymentsAdminServiceServer) {
+	// If the following call panics, it indicates UnimplementedPaymentsAdminServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PaymentsAdminService_ServiceDesc, srv)
+}
+
+func _PaymentsAdminService_ReplayOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReplayOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).ReplayOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_ReplayOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).ReplayOutbox(ctx, req.(*ReplayOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_ListDeadOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDeadOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).ListDeadOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_ListDeadOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).ListDeadOutbox(ctx, req.(*ListDeadOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_ListOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).ListOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_ListOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).ListOutbox(ctx, req.(*ListOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_RequeueDeadOutbox_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RequeueDeadOutboxRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).RequeueDeadOutbox(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_RequeueDeadOutbox_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).RequeueDeadOutbox(ctx, req.(*RequeueDeadOutboxRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_SetOverdraftLimit_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetOverdraftLimitRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).SetOverdraftLimit(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_SetOverdraftLimit_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).SetOverdraftLimit(ctx, req.(*SetOverdraftLimitRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_SetAccountType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SetAccountTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).SetAccountType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_SetAccountType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).SetAccountType(ctx, req.(*SetAccountTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_GrantBonus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GrantBonusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).GrantBonus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_GrantBonus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).GrantBonus(ctx, req.(*GrantBonusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_AdjustBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdjustBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).AdjustBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_AdjustBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).AdjustBalance(ctx, req.(*AdjustBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_RematerializeBalance_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RematerializeBalanceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).RematerializeBalance(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_RematerializeBalance_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).RematerializeBalance(ctx, req.(*RematerializeBalanceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_OpenDispute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(OpenDisputeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).OpenDispute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_OpenDispute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).OpenDispute(ctx, req.(*OpenDisputeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_ResolveDispute_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResolveDisputeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).ResolveDispute(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_ResolveDispute_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).ResolveDispute(ctx, req.(*ResolveDisputeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PaymentsAdminService_DryRunInboxMessage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DryRunInboxMessageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PaymentsAdminServiceServer).DryRunInboxMessage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PaymentsAdminService_DryRunInboxMessage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PaymentsAdminServiceServer).DryRunInboxMessage(ctx, req.(*DryRunInboxMessageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PaymentsAdminService_ServiceDesc is the grpc.ServiceDesc for PaymentsAdminService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PaymentsAdminService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "payments.v1.PaymentsAdminService",
+	HandlerType: (*PaymentsAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ReplayOutbox",
+			Handler:    _PaymentsAdminService_ReplayOutbox_Handler,
+		},
+		{
+			MethodName: "ListDeadOutbox",
+			Handler:    _PaymentsAdminService_ListDeadOutbox_Handler,
+		},
+		{
+			MethodName: "ListOutbox",
+			Handler:    _PaymentsAdminService_ListOutbox_Handler,
+		},
+		{
+			MethodName: "RequeueDeadOutbox",
+			Handler:    _PaymentsAdminService_RequeueDeadOutbox_Handler,
+		},
+		{
+			MethodName: "SetOverdraftLimit",
+			Handler:    _PaymentsAdminService_SetOverdraftLimit_Handler,
+		},
+		{
+			MethodName: "SetAccountType",
+			Handler:    _PaymentsAdminService_SetAccountType_Handler,
+		},
+		{
+			MethodName: "GrantBonus",
+			Handler:    _PaymentsAdminService_GrantBonus_Handler,
+		},
+		{
+			MethodName: "AdjustBalance",
+			Handler:    _PaymentsAdminService_AdjustBalance_Handler,
+		},
+		{
+			MethodName: "RematerializeBalance",
+			Handler:    _PaymentsAdminService_RematerializeBalance_Handler,
+		},
+		{
+			MethodName: "OpenDispute",
+			Handler:    _PaymentsAdminService_OpenDispute_Handler,
+		},
+		{
+			MethodName: "ResolveDispute",
+			Handler:    _PaymentsAdminService_ResolveDispute_Handler,
+		},
+		{
+			MethodName: "DryRunInboxMessage",
+			Handler:    _PaymentsAdminService_DryRunInboxMessage_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "payments/v1/payments.proto",
@@ -17,67 +17,326 @@ import (
 
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
 	"github.com/oapi-codegen/runtime"
 )
 
 const (
+	AdminTokenAuthScopes   = "AdminTokenAuth.Scopes"
+	BearerAuthScopes       = "BearerAuth.Scopes"
 	UserIdHeaderAuthScopes = "UserIdHeaderAuth.Scopes"
 )
 
+// Defines values for AccountType.
+const (
+	BASIC    AccountType = "BASIC"
+	BUSINESS AccountType = "BUSINESS"
+	PREMIUM  AccountType = "PREMIUM"
+)
+
+// Defines values for ApiKeyScope.
+const (
+	OrdersRead    ApiKeyScope = "orders:read"
+	OrdersWrite   ApiKeyScope = "orders:write"
+	PaymentsRead  ApiKeyScope = "payments:read"
+	PaymentsWrite ApiKeyScope = "payments:write"
+)
+
+// Defines values for ConfirmPaymentResponseStatus.
+const (
+	ConfirmPaymentFailed    ConfirmPaymentResponseStatus = "FAILED"
+	ConfirmPaymentPending   ConfirmPaymentResponseStatus = "PENDING"
+	ConfirmPaymentSucceeded ConfirmPaymentResponseStatus = "SUCCEEDED"
+)
+
+// Defines values for NotificationKind.
+const (
+	ORDERSTATUSCHANGED       NotificationKind = "ORDER_STATUS_CHANGED"
+	PAYMENTCHALLENGEREQUIRED NotificationKind = "PAYMENT_CHALLENGE_REQUIRED"
+	PAYMENTFAILED            NotificationKind = "PAYMENT_FAILED"
+	PAYMENTSUCCEEDED         NotificationKind = "PAYMENT_SUCCEEDED"
+)
+
+// Defines values for OrderDisputeStatus.
+const (
+	OrderDisputeOpen     OrderDisputeStatus = "OPEN"
+	OrderDisputeRefunded OrderDisputeStatus = "REFUNDED"
+	OrderDisputeUpheld   OrderDisputeStatus = "UPHELD"
+)
+
 // Defines values for OrderStatus.
 const (
-	CANCELLED OrderStatus = "CANCELLED"
-	FINISHED  OrderStatus = "FINISHED"
-	NEW       OrderStatus = "NEW"
+	OrderStatusCANCELLED     OrderStatus = "CANCELLED"
+	OrderStatusDISPUTED      OrderStatus = "DISPUTED"
+	OrderStatusFINISHED      OrderStatus = "FINISHED"
+	OrderStatusNEW           OrderStatus = "NEW"
+	OrderStatusPARTIALLYPAID OrderStatus = "PARTIALLY_PAID"
+	OrderStatusREFUNDED      OrderStatus = "REFUNDED"
+	OrderStatusSCHEDULED     OrderStatus = "SCHEDULED"
+)
+
+// Defines values for OrderTransferStatus.
+const (
+	OrderTransferStatusACCEPTED  OrderTransferStatus = "ACCEPTED"
+	OrderTransferStatusCANCELLED OrderTransferStatus = "CANCELLED"
+	OrderTransferStatusPENDING   OrderTransferStatus = "PENDING"
+)
+
+// Defines values for ReceiptItemKind.
+const (
+	ReceiptItemKindDiscount ReceiptItemKind = "discount"
+	ReceiptItemKindFee      ReceiptItemKind = "fee"
+	ReceiptItemKindOrder    ReceiptItemKind = "order"
+	ReceiptItemKindProduct  ReceiptItemKind = "product"
+)
+
+// Defines values for GetOrderReceiptParamsFormat.
+const (
+	Json GetOrderReceiptParamsFormat = "json"
+	Pdf  GetOrderReceiptParamsFormat = "pdf"
 )
 
-// CreateAccountRequest Empty request body. user_id is taken from X-User-Id header, or generated by gateway if missing.
+// AcceptOrderTransferResponse defines model for AcceptOrderTransferResponse.
+type AcceptOrderTransferResponse struct {
+	Order Order `json:"order"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// AccountType Account tier; decides the payment limit, overdraft and fees.
+type AccountType string
+
+// AccountVersion Incremented by every balance change.
+type AccountVersion = int64
+
+// ApiKey defines model for ApiKey.
+type ApiKey struct {
+	CreatedAt time.Time `json:"created_at"`
+	Id        string    `json:"id"`
+
+	// MonthlyCallQuota API calls allowed per calendar month (UTC); 0 is unlimited.
+	MonthlyCallQuota int64 `json:"monthly_call_quota"`
+
+	// MonthlyVolumeQuota Amount in minimal currency units that orders, installment payments and top-ups may move per calendar month (UTC); 0 is unlimited.
+	MonthlyVolumeQuota int64         `json:"monthly_volume_quota"`
+	Name               string        `json:"name"`
+	RateLimitPerMinute int32         `json:"rate_limit_per_minute"`
+	RevokedAt          *time.Time    `json:"revoked_at,omitempty"`
+	Scopes             []ApiKeyScope `json:"scopes"`
+
+	// Subjects User ids the key may act for in X-User-Id.
+	Subjects []string `json:"subjects"`
+}
+
+// ApiKeyScope defines model for ApiKeyScope.
+type ApiKeyScope string
+
+// AuditEntry defines model for AuditEntry.
+type AuditEntry struct {
+	IdempotencyKey *string   `json:"idempotency_key,omitempty"`
+	LatencyMs      int64     `json:"latency_ms"`
+	Method         string    `json:"method"`
+	OccurredAt     time.Time `json:"occurred_at"`
+	Path           string    `json:"path"`
+	Status         int32     `json:"status"`
+	UserId         string    `json:"user_id"`
+
+	// UserVerified false when user_id is the unchecked X-User-Id of a call the gateway rejected
+	UserVerified bool `json:"user_verified"`
+}
+
+// AuditEntryList defines model for AuditEntryList.
+type AuditEntryList struct {
+	Entries []AuditEntry `json:"entries"`
+}
+
+// BalanceAtResponse defines model for BalanceAtResponse.
+type BalanceAtResponse struct {
+	At time.Time `json:"at"`
+
+	// Balance Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Balance MoneyAmount `json:"balance"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
+	UserId   string   `json:"user_id"`
+}
+
+// CancelOrderResponse defines model for CancelOrderResponse.
+type CancelOrderResponse struct {
+	Order Order `json:"order"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// ConfirmPaymentRequest defines model for ConfirmPaymentRequest.
+type ConfirmPaymentRequest struct {
+	// PaymentId The payment to confirm; the oldest one waiting for confirmation when omitted.
+	PaymentId *string `json:"payment_id,omitempty"`
+}
+
+// ConfirmPaymentResponse defines model for ConfirmPaymentResponse.
+type ConfirmPaymentResponse struct {
+	OrderId   string `json:"order_id"`
+	PaymentId string `json:"payment_id"`
+
+	// Reason Why a FAILED payment failed.
+	Reason *string `json:"reason,omitempty"`
+
+	// Status SUCCEEDED and FAILED are final; PENDING means the payment method settles the payment later. The order is updated asynchronously either way.
+	Status ConfirmPaymentResponseStatus `json:"status"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// ConfirmPaymentResponseStatus SUCCEEDED and FAILED are final; PENDING means the payment method settles the payment later. The order is updated asynchronously either way.
+type ConfirmPaymentResponseStatus string
+
+// CreateAccountRequest Empty request body. user_id is taken from the X-User-Id header.
 type CreateAccountRequest = map[string]interface{}
 
 // CreateAccountResponse defines model for CreateAccountResponse.
 type CreateAccountResponse struct {
-	Balance int64 `json:"balance"`
+	// AccountType Account tier; decides the payment limit, overdraft and fees.
+	AccountType *AccountType `json:"account_type,omitempty"`
+
+	// Balance Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Balance MoneyAmount `json:"balance"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
 
-	// UserId Resolved user id (provided or generated by gateway).
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
 	UserId string `json:"user_id"`
+
+	// Version Incremented by every balance change.
+	Version *AccountVersion `json:"version,omitempty"`
+}
+
+// CreateApiKeyRequest defines model for CreateApiKeyRequest.
+type CreateApiKeyRequest struct {
+	MonthlyCallQuota   *int64        `json:"monthly_call_quota,omitempty"`
+	MonthlyVolumeQuota *int64        `json:"monthly_volume_quota,omitempty"`
+	Name               string        `json:"name"`
+	RateLimitPerMinute *int32        `json:"rate_limit_per_minute,omitempty"`
+	Scopes             []ApiKeyScope `json:"scopes"`
+
+	// Subjects User ids the key may act for. Requests name one of them in X-User-Id; a key bound to a single user may leave the header out.
+	Subjects []string `json:"subjects"`
+}
+
+// CreateApiKeyResponse defines model for CreateApiKeyResponse.
+type CreateApiKeyResponse struct {
+	ApiKey ApiKey `json:"api_key"`
+
+	// Key The secret. Shown only once; the gateway stores its hash.
+	Key string `json:"key"`
 }
 
 // CreateOrderRequest defines model for CreateOrderRequest.
 type CreateOrderRequest struct {
-	Amount      int64  `json:"amount"`
-	Description string `json:"description"`
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency    *Currency `json:"currency,omitempty"`
+	Description string    `json:"description"`
+
+	// Force Create the order even if the same amount and description were ordered moments ago; otherwise such a request is answered with 409 duplicate_order and details.order_id of the existing order.
+	Force *bool `json:"force,omitempty"`
+
+	// Metadata Free-form integrator references (e.g. an invoice number). At most 20 keys of up to 40 bytes, values up to 500 bytes.
+	Metadata *OrderMetadata `json:"metadata,omitempty"`
+
+	// PayAt Request the payment at this time instead of right away. The order stays SCHEDULED until then and can be cancelled via POST /orders/{orderId}/cancel. Must be in the future; not allowed together with pay_in_installments.
+	PayAt *time.Time `json:"pay_at,omitempty"`
+
+	// PayInInstallments If true, payment is not started on creation; the order is paid in parts via POST /orders/{orderId}/payments.
+	PayInInstallments *bool `json:"pay_in_installments,omitempty"`
+
+	// PaymentMethod How the part of each payment not covered by bonus grants is paid: balance (the default), or card and psp, where enabled. With psp the order stays pending until the external payment provider settles the charge. A method the payments service does not support cancels the order with payment_failure_reason starting with "unsupported payment method".
+	PaymentMethod *string `json:"payment_method,omitempty"`
+
+	// PromoCode Case-insensitive promo code whose discount is taken off amount. A code that cannot be used is answered with 400 and code promo_code_not_found, promo_code_inactive, promo_code_exhausted or promo_code_already_used; details.promo_code holds the code.
+	PromoCode *string `json:"promo_code,omitempty"`
+
+	// Tags Unique tags for filtering orders (GET /orders?tag=...). At most 10, each 1..64 bytes.
+	Tags *OrderTags `json:"tags,omitempty"`
 }
 
 // CreateOrderResponse defines model for CreateOrderResponse.
 type CreateOrderResponse struct {
-	Order Order `json:"order"`
+	// DiscountAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	DiscountAmount *MoneyAmount `json:"discount_amount,omitempty"`
+	Order          Order        `json:"order"`
 
-	// UserId Resolved user id (provided or generated by gateway).
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
 	UserId string `json:"user_id"`
 }
 
+// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+type Currency = string
+
 // ErrorResponse defines model for ErrorResponse.
 type ErrorResponse struct {
+	// Code Stable machine-readable error code, e.g. invalid_argument, not_found, rate_limited, idempotency_key_required.
+	Code    *string                 `json:"code,omitempty"`
 	Details *map[string]interface{} `json:"details,omitempty"`
-	Error   string                  `json:"error"`
 
-	// UserId Resolved user id (provided or generated by gateway), if available.
+	// Error Developer-facing message in English.
+	Error string `json:"error"`
+
+	// Message End-user message for code in the language negotiated from Accept-Language (en, ru; see Content-Language). Absent for codes without a translation.
+	Message *string `json:"message,omitempty"`
+
+	// UserId Caller's user id, if known.
 	UserId *string `json:"user_id,omitempty"`
 }
 
+// ExchangeRate defines model for ExchangeRate.
+type ExchangeRate struct {
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
+
+	// Rate Price of one unit of the currency in the base currency, as a decimal string.
+	Rate string `json:"rate"`
+}
+
 // GetBalanceResponse defines model for GetBalanceResponse.
 type GetBalanceResponse struct {
-	Balance int64 `json:"balance"`
+	// AccountType Account tier; decides the payment limit, overdraft and fees.
+	AccountType *AccountType `json:"account_type,omitempty"`
+
+	// Balance Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Balance MoneyAmount `json:"balance"`
+
+	// BonusBalance Unexpired promotional credit, spent on payments before balance. Omitted when zero.
+	BonusBalance *MoneyAmount `json:"bonus_balance,omitempty"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
 
 	// UserId User id from request header.
 	UserId string `json:"user_id"`
+
+	// Version Incremented by every balance change.
+	Version *AccountVersion `json:"version,omitempty"`
+}
+
+// GetOrderReceiptResponse defines model for GetOrderReceiptResponse.
+type GetOrderReceiptResponse struct {
+	Receipt Receipt `json:"receipt"`
+	UserId  string  `json:"user_id"`
 }
 
 // GetOrderResponse defines model for GetOrderResponse.
 type GetOrderResponse struct {
 	Order Order `json:"order"`
 
-	// UserId Resolved user id (provided or generated by gateway).
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
 	UserId string `json:"user_id"`
 }
 
@@ -85,55 +344,405 @@ type GetOrderResponse struct {
 type ListOrdersResponse struct {
 	Orders []Order `json:"orders"`
 
-	// UserId Resolved user id (provided or generated by gateway).
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
 	UserId string `json:"user_id"`
 }
 
+// MoneyAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+type MoneyAmount = money.Amount
+
+// NotificationKind defines model for NotificationKind.
+type NotificationKind string
+
+// NotificationPreferences defines model for NotificationPreferences.
+type NotificationPreferences struct {
+	// Email Empty turns email off.
+	Email string `json:"email"`
+
+	// Kinds Notifications the user wants; empty means all.
+	Kinds []NotificationKind `json:"kinds"`
+
+	// TelegramChatId Numeric chat id the bot writes to; empty turns Telegram off.
+	TelegramChatId string `json:"telegram_chat_id"`
+
+	// UpdatedAt Omitted until the preferences are first saved.
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserId    string     `json:"user_id"`
+
+	// WebhookUrl https URL that receives signed JSON callbacks (X-Signature header, see README); empty turns webhooks off. Redirects are not followed, and hosts resolving to private, loopback or link-local addresses are not called.
+	WebhookUrl string `json:"webhook_url"`
+}
+
 // Order defines model for Order.
 type Order struct {
-	// Amount Amount in minimal currency units (e.g. cents/kopecks).
-	Amount      int64       `json:"amount"`
-	CreatedAt   *time.Time  `json:"created_at,omitempty"`
-	Description string      `json:"description"`
-	OrderId     string      `json:"order_id"`
-	Status      OrderStatus `json:"status"`
-	UserId      string      `json:"user_id"`
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Archived The order was moved to the archive; only set when true.
+	Archived  *bool      `json:"archived,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency    Currency `json:"currency"`
+	Description string   `json:"description"`
+
+	// Dispute The payment dispute of the order. Present only when the order was disputed; order lists leave it out.
+	Dispute *OrderDispute `json:"dispute,omitempty"`
+
+	// FeeAmount Fees charged on top of the successful payments; not part of paid_amount.
+	FeeAmount *MoneyAmount `json:"fee_amount,omitempty"`
+
+	// Metadata Free-form integrator references (e.g. an invoice number). At most 20 keys of up to 40 bytes, values up to 500 bytes.
+	Metadata *OrderMetadata `json:"metadata,omitempty"`
+	OrderId  string         `json:"order_id"`
+
+	// PaidAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	PaidAmount *MoneyAmount `json:"paid_amount,omitempty"`
+
+	// PayAt When the payment of a scheduled order is (or was) requested; absent for orders paid right away.
+	PayAt *time.Time `json:"pay_at,omitempty"`
+
+	// PaymentFailureReason Why the payment failed. Present only for CANCELLED orders.
+	PaymentFailureReason *string `json:"payment_failure_reason,omitempty"`
+
+	// Status DISPUTED orders have a payment dispute open; REFUNDED ones had the payment refunded to the payer's balance.
+	Status OrderStatus `json:"status"`
+
+	// Tags Unique tags for filtering orders (GET /orders?tag=...). At most 10, each 1..64 bytes.
+	Tags      *OrderTags `json:"tags,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	UserId    string     `json:"user_id"`
+
+	// Version Incremented on every change; pass it to PATCH /orders/{orderId} to detect concurrent edits.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// OrderDispute The payment dispute of the order. Present only when the order was disputed; order lists leave it out.
+type OrderDispute struct {
+	// Amount The disputed amount, including the fee; refunded when the dispute is REFUNDED.
+	Amount     MoneyAmount `json:"amount"`
+	OpenedAt   time.Time   `json:"opened_at"`
+	Reason     string      `json:"reason"`
+	ResolvedAt *time.Time  `json:"resolved_at,omitempty"`
+
+	// Status UPHELD disputes ended with the payment kept and the order FINISHED again.
+	Status OrderDisputeStatus `json:"status"`
 }
 
-// OrderStatus defines model for OrderStatus.
+// OrderDisputeStatus UPHELD disputes ended with the payment kept and the order FINISHED again.
+type OrderDisputeStatus string
+
+// OrderMetadata Free-form integrator references (e.g. an invoice number). At most 20 keys of up to 40 bytes, values up to 500 bytes.
+type OrderMetadata map[string]string
+
+// OrderStatus DISPUTED orders have a payment dispute open; REFUNDED ones had the payment refunded to the payer's balance.
 type OrderStatus string
 
+// OrderTags Unique tags for filtering orders (GET /orders?tag=...). At most 10, each 1..64 bytes.
+type OrderTags = []string
+
+// OrderTransfer defines model for OrderTransfer.
+type OrderTransfer struct {
+	CreatedAt  time.Time           `json:"created_at"`
+	FromUserId string              `json:"from_user_id"`
+	OrderId    string              `json:"order_id"`
+	Status     OrderTransferStatus `json:"status"`
+	ToUserId   string              `json:"to_user_id"`
+	TransferId string              `json:"transfer_id"`
+}
+
+// OrderTransferStatus defines model for OrderTransferStatus.
+type OrderTransferStatus string
+
+// PayOrderRequest defines model for PayOrderRequest.
+type PayOrderRequest struct {
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency *Currency `json:"currency,omitempty"`
+}
+
+// PayOrderResponse defines model for PayOrderResponse.
+type PayOrderResponse struct {
+	Order Order `json:"order"`
+
+	// PaymentId Identifier of the requested installment payment.
+	PaymentId string `json:"payment_id"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// RatesResponse defines model for RatesResponse.
+type RatesResponse struct {
+	// AsOf When the provider published the rates.
+	AsOf time.Time `json:"as_of"`
+
+	// Base ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Base  Currency       `json:"base"`
+	Rates []ExchangeRate `json:"rates"`
+}
+
+// Receipt defines model for Receipt.
+type Receipt struct {
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
+
+	// FeeAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	FeeAmount MoneyAmount   `json:"fee_amount"`
+	IssuedAt  time.Time     `json:"issued_at"`
+	Items     []ReceiptItem `json:"items"`
+
+	// Number Unique and increasing, but not gapless.
+	Number         string    `json:"number"`
+	OrderCreatedAt time.Time `json:"order_created_at"`
+	OrderId        string    `json:"order_id"`
+	PaidAt         time.Time `json:"paid_at"`
+
+	// TotalAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	TotalAmount MoneyAmount `json:"total_amount"`
+}
+
+// ReceiptItem defines model for ReceiptItem.
+type ReceiptItem struct {
+	// Amount MoneyAmount that may be negative, e.g. on a discount line of a receipt.
+	Amount      SignedMoneyAmount `json:"amount"`
+	Description string            `json:"description"`
+
+	// Kind product — a catalog product of an order created from items (at the
+	// price when the order was created), order — an order created with an
+	// amount (before the discount), discount — the promo code discount
+	// (negative unit_price and amount), fee — the payment fee.
+	Kind ReceiptItemKind `json:"kind"`
+
+	// ProductId Set on product lines.
+	ProductId *string `json:"product_id,omitempty"`
+	Quantity  int64   `json:"quantity"`
+
+	// UnitPrice MoneyAmount that may be negative, e.g. on a discount line of a receipt.
+	UnitPrice SignedMoneyAmount `json:"unit_price"`
+}
+
+// ReceiptItemKind product — a catalog product of an order created from items (at the
+// price when the order was created), order — an order created with an
+// amount (before the discount), discount — the promo code discount
+// (negative unit_price and amount), fee — the payment fee.
+type ReceiptItemKind string
+
+// RetryPaymentResponse defines model for RetryPaymentResponse.
+type RetryPaymentResponse struct {
+	Order Order `json:"order"`
+
+	// RetriesLeft How many more times the payment of this order can be retried.
+	RetriesLeft int32 `json:"retries_left"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// SessionResponse defines model for SessionResponse.
+type SessionResponse struct {
+	ExpiresAt time.Time `json:"expires_at"`
+
+	// Token Bearer token for all other routes (`Authorization: Bearer <token>`).
+	Token string `json:"token"`
+
+	// UserId Stable user id of the session; it is kept when the session is renewed.
+	UserId string `json:"user_id"`
+}
+
+// SignedMoneyAmount MoneyAmount that may be negative, e.g. on a discount line of a receipt.
+type SignedMoneyAmount = money.Amount
+
 // TopUpAccountRequest defines model for TopUpAccountRequest.
 type TopUpAccountRequest struct {
-	// Amount Amount in minimal currency units (e.g. cents/kopecks).
-	Amount int64 `json:"amount"`
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency *Currency `json:"currency,omitempty"`
+
+	// ExpectedVersion Apply the top-up only if the account is still at this version (from GET /payments/account/balance); otherwise 409.
+	ExpectedVersion *int64 `json:"expected_version,omitempty"`
 }
 
 // TopUpAccountResponse defines model for TopUpAccountResponse.
 type TopUpAccountResponse struct {
-	Balance int64 `json:"balance"`
+	// AccountType Account tier; decides the payment limit, overdraft and fees.
+	AccountType *AccountType `json:"account_type,omitempty"`
+
+	// Balance Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Balance MoneyAmount `json:"balance"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+
+	// Version Incremented by every balance change.
+	Version *AccountVersion `json:"version,omitempty"`
+}
+
+// TransferOrderRequest defines model for TransferOrderRequest.
+type TransferOrderRequest struct {
+	// ToUserId User the order is offered to.
+	ToUserId string `json:"to_user_id"`
+}
+
+// TransferOrderResponse defines model for TransferOrderResponse.
+type TransferOrderResponse struct {
+	Transfer OrderTransfer `json:"transfer"`
+
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
+	UserId string `json:"user_id"`
+}
+
+// UpdateApiKeyQuotaRequest Fields left out keep their value.
+type UpdateApiKeyQuotaRequest struct {
+	MonthlyCallQuota   *int64 `json:"monthly_call_quota,omitempty"`
+	MonthlyVolumeQuota *int64 `json:"monthly_volume_quota,omitempty"`
+}
+
+// UpdateNotificationPreferencesRequest Replaces all preferences; fields left out are cleared.
+type UpdateNotificationPreferencesRequest struct {
+	Email          *string             `json:"email,omitempty"`
+	Kinds          *[]NotificationKind `json:"kinds,omitempty"`
+	TelegramChatId *string             `json:"telegram_chat_id,omitempty"`
+	WebhookUrl     *string             `json:"webhook_url,omitempty"`
+}
+
+// UpdateOrderRequest Only the fields present are changed. metadata and tags are replaced as a whole; send {} or [] to clear them.
+type UpdateOrderRequest struct {
+	Description *string `json:"description,omitempty"`
+
+	// Metadata Free-form integrator references (e.g. an invoice number). At most 20 keys of up to 40 bytes, values up to 500 bytes.
+	Metadata *OrderMetadata `json:"metadata,omitempty"`
+
+	// Tags Unique tags for filtering orders (GET /orders?tag=...). At most 10, each 1..64 bytes.
+	Tags *OrderTags `json:"tags,omitempty"`
+
+	// Version Expected order version. The update fails with 409 if the order changed since.
+	Version *int64 `json:"version,omitempty"`
+}
+
+// UpdateOrderResponse defines model for UpdateOrderResponse.
+type UpdateOrderResponse struct {
+	Order Order `json:"order"`
 
-	// UserId Resolved user id (provided or generated by gateway).
+	// UserId Caller's user id (from X-User-Id, the session or the JWT).
 	UserId string `json:"user_id"`
 }
 
+// UsageEntry defines model for UsageEntry.
+type UsageEntry struct {
+	Calls              int64  `json:"calls"`
+	KeyId              string `json:"key_id"`
+	KeyName            string `json:"key_name"`
+	MonthlyCallQuota   int64  `json:"monthly_call_quota"`
+	MonthlyVolumeQuota int64  `json:"monthly_volume_quota"`
+
+	// UpdatedAt When the counts were last flushed from Redis.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Volume Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Volume MoneyAmount `json:"volume"`
+}
+
+// UsageReport defines model for UsageReport.
+type UsageReport struct {
+	// Entries By volume, largest first.
+	Entries []UsageEntry `json:"entries"`
+	Month   string       `json:"month"`
+}
+
+// ValidateOrderResponse defines model for ValidateOrderResponse.
+type ValidateOrderResponse struct {
+	// Amount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	Amount MoneyAmount `json:"amount"`
+
+	// Currency ISO 4217 code. The deployment works in a single currency (RUB by default); in requests the field is optional and must match it when given.
+	Currency Currency `json:"currency"`
+
+	// DiscountAmount Amount in minimal currency units (e.g. cents/kopecks) as a decimal string, so that JavaScript clients do not lose precision. Requests also accept a plain integer.
+	DiscountAmount *MoneyAmount `json:"discount_amount,omitempty"`
+	UserId         string       `json:"user_id"`
+}
+
+// WaitOrderResponse defines model for WaitOrderResponse.
+type WaitOrderResponse struct {
+	Order Order `json:"order"`
+
+	// TimedOut The timeout expired while the order was still NEW.
+	TimedOut bool   `json:"timed_out"`
+	UserId   string `json:"user_id"`
+}
+
+// ApiKeyIdPath defines model for ApiKeyIdPath.
+type ApiKeyIdPath = string
+
+// AuditUserIdPath defines model for AuditUserIdPath.
+type AuditUserIdPath = string
+
 // IdempotencyKeyHeader defines model for IdempotencyKeyHeader.
 type IdempotencyKeyHeader = string
 
+// IncludeArchivedQuery defines model for IncludeArchivedQuery.
+type IncludeArchivedQuery = bool
+
 // LimitQuery defines model for LimitQuery.
 type LimitQuery = int32
 
+// OrderFieldsQuery defines model for OrderFieldsQuery.
+type OrderFieldsQuery = string
+
 // OrderIdPath defines model for OrderIdPath.
 type OrderIdPath = string
 
 // PageTokenQuery defines model for PageTokenQuery.
 type PageTokenQuery = string
 
+// PreferHeader defines model for PreferHeader.
+type PreferHeader = string
+
+// TagQuery defines model for TagQuery.
+type TagQuery = string
+
 // UserIdHeader defines model for UserIdHeader.
 type UserIdHeader = string
 
-// UserIdHeaderRequired defines model for UserIdHeaderRequired.
-type UserIdHeaderRequired = string
+// WaitTimeoutQuery defines model for WaitTimeoutQuery.
+type WaitTimeoutQuery = string
+
+// ListUserAuditParams defines parameters for ListUserAudit.
+type ListUserAuditParams struct {
+	Limit *int32 `form:"limit,omitempty" json:"limit,omitempty"`
+}
+
+// GetUsageParams defines parameters for GetUsage.
+type GetUsageParams struct {
+	// Month Calendar month in UTC; the current one by default.
+	Month *string `form:"month,omitempty" json:"month,omitempty"`
+
+	// KeyId Only this API key.
+	KeyId *string `form:"key_id,omitempty" json:"key_id,omitempty"`
+}
+
+// GetNotificationPreferencesParams defines parameters for GetNotificationPreferences.
+type GetNotificationPreferencesParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// UpdateNotificationPreferencesParams defines parameters for UpdateNotificationPreferences.
+type UpdateNotificationPreferencesParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
 
 // ListOrdersParams defines parameters for ListOrders.
 type ListOrdersParams struct {
@@ -143,60 +752,198 @@ type ListOrdersParams struct {
 	// PageToken Pagination token returned by previous request.
 	PageToken *PageTokenQuery `form:"page_token,omitempty" json:"page_token,omitempty"`
 
-	// XUserId Optional user identifier. If missing, gateway generates a new user_id.
+	// Tag Only orders carrying this tag.
+	Tag *TagQuery `form:"tag,omitempty" json:"tag,omitempty"`
+
+	// IncludeArchived Also list finished and cancelled orders moved to the archive.
+	IncludeArchived *IncludeArchivedQuery `form:"include_archived,omitempty" json:"include_archived,omitempty"`
+
+	// Fields Comma-separated Order properties to return, e.g. order_id,status. The other properties are left out, required ones included, which trims large orders with metadata and tags over slow connections. Unknown names are rejected with 400.
+	Fields *OrderFieldsQuery `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
 	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 }
 
 // CreateOrderParams defines parameters for CreateOrder.
 type CreateOrderParams struct {
-	// XUserId Optional user identifier. If missing, gateway generates a new user_id.
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
 	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 
-	// IdempotencyKey Optional idempotency key for safe retries of POST requests.
-	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+	// IdempotencyKey Required idempotency key for safe retries of POST requests.
+	IdempotencyKey IdempotencyKeyHeader `json:"Idempotency-Key"`
+
+	// Prefer RFC 7240 preferences. `respond-async` makes POST /orders answer 202 Accepted with a Location header pointing at GET /orders/{orderId} instead of 201.
+	Prefer *PreferHeader `json:"Prefer,omitempty"`
 }
 
 // GetOrderParams defines parameters for GetOrder.
 type GetOrderParams struct {
-	// XUserId Optional user identifier. If missing, gateway generates a new user_id.
+	// Fields Comma-separated Order properties to return, e.g. order_id,status. The other properties are left out, required ones included, which trims large orders with metadata and tags over slow connections. Unknown names are rejected with 400.
+	Fields *OrderFieldsQuery `form:"fields,omitempty" json:"fields,omitempty"`
+
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// UpdateOrderParams defines parameters for UpdateOrder.
+type UpdateOrderParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// CancelOrderParams defines parameters for CancelOrder.
+type CancelOrderParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// ConfirmPaymentParams defines parameters for ConfirmPayment.
+type ConfirmPaymentParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// PayOrderParams defines parameters for PayOrder.
+type PayOrderParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+
+	// IdempotencyKey Required idempotency key for safe retries of POST requests.
+	IdempotencyKey IdempotencyKeyHeader `json:"Idempotency-Key"`
+}
+
+// GetOrderReceiptParams defines parameters for GetOrderReceipt.
+type GetOrderReceiptParams struct {
+	// Format Document format; JSON by default.
+	Format *GetOrderReceiptParamsFormat `form:"format,omitempty" json:"format,omitempty"`
+
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// GetOrderReceiptParamsFormat defines parameters for GetOrderReceipt.
+type GetOrderReceiptParamsFormat string
+
+// RetryPaymentParams defines parameters for RetryPayment.
+type RetryPaymentParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// TransferOrderParams defines parameters for TransferOrder.
+type TransferOrderParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// AcceptOrderTransferParams defines parameters for AcceptOrderTransfer.
+type AcceptOrderTransferParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// WaitOrderParams defines parameters for WaitOrder.
+type WaitOrderParams struct {
+	// Timeout How long to hold the request, as a Go duration (`30s`, `1m`); at most 60s.
+	Timeout *WaitTimeoutQuery `form:"timeout,omitempty" json:"timeout,omitempty"`
+
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
+}
+
+// ValidateOrderParams defines parameters for ValidateOrder.
+type ValidateOrderParams struct {
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
 	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 }
 
 // CreateAccountParams defines parameters for CreateAccount.
 type CreateAccountParams struct {
-	// XUserId Optional user identifier. If missing, gateway generates a new user_id.
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
 	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 
-	// IdempotencyKey Optional idempotency key for safe retries of POST requests.
-	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+	// IdempotencyKey Required idempotency key for safe retries of POST requests.
+	IdempotencyKey IdempotencyKeyHeader `json:"Idempotency-Key"`
 }
 
 // GetBalanceParams defines parameters for GetBalance.
 type GetBalanceParams struct {
-	// XUserId Required user identifier for this endpoint.
-	XUserId UserIdHeaderRequired `json:"X-User-Id"`
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
+	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 }
 
 // TopUpAccountParams defines parameters for TopUpAccount.
 type TopUpAccountParams struct {
-	// XUserId Optional user identifier. If missing, gateway generates a new user_id.
+	// XUserId User the call acts for. Filled by the gateway from the session or JWT subject; support, admin and API key callers set it explicitly. On its own it identifies no one: the call also needs a bearer token or an API key.
 	XUserId *UserIdHeader `json:"X-User-Id,omitempty"`
 
-	// IdempotencyKey Optional idempotency key for safe retries of POST requests.
-	IdempotencyKey *IdempotencyKeyHeader `json:"Idempotency-Key,omitempty"`
+	// IdempotencyKey Required idempotency key for safe retries of POST requests.
+	IdempotencyKey IdempotencyKeyHeader `json:"Idempotency-Key"`
+}
+
+// GetBalanceAtParams defines parameters for GetBalanceAt.
+type GetBalanceAtParams struct {
+	// At RFC 3339 timestamp, not in the future.
+	At time.Time `form:"at" json:"at"`
 }
 
+// CreateApiKeyJSONRequestBody defines body for CreateApiKey for application/json ContentType.
+type CreateApiKeyJSONRequestBody = CreateApiKeyRequest
+
+// UpdateApiKeyQuotaJSONRequestBody defines body for UpdateApiKeyQuota for application/json ContentType.
+type UpdateApiKeyQuotaJSONRequestBody = UpdateApiKeyQuotaRequest
+
+// UpdateNotificationPreferencesJSONRequestBody defines body for UpdateNotificationPreferences for application/json ContentType.
+type UpdateNotificationPreferencesJSONRequestBody = UpdateNotificationPreferencesRequest
+
 // CreateOrderJSONRequestBody defines body for CreateOrder for application/json ContentType.
 type CreateOrderJSONRequestBody = CreateOrderRequest
 
-// CreateAccountJSONRequestBody defines body for CreateAccount for application/json ContentType.
-type CreateAccountJSONRequestBody = CreateAccountRequest
+// UpdateOrderJSONRequestBody defines body for UpdateOrder for application/json ContentType.
+type UpdateOrderJSONRequestBody = UpdateOrderRequest
 
-// TopUpAccountJSONRequestBody defines body for TopUpAccount for application/json ContentType.
-type TopUpAccountJSONRequestBody = TopUpAccountRequest
+// ConfirmPaymentJSONRequestBody defines body for ConfirmPayment for application/json ContentType.
+type ConfirmPaymentJSONRequestBody = ConfirmPaymentRequest
+
+// PayOrderJSONRequestBody defines body for PayOrder for application/json ContentType.
+type PayOrderJSONRequestBody = PayOrderRequest
+
+// TransferOrderJSONRequestBody defines body for TransferOrder for application/json ContentType.
+type TransferOrderJSONRequestBody = TransferOrderRequest
+
+// ValidateOrderJSONRequestBody defines body for ValidateOrder for application/json ContentType.
+type ValidateOrderJSONRequestBody = CreateOrderRequest
+
+// CreateAccountJSONRequestBody defines body for CreateAccount for application/json ContentType.
+type CreateAccountJSONRequestBody = CreateAccountRequest
+
+// TopUpAccountJSONRequestBody defines body for TopUpAccount for application/json ContentType.
+type TopUpAccountJSONRequestBody = TopUpAccountRequest
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
+	// Create an API key
+	// (POST /admin/api-keys)
+	CreateApiKey(w http.ResponseWriter, r *http.Request)
+	// Revoke an API key
+	// (DELETE /admin/api-keys/{keyId})
+	RevokeApiKey(w http.ResponseWriter, r *http.Request, keyId ApiKeyIdPath)
+	// Change the monthly quotas of an API key
+	// (PATCH /admin/api-keys/{keyId})
+	UpdateApiKeyQuota(w http.ResponseWriter, r *http.Request, keyId ApiKeyIdPath)
+	// Recent API calls of a user, newest first
+	// (GET /admin/audit/users/{userId})
+	ListUserAudit(w http.ResponseWriter, r *http.Request, userId AuditUserIdPath, params ListUserAuditParams)
+	// API calls and transaction volume per API key in a month
+	// (GET /admin/usage)
+	GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams)
+	// Get notification preferences
+	// (GET /notifications/preferences)
+	GetNotificationPreferences(w http.ResponseWriter, r *http.Request, params GetNotificationPreferencesParams)
+	// Replace notification preferences
+	// (PUT /notifications/preferences)
+	UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request, params UpdateNotificationPreferencesParams)
 	// List orders
 	// (GET /orders)
 	ListOrders(w http.ResponseWriter, r *http.Request, params ListOrdersParams)
@@ -206,6 +953,36 @@ type ServerInterface interface {
 	// Get order status/details
 	// (GET /orders/{orderId})
 	GetOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params GetOrderParams)
+	// Update order description, metadata or tags
+	// (PATCH /orders/{orderId})
+	UpdateOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params UpdateOrderParams)
+	// Cancel a scheduled order
+	// (POST /orders/{orderId}/cancel)
+	CancelOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params CancelOrderParams)
+	// Confirm a payment
+	// (POST /orders/{orderId}/confirm-payment)
+	ConfirmPayment(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params ConfirmPaymentParams)
+	// Pay part of an order (async payment starts)
+	// (POST /orders/{orderId}/payments)
+	PayOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params PayOrderParams)
+	// Get the receipt of a finished order
+	// (GET /orders/{orderId}/receipt)
+	GetOrderReceipt(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params GetOrderReceiptParams)
+	// Retry the payment of an order
+	// (POST /orders/{orderId}/retry-payment)
+	RetryPayment(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params RetryPaymentParams)
+	// Offer an order to another user
+	// (POST /orders/{orderId}/transfer)
+	TransferOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params TransferOrderParams)
+	// Accept an order offered to the caller
+	// (POST /orders/{orderId}/transfer/accept)
+	AcceptOrderTransfer(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params AcceptOrderTransferParams)
+	// Wait until the order leaves NEW
+	// (GET /orders/{orderId}/wait)
+	WaitOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params WaitOrderParams)
+	// Validate order without creating it
+	// (POST /orders:validate)
+	ValidateOrder(w http.ResponseWriter, r *http.Request, params ValidateOrderParams)
 	// Create account (max 1 per user)
 	// (POST /payments/account)
 	CreateAccount(w http.ResponseWriter, r *http.Request, params CreateAccountParams)
@@ -215,12 +992,63 @@ type ServerInterface interface {
 	// Top up account
 	// (POST /payments/account/topup)
 	TopUpAccount(w http.ResponseWriter, r *http.Request, params TopUpAccountParams)
+	// Get exchange rates
+	// (GET /rates)
+	GetRates(w http.ResponseWriter, r *http.Request)
+	// Start or renew an anonymous session
+	// (POST /session)
+	CreateSession(w http.ResponseWriter, r *http.Request)
+	// Balance of a user as of a past moment
+	// (GET /support/users/{userId}/balance)
+	GetBalanceAt(w http.ResponseWriter, r *http.Request, userId AuditUserIdPath, params GetBalanceAtParams)
 }
 
 // Unimplemented server implementation that returns http.StatusNotImplemented for each endpoint.
 
 type Unimplemented struct{}
 
+// Create an API key
+// (POST /admin/api-keys)
+func (_ Unimplemented) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Revoke an API key
+// (DELETE /admin/api-keys/{keyId})
+func (_ Unimplemented) RevokeApiKey(w http.ResponseWriter, r *http.Request, keyId ApiKeyIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Change the monthly quotas of an API key
+// (PATCH /admin/api-keys/{keyId})
+func (_ Unimplemented) UpdateApiKeyQuota(w http.ResponseWriter, r *http.Request, keyId ApiKeyIdPath) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Recent API calls of a user, newest first
+// (GET /admin/audit/users/{userId})
+func (_ Unimplemented) ListUserAudit(w http.ResponseWriter, r *http.Request, userId AuditUserIdPath, params ListUserAuditParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// API calls and transaction volume per API key in a month
+// (GET /admin/usage)
+func (_ Unimplemented) GetUsage(w http.ResponseWriter, r *http.Request, params GetUsageParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get notification preferences
+// (GET /notifications/preferences)
+func (_ Unimplemented) GetNotificationPreferences(w http.ResponseWriter, r *http.Request, params GetNotificationPreferencesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Replace notification preferences
+// (PUT /notifications/preferences)
+func (_ Unimplemented) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request, params UpdateNotificationPreferencesParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
 // List orders
 // (GET /orders)
 func (_ Unimplemented) ListOrders(w http.ResponseWriter, r *http.Request, params ListOrdersParams) {
@@ -239,56 +1067,883 @@ func (_ Unimplemented) GetOrder(w http.ResponseWriter, r *http.Request, orderId
 	w.WriteHeader(http.StatusNotImplemented)
 }
 
-// Create account (max 1 per user)
-// (POST /payments/account)
-func (_ Unimplemented) CreateAccount(w http.ResponseWriter, r *http.Request, params CreateAccountParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+// Update order description, metadata or tags
+// (PATCH /orders/{orderId})
+func (_ Unimplemented) UpdateOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params UpdateOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Cancel a scheduled order
+// (POST /orders/{orderId}/cancel)
+func (_ Unimplemented) CancelOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params CancelOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Confirm a payment
+// (POST /orders/{orderId}/confirm-payment)
+func (_ Unimplemented) ConfirmPayment(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params ConfirmPaymentParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Pay part of an order (async payment starts)
+// (POST /orders/{orderId}/payments)
+func (_ Unimplemented) PayOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params PayOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get the receipt of a finished order
+// (GET /orders/{orderId}/receipt)
+func (_ Unimplemented) GetOrderReceipt(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params GetOrderReceiptParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Retry the payment of an order
+// (POST /orders/{orderId}/retry-payment)
+func (_ Unimplemented) RetryPayment(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params RetryPaymentParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Offer an order to another user
+// (POST /orders/{orderId}/transfer)
+func (_ Unimplemented) TransferOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params TransferOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Accept an order offered to the caller
+// (POST /orders/{orderId}/transfer/accept)
+func (_ Unimplemented) AcceptOrderTransfer(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params AcceptOrderTransferParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Wait until the order leaves NEW
+// (GET /orders/{orderId}/wait)
+func (_ Unimplemented) WaitOrder(w http.ResponseWriter, r *http.Request, orderId OrderIdPath, params WaitOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Validate order without creating it
+// (POST /orders:validate)
+func (_ Unimplemented) ValidateOrder(w http.ResponseWriter, r *http.Request, params ValidateOrderParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Create account (max 1 per user)
+// (POST /payments/account)
+func (_ Unimplemented) CreateAccount(w http.ResponseWriter, r *http.Request, params CreateAccountParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get account balance
+// (GET /payments/account/balance)
+func (_ Unimplemented) GetBalance(w http.ResponseWriter, r *http.Request, params GetBalanceParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Top up account
+// (POST /payments/account/topup)
+func (_ Unimplemented) TopUpAccount(w http.ResponseWriter, r *http.Request, params TopUpAccountParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Get exchange rates
+// (GET /rates)
+func (_ Unimplemented) GetRates(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Start or renew an anonymous session
+// (POST /session)
+func (_ Unimplemented) CreateSession(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// Balance of a user as of a past moment
+// (GET /support/users/{userId}/balance)
+func (_ Unimplemented) GetBalanceAt(w http.ResponseWriter, r *http.Request, userId AuditUserIdPath, params GetBalanceAtParams) {
+	w.WriteHeader(http.StatusNotImplemented)
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler            ServerInterface
+	HandlerMiddlewares []MiddlewareFunc
+	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+type MiddlewareFunc func(http.Handler) http.Handler
+
+// CreateApiKey operation middleware
+func (siw *ServerInterfaceWrapper) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateApiKey(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RevokeApiKey operation middleware
+func (siw *ServerInterfaceWrapper) RevokeApiKey(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "keyId" -------------
+	var keyId ApiKeyIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "keyId", chi.URLParam(r, "keyId"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "keyId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RevokeApiKey(w, r, keyId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateApiKeyQuota operation middleware
+func (siw *ServerInterfaceWrapper) UpdateApiKeyQuota(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "keyId" -------------
+	var keyId ApiKeyIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "keyId", chi.URLParam(r, "keyId"), &keyId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "keyId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateApiKeyQuota(w, r, keyId)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListUserAudit operation middleware
+func (siw *ServerInterfaceWrapper) ListUserAudit(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "userId" -------------
+	var userId AuditUserIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", chi.URLParam(r, "userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListUserAuditParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListUserAudit(w, r, userId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetUsage(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetUsageParams
+
+	// ------------- Optional query parameter "month" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "month", r.URL.Query(), &params.Month)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "month", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "key_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "key_id", r.URL.Query(), &params.KeyId)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "key_id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetUsage(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserIdHeaderAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetNotificationPreferencesParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetNotificationPreferences(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateNotificationPreferences operation middleware
+func (siw *ServerInterfaceWrapper) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, UserIdHeaderAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateNotificationPreferencesParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateNotificationPreferences(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListOrders operation middleware
+func (siw *ServerInterfaceWrapper) ListOrders(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ListOrdersParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "page_token" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "tag", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "include_archived" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "include_archived", r.URL.Query(), &params.IncludeArchived)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "include_archived", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListOrders(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateOrder operation middleware
+func (siw *ServerInterfaceWrapper) CreateOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CreateOrderParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	// ------------- Required header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKeyHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = IdempotencyKey
+
+	} else {
+		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		return
+	}
+
+	// ------------- Optional header parameter "Prefer" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Prefer")]; found {
+		var Prefer PreferHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Prefer", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Prefer", valueList[0], &Prefer, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Prefer", Err: err})
+			return
+		}
+
+		params.Prefer = &Prefer
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateOrder(w, r, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOrder operation middleware
+func (siw *ServerInterfaceWrapper) GetOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetOrderParams
+
+	// ------------- Optional query parameter "fields" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "fields", r.URL.Query(), &params.Fields)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "fields", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// UpdateOrder operation middleware
+func (siw *ServerInterfaceWrapper) UpdateOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params UpdateOrderParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.UpdateOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CancelOrder operation middleware
+func (siw *ServerInterfaceWrapper) CancelOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params CancelOrderParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CancelOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ConfirmPayment operation middleware
+func (siw *ServerInterfaceWrapper) ConfirmPayment(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ConfirmPaymentParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ConfirmPayment(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// PayOrder operation middleware
+func (siw *ServerInterfaceWrapper) PayOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PayOrderParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	// ------------- Required header parameter "Idempotency-Key" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
+		var IdempotencyKey IdempotencyKeyHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			return
+		}
+
+		params.IdempotencyKey = IdempotencyKey
+
+	} else {
+		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PayOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetOrderReceipt operation middleware
+func (siw *ServerInterfaceWrapper) GetOrderReceipt(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetOrderReceiptParams
+
+	// ------------- Optional query parameter "format" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "format", r.URL.Query(), &params.Format)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "format", Err: err})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
 
-// Get account balance
-// (GET /payments/account/balance)
-func (_ Unimplemented) GetBalance(w http.ResponseWriter, r *http.Request, params GetBalanceParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	}
 
-// Top up account
-// (POST /payments/account/topup)
-func (_ Unimplemented) TopUpAccount(w http.ResponseWriter, r *http.Request, params TopUpAccountParams) {
-	w.WriteHeader(http.StatusNotImplemented)
-}
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetOrderReceipt(w, r, orderId, params)
+	}))
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler            ServerInterface
-	HandlerMiddlewares []MiddlewareFunc
-	ErrorHandlerFunc   func(w http.ResponseWriter, r *http.Request, err error)
-}
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
 
-type MiddlewareFunc func(http.Handler) http.Handler
+	handler.ServeHTTP(w, r)
+}
 
-// ListOrders operation middleware
-func (siw *ServerInterfaceWrapper) ListOrders(w http.ResponseWriter, r *http.Request) {
+// RetryPayment operation middleware
+func (siw *ServerInterfaceWrapper) RetryPayment(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params ListOrdersParams
-
-	// ------------- Optional query parameter "limit" -------------
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
 
-	err = runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit)
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "limit", Err: err})
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
 		return
 	}
 
-	// ------------- Optional query parameter "page_token" -------------
-
-	err = runtime.BindQueryParameter("form", true, false, "page_token", r.URL.Query(), &params.PageToken)
-	if err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "page_token", Err: err})
-		return
-	}
+	// Parameter object where we will unmarshal all parameters from the context
+	var params RetryPaymentParams
 
 	headers := r.Header
 
@@ -312,7 +1967,7 @@ func (siw *ServerInterfaceWrapper) ListOrders(w http.ResponseWriter, r *http.Req
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.ListOrders(w, r, params)
+		siw.Handler.RetryPayment(w, r, orderId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -322,13 +1977,22 @@ func (siw *ServerInterfaceWrapper) ListOrders(w http.ResponseWriter, r *http.Req
 	handler.ServeHTTP(w, r)
 }
 
-// CreateOrder operation middleware
-func (siw *ServerInterfaceWrapper) CreateOrder(w http.ResponseWriter, r *http.Request) {
+// TransferOrder operation middleware
+func (siw *ServerInterfaceWrapper) TransferOrder(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
 	// Parameter object where we will unmarshal all parameters from the context
-	var params CreateOrderParams
+	var params TransferOrderParams
 
 	headers := r.Header
 
@@ -351,27 +2015,57 @@ func (siw *ServerInterfaceWrapper) CreateOrder(w http.ResponseWriter, r *http.Re
 
 	}
 
-	// ------------- Optional header parameter "Idempotency-Key" -------------
-	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
-		var IdempotencyKey IdempotencyKeyHeader
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.TransferOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// AcceptOrderTransfer operation middleware
+func (siw *ServerInterfaceWrapper) AcceptOrderTransfer(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "orderId" -------------
+	var orderId OrderIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "orderId", chi.URLParam(r, "orderId"), &orderId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "orderId", Err: err})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params AcceptOrderTransferParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
 		n := len(valueList)
 		if n != 1 {
-			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "Idempotency-Key", Count: n})
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
 		if err != nil {
-			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
 			return
 		}
 
-		params.IdempotencyKey = &IdempotencyKey
+		params.XUserId = &XUserId
 
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.CreateOrder(w, r, params)
+		siw.Handler.AcceptOrderTransfer(w, r, orderId, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -381,8 +2075,8 @@ func (siw *ServerInterfaceWrapper) CreateOrder(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
-// GetOrder operation middleware
-func (siw *ServerInterfaceWrapper) GetOrder(w http.ResponseWriter, r *http.Request) {
+// WaitOrder operation middleware
+func (siw *ServerInterfaceWrapper) WaitOrder(w http.ResponseWriter, r *http.Request) {
 
 	var err error
 
@@ -396,7 +2090,15 @@ func (siw *ServerInterfaceWrapper) GetOrder(w http.ResponseWriter, r *http.Reque
 	}
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetOrderParams
+	var params WaitOrderParams
+
+	// ------------- Optional query parameter "timeout" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "timeout", r.URL.Query(), &params.Timeout)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "timeout", Err: err})
+		return
+	}
 
 	headers := r.Header
 
@@ -420,7 +2122,47 @@ func (siw *ServerInterfaceWrapper) GetOrder(w http.ResponseWriter, r *http.Reque
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		siw.Handler.GetOrder(w, r, orderId, params)
+		siw.Handler.WaitOrder(w, r, orderId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ValidateOrder operation middleware
+func (siw *ServerInterfaceWrapper) ValidateOrder(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params ValidateOrderParams
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "X-User-Id" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
+		var XUserId UserIdHeader
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
+			return
+		}
+
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		if err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
+			return
+		}
+
+		params.XUserId = &XUserId
+
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ValidateOrder(w, r, params)
 	}))
 
 	for _, middleware := range siw.HandlerMiddlewares {
@@ -459,7 +2201,7 @@ func (siw *ServerInterfaceWrapper) CreateAccount(w http.ResponseWriter, r *http.
 
 	}
 
-	// ------------- Optional header parameter "Idempotency-Key" -------------
+	// ------------- Required header parameter "Idempotency-Key" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
 		var IdempotencyKey IdempotencyKeyHeader
 		n := len(valueList)
@@ -468,14 +2210,18 @@ func (siw *ServerInterfaceWrapper) CreateAccount(w http.ResponseWriter, r *http.
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
 		if err != nil {
 			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
 			return
 		}
 
-		params.IdempotencyKey = &IdempotencyKey
+		params.IdempotencyKey = IdempotencyKey
 
+	} else {
+		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -496,6 +2242,8 @@ func (siw *ServerInterfaceWrapper) GetBalance(w http.ResponseWriter, r *http.Req
 
 	ctx := r.Context()
 
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
 	ctx = context.WithValue(ctx, UserIdHeaderAuthScopes, []string{})
 
 	r = r.WithContext(ctx)
@@ -505,27 +2253,23 @@ func (siw *ServerInterfaceWrapper) GetBalance(w http.ResponseWriter, r *http.Req
 
 	headers := r.Header
 
-	// ------------- Required header parameter "X-User-Id" -------------
+	// ------------- Optional header parameter "X-User-Id" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("X-User-Id")]; found {
-		var XUserId UserIdHeaderRequired
+		var XUserId UserIdHeader
 		n := len(valueList)
 		if n != 1 {
 			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{ParamName: "X-User-Id", Count: n})
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
+		err = runtime.BindStyledParameterWithOptions("simple", "X-User-Id", valueList[0], &XUserId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
 		if err != nil {
 			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "X-User-Id", Err: err})
 			return
 		}
 
-		params.XUserId = XUserId
+		params.XUserId = &XUserId
 
-	} else {
-		err := fmt.Errorf("Header parameter X-User-Id is required, but not found")
-		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "X-User-Id", Err: err})
-		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -568,7 +2312,7 @@ func (siw *ServerInterfaceWrapper) TopUpAccount(w http.ResponseWriter, r *http.R
 
 	}
 
-	// ------------- Optional header parameter "Idempotency-Key" -------------
+	// ------------- Required header parameter "Idempotency-Key" -------------
 	if valueList, found := headers[http.CanonicalHeaderKey("Idempotency-Key")]; found {
 		var IdempotencyKey IdempotencyKeyHeader
 		n := len(valueList)
@@ -577,14 +2321,18 @@ func (siw *ServerInterfaceWrapper) TopUpAccount(w http.ResponseWriter, r *http.R
 			return
 		}
 
-		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: false})
+		err = runtime.BindStyledParameterWithOptions("simple", "Idempotency-Key", valueList[0], &IdempotencyKey, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationHeader, Explode: false, Required: true})
 		if err != nil {
 			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "Idempotency-Key", Err: err})
 			return
 		}
 
-		params.IdempotencyKey = &IdempotencyKey
+		params.IdempotencyKey = IdempotencyKey
 
+	} else {
+		err := fmt.Errorf("Header parameter Idempotency-Key is required, but not found")
+		siw.ErrorHandlerFunc(w, r, &RequiredHeaderError{ParamName: "Idempotency-Key", Err: err})
+		return
 	}
 
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -598,6 +2346,85 @@ func (siw *ServerInterfaceWrapper) TopUpAccount(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r)
 }
 
+// GetRates operation middleware
+func (siw *ServerInterfaceWrapper) GetRates(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetRates(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateSession operation middleware
+func (siw *ServerInterfaceWrapper) CreateSession(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSession(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetBalanceAt operation middleware
+func (siw *ServerInterfaceWrapper) GetBalanceAt(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+
+	// ------------- Path parameter "userId" -------------
+	var userId AuditUserIdPath
+
+	err = runtime.BindStyledParameterWithOptions("simple", "userId", chi.URLParam(r, "userId"), &userId, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "userId", Err: err})
+		return
+	}
+
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{})
+
+	ctx = context.WithValue(ctx, AdminTokenAuthScopes, []string{})
+
+	r = r.WithContext(ctx)
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBalanceAtParams
+
+	// ------------- Required query parameter "at" -------------
+
+	if paramValue := r.URL.Query().Get("at"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "at"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "at", r.URL.Query(), &params.At)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "at", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetBalanceAt(w, r, userId, params)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -711,6 +2538,27 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 		ErrorHandlerFunc:   options.ErrorHandlerFunc,
 	}
 
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/admin/api-keys", wrapper.CreateApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Delete(options.BaseURL+"/admin/api-keys/{keyId}", wrapper.RevokeApiKey)
+	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/admin/api-keys/{keyId}", wrapper.UpdateApiKeyQuota)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/audit/users/{userId}", wrapper.ListUserAudit)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/admin/usage", wrapper.GetUsage)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/notifications/preferences", wrapper.GetNotificationPreferences)
+	})
+	r.Group(func(r chi.Router) {
+		r.Put(options.BaseURL+"/notifications/preferences", wrapper.UpdateNotificationPreferences)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/orders", wrapper.ListOrders)
 	})
@@ -720,6 +2568,36 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/orders/{orderId}", wrapper.GetOrder)
 	})
+	r.Group(func(r chi.Router) {
+		r.Patch(options.BaseURL+"/orders/{orderId}", wrapper.UpdateOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/cancel", wrapper.CancelOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/confirm-payment", wrapper.ConfirmPayment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/payments", wrapper.PayOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/orders/{orderId}/receipt", wrapper.GetOrderReceipt)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/retry-payment", wrapper.RetryPayment)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/transfer", wrapper.TransferOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders/{orderId}/transfer/accept", wrapper.AcceptOrderTransfer)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/orders/{orderId}/wait", wrapper.WaitOrder)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/orders:validate", wrapper.ValidateOrder)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/payments/account", wrapper.CreateAccount)
 	})
@@ -729,6 +2607,15 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/payments/account/topup", wrapper.TopUpAccount)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/rates", wrapper.GetRates)
+	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/session", wrapper.CreateSession)
+	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/support/users/{userId}/balance", wrapper.GetBalanceAt)
+	})
 
 	return r
 }
@@ -736,37 +2623,169 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RaW2/bRhb+K4PZfXAAWpST7KLLNydRHaGOrdoyuoBrBCPySJqanJnODBVzDf73xVxI",
-	"kRRlya0vRfpkUZzLuXznOxfrHsc8E5wB0wpH91gQSTLQIO3TOIFMcA0sLn6C4jOQBKT5PgEVSyo05QxH",
-	"+Nx+ICmi6+XoFgo05xIpMgckQUsKCvE5mpxfTpGE33NQWg1wgKk5YumODjAjGeCoefHhT1DgAKt4CRkx",
-	"l2eUnQJb6CWOjgKsC2E2KC0pW+CyDPApzaj+OQdZbIr6hdwhlmczkEYWLhOQCmluBMwlq8X53e6upUnN",
-	"iS0ZEpiTPNU4+tcwwHMuM6JxhCnT797iAGfkjmZ5hqO3w2Fg5HVPa2kp07AAacU9N0KMkwkxCt27+4V5",
-	"qK/nbgUOsLEblZDgSMscHmOUCVnAlN8C22KYCVlQRswD0maZtwgkaFYgIWFFea4qv22zkyAL+Gq3P8ph",
-	"V8qotxNduQJpIMY0nVOQAzSeo4wqRdkiQAui4Rsp0AIYSKJBIYIYfLObvtJkK9D+e2huP7TW/WMSX9Q+",
-	"6UpevelKbuNCL6lCwBLBKdN7ifdHnV9WS21If5RANBzHMc+ZvnD+NN+TJKHO0BPJBUhNQeFoTlIFQUev",
-	"USZ0UWEBzXhSDCo7I6qQJgY/c8kzVIuPnGIB4rJ2kYVW5Tda+3Lwq0GPV4LPfoNY4zLoyq0EZwosYzXE",
-	"vcczkhIW2xfNsPz3e9wIxOFmIAbYa9DnRsXT1dqN6EBIvqIJJNu0eTPAfahZO/C6vi2oJb7ZqrRliH1d",
-	"1TYHyYy5HrbGUZ81WibYHRBN1fyd7TN2arfNoZb8zId/SpjjCP8jXCes0OM6tGe8phOdlH1KjqTkD6iX",
-	"gCY0Vdvd6mJ941gwx5pdHQmf1gSBiUuyIjQlsxR2G8RJ1WeGE9AfHNBfNHavvKqWjSrGclz0VDF6Avo7",
-	"h/ApVU5FtUNH+4lqyNTe2vrLiJSkeHXtVa/655X7tlFrW9Bj+z2iDFnMkhTFuZS2Js4Z1QodwGAxQLG1",
-	"xy0XEN8qK+ojGTq27Jl8JW16T4iGQ00z2FR+g9U33lsrePNvvFSa6Hw/z166pW1/PuyK+uqg4ZXeVFJL",
-	"stVZl7WkwIz9rvHZ6Bcc4B/HZ+PLz6NPOMAfj88+jk5PR58ap6xVnXJxJR5ZI/2V0NGfkfvs1db071BV",
-	"GSRDnEuqi0uDWadhs6I/zl0r1ptK6iK+FndFyUahu1dBX3GfoKbFtYU6ZXPeA5rJGJ34Qlny3HQ3n6fT",
-	"Sd1Gmw7WETQiLEETUmQGP1ayxcXk4+BX5jRryEkV4lVnZfqRjCtd9yMqahTkfc1VVe6b61ynqBC1sJYe",
-	"RL4v+JFLdDKaolB4oULiwBZ6D3mDtSW7GP18Nb4YfXK9QEpj8LD0VvwynhqekKmxsNZCRWHIBTDFcxnD",
-	"gMtF6DeFGdWhTTRUp2brCf8fZ6hhURzgFUjlLH00GA6GlgkFMCIojvC7wXDwDge2J7dQCde5bgE2xE2o",
-	"2N55nOCokS7trvU85bqfOddLwlYnXAY71zdGHXus7swAyhsTPM5bVpu3w6H5E3OmwXEXESKlsVUt/E25",
-	"pLFuOx9KAz01gwV4p7d3oE2p0vXAwVj//RNK0i7Ae4T4QJK6NDygbEVSmiA72UDWeOqNI408y4gsvIP9",
-	"7MhEMVkY33pd8E0ZYMFVD/W7dqcaTNj96BvVS+TSGTob/WLjiaiCxUvJGc9VWpi3Uivk48fQTgxKDVzA",
-	"V3tTokGiGcQ8A4WqPGeYtE50LpbaUG10YM+O1d5ZosOgtf0HnhRP5vSexrlsJwvTV5UbAXD0PBLsiADk",
-	"azl0UHnZOh26WHjzmrHRCQKnn4fxgZUTtaRXb/qCowwq/gzv/Vyz3MqkVWv17NhszmCflRY3msWtkPgr",
-	"0KG7+/3L3e1UZ1yjOc9Z0oHcCXja9awXVsOTLTjrlhy2rt2Dmv1yP6YFW+zYaXP1gqQSSFIguKNKq6Cu",
-	"gGLO5imN7Ti3j2h9nf09Um2nWSo92z4ruXbblh5A+SUVwb5+MP3n5e4+7kVrP41XyD7IyB06QgKkRX2T",
-	"wquuYktwhY0e0ZN5W5xui2Tq/Cod9/9HZCMZ+Anin4qf+t81z83z3XFnLzpcC/RqXP/FdXimTqwK366b",
-	"XjwHVLDtZAHfslt3bzbr1zfGne1UUWF6VqNmXyhrLnLRzBZtJDZnJt8Xl/fNvfaqm4fPJMJumGguBCQo",
-	"F3+rQmlLkNT4n3KBclGFwBbo27CSqwq47RtOeUzSMIEVcmta85YoDO+XXOkyuhdc6jIkgoarIxzgFZGU",
-	"zFKHi2VdbPlfS+DUnGq/tm2y7Lz+YfjD0Kh7Uwu8+WsAH4nK5gzSzO+Us8Dg4dBozpIq8Afr6VutvQm8",
-	"Bw92B1pqNBVlYGcU5tkcPAcdL+uXvhptXOOr0PKm/H8AAAD//9xBhX5fIwAA",
+	"H4sIAAAAAAACA+09aW/bSJZ/hdAOMDZWkmXnmI2NwULtKIm7HcdjyZ1pdGeVsliSOKZINQ87msDA/oj9",
+	"hftL9h1VZJEs6nB8baY/JRbJOl69u97xtTEKZ/MwkEESN/a/NuYiEjOZyIj+6s69n+TiyD0VyRT/9oLG",
+	"PrwCfzQbAbwHf13ic/gzkr+nXiTdxn4SpbLZiEdTORP40cwLjmUwwRF2m41kMcfP4iTygknj5qbZ6Kau",
+	"l5zHMlo6T0ovfNNER66ErSYyGC1gV++kcGWE37kyHkXePPFCnPZMje94+esO7NIZh5ETi7F0IgljytgJ",
+	"x87ph/7AwRXJOInbsDpa+ZSHztZuTNyCmb9tE8HIT13ZjUZT70q6f0tltKhuouvHoeN7ceKMvcCLp7Ad",
+	"EbjOSAQj6fvwVxjBCmNnFsIYThI6yVQ6gsfMtvE7jZ3twuOZh+o1PIt82a4ci9RPGvtj4ccyW/hFGPpS",
+	"BLTyY2/mJTXrfS++OEE6u5ARQlUtDpYFoE6joG5FPo5oX8aLTrMBBzYTCa08ebYH783EF2+Wzhr7ex14",
+	"DJDmv3I4w4tyAieHy/2Ai3jjSd+NaxZ9GM5mohVLpJkEwEhfOPMonMsoQQTJNtB0ZHvS5n0NPbcZJyJJ",
+	"47YzAKiHAPrCVyKSji/HiROmSdPRmOIAjcaOOgO36VxPvdHUAbyYxY4voonUULv2kqkDJCxckQg69URM",
+	"AFmvYJbYD6+dURgEcoR7gBWcB5dBeB04CFGeOpL/gKcwIQ30vNNp/xbUwH9M0CkcgPwiZnMfH5Y227Ah",
+	"M0FsKdmH/MY3kcypmMhBeCmDmnOE514g8A84MXhNHRqA4GIBByOvvDCNNZXX4eIcJhnS542N1hbJsYxq",
+	"edGbQ+cve887uAp4DRiIhDP7HMkYWLbbEvEiGH12ZuISzo5Y0Y5CAhHE13Dee509pzsayXl2nsI5Dke8",
+	"V+ZSzjwErIflOCJx3vayIXa+KtDfANLFCbyLlLnX2TXQoczneDOF/Vd3PBCTmnP4EPgLjcUjEUULXFUy",
+	"9WLE4Dq4w6MiwMUXDfCXz5sr4c9ypw7++JRY40j4viNGSYxSoO288YiJAnrgwwlQ/7UAARGFM/ohlnGM",
+	"EAaB8ePHgROnF0hSB/Cf+TyMgKiFC+si2uyeHpFwwQlw37FMHC9x5Je57428xF+0nQ8B/AIEfI3/olSC",
+	"4xojowhCZAr7xgKR6wdSuoAAzoUEao4URsNKRKAnW3KCf2/hlltH7kZI/FF4ycCbSWBYNUf7DviOH+Jx",
+	"hs409F1as6IoAAeu923ouGnEqLn1+Vkn/tx0Pu/OPm8fIGbOQpBlLztxLRrw9HZh0IDR4EnOm/jv8kZu",
+	"9Mes+xDdEIcaREBPgNlnRHexJEUpY9j4F2Et/udPQAIw5r/t5GrVjhp0h8ZqwCyoywBntEgUwoI/xw6+",
+	"AUftbBFOZafSLKMX/gkott22stecZf6azdlUa/2UfRASduK6YMthGiQD+r2iUvBDB7YcHTiuHAEmxrSA",
+	"uVjMYKMOieMmSRo3EiDAEMHHUtKhyQBF7a+NH7r9o0P4+/Ss9/7o/D3874fz/tFJr983lqT3kC3pZ6AN",
+	"WkZ5VaAORRJnZ3KUMPfCuRA+KjrOaCqCCekzpirw8nmjKvCbStetHi2Mj9J9iJ9/zQcC6SpbiHUNy6r5",
+	"bCs/z8IgmfqLIRLr8Pc0TIQFzECj+BxIwgdpDduCteAvMnBF5NAQztb54BDoouMAb0wDgrt019ynXsRV",
+	"6KczWbuMGR02cClSk4TvjNIoIl04DZAdJVOgSmbWTZIQsFzCAoUNMase4byVzkHPBPaIuub6myEetcZ2",
+	"mP4twEadbEijDWHSIWwjTWThCLVaWB0UZD6wzc3OPB4B1hDGwPpn8SpmwOjWx4/wazUcSD2xoNFYZsQ1",
+	"MslzmfRQdCBsQTSRfQLnlTELYpV6KZXlFicsMQviEwTZbF/Gkupga0XwGoRrmnRl5UUGfFCvVOyDMW4f",
+	"vs1YWbx/HXk0vUY9/Tj7m1+w8hc0PntBElko3zAAh5fMGirf+4KfM5TXIT+ZTEM7ewhHRGObod1c6c5V",
+	"fGS1ez2ENyRSZSB6BnwVlQ6LzCKDD8wR0DLUKEjIiJxpALg+AjrKcRJ1SMHKiqk5aZujYTUcTcw0YZQB",
+	"s6mNhlzIFRedQaNwYFa0y/DhGKznKk4AOkXeJmSe49cqotND25b1A0u0blKvgmyCNEpArlr8e/h7wZIA",
+	"v9ISYNVnh/q9pYhVq5/oxTUbdMbZrDa4HJJDgxSr7105OwyDsRfNTpmnnbHuXN2s4nnWHQwMZQ0U8RGP",
+	"eEALBaUcxkODwrkGdR5NLxQo6h1Wy4nIQ+D6StuobmrlqpeeUR0HKu6pKumliG264ccpCEbnTffouPc6",
+	"2/dYeL519SbHLI7TPz887PVewyCo0qjx0FUy9gLhHzinvZPXRydvnRkwrKJGzPwJjbrELyvLQJ+R8gCR",
+	"3wi1n7lLjiSy66dRGIRpDCax9MhFBIyStSItC7N1wW+8KtSreTFVYddsfGnhl60rEZG3B4coHk8/BZtH",
+	"usQwi0/eENQqP5+CHodjf3oadMP/NbAlO1MrQZECogwMg56ECywbliL8UwNFlV+zuLXebJ4stB3rXIQu",
+	"mOumEBRoe2dugVwKstnNp7lqXbX8nl8YJspaWyqFDMPusdj//SFFs3GVm4drgEEbk+uIoOXSh0+KNNVa",
+	"hlxn9CnfRMdi5WSe6c5mBtyth9RG1FJHzxKTKpv6Zcfqd1/iaf9WwwnGPuLPdu/Kimo76jBjcouTTAS9",
+	"Fd6cFQysA5Au+OkFIBVdoggnBkj5kpEaRwQdFoxenEN5W8M0Ue43vdcVMF+yvxL61tprq1G3lsfMPW35",
+	"rD4SHFa9XFU7Ygn2XtJ2+lP0Y4bk6QUKOyiYAXESRnjJAYCfini6mv/r9fHE9ftUCuJ6LL4EAuZ/988q",
+	"CzBbiRaApiOLk463ywodaRXyCiSQN2ZuisjM+yFNxvjUuZaR+gT0j1moPDiT8IDvp649sPHidIQXCFrc",
+	"efqaIb8reuW4KTqukU/w/DxPAupD3NYyWhGTI7+AiYWKJj0oSMPM/CMbj66y1lLg3+uXWWtUlnT1ghfX",
+	"b2pjIlEXDWAwmfcdkTeZwlNUvQxVDZSKRez0D9/1Xp+jLggA9cigDfQtq3MhjcvWK08Urmfyu5Udfqnt",
+	"vE9RgcC5aV3jNEkjoI4gTDI/YBJOJCuCCGzcnRcMDc9bXPKZrXAbVD63OFfhnPCuLQOTF9OK4JsooStJ",
+	"h7w48PaBgXPw1lygBhTAvxHg0ZL9axdN3elrZS53m1QvFvggowQPTIrRNFsurnWE7mh2Dl+EQRo7k0gg",
+	"bqtF7mfO4i0cRgmy7SaqHCMR8bX5PJ7jhSuSiAzEBdoPzkc6hHhu7JvRYs46cY4UgOeg6gOvydYFHObK",
+	"oy8Mw2A0xVvcttPVVoOBn3gvFF15sEo3lOoM+C5JYVlsLEOjB8ENzR3ApCHbSHxyuDp66bdGGqhx0M9c",
+	"MFp+ayhjI7s0QXDwJbpmTM/2bJgFelw4HIWujT2JWLYA52QQA/9FdzC+7ODLAN8QeIzrxXzNkKnP4Xis",
+	"mBbChl4l3zNsHOFwQdLWtXGjDlMjfpEvaggfDccosZvmr2DJjXBFhR/ll6kAuqRwCfN34aODcTHEiQ8y",
+	"9pa/QFdc6lThTwZk6WKy6pAVk3gtHjfAFyuSkGVPUYysFIh1cl+fwvBW0u878bcYMrzEFvsfnOd7u3/h",
+	"wyW54Mq5HzL5XIfRJQZq5NpgdnOydXb+AzIizWUO8DUdPcR8HyMqEJfDOesnhMIzFA7A1IG1eQl7YSaA",
+	"q0GZQmF4G6/vRVG45LDtpNpPkNHBrKOpF8gWIjz9IHEw2rkKavGCK+F77hC4V4oAaDoGheUWA8aslBzq",
+	"Q30kBeafr1sRVr3CxoEglZOjJVZ39Bp0IR8/bo3FCHngDFBHTEjm9uCgPKvGiQoIvVYdsBe4Ldb01UBj",
+	"BRktxUGwTFJ8EMhJmHjk2yEc5rvl1rF+viUDgFV6ANgsnUOw7wCO2dNt4HsXMfmu1Pgc5AOmBOBYgnfT",
+	"PsngGjCuTWBNVBQpHGg1BTGMbXTT+8LXrmcisSHbLTTjSCQW6J9GKA8xWiyQdCep1cqM3NQpXIDQyX5U",
+	"wQZ4fY2Xmry7doGKXu21X6zcf7YPtTwbJN7KRPntn5AXh5SgofEtIMEH+PjXDUb5VPaDnQfyy5yi1EgI",
+	"KtYFuqGLAQHxHJE3DHJt5kICKkuterWdD+xTZtb2TxmF7Tt3OCmDn+lPmzDKD/eEXEqAM0o2jySsvR5x",
+	"In5h1frUOLe8itGTLF/p933vgveAtMh4xU7Xd2Fley77q54ADOzuIpP8N48V2SJFYUQwuAS4jS7jbRsf",
+	"BlYRsnL/o7gSfZrCGfke8Qw3JLvHRythDojp4VYNVx0FvwkSrDDw3BewHuVlLOtJuy86nRcdvjZG0wx+",
+	"+q9fO61Xn/79Tw3btckkbKkfZwiHdlcr29mjljdDK4ov4FDFb0xAQqcXbTj9Hc9fiEUSyevfM3O3pey5",
+	"nfnlZIcGpXM5CTG0j+Mzf/IC14x8OO3+8r53Mhiatz76t+z258PZ697ZsD/oDs77w8N33ZO3hffgl+Pj",
+	"Hvw4POv97fzoDB7agiLMZZzmkaeW2/AZKGgWxYiuRTCGNnboFTTirFz2EjZpcTyYC1CxBDHdgAHoDmBI",
+	"HJ5v24AuCnEuy8iuAl0LBSbSl5NIzIagxNgvUU9AywXVA+11jMZkFQMQk2JMMOZbL5D3P1AD1oJAXfpZ",
+	"XVVaLua+BCMQWF1BRiDHYnFViv9a6vxZFu1xLS+mYXg5TCPLwU6TZB4752fHTKUkHq5gJbE3wXDpH/sf",
+	"Tii440IAiTtbf2/14YFAV5YStU3ScM963dfve9tFQKmJYwIUkLULDAoDbnGXSPjjkP1gTTKJpiHSfCTj",
+	"0L/yOK50HnlXsOsmsIhwjitAPuh7wWXLD0doSbkuvB/LfEgKurVbH7VMklHegihF0GnUtjHTD1ou3oGn",
+	"OUvGsHrdlUNI2BM9DtgPjyHHpHihOdW2euFuEwN5Bz7wqkXoxXN15bRSvr5W76KzXErDk3EXCu8bCWjE",
+	"HjtygybhXJsfMV6ix/E4zfx9MftxtYsS3Y5qOe1vcnCviJnIZtkQper85h8JRwynOcVy4SBumqUUoe9i",
+	"KySc29ZqNjrJRG7Bqgh/chEb/vVNXNcW16Y9/MNcror8cECixWyQ+JzUddg9OewdoyOfl7YiOGTlGfX5",
+	"1c19emVh8O3c/GqduGnAX46bZsP9ACAW4y0csovT7uDwnSUnBB65MgGGhhFCTOqJg+ZevFYYcjmqL4/e",
+	"yDmt1aVpRPIttaMKLGBpIJTiKZp8+S6qiCTXGvNzfqq+Aszm3zDXLlbXvR6lbiEc6hj8XbAgcjuqVSgH",
+	"eVPlh3HejMTY+wMgwnEauNq4TvKvkFTPem/OT0CXJEYEaw02xL2c9iyPUDZvGkRdE4J1fvqud/xarxy0",
+	"St4R+vlNEr8k5T9wjcN6c3Ry1H+HAVsTsAjMTIQPp70T+FODAP7Ls6wZOmVi2Ic5pX2ZP50psJd+Pp9P",
+	"pe82PpUpIMPqDOsVaM1jqUX094YIsbtKC4lRLzodq7u1IOEiKVt4aGxERSIBTmkon2zTCbSxrkL0xHH6",
+	"JjorVbbOXgejIyhNNp0jw3jecS4WcHpN50r4IBjUz7Aa/r1kpmE+IA2N7Ork59ZeZ+95q9N5vkdCU3wx",
+	"t7fXqQNNvwajXh/1T88HGc93pki5osoVAPgHGZlwEuZUFC7ncgpT6hX8Tra6dm8Vg/b0rTH8dtL7SMbZ",
+	"2eAI7LJfhqfdI4rlUyiL4XZaNMH/9YpNnLVZb7lAqZJR4IFE5oRQlHxjzwf7N7uCh1M1MgD/E177a7vd",
+	"No50t9PkG9bddvvlc3VqhWCWDbLv6BB1bEunZIqBIKC1queommZbU9lYd5Oqg36U4TIRulTL2kAr0Ms2",
+	"tINw6cSJ+mAtn535ciEasrC/wpymKF2ei2Fbv+mZUCGnzUb38LB3Oihhrg1HT8Xi6cfk2K9YbQDKt3M3",
+	"vtBlQdxHOgE00ipLpmbb8rDa33QldI++1sIubUDFC6QlPlcRD8PxMitFB1nM0wufSyAQsITiWetmSsRy",
+	"07uq9R3BhbuyVfkhtBQ9Q1Pt3wq4/GrgkWLZigb3BpN5cZxummupAb0WxBVsUKzY3H+sxNQKTlQtPbSc",
+	"REzu6ouUg4wmAjSWOC7eI56hxvKys7v7ArQW0luaddLlNoJrDet/g9GSEBjHbc6sHITKACyIIOOulM/I",
+	"0HINTCktwgKbfF8mpiwhATrmW5JBn3yaJfxc5aC6VE77IvbA9G4KpvL//vf/UPYb7DKcOPpXdKMEylpR",
+	"m+VbSgKWs0WxifK3YE4X3hZbVH1EQWv4G01THpGrPwS/BSoAdEvdwSqDkG4xYYQs/goHUWxUR2jpZ78F",
+	"W4GcCArgwgueIa8MiYMHh3HgXPMhtANGlnThTBAwJBrNLPCIMcOqOqiXreKrL/meWUHW9wJp9+b8ngoQ",
+	"oslizaTNfJe3wJoShRCKlJ0a2XoKczWX6RxnMokW6+VVra13qMpGQyw/Y4+1nIkAM7kRcbxZKaWJ1BGM",
+	"XWLE4yhYHrKSnL46D/URFZMCGGyw7/M89WDncIh4QxaMxWMqO//BrOOBRhsmz3LRoCgkZ8jW526aTMPI",
+	"+yddbe076pPf0k7n2Yg+pP/Kz9ubaYMqBkyDXDu4efMHVI0kZp9LxpX0CXh4ORPI67pEQfsB6Po5Bvis",
+	"4K8QWbWgVP6Q76owG+OCIrEEx3lyRSYM08t4HvIL9mqrwIeiQG+9ADFevDVu/eeTuDcehPPz+YaZbI+k",
+	"GsLRUqr3sNY13Z3Pffbcc/UI9sGqHAYVL4X4FSce1rxRkftqOMUXyI2hgbajPtpRDpltM6nheedVTa2J",
+	"FfXB1jQNi0fzRzLfk03m0/6Nsl+ieFBFn01NtajsJiwcjykgPQlXc0Fj5DWWV4dIieEcW9sl9fiSN1u2",
+	"bevndCnGSWZ/w2xH43RKTmsqSJdVzwPZJClBw4vY5Vy9k7FnZ95RRuZGw9zU7rwmHKcWCmdy7gsKEAH+",
+	"aASMHHB4uQEfDIQY+agtuFXQZME99VE79xp2syo0ZY3qAwy/jTyNlpJ0WWB+jNCka0ECHLlu3Lal2CLX",
+	"UaRTcDnQ7Xoa+hLju+GVrzdIJr9+ohoMCH1KLVVZIl5gLmq3fCibZQneOrRg44vrWmneU/JeMUX1HmdN",
+	"8G03XczHeQqhZ1zEaiBjKsXIXslrhZhejhPfd+jqOWYl1BQ3ohpja5q/mKtRQ5P4qLYE15rM9RsYqsWC",
+	"XBJOl/mGSXOIOenVF1gl10/JQ0zHg7FnG7iIeZHf4jRT8DWg2VTnk42+WW0tAwi1eAFSQlsedRWOSobo",
+	"wuFpmlxwlooLR6oi6jqCwEBHiwigrRRLyKL/tNXZXUkL/GlzaQWlnzFJaTXhP1y69zel2N0ilj9zqy7V",
+	"grGU553yRqQad4ilOa1ROKpup6PzR66nni9L/k029E56H+3BibeAhXb05IurwgJvdyXAyksWfdwUb76L",
+	"lVupmDD6XKwuk8QbqQKv7LOhEkqTNFKJ4G+7g97H7i/D7uv3RyfDwYefeift+nKsNF9roPwimmayegus",
+	"FNcshV0FrSTUXgOuNKscwnlSNi12BwZtYbxG2/mgcg8P2K2jdR5291wpOnKzOBudTENZimMv0SU1QOxx",
+	"LQp6E6+tuEKoyp1OKWWOas1TAELQVOU0qFI1pg0o5sbl4oi1UVp29py5ET9hTw7FYNP7TWci4ZXne6+M",
+	"4Gmj3iRZZ1c6NaAO+KdHql57BfDsYtOA51q7b7S4AAneKGuS7/p7L14qjCBV53OcXnwm0HyOAJfjz84W",
+	"ImizVCWYU9EFVhYIg8UMK1FrfUG5BFFk8UGqB5wUoYqGq9A+chUClVF6ROHtShliwIBTyp7A5XDpTvLA",
+	"YHWUMFDmFFbxMAqh6Gz0GRXzE4gcC/oekAB1ygNFEcpjiTWK2Z2jfvXVzQCxD6JvgmgOeYw8b5TqNdvR",
+	"XiV6ZZfk6h6YEb5SgmmdQsilw0fO4AXj0F679a0uacg7fTcYnBopviEXiWeSONXpcLiyydnpIcCgR6dF",
+	"6iKYClSbO6vozDWi1d6SxT78BJi2OX5gED7aMOUq0UyYyI0xpdIszMKZ9UBOnV1nS42SZe9ukyc4QDxT",
+	"pcjTOYysVF4+Vx+4jxImCsDvj5BGyJTjtIL9nR0MtorDNAJNP4wmO+qjHeAZOyxKElIL3ob/DLmYtQJ2",
+	"w7A/GrvtTrujwxnhzLDcM/z0TFWJJC5eYnkk40I2DlHSkakKR79fKJOjatDDQf4QugvOnKZMXa6Uw3VP",
+	"4MOdf6iQyLwS9VKlwFJE6qYoulT0U6REMq13D/Si+1mCkvu0hiJ+/5SLDwTw807nzpZQzFG3zP0DMJVI",
+	"gwfnfvZwc7/34piD5EDCYBVzQ7zjYl485GIUqzayWDL9oqC0UHhxWV359RMGEsfpbCbQKNT1gvLa8A1t",
+	"+//K33Kkaoledr5S15cb5oC+5BDrIuWcUQnljHLMvjI1Yc/5KzuFvjO44hLqP6/yXsRNVbb5yeHHc17w",
+	"wywGAcH5UmlwC4zgc1uOEcRJRxbZOyD2L8djSbrCSBq64EhgpggmZ12CfFA3fM5Wpg6fHg1/6v0yPOwe",
+	"vusNB4Nj8oEUcariCL4LxLp7jl7rr16Lrd8dT+0aCksVR5SP4A9G/iQI1aHo+ox9/T+WKOSwzc0tMBzI",
+	"QItVnNNacgaLZu+Q+bHzlRt/kawBk64qaDA/H3V1qrS9OUMoNR+7aX7dtNXUbmdpr6kXK3tNfbpPHlCs",
+	"Z247fXzD0a6zf23VikCBsXmq0N23EAKGIIKVlffzoMASNvExMEa7T5dSQqorDynULzcgIzc25YFXHNjK",
+	"8Nfy9bzffdsbvjk+778bHp0Memc/d49VvQVdHSdRDhIqXCom3N4IQ2fS0ZTNuCLlvZUJuXSrRFe5BTH7",
+	"fcDxng8ODwoTo8GZF8aqa/Kj/by2dmO5r7hc1OHr85sW/2fv5k82X/JX+6UfgFA3TKpZT+a3r+93dZ+0",
+	"bbrxLdhMj6nhyiX7rP4Q84/BVM6Lfs474CxGiyB0wWLohKB+ftojimeuPXpUiE4Tjo3RBGatjZ15sdyH",
+	"le2ck1+QWAN7faj8RKE0BXynijtQxhyFBivlgufjgIMKP6mrPLKpWC+0dbtXIqxbsQUTjMdZi8EHp0uD",
+	"NlTlvopT9MFJpFjuZR0t1HS/A3kAC686hstkA+jlmMhuIqxBG4XFsLGbWtTOpaE5d4Gv92WXrogmemAb",
+	"9ZbkQxznwWnnSBEMRUY1dQWgpi5dQ4VxgK4uVZDTd0hDKrTsNnSEsiavk1ZryvH9yDcSUHPl+0Yz4jXe",
+	"LnWQXeOLrMvpGu9aWzqv8V2lQ/G9CjpLHTwLGqrrLWo9/WgyztA9nS0t5Uh3dwh88TZTQ4bWuDeVAm9g",
+	"sEJFEgGhLdCSved4jQYGnVl9mxOrMWSBVZ9iPx8qwh2b1cCxYFBb9Y9W31J/IOdCwv5hhqyKhVmuxmaT",
+	"GeWd752GrP3U16Ems+fxfQk7S+OHR7lVKwbT1FFMFpWxpbFC19gv4s420hKYuo+zSKFbSW/RBTKHMfBp",
+	"7jvFptTbB8489P282/RYRTdSzyyF5IzArHQS+PXbth7dMFc4rulQrQfPyLC9ogl1rku0MN1ENTas/+Lm",
+	"0c3nVw8397F3KYFNZa08nC1O/yy29mhaeng43PWh3PBjG6sQzQHRGGWod8lfkfwoApqvID0s3LNQPc6K",
+	"3FldUvIMW4RfToFMgJ9buHaucOSYUqt66Gq29840zZb3T07GV2r61rKCpyDbH/zGhrdeulwt2JehIcJ3",
+	"dP16u0Zhv0HtqmYvqu5YMRjSI5WCY+dLCQm61jmIUKU0cNlUjrCH3V3atAUjFv5hEf9eLdzNhX7nflaw",
+	"CpOewBWsTjrIECwIHT8MJvAHVqd6dAp7YNH3wUxScWZeTF03SoTOZ6xAZnzfzDOBEKpI82uKJdUGyoxD",
+	"K1+hcJcfYXSd4vlVGQePDYqhSMCO0IEXWkhOBZbcl4FRKajMVLLGVfn5my2srKZG3nv3EZjHPdGvraFw",
+	"vdKuwfPEKDjJseSJyUgGb7Vs7PqEwv1nWwq1l1AMvxgbNf3MCp98feEZZrjqugWSUrp5GT+jT3LT8UEx",
+	"dp61Xjt99KOp5oX1tdWLXrIYm4OBRB9NWYAbPdWoc9xMYDOhLCY4895FM1W9BUS8mqtvTDYYHFMCXans",
+	"pRevIN5CH9/vQvjbG2TfKAXgvviFvb+1zX2tzjs71H8p1fkktJMh9hqPK53Gy1yDH+WkvDa70CUg6vlE",
+	"1j/ClVgziPIwxzRTlHhYNZ5riIw5yF4RV2B6+OBJsYRnyZOnfXjFlxwxRgcfVdM0SoabFfyo23jm+EOh",
+	"Dm/4C6qcbaNpXYXwyTr+HoILlCtLrqX/793D9MuukSpVGnPN7A+DOiN7gGVWND+jvW/z/+wYbZOssQ0D",
+	"Smijd1CGcpW5ctG1ilceKZVDIThfPVY5bhwV+Ne5qwt1ZgNr/wln4URYzxrztdxwlLJWkPegxa4aNeFX",
+	"ZquoB/dcleop65Xzng+4GcjqmC4VOGmNrGz8gytg63px6k8Ap6Uy3AM5xoptuRDJzFFxaYVBs7jQCy8Q",
+	"0cISflYhCqNl12MxgmbVohh7ARdR1X582ljWEVh3kX2CPjmT8ki0G1vZxPTAqnCL1YbHmdnP02yVEeSF",
+	"8VRf6DEF4cTpGIwEj2gHdhBzpfp94wgm2PSXOtqANaKNdd1luXS5SDaKupAMwKDooYJhq8gHL6nGnKp0",
+	"OylmVNRvi9pI9Yfvu38favPmrDc4O+r1t22cyCxF+P24AqwFFpco9rlzhQ7wqdAvZtBzs6MVaKcIm9Cc",
+	"UYMFFeXtPAlP4N4DegIHYcjFLtOAyGwL6G5bxwgUmQwhSh25r81ezMpdds6i7wbw/DQXKBkVqow/XbMF",
+	"ucnlUU3USURhBnljKCyPFZMTJLwO0B+dZcpjf7054Qbf+cYHmqdgRTNd24icK6rdOTIbC2solC37LtwM",
+	"1jpxD3zLYC8GZ8Ni9aKuRPeElApToRCmN8xA1iemTXwg5M8EOQhjEXAlVkzr2JjSd5i26gn+fXilSuxm",
+	"E1LSBNUBaGJNMS66cSFLRAv8h1ybijb1hG3nmKKKsj68BVeMqmlGLc0KM/051mU3bRTOvayL9QS/Gx3A",
+	"srnV6KPB/Qe9reUWLCFpyU1vImKJHLuq2aumx7zapvnNulSJnshat8AxZpFgQNO+Mw3x3t0sfJOLTRoM",
+	"696w5085AzgpS7kKk0rRo7jJFwDsEojNLmRdVXdDqGxqo30e3V9wmK9xYWsj0Kyk01OLbcGFDRgUDxDb",
+	"Uq1sVX+vx0UHm/bzAl0wqxu1/Ye7LiNHBHCZFrgjHvnJlxPivq4rVS8NX2MZo5Tc81zORn2JPzLVxIBZ",
+	"cHjYX5prThkUJXQt8MwuClPfpbZfTdKX8YDjJOQkLbpVJd8i/qlb6Thc3wAozvCAY4UoVqpVoeEsxQsX",
+	"Yr99KxSje5rJIreNn707UrBX7LMpuaaYIzx6XKosUIXehREejphGUY+ManWEUS5fvuSuuxCHrkuk66BY",
+	"1E3bztE4eyB8LNG14NhNkD6aTPAGDgCUtGuiylXF7Ue6XrpXPC/VzrdeGu/e15xLEtLViT2ZoksP6IDp",
+	"WrHVHhqsMXtrJr44u5T/ilhvXgvpwIoa4toxyujXBQj/kBWSf6rJqPkil58qy6mnnoP6oLqNRrclpZNu",
+	"m3SqsTNvRLAuUibhPJ3XV6cze0t8X1zZ1tDkoX1stsYdSxAHzmoOtkE6/5cyCSxk80hyQgftuh66AZTR",
+	"Xek282jXCNzLhm5ZQAGMsJ7qTDUdwpsWrMwquV20kQ9FNwutLkYKWfOT8hpCN0W5CMhLVTgz3lDDcbJ2",
+	"lVavxyl2Icu8hNkdr6raMvJkrPMOdE1pPSNnIFNrKG66XRGmZ6qN5f1d3xXah9ru2UlnNoXggyZs696f",
+	"3JHUkrGtvQ9ZI1OwbtJAXAnPx7ZclptuWRiy/tRVBdd6g+IIw17Qnoip01a5oix5ENHU0DVeHWp0oJsM",
+	"qoLdLNiLnwo2xrnqbJNbhOENVGaskAmvuyWoIJisTBH9zpdWqgpxXkxXe6idE6qWa7HRa7NlVTe3+0TG",
+	"csM4Cz6oV3TbNCtEOPtz9yGXdQLHrM9Q5aQyi999QAFntsBT3hVhw68HJ2IFQCv5bgEO/vhxMOz3Ds96",
+	"g3LKeT+hyLaIz9tawNkgYD2PJmBmxaUSeRYbphJZBGOA4jTKeowat9bYF8hFP/KWCyxm4cSBmMdT2NPc",
+	"z/LRXeknIt5mf5d6XaWzE+0nuRsszmXBVKgQOiB0DEcAsUUdOqV74MDR+limexbyGiKqZ+V0aiLelO3S",
+	"vZtKfyXwvDl0nj179oqjYBIxmzfpTJWMG6cgKWRdHBvFsBW106YtDmxJK5F7NQ0zwK1jGYq42lucD+gp",
+	"2IqfRfL5wWuKHari61i83qM7X13zPkBxSAW1Hl35RijhHwuZ+Yy4QSJW8VPnt9qqXVV/TGNJVtBQ4Qve",
+	"KsbZRAbzYkAh76LJoyt7nUDM/vd3XHnl8DuF+vD7Oztfp6Cs3Ox/xcFusBj1zhXW+7sSkYcKEZHMNNNn",
+	"dAzp7ov/aO++7LT3dl+1UXZSNY+o9NKLzosOQvhTtupKUUDNiThbQZieOcr9YwW/md0CoFJSVMjaObfI",
+	"FDJL9cHiRJmjmHWdJpVWwb9x/LFMRtM8+15VVcinUf7k6iTdsqxRk/keMWHtpQappLsNZGqoMXwmlaoT",
+	"6DYIRBiwYt6S8W1XUUxVRgm3RdnPXCk6zhUhRW+gEs7MRSjsqg71cYr9nUg5vMDdXE+5lbbSpuJCKTo1",
+	"WrFq0c2nm/8Dq8sT3yHDAAA=",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -0,0 +1,90 @@
+// Package breaker is the consecutive-failure circuit breaker the services
+// put in front of their Redis caches, so that a Redis outage costs one
+// skipped call per request instead of a timeout.
+package breaker
+
+import (
+	"expvar"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// metrics counts, per breaker name, the failures, the calls skipped while
+// open, and the times the circuit opened and closed.
+var metrics = expvar.NewMap("cache_breaker")
+
+// Breaker guards the calls to one cache. After threshold failures in a row
+// it opens and every call is skipped until cooldown elapses; then a single
+// trial call is let through (half-open).
+type Breaker struct {
+	name      string
+	threshold int
+	cooldown  time.Duration
+	logger    *slog.Logger
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	open     bool
+	trial    bool
+
+	now func() time.Time
+}
+
+// New returns a closed breaker named name in the metrics and logs; logger
+// gets the lines written when the circuit opens and closes. A threshold or
+// cooldown that is not positive is replaced by 5 or 30s.
+func New(name string, threshold int, cooldown time.Duration, logger *slog.Logger) *Breaker {
+	if threshold <= 0 {
+		threshold = 5
+	}
+	if cooldown <= 0 {
+		cooldown = 30 * time.Second
+	}
+	return &Breaker{name: name, threshold: threshold, cooldown: cooldown, logger: logger, now: time.Now}
+}
+
+// Allow reports whether a call may be attempted.
+func (b *Breaker) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return true
+	}
+	if b.trial || b.now().Sub(b.openedAt) < b.cooldown {
+		metrics.Add(b.name+".skipped", 1)
+		return false
+	}
+	b.trial = true
+	return true
+}
+
+// Success records a call that worked and closes the circuit.
+func (b *Breaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		b.logger.Info("cache circuit closed", "cache", b.name)
+		metrics.Add(b.name+".closed", 1)
+	}
+	b.failures = 0
+	b.open = false
+	b.trial = false
+}
+
+// Failure records a failed call; it opens the circuit at the threshold or
+// when the trial call fails.
+func (b *Breaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	metrics.Add(b.name+".failures", 1)
+	b.failures++
+	if b.trial || (!b.open && b.failures >= b.threshold) {
+		b.logger.Warn("cache circuit opened", "cache", b.name, "failures", b.failures, "cooldown", b.cooldown.String())
+		metrics.Add(b.name+".trips", 1)
+		b.open = true
+		b.trial = false
+		b.openedAt = b.now()
+	}
+}
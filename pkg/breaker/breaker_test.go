@@ -0,0 +1,72 @@
+package breaker
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAfterThreshold(t *testing.T) {
+	b := New("test", 2, time.Minute, slog.Default())
+	b.Failure()
+	if !b.Allow() {
+		t.Fatal("breaker opened before threshold")
+	}
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker did not open after threshold")
+	}
+}
+
+func TestBreakerHalfOpenAfterCooldown(t *testing.T) {
+	now := time.Now()
+	b := New("test", 1, time.Second, slog.Default())
+	b.now = func() time.Time { return now }
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker allowed call during cooldown")
+	}
+
+	now = now.Add(2 * time.Second)
+	if !b.Allow() {
+		t.Fatal("breaker did not allow trial call after cooldown")
+	}
+	if b.Allow() {
+		t.Fatal("breaker allowed a second concurrent trial call")
+	}
+
+	b.Failure()
+	if b.Allow() {
+		t.Fatal("breaker did not re-open after failed trial")
+	}
+
+	now = now.Add(2 * time.Second)
+	if !b.Allow() {
+		t.Fatal("breaker did not allow trial call after second cooldown")
+	}
+	b.Success()
+	if !b.Allow() || !b.Allow() {
+		t.Fatal("breaker did not close after successful trial")
+	}
+}
+
+func TestPending(t *testing.T) {
+	now := time.Now()
+	p := NewPending("test", time.Minute)
+	p.now = func() time.Time { return now }
+	if keys := p.Take(); len(keys) != 0 {
+		t.Fatalf("Take() on empty set = %v", keys)
+	}
+
+	p.Add("old")
+	now = now.Add(2 * time.Minute)
+	p.Add("a")
+	p.Add("a")
+	keys := p.Take()
+	if len(keys) != 1 || keys[0] != "a" {
+		t.Fatalf("Take() = %v, want only the unexpired key a", keys)
+	}
+	if keys := p.Take(); len(keys) != 0 {
+		t.Fatalf("second Take() = %v, want nothing", keys)
+	}
+}
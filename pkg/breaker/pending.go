@@ -0,0 +1,59 @@
+package breaker
+
+import (
+	"sync"
+	"time"
+)
+
+// maxPending caps the keys a Pending remembers; further keys are dropped and
+// may be served stale until their TTL.
+const maxPending = 10000
+
+// Pending remembers the cache keys whose write or delete was skipped while
+// the circuit was open or failed, so that they are deleted once the cache is
+// reachable again instead of being served stale until their TTL.
+type Pending struct {
+	name string
+	ttl  time.Duration
+
+	mu   sync.Mutex
+	keys map[string]time.Time
+
+	now func() time.Time
+}
+
+// NewPending returns an empty set for the cache named name in the metrics.
+// A key older than ttl has expired from the cache and is forgotten.
+func NewPending(name string, ttl time.Duration) *Pending {
+	return &Pending{name: name, ttl: ttl, keys: map[string]time.Time{}, now: time.Now}
+}
+
+// Add records that key may be stale in the cache.
+func (p *Pending) Add(key string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.keys[key]; !ok && len(p.keys) >= maxPending {
+		metrics.Add(p.name+".pending_dropped", 1)
+		return
+	}
+	p.keys[key] = p.now()
+}
+
+// Take returns the recorded keys that have not expired yet and forgets all
+// of them; the caller adds back the ones it failed to delete.
+func (p *Pending) Take() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if len(p.keys) == 0 {
+		return nil
+	}
+	now := p.now()
+	keys := make([]string, 0, len(p.keys))
+	for k, at := range p.keys {
+		if p.ttl <= 0 || now.Sub(at) < p.ttl {
+			keys = append(keys, k)
+		}
+	}
+	clear(p.keys)
+	return keys
+}
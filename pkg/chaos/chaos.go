@@ -0,0 +1,135 @@
+// Package chaos injects latency and errors into a service's own calls, so
+// the failure modes of the payment saga can be rehearsed outside production.
+//
+// An Injector guards one kind of call (database transactions, Kafka
+// publishes, cache calls). Before the call the service asks it for a fault:
+// with probability DelayRate the call is delayed by Delay, and with
+// probability ErrorRate it fails with ErrInjected instead of running. A nil
+// Injector never injects anything, so call sites need no extra checks when
+// chaos is off, which is the default.
+package chaos
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInjected is the error of an injected failure.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// faults counts injected faults as "<target>.delayed" and "<target>.failed".
+var faults = expvar.NewMap("chaos_faults")
+
+// Config is the fault mix of one target. Rates are probabilities in [0, 1].
+type Config struct {
+	Delay     time.Duration
+	DelayRate float64
+	ErrorRate float64
+}
+
+// Enabled reports whether c injects anything at all.
+func (c Config) Enabled() bool {
+	return (c.Delay > 0 && c.DelayRate > 0) || c.ErrorRate > 0
+}
+
+// ParseConfig reads "delay=200ms,delay_rate=0.1,error_rate=0.05"; every key
+// is optional and an empty spec injects nothing.
+func ParseConfig(spec string) (Config, error) {
+	var c Config
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("chaos %q: want key=value", part)
+		}
+		key, val = strings.TrimSpace(key), strings.TrimSpace(val)
+		var err error
+		switch key {
+		case "delay":
+			c.Delay, err = time.ParseDuration(val)
+			if err == nil && c.Delay < 0 {
+				err = errors.New("must not be negative")
+			}
+		case "delay_rate":
+			c.DelayRate, err = parseRate(val)
+		case "error_rate":
+			c.ErrorRate, err = parseRate(val)
+		default:
+			err = errors.New("unknown key")
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("chaos %q: %w", part, err)
+		}
+	}
+	return c, nil
+}
+
+func parseRate(s string) (float64, error) {
+	r, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, err
+	}
+	if r < 0 || r > 1 {
+		return 0, errors.New("rate must be within [0, 1]")
+	}
+	return r, nil
+}
+
+// Injector injects the faults of one target. It is safe for concurrent use.
+type Injector struct {
+	target string
+	cfg    Config
+
+	// roll returns a number in [0, 1); tests replace it.
+	roll func() float64
+}
+
+// New returns the injector of target, or nil when cfg injects nothing.
+func New(target string, cfg Config) *Injector {
+	if !cfg.Enabled() {
+		return nil
+	}
+	return &Injector{target: target, cfg: cfg, roll: rand.Float64}
+}
+
+// Target is the name the injector was created with.
+func (i *Injector) Target() string {
+	if i == nil {
+		return ""
+	}
+	return i.target
+}
+
+// Inject runs before the guarded call. It may sleep for the configured
+// delay, returning ctx's error if ctx ends first, and then returns
+// ErrInjected for the calls chosen to fail; the caller returns that error
+// instead of making the call.
+func (i *Injector) Inject(ctx context.Context) error {
+	if i == nil {
+		return nil
+	}
+	if i.cfg.Delay > 0 && i.roll() < i.cfg.DelayRate {
+		faults.Add(i.target+".delayed", 1)
+		t := time.NewTimer(i.cfg.Delay)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		case <-t.C:
+		}
+	}
+	if i.roll() < i.cfg.ErrorRate {
+		faults.Add(i.target+".failed", 1)
+		return fmt.Errorf("%s: %w", i.target, ErrInjected)
+	}
+	return nil
+}
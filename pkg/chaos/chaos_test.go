@@ -0,0 +1,89 @@
+package chaos
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig(" delay=150ms, delay_rate=0.25 ,error_rate=0.1")
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+	if want := (Config{Delay: 150 * time.Millisecond, DelayRate: 0.25, ErrorRate: 0.1}); c != want {
+		t.Fatalf("ParseConfig() = %+v, want %+v", c, want)
+	}
+	if c, err := ParseConfig(""); err != nil || c.Enabled() {
+		t.Fatalf("ParseConfig(\"\") = %+v, %v; want a disabled config", c, err)
+	}
+	for _, spec := range []string{"delay", "delay=soon", "delay=-1s", "error_rate=2", "delay_rate=-0.1", "jitter=1s"} {
+		if _, err := ParseConfig(spec); err == nil {
+			t.Errorf("ParseConfig(%q) accepted an invalid spec", spec)
+		}
+	}
+}
+
+func TestNewDisabledIsNil(t *testing.T) {
+	if i := New("db", Config{Delay: time.Second}); i != nil {
+		t.Fatal("New() with a zero delay rate returned an injector")
+	}
+	var i *Injector
+	if err := i.Inject(context.Background()); err != nil {
+		t.Fatalf("nil Inject() = %v, want nil", err)
+	}
+}
+
+func TestInjectFailsByRate(t *testing.T) {
+	i := New("kafka", Config{ErrorRate: 0.5})
+	i.roll = func() float64 { return 0.4 }
+	if err := i.Inject(context.Background()); !errors.Is(err, ErrInjected) {
+		t.Fatalf("Inject() = %v, want ErrInjected", err)
+	}
+	i.roll = func() float64 { return 0.6 }
+	if err := i.Inject(context.Background()); err != nil {
+		t.Fatalf("Inject() = %v, want nil above the rate", err)
+	}
+}
+
+func TestInjectDelayHonoursContext(t *testing.T) {
+	i := New("cache", Config{Delay: time.Hour, DelayRate: 1})
+	i.roll = func() float64 { return 0 }
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := i.Inject(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Inject() = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestInjectDelays(t *testing.T) {
+	i := New("db", Config{Delay: 20 * time.Millisecond, DelayRate: 1})
+	start := time.Now()
+	if err := i.Inject(context.Background()); err != nil {
+		t.Fatalf("Inject() = %v, want nil", err)
+	}
+	if d := time.Since(start); d < 20*time.Millisecond {
+		t.Fatalf("Inject() returned after %v, want at least 20ms", d)
+	}
+}
+
+type countingWriter struct{ n int }
+
+func (w *countingWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	w.n += len(msgs)
+	return nil
+}
+
+func TestWithWriterFaults(t *testing.T) {
+	w := &countingWriter{}
+	if got := WithWriterFaults(w, nil); got != MessageWriter(w) {
+		t.Fatal("WithWriterFaults(w, nil) wrapped the writer")
+	}
+	faulty := WithWriterFaults(w, New("kafka", Config{ErrorRate: 1}))
+	if err := faulty.WriteMessages(context.Background(), kafka.Message{}); !errors.Is(err, ErrInjected) || w.n != 0 {
+		t.Fatalf("WriteMessages() = %v with %d messages sent, want ErrInjected and none", err, w.n)
+	}
+}
@@ -0,0 +1,34 @@
+package chaos
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// MessageWriter is the part of *kafka.Writer an outbox publisher uses.
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// WithWriterFaults returns w delaying or failing writes as f decides; a
+// failed write sends nothing, like a broker that is down. A nil f returns w
+// as is.
+func WithWriterFaults(w MessageWriter, f *Injector) MessageWriter {
+	if f == nil {
+		return w
+	}
+	return faultyWriter{w: w, faults: f}
+}
+
+type faultyWriter struct {
+	w      MessageWriter
+	faults *Injector
+}
+
+func (fw faultyWriter) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	if err := fw.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fw.w.WriteMessages(ctx, msgs...)
+}
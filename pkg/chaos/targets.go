@@ -0,0 +1,32 @@
+package chaos
+
+import "log/slog"
+
+// Targets builds the injectors of database transactions, Kafka publishes
+// and cache calls from their specs (see ParseConfig), logging the ones that
+// inject anything. They are all nil unless enabled.
+func Targets(enabled bool, dbSpec, kafkaSpec, cacheSpec string, logger *slog.Logger) (dbFaults, kafkaFaults, cacheFaults *Injector, err error) {
+	if !enabled {
+		return nil, nil, nil, nil
+	}
+	targets := []struct {
+		name string
+		spec string
+		out  **Injector
+	}{
+		{"db", dbSpec, &dbFaults},
+		{"kafka", kafkaSpec, &kafkaFaults},
+		{"cache", cacheSpec, &cacheFaults},
+	}
+	for _, t := range targets {
+		c, err := ParseConfig(t.spec)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		*t.out = New(t.name, c)
+		if c.Enabled() {
+			logger.Warn("chaos fault injection enabled", "target", t.name, "delay", c.Delay, "delay_rate", c.DelayRate, "error_rate", c.ErrorRate)
+		}
+	}
+	return dbFaults, kafkaFaults, cacheFaults, nil
+}
@@ -0,0 +1,287 @@
+// Package client is the Go SDK of the gateway REST API.
+//
+// client.gen.go is generated from api-files/openapi/api-gateway.yaml by
+// oapi-codegen (see oapi-codegen.yaml next to it) and changes with the API;
+// this file adds what every caller would otherwise write by hand:
+//
+//   - credentials: a bearer token, an API key, an admin token or a fixed
+//     X-User-Id set once on the client;
+//   - idempotency keys: a write whose endpoint takes an Idempotency-Key gets
+//     a fresh one when the caller leaves it empty, and keeps it across
+//     retries, so a retried request is never applied twice;
+//   - retries of network errors and of 429, 502, 503 and 504 answers with a
+//     doubling backoff that honours Retry-After. Writes are retried only
+//     when they carry an idempotency key;
+//   - typed errors: any answer of 400 or above is returned as an *APIError
+//     with the gateway's error code, instead of a response the caller has to
+//     check.
+//
+// Typical use:
+//
+//	c, err := client.New("http://gateway:8080/api/v1", client.WithBearerToken(token))
+//	...
+//	res, err := c.CreateOrderWithResponse(ctx, &client.CreateOrderParams{}, body)
+//	var apiErr *client.APIError
+//	if errors.As(err, &apiErr) && apiErr.Code == httperr.CodeConflict { ... }
+//
+// Error codes are those of pkg/httperr plus the gateway's own.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Version is the version of the gateway API the client was generated from;
+// the SDK is released together with the service.
+const Version = "1.0.0"
+
+// Headers the client fills in.
+const (
+	HeaderIdempotencyKey = "Idempotency-Key"
+	HeaderUserID         = "X-User-Id"
+	HeaderAPIKey         = "X-API-Key"
+	HeaderAdminToken     = "X-Admin-Token"
+)
+
+// Defaults of the retry policy.
+const (
+	defaultRetries    = 3
+	defaultBackoff    = 100 * time.Millisecond
+	defaultMaxBackoff = 2 * time.Second
+	// maxErrorBody caps how much of an error answer is read.
+	maxErrorBody = 64 << 10
+)
+
+// Option configures New.
+type Option func(*config)
+
+type config struct {
+	doer       HttpRequestDoer
+	headers    http.Header
+	retries    int
+	backoff    time.Duration
+	maxBackoff time.Duration
+	newKey     func() string
+	sleep      func(ctx context.Context, d time.Duration) error
+}
+
+// WithBearerToken authenticates as the user or anonymous session of token.
+func WithBearerToken(token string) Option {
+	return func(c *config) { c.headers.Set("Authorization", "Bearer "+token) }
+}
+
+// WithAPIKey authenticates with a merchant API key.
+func WithAPIKey(key string) Option {
+	return func(c *config) { c.headers.Set(HeaderAPIKey, key) }
+}
+
+// WithAdminToken authenticates the admin endpoints.
+func WithAdminToken(token string) Option {
+	return func(c *config) { c.headers.Set(HeaderAdminToken, token) }
+}
+
+// WithUserID sends X-User-Id on calls whose parameters leave it unset, for
+// support, admin and API key callers acting for one user.
+func WithUserID(id string) Option {
+	return func(c *config) { c.headers.Set(HeaderUserID, id) }
+}
+
+// WithDoer sends requests through d instead of http.DefaultClient.
+func WithDoer(d HttpRequestDoer) Option {
+	return func(c *config) { c.doer = d }
+}
+
+// WithRetries sets how many times a failed request is retried (0 turns
+// retries off) and the backoff before the first retry, which doubles up to
+// maxBackoff. Zero durations keep the defaults of 100ms and 2s.
+func WithRetries(n int, backoff, maxBackoff time.Duration) Option {
+	return func(c *config) {
+		c.retries = max(n, 0)
+		if backoff > 0 {
+			c.backoff = backoff
+		}
+		if maxBackoff > 0 {
+			c.maxBackoff = maxBackoff
+		}
+	}
+}
+
+// New returns a client of the gateway at server, the API root such as
+// "http://localhost:8080/api/v1".
+func New(server string, opts ...Option) (*ClientWithResponses, error) {
+	cfg := config{
+		doer:       http.DefaultClient,
+		headers:    http.Header{"User-Agent": {"payments-service-client/" + Version}},
+		retries:    defaultRetries,
+		backoff:    defaultBackoff,
+		maxBackoff: defaultMaxBackoff,
+		newKey:     uuid.NewString,
+		sleep:      sleep,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewClientWithResponses(server, WithHTTPClient(&doer{cfg: cfg}))
+}
+
+// doer applies the client's headers, idempotency keys, retries and error
+// mapping around the underlying HttpRequestDoer.
+type doer struct {
+	cfg config
+}
+
+func (d *doer) Do(req *http.Request) (*http.Response, error) {
+	for name, values := range d.cfg.headers {
+		if req.Header.Get(name) == "" {
+			req.Header[name] = values
+		}
+	}
+	// The generated code sets the header, possibly empty, exactly on the
+	// endpoints that take one.
+	if _, ok := req.Header[HeaderIdempotencyKey]; ok && req.Header.Get(HeaderIdempotencyKey) == "" {
+		req.Header.Set(HeaderIdempotencyKey, d.cfg.newKey())
+	}
+	retryable := idempotent(req.Method) || req.Header.Get(HeaderIdempotencyKey) != ""
+	if req.Body != nil && req.GetBody == nil {
+		retryable = false
+	}
+
+	backoff := d.cfg.backoff
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		resp, err := d.cfg.doer.Do(req)
+		var wait time.Duration
+		if err == nil {
+			if resp.StatusCode < http.StatusBadRequest {
+				return resp, nil
+			}
+			apiErr := readError(resp)
+			if !retryStatus(resp.StatusCode) {
+				return nil, apiErr
+			}
+			err, wait = apiErr, apiErr.RetryAfter
+		}
+		if !retryable || attempt >= d.cfg.retries || req.Context().Err() != nil {
+			return nil, err
+		}
+		if wait <= 0 {
+			wait = backoff
+			backoff = min(backoff*2, d.cfg.maxBackoff)
+		}
+		if err := d.cfg.sleep(req.Context(), wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+func retryStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// APIError is an answer of 400 or above from the gateway.
+type APIError struct {
+	StatusCode int
+	// Code is the gateway's machine-readable error code, such as
+	// "rate_limited" or "idempotency_key_required"; empty when the body was
+	// not an ErrorResponse.
+	Code string
+	// Message is the developer-facing message in English.
+	Message string
+	// UserMessage is the localized message for end users, if the gateway
+	// has one for Code.
+	UserMessage string
+	Details     map[string]any
+	// RetryAfter is the wait the gateway asked for, if any.
+	RetryAfter time.Duration
+	// Body is the raw answer, up to 64 KiB.
+	Body []byte
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("gateway: %d: %s", e.StatusCode, msg)
+	}
+	return fmt.Sprintf("gateway: %d %s: %s", e.StatusCode, e.Code, msg)
+}
+
+// Temporary reports whether the request may succeed if sent again later.
+func (e *APIError) Temporary() bool {
+	return retryStatus(e.StatusCode)
+}
+
+func readError(resp *http.Response) *APIError {
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	e := &APIError{StatusCode: resp.StatusCode, Body: body, RetryAfter: retryAfter(resp.Header.Get("Retry-After"))}
+	var er ErrorResponse
+	if json.NewDecoder(bytes.NewReader(body)).Decode(&er) != nil {
+		return e
+	}
+	if er.Code != nil {
+		e.Code = *er.Code
+	}
+	e.Message = er.Error
+	if er.Message != nil {
+		e.UserMessage = *er.Message
+	}
+	if er.Details != nil {
+		e.Details = *er.Details
+	}
+	return e
+}
+
+// retryAfter parses Retry-After given in seconds or as an HTTP date.
+func retryAfter(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(s, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
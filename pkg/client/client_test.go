@@ -0,0 +1,149 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// gateway answers with the statuses in order, repeating the last one, and
+// records the requests it saw.
+type gateway struct {
+	mu       sync.Mutex
+	statuses []int
+	keys     []string
+	bodies   []string
+	headers  http.Header
+}
+
+func (g *gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	body, _ := io.ReadAll(r.Body)
+	g.keys = append(g.keys, r.Header.Get(HeaderIdempotencyKey))
+	g.bodies = append(g.bodies, string(body))
+	g.headers = r.Header.Clone()
+	status := g.statuses[min(len(g.keys), len(g.statuses))-1]
+	w.Header().Set("Content-Type", "application/json")
+	switch status {
+	case http.StatusCreated:
+		w.WriteHeader(status)
+		io.WriteString(w, `{"order":{"order_id":"o-1","amount":"100","currency":"RUB","description":"book"},"user_id":"u-1"}`)
+	case http.StatusTooManyRequests:
+		w.Header().Set("Retry-After", "7")
+		w.WriteHeader(status)
+		io.WriteString(w, `{"error":"rate limit exceeded","code":"rate_limited","message":"Слишком много запросов."}`)
+	default:
+		w.WriteHeader(status)
+		io.WriteString(w, `{"error":"failure","code":"unavailable","details":{"shard":1}}`)
+	}
+}
+
+func newTestClient(t *testing.T, g *gateway, opts ...Option) (*ClientWithResponses, *[]time.Duration) {
+	t.Helper()
+	srv := httptest.NewServer(g)
+	t.Cleanup(srv.Close)
+	var waits []time.Duration
+	c, err := New(srv.URL, append(opts, func(c *config) {
+		c.newKey = func() string { return "generated" }
+		c.sleep = func(_ context.Context, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}
+	})...)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return c, &waits
+}
+
+func TestRetriesKeepIdempotencyKey(t *testing.T) {
+	g := &gateway{statuses: []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusCreated}}
+	c, waits := newTestClient(t, g, WithBearerToken("tok"))
+
+	res, err := c.CreateOrderWithResponse(context.Background(), &CreateOrderParams{}, CreateOrderJSONRequestBody{Description: "book"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if res.JSON201 == nil || res.JSON201.Order.OrderId != "o-1" {
+		t.Fatalf("CreateOrder() = %s, want order o-1", res.Body)
+	}
+	if len(g.keys) != 3 || g.keys[0] != "generated" || g.keys[1] != g.keys[0] || g.keys[2] != g.keys[0] {
+		t.Fatalf("idempotency keys = %q, want one generated key on every attempt", g.keys)
+	}
+	if g.bodies[2] != g.bodies[0] || g.bodies[0] == "" {
+		t.Fatalf("bodies = %q, want the same body on every attempt", g.bodies)
+	}
+	if got := g.headers.Get("Authorization"); got != "Bearer tok" {
+		t.Fatalf("Authorization = %q, want the bearer token", got)
+	}
+	if want := []time.Duration{defaultBackoff, 7 * time.Second}; len(*waits) != 2 || (*waits)[0] != want[0] || (*waits)[1] != want[1] {
+		t.Fatalf("waits = %v, want %v", *waits, want)
+	}
+
+	g2 := &gateway{statuses: []int{http.StatusServiceUnavailable, http.StatusCreated}}
+	c, _ = newTestClient(t, g2)
+	if _, err := c.CreateOrderWithResponse(context.Background(), &CreateOrderParams{IdempotencyKey: "mine"}, CreateOrderJSONRequestBody{Description: "book"}); err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if g2.keys[0] != "mine" || g2.keys[1] != "mine" {
+		t.Fatalf("idempotency keys = %q, want the caller's key kept", g2.keys)
+	}
+}
+
+func TestAPIError(t *testing.T) {
+	g := &gateway{statuses: []int{http.StatusTooManyRequests}}
+	c, waits := newTestClient(t, g, WithRetries(2, 0, 0))
+
+	_, err := c.CreateOrderWithResponse(context.Background(), &CreateOrderParams{}, CreateOrderJSONRequestBody{Description: "book"})
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("CreateOrder() error = %v, want an *APIError", err)
+	}
+	if apiErr.StatusCode != http.StatusTooManyRequests || apiErr.Code != "rate_limited" || apiErr.Message != "rate limit exceeded" ||
+		apiErr.UserMessage != "Слишком много запросов." || apiErr.RetryAfter != 7*time.Second || !apiErr.Temporary() {
+		t.Fatalf("APIError = %+v, want the rate_limited answer", apiErr)
+	}
+	if len(g.keys) != 3 || len(*waits) != 2 {
+		t.Fatalf("attempts = %d, waits = %d, want 3 and 2", len(g.keys), len(*waits))
+	}
+
+	// Other errors are returned at once.
+	g = &gateway{statuses: []int{http.StatusNotFound}}
+	c, _ = newTestClient(t, g)
+	_, err = c.GetOrderWithResponse(context.Background(), "o-1", &GetOrderParams{})
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound || apiErr.Details["shard"] != float64(1) {
+		t.Fatalf("GetOrder() error = %v, want a 404 APIError with details", err)
+	}
+	if len(g.keys) != 1 {
+		t.Fatalf("attempts = %d, want 1", len(g.keys))
+	}
+}
+
+func TestWritesWithoutKeyAreNotRetried(t *testing.T) {
+	g := &gateway{statuses: []int{http.StatusServiceUnavailable}}
+	c, _ := newTestClient(t, g)
+	// Without params the generated code sends no Idempotency-Key at all.
+	if _, err := c.CreateOrderWithResponse(context.Background(), nil, CreateOrderJSONRequestBody{Description: "book"}); err == nil {
+		t.Fatal("CreateOrder() succeeded against a failing gateway")
+	}
+	if len(g.keys) != 1 || g.keys[0] != "" {
+		t.Fatalf("idempotency keys = %q, want one attempt without a key", g.keys)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	for v, want := range map[string]time.Duration{"": 0, "3": 3 * time.Second, "-1": 0, "soon": 0} {
+		if got := retryAfter(v); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", v, got, want)
+		}
+	}
+	if got := retryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)); got < 59*time.Minute || got > time.Hour {
+		t.Errorf("retryAfter(date in an hour) = %v", got)
+	}
+}
@@ -0,0 +1,202 @@
+// Package contracttest checks the events a service exchanges against the
+// shared fixtures in api-files/contract/events/v1: the producer of an event
+// checks that it still writes the golden payload, the consumer that it reads
+// it, and the payloads of earlier versions in compat/, back. fields.lock pins
+// the numbers, names and types of the event fields; only additions are
+// compatible.
+package contracttest
+
+import (
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Suite is the fixture directory of a service's contract tests. With Update
+// set, the checks rewrite the golden files instead of failing.
+type Suite struct {
+	Dir    string
+	Update bool
+}
+
+// Golden reads the golden event name.json into m and makes it cover every
+// field, so a new field cannot skip the contract.
+func (s Suite) Golden(t *testing.T, name string, m proto.Message) {
+	t.Helper()
+	s.readEvent(t, name+".json", m)
+	r := m.ProtoReflect()
+	fields := r.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		if fd := fields.Get(i); !r.Has(fd) {
+			t.Errorf("golden %s leaves %s unset; give it a value", r.Descriptor().Name(), fd.Name())
+		}
+	}
+}
+
+// Produced compares the envelope b the producer wrote for the golden event
+// name with name.hex, or rewrites name.hex with Update.
+func (s Suite) Produced(t *testing.T, name string, b []byte, envelope func() proto.Message) {
+	t.Helper()
+	if s.Update {
+		s.writePayload(t, name+".hex", b)
+		return
+	}
+	got, want := envelope(), envelope()
+	if err := proto.Unmarshal(b, got); err != nil {
+		t.Fatalf("unmarshal produced envelope: %v", err)
+	}
+	if err := proto.Unmarshal(s.readPayload(t, name+".hex"), want); err != nil {
+		t.Fatalf("unmarshal golden envelope: %v", err)
+	}
+	if !proto.Equal(got, want) {
+		t.Fatalf("produced envelope differs from %s.hex; consumers may not read it\n got: %v\nwant: %v", name, got, want)
+	}
+}
+
+// Consumes decodes name.hex and every compat/name.*.hex with unmarshal and
+// compares each with its .json.
+func (s Suite) Consumes(t *testing.T, name string, event func() proto.Message, unmarshal func([]byte, proto.Message) error) {
+	t.Helper()
+	cases := []string{name}
+	compat, err := filepath.Glob(filepath.Join(s.Dir, "compat", name+".*.hex"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range compat {
+		cases = append(cases, filepath.Join("compat", strings.TrimSuffix(filepath.Base(path), ".hex")))
+	}
+
+	for _, c := range cases {
+		t.Run(c, func(t *testing.T) {
+			want, got := event(), event()
+			s.readEvent(t, c+".json", want)
+			if err := unmarshal(s.readPayload(t, c+".hex"), got); err != nil {
+				t.Fatalf("unmarshal %s: %v", c, err)
+			}
+			if !proto.Equal(got, want) {
+				t.Fatalf("decoded %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// FieldsLocked fails on fields of msgs that were removed, renamed or
+// retyped since fields.lock, and on new fields missing from it, which
+// Update adds.
+func (s Suite) FieldsLocked(t *testing.T, msgs ...protoreflect.MessageDescriptor) {
+	t.Helper()
+	path := filepath.Join(s.Dir, "fields.lock")
+	current := Fields(msgs)
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read fields.lock: %v", err)
+	}
+	locked := map[string]string{}
+	for _, line := range strings.Split(string(raw), "\n") {
+		if name, spec, ok := strings.Cut(line, " = "); ok {
+			locked[name] = spec
+		}
+	}
+
+	var broken, added []string
+	for name, spec := range locked {
+		switch got, ok := current[name]; {
+		case !ok:
+			broken = append(broken, fmt.Sprintf("%s was removed or renamed", name))
+		case got != spec:
+			broken = append(broken, fmt.Sprintf("%s changed from %q to %q", name, spec, got))
+		}
+	}
+	for name := range current {
+		if _, ok := locked[name]; !ok {
+			added = append(added, name)
+		}
+	}
+	sort.Strings(broken)
+	sort.Strings(added)
+	if len(broken) > 0 {
+		t.Fatalf("incompatible event schema change, reserve the old field and add a new one instead:\n%s", strings.Join(broken, "\n"))
+	}
+	if len(added) == 0 {
+		return
+	}
+	if !s.Update {
+		t.Fatalf("fields missing from fields.lock; add them to the golden events and rerun with -update:\n%s", strings.Join(added, "\n"))
+	}
+	lines := make([]string, 0, len(current))
+	for name, spec := range current {
+		lines = append(lines, name+" = "+spec)
+	}
+	sort.Strings(lines)
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// Fields describes every field of msgs, and every value of the enums they
+// use, as "full name" -> "number type".
+func Fields(msgs []protoreflect.MessageDescriptor) map[string]string {
+	fields := map[string]string{}
+	for _, md := range msgs {
+		for i := 0; i < md.Fields().Len(); i++ {
+			fd := md.Fields().Get(i)
+			typ := fd.Kind().String()
+			switch fd.Kind() {
+			case protoreflect.MessageKind:
+				typ = string(fd.Message().FullName())
+			case protoreflect.EnumKind:
+				ed := fd.Enum()
+				typ = string(ed.FullName())
+				for j := 0; j < ed.Values().Len(); j++ {
+					v := ed.Values().Get(j)
+					fields[string(ed.FullName())+"."+string(v.Name())] = fmt.Sprint(v.Number())
+				}
+			}
+			if fd.IsList() {
+				typ = "repeated " + typ
+			}
+			fields[string(fd.FullName())] = fmt.Sprintf("%d %s", fd.Number(), typ)
+		}
+	}
+	return fields
+}
+
+func (s Suite) readEvent(t *testing.T, name string, m proto.Message) {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	if err := protojson.Unmarshal(raw, m); err != nil {
+		t.Fatalf("parse %s: %v", name, err)
+	}
+}
+
+func (s Suite) readPayload(t *testing.T, name string) []byte {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(s.Dir, name))
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	b, err := hex.DecodeString(strings.TrimSpace(string(raw)))
+	if err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+	return b
+}
+
+func (s Suite) writePayload(t *testing.T, name string, b []byte) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(s.Dir, name), []byte(hex.EncodeToString(b)+"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,23 @@
+package contracttest
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+)
+
+func TestFields(t *testing.T) {
+	fields := Fields([]protoreflect.MessageDescriptor{(&eventsv1.PaymentResult{}).ProtoReflect().Descriptor()})
+	want := map[string]string{
+		"events.v1.PaymentResult.event_id":                            "1 string",
+		"events.v1.PaymentResult.status":                              "5 events.v1.PaymentResultStatus",
+		"events.v1.PaymentResultStatus.PAYMENT_RESULT_STATUS_SUCCESS": "1",
+	}
+	for name, spec := range want {
+		if fields[name] != spec {
+			t.Fatalf("Fields()[%s] = %q, want %q", name, fields[name], spec)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Package deadline carries the caller's time budget from the api-gateway
+// through gRPC down to the database.
+//
+// gRPC already forwards a client deadline to the server context. The server
+// interceptor adds what is missing at the edges: calls arriving without a
+// deadline get a default one, and calls whose caller has already gone are
+// rejected before any work starts. StatementTimeout turns what is left of the
+// budget into a Postgres statement_timeout, so the database stops work the
+// caller will no longer wait for even if pgx's cancel request is lost.
+package deadline
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryServerInterceptor bounds every call by def when the client set no
+// deadline; a zero def leaves such calls unbounded. Calls whose context is
+// already done fail with Canceled or DeadlineExceeded without reaching the
+// handler.
+func UnaryServerInterceptor(def time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if err := ctx.Err(); err != nil {
+			return nil, status.FromContextError(err).Err()
+		}
+		if _, ok := ctx.Deadline(); !ok && def > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, def)
+			defer cancel()
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StatementTimeout returns what is left of ctx's deadline, rounded up to a
+// whole millisecond, which is the statement_timeout resolution; Postgres
+// reads 0 as "no timeout", so a live deadline never maps to it. ok is false
+// when ctx has no deadline, and left is 0 when it has already passed.
+func StatementTimeout(ctx context.Context) (left time.Duration, ok bool) {
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return 0, false
+	}
+	left = time.Until(dl)
+	if left <= 0 {
+		return 0, true
+	}
+	return (left + time.Millisecond - 1).Truncate(time.Millisecond), true
+}
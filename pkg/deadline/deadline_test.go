@@ -0,0 +1,77 @@
+package deadline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func call(ctx context.Context, def time.Duration) (time.Duration, bool, error) {
+	var (
+		left time.Duration
+		has  bool
+	)
+	_, err := UnaryServerInterceptor(def)(ctx, nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		var dl time.Time
+		dl, has = ctx.Deadline()
+		left = time.Until(dl)
+		return nil, nil
+	})
+	return left, has, err
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	if _, has, err := call(context.Background(), 0); err != nil || has {
+		t.Fatalf("no default: deadline set = %v, err = %v; want none", has, err)
+	}
+	left, has, err := call(context.Background(), time.Minute)
+	if err != nil || !has || left > time.Minute || left < 59*time.Second {
+		t.Fatalf("default: left = %s (set %v, err %v), want about 1m", left, has, err)
+	}
+
+	// A client deadline wins over the default, even when it is longer.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Hour)
+	defer cancel()
+	if left, _, err := call(ctx, time.Minute); err != nil || left < 59*time.Minute {
+		t.Fatalf("client deadline: left = %s (err %v), want about 1h", left, err)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := call(ctx, time.Minute); status.Code(err) != codes.Canceled {
+		t.Fatalf("canceled caller: err = %v, want Canceled", err)
+	}
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if _, _, err := call(ctx, time.Minute); status.Code(err) != codes.DeadlineExceeded {
+		t.Fatalf("expired caller: err = %v, want DeadlineExceeded", err)
+	}
+}
+
+func TestStatementTimeout(t *testing.T) {
+	if _, ok := StatementTimeout(context.Background()); ok {
+		t.Fatal("StatementTimeout() without deadline reported ok")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if left, ok := StatementTimeout(ctx); !ok || left < time.Second || left > 2*time.Second {
+		t.Fatalf("StatementTimeout() = %s, %v; want about 2s", left, ok)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 100*time.Microsecond)
+	defer cancel()
+	if left, ok := StatementTimeout(ctx); !ok || (left != time.Millisecond && left != 0) {
+		t.Fatalf("StatementTimeout() = %s, %v; want 1ms or expired", left, ok)
+	}
+
+	ctx, cancel = context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if left, ok := StatementTimeout(ctx); !ok || left != 0 {
+		t.Fatalf("StatementTimeout() past deadline = %s, %v; want 0, true", left, ok)
+	}
+}
@@ -0,0 +1,69 @@
+// Package events is the wire format of the Kafka events: every event is
+// wrapped in an EventEnvelope that names its type, schema version and
+// producer.
+package events
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+)
+
+// Version is the payload schema version the services write and the newest
+// one they read. All event types share it until one of them needs a breaking
+// change.
+const Version = 1
+
+// ErrNewerVersion is returned by Unmarshal for an envelope of a version
+// newer than Version. The event is valid, just not readable by this build:
+// consumers must not commit past it, or it is lost once the reader is
+// upgraded.
+var ErrNewerVersion = errors.New("events: version newer than supported")
+
+// Event is a payload that can be wrapped in an EventEnvelope.
+type Event interface {
+	proto.Message
+	GetEventId() string
+	GetOccurredAt() *timestamppb.Timestamp
+}
+
+// Marshal wraps ev in an EventEnvelope from producer and marshals it for the
+// outbox. The envelope copies event_id and occurred_at from ev.
+func Marshal(producer string, ev Event) ([]byte, error) {
+	payload, err := anypb.New(ev)
+	if err != nil {
+		return nil, fmt.Errorf("wrap %s: %w", proto.MessageName(ev), err)
+	}
+	return proto.Marshal(&eventsv1.EventEnvelope{
+		EventId:    ev.GetEventId(),
+		Type:       string(proto.MessageName(ev)),
+		Version:    Version,
+		OccurredAt: ev.GetOccurredAt(),
+		Producer:   producer,
+		Payload:    payload,
+	})
+}
+
+// Unmarshal decodes a Kafka message value into ev. Besides envelopes it
+// accepts bare events, the format written before the envelope, so messages
+// already in the topics stay readable.
+func Unmarshal(value []byte, ev proto.Message) error {
+	var env eventsv1.EventEnvelope
+	if err := proto.Unmarshal(value, &env); err != nil {
+		return err
+	}
+	if env.GetPayload() == nil {
+		slog.Debug("decoding bare event", "type", proto.MessageName(ev))
+		return proto.Unmarshal(value, ev)
+	}
+	if env.GetVersion() > Version {
+		return fmt.Errorf("%w: %s version %d, supported %d", ErrNewerVersion, env.GetType(), env.GetVersion(), Version)
+	}
+	return env.GetPayload().UnmarshalTo(ev)
+}
@@ -0,0 +1,71 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+)
+
+func TestMarshalEnvelope(t *testing.T) {
+	ev := &eventsv1.PaymentRequested{EventId: "e-1", OccurredAt: timestamppb.Now(), OrderId: "o-1", UserId: "u-1", Amount: 100, PaymentId: "p-1"}
+	b, err := Marshal("orders-service", ev)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+
+	var env eventsv1.EventEnvelope
+	if err := proto.Unmarshal(b, &env); err != nil {
+		t.Fatalf("unmarshal envelope: %v", err)
+	}
+	if env.GetEventId() != "e-1" || env.GetType() != "events.v1.PaymentRequested" || env.GetVersion() != Version || env.GetProducer() != "orders-service" || !proto.Equal(env.GetOccurredAt(), ev.GetOccurredAt()) {
+		t.Fatalf("envelope = %v", &env)
+	}
+
+	var got eventsv1.PaymentRequested
+	if err := Unmarshal(b, &got); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !proto.Equal(&got, ev) {
+		t.Fatalf("payload = %v, want %v", &got, ev)
+	}
+}
+
+func TestUnmarshalBare(t *testing.T) {
+	for _, ev := range []proto.Message{
+		&eventsv1.PaymentRequested{EventId: "e-1", OccurredAt: timestamppb.Now(), OrderId: "o-1", UserId: "u-1", Amount: 100, PaymentId: "p-1"},
+		&eventsv1.PaymentResult{EventId: "e-2", OccurredAt: timestamppb.Now(), OrderId: "o-1", UserId: "u-1", Status: eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS, Reason: "not enough funds", PaymentId: "p-1", Amount: 100},
+		&eventsv1.AccountCreated{EventId: "e-3", OccurredAt: timestamppb.Now(), UserId: "u-1"},
+	} {
+		b, err := proto.Marshal(ev)
+		if err != nil {
+			t.Fatalf("marshal bare %T: %v", ev, err)
+		}
+		got := ev.ProtoReflect().New().Interface()
+		if err := Unmarshal(b, got); err != nil {
+			t.Fatalf("Unmarshal bare %T: %v", ev, err)
+		}
+		if !proto.Equal(got, ev) {
+			t.Fatalf("bare %T = %v, want %v", ev, got, ev)
+		}
+	}
+}
+
+func TestUnmarshalRejects(t *testing.T) {
+	payload, err := anypb.New(&eventsv1.AccountCreated{EventId: "e-1", UserId: "u-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrongType, _ := proto.Marshal(&eventsv1.EventEnvelope{Type: "events.v1.AccountCreated", Version: Version, Payload: payload})
+	if err := Unmarshal(wrongType, &eventsv1.PaymentResult{}); err == nil {
+		t.Fatal("AccountCreated decoded as PaymentResult")
+	}
+	newer, _ := proto.Marshal(&eventsv1.EventEnvelope{Type: "events.v1.AccountCreated", Version: Version + 1, Payload: payload})
+	if err := Unmarshal(newer, &eventsv1.AccountCreated{}); !errors.Is(err, ErrNewerVersion) {
+		t.Fatalf("newer version error = %v, want ErrNewerVersion", err)
+	}
+}
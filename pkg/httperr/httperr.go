@@ -0,0 +1,186 @@
+// Package httperr renders errors as the JSON error bodies of the REST API.
+//
+// The api-gateway and the services' own grpc-gateway endpoints both use it, so
+// a gRPC status maps to the same HTTP status and body whichever of them
+// answers:
+//
+//	{"error": "amount must be > 0", "code": "invalid_argument", "user_id": "u-1"}
+//
+// error is a developer-facing English message; code is a stable
+// machine-readable identifier that clients can branch on or localize.
+// Errors about a particular resource add details, e.g. the order a duplicate
+// refers to:
+//
+//	{"error": "...", "code": "duplicate_order", "details": {"order_id": "..."}}
+package httperr
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// RetryAfter is the Retry-After value, in seconds, sent with 429 responses.
+const RetryAfter = "60"
+
+// StatusClientClosedRequest is the non-standard status (nginx's 499) for a
+// request whose client went away before the answer was ready. Nobody reads
+// the body; the status keeps such requests out of the 5xx error rate.
+const StatusClientClosedRequest = 499
+
+// Error codes. Write and WriteGRPC derive a generic code from the status;
+// callers with a more specific one use WriteCode.
+const (
+	CodeInvalidArgument    = "invalid_argument"
+	CodeFailedPrecondition = "failed_precondition"
+	CodeUnauthenticated    = "unauthenticated"
+	CodePermissionDenied   = "permission_denied"
+	CodeNotFound           = "not_found"
+	CodeConflict           = "conflict"
+	CodePayloadTooLarge    = "payload_too_large"
+	CodeRateLimited        = "rate_limited"
+	CodeUnavailable        = "unavailable"
+	CodeTimeout            = "timeout"
+	CodeCanceled           = "canceled"
+	CodeInternal           = "internal"
+)
+
+// Body is the JSON error response. Message is a localized, end-user message
+// filled in by the api-gateway; it is empty elsewhere.
+type Body struct {
+	UserID  string `json:"user_id,omitempty"`
+	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+	// Details is structured data for the code, e.g. the id of a conflicting
+	// resource.
+	Details map[string]string `json:"details,omitempty"`
+}
+
+// Status maps a gRPC code to the HTTP status the REST API answers with.
+func Status(code codes.Code) int {
+	switch code {
+	case codes.InvalidArgument, codes.FailedPrecondition:
+		return http.StatusBadRequest
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.Canceled:
+		return StatusClientClosedRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// CodeForGRPC returns the error code for a gRPC code.
+func CodeForGRPC(code codes.Code) string {
+	switch code {
+	case codes.InvalidArgument:
+		return CodeInvalidArgument
+	case codes.FailedPrecondition:
+		return CodeFailedPrecondition
+	case codes.NotFound:
+		return CodeNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return CodeConflict
+	case codes.Unauthenticated:
+		return CodeUnauthenticated
+	case codes.PermissionDenied:
+		return CodePermissionDenied
+	case codes.ResourceExhausted:
+		return CodeRateLimited
+	case codes.Unavailable:
+		return CodeUnavailable
+	case codes.DeadlineExceeded:
+		return CodeTimeout
+	case codes.Canceled:
+		return CodeCanceled
+	default:
+		return CodeInternal
+	}
+}
+
+// CodeForStatus returns the generic error code for an HTTP status.
+func CodeForStatus(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeInvalidArgument
+	case http.StatusUnauthorized:
+		return CodeUnauthenticated
+	case http.StatusForbidden:
+		return CodePermissionDenied
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusRequestEntityTooLarge:
+		return CodePayloadTooLarge
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	case StatusClientClosedRequest:
+		return CodeCanceled
+	default:
+		return CodeInternal
+	}
+}
+
+// Write writes an error body with the given HTTP status and the generic code
+// for it. userID is omitted when empty.
+func Write(w http.ResponseWriter, userID string, code int, message string) {
+	WriteCode(w, userID, code, CodeForStatus(code), message)
+}
+
+// WriteCode writes an error body with an explicit error code.
+func WriteCode(w http.ResponseWriter, userID string, status int, code, message string) {
+	WriteDetails(w, userID, status, code, message, nil)
+}
+
+// WriteDetails is WriteCode with details; nil details are omitted.
+func WriteDetails(w http.ResponseWriter, userID string, status int, code, message string, details map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(Body{UserID: userID, Error: message, Code: code, Details: details})
+}
+
+// WriteGRPC writes err, which should be a gRPC status error, as an error
+// body. Errors without a status are reported as a bare 500 so that internal
+// details do not leak.
+func WriteGRPC(w http.ResponseWriter, userID string, err error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		Write(w, userID, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if st.Code() == codes.ResourceExhausted {
+		w.Header().Set("Retry-After", RetryAfter)
+	}
+	code, details := CodeForGRPC(st.Code()), map[string]string(nil)
+	// A google.rpc.ErrorInfo narrows the generic code down to its reason
+	// (DUPLICATE_ORDER -> duplicate_order) and passes its metadata on.
+	for _, d := range st.Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetReason() != "" {
+			code, details = strings.ToLower(info.GetReason()), info.GetMetadata()
+			break
+		}
+	}
+	WriteDetails(w, userID, Status(st.Code()), code, st.Message(), details)
+}
@@ -0,0 +1,86 @@
+package httperr
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		code     codes.Code
+		want     int
+		wantCode string
+	}{
+		{codes.InvalidArgument, http.StatusBadRequest, CodeInvalidArgument},
+		{codes.FailedPrecondition, http.StatusBadRequest, CodeFailedPrecondition},
+		{codes.NotFound, http.StatusNotFound, CodeNotFound},
+		{codes.AlreadyExists, http.StatusConflict, CodeConflict},
+		{codes.Aborted, http.StatusConflict, CodeConflict},
+		{codes.Unauthenticated, http.StatusUnauthorized, CodeUnauthenticated},
+		{codes.PermissionDenied, http.StatusForbidden, CodePermissionDenied},
+		{codes.ResourceExhausted, http.StatusTooManyRequests, CodeRateLimited},
+		{codes.Unavailable, http.StatusServiceUnavailable, CodeUnavailable},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout, CodeTimeout},
+		{codes.Canceled, StatusClientClosedRequest, CodeCanceled},
+		{codes.Internal, http.StatusInternalServerError, CodeInternal},
+	}
+
+	for _, tt := range tests {
+		if got := Status(tt.code); got != tt.want {
+			t.Fatalf("Status(%s) = %d, want %d", tt.code.String(), got, tt.want)
+		}
+		if got := CodeForGRPC(tt.code); got != tt.wantCode {
+			t.Fatalf("CodeForGRPC(%s) = %q, want %q", tt.code.String(), got, tt.wantCode)
+		}
+		// FailedPrecondition shares 400 with InvalidArgument.
+		if got := CodeForStatus(tt.want); tt.code != codes.FailedPrecondition && got != tt.wantCode {
+			t.Fatalf("CodeForStatus(%d) = %q, want %q", tt.want, got, tt.wantCode)
+		}
+	}
+}
+
+func TestWriteGRPC(t *testing.T) {
+	rec := httptest.NewRecorder()
+	WriteGRPC(rec, "u-1", status.Error(codes.ResourceExhausted, "too many top-ups"))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") != RetryAfter {
+		t.Fatalf("status = %d, Retry-After = %q; want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+	var body Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(body, Body{UserID: "u-1", Error: "too many top-ups", Code: CodeRateLimited}) {
+		t.Fatalf("body = %+v", body)
+	}
+
+	st, err := status.New(codes.AlreadyExists, "order duplicates a recent one").WithDetails(&errdetails.ErrorInfo{
+		Reason:   "DUPLICATE_ORDER",
+		Metadata: map[string]string{"order_id": "o-1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec = httptest.NewRecorder()
+	WriteGRPC(rec, "u-1", st.Err())
+	body = Body{}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Body{UserID: "u-1", Error: "order duplicates a recent one", Code: "duplicate_order", Details: map[string]string{"order_id": "o-1"}}); rec.Code != http.StatusConflict || !reflect.DeepEqual(body, want) {
+		t.Fatalf("ErrorInfo: status = %d, body = %+v; want 409 %+v", rec.Code, body, want)
+	}
+
+	rec = httptest.NewRecorder()
+	WriteGRPC(rec, "", errors.New("pq: connection refused"))
+	if rec.Code != http.StatusInternalServerError || rec.Body.String() != "{\"error\":\"internal error\",\"code\":\"internal\"}\n" {
+		t.Fatalf("plain error: status = %d, body = %s", rec.Code, rec.Body)
+	}
+}
@@ -0,0 +1,131 @@
+// Package idempotency makes retried requests safe by replaying the stored
+// response of the first attempt.
+//
+// Every service keeps one table of the same shape:
+//
+//	scope, user_id, idempotency_key  -- primary key
+//	request_hash                     -- sha256 of the request
+//	status                           -- IN_PROGRESS or COMPLETED
+//	response                         -- marshaled response of the first attempt
+//
+// Do claims the key, runs the operation and stores its response, all inside
+// the caller's transaction, so a failed operation rolls the claim back and the
+// key can be used again. A concurrent attempt with the same key blocks on the
+// uncommitted claim and then replays the committed response.
+//
+// Keys carried over from the tables used before this one have no request
+// hash and no response: they only block reuse (ErrUsed). A Sweeper deletes
+// keys after a retention period.
+package idempotency
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Status of a stored key.
+type Status string
+
+const (
+	StatusInProgress Status = "IN_PROGRESS"
+	StatusCompleted  Status = "COMPLETED"
+)
+
+// Errors are gRPC status errors so handlers can return them as is.
+var (
+	ErrMismatch   = status.Error(codes.FailedPrecondition, "idempotency key reuse with different parameters")
+	ErrInProgress = status.Error(codes.Aborted, "request with this idempotency key is in progress")
+	ErrUsed       = status.Error(codes.FailedPrecondition, "idempotency key was used by an earlier request whose response is not stored")
+)
+
+// Key identifies a request. Scope is the operation (e.g. "orders.CreateOrder"),
+// so the same client key may be used for different operations.
+type Key struct {
+	Scope  string
+	UserID string
+	Key    string
+}
+
+// Record is a stored key.
+type Record struct {
+	RequestHash []byte
+	Status      Status
+	Response    []byte
+}
+
+// Store persists keys. Implementations run on the caller's transaction.
+type Store interface {
+	// Claim stores key as IN_PROGRESS with requestHash and reports whether it
+	// was new; an existing key is left untouched.
+	Claim(ctx context.Context, key Key, requestHash []byte) (bool, error)
+	Get(ctx context.Context, key Key) (Record, error)
+	// Complete marks key COMPLETED with the marshaled response.
+	Complete(ctx context.Context, key Key, response []byte) error
+}
+
+// HashRequest returns the sha256 of the deterministic encoding of req.
+func HashRequest(req proto.Message) ([]byte, error) {
+	b, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("idempotency: marshal request: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return sum[:], nil
+}
+
+// Do runs fn once per key. The first call runs fn and stores its response;
+// later calls with the same request unmarshal the stored response into
+// replay and return it with replayed set. A different request under the same
+// key fails with ErrMismatch, and any request under a carried-over key with
+// ErrUsed.
+//
+// fn's error is returned as is and nothing is stored, so the caller's
+// transaction must roll back to release the key.
+func Do[T proto.Message](ctx context.Context, store Store, key Key, req proto.Message, replay T, fn func() (T, error)) (resp T, replayed bool, err error) {
+	hash, err := HashRequest(req)
+	if err != nil {
+		return resp, false, err
+	}
+	claimed, err := store.Claim(ctx, key, hash)
+	if err != nil {
+		return resp, false, fmt.Errorf("idempotency: claim: %w", err)
+	}
+	if !claimed {
+		rec, err := store.Get(ctx, key)
+		if err != nil {
+			return resp, false, fmt.Errorf("idempotency: get: %w", err)
+		}
+		if len(rec.RequestHash) == 0 {
+			return resp, false, ErrUsed
+		}
+		if !bytes.Equal(rec.RequestHash, hash) {
+			return resp, false, ErrMismatch
+		}
+		if rec.Status != StatusCompleted {
+			return resp, false, ErrInProgress
+		}
+		if err := proto.Unmarshal(rec.Response, replay); err != nil {
+			return resp, false, fmt.Errorf("idempotency: unmarshal response: %w", err)
+		}
+		return replay, true, nil
+	}
+
+	resp, err = fn()
+	if err != nil {
+		return resp, false, err
+	}
+	b, err := proto.Marshal(resp)
+	if err != nil {
+		return resp, false, fmt.Errorf("idempotency: marshal response: %w", err)
+	}
+	if err := store.Complete(ctx, key, b); err != nil {
+		return resp, false, fmt.Errorf("idempotency: complete: %w", err)
+	}
+	return resp, false, nil
+}
@@ -0,0 +1,132 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	records map[Key]Record
+}
+
+func newMemStore() *memStore {
+	return &memStore{records: map[Key]Record{}}
+}
+
+func (s *memStore) Claim(_ context.Context, key Key, hash []byte) (bool, error) {
+	if _, ok := s.records[key]; ok {
+		return false, nil
+	}
+	s.records[key] = Record{RequestHash: hash, Status: StatusInProgress}
+	return true, nil
+}
+
+func (s *memStore) Get(_ context.Context, key Key) (Record, error) {
+	return s.records[key], nil
+}
+
+func (s *memStore) Complete(_ context.Context, key Key, response []byte) error {
+	r := s.records[key]
+	r.Status, r.Response = StatusCompleted, response
+	s.records[key] = r
+	return nil
+}
+
+func TestDoReplaysStoredResponse(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	key := Key{Scope: "test.Op", UserID: "u-1", Key: "k-1"}
+	calls := 0
+	fn := func() (*wrapperspb.Int64Value, error) {
+		calls++
+		return wrapperspb.Int64(int64(100 * calls)), nil
+	}
+
+	first, replayed, err := Do(ctx, store, key, wrapperspb.String("req"), &wrapperspb.Int64Value{}, fn)
+	if err != nil || replayed || first.GetValue() != 100 {
+		t.Fatalf("Do() = (%v, %v, %v), want (100, false, nil)", first, replayed, err)
+	}
+	second, replayed, err := Do(ctx, store, key, wrapperspb.String("req"), &wrapperspb.Int64Value{}, fn)
+	if err != nil || !replayed || !proto.Equal(first, second) {
+		t.Fatalf("Do() replay = (%v, %v, %v), want (100, true, nil)", second, replayed, err)
+	}
+	if calls != 1 {
+		t.Fatalf("fn called %d times, want 1", calls)
+	}
+
+	// Same client key on another operation or user is independent.
+	other, replayed, err := Do(ctx, store, Key{Scope: "test.Other", UserID: "u-1", Key: "k-1"}, wrapperspb.String("req"), &wrapperspb.Int64Value{}, fn)
+	if err != nil || replayed || other.GetValue() != 200 {
+		t.Fatalf("Do() other scope = (%v, %v, %v), want (200, false, nil)", other, replayed, err)
+	}
+}
+
+func TestDoMismatch(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	key := Key{Scope: "test.Op", UserID: "u-1", Key: "k-1"}
+	fn := func() (*wrapperspb.Int64Value, error) { return wrapperspb.Int64(1), nil }
+
+	if _, _, err := Do(ctx, store, key, wrapperspb.String("a"), &wrapperspb.Int64Value{}, fn); err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	if _, _, err := Do(ctx, store, key, wrapperspb.String("b"), &wrapperspb.Int64Value{}, fn); !errors.Is(err, ErrMismatch) {
+		t.Fatalf("Do() with other request error = %v, want ErrMismatch", err)
+	}
+}
+
+func TestDoCarriedOverKey(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	key := Key{Scope: "test.Op", UserID: "u-1", Key: "k-1"}
+	store.records[key] = Record{Status: StatusCompleted}
+
+	_, _, err := Do(ctx, store, key, wrapperspb.String("a"), &wrapperspb.Int64Value{}, func() (*wrapperspb.Int64Value, error) {
+		t.Fatal("fn must not run for a carried-over key")
+		return nil, nil
+	})
+	if !errors.Is(err, ErrUsed) {
+		t.Fatalf("Do() error = %v, want ErrUsed", err)
+	}
+}
+
+func TestDoInProgress(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	key := Key{Scope: "test.Op", UserID: "u-1", Key: "k-1"}
+	hash, err := HashRequest(wrapperspb.String("a"))
+	if err != nil {
+		t.Fatalf("HashRequest() error: %v", err)
+	}
+	store.records[key] = Record{RequestHash: hash, Status: StatusInProgress}
+
+	_, _, err = Do(ctx, store, key, wrapperspb.String("a"), &wrapperspb.Int64Value{}, func() (*wrapperspb.Int64Value, error) {
+		t.Fatal("fn must not run for an in-progress key")
+		return nil, nil
+	})
+	if !errors.Is(err, ErrInProgress) {
+		t.Fatalf("Do() error = %v, want ErrInProgress", err)
+	}
+}
+
+func TestDoFailureDoesNotComplete(t *testing.T) {
+	ctx := context.Background()
+	store := newMemStore()
+	key := Key{Scope: "test.Op", UserID: "u-1", Key: "k-1"}
+	boom := errors.New("boom")
+
+	_, _, err := Do(ctx, store, key, wrapperspb.String("a"), &wrapperspb.Int64Value{}, func() (*wrapperspb.Int64Value, error) {
+		return nil, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Do() error = %v, want fn's error", err)
+	}
+	if store.records[key].Status == StatusCompleted {
+		t.Fatal("failed call stored a response")
+	}
+}
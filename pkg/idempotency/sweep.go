@@ -0,0 +1,80 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Execer is the part of *pgxpool.Pool that a Sweeper needs.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// sweepBatch bounds one DELETE so a large backlog does not hold locks for
+// long.
+const sweepBatch = 10000
+
+const sweepSQL = `DELETE FROM idempotency_keys
+WHERE ctid IN (SELECT ctid FROM idempotency_keys WHERE created_at < $1 LIMIT $2)`
+
+// maxSweepInterval caps the pause between two sweeps.
+const maxSweepInterval = time.Hour
+
+// Sweeper deletes keys created more than retention ago; a retry after that
+// runs the operation again.
+type Sweeper struct {
+	db        Execer
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewSweeper returns the sweeper of the idempotency_keys table in db.
+// service names the service in the logs.
+func NewSweeper(db Execer, retention time.Duration, service string) *Sweeper {
+	return &Sweeper{db: db, retention: retention, logger: slog.Default().With("service", service, "component", "idempotency")}
+}
+
+// Sweep deletes the keys created before now minus the retention, in batches,
+// and returns how many it deleted.
+func (s *Sweeper) Sweep(ctx context.Context, now time.Time) (int64, error) {
+	cutoff := now.Add(-s.retention)
+	var total int64
+	for {
+		tag, err := s.db.Exec(ctx, sweepSQL, cutoff, sweepBatch)
+		if err != nil {
+			return total, fmt.Errorf("idempotency: sweep keys: %w", err)
+		}
+		total += tag.RowsAffected()
+		if tag.RowsAffected() < sweepBatch {
+			return total, nil
+		}
+	}
+}
+
+// Run sweeps once per hour, or once per retention when that is shorter,
+// until ctx is done.
+func (s *Sweeper) Run(ctx context.Context) error {
+	interval := min(s.retention, maxSweepInterval)
+	s.logger.Info("idempotency key sweeper started", "retention", s.retention.String(), "interval", interval.String())
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		n, err := s.Sweep(ctx, time.Now())
+		if err != nil {
+			s.logger.Error("idempotency key sweep failed", "err", err)
+			continue
+		}
+		if n > 0 {
+			s.logger.Info("idempotency keys swept", "count", n)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package idempotency
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// batchExecer reports the given row counts for successive DELETEs.
+type batchExecer struct {
+	counts  []int64
+	cutoffs []time.Time
+}
+
+func (e *batchExecer) Exec(_ context.Context, _ string, args ...any) (pgconn.CommandTag, error) {
+	e.cutoffs = append(e.cutoffs, args[0].(time.Time))
+	n := e.counts[0]
+	e.counts = e.counts[1:]
+	return pgconn.NewCommandTag(fmt.Sprintf("DELETE %d", n)), nil
+}
+
+func TestSweeperDeletesInBatches(t *testing.T) {
+	db := &batchExecer{counts: []int64{sweepBatch, sweepBatch, 7}}
+	now := time.Now()
+	n, err := NewSweeper(db, 24*time.Hour, "test").Sweep(context.Background(), now)
+	if err != nil || n != 2*sweepBatch+7 {
+		t.Fatalf("Sweep() = %d, %v; want %d", n, err, 2*sweepBatch+7)
+	}
+	if len(db.cutoffs) != 3 || !db.cutoffs[0].Equal(now.Add(-24*time.Hour)) {
+		t.Fatalf("cutoffs = %v, want 3 batches before %s", db.cutoffs, now.Add(-24*time.Hour))
+	}
+}
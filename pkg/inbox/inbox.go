@@ -0,0 +1,276 @@
+// Package inbox makes Kafka consumers idempotent: a message is applied at
+// most once even though the broker delivers it at least once.
+//
+// Every service keeps one table of the same shape:
+//
+//	consumer, message_id  -- primary key
+//	correlation_id        -- request id of the API call behind the message
+//	topic, kafka_partition, kafka_offset
+//	                      -- where the message was read
+//	message_key, payload, headers
+//	                      -- the record itself, with StorePayloads only
+//	received_at           -- when the message was first claimed
+//	processed_at          -- NULL until MarkProcessed
+//
+// Begin claims the message and MarkProcessed records it as done, both on the
+// transaction that applies the message, so a failed handler rolls the claim
+// back and the redelivered message is applied again. A concurrent delivery
+// of the same message blocks on the uncommitted claim and then sees it
+// processed.
+//
+// Stored payloads let an operator load a message with Load and run it
+// through its handler again, for real or as a DryRun that rolls back; a
+// Purger drops them after a retention period.
+package inbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// metrics counts per consumer the messages claimed, processed, skipped as
+// duplicates and the inbox queries that failed.
+var metrics = expvar.NewMap("inbox")
+
+// ErrNotClaimed is returned by MarkProcessed for a message that Begin did
+// not claim in the transaction.
+var ErrNotClaimed = errors.New("inbox: message not claimed")
+
+// ErrNotFound is returned by Load for a message the inbox has no row for.
+var ErrNotFound = errors.New("inbox: message not found")
+
+// Message identifies a consumed message. ID is the event id, a UUID. The
+// Kafka fields describe the record it came in; Key, Payload and Headers are
+// only stored by an inbox with StorePayloads.
+type Message struct {
+	ID            string
+	CorrelationID string
+
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Payload   []byte
+	Headers   []Header
+}
+
+// Header is a Kafka record header.
+type Header struct {
+	Key   string `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// Inbox deduplicates the messages of one consumer. Consumers sharing a
+// database keep separate histories, so the same event may be applied once by
+// each of them.
+type Inbox struct {
+	consumer      string
+	storePayloads bool
+}
+
+// New returns the inbox of consumer, e.g. "payment_result".
+func New(consumer string) *Inbox {
+	return &Inbox{consumer: consumer}
+}
+
+// StorePayloads makes Begin keep the key, payload and headers of every
+// message it claims. Call it before the inbox is used.
+func (i *Inbox) StorePayloads(on bool) {
+	i.storePayloads = on
+}
+
+// Consumer returns the consumer name of the inbox.
+func (i *Inbox) Consumer() string {
+	return i.consumer
+}
+
+const claimSQL = `INSERT INTO inbox (consumer, message_id, correlation_id, topic, kafka_partition, kafka_offset, message_key, payload, headers)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+ON CONFLICT (consumer, message_id) DO UPDATE SET
+    correlation_id = EXCLUDED.correlation_id,
+    topic = EXCLUDED.topic,
+    kafka_partition = EXCLUDED.kafka_partition,
+    kafka_offset = EXCLUDED.kafka_offset,
+    message_key = EXCLUDED.message_key,
+    payload = EXCLUDED.payload,
+    headers = EXCLUDED.headers
+WHERE inbox.processed_at IS NULL
+RETURNING 1`
+
+const markSQL = `UPDATE inbox SET processed_at = now()
+WHERE consumer = $1 AND message_id = $2 AND processed_at IS NULL`
+
+// Begin claims msg in tx and reports whether it still has to be applied;
+// false means it was already processed and the caller should skip it. A
+// claim left unprocessed by an earlier committed transaction is taken over.
+func (i *Inbox) Begin(ctx context.Context, tx pgx.Tx, msg Message) (bool, error) {
+	if msg.ID == "" {
+		return false, errors.New("inbox: empty message id")
+	}
+	var (
+		key, payload []byte
+		headers      []byte
+	)
+	if i.storePayloads {
+		key, payload = msg.Key, msg.Payload
+		if payload == nil {
+			// NULL means not stored; an empty record still was.
+			payload = []byte{}
+		}
+		var err error
+		if headers, err = json.Marshal(msg.Headers); err != nil {
+			return false, fmt.Errorf("inbox: encode headers of %s: %w", msg.ID, err)
+		}
+	}
+	var one int
+	err := tx.QueryRow(ctx, claimSQL, i.consumer, msg.ID, msg.CorrelationID,
+		nullString(msg.Topic), int32(msg.Partition), msg.Offset, key, payload, headers).Scan(&one)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		metrics.Add(i.consumer+".duplicates", 1)
+		return false, nil
+	case err != nil:
+		metrics.Add(i.consumer+".errors", 1)
+		return false, fmt.Errorf("inbox: claim %s: %w", msg.ID, err)
+	}
+	metrics.Add(i.consumer+".claimed", 1)
+	return true, nil
+}
+
+// MarkProcessed records msg as applied. It must run on the transaction
+// that claimed msg with Begin, after the message's effects.
+func (i *Inbox) MarkProcessed(ctx context.Context, tx pgx.Tx, msg Message) error {
+	tag, err := tx.Exec(ctx, markSQL, i.consumer, msg.ID)
+	if err != nil {
+		metrics.Add(i.consumer+".errors", 1)
+		return fmt.Errorf("inbox: mark %s processed: %w", msg.ID, err)
+	}
+	if tag.RowsAffected() == 0 {
+		metrics.Add(i.consumer+".errors", 1)
+		return fmt.Errorf("%w: %s", ErrNotClaimed, msg.ID)
+	}
+	metrics.Add(i.consumer+".processed", 1)
+	return nil
+}
+
+func nullString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// Stored is a message as the inbox keeps it. Payload is nil when it was not
+// stored or has been purged.
+type Stored struct {
+	Message
+	Consumer    string
+	ReceivedAt  time.Time
+	ProcessedAt *time.Time
+}
+
+// Querier is the part of pgx.Tx and *pgxpool.Pool that Load needs.
+type Querier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+const loadSQL = `SELECT correlation_id, coalesce(topic, ''), coalesce(kafka_partition, 0), coalesce(kafka_offset, 0),
+    message_key, payload, headers, received_at, processed_at
+FROM inbox
+WHERE consumer = $1 AND message_id = $2`
+
+// Load returns message messageID of consumer, or ErrNotFound.
+func Load(ctx context.Context, q Querier, consumer, messageID string) (Stored, error) {
+	s := Stored{Consumer: consumer, Message: Message{ID: messageID}}
+	var (
+		correlationID *string
+		partition     int32
+		headers       []byte
+	)
+	err := q.QueryRow(ctx, loadSQL, consumer, messageID).Scan(&correlationID, &s.Topic, &partition, &s.Offset,
+		&s.Key, &s.Payload, &headers, &s.ReceivedAt, &s.ProcessedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Stored{}, fmt.Errorf("%w: %s", ErrNotFound, messageID)
+	}
+	if err != nil {
+		return Stored{}, fmt.Errorf("inbox: load %s: %w", messageID, err)
+	}
+	if correlationID != nil {
+		s.CorrelationID = *correlationID
+	}
+	s.Partition = int(partition)
+	if len(headers) > 0 {
+		if err := json.Unmarshal(headers, &s.Headers); err != nil {
+			return Stored{}, fmt.Errorf("inbox: decode headers of %s: %w", messageID, err)
+		}
+	}
+	return s, nil
+}
+
+// Execer is the part of *pgxpool.Pool that a Purger needs.
+type Execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+const purgeSQL = `UPDATE inbox SET message_key = NULL, payload = NULL, headers = NULL
+WHERE payload IS NOT NULL AND received_at < $1`
+
+// maxPurgeInterval caps the pause between two purges.
+const maxPurgeInterval = time.Hour
+
+// Purger drops the stored payloads of messages received more than retention
+// ago, in every consumer's rows; the rows themselves stay for deduplication.
+type Purger struct {
+	db        Execer
+	retention time.Duration
+	logger    *slog.Logger
+}
+
+// NewPurger returns the purger of the inbox table in db. service names the
+// service in the logs.
+func NewPurger(db Execer, retention time.Duration, service string) *Purger {
+	return &Purger{db: db, retention: retention, logger: slog.Default().With("service", service, "component", "inbox")}
+}
+
+// Purge drops the payloads received before now minus the retention and
+// returns how many messages lost theirs.
+func (p *Purger) Purge(ctx context.Context, now time.Time) (int64, error) {
+	tag, err := p.db.Exec(ctx, purgeSQL, now.Add(-p.retention))
+	if err != nil {
+		return 0, fmt.Errorf("inbox: purge payloads: %w", err)
+	}
+	metrics.Add("purged_payloads", tag.RowsAffected())
+	return tag.RowsAffected(), nil
+}
+
+// Run purges once per hour, or once per retention when that is shorter,
+// until ctx is done.
+func (p *Purger) Run(ctx context.Context) error {
+	interval := min(p.retention, maxPurgeInterval)
+	p.logger.Info("inbox payload purger started", "retention", p.retention.String(), "interval", interval.String())
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-t.C:
+		}
+		n, err := p.Purge(ctx, time.Now())
+		if err != nil {
+			p.logger.Error("inbox payload purge failed", "err", err)
+			continue
+		}
+		if n > 0 {
+			p.logger.Info("inbox payloads purged", "count", n)
+		}
+	}
+}
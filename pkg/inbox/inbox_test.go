@@ -0,0 +1,215 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// memTx is a pgx.Tx over an in-memory inbox table. Only the statements of
+// this package are understood; everything else panics on the nil Tx.
+type memTx struct {
+	pgx.Tx
+	// processed is keyed by consumer and message id; false is a claim.
+	processed map[[2]string]bool
+	// claims holds the arguments of the last claim of each message.
+	claims map[[2]string][]any
+	now    time.Time
+	fail   error
+}
+
+func newMemTx() *memTx {
+	return &memTx{processed: map[[2]string]bool{}, claims: map[[2]string][]any{}, now: time.Now()}
+}
+
+type row struct {
+	vals []any
+	err  error
+}
+
+func (r row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	for i, v := range r.vals {
+		if v != nil {
+			reflect.ValueOf(dest[i]).Elem().Set(reflect.ValueOf(v))
+		}
+	}
+	return nil
+}
+
+func (t *memTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	if t.fail != nil {
+		return row{err: t.fail}
+	}
+	key := [2]string{args[0].(string), args[1].(string)}
+	switch sql {
+	case claimSQL:
+		if t.processed[key] {
+			return row{err: pgx.ErrNoRows}
+		}
+		t.processed[key] = false
+		t.claims[key] = args
+		return row{vals: []any{1}}
+	case loadSQL:
+		c, ok := t.claims[key]
+		if !ok {
+			return row{err: pgx.ErrNoRows}
+		}
+		correlationID := c[2].(string)
+		topic := ""
+		if c[3] != nil {
+			topic = *c[3].(*string)
+		}
+		var processedAt *time.Time
+		if t.processed[key] {
+			processedAt = &t.now
+		}
+		return row{vals: []any{&correlationID, topic, c[4], c[5], c[6], c[7], c[8], t.now, processedAt}}
+	}
+	panic("unexpected query: " + sql)
+}
+
+func (t *memTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if sql == purgeSQL {
+		n := 0
+		for _, c := range t.claims {
+			if c[7].([]byte) != nil && t.now.Before(args[0].(time.Time)) {
+				c[6], c[7], c[8] = []byte(nil), []byte(nil), []byte(nil)
+				n++
+			}
+		}
+		return pgconn.NewCommandTag(fmt.Sprintf("UPDATE %d", n)), nil
+	}
+	if sql != markSQL {
+		panic("unexpected statement: " + sql)
+	}
+	if t.fail != nil {
+		return pgconn.CommandTag{}, t.fail
+	}
+	key := [2]string{args[0].(string), args[1].(string)}
+	done, claimed := t.processed[key]
+	if !claimed || done {
+		return pgconn.NewCommandTag("UPDATE 0"), nil
+	}
+	t.processed[key] = true
+	return pgconn.NewCommandTag("UPDATE 1"), nil
+}
+
+func TestBeginSkipsProcessedMessages(t *testing.T) {
+	ctx := context.Background()
+	tx := newMemTx()
+	in := New("payment_result")
+	msg := Message{ID: "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01", CorrelationID: "req-1"}
+
+	fresh, err := in.Begin(ctx, tx, msg)
+	if err != nil || !fresh {
+		t.Fatalf("Begin() = %v, %v; want a fresh message", fresh, err)
+	}
+	// A claim that was never marked processed is taken over.
+	if fresh, err := in.Begin(ctx, tx, msg); err != nil || !fresh {
+		t.Fatalf("Begin() of an unprocessed claim = %v, %v; want it claimed again", fresh, err)
+	}
+	if err := in.MarkProcessed(ctx, tx, msg); err != nil {
+		t.Fatalf("MarkProcessed() error: %v", err)
+	}
+	if fresh, err := in.Begin(ctx, tx, msg); err != nil || fresh {
+		t.Fatalf("Begin() after MarkProcessed = %v, %v; want a duplicate", fresh, err)
+	}
+
+	// Consumers keep separate histories.
+	if fresh, err := New("payment_requested").Begin(ctx, tx, msg); err != nil || !fresh {
+		t.Fatalf("Begin() for another consumer = %v, %v; want a fresh message", fresh, err)
+	}
+}
+
+func TestMarkProcessedRequiresClaim(t *testing.T) {
+	ctx := context.Background()
+	in := New("payment_result")
+	err := in.MarkProcessed(ctx, newMemTx(), Message{ID: "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01"})
+	if !errors.Is(err, ErrNotClaimed) {
+		t.Fatalf("MarkProcessed() error = %v, want ErrNotClaimed", err)
+	}
+}
+
+func TestInboxErrors(t *testing.T) {
+	ctx := context.Background()
+	in := New("payment_result")
+	if _, err := in.Begin(ctx, newMemTx(), Message{}); err == nil {
+		t.Fatal("Begin() with an empty id succeeded")
+	}
+
+	tx := newMemTx()
+	tx.fail = fmt.Errorf("connection reset")
+	if _, err := in.Begin(ctx, tx, Message{ID: "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01"}); !errors.Is(err, tx.fail) {
+		t.Fatalf("Begin() error = %v, want the query error", err)
+	}
+	if err := in.MarkProcessed(ctx, tx, Message{ID: "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01"}); !errors.Is(err, tx.fail) {
+		t.Fatalf("MarkProcessed() error = %v, want the query error", err)
+	}
+}
+
+func TestStorePayloads(t *testing.T) {
+	ctx := context.Background()
+	tx := newMemTx()
+	msg := Message{
+		ID: "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01", CorrelationID: "req-1",
+		Topic: "payments.payment_result", Partition: 3, Offset: 42,
+		Key: []byte("order-1"), Payload: []byte(`{"status":"SUCCEEDED"}`),
+		Headers: []Header{{Key: "traceparent", Value: []byte("00-abc-01")}},
+	}
+
+	plain := New("payment_result")
+	if _, err := plain.Begin(ctx, tx, msg); err != nil {
+		t.Fatalf("Begin() error: %v", err)
+	}
+	got, err := Load(ctx, tx, "payment_result", msg.ID)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if got.Topic != msg.Topic || got.Partition != 3 || got.Offset != 42 || got.CorrelationID != "req-1" {
+		t.Fatalf("Load() = %+v, want the record position", got)
+	}
+	if got.Payload != nil || got.Key != nil || got.Headers != nil {
+		t.Fatalf("Load() = %+v, want no payload without StorePayloads", got)
+	}
+
+	storing := New("payment_result")
+	storing.StorePayloads(true)
+	if _, err := storing.Begin(ctx, tx, msg); err != nil {
+		t.Fatalf("Begin() error: %v", err)
+	}
+	if err := storing.MarkProcessed(ctx, tx, msg); err != nil {
+		t.Fatalf("MarkProcessed() error: %v", err)
+	}
+	got, err = Load(ctx, tx, "payment_result", msg.ID)
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if !reflect.DeepEqual(got.Message, msg) || got.ProcessedAt == nil {
+		t.Fatalf("Load() = %+v, want %+v processed", got, msg)
+	}
+
+	if _, err := Load(ctx, tx, "payment_requested", msg.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Load() of another consumer error = %v, want ErrNotFound", err)
+	}
+
+	p := NewPurger(tx, time.Hour, "test")
+	if n, err := p.Purge(ctx, tx.now.Add(time.Minute)); err != nil || n != 0 {
+		t.Fatalf("Purge() within retention = %d, %v; want nothing purged", n, err)
+	}
+	if n, err := p.Purge(ctx, tx.now.Add(2*time.Hour)); err != nil || n != 1 {
+		t.Fatalf("Purge() after retention = %d, %v; want 1", n, err)
+	}
+	got, _ = Load(ctx, tx, "payment_result", msg.ID)
+	if got.Payload != nil || got.Topic != msg.Topic {
+		t.Fatalf("Load() after purge = %+v, want the position without the payload", got)
+	}
+}
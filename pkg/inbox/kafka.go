@@ -0,0 +1,86 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrPayloadNotStored is returned for a message whose payload was never
+// stored (StorePayloads was off) or has been purged.
+var ErrPayloadNotStored = errors.New("inbox message payload not stored")
+
+// ErrDryRun rolls back the transaction of a dry run.
+var ErrDryRun = errors.New("dry run")
+
+// DryRun is what a consumer did with a stored message when it was run again
+// without committing anything. R is a row of the outbox of the service.
+type DryRun[R any] struct {
+	Message Stored
+	// Event is the decoded message, nil when it could not be decoded.
+	Event proto.Message
+	// Skipped is why the consumer dropped the message without effects.
+	Skipped string
+	// Err is the error the consumer failed with; the message would have
+	// been redelivered.
+	Err error
+	// Outbox holds the events the consumer would have published, in order.
+	Outbox []R
+}
+
+// Skip records why a dry run dropped its message; d may be nil.
+func (d *DryRun[R]) Skip(reason string) {
+	if d != nil {
+		d.Skipped = reason
+	}
+}
+
+// TxOutbox lists the outbox rows written by the current transaction.
+type TxOutbox[R any] interface {
+	ListTxOutbox(ctx context.Context) ([]R, error)
+}
+
+// RunDry runs process on q and collects the outbox events it wrote into d,
+// then fails with ErrDryRun so the transaction rolls back.
+func RunDry[Q TxOutbox[R], R any](ctx context.Context, q Q, d *DryRun[R], process func(Q) error) error {
+	if err := process(q); err != nil {
+		return err
+	}
+	rows, err := q.ListTxOutbox(ctx)
+	if err != nil {
+		return err
+	}
+	d.Outbox = rows
+	return ErrDryRun
+}
+
+// FromKafka describes the Kafka message m for the inbox.
+func FromKafka(m kafka.Message, id, correlationID string) Message {
+	msg := Message{
+		ID:            id,
+		CorrelationID: correlationID,
+		Topic:         m.Topic,
+		Partition:     m.Partition,
+		Offset:        m.Offset,
+		Key:           m.Key,
+		Payload:       m.Value,
+	}
+	for _, h := range m.Headers {
+		msg.Headers = append(msg.Headers, Header{Key: h.Key, Value: h.Value})
+	}
+	return msg
+}
+
+// KafkaMessage rebuilds the Kafka message s was claimed for.
+func KafkaMessage(s Stored) (kafka.Message, error) {
+	if s.Payload == nil {
+		return kafka.Message{}, ErrPayloadNotStored
+	}
+	m := kafka.Message{Topic: s.Topic, Partition: s.Partition, Offset: s.Offset, Key: s.Key, Value: s.Payload}
+	for _, h := range s.Headers {
+		m.Headers = append(m.Headers, kafka.Header{Key: h.Key, Value: h.Value})
+	}
+	return m, nil
+}
@@ -0,0 +1,62 @@
+package inbox
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestKafkaMessage(t *testing.T) {
+	m := kafka.Message{
+		Topic: "orders.payment_requested", Partition: 2, Offset: 99,
+		Key: []byte("order-1"), Value: []byte(`{"event_id":"e-1"}`),
+		Headers: []kafka.Header{{Key: "traceparent", Value: []byte("00-abc-01")}},
+	}
+	msg := FromKafka(m, "e-1", "req-1")
+	if msg.ID != "e-1" || msg.CorrelationID != "req-1" || msg.Partition != 2 || len(msg.Headers) != 1 {
+		t.Fatalf("FromKafka() = %+v", msg)
+	}
+	got, err := KafkaMessage(Stored{Message: msg})
+	if err != nil {
+		t.Fatalf("KafkaMessage() error: %v", err)
+	}
+	if !reflect.DeepEqual(got, m) {
+		t.Fatalf("KafkaMessage() = %+v, want %+v", got, m)
+	}
+
+	msg.Payload = nil
+	if _, err := KafkaMessage(Stored{Message: msg}); !errors.Is(err, ErrPayloadNotStored) {
+		t.Fatalf("KafkaMessage() without a payload error = %v, want ErrPayloadNotStored", err)
+	}
+}
+
+type fakeOutbox struct{ rows []string }
+
+func (f *fakeOutbox) ListTxOutbox(context.Context) ([]string, error) {
+	return f.rows, nil
+}
+
+func TestRunDry(t *testing.T) {
+	q := &fakeOutbox{}
+	d := &DryRun[string]{}
+	err := RunDry(context.Background(), q, d, func(q *fakeOutbox) error {
+		q.rows = append(q.rows, "order.paid")
+		return nil
+	})
+	if !errors.Is(err, ErrDryRun) {
+		t.Fatalf("RunDry() error = %v, want ErrDryRun", err)
+	}
+	if len(d.Outbox) != 1 || d.Outbox[0] != "order.paid" {
+		t.Fatalf("RunDry() outbox = %v, want [order.paid]", d.Outbox)
+	}
+
+	failed := errors.New("boom")
+	if err := RunDry(context.Background(), q, d, func(*fakeOutbox) error { return failed }); !errors.Is(err, failed) {
+		t.Fatalf("RunDry() error = %v, want the process error", err)
+	}
+	var none *DryRun[string]
+	none.Skip("nil dry run is ignored")
+}
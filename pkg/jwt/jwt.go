@@ -0,0 +1,119 @@
+// Package jwt issues and verifies the HS256 tokens the gateway and the
+// services exchange: user tokens carrying roles and service tokens
+// (pkg/svcauth), both signed with a shared secret.
+package jwt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
+// Role is a role granted by a user token.
+type Role string
+
+const (
+	RoleUser    Role = "user"
+	RoleSupport Role = "support"
+	RoleAdmin   Role = "admin"
+)
+
+// Claims are the JWT fields the services rely on. Tokens may carry either a
+// roles array or a single role.
+type Claims struct {
+	Subject   string `json:"sub"`
+	Roles     []Role `json:"roles,omitempty"`
+	Role      Role   `json:"role,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+	ExpiresAt int64  `json:"exp"`
+	// Anonymous marks a session token issued by the gateway's POST /session
+	// rather than by the identity provider.
+	Anonymous bool `json:"anon,omitempty"`
+}
+
+// HasAny reports whether the claims grant one of roles.
+func (c Claims) HasAny(roles []Role) bool {
+	for _, have := range c.Roles {
+		for _, want := range roles {
+			if have == want {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Privileged reports whether the caller may act on behalf of other users.
+func (c Claims) Privileged() bool {
+	return c.HasAny([]Role{RoleSupport, RoleAdmin})
+}
+
+type header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+}
+
+var enc = base64.RawURLEncoding
+
+// Verify checks an HS256 token signed with secret and returns its claims.
+// Tokens without exp are rejected.
+func Verify(token string, secret []byte, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Claims{}, ErrInvalidToken
+	}
+	var h header
+	if err := decodeSegment(parts[0], &h); err != nil || h.Alg != "HS256" {
+		return Claims{}, ErrInvalidToken
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, sign(parts[0]+"."+parts[1], secret)) {
+		return Claims{}, ErrInvalidToken
+	}
+	var c Claims
+	if err := decodeSegment(parts[1], &c); err != nil {
+		return Claims{}, ErrInvalidToken
+	}
+	if c.ExpiresAt == 0 || !now.Before(time.Unix(c.ExpiresAt, 0)) {
+		return Claims{}, ErrInvalidToken
+	}
+	if c.Role != "" {
+		c.Roles = append(c.Roles, c.Role)
+		c.Role = ""
+	}
+	return c, nil
+}
+
+// Sign issues an HS256 token for c.
+func Sign(c Claims, secret []byte) (string, error) {
+	h, err := json.Marshal(header{Alg: "HS256", Typ: "JWT"})
+	if err != nil {
+		return "", err
+	}
+	p, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	unsigned := enc.EncodeToString(h) + "." + enc.EncodeToString(p)
+	return unsigned + "." + enc.EncodeToString(sign(unsigned, secret)), nil
+}
+
+func sign(s string, secret []byte) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(s))
+	return m.Sum(nil)
+}
+
+func decodeSegment(s string, v any) error {
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+var secret = []byte("test-secret")
+
+func TestVerify(t *testing.T) {
+	now := time.Now()
+	token, err := Sign(Claims{Subject: "u-1", Role: RoleSupport, ExpiresAt: now.Add(time.Minute).Unix()}, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	c, err := Verify(token, secret, now)
+	if err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+	if c.Subject != "u-1" || !c.HasAny([]Role{RoleSupport}) || !c.Privileged() {
+		t.Fatalf("Verify() = %+v, want sub u-1 with support role", c)
+	}
+
+	if _, err := Verify(token, []byte("other"), now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify(wrong secret) error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := Verify(token, secret, now.Add(2*time.Minute)); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify(expired) error = %v, want ErrInvalidToken", err)
+	}
+	if _, err := Verify(token+"x", secret, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify(tampered) error = %v, want ErrInvalidToken", err)
+	}
+	parts := strings.Split(token, ".")
+	none := enc.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+	if _, err := Verify(none, secret, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify(alg none) error = %v, want ErrInvalidToken", err)
+	}
+	noExp, _ := Sign(Claims{Subject: "u-1", Roles: []Role{RoleUser}}, secret)
+	if _, err := Verify(noExp, secret, now); !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("Verify(no exp) error = %v, want ErrInvalidToken", err)
+	}
+	user, _ := Sign(Claims{Subject: "u-1", Roles: []Role{RoleUser}, ExpiresAt: now.Add(time.Minute).Unix()}, secret)
+	if c, err := Verify(user, secret, now); err != nil || c.Privileged() {
+		t.Fatalf("Verify(user) = %+v, %v; want an unprivileged user", c, err)
+	}
+}
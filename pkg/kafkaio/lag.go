@@ -0,0 +1,159 @@
+package kafkaio
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var lagMetrics = expvar.NewMap("consumer_lag")
+
+// LagReporter periodically compares the committed offsets of a consumer group
+// with the latest offsets of its topic and publishes the per-partition lag
+// in the consumer_lag expvar map, as "<group>/<topic>/<partition>".
+type LagReporter struct {
+	client    *kafka.Client
+	group     string
+	topic     string
+	interval  time.Duration
+	threshold int64
+	logger    *slog.Logger
+}
+
+// NewLagReporter reports the lag of group on topic every interval; a lag
+// above threshold, if positive, is logged as a warning to logger.
+func NewLagReporter(brokers []string, transport kafka.RoundTripper, group, topic string, interval time.Duration, threshold int64, logger *slog.Logger) *LagReporter {
+	logger.Info("lag reporter initialized", "group", group, "topic", topic, "interval", interval.String(), "threshold", threshold)
+	return &LagReporter{
+		client:    &kafka.Client{Addr: kafka.TCP(brokers...), Transport: transport, Timeout: 10 * time.Second},
+		group:     group,
+		topic:     topic,
+		interval:  interval,
+		threshold: threshold,
+		logger:    logger,
+	}
+}
+
+func (l *LagReporter) Run(ctx context.Context) error {
+	logger := l.logger
+	if l.interval <= 0 {
+		logger.Info("lag reporter disabled")
+		return nil
+	}
+	logger.Info("lag reporter run start", "group", l.group, "topic", l.topic)
+	ticker := time.NewTicker(l.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("lag reporter stopped")
+			return nil
+		case <-ticker.C:
+			if err := l.reportOnce(ctx); err != nil && ctx.Err() == nil {
+				logger.Error("lag report failed", "err", err, "group", l.group, "topic", l.topic)
+			}
+		}
+	}
+}
+
+func (l *LagReporter) reportOnce(ctx context.Context) error {
+	logger := l.logger
+
+	meta, err := l.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{l.topic}})
+	if err != nil {
+		return err
+	}
+	var partitions []int
+	for _, t := range meta.Topics {
+		if t.Name != l.topic {
+			continue
+		}
+		if t.Error != nil {
+			return t.Error
+		}
+		for _, p := range t.Partitions {
+			partitions = append(partitions, p.ID)
+		}
+	}
+	if len(partitions) == 0 {
+		return fmt.Errorf("topic %q has no partitions", l.topic)
+	}
+
+	committed, err := l.client.OffsetFetch(ctx, &kafka.OffsetFetchRequest{
+		GroupID: l.group,
+		Topics:  map[string][]int{l.topic: partitions},
+	})
+	if err != nil {
+		return err
+	}
+	if committed.Error != nil {
+		return committed.Error
+	}
+
+	requests := make([]kafka.OffsetRequest, 0, 2*len(partitions))
+	for _, p := range partitions {
+		requests = append(requests, kafka.FirstOffsetOf(p), kafka.LastOffsetOf(p))
+	}
+	offsets, err := l.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics: map[string][]kafka.OffsetRequest{l.topic: requests},
+	})
+	if err != nil {
+		return err
+	}
+
+	committedByPartition := make(map[int]int64, len(partitions))
+	for _, p := range committed.Topics[l.topic] {
+		if p.Error == nil {
+			committedByPartition[p.Partition] = p.CommittedOffset
+		}
+	}
+	firstByPartition := make(map[int]int64, len(partitions))
+	latestByPartition := make(map[int]int64, len(partitions))
+	for _, p := range offsets.Topics[l.topic] {
+		if p.Error == nil {
+			firstByPartition[p.Partition] = p.FirstOffset
+			latestByPartition[p.Partition] = p.LastOffset
+		}
+	}
+
+	var total int64
+	for partition, lag := range computeLag(committedByPartition, firstByPartition, latestByPartition) {
+		total += lag
+		lagMetrics.Set(l.group+"/"+l.topic+"/"+strconv.Itoa(partition), metricInt(lag))
+		if l.threshold > 0 && lag > l.threshold {
+			logger.Warn("consumer lag above threshold", "group", l.group, "topic", l.topic, "partition", partition, "lag", lag, "threshold", l.threshold)
+		}
+	}
+	logger.Debug("consumer lag reported", "group", l.group, "topic", l.topic, "partitions", len(partitions), "total_lag", total)
+	return nil
+}
+
+// computeLag returns latest-committed for every partition with a known latest
+// offset. A partition the group never committed on counts from its first
+// offset, since retention may have removed the messages before it.
+func computeLag(committed, first, latest map[int]int64) map[int]int64 {
+	lag := make(map[int]int64, len(latest))
+	for partition, end := range latest {
+		c := committed[partition]
+		if c < 0 {
+			c = first[partition]
+		}
+		if d := end - c; d > 0 {
+			lag[partition] = d
+		} else {
+			lag[partition] = 0
+		}
+	}
+	return lag
+}
+
+func metricInt(v int64) *expvar.Int {
+	i := new(expvar.Int)
+	i.Set(v)
+	return i
+}
@@ -0,0 +1,21 @@
+package kafkaio
+
+import "testing"
+
+func TestComputeLag(t *testing.T) {
+	committed := map[int]int64{0: 10, 1: -1, 2: 50, 4: -1}
+	// Retention has removed the first 100 messages of partition 4.
+	first := map[int]int64{0: 0, 1: 0, 2: 0, 3: 0, 4: 100}
+	latest := map[int]int64{0: 15, 1: 7, 2: 40, 3: 3, 4: 130}
+
+	got := computeLag(committed, first, latest)
+	want := map[int]int64{0: 5, 1: 7, 2: 0, 3: 3, 4: 30}
+	if len(got) != len(want) {
+		t.Fatalf("computeLag() = %v, want %v", got, want)
+	}
+	for p, lag := range want {
+		if got[p] != lag {
+			t.Fatalf("computeLag()[%d] = %d, want %d", p, got[p], lag)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package kafkaio
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// ReaderOptions tune the consumer group readers. Zero durations and
+// capacity keep the kafka-go defaults: 10s max wait, a queue of 100
+// messages, 30s rebalance and session timeouts and 3s heartbeats.
+type ReaderOptions struct {
+	// StartOffset is where a group without a committed offset starts on a
+	// partition: first (the oldest retained message) or last (only new
+	// ones). Committed offsets always win.
+	StartOffset       string
+	MaxWait           time.Duration
+	QueueCapacity     int
+	RebalanceTimeout  time.Duration
+	SessionTimeout    time.Duration
+	HeartbeatInterval time.Duration
+}
+
+// NewReader returns the reader of groupID on topic. Offsets are committed
+// explicitly after each message, and transactional batches are only read
+// once committed.
+func NewReader(brokers []string, dialer *kafka.Dialer, topic, groupID string, opts ReaderOptions) (*kafka.Reader, error) {
+	var start int64
+	switch strings.ToLower(opts.StartOffset) {
+	case "", "first":
+		start = kafka.FirstOffset
+	case "last":
+		start = kafka.LastOffset
+	default:
+		return nil, fmt.Errorf("kafka consumer start offset %q: want first or last", opts.StartOffset)
+	}
+	// kafka.NewReader panics on these rather than returning an error.
+	if opts.MaxWait < 0 || opts.QueueCapacity < 0 || opts.RebalanceTimeout < 0 || opts.SessionTimeout < 0 || opts.HeartbeatInterval < 0 {
+		return nil, fmt.Errorf("kafka consumer options must not be negative: %+v", opts)
+	}
+	cfg := kafka.ReaderConfig{
+		Brokers:           brokers,
+		Dialer:            dialer,
+		Topic:             topic,
+		GroupID:           groupID,
+		MinBytes:          1e3,
+		MaxBytes:          10e6,
+		StartOffset:       start,
+		MaxWait:           opts.MaxWait,
+		QueueCapacity:     opts.QueueCapacity,
+		RebalanceTimeout:  opts.RebalanceTimeout,
+		SessionTimeout:    opts.SessionTimeout,
+		HeartbeatInterval: opts.HeartbeatInterval,
+		CommitInterval:    0,
+		IsolationLevel:    kafka.ReadCommitted,
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return kafka.NewReader(cfg), nil
+}
@@ -0,0 +1,39 @@
+package kafkaio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestNewReader(t *testing.T) {
+	r, err := NewReader([]string{"broker:9092"}, nil, "orders", "group", ReaderOptions{StartOffset: "Last", MaxWait: time.Second, QueueCapacity: 10})
+	if err != nil {
+		t.Fatalf("NewReader() error: %v", err)
+	}
+	defer r.Close()
+	cfg := r.Config()
+	if cfg.StartOffset != kafka.LastOffset || cfg.MaxWait != time.Second || cfg.QueueCapacity != 10 {
+		t.Fatalf("reader = start %d, max wait %s, queue %d; want last, 1s, 10", cfg.StartOffset, cfg.MaxWait, cfg.QueueCapacity)
+	}
+	if cfg.IsolationLevel != kafka.ReadCommitted || cfg.CommitInterval != 0 {
+		t.Fatalf("reader = isolation %d, commit interval %s; want read committed, synchronous commits", cfg.IsolationLevel, cfg.CommitInterval)
+	}
+
+	r, err = NewReader([]string{"broker:9092"}, nil, "orders", "group", ReaderOptions{})
+	if err != nil {
+		t.Fatalf("NewReader(defaults) error: %v", err)
+	}
+	defer r.Close()
+	if r.Config().StartOffset != kafka.FirstOffset {
+		t.Fatalf("default start offset = %d, want first", r.Config().StartOffset)
+	}
+
+	if _, err := NewReader([]string{"broker:9092"}, nil, "orders", "group", ReaderOptions{StartOffset: "newest"}); err == nil {
+		t.Fatal("NewReader(newest) succeeded, want an error")
+	}
+	if _, err := NewReader([]string{"broker:9092"}, nil, "orders", "group", ReaderOptions{HeartbeatInterval: -time.Second}); err == nil {
+		t.Fatal("NewReader() with a negative heartbeat succeeded, want an error")
+	}
+}
@@ -0,0 +1,116 @@
+package kafkaio
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+)
+
+// Security describes how to authenticate against the Kafka brokers; every
+// service builds its dialer, transport and transactional producers from
+// it. The zero value means plaintext without auth.
+type Security struct {
+	TLS      bool
+	CAFile   string
+	CertFile string
+	KeyFile  string
+
+	SASLMechanism string // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	SASLUsername  string
+	SASLPassword  string
+}
+
+// Dialer returns the dialer for kafka.Reader.
+func (s Security) Dialer() (*kafka.Dialer, error) {
+	tlsCfg, mech, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		TLS:           tlsCfg,
+		SASLMechanism: mech,
+	}, nil
+}
+
+// Transport returns the transport for kafka.Writer and kafka.Client.
+func (s Security) Transport() (*kafka.Transport, error) {
+	tlsCfg, mech, err := s.build()
+	if err != nil {
+		return nil, err
+	}
+	return &kafka.Transport{TLS: tlsCfg, SASL: mech}, nil
+}
+
+// TxConfig returns the config of the transactional producer with id
+// transactionalID.
+func (s Security) TxConfig(brokers []string, transactionalID string) (kafkatx.Config, error) {
+	tlsCfg, _, err := s.build()
+	if err != nil {
+		return kafkatx.Config{}, err
+	}
+	return kafkatx.Config{
+		Brokers:         brokers,
+		TransactionalID: transactionalID,
+		TLS:             tlsCfg,
+		SASLMechanism:   s.SASLMechanism,
+		SASLUsername:    s.SASLUsername,
+		SASLPassword:    s.SASLPassword,
+	}, nil
+}
+
+func (s Security) build() (*tls.Config, sasl.Mechanism, error) {
+	var tlsCfg *tls.Config
+	if s.TLS {
+		tlsCfg = &tls.Config{MinVersion: tls.VersionTLS12}
+		if s.CAFile != "" {
+			pem, err := os.ReadFile(s.CAFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("read kafka ca file: %w", err)
+			}
+			pool := x509.NewCertPool()
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, nil, fmt.Errorf("kafka ca file %q has no certificates", s.CAFile)
+			}
+			tlsCfg.RootCAs = pool
+		}
+		if s.CertFile != "" || s.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(s.CertFile, s.KeyFile)
+			if err != nil {
+				return nil, nil, fmt.Errorf("load kafka client certificate: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+	}
+
+	var (
+		mech sasl.Mechanism
+		err  error
+	)
+	switch strings.ToUpper(s.SASLMechanism) {
+	case "":
+	case "PLAIN":
+		mech = plain.Mechanism{Username: s.SASLUsername, Password: s.SASLPassword}
+	case "SCRAM-SHA-256":
+		mech, err = scram.Mechanism(scram.SHA256, s.SASLUsername, s.SASLPassword)
+	case "SCRAM-SHA-512":
+		mech, err = scram.Mechanism(scram.SHA512, s.SASLUsername, s.SASLPassword)
+	default:
+		err = fmt.Errorf("unsupported kafka sasl mechanism %q", s.SASLMechanism)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return tlsCfg, mech, nil
+}
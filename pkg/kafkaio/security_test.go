@@ -0,0 +1,54 @@
+package kafkaio
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSecurityZeroValue(t *testing.T) {
+	tr, err := Security{}.Transport()
+	if err != nil {
+		t.Fatalf("Transport() error: %v", err)
+	}
+	if tr.TLS != nil || tr.SASL != nil {
+		t.Fatalf("Transport() = tls %v sasl %v, want plaintext", tr.TLS, tr.SASL)
+	}
+}
+
+func TestSecuritySASLMechanisms(t *testing.T) {
+	for _, name := range []string{"PLAIN", "scram-sha-256", "SCRAM-SHA-512"} {
+		d, err := Security{SASLMechanism: name, SASLUsername: "u", SASLPassword: "p"}.Dialer()
+		if err != nil {
+			t.Fatalf("Dialer(%s) error: %v", name, err)
+		}
+		if d.SASLMechanism == nil {
+			t.Fatalf("Dialer(%s) has no sasl mechanism", name)
+		}
+	}
+	if _, err := (Security{SASLMechanism: "GSSAPI"}).Dialer(); err == nil {
+		t.Fatal("Dialer(GSSAPI) expected error")
+	}
+}
+
+func TestSecurityTLS(t *testing.T) {
+	d, err := Security{TLS: true}.Dialer()
+	if err != nil {
+		t.Fatalf("Dialer() error: %v", err)
+	}
+	if d.TLS == nil || d.TLS.RootCAs != nil {
+		t.Fatalf("Dialer() tls = %v, want system roots", d.TLS)
+	}
+
+	if _, err := (Security{TLS: true, CAFile: filepath.Join(t.TempDir(), "missing.pem")}).Dialer(); err == nil {
+		t.Fatal("Dialer() with missing ca file expected error")
+	}
+
+	bad := filepath.Join(t.TempDir(), "bad.pem")
+	if err := os.WriteFile(bad, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (Security{TLS: true, CAFile: bad}).Dialer(); err == nil {
+		t.Fatal("Dialer() with invalid ca file expected error")
+	}
+}
@@ -0,0 +1,16 @@
+package kafkaio
+
+import (
+	"context"
+	"time"
+)
+
+// WithHandlerTimeout derives the context one consumed message is processed
+// under. Consumers otherwise run on the process-wide context, which has no
+// deadline; a non-positive timeout keeps that behavior.
+func WithHandlerTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
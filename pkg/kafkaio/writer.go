@@ -0,0 +1,46 @@
+// Package kafkaio builds the kafka-go writers and readers the services share,
+// from the options their config exposes.
+package kafkaio
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// WriterOptions tune the outbox writer. Zero fields keep the kafka-go
+// defaults: no compression, batches of 100 messages or 1 MiB, flushed after
+// 1s, and 10 attempts per batch. The writer is always synchronous: the
+// outbox marks a row sent once WriteMessages returns, so it must not return
+// before the broker has acknowledged the row.
+type WriterOptions struct {
+	Compression  string // none, gzip, snappy, lz4 or zstd
+	BatchSize    int
+	BatchBytes   int64
+	BatchTimeout time.Duration
+	MaxAttempts  int
+}
+
+// NewWriter returns the writer of the outbox publisher. It has no fixed
+// Topic, as every outbox row names its own, and hashes keys to partitions.
+func NewWriter(brokers []string, transport *kafka.Transport, opts WriterOptions) (*kafka.Writer, error) {
+	var codec kafka.Compression
+	if opts.Compression != "" {
+		if err := codec.UnmarshalText([]byte(strings.ToLower(opts.Compression))); err != nil {
+			return nil, fmt.Errorf("kafka writer compression %q: want none, gzip, snappy, lz4 or zstd", opts.Compression)
+		}
+	}
+	return &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		Compression:  codec,
+		BatchSize:    opts.BatchSize,
+		BatchBytes:   opts.BatchBytes,
+		BatchTimeout: opts.BatchTimeout,
+		MaxAttempts:  opts.MaxAttempts,
+		Transport:    transport,
+	}, nil
+}
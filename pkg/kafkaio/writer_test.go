@@ -0,0 +1,35 @@
+package kafkaio
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+func TestNewWriter(t *testing.T) {
+	w, err := NewWriter([]string{"broker:9092"}, nil, WriterOptions{Compression: "ZSTD", BatchSize: 500, BatchTimeout: 5 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("NewWriter() error: %v", err)
+	}
+	if w.Compression != kafka.Zstd || w.BatchSize != 500 || w.BatchTimeout != 5*time.Millisecond {
+		t.Fatalf("writer = %s, %d messages, %s; want zstd, 500, 5ms", w.Compression, w.BatchSize, w.BatchTimeout)
+	}
+	if w.Async || w.RequiredAcks != kafka.RequireAll {
+		t.Fatalf("writer async %v, acks %v; want sync writes acknowledged by all replicas", w.Async, w.RequiredAcks)
+	}
+	if w.Topic != "" {
+		t.Fatalf("writer Topic = %q, want none", w.Topic)
+	}
+
+	w, err = NewWriter([]string{"broker:9092"}, nil, WriterOptions{Compression: "none"})
+	if err != nil {
+		t.Fatalf("NewWriter(none) error: %v", err)
+	}
+	if w.Compression != 0 {
+		t.Fatalf("writer = %s, want uncompressed", w.Compression)
+	}
+	if _, err := NewWriter([]string{"broker:9092"}, nil, WriterOptions{Compression: "brotli"}); err == nil {
+		t.Fatal("NewWriter(brotli) succeeded, want an error")
+	}
+}
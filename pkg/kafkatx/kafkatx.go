@@ -0,0 +1,185 @@
+// Package kafkatx publishes batches of messages in Kafka transactions, so an
+// outbox can hand a batch to Kafka and mark it sent as one step.
+//
+// A Producer owns one transactional id. The broker fences the previous
+// producer of an id when a new one starts, which makes a publisher that
+// crashed mid-batch unable to commit anything afterwards: its open
+// transaction is aborted and readers with read_committed isolation never see
+// its records.
+package kafkatx
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+	"github.com/twmb/franz-go/pkg/sasl"
+	"github.com/twmb/franz-go/pkg/sasl/plain"
+	"github.com/twmb/franz-go/pkg/sasl/scram"
+)
+
+// Config of a Producer.
+type Config struct {
+	Brokers []string
+	// TransactionalID must be stable across restarts and used by one
+	// publisher at a time.
+	TransactionalID string
+	// Timeout is how long the broker lets a transaction stay open before
+	// aborting it; 0 keeps the client default of 40s.
+	Timeout time.Duration
+	// Compression is none (or empty), gzip, snappy, lz4 or zstd.
+	Compression string
+
+	TLS           *tls.Config
+	SASLMechanism string // PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512
+	SASLUsername  string
+	SASLPassword  string
+}
+
+// Message is one record of a transaction.
+type Message struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// Producer publishes batches in transactions. It is not safe for concurrent
+// use: a transactional id has at most one transaction open.
+type Producer struct {
+	cl *kgo.Client
+}
+
+// NewProducer connects the producer of cfg.TransactionalID. Keys are
+// partitioned like the Hash balancer of segmentio/kafka-go, so a key keeps
+// its partition, and with it its order, when a service switches between
+// plain and transactional publishing.
+func NewProducer(cfg Config) (*Producer, error) {
+	if len(cfg.Brokers) == 0 {
+		return nil, errors.New("kafkatx: no brokers")
+	}
+	if cfg.TransactionalID == "" {
+		return nil, errors.New("kafkatx: no transactional id")
+	}
+	mech, err := mechanism(cfg.SASLMechanism, cfg.SASLUsername, cfg.SASLPassword)
+	if err != nil {
+		return nil, err
+	}
+	codec, err := compression(cfg.Compression)
+	if err != nil {
+		return nil, err
+	}
+	opts := []kgo.Opt{
+		kgo.SeedBrokers(cfg.Brokers...),
+		kgo.TransactionalID(cfg.TransactionalID),
+		kgo.RequiredAcks(kgo.AllISRAcks()),
+		kgo.RecordPartitioner(kgo.StickyKeyPartitioner(kgo.SaramaCompatHasher(fnv32a))),
+		kgo.ProducerBatchCompression(codec),
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, kgo.TransactionTimeout(cfg.Timeout))
+	}
+	if cfg.TLS != nil {
+		opts = append(opts, kgo.DialTLSConfig(cfg.TLS))
+	}
+	if mech != nil {
+		opts = append(opts, kgo.SASL(mech))
+	}
+	cl, err := kgo.NewClient(opts...)
+	if err != nil {
+		return nil, fmt.Errorf("kafkatx: %w", err)
+	}
+	return &Producer{cl: cl}, nil
+}
+
+// Publish writes msgs in one transaction and commits it only after commit
+// returns nil, so commit can record the batch as sent in the caller's own
+// transaction and fail the whole publish if that fails.
+//
+// When some messages fail to produce, the transaction is aborted and
+// Publish returns their errors, one per message with nil for the others;
+// commit is not called, and the error is only set if the abort failed. Any
+// other failure, including commit's, aborts the transaction and is returned
+// as the error. Once commit has run, an error means the batch may or may not
+// have reached readers.
+func (p *Producer) Publish(ctx context.Context, msgs []Message, commit func() error) ([]error, error) {
+	if err := p.cl.BeginTransaction(); err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	records := make([]*kgo.Record, len(msgs))
+	for i, m := range msgs {
+		records[i] = &kgo.Record{Topic: m.Topic, Key: m.Key, Value: m.Value}
+	}
+	results := p.cl.ProduceSync(ctx, records...)
+	if results.FirstErr() != nil {
+		errs := make([]error, len(msgs))
+		for i, r := range results {
+			errs[i] = r.Err
+		}
+		return errs, p.abort(ctx)
+	}
+	if err := commit(); err != nil {
+		return nil, errors.Join(err, p.abort(ctx))
+	}
+	// A cancelled context would leave the outcome of the commit unknown.
+	if err := p.cl.EndTransaction(context.WithoutCancel(ctx), kgo.TryCommit); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil, nil
+}
+
+func (p *Producer) abort(ctx context.Context) error {
+	ctx = context.WithoutCancel(ctx)
+	if err := p.cl.AbortBufferedRecords(ctx); err != nil {
+		return fmt.Errorf("abort buffered records: %w", err)
+	}
+	if err := p.cl.EndTransaction(ctx, kgo.TryAbort); err != nil {
+		return fmt.Errorf("abort transaction: %w", err)
+	}
+	return nil
+}
+
+// Close disconnects the producer.
+func (p *Producer) Close() {
+	p.cl.Close()
+}
+
+func fnv32a(key []byte) uint32 {
+	h := fnv.New32a()
+	h.Write(key)
+	return h.Sum32()
+}
+
+func compression(name string) (kgo.CompressionCodec, error) {
+	switch strings.ToLower(name) {
+	case "", "none":
+		return kgo.NoCompression(), nil
+	case "gzip":
+		return kgo.GzipCompression(), nil
+	case "snappy":
+		return kgo.SnappyCompression(), nil
+	case "lz4":
+		return kgo.Lz4Compression(), nil
+	case "zstd":
+		return kgo.ZstdCompression(), nil
+	}
+	return kgo.CompressionCodec{}, fmt.Errorf("unsupported kafka compression %q", name)
+}
+
+func mechanism(name, username, password string) (sasl.Mechanism, error) {
+	switch strings.ToUpper(name) {
+	case "":
+		return nil, nil
+	case "PLAIN":
+		return plain.Auth{User: username, Pass: password}.AsMechanism(), nil
+	case "SCRAM-SHA-256":
+		return scram.Auth{User: username, Pass: password}.AsSha256Mechanism(), nil
+	case "SCRAM-SHA-512":
+		return scram.Auth{User: username, Pass: password}.AsSha512Mechanism(), nil
+	}
+	return nil, fmt.Errorf("unsupported kafka sasl mechanism %q", name)
+}
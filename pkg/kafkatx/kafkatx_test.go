@@ -0,0 +1,46 @@
+package kafkatx
+
+import (
+	"testing"
+
+	"github.com/twmb/franz-go/pkg/kgo"
+)
+
+// TestPartitioning pins keys to the partitions the kafka-go Hash balancer
+// picks for them out of six.
+func TestPartitioning(t *testing.T) {
+	hash := kgo.SaramaCompatHasher(fnv32a)
+	for key, want := range map[string]int{
+		"user-1":                               0,
+		"order-42":                             2,
+		"3f6c2a9e-2b7d-4e0c-9a51-7d1e2f3a4b5c": 2,
+		"a":                                    0,
+	} {
+		if got := hash([]byte(key), 6); got != want {
+			t.Errorf("partition of %q = %d, want %d", key, got, want)
+		}
+	}
+}
+
+func TestNewProducerConfig(t *testing.T) {
+	for _, cfg := range []Config{
+		{TransactionalID: "outbox-0"},
+		{Brokers: []string{"broker:9092"}},
+		{Brokers: []string{"broker:9092"}, TransactionalID: "outbox-0", SASLMechanism: "GSSAPI"},
+		{Brokers: []string{"broker:9092"}, TransactionalID: "outbox-0", Compression: "brotli"},
+	} {
+		if _, err := NewProducer(cfg); err == nil {
+			t.Errorf("NewProducer(%+v) succeeded, want an error", cfg)
+		}
+	}
+	for _, name := range []string{"", "none", "gzip", "snappy", "LZ4", "zstd"} {
+		if _, err := compression(name); err != nil {
+			t.Errorf("compression(%q) error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "PLAIN", "scram-sha-256", "SCRAM-SHA-512"} {
+		if _, err := mechanism(name, "u", "p"); err != nil {
+			t.Errorf("mechanism(%q) error: %v", name, err)
+		}
+	}
+}
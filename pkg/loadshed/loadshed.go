@@ -0,0 +1,255 @@
+// Package loadshed rejects excess requests before they queue up.
+//
+// A Limiter caps the number of requests in flight. The cap adapts to latency
+// in AIMD fashion: it grows by about one per limit's worth of fast requests
+// while the capacity is actually used, and shrinks by Backoff when a request
+// is much slower than the baseline latency or fails with an overload error.
+// The baseline is the lowest recently observed latency, drifting slowly
+// upwards so that a lasting change in latency is eventually accepted.
+package loadshed
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Config bounds a limiter. MaxLimit <= 0 disables load shedding.
+type Config struct {
+	MinLimit int
+	MaxLimit int
+	// Tolerance is how many times slower than the baseline a request may be
+	// before it counts as a sign of overload (default 2).
+	Tolerance float64
+	// Backoff multiplies the limit on overload (default 0.9).
+	Backoff float64
+}
+
+func (c Config) Enabled() bool {
+	return c.MaxLimit > 0
+}
+
+func (c Config) withDefaults() Config {
+	if c.MinLimit <= 0 {
+		c.MinLimit = 1
+	}
+	if c.MinLimit > c.MaxLimit {
+		c.MinLimit = c.MaxLimit
+	}
+	if c.Tolerance <= 1 {
+		c.Tolerance = 2
+	}
+	if c.Backoff <= 0 || c.Backoff >= 1 {
+		c.Backoff = 0.9
+	}
+	return c
+}
+
+// minSlowdown keeps scheduling jitter on sub-millisecond calls from reading
+// as overload.
+const minSlowdown = time.Millisecond
+
+// Limiter is an adaptive concurrency limit. It is safe for concurrent use.
+type Limiter struct {
+	cfg Config
+
+	mu           sync.Mutex
+	limit        float64
+	inflight     int
+	baseline     time.Duration
+	lastDecrease time.Time
+	accepted     int64
+	rejected     int64
+}
+
+// NewLimiter starts at twice MinLimit, capped at MaxLimit.
+func NewLimiter(cfg Config) *Limiter {
+	cfg = cfg.withDefaults()
+	return &Limiter{cfg: cfg, limit: float64(min(2*cfg.MinLimit, cfg.MaxLimit))}
+}
+
+// Acquire admits a request when fewer than the limit are in flight. The
+// caller must call done once the request has finished, reporting whether it
+// failed in a way that signals overload (a timeout, an unavailable backend).
+func (l *Limiter) Acquire() (done func(overloaded bool), ok bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inflight >= int(l.limit) {
+		l.rejected++
+		return nil, false
+	}
+	l.inflight++
+	l.accepted++
+	start := time.Now()
+	return func(overloaded bool) { l.release(start, overloaded) }, true
+}
+
+func (l *Limiter) release(start time.Time, overloaded bool) {
+	now := time.Now()
+	rtt := now.Sub(start)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	used := l.inflight
+	l.inflight--
+
+	if l.baseline == 0 || rtt < l.baseline {
+		l.baseline = rtt
+	} else {
+		l.baseline += (rtt - l.baseline) / 100
+	}
+
+	slow := rtt > time.Duration(float64(l.baseline)*l.cfg.Tolerance) && rtt-l.baseline > minSlowdown
+	switch {
+	case overloaded || slow:
+		// Requests admitted before the last decrease saw the old limit; let
+		// them drain instead of shrinking the limit once per request.
+		if start.After(l.lastDecrease) {
+			l.limit = max(float64(l.cfg.MinLimit), l.limit*l.cfg.Backoff)
+			l.lastDecrease = now
+		}
+	case 2*used >= int(l.limit):
+		l.limit = min(float64(l.cfg.MaxLimit), l.limit+1/l.limit)
+	}
+}
+
+// Stats is a point-in-time view of a limiter.
+type Stats struct {
+	Limit    int   `json:"limit"`
+	InFlight int   `json:"in_flight"`
+	Accepted int64 `json:"accepted"`
+	Rejected int64 `json:"rejected"`
+}
+
+func (l *Limiter) Stats() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return Stats{Limit: int(l.limit), InFlight: l.inflight, Accepted: l.accepted, Rejected: l.rejected}
+}
+
+// DefaultRoute is the key of the limiter shared by routes without their own.
+const DefaultRoute = "*"
+
+// Set holds a limiter per configured route and one shared by all others.
+type Set struct {
+	fallback *Limiter
+	routes   map[string]*Limiter
+	exempt   map[string]bool
+}
+
+// NewSet builds the limiters: routes maps a route key to its own MaxLimit.
+// The set is exported as the expvar "load_shed" under name.
+func NewSet(name string, cfg Config, routes map[string]int) *Set {
+	s := &Set{fallback: NewLimiter(cfg), routes: make(map[string]*Limiter, len(routes))}
+	for route, limit := range routes {
+		rc := cfg
+		rc.MaxLimit = limit
+		s.routes[route] = NewLimiter(rc)
+	}
+	publish(name, s)
+	return s
+}
+
+// Exempt takes routes out of load shedding. Long polls hold their slot for
+// as long as they wait, which the limiters would read as overload. It must be
+// called before the set is used.
+func (s *Set) Exempt(routes ...string) *Set {
+	if s.exempt == nil {
+		s.exempt = make(map[string]bool, len(routes))
+	}
+	for _, route := range routes {
+		s.exempt[route] = true
+	}
+	return s
+}
+
+// IsExempt reports whether route bypasses the limiters.
+func (s *Set) IsExempt(route string) bool {
+	return s.exempt[route]
+}
+
+// For returns the limiter of route.
+func (s *Set) For(route string) *Limiter {
+	if l, ok := s.routes[route]; ok {
+		return l
+	}
+	return s.fallback
+}
+
+func (s *Set) Stats() map[string]Stats {
+	out := make(map[string]Stats, len(s.routes)+1)
+	out[DefaultRoute] = s.fallback.Stats()
+	for route, l := range s.routes {
+		out[route] = l.Stats()
+	}
+	return out
+}
+
+var (
+	publishOnce sync.Once
+	sets        sync.Map
+)
+
+func publish(name string, s *Set) {
+	sets.Store(name, s)
+	publishOnce.Do(func() {
+		expvar.Publish("load_shed", expvar.Func(func() any {
+			out := map[string]map[string]Stats{}
+			sets.Range(func(k, v any) bool {
+				out[k.(string)] = v.(*Set).Stats()
+				return true
+			})
+			return out
+		}))
+	})
+}
+
+// ParseRoutes parses "route=limit[,route=limit]", e.g.
+// "POST /orders=50,GET /orders=200" or "CreateOrder=50".
+func ParseRoutes(spec string) (map[string]int, error) {
+	routes := map[string]int{}
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		i := strings.LastIndex(part, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("load shed route %q: want route=limit", part)
+		}
+		limit, err := strconv.Atoi(strings.TrimSpace(part[i+1:]))
+		if err != nil || limit <= 0 {
+			return nil, fmt.Errorf("load shed route %q: limit must be a positive integer", part)
+		}
+		routes[strings.TrimSpace(part[:i])] = limit
+	}
+	return routes, nil
+}
+
+// UnaryServerInterceptor sheds gRPC calls with ResourceExhausted. Routes are
+// keyed by method name, e.g. "CreateOrder".
+func UnaryServerInterceptor(s *Set) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		route := path.Base(info.FullMethod)
+		if s.IsExempt(route) {
+			return handler(ctx, req)
+		}
+		done, ok := s.For(route).Acquire()
+		if !ok {
+			return nil, status.Error(codes.ResourceExhausted, "server overloaded, retry later")
+		}
+		resp, err := handler(ctx, req)
+		code := status.Code(err)
+		done(code == codes.DeadlineExceeded || code == codes.Unavailable)
+		return resp, err
+	}
+}
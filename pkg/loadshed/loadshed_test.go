@@ -0,0 +1,184 @@
+package loadshed
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLimiterRejectsOverLimit(t *testing.T) {
+	l := NewLimiter(Config{MinLimit: 1, MaxLimit: 2})
+	var dones []func(bool)
+	for i := 0; i < 2; i++ {
+		done, ok := l.Acquire()
+		if !ok {
+			t.Fatalf("Acquire() #%d rejected under the limit", i+1)
+		}
+		dones = append(dones, done)
+	}
+	if _, ok := l.Acquire(); ok {
+		t.Fatal("Acquire() admitted a request over the limit")
+	}
+	dones[0](false)
+	if _, ok := l.Acquire(); !ok {
+		t.Fatal("Acquire() rejected after a slot was released")
+	}
+	if s := l.Stats(); s.Accepted != 3 || s.Rejected != 1 || s.InFlight != 2 {
+		t.Fatalf("Stats() = %+v, want 3 accepted, 1 rejected, 2 in flight", s)
+	}
+}
+
+func TestLimiterBacksOffOnOverload(t *testing.T) {
+	l := NewLimiter(Config{MinLimit: 5, MaxLimit: 100})
+	if got := l.Stats().Limit; got != 10 {
+		t.Fatalf("initial limit = %d, want 10", got)
+	}
+
+	// Requests admitted together back off the limit once, not once each.
+	var dones []func(bool)
+	for i := 0; i < 5; i++ {
+		done, _ := l.Acquire()
+		dones = append(dones, done)
+	}
+	for _, done := range dones {
+		done(true)
+	}
+	if got := l.Stats().Limit; got != 9 {
+		t.Fatalf("limit after one overloaded batch = %d, want 9", got)
+	}
+
+	for i := 0; i < 50; i++ {
+		done, _ := l.Acquire()
+		done(true)
+	}
+	if got := l.Stats().Limit; got != 5 {
+		t.Fatalf("limit after sustained overload = %d, want MinLimit 5", got)
+	}
+}
+
+func TestLimiterGrowsWhenBusyAndFast(t *testing.T) {
+	l := NewLimiter(Config{MinLimit: 2, MaxLimit: 6})
+	for i := 0; i < 200; i++ {
+		limit := l.Stats().Limit
+		var dones []func(bool)
+		for j := 0; j < limit; j++ {
+			done, ok := l.Acquire()
+			if !ok {
+				t.Fatalf("Acquire() rejected at %d of limit %d", j, limit)
+			}
+			dones = append(dones, done)
+		}
+		for _, done := range dones {
+			done(false)
+		}
+	}
+	if got := l.Stats().Limit; got != 6 {
+		t.Fatalf("limit = %d, want MaxLimit 6", got)
+	}
+}
+
+func TestLimiterIdleDoesNotGrow(t *testing.T) {
+	l := NewLimiter(Config{MinLimit: 5, MaxLimit: 100})
+	for i := 0; i < 100; i++ {
+		done, _ := l.Acquire()
+		done(false)
+	}
+	if got := l.Stats().Limit; got != 10 {
+		t.Fatalf("limit = %d, want 10: one request at a time does not use the capacity", got)
+	}
+}
+
+func TestLimiterBacksOffOnSlowRequests(t *testing.T) {
+	l := NewLimiter(Config{MinLimit: 5, MaxLimit: 100})
+	l.baseline = time.Millisecond
+	done, _ := l.Acquire()
+	time.Sleep(5 * time.Millisecond)
+	done(false)
+	if got := l.Stats().Limit; got != 9 {
+		t.Fatalf("limit after a slow request = %d, want 9", got)
+	}
+}
+
+func TestParseRoutes(t *testing.T) {
+	routes, err := ParseRoutes(" POST /orders=50, GET /orders/{orderId}=200 ,")
+	if err != nil {
+		t.Fatalf("ParseRoutes() error: %v", err)
+	}
+	if len(routes) != 2 || routes["POST /orders"] != 50 || routes["GET /orders/{orderId}"] != 200 {
+		t.Fatalf("ParseRoutes() = %v", routes)
+	}
+	for _, bad := range []string{"POST /orders", "=5", "CreateOrder=0", "CreateOrder=x"} {
+		if _, err := ParseRoutes(bad); err == nil {
+			t.Fatalf("ParseRoutes(%q) expected error", bad)
+		}
+	}
+}
+
+func TestSetRoutes(t *testing.T) {
+	s := NewSet("test-set", Config{MinLimit: 1, MaxLimit: 10}, map[string]int{"CreateOrder": 1})
+	if s.For("CreateOrder") == s.For("GetOrder") {
+		t.Fatal("configured route shares the default limiter")
+	}
+	if s.For("GetOrder") != s.For("ListOrders") {
+		t.Fatal("unconfigured routes must share the default limiter")
+	}
+	if got := s.Stats()["CreateOrder"].Limit; got != 1 {
+		t.Fatalf("CreateOrder limit = %d, want 1", got)
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	s := NewSet("test-interceptor", Config{MinLimit: 1, MaxLimit: 10}, map[string]int{"CreateOrder": 1})
+	intercept := UnaryServerInterceptor(s)
+	info := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrdersService/CreateOrder"}
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go intercept(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+	defer close(release)
+
+	_, err := intercept(context.Background(), nil, info, func(context.Context, any) (any, error) {
+		t.Fatal("handler called over the limit")
+		return nil, nil
+	})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("over the limit: code = %s, want ResourceExhausted", status.Code(err))
+	}
+
+	other := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrdersService/GetOrder"}
+	if _, err := intercept(context.Background(), nil, other, func(context.Context, any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("other route: %v", err)
+	}
+}
+
+func TestUnaryServerInterceptorExempt(t *testing.T) {
+	s := NewSet("test-exempt", Config{MinLimit: 1, MaxLimit: 1}, nil).Exempt("WaitOrder")
+	intercept := UnaryServerInterceptor(s)
+
+	release := make(chan struct{})
+	started := make(chan struct{})
+	go intercept(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrdersService/GetOrder"}, func(context.Context, any) (any, error) {
+		close(started)
+		<-release
+		return nil, nil
+	})
+	<-started
+	defer close(release)
+
+	wait := &grpc.UnaryServerInfo{FullMethod: "/orders.v1.OrdersService/WaitOrder"}
+	if _, err := intercept(context.Background(), nil, wait, func(context.Context, any) (any, error) { return nil, nil }); err != nil {
+		t.Fatalf("exempt route at the limit: %v", err)
+	}
+	if !s.IsExempt("WaitOrder") || s.IsExempt("GetOrder") {
+		t.Fatal("IsExempt does not match Exempt")
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// Metadata keys the ids travel under between services.
+const (
+	requestIDMetadata = "x-request-id"
+	traceIDMetadata   = "x-trace-id"
+)
+
+// UnaryServerInterceptor puts the request and trace ids sent by the caller
+// into the context, generating a request id for calls that came without one,
+// and the user and order ids of the request message when it has them
+// (GetUserId, GetOrderId).
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var requestID, traceID string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			requestID = first(md, requestIDMetadata)
+			traceID = cleanID(first(md, traceIDMetadata))
+			if traceID == "" {
+				traceID = ParseTraceparent(first(md, TraceparentHeader))
+			}
+		}
+		if requestID = cleanID(requestID); requestID == "" {
+			requestID = NewRequestID()
+		}
+		ctx = WithTraceID(WithRequestID(ctx, requestID), traceID)
+		if m, ok := req.(interface{ GetUserId() string }); ok {
+			ctx = WithUserID(ctx, m.GetUserId())
+		}
+		if m, ok := req.(interface{ GetOrderId() string }); ok {
+			ctx = WithOrderID(ctx, m.GetOrderId())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// UnaryClientInterceptor forwards the request and trace ids in ctx to the
+// called service.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// outgoing returns ctx with its request and trace ids added to the outgoing
+// gRPC metadata.
+func outgoing(ctx context.Context) context.Context {
+	var kv []string
+	if id := RequestID(ctx); id != "" {
+		kv = append(kv, requestIDMetadata, id)
+	}
+	if id := TraceID(ctx); id != "" {
+		kv = append(kv, traceIDMetadata, id)
+	}
+	if len(kv) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, kv...)
+}
+
+func first(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+)
+
+// NewLogger builds the process logger of service. The returned LevelVar backs
+// the handler level so it can be changed at runtime (see WatchLogLevel).
+// redaction hides personal data; logging it verbatim needs dev, anything else
+// falls back to hashing, which fails without a salt.
+func NewLogger(service string, level slog.Level, format string, redaction Redaction, dev bool) (*slog.Logger, *slog.LevelVar, error) {
+	lv := new(slog.LevelVar)
+	lv.Set(level)
+	fallback := ""
+	if err := redaction.Validate(); err != nil && !errors.Is(err, ErrNoSalt) {
+		fallback = err.Error()
+	} else if redaction.Mode == RedactOff && !dev {
+		fallback = "LOG_REDACT=off requires LOG_DEV=true"
+	}
+	if fallback != "" {
+		redaction.Mode = RedactHash
+	}
+	if err := redaction.Validate(); err != nil {
+		return nil, nil, err
+	}
+	opts := &slog.HandlerOptions{Level: lv, ReplaceAttr: redaction.ReplaceAttr()}
+	var h slog.Handler
+	if strings.EqualFold(format, "text") {
+		h = slog.NewTextHandler(os.Stdout, opts)
+	} else {
+		h = slog.NewJSONHandler(os.Stdout, opts)
+	}
+	logger := slog.New(NewHandler(NewSamplingHandler(h))).With("service", service)
+	if fallback != "" {
+		logger.Warn("log redaction falls back to hash", "reason", fallback)
+	}
+	return logger, lv, nil
+}
+
+// WatchLogLevel toggles lv between its initial value and Debug on every
+// SIGHUP, or between Debug and Info when it starts at Debug.
+func WatchLogLevel(ctx context.Context, service string, lv *slog.LevelVar) {
+	logger := slog.Default().With("service", service, "component", "app")
+	base, other := lv.Level(), toggledLevel(lv.Level())
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGHUP)
+	defer signal.Stop(sig)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-sig:
+			next := other
+			if lv.Level() == other {
+				next = base
+			}
+			lv.Set(next)
+			logger.Info("log level changed", "level", next.String())
+		}
+	}
+}
+
+// toggledLevel is the level SIGHUP switches base to.
+func toggledLevel(base slog.Level) slog.Level {
+	if base <= slog.LevelDebug {
+		return slog.LevelInfo
+	}
+	return slog.LevelDebug
+}
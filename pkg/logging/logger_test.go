@@ -0,0 +1,37 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+func TestNewLogger(t *testing.T) {
+	_, lv, err := NewLogger("svc", slog.LevelWarn, "text", Redaction{Mode: RedactMask}, false)
+	if err != nil {
+		t.Fatalf("NewLogger() error: %v", err)
+	}
+	if lv.Level() != slog.LevelWarn {
+		t.Fatalf("level = %v, want %v", lv.Level(), slog.LevelWarn)
+	}
+
+	// off without dev falls back to hashing, which needs a salt.
+	if _, _, err := NewLogger("svc", slog.LevelInfo, "json", Redaction{Mode: RedactOff}, false); !errors.Is(err, ErrNoSalt) {
+		t.Fatalf("off without dev and salt = %v, want ErrNoSalt", err)
+	}
+	if _, _, err := NewLogger("svc", slog.LevelInfo, "json", Redaction{Mode: RedactOff}, true); err != nil {
+		t.Fatalf("off with dev = %v, want no error", err)
+	}
+}
+
+func TestToggledLevel(t *testing.T) {
+	for base, want := range map[slog.Level]slog.Level{
+		slog.LevelInfo:  slog.LevelDebug,
+		slog.LevelWarn:  slog.LevelDebug,
+		slog.LevelDebug: slog.LevelInfo,
+	} {
+		if got := toggledLevel(base); got != want {
+			t.Fatalf("toggledLevel(%v) = %v, want %v", base, got, want)
+		}
+	}
+}
@@ -0,0 +1,228 @@
+// Package logging attaches request-scoped ids to the context once and adds
+// them to every log line written with that context.
+//
+// The edges put the ids in: Middleware for HTTP, UnaryServerInterceptor for
+// gRPC, and handlers for ids they learn later (WithOrderID). Services wrap
+// their slog handler with NewHandler and log through the *Context methods
+// (logger.InfoContext(ctx, ...)); the handler then adds request_id, trace_id,
+// user_id and order_id from the context. UnaryClientInterceptor forwards the
+// request and trace ids to the next service, so one request can be followed
+// through the gateway, orders-service and payments-service logs.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+// Log attribute keys of the context values.
+const (
+	RequestIDKey = "request_id"
+	TraceIDKey   = "trace_id"
+	UserIDKey    = "user_id"
+	OrderIDKey   = "order_id"
+)
+
+// RequestIDHeader carries the request id over HTTP, both ways: a client may
+// set it, and the gateway always returns it.
+const RequestIDHeader = "X-Request-Id"
+
+// TraceparentHeader is the W3C Trace Context header; its trace-id is logged
+// as trace_id.
+const TraceparentHeader = "traceparent"
+
+type ctxKey int
+
+const (
+	requestIDCtx ctxKey = iota
+	traceIDCtx
+	userIDCtx
+	orderIDCtx
+)
+
+// fields lists the context values in the order they are logged.
+var fields = []struct {
+	key  ctxKey
+	attr string
+}{
+	{requestIDCtx, RequestIDKey},
+	{traceIDCtx, TraceIDKey},
+	{userIDCtx, UserIDKey},
+	{orderIDCtx, OrderIDKey},
+}
+
+func with(ctx context.Context, key ctxKey, v string) context.Context {
+	if v == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, key, v)
+}
+
+func value(ctx context.Context, key ctxKey) string {
+	v, _ := ctx.Value(key).(string)
+	return v
+}
+
+// WithRequestID returns ctx carrying the request id; an empty id leaves ctx
+// as is. The same holds for the other With functions.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return with(ctx, requestIDCtx, id)
+}
+
+// WithTraceID returns ctx carrying the trace id.
+func WithTraceID(ctx context.Context, id string) context.Context {
+	return with(ctx, traceIDCtx, id)
+}
+
+// WithUserID returns ctx carrying the user id.
+func WithUserID(ctx context.Context, id string) context.Context {
+	return with(ctx, userIDCtx, id)
+}
+
+// WithOrderID returns ctx carrying the order id.
+func WithOrderID(ctx context.Context, id string) context.Context {
+	return with(ctx, orderIDCtx, id)
+}
+
+// RequestID returns the request id in ctx, or "".
+func RequestID(ctx context.Context) string { return value(ctx, requestIDCtx) }
+
+// TraceID returns the trace id in ctx, or "".
+func TraceID(ctx context.Context) string { return value(ctx, traceIDCtx) }
+
+// UserID returns the user id in ctx, or "".
+func UserID(ctx context.Context) string { return value(ctx, userIDCtx) }
+
+// OrderID returns the order id in ctx, or "".
+func OrderID(ctx context.Context) string { return value(ctx, orderIDCtx) }
+
+// NewRequestID returns a random 128-bit id in hex.
+func NewRequestID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// maxIDLen bounds ids taken from clients, so a caller cannot blow up every
+// log line of its request.
+const maxIDLen = 128
+
+// cleanID returns a client-supplied id if it is short printable ASCII, and ""
+// otherwise.
+func cleanID(id string) string {
+	id = strings.TrimSpace(id)
+	if len(id) > maxIDLen {
+		return ""
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return ""
+		}
+	}
+	return id
+}
+
+// ParseTraceparent returns the trace-id of a W3C traceparent header
+// ("00-<32 hex trace-id>-<16 hex parent-id>-<2 hex flags>"), or "" if the
+// header is malformed or the trace-id is all zeros.
+func ParseTraceparent(h string) string {
+	parts := strings.Split(strings.TrimSpace(h), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || len(parts[1]) != 32 || len(parts[2]) != 16 {
+		return ""
+	}
+	id := strings.ToLower(parts[1])
+	if _, err := hex.DecodeString(id); err != nil || id == strings.Repeat("0", 32) {
+		return ""
+	}
+	return id
+}
+
+// Middleware puts the request id, trace id and X-Request-Id user into the
+// request context. The request id comes from X-Request-Id when the client
+// sent a usable one and is generated otherwise; it is echoed in the response
+// header either way.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := cleanID(r.Header.Get(RequestIDHeader))
+		if id == "" {
+			id = NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+		ctx := WithRequestID(r.Context(), id)
+		ctx = WithTraceID(ctx, ParseTraceparent(r.Header.Get(TraceparentHeader)))
+		ctx = WithUserID(ctx, cleanID(r.Header.Get("X-User-Id")))
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// handler adds the context's ids to each record, except those the record or
+// the logger already set explicitly, so a line never repeats a key.
+type handler struct {
+	slog.Handler
+	// set holds the keys added through WithAttrs outside of any group.
+	set     map[string]bool
+	grouped bool
+}
+
+// NewHandler wraps h so that records logged with a context carry its ids.
+func NewHandler(h slog.Handler) slog.Handler {
+	return &handler{Handler: h}
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		return h.Handler.Handle(ctx, r)
+	}
+	var add []slog.Attr
+	for _, f := range fields {
+		v := value(ctx, f.key)
+		if v == "" || h.set[f.attr] {
+			continue
+		}
+		if add == nil {
+			add = make([]slog.Attr, 0, len(fields))
+		}
+		add = append(add, slog.String(f.attr, v))
+	}
+	if len(add) == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+	if !h.grouped {
+		r.Attrs(func(a slog.Attr) bool {
+			for i := range add {
+				if add[i].Key == a.Key {
+					add = append(add[:i], add[i+1:]...)
+					break
+				}
+			}
+			return len(add) > 0
+		})
+	}
+	if len(add) > 0 {
+		r = r.Clone()
+		r.AddAttrs(add...)
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	set := h.set
+	if !h.grouped {
+		set = make(map[string]bool, len(h.set)+len(attrs))
+		for k := range h.set {
+			set[k] = true
+		}
+		for _, a := range attrs {
+			set[a.Key] = true
+		}
+	}
+	return &handler{Handler: h.Handler.WithAttrs(attrs), set: set, grouped: h.grouped}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{Handler: h.Handler.WithGroup(name), set: h.set, grouped: true}
+}
@@ -0,0 +1,184 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+func newTestLogger(buf *bytes.Buffer) *slog.Logger {
+	return slog.New(NewHandler(slog.NewJSONHandler(buf, nil)))
+}
+
+// lines decodes the JSON log lines in buf.
+func lines(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, l := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		m := map[string]any{}
+		if err := json.Unmarshal([]byte(l), &m); err != nil {
+			t.Fatalf("bad log line %q: %v", l, err)
+		}
+		out = append(out, m)
+	}
+	return out
+}
+
+func TestHandlerAddsContextIDs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTestLogger(&buf).With("component", "test")
+
+	ctx := WithOrderID(WithUserID(WithRequestID(context.Background(), "req-1"), "u1"), "o1")
+	logger.InfoContext(ctx, "with context")
+	logger.InfoContext(ctx, "explicit wins", "user_id", "u2")
+	logger.With("order_id", "o2").InfoContext(ctx, "logger attr wins")
+	logger.Info("no context")
+
+	got := lines(t, &buf)
+	if got[0]["request_id"] != "req-1" || got[0]["user_id"] != "u1" || got[0]["order_id"] != "o1" || got[0]["component"] != "test" {
+		t.Fatalf("with context: %v", got[0])
+	}
+	if _, ok := got[0]["trace_id"]; ok {
+		t.Fatalf("empty trace id logged: %v", got[0])
+	}
+	if got[1]["user_id"] != "u2" || strings.Count(buf.String(), `"user_id"`) != 3 {
+		t.Fatalf("explicit user_id: %v\n%s", got[1], buf.String())
+	}
+	if got[2]["order_id"] != "o2" || got[2]["request_id"] != "req-1" {
+		t.Fatalf("logger attr: %v", got[2])
+	}
+	if _, ok := got[3]["request_id"]; ok {
+		t.Fatalf("no context: %v", got[3])
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	cases := map[string]string{
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01": "4bf92f3577b34da6a3ce929d0e0e4736",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01": "",
+		"00-4bf92f3577b34da6-00f067aa0ba902b7-01":                 "",
+		"garbage": "",
+	}
+	for h, want := range cases {
+		if got := ParseTraceparent(h); got != want {
+			t.Fatalf("ParseTraceparent(%q) = %q, want %q", h, got, want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	var ctx context.Context
+	h := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) { ctx = r.Context() }))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "client-id")
+	req.Header.Set(TraceparentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	req.Header.Set("X-User-Id", "u1")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if RequestID(ctx) != "client-id" || rec.Header().Get(RequestIDHeader) != "client-id" {
+		t.Fatalf("request id = %q, header %q", RequestID(ctx), rec.Header().Get(RequestIDHeader))
+	}
+	if TraceID(ctx) != "4bf92f3577b34da6a3ce929d0e0e4736" || UserID(ctx) != "u1" {
+		t.Fatalf("trace id = %q, user id = %q", TraceID(ctx), UserID(ctx))
+	}
+
+	// An unusable client id is replaced.
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "has space")
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if id := RequestID(ctx); len(id) != 32 || rec.Header().Get(RequestIDHeader) != id {
+		t.Fatalf("generated request id = %q, header %q", id, rec.Header().Get(RequestIDHeader))
+	}
+}
+
+type orderRequest struct{}
+
+func (orderRequest) GetUserId() string  { return "u1" }
+func (orderRequest) GetOrderId() string { return "o1" }
+
+func TestGRPCPropagation(t *testing.T) {
+	ctx := WithTraceID(WithRequestID(context.Background(), "req-1"), "trace-1")
+
+	var md metadata.MD
+	err := UnaryClientInterceptor()(ctx, "/svc/M", nil, nil, nil, func(ctx context.Context, _ string, _, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ = metadata.FromOutgoingContext(ctx)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got context.Context
+	_, err = UnaryServerInterceptor()(metadata.NewIncomingContext(context.Background(), md), orderRequest{}, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		got = ctx
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if RequestID(got) != "req-1" || TraceID(got) != "trace-1" || UserID(got) != "u1" || OrderID(got) != "o1" {
+		t.Fatalf("server ids = %q %q %q %q", RequestID(got), TraceID(got), UserID(got), OrderID(got))
+	}
+
+	// Calls without metadata get a fresh request id.
+	_, _ = UnaryServerInterceptor()(context.Background(), nil, &grpc.UnaryServerInfo{}, func(ctx context.Context, _ any) (any, error) {
+		got = ctx
+		return nil, nil
+	})
+	if len(RequestID(got)) != 32 {
+		t.Fatalf("generated request id = %q", RequestID(got))
+	}
+}
+
+func TestRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	opts := &slog.HandlerOptions{ReplaceAttr: Redaction{Mode: RedactHash, Salt: "s"}.ReplaceAttr()}
+	logger := slog.New(NewHandler(slog.NewJSONHandler(&buf, opts))).With("operator", "admin@example.com")
+	ctx := WithUserID(context.Background(), "user-42")
+	logger.InfoContext(ctx, "a", "amount", 100, "idempotency_key", "")
+	logger.Info("b", "user_id", "user-42", slog.Group("req", "description", "birthday gift"))
+
+	got := lines(t, &buf)
+	if strings.Contains(buf.String(), "user-42") || strings.Contains(buf.String(), "admin@example.com") || strings.Contains(buf.String(), "birthday") {
+		t.Fatalf("values leaked:\n%s", buf.String())
+	}
+	// The same value hashes the same, from the context or from the call.
+	if got[0]["user_id"] != got[1]["user_id"] || !strings.HasPrefix(got[0]["user_id"].(string), "h:") {
+		t.Fatalf("user_id hashes = %v, %v", got[0]["user_id"], got[1]["user_id"])
+	}
+	if got[0]["amount"] != float64(100) || got[0]["idempotency_key"] != "" {
+		t.Fatalf("untouched attrs changed: %v", got[0])
+	}
+
+	mask := Redaction{Mode: RedactMask, Fields: []string{"user_id"}}.ReplaceAttr()
+	if a := mask(nil, slog.String("user_id", "user-42")); a.Value.String() != "us***42" {
+		t.Fatalf("mask = %q", a.Value.String())
+	}
+	if a := mask(nil, slog.String("user_id", "u1")); a.Value.String() != "***" {
+		t.Fatalf("mask short = %q", a.Value.String())
+	}
+	if (Redaction{Mode: RedactOff}).ReplaceAttr() != nil {
+		t.Fatal("off redaction should not replace attrs")
+	}
+	if (Redaction{Mode: "plain"}).Validate() == nil {
+		t.Fatal("unknown mode accepted")
+	}
+	if err := (Redaction{Mode: RedactHash}).Validate(); !errors.Is(err, ErrNoSalt) {
+		t.Fatalf("hash without salt = %v, want ErrNoSalt", err)
+	}
+	if a := (Redaction{Mode: RedactHash, Salt: "s"}).ReplaceAttr()(nil, slog.String("sub", "user-42")); a.Value.String() == "user-42" {
+		t.Fatal("jwt subject not redacted")
+	}
+}
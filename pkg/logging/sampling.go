@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// Below Info, each message is logged samplingFirst times per second and
+	// then only every samplingThereafter-th time within that second.
+	samplingFirst      = 10
+	samplingThereafter = 100
+)
+
+type sampleCounter struct {
+	second atomic.Int64
+	n      atomic.Uint64
+}
+
+// samplingHandler thins out high-frequency Debug records so that enabling
+// debug logging under load does not flood the output. Info and above are
+// always passed through.
+type samplingHandler struct {
+	slog.Handler
+	counters *sync.Map
+}
+
+// NewSamplingHandler wraps h so that records below Info are sampled per
+// message and second.
+func NewSamplingHandler(h slog.Handler) slog.Handler {
+	return &samplingHandler{Handler: h, counters: &sync.Map{}}
+}
+
+func (h *samplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelInfo {
+		return h.Handler.Handle(ctx, r)
+	}
+	v, _ := h.counters.LoadOrStore(r.Message, &sampleCounter{})
+	c := v.(*sampleCounter)
+	sec := r.Time.Unix()
+	if old := c.second.Load(); old != sec && c.second.CompareAndSwap(old, sec) {
+		c.n.Store(0)
+	}
+	n := c.n.Add(1)
+	if n <= samplingFirst || (n-samplingFirst)%samplingThereafter == 0 {
+		return h.Handler.Handle(ctx, r)
+	}
+	return nil
+}
+
+func (h *samplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithAttrs(attrs), counters: h.counters}
+}
+
+func (h *samplingHandler) WithGroup(name string) slog.Handler {
+	return &samplingHandler{Handler: h.Handler.WithGroup(name), counters: h.counters}
+}
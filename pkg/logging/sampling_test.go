@@ -0,0 +1,58 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	ctx := context.Background()
+	sec := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	handle := func(at time.Time, level slog.Level, msg string) {
+		t.Helper()
+		if err := h.Handle(ctx, slog.NewRecord(at, level, msg, 0)); err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+	}
+	count := func(msg string) int {
+		return strings.Count(buf.String(), "msg="+msg+"\n")
+	}
+
+	// 10 first, then every 100th: 10 + 110th + 210th.
+	for range 250 {
+		handle(sec, slog.LevelDebug, "tick")
+	}
+	if got := count("tick"); got != samplingFirst+2 {
+		t.Fatalf("debug lines in one second = %d, want %d", got, samplingFirst+2)
+	}
+
+	// The count restarts every second and is kept per message.
+	for range 5 {
+		handle(sec.Add(time.Second), slog.LevelDebug, "tick")
+		handle(sec.Add(time.Second), slog.LevelDebug, "tock")
+	}
+	if got := count("tick"); got != samplingFirst+2+5 {
+		t.Fatalf("debug lines after the next second = %d, want %d", got, samplingFirst+2+5)
+	}
+	if got := count("tock"); got != 5 {
+		t.Fatalf("lines of another message = %d, want 5", got)
+	}
+
+	// Info and above are never sampled, also through WithAttrs.
+	info := h.WithAttrs([]slog.Attr{slog.String("k", "v")})
+	for range 50 {
+		if err := info.Handle(ctx, slog.NewRecord(sec, slog.LevelInfo, "info", 0)); err != nil {
+			t.Fatalf("Handle() error: %v", err)
+		}
+	}
+	if got := strings.Count(buf.String(), "msg=info k=v"); got != 50 {
+		t.Fatalf("info lines = %d, want 50", got)
+	}
+}
@@ -0,0 +1,95 @@
+// Package lru is the in-process cache of the services: a size-bounded
+// least-recently-used map whose entries also expire.
+package lru
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// Cache is a size-bounded least-recently-used map whose entries also expire
+// after ttl. It is safe for concurrent use.
+type Cache[V any] struct {
+	size int
+	ttl  time.Duration
+
+	mu    sync.Mutex
+	ll    *list.List
+	items map[string]*list.Element
+
+	now func() time.Time
+}
+
+type entry[V any] struct {
+	key       string
+	value     V
+	expiresAt time.Time
+}
+
+// New returns a Cache of at most size entries (10000 when size is not
+// positive) that expire ttl after they were set.
+func New[V any](size int, ttl time.Duration) *Cache[V] {
+	if size <= 0 {
+		size = 10000
+	}
+	return &Cache[V]{size: size, ttl: ttl, ll: list.New(), items: make(map[string]*list.Element), now: time.Now}
+}
+
+// Get returns the value of key unless it is missing or expired.
+func (l *Cache[V]) Get(key string) (V, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var zero V
+	el, ok := l.items[key]
+	if !ok {
+		return zero, false
+	}
+	e := el.Value.(*entry[V])
+	if l.ttl > 0 && !l.now().Before(e.expiresAt) {
+		l.ll.Remove(el)
+		delete(l.items, key)
+		return zero, false
+	}
+	l.ll.MoveToFront(el)
+	return e.value, true
+}
+
+// Set stores value under key, evicting the least recently used entry when
+// the cache is full.
+func (l *Cache[V]) Set(key string, value V) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	expiresAt := l.now().Add(l.ttl)
+	if el, ok := l.items[key]; ok {
+		e := el.Value.(*entry[V])
+		e.value = value
+		e.expiresAt = expiresAt
+		l.ll.MoveToFront(el)
+		return
+	}
+	l.items[key] = l.ll.PushFront(&entry[V]{key: key, value: value, expiresAt: expiresAt})
+	for l.ll.Len() > l.size {
+		oldest := l.ll.Back()
+		l.ll.Remove(oldest)
+		delete(l.items, oldest.Value.(*entry[V]).key)
+	}
+}
+
+// Remove drops key from the cache.
+func (l *Cache[V]) Remove(key string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if el, ok := l.items[key]; ok {
+		l.ll.Remove(el)
+		delete(l.items, key)
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet
+// dropped.
+func (l *Cache[V]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.ll.Len()
+}
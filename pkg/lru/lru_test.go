@@ -0,0 +1,45 @@
+package lru
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	l := New[int](2, time.Minute)
+	l.Set("a", 1)
+	l.Set("b", 2)
+	if _, ok := l.Get("a"); !ok {
+		t.Fatal("Get(a) missed before eviction")
+	}
+	l.Set("c", 3)
+
+	if _, ok := l.Get("b"); ok {
+		t.Fatal("Get(b) hit, want it evicted as least recently used")
+	}
+	if v, ok := l.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = (%d, %v), want (1, true)", v, ok)
+	}
+	if l.Len() != 2 {
+		t.Fatalf("Len() = %d, want 2", l.Len())
+	}
+}
+
+func TestLRUExpiresAfterTTL(t *testing.T) {
+	now := time.Now()
+	l := New[int](10, time.Second)
+	l.now = func() time.Time { return now }
+	l.Set("a", 1)
+
+	now = now.Add(500 * time.Millisecond)
+	if _, ok := l.Get("a"); !ok {
+		t.Fatal("Get(a) missed before ttl")
+	}
+	now = now.Add(time.Second)
+	if _, ok := l.Get("a"); ok {
+		t.Fatal("Get(a) hit after ttl")
+	}
+	if l.Len() != 0 {
+		t.Fatalf("Len() = %d, want expired entry removed", l.Len())
+	}
+}
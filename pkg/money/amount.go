@@ -0,0 +1,33 @@
+package money
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Amount is a count of minor units that is encoded in JSON as a decimal
+// string ("150050"). Decoding also accepts a plain integer so that clients
+// written against the numeric encoding keep working.
+type Amount int64
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strconv.FormatInt(int64(a), 10))
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	raw := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		if err := json.Unmarshal(b, &raw); err != nil {
+			return err
+		}
+	}
+	v, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w %s: want an integer number of minor units", ErrInvalidAmount, b)
+	}
+	*a = Amount(v)
+	return nil
+}
@@ -0,0 +1,180 @@
+// Package money represents amounts as int64 minor units (kopecks, cents) of a
+// known currency.
+//
+// Minor units keep arithmetic exact; the currency's exponent is only needed
+// at the edges, when an amount is shown to a person or parsed from one:
+//
+//	money.New(150050, money.RUB).String() // "1500.50 RUB"
+//
+// Amounts cross JSON as decimal strings of minor units (see Amount), because
+// JavaScript numbers lose precision above 2^53.
+package money
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 code.
+type Currency string
+
+const (
+	RUB Currency = "RUB"
+	USD Currency = "USD"
+	EUR Currency = "EUR"
+	JPY Currency = "JPY"
+)
+
+// exponents is the number of minor-unit digits of each supported currency.
+var exponents = map[Currency]int{
+	RUB: 2,
+	USD: 2,
+	EUR: 2,
+	JPY: 0,
+}
+
+// MaxAmount bounds a single amount: 10^15 minor units, so that summing a
+// million of them still fits in int64.
+const MaxAmount int64 = 1_000_000_000_000_000
+
+var (
+	ErrUnknownCurrency = errors.New("unknown currency")
+	ErrNotPositive     = errors.New("amount must be positive")
+	ErrTooLarge        = errors.New("amount exceeds the maximum")
+	ErrInvalidAmount   = errors.New("invalid amount")
+)
+
+// ParseCurrency returns the supported currency for code, case-insensitively.
+func ParseCurrency(code string) (Currency, error) {
+	c := Currency(strings.ToUpper(strings.TrimSpace(code)))
+	if _, ok := exponents[c]; !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownCurrency, code)
+	}
+	return c, nil
+}
+
+// Exponent returns the number of minor-unit digits, or -1 for an unknown
+// currency.
+func (c Currency) Exponent() int {
+	e, ok := exponents[c]
+	if !ok {
+		return -1
+	}
+	return e
+}
+
+// Money is an amount in minor units of Currency.
+type Money struct {
+	Amount   int64
+	Currency Currency
+}
+
+func New(amount int64, c Currency) Money {
+	return Money{Amount: amount, Currency: c}
+}
+
+// Validate checks that m is a positive amount of a known currency no larger
+// than MaxAmount, which is what every payment, top-up and order requires.
+func (m Money) Validate() error {
+	if m.Currency.Exponent() < 0 {
+		return fmt.Errorf("%w %q", ErrUnknownCurrency, string(m.Currency))
+	}
+	return ValidateAmount(m.Amount)
+}
+
+// ValidateAmount is Validate for an amount whose currency is implied.
+func ValidateAmount(amount int64) error {
+	if amount <= 0 {
+		return ErrNotPositive
+	}
+	if amount > MaxAmount {
+		return ErrTooLarge
+	}
+	return nil
+}
+
+// MulAmount returns amount*n, e.g. a unit price times a quantity. Both must
+// be non-negative; a product above MaxAmount is ErrTooLarge, so the
+// multiplication cannot overflow.
+func MulAmount(amount, n int64) (int64, error) {
+	if amount < 0 || n < 0 {
+		return 0, ErrInvalidAmount
+	}
+	if amount != 0 && n > MaxAmount/amount {
+		return 0, ErrTooLarge
+	}
+	return amount * n, nil
+}
+
+// AddAmount returns a+b for amounts between 0 and MaxAmount; a sum above
+// MaxAmount is ErrTooLarge.
+func AddAmount(a, b int64) (int64, error) {
+	if a < 0 || b < 0 || a > MaxAmount || b > MaxAmount {
+		return 0, ErrInvalidAmount
+	}
+	if a > MaxAmount-b {
+		return 0, ErrTooLarge
+	}
+	return a + b, nil
+}
+
+// String formats m for people, e.g. "1500.50 RUB".
+func (m Money) String() string {
+	return FormatMinor(m.Amount, m.Currency) + " " + string(m.Currency)
+}
+
+// FormatMinor renders amount in major units with the currency's number of
+// decimals, e.g. 150050 RUB -> "1500.50". Unknown currencies print the raw
+// minor units.
+func FormatMinor(amount int64, c Currency) string {
+	exp := c.Exponent()
+	if exp <= 0 {
+		return strconv.FormatInt(amount, 10)
+	}
+	sign := ""
+	u := uint64(amount)
+	if amount < 0 {
+		sign = "-"
+		u = -u
+	}
+	digits := strconv.FormatUint(u, 10)
+	if len(digits) <= exp {
+		digits = strings.Repeat("0", exp-len(digits)+1) + digits
+	}
+	cut := len(digits) - exp
+	return sign + digits[:cut] + "." + digits[cut:]
+}
+
+// ParseMajor parses an amount in major units ("1500.5") into minor units of
+// c. More decimals than the currency has are rejected rather than rounded.
+func ParseMajor(s string, c Currency) (int64, error) {
+	exp := c.Exponent()
+	if exp < 0 {
+		return 0, fmt.Errorf("%w %q", ErrUnknownCurrency, string(c))
+	}
+	s = strings.TrimSpace(s)
+	whole, frac, hasDot := strings.Cut(s, ".")
+	// The integer part needs a digit of its own: "-.50" and "+1" are not
+	// amounts.
+	if digits := strings.TrimPrefix(whole, "-"); digits == "" || !isDigits(digits) ||
+		(hasDot && (frac == "" || len(frac) > exp || !isDigits(frac))) {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	frac += strings.Repeat("0", exp-len(frac))
+	v, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrInvalidAmount, s)
+	}
+	return v, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
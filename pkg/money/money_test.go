@@ -0,0 +1,129 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		m    Money
+		want string
+	}{
+		{New(150050, RUB), "1500.50 RUB"},
+		{New(5, USD), "0.05 USD"},
+		{New(0, EUR), "0.00 EUR"},
+		{New(-1999, RUB), "-19.99 RUB"},
+		{New(1500, JPY), "1500 JPY"},
+	}
+	for _, tc := range cases {
+		if got := tc.m.String(); got != tc.want {
+			t.Fatalf("String(%d %s) = %q, want %q", tc.m.Amount, tc.m.Currency, got, tc.want)
+		}
+	}
+}
+
+func TestParseMajor(t *testing.T) {
+	cases := []struct {
+		in   string
+		c    Currency
+		want int64
+	}{
+		{"1500.5", RUB, 150050},
+		{"1500", RUB, 150000},
+		{"0.07", USD, 7},
+		{"-2.10", EUR, -210},
+		{"-0.50", RUB, -50},
+		{"1500", JPY, 1500},
+	}
+	for _, tc := range cases {
+		got, err := ParseMajor(tc.in, tc.c)
+		if err != nil || got != tc.want {
+			t.Fatalf("ParseMajor(%q, %s) = %d, %v, want %d", tc.in, tc.c, got, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "1.234", "1.", ".5", "-.50", "-", "+1", "1.x", "abc", "1.5"} {
+		c := RUB
+		if in == "1.5" {
+			c = JPY
+		}
+		if _, err := ParseMajor(in, c); !errors.Is(err, ErrInvalidAmount) {
+			t.Fatalf("ParseMajor(%q, %s) error = %v, want ErrInvalidAmount", in, c, err)
+		}
+	}
+	if _, err := ParseMajor("1", "XXX"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("ParseMajor(XXX) error = %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := New(100, RUB).Validate(); err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if err := New(MaxAmount, RUB).Validate(); err != nil {
+		t.Fatalf("Validate(MaxAmount) error: %v", err)
+	}
+	if err := New(0, RUB).Validate(); !errors.Is(err, ErrNotPositive) {
+		t.Fatalf("Validate(0) error = %v, want ErrNotPositive", err)
+	}
+	if err := New(MaxAmount+1, RUB).Validate(); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Validate(MaxAmount+1) error = %v, want ErrTooLarge", err)
+	}
+	if err := New(100, "GBP").Validate(); !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("Validate(GBP) error = %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	if got, err := MulAmount(250, 4); err != nil || got != 1000 {
+		t.Fatalf("MulAmount(250, 4) = %d, %v, want 1000", got, err)
+	}
+	if got, err := AddAmount(MaxAmount-1, 1); err != nil || got != MaxAmount {
+		t.Fatalf("AddAmount(MaxAmount-1, 1) = %d, %v, want MaxAmount", got, err)
+	}
+	// 2^62 * 4 wraps to 0 in int64.
+	if _, err := MulAmount(1<<62, 4); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("MulAmount(2^62, 4) error = %v, want ErrTooLarge", err)
+	}
+	if _, err := MulAmount(MaxAmount/2+1, 2); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("MulAmount above MaxAmount error = %v, want ErrTooLarge", err)
+	}
+	if _, err := AddAmount(MaxAmount, 1); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("AddAmount(MaxAmount, 1) error = %v, want ErrTooLarge", err)
+	}
+	if _, err := MulAmount(-1, 2); !errors.Is(err, ErrInvalidAmount) {
+		t.Fatalf("MulAmount(-1, 2) error = %v, want ErrInvalidAmount", err)
+	}
+}
+
+func TestParseCurrency(t *testing.T) {
+	if c, err := ParseCurrency(" usd "); err != nil || c != USD {
+		t.Fatalf("ParseCurrency(usd) = %q, %v", c, err)
+	}
+	if _, err := ParseCurrency("GBP"); !errors.Is(err, ErrUnknownCurrency) {
+		t.Fatalf("ParseCurrency(GBP) error = %v, want ErrUnknownCurrency", err)
+	}
+}
+
+func TestAmountJSON(t *testing.T) {
+	b, err := json.Marshal(struct {
+		Amount Amount `json:"amount"`
+	}{9007199254740993})
+	if err != nil || string(b) != `{"amount":"9007199254740993"}` {
+		t.Fatalf("Marshal() = %s, %v", b, err)
+	}
+
+	for in, want := range map[string]Amount{`"150050"`: 150050, `150050`: 150050, `"-5"`: -5} {
+		var a Amount
+		if err := json.Unmarshal([]byte(in), &a); err != nil || a != want {
+			t.Fatalf("Unmarshal(%s) = %d, %v, want %d", in, a, err, want)
+		}
+	}
+	for _, in := range []string{`"1.5"`, `1.5`, `"abc"`, `true`} {
+		var a Amount
+		if err := json.Unmarshal([]byte(in), &a); err == nil {
+			t.Fatalf("Unmarshal(%s) expected error", in)
+		}
+	}
+}
@@ -0,0 +1,241 @@
+// Package partition maintains tables that are range-partitioned by month on
+// created_at.
+//
+// A partition covers one UTC calendar month and is named <table>_pYYYYMM;
+// <table>_default catches rows outside of all of them; creating a partition
+// moves the rows of its month out of the default one. The Maintainer creates
+// the partitions of the coming months ahead of time, so inserts do not end up
+// in the default partition, and drops partitions whose whole month is older
+// than the table's retention.
+package partition
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Table is a partitioned table under maintenance.
+type Table struct {
+	Name string
+	// Retention is how long rows are kept: a partition is dropped once its
+	// month ended more than Retention ago. 0 keeps partitions forever.
+	Retention time.Duration
+	// KeepRows drops an expired partition only once it is empty, for tables
+	// whose rows leave on their own terms: orders move to orders_archive
+	// when they settle, and an unsettled order keeps its month.
+	KeepRows bool
+}
+
+// Store runs the DDL. PgStore implements it on a Postgres pool.
+type Store interface {
+	// Partitions returns the names of the partitions attached to table.
+	Partitions(ctx context.Context, table string) ([]string, error)
+	// Create attaches partition name to table for created_at in [from, to),
+	// moving the rows of that range out of the default partition. It must
+	// succeed if the partition already exists.
+	Create(ctx context.Context, table, name string, from, to time.Time) error
+	// Drop drops partition name of table, or with onlyEmpty leaves it alone
+	// while it has rows, and reports whether it is gone. It must succeed if
+	// the partition no longer exists.
+	Drop(ctx context.Context, table, name string, onlyEmpty bool) (bool, error)
+}
+
+// MonthStart returns the first instant of t's month in UTC.
+func MonthStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Name returns the name of table's partition for the month starting at month.
+func Name(table string, month time.Time) string {
+	return fmt.Sprintf("%s_p%s", table, month.UTC().Format("200601"))
+}
+
+// DefaultName returns the name of table's default partition.
+func DefaultName(table string) string {
+	return table + "_default"
+}
+
+// parseName returns the month of a partition named by Name; ok is false for
+// other partitions, such as the default one.
+func parseName(table, name string) (month time.Time, ok bool) {
+	suffix, found := strings.CutPrefix(name, table+"_p")
+	if !found || len(suffix) != 6 {
+		return time.Time{}, false
+	}
+	month, err := time.Parse("200601", suffix)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return month, true
+}
+
+// Plan returns the months to create so that t has partitions from now's
+// month through ahead months later, and the partitions past t's retention,
+// oldest first.
+func Plan(t Table, existing []string, now time.Time, ahead int) (create []time.Time, drop []string) {
+	have := make(map[time.Time]bool, len(existing))
+	for _, name := range existing {
+		month, ok := parseName(t.Name, name)
+		if !ok {
+			continue
+		}
+		have[month] = true
+		if t.Retention > 0 && !month.AddDate(0, 1, 0).After(now.Add(-t.Retention)) {
+			drop = append(drop, name)
+		}
+	}
+	sort.Strings(drop)
+
+	first := MonthStart(now)
+	for i := 0; i <= ahead; i++ {
+		month := first.AddDate(0, i, 0)
+		if !have[month] {
+			create = append(create, month)
+		}
+	}
+	return create, drop
+}
+
+// Maintainer keeps the partitions of a set of tables in shape. Store
+// operations tolerate existing and missing partitions, so every replica may
+// run one.
+type Maintainer struct {
+	store    Store
+	tables   []Table
+	ahead    int
+	interval time.Duration
+	logger   *slog.Logger
+	now      func() time.Time
+}
+
+// New builds a maintainer that keeps partitions for the current month and
+// ahead more, checking every interval.
+func New(store Store, ahead int, interval time.Duration, logger *slog.Logger, tables ...Table) *Maintainer {
+	return &Maintainer{store: store, tables: tables, ahead: ahead, interval: interval, logger: logger, now: time.Now}
+}
+
+// Run maintains the tables right away and then every interval until ctx is
+// done.
+func (m *Maintainer) Run(ctx context.Context) error {
+	t := time.NewTicker(m.interval)
+	defer t.Stop()
+
+	for {
+		if _, _, err := m.MaintainOnce(ctx); err != nil && ctx.Err() == nil {
+			m.logger.Error("partition maintenance failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			m.logger.Info("partition maintainer stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// MaintainOnce creates missing partitions and drops expired ones in every
+// table and returns how many it created and dropped. A failing table does not
+// stop the others; their errors are joined.
+func (m *Maintainer) MaintainOnce(ctx context.Context) (created, dropped int, err error) {
+	now := m.now()
+	var errs []error
+	for _, t := range m.tables {
+		c, d, err := m.maintain(ctx, t, now)
+		created += c
+		dropped += d
+		if err != nil {
+			errs = append(errs, fmt.Errorf("partition %s: %w", t.Name, err))
+		}
+	}
+	return created, dropped, errors.Join(errs...)
+}
+
+func (m *Maintainer) maintain(ctx context.Context, t Table, now time.Time) (created, dropped int, err error) {
+	existing, err := m.store.Partitions(ctx, t.Name)
+	if err != nil {
+		return 0, 0, err
+	}
+	create, drop := Plan(t, existing, now, m.ahead)
+	for _, month := range create {
+		name := Name(t.Name, month)
+		if err := m.store.Create(ctx, t.Name, name, month, month.AddDate(0, 1, 0)); err != nil {
+			return created, dropped, fmt.Errorf("create %s: %w", name, err)
+		}
+		m.logger.Info("partition created", "table", t.Name, "partition", name)
+		created++
+	}
+	for _, name := range drop {
+		ok, err := m.store.Drop(ctx, t.Name, name, t.KeepRows)
+		if err != nil {
+			return created, dropped, fmt.Errorf("drop %s: %w", name, err)
+		}
+		if !ok {
+			m.logger.Info("expired partition kept, it still has rows", "table", t.Name, "partition", name)
+			continue
+		}
+		m.logger.Info("partition dropped", "table", t.Name, "partition", name)
+		dropped++
+	}
+	return created, dropped, nil
+}
+
+// ListSQL selects the names of the partitions of the table given as $1.
+const ListSQL = `SELECT c.relname::text
+FROM pg_inherits i
+JOIN pg_class c ON c.oid = i.inhrelid
+WHERE i.inhparent = to_regclass($1::text)`
+
+// A partition cannot be attached while the default partition holds rows of
+// its range, so PgStore creates it detached with CreateSQL, moves those rows
+// into it with MoveSQL and attaches it with AttachSQL, all in one
+// transaction. DDL takes no parameters, so the bounds are inlined.
+
+// CreateSQL returns the statement creating partition name shaped like table.
+func CreateSQL(table, name string) string {
+	return fmt.Sprintf("CREATE TABLE %s (LIKE %s INCLUDING DEFAULTS INCLUDING CONSTRAINTS)", quoteIdent(name), quoteIdent(table))
+}
+
+// MoveSQL returns the statement moving the rows of [from, to) from the
+// default partition of table to partition name.
+func MoveSQL(table, name string, from, to time.Time) string {
+	return fmt.Sprintf("WITH moved AS (DELETE FROM %s WHERE created_at >= %s AND created_at < %s RETURNING *) INSERT INTO %s SELECT * FROM moved",
+		quoteIdent(DefaultName(table)), quoteTime(from), quoteTime(to), quoteIdent(name))
+}
+
+// AttachSQL returns the statement attaching partition name to table for
+// [from, to).
+func AttachSQL(table, name string, from, to time.Time) string {
+	return fmt.Sprintf("ALTER TABLE %s ATTACH PARTITION %s FOR VALUES FROM (%s) TO (%s)",
+		quoteIdent(table), quoteIdent(name), quoteTime(from), quoteTime(to))
+}
+
+// ExistsSQL selects whether the relation given as $1 exists.
+const ExistsSQL = `SELECT to_regclass($1::text) IS NOT NULL`
+
+// HasRowsSQL returns the query selecting whether partition name has rows.
+func HasRowsSQL(name string) string {
+	return "SELECT EXISTS (SELECT 1 FROM " + quoteIdent(name) + ")"
+}
+
+// DropSQL returns the statement dropping partition name.
+func DropSQL(name string) string {
+	return "DROP TABLE IF EXISTS " + quoteIdent(name)
+}
+
+// LockSQL takes a transaction-level advisory lock on the table given as $1,
+// serializing DDL of replicas maintaining the same table.
+const LockSQL = `SELECT pg_advisory_xact_lock(hashtext('partition:' || $1::text))`
+
+func quoteIdent(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+func quoteTime(t time.Time) string {
+	return "'" + t.UTC().Format("2006-01-02 15:04:05") + "+00'"
+}
@@ -0,0 +1,159 @@
+package partition
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// memStore is an in-memory Store.
+type memStore struct {
+	parts   map[string][]string
+	failOn  string
+	created []string
+	// nonEmpty holds partitions that still have rows.
+	nonEmpty map[string]bool
+}
+
+func (s *memStore) Partitions(_ context.Context, table string) ([]string, error) {
+	return append([]string(nil), s.parts[table]...), nil
+}
+
+func (s *memStore) Create(_ context.Context, table, name string, _, _ time.Time) error {
+	if name == s.failOn {
+		return errors.New("boom")
+	}
+	s.parts[table] = append(s.parts[table], name)
+	s.created = append(s.created, name)
+	return nil
+}
+
+func (s *memStore) Drop(_ context.Context, table, name string, onlyEmpty bool) (bool, error) {
+	if onlyEmpty && s.nonEmpty[name] {
+		return false, nil
+	}
+	kept := s.parts[table][:0]
+	for _, p := range s.parts[table] {
+		if p != name {
+			kept = append(kept, p)
+		}
+	}
+	s.parts[table] = kept
+	return true, nil
+}
+
+func month(y int, m time.Month) time.Time {
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+	existing := []string{"orders_default", "orders_p202607", "orders_p202608", "orders_p202609", "orders_p202610", "other_p202601"}
+
+	create, drop := Plan(Table{Name: "orders"}, existing, now, 2)
+	if want := []time.Time{month(2026, time.November), month(2026, time.December)}; !reflect.DeepEqual(create, want) {
+		t.Fatalf("create = %v, want %v", create, want)
+	}
+	if drop != nil {
+		t.Fatalf("drop = %v without retention", drop)
+	}
+
+	// 45 days back is 2026-09-01 12:00: August ended before that, September
+	// did not.
+	_, drop = Plan(Table{Name: "orders", Retention: 45 * 24 * time.Hour}, existing, now, 2)
+	if want := []string{"orders_p202607", "orders_p202608"}; !reflect.DeepEqual(drop, want) {
+		t.Fatalf("drop = %v, want %v", drop, want)
+	}
+}
+
+func TestPlanCrossesYear(t *testing.T) {
+	now := time.Date(2026, time.December, 31, 23, 0, 0, 0, time.FixedZone("UTC-3", -3*3600))
+	create, _ := Plan(Table{Name: "t"}, nil, now, 1)
+	if want := []time.Time{month(2027, time.January), month(2027, time.February)}; !reflect.DeepEqual(create, want) {
+		t.Fatalf("create = %v, want %v", create, want)
+	}
+}
+
+func TestMaintainOnce(t *testing.T) {
+	store := &memStore{parts: map[string][]string{
+		"orders":         {"orders_default", "orders_p202601"},
+		"balance_ledger": {"balance_ledger_p202610"},
+	}}
+	m := New(store, 1, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Table{Name: "orders", Retention: 30 * 24 * time.Hour},
+		Table{Name: "balance_ledger"},
+	)
+	m.now = func() time.Time { return time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC) }
+
+	created, dropped, err := m.MaintainOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created != 3 || dropped != 1 {
+		t.Fatalf("created, dropped = %d, %d, want 3, 1", created, dropped)
+	}
+	if want := []string{"orders_default", "orders_p202610", "orders_p202611"}; !reflect.DeepEqual(store.parts["orders"], want) {
+		t.Fatalf("orders partitions = %v, want %v", store.parts["orders"], want)
+	}
+
+	// Nothing left to do.
+	created, dropped, err = m.MaintainOnce(context.Background())
+	if err != nil || created != 0 || dropped != 0 {
+		t.Fatalf("second run = %d, %d, %v", created, dropped, err)
+	}
+}
+
+func TestMaintainOnceContinuesAfterFailure(t *testing.T) {
+	store := &memStore{parts: map[string][]string{}, failOn: "a_p202610"}
+	m := New(store, 0, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)), Table{Name: "a"}, Table{Name: "b"})
+	m.now = func() time.Time { return time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC) }
+
+	created, _, err := m.MaintainOnce(context.Background())
+	if err == nil {
+		t.Fatal("expected the failure of a to be reported")
+	}
+	if created != 1 || !reflect.DeepEqual(store.created, []string{"b_p202610"}) {
+		t.Fatalf("created = %d %v, want b_p202610 only", created, store.created)
+	}
+}
+
+func TestMaintainOnceKeepsRows(t *testing.T) {
+	store := &memStore{
+		parts:    map[string][]string{"orders": {"orders_p202607", "orders_p202608", "orders_p202610"}},
+		nonEmpty: map[string]bool{"orders_p202607": true},
+	}
+	m := New(store, 0, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Table{Name: "orders", Retention: 30 * 24 * time.Hour, KeepRows: true})
+	m.now = func() time.Time { return time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC) }
+
+	_, dropped, err := m.MaintainOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dropped != 1 {
+		t.Fatalf("dropped = %d, want 1", dropped)
+	}
+	if want := []string{"orders_p202607", "orders_p202610"}; !reflect.DeepEqual(store.parts["orders"], want) {
+		t.Fatalf("orders partitions = %v, want %v", store.parts["orders"], want)
+	}
+}
+
+func TestCreateSQL(t *testing.T) {
+	from, to := month(2026, time.October), month(2026, time.November)
+	if got, want := CreateSQL("orders", "orders_p202610"), `CREATE TABLE "orders_p202610" (LIKE "orders" INCLUDING DEFAULTS INCLUDING CONSTRAINTS)`; got != want {
+		t.Fatalf("CreateSQL = %s\nwant %s", got, want)
+	}
+	if got, want := MoveSQL("orders", "orders_p202610", from, to), `WITH moved AS (DELETE FROM "orders_default" WHERE created_at >= '2026-10-01 00:00:00+00' AND created_at < '2026-11-01 00:00:00+00' RETURNING *) INSERT INTO "orders_p202610" SELECT * FROM moved`; got != want {
+		t.Fatalf("MoveSQL = %s\nwant %s", got, want)
+	}
+	if got, want := AttachSQL("orders", "orders_p202610", from, to), `ALTER TABLE "orders" ATTACH PARTITION "orders_p202610" FOR VALUES FROM ('2026-10-01 00:00:00+00') TO ('2026-11-01 00:00:00+00')`; got != want {
+		t.Fatalf("AttachSQL = %s\nwant %s", got, want)
+	}
+	if got := DropSQL(`x"y`); got != `DROP TABLE IF EXISTS "x""y"` {
+		t.Fatalf("DropSQL = %s", got)
+	}
+}
@@ -0,0 +1,93 @@
+package partition
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var _ Store = (*PgStore)(nil)
+
+// PgStore runs the DDL of the maintainer on a Postgres primary.
+type PgStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPgStore(pool *pgxpool.Pool) *PgStore {
+	return &PgStore{pool: pool}
+}
+
+func (s *PgStore) Partitions(ctx context.Context, table string) ([]string, error) {
+	rows, err := s.pool.Query(ctx, ListSQL, table)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+func (s *PgStore) Create(ctx context.Context, table, name string, from, to time.Time) error {
+	return s.locked(ctx, table, func(tx pgx.Tx) error {
+		if ok, err := exists(ctx, tx, name); err != nil || ok {
+			return err
+		}
+		if _, err := tx.Exec(ctx, CreateSQL(table, name)); err != nil {
+			return err
+		}
+		hasDefault, err := exists(ctx, tx, DefaultName(table))
+		if err != nil {
+			return err
+		}
+		if hasDefault {
+			if _, err := tx.Exec(ctx, MoveSQL(table, name, from, to)); err != nil {
+				return err
+			}
+		}
+		_, err = tx.Exec(ctx, AttachSQL(table, name, from, to))
+		return err
+	})
+}
+
+func (s *PgStore) Drop(ctx context.Context, table, name string, onlyEmpty bool) (bool, error) {
+	dropped := false
+	err := s.locked(ctx, table, func(tx pgx.Tx) error {
+		if onlyEmpty {
+			ok, err := exists(ctx, tx, name)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				dropped = true
+				return nil
+			}
+			var hasRows bool
+			if err := tx.QueryRow(ctx, HasRowsSQL(name)).Scan(&hasRows); err != nil || hasRows {
+				return err
+			}
+		}
+		if _, err := tx.Exec(ctx, DropSQL(name)); err != nil {
+			return err
+		}
+		dropped = true
+		return nil
+	})
+	return dropped, err
+}
+
+// locked runs fn holding the table's maintenance lock, so replicas do not
+// race on the same partition.
+func (s *PgStore) locked(ctx context.Context, table string, fn func(pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, s.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, LockSQL, table); err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+func exists(ctx context.Context, tx pgx.Tx, name string) (bool, error) {
+	var ok bool
+	err := tx.QueryRow(ctx, ExistsSQL, quoteIdent(name)).Scan(&ok)
+	return ok, err
+}
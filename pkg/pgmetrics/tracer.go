@@ -0,0 +1,165 @@
+// Package pgmetrics records the latency of every statement run through a pgx
+// pool, exported as the expvar "db_queries", and logs slow statements.
+package pgmetrics
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// latencyBuckets are the upper bounds of the query latency histogram.
+var latencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
+// queryStats are the latency histograms of all pools, keyed by sqlc query
+// name, exported as the expvar "db_queries".
+var queryStats = &queryMetrics{byName: map[string]*queryHistogram{}}
+
+func init() {
+	expvar.Publish("db_queries", expvar.Func(func() any { return queryStats.snapshot() }))
+}
+
+type queryMetrics struct {
+	mu     sync.Mutex
+	byName map[string]*queryHistogram
+}
+
+type queryHistogram struct {
+	count   int64
+	errors  int64
+	totalMs float64
+	// buckets[i] counts queries up to latencyBuckets[i]; the last one
+	// counts the slower rest.
+	buckets []int64
+}
+
+func (m *queryMetrics) observe(name string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.byName[name]
+	if !ok {
+		h = &queryHistogram{buckets: make([]int64, len(latencyBuckets)+1)}
+		m.byName[name] = h
+	}
+	h.count++
+	if failed {
+		h.errors++
+	}
+	h.totalMs += float64(d) / float64(time.Millisecond)
+	i := 0
+	for i < len(latencyBuckets) && d > latencyBuckets[i] {
+		i++
+	}
+	h.buckets[i]++
+}
+
+func (m *queryMetrics) snapshot() map[string]any {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make(map[string]any, len(m.byName))
+	for name, h := range m.byName {
+		buckets := make(map[string]int64, len(h.buckets))
+		for i, n := range h.buckets {
+			le := "+Inf"
+			if i < len(latencyBuckets) {
+				le = latencyBuckets[i].String()
+			}
+			buckets[le] = n
+		}
+		out[name] = map[string]any{
+			"count":    h.count,
+			"errors":   h.errors,
+			"total_ms": h.totalMs,
+			"buckets":  buckets,
+		}
+	}
+	return out
+}
+
+// Tracer is a pgx.QueryTracer that records every statement in queryStats
+// and logs statements slower than slow; 0 disables the log. Parameters are
+// never logged, only their types.
+type Tracer struct {
+	slow   time.Duration
+	logger *slog.Logger
+}
+
+var _ pgx.QueryTracer = Tracer{}
+
+// NewTracer returns a tracer logging slow statements to logger.
+func NewTracer(logger *slog.Logger, slow time.Duration) Tracer {
+	return Tracer{slow: slow, logger: logger}
+}
+
+type traceKey struct{}
+
+type traceStart struct {
+	at   time.Time
+	name string
+	sql  string
+	args []any
+}
+
+func (t Tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceStart{at: time.Now(), name: queryName(data.SQL), sql: data.SQL, args: data.Args})
+}
+
+func (t Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(traceKey{}).(traceStart)
+	if !ok {
+		return
+	}
+	d := time.Since(start.at)
+	queryStats.observe(start.name, d, data.Err != nil)
+	if t.slow > 0 && d >= t.slow {
+		t.logger.Warn("slow query",
+			"query", start.name, "duration", d, "sql", statement(start.sql), "args", redact(start.args), "err", data.Err)
+	}
+}
+
+// queryName returns the name from the "-- name: GetOrder :one" header sqlc
+// puts in front of every generated query, or "other" for hand-written SQL.
+func queryName(sql string) string {
+	rest, ok := strings.CutPrefix(sql, "-- name: ")
+	if !ok {
+		return "other"
+	}
+	if i := strings.IndexAny(rest, " \n"); i > 0 {
+		return rest[:i]
+	}
+	return "other"
+}
+
+// statement drops the sqlc header and folds whitespace, so the statement
+// fits on one log line.
+func statement(sql string) string {
+	if strings.HasPrefix(sql, "-- name: ") {
+		if i := strings.IndexByte(sql, '\n'); i >= 0 {
+			sql = sql[i+1:]
+		}
+	}
+	return strings.Join(strings.Fields(sql), " ")
+}
+
+// redact replaces query parameters by their Go types.
+func redact(args []any) []string {
+	out := make([]string, len(args))
+	for i, a := range args {
+		out[i] = fmt.Sprintf("$%d=%T", i+1, a)
+	}
+	return out
+}
@@ -0,0 +1,59 @@
+package pgmetrics
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func TestQueryName(t *testing.T) {
+	cases := map[string]string{
+		"-- name: GetOrder :one\nSELECT 1":    "GetOrder",
+		"-- name: ListOrders :many\nSELECT 1": "ListOrders",
+		"SELECT set_config('x', $1, true)":    "other",
+		"-- name: \nSELECT 1":                 "other",
+	}
+	for sql, want := range cases {
+		if got := queryName(sql); got != want {
+			t.Fatalf("queryName(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
+
+func TestStatementAndRedact(t *testing.T) {
+	got := statement("-- name: GetOrder :one\nSELECT *\n  FROM orders\n WHERE order_id = $1")
+	if got != "SELECT * FROM orders WHERE order_id = $1" {
+		t.Fatalf("statement = %q", got)
+	}
+	if got := redact([]any{"secret", int64(5)}); !reflect.DeepEqual(got, []string{"$1=string", "$2=int64"}) {
+		t.Fatalf("redact = %v", got)
+	}
+}
+
+func TestQueryTracerRecordsLatency(t *testing.T) {
+	tr := NewTracer(slog.New(slog.NewTextHandler(io.Discard, nil)), 0)
+	ctx := tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "-- name: TracerTestQuery :one\nSELECT 1"})
+	tr.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{})
+	ctx = tr.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: "-- name: TracerTestQuery :one\nSELECT 1"})
+	tr.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	m := &queryMetrics{byName: map[string]*queryHistogram{}}
+	m.observe("q", 3*time.Millisecond, false)
+	m.observe("q", 2*time.Second, true)
+	h := m.snapshot()["q"].(map[string]any)
+	buckets := h["buckets"].(map[string]int64)
+	if h["count"] != int64(2) || h["errors"] != int64(1) || buckets["5ms"] != 1 || buckets["+Inf"] != 1 || buckets["1ms"] != 0 {
+		t.Fatalf("histogram = %v", h)
+	}
+
+	traced := queryStats.snapshot()["TracerTestQuery"].(map[string]any)
+	if traced["count"] != int64(2) || traced["errors"] != int64(1) {
+		t.Fatalf("traced = %v, want 2 queries, 1 failed", traced)
+	}
+}
@@ -0,0 +1,202 @@
+// Package pgtx opens the Postgres pools and runs the transactions of the
+// repositories of all services.
+//
+// A Manager begins a transaction, caps its statements at what is left of the
+// caller's deadline, runs fn and commits; an error from fn rolls it back. A
+// transaction that fails with a serialization failure or a deadlock is run
+// again from the start, so fn must only have database effects (or tolerate
+// being repeated). Calling Do with the context fn was given nests the call
+// in a savepoint: its failure rolls back only the nested part, and retries
+// always restart the outermost transaction.
+package pgtx
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ilyaytrewq/payments-service/pkg/deadline"
+)
+
+// metrics counts retried transactions and those that still failed after
+// the last retry.
+var metrics = expvar.NewMap("pgtx")
+
+// Postgres error codes of transactions that may succeed when run again.
+const (
+	codeSerializationFailure = "40001"
+	codeDeadlockDetected     = "40P01"
+)
+
+// Beginner starts transactions; *pgxpool.Pool implements it.
+type Beginner interface {
+	BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+}
+
+// Config tunes a Manager. The zero value never retries.
+type Config struct {
+	// Retries is how many times a transaction failing with a serialization
+	// failure or a deadlock is run again.
+	Retries int
+	// Backoff is the pause before the first retry; it doubles with each
+	// retry. Zero retries right away.
+	Backoff time.Duration
+	// BeforeCommit runs after fn, just before the commit of the outermost
+	// transaction; an error rolls it back. Used for fault injection.
+	BeforeCommit func(ctx context.Context) error
+}
+
+// Manager runs transactions on one database.
+type Manager struct {
+	db     Beginner
+	cfg    Config
+	logger *slog.Logger
+}
+
+// New returns a Manager for db. logger gets the transaction logs.
+func New(db Beginner, cfg Config, logger *slog.Logger) *Manager {
+	return &Manager{db: db, cfg: cfg, logger: logger}
+}
+
+// txKey is the context key of the open transaction of a Manager.
+type txKey struct{ m *Manager }
+
+// Do runs fn in a transaction with the server's default isolation level
+// (READ COMMITTED), or in a savepoint of the transaction carried by ctx.
+func (m *Manager) Do(ctx context.Context, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	return m.DoWith(ctx, pgx.TxOptions{}, fn)
+}
+
+// DoWith is Do with explicit transaction options such as
+// pgx.TxOptions{IsoLevel: pgx.Serializable}. A nested call runs with the
+// options of the outer transaction.
+func (m *Manager) DoWith(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	if tx, ok := ctx.Value(txKey{m}).(pgx.Tx); ok {
+		return m.savepoint(ctx, tx, fn)
+	}
+	backoff := m.cfg.Backoff
+	for attempt := 0; ; attempt++ {
+		err := m.run(ctx, opts, fn)
+		if err == nil || !Retryable(err) {
+			return err
+		}
+		if attempt >= m.cfg.Retries || ctx.Err() != nil {
+			if m.cfg.Retries > 0 {
+				metrics.Add("retries_exhausted", 1)
+			}
+			return err
+		}
+		metrics.Add("retries", 1)
+		m.logger.Warn("transaction retry", "err", err, "attempt", attempt+1, "isolation", string(opts.IsoLevel), "backoff", backoff)
+		if backoff > 0 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+	}
+}
+
+// run makes a single attempt of the outermost transaction.
+func (m *Manager) run(ctx context.Context, opts pgx.TxOptions, fn func(ctx context.Context, tx pgx.Tx) error) (err error) {
+	start := time.Now()
+	m.logger.Debug("transaction start")
+	tx, err := m.db.BeginTx(ctx, opts)
+	if err != nil {
+		m.logger.Error("transaction begin failed", "err", err)
+		return err
+	}
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+				m.logger.Error("transaction rollback failed", "err", rbErr)
+			}
+			m.logger.Error("transaction failed", "err", err, "duration", time.Since(start))
+		} else {
+			m.logger.Debug("transaction completed", "duration", time.Since(start))
+		}
+	}()
+
+	if err = applyStatementTimeout(ctx, tx); err != nil {
+		m.logger.Error("transaction statement timeout failed", "err", err)
+		return err
+	}
+	if err = fn(context.WithValue(ctx, txKey{m}, tx), tx); err != nil {
+		m.logger.Error("transaction function failed", "err", err)
+		return err
+	}
+	if m.cfg.BeforeCommit != nil {
+		if err = m.cfg.BeforeCommit(ctx); err != nil {
+			m.logger.Warn("transaction before commit failed", "err", err)
+			return err
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// savepoint runs fn in a savepoint of tx.
+func (m *Manager) savepoint(ctx context.Context, tx pgx.Tx, fn func(ctx context.Context, tx pgx.Tx) error) error {
+	sp, err := tx.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{m}, sp), sp); err != nil {
+		if rbErr := sp.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
+			m.logger.Error("savepoint rollback failed", "err", rbErr)
+		}
+		return err
+	}
+	return sp.Commit(ctx)
+}
+
+// Retryable reports whether err is a serialization failure or a deadlock,
+// after which the whole transaction may be run again.
+func Retryable(err error) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	return pgErr.Code == codeSerializationFailure || pgErr.Code == codeDeadlockDetected
+}
+
+// ParseIsolation parses an isolation level as written in SQL, case
+// insensitive and with "_" or "-" allowed for the space, e.g. "serializable"
+// or "repeatable_read". Empty means the server default.
+func ParseIsolation(s string) (pgx.TxIsoLevel, error) {
+	level := pgx.TxIsoLevel(strings.ToLower(strings.NewReplacer("_", " ", "-", " ").Replace(strings.TrimSpace(s))))
+	switch level {
+	case "", pgx.ReadCommitted, pgx.RepeatableRead, pgx.Serializable, pgx.ReadUncommitted:
+		return level, nil
+	}
+	return "", fmt.Errorf("pgtx: unknown isolation level %q", s)
+}
+
+// execer is the part of pgx.Tx that applyStatementTimeout needs.
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+}
+
+// applyStatementTimeout caps the statements of tx at what is left of ctx's
+// deadline, so a request the caller gave up on does not keep the database
+// busy. A ctx without a deadline leaves the server default in place.
+func applyStatementTimeout(ctx context.Context, tx execer) error {
+	left, ok := deadline.StatementTimeout(ctx)
+	if !ok {
+		return nil
+	}
+	if left <= 0 {
+		return context.DeadlineExceeded
+	}
+	_, err := tx.Exec(ctx, "SELECT set_config('statement_timeout', $1, true)", strconv.FormatInt(left.Milliseconds(), 10))
+	return err
+}
@@ -0,0 +1,199 @@
+package pgtx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// fakeDB hands out fakeTx transactions and records how they ended.
+type fakeDB struct {
+	began     []pgx.TxOptions
+	committed int
+	rolled    int
+	// savepoints counts released and rolled back savepoints.
+	released, spRolled int
+}
+
+func (d *fakeDB) BeginTx(_ context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	d.began = append(d.began, opts)
+	return &fakeTx{db: d}, nil
+}
+
+// fakeTx is a pgx.Tx; methods Manager does not use panic on the nil Tx.
+type fakeTx struct {
+	pgx.Tx
+	db     *fakeDB
+	nested bool
+	closed bool
+}
+
+func (t *fakeTx) Begin(context.Context) (pgx.Tx, error) {
+	return &fakeTx{db: t.db, nested: true}, nil
+}
+
+func (t *fakeTx) Commit(context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+	if t.nested {
+		t.db.released++
+	} else {
+		t.db.committed++
+	}
+	return nil
+}
+
+func (t *fakeTx) Rollback(context.Context) error {
+	if t.closed {
+		return pgx.ErrTxClosed
+	}
+	t.closed = true
+	if t.nested {
+		t.db.spRolled++
+	} else {
+		t.db.rolled++
+	}
+	return nil
+}
+
+func (t *fakeTx) Exec(context.Context, string, ...any) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, nil
+}
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestDoRetriesSerializationFailures(t *testing.T) {
+	db := &fakeDB{}
+	m := New(db, Config{Retries: 2, Backoff: time.Millisecond}, discard)
+	calls := 0
+	err := m.DoWith(context.Background(), pgx.TxOptions{IsoLevel: pgx.Serializable}, func(context.Context, pgx.Tx) error {
+		calls++
+		if calls < 3 {
+			return &pgconn.PgError{Code: "40001"}
+		}
+		return nil
+	})
+	if err != nil || calls != 3 {
+		t.Fatalf("DoWith() = %v after %d calls, want success on the third", err, calls)
+	}
+	if db.committed != 1 || db.rolled != 2 || len(db.began) != 3 || db.began[0].IsoLevel != pgx.Serializable {
+		t.Fatalf("committed %d, rolled back %d, began %v; want 1, 2 and three serializable transactions", db.committed, db.rolled, db.began)
+	}
+
+	calls = 0
+	err = m.Do(context.Background(), func(context.Context, pgx.Tx) error {
+		calls++
+		return &pgconn.PgError{Code: "40P01"}
+	})
+	if !Retryable(err) || calls != 3 {
+		t.Fatalf("Do() = %v after %d calls, want the deadlock after 1 try and 2 retries", err, calls)
+	}
+}
+
+func TestDoDoesNotRetryOtherErrors(t *testing.T) {
+	db := &fakeDB{}
+	m := New(db, Config{Retries: 3}, discard)
+	calls := 0
+	uniq := &pgconn.PgError{Code: "23505"}
+	if err := m.Do(context.Background(), func(context.Context, pgx.Tx) error {
+		calls++
+		return uniq
+	}); !errors.Is(err, uniq) || calls != 1 || db.rolled != 1 {
+		t.Fatalf("Do() = %v after %d calls, %d rollbacks; want the error once", err, calls, db.rolled)
+	}
+
+	injected := errors.New("injected")
+	m = New(db, Config{BeforeCommit: func(context.Context) error { return injected }}, discard)
+	if err := m.Do(context.Background(), func(context.Context, pgx.Tx) error { return nil }); !errors.Is(err, injected) || db.committed != 0 {
+		t.Fatalf("Do() = %v with %d commits, want the BeforeCommit error and no commit", err, db.committed)
+	}
+}
+
+func TestNestedDoUsesSavepoints(t *testing.T) {
+	db := &fakeDB{}
+	m := New(db, Config{}, discard)
+	failed := errors.New("nested failed")
+	err := m.Do(context.Background(), func(ctx context.Context, outer pgx.Tx) error {
+		if err := m.Do(ctx, func(context.Context, pgx.Tx) error { return nil }); err != nil {
+			return err
+		}
+		if err := m.Do(ctx, func(context.Context, pgx.Tx) error { return failed }); !errors.Is(err, failed) {
+			t.Errorf("nested Do() = %v, want its error", err)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Do() error: %v", err)
+	}
+	if len(db.began) != 1 || db.committed != 1 || db.released != 1 || db.spRolled != 1 {
+		t.Fatalf("began %d, committed %d, savepoints released %d and rolled back %d; want 1, 1, 1, 1", len(db.began), db.committed, db.released, db.spRolled)
+	}
+
+	// Another manager's transaction does not nest.
+	other := New(db, Config{}, discard)
+	_ = m.Do(context.Background(), func(ctx context.Context, _ pgx.Tx) error {
+		return other.Do(ctx, func(context.Context, pgx.Tx) error { return nil })
+	})
+	if len(db.began) != 3 {
+		t.Fatalf("began %d transactions, want a separate one for the other manager", len(db.began))
+	}
+}
+
+func TestParseIsolation(t *testing.T) {
+	cases := map[string]pgx.TxIsoLevel{
+		"":                "",
+		"serializable":    pgx.Serializable,
+		"REPEATABLE_READ": pgx.RepeatableRead,
+		" read-committed": pgx.ReadCommitted,
+	}
+	for in, want := range cases {
+		if got, err := ParseIsolation(in); err != nil || got != want {
+			t.Fatalf("ParseIsolation(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseIsolation("snapshot"); err == nil {
+		t.Fatal("ParseIsolation(\"snapshot\") succeeded")
+	}
+}
+
+type fakeExecer struct {
+	sql  string
+	args []any
+}
+
+func (f *fakeExecer) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	f.sql, f.args = sql, args
+	return pgconn.CommandTag{}, nil
+}
+
+func TestApplyStatementTimeout(t *testing.T) {
+	var tx fakeExecer
+	if err := applyStatementTimeout(context.Background(), &tx); err != nil || tx.sql != "" {
+		t.Fatalf("no deadline: err = %v, sql = %q; want nothing executed", err, tx.sql)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	if err := applyStatementTimeout(ctx, &tx); err != nil {
+		t.Fatalf("applyStatementTimeout() error: %v", err)
+	}
+	ms, err := strconv.Atoi(tx.args[0].(string))
+	if err != nil || ms < 1000 || ms > 1500 {
+		t.Fatalf("statement_timeout = %v, want about 1500ms", tx.args)
+	}
+
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+	if err := applyStatementTimeout(expired, &tx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expired: err = %v, want DeadlineExceeded", err)
+	}
+}
@@ -0,0 +1,132 @@
+package pgtx
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ilyaytrewq/payments-service/pkg/pgmetrics"
+)
+
+// PoolOptions tunes the connection pool and the startup connectivity check.
+// Zero values keep the pgxpool defaults.
+type PoolOptions struct {
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+
+	ConnectRetries int
+	ConnectBackoff time.Duration
+
+	// SlowQueryThreshold logs statements that take at least that long;
+	// 0 disables the log. Query metrics are recorded regardless.
+	SlowQueryThreshold time.Duration
+	// StatementTimeout caps every statement on the pool's connections,
+	// including reads and background jobs outside a transaction; 0 keeps
+	// the server default. Transactions with a deadline lower it further.
+	StatementTimeout time.Duration
+}
+
+// minConnectBackoff is the first ping retry delay when ConnectBackoff is
+// smaller, so a zero backoff does not retry in a tight loop.
+const minConnectBackoff = 100 * time.Millisecond
+
+// NewPool creates the pool and pings the database until it answers, retrying
+// up to ConnectRetries times with doubling backoff, so the service does not
+// report itself started against a dead database. The first pool a process
+// creates is exported as the expvar "db_pool".
+func NewPool(ctx context.Context, url string, opts PoolOptions, logger *slog.Logger) (*pgxpool.Pool, error) {
+	cfg, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxConns > 0 {
+		cfg.MaxConns = opts.MaxConns
+	}
+	if opts.MinConns > 0 {
+		cfg.MinConns = opts.MinConns
+	}
+	if opts.MaxConnLifetime > 0 {
+		cfg.MaxConnLifetime = opts.MaxConnLifetime
+	}
+	if opts.StatementTimeout > 0 {
+		cfg.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(opts.StatementTimeout.Milliseconds(), 10)
+	}
+	cfg.ConnConfig.Tracer = pgmetrics.NewTracer(logger, opts.SlowQueryThreshold)
+
+	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	backoff := max(opts.ConnectBackoff, minConnectBackoff)
+	for attempt := 0; ; attempt++ {
+		err = pool.Ping(ctx)
+		if err == nil {
+			break
+		}
+		if attempt >= opts.ConnectRetries {
+			pool.Close()
+			return nil, err
+		}
+		logger.Warn("database ping failed, retrying", "err", err, "attempt", attempt+1, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			pool.Close()
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	logger.Info("database pool ready", "max_conns", cfg.MaxConns, "min_conns", cfg.MinConns, "max_conn_lifetime", cfg.MaxConnLifetime)
+	publishPoolStats(pool)
+	return pool, nil
+}
+
+var publishOnce sync.Once
+
+// publishPoolStats exports pool statistics as the expvar "db_pool".
+func publishPoolStats(pool *pgxpool.Pool) {
+	publishOnce.Do(func() {
+		expvar.Publish("db_pool", expvar.Func(func() any {
+			return PoolStats(pool)
+		}))
+	})
+}
+
+// PoolStats reports the connection statistics of pool.
+func PoolStats(pool *pgxpool.Pool) map[string]int64 {
+	s := pool.Stat()
+	return map[string]int64{
+		"total_conns":          int64(s.TotalConns()),
+		"idle_conns":           int64(s.IdleConns()),
+		"acquired_conns":       int64(s.AcquiredConns()),
+		"max_conns":            int64(s.MaxConns()),
+		"acquire_count":        s.AcquireCount(),
+		"empty_acquire_count":  s.EmptyAcquireCount(),
+		"canceled_acquires":    s.CanceledAcquireCount(),
+		"acquire_duration_ms":  s.AcquireDuration().Milliseconds(),
+		"new_conns_count":      s.NewConnsCount(),
+		"max_lifetime_destroy": s.MaxLifetimeDestroyCount(),
+	}
+}
+
+// IsConnError reports whether err came from reaching the server rather than
+// from the query itself, so a read on a replica can be retried on the
+// primary.
+func IsConnError(err error) bool {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false
+	}
+	var pgErr *pgconn.PgError
+	return !errors.As(err, &pgErr)
+}
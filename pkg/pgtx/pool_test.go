@@ -0,0 +1,64 @@
+package pgtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestNewPoolInvalidURL(t *testing.T) {
+	if _, err := NewPool(context.Background(), "://not-a-url", PoolOptions{}, discard); err == nil {
+		t.Fatal("NewPool() with invalid url expected error")
+	}
+}
+
+func TestNewPoolGivesUpAfterRetries(t *testing.T) {
+	start := time.Now()
+	_, err := NewPool(context.Background(), "postgres://u:p@127.0.0.1:1/db?connect_timeout=1", PoolOptions{
+		ConnectRetries: 2,
+	}, discard)
+	if err == nil {
+		t.Fatal("NewPool() against closed port expected error")
+	}
+	// A zero backoff starts at minConnectBackoff: 100ms, then 200ms.
+	if d := time.Since(start); d < 3*minConnectBackoff {
+		t.Fatalf("NewPool() returned after %s, want retries with backoff", d)
+	}
+}
+
+func TestNewPoolStopsOnContextCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewPool(ctx, "postgres://u:p@127.0.0.1:1/db?connect_timeout=1", PoolOptions{
+		ConnectRetries: 100,
+		ConnectBackoff: time.Second,
+	}, discard)
+	if err == nil {
+		t.Fatal("NewPool() expected error after context cancel")
+	}
+}
+
+func TestIsConnError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"no rows", pgx.ErrNoRows, false},
+		{"wrapped no rows", fmt.Errorf("get: %w", pgx.ErrNoRows), false},
+		{"server error", &pgconn.PgError{Code: "23505"}, false},
+		{"connection refused", errors.New("dial tcp 127.0.0.1:5432: connect: connection refused"), true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := IsConnError(tc.err); got != tc.want {
+				t.Fatalf("IsConnError(%v) = %v, want %v", tc.err, got, tc.want)
+			}
+		})
+	}
+}
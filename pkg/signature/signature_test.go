@@ -0,0 +1,71 @@
+package signature
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseKeyring(t *testing.T) {
+	kr, err := ParseKeyring(" k2:new , k1:old")
+	if err != nil {
+		t.Fatalf("ParseKeyring() error: %v", err)
+	}
+	if kr.ActiveKeyID() != "k2" {
+		t.Fatalf("ActiveKeyID() = %q, want k2", kr.ActiveKeyID())
+	}
+	for _, bad := range []string{"", "k1", "k1:", ":s", "k1:a,k1:b"} {
+		if _, err := ParseKeyring(bad); err == nil {
+			t.Fatalf("ParseKeyring(%q) expected error", bad)
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	payload := RequestPayload("POST", "/payments/account/topup", []byte(`{"amount":10}`))
+	kr, _ := ParseKeyring("k2:new,k1:old")
+	header := kr.Sign(payload, now)
+	if !strings.HasPrefix(header, "t=1700000000,kid=k2,v1=") {
+		t.Fatalf("Sign() = %q", header)
+	}
+	if err := kr.Verify(header, payload, now.Add(time.Minute), 5*time.Minute); err != nil {
+		t.Fatalf("Verify() error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		header  string
+		payload []byte
+		now     time.Time
+		want    error
+	}{
+		{"missing", "", payload, now, ErrMissing},
+		{"malformed", "garbage", payload, now, ErrMalformed},
+		{"bad hex", "t=1700000000,kid=k2,v1=zz", payload, now, ErrMalformed},
+		{"expired", header, payload, now.Add(time.Hour), ErrExpired},
+		{"other path", header, RequestPayload("POST", "/orders", []byte(`{"amount":10}`)), now, ErrMismatch},
+		{"unknown key", strings.Replace(header, "kid=k2", "kid=k9", 1), payload, now, ErrUnknownKey},
+	}
+	for _, tt := range tests {
+		if err := kr.Verify(tt.header, tt.payload, tt.now, 5*time.Minute); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: Verify() error = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestRotation(t *testing.T) {
+	now := time.Now()
+	old, _ := ParseKeyring("k1:old")
+	rotated, _ := ParseKeyring("k2:new,k1:old")
+	dropped, _ := ParseKeyring("k2:new")
+
+	header := old.Sign([]byte("x"), now)
+	if err := rotated.Verify(header, []byte("x"), now, 0); err != nil {
+		t.Fatalf("rotated keyring rejected old signature: %v", err)
+	}
+	if err := dropped.Verify(header, []byte("x"), now, 0); !errors.Is(err, ErrUnknownKey) {
+		t.Fatalf("Verify() after dropping k1 error = %v, want ErrUnknownKey", err)
+	}
+}
@@ -0,0 +1,32 @@
+package startup
+
+import (
+	"context"
+	"errors"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// Kafka is ready once one of brokers answers a metadata request through
+// dialer.
+func Kafka(name string, dialer *kafka.Dialer, brokers []string) Dependency {
+	return Dependency{Name: name, Check: func(ctx context.Context) error {
+		err := errors.New("no kafka brokers configured")
+		for _, broker := range brokers {
+			var conn *kafka.Conn
+			conn, err = dialer.DialContext(ctx, "tcp", broker)
+			if err != nil {
+				continue
+			}
+			if deadline, ok := ctx.Deadline(); ok {
+				_ = conn.SetDeadline(deadline)
+			}
+			_, err = conn.Brokers()
+			_ = conn.Close()
+			if err == nil {
+				return nil
+			}
+		}
+		return err
+	}}
+}
@@ -0,0 +1,134 @@
+// Package startup holds a service back until the dependencies it cannot run
+// without (Postgres, Kafka) answer, so that a service started together with
+// them in docker-compose waits instead of crash-looping.
+//
+// Wait checks every dependency concurrently: a failed check is retried with
+// doubling backoff, and each attempt is capped by Config.CheckTimeout so a
+// dependency that hangs (a blackholed port, a broker still electing a
+// controller) does not use up the whole wait.
+package startup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Defaults of the retry backoff.
+const (
+	defaultBackoff = 250 * time.Millisecond
+	maxBackoff     = 5 * time.Second
+)
+
+// Dependency is something the service needs before it starts.
+type Dependency struct {
+	Name string
+	// Check returns nil once the dependency is ready.
+	Check func(ctx context.Context) error
+}
+
+// Config tunes Wait.
+type Config struct {
+	// Timeout bounds the whole wait; 0 disables it and Wait returns at once.
+	Timeout time.Duration
+	// CheckTimeout caps a single check; 0 leaves it to Timeout.
+	CheckTimeout time.Duration
+	// Backoff is the pause after the first failed check; it doubles up to
+	// 5s. Zero means 250ms.
+	Backoff time.Duration
+}
+
+// Wait blocks until every dependency is ready. It returns an error naming
+// the dependencies still not ready after cfg.Timeout, or ctx's error if ctx
+// ends first.
+func Wait(ctx context.Context, cfg Config, logger *slog.Logger, deps ...Dependency) error {
+	if cfg.Timeout <= 0 || len(deps) == 0 {
+		return nil
+	}
+	start := time.Now()
+	names := make([]string, len(deps))
+	for i, dep := range deps {
+		names[i] = dep.Name
+	}
+	logger.Info("waiting for dependencies", "dependencies", names, "timeout", cfg.Timeout, "check_timeout", cfg.CheckTimeout)
+
+	waitCtx, cancel := context.WithTimeout(ctx, cfg.Timeout)
+	defer cancel()
+	errs := make([]error, len(deps))
+	var wg sync.WaitGroup
+	for i, dep := range deps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = waitFor(waitCtx, cfg, logger, dep)
+		}()
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := errors.Join(errs...); err != nil {
+		logger.Error("dependencies not ready", "err", err, "timeout", cfg.Timeout)
+		return err
+	}
+	logger.Info("dependencies ready", "duration", time.Since(start))
+	return nil
+}
+
+// waitFor retries dep until its check passes or ctx ends.
+func waitFor(ctx context.Context, cfg Config, logger *slog.Logger, dep Dependency) error {
+	start := time.Now()
+	backoff := cfg.Backoff
+	if backoff <= 0 {
+		backoff = defaultBackoff
+	}
+	for attempt := 1; ; attempt++ {
+		err := check(ctx, cfg.CheckTimeout, dep)
+		if err == nil {
+			logger.Info("dependency ready", "dependency", dep.Name, "attempts", attempt, "duration", time.Since(start))
+			return nil
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("startup: %s not ready after %d attempts: %w", dep.Name, attempt, err)
+		}
+		logger.Warn("dependency not ready", "dependency", dep.Name, "err", err, "attempt", attempt, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("startup: %s not ready after %d attempts: %w", dep.Name, attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
+	}
+}
+
+// check runs one check of dep, capped by timeout.
+func check(ctx context.Context, timeout time.Duration, dep Dependency) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return dep.Check(ctx)
+}
+
+// Postgres is ready once a connection to url answers a ping.
+func Postgres(name, url string) Dependency {
+	return Dependency{Name: name, Check: func(ctx context.Context) error {
+		conn, err := pgx.Connect(ctx, url)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), time.Second)
+			defer cancel()
+			_ = conn.Close(closeCtx)
+		}()
+		return conn.Ping(ctx)
+	}}
+}
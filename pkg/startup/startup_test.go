@@ -0,0 +1,85 @@
+package startup
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// flaky fails its first n checks.
+func flaky(name string, n int32, calls *atomic.Int32) Dependency {
+	return Dependency{Name: name, Check: func(context.Context) error {
+		if calls.Add(1) <= n {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}
+}
+
+func TestWaitRetriesUntilReady(t *testing.T) {
+	var db, broker atomic.Int32
+	cfg := Config{Timeout: time.Second, Backoff: time.Millisecond}
+	if err := Wait(context.Background(), cfg, discard, flaky("postgres", 3, &db), flaky("kafka", 1, &broker)); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if db.Load() != 4 || broker.Load() != 2 {
+		t.Fatalf("checks = %d, %d; want 4 and 2", db.Load(), broker.Load())
+	}
+}
+
+func TestWaitTimesOut(t *testing.T) {
+	var db, broker atomic.Int32
+	hang := Dependency{Name: "kafka", Check: func(ctx context.Context) error {
+		broker.Add(1)
+		<-ctx.Done()
+		return ctx.Err()
+	}}
+	cfg := Config{Timeout: 100 * time.Millisecond, CheckTimeout: 20 * time.Millisecond, Backoff: time.Millisecond}
+	err := Wait(context.Background(), cfg, discard, flaky("postgres", 0, &db), hang)
+	if err == nil || !strings.Contains(err.Error(), "kafka not ready") || strings.Contains(err.Error(), "postgres") {
+		t.Fatalf("Wait() = %v, want only kafka not ready", err)
+	}
+	if broker.Load() < 2 {
+		t.Fatalf("kafka checks = %d, want a hanging check cut off and retried", broker.Load())
+	}
+}
+
+func TestWaitDisabled(t *testing.T) {
+	var calls atomic.Int32
+	if err := Wait(context.Background(), Config{}, discard, flaky("postgres", 100, &calls)); err != nil || calls.Load() != 0 {
+		t.Fatalf("Wait() = %v after %d checks, want nil without checking", err, calls.Load())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := Wait(ctx, Config{Timeout: time.Second}, discard, flaky("postgres", 100, &calls)); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait() with a canceled context = %v, want context.Canceled", err)
+	}
+}
+
+func TestKafkaFailsWithoutBroker(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	dialer := &kafka.Dialer{Timeout: time.Second}
+	if err := Kafka("kafka", dialer, nil).Check(context.Background()); err == nil {
+		t.Fatal("Kafka check without brokers expected error")
+	}
+	if err := Kafka("kafka", dialer, []string{addr}).Check(context.Background()); err == nil {
+		t.Fatalf("Kafka check of %s with nothing listening expected error", addr)
+	}
+}
@@ -0,0 +1,276 @@
+// Package svcauth authenticates the services calling each other over gRPC.
+//
+// Every call carries the caller's identity token in the x-service-token
+// metadata, next to the end user's bearer token. Two kinds of token are
+// supported:
+//
+//   - static: each service has its own shared secret, listed for all services
+//     as "api-gateway=secret1,orders-service=secret2"; the secret a caller
+//     presents names it.
+//   - jwt: short-lived HS256 tokens signed with one shared key whose subject
+//     is the caller's SPIFFE id, spiffe://<trust domain>/<service>.
+//
+// The server interceptor checks the token and the per-method list of
+// services allowed to call the method. The in-process REST proxies do not
+// call with the token of their own service: they forward the token their
+// HTTP caller sent in the X-Service-Token header, so a REST call is allowed
+// exactly what the caller could do over gRPC.
+package svcauth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+)
+
+// MetadataKey carries the caller's service token.
+const MetadataKey = "x-service-token"
+
+// HTTPHeader carries the caller's service token to the REST proxies.
+const HTTPHeader = "X-Service-Token"
+
+// Modes of Config.
+const (
+	ModeOff    = ""
+	ModeStatic = "static"
+	ModeJWT    = "jwt"
+)
+
+// DefaultTrustDomain is the SPIFFE trust domain of jwt tokens when none is
+// configured.
+const DefaultTrustDomain = "payments.internal"
+
+// tokenTTL is the lifetime of the jwt tokens a client issues; they are
+// reissued when less than a third of it is left.
+const tokenTTL = 5 * time.Minute
+
+var ErrInvalidToken = errors.New("invalid service token")
+
+// Config selects how services authenticate. The zero value turns service
+// authentication off.
+type Config struct {
+	Mode string
+	// Tokens maps service names to their secrets in static mode.
+	Tokens map[string]string
+	// JWTSecret signs and verifies jwt tokens.
+	JWTSecret   []byte
+	TrustDomain string
+}
+
+// ParseConfig builds a Config from its environment form: the mode, the
+// static tokens as "name=secret,...", the jwt secret and the trust domain.
+func ParseConfig(mode, tokens, jwtSecret, trustDomain string) (Config, error) {
+	cfg := Config{Mode: strings.ToLower(strings.TrimSpace(mode)), JWTSecret: []byte(jwtSecret), TrustDomain: trustDomain}
+	if cfg.TrustDomain == "" {
+		cfg.TrustDomain = DefaultTrustDomain
+	}
+	switch cfg.Mode {
+	case ModeOff:
+		return Config{}, nil
+	case ModeStatic:
+		cfg.Tokens = make(map[string]string)
+		for _, pair := range strings.Split(tokens, ",") {
+			pair = strings.TrimSpace(pair)
+			if pair == "" {
+				continue
+			}
+			name, secret, ok := strings.Cut(pair, "=")
+			name, secret = strings.TrimSpace(name), strings.TrimSpace(secret)
+			if !ok || name == "" || secret == "" {
+				return Config{}, fmt.Errorf("service token %q: want name=secret", pair)
+			}
+			if _, dup := cfg.Tokens[name]; dup {
+				return Config{}, fmt.Errorf("service token of %s given twice", name)
+			}
+			cfg.Tokens[name] = secret
+		}
+		if len(cfg.Tokens) == 0 {
+			return Config{}, errors.New("static service auth needs tokens")
+		}
+	case ModeJWT:
+		if len(cfg.JWTSecret) == 0 {
+			return Config{}, errors.New("jwt service auth needs a secret")
+		}
+	default:
+		return Config{}, fmt.Errorf("unknown service auth mode %q", mode)
+	}
+	return cfg, nil
+}
+
+func (c Config) Enabled() bool {
+	return c.Mode != ModeOff
+}
+
+// verify returns the name of the service token belongs to.
+func (c Config) verify(token string, now time.Time) (string, error) {
+	switch c.Mode {
+	case ModeStatic:
+		for name, secret := range c.Tokens {
+			if subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1 {
+				return name, nil
+			}
+		}
+		return "", ErrInvalidToken
+	case ModeJWT:
+		claims, err := jwt.Verify(token, c.JWTSecret, now)
+		if err != nil {
+			return "", ErrInvalidToken
+		}
+		name, ok := strings.CutPrefix(claims.Subject, spiffePrefix(c.TrustDomain))
+		if !ok || name == "" || strings.Contains(name, "/") {
+			return "", ErrInvalidToken
+		}
+		return name, nil
+	}
+	return "", ErrInvalidToken
+}
+
+func spiffePrefix(trustDomain string) string {
+	return "spiffe://" + trustDomain + "/"
+}
+
+type callerKey struct{}
+
+// FromContext returns the service that made an authenticated call.
+func FromContext(ctx context.Context) (string, bool) {
+	name, ok := ctx.Value(callerKey{}).(string)
+	return name, ok
+}
+
+// UnaryServerInterceptor admits calls to methods under prefix, such as
+// "/payments.v1.", only from the services allow lists for the method; self
+// is the name of the serving service, which gets no exemption. Methods
+// missing from allow are denied, and methods outside prefix (health,
+// reflection) are not checked.
+func UnaryServerInterceptor(cfg Config, self, prefix string, allow map[string][]string) grpc.UnaryServerInterceptor {
+	logger := slog.Default().With("service", self, "component", "svcauth")
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		token := incomingToken(ctx)
+		if token == "" {
+			logger.WarnContext(ctx, "service token missing", "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, "missing service token")
+		}
+		caller, err := cfg.verify(token, time.Now())
+		if err != nil {
+			logger.WarnContext(ctx, "service token rejected", "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if !allowed(allow[info.FullMethod], caller) {
+			logger.WarnContext(ctx, "service not allowed", "method", info.FullMethod, "caller", caller)
+			return nil, status.Errorf(codes.PermissionDenied, "service %s may not call this method", caller)
+		}
+		return handler(context.WithValue(ctx, callerKey{}, caller), req)
+	}
+}
+
+func allowed(callers []string, caller string) bool {
+	for _, c := range callers {
+		if c == caller {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderMatcher forwards HTTPHeader of a REST call as MetadataKey; other
+// headers are left to next. It fits runtime.WithIncomingHeaderMatcher of
+// grpc-gateway.
+func HeaderMatcher(next func(string) (string, bool)) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		if strings.EqualFold(key, HTTPHeader) {
+			return MetadataKey, true
+		}
+		return next(key)
+	}
+}
+
+func incomingToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	v := md.Get(MetadataKey)
+	if len(v) == 0 {
+		return ""
+	}
+	return v[0]
+}
+
+// UnaryClientInterceptor attaches the token of service self to every call.
+// It fails when cfg has no static token for self.
+func UnaryClientInterceptor(cfg Config, self string) (grpc.UnaryClientInterceptor, error) {
+	src, err := newTokenSource(cfg, self)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		token, err := src.token(time.Now())
+		if err != nil {
+			return status.Error(codes.Internal, "failed to issue service token")
+		}
+		return invoker(metadata.AppendToOutgoingContext(ctx, MetadataKey, token), method, req, reply, cc, opts...)
+	}, nil
+}
+
+// tokenSource hands out the caller's token, reissuing jwt tokens before
+// they expire.
+type tokenSource struct {
+	cfg  Config
+	self string
+
+	mu      sync.Mutex
+	current string
+	renewAt time.Time
+}
+
+func newTokenSource(cfg Config, self string) (*tokenSource, error) {
+	s := &tokenSource{cfg: cfg, self: self}
+	switch cfg.Mode {
+	case ModeStatic:
+		secret, ok := cfg.Tokens[self]
+		if !ok {
+			return nil, fmt.Errorf("no service token for %s", self)
+		}
+		s.current = secret
+	case ModeJWT:
+	default:
+		return nil, errors.New("service auth is off")
+	}
+	return s, nil
+}
+
+func (s *tokenSource) token(now time.Time) (string, error) {
+	if s.cfg.Mode == ModeStatic {
+		return s.current, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.current != "" && now.Before(s.renewAt) {
+		return s.current, nil
+	}
+	token, err := jwt.Sign(jwt.Claims{
+		Subject:   spiffePrefix(s.cfg.TrustDomain) + s.self,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(tokenTTL).Unix(),
+	}, s.cfg.JWTSecret)
+	if err != nil {
+		return "", err
+	}
+	s.current, s.renewAt = token, now.Add(tokenTTL*2/3)
+	return token, nil
+}
@@ -0,0 +1,133 @@
+package svcauth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const method = "/payments.v1.PaymentsService/GetBalance"
+
+// call sends a request from caller through both interceptors and returns
+// the service the server saw.
+func call(t *testing.T, client, server Config, caller, fullMethod string) (string, error) {
+	t.Helper()
+	out, err := UnaryClientInterceptor(client, caller)
+	if err != nil {
+		t.Fatalf("UnaryClientInterceptor(%s) error: %v", caller, err)
+	}
+	in := UnaryServerInterceptor(server, "payments-service", "/payments.v1.", map[string][]string{method: {"api-gateway"}})
+	var seen string
+	err = out(context.Background(), fullMethod, nil, nil, nil, func(ctx context.Context, m string, req, _ any, _ *grpc.ClientConn, _ ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		_, err := in(metadata.NewIncomingContext(ctx, md), req, &grpc.UnaryServerInfo{FullMethod: m}, func(ctx context.Context, _ any) (any, error) {
+			seen, _ = FromContext(ctx)
+			return nil, nil
+		})
+		return err
+	})
+	return seen, err
+}
+
+func wantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Fatalf("error code = %s (%v), want %s", got, err, code)
+	}
+}
+
+func TestStatic(t *testing.T) {
+	cfg, err := ParseConfig("static", "api-gateway=g-secret, orders-service=o-secret,payments-service=p-secret", "", "")
+	if err != nil {
+		t.Fatalf("ParseConfig() error: %v", err)
+	}
+	if got, err := call(t, cfg, cfg, "api-gateway", method); err != nil || got != "api-gateway" {
+		t.Fatalf("gateway call = (%q, %v), want allowed as api-gateway", got, err)
+	}
+	_, err = call(t, cfg, cfg, "orders-service", method)
+	wantCode(t, err, codes.PermissionDenied)
+	// The serving service is held to the allow list too.
+	_, err = call(t, cfg, cfg, "payments-service", method)
+	wantCode(t, err, codes.PermissionDenied)
+	// Methods without an allow list are denied; other services are not checked.
+	_, err = call(t, cfg, cfg, "api-gateway", "/payments.v1.PaymentsService/TopUp")
+	wantCode(t, err, codes.PermissionDenied)
+	if _, err := call(t, cfg, cfg, "orders-service", "/grpc.health.v1.Health/Check"); err != nil {
+		t.Fatalf("health check error: %v", err)
+	}
+
+	forged := Config{Mode: ModeStatic, Tokens: map[string]string{"api-gateway": "guess"}}
+	_, err = call(t, forged, cfg, "api-gateway", method)
+	wantCode(t, err, codes.Unauthenticated)
+	if _, err := UnaryClientInterceptor(cfg, "notifications-service"); err == nil {
+		t.Fatal("UnaryClientInterceptor() without a token of its own succeeded")
+	}
+
+	in := UnaryServerInterceptor(cfg, "payments-service", "/payments.v1.", nil)
+	_, err = in(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: method}, func(context.Context, any) (any, error) { return nil, nil })
+	wantCode(t, err, codes.Unauthenticated)
+}
+
+func TestJWT(t *testing.T) {
+	cfg, err := ParseConfig("jwt", "", "shared", "")
+	if err != nil {
+		t.Fatalf("ParseConfig() error: %v", err)
+	}
+	if got, err := call(t, cfg, cfg, "api-gateway", method); err != nil || got != "api-gateway" {
+		t.Fatalf("gateway call = (%q, %v), want allowed as api-gateway", got, err)
+	}
+	_, err = call(t, cfg, cfg, "orders-service", method)
+	wantCode(t, err, codes.PermissionDenied)
+
+	otherDomain, _ := ParseConfig("jwt", "", "shared", "example.org")
+	_, err = call(t, otherDomain, cfg, "api-gateway", method)
+	wantCode(t, err, codes.Unauthenticated)
+	otherKey, _ := ParseConfig("jwt", "", "guess", "")
+	_, err = call(t, otherKey, cfg, "api-gateway", method)
+	wantCode(t, err, codes.Unauthenticated)
+
+	now := time.Now()
+	src, _ := newTokenSource(cfg, "api-gateway")
+	first, _ := src.token(now)
+	if again, _ := src.token(now.Add(time.Minute)); again != first {
+		t.Fatal("token reissued while still fresh")
+	}
+	if renewed, _ := src.token(now.Add(tokenTTL - time.Minute)); renewed == first {
+		t.Fatal("token not reissued near its expiry")
+	}
+	if _, err := cfg.verify(first, now.Add(tokenTTL)); err == nil {
+		t.Fatal("expired token accepted")
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	if cfg, err := ParseConfig("", "ignored", "", ""); err != nil || cfg.Enabled() {
+		t.Fatalf("ParseConfig(off) = (%+v, %v), want disabled", cfg, err)
+	}
+	for _, tc := range [][4]string{
+		{"static", "", "", ""},
+		{"static", "api-gateway", "", ""},
+		{"static", "a=1,a=2", "", ""},
+		{"jwt", "", "", ""},
+		{"mtls", "", "", ""},
+	} {
+		if _, err := ParseConfig(tc[0], tc[1], tc[2], tc[3]); err == nil {
+			t.Errorf("ParseConfig(%q) succeeded, want an error", tc)
+		}
+	}
+}
+
+func TestHeaderMatcher(t *testing.T) {
+	match := HeaderMatcher(func(key string) (string, bool) { return "", false })
+	if got, ok := match("x-service-token"); !ok || got != MetadataKey {
+		t.Fatalf("match(x-service-token) = (%q, %v), want %s", got, ok, MetadataKey)
+	}
+	if _, ok := match("X-Other"); ok {
+		t.Fatal("match(X-Other) forwarded a header next does not")
+	}
+}
@@ -0,0 +1,129 @@
+// Package txoffset stores the offsets of consumed Kafka messages in Postgres,
+// on the transaction that applies each message, for the consumers that run
+// in transactional offsets mode.
+//
+// Every service keeps one table of the same shape:
+//
+//	topic, partition  -- primary key
+//	last_offset       -- highest offset applied on the partition
+//	updated_at
+//
+// Wrap skips a message at or below the stored offset of its partition and
+// otherwise stores its offset with its effects, so a message redelivered
+// after a crash between the DB commit and the Kafka commit is not applied
+// twice. Seek moves the consumer group past the stored offsets at startup.
+package txoffset
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/segmentio/kafka-go"
+)
+
+const (
+	getSQL = `SELECT last_offset FROM kafka_offsets WHERE topic = $1 AND partition = $2`
+
+	saveSQL = `INSERT INTO kafka_offsets (topic, partition, last_offset)
+VALUES ($1, $2, $3)
+ON CONFLICT (topic, partition) DO UPDATE
+SET last_offset = GREATEST(kafka_offsets.last_offset, EXCLUDED.last_offset),
+    updated_at = now()`
+
+	listSQL = `SELECT partition, last_offset FROM kafka_offsets WHERE topic = $1 ORDER BY partition`
+)
+
+// Wrap wraps the handler apply of message m: with enabled set, a message at
+// or below the offset stored for its partition is skipped, and otherwise its
+// offset is stored on tx after apply. With enabled unset apply runs
+// unchanged.
+func Wrap[Q any](ctx context.Context, enabled bool, m kafka.Message, apply func(pgx.Tx, Q) error, logger *slog.Logger) func(pgx.Tx, Q) error {
+	return func(tx pgx.Tx, q Q) error {
+		if !enabled {
+			return apply(tx, q)
+		}
+
+		var last int64
+		err := tx.QueryRow(ctx, getSQL, m.Topic, int32(m.Partition)).Scan(&last)
+		switch {
+		case err == nil && m.Offset <= last:
+			logger.Info("message offset already processed", "topic", m.Topic, "partition", m.Partition, "offset", m.Offset, "last_offset", last)
+			return nil
+		case err != nil && !errors.Is(err, pgx.ErrNoRows):
+			logger.Error("failed to load stored offset", "err", err, "topic", m.Topic, "partition", m.Partition)
+			return err
+		}
+
+		if err := apply(tx, q); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, saveSQL, m.Topic, int32(m.Partition), m.Offset); err != nil {
+			logger.Error("failed to store offset", "err", err, "topic", m.Topic, "partition", m.Partition, "offset", m.Offset)
+			return err
+		}
+		return nil
+	}
+}
+
+// Querier is the part of *pgxpool.Pool that Seek needs.
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// Seek moves the consumer group of r past every offset stored in dbs. Group
+// readers cannot seek, so this commits the stored offsets to Kafka; they are
+// never behind the Kafka ones because the DB commit always happens first.
+// With several databases (sharded accounts) each holds the offsets of the
+// messages it applied, and a partition resumes after the highest of them.
+func Seek(ctx context.Context, r *kafka.Reader, dbs []Querier, logger *slog.Logger) error {
+	topic := r.Config().Topic
+
+	last := map[int]int64{}
+	for i, db := range dbs {
+		if err := list(ctx, db, topic, last); err != nil {
+			if len(dbs) > 1 {
+				return fmt.Errorf("database %d: %w", i, err)
+			}
+			return err
+		}
+	}
+	if len(last) == 0 {
+		logger.Info("no stored offsets to seek", "topic", topic)
+		return nil
+	}
+
+	msgs := make([]kafka.Message, 0, len(last))
+	for partition, offset := range last {
+		msgs = append(msgs, kafka.Message{Topic: topic, Partition: partition, Offset: offset})
+	}
+	if err := r.CommitMessages(ctx, msgs...); err != nil {
+		return err
+	}
+	logger.Info("seeked past stored offsets", "topic", topic, "partitions", len(last), "databases", len(dbs))
+	return nil
+}
+
+// list merges the offsets of topic stored in db into last.
+func list(ctx context.Context, db Querier, topic string, last map[int]int64) error {
+	rows, err := db.Query(ctx, listSQL, topic)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			partition int32
+			offset    int64
+		)
+		if err := rows.Scan(&partition, &offset); err != nil {
+			return err
+		}
+		if off, ok := last[int(partition)]; !ok || offset > off {
+			last[int(partition)] = offset
+		}
+	}
+	return rows.Err()
+}
@@ -0,0 +1,88 @@
+package txoffset
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/segmentio/kafka-go"
+)
+
+// memTx is a pgx.Tx over an in-memory kafka_offsets table. Only the
+// statements of this package are understood; everything else panics on the
+// nil Tx.
+type memTx struct {
+	pgx.Tx
+	offsets map[int32]int64
+}
+
+type row struct {
+	val int64
+	err error
+}
+
+func (r row) Scan(dest ...any) error {
+	if r.err != nil {
+		return r.err
+	}
+	*dest[0].(*int64) = r.val
+	return nil
+}
+
+func (t *memTx) QueryRow(_ context.Context, sql string, args ...any) pgx.Row {
+	if sql != getSQL {
+		panic("unexpected query: " + sql)
+	}
+	off, ok := t.offsets[args[1].(int32)]
+	if !ok {
+		return row{err: pgx.ErrNoRows}
+	}
+	return row{val: off}
+}
+
+func (t *memTx) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if sql != saveSQL {
+		panic("unexpected statement: " + sql)
+	}
+	t.offsets[args[1].(int32)] = max(t.offsets[args[1].(int32)], args[2].(int64))
+	return pgconn.NewCommandTag("INSERT 0 1"), nil
+}
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestWrap(t *testing.T) {
+	tx := &memTx{offsets: map[int32]int64{}}
+	applied := 0
+	apply := func(pgx.Tx, string) error {
+		applied++
+		return nil
+	}
+	handle := func(enabled bool, offset int64) {
+		t.Helper()
+		m := kafka.Message{Topic: "payments.results", Partition: 1, Offset: offset}
+		if err := Wrap(context.Background(), enabled, m, apply, discard)(tx, "q"); err != nil {
+			t.Fatalf("Wrap() offset %d error: %v", offset, err)
+		}
+	}
+
+	handle(true, 5)
+	if applied != 1 || tx.offsets[1] != 5 {
+		t.Fatalf("after first message applied = %d, stored = %d, want 1 and 5", applied, tx.offsets[1])
+	}
+	handle(true, 5)
+	handle(true, 3)
+	if applied != 1 {
+		t.Fatalf("redelivered messages applied %d times, want skipped", applied-1)
+	}
+	handle(true, 6)
+	if applied != 2 || tx.offsets[1] != 6 {
+		t.Fatalf("after next message applied = %d, stored = %d, want 2 and 6", applied, tx.offsets[1])
+	}
+	handle(false, 1)
+	if applied != 3 || tx.offsets[1] != 6 {
+		t.Fatalf("disabled: applied = %d, stored = %d, want 3 and 6 untouched", applied, tx.offsets[1])
+	}
+}
@@ -0,0 +1,109 @@
+// Package userauth authorizes the end user's calls to the services over
+// gRPC.
+//
+// The gateway forwards the user's bearer token (pkg/jwt) in the
+// authorization metadata. The server interceptor verifies it and checks the
+// per-method list of roles allowed to call the method; plain users may only
+// act on their own user_id. Service identities are checked separately by
+// pkg/svcauth.
+package userauth
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+)
+
+// MetadataKey carries the user's bearer token.
+const MetadataKey = "authorization"
+
+type claimsKey struct{}
+
+// NewContext returns ctx carrying claims, as the interceptor passes them to
+// handlers.
+func NewContext(ctx context.Context, claims jwt.Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext returns the claims of an authorized call.
+func FromContext(ctx context.Context) (jwt.Claims, bool) {
+	c, ok := ctx.Value(claimsKey{}).(jwt.Claims)
+	return c, ok
+}
+
+type userScoped interface {
+	GetUserId() string
+}
+
+// UnaryServerInterceptor enforces roles on the RPCs whose full method starts
+// with prefix, using the bearer token signed with secret. RPCs under prefix
+// missing from roles are denied; anything else (health, reflection) is
+// passed through.
+func UnaryServerInterceptor(secret []byte, prefix string, roles map[string][]jwt.Role, logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, prefix) {
+			return handler(ctx, req)
+		}
+		allowed, ok := roles[info.FullMethod]
+		if !ok {
+			logger.Warn("rpc has no role policy", "method", info.FullMethod)
+			return nil, status.Error(codes.PermissionDenied, "method not allowed")
+		}
+
+		token := bearerToken(ctx)
+		if token == "" {
+			return nil, status.Error(codes.Unauthenticated, "missing bearer token")
+		}
+		claims, err := jwt.Verify(token, secret, time.Now())
+		if err != nil {
+			logger.Warn("token rejected", "method", info.FullMethod)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		if !claims.HasAny(allowed) {
+			logger.Warn("role denied", "method", info.FullMethod, "sub", claims.Subject, "roles", claims.Roles)
+			return nil, status.Error(codes.PermissionDenied, "role not allowed")
+		}
+		if r, ok := req.(userScoped); ok && !claims.Privileged() && r.GetUserId() != claims.Subject {
+			logger.Warn("user id does not match token subject", "method", info.FullMethod, "sub", claims.Subject)
+			return nil, status.Error(codes.PermissionDenied, "user_id does not match token")
+		}
+		return handler(NewContext(ctx, claims), req)
+	}
+}
+
+// ForwardToken copies the caller's bearer token onto outgoing calls so that
+// downstream services authorize them as the original user.
+func ForwardToken() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if v := md.Get(MetadataKey); len(v) > 0 {
+				ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, v[0])
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+func bearerToken(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	v := md.Get(MetadataKey)
+	if len(v) == 0 {
+		return ""
+	}
+	token, ok := strings.CutPrefix(v[0], "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
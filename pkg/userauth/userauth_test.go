@@ -0,0 +1,95 @@
+package userauth
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+)
+
+var secret = []byte("test-secret")
+
+const (
+	getMethod   = "/payments.v1.PaymentsService/GetBalance"
+	adminMethod = "/payments.v1.PaymentsAdminService/ReplayOutbox"
+)
+
+var roles = map[string][]jwt.Role{
+	getMethod:   {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	adminMethod: {jwt.RoleAdmin},
+}
+
+type userReq struct{ userID string }
+
+func (r userReq) GetUserId() string { return r.userID }
+
+func sign(t *testing.T, sub string, role jwt.Role) string {
+	t.Helper()
+	token, err := jwt.Sign(jwt.Claims{Subject: sub, Roles: []jwt.Role{role}, ExpiresAt: time.Now().Add(time.Minute).Unix()}, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	return token
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user := sign(t, "u-1", jwt.RoleUser)
+	support := sign(t, "s-1", jwt.RoleSupport)
+	admin := sign(t, "a-1", jwt.RoleAdmin)
+	intercept := UnaryServerInterceptor(secret, "/payments.v1.", roles, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		req    any
+		want   codes.Code
+	}{
+		{"missing token", getMethod, "", userReq{"u-1"}, codes.Unauthenticated},
+		{"garbage token", getMethod, "x.y.z", userReq{"u-1"}, codes.Unauthenticated},
+		{"own user", getMethod, user, userReq{"u-1"}, codes.OK},
+		{"other user", getMethod, user, userReq{"u-2"}, codes.PermissionDenied},
+		{"support reads any user", getMethod, support, userReq{"u-2"}, codes.OK},
+		{"role denied", adminMethod, support, nil, codes.PermissionDenied},
+		{"admin allowed", adminMethod, admin, nil, codes.OK},
+		{"unlisted rpc", "/payments.v1.PaymentsService/Drop", admin, nil, codes.PermissionDenied},
+		{"outside prefix", "/grpc.health.v1.Health/Check", "", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.token != "" {
+			ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(MetadataKey, "Bearer "+tt.token))
+		}
+		_, err := intercept(ctx, tt.req, &grpc.UnaryServerInfo{FullMethod: tt.method}, func(ctx context.Context, _ any) (any, error) {
+			if _, ok := FromContext(ctx); !ok && tt.method != "/grpc.health.v1.Health/Check" {
+				t.Fatalf("%s: handler called without claims in context", tt.name)
+			}
+			return nil, nil
+		})
+		if got := status.Code(err); got != tt.want {
+			t.Fatalf("%s: code = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestForwardToken(t *testing.T) {
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, "Bearer abc"))
+	var got []string
+	err := ForwardToken()(ctx, getMethod, nil, nil, nil,
+		func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			got = md.Get(MetadataKey)
+			return nil
+		})
+	if err != nil || len(got) != 1 || got[0] != "Bearer abc" {
+		t.Fatalf("forwarded authorization = %v (err %v), want [Bearer abc]", got, err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package webhooksdk parses and verifies the webhooks notifications-service
+// POSTs to users, for integrators writing their receivers in Go.
+//
+// A receiver needs the signing keys it was given, in the
+// "id:secret[,id:secret...]" form of NOTIFICATIONS_WEBHOOK_SIGNING_KEYS:
+//
+//	v, err := webhooksdk.NewVerifier(os.Getenv("WEBHOOK_KEYS"))
+//	...
+//	http.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
+//		ev, err := v.ParseRequest(r)
+//		if err != nil {
+//			http.Error(w, err.Error(), http.StatusBadRequest)
+//			return
+//		}
+//		if ev.Type == webhooksdk.TypeOrderStatusChanged {
+//			order, err := ev.Order()
+//			...
+//		}
+//	})
+//
+// Deliveries are retried until a 2xx answer, so the same event may arrive
+// more than once; Event.ID stays the same and lets receivers drop repeats.
+package webhooksdk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+// Headers of a webhook request.
+const (
+	// SignatureHeader carries "t=<unix>,kid=<id>,v1=<hex hmac>", see
+	// package signature.
+	SignatureHeader = signature.Header
+	// IDHeader carries the event id, the same as Event.ID.
+	IDHeader = "X-Webhook-Id"
+)
+
+// Event types, the Type of an Event.
+const (
+	TypePaymentSucceeded         = "payment.succeeded"
+	TypePaymentFailed            = "payment.failed"
+	TypeOrderStatusChanged       = "order.status_changed"
+	TypePaymentChallengeRequired = "payment.challenge_required"
+)
+
+// DefaultTolerance is how far the signature timestamp may be from the
+// receiver's clock; older deliveries are rejected as possible replays.
+const DefaultTolerance = 5 * time.Minute
+
+// MaxBodySize bounds the body ParseRequest reads.
+const MaxBodySize = 1 << 20
+
+var (
+	ErrUnknownType = errors.New("webhook: unexpected event type")
+	ErrTooLarge    = errors.New("webhook: body too large")
+)
+
+// Event is the JSON body of a webhook. Data depends on Type; decode it with
+// Payment, Order or Challenge.
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// PaymentData is the Data of payment.succeeded and payment.failed events.
+// Amounts are minor units of Currency.
+type PaymentData struct {
+	OrderID   string         `json:"order_id"`
+	PaymentID string         `json:"payment_id,omitempty"`
+	Amount    money.Amount   `json:"amount"`
+	Fee       money.Amount   `json:"fee"`
+	Currency  money.Currency `json:"currency"`
+	// Reason is set for failed payments: no account, not enough funds,
+	// declined or limit exceeded.
+	Reason string `json:"reason,omitempty"`
+}
+
+// OrderData is the Data of order.status_changed events.
+type OrderData struct {
+	OrderID        string         `json:"order_id"`
+	PreviousStatus string         `json:"previous_status"`
+	Status         string         `json:"status"`
+	Amount         money.Amount   `json:"amount"`
+	PaidAmount     money.Amount   `json:"paid_amount"`
+	Currency       money.Currency `json:"currency"`
+	Reason         string         `json:"reason,omitempty"`
+}
+
+// ChallengeData is the Data of payment.challenge_required events: the
+// payment waits for the payer's confirmation until ExpiresAt, after which
+// it fails and the order is cancelled.
+type ChallengeData struct {
+	OrderID       string         `json:"order_id"`
+	PaymentID     string         `json:"payment_id,omitempty"`
+	Amount        money.Amount   `json:"amount"`
+	Currency      money.Currency `json:"currency"`
+	PaymentMethod string         `json:"payment_method"`
+	ExpiresAt     time.Time      `json:"expires_at"`
+}
+
+// Payment decodes the data of a payment event.
+func (e *Event) Payment() (PaymentData, error) {
+	var d PaymentData
+	if e.Type != TypePaymentSucceeded && e.Type != TypePaymentFailed {
+		return d, fmt.Errorf("%w %q, want a payment event", ErrUnknownType, e.Type)
+	}
+	if err := json.Unmarshal(e.Data, &d); err != nil {
+		return d, fmt.Errorf("webhook: decode payment data: %w", err)
+	}
+	return d, nil
+}
+
+// Order decodes the data of an order.status_changed event.
+func (e *Event) Order() (OrderData, error) {
+	var d OrderData
+	if e.Type != TypeOrderStatusChanged {
+		return d, fmt.Errorf("%w %q, want %s", ErrUnknownType, e.Type, TypeOrderStatusChanged)
+	}
+	if err := json.Unmarshal(e.Data, &d); err != nil {
+		return d, fmt.Errorf("webhook: decode order data: %w", err)
+	}
+	return d, nil
+}
+
+// Challenge decodes the data of a payment.challenge_required event.
+func (e *Event) Challenge() (ChallengeData, error) {
+	var d ChallengeData
+	if e.Type != TypePaymentChallengeRequired {
+		return d, fmt.Errorf("%w %q, want %s", ErrUnknownType, e.Type, TypePaymentChallengeRequired)
+	}
+	if err := json.Unmarshal(e.Data, &d); err != nil {
+		return d, fmt.Errorf("webhook: decode challenge data: %w", err)
+	}
+	return d, nil
+}
+
+// Verifier checks webhook signatures. Errors of a bad signature wrap those
+// of package signature, e.g. signature.ErrMismatch.
+type Verifier struct {
+	keys *signature.Keyring
+	// Tolerance bounds the age of a signature; 0 disables the check.
+	Tolerance time.Duration
+
+	now func() time.Time
+}
+
+// NewVerifier accepts signatures made with any of keys,
+// "id:secret[,id:secret...]"; keep the previous key listed while the
+// sender rotates to a new one.
+func NewVerifier(keys string) (*Verifier, error) {
+	kr, err := signature.ParseKeyring(keys)
+	if err != nil {
+		return nil, fmt.Errorf("webhook: %w", err)
+	}
+	return &Verifier{keys: kr, Tolerance: DefaultTolerance, now: time.Now}, nil
+}
+
+// Verify checks the signature header of body.
+func (v *Verifier) Verify(header string, body []byte) error {
+	if err := v.keys.Verify(header, body, v.now(), v.Tolerance); err != nil {
+		return fmt.Errorf("webhook: %w", err)
+	}
+	return nil
+}
+
+// Parse verifies body against the signature header and decodes it.
+func (v *Verifier) Parse(header string, body []byte) (*Event, error) {
+	if err := v.Verify(header, body); err != nil {
+		return nil, err
+	}
+	var e Event
+	if err := json.Unmarshal(body, &e); err != nil {
+		return nil, fmt.Errorf("webhook: decode event: %w", err)
+	}
+	return &e, nil
+}
+
+// ParseRequest reads, verifies and decodes a webhook request. The body is
+// consumed.
+func (v *Verifier) ParseRequest(r *http.Request) (*Event, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		return nil, fmt.Errorf("webhook: read body: %w", err)
+	}
+	if len(body) > MaxBodySize {
+		return nil, ErrTooLarge
+	}
+	e, err := v.Parse(r.Header.Get(SignatureHeader), body)
+	if err != nil {
+		return nil, err
+	}
+	if id := r.Header.Get(IDHeader); id != "" && id != e.ID {
+		return nil, fmt.Errorf("webhook: %s %q does not match event id %q", IDHeader, id, e.ID)
+	}
+	return e, nil
+}
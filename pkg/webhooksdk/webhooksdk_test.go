@@ -0,0 +1,87 @@
+package webhooksdk
+
+import (
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+// paymentBody is a webhook as notifications-service sends it.
+const paymentBody = `{"id":"e-1","type":"payment.succeeded","created_at":"2026-01-02T03:04:05Z","data":{"order_id":"o-1","amount":"150050","fee":"100","currency":"RUB"}}`
+
+func TestParseRequest(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 5, 0, 0, time.UTC)
+	sender, _ := signature.ParseKeyring("k2:new")
+	v, err := NewVerifier("k2:new,k1:old")
+	if err != nil {
+		t.Fatalf("NewVerifier() error: %v", err)
+	}
+	v.now = func() time.Time { return now }
+
+	r := httptest.NewRequest("POST", "/webhooks", strings.NewReader(paymentBody))
+	r.Header.Set(SignatureHeader, sender.Sign([]byte(paymentBody), now))
+	r.Header.Set(IDHeader, "e-1")
+	ev, err := v.ParseRequest(r)
+	if err != nil {
+		t.Fatalf("ParseRequest() error: %v", err)
+	}
+	if ev.ID != "e-1" || ev.Type != TypePaymentSucceeded || !ev.CreatedAt.Equal(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Fatalf("event = %+v", ev)
+	}
+	p, err := ev.Payment()
+	if err != nil || p.OrderID != "o-1" || p.Amount != 150050 || p.Fee != 100 || p.Currency != money.RUB {
+		t.Fatalf("Payment() = (%+v, %v)", p, err)
+	}
+	if _, err := ev.Order(); !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("Order() of a payment event error = %v, want ErrUnknownType", err)
+	}
+
+	tests := []struct {
+		name   string
+		header string
+		body   string
+		id     string
+		want   error
+	}{
+		{"unsigned", "", paymentBody, "", signature.ErrMissing},
+		{"tampered", sender.Sign([]byte(paymentBody), now), strings.Replace(paymentBody, "150050", "950050", 1), "", signature.ErrMismatch},
+		{"stale", sender.Sign([]byte(paymentBody), now.Add(-time.Hour)), paymentBody, "", signature.ErrExpired},
+		{"other id", sender.Sign([]byte(paymentBody), now), paymentBody, "e-2", nil},
+	}
+	for _, tc := range tests {
+		r := httptest.NewRequest("POST", "/webhooks", strings.NewReader(tc.body))
+		r.Header.Set(SignatureHeader, tc.header)
+		r.Header.Set(IDHeader, tc.id)
+		_, err := v.ParseRequest(r)
+		if err == nil || (tc.want != nil && !errors.Is(err, tc.want)) {
+			t.Errorf("%s: ParseRequest() error = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestOrder(t *testing.T) {
+	ev := &Event{Type: TypeOrderStatusChanged, Data: []byte(`{"order_id":"o-1","previous_status":"NEW","status":"PARTIALLY_PAID","amount":"30000","paid_amount":"10000","currency":"RUB"}`)}
+	o, err := ev.Order()
+	if err != nil || o.Status != "PARTIALLY_PAID" || o.PaidAmount != 10000 {
+		t.Fatalf("Order() = (%+v, %v)", o, err)
+	}
+	if _, err := NewVerifier(""); err == nil {
+		t.Fatal("NewVerifier() without keys succeeded")
+	}
+}
+
+func TestChallenge(t *testing.T) {
+	ev := &Event{Type: TypePaymentChallengeRequired, Data: []byte(`{"order_id":"o-1","amount":"30000","currency":"RUB","payment_method":"card","expires_at":"2026-01-02T03:04:05Z"}`)}
+	c, err := ev.Challenge()
+	if err != nil || c.Amount != 30000 || c.PaymentMethod != "card" || c.ExpiresAt.Hour() != 3 {
+		t.Fatalf("Challenge() = (%+v, %v)", c, err)
+	}
+	if _, err := (&Event{Type: TypePaymentFailed}).Challenge(); !errors.Is(err, ErrUnknownType) {
+		t.Fatalf("Challenge() of a payment event error = %v, want ErrUnknownType", err)
+	}
+}
@@ -7,3 +7,28 @@ docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:
   --create --if-not-exists \
   --topic payments.payment_result.v1 \
   --partitions 3 --replication-factor 1
+
+docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:9092 \
+  --create --if-not-exists \
+  --topic payments.account_created.v1 \
+  --partitions 3 --replication-factor 1
+
+docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:9092 \
+  --create --if-not-exists \
+  --topic payments.payment_challenge_required.v1 \
+  --partitions 3 --replication-factor 1
+
+docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:9092 \
+  --create --if-not-exists \
+  --topic payments.payment_dispute_changed.v1 \
+  --partitions 3 --replication-factor 1
+
+docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:9092 \
+  --create --if-not-exists \
+  --topic orders.order_transferred.v1 \
+  --partitions 3 --replication-factor 1
+
+docker exec -it broker /opt/kafka/bin/kafka-topics.sh --bootstrap-server broker:9092 \
+  --create --if-not-exists \
+  --topic orders.order_status_changed.v1 \
+  --partitions 3 --replication-factor 1
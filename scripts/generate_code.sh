@@ -9,3 +9,6 @@ oapi-codegen \
   -generate types,chi-server,spec \
   -o gen/openapi/gateway/gateway.gen.go \
   api-files/openapi/api-gateway.yaml
+oapi-codegen -config pkg/client/oapi-codegen.yaml api-files/openapi/api-gateway.yaml
+
+(cd services/api-gateway && go run github.com/99designs/gqlgen generate)
@@ -3,3 +3,6 @@ sqlc generate
 
 cd ../payments-service/
 sqlc generate
+
+cd ../notifications-service/
+sqlc generate
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+// Operations of the mix.
+const (
+	opCreateOrder = "create_order"
+	opTopUp       = "top_up"
+	opGetBalance  = "get_balance"
+)
+
+type mixEntry struct {
+	op     string
+	weight int
+}
+
+// parseMix parses "create_order=5,top_up=1,get_balance=4"; zero weights are
+// dropped.
+func parseMix(s string) ([]mixEntry, error) {
+	var mix []mixEntry
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		op, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("mix %q: want op=weight", part)
+		}
+		op = strings.TrimSpace(op)
+		switch op {
+		case opCreateOrder, opTopUp, opGetBalance:
+		default:
+			return nil, fmt.Errorf("mix %q: unknown op, want %s, %s or %s", part, opCreateOrder, opTopUp, opGetBalance)
+		}
+		if seen[op] {
+			return nil, fmt.Errorf("mix: %s given twice", op)
+		}
+		seen[op] = true
+		w, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("mix %q: want a non-negative integer weight", part)
+		}
+		if w > 0 {
+			mix = append(mix, mixEntry{op: op, weight: w})
+		}
+	}
+	if len(mix) == 0 {
+		return nil, fmt.Errorf("mix %q: nothing to run", s)
+	}
+	return mix, nil
+}
+
+func pickOp(mix []mixEntry, rng *rand.Rand) string {
+	total := 0
+	for _, m := range mix {
+		total += m.weight
+	}
+	n := rng.IntN(total)
+	for _, m := range mix {
+		if n < m.weight {
+			return m.op
+		}
+		n -= m.weight
+	}
+	return mix[len(mix)-1].op
+}
+
+type loadConfig struct {
+	rps      float64
+	duration time.Duration
+	// maxInFlight caps concurrent calls; calls due while at the cap are
+	// dropped and counted, so a slow system shows up as drops instead of
+	// the generator silently slowing down.
+	maxInFlight int
+	users       []string
+	amount      int64
+	mix         []mixEntry
+	seed        uint64
+}
+
+// createdOrder is an order created during the run, checked by verify.
+type createdOrder struct {
+	userID, orderID string
+}
+
+type runner struct {
+	orders   ordersv1.OrdersServiceClient
+	payments paymentsv1.PaymentsServiceClient
+	rec      *recorder
+	// timeout of a single call
+	timeout time.Duration
+
+	mu      sync.Mutex
+	created []createdOrder
+	dropped atomic.Int64
+}
+
+// setup creates an account for every user, tolerating existing ones, and
+// tops it up with balance so that the orders of the run can be paid.
+func (r *runner) setup(ctx context.Context, users []string, balance int64, concurrency int) error {
+	sem := make(chan struct{}, concurrency)
+	errs := make(chan error, len(users))
+	var wg sync.WaitGroup
+	for _, userID := range users {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			cctx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			_, err := r.payments.CreateAccount(cctx, &paymentsv1.CreateAccountRequest{UserId: userID})
+			if err != nil && status.Code(err) != codes.AlreadyExists {
+				errs <- fmt.Errorf("create account %s: %w", userID, err)
+				return
+			}
+			if balance <= 0 {
+				return
+			}
+			if _, err := r.payments.TopUp(cctx, &paymentsv1.TopUpRequest{UserId: userID, Amount: balance, IdempotencyKey: uuid.NewString()}); err != nil {
+				errs <- fmt.Errorf("top up %s: %w", userID, err)
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	var all []error
+	for err := range errs {
+		all = append(all, err)
+	}
+	return errors.Join(all...)
+}
+
+// run issues calls at cfg.rps for cfg.duration (open loop: the schedule does
+// not wait for answers) and waits for the calls in flight.
+func (r *runner) run(ctx context.Context, cfg loadConfig) time.Duration {
+	rng := rand.New(rand.NewPCG(cfg.seed, cfg.seed))
+	sem := make(chan struct{}, cfg.maxInFlight)
+	var wg sync.WaitGroup
+
+	tick := max(time.Duration(float64(time.Second)/cfg.rps), time.Millisecond)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	start := time.Now()
+	sent := 0
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case now := <-ticker.C:
+			elapsed := now.Sub(start)
+			if elapsed >= cfg.duration {
+				break loop
+			}
+			// Catch up with the schedule in one go when ticks are coarser
+			// than the call interval or were missed.
+			for due := int(elapsed.Seconds()*cfg.rps) + 1; sent < due; sent++ {
+				select {
+				case sem <- struct{}{}:
+				default:
+					r.dropped.Add(1)
+					continue
+				}
+				op, userID := pickOp(cfg.mix, rng), cfg.users[rng.IntN(len(cfg.users))]
+				wg.Add(1)
+				go func() {
+					defer func() { <-sem; wg.Done() }()
+					r.call(ctx, op, userID, cfg.amount)
+				}()
+			}
+		}
+	}
+	elapsed := time.Since(start)
+	wg.Wait()
+	return elapsed
+}
+
+func (r *runner) call(ctx context.Context, op, userID string, amount int64) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	var err error
+	switch op {
+	case opCreateOrder:
+		var resp *ordersv1.CreateOrderResponse
+		resp, err = r.orders.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+			UserId:         userID,
+			Amount:         amount,
+			Description:    "loadgen",
+			IdempotencyKey: uuid.NewString(),
+			// Every order of the run looks the same; it is not a repeat.
+			Force: true,
+		})
+		if err == nil {
+			r.mu.Lock()
+			r.created = append(r.created, createdOrder{userID: userID, orderID: resp.GetOrder().GetOrderId()})
+			r.mu.Unlock()
+		}
+	case opTopUp:
+		_, err = r.payments.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: userID, Amount: amount, IdempotencyKey: uuid.NewString()})
+	case opGetBalance:
+		_, err = r.payments.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: userID})
+	}
+	r.rec.observe(op, time.Since(start), err)
+}
+
+func (r *runner) createdOrders() []createdOrder {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]createdOrder(nil), r.created...)
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/testsupport"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+func TestParseMix(t *testing.T) {
+	got, err := parseMix("create_order=3, get_balance=1,top_up=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (mixEntry{opCreateOrder, 3}) || got[1] != (mixEntry{opGetBalance, 1}) {
+		t.Fatalf("parseMix() = %v", got)
+	}
+	for _, bad := range []string{"", "top_up=0", "pay=1", "top_up", "top_up=x", "top_up=1,top_up=2"} {
+		if _, err := parseMix(bad); err == nil {
+			t.Errorf("parseMix(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	var lat []time.Duration
+	for i := 1; i <= 100; i++ {
+		lat = append(lat, time.Duration(i)*time.Millisecond)
+	}
+	for p, want := range map[int]time.Duration{50: 50 * time.Millisecond, 90: 90 * time.Millisecond, 99: 99 * time.Millisecond, 100: 100 * time.Millisecond} {
+		if got := percentile(lat, p); got != want {
+			t.Errorf("percentile(%d) = %s, want %s", p, got, want)
+		}
+	}
+	if got := percentile(lat[:1], 99); got != time.Millisecond {
+		t.Errorf("percentile of one = %s", got)
+	}
+}
+
+func TestRunAndVerify(t *testing.T) {
+	b := testsupport.Start(t)
+	r := &runner{orders: b.OrdersClient, payments: b.PaymentsClient, rec: newRecorder(), timeout: time.Second}
+	users := []string{"u-1", "u-2"}
+	if err := r.setup(context.Background(), users, 1000, 2); err != nil {
+		t.Fatal(err)
+	}
+	// Accounts that already exist are kept.
+	if err := r.setup(context.Background(), users, 0, 2); err != nil {
+		t.Fatalf("setup again: %v", err)
+	}
+
+	mix, _ := parseMix("create_order=1,top_up=1,get_balance=1")
+	r.run(context.Background(), loadConfig{rps: 500, duration: 200 * time.Millisecond, maxInFlight: 16, users: users, amount: 10, mix: mix, seed: 1})
+
+	total := 0
+	for _, s := range r.rec.summary() {
+		if s.failed > 0 {
+			t.Fatalf("%s failed: %v", s.op, s.errors)
+		}
+		total += s.ok
+	}
+	if total < 50 || int64(total)+r.dropped.Load() > 101 {
+		t.Fatalf("%d calls and %d dropped at 500 rps for 200ms", total, r.dropped.Load())
+	}
+
+	created := r.createdOrders()
+	if len(created) == 0 || len(created) != b.Orders.Calls("CreateOrder") {
+		t.Fatalf("%d created orders for %d CreateOrder calls", len(created), b.Orders.Calls("CreateOrder"))
+	}
+
+	// The fake never pays, so every order stays NEW until finished here.
+	res := verify(context.Background(), b.OrdersClient, created, 50*time.Millisecond, 10*time.Millisecond, 4)
+	if len(res.pending) != len(created) {
+		t.Fatalf("%d of %d orders pending, want all", len(res.pending), len(created))
+	}
+	for _, o := range created {
+		b.Orders.SetStatus(o.orderID, ordersv1.OrderStatus_ORDER_STATUS_FINISHED)
+	}
+	res = verify(context.Background(), b.OrdersClient, created, time.Second, 10*time.Millisecond, 4)
+	if len(res.pending) != 0 || res.byStatus[ordersv1.OrderStatus_ORDER_STATUS_FINISHED] != len(created) {
+		t.Fatalf("verify() = %+v", res)
+	}
+}
@@ -0,0 +1,155 @@
+// Command loadgen drives orders-service and payments-service over gRPC at a
+// fixed rate and reports latency percentiles per call, to measure changes to
+// the outbox and consumer throughput.
+//
+//	go run ./cmd/loadgen -rps 200 -duration 1m -mix create_order=5,top_up=1,get_balance=4 -verify
+//
+// Calls go straight to the services, bypassing api-gateway. The load is open
+// loop: calls are issued on schedule whether or not earlier ones answered,
+// and calls that would exceed -max-in-flight are dropped and reported. With
+// -verify loadgen then waits until every order created during the run has
+// left NEW, i.e. its payment went through the whole pipeline, and fails if
+// some did not within -verify-timeout.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+func main() {
+	var (
+		ordersAddr    = flag.String("orders-addr", "localhost:9001", "gRPC address of orders-service")
+		paymentsAddr  = flag.String("payments-addr", "localhost:9002", "gRPC address of payments-service")
+		rps           = flag.Float64("rps", 50, "calls per second")
+		duration      = flag.Duration("duration", 30*time.Second, "how long to generate load")
+		mixFlag       = flag.String("mix", "create_order=5,top_up=1,get_balance=4", "weights of the calls")
+		users         = flag.Int("users", 100, "number of users the calls are spread over")
+		prefix        = flag.String("prefix", "", "user id prefix (default: loadgen-<random>)")
+		balance       = flag.Int64("balance", 1_000_000_000, "initial top-up of every user")
+		amount        = flag.Int64("amount", 100, "amount of every order and top-up")
+		maxInFlight   = flag.Int("max-in-flight", 512, "maximum concurrent calls")
+		timeout       = flag.Duration("timeout", 5*time.Second, "timeout of a single call")
+		seed          = flag.Uint64("seed", 0, "random seed of the call sequence (default: random)")
+		jwtSecret     = flag.String("jwt-secret", os.Getenv("JWT_SECRET"), "sign an admin token with this secret when the services check roles")
+		verifyFlag    = flag.Bool("verify", false, "after the run, wait until every created order has left NEW")
+		verifyTimeout = flag.Duration("verify-timeout", time.Minute, "how long -verify waits")
+	)
+	flag.Parse()
+
+	mix, err := parseMix(*mixFlag)
+	if err == nil && (*rps <= 0 || *users < 1 || *amount < 1 || *maxInFlight < 1) {
+		err = errors.New("rps, users, amount and max-in-flight must be positive")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(2)
+	}
+	if *prefix == "" {
+		*prefix = "loadgen-" + uuid.NewString()[:8]
+	}
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if *jwtSecret != "" {
+		claims := auth.Claims{Subject: "loadgen", Roles: []auth.Role{auth.RoleAdmin}, ExpiresAt: time.Now().Add(*duration + *verifyTimeout + time.Hour).Unix()}
+		token, err := auth.Sign(claims, []byte(*jwtSecret))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "loadgen:", err)
+			os.Exit(2)
+		}
+		ctx = auth.WithClaims(ctx, claims, token)
+	}
+
+	// The load stands in for gateway traffic, so with service authentication
+	// on it presents the gateway's identity.
+	interceptors := []grpc.UnaryClientInterceptor{auth.ForwardToken()}
+	serviceAuth, err := svcauth.ParseConfig(os.Getenv("SERVICE_AUTH_MODE"), os.Getenv("SERVICE_AUTH_TOKENS"), os.Getenv("SERVICE_AUTH_JWT_SECRET"), os.Getenv("SERVICE_AUTH_TRUST_DOMAIN"))
+	if err == nil && serviceAuth.Enabled() {
+		var creds grpc.UnaryClientInterceptor
+		if creds, err = svcauth.UnaryClientInterceptor(serviceAuth, "api-gateway"); err == nil {
+			interceptors = append(interceptors, creds)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "loadgen:", err)
+		os.Exit(2)
+	}
+
+	dial := func(addr string) *grpc.ClientConn {
+		conn, err := grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithChainUnaryInterceptor(interceptors...))
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "loadgen:", err)
+			os.Exit(2)
+		}
+		return conn
+	}
+	ordersConn, paymentsConn := dial(*ordersAddr), dial(*paymentsAddr)
+	defer ordersConn.Close()
+	defer paymentsConn.Close()
+
+	r := &runner{
+		orders:   ordersv1.NewOrdersServiceClient(ordersConn),
+		payments: paymentsv1.NewPaymentsServiceClient(paymentsConn),
+		rec:      newRecorder(),
+		timeout:  *timeout,
+	}
+	cfg := loadConfig{rps: *rps, duration: *duration, maxInFlight: *maxInFlight, amount: *amount, mix: mix, seed: *seed}
+	for i := range *users {
+		cfg.users = append(cfg.users, fmt.Sprintf("%s-%d", *prefix, i+1))
+	}
+
+	slog.Info("setting up accounts", "users", *users, "prefix", *prefix)
+	if err := r.setup(ctx, cfg.users, *balance, min(*maxInFlight, 32)); err != nil {
+		slog.Error("setup failed", "err", err)
+		os.Exit(1)
+	}
+
+	slog.Info("generating load", "rps", *rps, "duration", *duration, "mix", *mixFlag, "seed", *seed)
+	elapsed := r.run(ctx, cfg)
+	writeReport(os.Stdout, r.rec.summary(), elapsed)
+	if n := r.dropped.Load(); n > 0 {
+		fmt.Printf("dropped %d calls at -max-in-flight %d\n", n, *maxInFlight)
+	}
+
+	if !*verifyFlag {
+		return
+	}
+	created := r.createdOrders()
+	slog.Info("verifying orders leave NEW", "orders", len(created), "timeout", *verifyTimeout)
+	res := verify(ctx, r.orders, created, *verifyTimeout, 500*time.Millisecond, min(*maxInFlight, 64))
+	var counts []string
+	for st, n := range res.byStatus {
+		counts = append(counts, fmt.Sprintf("%s=%d", st, n))
+	}
+	sort.Strings(counts)
+	if len(res.pending) > 0 {
+		fmt.Printf("consistency: %d of %d orders still NEW after %s (%v)\n", len(res.pending), len(created), *verifyTimeout, counts)
+		for _, o := range res.pending[:min(len(res.pending), 10)] {
+			fmt.Printf("  %s (user %s)\n", o.orderID, o.userID)
+		}
+		os.Exit(1)
+	}
+	fmt.Printf("consistency: all %d orders left NEW within %s after the load (%v)\n", len(created), res.settled.Round(time.Millisecond), counts)
+}
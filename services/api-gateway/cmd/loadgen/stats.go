@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/status"
+)
+
+// recorder collects the latency and outcome of every call per operation.
+type recorder struct {
+	mu  sync.Mutex
+	ops map[string]*opStats
+}
+
+type opStats struct {
+	latencies []time.Duration
+	// errors by gRPC code
+	errors map[string]int
+}
+
+func newRecorder() *recorder {
+	return &recorder{ops: map[string]*opStats{}}
+}
+
+// observe records a call of op; failed calls count as errors and their
+// latency is not part of the percentiles.
+func (r *recorder) observe(op string, d time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s, ok := r.ops[op]
+	if !ok {
+		s = &opStats{errors: map[string]int{}}
+		r.ops[op] = s
+	}
+	if err != nil {
+		s.errors[status.Code(err).String()]++
+		return
+	}
+	s.latencies = append(s.latencies, d)
+}
+
+// opSummary is the report line of one operation.
+type opSummary struct {
+	op                 string
+	ok, failed         int
+	p50, p90, p99, max time.Duration
+	errors             map[string]int
+}
+
+func (r *recorder) summary() []opSummary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]opSummary, 0, len(r.ops))
+	for op, s := range r.ops {
+		lat := slices.Clone(s.latencies)
+		slices.Sort(lat)
+		sum := opSummary{op: op, ok: len(lat), errors: map[string]int{}}
+		for code, n := range s.errors {
+			sum.failed += n
+			sum.errors[code] = n
+		}
+		if len(lat) > 0 {
+			sum.p50 = percentile(lat, 50)
+			sum.p90 = percentile(lat, 90)
+			sum.p99 = percentile(lat, 99)
+			sum.max = lat[len(lat)-1]
+		}
+		out = append(out, sum)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].op < out[j].op })
+	return out
+}
+
+// percentile returns the nearest-rank percentile p of sorted.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank, 1)-1]
+}
+
+func writeReport(w io.Writer, sums []opSummary, elapsed time.Duration) {
+	fmt.Fprintf(w, "%-12s %8s %8s %8s %10s %10s %10s %10s  %s\n", "op", "ok", "failed", "rps", "p50", "p90", "p99", "max", "errors")
+	for _, s := range sums {
+		var errs []string
+		for code, n := range s.errors {
+			errs = append(errs, fmt.Sprintf("%s=%d", code, n))
+		}
+		sort.Strings(errs)
+		rps := float64(s.ok+s.failed) / elapsed.Seconds()
+		fmt.Fprintf(w, "%-12s %8d %8d %8.1f %10s %10s %10s %10s  %s\n",
+			s.op, s.ok, s.failed, rps, round(s.p50), round(s.p90), round(s.p99), round(s.max), strings.Join(errs, " "))
+	}
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(10 * time.Microsecond)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+// verifyResult is the outcome of waiting for the created orders to leave NEW.
+type verifyResult struct {
+	// byStatus counts the orders that left NEW by their status.
+	byStatus map[ordersv1.OrderStatus]int
+	// pending are the orders still NEW (or unreadable) at the deadline.
+	pending []createdOrder
+	// settled is how long after the start of verify the last order left NEW.
+	settled time.Duration
+}
+
+// verify polls the orders every interval until none of them is NEW or
+// timeout passes, checking that every payment requested during the run was
+// processed end to end: outbox → payments consumer → result consumer.
+func verify(ctx context.Context, orders ordersv1.OrdersServiceClient, created []createdOrder, timeout, interval time.Duration, concurrency int) verifyResult {
+	res := verifyResult{byStatus: map[ordersv1.OrderStatus]int{}, pending: created}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	for {
+		res.pending = pollPending(ctx, orders, res.pending, res.byStatus, concurrency)
+		if len(res.pending) == 0 {
+			res.settled = time.Since(start)
+			return res
+		}
+		select {
+		case <-ctx.Done():
+			return res
+		case <-time.After(interval):
+		}
+	}
+}
+
+// pollPending reads every pending order once and returns those still NEW;
+// the others are counted in byStatus. Orders that cannot be read stay pending.
+func pollPending(ctx context.Context, orders ordersv1.OrdersServiceClient, pending []createdOrder, byStatus map[ordersv1.OrderStatus]int, concurrency int) []createdOrder {
+	statuses := make([]ordersv1.OrderStatus, len(pending))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, o := range pending {
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			resp, err := orders.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: o.userID, OrderId: o.orderID})
+			if err == nil {
+				statuses[i] = resp.GetOrder().GetStatus()
+			}
+		}()
+	}
+	wg.Wait()
+
+	var still []createdOrder
+	for i, o := range pending {
+		switch statuses[i] {
+		case ordersv1.OrderStatus_ORDER_STATUS_NEW, ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED:
+			still = append(still, o)
+		default:
+			byStatus[statuses[i]]++
+		}
+	}
+	return still
+}
@@ -9,18 +9,28 @@ import (
 
 	"github.com/ilyaytrewq/payments-service/api-gateway/internal/app"
 	"github.com/ilyaytrewq/payments-service/api-gateway/internal/config"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).
-		With("service", "api-gateway")
-	slog.SetDefault(logger)
-
 	cfg := config.MustLoad()
 
+	logger, level, err := logging.NewLogger("api-gateway", cfg.LogLevel, cfg.LogFormat, logging.Redaction{
+		Mode:   cfg.LogRedact,
+		Fields: cfg.LogRedactFields,
+		Salt:   cfg.LogRedactSalt,
+	}, cfg.LogDev)
+	if err != nil {
+		slog.Error("invalid log config", "err", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	go logging.WatchLogLevel(ctx, "api-gateway", level)
+
 	if err := app.Run(ctx, cfg); err != nil {
 		slog.Error("api gateway stopped with error", "err", err)
 		os.Exit(1)
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+func newAccountCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "account", Short: "Correct payment accounts"}
+	cmd.AddCommand(newAdjustCmd(c), newRematerializeCmd(c))
+	return cmd
+}
+
+func newAdjustCmd(c *cli) *cobra.Command {
+	var (
+		amount    int64
+		reason    string
+		requestID string
+	)
+	cmd := &cobra.Command{
+		Use:   "adjust <user-id>",
+		Short: "Credit (positive --amount) or debit (negative) an account (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if amount == 0 {
+				return errors.New("--amount must be non-zero")
+			}
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			if strings.TrimSpace(requestID) == "" {
+				return errors.New("--request-id is required")
+			}
+			client, err := c.payments()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: args[0], Amount: amount, Reason: reason, RequestId: requestID})
+			if err != nil {
+				return err
+			}
+			a := resp.GetAccount()
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "account %s: balance %d %s (%+d), version %d\n", a.GetUserId(), a.GetBalance(), a.GetCurrency(), amount, a.GetVersion())
+			})
+		},
+	}
+	cmd.Flags().Int64Var(&amount, "amount", 0, "signed amount in minimal currency units (required)")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the balance is adjusted; stored in the audit log (required)")
+	cmd.Flags().StringVar(&requestID, "request-id", "", "id of this adjustment; rerun with the same id to retry it safely (required)")
+	return cmd
+}
+
+func newRematerializeCmd(c *cli) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "rematerialize <user-id>",
+		Short: "Reset the stored balance to the ledger balance and resolve its anomalies (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.payments()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.RematerializeBalance(ctx, &paymentsv1.RematerializeBalanceRequest{UserId: args[0], Reason: reason})
+			if err != nil {
+				return err
+			}
+			a := resp.GetAccount()
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "account %s: balance %d %s (was %d), version %d\n", a.GetUserId(), a.GetBalance(), a.GetCurrency(), resp.GetPreviousBalance(), a.GetVersion())
+				fmt.Fprintf(w, "resolved anomalies\t%d\n", resp.GetResolvedAnomalies())
+			})
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the balance is rematerialized; stored in the audit log (required)")
+	return cmd
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+// Values of --output.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// Values of --service.
+const (
+	serviceOrders   = "orders"
+	servicePayments = "payments"
+)
+
+// cli holds the global flags and the connections of one invocation.
+type cli struct {
+	ordersAddr   string
+	paymentsAddr string
+	jwtSecret    string
+	operator     string
+	timeout      time.Duration
+	output       string
+	// serviceAuth is read from the SERVICE_AUTH_* variables of the services;
+	// the CLI presents itself as paymentsctl.
+	serviceAuth svcauth.Config
+
+	out io.Writer
+	// dial opens a connection to addr; tests replace it with bufconn.
+	dial func(addr string) (*grpc.ClientConn, error)
+
+	mu    sync.Mutex
+	conns map[string]*grpc.ClientConn
+}
+
+func newCLI(out io.Writer) *cli {
+	c := &cli{out: out, conns: map[string]*grpc.ClientConn{}}
+	c.dial = func(addr string) (*grpc.ClientConn, error) {
+		interceptors := []grpc.UnaryClientInterceptor{auth.ForwardToken()}
+		if c.serviceAuth.Enabled() {
+			creds, err := svcauth.UnaryClientInterceptor(c.serviceAuth, "paymentsctl")
+			if err != nil {
+				return nil, err
+			}
+			interceptors = append(interceptors, creds)
+		}
+		return grpc.NewClient(addr, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithChainUnaryInterceptor(interceptors...))
+	}
+	return c
+}
+
+func newRootCmd(c *cli) *cobra.Command {
+	operator := os.Getenv("USER")
+	if operator == "" {
+		operator = "paymentsctl"
+	}
+	root := &cobra.Command{
+		Use:           "paymentsctl",
+		Short:         "Operate orders-service and payments-service through their admin APIs",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			if c.output != outputTable && c.output != outputJSON {
+				return fmt.Errorf("--output must be %s or %s", outputTable, outputJSON)
+			}
+			var err error
+			c.serviceAuth, err = svcauth.ParseConfig(os.Getenv("SERVICE_AUTH_MODE"), os.Getenv("SERVICE_AUTH_TOKENS"), os.Getenv("SERVICE_AUTH_JWT_SECRET"), os.Getenv("SERVICE_AUTH_TRUST_DOMAIN"))
+			return err
+		},
+		PersistentPostRun: func(*cobra.Command, []string) { c.close() },
+	}
+	f := root.PersistentFlags()
+	f.StringVar(&c.ordersAddr, "orders-addr", "localhost:9001", "gRPC address of orders-service")
+	f.StringVar(&c.paymentsAddr, "payments-addr", "localhost:9002", "gRPC address of payments-service")
+	f.StringVar(&c.jwtSecret, "jwt-secret", os.Getenv("JWT_SECRET"), "sign an admin token with this secret when the services check roles")
+	f.StringVar(&c.operator, "operator", operator, "subject of the admin token, recorded in the audit log")
+	f.DurationVar(&c.timeout, "timeout", 10*time.Second, "timeout of a call")
+	f.StringVarP(&c.output, "output", "o", outputTable, "output format: table or json")
+
+	root.AddCommand(newOrderCmd(c), newDLQCmd(c), newOutboxCmd(c), newInboxCmd(c), newAccountCmd(c), newDisputeCmd(c), newPromoCmd(c), newProjectionCmd(c))
+	return root
+}
+
+// context bounds a call by --timeout and attaches the admin token.
+func (c *cli) context(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	if c.jwtSecret == "" {
+		return ctx, cancel, nil
+	}
+	claims := auth.Claims{Subject: c.operator, Roles: []auth.Role{auth.RoleAdmin}, ExpiresAt: time.Now().Add(c.timeout + time.Minute).Unix()}
+	token, err := auth.Sign(claims, []byte(c.jwtSecret))
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return auth.WithClaims(ctx, claims, token), cancel, nil
+}
+
+func (c *cli) conn(addr string) (*grpc.ClientConn, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if conn, ok := c.conns[addr]; ok {
+		return conn, nil
+	}
+	conn, err := c.dial(addr)
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", addr, err)
+	}
+	c.conns[addr] = conn
+	return conn, nil
+}
+
+func (c *cli) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for addr, conn := range c.conns {
+		conn.Close()
+		delete(c.conns, addr)
+	}
+}
+
+func (c *cli) orders() (ordersv1.OrdersAdminServiceClient, error) {
+	conn, err := c.conn(c.ordersAddr)
+	if err != nil {
+		return nil, err
+	}
+	return ordersv1.NewOrdersAdminServiceClient(conn), nil
+}
+
+func (c *cli) payments() (paymentsv1.PaymentsAdminServiceClient, error) {
+	conn, err := c.conn(c.paymentsAddr)
+	if err != nil {
+		return nil, err
+	}
+	return paymentsv1.NewPaymentsAdminServiceClient(conn), nil
+}
+
+// print writes resp as JSON with --output json and calls table otherwise.
+func (c *cli) print(resp proto.Message, table func(w *tabwriter.Writer)) error {
+	if c.output == outputJSON {
+		b, err := protojson.MarshalOptions{Multiline: true, Indent: "  "}.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.out, string(b))
+		return err
+	}
+	w := tabwriter.NewWriter(c.out, 0, 4, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+// serviceFlag registers --service on cmd.
+func serviceFlag(cmd *cobra.Command, service *string) {
+	cmd.Flags().StringVar(service, "service", serviceOrders, "whose outbox: orders or payments")
+}
+
+func checkService(service string) error {
+	if service != serviceOrders && service != servicePayments {
+		return fmt.Errorf("--service must be %s or %s", serviceOrders, servicePayments)
+	}
+	return nil
+}
+
+// enumName strips the enum prefix: ORDER_STATUS_FINISHED becomes FINISHED.
+func enumName(v fmt.Stringer, prefix string) string {
+	return strings.TrimPrefix(v.String(), prefix)
+}
+
+func formatTime(ts *timestamppb.Timestamp) string {
+	if ts == nil {
+		return "-"
+	}
+	return ts.AsTime().UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+const disputeStatusPrefix = "DISPUTE_STATUS_"
+
+func newDisputeCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "dispute", Short: "Open and resolve payment disputes of orders"}
+	cmd.AddCommand(newDisputeOpenCmd(c), newDisputeResolveCmd(c))
+	return cmd
+}
+
+func newDisputeOpenCmd(c *cli) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "open <order-id>",
+		Short: "Freeze the payments of a finished order and move it to DISPUTED (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.payments()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: args[0], Reason: reason})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				writeDispute(w, resp.GetDispute())
+			})
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the payment is disputed; stored in the audit log (required)")
+	return cmd
+}
+
+func newDisputeResolveCmd(c *cli) *cobra.Command {
+	var resolution, reason string
+	cmd := &cobra.Command{
+		Use:   "resolve <order-id>",
+		Short: "Refund the disputed payment to the payer's balance or uphold it (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var res paymentsv1.DisputeResolution
+			switch resolution {
+			case "refund":
+				res = paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_REFUND
+			case "uphold":
+				res = paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_UPHOLD
+			default:
+				return errors.New("--resolution must be refund or uphold")
+			}
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.payments()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.ResolveDispute(ctx, &paymentsv1.ResolveDisputeRequest{OrderId: args[0], Resolution: res, Reason: reason})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				writeDispute(w, resp.GetDispute())
+				if a := resp.GetAccount(); a != nil {
+					fmt.Fprintf(w, "account\t%s: balance %d %s, version %d\n", a.GetUserId(), a.GetBalance(), a.GetCurrency(), a.GetVersion())
+				}
+			})
+		},
+	}
+	cmd.Flags().StringVar(&resolution, "resolution", "", "refund or uphold (required)")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the dispute is resolved this way; stored in the audit log (required)")
+	return cmd
+}
+
+func writeDispute(w *tabwriter.Writer, d *paymentsv1.Dispute) {
+	fmt.Fprintf(w, "order\t%s\n", d.GetOrderId())
+	fmt.Fprintf(w, "user\t%s\n", d.GetUserId())
+	fmt.Fprintf(w, "status\t%s\n", enumName(d.GetStatus(), disputeStatusPrefix))
+	fmt.Fprintf(w, "amount\t%d\n", d.GetAmount())
+	fmt.Fprintf(w, "reason\t%s\n", d.GetReason())
+	if r := d.GetResolutionReason(); r != "" {
+		fmt.Fprintf(w, "resolution\t%s\n", r)
+	}
+	fmt.Fprintf(w, "opened\t%s\n", formatTime(d.GetOpenedAt()))
+	fmt.Fprintf(w, "resolved\t%s\n", formatTime(d.GetResolvedAt()))
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+// The inbox consumer of each service: payment results in orders, payment
+// requests in payments.
+var defaultInboxConsumer = map[string]string{
+	serviceOrders:   "payment_result",
+	servicePayments: "payment_requested",
+}
+
+func newInboxCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "inbox", Short: "Run consumed messages kept in the inbox again"}
+	cmd.AddCommand(newInboxDryRunCmd(c))
+	return cmd
+}
+
+func newInboxDryRunCmd(c *cli) *cobra.Command {
+	var service, consumer string
+	cmd := &cobra.Command{
+		Use:   "dry-run <message-id>",
+		Short: "Run a stored message through its consumer and roll everything back",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkService(service); err != nil {
+				return err
+			}
+			if consumer == "" {
+				consumer = defaultInboxConsumer[service]
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			var resp *ordersv1.DryRunInboxMessageResponse
+			if service == serviceOrders {
+				client, err := c.orders()
+				if err != nil {
+					return err
+				}
+				if resp, err = client.DryRunInboxMessage(ctx, &ordersv1.DryRunInboxMessageRequest{Consumer: consumer, MessageId: args[0]}); err != nil {
+					return err
+				}
+			} else {
+				client, err := c.payments()
+				if err != nil {
+					return err
+				}
+				presp, err := client.DryRunInboxMessage(ctx, &paymentsv1.DryRunInboxMessageRequest{Consumer: consumer, MessageId: args[0]})
+				if err != nil {
+					return err
+				}
+				resp = dryRunFromPayments(presp)
+			}
+			return c.print(resp, func(w *tabwriter.Writer) { writeDryRun(w, resp) })
+		},
+	}
+	cmd.Flags().StringVar(&service, "service", serviceOrders, "whose inbox: orders or payments")
+	cmd.Flags().StringVar(&consumer, "consumer", "", "inbox consumer; defaults to the only one of the service")
+	return cmd
+}
+
+func dryRunFromPayments(p *paymentsv1.DryRunInboxMessageResponse) *ordersv1.DryRunInboxMessageResponse {
+	m := p.GetMessage()
+	resp := &ordersv1.DryRunInboxMessageResponse{
+		Message: &ordersv1.InboxMessage{
+			Consumer:      m.GetConsumer(),
+			MessageId:     m.GetMessageId(),
+			CorrelationId: m.GetCorrelationId(),
+			Topic:         m.GetTopic(),
+			Partition:     m.GetPartition(),
+			Offset:        m.GetOffset(),
+			Key:           m.GetKey(),
+			Payload:       m.GetPayload(),
+			ReceivedAt:    m.GetReceivedAt(),
+			ProcessedAt:   m.GetProcessedAt(),
+		},
+		EventJson: p.GetEventJson(),
+		Skipped:   p.GetSkipped(),
+		Error:     p.GetError(),
+	}
+	for _, h := range m.GetHeaders() {
+		resp.Message.Headers = append(resp.Message.Headers, &ordersv1.InboxMessageHeader{Key: h.GetKey(), Value: h.GetValue()})
+	}
+	for _, e := range p.GetWouldPublish() {
+		resp.WouldPublish = append(resp.WouldPublish, &ordersv1.DryRunOutboxEvent{Topic: e.GetTopic(), KafkaKey: e.GetKafkaKey(), Payload: e.GetPayload()})
+	}
+	return resp
+}
+
+func writeDryRun(w *tabwriter.Writer, resp *ordersv1.DryRunInboxMessageResponse) {
+	m := resp.GetMessage()
+	fmt.Fprintf(w, "message\t%s\n", m.GetMessageId())
+	fmt.Fprintf(w, "consumer\t%s\n", m.GetConsumer())
+	fmt.Fprintf(w, "record\t%s[%d]@%d key %q\n", m.GetTopic(), m.GetPartition(), m.GetOffset(), m.GetKey())
+	fmt.Fprintf(w, "correlation\t%s\n", m.GetCorrelationId())
+	fmt.Fprintf(w, "received\t%s\n", formatTime(m.GetReceivedAt()))
+	fmt.Fprintf(w, "processed\t%s\n", formatTime(m.GetProcessedAt()))
+	fmt.Fprintf(w, "event\t%s\n", resp.GetEventJson())
+	switch {
+	case resp.GetSkipped() != "":
+		fmt.Fprintf(w, "result\tskipped: %s\n", resp.GetSkipped())
+	case resp.GetError() != "":
+		fmt.Fprintf(w, "result\tfailed: %s\n", resp.GetError())
+	default:
+		fmt.Fprintf(w, "result\tapplied (rolled back)\n")
+	}
+
+	fmt.Fprintf(w, "\nWOULD PUBLISH (%d)\n", len(resp.GetWouldPublish()))
+	if len(resp.GetWouldPublish()) > 0 {
+		fmt.Fprintln(w, "TOPIC\tKEY\tBYTES")
+	}
+	for _, e := range resp.GetWouldPublish() {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", e.GetTopic(), e.GetKafkaKey(), len(e.GetPayload()))
+	}
+}
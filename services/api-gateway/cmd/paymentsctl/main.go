@@ -0,0 +1,39 @@
+// Command paymentsctl is the operator CLI over the admin gRPC APIs of
+// orders-service and payments-service (registered with ENABLE_ADMIN_API).
+//
+//	paymentsctl order saga 5b0c…                     # order, installments, retries, outbox events
+//	paymentsctl order force-status 5b0c… --status FINISHED --reason "settled by hand"
+//	paymentsctl outbox list --state failed --topic payments.payment_requested.v1 --from 2024-05-01T00:00:00Z
+//	paymentsctl dlq list --service payments --shard 1
+//	paymentsctl dlq requeue --service orders 41 42
+//	paymentsctl outbox replay --service orders --from 2024-05-01T00:00:00Z --to 2024-05-01T06:00:00Z --dry-run
+//	paymentsctl account adjust user-1 --amount -500 --reason "duplicate top-up" --request-id adj-2024-05-01-1
+//	paymentsctl account rematerialize user-1 --reason "balance anomaly 17"
+//	paymentsctl promo create SPRING10 --percent 10 --max-redemptions 1000 --per-user 1 --expires 2024-06-01T00:00:00Z
+//	paymentsctl promo get SPRING10                   # redemptions and discounts given
+//	paymentsctl projection replay order_status_history --rate 1000 --reason "status bug"
+//	paymentsctl projection status 3                  # progress per partition
+//
+// With --jwt-secret (JWT_SECRET by default) every call carries an admin token
+// whose subject is --operator; the services record it in their logs and in
+// admin_audit_log for force-status and adjust. Without it the services must
+// run with RBAC off and the operator is not known to them; adjust is then
+// refused.
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if err := newRootCmd(newCLI(os.Stdout)).ExecuteContext(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "paymentsctl:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+const orderStatusPrefix = "ORDER_STATUS_"
+
+func newOrderCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "order", Short: "Inspect and repair orders of any user"}
+	cmd.AddCommand(
+		&cobra.Command{
+			Use:   "get <order-id>",
+			Short: "Show an order, archived ones included",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				resp, err := c.inspectOrder(cmd, args[0])
+				if err != nil {
+					return err
+				}
+				return c.print(resp.GetOrder(), func(w *tabwriter.Writer) { writeOrder(w, resp.GetOrder()) })
+			},
+		},
+		&cobra.Command{
+			Use:   "saga <order-id>",
+			Short: "Show an order with its installments, scheduled retries and outbox events",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				resp, err := c.inspectOrder(cmd, args[0])
+				if err != nil {
+					return err
+				}
+				return c.print(resp, func(w *tabwriter.Writer) { writeSaga(w, resp) })
+			},
+		},
+		newForceStatusCmd(c),
+	)
+	return cmd
+}
+
+func (c *cli) inspectOrder(cmd *cobra.Command, orderID string) (*ordersv1.InspectOrderResponse, error) {
+	client, err := c.orders()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel, err := c.context(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return client.InspectOrder(ctx, &ordersv1.InspectOrderRequest{OrderId: orderID})
+}
+
+func newForceStatusCmd(c *cli) *cobra.Command {
+	var statusName, reason string
+	cmd := &cobra.Command{
+		Use:   "force-status <order-id>",
+		Short: "Set the status of an order regardless of its payments (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			st, err := parseOrderStatus(statusName)
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: args[0], Status: st, Reason: reason})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "order %s: %s -> %s (version %d)\n", resp.GetOrder().GetOrderId(),
+					enumName(resp.GetPreviousStatus(), orderStatusPrefix), enumName(resp.GetOrder().GetStatus(), orderStatusPrefix), resp.GetOrder().GetVersion())
+			})
+		},
+	}
+	cmd.Flags().StringVar(&statusName, "status", "", "new status: NEW, PARTIALLY_PAID, FINISHED or CANCELLED")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the status is forced; stored in the audit log")
+	return cmd
+}
+
+// parseOrderStatus accepts FINISHED as well as ORDER_STATUS_FINISHED, in any
+// case.
+func parseOrderStatus(s string) (ordersv1.OrderStatus, error) {
+	name := strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(name, orderStatusPrefix) {
+		name = orderStatusPrefix + name
+	}
+	v, ok := ordersv1.OrderStatus_value[name]
+	if !ok || v == int32(ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED) {
+		return 0, fmt.Errorf("--status %q: want NEW, PARTIALLY_PAID, FINISHED or CANCELLED", s)
+	}
+	return ordersv1.OrderStatus(v), nil
+}
+
+func writeOrder(w *tabwriter.Writer, o *ordersv1.Order) {
+	fmt.Fprintf(w, "order\t%s\n", o.GetOrderId())
+	fmt.Fprintf(w, "user\t%s\n", o.GetUserId())
+	fmt.Fprintf(w, "status\t%s\n", enumName(o.GetStatus(), orderStatusPrefix))
+	fmt.Fprintf(w, "amount\t%d %s (paid %d, fee %d)\n", o.GetAmount(), o.GetCurrency(), o.GetPaidAmount(), o.GetFeeAmount())
+	fmt.Fprintf(w, "description\t%s\n", o.GetDescription())
+	if r := o.GetPaymentFailureReason(); r != "" {
+		fmt.Fprintf(w, "failure\t%s\n", r)
+	}
+	fmt.Fprintf(w, "version\t%d\n", o.GetVersion())
+	fmt.Fprintf(w, "created\t%s\n", formatTime(o.GetCreatedAt()))
+	fmt.Fprintf(w, "updated\t%s\n", formatTime(o.GetUpdatedAt()))
+	if o.GetArchived() {
+		fmt.Fprintf(w, "archived\tyes\n")
+	}
+	if d := o.GetDispute(); d != nil {
+		fmt.Fprintf(w, "dispute\t%s, %d since %s: %s\n", enumName(d.GetStatus(), disputeStatusPrefix), d.GetAmount(), formatTime(d.GetOpenedAt()), d.GetReason())
+	}
+}
+
+func writeSaga(w *tabwriter.Writer, resp *ordersv1.InspectOrderResponse) {
+	writeOrder(w, resp.GetOrder())
+
+	fmt.Fprintf(w, "\nINSTALLMENTS (%d)\n", len(resp.GetPayments()))
+	if len(resp.GetPayments()) > 0 {
+		fmt.Fprintln(w, "PAYMENT\tAMOUNT\tFEE\tSTATUS\tCREATED\tFAILURE")
+	}
+	for _, p := range resp.GetPayments() {
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\t%s\n", p.GetPaymentId(), p.GetAmount(), p.GetFee(), p.GetStatus(), formatTime(p.GetCreatedAt()), p.GetFailureReason())
+	}
+
+	fmt.Fprintf(w, "\nRETRIES (%d)\n", len(resp.GetRetries()))
+	if len(resp.GetRetries()) > 0 {
+		fmt.Fprintln(w, "KEY\tATTEMPTS\tNEXT\tLAST REASON")
+	}
+	for _, r := range resp.GetRetries() {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", r.GetRetryKey(), r.GetAttempts(), formatTime(r.GetNextAttemptAt()), r.GetLastReason())
+	}
+
+	fmt.Fprintf(w, "\nOUTBOX (%d)\n", len(resp.GetEvents()))
+	if len(resp.GetEvents()) > 0 {
+		fmt.Fprintln(w, "ID\tTOPIC\tSTATUS\tATTEMPTS\tCREATED\tSENT\tCORRELATION\tLAST ERROR")
+	}
+	for _, e := range resp.GetEvents() {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", e.GetId(), e.GetTopic(), e.GetStatus(), e.GetAttempts(),
+			formatTime(e.GetCreatedAt()), formatTime(e.GetSentAt()), e.GetCorrelationId(), e.GetLastError())
+	}
+
+	fmt.Fprintf(w, "\nSTATUS HISTORY (%d)\n", len(resp.GetStatusHistory()))
+	if len(resp.GetStatusHistory()) > 0 {
+		fmt.Fprintln(w, "AT\tFROM\tTO\tREASON")
+	}
+	for _, c := range resp.GetStatusHistory() {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", formatTime(c.GetOccurredAt()), c.GetPreviousStatus(), c.GetStatus(), c.GetReason())
+	}
+}
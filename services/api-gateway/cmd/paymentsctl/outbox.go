@@ -0,0 +1,387 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+// Both services page dead events with the same request and event shape; the
+// CLI works on the orders messages and converts the payments ones.
+
+func newDLQCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "dlq", Short: "List and requeue dead-lettered outbox events"}
+	cmd.AddCommand(newDLQListCmd(c), newDLQRequeueCmd(c))
+	return cmd
+}
+
+func newDLQListCmd(c *cli) *cobra.Command {
+	var (
+		service, topic string
+		shard          int32
+		limit          int
+		afterID        int64
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List dead-lettered outbox events, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := checkService(service); err != nil {
+				return err
+			}
+			if limit < 1 {
+				return errors.New("--limit must be positive")
+			}
+			resp := &ordersv1.ListDeadOutboxResponse{}
+			// Pages until --limit events are listed or none are left;
+			// next_after_id tells where to continue.
+			for {
+				page, err := c.listDead(cmd, service, &ordersv1.ListDeadOutboxRequest{
+					Topic:    topic,
+					PageSize: int32(min(limit-len(resp.Events), 500)),
+					AfterId:  afterID,
+				}, shard)
+				if err != nil {
+					return err
+				}
+				resp.Events = append(resp.Events, page.GetEvents()...)
+				resp.NextAfterId = page.GetNextAfterId()
+				afterID = page.GetNextAfterId()
+				if afterID == 0 || len(resp.Events) >= limit {
+					break
+				}
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\tTOPIC\tKEY\tATTEMPTS\tCREATED\tLAST ERROR")
+				for _, e := range resp.GetEvents() {
+					fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", e.GetId(), e.GetTopic(), e.GetKafkaKey(), e.GetAttempts(), formatTime(e.GetCreatedAt()), e.GetLastError())
+				}
+				if resp.GetNextAfterId() != 0 {
+					fmt.Fprintf(w, "more: --after %d\n", resp.GetNextAfterId())
+				}
+			})
+		},
+	}
+	serviceFlag(cmd, &service)
+	cmd.Flags().StringVar(&topic, "topic", "", "only events of this topic")
+	cmd.Flags().Int32Var(&shard, "shard", 0, "account shard (payments only)")
+	cmd.Flags().IntVar(&limit, "limit", 100, "list at most this many events")
+	cmd.Flags().Int64Var(&afterID, "after", 0, "list events with an id greater than this")
+	return cmd
+}
+
+func (c *cli) listDead(cmd *cobra.Command, service string, req *ordersv1.ListDeadOutboxRequest, shard int32) (*ordersv1.ListDeadOutboxResponse, error) {
+	ctx, cancel, err := c.context(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	if service == serviceOrders {
+		client, err := c.orders()
+		if err != nil {
+			return nil, err
+		}
+		return client.ListDeadOutbox(ctx, req)
+	}
+	client, err := c.payments()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{
+		Topic:    req.GetTopic(),
+		PageSize: req.GetPageSize(),
+		AfterId:  req.GetAfterId(),
+		Shard:    shard,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &ordersv1.ListDeadOutboxResponse{NextAfterId: resp.GetNextAfterId()}
+	for _, e := range resp.GetEvents() {
+		out.Events = append(out.Events, &ordersv1.DeadOutboxEvent{
+			Id:        e.GetId(),
+			Topic:     e.GetTopic(),
+			KafkaKey:  e.GetKafkaKey(),
+			Attempts:  e.GetAttempts(),
+			LastError: e.GetLastError(),
+			CreatedAt: e.GetCreatedAt(),
+		})
+	}
+	return out, nil
+}
+
+func newDLQRequeueCmd(c *cli) *cobra.Command {
+	var (
+		service, topic string
+		shard          int32
+		all            bool
+	)
+	cmd := &cobra.Command{
+		Use:   "requeue [id...]",
+		Short: "Put dead-lettered events back in the queue with their attempts reset",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := checkService(service); err != nil {
+				return err
+			}
+			if all == (len(args) > 0) {
+				return errors.New("give event ids or --all, not both")
+			}
+			ids := make([]int64, 0, len(args))
+			for _, a := range args {
+				id, err := strconv.ParseInt(a, 10, 64)
+				if err != nil || id < 1 {
+					return fmt.Errorf("event id %q: want a positive integer", a)
+				}
+				ids = append(ids, id)
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			var requeued int64
+			if service == serviceOrders {
+				client, err := c.orders()
+				if err != nil {
+					return err
+				}
+				resp, err := client.RequeueDeadOutbox(ctx, &ordersv1.RequeueDeadOutboxRequest{Ids: ids, All: all, Topic: topic})
+				if err != nil {
+					return err
+				}
+				requeued = resp.GetRequeued()
+			} else {
+				client, err := c.payments()
+				if err != nil {
+					return err
+				}
+				resp, err := client.RequeueDeadOutbox(ctx, &paymentsv1.RequeueDeadOutboxRequest{Ids: ids, All: all, Topic: topic, Shard: shard})
+				if err != nil {
+					return err
+				}
+				requeued = resp.GetRequeued()
+			}
+			resp := &ordersv1.RequeueDeadOutboxResponse{Requeued: requeued}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintf(w, "requeued %d events\n", requeued)
+			})
+		},
+	}
+	serviceFlag(cmd, &service)
+	cmd.Flags().BoolVar(&all, "all", false, "requeue every dead event (of --topic when set)")
+	cmd.Flags().StringVar(&topic, "topic", "", "only events of this topic")
+	cmd.Flags().Int32Var(&shard, "shard", 0, "account shard (payments only)")
+	return cmd
+}
+
+func newOutboxCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "outbox", Short: "Browse unsent outbox events and re-emit sent ones"}
+	cmd.AddCommand(newOutboxListCmd(c), newOutboxReplayCmd(c))
+	return cmd
+}
+
+const outboxStatePrefix = "OUTBOX_STATE_"
+
+func newOutboxListCmd(c *cli) *cobra.Command {
+	var (
+		service, stateName, topic, from, to string
+		shard                               int32
+		limit                               int
+		afterID                             int64
+	)
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List outbox events that are unsent, failing or dead, oldest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := checkService(service); err != nil {
+				return err
+			}
+			state, ok := ordersv1.OutboxState_value[outboxStatePrefix+strings.ToUpper(stateName)]
+			if !ok || state == int32(ordersv1.OutboxState_OUTBOX_STATE_UNSPECIFIED) {
+				return fmt.Errorf("--state %q: want unsent, failed or dead", stateName)
+			}
+			if limit < 1 {
+				return errors.New("--limit must be positive")
+			}
+			req := &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState(state), Topic: topic, AfterId: afterID}
+			var err error
+			if req.CreatedFrom, err = parseTimeFlag("--from", from); err != nil {
+				return err
+			}
+			if req.CreatedTo, err = parseTimeFlag("--to", to); err != nil {
+				return err
+			}
+
+			resp := &ordersv1.ListOutboxResponse{}
+			for {
+				req.PageSize = int32(min(limit-len(resp.Events), 500))
+				page, err := c.listOutbox(cmd, service, req, shard)
+				if err != nil {
+					return err
+				}
+				resp.Events = append(resp.Events, page.GetEvents()...)
+				resp.NextAfterId = page.GetNextAfterId()
+				req.AfterId = page.GetNextAfterId()
+				if req.AfterId == 0 || len(resp.Events) >= limit {
+					break
+				}
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				fmt.Fprintln(w, "ID\tTOPIC\tKEY\tSTATUS\tATTEMPTS\tCREATED\tNEXT RETRY\tCORRELATION\tLAST ERROR")
+				for _, e := range resp.GetEvents() {
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\t%s\t%s\n", e.GetId(), e.GetTopic(), e.GetKafkaKey(), e.GetStatus(), e.GetAttempts(),
+						formatTime(e.GetCreatedAt()), formatTime(e.GetNextRetryAt()), e.GetCorrelationId(), e.GetLastError())
+				}
+				if resp.GetNextAfterId() != 0 {
+					fmt.Fprintf(w, "more: --after %d\n", resp.GetNextAfterId())
+				}
+			})
+		},
+	}
+	serviceFlag(cmd, &service)
+	cmd.Flags().StringVar(&stateName, "state", "unsent", "unsent, failed (backing off after a failed publish) or dead")
+	cmd.Flags().StringVar(&topic, "topic", "", "only events of this topic")
+	cmd.Flags().StringVar(&from, "from", "", "only events created at or after this time, RFC 3339")
+	cmd.Flags().StringVar(&to, "to", "", "only events created before this time, RFC 3339")
+	cmd.Flags().Int32Var(&shard, "shard", 0, "account shard (payments only)")
+	cmd.Flags().IntVar(&limit, "limit", 100, "list at most this many events")
+	cmd.Flags().Int64Var(&afterID, "after", 0, "list events with an id greater than this")
+	return cmd
+}
+
+func (c *cli) listOutbox(cmd *cobra.Command, service string, req *ordersv1.ListOutboxRequest, shard int32) (*ordersv1.ListOutboxResponse, error) {
+	ctx, cancel, err := c.context(cmd.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	if service == serviceOrders {
+		client, err := c.orders()
+		if err != nil {
+			return nil, err
+		}
+		return client.ListOutbox(ctx, req)
+	}
+	client, err := c.payments()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{
+		State:       paymentsv1.OutboxState(req.GetState()),
+		Topic:       req.GetTopic(),
+		CreatedFrom: req.GetCreatedFrom(),
+		CreatedTo:   req.GetCreatedTo(),
+		PageSize:    req.GetPageSize(),
+		AfterId:     req.GetAfterId(),
+		Shard:       shard,
+	})
+	if err != nil {
+		return nil, err
+	}
+	out := &ordersv1.ListOutboxResponse{NextAfterId: resp.GetNextAfterId()}
+	for _, e := range resp.GetEvents() {
+		out.Events = append(out.Events, &ordersv1.OutboxEvent{
+			Id:            e.GetId(),
+			Topic:         e.GetTopic(),
+			KafkaKey:      e.GetKafkaKey(),
+			Status:        e.GetStatus(),
+			Attempts:      e.GetAttempts(),
+			LastError:     e.GetLastError(),
+			CreatedAt:     e.GetCreatedAt(),
+			NextRetryAt:   e.GetNextRetryAt(),
+			CorrelationId: e.GetCorrelationId(),
+		})
+	}
+	return out, nil
+}
+
+// parseTimeFlag parses an optional RFC 3339 flag value; empty gives nil.
+func parseTimeFlag(name, value string) (*timestamppb.Timestamp, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%s: want RFC 3339, e.g. 2024-05-01T00:00:00Z: %w", name, err)
+	}
+	return timestamppb.New(t), nil
+}
+
+func newOutboxReplayCmd(c *cli) *cobra.Command {
+	var (
+		service, topic, from, to string
+		dryRun                   bool
+	)
+	cmd := &cobra.Command{
+		Use:   "replay",
+		Short: "Re-emit copies of the events sent in [--from, --to)",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if err := checkService(service); err != nil {
+				return err
+			}
+			fromTime, err := time.Parse(time.RFC3339, from)
+			if err != nil {
+				return fmt.Errorf("--from: want RFC 3339, e.g. 2024-05-01T00:00:00Z: %w", err)
+			}
+			toTime, err := time.Parse(time.RFC3339, to)
+			if err != nil {
+				return fmt.Errorf("--to: want RFC 3339, e.g. 2024-05-01T06:00:00Z: %w", err)
+			}
+			req := &ordersv1.ReplayOutboxRequest{From: timestamppb.New(fromTime), To: timestamppb.New(toTime), Topic: topic, DryRun: dryRun}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+
+			var resp *ordersv1.ReplayOutboxResponse
+			if service == serviceOrders {
+				client, err := c.orders()
+				if err != nil {
+					return err
+				}
+				if resp, err = client.ReplayOutbox(ctx, req); err != nil {
+					return err
+				}
+			} else {
+				client, err := c.payments()
+				if err != nil {
+					return err
+				}
+				presp, err := client.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: req.GetFrom(), To: req.GetTo(), Topic: topic, DryRun: dryRun})
+				if err != nil {
+					return err
+				}
+				resp = &ordersv1.ReplayOutboxResponse{Matched: presp.GetMatched(), Replayed: presp.GetReplayed(), DryRun: presp.GetDryRun()}
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				if resp.GetDryRun() {
+					fmt.Fprintf(w, "%d events match (dry run)\n", resp.GetMatched())
+					return
+				}
+				fmt.Fprintf(w, "replayed %d of %d matching events\n", resp.GetReplayed(), resp.GetMatched())
+			})
+		},
+	}
+	serviceFlag(cmd, &service)
+	cmd.Flags().StringVar(&from, "from", "", "start of the creation time range, RFC 3339 (required)")
+	cmd.Flags().StringVar(&to, "to", "", "end of the creation time range, exclusive, RFC 3339 (required)")
+	cmd.Flags().StringVar(&topic, "topic", "", "only events of this topic")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only count the matching events")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+	return cmd
+}
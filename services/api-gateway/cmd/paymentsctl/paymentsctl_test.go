@@ -0,0 +1,398 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+type fakeOrdersAdmin struct {
+	ordersv1.UnimplementedOrdersAdminServiceServer
+	force   *ordersv1.ForceOrderStatusRequest
+	requeue *ordersv1.RequeueDeadOutboxRequest
+	replay  *ordersv1.ReplayOutboxRequest
+	promo   *ordersv1.PromoCode
+	started *ordersv1.StartProjectionReplayRequest
+	// token is the authorization metadata of the last call.
+	token string
+}
+
+func (f *fakeOrdersAdmin) InspectOrder(_ context.Context, req *ordersv1.InspectOrderRequest) (*ordersv1.InspectOrderResponse, error) {
+	return &ordersv1.InspectOrderResponse{
+		Order:    &ordersv1.Order{OrderId: req.GetOrderId(), UserId: "user-1", Amount: 300, Currency: "RUB", Status: ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID, PaidAmount: 100},
+		Payments: []*ordersv1.OrderPaymentStep{{PaymentId: "pay-1", Amount: 100, Status: "SUCCEEDED"}, {PaymentId: "pay-2", Amount: 200, Status: "PENDING"}},
+		Retries:  []*ordersv1.PaymentRetryStep{{RetryKey: "pay-2", Attempts: 1, NextAttemptAt: timestamppb.New(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)), LastReason: "FAIL_INTERNAL"}},
+		Events:   []*ordersv1.OutboxEventStep{{Id: 7, Topic: "payments.requested", Status: "SENT"}},
+		StatusHistory: []*ordersv1.OrderStatusChange{
+			{PreviousStatus: "NEW", Status: "PARTIALLY_PAID", OccurredAt: timestamppb.New(time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC))},
+		},
+	}, nil
+}
+
+func (f *fakeOrdersAdmin) ForceOrderStatus(ctx context.Context, req *ordersv1.ForceOrderStatusRequest) (*ordersv1.ForceOrderStatusResponse, error) {
+	f.force, f.token = req, tokenOf(ctx)
+	return &ordersv1.ForceOrderStatusResponse{
+		Order:          &ordersv1.Order{OrderId: req.GetOrderId(), Status: req.GetStatus(), Version: 3},
+		PreviousStatus: ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID,
+	}, nil
+}
+
+func (f *fakeOrdersAdmin) RequeueDeadOutbox(_ context.Context, req *ordersv1.RequeueDeadOutboxRequest) (*ordersv1.RequeueDeadOutboxResponse, error) {
+	f.requeue = req
+	return &ordersv1.RequeueDeadOutboxResponse{Requeued: int64(len(req.GetIds()))}, nil
+}
+
+func (f *fakeOrdersAdmin) ReplayOutbox(_ context.Context, req *ordersv1.ReplayOutboxRequest) (*ordersv1.ReplayOutboxResponse, error) {
+	f.replay = req
+	return &ordersv1.ReplayOutboxResponse{Matched: 5, DryRun: req.GetDryRun()}, nil
+}
+
+func (f *fakeOrdersAdmin) CreatePromoCode(_ context.Context, req *ordersv1.CreatePromoCodeRequest) (*ordersv1.CreatePromoCodeResponse, error) {
+	f.promo = req.GetPromoCode()
+	p := proto.Clone(req.GetPromoCode()).(*ordersv1.PromoCode)
+	p.Code = strings.ToUpper(p.GetCode())
+	return &ordersv1.CreatePromoCodeResponse{PromoCode: p}, nil
+}
+
+func (f *fakeOrdersAdmin) GetPromoCode(_ context.Context, req *ordersv1.GetPromoCodeRequest) (*ordersv1.GetPromoCodeResponse, error) {
+	return &ordersv1.GetPromoCodeResponse{
+		PromoCode:   &ordersv1.PromoCode{Code: req.GetCode(), AmountOff: 500, MaxRedemptions: 100, RedeemedCount: 7},
+		Redemptions: 7, Released: 2, DiscountTotal: 3500, AmountTotal: 21000,
+	}, nil
+}
+
+func (f *fakeOrdersAdmin) StartProjectionReplay(_ context.Context, req *ordersv1.StartProjectionReplayRequest) (*ordersv1.StartProjectionReplayResponse, error) {
+	f.started = req
+	return &ordersv1.StartProjectionReplayResponse{Replay: &ordersv1.ProjectionReplay{
+		Id: 3, Projection: req.GetProjection(), Topic: "orders.order_status_changed.v1", Status: ordersv1.ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_RUNNING,
+		RateLimit: req.GetRateLimit(), RequestedBy: "alice", Reason: req.GetReason(),
+	}}, nil
+}
+
+func (f *fakeOrdersAdmin) GetProjectionReplay(_ context.Context, req *ordersv1.GetProjectionReplayRequest) (*ordersv1.GetProjectionReplayResponse, error) {
+	return &ordersv1.GetProjectionReplayResponse{Replay: &ordersv1.ProjectionReplay{
+		Id: req.GetReplayId(), Projection: "order_status_history", Status: ordersv1.ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_RUNNING,
+		Applied: 40, Remaining: 60,
+		Partitions: []*ordersv1.ProjectionReplayPartition{{Partition: 0, StartOffset: 0, NextOffset: 40, EndOffset: 50}, {Partition: 1, StartOffset: 10, NextOffset: 10, EndOffset: 60}},
+	}}, nil
+}
+
+type fakePaymentsAdmin struct {
+	paymentsv1.UnimplementedPaymentsAdminServiceServer
+	adjust *paymentsv1.AdjustBalanceRequest
+	remat  *paymentsv1.RematerializeBalanceRequest
+	listed *paymentsv1.ListOutboxRequest
+	dryRun *paymentsv1.DryRunInboxMessageRequest
+	// resolve is the last ResolveDispute request.
+	resolve *paymentsv1.ResolveDisputeRequest
+	// dead are the events ListDeadOutbox pages through, two at a time.
+	dead []*paymentsv1.DeadOutboxEvent
+}
+
+func (f *fakePaymentsAdmin) AdjustBalance(_ context.Context, req *paymentsv1.AdjustBalanceRequest) (*paymentsv1.AdjustBalanceResponse, error) {
+	f.adjust = req
+	return &paymentsv1.AdjustBalanceResponse{Account: &paymentsv1.Account{UserId: req.GetUserId(), Balance: 1000 + req.GetAmount(), Currency: "RUB", Version: 2}}, nil
+}
+
+func (f *fakePaymentsAdmin) RematerializeBalance(_ context.Context, req *paymentsv1.RematerializeBalanceRequest) (*paymentsv1.RematerializeBalanceResponse, error) {
+	f.remat = req
+	return &paymentsv1.RematerializeBalanceResponse{
+		Account:         &paymentsv1.Account{UserId: req.GetUserId(), Balance: 900, Currency: "RUB", Version: 4},
+		PreviousBalance: 1000, ResolvedAnomalies: 1,
+	}, nil
+}
+
+func (f *fakePaymentsAdmin) ResolveDispute(_ context.Context, req *paymentsv1.ResolveDisputeRequest) (*paymentsv1.ResolveDisputeResponse, error) {
+	f.resolve = req
+	return &paymentsv1.ResolveDisputeResponse{
+		Dispute: &paymentsv1.Dispute{OrderId: req.GetOrderId(), UserId: "user-1", Status: paymentsv1.DisputeStatus_DISPUTE_STATUS_REFUNDED, Amount: 310, Reason: "not delivered", ResolutionReason: req.GetReason()},
+		Account: &paymentsv1.Account{UserId: "user-1", Balance: 1310, Currency: "RUB", Version: 3},
+	}, nil
+}
+
+func (f *fakePaymentsAdmin) ListDeadOutbox(_ context.Context, req *paymentsv1.ListDeadOutboxRequest) (*paymentsv1.ListDeadOutboxResponse, error) {
+	resp := &paymentsv1.ListDeadOutboxResponse{}
+	for _, e := range f.dead {
+		if e.GetId() > req.GetAfterId() && len(resp.Events) < int(min(req.GetPageSize(), 2)) {
+			resp.Events = append(resp.Events, e)
+		}
+	}
+	if n := len(resp.Events); n > 0 && n == int(min(req.GetPageSize(), 2)) {
+		resp.NextAfterId = resp.Events[n-1].GetId()
+	}
+	return resp, nil
+}
+
+func (f *fakePaymentsAdmin) ListOutbox(_ context.Context, req *paymentsv1.ListOutboxRequest) (*paymentsv1.ListOutboxResponse, error) {
+	f.listed = req
+	return &paymentsv1.ListOutboxResponse{Events: []*paymentsv1.OutboxEvent{
+		{Id: 3, Topic: "accounts.created", Status: "FAILED", Attempts: 2, LastError: "broker down", NextRetryAt: timestamppb.New(time.Date(2024, 5, 1, 0, 0, 5, 0, time.UTC))},
+	}}, nil
+}
+
+func (f *fakePaymentsAdmin) DryRunInboxMessage(_ context.Context, req *paymentsv1.DryRunInboxMessageRequest) (*paymentsv1.DryRunInboxMessageResponse, error) {
+	f.dryRun = req
+	return &paymentsv1.DryRunInboxMessageResponse{
+		Message: &paymentsv1.InboxMessage{
+			Consumer: req.GetConsumer(), MessageId: req.GetMessageId(), Topic: "orders.payment_requested", Partition: 1, Offset: 42,
+			ReceivedAt: timestamppb.New(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)),
+		},
+		EventJson:    `{"orderId":"order-1"}`,
+		WouldPublish: []*paymentsv1.DryRunOutboxEvent{{Topic: "payments.payment_result", KafkaKey: "order-1", Payload: []byte("abc")}},
+	}, nil
+}
+
+func tokenOf(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// run executes paymentsctl with args against the fakes and returns stdout.
+func run(t *testing.T, orders *fakeOrdersAdmin, payments *fakePaymentsAdmin, args ...string) (string, error) {
+	t.Helper()
+	lis := map[string]*bufconn.Listener{}
+	for addr, register := range map[string]func(*grpc.Server){
+		"orders":   func(s *grpc.Server) { ordersv1.RegisterOrdersAdminServiceServer(s, orders) },
+		"payments": func(s *grpc.Server) { paymentsv1.RegisterPaymentsAdminServiceServer(s, payments) },
+	} {
+		l := bufconn.Listen(1 << 20)
+		srv := grpc.NewServer()
+		register(srv)
+		go srv.Serve(l)
+		t.Cleanup(srv.Stop)
+		lis[addr] = l
+	}
+
+	var out bytes.Buffer
+	c := newCLI(&out)
+	c.dial = func(addr string) (*grpc.ClientConn, error) {
+		l := lis[addr]
+		return grpc.NewClient("passthrough:///"+addr,
+			grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithChainUnaryInterceptor(auth.ForwardToken()))
+	}
+	root := newRootCmd(c)
+	root.SetArgs(append([]string{"--orders-addr", "orders", "--payments-addr", "payments", "--jwt-secret", "", "--operator", "alice"}, args...))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	err := root.ExecuteContext(context.Background())
+	return out.String(), err
+}
+
+func TestOrderSaga(t *testing.T) {
+	out, err := run(t, &fakeOrdersAdmin{}, &fakePaymentsAdmin{}, "order", "saga", "order-1")
+	if err != nil {
+		t.Fatalf("order saga: %v", err)
+	}
+	for _, want := range []string{"PARTIALLY_PAID", "INSTALLMENTS (2)", "pay-2", "RETRIES (1)", "2024-05-01T00:00:00Z", "FAIL_INTERNAL", "OUTBOX (1)", "payments.requested", "STATUS HISTORY (1)", "NEW"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	// protojson varies its whitespace between builds.
+	out, err = run(t, &fakeOrdersAdmin{}, &fakePaymentsAdmin{}, "-o", "json", "order", "get", "order-1")
+	if err != nil || !strings.Contains(strings.Join(strings.Fields(out), ""), `"orderId":"order-1"`) || strings.Contains(out, "INSTALLMENTS") {
+		t.Fatalf("order get -o json = (%s, %v)", out, err)
+	}
+}
+
+func TestForceStatus(t *testing.T) {
+	orders := &fakeOrdersAdmin{}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "order", "force-status", "order-1", "--status", "finished"); err == nil || orders.force != nil {
+		t.Fatalf("force-status without --reason = %v, call %v; want an error and no call", err, orders.force)
+	}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "order", "force-status", "order-1", "--status", "PAID", "--reason", "x"); err == nil {
+		t.Fatal("force-status accepted an unknown status")
+	}
+
+	out, err := run(t, orders, &fakePaymentsAdmin{}, "--jwt-secret", "s3cret", "order", "force-status", "order-1", "--status", "finished", "--reason", "settled by hand")
+	if err != nil {
+		t.Fatalf("force-status: %v", err)
+	}
+	if orders.force.GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_FINISHED || orders.force.GetReason() != "settled by hand" {
+		t.Fatalf("request = %v", orders.force)
+	}
+	if !strings.Contains(out, "PARTIALLY_PAID -> FINISHED") {
+		t.Fatalf("output = %q", out)
+	}
+	claims, err := auth.Verify(strings.TrimPrefix(orders.token, "Bearer "), []byte("s3cret"), time.Now())
+	if err != nil || claims.Subject != "alice" || !claims.HasAny([]auth.Role{auth.RoleAdmin}) {
+		t.Fatalf("token claims = (%+v, %v), want an admin token of alice", claims, err)
+	}
+}
+
+func TestDLQ(t *testing.T) {
+	orders := &fakeOrdersAdmin{}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "dlq", "requeue", "--all", "4"); err == nil {
+		t.Fatal("requeue accepted ids together with --all")
+	}
+	out, err := run(t, orders, &fakePaymentsAdmin{}, "dlq", "requeue", "4", "9")
+	if err != nil || len(orders.requeue.GetIds()) != 2 || !strings.Contains(out, "requeued 2 events") {
+		t.Fatalf("requeue = (%q, %v), request %v", out, err, orders.requeue)
+	}
+
+	payments := &fakePaymentsAdmin{}
+	for i := int64(1); i <= 5; i++ {
+		payments.dead = append(payments.dead, &paymentsv1.DeadOutboxEvent{Id: i, Topic: "accounts.created"})
+	}
+	out, err = run(t, orders, payments, "dlq", "list", "--service", "payments", "--limit", "3")
+	if err != nil {
+		t.Fatalf("dlq list: %v", err)
+	}
+	// Three events over two pages, then a hint where to continue.
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 5 || !strings.HasPrefix(lines[3], "3 ") || lines[4] != "more: --after 3" {
+		t.Fatalf("dlq list output:\n%s", out)
+	}
+}
+
+func TestOutboxList(t *testing.T) {
+	payments := &fakePaymentsAdmin{}
+	if _, err := run(t, &fakeOrdersAdmin{}, payments, "outbox", "list", "--service", "payments", "--state", "stuck"); err == nil || payments.listed != nil {
+		t.Fatal("outbox list accepted an unknown --state")
+	}
+	out, err := run(t, &fakeOrdersAdmin{}, payments, "outbox", "list", "--service", "payments", "--state", "failed", "--shard", "1", "--from", "2024-05-01T00:00:00Z")
+	if err != nil || !strings.Contains(out, "broker down") || !strings.Contains(out, "2024-05-01T00:00:05Z") {
+		t.Fatalf("outbox list = (%q, %v)", out, err)
+	}
+	req := payments.listed
+	if req.GetState() != paymentsv1.OutboxState_OUTBOX_STATE_FAILED || req.GetShard() != 1 || req.GetCreatedTo() != nil ||
+		!req.GetCreatedFrom().AsTime().Equal(time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("request = %v", req)
+	}
+}
+
+func TestOutboxReplay(t *testing.T) {
+	orders := &fakeOrdersAdmin{}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "outbox", "replay", "--from", "yesterday", "--to", "2024-05-01T06:00:00Z"); err == nil {
+		t.Fatal("replay accepted a malformed --from")
+	}
+	out, err := run(t, orders, &fakePaymentsAdmin{}, "outbox", "replay", "--from", "2024-05-01T00:00:00Z", "--to", "2024-05-01T06:00:00Z", "--dry-run")
+	if err != nil || !strings.Contains(out, "5 events match (dry run)") {
+		t.Fatalf("replay = (%q, %v)", out, err)
+	}
+	if !orders.replay.GetTo().AsTime().Equal(time.Date(2024, 5, 1, 6, 0, 0, 0, time.UTC)) || !orders.replay.GetDryRun() {
+		t.Fatalf("request = %v", orders.replay)
+	}
+}
+
+func TestAccountAdjust(t *testing.T) {
+	payments := &fakePaymentsAdmin{}
+	if _, err := run(t, &fakeOrdersAdmin{}, payments, "account", "adjust", "user-1", "--amount", "-500"); err == nil || payments.adjust != nil {
+		t.Fatal("adjust without --reason was sent")
+	}
+	if _, err := run(t, &fakeOrdersAdmin{}, payments, "account", "adjust", "user-1", "--amount", "-500", "--reason", "duplicate top-up"); err == nil || payments.adjust != nil {
+		t.Fatal("adjust without --request-id was sent")
+	}
+	out, err := run(t, &fakeOrdersAdmin{}, payments, "account", "adjust", "user-1", "--amount", "-500", "--reason", "duplicate top-up", "--request-id", "adj-1")
+	if err != nil || payments.adjust.GetAmount() != -500 || payments.adjust.GetRequestId() != "adj-1" || !strings.Contains(out, "balance 500 RUB (-500)") {
+		t.Fatalf("adjust = (%q, %v), request %v", out, err, payments.adjust)
+	}
+}
+
+func TestAccountRematerialize(t *testing.T) {
+	payments := &fakePaymentsAdmin{}
+	if _, err := run(t, &fakeOrdersAdmin{}, payments, "account", "rematerialize", "user-1"); err == nil || payments.remat != nil {
+		t.Fatal("rematerialize without --reason was sent")
+	}
+	out, err := run(t, &fakeOrdersAdmin{}, payments, "account", "rematerialize", "user-1", "--reason", "anomaly")
+	if err != nil || payments.remat.GetUserId() != "user-1" || !strings.Contains(out, "balance 900 RUB (was 1000)") || !strings.Contains(out, "resolved anomalies") {
+		t.Fatalf("rematerialize = (%q, %v), request %v", out, err, payments.remat)
+	}
+}
+
+func TestDisputeResolve(t *testing.T) {
+	payments := &fakePaymentsAdmin{}
+	if _, err := run(t, &fakeOrdersAdmin{}, payments, "dispute", "resolve", "order-1", "--resolution", "keep", "--reason", "r"); err == nil || payments.resolve != nil {
+		t.Fatal("resolve with an unknown --resolution was sent")
+	}
+	out, err := run(t, &fakeOrdersAdmin{}, payments, "dispute", "resolve", "order-1", "--resolution", "refund", "--reason", "merchant agreed")
+	if err != nil || payments.resolve.GetResolution() != paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_REFUND {
+		t.Fatalf("resolve = (%q, %v), request %v", out, err, payments.resolve)
+	}
+	for _, want := range []string{"REFUNDED", "merchant agreed", "balance 1310 RUB"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInboxDryRun(t *testing.T) {
+	payments := &fakePaymentsAdmin{}
+	out, err := run(t, &fakeOrdersAdmin{}, payments, "inbox", "dry-run", "--service", "payments", "6f1c3c43-6a55-4c5e-9b0c-8f8a3f0c1a01")
+	if err != nil {
+		t.Fatalf("inbox dry-run: %v", err)
+	}
+	if payments.dryRun.GetConsumer() != "payment_requested" {
+		t.Fatalf("request = %v, want the payment_requested consumer", payments.dryRun)
+	}
+	for _, want := range []string{"orders.payment_requested[1]@42", "processed    -", "applied (rolled back)", "WOULD PUBLISH (1)", "payments.payment_result  order-1  3"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestPromo(t *testing.T) {
+	orders := &fakeOrdersAdmin{}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "promo", "create", "spring10", "--percent", "10", "--amount", "100"); err == nil || orders.promo != nil {
+		t.Fatal("promo create accepted both --percent and --amount")
+	}
+	out, err := run(t, orders, &fakePaymentsAdmin{}, "promo", "create", "spring10", "--percent", "10", "--per-user", "1", "--expires", "2024-06-01T00:00:00Z")
+	if err != nil {
+		t.Fatalf("promo create: %v", err)
+	}
+	if orders.promo.GetPercentOff() != 10 || orders.promo.GetMaxRedemptionsPerUser() != 1 || !orders.promo.GetExpiresAt().AsTime().Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("request = %v", orders.promo)
+	}
+	for _, want := range []string{"SPRING10", "10%", "0 of unlimited, 1 per user", "- - 2024-06-01T00:00:00Z"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run(t, orders, &fakePaymentsAdmin{}, "promo", "get", "FIXED")
+	if err != nil || !strings.Contains(out, "7 (2 released by cancellations)") || !strings.Contains(out, "3500 off 21000") {
+		t.Fatalf("promo get = (%q, %v)", out, err)
+	}
+}
+
+func TestProjectionReplay(t *testing.T) {
+	orders := &fakeOrdersAdmin{}
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "projection", "replay", "order_status_history"); err == nil || orders.started != nil {
+		t.Fatal("projection replay accepted no --reason")
+	}
+	out, err := run(t, orders, &fakePaymentsAdmin{}, "projection", "replay", "order_status_history", "--rate", "1000", "--reason", "status bug")
+	if err != nil {
+		t.Fatalf("projection replay: %v", err)
+	}
+	if orders.started.GetRateLimit() != 1000 || orders.started.GetReason() != "status bug" || !strings.Contains(out, "RUNNING") || !strings.Contains(out, "1000/s") {
+		t.Fatalf("request = %v, output:\n%s", orders.started, out)
+	}
+
+	if _, err := run(t, orders, &fakePaymentsAdmin{}, "projection", "status", "three"); err == nil {
+		t.Fatal("projection status accepted a non-numeric id")
+	}
+	out, err = run(t, orders, &fakePaymentsAdmin{}, "projection", "status", "3")
+	if err != nil || !strings.Contains(out, "40 (60 remaining)") || !strings.Contains(out, "PARTITION") {
+		t.Fatalf("projection status = (%q, %v)", out, err)
+	}
+}
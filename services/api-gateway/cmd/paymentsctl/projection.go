@@ -0,0 +1,152 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+const projectionReplayStatusPrefix = "PROJECTION_REPLAY_STATUS_"
+
+func newProjectionCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "projection", Short: "Rebuild projections of orders from their topics"}
+	cmd.AddCommand(newProjectionReplayCmd(c), newProjectionStatusCmd(c), newProjectionStopCmd(c))
+	return cmd
+}
+
+func newProjectionReplayCmd(c *cli) *cobra.Command {
+	var (
+		rate   int32
+		reason string
+	)
+	cmd := &cobra.Command{
+		Use:   "replay <projection>",
+		Short: "Empty a projection and rebuild it from the earliest offset of its topic (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if rate < 0 {
+				return errors.New("--rate must not be negative")
+			}
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: args[0], RateLimit: rate, Reason: reason})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) { writeProjectionReplay(w, resp.GetReplay()) })
+		},
+	}
+	cmd.Flags().Int32Var(&rate, "rate", 0, "messages per second; 0 is unlimited")
+	cmd.Flags().StringVar(&reason, "reason", "", "why the projection is rebuilt; stored in the audit log (required)")
+	return cmd
+}
+
+func newProjectionStatusCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "status <replay-id>",
+		Short: "Show a replay with its progress per partition",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseReplayID(args[0])
+			if err != nil {
+				return err
+			}
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.GetProjectionReplay(ctx, &ordersv1.GetProjectionReplayRequest{ReplayId: id})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) { writeProjectionReplay(w, resp.GetReplay()) })
+		},
+	}
+}
+
+func newProjectionStopCmd(c *cli) *cobra.Command {
+	var reason string
+	cmd := &cobra.Command{
+		Use:   "stop <replay-id>",
+		Short: "Stop a running replay, leaving the projection partly rebuilt (audited)",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id, err := parseReplayID(args[0])
+			if err != nil {
+				return err
+			}
+			if strings.TrimSpace(reason) == "" {
+				return errors.New("--reason is required")
+			}
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.StopProjectionReplay(ctx, &ordersv1.StopProjectionReplayRequest{ReplayId: id, Reason: reason})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) { writeProjectionReplay(w, resp.GetReplay()) })
+		},
+	}
+	cmd.Flags().StringVar(&reason, "reason", "", "why the replay is stopped; stored in the audit log (required)")
+	return cmd
+}
+
+func parseReplayID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("replay id %q: want a positive integer", s)
+	}
+	return id, nil
+}
+
+func writeProjectionReplay(w *tabwriter.Writer, r *ordersv1.ProjectionReplay) {
+	fmt.Fprintf(w, "replay\t%d\n", r.GetId())
+	fmt.Fprintf(w, "projection\t%s (topic %s)\n", r.GetProjection(), r.GetTopic())
+	fmt.Fprintf(w, "status\t%s\n", enumName(r.GetStatus(), projectionReplayStatusPrefix))
+	rate := "unlimited"
+	if r.GetRateLimit() > 0 {
+		rate = fmt.Sprintf("%d/s", r.GetRateLimit())
+	}
+	fmt.Fprintf(w, "rate\t%s\n", rate)
+	fmt.Fprintf(w, "applied\t%d (%d remaining)\n", r.GetApplied(), r.GetRemaining())
+	if e := r.GetError(); e != "" {
+		fmt.Fprintf(w, "error\t%s\n", e)
+	}
+	fmt.Fprintf(w, "requested\t%s by %s: %s\n", formatTime(r.GetCreatedAt()), r.GetRequestedBy(), r.GetReason())
+	fmt.Fprintf(w, "heartbeat\t%s\n", formatTime(r.GetHeartbeatAt()))
+	fmt.Fprintf(w, "finished\t%s\n", formatTime(r.GetFinishedAt()))
+	if len(r.GetPartitions()) > 0 {
+		fmt.Fprintln(w, "\nPARTITION\tSTART\tNEXT\tEND")
+	}
+	for _, p := range r.GetPartitions() {
+		fmt.Fprintf(w, "%d\t%d\t%d\t%d\n", p.GetPartition(), p.GetStartOffset(), p.GetNextOffset(), p.GetEndOffset())
+	}
+}
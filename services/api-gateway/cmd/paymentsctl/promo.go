@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+func newPromoCmd(c *cli) *cobra.Command {
+	cmd := &cobra.Command{Use: "promo", Short: "Create promo codes and report on their redemptions"}
+	cmd.AddCommand(newPromoCreateCmd(c), newPromoGetCmd(c))
+	return cmd
+}
+
+func newPromoCreateCmd(c *cli) *cobra.Command {
+	var (
+		p               ordersv1.PromoCode
+		starts, expires string
+	)
+	cmd := &cobra.Command{
+		Use:   "create <code>",
+		Short: "Create a promo code with a --percent or --amount discount",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (p.PercentOff == 0) == (p.AmountOff == 0) {
+				return errors.New("exactly one of --percent and --amount is required")
+			}
+			var err error
+			if p.StartsAt, err = parseTimeFlag("--starts", starts); err != nil {
+				return err
+			}
+			if p.ExpiresAt, err = parseTimeFlag("--expires", expires); err != nil {
+				return err
+			}
+			p.Code = args[0]
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.CreatePromoCode(ctx, &ordersv1.CreatePromoCodeRequest{PromoCode: &p})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) { writePromoCode(w, resp.GetPromoCode()) })
+		},
+	}
+	f := cmd.Flags()
+	f.Int32Var(&p.PercentOff, "percent", 0, "discount in percent of the order amount, 1-99")
+	f.Int64Var(&p.AmountOff, "amount", 0, "fixed discount in minimal currency units")
+	f.Int32Var(&p.MaxRedemptions, "max-redemptions", 0, "how many orders may use the code; 0 is unlimited")
+	f.Int32Var(&p.MaxRedemptionsPerUser, "per-user", 0, "how many orders of one user may use the code; 0 is unlimited")
+	f.StringVar(&starts, "starts", "", "accept the code from this time on, RFC 3339")
+	f.StringVar(&expires, "expires", "", "stop accepting the code at this time, RFC 3339")
+	return cmd
+}
+
+func newPromoGetCmd(c *cli) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get <code>",
+		Short: "Show a promo code with its redemptions and discounts given",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			client, err := c.orders()
+			if err != nil {
+				return err
+			}
+			ctx, cancel, err := c.context(cmd.Context())
+			if err != nil {
+				return err
+			}
+			defer cancel()
+			resp, err := client.GetPromoCode(ctx, &ordersv1.GetPromoCodeRequest{Code: args[0]})
+			if err != nil {
+				return err
+			}
+			return c.print(resp, func(w *tabwriter.Writer) {
+				writePromoCode(w, resp.GetPromoCode())
+				fmt.Fprintf(w, "redemptions\t%d (%d released by cancellations)\n", resp.GetRedemptions(), resp.GetReleased())
+				fmt.Fprintf(w, "discounts\t%d off %d\n", resp.GetDiscountTotal(), resp.GetAmountTotal())
+			})
+		},
+	}
+}
+
+func writePromoCode(w *tabwriter.Writer, p *ordersv1.PromoCode) {
+	fmt.Fprintf(w, "code\t%s\n", p.GetCode())
+	if p.GetPercentOff() != 0 {
+		fmt.Fprintf(w, "discount\t%d%%\n", p.GetPercentOff())
+	} else {
+		fmt.Fprintf(w, "discount\t%d\n", p.GetAmountOff())
+	}
+	fmt.Fprintf(w, "used\t%d of %s, %s per user\n", p.GetRedeemedCount(), limit(p.GetMaxRedemptions()), limit(p.GetMaxRedemptionsPerUser()))
+	fmt.Fprintf(w, "valid\t%s - %s\n", formatTime(p.GetStartsAt()), formatTime(p.GetExpiresAt()))
+	fmt.Fprintf(w, "created\t%s\n", formatTime(p.GetCreatedAt()))
+}
+
+// limit prints a redemption limit, where 0 is unlimited.
+func limit(n int32) string {
+	if n == 0 {
+		return "unlimited"
+	}
+	return fmt.Sprint(n)
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+// client calls the public REST API of api-gateway.
+type client struct {
+	http    *http.Client
+	baseURL string
+	// keys signs POSTs when the gateway requires X-Signature; may be nil.
+	keys *signature.Keyring
+	// jwtSecret signs a short-lived user token for each request; the gateway
+	// checks it against X-User-Id. May be nil.
+	jwtSecret []byte
+}
+
+// userTokenTTL bounds the token of one request.
+const userTokenTTL = time.Minute
+
+// apiError is a non-2xx answer of the gateway.
+type apiError struct {
+	status int
+	code   string
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.status, e.code, e.msg)
+}
+
+// do sends body as JSON on behalf of userID and decodes the answer into out.
+// Every POST gets a fresh Idempotency-Key.
+func (c *client) do(ctx context.Context, method, path, userID string, body, out any) error {
+	var raw []byte
+	if body != nil {
+		var err error
+		if raw, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimSuffix(c.baseURL, "/")+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-Id", userID)
+	if c.jwtSecret != nil {
+		token, err := jwt.Sign(jwt.Claims{Subject: userID, Roles: []jwt.Role{jwt.RoleUser}, ExpiresAt: time.Now().Add(userTokenTTL).Unix()}, c.jwtSecret)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if method == http.MethodPost {
+		req.Header.Set("Idempotency-Key", uuid.NewString())
+		if c.keys != nil {
+			req.Header.Set("X-Signature", c.keys.Sign(signature.RequestPayload(method, path, raw), time.Now()))
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s %s: read response: %w", method, path, err)
+	}
+	if resp.StatusCode >= 300 {
+		apiErr := &apiError{status: resp.StatusCode, msg: strings.TrimSpace(string(data))}
+		var e gateway.ErrorResponse
+		if json.Unmarshal(data, &e) == nil && e.Error != "" {
+			apiErr.msg = e.Error
+			if e.Code != nil {
+				apiErr.code = *e.Code
+			}
+		}
+		return fmt.Errorf("%s %s: %w", method, path, apiErr)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("%s %s: decode response: %w", method, path, err)
+	}
+	return nil
+}
+
+func (c *client) createAccount(ctx context.Context, userID string) error {
+	return c.do(ctx, http.MethodPost, "/payments/account", userID, gateway.CreateAccountRequest{}, nil)
+}
+
+func (c *client) topUp(ctx context.Context, userID string, amount int64) error {
+	return c.do(ctx, http.MethodPost, "/payments/account/topup", userID, gateway.TopUpAccountRequest{Amount: gateway.MoneyAmount(amount)}, nil)
+}
+
+func (c *client) createOrder(ctx context.Context, userID string, o orderPlan) (string, error) {
+	req := gateway.CreateOrderRequest{
+		Amount:            gateway.MoneyAmount(o.amount),
+		Description:       o.description,
+		PayInInstallments: &o.installments,
+		Tags:              &[]string{"demo"},
+	}
+	var resp gateway.CreateOrderResponse
+	if err := c.do(ctx, http.MethodPost, "/orders", userID, req, &resp); err != nil {
+		return "", err
+	}
+	return resp.Order.OrderId, nil
+}
+
+func (c *client) payOrder(ctx context.Context, userID, orderID string, amount int64) error {
+	return c.do(ctx, http.MethodPost, "/orders/"+orderID+"/payments", userID, gateway.PayOrderRequest{Amount: gateway.MoneyAmount(amount)}, nil)
+}
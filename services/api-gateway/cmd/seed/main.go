@@ -0,0 +1,190 @@
+// Command seed fills a running deployment with demo data through the public
+// REST API of api-gateway: accounts with balances and orders that end up in
+// every status, for demos and as a starting point for load tests.
+//
+//	go run ./cmd/seed -url http://localhost:5050/api/v1 -accounts 50 -orders 500
+//
+// Orders meant to be FINISHED are covered by the top-up of their account,
+// CANCELLED ones ask for more than the account ever holds, NEW and
+// PARTIALLY_PAID ones are paid in installments (half of a PARTIALLY_PAID
+// order is paid right away). Payments run asynchronously, so the final
+// statuses appear once payments-service has processed them.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+func main() {
+	var (
+		baseURL     = flag.String("url", "http://localhost:5050/api/v1", "base URL of api-gateway")
+		accounts    = flag.Int("accounts", 20, "number of demo accounts")
+		orders      = flag.Int("orders", 200, "number of demo orders, spread over the accounts at random")
+		concurrency = flag.Int("concurrency", 8, "requests in flight")
+		balance     = flag.String("balance", "1000-100000", "spare balance per account left after its orders, min-max")
+		amount      = flag.String("amount", "100-5000", "order amount, min-max")
+		statuses    = flag.String("statuses", "finished=6,cancelled=2,partially_paid=1,new=1", "weights of the order statuses to end up in")
+		prefix      = flag.String("prefix", "", "user id prefix (default: demo-<random>, new users every run)")
+		seed        = flag.Uint64("seed", 0, "random seed for amounts and statuses (default: random)")
+		signingKeys = flag.String("signing-keys", os.Getenv("GATEWAY_SIGNING_KEYS"), "id:secret to sign requests with, when the gateway requires X-Signature")
+		jwtSecret   = flag.String("jwt-secret", os.Getenv("JWT_SECRET"), "sign a user token for every demo user with the gateway's JWT_SECRET")
+		timeout     = flag.Duration("timeout", 10*time.Second, "timeout of a single request")
+	)
+	flag.Parse()
+
+	cfg, err := buildConfig(*accounts, *orders, *balance, *amount, *statuses, *prefix)
+	if err == nil && *concurrency < 1 {
+		err = errors.New("concurrency must be >= 1")
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "seed:", err)
+		os.Exit(2)
+	}
+
+	c := &client{http: &http.Client{Timeout: *timeout}, baseURL: *baseURL}
+	if *jwtSecret != "" {
+		c.jwtSecret = []byte(*jwtSecret)
+	}
+	if *signingKeys != "" {
+		if c.keys, err = signature.ParseKeyring(*signingKeys); err != nil {
+			fmt.Fprintln(os.Stderr, "seed:", err)
+			os.Exit(2)
+		}
+	}
+
+	if *seed == 0 {
+		*seed = rand.Uint64()
+	}
+	p := newPlan(cfg, rand.New(rand.NewPCG(*seed, *seed)))
+	slog.Info("seeding", "url", *baseURL, "prefix", cfg.prefix, "seed", *seed, "accounts", len(p.accounts), "orders", p.counts())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	start := time.Now()
+	res := run(ctx, c, p, *concurrency)
+	slog.Info("seeding finished",
+		"accounts", res.accounts, "orders", res.orders, "installments", res.installments,
+		"failed", res.failed, "duration", time.Since(start).Round(time.Millisecond))
+	if res.failed > 0 {
+		os.Exit(1)
+	}
+}
+
+func buildConfig(accounts, orders int, balance, amount, statuses, prefix string) (planConfig, error) {
+	cfg := planConfig{prefix: prefix, accounts: accounts, orders: orders}
+	if accounts < 1 || orders < 0 {
+		return cfg, errors.New("want at least one account and a non-negative number of orders")
+	}
+	var err error
+	if cfg.balance, err = parseSpan(balance); err != nil {
+		return cfg, err
+	}
+	if cfg.amount, err = parseSpan(amount); err != nil {
+		return cfg, err
+	}
+	if cfg.weights, err = parseWeights(statuses); err != nil {
+		return cfg, err
+	}
+	if cfg.prefix == "" {
+		cfg.prefix = "demo-" + uuid.NewString()[:8]
+	}
+	return cfg, nil
+}
+
+// result counts what was created; a failed step is logged and counted, and
+// the rest of that account or order is skipped.
+type result struct {
+	accounts, orders, installments, failed int64
+}
+
+func run(ctx context.Context, c *client, p plan, concurrency int) result {
+	var accounts, orders, installments, failed atomic.Int64
+	fail := func(msg, userID string, err error) {
+		failed.Add(1)
+		slog.Error(msg, "user_id", userID, "err", err)
+	}
+
+	// Orders need the accounts, so all accounts are set up first.
+	ready := make([]bool, len(p.accounts))
+	forEach(ctx, len(p.accounts), concurrency, func(i int) {
+		a := p.accounts[i]
+		if err := c.createAccount(ctx, a.userID); err != nil {
+			fail("create account failed", a.userID, err)
+			return
+		}
+		if a.topUp > 0 {
+			if err := c.topUp(ctx, a.userID, a.topUp); err != nil {
+				fail("top up failed", a.userID, err)
+				return
+			}
+		}
+		ready[i] = true
+		accounts.Add(1)
+	})
+
+	forEach(ctx, len(p.orders), concurrency, func(i int) {
+		o := p.orders[i]
+		userID := p.accounts[o.account].userID
+		if !ready[o.account] {
+			return
+		}
+		orderID, err := c.createOrder(ctx, userID, o)
+		if err != nil {
+			fail("create order failed", userID, err)
+			return
+		}
+		orders.Add(1)
+		if o.pay > 0 {
+			if err := c.payOrder(ctx, userID, orderID, o.pay); err != nil {
+				fail("pay order failed", userID, err)
+				return
+			}
+			installments.Add(1)
+		}
+	})
+
+	return result{accounts: accounts.Load(), orders: orders.Load(), installments: installments.Load(), failed: failed.Load()}
+}
+
+// forEach calls fn for 0..n-1 from concurrency goroutines and stops handing
+// out work once ctx is done.
+func forEach(ctx context.Context, n, concurrency int, fn func(i int)) {
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, n) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				fn(i)
+			}
+		}()
+	}
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+}
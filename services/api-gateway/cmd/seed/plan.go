@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"sort"
+	"strconv"
+	"strings"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+// span is an inclusive range of amounts in minimal currency units.
+type span struct {
+	min, max int64
+}
+
+// parseSpan parses "min-max" or a single amount.
+func parseSpan(s string) (span, error) {
+	lo, hi, ok := strings.Cut(strings.TrimSpace(s), "-")
+	if !ok {
+		hi = lo
+	}
+	min, err := strconv.ParseInt(strings.TrimSpace(lo), 10, 64)
+	if err != nil {
+		return span{}, fmt.Errorf("range %q: %w", s, err)
+	}
+	max, err := strconv.ParseInt(strings.TrimSpace(hi), 10, 64)
+	if err != nil {
+		return span{}, fmt.Errorf("range %q: %w", s, err)
+	}
+	if min < 0 || max < min {
+		return span{}, fmt.Errorf("range %q: want 0 <= min <= max", s)
+	}
+	return span{min: min, max: max}, nil
+}
+
+func (s span) pick(rng *rand.Rand) int64 {
+	return s.min + rng.Int64N(s.max-s.min+1)
+}
+
+// statusWeight is the share of orders meant to end up in a status.
+type statusWeight struct {
+	status gateway.OrderStatus
+	weight int
+}
+
+// parseWeights parses "finished=6,cancelled=2,..." into weights of order
+// statuses; names are case-insensitive and zero weights are dropped.
+func parseWeights(s string) ([]statusWeight, error) {
+	known := map[string]gateway.OrderStatus{}
+	for _, st := range []gateway.OrderStatus{gateway.OrderStatusNEW, gateway.OrderStatusPARTIALLYPAID, gateway.OrderStatusFINISHED, gateway.OrderStatusCANCELLED} {
+		known[string(st)] = st
+	}
+
+	var weights []statusWeight
+	seen := map[gateway.OrderStatus]bool{}
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("status weight %q: want status=weight", part)
+		}
+		st, ok := known[strings.ToUpper(strings.TrimSpace(name))]
+		if !ok {
+			return nil, fmt.Errorf("status weight %q: unknown status", part)
+		}
+		if seen[st] {
+			return nil, fmt.Errorf("status %s given twice", st)
+		}
+		seen[st] = true
+		w, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || w < 0 {
+			return nil, fmt.Errorf("status weight %q: want a non-negative integer", part)
+		}
+		if w > 0 {
+			weights = append(weights, statusWeight{status: st, weight: w})
+		}
+	}
+	if len(weights) == 0 {
+		return nil, fmt.Errorf("status weights %q: nothing to create", s)
+	}
+	return weights, nil
+}
+
+func pickStatus(weights []statusWeight, rng *rand.Rand) gateway.OrderStatus {
+	total := 0
+	for _, w := range weights {
+		total += w.weight
+	}
+	n := rng.IntN(total)
+	for _, w := range weights {
+		if n < w.weight {
+			return w.status
+		}
+		n -= w.weight
+	}
+	return weights[len(weights)-1].status
+}
+
+type accountPlan struct {
+	userID string
+	// topUp is what the account is topped up with: the payments of its
+	// orders that should succeed plus the spare balance.
+	topUp int64
+}
+
+type orderPlan struct {
+	account     int
+	amount      int64
+	description string
+	// status is the status the order is meant to end up in.
+	status gateway.OrderStatus
+	// installments orders are not paid on creation.
+	installments bool
+	// pay is the installment paid right after creation, 0 for none.
+	pay int64
+}
+
+type plan struct {
+	accounts []accountPlan
+	orders   []orderPlan
+}
+
+type planConfig struct {
+	prefix   string
+	accounts int
+	orders   int
+	balance  span
+	amount   span
+	weights  []statusWeight
+}
+
+// newPlan decides everything up front, so the outcome of every order does
+// not depend on the order the requests run in: accounts get enough for the
+// orders meant to be paid, and orders meant to be cancelled ask for more than
+// the account ever holds.
+func newPlan(cfg planConfig, rng *rand.Rand) plan {
+	p := plan{accounts: make([]accountPlan, cfg.accounts), orders: make([]orderPlan, cfg.orders)}
+	for i := range p.accounts {
+		p.accounts[i] = accountPlan{userID: fmt.Sprintf("%s-%d", cfg.prefix, i+1), topUp: cfg.balance.pick(rng)}
+	}
+
+	for i := range p.orders {
+		o := orderPlan{
+			account:     rng.IntN(cfg.accounts),
+			amount:      max(cfg.amount.pick(rng), 1),
+			description: fmt.Sprintf("demo order %s #%d", cfg.prefix, i+1),
+			status:      pickStatus(cfg.weights, rng),
+		}
+		switch o.status {
+		case gateway.OrderStatusFINISHED:
+			p.accounts[o.account].topUp += o.amount
+		case gateway.OrderStatusPARTIALLYPAID:
+			o.amount = max(o.amount, 2)
+			o.installments = true
+			o.pay = o.amount / 2
+			p.accounts[o.account].topUp += o.pay
+		case gateway.OrderStatusNEW:
+			o.installments = true
+		}
+		p.orders[i] = o
+	}
+
+	// Cancelled orders are sized once the top-ups are known.
+	for i := range p.orders {
+		if o := &p.orders[i]; o.status == gateway.OrderStatusCANCELLED {
+			o.amount += p.accounts[o.account].topUp
+		}
+	}
+	return p
+}
+
+// counts returns the number of planned orders per status, sorted by status.
+func (p plan) counts() string {
+	n := map[gateway.OrderStatus]int{}
+	for _, o := range p.orders {
+		n[o.status]++
+	}
+	parts := make([]string, 0, len(n))
+	for st, c := range n {
+		parts = append(parts, fmt.Sprintf("%s=%d", st, c))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/testsupport"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+func TestParseWeights(t *testing.T) {
+	got, err := parseWeights("Finished=3, partially_paid=1,new=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0] != (statusWeight{gateway.OrderStatusFINISHED, 3}) || got[1] != (statusWeight{gateway.OrderStatusPARTIALLYPAID, 1}) {
+		t.Fatalf("parseWeights() = %v", got)
+	}
+
+	for _, bad := range []string{"", "new=0", "paid=1", "new", "new=-1", "new=1,NEW=2"} {
+		if _, err := parseWeights(bad); err == nil {
+			t.Errorf("parseWeights(%q) succeeded, want an error", bad)
+		}
+	}
+}
+
+func TestParseSpan(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    span
+		wantErr bool
+	}{
+		{"100-500", span{100, 500}, false},
+		{"42", span{42, 42}, false},
+		{"500-100", span{}, true},
+		{"-5", span{}, true},
+		{"a-b", span{}, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSpan(tt.in)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseSpan(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestPlanOutcomes(t *testing.T) {
+	weights, _ := parseWeights("finished=1,cancelled=1,partially_paid=1,new=1")
+	p := newPlan(planConfig{prefix: "t", accounts: 5, orders: 400, balance: span{0, 1000}, amount: span{1, 5000}, weights: weights}, rand.New(rand.NewPCG(1, 2)))
+
+	// Whatever order the payments run in, the paid ones fit the top-up and a
+	// cancelled one never does.
+	paid := make([]int64, len(p.accounts))
+	for _, o := range p.orders {
+		switch o.status {
+		case gateway.OrderStatusFINISHED:
+			paid[o.account] += o.amount
+		case gateway.OrderStatusPARTIALLYPAID:
+			if !o.installments || o.pay <= 0 || o.pay >= o.amount {
+				t.Fatalf("partially paid order %+v", o)
+			}
+			paid[o.account] += o.pay
+		case gateway.OrderStatusNEW:
+			if !o.installments || o.pay != 0 {
+				t.Fatalf("new order %+v", o)
+			}
+		}
+	}
+	for i, a := range p.accounts {
+		if spare := a.topUp - paid[i]; spare < 0 || spare > 1000 {
+			t.Fatalf("account %d: top-up %d for payments %d", i, a.topUp, paid[i])
+		}
+	}
+	for _, o := range p.orders {
+		if o.status == gateway.OrderStatusCANCELLED && o.amount <= p.accounts[o.account].topUp {
+			t.Fatalf("cancelled order %+v fits the top-up %d", o, p.accounts[o.account].topUp)
+		}
+	}
+}
+
+func TestRun(t *testing.T) {
+	b := testsupport.Start(t)
+	h := handler.New(b.OrdersClient, b.PaymentsClient, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	srv := httptest.NewServer(gateway.HandlerWithOptions(h, gateway.ChiServerOptions{BaseURL: "/api/v1", BaseRouter: chi.NewRouter()}))
+	defer srv.Close()
+
+	weights, _ := parseWeights("finished=1,partially_paid=1")
+	p := newPlan(planConfig{prefix: "t", accounts: 3, orders: 20, balance: span{10, 10}, amount: span{10, 100}, weights: weights}, rand.New(rand.NewPCG(1, 2)))
+	c := &client{http: &http.Client{Timeout: time.Second}, baseURL: srv.URL + "/api/v1"}
+
+	res := run(context.Background(), c, p, 4)
+	installments := 0
+	for _, o := range p.orders {
+		if o.pay > 0 {
+			installments++
+		}
+	}
+	if res != (result{accounts: 3, orders: 20, installments: int64(installments)}) {
+		t.Fatalf("run() = %+v", res)
+	}
+	for _, a := range p.accounts {
+		if got := b.Payments.Balance(a.userID); got != a.topUp {
+			t.Fatalf("balance of %s = %d, want %d", a.userID, got, a.topUp)
+		}
+	}
+	if got := b.Orders.Calls("PayOrder"); got != installments {
+		t.Fatalf("PayOrder calls = %d, want %d", got, installments)
+	}
+}
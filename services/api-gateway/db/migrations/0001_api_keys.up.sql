@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS gateway_api_keys (
+    id uuid PRIMARY KEY,
+    name text NOT NULL,
+    key_hash text NOT NULL UNIQUE,
+    scopes text[] NOT NULL,
+    rate_limit_per_minute int NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    revoked_at timestamptz
+    );
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS gateway_audit_log (
+    id bigserial PRIMARY KEY,
+    occurred_at timestamptz NOT NULL,
+    method text NOT NULL,
+    path text NOT NULL,
+    user_id text NOT NULL DEFAULT '',
+    idempotency_key text NOT NULL DEFAULT '',
+    status int NOT NULL,
+    latency_ms bigint NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS gateway_audit_log_user_idx ON gateway_audit_log (user_id, occurred_at DESC);
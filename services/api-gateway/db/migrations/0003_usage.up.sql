@@ -0,0 +1,15 @@
+-- Monthly quotas per API key; 0 is unlimited.
+ALTER TABLE gateway_api_keys ADD COLUMN IF NOT EXISTS monthly_call_quota bigint NOT NULL DEFAULT 0;
+ALTER TABLE gateway_api_keys ADD COLUMN IF NOT EXISTS monthly_volume_quota bigint NOT NULL DEFAULT 0;
+
+-- Usage per key and month ("2006-01", UTC), flushed from the Redis counters.
+CREATE TABLE IF NOT EXISTS gateway_usage (
+    key_id uuid NOT NULL REFERENCES gateway_api_keys (id),
+    period text NOT NULL,
+    calls bigint NOT NULL DEFAULT 0,
+    volume bigint NOT NULL DEFAULT 0,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (key_id, period)
+    );
+
+CREATE INDEX IF NOT EXISTS gateway_usage_period_idx ON gateway_usage (period);
@@ -0,0 +1,3 @@
+-- user_id of rejected calls is the unchecked X-User-Id header; rows written
+-- before this column existed cannot be told apart and stay unverified.
+ALTER TABLE gateway_audit_log ADD COLUMN IF NOT EXISTS user_verified boolean NOT NULL DEFAULT false;
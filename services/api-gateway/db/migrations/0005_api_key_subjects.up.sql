@@ -0,0 +1,3 @@
+-- User ids an API key may act for in X-User-Id. Keys issued before this
+-- column existed have none and are refused until they are reissued.
+ALTER TABLE gateway_api_keys ADD COLUMN IF NOT EXISTS subjects text[] NOT NULL DEFAULT '{}';
@@ -0,0 +1,168 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeStore struct {
+	keys    map[string]Key
+	lookups int
+}
+
+func (s *fakeStore) Create(_ context.Context, key Key, hash string) (Key, error) {
+	s.keys[hash] = key
+	return key, nil
+}
+
+func (s *fakeStore) Lookup(_ context.Context, hash string) (Key, error) {
+	s.lookups++
+	k, ok := s.keys[hash]
+	if !ok {
+		return Key{}, ErrNotFound
+	}
+	return k, nil
+}
+
+func (s *fakeStore) Revoke(_ context.Context, id string) error {
+	for hash, k := range s.keys {
+		if k.ID == id {
+			now := time.Now()
+			k.RevokedAt = &now
+			s.keys[hash] = k
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+func (s *fakeStore) SetQuota(_ context.Context, id string, calls, volume *int64) (Key, error) {
+	for hash, k := range s.keys {
+		if k.ID == id && k.RevokedAt == nil {
+			if calls != nil {
+				k.MonthlyCallQuota = *calls
+			}
+			if volume != nil {
+				k.MonthlyVolumeQuota = *volume
+			}
+			s.keys[hash] = k
+			return k, nil
+		}
+	}
+	return Key{}, ErrNotFound
+}
+
+func TestNewSecret(t *testing.T) {
+	secret, hash, err := NewSecret()
+	if err != nil {
+		t.Fatalf("NewSecret() error: %v", err)
+	}
+	if !strings.HasPrefix(secret, "gk_") || hash != Hash(secret) || strings.Contains(hash, secret) {
+		t.Fatalf("NewSecret() = (%q, %q), want gk_ secret and its sha256", secret, hash)
+	}
+	other, _, _ := NewSecret()
+	if other == secret {
+		t.Fatal("NewSecret() returned the same secret twice")
+	}
+}
+
+func TestRequiredScope(t *testing.T) {
+	tests := []struct {
+		method, path, want string
+	}{
+		{http.MethodGet, "/orders", ScopeOrdersRead},
+		{http.MethodGet, "/orders/123", ScopeOrdersRead},
+		{http.MethodPost, "/orders", ScopeOrdersWrite},
+		{http.MethodPost, "/orders/123/payments", ScopeOrdersWrite},
+		{http.MethodGet, "/payments/account/balance", ScopePaymentsRead},
+		{http.MethodPost, "/payments/account/topup", ScopePaymentsWrite},
+		{http.MethodGet, "/rates", ScopePaymentsRead},
+		{http.MethodGet, "/ordersx", ""},
+		{http.MethodPost, "/admin/api-keys", ""},
+	}
+	for _, tt := range tests {
+		if got := RequiredScope(tt.method, tt.path); got != tt.want {
+			t.Fatalf("RequiredScope(%s, %s) = %q, want %q", tt.method, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	now := time.Now()
+	l := newLimiter()
+	l.now = func() time.Time { return now }
+	for i := 0; i < 2; i++ {
+		if !l.allow("k", 2) {
+			t.Fatalf("allow() #%d = false within burst", i+1)
+		}
+	}
+	if l.allow("k", 2) {
+		t.Fatal("allow() = true after burst exhausted")
+	}
+	if !l.allow("other", 2) {
+		t.Fatal("allow() for another key = false, want separate bucket")
+	}
+	now = now.Add(30 * time.Second)
+	if !l.allow("k", 2) {
+		t.Fatal("allow() = false after refill")
+	}
+}
+
+func TestAuthenticatorAuthorize(t *testing.T) {
+	store := &fakeStore{keys: map[string]Key{}}
+	secret, hash, _ := NewSecret()
+	store.keys[hash] = Key{ID: "k-1", Scopes: []string{ScopeOrdersRead}, RateLimitPerMinute: 1}
+	a := NewAuthenticator(store, time.Minute)
+	ctx := context.Background()
+
+	if _, err := a.Authorize(ctx, "gk_unknown", http.MethodGet, "/orders"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Authorize(unknown) error = %v, want ErrInvalidKey", err)
+	}
+	if _, err := a.Authorize(ctx, secret, http.MethodPost, "/orders"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("Authorize(no scope) error = %v, want ErrForbidden", err)
+	}
+	key, err := a.Authorize(ctx, secret, http.MethodGet, "/orders")
+	if err != nil || key.ID != "k-1" {
+		t.Fatalf("Authorize() = (%v, %v), want k-1", key, err)
+	}
+	if _, err := a.Authorize(ctx, secret, http.MethodGet, "/orders"); !errors.Is(err, ErrRateLimited) {
+		t.Fatalf("Authorize() over limit error = %v, want ErrRateLimited", err)
+	}
+	if store.lookups != 2 {
+		t.Fatalf("store lookups = %d, want 2 (unknown + first hit, rest cached)", store.lookups)
+	}
+
+	_ = store.Revoke(ctx, "k-1")
+	a.Forget("k-1")
+	if _, err := a.Authorize(ctx, secret, http.MethodGet, "/orders"); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("Authorize(revoked) error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestKeySubject(t *testing.T) {
+	single := Key{Subjects: []string{"u-1"}}
+	multi := Key{Subjects: []string{"u-1", "u-2"}}
+	for _, tt := range []struct {
+		key    Key
+		userID string
+		want   string
+		ok     bool
+	}{
+		{single, "", "u-1", true},
+		{single, "u-1", "u-1", true},
+		{single, "u-2", "", false},
+		{multi, "", "", false},
+		{multi, "u-2", "u-2", true},
+		{Key{}, "", "", false},
+		{Key{}, "u-1", "", false},
+	} {
+		got, ok := tt.key.Subject(tt.userID)
+		if ok != tt.ok || (ok && got != tt.want) {
+			t.Fatalf("Key%v.Subject(%q) = (%q, %v), want (%q, %v)", tt.key.Subjects, tt.userID, got, ok, tt.want, tt.ok)
+		}
+	}
+}
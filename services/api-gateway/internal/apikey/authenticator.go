@@ -0,0 +1,105 @@
+package apikey
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	ErrInvalidKey  = errors.New("invalid api key")
+	ErrForbidden   = errors.New("api key lacks the required scope")
+	ErrRateLimited = errors.New("api key rate limit exceeded")
+)
+
+// Authenticator validates keys against the Store. Lookups are cached for
+// cacheTTL, so a revoked key may keep working for up to that long.
+type Authenticator struct {
+	store    Store
+	cacheTTL time.Duration
+	limits   *limiter
+
+	mu    sync.Mutex
+	cache map[string]cachedKey
+
+	now func() time.Time
+}
+
+type cachedKey struct {
+	key       Key
+	expiresAt time.Time
+}
+
+func NewAuthenticator(store Store, cacheTTL time.Duration) *Authenticator {
+	return &Authenticator{store: store, cacheTTL: cacheTTL, limits: newLimiter(), cache: make(map[string]cachedKey), now: time.Now}
+}
+
+// Authorize checks that secret is a live key allowed to call method+path and
+// still within its rate limit. path is relative to the API base path.
+func (a *Authenticator) Authorize(ctx context.Context, secret, method, path string) (Key, error) {
+	key, found, err := a.lookup(ctx, Hash(secret))
+	if err != nil {
+		return Key{}, err
+	}
+	if !found || key.RevokedAt != nil {
+		return Key{}, ErrInvalidKey
+	}
+	scope := RequiredScope(method, path)
+	if scope == "" || !key.HasScope(scope) {
+		return key, ErrForbidden
+	}
+	if !a.limits.allow(key.ID, key.RateLimitPerMinute) {
+		return key, ErrRateLimited
+	}
+	return key, nil
+}
+
+// Forget drops a cached lookup, e.g. right after the key was revoked here.
+func (a *Authenticator) Forget(id string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for hash, c := range a.cache {
+		if c.key.ID == id {
+			delete(a.cache, hash)
+		}
+	}
+}
+
+func (a *Authenticator) lookup(ctx context.Context, hash string) (Key, bool, error) {
+	now := a.now()
+	a.mu.Lock()
+	c, ok := a.cache[hash]
+	a.mu.Unlock()
+	if ok && now.Before(c.expiresAt) {
+		return c.key, true, nil
+	}
+
+	key, err := a.store.Lookup(ctx, hash)
+	if errors.Is(err, ErrNotFound) {
+		return Key{}, false, nil
+	}
+	if err != nil {
+		return Key{}, false, err
+	}
+	// Only hits are cached so unknown secrets cannot grow the map.
+	if a.cacheTTL > 0 {
+		a.mu.Lock()
+		a.cache[hash] = cachedKey{key: key, expiresAt: now.Add(a.cacheTTL)}
+		a.mu.Unlock()
+	}
+	return key, true, nil
+}
+
+type ctxKey struct{}
+
+// WithKey stores the authenticated key in ctx.
+func WithKey(ctx context.Context, key Key) context.Context {
+	return context.WithValue(ctx, ctxKey{}, key)
+}
+
+// FromContext returns the key that authenticated the request, if any.
+func FromContext(ctx context.Context) (Key, bool) {
+	key, ok := ctx.Value(ctxKey{}).(Key)
+	return key, ok
+}
@@ -0,0 +1,49 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// apiKeyAuth validates X-API-Key when present. Requests without the header
+// pass through unchanged; admin routes are guarded by their own token, and a
+// batch is not checked itself since each of its items is.
+func apiKeyAuth(auth *apikey.Authenticator, basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "apikey")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			secret := r.Header.Get(apikey.Header)
+			path, underBase := strings.CutPrefix(r.URL.Path, basePath)
+			if secret == "" || !underBase || strings.HasPrefix(path, "/admin/") || path == batchPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID := r.Header.Get("X-User-Id")
+			key, err := auth.Authorize(r.Context(), secret, r.Method, path)
+			switch {
+			case err == nil:
+				logger.Debug("api key accepted", "key_id", key.ID, "path", path)
+				next.ServeHTTP(w, r.WithContext(apikey.WithKey(r.Context(), key)))
+			case errors.Is(err, apikey.ErrInvalidKey):
+				logger.Warn("api key rejected", "path", path)
+				handler.WriteError(w, userID, http.StatusUnauthorized, err.Error())
+			case errors.Is(err, apikey.ErrForbidden):
+				logger.Warn("api key scope denied", "key_id", key.ID, "method", r.Method, "path", path)
+				handler.WriteError(w, userID, http.StatusForbidden, err.Error())
+			case errors.Is(err, apikey.ErrRateLimited):
+				logger.Warn("api key rate limited", "key_id", key.ID)
+				w.Header().Set("Retry-After", "1")
+				handler.WriteError(w, userID, http.StatusTooManyRequests, err.Error())
+			default:
+				logger.Error("api key lookup failed", "err", err)
+				handler.WriteError(w, userID, http.StatusServiceUnavailable, "api key check unavailable")
+			}
+		})
+	}
+}
@@ -3,49 +3,178 @@ package app
 import (
 	"context"
 	"errors"
+	"expvar"
 	"log/slog"
 	"net/http"
-	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/cors"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
 	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
 	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
 	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/loadshed"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+	"github.com/ilyaytrewq/payments-service/pkg/startup"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
 
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/audit"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
 	"github.com/ilyaytrewq/payments-service/api-gateway/internal/config"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/graphql"
 	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/i18n"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/timing"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
 )
 
 func Run(ctx context.Context, cfg config.Config) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "api-gateway", "component", "app")
 	logger.Info("api gateway starting", "http_addr", cfg.HTTPAddr, "base_path", cfg.BasePath)
-	ordersConn, err := grpc.DialContext(ctx, cfg.OrdersGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if cfg.JWTSecret == "" {
+		// Without tokens the caller would be whoever X-User-Id names.
+		err := errors.New("JWT_SECRET is required")
+		logger.Error("missing jwt secret", "err", err)
+		return err
+	}
+	if cfg.DatabaseURL != "" {
+		waitCfg := startup.Config{Timeout: cfg.StartupWaitTimeout, CheckTimeout: cfg.StartupCheckTimeout}
+		if err := startup.Wait(ctx, waitCfg, logger, startup.Postgres("postgres", cfg.DatabaseURL)); err != nil {
+			return err
+		}
+	}
+
+	serviceAuth, err := svcauth.ParseConfig(cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	if err != nil {
+		logger.Error("invalid service auth config", "err", err)
+		return err
+	}
+	clientInterceptors := []grpc.UnaryClientInterceptor{auth.ForwardToken(), logging.UnaryClientInterceptor(), timing.UnaryClientInterceptor()}
+	if serviceAuth.Enabled() {
+		creds, err := svcauth.UnaryClientInterceptor(serviceAuth, "api-gateway")
+		if err != nil {
+			logger.Error("invalid service auth config", "err", err)
+			return err
+		}
+		clientInterceptors = append(clientInterceptors, creds)
+		logger.Info("service auth enabled", "mode", serviceAuth.Mode)
+	}
+
+	ordersConn, err := grpc.DialContext(ctx, cfg.OrdersGRPCAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(clientInterceptors...),
+	)
 	if err != nil {
 		logger.Error("failed to dial orders grpc", "err", err, "addr", cfg.OrdersGRPCAddr)
 		return err
 	}
 	defer ordersConn.Close()
 
-	paymentsConn, err := grpc.DialContext(ctx, cfg.PaymentsGRPCAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	paymentsConn, err := grpc.DialContext(ctx, cfg.PaymentsGRPCAddr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(clientInterceptors...),
+	)
 	if err != nil {
 		logger.Error("failed to dial payments grpc", "err", err, "addr", cfg.PaymentsGRPCAddr)
 		return err
 	}
 	defer paymentsConn.Close()
 
+	var notifications notificationsv1.NotificationsServiceClient
+	if cfg.NotificationsGRPCAddr != "" {
+		notificationsConn, err := grpc.DialContext(ctx, cfg.NotificationsGRPCAddr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithChainUnaryInterceptor(clientInterceptors...),
+		)
+		if err != nil {
+			logger.Error("failed to dial notifications grpc", "err", err, "addr", cfg.NotificationsGRPCAddr)
+			return err
+		}
+		defer notificationsConn.Close()
+		notifications = notificationsv1.NewNotificationsServiceClient(notificationsConn)
+		logger.Info("notification preferences enabled", "addr", cfg.NotificationsGRPCAddr)
+	}
+
+	var (
+		apiKeys  apikey.Store
+		keyAuth  *apikey.Authenticator
+		auditLog audit.Store
+		auditor  *audit.Logger
+		usageLog usage.Store
+		meter    *usage.Meter
+	)
+	if cfg.DatabaseURL != "" {
+		pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+		if err != nil {
+			logger.Error("failed to create api key db pool", "err", err)
+			return err
+		}
+		defer pool.Close()
+		apiKeys = apikey.NewPostgresStore(pool)
+		keyAuth = apikey.NewAuthenticator(apiKeys, cfg.APIKeyCacheTTL)
+		logger.Info("api key auth enabled", "cache_ttl", cfg.APIKeyCacheTTL, "admin_api", cfg.AdminToken != "")
+
+		auditLog = audit.NewPostgresStore(pool)
+		auditor = audit.NewLogger(auditLog, audit.Options{
+			SampleRate:    cfg.AuditSampleRate,
+			BufferSize:    cfg.AuditBufferSize,
+			FlushInterval: cfg.AuditFlushInterval,
+		})
+		defer auditor.Close()
+		logger.Info("audit log enabled", "sample_rate", cfg.AuditSampleRate, "buffer_size", cfg.AuditBufferSize)
+
+		if cfg.RedisAddr != "" {
+			rdb := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+			defer func() {
+				if err := rdb.Close(); err != nil {
+					logger.Error("failed to close redis client", "err", err)
+				}
+			}()
+			usageLog = usage.NewPostgresStore(pool)
+			meter = usage.NewMeter(usage.NewRedisCounters(rdb), usageLog, cfg.UsageFlushInterval)
+			defer meter.Close()
+			logger.Info("usage metering enabled", "flush_interval", cfg.UsageFlushInterval)
+		}
+	}
+
+	sessions := auth.NewSessions([]byte(cfg.JWTSecret), cfg.SessionTTL)
+
 	apiHandler := handler.New(
 		ordersv1.NewOrdersServiceClient(ordersConn),
 		paymentsv1.NewPaymentsServiceClient(paymentsConn),
+		notifications,
+		cfg.RequestBudget,
+		cfg.HedgeDelay,
+		apiKeys,
+		keyAuth,
+		auditLog,
+		usageLog,
+		cfg.AdminToken,
+		sessions,
 	)
 
+	catalog, err := i18n.New(cfg.DefaultLanguage)
+	if err != nil {
+		logger.Error("invalid GATEWAY_DEFAULT_LANGUAGE", "err", err)
+		return err
+	}
+
 	router := chi.NewRouter()
+	router.Use(logging.Middleware)
 	router.Use(requestLogger)
+	router.Use(localizeErrors(catalog))
+	if auditor != nil {
+		router.Use(auditCalls(auditor, cfg.BasePath))
+	}
 
 	router.Use(cors.Handler(cors.Options{
 		AllowedOrigins: []string{
@@ -63,25 +192,41 @@ func Run(ctx context.Context, cfg config.Config) error {
 			"X-CSRF-Token",
 			"X-User-Id",
 			"Idempotency-Key",
+			apikey.Header,
+			"X-Admin-Token",
+			signature.Header,
+			logging.RequestIDHeader,
+			logging.TraceparentHeader,
+			debugTimingHeader,
 		},
-		ExposedHeaders:   []string{"Link"},
+		ExposedHeaders:   []string{"Link", logging.RequestIDHeader, "Server-Timing"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
 
-	router.Use(func(next http.Handler) http.Handler {
-		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-			if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, cfg.BasePath) {
-				if strings.TrimSpace(r.Header.Get("Idempotency-Key")) == "" {
-					userID := r.Header.Get("X-User-Id")
-					logger.Error("missing idempotency key", "path", r.URL.Path, "user_id", userID)
-					handler.WriteBadRequest(w, userID, errors.New("idempotency key is required"))
-					return
-				}
-			}
-			next.ServeHTTP(w, r)
-		})
-	})
+	router.Use(requireIdempotencyKey(cfg.BasePath))
+
+	if cfg.SigningKeys != "" {
+		keys, err := signature.ParseKeyring(cfg.SigningKeys)
+		if err != nil {
+			logger.Error("invalid GATEWAY_SIGNING_KEYS", "err", err)
+			return err
+		}
+		router.Use(requireSignature(keys, cfg.SignatureTolerance, cfg.BasePath))
+		logger.Info("request signatures required", "active_key", keys.ActiveKeyID(), "tolerance", cfg.SignatureTolerance)
+	}
+	if keyAuth != nil {
+		router.Use(apiKeyAuth(keyAuth, cfg.BasePath))
+	}
+	if meter != nil {
+		router.Use(meterUsage(meter, cfg.BasePath))
+	}
+	router.Use(rbacAuth([]byte(cfg.JWTSecret), cfg.BasePath))
+	logger.Info("rbac enabled", "session_ttl", cfg.SessionTTL)
+	router.Use(debugTiming(cfg.AdminToken, cfg.DebugTiming))
+	if cfg.DebugTiming {
+		logger.Warn("debug timing open to every caller")
+	}
 
 	healthHandler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -96,9 +241,22 @@ func Run(ctx context.Context, cfg config.Config) error {
 		w.WriteHeader(http.StatusNoContent)
 	})
 
+	var handlerMiddlewares []gateway.MiddlewareFunc
+	shedConfig := loadshed.Config{MinLimit: cfg.LoadShedMinLimit, MaxLimit: cfg.LoadShedMaxLimit}
+	if shedConfig.Enabled() {
+		routes, err := loadshed.ParseRoutes(cfg.LoadShedRoutes)
+		if err != nil {
+			logger.Error("invalid GATEWAY_LOADSHED_ROUTES", "err", err)
+			return err
+		}
+		handlerMiddlewares = append(handlerMiddlewares, loadShed(loadshed.NewSet("api-gateway", shedConfig, routes).Exempt("GET /orders/{orderId}/wait"), cfg.BasePath))
+		logger.Info("load shedding enabled", "max_limit", cfg.LoadShedMaxLimit, "min_limit", cfg.LoadShedMinLimit, "routes", len(routes))
+	}
+
 	gateway.HandlerWithOptions(apiHandler, gateway.ChiServerOptions{
-		BaseURL:    cfg.BasePath,
-		BaseRouter: router,
+		BaseURL:     cfg.BasePath,
+		BaseRouter:  router,
+		Middlewares: handlerMiddlewares,
 		ErrorHandlerFunc: func(w http.ResponseWriter, r *http.Request, err error) {
 			userID := r.Header.Get("X-User-Id")
 			logger.Error("gateway handler error", "err", err, "path", r.URL.Path, "user_id", userID)
@@ -106,23 +264,55 @@ func Run(ctx context.Context, cfg config.Config) error {
 		},
 	})
 
-	server := &http.Server{
-		Addr:              cfg.HTTPAddr,
-		Handler:           router,
-		ReadHeaderTimeout: 5 * time.Second,
+	graphqlHandler := graphql.NewHandler(
+		ordersv1.NewOrdersServiceClient(ordersConn),
+		paymentsv1.NewPaymentsServiceClient(paymentsConn),
+		cfg.RequestBudget,
+		cfg.HedgeDelay,
+		cfg.GraphQLComplexityLimit,
+		cfg.GraphQLIntrospection,
+	)
+	for _, middleware := range handlerMiddlewares {
+		graphqlHandler = middleware(graphqlHandler)
+	}
+	router.Method(http.MethodPost, cfg.BasePath+"/graphql", graphqlHandler)
+
+	if cfg.BatchMaxRequests > 0 {
+		router.Method(http.MethodPost, cfg.BasePath+batchPath, batchHandler(router, cfg.BasePath, cfg.BatchMaxRequests, cfg.RequestBudget))
+		logger.Info("batch endpoint enabled", "max_requests", cfg.BatchMaxRequests)
 	}
 
-	errCh := make(chan error, 1)
+	tracker := newServerTracker()
+	server := newServer(cfg, router, tracker)
+	server.RegisterOnShutdown(apiHandler.Drain)
+
+	errCh := make(chan error, 2)
 	go func() {
-		logger.Info("gateway listening", "http_addr", cfg.HTTPAddr)
+		logger.Info("gateway listening", "http_addr", cfg.HTTPAddr, "h2c", cfg.H2C, "http2_max_streams", cfg.HTTP2MaxStreams,
+			"read_timeout", cfg.HTTPReadTimeout, "write_timeout", cfg.HTTPWriteTimeout, "idle_timeout", cfg.HTTPIdleTimeout)
 		errCh <- server.ListenAndServe()
 	}()
 
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			logger.Info("metrics listening", "metrics_addr", cfg.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}()
+		defer metricsServer.Close()
+	}
+
 	select {
 	case <-ctx.Done():
-		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer cancel()
-		err := server.Shutdown(shutdownCtx)
+		err := tracker.drain(server, cfg.ShutdownTimeout, logger)
 		if err != nil {
 			logger.Error("gateway shutdown failed", "err", err, "duration", time.Since(start))
 			return err
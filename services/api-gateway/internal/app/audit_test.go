@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/audit"
+)
+
+type recordingAuditStore struct {
+	mu      sync.Mutex
+	entries []audit.Entry
+}
+
+func (s *recordingAuditStore) Insert(_ context.Context, entries []audit.Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *recordingAuditStore) Recent(context.Context, string, int) ([]audit.Entry, error) {
+	return nil, nil
+}
+
+func TestAuditCalls(t *testing.T) {
+	store := &recordingAuditStore{}
+	log := audit.NewLogger(store, audit.Options{SampleRate: 1, FlushInterval: time.Hour})
+	h := auditCalls(log, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		r.Header.Set("X-User-Id", "from-token")
+		markAuditVerified(r.Context())
+		w.WriteHeader(http.StatusTeapot)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders", nil)
+	req.Header.Set("Idempotency-Key", "idem-1")
+	req.Header.Set("Authorization", "Bearer t")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	spoofed := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	spoofed.Header.Set("X-User-Id", "victim")
+	h.ServeHTTP(httptest.NewRecorder(), spoofed)
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodOptions, "/api/v1/orders", nil))
+	log.Close()
+
+	if len(store.entries) != 2 {
+		t.Fatalf("entries = %d, want 2", len(store.entries))
+	}
+	e := store.entries[0]
+	if e.Method != http.MethodPost || e.Path != "/api/v1/orders" || e.UserID != "from-token" || !e.UserVerified || e.IdempotencyKey != "idem-1" || e.Status != http.StatusTeapot {
+		t.Fatalf("entry = %+v", e)
+	}
+	if e := store.entries[1]; e.UserID != "victim" || e.UserVerified || e.Status != http.StatusUnauthorized {
+		t.Fatalf("rejected entry = %+v, want unverified victim", e)
+	}
+}
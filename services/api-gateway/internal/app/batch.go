@@ -0,0 +1,207 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/fanout"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// maxBatchBody caps how much of a batch request is read.
+const maxBatchBody = 1 << 20
+
+// batchPath is the batch route relative to the base path.
+const batchPath = "/batch"
+
+// batchHeaders are copied from the batch request to each of its items so that
+// every item runs as the same caller; an item's own headers win.
+var batchHeaders = []string{"Authorization", "X-User-Id", apikey.Header, "X-Admin-Token", "Accept-Language", logging.TraceparentHeader}
+
+type batchRequest struct {
+	Requests []batchItem `json:"requests"`
+}
+
+// batchItem is one sub-request; Path is relative to the base path and may
+// carry a query string.
+type batchItem struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type batchResponse struct {
+	Responses []batchResult `json:"responses"`
+}
+
+type batchResult struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// batchHandler serves POST /batch: it runs up to maxItems sub-requests
+// concurrently through next, the gateway's own router, so each item passes
+// the same RBAC, API key, idempotency and signature checks as if it were sent
+// alone. All items share one deadline of budget, and the results come back in
+// request order with each item's status and body.
+func batchHandler(next http.Handler, basePath string, maxItems int, budget time.Duration) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "batch")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		userID := r.Header.Get("X-User-Id")
+		var req batchRequest
+		dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBatchBody))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&req); err != nil {
+			logger.WarnContext(r.Context(), "batch decode failed", "err", err)
+			handler.WriteBadRequest(w, userID, err)
+			return
+		}
+		if len(req.Requests) == 0 || len(req.Requests) > maxItems {
+			handler.WriteError(w, userID, http.StatusBadRequest, fmt.Sprintf("a batch holds 1 to %d requests", maxItems))
+			return
+		}
+
+		ctx, cancel := fanout.WithBudget(r.Context(), budget)
+		defer cancel()
+		r = r.WithContext(ctx)
+
+		// A failed item is just its own result and never cancels the others.
+		results := make([]batchResult, len(req.Requests))
+		calls := make([]fanout.Call, len(req.Requests))
+		for i, item := range req.Requests {
+			calls[i] = func(ctx context.Context) error {
+				results[i] = runBatchItem(next, r.WithContext(ctx), basePath, item)
+				return nil
+			}
+		}
+		_ = fanout.All(ctx, calls...)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_ = json.NewEncoder(w).Encode(batchResponse{Responses: results})
+		logger.InfoContext(ctx, "batch completed", "requests", len(results), "duration", time.Since(start))
+	})
+}
+
+// runBatchItem serves one item of the batch r and records its response.
+func runBatchItem(next http.Handler, r *http.Request, basePath string, item batchItem) batchResult {
+	rec := &batchRecorder{header: http.Header{}}
+	sub, err := newBatchItemRequest(r, basePath, item)
+	if err != nil {
+		handler.WriteError(rec, r.Header.Get("X-User-Id"), http.StatusBadRequest, err.Error())
+	} else {
+		next.ServeHTTP(rec, sub)
+	}
+	return rec.result()
+}
+
+// newBatchItemRequest builds the request of item under the batch r: same
+// caller, same context and request id.
+func newBatchItemRequest(r *http.Request, basePath string, item batchItem) (*http.Request, error) {
+	method := strings.ToUpper(strings.TrimSpace(item.Method))
+	switch method {
+	case http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+	default:
+		return nil, fmt.Errorf("method %q is not allowed in a batch", item.Method)
+	}
+	target, err := url.ParseRequestURI(item.Path)
+	if err != nil || target.IsAbs() || target.Host != "" || !strings.HasPrefix(target.Path, "/") {
+		return nil, fmt.Errorf("path %q must be relative to the base path, e.g. /orders", item.Path)
+	}
+	if target.Path == batchPath {
+		return nil, fmt.Errorf("batches cannot be nested")
+	}
+
+	var body []byte
+	if len(item.Body) > 0 && string(item.Body) != "null" {
+		body = item.Body
+	}
+	// chi reuses a routing context it finds on the request; drop the batch's
+	// so the item is routed from scratch.
+	ctx := context.WithValue(r.Context(), chi.RouteCtxKey, nil)
+	sub, err := http.NewRequestWithContext(ctx, method, basePath+target.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	sub.RemoteAddr = r.RemoteAddr
+	for _, name := range batchHeaders {
+		if v := r.Header.Get(name); v != "" {
+			sub.Header.Set(name, v)
+		}
+	}
+	if id := logging.RequestID(r.Context()); id != "" {
+		sub.Header.Set(logging.RequestIDHeader, id)
+	}
+	if body != nil {
+		sub.Header.Set("Content-Type", "application/json")
+	}
+	for name, v := range item.Headers {
+		sub.Header.Set(name, v)
+	}
+	return sub, nil
+}
+
+// batchRecorder buffers the response of one batch item.
+type batchRecorder struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (r *batchRecorder) Header() http.Header {
+	return r.header
+}
+
+func (r *batchRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+}
+
+func (r *batchRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.body.Write(b)
+}
+
+// result returns the recorded response; a body that is not JSON is returned
+// as a JSON string.
+func (r *batchRecorder) result() batchResult {
+	res := batchResult{Status: r.status}
+	if res.Status == 0 {
+		res.Status = http.StatusOK
+	}
+	for name, values := range r.header {
+		if name == "Content-Type" || name == "Content-Length" || len(values) == 0 {
+			continue
+		}
+		if res.Headers == nil {
+			res.Headers = map[string]string{}
+		}
+		res.Headers[name] = values[0]
+	}
+	if body := bytes.TrimSpace(r.body.Bytes()); len(body) > 0 {
+		if json.Valid(body) {
+			res.Body = body
+		} else {
+			res.Body, _ = json.Marshal(string(body))
+		}
+	}
+	return res
+}
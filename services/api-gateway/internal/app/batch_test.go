@@ -0,0 +1,103 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+)
+
+func TestBatchHandler(t *testing.T) {
+	// Both /slow items must be in flight at once to get past the barrier.
+	var started sync.WaitGroup
+	started.Add(2)
+	router := chi.NewRouter()
+	router.Use(requireIdempotencyKey("/api/v1"))
+	router.Get("/api/v1/echo", func(w http.ResponseWriter, r *http.Request) {
+		_, hasDeadline := r.Context().Deadline()
+		writeTestJSON(w, http.StatusOK, map[string]any{"user": r.Header.Get("X-User-Id"), "q": r.URL.Query().Get("q"), "deadline": hasDeadline})
+	})
+	router.Post("/api/v1/echo", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		w.Header().Set("Location", "/api/v1/echo/1")
+		writeTestJSON(w, http.StatusCreated, body)
+	})
+	router.Get("/api/v1/slow", func(w http.ResponseWriter, r *http.Request) {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			w.WriteHeader(http.StatusNoContent)
+		case <-r.Context().Done():
+			w.WriteHeader(http.StatusGatewayTimeout)
+		}
+	})
+	router.Method(http.MethodPost, "/api/v1"+batchPath, batchHandler(router, "/api/v1", 5, time.Second))
+
+	do := func(body string) (int, batchResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/batch", strings.NewReader(body))
+		req.Header.Set("X-User-Id", "u-1")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		var resp batchResponse
+		if rec.Code == http.StatusOK {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, resp := do(`{"requests":[
+		{"method":"GET","path":"/echo?q=tea"},
+		{"method":"POST","path":"/echo","headers":{"Idempotency-Key":"k-1"},"body":{"amount":"10"}},
+		{"method":"POST","path":"/echo","body":{}},
+		{"method":"GET","path":"/slow"},
+		{"method":"GET","path":"/slow"}
+	]}`)
+	if code != http.StatusOK || len(resp.Responses) != 5 {
+		t.Fatalf("batch = %d, %+v; want 200 with 5 responses", code, resp)
+	}
+	if got := resp.Responses[0]; got.Status != http.StatusOK || string(got.Body) != `{"deadline":true,"q":"tea","user":"u-1"}` {
+		t.Fatalf("echo = %d %s; want the caller, query and shared deadline", got.Status, got.Body)
+	}
+	if got := resp.Responses[1]; got.Status != http.StatusCreated || string(got.Body) != `{"amount":"10"}` || got.Headers["Location"] != "/api/v1/echo/1" {
+		t.Fatalf("post = %d %s %v", got.Status, got.Body, got.Headers)
+	}
+	if got := resp.Responses[2]; got.Status != http.StatusBadRequest || !strings.Contains(string(got.Body), "idempotency_key_required") {
+		t.Fatalf("post without key = %d %s; want 400 from the item's own checks", got.Status, got.Body)
+	}
+	if resp.Responses[3].Status != http.StatusNoContent || resp.Responses[4].Status != http.StatusNoContent {
+		t.Fatalf("slow = %d, %d; want both served concurrently", resp.Responses[3].Status, resp.Responses[4].Status)
+	}
+
+	code, resp = do(`{"requests":[{"method":"POST","path":"/batch"},{"method":"TRACE","path":"/echo"},{"method":"GET","path":"http://evil/echo"},{"method":"GET","path":"/missing"}]}`)
+	if code != http.StatusOK {
+		t.Fatalf("invalid items: status = %d, want 200", code)
+	}
+	for i, want := range []int{http.StatusBadRequest, http.StatusBadRequest, http.StatusBadRequest, http.StatusNotFound} {
+		if resp.Responses[i].Status != want {
+			t.Fatalf("item %d: status = %d, want %d (%s)", i, resp.Responses[i].Status, want, resp.Responses[i].Body)
+		}
+	}
+
+	if code, _ := do(`{"requests":[]}`); code != http.StatusBadRequest {
+		t.Fatalf("empty batch: status = %d, want 400", code)
+	}
+	if code, _ := do(`{"requests":[` + strings.Repeat(`{"method":"GET","path":"/echo"},`, 5) + `{"method":"GET","path":"/echo"}]}`); code != http.StatusBadRequest {
+		t.Fatalf("oversized batch: status = %d, want 400", code)
+	}
+}
+
+func writeTestJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/i18n"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+)
+
+// localizeErrors fills the message field of JSON error bodies with the
+// catalog text for their code in the client's Accept-Language. It wraps the
+// whole router, so errors written by any middleware are localized too.
+// Successful responses pass through unbuffered.
+func localizeErrors(catalog *i18n.Catalog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			lw := &localizingResponseWriter{ResponseWriter: w}
+			next.ServeHTTP(lw, r)
+			if lw.buf == nil {
+				return
+			}
+
+			body := lw.buf.Bytes()
+			var eb httperr.Body
+			if err := json.Unmarshal(body, &eb); err == nil && eb.Code != "" {
+				lang := catalog.Negotiate(r.Header.Get("Accept-Language"))
+				if msg, ok := catalog.Message(lang, eb.Code); ok {
+					eb.Message = msg
+					w.Header().Set("Content-Language", lang.String())
+					if b, err := json.Marshal(eb); err == nil {
+						body = append(b, '\n')
+					}
+				}
+			}
+			w.Header().Add("Vary", "Accept-Language")
+			w.WriteHeader(lw.status)
+			_, _ = w.Write(body)
+		})
+	}
+}
+
+// localizingResponseWriter holds back JSON error responses (status >= 400)
+// so that their body can be rewritten; anything else is written through.
+type localizingResponseWriter struct {
+	http.ResponseWriter
+	status int
+	buf    *bytes.Buffer
+}
+
+func (w *localizingResponseWriter) WriteHeader(code int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = code
+	if code >= http.StatusBadRequest && strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.buf = new(bytes.Buffer)
+		return
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *localizingResponseWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.buf != nil {
+		return w.buf.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
@@ -0,0 +1,69 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/i18n"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+)
+
+func TestLocalizeErrors(t *testing.T) {
+	catalog, err := i18n.New("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := localizeErrors(catalog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"status":"ok"}`))
+		case "/custom":
+			handler.WriteErrorCode(w, "u-1", http.StatusBadRequest, "no_such_code", "odd")
+		default:
+			handler.WriteErrorCode(w, "u-1", http.StatusBadRequest, handler.CodeInvalidAmount, "amount must be > 0")
+		}
+	}))
+	serve := func(path, lang string) (*httptest.ResponseRecorder, httperr.Body) {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if lang != "" {
+			req.Header.Set("Accept-Language", lang)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		var body httperr.Body
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: decode %q: %v", path, rec.Body, err)
+		}
+		return rec, body
+	}
+
+	rec, body := serve("/orders", "ru-RU,ru;q=0.9")
+	if rec.Code != http.StatusBadRequest || rec.Header().Get("Content-Language") != "ru" {
+		t.Fatalf("ru: status = %d, Content-Language = %q", rec.Code, rec.Header().Get("Content-Language"))
+	}
+	if !reflect.DeepEqual(body, httperr.Body{UserID: "u-1", Error: "amount must be > 0", Code: handler.CodeInvalidAmount, Message: "Сумма должна быть больше нуля."}) {
+		t.Fatalf("ru body = %+v", body)
+	}
+
+	rec, body = serve("/orders", "")
+	if rec.Header().Get("Content-Language") != "en" || body.Message != "The amount must be greater than zero." {
+		t.Fatalf("default: Content-Language = %q, message = %q", rec.Header().Get("Content-Language"), body.Message)
+	}
+
+	rec, body = serve("/custom", "ru")
+	if rec.Code != http.StatusBadRequest || body.Message != "" || body.Error != "odd" {
+		t.Fatalf("unknown code: status = %d, body = %+v; want it unchanged", rec.Code, body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ok", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"status":"ok"}` || rec.Header().Get("Vary") != "" {
+		t.Fatalf("success: status = %d, body = %s, Vary = %q", rec.Code, rec.Body, rec.Header().Get("Vary"))
+	}
+}
@@ -0,0 +1,37 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// requireIdempotencyKey rejects POSTs under basePath without an
+// Idempotency-Key header, so a retried write never runs twice.
+func requireIdempotencyKey(basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "app")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Admin calls are not retried by clients, GraphQL queries and
+			// order validation change nothing, a retried session start
+			// only issues another token, and the items of a batch carry
+			// their own keys, so all are exempt.
+			exempt := strings.HasPrefix(r.URL.Path, basePath+"/admin/") ||
+				r.URL.Path == basePath+"/graphql" ||
+				r.URL.Path == basePath+sessionPath ||
+				r.URL.Path == basePath+batchPath ||
+				r.URL.Path == basePath+"/orders:validate"
+			if r.Method == http.MethodPost && strings.HasPrefix(r.URL.Path, basePath) && !exempt {
+				if strings.TrimSpace(r.Header.Get("Idempotency-Key")) == "" {
+					userID := r.Header.Get("X-User-Id")
+					logger.Error("missing idempotency key", "path", r.URL.Path, "user_id", userID)
+					handler.WriteErrorCode(w, userID, http.StatusBadRequest, handler.CodeIdempotencyKeyRequired, "idempotency key is required")
+					return
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/testsupport"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+func TestRequireIdempotencyKey(t *testing.T) {
+	b := testsupport.Start(t)
+	h := handler.New(b.OrdersClient, b.PaymentsClient, nil, time.Second, 0, nil, nil, nil, nil, "", auth.NewSessions([]byte("s"), time.Hour))
+	router := chi.NewRouter()
+	router.Use(requireIdempotencyKey("/api/v1"))
+	srv := gateway.HandlerWithOptions(h, gateway.ChiServerOptions{BaseURL: "/api/v1", BaseRouter: router})
+
+	tests := []struct {
+		name      string
+		path      string
+		key       string
+		want      int
+		wantCalls int
+	}{
+		{"write without key", "/api/v1/orders", "", http.StatusBadRequest, 0},
+		{"blank key", "/api/v1/orders", "  ", http.StatusBadRequest, 0},
+		{"write with key", "/api/v1/orders", "k-1", http.StatusCreated, 1},
+		{"validation is exempt", "/api/v1/orders:validate", "", http.StatusOK, 0},
+		{"session is exempt", "/api/v1/session", "", http.StatusCreated, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := b.Orders.Calls("CreateOrder")
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"amount": "100", "description": "tea"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-User-Id", "u-1")
+			if tt.key != "" {
+				req.Header.Set("Idempotency-Key", tt.key)
+			}
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.want == http.StatusBadRequest && !strings.Contains(rec.Body.String(), handler.CodeIdempotencyKeyRequired) {
+				t.Fatalf("body %s lacks code %s", rec.Body, handler.CodeIdempotencyKeyRequired)
+			}
+			if got := b.Orders.Calls("CreateOrder") - before; got != tt.wantCalls {
+				t.Fatalf("CreateOrder calls = %d, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,39 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/pkg/loadshed"
+)
+
+// loadShed answers 503 when the route's adaptive concurrency limit is
+// reached. It runs after routing, so routes are keyed by method and pattern
+// relative to the base path, e.g. "GET /orders/{orderId}". A 503 or 504 from
+// downstream counts as overload and lowers the limit.
+func loadShed(limiters *loadshed.Set, basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "loadshed")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			route := r.Method + " " + strings.TrimPrefix(chi.RouteContext(r.Context()).RoutePattern(), basePath)
+			if limiters.IsExempt(route) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			done, ok := limiters.For(route).Acquire()
+			if !ok {
+				logger.Warn("request shed", "route", route)
+				w.Header().Set("Retry-After", "1")
+				handler.WriteErrorCode(w, r.Header.Get("X-User-Id"), http.StatusServiceUnavailable, handler.CodeOverloaded, "server overloaded, retry later")
+				return
+			}
+			lw := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(lw, r)
+			done(lw.status == http.StatusServiceUnavailable || lw.status == http.StatusGatewayTimeout)
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ilyaytrewq/payments-service/pkg/loadshed"
+)
+
+func TestLoadShed(t *testing.T) {
+	limiters := loadshed.NewSet("gateway-test", loadshed.Config{MinLimit: 1, MaxLimit: 10}, map[string]int{"GET /orders/{orderId}": 1}).Exempt("GET /orders/{orderId}/wait")
+
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	router := chi.NewRouter()
+	router.With(loadShed(limiters, "/api/v1")).Get("/api/v1/orders/{orderId}", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("block") != "" {
+			started <- struct{}{}
+			<-release
+		}
+	})
+	router.With(loadShed(limiters, "/api/v1")).Get("/api/v1/orders", func(http.ResponseWriter, *http.Request) {})
+	router.With(loadShed(limiters, "/api/v1")).Get("/api/v1/orders/{orderId}/wait", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	go router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1?block=1", nil))
+	<-started
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-2", nil))
+	if rec.Code != http.StatusServiceUnavailable || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("over the route limit: status = %d, Retry-After = %q; want 503 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("other route: status = %d, want 200", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1/wait", nil))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("exempt route: status = %d, want 204", rec.Code)
+	}
+	if _, ok := limiters.Stats()["GET /orders/{orderId}/wait"]; ok {
+		t.Fatal("exempt route got a limiter")
+	}
+
+	close(release)
+}
@@ -32,6 +32,6 @@ func requestLogger(next http.Handler) http.Handler {
 		lw := &loggingResponseWriter{ResponseWriter: w, status: http.StatusOK}
 		next.ServeHTTP(lw, r)
 		logger := slog.Default().With("service", "api-gateway", "component", "http")
-		logger.Info("http request completed", "method", r.Method, "path", r.URL.Path, "status", lw.status, "bytes", lw.bytes, "duration", time.Since(start))
+		logger.InfoContext(r.Context(), "http request completed", "method", r.Method, "path", r.URL.Path, "status", lw.status, "bytes", lw.bytes, "duration", time.Since(start))
 	})
 }
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// apiKeyTokenTTL bounds the token minted for API key requests; it only has to
+// outlive the downstream calls of one request.
+const apiKeyTokenTTL = time.Minute
+
+// sessionPath is the route handing out session tokens, relative to the base
+// path.
+const sessionPath = "/session"
+
+// rbacAuth checks the bearer token (a JWT or a session token from POST
+// /session) against the route's roles and pins plain users to their own
+// X-User-Id. Requests already authenticated by an API key act for one of the
+// key's subjects, named in X-User-Id unless the key has only one, and get a
+// short-lived user token for it so the services can authorize them too; the
+// route must allow that user role. Routes under basePath missing from the role table are
+// denied, except POST /session, which hands out the token, and /batch, whose
+// items are checked one by one as they go through the router again.
+func rbacAuth(secret []byte, basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "rbac")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, underBase := strings.CutPrefix(r.URL.Path, basePath)
+			if !underBase || r.Method == http.MethodOptions || path == sessionPath || path == batchPath {
+				next.ServeHTTP(w, r)
+				return
+			}
+			userID := r.Header.Get("X-User-Id")
+			roles, ok := auth.RequiredRoles(r.Method, path)
+			if !ok {
+				logger.Warn("route has no role policy", "method", r.Method, "path", path)
+				handler.WriteError(w, userID, http.StatusForbidden, "route not allowed")
+				return
+			}
+			if key, ok := apikey.FromContext(r.Context()); ok {
+				if strings.TrimSpace(userID) == "" {
+					userID = ""
+				}
+				subject, ok := key.Subject(userID)
+				if !ok && userID == "" {
+					logger.Warn("api key request without user id", "key_id", key.ID, "path", path)
+					handler.WriteErrorCode(w, "", http.StatusBadRequest, handler.CodeUserIDRequired, "X-User-Id header is required")
+					return
+				}
+				if !ok {
+					logger.Warn("user id not bound to api key", "key_id", key.ID, "user_id", userID)
+					handler.WriteError(w, userID, http.StatusForbidden, "X-User-Id is not bound to this api key")
+					return
+				}
+				claims := auth.Claims{Subject: subject, Roles: []auth.Role{auth.RoleUser}, ExpiresAt: time.Now().Add(apiKeyTokenTTL).Unix()}
+				if !claims.HasAny(roles) {
+					logger.Warn("role denied", "key_id", key.ID, "roles", claims.Roles, "method", r.Method, "path", path)
+					handler.WriteError(w, subject, http.StatusForbidden, "role not allowed")
+					return
+				}
+				token, err := auth.Sign(claims, secret)
+				if err != nil {
+					logger.Error("sign api key token failed", "err", err, "key_id", key.ID)
+					handler.WriteError(w, subject, http.StatusInternalServerError, "internal error")
+					return
+				}
+				r.Header.Set("X-User-Id", subject)
+				markAuditVerified(r.Context())
+				next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims, token)))
+				return
+			}
+
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || strings.TrimSpace(token) == "" {
+				handler.WriteErrorCode(w, userID, http.StatusUnauthorized, handler.CodeSessionRequired, "missing bearer token, see POST /session")
+				return
+			}
+			token = strings.TrimSpace(token)
+			claims, err := auth.Verify(token, secret, time.Now())
+			if err != nil {
+				logger.Warn("token rejected", "path", path)
+				handler.WriteError(w, userID, http.StatusUnauthorized, err.Error())
+				return
+			}
+			if !claims.HasAny(roles) {
+				logger.Warn("role denied", "sub", claims.Subject, "roles", claims.Roles, "method", r.Method, "path", path)
+				handler.WriteError(w, userID, http.StatusForbidden, "role not allowed")
+				return
+			}
+			if !claims.Privileged() {
+				if userID != "" && userID != claims.Subject {
+					logger.Warn("user id does not match token subject", "sub", claims.Subject, "user_id", userID)
+					handler.WriteError(w, userID, http.StatusForbidden, "X-User-Id does not match token")
+					return
+				}
+				r.Header.Set("X-User-Id", claims.Subject)
+			}
+			logger.Debug("token accepted", "sub", claims.Subject, "roles", claims.Roles, "path", path)
+			markAuditVerified(r.Context())
+			next.ServeHTTP(w, r.WithContext(auth.WithClaims(r.Context(), claims, token)))
+		})
+	}
+}
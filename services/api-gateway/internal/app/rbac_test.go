@@ -0,0 +1,94 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+)
+
+func TestRBACAuth(t *testing.T) {
+	secret := []byte("s")
+	sign := func(sub string, roles ...auth.Role) string {
+		token, err := auth.Sign(auth.Claims{Subject: sub, Roles: roles, ExpiresAt: time.Now().Add(time.Minute).Unix()}, secret)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+
+	session, claims, err := auth.NewSessions(secret, time.Hour).Issue(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var seenUser string
+	var seenClaims bool
+	h := rbacAuth(secret, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenUser = r.Header.Get("X-User-Id")
+		_, seenClaims = auth.FromContext(r.Context())
+	}))
+
+	tests := []struct {
+		name          string
+		method, path  string
+		authorization string
+		userID        string
+		keySubjects   []string
+		want          int
+		wantUser      string
+	}{
+		{"no token", http.MethodGet, "/api/v1/orders", "", "", nil, http.StatusUnauthorized, ""},
+		{"bad token", http.MethodGet, "/api/v1/orders", "Bearer x.y.z", "", nil, http.StatusUnauthorized, ""},
+		{"session fills own id", http.MethodPost, "/api/v1/orders", "Bearer " + session, "", nil, http.StatusOK, claims.Subject},
+		{"session impersonation", http.MethodGet, "/api/v1/orders", "Bearer " + session, "u-2", nil, http.StatusForbidden, ""},
+		{"start session", http.MethodPost, "/api/v1/session", "", "", nil, http.StatusOK, ""},
+		{"user fills own id", http.MethodGet, "/api/v1/orders", sign("u-1", auth.RoleUser), "", nil, http.StatusOK, "u-1"},
+		{"user impersonation", http.MethodGet, "/api/v1/orders", sign("u-1", auth.RoleUser), "u-2", nil, http.StatusForbidden, ""},
+		{"support reads other user", http.MethodGet, "/api/v1/orders", sign("s-1", auth.RoleSupport), "u-2", nil, http.StatusOK, "u-2"},
+		{"support cannot pay", http.MethodPost, "/api/v1/orders/o-1/payments", sign("s-1", auth.RoleSupport), "u-2", nil, http.StatusForbidden, ""},
+		{"support cannot update", http.MethodPatch, "/api/v1/orders/o-1", sign("s-1", auth.RoleSupport), "u-2", nil, http.StatusForbidden, ""},
+		{"user on admin route", http.MethodPost, "/api/v1/admin/api-keys", sign("u-1", auth.RoleUser), "", nil, http.StatusForbidden, ""},
+		{"api key", http.MethodPost, "/api/v1/orders", "", "u-3", []string{"u-3", "u-4"}, http.StatusOK, "u-3"},
+		{"api key without user", http.MethodPost, "/api/v1/orders", "", "", []string{"u-3", "u-4"}, http.StatusBadRequest, ""},
+		{"api key fills its only user", http.MethodPost, "/api/v1/orders", "", "", []string{"u-3"}, http.StatusOK, "u-3"},
+		{"api key for unbound user", http.MethodPost, "/api/v1/orders", "", "u-5", []string{"u-3", "u-4"}, http.StatusForbidden, ""},
+		{"api key without subjects", http.MethodPost, "/api/v1/orders", "", "u-3", []string{}, http.StatusForbidden, ""},
+		{"api key on admin route", http.MethodGet, "/api/v1/admin/usage", "", "u-3", []string{"u-3"}, http.StatusForbidden, ""},
+		{"admin edits api key", http.MethodPatch, "/api/v1/admin/api-keys/k-1", sign("a-1", auth.RoleAdmin), "", nil, http.StatusOK, ""},
+		{"user edits api key", http.MethodPatch, "/api/v1/admin/api-keys/k-1", sign("u-1", auth.RoleUser), "", nil, http.StatusForbidden, ""},
+		{"support reads usage", http.MethodGet, "/api/v1/admin/usage", sign("s-1", auth.RoleSupport), "", nil, http.StatusForbidden, ""},
+		{"unlisted route", http.MethodGet, "/api/v1/admin/secrets", sign("a-1", auth.RoleAdmin), "", nil, http.StatusForbidden, ""},
+		{"unlisted method", http.MethodDelete, "/api/v1/orders", sign("u-1", auth.RoleUser), "", nil, http.StatusForbidden, ""},
+		{"batch checked per item", http.MethodPost, "/api/v1/batch", "", "", nil, http.StatusOK, ""},
+		{"preflight", http.MethodOptions, "/api/v1/orders", "", "", nil, http.StatusOK, ""},
+		{"outside base path", http.MethodGet, "/health", "", "", nil, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		seenUser, seenClaims = "", false
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+		if tt.userID != "" {
+			req.Header.Set("X-User-Id", tt.userID)
+		}
+		if tt.keySubjects != nil {
+			req = req.WithContext(apikey.WithKey(req.Context(), apikey.Key{ID: "k-1", Subjects: tt.keySubjects}))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Fatalf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if seenUser != tt.wantUser {
+			t.Fatalf("%s: X-User-Id = %q, want %q", tt.name, seenUser, tt.wantUser)
+		}
+		if tt.want == http.StatusOK && tt.path != "/health" && tt.path != "/api/v1/session" && tt.path != "/api/v1/batch" && tt.method != http.MethodOptions && !seenClaims {
+			t.Fatalf("%s: no claims in request context", tt.name)
+		}
+	}
+}
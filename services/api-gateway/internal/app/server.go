@@ -0,0 +1,145 @@
+package app
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/config"
+)
+
+// serverMetrics holds the connection gauges of the gateway server and the
+// outcome of its drains: drains, drained_requests (finished during a drain),
+// dropped_requests (cut off when the drain timed out) and last_drain_ms.
+var serverMetrics = expvar.NewMap("http_server")
+
+// drainLogInterval is how often a drain reports what it is still waiting on.
+const drainLogInterval = time.Second
+
+// serverTracker follows the connections and in-flight requests of a server.
+type serverTracker struct {
+	mu       sync.Mutex
+	conns    map[net.Conn]http.ConnState
+	inFlight atomic.Int64
+}
+
+func newServerTracker() *serverTracker {
+	t := &serverTracker{conns: make(map[net.Conn]http.ConnState)}
+	serverMetrics.Set("connections", expvar.Func(func() any { return t.connections(false) }))
+	serverMetrics.Set("active_connections", expvar.Func(func() any { return t.connections(true) }))
+	serverMetrics.Set("requests_in_flight", expvar.Func(func() any { return t.inFlight.Load() }))
+	return t
+}
+
+// connState is the http.Server ConnState hook.
+func (t *serverTracker) connState(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateHijacked, http.StateClosed:
+		delete(t.conns, conn)
+	default:
+		t.conns[conn] = state
+	}
+}
+
+// connections counts the open connections, or only those serving a
+// request when active is set.
+func (t *serverTracker) connections(active bool) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !active {
+		return len(t.conns)
+	}
+	n := 0
+	for _, state := range t.conns {
+		if state == http.StateActive {
+			n++
+		}
+	}
+	return n
+}
+
+// track counts the requests next is serving.
+func (t *serverTracker) track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.inFlight.Add(1)
+		defer t.inFlight.Add(-1)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// newServer builds the gateway server for handler. It speaks HTTP/1.1 and,
+// with cfg.H2C, HTTP/2 without TLS; an HTTP/2 connection carries at most
+// cfg.HTTP2MaxStreams requests at once.
+func newServer(cfg config.Config, handler http.Handler, t *serverTracker) *http.Server {
+	server := &http.Server{
+		Addr:              cfg.HTTPAddr,
+		Handler:           t.track(handler),
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+		MaxHeaderBytes:    cfg.HTTPMaxHeaderBytes,
+		HTTP2:             &http.HTTP2Config{MaxConcurrentStreams: cfg.HTTP2MaxStreams},
+		ConnState:         t.connState,
+	}
+	if cfg.H2C {
+		var protocols http.Protocols
+		protocols.SetHTTP1(true)
+		protocols.SetUnencryptedHTTP2(true)
+		server.Protocols = &protocols
+	}
+	return server
+}
+
+// drain shuts server down gracefully: it stops accepting connections,
+// closes the idle ones and waits up to timeout for the requests in flight.
+// Requests still running at the timeout are dropped with their connections
+// and the timeout error is returned.
+func (t *serverTracker) drain(server *http.Server, timeout time.Duration, logger *slog.Logger) error {
+	start := time.Now()
+	pending := t.inFlight.Load()
+	logger.Info("gateway draining", "connections", t.connections(false), "requests_in_flight", pending, "timeout", timeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown(ctx)
+	}()
+
+	ticker := time.NewTicker(drainLogInterval)
+	defer ticker.Stop()
+	var err error
+	for waiting := true; waiting; {
+		select {
+		case err = <-done:
+			waiting = false
+		case <-ticker.C:
+			logger.Info("gateway draining", "connections", t.connections(false), "requests_in_flight", t.inFlight.Load(), "elapsed", time.Since(start))
+		}
+	}
+
+	var dropped int64
+	if err != nil {
+		dropped = t.inFlight.Load()
+		if closeErr := server.Close(); closeErr != nil {
+			logger.Error("gateway close failed", "err", closeErr)
+		}
+		logger.Warn("gateway drain timed out", "err", err, "dropped_requests", dropped, "timeout", timeout)
+	}
+	serverMetrics.Add("drains", 1)
+	serverMetrics.Add("drained_requests", max(pending-dropped, 0))
+	serverMetrics.Add("dropped_requests", dropped)
+	lastDrain := new(expvar.Int)
+	lastDrain.Set(time.Since(start).Milliseconds())
+	serverMetrics.Set("last_drain_ms", lastDrain)
+	logger.Info("gateway drained", "drained_requests", max(pending-dropped, 0), "dropped_requests", dropped, "duration", time.Since(start))
+	return err
+}
@@ -0,0 +1,183 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/config"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// startTestServer serves handler with newServer on a random port.
+func startTestServer(t *testing.T, cfg config.Config, handler http.Handler) (*http.Server, *serverTracker, string) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	tracker := newServerTracker()
+	server := newServer(cfg, handler, tracker)
+	go func() { _ = server.Serve(ln) }()
+	t.Cleanup(func() { _ = server.Close() })
+	return server, tracker, "http://" + ln.Addr().String()
+}
+
+func TestServerH2C(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, r.Proto)
+	})
+	_, _, url := startTestServer(t, config.Config{H2C: true, HTTP2MaxStreams: 10}, handler)
+
+	var protocols http.Protocols
+	protocols.SetUnencryptedHTTP2(true)
+	client := &http.Client{Transport: &http.Transport{Protocols: &protocols}}
+	resp, err := client.Get(url + "/health")
+	if err != nil {
+		t.Fatalf("h2c request: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if resp.ProtoMajor != 2 || string(body) != "HTTP/2.0" {
+		t.Fatalf("h2c request served over %s (%s), want HTTP/2.0", resp.Proto, body)
+	}
+
+	resp, err = http.Get(url + "/health")
+	if err != nil {
+		t.Fatalf("http/1.1 request: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.ProtoMajor != 1 {
+		t.Fatalf("plain request served over %s, want HTTP/1.1", resp.Proto)
+	}
+}
+
+func TestServerDrain(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+		w.WriteHeader(http.StatusNoContent)
+	})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	// drain shuts a server down while one request is in flight; finish
+	// releases the request during the drain.
+	drain := func(timeout time.Duration, finish bool) (int, error) {
+		server, tracker, url := startTestServer(t, config.Config{}, handler)
+		status := make(chan int, 1)
+		go func() {
+			resp, err := http.Get(url)
+			if err != nil {
+				status <- 0
+				return
+			}
+			_ = resp.Body.Close()
+			status <- resp.StatusCode
+		}()
+		<-started
+		if tracker.inFlight.Load() != 1 {
+			t.Fatalf("requests_in_flight = %d, want 1", tracker.inFlight.Load())
+		}
+		if finish {
+			time.AfterFunc(50*time.Millisecond, func() { release <- struct{}{} })
+		}
+		err := tracker.drain(server, timeout, logger)
+		return <-status, err
+	}
+
+	drained, dropped := serverCount("drained_requests"), serverCount("dropped_requests")
+	code, err := drain(5*time.Second, true)
+	if err != nil || code != http.StatusNoContent {
+		t.Fatalf("drain = %v, request = %d; want the request in flight to finish", err, code)
+	}
+	if got := serverCount("drained_requests") - drained; got != 1 {
+		t.Fatalf("drained_requests grew by %d, want 1", got)
+	}
+
+	code, err = drain(50*time.Millisecond, false)
+	close(release)
+	if !errors.Is(err, context.DeadlineExceeded) || code != 0 {
+		t.Fatalf("drain = %v, request = %d; want a timeout that drops the request", err, code)
+	}
+	if got := serverCount("dropped_requests") - dropped; got != 1 {
+		t.Fatalf("dropped_requests grew by %d, want 1", got)
+	}
+}
+
+// waitingOrders holds every WaitOrder until its context ends; the order
+// itself stays NEW.
+type waitingOrders struct {
+	ordersv1.OrdersServiceClient
+	waiting chan struct{}
+}
+
+func (o waitingOrders) WaitOrder(ctx context.Context, _ *ordersv1.WaitOrderRequest, _ ...grpc.CallOption) (*ordersv1.WaitOrderResponse, error) {
+	o.waiting <- struct{}{}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (waitingOrders) GetOrder(_ context.Context, in *ordersv1.GetOrderRequest, _ ...grpc.CallOption) (*ordersv1.GetOrderResponse, error) {
+	return &ordersv1.GetOrderResponse{Order: &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Status: ordersv1.OrderStatus_ORDER_STATUS_NEW}}, nil
+}
+
+// TestServerDrainEndsWaits checks that a drain does not sit out a long poll:
+// the wait in flight answers timed out as soon as the shutdown starts.
+func TestServerDrainEndsWaits(t *testing.T) {
+	orders := waitingOrders{waiting: make(chan struct{}, 1)}
+	h := handler.New(orders, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	server, tracker, url := startTestServer(t, config.Config{}, gateway.HandlerWithOptions(h, gateway.ChiServerOptions{BaseURL: "/api/v1"}))
+	server.RegisterOnShutdown(h.Drain)
+
+	type result struct {
+		code int
+		resp gateway.WaitOrderResponse
+	}
+	done := make(chan result, 1)
+	go func() {
+		req, _ := http.NewRequest(http.MethodGet, url+"/api/v1/orders/o-1/wait?timeout=60s", nil)
+		req.Header.Set("X-User-Id", "u-1")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			done <- result{}
+			return
+		}
+		defer resp.Body.Close()
+		var res result
+		res.code = resp.StatusCode
+		_ = json.NewDecoder(resp.Body).Decode(&res.resp)
+		done <- res
+	}()
+	<-orders.waiting
+
+	start := time.Now()
+	err := tracker.drain(server, 5*time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res := <-done
+	if err != nil || time.Since(start) > time.Second {
+		t.Fatalf("drain = %v after %s; want the wait to end at once", err, time.Since(start))
+	}
+	if res.code != http.StatusOK || !res.resp.TimedOut || res.resp.Order.Status != gateway.OrderStatusNEW {
+		t.Fatalf("wait = %d %+v; want 200, NEW, timed out", res.code, res.resp)
+	}
+}
+
+func serverCount(name string) int64 {
+	if v, ok := serverMetrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
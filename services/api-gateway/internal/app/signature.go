@@ -0,0 +1,69 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+// maxSignedBody caps how much of the body is buffered for verification.
+const maxSignedBody = 1 << 20
+
+// signedRoute reports whether path (relative to the base path) moves money or
+// manages credentials and therefore needs a request signature.
+func signedRoute(method, path string) bool {
+	switch {
+	case strings.HasPrefix(path, "/admin/"):
+		return true
+	case method != http.MethodPost:
+		return false
+	case path == "/payments/account/topup":
+		return true
+	case strings.HasPrefix(path, "/orders/") && strings.HasSuffix(path, "/payments"):
+		return true
+	}
+	return false
+}
+
+// requireSignature verifies X-Signature over method, path and body on the
+// routes picked by signedRoute.
+func requireSignature(keys *signature.Keyring, tolerance time.Duration, basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "signature")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, underBase := strings.CutPrefix(r.URL.Path, basePath)
+			if !underBase || !signedRoute(r.Method, path) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			userID := r.Header.Get("X-User-Id")
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSignedBody))
+			if err != nil {
+				handler.WriteError(w, userID, http.StatusRequestEntityTooLarge, "request body too large")
+				return
+			}
+			r.Body = io.NopCloser(bytes.NewReader(body))
+
+			err = keys.Verify(r.Header.Get(signature.Header), signature.RequestPayload(r.Method, path, body), time.Now(), tolerance)
+			if err != nil {
+				logger.Warn("request signature rejected", "err", err, "method", r.Method, "path", path, "user_id", userID)
+				code := http.StatusUnauthorized
+				if errors.Is(err, signature.ErrMalformed) {
+					code = http.StatusBadRequest
+				}
+				handler.WriteError(w, userID, code, err.Error())
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
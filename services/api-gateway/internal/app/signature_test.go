@@ -0,0 +1,57 @@
+package app
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+func TestRequireSignature(t *testing.T) {
+	keys, err := signature.ParseKeyring("k1:secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var seenBody string
+	h := requireSignature(keys, time.Minute, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		seenBody = string(b)
+	}))
+
+	body := `{"amount":100}`
+	signed := keys.Sign(signature.RequestPayload(http.MethodPost, "/payments/account/topup", []byte(body)), time.Now())
+
+	tests := []struct {
+		name         string
+		method, path string
+		sig          string
+		want         int
+	}{
+		{"unsigned read", http.MethodGet, "/api/v1/orders", "", http.StatusOK},
+		{"unsigned create order", http.MethodPost, "/api/v1/orders", "", http.StatusOK},
+		{"missing signature", http.MethodPost, "/api/v1/payments/account/topup", "", http.StatusUnauthorized},
+		{"malformed signature", http.MethodPost, "/api/v1/payments/account/topup", "junk", http.StatusBadRequest},
+		{"signature for another route", http.MethodPost, "/api/v1/orders/o-1/payments", signed, http.StatusUnauthorized},
+		{"admin route", http.MethodDelete, "/api/v1/admin/api-keys/k", "", http.StatusUnauthorized},
+		{"valid", http.MethodPost, "/api/v1/payments/account/topup", signed, http.StatusOK},
+	}
+	for _, tt := range tests {
+		seenBody = ""
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(body))
+		if tt.sig != "" {
+			req.Header.Set(signature.Header, tt.sig)
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Fatalf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && seenBody != body {
+			t.Fatalf("%s: handler saw body %q, want %q", tt.name, seenBody, body)
+		}
+	}
+}
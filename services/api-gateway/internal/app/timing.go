@@ -0,0 +1,105 @@
+package app
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/timing"
+)
+
+// debugTimingHeader asks for the Server-Timing breakdown of a request.
+const debugTimingHeader = "X-Debug-Timing"
+
+// debugTiming answers a request carrying X-Debug-Timing: true with a
+// Server-Timing header: one grpc entry per backend call, serialize (writing
+// the response body), gateway (the rest of the gateway's own time) and
+// total. Only admins get it, by role or X-Admin-Token, unless open is set
+// for development; other callers are served as if they had not asked.
+//
+// The response of a timed request is buffered so that its header can carry
+// the serialization time.
+func debugTiming(adminToken string, open bool) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "timing")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if want, _ := strconv.ParseBool(r.Header.Get(debugTimingHeader)); !want {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !open && !timingAllowed(r, adminToken) {
+				logger.DebugContext(r.Context(), "debug timing refused", "path", r.URL.Path)
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			start := time.Now()
+			ctx, rec := timing.With(r.Context())
+			tw := &timingWriter{ResponseWriter: w}
+			next.ServeHTTP(tw, r.WithContext(ctx))
+			total := time.Since(start)
+
+			var serialize time.Duration
+			if !tw.headerAt.IsZero() && tw.lastWriteAt.After(tw.headerAt) {
+				serialize = tw.lastWriteAt.Sub(tw.headerAt)
+			}
+			entries := rec.Entries()
+			gateway := max(total-rec.Total("grpc")-serialize, 0)
+			entries = append(entries,
+				timing.Entry{Name: "serialize", Dur: serialize},
+				timing.Entry{Name: "gateway", Dur: gateway},
+				timing.Entry{Name: "total", Dur: total},
+			)
+			w.Header().Add("Server-Timing", timing.Header(entries))
+			tw.flush()
+		})
+	}
+}
+
+// timingAllowed reports whether r comes from an admin.
+func timingAllowed(r *http.Request, adminToken string) bool {
+	if c, ok := auth.FromContext(r.Context()); ok && c.HasAny([]auth.Role{auth.RoleAdmin}) {
+		return true
+	}
+	token := r.Header.Get("X-Admin-Token")
+	return adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// timingWriter holds the response back until its Server-Timing header is
+// known and notes when the status and the body were written.
+type timingWriter struct {
+	http.ResponseWriter
+	status      int
+	headerAt    time.Time
+	lastWriteAt time.Time
+	body        bytes.Buffer
+}
+
+func (w *timingWriter) WriteHeader(code int) {
+	if w.status == 0 {
+		w.status = code
+		w.headerAt = time.Now()
+	}
+}
+
+func (w *timingWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.body.Write(b)
+	w.lastWriteAt = time.Now()
+	return n, err
+}
+
+// flush sends the held response.
+func (w *timingWriter) flush() {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	_, _ = w.ResponseWriter.Write(w.body.Bytes())
+}
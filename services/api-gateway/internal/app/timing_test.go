@@ -0,0 +1,66 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/timing"
+)
+
+func TestDebugTiming(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timing.FromContext(r.Context()).Add("grpc", "orders.v1.OrdersService/GetOrder", 5*time.Millisecond)
+		writeTestJSON(w, http.StatusCreated, map[string]string{"order_id": "o-1"})
+	})
+
+	tests := []struct {
+		name   string
+		open   bool
+		header string
+		admin  string
+		role   auth.Role
+		want   bool
+	}{
+		{"not asked", false, "", "secret", "", false},
+		{"not an admin", false, "true", "", auth.RoleUser, false},
+		{"wrong admin token", false, "1", "nope", "", false},
+		{"admin token", false, "1", "secret", "", true},
+		{"admin role", false, "true", "", auth.RoleAdmin, true},
+		{"open for development", true, "true", "", "", true},
+		{"open but not asked", true, "false", "", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1", nil)
+		if tt.header != "" {
+			req.Header.Set(debugTimingHeader, tt.header)
+		}
+		if tt.admin != "" {
+			req.Header.Set("X-Admin-Token", tt.admin)
+		}
+		if tt.role != "" {
+			req = req.WithContext(auth.WithClaims(req.Context(), auth.Claims{Subject: "a-1", Roles: []auth.Role{tt.role}}, "token"))
+		}
+		rec := httptest.NewRecorder()
+		debugTiming("secret", tt.open)(handler).ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusCreated || !strings.Contains(rec.Body.String(), `"order_id":"o-1"`) {
+			t.Fatalf("%s: response = %d %s, want the handler's", tt.name, rec.Code, rec.Body)
+		}
+		got := rec.Header().Get("Server-Timing")
+		if !tt.want {
+			if got != "" {
+				t.Fatalf("%s: Server-Timing = %q, want none", tt.name, got)
+			}
+			continue
+		}
+		for _, part := range []string{`grpc;desc="orders.v1.OrdersService/GetOrder";dur=5`, "serialize;dur=", "gateway;dur=", "total;dur="} {
+			if !strings.Contains(got, part) {
+				t.Fatalf("%s: Server-Timing = %q, want %q in it", tt.name, got, part)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"errors"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
+)
+
+// meterUsage counts the calls made with an API key and rejects them with 429
+// once the key's monthly quota is used up; write calls are also rejected
+// once its volume quota is. It runs after apiKeyAuth, which puts the key in
+// the context. When the counters cannot be reached the call goes through
+// uncounted, so metering never takes the API down.
+func meterUsage(meter *usage.Meter, basePath string) func(http.Handler) http.Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "usage")
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key, ok := apikey.FromContext(r.Context())
+			path, underBase := strings.CutPrefix(r.URL.Path, basePath)
+			if !ok || !underBase {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			scope := apikey.RequiredScope(r.Method, path)
+			moving := scope == apikey.ScopeOrdersWrite || scope == apikey.ScopePaymentsWrite
+			quota := usage.Quota{Calls: key.MonthlyCallQuota, Volume: key.MonthlyVolumeQuota}
+			resetAt, err := meter.Take(r.Context(), key.ID, quota, moving)
+			switch {
+			case err == nil:
+				next.ServeHTTP(w, r.WithContext(meter.Bind(r.Context(), key.ID)))
+			case errors.Is(err, usage.ErrCallQuota), errors.Is(err, usage.ErrVolumeQuota):
+				logger.Warn("api key over quota", "key_id", key.ID, "err", err)
+				retry := int64(math.Ceil(time.Until(resetAt).Seconds()))
+				w.Header().Set("Retry-After", strconv.FormatInt(max(retry, 1), 10))
+				handler.WriteError(w, r.Header.Get("X-User-Id"), http.StatusTooManyRequests, err.Error())
+			default:
+				logger.Error("usage metering failed, call not counted", "err", err, "key_id", key.ID)
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
+)
+
+type countingUsage struct {
+	mu    sync.Mutex
+	calls map[string]int64
+	err   error
+}
+
+func (c *countingUsage) Take(_ context.Context, _, keyID string, quota usage.Quota, moving bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	if quota.Calls > 0 && c.calls[keyID] >= quota.Calls {
+		return usage.ErrCallQuota
+	}
+	if moving && quota.Volume > 0 {
+		return usage.ErrVolumeQuota
+	}
+	c.calls[keyID]++
+	return nil
+}
+
+func (c *countingUsage) AddVolume(context.Context, string, string, int64) error { return nil }
+
+func (c *countingUsage) Snapshot(context.Context, string) (map[string]usage.Usage, error) {
+	return nil, nil
+}
+
+type discardUsage struct{}
+
+func (discardUsage) Save(context.Context, string, map[string]usage.Usage) error { return nil }
+func (discardUsage) List(context.Context, string, string) ([]usage.Record, error) {
+	return nil, nil
+}
+
+func TestMeterUsage(t *testing.T) {
+	counters := &countingUsage{calls: map[string]int64{}}
+	meter := usage.NewMeter(counters, discardUsage{}, time.Hour)
+	defer meter.Close()
+	h := meterUsage(meter, "/api/v1")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	serve := func(method, path string, key *apikey.Key) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != nil {
+			req = req.WithContext(apikey.WithKey(req.Context(), *key))
+		}
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Calls without a key are not metered.
+	for i := 0; i < 3; i++ {
+		if rec := serve(http.MethodGet, "/api/v1/orders", nil); rec.Code != http.StatusNoContent {
+			t.Fatalf("anonymous call status = %d", rec.Code)
+		}
+	}
+
+	key := &apikey.Key{ID: "k-1", MonthlyCallQuota: 1, MonthlyVolumeQuota: 100}
+	if rec := serve(http.MethodPost, "/api/v1/orders", key); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("write over volume quota status = %d, want 429", rec.Code)
+	}
+	if rec := serve(http.MethodGet, "/api/v1/orders", key); rec.Code != http.StatusNoContent {
+		t.Fatalf("read status = %d, want 204", rec.Code)
+	}
+	rec := serve(http.MethodGet, "/api/v1/orders", key)
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("read over call quota status = %d, want 429", rec.Code)
+	}
+	if retry, err := strconv.Atoi(rec.Header().Get("Retry-After")); err != nil || retry < 1 || retry > 31*24*3600 {
+		t.Fatalf("Retry-After = %q, want seconds until the month ends", rec.Header().Get("Retry-After"))
+	}
+	if counters.calls["k-1"] != 1 {
+		t.Fatalf("counted calls = %d, want 1", counters.calls["k-1"])
+	}
+
+	// An unreachable counter store lets calls through.
+	counters.err = errors.New("redis down")
+	if rec := serve(http.MethodPost, "/api/v1/orders", key); rec.Code != http.StatusNoContent {
+		t.Fatalf("call with counters down status = %d, want 204", rec.Code)
+	}
+}
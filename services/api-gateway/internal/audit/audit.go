@@ -0,0 +1,150 @@
+// Package audit records gateway API calls asynchronously.
+package audit
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+// Entry is one recorded API call.
+type Entry struct {
+	OccurredAt time.Time
+	Method     string
+	Path       string
+	UserID     string
+	// UserVerified is false when UserID is the unchecked X-User-Id of a call
+	// the gateway did not authenticate.
+	UserVerified   bool
+	IdempotencyKey string
+	Status         int
+	Latency        time.Duration
+}
+
+// Store persists entries and reads them back for the admin API.
+type Store interface {
+	Insert(ctx context.Context, entries []Entry) error
+	Recent(ctx context.Context, userID string, limit int) ([]Entry, error)
+}
+
+type Options struct {
+	// SampleRate is the share of successful calls that are recorded; calls
+	// answered with 4xx/5xx are always recorded.
+	SampleRate    float64
+	BufferSize    int
+	BatchSize     int
+	FlushInterval time.Duration
+}
+
+var stats = expvar.NewMap("audit_log")
+
+// Logger buffers entries in memory and writes them in batches from a single
+// goroutine, so request latency does not depend on the store. When the
+// buffer is full new entries are dropped.
+type Logger struct {
+	store Store
+	opts  Options
+	rand  func() float64
+
+	mu     sync.RWMutex
+	closed bool
+	ch     chan Entry
+	done   chan struct{}
+
+	logger *slog.Logger
+}
+
+func NewLogger(store Store, opts Options) *Logger {
+	if opts.BufferSize <= 0 {
+		opts.BufferSize = 1024
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = time.Second
+	}
+	l := &Logger{
+		store:  store,
+		opts:   opts,
+		rand:   rand.Float64,
+		ch:     make(chan Entry, opts.BufferSize),
+		done:   make(chan struct{}),
+		logger: slog.Default().With("service", "api-gateway", "component", "audit"),
+	}
+	go l.run()
+	return l
+}
+
+// Record queues e unless it is sampled out or the buffer is full.
+func (l *Logger) Record(e Entry) {
+	if e.Status < 400 && l.rand() >= l.opts.SampleRate {
+		stats.Add("sampled_out", 1)
+		return
+	}
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.closed {
+		return
+	}
+	select {
+	case l.ch <- e:
+		stats.Add("queued", 1)
+	default:
+		stats.Add("dropped", 1)
+	}
+}
+
+// Close stops accepting entries and flushes what is buffered.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.ch)
+	}
+	l.mu.Unlock()
+	<-l.done
+}
+
+func (l *Logger) run() {
+	defer close(l.done)
+	ticker := time.NewTicker(l.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Entry, 0, l.opts.BatchSize)
+	for {
+		select {
+		case e, ok := <-l.ch:
+			if !ok {
+				l.flush(batch)
+				return
+			}
+			batch = append(batch, e)
+			if len(batch) >= l.opts.BatchSize {
+				l.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			l.flush(batch)
+			batch = batch[:0]
+		}
+	}
+}
+
+func (l *Logger) flush(batch []Entry) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := l.store.Insert(ctx, batch); err != nil {
+		stats.Add("flush_errors", 1)
+		stats.Add("lost", int64(len(batch)))
+		l.logger.Error("audit flush failed", "err", err, "entries", len(batch))
+		return
+	}
+	stats.Add("written", int64(len(batch)))
+}
@@ -0,0 +1,85 @@
+package audit
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+type memStore struct {
+	mu      sync.Mutex
+	batches [][]Entry
+}
+
+func (s *memStore) Insert(_ context.Context, entries []Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, append([]Entry(nil), entries...))
+	return nil
+}
+
+func (s *memStore) Recent(context.Context, string, int) ([]Entry, error) {
+	return nil, nil
+}
+
+func (s *memStore) entries() []Entry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Entry
+	for _, b := range s.batches {
+		out = append(out, b...)
+	}
+	return out
+}
+
+func TestLoggerBatchesAndFlushesOnClose(t *testing.T) {
+	store := &memStore{}
+	l := NewLogger(store, Options{SampleRate: 1, BatchSize: 2, FlushInterval: time.Hour})
+	for i := 0; i < 5; i++ {
+		l.Record(Entry{Path: "/api/v1/orders", Status: 200})
+	}
+	l.Close()
+
+	if got := len(store.entries()); got != 5 {
+		t.Fatalf("written entries = %d, want 5", got)
+	}
+	if got := len(store.batches); got != 3 {
+		t.Fatalf("batches = %d, want 3 (2+2+1)", got)
+	}
+	l.Record(Entry{Status: 500})
+	if got := len(store.entries()); got != 5 {
+		t.Fatalf("entries after Close = %d, want 5", got)
+	}
+}
+
+func TestLoggerSampling(t *testing.T) {
+	store := &memStore{}
+	l := NewLogger(store, Options{SampleRate: 0.5, FlushInterval: time.Hour})
+	l.rand = func() float64 { return 0.7 }
+	l.Record(Entry{Path: "ok", Status: 200})
+	l.Record(Entry{Path: "denied", Status: 403})
+	l.rand = func() float64 { return 0.2 }
+	l.Record(Entry{Path: "sampled", Status: 201})
+	l.Close()
+
+	got := store.entries()
+	if len(got) != 2 || got[0].Path != "denied" || got[1].Path != "sampled" {
+		t.Fatalf("entries = %+v, want denied and sampled", got)
+	}
+}
+
+func TestLoggerFlushesOnInterval(t *testing.T) {
+	store := &memStore{}
+	l := NewLogger(store, Options{SampleRate: 1, FlushInterval: 10 * time.Millisecond})
+	defer l.Close()
+	l.Record(Entry{Status: 200})
+
+	deadline := time.Now().Add(time.Second)
+	for len(store.entries()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("entry was not flushed by the ticker")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
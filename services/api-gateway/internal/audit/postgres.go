@@ -0,0 +1,57 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+var auditColumns = []string{"occurred_at", "method", "path", "user_id", "user_verified", "idempotency_key", "status", "latency_ms"}
+
+func (s *PostgresStore) Insert(ctx context.Context, entries []Entry) error {
+	_, err := s.pool.CopyFrom(ctx, pgx.Identifier{"gateway_audit_log"}, auditColumns,
+		pgx.CopyFromSlice(len(entries), func(i int) ([]any, error) {
+			e := entries[i]
+			return []any{e.OccurredAt, e.Method, e.Path, e.UserID, e.UserVerified, e.IdempotencyKey, int32(e.Status), e.Latency.Milliseconds()}, nil
+		}))
+	return err
+}
+
+func (s *PostgresStore) Recent(ctx context.Context, userID string, limit int) ([]Entry, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT occurred_at, method, path, user_id, user_verified, idempotency_key, status, latency_ms
+FROM gateway_audit_log
+WHERE user_id = $1
+ORDER BY occurred_at DESC
+LIMIT $2`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Entry
+	for rows.Next() {
+		var (
+			e         Entry
+			status    int32
+			latencyMs int64
+		)
+		if err := rows.Scan(&e.OccurredAt, &e.Method, &e.Path, &e.UserID, &e.UserVerified, &e.IdempotencyKey, &status, &latencyMs); err != nil {
+			return nil, err
+		}
+		e.Status = int(status)
+		e.Latency = time.Duration(latencyMs) * time.Millisecond
+		out = append(out, e)
+	}
+	return out, rows.Err()
+}
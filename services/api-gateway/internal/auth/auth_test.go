@@ -0,0 +1,135 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+func TestRequiredRoles(t *testing.T) {
+	tests := []struct {
+		method, path string
+		want         []Role
+		ok           bool
+	}{
+		{http.MethodGet, "/orders", []Role{RoleUser, RoleSupport, RoleAdmin}, true},
+		{http.MethodPost, "/orders:validate", []Role{RoleUser, RoleAdmin}, true},
+		{http.MethodPost, "/orders/123/payments", []Role{RoleUser, RoleAdmin}, true},
+		{http.MethodGet, "/orders/123/wait", []Role{RoleUser, RoleSupport, RoleAdmin}, true},
+		{http.MethodPost, "/orders/123/transfer/accept", []Role{RoleUser, RoleAdmin}, true},
+		{http.MethodGet, "/payments/account/balance", []Role{RoleUser, RoleSupport, RoleAdmin}, true},
+		{http.MethodGet, "/rates", []Role{RoleUser, RoleSupport, RoleAdmin}, true},
+		{http.MethodPut, "/notifications/preferences", []Role{RoleUser, RoleAdmin}, true},
+		{http.MethodPost, "/graphql", []Role{RoleUser, RoleSupport, RoleAdmin}, true},
+		{http.MethodDelete, "/admin/api-keys/k-1", []Role{RoleAdmin}, true},
+		{http.MethodPatch, "/admin/api-keys/k-1", []Role{RoleAdmin}, true},
+		{http.MethodGet, "/admin/usage", []Role{RoleAdmin}, true},
+		{http.MethodPost, "/orders//payments", nil, false},
+		{http.MethodGet, "/unknown", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := RequiredRoles(tt.method, tt.path)
+		if ok != tt.ok || len(got) != len(tt.want) {
+			t.Fatalf("RequiredRoles(%s, %s) = %v, %v; want %v, %v", tt.method, tt.path, got, ok, tt.want, tt.ok)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("RequiredRoles(%s, %s) = %v, want %v", tt.method, tt.path, got, tt.want)
+			}
+		}
+	}
+}
+
+// TestRouteRolesCoverSpec fails when a route of the OpenAPI spec has no
+// roles: the gateway would deny it to everyone.
+func TestRouteRolesCoverSpec(t *testing.T) {
+	spec, err := gateway.GetSwagger()
+	if err != nil {
+		t.Fatalf("GetSwagger() error: %v", err)
+	}
+	param := regexp.MustCompile(`\{[^}]+\}`)
+	for path, item := range spec.Paths.Map() {
+		if path == "/session" {
+			continue
+		}
+		for method := range item.Operations() {
+			if _, ok := RequiredRoles(method, param.ReplaceAllString(path, "x")); !ok {
+				t.Errorf("%s %s has no roles", method, path)
+			}
+		}
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	secret := []byte("s")
+	token, err := Sign(Claims{Subject: "u-1", Role: RoleAdmin, ExpiresAt: time.Now().Add(time.Minute).Unix()}, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	c, err := Verify(token, secret, time.Now())
+	if err != nil || c.Subject != "u-1" || !c.Privileged() {
+		t.Fatalf("Verify() = %+v, %v; want admin u-1", c, err)
+	}
+	if _, err := Verify(token+"x", secret, time.Now()); err == nil {
+		t.Fatal("Verify(tampered) expected error")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	secret := []byte("s")
+	now := time.Now()
+	sessions := NewSessions(secret, time.Hour)
+	token, c, err := sessions.Issue(now)
+	if err != nil {
+		t.Fatalf("Issue() error: %v", err)
+	}
+	got, err := Verify(token, secret, now)
+	if err != nil || got.Subject == "" || got.Subject != c.Subject || !got.Anonymous || !got.HasAny([]Role{RoleUser}) || got.Privileged() {
+		t.Fatalf("Verify(session) = %+v, %v; want anonymous user", got, err)
+	}
+
+	renewed, rc, err := sessions.Renew(token, now.Add(30*time.Minute))
+	if err != nil || rc.Subject != c.Subject || rc.ExpiresAt <= c.ExpiresAt {
+		t.Fatalf("Renew() = %+v, %v; want %s with a later expiry", rc, err, c.Subject)
+	}
+	if _, err := Verify(renewed, secret, now.Add(80*time.Minute)); err != nil {
+		t.Fatalf("Verify(renewed) error: %v", err)
+	}
+	if _, _, err := sessions.Renew(token, now.Add(2*time.Hour)); err == nil {
+		t.Fatal("Renew(expired) expected error")
+	}
+
+	user, err := Sign(Claims{Subject: "u-1", Role: RoleUser, ExpiresAt: now.Add(time.Minute).Unix()}, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	if _, _, err := sessions.Renew(user, now); err == nil {
+		t.Fatal("Renew(user jwt) expected error")
+	}
+}
+
+func TestForwardToken(t *testing.T) {
+	var got []string
+	invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		got = md.Get("authorization")
+		return nil
+	}
+
+	_ = ForwardToken()(context.Background(), "/m", nil, nil, nil, invoker)
+	if len(got) != 0 {
+		t.Fatalf("authorization without claims = %v, want none", got)
+	}
+	ctx := WithClaims(context.Background(), Claims{Subject: "u-1"}, "tok")
+	_ = ForwardToken()(ctx, "/m", nil, nil, nil, invoker)
+	if len(got) != 1 || got[0] != "Bearer tok" {
+		t.Fatalf("authorization = %v, want [Bearer tok]", got)
+	}
+}
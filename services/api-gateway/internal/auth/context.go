@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+type ctxKey struct{}
+
+type principal struct {
+	claims Claims
+	token  string
+}
+
+// WithClaims stores the verified claims and the token they came from in ctx.
+func WithClaims(ctx context.Context, c Claims, token string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, principal{claims: c, token: token})
+}
+
+// FromContext returns the claims of the authorized request, if any.
+func FromContext(ctx context.Context) (Claims, bool) {
+	p, ok := ctx.Value(ctxKey{}).(principal)
+	return p.claims, ok
+}
+
+// ForwardToken passes the request's token to the services as the
+// authorization metadata so that their interceptors apply the same roles.
+func ForwardToken() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if p, ok := ctx.Value(ctxKey{}).(principal); ok && p.token != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+p.token)
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
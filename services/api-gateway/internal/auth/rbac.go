@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"net/http"
+	"strings"
+)
+
+type routeRule struct {
+	method  string
+	pattern string
+	roles   []Role
+}
+
+// routeRoles lists the roles allowed on each route, relative to the base
+// path. A {name} segment matches any single path segment. Keep in sync with
+// the RPC tables in orders-service and payments-service.
+var routeRoles = []routeRule{
+	{http.MethodPost, "/orders", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders:validate", []Role{RoleUser, RoleAdmin}},
+	{http.MethodGet, "/orders", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodGet, "/orders/{orderId}", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodGet, "/orders/{orderId}/wait", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodGet, "/orders/{orderId}/receipt", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodPatch, "/orders/{orderId}", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/payments", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/transfer", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/transfer/accept", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/cancel", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/retry-payment", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/orders/{orderId}/confirm-payment", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/payments/account", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/payments/account/topup", []Role{RoleUser, RoleAdmin}},
+	{http.MethodGet, "/payments/account/balance", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodGet, "/rates", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodGet, "/notifications/preferences", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodPut, "/notifications/preferences", []Role{RoleUser, RoleAdmin}},
+	{http.MethodPost, "/graphql", []Role{RoleUser, RoleSupport, RoleAdmin}},
+	{http.MethodPost, "/admin/api-keys", []Role{RoleAdmin}},
+	{http.MethodPatch, "/admin/api-keys/{keyId}", []Role{RoleAdmin}},
+	{http.MethodDelete, "/admin/api-keys/{keyId}", []Role{RoleAdmin}},
+	{http.MethodGet, "/admin/usage", []Role{RoleAdmin}},
+	{http.MethodGet, "/admin/audit/users/{userId}", []Role{RoleAdmin}},
+	{http.MethodGet, "/support/users/{userId}/balance", []Role{RoleSupport, RoleAdmin}},
+}
+
+// RequiredRoles returns the roles allowed to call method on path. ok is false
+// for routes not in the table; the gateway denies those.
+func RequiredRoles(method, path string) (roles []Role, ok bool) {
+	for _, rule := range routeRoles {
+		if rule.method == method && matchPattern(rule.pattern, path) {
+			return rule.roles, true
+		}
+	}
+	return nil, false
+}
+
+func matchPattern(pattern, path string) bool {
+	want := strings.Split(strings.Trim(pattern, "/"), "/")
+	got := strings.Split(strings.Trim(path, "/"), "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		if strings.HasPrefix(want[i], "{") {
+			if got[i] == "" {
+				return false
+			}
+			continue
+		}
+		if want[i] != got[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Sessions issues anonymous session tokens: user-role JWTs for a user id
+// minted by the gateway, signed with the secret the services verify, so an
+// unauthenticated client gets one stable identity instead of a new one per
+// request.
+type Sessions struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+// NewSessions returns an issuer of tokens valid for ttl.
+func NewSessions(secret []byte, ttl time.Duration) *Sessions {
+	return &Sessions{secret: secret, ttl: ttl}
+}
+
+// Issue starts a session for a new user id.
+func (s *Sessions) Issue(now time.Time) (string, Claims, error) {
+	return s.sign(uuid.NewString(), now)
+}
+
+// Renew extends the session of token, keeping its user id. Only valid,
+// unexpired session tokens can be renewed; anything else, including regular
+// user JWTs, is ErrInvalidToken.
+func (s *Sessions) Renew(token string, now time.Time) (string, Claims, error) {
+	c, err := Verify(token, s.secret, now)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	if !c.Anonymous || c.Subject == "" {
+		return "", Claims{}, ErrInvalidToken
+	}
+	return s.sign(c.Subject, now)
+}
+
+func (s *Sessions) sign(userID string, now time.Time) (string, Claims, error) {
+	c := Claims{Subject: userID, Roles: []Role{RoleUser}, ExpiresAt: now.Add(s.ttl).Unix(), Anonymous: true}
+	token, err := Sign(c, s.secret)
+	if err != nil {
+		return "", Claims{}, err
+	}
+	return token, c, nil
+}
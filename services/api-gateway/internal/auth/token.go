@@ -0,0 +1,31 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+)
+
+// The token format and roles are shared with the other services (pkg/jwt).
+type (
+	Role   = jwt.Role
+	Claims = jwt.Claims
+)
+
+const (
+	RoleUser    = jwt.RoleUser
+	RoleSupport = jwt.RoleSupport
+	RoleAdmin   = jwt.RoleAdmin
+)
+
+var ErrInvalidToken = jwt.ErrInvalidToken
+
+// Verify checks an HS256 token signed with secret and returns its claims.
+func Verify(token string, secret []byte, now time.Time) (Claims, error) {
+	return jwt.Verify(token, secret, now)
+}
+
+// Sign issues an HS256 token for c.
+func Sign(c Claims, secret []byte) (string, error) {
+	return jwt.Sign(c, secret)
+}
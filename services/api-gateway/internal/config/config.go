@@ -1,20 +1,189 @@
 package config
 
-import "os"
+import (
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
 
 type Config struct {
-	HTTPAddr         string
-	BasePath         string
+	HTTPAddr string
+	BasePath string
+	// HTTPReadTimeout, HTTPWriteTimeout and HTTPIdleTimeout bound reading a
+	// request, writing its response and keeping an idle connection open;
+	// 0 means no limit. HTTPWriteTimeout must cover the longest wait of
+	// GET /orders/{orderId}/wait.
+	HTTPReadTimeout  time.Duration
+	HTTPWriteTimeout time.Duration
+	HTTPIdleTimeout  time.Duration
+	// HTTPMaxHeaderBytes caps the request headers and HTTP2MaxStreams the
+	// concurrent requests of one HTTP/2 connection.
+	HTTPMaxHeaderBytes int
+	HTTP2MaxStreams    int
+	// H2C serves HTTP/2 without TLS next to HTTP/1.1, for clients and
+	// proxies that speak it with prior knowledge.
+	H2C bool
+	// ShutdownTimeout is how long open requests may run after a shutdown
+	// signal before their connections are closed.
+	ShutdownTimeout time.Duration
+	// MetricsAddr serves the expvar metrics at /debug/vars; empty disables
+	// it.
+	MetricsAddr      string
 	OrdersGRPCAddr   string
 	PaymentsGRPCAddr string
+	// NotificationsGRPCAddr enables the notification preferences API;
+	// empty answers it with 503.
+	NotificationsGRPCAddr string
+
+	LogLevel  slog.Level
+	LogFormat string
+	// LogRedact hides personal data in logs: hash, mask or off. off is
+	// honoured only with LogDev. LogRedactFields overrides the redacted
+	// attribute keys; LogRedactSalt keys the hash.
+	LogRedact       string
+	LogRedactFields []string
+	LogRedactSalt   string
+	LogDev          bool
+
+	// StartupWaitTimeout bounds the wait for the dependencies at startup
+	// (0 starts right away); StartupCheckTimeout caps one check of one
+	// dependency.
+	StartupWaitTimeout  time.Duration
+	StartupCheckTimeout time.Duration
+
+	RequestBudget time.Duration
+	HedgeDelay    time.Duration
+	// BatchMaxRequests caps the sub-requests of one POST /batch, which all
+	// share one RequestBudget; 0 disables the endpoint.
+	BatchMaxRequests int
+	// GraphQLComplexityLimit rejects GraphQL queries selecting more fields;
+	// 0 disables the check. GraphQLIntrospection serves the schema to
+	// __schema and __type queries.
+	GraphQLComplexityLimit int
+	GraphQLIntrospection   bool
+
+	// DatabaseURL enables API keys; keys live in gateway_api_keys there.
+	DatabaseURL    string
+	AdminToken     string
+	APIKeyCacheTTL time.Duration
+
+	// JWTSecret is the HS256 key shared with the services; the gateway does
+	// not start without it.
+	JWTSecret string
+	// ServiceAuthMode turns on authentication of the services calling each
+	// other over gRPC: "static" with ServiceAuthTokens ("name=secret,..."),
+	// or "jwt" with ServiceAuthJWTSecret and SPIFFE ids in
+	// ServiceAuthTrustDomain. Empty disables it.
+	ServiceAuthMode        string
+	ServiceAuthTokens      string
+	ServiceAuthJWTSecret   string
+	ServiceAuthTrustDomain string
+	// SessionTTL is how long an anonymous session token from POST /session
+	// stays valid; it is signed with JWTSecret.
+	SessionTTL time.Duration
+
+	// SigningKeys is "id:secret[,id:secret]", newest first; when set,
+	// money-moving and admin routes require X-Signature.
+	SigningKeys        string
+	SignatureTolerance time.Duration
+
+	// Audit records go to the DatabaseURL database. AuditSampleRate applies
+	// to successful calls only; failed calls are always recorded.
+	AuditSampleRate    float64
+	AuditBufferSize    int
+	AuditFlushInterval time.Duration
+
+	// RedisAddr enables usage metering of API keys, which also needs
+	// DatabaseURL: live counters and quotas are kept in Redis and flushed to
+	// gateway_usage every UsageFlushInterval.
+	RedisAddr          string
+	UsageFlushInterval time.Duration
+
+	// LoadShedMaxLimit caps in-flight requests per route (0 disables load
+	// shedding); LoadShedRoutes is "METHOD /path=limit[,...]" for routes
+	// with their own cap.
+	LoadShedMaxLimit int
+	LoadShedMinLimit int
+	LoadShedRoutes   string
+
+	// DebugTiming answers X-Debug-Timing from every caller, not only
+	// admins; for development only.
+	DebugTiming bool
+
+	// DefaultLanguage localizes error messages when Accept-Language matches
+	// no catalog; it must have one itself.
+	DefaultLanguage string
 }
 
 func MustLoad() Config {
 	return Config{
-		HTTPAddr:         getenv("GATEWAY_HTTP_ADDR", ":5050"),
-		BasePath:         getenv("GATEWAY_BASE_PATH", "/api/v1"),
+		HTTPAddr: getenv("GATEWAY_HTTP_ADDR", ":5050"),
+		BasePath: getenv("GATEWAY_BASE_PATH", "/api/v1"),
+
+		HTTPReadTimeout:    getenvDuration("GATEWAY_HTTP_READ_TIMEOUT", 30*time.Second),
+		HTTPWriteTimeout:   getenvDuration("GATEWAY_HTTP_WRITE_TIMEOUT", 90*time.Second),
+		HTTPIdleTimeout:    getenvDuration("GATEWAY_HTTP_IDLE_TIMEOUT", 120*time.Second),
+		HTTPMaxHeaderBytes: getenvInt("GATEWAY_HTTP_MAX_HEADER_BYTES", 1<<20),
+		HTTP2MaxStreams:    getenvInt("GATEWAY_HTTP2_MAX_CONCURRENT_STREAMS", 250),
+		H2C:                getenvBool("GATEWAY_H2C", true),
+		ShutdownTimeout:    getenvDuration("GATEWAY_SHUTDOWN_TIMEOUT", 30*time.Second),
+		MetricsAddr:        getenv("GATEWAY_METRICS_ADDR", ""),
+
 		OrdersGRPCAddr:   getenv("ORDERS_GRPC_ADDR", "orders-service:9001"),
 		PaymentsGRPCAddr: getenv("PAYMENTS_GRPC_ADDR", "payments-service:9002"),
+
+		NotificationsGRPCAddr: getenv("NOTIFICATIONS_GRPC_ADDR", ""),
+
+		LogLevel:  getenvLevel("LOG_LEVEL", slog.LevelInfo),
+		LogFormat: getenv("LOG_FORMAT", "json"),
+
+		LogRedact:       getenv("LOG_REDACT", "hash"),
+		LogRedactFields: getenvList("LOG_REDACT_FIELDS"),
+		LogRedactSalt:   getenv("LOG_REDACT_SALT", ""),
+		LogDev:          getenvBool("LOG_DEV", false),
+
+		StartupWaitTimeout:  getenvDuration("STARTUP_WAIT_TIMEOUT", time.Minute),
+		StartupCheckTimeout: getenvDuration("STARTUP_CHECK_TIMEOUT", 5*time.Second),
+
+		RequestBudget: getenvDuration("GATEWAY_REQUEST_BUDGET", 5*time.Second),
+		HedgeDelay:    getenvDuration("GATEWAY_HEDGE_DELAY", 0),
+
+		BatchMaxRequests: getenvInt("GATEWAY_BATCH_MAX_REQUESTS", 20),
+
+		GraphQLComplexityLimit: getenvInt("GATEWAY_GRAPHQL_COMPLEXITY_LIMIT", 200),
+		GraphQLIntrospection:   getenvBool("GATEWAY_GRAPHQL_INTROSPECTION", false),
+
+		DatabaseURL:    getenv("GATEWAY_DATABASE_URL", ""),
+		AdminToken:     getenv("GATEWAY_ADMIN_TOKEN", ""),
+		APIKeyCacheTTL: getenvDuration("GATEWAY_API_KEY_CACHE_TTL", 30*time.Second),
+
+		JWTSecret:  getenv("JWT_SECRET", ""),
+		SessionTTL: getenvDuration("GATEWAY_SESSION_TTL", 720*time.Hour),
+
+		ServiceAuthMode:        getenv("SERVICE_AUTH_MODE", ""),
+		ServiceAuthTokens:      getenv("SERVICE_AUTH_TOKENS", ""),
+		ServiceAuthJWTSecret:   getenv("SERVICE_AUTH_JWT_SECRET", ""),
+		ServiceAuthTrustDomain: getenv("SERVICE_AUTH_TRUST_DOMAIN", ""),
+
+		SigningKeys:        getenv("GATEWAY_SIGNING_KEYS", ""),
+		SignatureTolerance: getenvDuration("GATEWAY_SIGNATURE_TOLERANCE", 5*time.Minute),
+
+		AuditSampleRate:    getenvFloat("GATEWAY_AUDIT_SAMPLE_RATE", 1),
+		AuditBufferSize:    getenvInt("GATEWAY_AUDIT_BUFFER_SIZE", 1024),
+		AuditFlushInterval: getenvDuration("GATEWAY_AUDIT_FLUSH_INTERVAL", time.Second),
+
+		RedisAddr:          getenv("GATEWAY_REDIS_ADDR", ""),
+		UsageFlushInterval: getenvDuration("GATEWAY_USAGE_FLUSH_INTERVAL", time.Minute),
+
+		LoadShedMaxLimit: getenvInt("GATEWAY_LOADSHED_MAX_LIMIT", 0),
+		LoadShedMinLimit: getenvInt("GATEWAY_LOADSHED_MIN_LIMIT", 10),
+		LoadShedRoutes:   getenv("GATEWAY_LOADSHED_ROUTES", ""),
+
+		DebugTiming: getenvBool("GATEWAY_DEBUG_TIMING", false),
+
+		DefaultLanguage: getenv("GATEWAY_DEFAULT_LANGUAGE", "en"),
 	}
 }
 
@@ -24,3 +193,75 @@ func getenv(k, d string) string {
 	}
 	return d
 }
+
+// getenvList splits a comma-separated value, dropping empty items; unset
+// gives nil.
+func getenvList(k string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(k), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getenvInt(k string, d int) int {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return d
+	}
+	return n
+}
+
+func getenvFloat(k string, d float64) float64 {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || f > 1 {
+		return d
+	}
+	return f
+}
+
+func getenvBool(k string, d bool) bool {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return d
+	}
+	return b
+}
+
+func getenvDuration(k string, d time.Duration) time.Duration {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	dd, err := time.ParseDuration(v)
+	if err != nil {
+		return d
+	}
+	return dd
+}
+
+func getenvLevel(k string, d slog.Level) slog.Level {
+	v := os.Getenv(k)
+	if v == "" {
+		return d
+	}
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(v)); err != nil {
+		return d
+	}
+	return l
+}
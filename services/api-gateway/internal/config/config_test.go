@@ -1,12 +1,56 @@
 package config
 
-import "testing"
+import (
+	"log/slog"
+	"testing"
+)
 
 func TestMustLoadDefaults(t *testing.T) {
 	t.Setenv("GATEWAY_HTTP_ADDR", "")
 	t.Setenv("GATEWAY_BASE_PATH", "")
+	t.Setenv("GATEWAY_HTTP_READ_TIMEOUT", "")
+	t.Setenv("GATEWAY_HTTP_WRITE_TIMEOUT", "")
+	t.Setenv("GATEWAY_HTTP_IDLE_TIMEOUT", "")
+	t.Setenv("GATEWAY_HTTP_MAX_HEADER_BYTES", "")
+	t.Setenv("GATEWAY_HTTP2_MAX_CONCURRENT_STREAMS", "")
+	t.Setenv("GATEWAY_H2C", "")
+	t.Setenv("GATEWAY_SHUTDOWN_TIMEOUT", "")
+	t.Setenv("GATEWAY_METRICS_ADDR", "")
 	t.Setenv("ORDERS_GRPC_ADDR", "")
 	t.Setenv("PAYMENTS_GRPC_ADDR", "")
+	t.Setenv("NOTIFICATIONS_GRPC_ADDR", "")
+	t.Setenv("LOG_LEVEL", "")
+	t.Setenv("LOG_FORMAT", "")
+	t.Setenv("LOG_REDACT", "")
+	t.Setenv("LOG_REDACT_FIELDS", "")
+	t.Setenv("LOG_REDACT_SALT", "")
+	t.Setenv("LOG_DEV", "")
+	t.Setenv("STARTUP_WAIT_TIMEOUT", "")
+	t.Setenv("STARTUP_CHECK_TIMEOUT", "")
+	t.Setenv("GATEWAY_REQUEST_BUDGET", "")
+	t.Setenv("GATEWAY_HEDGE_DELAY", "")
+	t.Setenv("GATEWAY_BATCH_MAX_REQUESTS", "")
+	t.Setenv("GATEWAY_GRAPHQL_COMPLEXITY_LIMIT", "")
+	t.Setenv("GATEWAY_GRAPHQL_INTROSPECTION", "")
+	t.Setenv("GATEWAY_DATABASE_URL", "")
+	t.Setenv("GATEWAY_ADMIN_TOKEN", "")
+	t.Setenv("GATEWAY_API_KEY_CACHE_TTL", "")
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("SERVICE_AUTH_MODE", "")
+	t.Setenv("SERVICE_AUTH_TOKENS", "")
+	t.Setenv("SERVICE_AUTH_JWT_SECRET", "")
+	t.Setenv("SERVICE_AUTH_TRUST_DOMAIN", "")
+	t.Setenv("GATEWAY_SESSION_TTL", "")
+	t.Setenv("GATEWAY_SIGNING_KEYS", "")
+	t.Setenv("GATEWAY_SIGNATURE_TOLERANCE", "")
+	t.Setenv("GATEWAY_AUDIT_SAMPLE_RATE", "")
+	t.Setenv("GATEWAY_AUDIT_BUFFER_SIZE", "")
+	t.Setenv("GATEWAY_AUDIT_FLUSH_INTERVAL", "")
+	t.Setenv("GATEWAY_LOADSHED_MAX_LIMIT", "")
+	t.Setenv("GATEWAY_LOADSHED_MIN_LIMIT", "")
+	t.Setenv("GATEWAY_LOADSHED_ROUTES", "")
+	t.Setenv("GATEWAY_DEFAULT_LANGUAGE", "")
+	t.Setenv("GATEWAY_DEBUG_TIMING", "")
 
 	cfg := MustLoad()
 	if cfg.HTTPAddr != ":5050" {
@@ -15,19 +59,182 @@ func TestMustLoadDefaults(t *testing.T) {
 	if cfg.BasePath != "/api/v1" {
 		t.Fatalf("BasePath = %q, want %q", cfg.BasePath, "/api/v1")
 	}
+	if cfg.HTTPReadTimeout.String() != "30s" {
+		t.Fatalf("HTTPReadTimeout = %s, want 30s", cfg.HTTPReadTimeout)
+	}
+	if cfg.HTTPWriteTimeout.String() != "1m30s" {
+		t.Fatalf("HTTPWriteTimeout = %s, want 1m30s", cfg.HTTPWriteTimeout)
+	}
+	if cfg.HTTPIdleTimeout.String() != "2m0s" {
+		t.Fatalf("HTTPIdleTimeout = %s, want 2m0s", cfg.HTTPIdleTimeout)
+	}
+	if cfg.HTTPMaxHeaderBytes != 1048576 {
+		t.Fatalf("HTTPMaxHeaderBytes = %d, want 1048576", cfg.HTTPMaxHeaderBytes)
+	}
+	if cfg.HTTP2MaxStreams != 250 {
+		t.Fatalf("HTTP2MaxStreams = %d, want 250", cfg.HTTP2MaxStreams)
+	}
+	if cfg.H2C != true {
+		t.Fatalf("H2C = %t, want true", cfg.H2C)
+	}
+	if cfg.ShutdownTimeout.String() != "30s" {
+		t.Fatalf("ShutdownTimeout = %s, want 30s", cfg.ShutdownTimeout)
+	}
+	if cfg.MetricsAddr != "" {
+		t.Fatalf("MetricsAddr = %q, want %q", cfg.MetricsAddr, "")
+	}
 	if cfg.OrdersGRPCAddr != "orders-service:9001" {
 		t.Fatalf("OrdersGRPCAddr = %q, want %q", cfg.OrdersGRPCAddr, "orders-service:9001")
 	}
 	if cfg.PaymentsGRPCAddr != "payments-service:9002" {
 		t.Fatalf("PaymentsGRPCAddr = %q, want %q", cfg.PaymentsGRPCAddr, "payments-service:9002")
 	}
+	if cfg.NotificationsGRPCAddr != "" {
+		t.Fatalf("NotificationsGRPCAddr = %q, want empty", cfg.NotificationsGRPCAddr)
+	}
+	if cfg.LogLevel != slog.LevelInfo {
+		t.Fatalf("LogLevel = %s, want %s", cfg.LogLevel, slog.LevelInfo)
+	}
+	if cfg.LogFormat != "json" {
+		t.Fatalf("LogFormat = %q, want %q", cfg.LogFormat, "json")
+	}
+	if cfg.LogRedact != "hash" {
+		t.Fatalf("LogRedact = %q, want %q", cfg.LogRedact, "hash")
+	}
+	if cfg.LogRedactFields != nil {
+		t.Fatalf("LogRedactFields = %v, want nil", cfg.LogRedactFields)
+	}
+	if cfg.LogRedactSalt != "" {
+		t.Fatalf("LogRedactSalt = %q, want %q", cfg.LogRedactSalt, "")
+	}
+	if cfg.LogDev {
+		t.Fatal("LogDev = true, want false")
+	}
+	if cfg.StartupWaitTimeout.String() != "1m0s" {
+		t.Fatalf("StartupWaitTimeout = %s, want 1m0s", cfg.StartupWaitTimeout)
+	}
+	if cfg.StartupCheckTimeout.String() != "5s" {
+		t.Fatalf("StartupCheckTimeout = %s, want 5s", cfg.StartupCheckTimeout)
+	}
+	if cfg.RequestBudget.String() != "5s" {
+		t.Fatalf("RequestBudget = %s, want %s", cfg.RequestBudget, "5s")
+	}
+	if cfg.HedgeDelay != 0 {
+		t.Fatalf("HedgeDelay = %s, want 0", cfg.HedgeDelay)
+	}
+	if cfg.BatchMaxRequests != 20 {
+		t.Fatalf("BatchMaxRequests = %d, want %d", cfg.BatchMaxRequests, 20)
+	}
+	if cfg.GraphQLComplexityLimit != 200 {
+		t.Fatalf("GraphQLComplexityLimit = %d, want %d", cfg.GraphQLComplexityLimit, 200)
+	}
+	if cfg.GraphQLIntrospection {
+		t.Fatal("GraphQLIntrospection = true, want false")
+	}
+	if cfg.DatabaseURL != "" {
+		t.Fatalf("DatabaseURL = %q, want %q", cfg.DatabaseURL, "")
+	}
+	if cfg.AdminToken != "" {
+		t.Fatalf("AdminToken = %q, want %q", cfg.AdminToken, "")
+	}
+	if cfg.APIKeyCacheTTL.String() != "30s" {
+		t.Fatalf("APIKeyCacheTTL = %s, want %s", cfg.APIKeyCacheTTL, "30s")
+	}
+	if cfg.JWTSecret != "" {
+		t.Fatalf("JWTSecret = %q, want %q", cfg.JWTSecret, "")
+	}
+	if cfg.ServiceAuthMode != "" || cfg.ServiceAuthTokens != "" || cfg.ServiceAuthJWTSecret != "" || cfg.ServiceAuthTrustDomain != "" {
+		t.Fatalf("ServiceAuth = %q/%q/%q/%q, want all empty", cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	}
+	if cfg.SessionTTL.String() != "720h0m0s" {
+		t.Fatalf("SessionTTL = %s, want %s", cfg.SessionTTL, "720h0m0s")
+	}
+	if cfg.SigningKeys != "" {
+		t.Fatalf("SigningKeys = %q, want %q", cfg.SigningKeys, "")
+	}
+	if cfg.SignatureTolerance.String() != "5m0s" {
+		t.Fatalf("SignatureTolerance = %s, want %s", cfg.SignatureTolerance, "5m0s")
+	}
+	if cfg.AuditSampleRate != 1 {
+		t.Fatalf("AuditSampleRate = %v, want 1", cfg.AuditSampleRate)
+	}
+	if cfg.AuditBufferSize != 1024 {
+		t.Fatalf("AuditBufferSize = %d, want %d", cfg.AuditBufferSize, 1024)
+	}
+	if cfg.AuditFlushInterval.String() != "1s" {
+		t.Fatalf("AuditFlushInterval = %s, want %s", cfg.AuditFlushInterval, "1s")
+	}
+	if cfg.RedisAddr != "" {
+		t.Fatalf("RedisAddr = %q, want %q", cfg.RedisAddr, "")
+	}
+	if cfg.UsageFlushInterval.String() != "1m0s" {
+		t.Fatalf("UsageFlushInterval = %s, want %s", cfg.UsageFlushInterval, "1m0s")
+	}
+	if cfg.LoadShedMaxLimit != 0 {
+		t.Fatalf("LoadShedMaxLimit = %d, want %d", cfg.LoadShedMaxLimit, 0)
+	}
+	if cfg.LoadShedMinLimit != 10 {
+		t.Fatalf("LoadShedMinLimit = %d, want %d", cfg.LoadShedMinLimit, 10)
+	}
+	if cfg.LoadShedRoutes != "" {
+		t.Fatalf("LoadShedRoutes = %q, want %q", cfg.LoadShedRoutes, "")
+	}
+	if cfg.DefaultLanguage != "en" {
+		t.Fatalf("DefaultLanguage = %q, want %q", cfg.DefaultLanguage, "en")
+	}
+	if cfg.DebugTiming {
+		t.Fatal("DebugTiming = true, want false")
+	}
 }
 
 func TestMustLoadOverrides(t *testing.T) {
 	t.Setenv("GATEWAY_HTTP_ADDR", ":9000")
 	t.Setenv("GATEWAY_BASE_PATH", "/custom")
+	t.Setenv("GATEWAY_HTTP_READ_TIMEOUT", "45s")
+	t.Setenv("GATEWAY_HTTP_WRITE_TIMEOUT", "2m0s")
+	t.Setenv("GATEWAY_HTTP_IDLE_TIMEOUT", "5m0s")
+	t.Setenv("GATEWAY_HTTP_MAX_HEADER_BYTES", "4096")
+	t.Setenv("GATEWAY_HTTP2_MAX_CONCURRENT_STREAMS", "100")
+	t.Setenv("GATEWAY_H2C", "false")
+	t.Setenv("GATEWAY_SHUTDOWN_TIMEOUT", "1m0s")
+	t.Setenv("GATEWAY_METRICS_ADDR", ":9100")
 	t.Setenv("ORDERS_GRPC_ADDR", "orders:9999")
 	t.Setenv("PAYMENTS_GRPC_ADDR", "payments:8888")
+	t.Setenv("NOTIFICATIONS_GRPC_ADDR", "notifications:7777")
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "text")
+	t.Setenv("GATEWAY_REQUEST_BUDGET", "2s")
+	t.Setenv("GATEWAY_HEDGE_DELAY", "150ms")
+	t.Setenv("GATEWAY_BATCH_MAX_REQUESTS", "0")
+	t.Setenv("GATEWAY_GRAPHQL_COMPLEXITY_LIMIT", "50")
+	t.Setenv("GATEWAY_GRAPHQL_INTROSPECTION", "true")
+	t.Setenv("GATEWAY_DATABASE_URL", "postgres://x:y@db:5432/gw")
+	t.Setenv("GATEWAY_ADMIN_TOKEN", "admin-secret")
+	t.Setenv("GATEWAY_API_KEY_CACHE_TTL", "1m")
+	t.Setenv("JWT_SECRET", "s3cret")
+	t.Setenv("SERVICE_AUTH_MODE", "jwt")
+	t.Setenv("SERVICE_AUTH_TOKENS", "api-gateway=g")
+	t.Setenv("SERVICE_AUTH_JWT_SECRET", "svc-secret")
+	t.Setenv("SERVICE_AUTH_TRUST_DOMAIN", "example.org")
+	t.Setenv("GATEWAY_SESSION_TTL", "24h")
+	t.Setenv("GATEWAY_SIGNING_KEYS", "k1:s")
+	t.Setenv("GATEWAY_SIGNATURE_TOLERANCE", "30s")
+	t.Setenv("GATEWAY_AUDIT_SAMPLE_RATE", "0.25")
+	t.Setenv("GATEWAY_AUDIT_BUFFER_SIZE", "64")
+	t.Setenv("GATEWAY_AUDIT_FLUSH_INTERVAL", "250ms")
+	t.Setenv("GATEWAY_REDIS_ADDR", "redis:6379")
+	t.Setenv("GATEWAY_USAGE_FLUSH_INTERVAL", "10s")
+	t.Setenv("GATEWAY_LOADSHED_MAX_LIMIT", "200")
+	t.Setenv("GATEWAY_LOADSHED_MIN_LIMIT", "4")
+	t.Setenv("GATEWAY_LOADSHED_ROUTES", "POST /orders=50")
+	t.Setenv("GATEWAY_DEFAULT_LANGUAGE", "ru")
+	t.Setenv("GATEWAY_DEBUG_TIMING", "true")
+	t.Setenv("LOG_REDACT", "mask")
+	t.Setenv("LOG_REDACT_FIELDS", "user_id, operator")
+	t.Setenv("LOG_REDACT_SALT", "pepper")
+	t.Setenv("LOG_DEV", "true")
+	t.Setenv("STARTUP_WAIT_TIMEOUT", "0")
+	t.Setenv("STARTUP_CHECK_TIMEOUT", "2s")
 
 	cfg := MustLoad()
 	if cfg.HTTPAddr != ":9000" {
@@ -36,10 +243,151 @@ func TestMustLoadOverrides(t *testing.T) {
 	if cfg.BasePath != "/custom" {
 		t.Fatalf("BasePath = %q, want %q", cfg.BasePath, "/custom")
 	}
+	if cfg.HTTPReadTimeout.String() != "45s" {
+		t.Fatalf("HTTPReadTimeout = %s, want 45s", cfg.HTTPReadTimeout)
+	}
+	if cfg.HTTPWriteTimeout.String() != "2m0s" {
+		t.Fatalf("HTTPWriteTimeout = %s, want 2m0s", cfg.HTTPWriteTimeout)
+	}
+	if cfg.HTTPIdleTimeout.String() != "5m0s" {
+		t.Fatalf("HTTPIdleTimeout = %s, want 5m0s", cfg.HTTPIdleTimeout)
+	}
+	if cfg.HTTPMaxHeaderBytes != 4096 {
+		t.Fatalf("HTTPMaxHeaderBytes = %d, want 4096", cfg.HTTPMaxHeaderBytes)
+	}
+	if cfg.HTTP2MaxStreams != 100 {
+		t.Fatalf("HTTP2MaxStreams = %d, want 100", cfg.HTTP2MaxStreams)
+	}
+	if cfg.H2C != false {
+		t.Fatalf("H2C = %t, want false", cfg.H2C)
+	}
+	if cfg.ShutdownTimeout.String() != "1m0s" {
+		t.Fatalf("ShutdownTimeout = %s, want 1m0s", cfg.ShutdownTimeout)
+	}
+	if cfg.MetricsAddr != ":9100" {
+		t.Fatalf("MetricsAddr = %q, want %q", cfg.MetricsAddr, ":9100")
+	}
 	if cfg.OrdersGRPCAddr != "orders:9999" {
 		t.Fatalf("OrdersGRPCAddr = %q, want %q", cfg.OrdersGRPCAddr, "orders:9999")
 	}
 	if cfg.PaymentsGRPCAddr != "payments:8888" {
 		t.Fatalf("PaymentsGRPCAddr = %q, want %q", cfg.PaymentsGRPCAddr, "payments:8888")
 	}
+	if cfg.NotificationsGRPCAddr != "notifications:7777" {
+		t.Fatalf("NotificationsGRPCAddr = %q, want %q", cfg.NotificationsGRPCAddr, "notifications:7777")
+	}
+	if cfg.LogLevel != slog.LevelDebug {
+		t.Fatalf("LogLevel = %s, want %s", cfg.LogLevel, slog.LevelDebug)
+	}
+	if cfg.LogFormat != "text" {
+		t.Fatalf("LogFormat = %q, want %q", cfg.LogFormat, "text")
+	}
+	if cfg.LogRedact != "mask" {
+		t.Fatalf("LogRedact = %q, want %q", cfg.LogRedact, "mask")
+	}
+	if len(cfg.LogRedactFields) != 2 || cfg.LogRedactFields[0] != "user_id" || cfg.LogRedactFields[1] != "operator" {
+		t.Fatalf("LogRedactFields = %v, want [user_id operator]", cfg.LogRedactFields)
+	}
+	if cfg.LogRedactSalt != "pepper" {
+		t.Fatalf("LogRedactSalt = %q, want %q", cfg.LogRedactSalt, "pepper")
+	}
+	if !cfg.LogDev {
+		t.Fatal("LogDev = false, want true")
+	}
+	if cfg.StartupWaitTimeout != 0 {
+		t.Fatalf("StartupWaitTimeout = %s, want 0", cfg.StartupWaitTimeout)
+	}
+	if cfg.StartupCheckTimeout.String() != "2s" {
+		t.Fatalf("StartupCheckTimeout = %s, want 2s", cfg.StartupCheckTimeout)
+	}
+	if cfg.RequestBudget.String() != "2s" {
+		t.Fatalf("RequestBudget = %s, want %s", cfg.RequestBudget, "2s")
+	}
+	if cfg.HedgeDelay.String() != "150ms" {
+		t.Fatalf("HedgeDelay = %s, want %s", cfg.HedgeDelay, "150ms")
+	}
+	if cfg.BatchMaxRequests != 0 {
+		t.Fatalf("BatchMaxRequests = %d, want %d", cfg.BatchMaxRequests, 0)
+	}
+	if cfg.GraphQLComplexityLimit != 50 {
+		t.Fatalf("GraphQLComplexityLimit = %d, want %d", cfg.GraphQLComplexityLimit, 50)
+	}
+	if !cfg.GraphQLIntrospection {
+		t.Fatal("GraphQLIntrospection = false, want true")
+	}
+	if cfg.DatabaseURL != "postgres://x:y@db:5432/gw" {
+		t.Fatalf("DatabaseURL = %q, want %q", cfg.DatabaseURL, "postgres://x:y@db:5432/gw")
+	}
+	if cfg.AdminToken != "admin-secret" {
+		t.Fatalf("AdminToken = %q, want %q", cfg.AdminToken, "admin-secret")
+	}
+	if cfg.APIKeyCacheTTL.String() != "1m0s" {
+		t.Fatalf("APIKeyCacheTTL = %s, want %s", cfg.APIKeyCacheTTL, "1m0s")
+	}
+	if cfg.JWTSecret != "s3cret" {
+		t.Fatalf("JWTSecret = %q, want %q", cfg.JWTSecret, "s3cret")
+	}
+	if cfg.ServiceAuthMode != "jwt" || cfg.ServiceAuthTokens != "api-gateway=g" || cfg.ServiceAuthJWTSecret != "svc-secret" || cfg.ServiceAuthTrustDomain != "example.org" {
+		t.Fatalf("ServiceAuth = %q/%q/%q/%q, want jwt/api-gateway=g/svc-secret/example.org", cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	}
+	if cfg.SessionTTL.String() != "24h0m0s" {
+		t.Fatalf("SessionTTL = %s, want %s", cfg.SessionTTL, "24h0m0s")
+	}
+	if cfg.SigningKeys != "k1:s" {
+		t.Fatalf("SigningKeys = %q, want %q", cfg.SigningKeys, "k1:s")
+	}
+	if cfg.SignatureTolerance.String() != "30s" {
+		t.Fatalf("SignatureTolerance = %s, want %s", cfg.SignatureTolerance, "30s")
+	}
+	if cfg.AuditSampleRate != 0.25 {
+		t.Fatalf("AuditSampleRate = %v, want 0.25", cfg.AuditSampleRate)
+	}
+	if cfg.AuditBufferSize != 64 {
+		t.Fatalf("AuditBufferSize = %d, want %d", cfg.AuditBufferSize, 64)
+	}
+	if cfg.AuditFlushInterval.String() != "250ms" {
+		t.Fatalf("AuditFlushInterval = %s, want %s", cfg.AuditFlushInterval, "250ms")
+	}
+	if cfg.RedisAddr != "redis:6379" {
+		t.Fatalf("RedisAddr = %q, want %q", cfg.RedisAddr, "redis:6379")
+	}
+	if cfg.UsageFlushInterval.String() != "10s" {
+		t.Fatalf("UsageFlushInterval = %s, want %s", cfg.UsageFlushInterval, "10s")
+	}
+	if cfg.LoadShedMaxLimit != 200 {
+		t.Fatalf("LoadShedMaxLimit = %d, want %d", cfg.LoadShedMaxLimit, 200)
+	}
+	if cfg.LoadShedMinLimit != 4 {
+		t.Fatalf("LoadShedMinLimit = %d, want %d", cfg.LoadShedMinLimit, 4)
+	}
+	if cfg.LoadShedRoutes != "POST /orders=50" {
+		t.Fatalf("LoadShedRoutes = %q, want %q", cfg.LoadShedRoutes, "POST /orders=50")
+	}
+	if cfg.DefaultLanguage != "ru" {
+		t.Fatalf("DefaultLanguage = %q, want %q", cfg.DefaultLanguage, "ru")
+	}
+	if !cfg.DebugTiming {
+		t.Fatal("DebugTiming = false, want true")
+	}
+}
+
+func TestMustLoadInvalidOverridesFallback(t *testing.T) {
+	t.Setenv("GATEWAY_REQUEST_BUDGET", "bad")
+	t.Setenv("GATEWAY_HEDGE_DELAY", "soon")
+	t.Setenv("GATEWAY_AUDIT_SAMPLE_RATE", "1.5")
+	t.Setenv("GATEWAY_AUDIT_BUFFER_SIZE", "many")
+
+	cfg := MustLoad()
+	if cfg.RequestBudget.String() != "5s" {
+		t.Fatalf("RequestBudget = %s, want %s", cfg.RequestBudget, "5s")
+	}
+	if cfg.HedgeDelay != 0 {
+		t.Fatalf("HedgeDelay = %s, want 0", cfg.HedgeDelay)
+	}
+	if cfg.AuditSampleRate != 1 {
+		t.Fatalf("AuditSampleRate = %v, want 1", cfg.AuditSampleRate)
+	}
+	if cfg.AuditBufferSize != 1024 {
+		t.Fatalf("AuditBufferSize = %d, want 1024", cfg.AuditBufferSize)
+	}
 }
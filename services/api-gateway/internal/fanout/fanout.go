@@ -0,0 +1,113 @@
+package fanout
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Call is a single backend call issued on behalf of an HTTP request.
+type Call func(ctx context.Context) error
+
+// WithBudget derives the context shared by all backend calls of one request.
+// The deadline is now+budget unless the parent already ends earlier, so
+// nested helpers never extend what the caller granted. A non-positive budget
+// only inherits the parent deadline.
+func WithBudget(parent context.Context, budget time.Duration) (context.Context, context.CancelFunc) {
+	if budget <= 0 {
+		return context.WithCancel(parent)
+	}
+	if dl, ok := parent.Deadline(); ok && time.Until(dl) < budget {
+		return context.WithCancel(parent)
+	}
+	return context.WithTimeout(parent, budget)
+}
+
+// Remaining reports how much of the budget is left; zero when ctx has no
+// deadline or it has already passed.
+func Remaining(ctx context.Context) time.Duration {
+	dl, ok := ctx.Deadline()
+	if !ok {
+		return 0
+	}
+	if left := time.Until(dl); left > 0 {
+		return left
+	}
+	return 0
+}
+
+// All runs calls in parallel under ctx, so they share its budget. The first
+// failure cancels the rest and is returned once every call has finished.
+func All(ctx context.Context, calls ...Call) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	for _, call := range calls {
+		wg.Add(1)
+		go func(call Call) {
+			defer wg.Done()
+			if err := call(ctx); err != nil {
+				once.Do(func() {
+					firstErr = err
+					cancel()
+				})
+			}
+		}(call)
+	}
+	wg.Wait()
+	return firstErr
+}
+
+// Hedged runs an idempotent read and, if it has not answered after delay,
+// races a second identical attempt against it. The first success wins and the
+// loser is cancelled. A non-positive delay, or a budget too small to fit a
+// second attempt, disables hedging.
+func Hedged[T any](ctx context.Context, delay time.Duration, call func(ctx context.Context) (T, error)) (T, error) {
+	if delay <= 0 || Remaining(ctx) <= delay {
+		return call(ctx)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		val T
+		err error
+	}
+	results := make(chan result, 2)
+	attempt := func() {
+		v, err := call(ctx)
+		results <- result{val: v, err: err}
+	}
+
+	go attempt()
+	inflight := 1
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-timer.C:
+			slog.Default().With("service", "api-gateway", "component", "fanout").Debug("hedging request", "delay", delay)
+			go attempt()
+			inflight++
+		case res := <-results:
+			inflight--
+			if res.err == nil {
+				return res.val, nil
+			}
+			// An attempt that fails before the hedge fires is not retried:
+			// hedging covers slowness, not errors.
+			if inflight == 0 {
+				var zero T
+				return zero, res.err
+			}
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package fanout
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWithBudgetKeepsEarlierParentDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	ctx, cancelBudget := WithBudget(parent, time.Hour)
+	defer cancelBudget()
+	if left := Remaining(ctx); left <= 0 || left > 50*time.Millisecond {
+		t.Fatalf("Remaining() = %s, want <= 50ms", left)
+	}
+
+	ctx, cancelBudget = WithBudget(context.Background(), time.Second)
+	defer cancelBudget()
+	if left := Remaining(ctx); left <= 500*time.Millisecond || left > time.Second {
+		t.Fatalf("Remaining() = %s, want ~1s", left)
+	}
+}
+
+func TestRemainingWithoutDeadline(t *testing.T) {
+	if left := Remaining(context.Background()); left != 0 {
+		t.Fatalf("Remaining() = %s, want 0", left)
+	}
+}
+
+func TestAllRunsInParallel(t *testing.T) {
+	// Every call waits for all of them to have started, which only happens
+	// when they run at the same time.
+	const n = 3
+	var started sync.WaitGroup
+	started.Add(n)
+	call := func(ctx context.Context) error {
+		started.Done()
+		done := make(chan struct{})
+		go func() { started.Wait(); close(done) }()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	ctx, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+	if err := All(ctx, call, call, call); err != nil {
+		t.Fatalf("All() error: %v, want the calls to run in parallel", err)
+	}
+}
+
+func TestAllCancelsOnFirstError(t *testing.T) {
+	boom := errors.New("boom")
+	var cancelled atomic.Bool
+	err := All(context.Background(),
+		func(ctx context.Context) error { return boom },
+		func(ctx context.Context) error {
+			select {
+			case <-ctx.Done():
+				cancelled.Store(true)
+				return ctx.Err()
+			case <-time.After(time.Second):
+				return nil
+			}
+		},
+	)
+	if !errors.Is(err, boom) {
+		t.Fatalf("All() error = %v, want %v", err, boom)
+	}
+	if !cancelled.Load() {
+		t.Fatal("All() did not cancel sibling call")
+	}
+}
+
+func TestHedgedFastCallNotHedged(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+
+	var calls atomic.Int32
+	v, err := Hedged(ctx, 100*time.Millisecond, func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		return 7, nil
+	})
+	if err != nil || v != 7 {
+		t.Fatalf("Hedged() = (%d, %v), want (7, nil)", v, err)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Hedged() made %d calls, want 1", n)
+	}
+}
+
+func TestHedgedSlowCallIsRaced(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+
+	var calls atomic.Int32
+	var firstCancelled atomic.Bool
+	v, err := Hedged(ctx, 20*time.Millisecond, func(ctx context.Context) (int, error) {
+		if calls.Add(1) == 1 {
+			<-ctx.Done()
+			firstCancelled.Store(true)
+			return 0, ctx.Err()
+		}
+		return 2, nil
+	})
+	if err != nil || v != 2 {
+		t.Fatalf("Hedged() = (%d, %v), want (2, nil)", v, err)
+	}
+	if n := calls.Load(); n != 2 {
+		t.Fatalf("Hedged() made %d calls, want 2", n)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if !firstCancelled.Load() {
+		t.Fatal("Hedged() did not cancel the losing attempt")
+	}
+}
+
+func TestHedgedFailsFastBeforeHedge(t *testing.T) {
+	ctx, cancel := WithBudget(context.Background(), time.Second)
+	defer cancel()
+
+	boom := errors.New("boom")
+	var calls atomic.Int32
+	_, err := Hedged(ctx, 100*time.Millisecond, func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		return 0, boom
+	})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Hedged() error = %v, want %v", err, boom)
+	}
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Hedged() made %d calls, want 1", n)
+	}
+}
+
+func TestHedgedDisabledWithoutBudget(t *testing.T) {
+	var calls atomic.Int32
+	_, _ = Hedged(context.Background(), 10*time.Millisecond, func(ctx context.Context) (int, error) {
+		calls.Add(1)
+		time.Sleep(30 * time.Millisecond)
+		return 1, nil
+	})
+	if n := calls.Load(); n != 1 {
+		t.Fatalf("Hedged() made %d calls, want 1", n)
+	}
+}
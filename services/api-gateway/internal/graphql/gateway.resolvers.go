@@ -0,0 +1,141 @@
+package graphql
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/fanout"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/graphql/generated"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/graphql/model"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Orders is the resolver for the orders field.
+func (r *queryResolver) Orders(ctx context.Context, limit *int, pageToken *string, tag *string, includeArchived *bool) (*model.OrderPage, error) {
+	req := &ordersv1.ListOrdersRequest{UserId: userIDFrom(ctx)}
+	if limit != nil {
+		req.Limit = int32(*limit)
+	}
+	if pageToken != nil {
+		req.PageToken = *pageToken
+	}
+	if tag != nil {
+		req.Tag = *tag
+	}
+	if includeArchived != nil {
+		req.IncludeArchived = *includeArchived
+	}
+	resp, err := fanout.Hedged(ctx, r.hedgeDelay, func(ctx context.Context) (*ordersv1.ListOrdersResponse, error) {
+		return r.orders.ListOrders(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.OrderPage{Orders: make([]*model.Order, 0, len(resp.GetOrders()))}
+	for _, order := range resp.GetOrders() {
+		page.Orders = append(page.Orders, mapOrder(order))
+	}
+	if token := resp.GetNextPageToken(); token != "" {
+		page.NextPageToken = &token
+	}
+	return page, nil
+}
+
+// Order is the resolver for the order field.
+func (r *queryResolver) Order(ctx context.Context, id string) (*model.Order, error) {
+	order, err := loaderFrom(ctx).Load(ctx, id)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapOrder(order), nil
+}
+
+// Balance is the resolver for the balance field.
+func (r *queryResolver) Balance(ctx context.Context) (*model.Balance, error) {
+	resp, err := fanout.Hedged(ctx, r.hedgeDelay, func(ctx context.Context) (*paymentsv1.GetBalanceResponse, error) {
+		return r.payments.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: userIDFrom(ctx)})
+	})
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &model.Balance{
+		Balance:      resp.GetBalance(),
+		BonusBalance: resp.GetBonusBalance(),
+		Currency:     resp.GetCurrency(),
+		Version:      resp.GetVersion(),
+		AccountType:  mapAccountType(resp.GetAccountType()),
+	}, nil
+}
+
+// Transactions is the resolver for the transactions field.
+func (r *queryResolver) Transactions(ctx context.Context, pageSize *int, beforeID *string) (*model.TransactionPage, error) {
+	req := &paymentsv1.ListTransactionsRequest{UserId: userIDFrom(ctx)}
+	if pageSize != nil {
+		req.PageSize = int32(*pageSize)
+	}
+	if beforeID != nil {
+		id, err := strconv.ParseInt(*beforeID, 10, 64)
+		if err != nil {
+			return nil, invalidArgument(ctx, "beforeId must be a transaction id")
+		}
+		req.BeforeId = id
+	}
+	resp, err := fanout.Hedged(ctx, r.hedgeDelay, func(ctx context.Context) (*paymentsv1.ListTransactionsResponse, error) {
+		return r.payments.ListTransactions(ctx, req)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	page := &model.TransactionPage{
+		Transactions: make([]*model.Transaction, 0, len(resp.GetTransactions())),
+		Currency:     resp.GetCurrency(),
+	}
+	for _, tx := range resp.GetTransactions() {
+		page.Transactions = append(page.Transactions, mapTransaction(tx))
+	}
+	if next := resp.GetNextBeforeId(); next > 0 {
+		id := strconv.FormatInt(next, 10)
+		page.NextBeforeID = &id
+	}
+	return page, nil
+}
+
+// Order is the resolver for the order field. Orders that have since moved to
+// another user resolve to null.
+func (r *transactionResolver) Order(ctx context.Context, obj *model.Transaction) (*model.Order, error) {
+	if obj.OrderID == nil {
+		return nil, nil
+	}
+	order, err := loaderFrom(ctx).Load(ctx, *obj.OrderID)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return mapOrder(order), nil
+}
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+// Transaction returns generated.TransactionResolver implementation.
+func (r *Resolver) Transaction() generated.TransactionResolver { return &transactionResolver{r} }
+
+type queryResolver struct{ *Resolver }
+type transactionResolver struct{ *Resolver }
@@ -0,0 +1,120 @@
+// Package graphql serves the read-only GraphQL view of the gateway. Root
+// fields of one query, and the per-item fields of a list, are resolved
+// concurrently by gqlgen; every backend call of the query shares the
+// request budget.
+//
+// The schema lives in api-files/graphql; after changing it, regenerate with
+// `go run github.com/99designs/gqlgen generate` from services/api-gateway.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	gql "github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/fanout"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/graphql/generated"
+	resthandler "github.com/ilyaytrewq/payments-service/api-gateway/internal/handler"
+)
+
+type ctxKey int
+
+const (
+	userIDKey ctxKey = iota
+	loaderKey
+)
+
+// NewHandler serves GraphQL queries over POST. Queries act on the user in
+// X-User-Id; budget bounds all backend calls of one query and hedgeDelay
+// enables hedged reads when positive. Queries more complex than
+// complexityLimit are rejected unless it is 0, and introspection is only
+// answered when enabled.
+func NewHandler(orders ordersv1.OrdersServiceClient, payments paymentsv1.PaymentsServiceClient, budget, hedgeDelay time.Duration, complexityLimit int, introspection bool) http.Handler {
+	r := &Resolver{orders: orders, payments: payments, hedgeDelay: hedgeDelay}
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: r}))
+	srv.AddTransport(transport.POST{})
+	if introspection {
+		srv.Use(extension.Introspection{})
+	}
+	if complexityLimit > 0 {
+		srv.Use(extension.FixedComplexityLimit(complexityLimit))
+	}
+	srv.SetErrorPresenter(presentError)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userID, ok := resthandler.RequireUserID(w, req)
+		if !ok {
+			return
+		}
+		ctx, cancel := fanout.WithBudget(req.Context(), budget)
+		defer cancel()
+		ctx = context.WithValue(ctx, userIDKey, userID)
+		ctx = context.WithValue(ctx, loaderKey, newOrderLoader(ctx, func(ctx context.Context, orderIDs []string) (map[string]*ordersv1.Order, error) {
+			resp, err := fanout.Hedged(ctx, hedgeDelay, func(ctx context.Context) (*ordersv1.GetOrdersResponse, error) {
+				return orders.GetOrders(ctx, &ordersv1.GetOrdersRequest{UserId: userID, OrderIds: orderIDs})
+			})
+			if err != nil {
+				return nil, err
+			}
+			byID := make(map[string]*ordersv1.Order, len(resp.GetOrders()))
+			for _, o := range resp.GetOrders() {
+				byID[o.GetOrderId()] = o
+			}
+			return byID, nil
+		}))
+		srv.ServeHTTP(w, req.WithContext(ctx))
+	})
+}
+
+func userIDFrom(ctx context.Context) string {
+	userID, _ := ctx.Value(userIDKey).(string)
+	return userID
+}
+
+func loaderFrom(ctx context.Context) *orderLoader {
+	l, _ := ctx.Value(loaderKey).(*orderLoader)
+	return l
+}
+
+// presentError exposes gRPC failures with their message and the REST error
+// code in extensions.code, so clients branch on the same codes as for REST.
+func presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := gql.DefaultErrorPresenter(ctx, err)
+	var se interface{ GRPCStatus() *status.Status }
+	if !errors.As(err, &se) {
+		if gqlErr.Extensions == nil || gqlErr.Extensions["code"] == nil {
+			slog.ErrorContext(ctx, "graphql resolver failed", "err", err, "path", gqlErr.Path.String())
+		}
+		return gqlErr
+	}
+	st := se.GRPCStatus()
+	slog.DebugContext(ctx, "graphql grpc error", "grpc_code", st.Code().String(), "message", st.Message(), "path", gqlErr.Path.String())
+	gqlErr.Message = st.Message()
+	if gqlErr.Extensions == nil {
+		gqlErr.Extensions = map[string]any{}
+	}
+	gqlErr.Extensions["code"] = httperr.CodeForGRPC(st.Code())
+	return gqlErr
+}
+
+// invalidArgument reports a bad query argument with the REST code for it.
+func invalidArgument(ctx context.Context, message string) error {
+	return &gqlerror.Error{
+		Message:    message,
+		Path:       gql.GetPath(ctx),
+		Extensions: map[string]any{"code": httperr.CodeInvalidArgument},
+	}
+}
@@ -0,0 +1,270 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+type fakeOrders struct {
+	ordersv1.OrdersServiceClient
+	getOrdersCalls atomic.Int32
+}
+
+func (f *fakeOrders) GetOrders(_ context.Context, in *ordersv1.GetOrdersRequest, _ ...grpc.CallOption) (*ordersv1.GetOrdersResponse, error) {
+	f.getOrdersCalls.Add(1)
+	resp := &ordersv1.GetOrdersResponse{}
+	for _, id := range in.GetOrderIds() {
+		if id == "o-gone" {
+			resp.MissingOrderIds = append(resp.MissingOrderIds, id)
+			continue
+		}
+		resp.Orders = append(resp.Orders, &ordersv1.Order{OrderId: id, UserId: in.GetUserId(), Amount: 40, Status: ordersv1.OrderStatus_ORDER_STATUS_FINISHED})
+	}
+	return resp, nil
+}
+
+func (f *fakeOrders) ListOrders(_ context.Context, in *ordersv1.ListOrdersRequest, _ ...grpc.CallOption) (*ordersv1.ListOrdersResponse, error) {
+	return &ordersv1.ListOrdersResponse{
+		Orders:        []*ordersv1.Order{{OrderId: "o-1", UserId: in.GetUserId(), Amount: 40, Status: ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID, Tags: []string{in.GetTag()}}},
+		NextPageToken: "next",
+	}, nil
+}
+
+type fakePayments struct {
+	paymentsv1.PaymentsServiceClient
+}
+
+func (fakePayments) GetBalance(_ context.Context, in *paymentsv1.GetBalanceRequest, _ ...grpc.CallOption) (*paymentsv1.GetBalanceResponse, error) {
+	if in.GetUserId() == "no-account" {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	return &paymentsv1.GetBalanceResponse{Balance: 60, BonusBalance: 5, Currency: "RUB", Version: 3, AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM}, nil
+}
+
+func (fakePayments) ListTransactions(_ context.Context, in *paymentsv1.ListTransactionsRequest, _ ...grpc.CallOption) (*paymentsv1.ListTransactionsResponse, error) {
+	if in.GetPageSize() > 500 {
+		return nil, status.Error(codes.InvalidArgument, "page_size must be 0..500")
+	}
+	created := timestamppb.New(time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC))
+	return &paymentsv1.ListTransactionsResponse{
+		Transactions: []*paymentsv1.Transaction{
+			{Id: 9, Amount: -2, Kind: paymentsv1.TransactionKind_TRANSACTION_KIND_FEE, PaymentId: "p-2", OrderId: "o-1", CreatedAt: created},
+			{Id: 8, Amount: -20, Kind: paymentsv1.TransactionKind_TRANSACTION_KIND_PAYMENT, PaymentId: "p-2", OrderId: "o-1", CreatedAt: created},
+			{Id: 7, Amount: -20, Kind: paymentsv1.TransactionKind_TRANSACTION_KIND_PAYMENT, PaymentId: "p-1", OrderId: "o-gone", CreatedAt: created},
+			{Id: 6, Amount: 100, Kind: paymentsv1.TransactionKind_TRANSACTION_KIND_TOP_UP, CreatedAt: created},
+		},
+		Currency:     "RUB",
+		NextBeforeId: 6,
+	}, nil
+}
+
+type response struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func query(t *testing.T, h http.Handler, userID, q string) (int, response) {
+	t.Helper()
+	body, _ := json.Marshal(map[string]string{"query": q})
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/graphql", strings.NewReader(string(body)))
+	req.Header.Set("Content-Type", "application/json")
+	if userID != "" {
+		req.Header.Set("X-User-Id", userID)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	var resp response
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+	}
+	return rec.Code, resp
+}
+
+func TestComposedQuery(t *testing.T) {
+	orders := &fakeOrders{}
+	h := NewHandler(orders, fakePayments{}, time.Second, 0, 200, false)
+
+	code, resp := query(t, h, "u-1", `{
+		orders(tag: "gift") { orders { id status tags } nextPageToken }
+		balance { balance bonusBalance currency accountType }
+		transactions { currency nextBeforeId transactions { id amount kind orderId order { id userId status } } }
+	}`)
+	if code != http.StatusOK || len(resp.Errors) != 0 {
+		t.Fatalf("status = %d, errors = %+v", code, resp.Errors)
+	}
+	var data struct {
+		Orders struct {
+			Orders []struct {
+				ID     string   `json:"id"`
+				Status string   `json:"status"`
+				Tags   []string `json:"tags"`
+			} `json:"orders"`
+			NextPageToken string `json:"nextPageToken"`
+		} `json:"orders"`
+		Balance struct {
+			Balance      int64  `json:"balance"`
+			BonusBalance int64  `json:"bonusBalance"`
+			AccountType  string `json:"accountType"`
+		} `json:"balance"`
+		Transactions struct {
+			NextBeforeID string `json:"nextBeforeId"`
+			Transactions []struct {
+				ID    string `json:"id"`
+				Kind  string `json:"kind"`
+				Order *struct {
+					ID     string `json:"id"`
+					UserID string `json:"userId"`
+				} `json:"order"`
+			} `json:"transactions"`
+		} `json:"transactions"`
+	}
+	if err := json.Unmarshal(resp.Data, &data); err != nil {
+		t.Fatalf("decode data: %v", err)
+	}
+
+	if len(data.Orders.Orders) != 1 || data.Orders.Orders[0].Status != "PARTIALLY_PAID" || data.Orders.Orders[0].Tags[0] != "gift" || data.Orders.NextPageToken != "next" {
+		t.Fatalf("orders = %+v", data.Orders)
+	}
+	if data.Balance.Balance != 60 || data.Balance.BonusBalance != 5 || data.Balance.AccountType != "PREMIUM" {
+		t.Fatalf("balance = %+v", data.Balance)
+	}
+	txs := data.Transactions.Transactions
+	if len(txs) != 4 || data.Transactions.NextBeforeID != "6" || txs[0].ID != "9" || txs[0].Kind != "FEE" || txs[3].Kind != "TOP_UP" {
+		t.Fatalf("transactions = %+v", data.Transactions)
+	}
+	if txs[0].Order == nil || txs[0].Order.ID != "o-1" || txs[0].Order.UserID != "u-1" {
+		t.Fatalf("order of first transaction = %+v", txs[0].Order)
+	}
+	// The transferred order and the top-up resolve to null.
+	if txs[2].Order != nil || txs[3].Order != nil {
+		t.Fatalf("orders = %+v, %+v; want null", txs[2].Order, txs[3].Order)
+	}
+	// o-1 is shared by two entries but fetched once, in one batch with o-gone.
+	if n := orders.getOrdersCalls.Load(); n != 1 {
+		t.Fatalf("GetOrders calls = %d, want 1", n)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	h := NewHandler(&fakeOrders{}, fakePayments{}, time.Second, 0, 200, false)
+
+	if code, _ := query(t, h, "", `{ balance { balance } }`); code != http.StatusUnauthorized {
+		t.Fatalf("without X-User-Id: status = %d, want 401", code)
+	}
+
+	code, resp := query(t, h, "no-account", `{ balance { balance } }`)
+	if code != http.StatusOK || len(resp.Errors) != 0 || string(resp.Data) != `{"balance":null}` {
+		t.Fatalf("missing account: status = %d, data = %s, errors = %+v", code, resp.Data, resp.Errors)
+	}
+
+	for _, tc := range []struct {
+		query string
+		code  string
+	}{
+		{`{ transactions(pageSize: 501) { currency } }`, "invalid_argument"},
+		{`{ transactions(beforeId: "x") { currency } }`, "invalid_argument"},
+	} {
+		_, resp := query(t, h, "u-1", tc.query)
+		if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != tc.code {
+			t.Fatalf("%s: errors = %+v, want code %s", tc.query, resp.Errors, tc.code)
+		}
+	}
+}
+
+func TestQueryLimits(t *testing.T) {
+	introspect := `{ __schema { queryType { name } } }`
+	h := NewHandler(&fakeOrders{}, fakePayments{}, time.Second, 0, 5, false)
+
+	if _, resp := query(t, h, "u-1", introspect); len(resp.Errors) == 0 {
+		t.Fatalf("introspection off: data = %s, want an error", resp.Data)
+	}
+	if _, resp := query(t, h, "u-1", `{ balance { balance } }`); len(resp.Errors) != 0 {
+		t.Fatalf("simple query: errors = %+v", resp.Errors)
+	}
+	_, resp := query(t, h, "u-1", `{ balance { balance bonusBalance currency accountType } transactions { currency } }`)
+	if len(resp.Errors) != 1 || resp.Errors[0].Extensions["code"] != "COMPLEXITY_LIMIT_EXCEEDED" {
+		t.Fatalf("complex query: errors = %+v, want COMPLEXITY_LIMIT_EXCEEDED", resp.Errors)
+	}
+
+	h = NewHandler(&fakeOrders{}, fakePayments{}, time.Second, 0, 0, true)
+	if _, resp := query(t, h, "u-1", introspect); len(resp.Errors) != 0 {
+		t.Fatalf("introspection on: errors = %+v", resp.Errors)
+	}
+}
+
+func TestOrderLoaderBatches(t *testing.T) {
+	var mu sync.Mutex
+	var sizes []int
+	l := newOrderLoader(context.Background(), func(_ context.Context, ids []string) (map[string]*ordersv1.Order, error) {
+		mu.Lock()
+		sizes = append(sizes, len(ids))
+		mu.Unlock()
+		byID := map[string]*ordersv1.Order{}
+		for _, id := range ids {
+			if id != "o-gone" {
+				byID[id] = &ordersv1.Order{OrderId: id}
+			}
+		}
+		return byID, nil
+	})
+
+	var wg sync.WaitGroup
+	errs := make([]error, maxBatch+51)
+	for i := range errs {
+		id := fmt.Sprintf("o-%d", i%(maxBatch+50))
+		if i == maxBatch+50 {
+			id = "o-gone"
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			o, err := l.Load(context.Background(), id)
+			if err == nil && o.GetOrderId() != id {
+				err = fmt.Errorf("got order %q", o.GetOrderId())
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+
+	for i, err := range errs[:maxBatch+50] {
+		if err != nil {
+			t.Fatalf("Load(o-%d) error: %v", i, err)
+		}
+	}
+	if code := status.Code(errs[maxBatch+50]); code != codes.NotFound {
+		t.Fatalf("Load(o-gone) code = %s, want NotFound", code)
+	}
+	// Each distinct id is fetched once, in batches of at most maxBatch.
+	total := 0
+	for _, n := range sizes {
+		if n > maxBatch {
+			t.Fatalf("batch sizes = %v, want at most %d", sizes, maxBatch)
+		}
+		total += n
+	}
+	if total != maxBatch+51 || len(sizes) < 2 {
+		t.Fatalf("batch sizes = %v, want %d ids in at least 2 batches", sizes, maxBatch+51)
+	}
+}
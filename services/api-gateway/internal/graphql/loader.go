@@ -0,0 +1,114 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+const (
+	// batchWait is how long the loader collects order ids before fetching
+	// them; gqlgen starts the resolvers of a list together, well within it.
+	batchWait = 2 * time.Millisecond
+	// maxBatch is the GetOrders limit of orders-service; a full batch is
+	// fetched without waiting.
+	maxBatch = 100
+)
+
+// orderLoader fetches each order at most once per query. gqlgen resolves
+// the order of every transaction of a page in parallel; the loader collects
+// the distinct order ids for batchWait and fetches them with one GetOrders
+// call per batch.
+type orderLoader struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, orderIDs []string) (map[string]*ordersv1.Order, error)
+
+	mu      sync.Mutex
+	entries map[string]*orderEntry
+	pending *orderBatch
+}
+
+type orderEntry struct {
+	done  chan struct{}
+	order *ordersv1.Order
+	err   error
+}
+
+type orderBatch struct {
+	ids     []string
+	entries []*orderEntry
+}
+
+// newOrderLoader returns a loader for one query; ctx bounds its fetches,
+// which serve every resolver waiting on a batch rather than the first one.
+func newOrderLoader(ctx context.Context, fetch func(ctx context.Context, orderIDs []string) (map[string]*ordersv1.Order, error)) *orderLoader {
+	return &orderLoader{ctx: ctx, fetch: fetch, entries: make(map[string]*orderEntry)}
+}
+
+// Load returns the order, joining a batch already pending or in flight for
+// it. Orders the batch does not return fail with NOT_FOUND, as in GetOrder.
+func (l *orderLoader) Load(ctx context.Context, orderID string) (*ordersv1.Order, error) {
+	// GetOrders answers with canonical ids.
+	if id, err := uuid.Parse(orderID); err == nil {
+		orderID = id.String()
+	}
+	l.mu.Lock()
+	e, ok := l.entries[orderID]
+	if !ok {
+		e = &orderEntry{done: make(chan struct{})}
+		l.entries[orderID] = e
+		b := l.pending
+		if b == nil {
+			b = &orderBatch{}
+			l.pending = b
+			time.AfterFunc(batchWait, func() { l.flush(b) })
+		}
+		b.ids = append(b.ids, orderID)
+		b.entries = append(b.entries, e)
+		if len(b.ids) >= maxBatch {
+			l.pending = nil
+			go l.run(b)
+		}
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-e.done:
+		return e.order, e.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// flush runs b when its wait is over, unless it already ran when full.
+func (l *orderLoader) flush(b *orderBatch) {
+	l.mu.Lock()
+	if l.pending != b {
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+	l.run(b)
+}
+
+func (l *orderLoader) run(b *orderBatch) {
+	orders, err := l.fetch(l.ctx, b.ids)
+	for i, e := range b.entries {
+		switch o, ok := orders[b.ids[i]]; {
+		case err != nil:
+			e.err = err
+		case !ok:
+			e.err = status.Error(codes.NotFound, "order not found")
+		default:
+			e.order = o
+		}
+		close(e.done)
+	}
+}
@@ -0,0 +1,120 @@
+package graphql
+
+import (
+	"strconv"
+	"strings"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/graphql/model"
+)
+
+func mapOrder(order *ordersv1.Order) *model.Order {
+	if order == nil {
+		return nil
+	}
+	mapped := &model.Order{
+		ID:          order.GetOrderId(),
+		UserID:      order.GetUserId(),
+		Amount:      order.GetAmount(),
+		Currency:    order.GetCurrency(),
+		Description: order.GetDescription(),
+		Status:      mapOrderStatus(order.GetStatus()),
+		Version:     order.GetVersion(),
+		PaidAmount:  order.GetPaidAmount(),
+		FeeAmount:   order.GetFeeAmount(),
+		Tags:        order.GetTags(),
+		Archived:    order.GetArchived(),
+	}
+	if mapped.Tags == nil {
+		mapped.Tags = []string{}
+	}
+	if order.GetCreatedAt() != nil {
+		t := order.GetCreatedAt().AsTime()
+		mapped.CreatedAt = &t
+	}
+	if order.GetUpdatedAt() != nil {
+		t := order.GetUpdatedAt().AsTime()
+		mapped.UpdatedAt = &t
+	}
+	if reason := order.GetPaymentFailureReason(); reason != "" {
+		mapped.PaymentFailureReason = &reason
+	}
+	if order.GetPayAt() != nil {
+		t := order.GetPayAt().AsTime()
+		mapped.PayAt = &t
+	}
+	if d := order.GetDispute(); d != nil {
+		mapped.Dispute = &model.OrderDispute{
+			Status:   mapDisputeStatus(d.GetStatus()),
+			Amount:   d.GetAmount(),
+			Reason:   d.GetReason(),
+			OpenedAt: d.GetOpenedAt().AsTime(),
+		}
+		if d.GetResolvedAt() != nil {
+			t := d.GetResolvedAt().AsTime()
+			mapped.Dispute.ResolvedAt = &t
+		}
+	}
+	return mapped
+}
+
+func mapOrderStatus(s ordersv1.OrderStatus) model.OrderStatus {
+	switch s {
+	case ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID:
+		return model.OrderStatusPartiallyPaid
+	case ordersv1.OrderStatus_ORDER_STATUS_FINISHED:
+		return model.OrderStatusFinished
+	case ordersv1.OrderStatus_ORDER_STATUS_CANCELLED:
+		return model.OrderStatusCancelled
+	case ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED:
+		return model.OrderStatusScheduled
+	case ordersv1.OrderStatus_ORDER_STATUS_DISPUTED:
+		return model.OrderStatusDisputed
+	case ordersv1.OrderStatus_ORDER_STATUS_REFUNDED:
+		return model.OrderStatusRefunded
+	default:
+		return model.OrderStatusNew
+	}
+}
+
+func mapDisputeStatus(s ordersv1.DisputeStatus) model.DisputeStatus {
+	switch s {
+	case ordersv1.DisputeStatus_DISPUTE_STATUS_REFUNDED:
+		return model.DisputeStatusRefunded
+	case ordersv1.DisputeStatus_DISPUTE_STATUS_UPHELD:
+		return model.DisputeStatusUpheld
+	default:
+		return model.DisputeStatusOpen
+	}
+}
+
+// mapAccountType is null for a payments-service that predates tiers.
+func mapAccountType(t paymentsv1.AccountType) *model.AccountType {
+	if t == paymentsv1.AccountType_ACCOUNT_TYPE_UNSPECIFIED {
+		return nil
+	}
+	name := model.AccountType(strings.TrimPrefix(t.String(), "ACCOUNT_TYPE_"))
+	return &name
+}
+
+func mapTransaction(tx *paymentsv1.Transaction) *model.Transaction {
+	mapped := &model.Transaction{
+		ID:     strconv.FormatInt(tx.GetId(), 10),
+		Amount: tx.GetAmount(),
+	}
+	if kind := model.TransactionKind(strings.TrimPrefix(tx.GetKind().String(), "TRANSACTION_KIND_")); kind.IsValid() {
+		mapped.Kind = &kind
+	}
+	if id := tx.GetPaymentId(); id != "" {
+		mapped.PaymentID = &id
+	}
+	if id := tx.GetOrderId(); id != "" {
+		mapped.OrderID = &id
+	}
+	if tx.GetCreatedAt() != nil {
+		mapped.CreatedAt = tx.GetCreatedAt().AsTime()
+	}
+	return mapped
+}
@@ -0,0 +1,16 @@
+package graphql
+
+import (
+	"time"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+// Resolver is the gqlgen root resolver. It holds no per-request state; the
+// caller and the order loader travel in the context.
+type Resolver struct {
+	orders     ordersv1.OrdersServiceClient
+	payments   paymentsv1.PaymentsServiceClient
+	hedgeDelay time.Duration
+}
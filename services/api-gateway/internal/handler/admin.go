@@ -0,0 +1,303 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
+)
+
+const defaultKeyRateLimit = 600
+
+func (h *Handler) CreateApiKey(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	if h.apiKeys == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "api keys are not configured")
+		return
+	}
+
+	var body gateway.CreateApiKeyRequest
+	if err := decodeJSON(r, &body); err != nil {
+		WriteBadRequest(w, "", err)
+		return
+	}
+	if body.Name == "" || len(body.Scopes) == 0 || len(body.Subjects) == 0 {
+		WriteBadRequest(w, "", errors.New("name, scopes and subjects are required"))
+		return
+	}
+	scopes := make([]string, 0, len(body.Scopes))
+	for _, s := range body.Scopes {
+		switch s {
+		case gateway.OrdersRead, gateway.OrdersWrite, gateway.PaymentsRead, gateway.PaymentsWrite:
+			scopes = append(scopes, string(s))
+		default:
+			WriteBadRequest(w, "", errors.New("unknown scope "+string(s)))
+			return
+		}
+	}
+	subjects := make([]string, 0, len(body.Subjects))
+	for _, s := range body.Subjects {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			WriteBadRequest(w, "", errors.New("subjects must not be empty"))
+			return
+		}
+		if !slices.Contains(subjects, s) {
+			subjects = append(subjects, s)
+		}
+	}
+	limit := defaultKeyRateLimit
+	if body.RateLimitPerMinute != nil {
+		if *body.RateLimitPerMinute <= 0 {
+			WriteBadRequest(w, "", errors.New("rate_limit_per_minute must be > 0"))
+			return
+		}
+		limit = int(*body.RateLimitPerMinute)
+	}
+	callQuota, volumeQuota := optQuota(body.MonthlyCallQuota), optQuota(body.MonthlyVolumeQuota)
+	if callQuota < 0 || volumeQuota < 0 {
+		WriteBadRequest(w, "", errors.New("quotas must be >= 0"))
+		return
+	}
+
+	secret, hash, err := apikey.NewSecret()
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "generate api key failed", "err", err)
+		WriteError(w, "", http.StatusInternalServerError, "internal error")
+		return
+	}
+	key, err := h.apiKeys.Create(r.Context(), apikey.Key{
+		ID:                 uuid.NewString(),
+		Name:               body.Name,
+		Scopes:             scopes,
+		Subjects:           subjects,
+		RateLimitPerMinute: limit,
+		MonthlyCallQuota:   callQuota,
+		MonthlyVolumeQuota: volumeQuota,
+	}, hash)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "create api key failed", "err", err, "name", body.Name)
+		WriteError(w, "", http.StatusInternalServerError, "failed to create api key")
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, gateway.CreateApiKeyResponse{
+		ApiKey: mapAPIKey(key),
+		Key:    secret,
+	})
+	h.logger.InfoContext(r.Context(), "api key created", "key_id", key.ID, "name", key.Name, "scopes", key.Scopes, "subjects", len(key.Subjects), "duration", time.Since(start))
+}
+
+func (h *Handler) RevokeApiKey(w http.ResponseWriter, r *http.Request, keyId gateway.ApiKeyIdPath) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	if h.apiKeys == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "api keys are not configured")
+		return
+	}
+	if err := h.apiKeys.Revoke(r.Context(), keyId); err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			WriteError(w, "", http.StatusNotFound, "api key not found")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "revoke api key failed", "err", err, "key_id", keyId)
+		WriteError(w, "", http.StatusInternalServerError, "failed to revoke api key")
+		return
+	}
+	if h.auth != nil {
+		h.auth.Forget(keyId)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	h.logger.InfoContext(r.Context(), "api key revoked", "key_id", keyId)
+}
+
+// UpdateApiKeyQuota changes the monthly quotas of a live key.
+func (h *Handler) UpdateApiKeyQuota(w http.ResponseWriter, r *http.Request, keyId gateway.ApiKeyIdPath) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	if h.apiKeys == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "api keys are not configured")
+		return
+	}
+	var body gateway.UpdateApiKeyQuotaRequest
+	if err := decodeJSON(r, &body); err != nil {
+		WriteBadRequest(w, "", err)
+		return
+	}
+	if optQuota(body.MonthlyCallQuota) < 0 || optQuota(body.MonthlyVolumeQuota) < 0 {
+		WriteBadRequest(w, "", errors.New("quotas must be >= 0"))
+		return
+	}
+	key, err := h.apiKeys.SetQuota(r.Context(), keyId, body.MonthlyCallQuota, body.MonthlyVolumeQuota)
+	if err != nil {
+		if errors.Is(err, apikey.ErrNotFound) {
+			WriteError(w, "", http.StatusNotFound, "api key not found")
+			return
+		}
+		h.logger.ErrorContext(r.Context(), "update api key quota failed", "err", err, "key_id", keyId)
+		WriteError(w, "", http.StatusInternalServerError, "failed to update api key")
+		return
+	}
+	if h.auth != nil {
+		h.auth.Forget(keyId)
+	}
+	writeJSON(w, http.StatusOK, mapAPIKey(key))
+	h.logger.InfoContext(r.Context(), "api key quota updated", "key_id", keyId, "monthly_call_quota", key.MonthlyCallQuota, "monthly_volume_quota", key.MonthlyVolumeQuota)
+}
+
+func optQuota(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// requireAdmin accepts a token with the admin role (already verified by the
+// rbac middleware) or the static X-Admin-Token.
+func (h *Handler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	return h.requireRoles(w, r, auth.RoleAdmin)
+}
+
+// requireRoles is requireAdmin for routes that more roles may call; the
+// X-Admin-Token is accepted on all of them.
+func (h *Handler) requireRoles(w http.ResponseWriter, r *http.Request, roles ...auth.Role) bool {
+	if c, ok := auth.FromContext(r.Context()); ok && c.HasAny(roles) {
+		return true
+	}
+	if h.adminToken == "" {
+		WriteError(w, "", http.StatusServiceUnavailable, "admin token is not configured")
+		return false
+	}
+	token := r.Header.Get("X-Admin-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		h.logger.WarnContext(r.Context(), "admin request with bad token", "path", r.URL.Path)
+		WriteError(w, "", http.StatusForbidden, "forbidden")
+		return false
+	}
+	return true
+}
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 500
+)
+
+func (h *Handler) ListUserAudit(w http.ResponseWriter, r *http.Request, userId gateway.AuditUserIdPath, params gateway.ListUserAuditParams) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	if h.auditLog == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "audit log is not configured")
+		return
+	}
+	limit := defaultAuditLimit
+	if params.Limit != nil {
+		limit = int(*params.Limit)
+	}
+	if limit <= 0 || limit > maxAuditLimit {
+		WriteBadRequest(w, "", errors.New("limit must be between 1 and 500"))
+		return
+	}
+
+	entries, err := h.auditLog.Recent(r.Context(), userId, limit)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "read audit log failed", "err", err, "user_id", userId)
+		WriteError(w, "", http.StatusInternalServerError, "failed to read audit log")
+		return
+	}
+	resp := gateway.AuditEntryList{Entries: make([]gateway.AuditEntry, 0, len(entries))}
+	for _, e := range entries {
+		item := gateway.AuditEntry{
+			OccurredAt:   e.OccurredAt,
+			Method:       e.Method,
+			Path:         e.Path,
+			UserId:       e.UserID,
+			UserVerified: e.UserVerified,
+			Status:       int32(e.Status),
+			LatencyMs:    e.Latency.Milliseconds(),
+		}
+		if e.IdempotencyKey != "" {
+			key := e.IdempotencyKey
+			item.IdempotencyKey = &key
+		}
+		resp.Entries = append(resp.Entries, item)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// GetUsage reports the flushed usage of every API key in a month.
+func (h *Handler) GetUsage(w http.ResponseWriter, r *http.Request, params gateway.GetUsageParams) {
+	if !h.requireAdmin(w, r) {
+		return
+	}
+	if h.usage == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "usage metering is not configured")
+		return
+	}
+	month := usage.Period(time.Now())
+	if params.Month != nil {
+		if _, err := time.Parse("2006-01", *params.Month); err != nil {
+			WriteBadRequest(w, "", errors.New("month must be YYYY-MM"))
+			return
+		}
+		month = *params.Month
+	}
+	keyID := ""
+	if params.KeyId != nil {
+		keyID = *params.KeyId
+	}
+
+	records, err := h.usage.List(r.Context(), month, keyID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "read usage failed", "err", err, "month", month)
+		WriteError(w, "", http.StatusInternalServerError, "failed to read usage")
+		return
+	}
+	resp := gateway.UsageReport{Month: month, Entries: make([]gateway.UsageEntry, 0, len(records))}
+	for _, rec := range records {
+		resp.Entries = append(resp.Entries, gateway.UsageEntry{
+			KeyId:              rec.KeyID,
+			KeyName:            rec.KeyName,
+			Calls:              rec.Usage.Calls,
+			Volume:             money.Amount(rec.Usage.Volume),
+			MonthlyCallQuota:   rec.Quota.Calls,
+			MonthlyVolumeQuota: rec.Quota.Volume,
+			UpdatedAt:          rec.UpdatedAt,
+		})
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func mapAPIKey(k apikey.Key) gateway.ApiKey {
+	scopes := make([]gateway.ApiKeyScope, 0, len(k.Scopes))
+	for _, s := range k.Scopes {
+		scopes = append(scopes, gateway.ApiKeyScope(s))
+	}
+	return gateway.ApiKey{
+		Id:                 k.ID,
+		Name:               k.Name,
+		Scopes:             scopes,
+		Subjects:           k.Subjects,
+		RateLimitPerMinute: int32(k.RateLimitPerMinute),
+		MonthlyCallQuota:   k.MonthlyCallQuota,
+		MonthlyVolumeQuota: k.MonthlyVolumeQuota,
+		CreatedAt:          k.CreatedAt,
+		RevokedAt:          k.RevokedAt,
+	}
+}
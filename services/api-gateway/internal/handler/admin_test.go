@@ -0,0 +1,290 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/audit"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
+)
+
+type memKeyStore struct {
+	keys map[string]apikey.Key
+}
+
+func (s *memKeyStore) Create(_ context.Context, key apikey.Key, hash string) (apikey.Key, error) {
+	key.CreatedAt = time.Now()
+	s.keys[hash] = key
+	return key, nil
+}
+
+func (s *memKeyStore) Lookup(_ context.Context, hash string) (apikey.Key, error) {
+	k, ok := s.keys[hash]
+	if !ok {
+		return apikey.Key{}, apikey.ErrNotFound
+	}
+	return k, nil
+}
+
+func (s *memKeyStore) Revoke(_ context.Context, id string) error {
+	for hash, k := range s.keys {
+		if k.ID == id {
+			now := time.Now()
+			k.RevokedAt = &now
+			s.keys[hash] = k
+			return nil
+		}
+	}
+	return apikey.ErrNotFound
+}
+
+func (s *memKeyStore) SetQuota(_ context.Context, id string, calls, volume *int64) (apikey.Key, error) {
+	for hash, k := range s.keys {
+		if k.ID == id && k.RevokedAt == nil {
+			if calls != nil {
+				k.MonthlyCallQuota = *calls
+			}
+			if volume != nil {
+				k.MonthlyVolumeQuota = *volume
+			}
+			s.keys[hash] = k
+			return k, nil
+		}
+	}
+	return apikey.Key{}, apikey.ErrNotFound
+}
+
+func TestCreateApiKeyRequiresAdmin(t *testing.T) {
+	body := `{"name":"ci","scopes":["orders:read"],"subjects":["u-1"]}`
+
+	h := New(nil, nil, nil, 0, 0, nil, nil, nil, nil, "", nil)
+	rec := httptest.NewRecorder()
+	h.CreateApiKey(rec, httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(body)))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("not configured: status = %d, want 503", rec.Code)
+	}
+
+	h = New(nil, nil, nil, 0, 0, &memKeyStore{keys: map[string]apikey.Key{}}, nil, nil, nil, "secret", nil)
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(body))
+	req.Header.Set("X-Admin-Token", "wrong")
+	rec = httptest.NewRecorder()
+	h.CreateApiKey(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("bad token: status = %d, want 403", rec.Code)
+	}
+}
+
+func TestCreateAndRevokeApiKey(t *testing.T) {
+	store := &memKeyStore{keys: map[string]apikey.Key{}}
+	h := New(nil, nil, nil, 0, 0, store, apikey.NewAuthenticator(store, time.Minute), nil, nil, "secret", nil)
+
+	req := httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(`{"name":"ci","scopes":["orders:read","admin:all"],"subjects":["u-1"]}`))
+	req.Header.Set("X-Admin-Token", "secret")
+	rec := httptest.NewRecorder()
+	h.CreateApiKey(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown scope: status = %d, want 400", rec.Code)
+	}
+
+	for _, subjects := range []string{``, `,"subjects":[]`, `,"subjects":[" "]`} {
+		req = httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(`{"name":"ci","scopes":["orders:read"]`+subjects+`}`))
+		req.Header.Set("X-Admin-Token", "secret")
+		rec = httptest.NewRecorder()
+		h.CreateApiKey(rec, req)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("subjects %q: status = %d, want 400", subjects, rec.Code)
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/admin/api-keys", strings.NewReader(`{"name":"ci","scopes":["orders:read"],"subjects":["u-1"," u-2 ","u-1"]}`))
+	req.Header.Set("X-Admin-Token", "secret")
+	rec = httptest.NewRecorder()
+	h.CreateApiKey(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("create: status = %d, want 201: %s", rec.Code, rec.Body)
+	}
+	var resp gateway.CreateApiKeyResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if resp.Key == "" || resp.ApiKey.RateLimitPerMinute != defaultKeyRateLimit {
+		t.Fatalf("create response = %+v, want secret and default rate limit", resp)
+	}
+	if !slices.Equal(resp.ApiKey.Subjects, []string{"u-1", "u-2"}) {
+		t.Fatalf("subjects = %q, want trimmed and deduplicated", resp.ApiKey.Subjects)
+	}
+	if _, ok := store.keys[apikey.Hash(resp.Key)]; !ok {
+		t.Fatal("store has no key under the secret's hash")
+	}
+
+	req = httptest.NewRequest(http.MethodDelete, "/admin/api-keys/"+resp.ApiKey.Id, nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec = httptest.NewRecorder()
+	h.RevokeApiKey(rec, req, resp.ApiKey.Id)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("revoke: status = %d, want 204", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.RevokeApiKey(rec, req, "missing")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("revoke missing: status = %d, want 404", rec.Code)
+	}
+}
+
+type memAuditStore struct {
+	entries []audit.Entry
+}
+
+func (s *memAuditStore) Insert(_ context.Context, entries []audit.Entry) error {
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *memAuditStore) Recent(_ context.Context, userID string, limit int) ([]audit.Entry, error) {
+	var out []audit.Entry
+	for i := len(s.entries) - 1; i >= 0 && len(out) < limit; i-- {
+		if s.entries[i].UserID == userID {
+			out = append(out, s.entries[i])
+		}
+	}
+	return out, nil
+}
+
+func TestListUserAudit(t *testing.T) {
+	store := &memAuditStore{entries: []audit.Entry{
+		{Method: http.MethodGet, Path: "/api/v1/orders", UserID: "u-1", Status: 200, Latency: 12 * time.Millisecond},
+		{Method: http.MethodPost, Path: "/api/v1/orders", UserID: "u-2", Status: 201},
+		{Method: http.MethodPost, Path: "/api/v1/payments/account/topup", UserID: "u-1", IdempotencyKey: "k-1", Status: 429},
+	}}
+	h := New(nil, nil, nil, 0, 0, nil, nil, store, nil, "secret", nil)
+
+	limit := int32(501)
+	req := httptest.NewRequest(http.MethodGet, "/admin/audit/users/u-1", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec := httptest.NewRecorder()
+	h.ListUserAudit(rec, req, "u-1", gateway.ListUserAuditParams{Limit: &limit})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("limit 501: status = %d, want 400", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ListUserAudit(rec, req, "u-1", gateway.ListUserAuditParams{})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp gateway.AuditEntryList
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if len(resp.Entries) != 2 || resp.Entries[0].Status != 429 || resp.Entries[0].IdempotencyKey == nil || resp.Entries[1].LatencyMs != 12 {
+		t.Fatalf("entries = %+v, want u-1 calls newest first", resp.Entries)
+	}
+
+	h = New(nil, nil, nil, 0, 0, nil, nil, nil, nil, "secret", nil)
+	rec = httptest.NewRecorder()
+	h.ListUserAudit(rec, req, "u-1", gateway.ListUserAuditParams{})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without audit store: status = %d, want 503", rec.Code)
+	}
+}
+
+func TestUpdateApiKeyQuota(t *testing.T) {
+	store := &memKeyStore{keys: map[string]apikey.Key{"h": {ID: "k-1", MonthlyCallQuota: 10, MonthlyVolumeQuota: 500}}}
+	h := New(nil, nil, nil, 0, 0, store, nil, nil, nil, "secret", nil)
+	update := func(keyID, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPatch, "/admin/api-keys/"+keyID, strings.NewReader(body))
+		req.Header.Set("X-Admin-Token", "secret")
+		rec := httptest.NewRecorder()
+		h.UpdateApiKeyQuota(rec, req, keyID)
+		return rec
+	}
+
+	if rec := update("k-1", `{"monthly_call_quota":-1}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("negative quota: status = %d, want 400", rec.Code)
+	}
+	if rec := update("missing", `{"monthly_call_quota":1}`); rec.Code != http.StatusNotFound {
+		t.Fatalf("missing key: status = %d, want 404", rec.Code)
+	}
+	rec := update("k-1", `{"monthly_call_quota":0}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var key gateway.ApiKey
+	if err := json.NewDecoder(rec.Body).Decode(&key); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	if key.MonthlyCallQuota != 0 || key.MonthlyVolumeQuota != 500 {
+		t.Fatalf("quotas = %d, %d; want the call quota lifted and the volume quota kept", key.MonthlyCallQuota, key.MonthlyVolumeQuota)
+	}
+}
+
+type memUsageStore struct {
+	records []usage.Record
+}
+
+func (s *memUsageStore) Save(context.Context, string, map[string]usage.Usage) error { return nil }
+
+func (s *memUsageStore) List(_ context.Context, period, keyID string) ([]usage.Record, error) {
+	var out []usage.Record
+	for _, r := range s.records {
+		if r.Period == period && (keyID == "" || r.KeyID == keyID) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func TestGetUsage(t *testing.T) {
+	store := &memUsageStore{records: []usage.Record{
+		{KeyID: "k-1", KeyName: "shop", Period: "2026-01", Usage: usage.Usage{Calls: 40, Volume: 90000}, Quota: usage.Quota{Calls: 100}},
+		{KeyID: "k-2", KeyName: "crm", Period: "2026-01", Usage: usage.Usage{Calls: 3}},
+		{KeyID: "k-1", KeyName: "shop", Period: usage.Period(time.Now()), Usage: usage.Usage{Calls: 1}},
+	}}
+	h := New(nil, nil, nil, 0, 0, nil, nil, nil, store, "secret", nil)
+	get := func(params gateway.GetUsageParams) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/admin/usage", nil)
+		req.Header.Set("X-Admin-Token", "secret")
+		rec := httptest.NewRecorder()
+		h.GetUsage(rec, req, params)
+		return rec
+	}
+	decode := func(rec *httptest.ResponseRecorder) gateway.UsageReport {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		var resp gateway.UsageReport
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("decode response: %v", err)
+		}
+		return resp
+	}
+
+	bad := "2026-13"
+	if rec := get(gateway.GetUsageParams{Month: &bad}); rec.Code != http.StatusBadRequest {
+		t.Fatalf("month %s: status = %d, want 400", bad, rec.Code)
+	}
+	if resp := decode(get(gateway.GetUsageParams{})); resp.Month != usage.Period(time.Now()) || len(resp.Entries) != 1 {
+		t.Fatalf("current month = %+v, want one entry", resp)
+	}
+	month, keyID := "2026-01", "k-1"
+	resp := decode(get(gateway.GetUsageParams{Month: &month, KeyId: &keyID}))
+	if len(resp.Entries) != 1 || resp.Entries[0].Volume != 90000 || resp.Entries[0].MonthlyCallQuota != 100 || resp.Entries[0].KeyName != "shop" {
+		t.Fatalf("entries = %+v, want k-1 of 2026-01", resp.Entries)
+	}
+
+	h = New(nil, nil, nil, 0, 0, nil, nil, nil, nil, "secret", nil)
+	if rec := get(gateway.GetUsageParams{}); rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("without usage store: status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/testsupport"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+)
+
+// Tests through the generated router and real gRPC clients against the fakes
+// in testsupport, covering request mapping and error mapping end to end.
+
+func newBackendServer(t *testing.T) (*testsupport.Backends, http.Handler) {
+	t.Helper()
+	b := testsupport.Start(t)
+	h := New(b.OrdersClient, b.PaymentsClient, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	return b, gateway.HandlerWithOptions(h, gateway.ChiServerOptions{BaseURL: "/api/v1"})
+}
+
+func doBackend(t *testing.T, srv http.Handler, method, path, body string, header map[string]string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	rec := httptest.NewRecorder()
+	srv.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeErrorCode(t *testing.T, rec *httptest.ResponseRecorder) string {
+	t.Helper()
+	var resp gateway.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("decode error body %q: %v", rec.Body.String(), err)
+	}
+	if resp.Code == nil {
+		return ""
+	}
+	return *resp.Code
+}
+
+func TestBackendCreateOrderIdempotent(t *testing.T) {
+	b, srv := newBackendServer(t)
+	header := map[string]string{"X-User-Id": "u-1", "Idempotency-Key": "k-1"}
+	body := `{"amount": "1500", "description": "coffee", "tags": ["food"]}`
+
+	first := doBackend(t, srv, http.MethodPost, "/api/v1/orders", body, header)
+	if first.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want 201; body %s", first.Code, first.Body)
+	}
+	var created gateway.CreateOrderResponse
+	if err := json.Unmarshal(first.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.UserId != "u-1" || created.Order.Amount != 1500 || created.Order.Status != gateway.OrderStatusNEW || created.Order.Description != "coffee" {
+		t.Fatalf("unexpected order %+v", created)
+	}
+
+	again := doBackend(t, srv, http.MethodPost, "/api/v1/orders", body, header)
+	var repeated gateway.CreateOrderResponse
+	if err := json.Unmarshal(again.Body.Bytes(), &repeated); err != nil {
+		t.Fatal(err)
+	}
+	if repeated.Order.OrderId != created.Order.OrderId {
+		t.Fatalf("repeated create returned order %q, want %q", repeated.Order.OrderId, created.Order.OrderId)
+	}
+	if got := b.Orders.Calls("CreateOrder"); got != 2 {
+		t.Fatalf("CreateOrder calls = %d, want 2", got)
+	}
+
+	rec := doBackend(t, srv, http.MethodGet, "/api/v1/orders/"+created.Order.OrderId, "", map[string]string{"X-User-Id": "u-1"})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("get status = %d, want 200; body %s", rec.Code, rec.Body)
+	}
+}
+
+func TestBackendErrorMapping(t *testing.T) {
+	b, srv := newBackendServer(t)
+	b.Payments.AddAccount(&paymentsv1.Account{UserId: "u-1", Balance: 100, Currency: "RUB", Version: 3})
+
+	tests := []struct {
+		name     string
+		prepare  func()
+		method   string
+		path     string
+		body     string
+		wantCode int
+		wantErr  string
+	}{
+		{
+			name:     "unknown order",
+			method:   http.MethodGet,
+			path:     "/api/v1/orders/order-1",
+			wantCode: http.StatusNotFound,
+			wantErr:  httperr.CodeNotFound,
+		},
+		{
+			name:     "stale account version",
+			method:   http.MethodPost,
+			path:     "/api/v1/payments/account/topup",
+			body:     `{"amount": "50", "expected_version": 2}`,
+			wantCode: http.StatusConflict,
+			wantErr:  httperr.CodeConflict,
+		},
+		{
+			name:     "backend unavailable",
+			prepare:  func() { b.Payments.FailNext("GetBalance", status.Error(codes.Unavailable, "down")) },
+			method:   http.MethodGet,
+			path:     "/api/v1/payments/account/balance",
+			wantCode: http.StatusServiceUnavailable,
+			wantErr:  httperr.CodeUnavailable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.prepare != nil {
+				tt.prepare()
+			}
+			rec := doBackend(t, srv, tt.method, tt.path, tt.body, map[string]string{"X-User-Id": "u-1", "Idempotency-Key": "k-" + tt.name})
+			if rec.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d; body %s", rec.Code, tt.wantCode, rec.Body)
+			}
+			if got := decodeErrorCode(t, rec); got != tt.wantErr {
+				t.Fatalf("code = %q, want %q", got, tt.wantErr)
+			}
+		})
+	}
+
+	if got := b.Payments.Balance("u-1"); got != 100 {
+		t.Fatalf("balance = %d after rejected top-up, want 100", got)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+// parseOrderFields reads the fields query parameter of GetOrder and
+// ListOrders. The JSON properties of an order are named after its proto
+// fields, so the same names make the read mask sent to orders-service. nil
+// means the whole order.
+func parseOrderFields(raw *gateway.OrderFieldsQuery) ([]string, error) {
+	if raw == nil || strings.TrimSpace(*raw) == "" {
+		return nil, nil
+	}
+	known := (&ordersv1.Order{}).ProtoReflect().Descriptor().Fields()
+	seen := make(map[string]bool)
+	var fields []string
+	for _, name := range strings.Split(*raw, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		if known.ByName(protoreflect.Name(name)) == nil {
+			return nil, fmt.Errorf("fields: unknown order field %q", name)
+		}
+		seen[name] = true
+		fields = append(fields, name)
+	}
+	return fields, nil
+}
+
+// orderReadMask turns the selected fields into the read mask of the RPC.
+func orderReadMask(fields []string) *fieldmaskpb.FieldMask {
+	if len(fields) == 0 {
+		return nil
+	}
+	return &fieldmaskpb.FieldMask{Paths: fields}
+}
+
+// selectOrderFields returns order with only the selected properties; the
+// required ones would otherwise come back as zero values.
+func selectOrderFields(order gateway.Order, fields []string) (any, error) {
+	if len(fields) == 0 {
+		return order, nil
+	}
+	data, err := json.Marshal(order)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, name := range fields {
+		if v, ok := all[name]; ok {
+			selected[name] = v
+		}
+	}
+	return selected, nil
+}
@@ -3,164 +3,742 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/google/uuid"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
 	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
 	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
 	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
-)
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
 
-const requestTimeout = 5 * time.Second
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/apikey"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/audit"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/fanout"
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/usage"
+)
 
 type Handler struct {
-	orders   ordersv1.OrdersServiceClient
-	payments paymentsv1.PaymentsServiceClient
+	orders        ordersv1.OrdersServiceClient
+	payments      paymentsv1.PaymentsServiceClient
+	notifications notificationsv1.NotificationsServiceClient
+
+	budget     time.Duration
+	hedgeDelay time.Duration
+
+	apiKeys    apikey.Store
+	auth       *apikey.Authenticator
+	auditLog   audit.Store
+	usage      usage.Store
+	adminToken string
+	sessions   *auth.Sessions
+
+	// draining is closed by Drain when the server shuts down.
+	draining  chan struct{}
+	drainOnce sync.Once
+
+	logger *slog.Logger
 }
 
-var logger = slog.Default().With("service", "api-gateway", "component", "handler")
+// New builds the gateway handler. budget bounds all backend calls made for one
+// request; hedgeDelay enables hedged reads when positive. notifications,
+// apiKeys, auth, auditLog, usageStore and sessions may be nil, which disables
+// the matching endpoints.
+func New(orders ordersv1.OrdersServiceClient, payments paymentsv1.PaymentsServiceClient, notifications notificationsv1.NotificationsServiceClient, budget, hedgeDelay time.Duration, apiKeys apikey.Store, auth *apikey.Authenticator, auditLog audit.Store, usageStore usage.Store, adminToken string, sessions *auth.Sessions) *Handler {
+	logger := slog.Default().With("service", "api-gateway", "component", "handler")
+	logger.Info("handler initialized", "budget", budget, "hedge_delay", hedgeDelay)
+	return &Handler{orders: orders, payments: payments, notifications: notifications, budget: budget, hedgeDelay: hedgeDelay, apiKeys: apiKeys, auth: auth, auditLog: auditLog, usage: usageStore, adminToken: adminToken, sessions: sessions, draining: make(chan struct{}), logger: logger}
+}
 
-func New(orders ordersv1.OrdersServiceClient, payments paymentsv1.PaymentsServiceClient) *Handler {
-	logger.Info("handler initialized")
-	return &Handler{orders: orders, payments: payments}
+// Drain ends the order waits in flight, and those started after it, with
+// their usual timed-out response, so that a shutdown does not sit out the
+// long polls. It is meant for http.Server.RegisterOnShutdown.
+func (h *Handler) Drain() {
+	h.drainOnce.Do(func() { close(h.draining) })
 }
 
 func (h *Handler) ListOrders(w http.ResponseWriter, r *http.Request, params gateway.ListOrdersParams) {
 	start := time.Now()
-	userID, _ := resolveUserID(params.XUserId)
-	logger.Info("list orders start", "user_id", userID)
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "list orders start", "user_id", userID)
 
-	req := &ordersv1.ListOrdersRequest{UserId: userID}
+	fields, err := parseOrderFields(params.Fields)
+	if err != nil {
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+	req := &ordersv1.ListOrdersRequest{UserId: userID, ReadMask: orderReadMask(fields)}
 	if params.Limit != nil {
 		req.Limit = int32(*params.Limit)
 	}
 	if params.PageToken != nil {
 		req.PageToken = string(*params.PageToken)
 	}
+	if params.Tag != nil {
+		req.Tag = string(*params.Tag)
+	}
+	if params.IncludeArchived != nil {
+		req.IncludeArchived = bool(*params.IncludeArchived)
+	}
 
-	ctx, cancel := withTimeout(r)
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
-	resp, err := h.orders.ListOrders(ctx, req)
+	resp, err := fanout.Hedged(ctx, h.hedgeDelay, func(ctx context.Context) (*ordersv1.ListOrdersResponse, error) {
+		return h.orders.ListOrders(ctx, req)
+	})
 	if err != nil {
-		logger.Error("list orders grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "list orders grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
 
-	out := make([]gateway.Order, 0, len(resp.GetOrders()))
+	out := make([]any, 0, len(resp.GetOrders()))
 	for _, order := range resp.GetOrders() {
-		if mapped := mapOrder(order); mapped != nil {
-			out = append(out, *mapped)
+		mapped := mapOrder(order)
+		if mapped == nil {
+			continue
+		}
+		selected, err := selectOrderFields(*mapped, fields)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "list orders field selection failed", "err", err, "user_id", userID, "duration", time.Since(start))
+			WriteError(w, userID, http.StatusInternalServerError, "internal error")
+			return
 		}
+		out = append(out, selected)
 	}
 
-	writeJSON(w, http.StatusOK, gateway.ListOrdersResponse{
-		UserId: userID,
-		Orders: out,
-	})
-	logger.Info("list orders completed", "user_id", userID, "orders_count", len(out), "duration", time.Since(start))
+	writeJSON(w, http.StatusOK, map[string]any{"user_id": userID, "orders": out})
+	h.logger.InfoContext(ctx, "list orders completed", "user_id", userID, "orders_count", len(out), "duration", time.Since(start))
 }
 
 func (h *Handler) CreateOrder(w http.ResponseWriter, r *http.Request, params gateway.CreateOrderParams) {
 	start := time.Now()
-	userID, _ := resolveUserID(params.XUserId)
-	idempotencyKey := getHeader(params.IdempotencyKey)
-	logger.Info("create order start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	idempotencyKey := getHeader(&params.IdempotencyKey)
+	h.logger.DebugContext(r.Context(), "create order start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
 
+	req, err := decodeCreateOrder(r, userID)
+	if err != nil {
+		h.logger.ErrorContext(r.Context(), "create order validation failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+	req.IdempotencyKey = idempotencyKey
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.CreateOrder(ctx, req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "create order grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	usage.AddVolume(ctx, resp.GetOrder().GetAmount())
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "create order mapping failed", "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	code := http.StatusCreated
+	if params.Prefer != nil && prefersAsync(string(*params.Prefer)) {
+		// The order is already persisted; the client polls Location for the
+		// payment outcome instead of treating 201 as final.
+		w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+mapped.OrderId)
+		w.Header().Set("Preference-Applied", "respond-async")
+		code = http.StatusAccepted
+	}
+	writeJSON(w, code, gateway.CreateOrderResponse{
+		UserId:         userID,
+		Order:          *mapped,
+		DiscountAmount: discountAmount(resp.GetDiscountAmount()),
+	})
+	h.logger.InfoContext(ctx, "create order completed", "user_id", userID, "order_id", mapped.OrderId, "status", code, "duration", time.Since(start))
+}
+
+// ValidateOrder runs the checks of CreateOrder without creating the order.
+func (h *Handler) ValidateOrder(w http.ResponseWriter, r *http.Request, params gateway.ValidateOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "validate order start", "user_id", userID)
+
+	req, err := decodeCreateOrder(r, userID)
+	if err != nil {
+		h.logger.InfoContext(r.Context(), "validate order rejected", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.ValidateOrder(ctx, req)
+	if err != nil {
+		h.logger.InfoContext(ctx, "validate order grpc rejected", "err", err, "user_id", userID, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.ValidateOrderResponse{
+		UserId:         userID,
+		Amount:         gateway.MoneyAmount(resp.GetAmount()),
+		Currency:       gateway.Currency(resp.GetCurrency()),
+		DiscountAmount: discountAmount(resp.GetDiscountAmount()),
+	})
+	h.logger.InfoContext(ctx, "validate order completed", "user_id", userID, "amount", resp.GetAmount(), "duration", time.Since(start))
+}
+
+// decodeCreateOrder reads the body shared by CreateOrder and ValidateOrder.
+func decodeCreateOrder(r *http.Request, userID string) (*ordersv1.CreateOrderRequest, error) {
 	var body gateway.CreateOrderRequest
 	if err := decodeJSON(r, &body); err != nil {
-		logger.Error("create order decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
-		writeError(w, userID, http.StatusBadRequest, err.Error())
-		return
+		return nil, err
 	}
 	if body.Amount <= 0 || strings.TrimSpace(body.Description) == "" {
-		logger.Error("create order validation failed", "user_id", userID, "amount", body.Amount, "duration", time.Since(start))
-		writeError(w, userID, http.StatusBadRequest, "amount must be > 0 and description is required")
+		return nil, errors.New("amount must be > 0 and description is required")
+	}
+
+	req := &ordersv1.CreateOrderRequest{
+		UserId:            userID,
+		Amount:            int64(body.Amount),
+		Currency:          optString(body.Currency),
+		Description:       body.Description,
+		PayInInstallments: body.PayInInstallments != nil && *body.PayInInstallments,
+		Force:             body.Force != nil && *body.Force,
+		PromoCode:         optString(body.PromoCode),
+		PaymentMethod:     optString(body.PaymentMethod),
+	}
+	if body.Metadata != nil {
+		req.Metadata = *body.Metadata
+	}
+	if body.Tags != nil {
+		req.Tags = *body.Tags
+	}
+	if body.PayAt != nil {
+		req.PayAt = timestamppb.New(*body.PayAt)
+	}
+	return req, nil
+}
+
+func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.GetOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
 		return
 	}
+	h.logger.DebugContext(r.Context(), "get order start", "user_id", userID, "order_id", orderId)
 
-	ctx, cancel := withTimeout(r)
+	fields, err := parseOrderFields(params.Fields)
+	if err != nil {
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
-	resp, err := h.orders.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+	resp, err := fanout.Hedged(ctx, h.hedgeDelay, func(ctx context.Context) (*ordersv1.GetOrderResponse, error) {
+		return h.orders.GetOrder(ctx, &ordersv1.GetOrderRequest{
+			UserId:   userID,
+			OrderId:  string(orderId),
+			ReadMask: orderReadMask(fields),
+		})
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "get order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	order, err := selectOrderFields(*mapped, fields)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get order field selection failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "internal error")
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"user_id": userID, "order": order})
+	h.logger.InfoContext(ctx, "get order completed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+}
+
+// Bounds of the WaitOrder timeout; orders-service enforces the same maximum.
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 60 * time.Second
+)
+
+// WaitOrder long-polls orders-service until the order leaves NEW. The wait
+// comes on top of the usual request budget and is never hedged.
+func (h *Handler) WaitOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.WaitOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	timeout := defaultWaitTimeout
+	if params.Timeout != nil {
+		d, err := time.ParseDuration(string(*params.Timeout))
+		if err != nil || d <= 0 || d > maxWaitTimeout {
+			h.logger.ErrorContext(r.Context(), "wait order invalid timeout", "user_id", userID, "timeout", *params.Timeout)
+			WriteError(w, userID, http.StatusBadRequest, fmt.Sprintf("timeout must be a duration in (0, %s]", maxWaitTimeout))
+			return
+		}
+		timeout = d
+	}
+	h.logger.DebugContext(r.Context(), "wait order start", "user_id", userID, "order_id", orderId, "timeout", timeout)
+
+	ctx, cancel := fanout.WithBudget(r.Context(), timeout+h.budget)
+	defer cancel()
+
+	resp, err := h.waitOrder(ctx, &ordersv1.WaitOrderRequest{
+		UserId:  userID,
+		OrderId: string(orderId),
+		Timeout: durationpb.New(timeout),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "wait order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "wait order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.WaitOrderResponse{
+		UserId:   userID,
+		Order:    *mapped,
+		TimedOut: resp.GetTimedOut(),
+	})
+	h.logger.InfoContext(ctx, "wait order completed", "user_id", userID, "order_id", mapped.OrderId, "timed_out", resp.GetTimedOut(), "duration", time.Since(start))
+}
+
+// waitOrder runs the wait of req until Drain is called; a drained wait
+// answers with the order as it is now, timed out.
+func (h *Handler) waitOrder(ctx context.Context, req *ordersv1.WaitOrderRequest) (*ordersv1.WaitOrderResponse, error) {
+	waitCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-h.draining:
+			cancel()
+		case <-waitCtx.Done():
+		}
+	}()
+
+	resp, err := h.orders.WaitOrder(waitCtx, req)
+	select {
+	case <-h.draining:
+	default:
+		return resp, err
+	}
+	if err == nil || ctx.Err() != nil {
+		return resp, err
+	}
+	h.logger.InfoContext(ctx, "wait order drained", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	getCtx, cancelGet := fanout.WithBudget(ctx, h.budget)
+	defer cancelGet()
+	order, err := h.orders.GetOrder(getCtx, &ordersv1.GetOrderRequest{UserId: req.GetUserId(), OrderId: req.GetOrderId()})
+	if err != nil {
+		return nil, err
+	}
+	return &ordersv1.WaitOrderResponse{Order: order.GetOrder(), TimedOut: true}, nil
+}
+
+// UpdateOrder turns the fields present in the body into the update mask, so
+// a client changes only what it sends.
+func (h *Handler) UpdateOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.UpdateOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "update order start", "user_id", userID, "order_id", orderId)
+
+	var body gateway.UpdateOrderRequest
+	if err := decodeJSON(r, &body); err != nil {
+		h.logger.ErrorContext(r.Context(), "update order decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+	req := &ordersv1.UpdateOrderRequest{
+		UserId:     userID,
+		OrderId:    string(orderId),
+		UpdateMask: &fieldmaskpb.FieldMask{},
+	}
+	if body.Description != nil {
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "description")
+		req.Description = *body.Description
+	}
+	if body.Metadata != nil {
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "metadata")
+		req.Metadata = *body.Metadata
+	}
+	if body.Tags != nil {
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, "tags")
+		req.Tags = *body.Tags
+	}
+	if body.Version != nil {
+		req.Version = *body.Version
+	}
+	if len(req.UpdateMask.Paths) == 0 {
+		h.logger.ErrorContext(r.Context(), "update order validation failed", "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, "nothing to update")
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.UpdateOrder(ctx, req)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "update order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "update order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.UpdateOrderResponse{
+		UserId: userID,
+		Order:  *mapped,
+	})
+	h.logger.InfoContext(ctx, "update order completed", "user_id", userID, "order_id", mapped.OrderId, "paths", req.UpdateMask.GetPaths(), "duration", time.Since(start))
+}
+
+func (h *Handler) PayOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.PayOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	idempotencyKey := getHeader(&params.IdempotencyKey)
+	h.logger.DebugContext(r.Context(), "pay order start", "user_id", userID, "order_id", orderId, "has_idempotency_key", idempotencyKey != "")
+
+	var body gateway.PayOrderRequest
+	if err := decodeJSON(r, &body); err != nil {
+		h.logger.ErrorContext(r.Context(), "pay order decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.Amount <= 0 {
+		h.logger.ErrorContext(r.Context(), "pay order validation failed", "user_id", userID, "amount", body.Amount, "duration", time.Since(start))
+		WriteErrorCode(w, userID, http.StatusBadRequest, CodeInvalidAmount, "amount must be > 0")
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.PayOrder(ctx, &ordersv1.PayOrderRequest{
 		UserId:         userID,
-		Amount:         body.Amount,
-		Description:    body.Description,
+		OrderId:        string(orderId),
+		Amount:         int64(body.Amount),
+		Currency:       optString(body.Currency),
 		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
-		logger.Error("create order grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "pay order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
 
+	usage.AddVolume(ctx, int64(body.Amount))
+
 	mapped := mapOrder(resp.GetOrder())
 	if mapped == nil {
-		logger.Error("create order mapping failed", "user_id", userID, "duration", time.Since(start))
-		writeError(w, userID, http.StatusInternalServerError, "empty order response")
+		h.logger.ErrorContext(ctx, "pay order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, gateway.CreateOrderResponse{
+	writeJSON(w, http.StatusAccepted, gateway.PayOrderResponse{
+		UserId:    userID,
+		Order:     *mapped,
+		PaymentId: resp.GetPaymentId(),
+	})
+	h.logger.InfoContext(ctx, "pay order completed", "user_id", userID, "order_id", mapped.OrderId, "payment_id", resp.GetPaymentId(), "duration", time.Since(start))
+}
+
+func (h *Handler) TransferOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.TransferOrderParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "transfer order start", "user_id", userID, "order_id", orderId)
+
+	var body gateway.TransferOrderRequest
+	if err := decodeJSON(r, &body); err != nil {
+		h.logger.ErrorContext(r.Context(), "transfer order decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+	if body.ToUserId == "" {
+		h.logger.ErrorContext(r.Context(), "transfer order validation failed", "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, "to_user_id is required")
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.TransferOrder(ctx, &ordersv1.TransferOrderRequest{
+		UserId:   userID,
+		OrderId:  string(orderId),
+		ToUserId: body.ToUserId,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "transfer order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	transfer := resp.GetTransfer()
+	if transfer == nil {
+		h.logger.ErrorContext(ctx, "transfer order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty transfer response")
+		return
+	}
+	writeJSON(w, http.StatusOK, gateway.TransferOrderResponse{
+		UserId: userID,
+		Transfer: gateway.OrderTransfer{
+			TransferId: transfer.GetTransferId(),
+			OrderId:    transfer.GetOrderId(),
+			FromUserId: transfer.GetFromUserId(),
+			ToUserId:   transfer.GetToUserId(),
+			Status:     mapTransferStatus(transfer.GetStatus()),
+			CreatedAt:  transfer.GetCreatedAt().AsTime(),
+		},
+	})
+	h.logger.InfoContext(ctx, "transfer order completed", "user_id", userID, "order_id", orderId, "transfer_id", transfer.GetTransferId(), "duration", time.Since(start))
+}
+
+func (h *Handler) AcceptOrderTransfer(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.AcceptOrderTransferParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "accept order transfer start", "user_id", userID, "order_id", orderId)
+
+	if err := decodeOptionalJSON(r); err != nil {
+		h.logger.ErrorContext(r.Context(), "accept order transfer decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.AcceptOrderTransfer(ctx, &ordersv1.AcceptOrderTransferRequest{
+		UserId:  userID,
+		OrderId: string(orderId),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "accept order transfer grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "accept order transfer mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.AcceptOrderTransferResponse{
 		UserId: userID,
 		Order:  *mapped,
 	})
-	logger.Info("create order completed", "user_id", userID, "order_id", mapped.OrderId, "duration", time.Since(start))
+	h.logger.InfoContext(ctx, "accept order transfer completed", "user_id", userID, "order_id", mapped.OrderId, "duration", time.Since(start))
 }
 
-func (h *Handler) GetOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.GetOrderParams) {
+func (h *Handler) CancelOrder(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.CancelOrderParams) {
 	start := time.Now()
-	userID, _ := resolveUserID(params.XUserId)
-	logger.Info("get order start", "user_id", userID, "order_id", orderId)
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "cancel order start", "user_id", userID, "order_id", orderId)
 
-	ctx, cancel := withTimeout(r)
+	if err := decodeOptionalJSON(r); err != nil {
+		h.logger.ErrorContext(r.Context(), "cancel order decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
-	resp, err := h.orders.GetOrder(ctx, &ordersv1.GetOrderRequest{
+	resp, err := h.orders.CancelOrder(ctx, &ordersv1.CancelOrderRequest{
 		UserId:  userID,
 		OrderId: string(orderId),
 	})
 	if err != nil {
-		logger.Error("get order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "cancel order grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
 
 	mapped := mapOrder(resp.GetOrder())
 	if mapped == nil {
-		logger.Error("get order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
-		writeError(w, userID, http.StatusInternalServerError, "empty order response")
+		h.logger.ErrorContext(ctx, "cancel order mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
 		return
 	}
 
-	writeJSON(w, http.StatusOK, gateway.GetOrderResponse{
+	writeJSON(w, http.StatusOK, gateway.CancelOrderResponse{
 		UserId: userID,
 		Order:  *mapped,
 	})
-	logger.Info("get order completed", "user_id", userID, "order_id", mapped.OrderId, "duration", time.Since(start))
+	h.logger.InfoContext(ctx, "cancel order completed", "user_id", userID, "order_id", mapped.OrderId, "duration", time.Since(start))
+}
+
+func (h *Handler) RetryPayment(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.RetryPaymentParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "retry payment start", "user_id", userID, "order_id", orderId)
+
+	if err := decodeOptionalJSON(r); err != nil {
+		h.logger.ErrorContext(r.Context(), "retry payment decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{
+		UserId:  userID,
+		OrderId: string(orderId),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "retry payment grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	mapped := mapOrder(resp.GetOrder())
+	if mapped == nil {
+		h.logger.ErrorContext(ctx, "retry payment mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty order response")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.RetryPaymentResponse{
+		UserId:      userID,
+		Order:       *mapped,
+		RetriesLeft: resp.GetRetriesLeft(),
+	})
+	h.logger.InfoContext(ctx, "retry payment completed", "user_id", userID, "order_id", mapped.OrderId, "retries_left", resp.GetRetriesLeft(), "duration", time.Since(start))
+}
+
+func (h *Handler) ConfirmPayment(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.ConfirmPaymentParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	h.logger.DebugContext(r.Context(), "confirm payment start", "user_id", userID, "order_id", orderId)
+
+	var body gateway.ConfirmPaymentRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := decodeJSON(r, &body); err != nil {
+			h.logger.ErrorContext(r.Context(), "confirm payment decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+			WriteError(w, userID, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.payments.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{
+		UserId:    userID,
+		OrderId:   string(orderId),
+		PaymentId: optString(body.PaymentId),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "confirm payment grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+
+	out := gateway.ConfirmPaymentResponse{
+		UserId:    userID,
+		OrderId:   resp.GetOrderId(),
+		PaymentId: resp.GetPaymentId(),
+		Status:    gateway.ConfirmPaymentResponseStatus(strings.TrimPrefix(resp.GetStatus().String(), "CONFIRM_PAYMENT_STATUS_")),
+	}
+	if resp.GetReason() != "" {
+		reason := resp.GetReason()
+		out.Reason = &reason
+	}
+	writeJSON(w, http.StatusOK, out)
+	h.logger.InfoContext(ctx, "confirm payment completed", "user_id", userID, "order_id", resp.GetOrderId(), "payment_id", resp.GetPaymentId(), "status", out.Status, "duration", time.Since(start))
 }
 
 func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request, params gateway.CreateAccountParams) {
 	start := time.Now()
-	userID, _ := resolveUserID(params.XUserId)
-	idempotencyKey := getHeader(params.IdempotencyKey)
-	logger.Info("create account start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	idempotencyKey := getHeader(&params.IdempotencyKey)
+	h.logger.DebugContext(r.Context(), "create account start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
 
 	if err := decodeOptionalJSON(r); err != nil {
-		logger.Error("create account decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
-		writeError(w, userID, http.StatusBadRequest, err.Error())
+		h.logger.ErrorContext(r.Context(), "create account decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
 		return
 	}
 
-	ctx, cancel := withTimeout(r)
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
 	resp, err := h.payments.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{
@@ -168,88 +746,133 @@ func (h *Handler) CreateAccount(w http.ResponseWriter, r *http.Request, params g
 		IdempotencyKey: idempotencyKey,
 	})
 	if err != nil {
-		logger.Error("create account grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "create account grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
 
 	writeJSON(w, http.StatusCreated, gateway.CreateAccountResponse{
-		UserId:  userID,
-		Balance: resp.GetAccount().GetBalance(),
+		UserId:      userID,
+		Balance:     money.Amount(resp.GetAccount().GetBalance()),
+		Currency:    resp.GetAccount().GetCurrency(),
+		Version:     accountVersion(resp.GetAccount().GetVersion()),
+		AccountType: accountType(resp.GetAccount().GetAccountType()),
 	})
-	logger.Info("create account completed", "user_id", userID, "duration", time.Since(start))
+	h.logger.InfoContext(ctx, "create account completed", "user_id", userID, "duration", time.Since(start))
 }
 
 func (h *Handler) GetBalance(w http.ResponseWriter, r *http.Request, params gateway.GetBalanceParams) {
 	start := time.Now()
-	userID := string(params.XUserId)
-	if strings.TrimSpace(userID) == "" {
-		logger.Error("get balance validation failed", "duration", time.Since(start))
-		writeError(w, "", http.StatusBadRequest, "X-User-Id header is required")
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
 		return
 	}
-	logger.Info("get balance start", "user_id", userID)
+	h.logger.DebugContext(r.Context(), "get balance start", "user_id", userID)
 
-	ctx, cancel := withTimeout(r)
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
-	resp, err := h.payments.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: userID})
+	resp, err := fanout.Hedged(ctx, h.hedgeDelay, func(ctx context.Context) (*paymentsv1.GetBalanceResponse, error) {
+		return h.payments.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: userID})
+	})
 	if err != nil {
-		logger.Error("get balance grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "get balance grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
 
-	writeJSON(w, http.StatusOK, gateway.GetBalanceResponse{
-		UserId:  userID,
-		Balance: resp.GetBalance(),
+	balance := gateway.GetBalanceResponse{
+		UserId:      userID,
+		Balance:     money.Amount(resp.GetBalance()),
+		Currency:    resp.GetCurrency(),
+		Version:     accountVersion(resp.GetVersion()),
+		AccountType: accountType(resp.GetAccountType()),
+	}
+	if bonus := money.Amount(resp.GetBonusBalance()); bonus > 0 {
+		balance.BonusBalance = &bonus
+	}
+	writeJSON(w, http.StatusOK, balance)
+	h.logger.InfoContext(ctx, "get balance completed", "user_id", userID, "duration", time.Since(start))
+}
+
+func (h *Handler) GetRates(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := fanout.Hedged(ctx, h.hedgeDelay, func(ctx context.Context) (*paymentsv1.GetRatesResponse, error) {
+		return h.payments.GetRates(ctx, &paymentsv1.GetRatesRequest{})
 	})
-	logger.Info("get balance completed", "user_id", userID, "duration", time.Since(start))
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get rates grpc failed", "err", err, "duration", time.Since(start))
+		writeGRPCError(w, "", err)
+		return
+	}
+
+	out := gateway.RatesResponse{
+		Base:  resp.GetBase(),
+		Rates: make([]gateway.ExchangeRate, 0, len(resp.GetRates())),
+		AsOf:  resp.GetAsOf().AsTime(),
+	}
+	for _, rate := range resp.GetRates() {
+		out.Rates = append(out.Rates, gateway.ExchangeRate{Currency: rate.GetCurrency(), Rate: rate.GetRate()})
+	}
+	writeJSON(w, http.StatusOK, out)
+	h.logger.InfoContext(ctx, "get rates completed", "count", len(out.Rates), "duration", time.Since(start))
 }
 
 func (h *Handler) TopUpAccount(w http.ResponseWriter, r *http.Request, params gateway.TopUpAccountParams) {
 	start := time.Now()
-	userID, _ := resolveUserID(params.XUserId)
-	idempotencyKey := getHeader(params.IdempotencyKey)
-	logger.Info("top up start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	idempotencyKey := getHeader(&params.IdempotencyKey)
+	h.logger.DebugContext(r.Context(), "top up start", "user_id", userID, "has_idempotency_key", idempotencyKey != "")
 
 	var body gateway.TopUpAccountRequest
 	if err := decodeJSON(r, &body); err != nil {
-		logger.Error("top up decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
-		writeError(w, userID, http.StatusBadRequest, err.Error())
+		h.logger.ErrorContext(r.Context(), "top up decode failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusBadRequest, err.Error())
 		return
 	}
 	if body.Amount <= 0 {
-		logger.Error("top up validation failed", "user_id", userID, "amount", body.Amount, "duration", time.Since(start))
-		writeError(w, userID, http.StatusBadRequest, "amount must be > 0")
+		h.logger.ErrorContext(r.Context(), "top up validation failed", "user_id", userID, "amount", body.Amount, "duration", time.Since(start))
+		WriteErrorCode(w, userID, http.StatusBadRequest, CodeInvalidAmount, "amount must be > 0")
 		return
 	}
 
-	ctx, cancel := withTimeout(r)
+	ctx, cancel := h.withBudget(r)
 	defer cancel()
 
 	resp, err := h.payments.TopUp(ctx, &paymentsv1.TopUpRequest{
-		UserId:         userID,
-		Amount:         body.Amount,
-		IdempotencyKey: idempotencyKey,
+		UserId:          userID,
+		Amount:          int64(body.Amount),
+		Currency:        optString(body.Currency),
+		IdempotencyKey:  idempotencyKey,
+		ExpectedVersion: optInt64(body.ExpectedVersion),
 	})
 	if err != nil {
-		logger.Error("top up grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		h.logger.ErrorContext(ctx, "top up grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
 		writeGRPCError(w, userID, err)
 		return
 	}
+	usage.AddVolume(ctx, int64(body.Amount))
 
 	writeJSON(w, http.StatusOK, gateway.TopUpAccountResponse{
-		UserId:  userID,
-		Balance: resp.GetAccount().GetBalance(),
+		UserId:      userID,
+		Balance:     money.Amount(resp.GetAccount().GetBalance()),
+		Currency:    resp.GetAccount().GetCurrency(),
+		Version:     accountVersion(resp.GetAccount().GetVersion()),
+		AccountType: accountType(resp.GetAccount().GetAccountType()),
 	})
-	logger.Info("top up completed", "user_id", userID, "duration", time.Since(start))
+	h.logger.InfoContext(ctx, "top up completed", "user_id", userID, "duration", time.Since(start))
 }
 
 func mapOrder(order *ordersv1.Order) *gateway.Order {
-	logger.Info("map order start", "has_order", order != nil)
+	slog.Debug("map order start", "has_order", order != nil)
 	if order == nil {
-		logger.Error("map order failed (nil order)")
+		slog.Error("map order failed (nil order)")
 		return nil
 	}
 
@@ -262,17 +885,52 @@ func mapOrder(order *ordersv1.Order) *gateway.Order {
 	mapped := &gateway.Order{
 		OrderId:     order.GetOrderId(),
 		UserId:      order.GetUserId(),
-		Amount:      order.GetAmount(),
+		Amount:      money.Amount(order.GetAmount()),
+		Currency:    order.GetCurrency(),
 		Description: order.GetDescription(),
 		Status:      mapOrderStatus(order.GetStatus()),
 		CreatedAt:   createdAt,
 	}
-	logger.Info("map order completed", "order_id", mapped.OrderId)
+	if reason := order.GetPaymentFailureReason(); reason != "" {
+		mapped.PaymentFailureReason = &reason
+	}
+	if paid := money.Amount(order.GetPaidAmount()); paid > 0 {
+		mapped.PaidAmount = &paid
+	}
+	if fee := money.Amount(order.GetFeeAmount()); fee > 0 {
+		mapped.FeeAmount = &fee
+	}
+	if md := order.GetMetadata(); len(md) > 0 {
+		metadata := gateway.OrderMetadata(md)
+		mapped.Metadata = &metadata
+	}
+	if tags := order.GetTags(); len(tags) > 0 {
+		mapped.Tags = &tags
+	}
+	if v := order.GetVersion(); v > 0 {
+		mapped.Version = &v
+	}
+	if order.GetUpdatedAt() != nil {
+		t := order.GetUpdatedAt().AsTime()
+		mapped.UpdatedAt = &t
+	}
+	if order.GetArchived() {
+		archived := true
+		mapped.Archived = &archived
+	}
+	if order.GetPayAt() != nil {
+		t := order.GetPayAt().AsTime()
+		mapped.PayAt = &t
+	}
+	if d := order.GetDispute(); d != nil {
+		mapped.Dispute = mapOrderDispute(d)
+	}
+	slog.Debug("map order completed", "order_id", mapped.OrderId)
 	return mapped
 }
 
 func mapOrderStatus(status ordersv1.OrderStatus) gateway.OrderStatus {
-	logger.Info("map order status", "status", status.String())
+	slog.Debug("map order status", "status", status.String())
 	switch status {
 	case ordersv1.OrderStatus_ORDER_STATUS_FINISHED:
 		return gateway.OrderStatus("FINISHED")
@@ -280,43 +938,171 @@ func mapOrderStatus(status ordersv1.OrderStatus) gateway.OrderStatus {
 		return gateway.OrderStatus("CANCELLED")
 	case ordersv1.OrderStatus_ORDER_STATUS_NEW:
 		return gateway.OrderStatus("NEW")
+	case ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID:
+		return gateway.OrderStatus("PARTIALLY_PAID")
+	case ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED:
+		return gateway.OrderStatus("SCHEDULED")
+	case ordersv1.OrderStatus_ORDER_STATUS_DISPUTED:
+		return gateway.OrderStatusDISPUTED
+	case ordersv1.OrderStatus_ORDER_STATUS_REFUNDED:
+		return gateway.OrderStatusREFUNDED
 	default:
 		return gateway.OrderStatus("NEW")
 	}
 }
 
-func resolveUserID(header *gateway.UserIdHeader) (string, bool) {
-	logger.Info("resolve user id start", "header_present", header != nil)
+func mapOrderDispute(d *ordersv1.OrderDispute) *gateway.OrderDispute {
+	mapped := &gateway.OrderDispute{
+		Amount:   money.Amount(d.GetAmount()),
+		Reason:   d.GetReason(),
+		OpenedAt: d.GetOpenedAt().AsTime(),
+	}
+	switch d.GetStatus() {
+	case ordersv1.DisputeStatus_DISPUTE_STATUS_REFUNDED:
+		mapped.Status = gateway.OrderDisputeRefunded
+	case ordersv1.DisputeStatus_DISPUTE_STATUS_UPHELD:
+		mapped.Status = gateway.OrderDisputeUpheld
+	default:
+		mapped.Status = gateway.OrderDisputeOpen
+	}
+	if d.GetResolvedAt() != nil {
+		t := d.GetResolvedAt().AsTime()
+		mapped.ResolvedAt = &t
+	}
+	return mapped
+}
+
+func mapTransferStatus(status ordersv1.OrderTransferStatus) gateway.OrderTransferStatus {
+	switch status {
+	case ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_ACCEPTED:
+		return gateway.OrderTransferStatusACCEPTED
+	case ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_CANCELLED:
+		return gateway.OrderTransferStatusCANCELLED
+	default:
+		return gateway.OrderTransferStatusPENDING
+	}
+}
+
+// RequireUserID returns the user the request acts for, from X-User-Id, which
+// the rbac middleware fills from the session or JWT subject. The gateway never
+// makes an id up: orders placed under a throwaway id could not be found again.
+// A caller with no identity at all gets 401 session_required; an
+// authenticated one (API key, support, admin) that named no user gets 400
+// user_id_required.
+func RequireUserID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	header := gateway.UserIdHeader(r.Header.Get("X-User-Id"))
+	return requireUserID(w, r, &header)
+}
+
+// requireUserID is RequireUserID for the X-User-Id parameter of a generated
+// handler.
+func requireUserID(w http.ResponseWriter, r *http.Request, header *gateway.UserIdHeader) (string, bool) {
 	if header != nil && strings.TrimSpace(string(*header)) != "" {
-		return string(*header), false
+		return strings.TrimSpace(string(*header)), true
 	}
-	newID := uuid.NewString()
-	logger.Info("generated user id", "user_id", newID)
-	return newID, true
+	_, byToken := auth.FromContext(r.Context())
+	_, byKey := apikey.FromContext(r.Context())
+	if byToken || byKey {
+		slog.DebugContext(r.Context(), "user id missing for authenticated caller", "path", r.URL.Path)
+		WriteErrorCode(w, "", http.StatusBadRequest, CodeUserIDRequired, "X-User-Id header is required")
+		return "", false
+	}
+	slog.DebugContext(r.Context(), "request without session", "path", r.URL.Path)
+	WriteErrorCode(w, "", http.StatusUnauthorized, CodeSessionRequired, "a session or bearer token is required, see POST /session")
+	return "", false
 }
 
 func getHeader(header *gateway.IdempotencyKeyHeader) string {
 	if header == nil {
 		return ""
 	}
-	logger.Info("idempotency key header resolved", "has_value", strings.TrimSpace(string(*header)) != "")
+	slog.Debug("idempotency key header resolved", "has_value", strings.TrimSpace(string(*header)) != "")
 	return string(*header)
 }
 
+// prefersAsync reports whether an RFC 7240 Prefer header asks for
+// respond-async, e.g. "respond-async, wait=10".
+func prefersAsync(prefer string) bool {
+	for _, p := range strings.Split(prefer, ",") {
+		name, _, _ := strings.Cut(p, ";")
+		name, _, _ = strings.Cut(name, "=")
+		if strings.EqualFold(strings.TrimSpace(name), "respond-async") {
+			return true
+		}
+	}
+	return false
+}
+
+// optString dereferences an optional request field, empty when absent.
+func optString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+// optInt64 dereferences an optional request field, zero when absent.
+func optInt64(v *int64) int64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
+// discountAmount omits the discount of orders created without a promo code.
+func discountAmount(v int64) *gateway.MoneyAmount {
+	if v == 0 {
+		return nil
+	}
+	d := gateway.MoneyAmount(v)
+	return &d
+}
+
+// accountVersion omits the version of a payments-service that predates it.
+func accountVersion(v int64) *gateway.AccountVersion {
+	if v == 0 {
+		return nil
+	}
+	return &v
+}
+
+// accountType omits the type of a payments-service that predates tiers.
+func accountType(t paymentsv1.AccountType) *gateway.AccountType {
+	if t == paymentsv1.AccountType_ACCOUNT_TYPE_UNSPECIFIED {
+		return nil
+	}
+	name := gateway.AccountType(strings.TrimPrefix(t.String(), "ACCOUNT_TYPE_"))
+	return &name
+}
+
 func writeJSON(w http.ResponseWriter, status int, payload interface{}) {
-	logger.Info("write json response", "status", status)
+	slog.Debug("write json response", "status", status)
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
 	_ = json.NewEncoder(w).Encode(payload)
 }
 
-func writeError(w http.ResponseWriter, userID string, statusCode int, message string) {
-	logger.Error("write error response", "user_id", userID, "status", statusCode, "message", message)
-	resp := gateway.ErrorResponse{Error: message}
-	if userID != "" {
-		resp.UserId = &userID
-	}
-	writeJSON(w, statusCode, resp)
+// WriteError writes the gateway's JSON error body.
+// Error codes specific to the gateway, on top of the generic httperr codes.
+// Each needs an entry in every i18n catalog.
+const (
+	CodeIdempotencyKeyRequired = "idempotency_key_required"
+	CodeUserIDRequired         = "user_id_required"
+	CodeSessionRequired        = "session_required"
+	CodeInvalidAmount          = "invalid_amount"
+	CodeOverloaded             = "overloaded"
+)
+
+func WriteError(w http.ResponseWriter, userID string, statusCode int, message string) {
+	slog.Debug("write error response", "user_id", userID, "status", statusCode, "message", message)
+	httperr.Write(w, userID, statusCode, message)
+}
+
+// WriteErrorCode is WriteError with a specific error code instead of the
+// generic one for statusCode.
+func WriteErrorCode(w http.ResponseWriter, userID string, statusCode int, code, message string) {
+	slog.Debug("write error response", "user_id", userID, "status", statusCode, "code", code, "message", message)
+	httperr.WriteCode(w, userID, statusCode, code, message)
 }
 
 func WriteBadRequest(w http.ResponseWriter, userID string, err error) {
@@ -324,81 +1110,57 @@ func WriteBadRequest(w http.ResponseWriter, userID string, err error) {
 	if err != nil {
 		message = err.Error()
 	}
-	logger.Error("write bad request", "user_id", userID, "message", message)
-	writeError(w, userID, http.StatusBadRequest, message)
+	slog.Debug("write bad request", "user_id", userID, "message", message)
+	WriteError(w, userID, http.StatusBadRequest, message)
 }
 
 func writeGRPCError(w http.ResponseWriter, userID string, err error) {
-	st, ok := status.FromError(err)
-	if !ok {
-		logger.Error("write grpc error failed to parse status", "user_id", userID, "err", err)
-		writeError(w, userID, http.StatusInternalServerError, "internal error")
-		return
-	}
-	logger.Error("write grpc error", "user_id", userID, "grpc_code", st.Code().String(), "message", st.Message())
-	writeError(w, userID, grpcCodeToStatus(st.Code()), st.Message())
-}
-
-func grpcCodeToStatus(code codes.Code) int {
-	logger.Info("grpc code to status", "grpc_code", code.String())
-	switch code {
-	case codes.InvalidArgument, codes.FailedPrecondition:
-		return http.StatusBadRequest
-	case codes.NotFound:
-		return http.StatusNotFound
-	case codes.AlreadyExists:
-		return http.StatusConflict
-	case codes.Unauthenticated:
-		return http.StatusUnauthorized
-	case codes.PermissionDenied:
-		return http.StatusForbidden
-	case codes.Unavailable:
-		return http.StatusServiceUnavailable
-	case codes.DeadlineExceeded:
-		return http.StatusGatewayTimeout
-	default:
-		return http.StatusInternalServerError
+	if st, ok := status.FromError(err); ok {
+		slog.Debug("write grpc error", "user_id", userID, "grpc_code", st.Code().String(), "message", st.Message())
+	} else {
+		slog.Error("write grpc error failed to parse status", "user_id", userID, "err", err)
 	}
+	httperr.WriteGRPC(w, userID, err)
 }
 
 func decodeJSON(r *http.Request, dst interface{}) error {
 	if r.Body == nil {
-		logger.Error("decode json failed (empty body)")
+		slog.ErrorContext(r.Context(), "decode json failed (empty body)")
 		return fmt.Errorf("request body is required")
 	}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(dst); err != nil {
-		logger.Error("decode json failed", "err", err)
+		slog.ErrorContext(r.Context(), "decode json failed", "err", err)
 		return err
 	}
-	logger.Info("decode json completed")
+	slog.DebugContext(r.Context(), "decode json completed")
 	return nil
 }
 
 func decodeOptionalJSON(r *http.Request) error {
 	if r.Body == nil || r.ContentLength == 0 {
-		logger.Info("decode optional json skipped (empty body)")
+		slog.DebugContext(r.Context(), "decode optional json skipped (empty body)")
 		return nil
 	}
 	var payload map[string]interface{}
 	dec := json.NewDecoder(r.Body)
 	dec.DisallowUnknownFields()
 	if err := dec.Decode(&payload); err != nil {
-		logger.Error("decode optional json failed", "err", err)
+		slog.ErrorContext(r.Context(), "decode optional json failed", "err", err)
 		return err
 	}
 	if len(payload) > 0 {
-		logger.Error("decode optional json failed (non-empty body)")
+		slog.ErrorContext(r.Context(), "decode optional json failed (non-empty body)")
 		return fmt.Errorf("request body must be empty")
 	}
-	logger.Info("decode optional json completed")
+	slog.DebugContext(r.Context(), "decode optional json completed")
 	return nil
 }
 
-func withTimeout(r *http.Request) (context.Context, func()) {
-	logger.Info("with timeout", "timeout", requestTimeout.String())
-	return context.WithTimeout(r.Context(), requestTimeout)
+func (h *Handler) withBudget(r *http.Request) (context.Context, func()) {
+	h.logger.DebugContext(r.Context(), "with budget", "budget", h.budget.String())
+	return fanout.WithBudget(r.Context(), h.budget)
 }
 
 var _ gateway.ServerInterface = (*Handler)(nil)
@@ -1,18 +1,27 @@
 package handler
 
 import (
+	"context"
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
-	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
 	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
 )
 
 func TestMapOrderStatus(t *testing.T) {
@@ -24,6 +33,10 @@ func TestMapOrderStatus(t *testing.T) {
 		{"finished", ordersv1.OrderStatus_ORDER_STATUS_FINISHED, gateway.OrderStatus("FINISHED")},
 		{"cancelled", ordersv1.OrderStatus_ORDER_STATUS_CANCELLED, gateway.OrderStatus("CANCELLED")},
 		{"new", ordersv1.OrderStatus_ORDER_STATUS_NEW, gateway.OrderStatus("NEW")},
+		{"partially paid", ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID, gateway.OrderStatus("PARTIALLY_PAID")},
+		{"scheduled", ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED, gateway.OrderStatus("SCHEDULED")},
+		{"disputed", ordersv1.OrderStatus_ORDER_STATUS_DISPUTED, gateway.OrderStatusDISPUTED},
+		{"refunded", ordersv1.OrderStatus_ORDER_STATUS_REFUNDED, gateway.OrderStatusREFUNDED},
 		{"unknown", ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED, gateway.OrderStatus("NEW")},
 	}
 
@@ -62,33 +75,103 @@ func TestMapOrder(t *testing.T) {
 	}
 }
 
+func TestMapOrderArchived(t *testing.T) {
+	if mapped := mapOrder(&ordersv1.Order{OrderId: "o-1"}); mapped.Archived != nil {
+		t.Fatalf("mapOrder() archived = %v for a hot order, want omitted", *mapped.Archived)
+	}
+	if mapped := mapOrder(&ordersv1.Order{OrderId: "o-1", Archived: true}); mapped.Archived == nil || !*mapped.Archived {
+		t.Fatalf("mapOrder() archived = %v, want true", mapped.Archived)
+	}
+}
+
+func TestMapOrderPaymentFailureReason(t *testing.T) {
+	mapped := mapOrder(&ordersv1.Order{OrderId: "o-1", Status: ordersv1.OrderStatus_ORDER_STATUS_NEW})
+	if mapped.PaymentFailureReason != nil {
+		t.Fatalf("mapOrder() payment_failure_reason = %q, want nil", *mapped.PaymentFailureReason)
+	}
+
+	mapped = mapOrder(&ordersv1.Order{
+		OrderId:              "o-2",
+		Status:               ordersv1.OrderStatus_ORDER_STATUS_CANCELLED,
+		PaymentFailureReason: "not enough funds",
+	})
+	if mapped.PaymentFailureReason == nil || *mapped.PaymentFailureReason != "not enough funds" {
+		t.Fatalf("mapOrder() payment_failure_reason = %v, want %q", mapped.PaymentFailureReason, "not enough funds")
+	}
+}
+
+func TestMapOrderPaidAmount(t *testing.T) {
+	mapped := mapOrder(&ordersv1.Order{OrderId: "o-1", Status: ordersv1.OrderStatus_ORDER_STATUS_NEW})
+	if mapped.PaidAmount != nil || mapped.FeeAmount != nil {
+		t.Fatalf("mapOrder() paid/fee = %v/%v, want nil", mapped.PaidAmount, mapped.FeeAmount)
+	}
+
+	mapped = mapOrder(&ordersv1.Order{
+		OrderId:    "o-2",
+		Amount:     100,
+		Status:     ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID,
+		PaidAmount: 40,
+		FeeAmount:  2,
+	})
+	if mapped.PaidAmount == nil || *mapped.PaidAmount != 40 {
+		t.Fatalf("mapOrder() paid_amount = %v, want 40", mapped.PaidAmount)
+	}
+	if mapped.FeeAmount == nil || *mapped.FeeAmount != 2 {
+		t.Fatalf("mapOrder() fee_amount = %v, want 2", mapped.FeeAmount)
+	}
+}
+
+func TestMapOrderMetadataAndTags(t *testing.T) {
+	mapped := mapOrder(&ordersv1.Order{OrderId: "o-1", Status: ordersv1.OrderStatus_ORDER_STATUS_NEW})
+	if mapped.Metadata != nil || mapped.Tags != nil {
+		t.Fatalf("mapOrder() metadata = %v, tags = %v, want both nil", mapped.Metadata, mapped.Tags)
+	}
+
+	mapped = mapOrder(&ordersv1.Order{
+		OrderId:  "o-2",
+		Status:   ordersv1.OrderStatus_ORDER_STATUS_NEW,
+		Metadata: map[string]string{"invoice": "INV-42"},
+		Tags:     []string{"b2b"},
+	})
+	if mapped.Metadata == nil || (*mapped.Metadata)["invoice"] != "INV-42" {
+		t.Fatalf("mapOrder() metadata = %v, want invoice INV-42", mapped.Metadata)
+	}
+	if mapped.Tags == nil || len(*mapped.Tags) != 1 || (*mapped.Tags)[0] != "b2b" {
+		t.Fatalf("mapOrder() tags = %v, want [b2b]", mapped.Tags)
+	}
+}
+
 func TestMapOrderNil(t *testing.T) {
 	if mapOrder(nil) != nil {
 		t.Fatal("mapOrder(nil) should return nil")
 	}
 }
 
-func TestResolveUserID(t *testing.T) {
-	h := gateway.UserIdHeader("user-1")
-	got, generated := resolveUserID(&h)
-	if generated {
-		t.Fatal("resolveUserID() generated id for provided header")
+func TestRequireUserID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	req.Header.Set("X-User-Id", "user-1")
+	rec := httptest.NewRecorder()
+	if got, ok := RequireUserID(rec, req); !ok || got != "user-1" {
+		t.Fatalf("RequireUserID() = %q, %v; want user-1", got, ok)
 	}
-	if got != "user-1" {
-		t.Fatalf("resolveUserID() = %q, want %q", got, "user-1")
-	}
-}
 
-func TestResolveUserIDGenerated(t *testing.T) {
-	got, generated := resolveUserID(nil)
-	if !generated {
-		t.Fatal("resolveUserID() did not generate id for missing header")
+	rec = httptest.NewRecorder()
+	if _, ok := RequireUserID(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)); ok {
+		t.Fatal("RequireUserID() accepted a request without identity")
+	}
+	if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), CodeSessionRequired) {
+		t.Fatalf("no identity: status = %d, body = %s; want 401 %s", rec.Code, rec.Body, CodeSessionRequired)
 	}
-	if got == "" {
-		t.Fatal("resolveUserID() generated empty id")
+
+	// An admin token names no user by itself.
+	req = httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	req = req.WithContext(auth.WithClaims(req.Context(), auth.Claims{Subject: "ops", Roles: []auth.Role{auth.RoleAdmin}}, "tok"))
+	rec = httptest.NewRecorder()
+	if _, ok := RequireUserID(rec, req); ok {
+		t.Fatal("RequireUserID() accepted an admin request without X-User-Id")
 	}
-	if _, err := uuid.Parse(got); err != nil {
-		t.Fatalf("resolveUserID() generated invalid uuid: %v", err)
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), CodeUserIDRequired) {
+		t.Fatalf("admin without user: status = %d, body = %s; want 400 %s", rec.Code, rec.Body, CodeUserIDRequired)
 	}
 }
 
@@ -102,29 +185,6 @@ func TestGetHeader(t *testing.T) {
 	}
 }
 
-func TestGrpcCodeToStatus(t *testing.T) {
-	tests := []struct {
-		code codes.Code
-		want int
-	}{
-		{codes.InvalidArgument, http.StatusBadRequest},
-		{codes.FailedPrecondition, http.StatusBadRequest},
-		{codes.NotFound, http.StatusNotFound},
-		{codes.AlreadyExists, http.StatusConflict},
-		{codes.Unauthenticated, http.StatusUnauthorized},
-		{codes.PermissionDenied, http.StatusForbidden},
-		{codes.Unavailable, http.StatusServiceUnavailable},
-		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
-		{codes.Internal, http.StatusInternalServerError},
-	}
-
-	for _, tt := range tests {
-		if got := grpcCodeToStatus(tt.code); got != tt.want {
-			t.Fatalf("grpcCodeToStatus(%s) = %d, want %d", tt.code.String(), got, tt.want)
-		}
-	}
-}
-
 func TestDecodeJSON(t *testing.T) {
 	t.Run("missing body", func(t *testing.T) {
 		req := &http.Request{Body: nil}
@@ -188,3 +248,617 @@ func TestDecodeOptionalJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestWriteGRPCErrorResourceExhausted(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeGRPCError(rec, "u-1", status.Error(codes.ResourceExhausted, "too many top-ups"))
+	if rec.Code != http.StatusTooManyRequests || rec.Header().Get("Retry-After") == "" {
+		t.Fatalf("status = %d, Retry-After = %q; want 429 with Retry-After", rec.Code, rec.Header().Get("Retry-After"))
+	}
+}
+
+type fakeOrders struct {
+	ordersv1.OrdersServiceClient
+}
+
+func (fakeOrders) CreateOrder(_ context.Context, in *ordersv1.CreateOrderRequest, _ ...grpc.CallOption) (*ordersv1.CreateOrderResponse, error) {
+	if in.GetDescription() == "again" && !in.GetForce() {
+		st, _ := status.New(codes.AlreadyExists, "order duplicates a recent one").WithDetails(&errdetails.ErrorInfo{
+			Reason:   "DUPLICATE_ORDER",
+			Metadata: map[string]string{"order_id": "o-0"},
+		})
+		return nil, st.Err()
+	}
+	order := &ordersv1.Order{OrderId: "o-1", UserId: in.GetUserId(), Amount: in.GetAmount(), Description: in.GetDescription(), Status: ordersv1.OrderStatus_ORDER_STATUS_NEW}
+	if in.GetPayAt() != nil {
+		order.Status, order.PayAt = ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED, in.GetPayAt()
+	}
+	return &ordersv1.CreateOrderResponse{Order: order}, nil
+}
+
+// UpdateOrder echoes the mask back in the description so tests can see it.
+func (fakeOrders) UpdateOrder(_ context.Context, in *ordersv1.UpdateOrderRequest, _ ...grpc.CallOption) (*ordersv1.UpdateOrderResponse, error) {
+	if in.GetVersion() == 9 {
+		return nil, status.Error(codes.Aborted, "order version is 2, not 9")
+	}
+	return &ordersv1.UpdateOrderResponse{Order: &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Description: strings.Join(in.GetUpdateMask().GetPaths(), ","), Version: 2}}, nil
+}
+
+func TestUpdateOrderFieldMask(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	params := gateway.UpdateOrderParams{XUserId: &user}
+	for _, tc := range []struct {
+		body string
+		code int
+		mask string
+	}{
+		{`{"description":"new"}`, http.StatusOK, "description"},
+		{`{"tags":[],"metadata":{"k":"v"},"version":2}`, http.StatusOK, "metadata,tags"},
+		{`{"version":2}`, http.StatusBadRequest, ""},
+		{`{"description":"new","version":9}`, http.StatusConflict, ""},
+	} {
+		rec := httptest.NewRecorder()
+		h.UpdateOrder(rec, httptest.NewRequest(http.MethodPatch, "/api/v1/orders/o-1", strings.NewReader(tc.body)), "o-1", params)
+		if rec.Code != tc.code {
+			t.Fatalf("%s: status = %d, want %d (%s)", tc.body, rec.Code, tc.code, rec.Body.String())
+		}
+		if tc.code != http.StatusOK {
+			continue
+		}
+		var resp gateway.UpdateOrderResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Order.Description != tc.mask || resp.Order.Version == nil || *resp.Order.Version != 2 {
+			t.Fatalf("%s: body = %+v (%v), want mask %q and version 2", tc.body, resp, err, tc.mask)
+		}
+	}
+}
+
+// GetOrder and ListOrders ignore the read mask, leaving the trimming to the
+// gateway, and report it in the metadata.
+func (fakeOrders) GetOrder(_ context.Context, in *ordersv1.GetOrderRequest, _ ...grpc.CallOption) (*ordersv1.GetOrderResponse, error) {
+	return &ordersv1.GetOrderResponse{Order: fullOrder(in.GetOrderId(), in.GetUserId(), in.GetReadMask().GetPaths())}, nil
+}
+
+func (fakeOrders) ListOrders(_ context.Context, in *ordersv1.ListOrdersRequest, _ ...grpc.CallOption) (*ordersv1.ListOrdersResponse, error) {
+	return &ordersv1.ListOrdersResponse{Orders: []*ordersv1.Order{
+		fullOrder("o-1", in.GetUserId(), in.GetReadMask().GetPaths()),
+		fullOrder("o-2", in.GetUserId(), in.GetReadMask().GetPaths()),
+	}}, nil
+}
+
+func fullOrder(orderID, userID string, mask []string) *ordersv1.Order {
+	return &ordersv1.Order{
+		OrderId: orderID, UserId: userID, Amount: 500, Currency: "RUB", Description: "book",
+		Status: ordersv1.OrderStatus_ORDER_STATUS_NEW, Tags: []string{"gift"}, Version: 1,
+		Metadata: map[string]string{"read_mask": strings.Join(mask, ",")},
+	}
+}
+
+func TestOrderFields(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	decode := func(rec *httptest.ResponseRecorder) map[string]json.RawMessage {
+		t.Helper()
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("decode %s: %v", rec.Body, err)
+		}
+		return body
+	}
+	keys := func(raw json.RawMessage) string {
+		t.Helper()
+		var order map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &order); err != nil {
+			t.Fatalf("decode order %s: %v", raw, err)
+		}
+		names := make([]string, 0, len(order))
+		for name := range order {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return strings.Join(names, ",")
+	}
+
+	fields := gateway.OrderFieldsQuery(" status,metadata,, order_id,status")
+	rec := httptest.NewRecorder()
+	h.GetOrder(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1", nil), "o-1", gateway.GetOrderParams{XUserId: &user, Fields: &fields})
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GetOrder status = %d, want 200 (%s)", rec.Code, rec.Body)
+	}
+	order := decode(rec)["order"]
+	if got := keys(order); got != "metadata,order_id,status" {
+		t.Fatalf("GetOrder order keys = %s, want metadata,order_id,status", got)
+	}
+	if !strings.Contains(string(order), `"read_mask":"status,metadata,order_id"`) {
+		t.Fatalf("GetOrder order = %s, want the fields passed as the read mask", order)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ListOrders(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil), gateway.ListOrdersParams{XUserId: &user, Fields: &fields})
+	var orders []json.RawMessage
+	if err := json.Unmarshal(decode(rec)["orders"], &orders); err != nil || len(orders) != 2 {
+		t.Fatalf("ListOrders orders = %s (%v), want two", rec.Body, err)
+	}
+	for _, o := range orders {
+		if got := keys(o); got != "metadata,order_id,status" {
+			t.Fatalf("ListOrders order keys = %s, want metadata,order_id,status", got)
+		}
+	}
+
+	// Without fields the whole order comes back and no mask is sent.
+	rec = httptest.NewRecorder()
+	h.GetOrder(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1", nil), "o-1", gateway.GetOrderParams{XUserId: &user})
+	var full gateway.GetOrderResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &full); err != nil || full.Order.Description != "book" || (*full.Order.Metadata)["read_mask"] != "" {
+		t.Fatalf("GetOrder without fields = %s (%v), want the whole order", rec.Body, err)
+	}
+
+	unknown := gateway.OrderFieldsQuery("order_id,items")
+	rec = httptest.NewRecorder()
+	h.GetOrder(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1", nil), "o-1", gateway.GetOrderParams{XUserId: &user, Fields: &unknown})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GetOrder unknown field status = %d, want 400", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ListOrders(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil), gateway.ListOrdersParams{XUserId: &user, Fields: &unknown})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("ListOrders unknown field status = %d, want 400", rec.Code)
+	}
+}
+
+func (fakeOrders) TransferOrder(_ context.Context, in *ordersv1.TransferOrderRequest, _ ...grpc.CallOption) (*ordersv1.TransferOrderResponse, error) {
+	return &ordersv1.TransferOrderResponse{Transfer: &ordersv1.OrderTransfer{TransferId: "t-1", OrderId: in.GetOrderId(), FromUserId: in.GetUserId(), ToUserId: in.GetToUserId(), Status: ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_PENDING}}, nil
+}
+
+func (fakeOrders) AcceptOrderTransfer(_ context.Context, in *ordersv1.AcceptOrderTransferRequest, _ ...grpc.CallOption) (*ordersv1.AcceptOrderTransferResponse, error) {
+	if in.GetUserId() != "u-2" {
+		return nil, status.Error(codes.NotFound, "no pending transfer of this order to user_id")
+	}
+	return &ordersv1.AcceptOrderTransferResponse{Order: &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Status: ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID}}, nil
+}
+
+func TestTransferOrder(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	owner, recipient := gateway.UserIdHeader("u-1"), gateway.UserIdHeader("u-2")
+
+	rec := httptest.NewRecorder()
+	h.TransferOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/transfer", strings.NewReader(`{}`)), "o-1", gateway.TransferOrderParams{XUserId: &owner})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("TransferOrder without to_user_id: status = %d, want 400", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.TransferOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/transfer", strings.NewReader(`{"to_user_id":"u-2"}`)), "o-1", gateway.TransferOrderParams{XUserId: &owner})
+	var offered gateway.TransferOrderResponse
+	if err := json.NewDecoder(rec.Body).Decode(&offered); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("TransferOrder: status = %d (%v)", rec.Code, err)
+	}
+	if tr := offered.Transfer; tr.TransferId != "t-1" || tr.FromUserId != "u-1" || tr.ToUserId != "u-2" || tr.Status != gateway.OrderTransferStatusPENDING {
+		t.Fatalf("TransferOrder: transfer = %+v, want pending t-1 from u-1 to u-2", tr)
+	}
+
+	rec = httptest.NewRecorder()
+	h.AcceptOrderTransfer(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/transfer/accept", nil), "o-1", gateway.AcceptOrderTransferParams{XUserId: &owner})
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("AcceptOrderTransfer by owner: status = %d, want 404", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.AcceptOrderTransfer(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/transfer/accept", nil), "o-1", gateway.AcceptOrderTransferParams{XUserId: &recipient})
+	var accepted gateway.AcceptOrderTransferResponse
+	if err := json.NewDecoder(rec.Body).Decode(&accepted); err != nil || rec.Code != http.StatusOK || accepted.Order.UserId != "u-2" {
+		t.Fatalf("AcceptOrderTransfer: status = %d, body = %+v (%v); want the order owned by u-2", rec.Code, accepted, err)
+	}
+}
+
+func (fakeOrders) CancelOrder(_ context.Context, in *ordersv1.CancelOrderRequest, _ ...grpc.CallOption) (*ordersv1.CancelOrderResponse, error) {
+	if in.GetOrderId() != "o-1" {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &ordersv1.CancelOrderResponse{Order: &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Status: ordersv1.OrderStatus_ORDER_STATUS_CANCELLED, PaymentFailureReason: "cancelled by user"}}, nil
+}
+
+func TestScheduledOrder(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+
+	rec := httptest.NewRecorder()
+	h.CreateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"amount":10,"description":"rent","pay_at":"2030-01-02T03:04:05Z"}`)), gateway.CreateOrderParams{XUserId: &user})
+	var created gateway.CreateOrderResponse
+	if err := json.NewDecoder(rec.Body).Decode(&created); err != nil || rec.Code != http.StatusCreated {
+		t.Fatalf("CreateOrder: status = %d (%v)", rec.Code, err)
+	}
+	want := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	if created.Order.Status != gateway.OrderStatus("SCHEDULED") || created.Order.PayAt == nil || !created.Order.PayAt.Equal(want) {
+		t.Fatalf("CreateOrder: order = %+v, want SCHEDULED at %s", created.Order, want)
+	}
+
+	rec = httptest.NewRecorder()
+	h.CancelOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/cancel", nil), "o-1", gateway.CancelOrderParams{XUserId: &user})
+	var cancelled gateway.CancelOrderResponse
+	if err := json.NewDecoder(rec.Body).Decode(&cancelled); err != nil || rec.Code != http.StatusOK || cancelled.Order.Status != gateway.OrderStatus("CANCELLED") {
+		t.Fatalf("CancelOrder: status = %d, body = %+v (%v); want the order CANCELLED", rec.Code, cancelled, err)
+	}
+	rec = httptest.NewRecorder()
+	h.CancelOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-2/cancel", nil), "o-2", gateway.CancelOrderParams{XUserId: &user})
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("CancelOrder unknown order: status = %d, want 404", rec.Code)
+	}
+}
+
+func (fakeOrders) RetryPayment(_ context.Context, in *ordersv1.RetryPaymentRequest, _ ...grpc.CallOption) (*ordersv1.RetryPaymentResponse, error) {
+	if in.GetOrderId() != "o-1" {
+		return nil, status.Error(codes.FailedPrecondition, "only orders cancelled for insufficient funds can be retried")
+	}
+	return &ordersv1.RetryPaymentResponse{Order: &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Amount: 10, Status: ordersv1.OrderStatus_ORDER_STATUS_NEW}, RetriesLeft: 2}, nil
+}
+
+func TestRetryPayment(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+
+	rec := httptest.NewRecorder()
+	h.RetryPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/retry-payment", nil), "o-1", gateway.RetryPaymentParams{XUserId: &user})
+	var resp gateway.RetryPaymentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("RetryPayment: status = %d (%v)", rec.Code, err)
+	}
+	if resp.Order.Status != gateway.OrderStatus("NEW") || resp.RetriesLeft != 2 {
+		t.Fatalf("RetryPayment: body = %+v, want NEW with 2 retries left", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	h.RetryPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-2/retry-payment", nil), "o-2", gateway.RetryPaymentParams{XUserId: &user})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("RetryPayment of a paid order: status = %d, want 400", rec.Code)
+	}
+}
+
+func (f *fakePayments) ConfirmPayment(_ context.Context, in *paymentsv1.ConfirmPaymentRequest, _ ...grpc.CallOption) (*paymentsv1.ConfirmPaymentResponse, error) {
+	if in.GetOrderId() != "o-1" {
+		return nil, status.Error(codes.NotFound, "no payment of the order waits for confirmation")
+	}
+	resp := &paymentsv1.ConfirmPaymentResponse{OrderId: in.GetOrderId(), PaymentId: "p-1", Status: paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_SUCCEEDED}
+	if in.GetPaymentId() != "" {
+		resp.PaymentId = in.GetPaymentId()
+		resp.Status, resp.Reason = paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_FAILED, "payment declined"
+	}
+	return resp, nil
+}
+
+func TestConfirmPayment(t *testing.T) {
+	h := New(nil, &fakePayments{}, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	params := gateway.ConfirmPaymentParams{XUserId: &user}
+
+	rec := httptest.NewRecorder()
+	h.ConfirmPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/confirm-payment", nil), "o-1", params)
+	var resp gateway.ConfirmPaymentResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("ConfirmPayment: status = %d (%v)", rec.Code, err)
+	}
+	if resp.Status != gateway.ConfirmPaymentSucceeded || resp.PaymentId != "p-1" || resp.Reason != nil {
+		t.Fatalf("ConfirmPayment: body = %+v, want p-1 SUCCEEDED", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ConfirmPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/confirm-payment", strings.NewReader(`{"payment_id":"p-2"}`)), "o-1", params)
+	resp = gateway.ConfirmPaymentResponse{}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Status != gateway.ConfirmPaymentFailed || resp.PaymentId != "p-2" || resp.Reason == nil {
+		t.Fatalf("ConfirmPayment p-2: status = %d, body = %+v (%v); want p-2 FAILED with a reason", rec.Code, resp, err)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ConfirmPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-1/confirm-payment", strings.NewReader(`{"payment":"p-2"}`)), "o-1", params)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("ConfirmPayment with an unknown field: status = %d, want 400", rec.Code)
+	}
+	rec = httptest.NewRecorder()
+	h.ConfirmPayment(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders/o-2/confirm-payment", nil), "o-2", params)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("ConfirmPayment without a challenge: status = %d, want 404", rec.Code)
+	}
+}
+
+func (fakeOrders) GetOrderReceipt(_ context.Context, in *ordersv1.GetOrderReceiptRequest, _ ...grpc.CallOption) (*ordersv1.GetOrderReceiptResponse, error) {
+	if in.GetOrderId() != "o-1" {
+		return nil, status.Error(codes.FailedPrecondition, "order is not finished")
+	}
+	resp := &ordersv1.GetOrderReceiptResponse{Receipt: &ordersv1.Receipt{
+		Number: "R-20260102-000001", OrderId: in.GetOrderId(), UserId: in.GetUserId(), Currency: "RUB",
+		Items: []*ordersv1.ReceiptItem{
+			{Kind: "order", Description: "book", Quantity: 1, UnitPrice: 500, Amount: 500},
+			{Kind: "fee", Description: "Payment fee", Quantity: 1, UnitPrice: 10, Amount: 10},
+		},
+		Amount: 500, FeeAmount: 10, TotalAmount: 510,
+	}}
+	if in.GetFormat() == "pdf" {
+		resp.Document, resp.ContentType = []byte("%PDF-1.4"), "application/pdf"
+	}
+	return resp, nil
+}
+
+func TestGetOrderReceipt(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+
+	rec := httptest.NewRecorder()
+	h.GetOrderReceipt(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1/receipt", nil), "o-1", gateway.GetOrderReceiptParams{XUserId: &user})
+	var resp gateway.GetOrderReceiptResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("GetOrderReceipt: status = %d (%v)", rec.Code, err)
+	}
+	if resp.Receipt.Number != "R-20260102-000001" || len(resp.Receipt.Items) != 2 || resp.Receipt.Items[1].Kind != gateway.ReceiptItemKindFee || resp.Receipt.TotalAmount != 510 {
+		t.Fatalf("GetOrderReceipt: body = %+v, want the order and fee lines", resp)
+	}
+
+	pdf := gateway.Pdf
+	rec = httptest.NewRecorder()
+	h.GetOrderReceipt(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-1/receipt?format=pdf", nil), "o-1", gateway.GetOrderReceiptParams{XUserId: &user, Format: &pdf})
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/pdf" || rec.Body.String() != "%PDF-1.4" {
+		t.Fatalf("GetOrderReceipt pdf: status = %d, Content-Type = %q, body = %q", rec.Code, rec.Header().Get("Content-Type"), rec.Body)
+	}
+	if got := rec.Header().Get("Content-Disposition"); got != `inline; filename="R-20260102-000001.pdf"` {
+		t.Fatalf("GetOrderReceipt pdf: Content-Disposition = %q", got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetOrderReceipt(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/o-2/receipt", nil), "o-2", gateway.GetOrderReceiptParams{XUserId: &user})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("GetOrderReceipt of an unfinished order: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestCreateOrderPreferAsync(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	for _, tc := range []struct {
+		prefer   string
+		code     int
+		location string
+	}{
+		{"", http.StatusCreated, ""},
+		{"return=minimal", http.StatusCreated, ""},
+		{"respond-async", http.StatusAccepted, "/api/v1/orders/o-1"},
+		{"wait=10, Respond-Async", http.StatusAccepted, "/api/v1/orders/o-1"},
+	} {
+		params := gateway.CreateOrderParams{XUserId: &user, IdempotencyKey: "k-1"}
+		if tc.prefer != "" {
+			prefer := gateway.PreferHeader(tc.prefer)
+			params.Prefer = &prefer
+		}
+		rec := httptest.NewRecorder()
+		h.CreateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"amount":10,"description":"ok"}`)), params)
+		if rec.Code != tc.code || rec.Header().Get("Location") != tc.location {
+			t.Fatalf("Prefer %q: status = %d, Location = %q; want %d, %q", tc.prefer, rec.Code, rec.Header().Get("Location"), tc.code, tc.location)
+		}
+		if applied := rec.Header().Get("Preference-Applied"); (applied != "") != (tc.code == http.StatusAccepted) {
+			t.Fatalf("Prefer %q: Preference-Applied = %q", tc.prefer, applied)
+		}
+		var resp gateway.CreateOrderResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Order.OrderId != "o-1" {
+			t.Fatalf("Prefer %q: body = %+v (%v)", tc.prefer, resp, err)
+		}
+	}
+}
+
+func (fakeOrders) ValidateOrder(_ context.Context, in *ordersv1.CreateOrderRequest, _ ...grpc.CallOption) (*ordersv1.ValidateOrderResponse, error) {
+	if in.GetCurrency() != "" && in.GetCurrency() != "RUB" {
+		return nil, status.Error(codes.InvalidArgument, "currency must be RUB")
+	}
+	if in.GetPromoCode() == "HALF" {
+		return &ordersv1.ValidateOrderResponse{Amount: in.GetAmount() / 2, Currency: "RUB", DiscountAmount: in.GetAmount() / 2}, nil
+	}
+	return &ordersv1.ValidateOrderResponse{Amount: in.GetAmount(), Currency: "RUB"}, nil
+}
+
+func TestValidateOrder(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	params := gateway.ValidateOrderParams{XUserId: &user}
+	for _, tc := range []struct {
+		body string
+		code int
+	}{
+		{`{"amount":10,"description":"ok"}`, http.StatusOK},
+		{`{"amount":10}`, http.StatusBadRequest},
+		{`{"amount":10,"description":"ok","currency":"USD"}`, http.StatusBadRequest},
+	} {
+		rec := httptest.NewRecorder()
+		h.ValidateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders:validate", strings.NewReader(tc.body)), params)
+		if rec.Code != tc.code {
+			t.Fatalf("%s: status = %d, want %d (%s)", tc.body, rec.Code, tc.code, rec.Body.String())
+		}
+		if tc.code != http.StatusOK {
+			continue
+		}
+		var resp gateway.ValidateOrderResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Amount != 10 || resp.Currency != "RUB" || resp.UserId != "u-1" || resp.DiscountAmount != nil {
+			t.Fatalf("%s: body = %+v (%v), want 10 RUB for u-1", tc.body, resp, err)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	h.ValidateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders:validate", strings.NewReader(`{"amount":10,"description":"ok","promo_code":"HALF"}`)), params)
+	var resp gateway.ValidateOrderResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Amount != 5 || resp.DiscountAmount == nil || *resp.DiscountAmount != 5 {
+		t.Fatalf("promo code: body = %+v (%v), want 5 after a discount of 5", resp, err)
+	}
+}
+
+func TestCreateOrderDuplicate(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	params := gateway.CreateOrderParams{XUserId: &user, IdempotencyKey: "k-1"}
+
+	rec := httptest.NewRecorder()
+	h.CreateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"amount":10,"description":"again"}`)), params)
+	var body httperr.Body
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil || rec.Code != http.StatusConflict {
+		t.Fatalf("duplicate: status = %d (%v), want 409", rec.Code, err)
+	}
+	if body.Code != "duplicate_order" || body.Details["order_id"] != "o-0" {
+		t.Fatalf("duplicate: body = %+v, want duplicate_order of o-0", body)
+	}
+
+	rec = httptest.NewRecorder()
+	h.CreateOrder(rec, httptest.NewRequest(http.MethodPost, "/api/v1/orders", strings.NewReader(`{"amount":10,"description":"again","force":true}`)), params)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("forced: status = %d, want 201 (%s)", rec.Code, rec.Body.String())
+	}
+}
+
+func (f *fakePayments) TopUp(_ context.Context, in *paymentsv1.TopUpRequest, _ ...grpc.CallOption) (*paymentsv1.TopUpResponse, error) {
+	if in.GetExpectedVersion() != 0 && in.GetExpectedVersion() != 3 {
+		return nil, status.Errorf(codes.Aborted, "account version is 3, not %d", in.GetExpectedVersion())
+	}
+	return &paymentsv1.TopUpResponse{Account: &paymentsv1.Account{UserId: in.GetUserId(), Balance: in.GetAmount(), Currency: "RUB", Version: 4, AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM}}, nil
+}
+
+func TestTopUpExpectedVersion(t *testing.T) {
+	h := New(nil, &fakePayments{}, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	user := gateway.UserIdHeader("u-1")
+	params := gateway.TopUpAccountParams{XUserId: &user, IdempotencyKey: "k-1"}
+	for _, tc := range []struct {
+		body string
+		code int
+	}{
+		{`{"amount":10}`, http.StatusOK},
+		{`{"amount":10,"expected_version":3}`, http.StatusOK},
+		{`{"amount":10,"expected_version":2}`, http.StatusConflict},
+	} {
+		rec := httptest.NewRecorder()
+		h.TopUpAccount(rec, httptest.NewRequest(http.MethodPost, "/api/v1/payments/account/topup", strings.NewReader(tc.body)), params)
+		if rec.Code != tc.code {
+			t.Fatalf("%s: status = %d, want %d (%s)", tc.body, rec.Code, tc.code, rec.Body.String())
+		}
+		if tc.code != http.StatusOK {
+			continue
+		}
+		var resp gateway.TopUpAccountResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || resp.Version == nil || *resp.Version != 4 {
+			t.Fatalf("%s: body = %+v (%v), want version 4", tc.body, resp, err)
+		}
+		if resp.AccountType == nil || *resp.AccountType != gateway.PREMIUM {
+			t.Fatalf("%s: account_type = %v, want PREMIUM", tc.body, resp.AccountType)
+		}
+	}
+}
+
+func (f *fakePayments) GetBalance(_ context.Context, in *paymentsv1.GetBalanceRequest, _ ...grpc.CallOption) (*paymentsv1.GetBalanceResponse, error) {
+	resp := &paymentsv1.GetBalanceResponse{Balance: 100, Currency: "RUB", Version: 2, AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_BASIC}
+	if in.GetUserId() == "with-bonus" {
+		resp.BonusBalance = 25
+	}
+	return resp, nil
+}
+
+func TestGetBalanceBonus(t *testing.T) {
+	h := New(nil, &fakePayments{}, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	for _, tc := range []struct {
+		user  string
+		bonus int64 // 0 means omitted
+	}{
+		{"no-bonus", 0},
+		{"with-bonus", 25},
+	} {
+		user := gateway.UserIdHeader(tc.user)
+		rec := httptest.NewRecorder()
+		h.GetBalance(rec, httptest.NewRequest(http.MethodGet, "/api/v1/payments/account/balance", nil), gateway.GetBalanceParams{XUserId: &user})
+		var resp gateway.GetBalanceResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+			t.Fatalf("%s: status %d, decode error %v", tc.user, rec.Code, err)
+		}
+		var got int64
+		if resp.BonusBalance != nil {
+			got = int64(*resp.BonusBalance)
+			if got == 0 {
+				t.Fatalf("%s: bonus_balance = 0, want omitted", tc.user)
+			}
+		}
+		if got != tc.bonus {
+			t.Fatalf("%s: bonus_balance = %d, want %d", tc.user, got, tc.bonus)
+		}
+	}
+}
+
+// WaitOrder reports how much time the call was given in the description.
+func (fakeOrders) WaitOrder(ctx context.Context, in *ordersv1.WaitOrderRequest, _ ...grpc.CallOption) (*ordersv1.WaitOrderResponse, error) {
+	if in.GetOrderId() == "missing" {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	dl, _ := ctx.Deadline()
+	order := &ordersv1.Order{OrderId: in.GetOrderId(), UserId: in.GetUserId(), Status: ordersv1.OrderStatus_ORDER_STATUS_NEW, Description: time.Until(dl).Round(time.Second).String()}
+	return &ordersv1.WaitOrderResponse{Order: order, TimedOut: in.GetTimeout().AsDuration() == 10*time.Second}, nil
+}
+
+func TestWaitOrder(t *testing.T) {
+	h := New(fakeOrders{}, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil)
+	for _, tc := range []struct {
+		order, timeout string
+		code           int
+		budget         string
+		timedOut       bool
+	}{
+		{"o-1", "", http.StatusOK, "31s", false},
+		{"o-1", "10s", http.StatusOK, "11s", true},
+		{"o-1", "2m", http.StatusBadRequest, "", false},
+		{"o-1", "soon", http.StatusBadRequest, "", false},
+		{"missing", "1s", http.StatusNotFound, "", false},
+	} {
+		user := gateway.UserIdHeader("u-1")
+		params := gateway.WaitOrderParams{XUserId: &user}
+		if tc.timeout != "" {
+			timeout := gateway.WaitTimeoutQuery(tc.timeout)
+			params.Timeout = &timeout
+		}
+		rec := httptest.NewRecorder()
+		h.WaitOrder(rec, httptest.NewRequest(http.MethodGet, "/api/v1/orders/"+tc.order+"/wait", nil), gateway.OrderIdPath(tc.order), params)
+		if rec.Code != tc.code {
+			t.Fatalf("timeout %q: status = %d, want %d (%s)", tc.timeout, rec.Code, tc.code, rec.Body.String())
+		}
+		if tc.code != http.StatusOK {
+			continue
+		}
+		var resp gateway.WaitOrderResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("timeout %q: decode: %v", tc.timeout, err)
+		}
+		// The wait comes on top of the one second budget.
+		if resp.Order.Description != tc.budget || resp.TimedOut != tc.timedOut {
+			t.Fatalf("timeout %q: budget = %s, timed_out = %v; want %s, %v", tc.timeout, resp.Order.Description, resp.TimedOut, tc.budget, tc.timedOut)
+		}
+	}
+}
+
+func (f *fakePayments) GetRates(context.Context, *paymentsv1.GetRatesRequest, ...grpc.CallOption) (*paymentsv1.GetRatesResponse, error) {
+	return &paymentsv1.GetRatesResponse{
+		Base:  "RUB",
+		Rates: []*paymentsv1.ExchangeRate{{Currency: "EUR", Rate: "97.5"}, {Currency: "USD", Rate: "90"}},
+		AsOf:  timestamppb.New(time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)),
+	}, nil
+}
+
+type noRatesPayments struct {
+	paymentsv1.PaymentsServiceClient
+}
+
+func (noRatesPayments) GetRates(context.Context, *paymentsv1.GetRatesRequest, ...grpc.CallOption) (*paymentsv1.GetRatesResponse, error) {
+	return nil, status.Error(codes.Unavailable, "exchange rates are not configured")
+}
+
+func TestGetRates(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(nil, &fakePayments{}, nil, time.Second, 0, nil, nil, nil, nil, "", nil).GetRates(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rates", nil))
+	var resp gateway.RatesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("status %d, decode error %v", rec.Code, err)
+	}
+	if resp.Base != "RUB" || len(resp.Rates) != 2 || resp.Rates[1] != (gateway.ExchangeRate{Currency: "USD", Rate: "90"}) || resp.AsOf.Year() != 2026 {
+		t.Fatalf("rates = %+v, want EUR and USD in RUB", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	New(nil, noRatesPayments{}, nil, time.Second, 0, nil, nil, nil, nil, "", nil).GetRates(rec, httptest.NewRequest(http.MethodGet, "/api/v1/rates", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("no rates: status = %d, want 503", rec.Code)
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+const notificationKindPrefix = "NOTIFICATION_KIND_"
+
+func (h *Handler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request, params gateway.GetNotificationPreferencesParams) {
+	start := time.Now()
+	userID, ok := h.notificationsUser(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.notifications.GetPreferences(ctx, &notificationsv1.GetPreferencesRequest{UserId: userID})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get notification preferences grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, mapNotificationPreferences(resp.GetPreferences()))
+	h.logger.InfoContext(ctx, "get notification preferences completed", "user_id", userID, "duration", time.Since(start))
+}
+
+func (h *Handler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request, params gateway.UpdateNotificationPreferencesParams) {
+	start := time.Now()
+	userID, ok := h.notificationsUser(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+
+	var body gateway.UpdateNotificationPreferencesRequest
+	if err := decodeJSON(r, &body); err != nil {
+		WriteBadRequest(w, userID, err)
+		return
+	}
+	prefs := &notificationsv1.Preferences{UserId: userID}
+	if body.Email != nil {
+		prefs.Email = *body.Email
+	}
+	if body.TelegramChatId != nil {
+		prefs.TelegramChatId = *body.TelegramChatId
+	}
+	if body.WebhookUrl != nil {
+		prefs.WebhookUrl = *body.WebhookUrl
+	}
+	if body.Kinds != nil {
+		for _, k := range *body.Kinds {
+			v, ok := notificationsv1.NotificationKind_value[notificationKindPrefix+string(k)]
+			if !ok || v == 0 {
+				WriteBadRequest(w, userID, fmt.Errorf("unknown notification kind %q", k))
+				return
+			}
+			prefs.Kinds = append(prefs.Kinds, notificationsv1.NotificationKind(v))
+		}
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.notifications.UpdatePreferences(ctx, &notificationsv1.UpdatePreferencesRequest{Preferences: prefs})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "update notification preferences grpc failed", "err", err, "user_id", userID, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, mapNotificationPreferences(resp.GetPreferences()))
+	h.logger.InfoContext(ctx, "update notification preferences completed", "user_id", userID, "duration", time.Since(start))
+}
+
+// notificationsUser checks that notifications are configured and the caller
+// is a known user.
+func (h *Handler) notificationsUser(w http.ResponseWriter, r *http.Request, header *gateway.UserIdHeader) (string, bool) {
+	if h.notifications == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "notifications are not configured")
+		return "", false
+	}
+	return requireUserID(w, r, header)
+}
+
+func mapNotificationPreferences(p *notificationsv1.Preferences) gateway.NotificationPreferences {
+	out := gateway.NotificationPreferences{
+		UserId:         p.GetUserId(),
+		Email:          p.GetEmail(),
+		TelegramChatId: p.GetTelegramChatId(),
+		WebhookUrl:     p.GetWebhookUrl(),
+		Kinds:          []gateway.NotificationKind{},
+	}
+	for _, k := range p.GetKinds() {
+		out.Kinds = append(out.Kinds, gateway.NotificationKind(strings.TrimPrefix(k.String(), notificationKindPrefix)))
+	}
+	if p.GetUpdatedAt() != nil {
+		updatedAt := p.GetUpdatedAt().AsTime()
+		out.UpdatedAt = &updatedAt
+	}
+	return out
+}
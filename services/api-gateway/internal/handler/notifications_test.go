@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+)
+
+type fakeNotifications struct {
+	notificationsv1.NotificationsServiceClient
+
+	prefs *notificationsv1.Preferences
+}
+
+func (f *fakeNotifications) GetPreferences(_ context.Context, in *notificationsv1.GetPreferencesRequest, _ ...grpc.CallOption) (*notificationsv1.GetPreferencesResponse, error) {
+	if f.prefs == nil || f.prefs.GetUserId() != in.GetUserId() {
+		return &notificationsv1.GetPreferencesResponse{Preferences: &notificationsv1.Preferences{UserId: in.GetUserId()}}, nil
+	}
+	return &notificationsv1.GetPreferencesResponse{Preferences: f.prefs}, nil
+}
+
+func (f *fakeNotifications) UpdatePreferences(_ context.Context, in *notificationsv1.UpdatePreferencesRequest, _ ...grpc.CallOption) (*notificationsv1.UpdatePreferencesResponse, error) {
+	if strings.HasPrefix(in.GetPreferences().GetWebhookUrl(), "ftp:") {
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook_url")
+	}
+	f.prefs = in.GetPreferences()
+	f.prefs.UpdatedAt = timestamppb.Now()
+	return &notificationsv1.UpdatePreferencesResponse{Preferences: f.prefs}, nil
+}
+
+func TestNotificationPreferences(t *testing.T) {
+	user := gateway.UserIdHeader("u-1")
+	rec := httptest.NewRecorder()
+	New(nil, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil).
+		GetNotificationPreferences(rec, httptest.NewRequest(http.MethodGet, "/notifications/preferences", nil), gateway.GetNotificationPreferencesParams{XUserId: &user})
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("not configured: status = %d, want 503", rec.Code)
+	}
+
+	notifications := &fakeNotifications{}
+	h := New(nil, nil, notifications, time.Second, 0, nil, nil, nil, nil, "", nil)
+	put := func(userID, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.UpdateNotificationPreferences(rec, httptest.NewRequest(http.MethodPut, "/notifications/preferences", strings.NewReader(body)), gateway.UpdateNotificationPreferencesParams{XUserId: (*gateway.UserIdHeader)(&userID)})
+		return rec
+	}
+
+	if rec := put("", `{}`); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("no user: status = %d, want 401", rec.Code)
+	}
+	if rec := put("u-1", `{"kinds":["PAYMENT_LOST"]}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("unknown kind: status = %d, want 400", rec.Code)
+	}
+	if rec := put("u-1", `{"webhook_url":"ftp://example.com"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("rejected by service: status = %d, want 400", rec.Code)
+	}
+
+	rec = put("u-1", `{"email":"a@example.com","kinds":["PAYMENT_FAILED"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("update: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if got := notifications.prefs; got.GetEmail() != "a@example.com" || len(got.GetKinds()) != 1 || got.GetKinds()[0] != notificationsv1.NotificationKind_NOTIFICATION_KIND_PAYMENT_FAILED {
+		t.Fatalf("sent preferences = %v", got)
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetNotificationPreferences(rec, httptest.NewRequest(http.MethodGet, "/notifications/preferences", nil), gateway.GetNotificationPreferencesParams{XUserId: &user})
+	var resp gateway.NotificationPreferences
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("get: status = %d, err = %v", rec.Code, err)
+	}
+	if resp.UserId != "u-1" || resp.Email != "a@example.com" || len(resp.Kinds) != 1 || resp.Kinds[0] != "PAYMENT_FAILED" || resp.UpdatedAt == nil {
+		t.Fatalf("get = %+v", resp)
+	}
+}
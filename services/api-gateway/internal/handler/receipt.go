@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// GetOrderReceipt returns the receipt of a finished order as JSON, or with
+// format=pdf the document orders-service rendered.
+func (h *Handler) GetOrderReceipt(w http.ResponseWriter, r *http.Request, orderId gateway.OrderIdPath, params gateway.GetOrderReceiptParams) {
+	start := time.Now()
+	userID, ok := requireUserID(w, r, params.XUserId)
+	if !ok {
+		return
+	}
+	format := ""
+	if params.Format != nil && *params.Format != gateway.Json {
+		format = string(*params.Format)
+	}
+	h.logger.DebugContext(r.Context(), "get order receipt start", "user_id", userID, "order_id", orderId, "format", format)
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.orders.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{
+		UserId:  userID,
+		OrderId: string(orderId),
+		Format:  format,
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get order receipt grpc failed", "err", err, "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		writeGRPCError(w, userID, err)
+		return
+	}
+	if resp.GetReceipt() == nil {
+		h.logger.ErrorContext(ctx, "get order receipt mapping failed", "user_id", userID, "order_id", orderId, "duration", time.Since(start))
+		WriteError(w, userID, http.StatusInternalServerError, "empty receipt response")
+		return
+	}
+
+	if format != "" {
+		w.Header().Set("Content-Type", resp.GetContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("inline; filename=%q", resp.GetReceipt().GetNumber()+"."+format))
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(resp.GetDocument())
+	} else {
+		writeJSON(w, http.StatusOK, gateway.GetOrderReceiptResponse{UserId: userID, Receipt: mapReceipt(resp.GetReceipt())})
+	}
+	h.logger.InfoContext(ctx, "get order receipt completed", "user_id", userID, "order_id", orderId, "number", resp.GetReceipt().GetNumber(), "format", format, "duration", time.Since(start))
+}
+
+func mapReceipt(rc *ordersv1.Receipt) gateway.Receipt {
+	items := make([]gateway.ReceiptItem, len(rc.GetItems()))
+	for i, it := range rc.GetItems() {
+		items[i] = gateway.ReceiptItem{
+			Kind:        gateway.ReceiptItemKind(it.GetKind()),
+			Description: it.GetDescription(),
+			Quantity:    it.GetQuantity(),
+			UnitPrice:   money.Amount(it.GetUnitPrice()),
+			Amount:      money.Amount(it.GetAmount()),
+		}
+		if id := it.GetProductId(); id != "" {
+			items[i].ProductId = &id
+		}
+	}
+	return gateway.Receipt{
+		Number:         rc.GetNumber(),
+		OrderId:        rc.GetOrderId(),
+		Currency:       rc.GetCurrency(),
+		Items:          items,
+		Amount:         money.Amount(rc.GetAmount()),
+		FeeAmount:      money.Amount(rc.GetFeeAmount()),
+		TotalAmount:    money.Amount(rc.GetTotalAmount()),
+		OrderCreatedAt: rc.GetOrderCreatedAt().AsTime(),
+		PaidAt:         rc.GetPaidAt().AsTime(),
+		IssuedAt:       rc.GetIssuedAt().AsTime(),
+	}
+}
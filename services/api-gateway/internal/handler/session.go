@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+)
+
+// CreateSession starts an anonymous session, or renews the one whose token
+// is sent as the bearer so that the user id stays the same.
+func (h *Handler) CreateSession(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	if h.sessions == nil {
+		WriteError(w, "", http.StatusServiceUnavailable, "sessions are not configured")
+		return
+	}
+
+	var (
+		token  string
+		claims auth.Claims
+		err    error
+	)
+	code := http.StatusCreated
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && strings.TrimSpace(bearer) != "" {
+		token, claims, err = h.sessions.Renew(strings.TrimSpace(bearer), start)
+		if err != nil {
+			h.logger.WarnContext(r.Context(), "session renewal rejected", "duration", time.Since(start))
+			WriteError(w, "", http.StatusUnauthorized, err.Error())
+			return
+		}
+		code = http.StatusOK
+	} else {
+		token, claims, err = h.sessions.Issue(start)
+		if err != nil {
+			h.logger.ErrorContext(r.Context(), "session issue failed", "err", err, "duration", time.Since(start))
+			WriteError(w, "", http.StatusInternalServerError, "internal error")
+			return
+		}
+	}
+
+	writeJSON(w, code, gateway.SessionResponse{
+		UserId:    claims.Subject,
+		Token:     token,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+	})
+	h.logger.InfoContext(r.Context(), "create session completed", "user_id", claims.Subject, "renewed", code == http.StatusOK, "duration", time.Since(start))
+}
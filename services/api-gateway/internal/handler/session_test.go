@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+)
+
+func TestCreateSession(t *testing.T) {
+	rec := httptest.NewRecorder()
+	New(nil, nil, nil, time.Second, 0, nil, nil, nil, nil, "", nil).CreateSession(rec, httptest.NewRequest(http.MethodPost, "/api/v1/session", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("not configured: status = %d, want 503", rec.Code)
+	}
+
+	secret := []byte("s")
+	h := New(nil, nil, nil, time.Second, 0, nil, nil, nil, nil, "", auth.NewSessions(secret, time.Hour))
+	start := func(bearer string) (int, gateway.SessionResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/session", nil)
+		if bearer != "" {
+			req.Header.Set("Authorization", "Bearer "+bearer)
+		}
+		rec := httptest.NewRecorder()
+		h.CreateSession(rec, req)
+		var resp gateway.SessionResponse
+		if rec.Code < 300 {
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+		}
+		return rec.Code, resp
+	}
+
+	code, first := start("")
+	if code != http.StatusCreated || first.UserId == "" || first.Token == "" || !first.ExpiresAt.After(time.Now()) {
+		t.Fatalf("start = %d, %+v; want 201 with user id and token", code, first)
+	}
+	claims, err := auth.Verify(first.Token, secret, time.Now())
+	if err != nil || claims.Subject != first.UserId || !claims.HasAny([]auth.Role{auth.RoleUser}) {
+		t.Fatalf("token claims = %+v, %v; want user %s", claims, err, first.UserId)
+	}
+
+	if code, other := start(""); code != http.StatusCreated || other.UserId == first.UserId {
+		t.Fatalf("second start = %d, %s; want a new user id", code, other.UserId)
+	}
+	if code, renewed := start(first.Token); code != http.StatusOK || renewed.UserId != first.UserId {
+		t.Fatalf("renew = %d, %s; want 200 for %s", code, renewed.UserId, first.UserId)
+	}
+	if code, _ := start("x.y.z"); code != http.StatusUnauthorized {
+		t.Fatalf("renew bad token: status = %d, want 401", code)
+	}
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+)
+
+func (h *Handler) GetBalanceAt(w http.ResponseWriter, r *http.Request, userId gateway.AuditUserIdPath, params gateway.GetBalanceAtParams) {
+	start := time.Now()
+	if !h.requireRoles(w, r, auth.RoleSupport, auth.RoleAdmin) {
+		return
+	}
+	if params.At.IsZero() || params.At.After(start) {
+		WriteBadRequest(w, userId, errors.New("at must be a past RFC 3339 timestamp"))
+		return
+	}
+
+	ctx, cancel := h.withBudget(r)
+	defer cancel()
+
+	resp, err := h.payments.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{
+		UserId: userId,
+		At:     timestamppb.New(params.At),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get balance at grpc failed", "err", err, "user_id", userId, "duration", time.Since(start))
+		writeGRPCError(w, userId, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, gateway.BalanceAtResponse{
+		UserId:   userId,
+		Balance:  money.Amount(resp.GetBalance()),
+		Currency: resp.GetCurrency(),
+		At:       resp.GetAt().AsTime(),
+	})
+	h.logger.InfoContext(ctx, "get balance at completed", "user_id", userId, "at", params.At, "duration", time.Since(start))
+}
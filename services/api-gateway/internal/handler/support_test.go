@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	gateway "github.com/ilyaytrewq/payments-service/gen/openapi/gateway"
+
+	"github.com/ilyaytrewq/payments-service/api-gateway/internal/auth"
+)
+
+type fakePayments struct {
+	paymentsv1.PaymentsServiceClient
+
+	req *paymentsv1.GetBalanceAtRequest
+}
+
+func (f *fakePayments) GetBalanceAt(_ context.Context, in *paymentsv1.GetBalanceAtRequest, _ ...grpc.CallOption) (*paymentsv1.GetBalanceAtResponse, error) {
+	f.req = in
+	if in.GetUserId() == "missing" {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	return &paymentsv1.GetBalanceAtResponse{Balance: 120, At: in.GetAt()}, nil
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	payments := &fakePayments{}
+	h := New(nil, payments, nil, time.Second, 0, nil, nil, nil, nil, "secret", nil)
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	params := gateway.GetBalanceAtParams{At: at}
+
+	rec := httptest.NewRecorder()
+	h.GetBalanceAt(rec, httptest.NewRequest(http.MethodGet, "/support/users/u-1/balance", nil), "u-1", params)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("no credentials: status = %d, want 403", rec.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/support/users/u-1/balance", nil)
+	user := auth.Claims{Subject: "u-1", Roles: []auth.Role{auth.RoleUser}}
+	rec = httptest.NewRecorder()
+	h.GetBalanceAt(rec, req.WithContext(auth.WithClaims(req.Context(), user, "t")), "u-1", params)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("user role: status = %d, want 403", rec.Code)
+	}
+
+	support := auth.Claims{Subject: "s-1", Roles: []auth.Role{auth.RoleSupport}}
+	rec = httptest.NewRecorder()
+	h.GetBalanceAt(rec, req.WithContext(auth.WithClaims(req.Context(), support, "t")), "u-1", params)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("support role: status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if !strings.Contains(rec.Body.String(), `"balance":"120"`) {
+		t.Fatalf("balance must be a JSON string: %s", rec.Body)
+	}
+	var resp gateway.BalanceAtResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.UserId != "u-1" || resp.Balance != 120 || !resp.At.Equal(at) {
+		t.Fatalf("response = %+v", resp)
+	}
+	if !payments.req.GetAt().AsTime().Equal(at) {
+		t.Fatalf("forwarded at = %s, want %s", payments.req.GetAt().AsTime(), at)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/support/users/missing/balance", nil)
+	req.Header.Set("X-Admin-Token", "secret")
+	rec = httptest.NewRecorder()
+	h.GetBalanceAt(rec, req, "missing", params)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing account: status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	h.GetBalanceAt(rec, req, "u-1", gateway.GetBalanceAtParams{At: time.Now().Add(time.Hour)})
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("future at: status = %d, want 400", rec.Code)
+	}
+}
@@ -0,0 +1,90 @@
+// Package i18n localizes API error codes into end-user messages.
+//
+// Catalogs are embedded JSON files, locales/<language>.json, mapping an error
+// code to a message. A request's Accept-Language is matched against the
+// catalogs (so "ru-RU" gets "ru"); a code missing from the matched catalog
+// falls back to the default language, and a code missing there too has no
+// message.
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/text/language"
+)
+
+//go:embed locales/*.json
+var locales embed.FS
+
+// Catalog holds the messages of all embedded languages.
+type Catalog struct {
+	// tags[0] is the default language; the matcher falls back to it.
+	tags     []language.Tag
+	matcher  language.Matcher
+	messages map[language.Tag]map[string]string
+}
+
+// New loads the embedded catalogs. defaultLang must be one of them.
+func New(defaultLang string) (*Catalog, error) {
+	def, err := language.Parse(defaultLang)
+	if err != nil {
+		return nil, fmt.Errorf("i18n: default language %q: %w", defaultLang, err)
+	}
+	files, err := locales.ReadDir("locales")
+	if err != nil {
+		return nil, err
+	}
+	c := &Catalog{tags: []language.Tag{def}, messages: make(map[language.Tag]map[string]string, len(files))}
+	for _, f := range files {
+		tag, err := language.Parse(strings.TrimSuffix(f.Name(), path.Ext(f.Name())))
+		if err != nil {
+			return nil, fmt.Errorf("i18n: catalog %s: %w", f.Name(), err)
+		}
+		b, err := locales.ReadFile("locales/" + f.Name())
+		if err != nil {
+			return nil, err
+		}
+		var msgs map[string]string
+		if err := json.Unmarshal(b, &msgs); err != nil {
+			return nil, fmt.Errorf("i18n: catalog %s: %w", f.Name(), err)
+		}
+		c.messages[tag] = msgs
+		if tag != def {
+			c.tags = append(c.tags, tag)
+		}
+	}
+	if _, ok := c.messages[def]; !ok {
+		return nil, fmt.Errorf("i18n: no catalog for default language %q", defaultLang)
+	}
+	c.matcher = language.NewMatcher(c.tags)
+	return c, nil
+}
+
+// Negotiate picks the catalog language for an Accept-Language header. An
+// empty or unparsable header, or one matching no catalog, gets the default
+// language.
+func (c *Catalog) Negotiate(acceptLanguage string) language.Tag {
+	prefs, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(prefs) == 0 {
+		return c.tags[0]
+	}
+	_, i, conf := c.matcher.Match(prefs...)
+	if conf == language.No {
+		return c.tags[0]
+	}
+	return c.tags[i]
+}
+
+// Message returns the message for code in lang, falling back to the default
+// language. ok is false when neither catalog has the code.
+func (c *Catalog) Message(lang language.Tag, code string) (msg string, ok bool) {
+	if msg, ok := c.messages[lang][code]; ok {
+		return msg, true
+	}
+	msg, ok = c.messages[c.tags[0]][code]
+	return msg, ok
+}
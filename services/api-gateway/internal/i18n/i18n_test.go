@@ -0,0 +1,86 @@
+package i18n
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"golang.org/x/text/language"
+
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+)
+
+func TestCatalogsHaveSameCodes(t *testing.T) {
+	c, err := New("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	en := slices.Sorted(maps.Keys(c.messages[language.English]))
+	for tag, msgs := range c.messages {
+		if got := slices.Sorted(maps.Keys(msgs)); !slices.Equal(got, en) {
+			t.Fatalf("%s codes = %v, want %v", tag, got, en)
+		}
+	}
+	for _, code := range []string{
+		httperr.CodeInvalidArgument, httperr.CodeFailedPrecondition, httperr.CodeUnauthenticated,
+		httperr.CodePermissionDenied, httperr.CodeNotFound, httperr.CodeConflict, httperr.CodePayloadTooLarge,
+		httperr.CodeRateLimited, httperr.CodeUnavailable, httperr.CodeTimeout, httperr.CodeCanceled, httperr.CodeInternal,
+	} {
+		if _, ok := c.messages[language.English][code]; !ok {
+			t.Fatalf("no message for %q", code)
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	c, err := New("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		header string
+		want   language.Tag
+	}{
+		{"", language.English},
+		{"ru", language.Russian},
+		{"ru-RU,ru;q=0.9,en;q=0.8", language.Russian},
+		{"de-DE, en;q=0.5", language.English},
+		{"fr", language.English},
+		{"fr, ru;q=0.3", language.Russian},
+		{";;;", language.English},
+	}
+	for _, tt := range tests {
+		if got := c.Negotiate(tt.header); got != tt.want {
+			t.Fatalf("Negotiate(%q) = %s, want %s", tt.header, got, tt.want)
+		}
+	}
+
+	ru, err := New("ru")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := ru.Negotiate("fr"); got != language.Russian {
+		t.Fatalf("default ru: Negotiate(fr) = %s, want ru", got)
+	}
+}
+
+func TestMessageFallback(t *testing.T) {
+	c, err := New("en")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.messages[language.English]["only_en"] = "English only"
+
+	if msg, ok := c.Message(language.Russian, httperr.CodeNotFound); !ok || msg != "Ничего не найдено." {
+		t.Fatalf("ru not_found = %q, %v", msg, ok)
+	}
+	if msg, ok := c.Message(language.Russian, "only_en"); !ok || msg != "English only" {
+		t.Fatalf("ru only_en = %q, %v; want the English fallback", msg, ok)
+	}
+	if _, ok := c.Message(language.Russian, "unknown"); ok {
+		t.Fatal("unknown code has a message")
+	}
+	if _, err := New("de"); err == nil {
+		t.Fatal("New(de) succeeded without a de catalog")
+	}
+}
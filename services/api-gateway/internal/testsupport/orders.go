@@ -0,0 +1,198 @@
+package testsupport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+// FakeOrders is an in-memory OrdersService. Orders are created NEW and stay
+// so unless changed with SetStatus; PayOrder records the payment without
+// resolving it.
+type FakeOrders struct {
+	ordersv1.UnimplementedOrdersServiceServer
+	calls
+
+	orders map[string]*ordersv1.Order
+	// order ids in creation order, for ListOrders
+	ids []string
+	// byKey maps user id + idempotency key to the order it created.
+	byKey map[string]string
+	seq   int
+}
+
+func NewFakeOrders() *FakeOrders {
+	return &FakeOrders{calls: newCalls(), orders: map[string]*ordersv1.Order{}, byKey: map[string]string{}}
+}
+
+// AddOrder stores a copy of o as if it had been created.
+func (f *FakeOrders) AddOrder(o *ordersv1.Order) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.orders[o.GetOrderId()] = proto.Clone(o).(*ordersv1.Order)
+	f.ids = append(f.ids, o.GetOrderId())
+}
+
+// SetStatus changes the status of a stored order.
+func (f *FakeOrders) SetStatus(orderID string, s ordersv1.OrderStatus) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if o, ok := f.orders[orderID]; ok {
+		o.Status = s
+		o.Version++
+	}
+}
+
+func (f *FakeOrders) CreateOrder(_ context.Context, req *ordersv1.CreateOrderRequest) (*ordersv1.CreateOrderResponse, error) {
+	err := f.begin("CreateOrder")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetAmount() <= 0 && len(req.GetItems()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be > 0")
+	}
+
+	key := req.GetUserId() + "\x00" + req.GetIdempotencyKey()
+	if req.GetIdempotencyKey() != "" {
+		if id, ok := f.byKey[key]; ok {
+			return &ordersv1.CreateOrderResponse{Order: proto.Clone(f.orders[id]).(*ordersv1.Order)}, nil
+		}
+	}
+
+	f.seq++
+	now := timestamppb.Now()
+	o := &ordersv1.Order{
+		OrderId:     fmt.Sprintf("order-%d", f.seq),
+		UserId:      req.GetUserId(),
+		Amount:      req.GetAmount(),
+		Description: req.GetDescription(),
+		Status:      ordersv1.OrderStatus_ORDER_STATUS_NEW,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+		Currency:    "RUB",
+		Metadata:    req.GetMetadata(),
+		Tags:        req.GetTags(),
+		Version:     1,
+	}
+	f.orders[o.GetOrderId()] = o
+	f.ids = append(f.ids, o.GetOrderId())
+	if req.GetIdempotencyKey() != "" {
+		f.byKey[key] = o.GetOrderId()
+	}
+	return &ordersv1.CreateOrderResponse{Order: proto.Clone(o).(*ordersv1.Order)}, nil
+}
+
+func (f *FakeOrders) ValidateOrder(_ context.Context, req *ordersv1.CreateOrderRequest) (*ordersv1.ValidateOrderResponse, error) {
+	err := f.begin("ValidateOrder")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be > 0")
+	}
+	return &ordersv1.ValidateOrderResponse{Amount: req.GetAmount(), Currency: "RUB"}, nil
+}
+
+// ListOrders returns all orders of the user, newest first, in one page.
+func (f *FakeOrders) ListOrders(_ context.Context, req *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	err := f.begin("ListOrders")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	resp := &ordersv1.ListOrdersResponse{}
+	for i := len(f.ids) - 1; i >= 0; i-- {
+		if o := f.orders[f.ids[i]]; o.GetUserId() == req.GetUserId() {
+			resp.Orders = append(resp.Orders, proto.Clone(o).(*ordersv1.Order))
+		}
+	}
+	return resp, nil
+}
+
+func (f *FakeOrders) GetOrder(_ context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	err := f.begin("GetOrder")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	o, err := f.find(req.GetUserId(), req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	return &ordersv1.GetOrderResponse{Order: proto.Clone(o).(*ordersv1.Order)}, nil
+}
+
+// GetOrderReceipt issues receipts of FINISHED orders numbered after the
+// order; the "pdf" document is a stub.
+func (f *FakeOrders) GetOrderReceipt(_ context.Context, req *ordersv1.GetOrderReceiptRequest) (*ordersv1.GetOrderReceiptResponse, error) {
+	err := f.begin("GetOrderReceipt")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	o, err := f.find(req.GetUserId(), req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if o.GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_FINISHED {
+		return nil, status.Error(codes.FailedPrecondition, "order is not finished")
+	}
+	resp := &ordersv1.GetOrderReceiptResponse{Receipt: &ordersv1.Receipt{
+		Number:         "R-" + o.GetOrderId(),
+		OrderId:        o.GetOrderId(),
+		UserId:         o.GetUserId(),
+		Currency:       o.GetCurrency(),
+		Items:          []*ordersv1.ReceiptItem{{Kind: "order", Description: o.GetDescription(), Quantity: 1, UnitPrice: o.GetAmount(), Amount: o.GetAmount()}},
+		Amount:         o.GetAmount(),
+		TotalAmount:    o.GetAmount(),
+		OrderCreatedAt: o.GetCreatedAt(),
+		PaidAt:         o.GetUpdatedAt(),
+		IssuedAt:       o.GetUpdatedAt(),
+	}}
+	switch req.GetFormat() {
+	case "":
+	case "pdf":
+		resp.Document, resp.ContentType = []byte("%PDF-1.4 stub"), "application/pdf"
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported receipt format %q", req.GetFormat())
+	}
+	return resp, nil
+}
+
+func (f *FakeOrders) PayOrder(_ context.Context, req *ordersv1.PayOrderRequest) (*ordersv1.PayOrderResponse, error) {
+	err := f.begin("PayOrder")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	o, err := f.find(req.GetUserId(), req.GetOrderId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetAmount() <= 0 || o.GetPaidAmount()+req.GetAmount() > o.GetAmount() {
+		return nil, status.Error(codes.InvalidArgument, "amount exceeds the unpaid part of the order")
+	}
+	f.seq++
+	return &ordersv1.PayOrderResponse{Order: proto.Clone(o).(*ordersv1.Order), PaymentId: fmt.Sprintf("payment-%d", f.seq)}, nil
+}
+
+// find returns the order of the user; orders of other users are not found,
+// as in orders-service.
+func (f *FakeOrders) find(userID, orderID string) (*ordersv1.Order, error) {
+	o, ok := f.orders[orderID]
+	if !ok || o.GetUserId() != userID {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return o, nil
+}
@@ -0,0 +1,105 @@
+package testsupport
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+// FakePayments is an in-memory PaymentsService holding one account per user.
+type FakePayments struct {
+	paymentsv1.UnimplementedPaymentsServiceServer
+	calls
+
+	accounts map[string]*paymentsv1.Account
+	// topUps maps user id + idempotency key to the account after that
+	// top-up, so a retried top-up answers the same without adding again.
+	topUps map[string]*paymentsv1.Account
+}
+
+func NewFakePayments() *FakePayments {
+	return &FakePayments{calls: newCalls(), accounts: map[string]*paymentsv1.Account{}, topUps: map[string]*paymentsv1.Account{}}
+}
+
+// AddAccount stores a copy of a as if it had been created.
+func (f *FakePayments) AddAccount(a *paymentsv1.Account) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.accounts[a.GetUserId()] = proto.Clone(a).(*paymentsv1.Account)
+}
+
+// Balance returns the stored balance of the user, 0 without an account.
+func (f *FakePayments) Balance(userID string) int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.accounts[userID].GetBalance()
+}
+
+func (f *FakePayments) CreateAccount(_ context.Context, req *paymentsv1.CreateAccountRequest) (*paymentsv1.CreateAccountResponse, error) {
+	err := f.begin("CreateAccount")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if _, ok := f.accounts[req.GetUserId()]; ok {
+		return nil, status.Error(codes.AlreadyExists, "account already exists")
+	}
+	a := &paymentsv1.Account{UserId: req.GetUserId(), Currency: "RUB", AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_BASIC}
+	f.accounts[a.GetUserId()] = a
+	return &paymentsv1.CreateAccountResponse{Account: proto.Clone(a).(*paymentsv1.Account)}, nil
+}
+
+func (f *FakePayments) TopUp(_ context.Context, req *paymentsv1.TopUpRequest) (*paymentsv1.TopUpResponse, error) {
+	err := f.begin("TopUp")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be > 0")
+	}
+	key := req.GetUserId() + "\x00" + req.GetIdempotencyKey()
+	if done, ok := f.topUps[key]; ok && req.GetIdempotencyKey() != "" {
+		return &paymentsv1.TopUpResponse{Account: proto.Clone(done).(*paymentsv1.Account)}, nil
+	}
+	a, ok := f.accounts[req.GetUserId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	if v := req.GetExpectedVersion(); v != 0 && v != a.GetVersion() {
+		return nil, status.Error(codes.Aborted, fmt.Sprintf("account version is %d, not %d", a.GetVersion(), v))
+	}
+	a.Balance += req.GetAmount()
+	a.Version++
+	if req.GetIdempotencyKey() != "" {
+		f.topUps[key] = proto.Clone(a).(*paymentsv1.Account)
+	}
+	return &paymentsv1.TopUpResponse{Account: proto.Clone(a).(*paymentsv1.Account)}, nil
+}
+
+func (f *FakePayments) GetBalance(_ context.Context, req *paymentsv1.GetBalanceRequest) (*paymentsv1.GetBalanceResponse, error) {
+	err := f.begin("GetBalance")
+	defer f.end()
+	if err != nil {
+		return nil, err
+	}
+	a, ok := f.accounts[req.GetUserId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	return &paymentsv1.GetBalanceResponse{
+		Balance:      a.GetBalance(),
+		Currency:     a.GetCurrency(),
+		Version:      a.GetVersion(),
+		AccountType:  a.GetAccountType(),
+		BonusBalance: a.GetBonusBalance(),
+	}, nil
+}
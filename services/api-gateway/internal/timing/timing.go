@@ -0,0 +1,103 @@
+// Package timing collects where the time of one gateway request went, for
+// the Server-Timing response header of X-Debug-Timing requests.
+package timing
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+// Entry is one measured span of a request.
+type Entry struct {
+	Name string
+	Desc string
+	Dur  time.Duration
+}
+
+// Recorder gathers the entries of one request; it is safe for concurrent use
+// by the hedged and fanned out calls of that request.
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+type ctxKey struct{}
+
+// With returns ctx carrying a new Recorder.
+func With(ctx context.Context) (context.Context, *Recorder) {
+	r := &Recorder{}
+	return context.WithValue(ctx, ctxKey{}, r), r
+}
+
+// FromContext returns the Recorder of ctx, or nil when timing is off.
+func FromContext(ctx context.Context) *Recorder {
+	r, _ := ctx.Value(ctxKey{}).(*Recorder)
+	return r
+}
+
+// Add records a span; a nil Recorder ignores it.
+func (r *Recorder) Add(name, desc string, d time.Duration) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, Entry{Name: name, Desc: desc, Dur: d})
+}
+
+// Entries returns the spans recorded so far.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// Total sums the spans named name.
+func (r *Recorder) Total(name string) time.Duration {
+	var total time.Duration
+	for _, e := range r.Entries() {
+		if e.Name == name {
+			total += e.Dur
+		}
+	}
+	return total
+}
+
+// UnaryClientInterceptor records every gRPC call made with a Recorder in its
+// context as a "grpc" span described by the method, e.g.
+// "orders.v1.OrdersService/GetOrder".
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		r := FromContext(ctx)
+		if r == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		r.Add("grpc", strings.TrimPrefix(method, "/"), time.Since(start))
+		return err
+	}
+}
+
+// Header formats entries as a Server-Timing header value, durations in
+// milliseconds: `grpc;desc="orders.v1.OrdersService/GetOrder";dur=12.5, total;dur=14.1`.
+func Header(entries []Entry) string {
+	parts := make([]string, 0, len(entries))
+	for _, e := range entries {
+		var b strings.Builder
+		b.WriteString(e.Name)
+		if e.Desc != "" {
+			fmt.Fprintf(&b, ";desc=%s", strconv.Quote(e.Desc))
+		}
+		b.WriteString(";dur=")
+		b.WriteString(strconv.FormatFloat(float64(e.Dur.Microseconds())/1000, 'f', -1, 64))
+		parts = append(parts, b.String())
+	}
+	return strings.Join(parts, ", ")
+}
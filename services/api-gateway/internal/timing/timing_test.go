@@ -0,0 +1,45 @@
+package timing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+)
+
+func TestHeader(t *testing.T) {
+	got := Header([]Entry{
+		{Name: "grpc", Desc: "orders.v1.OrdersService/GetOrder", Dur: 12500 * time.Microsecond},
+		{Name: "total", Dur: 14 * time.Millisecond},
+	})
+	if want := `grpc;desc="orders.v1.OrdersService/GetOrder";dur=12.5, total;dur=14`; got != want {
+		t.Fatalf("Header() = %q, want %q", got, want)
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+		time.Sleep(time.Millisecond)
+		return errors.New("unavailable")
+	}
+
+	// Without a Recorder nothing is recorded, and nil is safe to use.
+	if err := interceptor(context.Background(), "/orders.v1.OrdersService/GetOrder", nil, nil, nil, invoker); err == nil {
+		t.Fatal("interceptor() error = nil, want the call's error")
+	}
+
+	ctx, rec := With(context.Background())
+	for range 2 {
+		_ = interceptor(ctx, "/orders.v1.OrdersService/GetOrder", nil, nil, nil, invoker)
+	}
+	entries := rec.Entries()
+	if len(entries) != 2 || entries[0].Name != "grpc" || entries[0].Desc != "orders.v1.OrdersService/GetOrder" {
+		t.Fatalf("entries = %+v, want two grpc calls of GetOrder", entries)
+	}
+	if rec.Total("grpc") < 2*time.Millisecond {
+		t.Fatalf("Total(grpc) = %s, want both calls summed", rec.Total("grpc"))
+	}
+}
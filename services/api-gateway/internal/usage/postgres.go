@@ -0,0 +1,53 @@
+package usage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type PostgresStore struct {
+	pool *pgxpool.Pool
+}
+
+func NewPostgresStore(pool *pgxpool.Pool) *PostgresStore {
+	return &PostgresStore{pool: pool}
+}
+
+func (s *PostgresStore) Save(ctx context.Context, period string, usage map[string]Usage) error {
+	batch := &pgx.Batch{}
+	for keyID, u := range usage {
+		batch.Queue(`
+INSERT INTO gateway_usage (key_id, period, calls, volume)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (key_id, period) DO UPDATE
+SET calls = GREATEST(gateway_usage.calls, excluded.calls),
+    volume = GREATEST(gateway_usage.volume, excluded.volume),
+    updated_at = now()`, keyID, period, u.Calls, u.Volume)
+	}
+	return s.pool.SendBatch(ctx, batch).Close()
+}
+
+func (s *PostgresStore) List(ctx context.Context, period, keyID string) ([]Record, error) {
+	rows, err := s.pool.Query(ctx, `
+SELECT u.key_id::text, k.name, u.period, u.calls, u.volume, k.monthly_call_quota, k.monthly_volume_quota, u.updated_at
+FROM gateway_usage u
+JOIN gateway_api_keys k ON k.id = u.key_id
+WHERE u.period = $1 AND ($2 = '' OR u.key_id::text = $2)
+ORDER BY u.volume DESC, u.calls DESC, u.key_id`, period, keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Record
+	for rows.Next() {
+		var r Record
+		if err := rows.Scan(&r.KeyID, &r.KeyName, &r.Period, &r.Usage.Calls, &r.Usage.Volume, &r.Quota.Calls, &r.Quota.Volume, &r.UpdatedAt); err != nil {
+			return nil, err
+		}
+		out = append(out, r)
+	}
+	return out, rows.Err()
+}
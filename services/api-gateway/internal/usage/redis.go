@@ -0,0 +1,101 @@
+package usage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// counterTTL keeps a period's counters around long enough to be flushed
+// after the month is over.
+const counterTTL = 62 * 24 * time.Hour
+
+// The period is a hash tag, so all keys of a period share a cluster slot.
+func countersKey(period, keyID string) string { return "usage:{" + period + "}:" + keyID }
+func keysKey(period string) string            { return "usage:{" + period + "}:keys" }
+
+// takeScript checks the quota and counts the call in one step, so
+// concurrent calls on several instances cannot overshoot the call quota.
+// KEYS: counters hash, set of used keys. ARGV: call quota, volume quota,
+// moving (0/1), key id, ttl in seconds.
+var takeScript = redis.NewScript(`
+local calls = tonumber(redis.call('HGET', KEYS[1], 'calls') or '0')
+if tonumber(ARGV[1]) > 0 and calls >= tonumber(ARGV[1]) then
+  return 1
+end
+if ARGV[3] == '1' and tonumber(ARGV[2]) > 0 then
+  local volume = tonumber(redis.call('HGET', KEYS[1], 'volume') or '0')
+  if volume >= tonumber(ARGV[2]) then
+    return 2
+  end
+end
+redis.call('HINCRBY', KEYS[1], 'calls', 1)
+redis.call('EXPIRE', KEYS[1], ARGV[5])
+redis.call('SADD', KEYS[2], ARGV[4])
+redis.call('EXPIRE', KEYS[2], ARGV[5])
+return 0
+`)
+
+// RedisCounters keeps counters in a hash per key and period.
+type RedisCounters struct {
+	rdb redis.UniversalClient
+}
+
+func NewRedisCounters(rdb redis.UniversalClient) *RedisCounters {
+	return &RedisCounters{rdb: rdb}
+}
+
+func (c *RedisCounters) Take(ctx context.Context, period, keyID string, quota Quota, moving bool) error {
+	movingArg := "0"
+	if moving {
+		movingArg = "1"
+	}
+	res, err := takeScript.Run(ctx, c.rdb, []string{countersKey(period, keyID), keysKey(period)},
+		quota.Calls, quota.Volume, movingArg, keyID, int64(counterTTL/time.Second)).Int()
+	if err != nil {
+		return err
+	}
+	switch res {
+	case 1:
+		return ErrCallQuota
+	case 2:
+		return ErrVolumeQuota
+	}
+	return nil
+}
+
+func (c *RedisCounters) AddVolume(ctx context.Context, period, keyID string, amount int64) error {
+	key := countersKey(period, keyID)
+	pipe := c.rdb.TxPipeline()
+	pipe.HIncrBy(ctx, key, "volume", amount)
+	pipe.Expire(ctx, key, counterTTL)
+	pipe.SAdd(ctx, keysKey(period), keyID)
+	pipe.Expire(ctx, keysKey(period), counterTTL)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *RedisCounters) Snapshot(ctx context.Context, period string) (map[string]Usage, error) {
+	ids, err := c.rdb.SMembers(ctx, keysKey(period)).Result()
+	if err != nil || len(ids) == 0 {
+		return nil, err
+	}
+	pipe := c.rdb.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(ids))
+	for i, id := range ids {
+		cmds[i] = pipe.HGetAll(ctx, countersKey(period, id))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	out := make(map[string]Usage, len(ids))
+	for i, id := range ids {
+		fields := cmds[i].Val()
+		calls, _ := strconv.ParseInt(fields["calls"], 10, 64)
+		volume, _ := strconv.ParseInt(fields["volume"], 10, 64)
+		out[id] = Usage{Calls: calls, Volume: volume}
+	}
+	return out, nil
+}
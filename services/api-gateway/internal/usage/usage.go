@@ -0,0 +1,192 @@
+// Package usage meters API calls and transaction volume per API key, the
+// gateway's tenants, enforces monthly quotas and flushes the counts to
+// Postgres for billing.
+package usage
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+var (
+	ErrCallQuota   = errors.New("monthly api call quota exceeded")
+	ErrVolumeQuota = errors.New("monthly transaction volume quota exceeded")
+)
+
+// Usage is what a key used in one period.
+type Usage struct {
+	Calls  int64
+	Volume int64
+}
+
+// Quota caps a key's usage per calendar month (UTC); 0 is unlimited.
+type Quota struct {
+	Calls  int64
+	Volume int64
+}
+
+// Record is a key's flushed usage of one period, as read by the admin API.
+type Record struct {
+	KeyID     string
+	KeyName   string
+	Period    string
+	Usage     Usage
+	Quota     Quota
+	UpdatedAt time.Time
+}
+
+// Period is the month t falls in, as "2006-01" in UTC.
+func Period(t time.Time) string {
+	return t.UTC().Format("2006-01")
+}
+
+// nextPeriod is when the period of t ends.
+func nextPeriod(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Counters keeps the live counts shared by all gateway instances.
+type Counters interface {
+	// Take counts a call of keyID in period unless the key is over quota;
+	// then it returns ErrCallQuota, or ErrVolumeQuota for a write
+	// call, and counts nothing.
+	Take(ctx context.Context, period, keyID string, quota Quota, moving bool) error
+	AddVolume(ctx context.Context, period, keyID string, amount int64) error
+	// Snapshot returns the counts of every key used in period.
+	Snapshot(ctx context.Context, period string) (map[string]Usage, error)
+}
+
+// Store persists flushed counts and reads them back for the admin API.
+type Store interface {
+	// Save writes the counts of period; they only ever grow, so a count
+	// lower than the stored one is ignored.
+	Save(ctx context.Context, period string, usage map[string]Usage) error
+	// List returns the usage of period, of one key when keyID is set.
+	List(ctx context.Context, period, keyID string) ([]Record, error)
+}
+
+var stats = expvar.NewMap("api_usage")
+
+// Meter counts calls and volume in Counters and copies them to the Store
+// from a single goroutine every flushInterval. Quotas are checked against
+// Counters only, so the Store may lag by one interval.
+type Meter struct {
+	counters Counters
+	store    Store
+	interval time.Duration
+	now      func() time.Time
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+
+	logger *slog.Logger
+}
+
+func NewMeter(counters Counters, store Store, flushInterval time.Duration) *Meter {
+	if flushInterval <= 0 {
+		flushInterval = time.Minute
+	}
+	m := &Meter{
+		counters: counters,
+		store:    store,
+		interval: flushInterval,
+		now:      time.Now,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+		logger:   slog.Default().With("service", "api-gateway", "component", "usage"),
+	}
+	go m.run()
+	return m
+}
+
+// Take counts a call of keyID against quota. A rejected call is not
+// counted; its error tells when the quota resets.
+func (m *Meter) Take(ctx context.Context, keyID string, quota Quota, moving bool) (resetAt time.Time, err error) {
+	now := m.now()
+	if err := m.counters.Take(ctx, Period(now), keyID, quota, moving); err != nil {
+		if errors.Is(err, ErrCallQuota) || errors.Is(err, ErrVolumeQuota) {
+			stats.Add("rejected", 1)
+		}
+		return nextPeriod(now), err
+	}
+	stats.Add("calls", 1)
+	return time.Time{}, nil
+}
+
+type ctxKey struct{}
+
+type binding struct {
+	meter *Meter
+	keyID string
+}
+
+// Bind makes AddVolume on ctx count against keyID.
+func (m *Meter) Bind(ctx context.Context, keyID string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, binding{meter: m, keyID: keyID})
+}
+
+// AddVolume adds amount to the transaction volume of the key bound to ctx;
+// without one it does nothing. The money has already moved by then, so a
+// failure is logged rather than returned.
+func AddVolume(ctx context.Context, amount int64) {
+	b, ok := ctx.Value(ctxKey{}).(binding)
+	if !ok || amount <= 0 {
+		return
+	}
+	if err := b.meter.counters.AddVolume(ctx, Period(b.meter.now()), b.keyID, amount); err != nil {
+		stats.Add("volume_errors", 1)
+		b.meter.logger.ErrorContext(ctx, "add usage volume failed", "err", err, "key_id", b.keyID, "amount", amount)
+	}
+}
+
+// Close stops the flush loop after a last flush.
+func (m *Meter) Close() {
+	m.once.Do(func() { close(m.stop) })
+	<-m.done
+}
+
+func (m *Meter) run() {
+	defer close(m.done)
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stop:
+			m.Flush()
+			return
+		case <-ticker.C:
+			m.Flush()
+		}
+	}
+}
+
+// Flush copies the counts of the current period, and of the previous one
+// for calls made just before the month turned, to the Store.
+func (m *Meter) Flush() {
+	now := m.now()
+	periods := []string{Period(now)}
+	if prev := Period(now.Add(-m.interval)); prev != periods[0] {
+		periods = append(periods, prev)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, period := range periods {
+		usage, err := m.counters.Snapshot(ctx, period)
+		if err == nil && len(usage) > 0 {
+			err = m.store.Save(ctx, period, usage)
+		}
+		if err != nil {
+			stats.Add("flush_errors", 1)
+			m.logger.Error("usage flush failed", "err", err, "period", period)
+			continue
+		}
+		stats.Add("flushed", int64(len(usage)))
+	}
+}
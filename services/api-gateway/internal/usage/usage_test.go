@@ -0,0 +1,149 @@
+package usage
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memCounters mirrors RedisCounters.
+type memCounters struct {
+	mu     sync.Mutex
+	counts map[string]map[string]Usage
+}
+
+func newMemCounters() *memCounters {
+	return &memCounters{counts: map[string]map[string]Usage{}}
+}
+
+func (c *memCounters) Take(_ context.Context, period, keyID string, quota Quota, moving bool) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u := c.counts[period][keyID]
+	if quota.Calls > 0 && u.Calls >= quota.Calls {
+		return ErrCallQuota
+	}
+	if moving && quota.Volume > 0 && u.Volume >= quota.Volume {
+		return ErrVolumeQuota
+	}
+	u.Calls++
+	c.set(period, keyID, u)
+	return nil
+}
+
+func (c *memCounters) AddVolume(_ context.Context, period, keyID string, amount int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	u := c.counts[period][keyID]
+	u.Volume += amount
+	c.set(period, keyID, u)
+	return nil
+}
+
+func (c *memCounters) set(period, keyID string, u Usage) {
+	if c.counts[period] == nil {
+		c.counts[period] = map[string]Usage{}
+	}
+	c.counts[period][keyID] = u
+}
+
+func (c *memCounters) Snapshot(_ context.Context, period string) (map[string]Usage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := map[string]Usage{}
+	for k, u := range c.counts[period] {
+		out[k] = u
+	}
+	return out, nil
+}
+
+type memStore struct {
+	mu    sync.Mutex
+	saved map[string]map[string]Usage
+}
+
+func (s *memStore) Save(_ context.Context, period string, usage map[string]Usage) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.saved == nil {
+		s.saved = map[string]map[string]Usage{}
+	}
+	s.saved[period] = usage
+	return nil
+}
+
+func (s *memStore) List(context.Context, string, string) ([]Record, error) { return nil, nil }
+
+func TestPeriod(t *testing.T) {
+	at := time.Date(2025, 12, 31, 23, 30, 0, 0, time.FixedZone("UTC-1", -3600))
+	if got := Period(at); got != "2026-01" {
+		t.Fatalf("Period(%v) = %q, want 2026-01", at, got)
+	}
+	if got := nextPeriod(at); !got.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("nextPeriod(%v) = %v, want 2026-02-01", at, got)
+	}
+}
+
+func TestMeterQuotas(t *testing.T) {
+	counters := newMemCounters()
+	m := NewMeter(counters, &memStore{}, time.Hour)
+	defer m.Close()
+	now := time.Date(2026, 1, 20, 12, 0, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+	quota := Quota{Calls: 3, Volume: 1000}
+
+	if _, err := m.Take(ctx, "k-1", quota, true); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	AddVolume(m.Bind(ctx, "k-1"), 1000)
+	AddVolume(ctx, 5000) // no key bound: ignored
+
+	// The volume quota is used up: write calls are rejected, reads are not.
+	resetAt, err := m.Take(ctx, "k-1", quota, true)
+	if !errors.Is(err, ErrVolumeQuota) || !resetAt.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("write over volume quota = (%v, %v), want ErrVolumeQuota until February", resetAt, err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := m.Take(ctx, "k-1", quota, false); err != nil {
+			t.Fatalf("read #%d: %v", i+1, err)
+		}
+	}
+	if _, err := m.Take(ctx, "k-1", quota, false); !errors.Is(err, ErrCallQuota) {
+		t.Fatalf("fourth call = %v, want ErrCallQuota", err)
+	}
+	if u := counters.counts["2026-01"]["k-1"]; u != (Usage{Calls: 3, Volume: 1000}) {
+		t.Fatalf("usage = %+v, want rejected calls uncounted", u)
+	}
+	if _, err := m.Take(ctx, "k-2", Quota{}, true); err != nil {
+		t.Fatalf("unlimited key: %v", err)
+	}
+
+	// A new month starts from zero.
+	now = time.Date(2026, 2, 1, 0, 0, 1, 0, time.UTC)
+	if _, err := m.Take(ctx, "k-1", quota, true); err != nil {
+		t.Fatalf("call in the next month: %v", err)
+	}
+}
+
+func TestMeterFlush(t *testing.T) {
+	counters := newMemCounters()
+	store := &memStore{}
+	m := NewMeter(counters, store, time.Hour)
+	now := time.Date(2026, 1, 31, 23, 59, 0, 0, time.UTC)
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	_, _ = m.Take(ctx, "k-1", Quota{}, false)
+	now = now.Add(2 * time.Minute)
+	_, _ = m.Take(ctx, "k-1", Quota{}, false)
+	_, _ = m.Take(ctx, "k-2", Quota{}, false)
+
+	// Close flushes the new month and the one that just ended.
+	m.Close()
+	if len(store.saved["2026-01"]) != 1 || len(store.saved["2026-02"]) != 2 || store.saved["2026-02"]["k-2"].Calls != 1 {
+		t.Fatalf("saved = %v, want k-1 in January and both keys in February", store.saved)
+	}
+}
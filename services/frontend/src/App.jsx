@@ -1,20 +1,6 @@
-import React, { useMemo, useState } from "react";
+import React, { useEffect, useState } from "react";
 import toast, { Toaster } from "react-hot-toast";
-import { makeUUID, mustJson, httpJson, sleep } from "./lib/api.js";
-
-function pickStoredUser() {
-  try {
-    return localStorage.getItem("gozon_user_id") || "";
-  } catch {
-    return "";
-  }
-}
-
-function storeUser(v) {
-  try {
-    localStorage.setItem("gozon_user_id", v);
-  } catch { }
-}
+import { clearSession, makeUUID, mustJson, httpJson, session, sleep } from "./lib/api.js";
 
 function pretty(x) {
   try {
@@ -25,7 +11,7 @@ function pretty(x) {
 }
 
 export default function App() {
-  const [userId, setUserId] = useState(pickStoredUser());
+  const [userId, setUserId] = useState("");
   const [balance, setBalance] = useState(null);
 
   const [topupAmount, setTopupAmount] = useState(10000);
@@ -43,47 +29,51 @@ export default function App() {
 
   const [busy, setBusy] = useState(false);
 
-  const resolvedUserId = useMemo(() => userId.trim(), [userId]);
+  useEffect(() => {
+    session()
+      .then((s) => setUserId(s.user_id))
+      .catch((e) => toast.error(e?.message || String(e)));
+  }, []);
 
-  function ensureUserId() {
-    const v = resolvedUserId;
-    if (!v) throw new Error("user_id пустой");
-    return v;
+  // Пользователь запроса — subject токена сессии: gateway подставляет его сам.
+  async function ensureSession() {
+    const s = await session();
+    setUserId(s.user_id);
   }
 
   async function refreshBalance() {
-    const uid = ensureUserId();
-    const data = await mustJson("GET", "/api/v1/payments/account/balance", { userId: uid });
+    await ensureSession();
+    const data = await mustJson("GET", "/api/v1/payments/account/balance");
     setBalance(data.balance);
     return data.balance;
   }
 
   async function createAccount() {
-    const uid = ensureUserId();
+    await ensureSession();
     const idemKey = makeUUID();
-    const data = await mustJson("POST", "/api/v1/payments/account", { userId: uid, idemKey, body: {} });
+    const data = await mustJson("POST", "/api/v1/payments/account", { idemKey, body: {} });
     toast.success("Счёт создан");
     setBalance(data.balance ?? 0);
   }
 
   async function topup() {
-    const uid = ensureUserId();
+    await ensureSession();
     const amount = Number(topupAmount);
     if (!Number.isFinite(amount) || amount <= 0) throw new Error("amount должен быть > 0");
 
     const idemKey = makeUUID();
-    const data = await mustJson("POST", "/api/v1/payments/account/topup", { userId: uid, idemKey, body: { amount } });
+    const data = await mustJson("POST", "/api/v1/payments/account/topup", { idemKey, body: { amount } });
     toast.success("Баланс пополнен");
     setBalance(data.balance);
   }
 
   async function createOrder() {
-    const uid = ensureUserId();
+    await ensureSession();
     const amount = Number(orderAmount);
     if (!Number.isFinite(amount) || amount <= 0) throw new Error("amount должен быть > 0");
 
     const idemKey = makeUUID();
-    const data = await mustJson("POST", "/api/v1/orders", { userId: uid, idemKey, body: { amount, description: orderDesc || "order" } });
+    const data = await mustJson("POST", "/api/v1/orders", { idemKey, body: { amount, description: orderDesc || "order" } });
     const id = data?.order?.order_id || "";
     toast.success("Заказ создан");
     setOrderId(id);
@@ -91,30 +81,30 @@ export default function App() {
   }
 
   async function listOrders() {
-    const uid = ensureUserId();
+    await ensureSession();
     const l = Math.min(200, Math.max(1, Number(limit) || 50));
-    const data = await mustJson("GET", `/api/v1/orders?limit=${encodeURIComponent(l)}`, { userId: uid });
+    const data = await mustJson("GET", `/api/v1/orders?limit=${encodeURIComponent(l)}`);
     setOrders(data.orders || []);
     return data.orders || [];
   }
 
   async function getOrder() {
-    const uid = ensureUserId();
+    await ensureSession();
     const id = orderId.trim();
     if (!id) throw new Error("order_id пустой");
-    const data = await mustJson("GET", `/api/v1/orders/${encodeURIComponent(id)}`, { userId: uid });
+    const data = await mustJson("GET", `/api/v1/orders/${encodeURIComponent(id)}`);
     setOrderDetails(data);
     return data;
   }
 
   async function pollFinal() {
-    const uid = ensureUserId();
+    await ensureSession();
     const id = orderId.trim();
     if (!id) throw new Error("order_id пустой");
 
     const deadline = Date.now() + 20000;
     while (Date.now() < deadline) {
-      const data = await mustJson("GET", `/api/v1/orders/${encodeURIComponent(id)}`, { userId: uid });
+      const data = await mustJson("GET", `/api/v1/orders/${encodeURIComponent(id)}`);
       setOrderDetails(data);
       const st = data?.order?.status;
       if (st === "FINISHED" || st === "CANCELLED") {
@@ -127,7 +117,7 @@ export default function App() {
   }
 
   async function runIdempotency() {
-    const uid = ensureUserId();
+    await ensureSession();
     const n = Math.min(30, Math.max(2, Number(idemN) || 5));
     const idemKey = makeUUID();
 
@@ -135,13 +125,13 @@ export default function App() {
     if (idemKind === "topup") {
       const amount = Number(topupAmount) || 1000;
       calls = Array.from({ length: n }, () =>
-        httpJson("POST", "/api/v1/payments/account/topup", { userId: uid, idemKey, body: { amount } })
+        httpJson("POST", "/api/v1/payments/account/topup", { idemKey, body: { amount } })
       );
     } else {
       const amount = Number(orderAmount) || 1000;
       const description = (orderDesc || "idem-order") + " (idem)";
       calls = Array.from({ length: n }, () =>
-        httpJson("POST", "/api/v1/orders", { userId: uid, idemKey, body: { amount, description } })
+        httpJson("POST", "/api/v1/orders", { idemKey, body: { amount, description } })
       );
     }
 
@@ -176,13 +166,15 @@ export default function App() {
             POST требуют заголовок <span className="kbd">Idempotency-Key</span>; UI ставит его сам.
           </div>
         </div>
-        <button className="secondary" disabled={busy} onClick={() => {
-          const v = "user-" + makeUUID().slice(0, 8);
-          setUserId(v);
-          storeUser(v);
-          toast.success("user_id сгенерирован");
-        }}>
-          Сгенерировать user_id
+        <button className="secondary" disabled={busy} onClick={() => wrap(async () => {
+          clearSession();
+          await ensureSession();
+          setBalance(null);
+          setOrders([]);
+          setOrderDetails(null);
+          toast.success("Новая сессия");
+        })}>
+          Новая сессия
         </button>
       </header>
 
@@ -191,11 +183,7 @@ export default function App() {
         <div className="grid2">
           <div className="field">
             <label>user_id</label>
-            <input
-              value={userId}
-              onChange={(e) => { setUserId(e.target.value); storeUser(e.target.value); }}
-              placeholder="например, test-user-1"
-            />
+            <input value={userId} readOnly placeholder="сессия запускается…" />
           </div>
 
           <div className="field">
@@ -256,6 +244,7 @@ export default function App() {
                 <div key={o.order_id} className="item" onClick={() => { setOrderId(o.order_id); wrap(getOrder); }}>
                   <b>{o.order_id}</b><br />
                   status: <b>{o.status}</b> · amount: {o.amount}<br />
+                  {o.payment_failure_reason && <span className="muted">причина: {o.payment_failure_reason}<br /></span>}
                   <span className="muted">{o.description}</span>
                 </div>
               ))}
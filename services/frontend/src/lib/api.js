@@ -8,16 +8,83 @@ export function makeUUID() {
   });
 }
 
-export async function httpJson(method, path, { userId, idemKey, body } = {}) {
-  const headers = { "Content-Type": "application/json" };
-  if (userId) headers["X-User-Id"] = userId;
+const SESSION_PATH = "/api/v1/session";
+const SESSION_KEY = "gozon_session";
+// Сессию продлеваем заранее, пока до истечения осталось меньше минуты.
+const RENEW_BEFORE_MS = 60 * 1000;
+
+function loadSession() {
+  try {
+    return JSON.parse(localStorage.getItem(SESSION_KEY) || "null");
+  } catch {
+    return null;
+  }
+}
+
+function saveSession(s) {
+  try {
+    localStorage.setItem(SESSION_KEY, JSON.stringify(s));
+  } catch { }
+}
+
+export function clearSession() {
+  try {
+    localStorage.removeItem(SESSION_KEY);
+  } catch { }
+}
+
+async function requestSession(token) {
+  const headers = {};
+  if (token) headers["Authorization"] = `Bearer ${token}`;
+  const res = await fetch(SESSION_PATH, { method: "POST", headers });
+  if (res.status === 401 && token) {
+    // Токен уже не принимается: начинаем новую сессию.
+    return requestSession(null);
+  }
+  if (!res.ok) {
+    throw new Error(`POST ${SESSION_PATH} -> ${res.status}: ${await res.text()}`);
+  }
+  const s = await res.json();
+  saveSession(s);
+  return s;
+}
+
+let pendingSession = null;
+
+// session возвращает действующую сессию {user_id, token, expires_at}: начинает
+// её через POST /session или продлевает тем же запросом с текущим токеном,
+// чтобы user_id (а с ним счёт и заказы) сохранялся.
+export async function session() {
+  const s = loadSession();
+  const left = s ? Date.parse(s.expires_at) - Date.now() : 0;
+  if (s && left > RENEW_BEFORE_MS) return s;
+  if (!pendingSession) {
+    pendingSession = requestSession(s && left > 0 ? s.token : null).finally(() => {
+      pendingSession = null;
+    });
+  }
+  return pendingSession;
+}
+
+async function send(method, path, { idemKey, body } = {}) {
+  const { token } = await session();
+  const headers = { "Content-Type": "application/json", "Authorization": `Bearer ${token}` };
   if (idemKey) headers["Idempotency-Key"] = idemKey;
 
-  const res = await fetch(path, {
+  return fetch(path, {
     method,
     headers,
     body: body === undefined ? undefined : JSON.stringify(body),
   });
+}
+
+export async function httpJson(method, path, opts = {}) {
+  let res = await send(method, path, opts);
+  if (res.status === 401) {
+    // Сессия истекла или секрет gateway сменился: один повтор с новой сессией.
+    clearSession();
+    res = await send(method, path, opts);
+  }
 
   const text = await res.text();
   let data = null;
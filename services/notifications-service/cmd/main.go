@@ -0,0 +1,38 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/app"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+func main() {
+	cfg := config.MustLoad()
+
+	logger, level, err := logging.NewLogger("notifications-service", cfg.LogLevel, cfg.LogFormat, logging.Redaction{
+		Mode:   cfg.LogRedact,
+		Fields: cfg.LogRedactFields,
+		Salt:   cfg.LogRedactSalt,
+	}, cfg.LogDev)
+	if err != nil {
+		slog.Error("invalid log config", "err", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go logging.WatchLogLevel(ctx, "notifications-service", level)
+
+	if err := app.Run(ctx, cfg); err != nil {
+		slog.Error("notifications service stopped with error", "err", err)
+		os.Exit(1)
+	}
+}
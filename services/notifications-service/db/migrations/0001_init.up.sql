@@ -0,0 +1,35 @@
+-- Настройки уведомлений пользователя; канал включён, если задан его адрес
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id text PRIMARY KEY,
+    email text NOT NULL DEFAULT '',
+    telegram_chat_id text NOT NULL DEFAULT '',
+    webhook_url text NOT NULL DEFAULT '',
+    -- Виды уведомлений (PAYMENT_SUCCEEDED, ...); пустой массив — все
+    kinds text[] NOT NULL DEFAULT '{}',
+    updated_at timestamptz NOT NULL DEFAULT now()
+    );
+
+-- Одна доставка на событие и канал: повтор события из Kafka не шлёт второе уведомление
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id bigserial PRIMARY KEY,
+    event_id uuid NOT NULL,
+    user_id text NOT NULL,
+    kind text NOT NULL,
+    channel text NOT NULL,
+    recipient text NOT NULL,
+    subject text NOT NULL,
+    body text NOT NULL,
+    payload jsonb NOT NULL,
+    -- PENDING -> SENT | DEAD
+    status text NOT NULL DEFAULT 'PENDING',
+    attempts int NOT NULL DEFAULT 0,
+    last_error text,
+    next_attempt_at timestamptz NOT NULL DEFAULT now(),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    sent_at timestamptz,
+    UNIQUE (event_id, channel)
+    );
+
+CREATE INDEX IF NOT EXISTS notification_deliveries_due_idx
+    ON notification_deliveries (next_attempt_at)
+    WHERE status = 'PENDING';
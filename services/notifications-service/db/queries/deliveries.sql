@@ -0,0 +1,34 @@
+-- Повтор того же события по тому же каналу игнорируется
+-- name: InsertDelivery :execrows
+INSERT INTO notification_deliveries (event_id, user_id, kind, channel, recipient, subject, body, payload)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+ON CONFLICT (event_id, channel) DO NOTHING;
+
+-- Забираем пачку к отправке и сразу сдвигаем next_attempt_at на lease: отправка идёт вне транзакции,
+-- а упавший на полпути инстанс не держит строки дольше lease
+-- name: ClaimDueDeliveries :many
+UPDATE notification_deliveries d
+SET next_attempt_at = now() + sqlc.arg(lease_ms)::bigint * interval '1 millisecond'
+WHERE d.id IN (
+    SELECT id
+    FROM notification_deliveries
+    WHERE status = 'PENDING' AND next_attempt_at <= now()
+    ORDER BY next_attempt_at, id
+    LIMIT sqlc.arg('limit')
+    FOR UPDATE SKIP LOCKED
+)
+    RETURNING d.id, d.event_id, d.user_id, d.kind, d.channel, d.recipient, d.subject, d.body, d.payload, d.attempts;
+
+-- name: MarkDeliverySent :exec
+UPDATE notification_deliveries
+SET status = 'SENT', attempts = attempts + 1, last_error = NULL, sent_at = now()
+WHERE id = $1;
+
+-- dead: попытки кончились или ошибка постоянная, доставка больше не повторяется
+-- name: MarkDeliveryFailed :exec
+UPDATE notification_deliveries
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    status = CASE WHEN sqlc.arg(dead)::boolean THEN 'DEAD' ELSE 'PENDING' END,
+    next_attempt_at = now() + sqlc.arg(backoff_ms)::bigint * interval '1 millisecond'
+WHERE id = sqlc.arg(id);
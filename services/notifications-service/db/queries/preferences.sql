@@ -0,0 +1,15 @@
+-- name: GetPreferences :one
+SELECT user_id, email, telegram_chat_id, webhook_url, kinds, updated_at
+FROM notification_preferences
+WHERE user_id = $1;
+
+-- name: UpsertPreferences :one
+INSERT INTO notification_preferences (user_id, email, telegram_chat_id, webhook_url, kinds)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (user_id) DO UPDATE
+SET email = excluded.email,
+    telegram_chat_id = excluded.telegram_chat_id,
+    webhook_url = excluded.webhook_url,
+    kinds = excluded.kinds,
+    updated_at = now()
+    RETURNING user_id, email, telegram_chat_id, webhook_url, kinds, updated_at;
@@ -0,0 +1,36 @@
+module github.com/ilyaytrewq/payments-service/notifications-service
+
+go 1.25.4
+
+require (
+	github.com/google/uuid v1.6.0
+	github.com/ilyaytrewq/payments-service/gen v0.0.0
+	github.com/ilyaytrewq/payments-service/pkg v0.0.0
+	github.com/jackc/pgx/v5 v5.7.6
+	github.com/segmentio/kafka-go v0.4.49
+	golang.org/x/sync v0.19.0
+	google.golang.org/grpc v1.78.0
+	google.golang.org/protobuf v1.36.11
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	github.com/jackc/puddle/v2 v2.2.2 // indirect
+	github.com/klauspost/compress v1.17.8 // indirect
+	github.com/pierrec/lz4/v4 v4.1.21 // indirect
+	github.com/twmb/franz-go v1.17.0 // indirect
+	github.com/twmb/franz-go/pkg/kmsg v1.8.0 // indirect
+	github.com/xdg-go/pbkdf2 v1.0.0 // indirect
+	github.com/xdg-go/scram v1.1.2 // indirect
+	github.com/xdg-go/stringprep v1.0.4 // indirect
+	golang.org/x/crypto v0.44.0 // indirect
+	golang.org/x/net v0.47.0 // indirect
+	golang.org/x/sys v0.38.0 // indirect
+	golang.org/x/text v0.31.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251029180050-ab9386a59fda // indirect
+)
+
+replace github.com/ilyaytrewq/payments-service/gen => ../../gen
+
+replace github.com/ilyaytrewq/payments-service/pkg => ../../pkg
@@ -0,0 +1,240 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/auth"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/dispatch"
+	grpcsvc "github.com/ilyaytrewq/payments-service/notifications-service/internal/grpc"
+	kafkasvc "github.com/ilyaytrewq/payments-service/notifications-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+func Run(ctx context.Context, cfg config.Config) error {
+	start := time.Now()
+	logger := slog.Default().With("service", "notifications-service", "component", "app")
+	logger.Info("notifications service starting", "grpc_addr", cfg.GRPCAddr, "kafka_brokers", len(cfg.KafkaBrokers), "reflection", cfg.EnableReflection)
+
+	currency, err := money.ParseCurrency(cfg.Currency)
+	if err != nil {
+		logger.Error("invalid currency", "err", err)
+		return err
+	}
+	serviceAuth, err := svcauth.ParseConfig(cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	if err != nil {
+		logger.Error("invalid service auth config", "err", err)
+		return err
+	}
+
+	kafkaSecurity := kafkaio.Security{
+		TLS:           cfg.KafkaTLS,
+		CAFile:        cfg.KafkaTLSCAFile,
+		CertFile:      cfg.KafkaTLSCertFile,
+		KeyFile:       cfg.KafkaTLSKeyFile,
+		SASLMechanism: cfg.KafkaSASLMechanism,
+		SASLUsername:  cfg.KafkaSASLUsername,
+		SASLPassword:  cfg.KafkaSASLPassword,
+	}
+	kafkaDialer, err := kafkaSecurity.Dialer()
+	if err != nil {
+		logger.Error("failed to configure kafka dialer", "err", err)
+		return err
+	}
+
+	if err := waitForDependencies(ctx, cfg, kafkaDialer, logger); err != nil {
+		return err
+	}
+
+	providers, err := newProviders(cfg)
+	if err != nil {
+		logger.Error("invalid channel config", "err", err)
+		return err
+	}
+	channels := make([]string, 0, len(providers))
+	for _, ch := range []string{channel.Email, channel.Telegram, channel.Webhook} {
+		if _, ok := providers[ch]; ok {
+			channels = append(channels, ch)
+		}
+	}
+	if len(channels) == 0 {
+		logger.Warn("no delivery channel configured, notifications are dropped")
+	}
+	logger.Info("delivery channels configured", "channels", channels)
+
+	dbLogger := slog.Default().With("service", "notifications-service", "component", "repo")
+	pool, err := pgtx.NewPool(ctx, cfg.DatabaseURL, pgtx.PoolOptions{
+		MaxConns:       int32(cfg.DBMaxConns),
+		ConnectRetries: cfg.DBConnectRetries,
+		ConnectBackoff: cfg.DBConnectBackoff,
+	}, dbLogger)
+	if err != nil {
+		logger.Error("failed to create db pool", "err", err)
+		return err
+	}
+	defer pool.Close()
+	repo := postgres.NewRepo(pool, pgtx.Config{Retries: cfg.DBTxRetries, Backoff: cfg.DBTxRetryBackoff})
+
+	// One group, one reader per topic: each topic has its own decoder.
+	readerOpts := kafkaio.ReaderOptions{
+		StartOffset:       cfg.KafkaConsumerStartOffset,
+		MaxWait:           cfg.KafkaConsumerMaxWait,
+		QueueCapacity:     cfg.KafkaConsumerQueueCapacity,
+		RebalanceTimeout:  cfg.KafkaConsumerRebalanceTimeout,
+		SessionTimeout:    cfg.KafkaConsumerSessionTimeout,
+		HeartbeatInterval: cfg.KafkaConsumerHeartbeatInterval,
+	}
+	var consumers []*kafkasvc.Consumer
+	for topic, decode := range map[string]kafkasvc.Decoder{
+		cfg.TopicPaymentResult:    kafkasvc.PaymentResults(currency),
+		cfg.TopicStatusChanged:    kafkasvc.OrderStatusChanges(currency),
+		cfg.TopicPaymentChallenge: kafkasvc.PaymentChallenges(currency),
+	} {
+		reader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, topic, cfg.ConsumerGroupID, readerOpts)
+		if err != nil {
+			logger.Error("invalid kafka consumer config", "err", err, "topic", topic)
+			return err
+		}
+		defer func() {
+			if err := reader.Close(); err != nil {
+				logger.Error("failed to close kafka reader", "err", err, "topic", topic)
+			}
+		}()
+		consumers = append(consumers, kafkasvc.NewConsumer(repo, reader, decode, channels, cfg.KafkaHandlerTimeout))
+	}
+
+	dispatcher := dispatch.New(repo, providers, cfg.DispatchInterval, cfg.DispatchBatch, cfg.SendTimeout, dispatch.RetryPolicy{
+		MaxAttempts: cfg.MaxAttempts,
+		Backoff:     cfg.RetryBackoff,
+		MaxBackoff:  cfg.RetryMaxBackoff,
+	})
+
+	interceptors := []grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(), grpcUnaryLogger()}
+	if serviceAuth.Enabled() {
+		interceptors = append(interceptors, auth.ServiceInterceptor(serviceAuth))
+		logger.Info("service auth enabled", "mode", serviceAuth.Mode)
+	} else {
+		logger.Warn("SERVICE_AUTH_MODE is empty, service auth disabled")
+	}
+	if cfg.JWTSecret != "" {
+		interceptors = append(interceptors, auth.UnaryServerInterceptor([]byte(cfg.JWTSecret)))
+		logger.Info("rbac enabled")
+	} else {
+		logger.Warn("JWT_SECRET is empty, rbac disabled")
+	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	notificationsv1.RegisterNotificationsServiceServer(grpcServer, grpcsvc.NewHandlers(repo))
+	if cfg.EnableReflection {
+		reflection.Register(grpcServer)
+		logger.Info("grpc reflection enabled")
+	}
+
+	lis, err := net.Listen("tcp", cfg.GRPCAddr)
+	if err != nil {
+		logger.Error("failed to listen on grpc address", "err", err, "grpc_addr", cfg.GRPCAddr)
+		return err
+	}
+
+	g, ctx := errgroup.WithContext(ctx)
+
+	g.Go(func() error {
+		logger.Info("grpc listening", "grpc_addr", cfg.GRPCAddr)
+		return grpcServer.Serve(lis)
+	})
+
+	g.Go(func() error {
+		<-ctx.Done()
+		logger.Info("grpc shutting down")
+		grpcServer.GracefulStop()
+		return nil
+	})
+
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		g.Go(func() error {
+			logger.Info("metrics listening", "metrics_addr", cfg.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return metricsServer.Shutdown(shutdownCtx)
+		})
+	}
+
+	for _, consumer := range consumers {
+		g.Go(func() error {
+			err := consumer.Run(ctx)
+			if err != nil {
+				logger.Error("notifications consumer stopped with error", "err", err)
+			}
+			return err
+		})
+	}
+
+	g.Go(func() error {
+		err := dispatcher.Run(ctx)
+		if err != nil {
+			logger.Error("dispatcher stopped with error", "err", err)
+		}
+		return err
+	})
+
+	err = g.Wait()
+	if err != nil {
+		logger.Error("notifications service stopped with error", "err", err, "duration", time.Since(start))
+	} else {
+		logger.Info("notifications service stopped", "duration", time.Since(start))
+	}
+	return err
+}
+
+// newProviders builds a provider for every channel that is configured; the
+// others are neither enqueued nor sent.
+func newProviders(cfg config.Config) (map[string]channel.Provider, error) {
+	client := &http.Client{Timeout: cfg.SendTimeout}
+	providers := make(map[string]channel.Provider)
+	if cfg.SMTPAddr != "" {
+		providers[channel.Email] = channel.NewSMTP(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPUsername, cfg.SMTPPassword)
+	}
+	if cfg.TelegramBotToken != "" {
+		providers[channel.Telegram] = channel.NewTelegramBot(cfg.TelegramBotToken, client)
+	}
+	if cfg.WebhookSigningKeys != "" {
+		keys, err := signature.ParseKeyring(cfg.WebhookSigningKeys)
+		if err != nil {
+			return nil, err
+		}
+		providers[channel.Webhook] = channel.NewWebhooks(keys, channel.NewWebhookClient(cfg.SendTimeout))
+	}
+	return providers, nil
+}
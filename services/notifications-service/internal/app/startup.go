@@ -0,0 +1,21 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/pkg/startup"
+)
+
+// waitForDependencies holds the service back until Postgres and Kafka answer or
+// cfg.StartupWaitTimeout runs out.
+func waitForDependencies(ctx context.Context, cfg config.Config, dialer *kafka.Dialer, logger *slog.Logger) error {
+	deps := []startup.Dependency{
+		startup.Postgres("postgres", cfg.DatabaseURL),
+		startup.Kafka("kafka", dialer, cfg.KafkaBrokers),
+	}
+	return startup.Wait(ctx, startup.Config{Timeout: cfg.StartupWaitTimeout, CheckTimeout: cfg.StartupCheckTimeout}, logger, deps...)
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+var secret = []byte("test-secret")
+
+func mustSign(t *testing.T, c jwt.Claims) string {
+	t.Helper()
+	if c.ExpiresAt == 0 {
+		c.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	}
+	token, err := jwt.Sign(c, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	return token
+}
+
+func call(t *testing.T, method, token string, req interface{}) codes.Code {
+	t.Helper()
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(userauth.MetadataKey, "Bearer "+token))
+	}
+	intercept := UnaryServerInterceptor(secret)
+	_, err := intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := userauth.FromContext(ctx); !ok && strings.HasPrefix(method, servicePrefix) {
+			t.Fatal("handler called without claims in context")
+		}
+		return nil, nil
+	})
+	return status.Code(err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user := mustSign(t, jwt.Claims{Subject: "u-1", Roles: []jwt.Role{jwt.RoleUser}})
+	support := mustSign(t, jwt.Claims{Subject: "s-1", Roles: []jwt.Role{jwt.RoleSupport}})
+	admin := mustSign(t, jwt.Claims{Subject: "a-1", Roles: []jwt.Role{jwt.RoleAdmin}})
+	get := notificationsv1.NotificationsService_GetPreferences_FullMethodName
+	update := notificationsv1.NotificationsService_UpdatePreferences_FullMethodName
+	prefs := func(userID string) *notificationsv1.UpdatePreferencesRequest {
+		return &notificationsv1.UpdatePreferencesRequest{Preferences: &notificationsv1.Preferences{UserId: userID}}
+	}
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		req    interface{}
+		want   codes.Code
+	}{
+		{"missing token", get, "", &notificationsv1.GetPreferencesRequest{UserId: "u-1"}, codes.Unauthenticated},
+		{"own preferences", get, user, &notificationsv1.GetPreferencesRequest{UserId: "u-1"}, codes.OK},
+		{"other user", get, user, &notificationsv1.GetPreferencesRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"support reads any user", get, support, &notificationsv1.GetPreferencesRequest{UserId: "u-2"}, codes.OK},
+		{"own update", update, user, prefs("u-1"), codes.OK},
+		{"support cannot update", update, support, prefs("u-2"), codes.PermissionDenied},
+		{"admin updates", update, admin, prefs("u-2"), codes.OK},
+		{"unlisted rpc", "/notifications.v1.NotificationsService/Drop", user, nil, codes.PermissionDenied},
+		{"health is not covered", "/grpc.health.v1.Health/Check", "", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		if got := call(t, tt.method, tt.token, tt.req); got != tt.want {
+			t.Fatalf("%s: code = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMethodRolesCoverService(t *testing.T) {
+	for _, m := range notificationsv1.NotificationsService_ServiceDesc.Methods {
+		method := "/" + notificationsv1.NotificationsService_ServiceDesc.ServiceName + "/" + m.MethodName
+		if len(methodRoles[method]) == 0 {
+			t.Errorf("%s has no roles", method)
+		}
+	}
+}
+
+func TestMethodCallers(t *testing.T) {
+	for method := range methodRoles {
+		if len(methodCallers[method]) == 0 {
+			t.Errorf("%s has roles but no allowed services", method)
+		}
+	}
+	for method := range methodCallers {
+		if _, ok := methodRoles[method]; !ok {
+			t.Errorf("%s has allowed services but no roles", method)
+		}
+	}
+}
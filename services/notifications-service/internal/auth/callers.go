@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"google.golang.org/grpc"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+// Service identities of the callers, as named in SERVICE_AUTH_TOKENS or in
+// the SPIFFE ids of their tokens.
+const (
+	serviceName = "notifications-service"
+	callerGW    = "api-gateway"
+)
+
+// methodCallers lists the services allowed to call each RPC when service
+// authentication is on. RPCs missing from this table are denied to every
+// service, notifications-service included.
+var methodCallers = map[string][]string{
+	notificationsv1.NotificationsService_GetPreferences_FullMethodName:    {callerGW},
+	notificationsv1.NotificationsService_UpdatePreferences_FullMethodName: {callerGW},
+}
+
+// ServiceInterceptor checks the service token of every call against
+// methodCallers.
+func ServiceInterceptor(cfg svcauth.Config) grpc.UnaryServerInterceptor {
+	return svcauth.UnaryServerInterceptor(cfg, serviceName, servicePrefix, methodCallers)
+}
@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// servicePrefix marks the RPCs covered by methodRoles; anything else (health,
+// reflection) is not subject to RBAC.
+const servicePrefix = "/notifications.v1."
+
+// methodRoles lists the roles allowed to call each RPC. RPCs of the service
+// missing from this table are denied.
+var methodRoles = map[string][]jwt.Role{
+	notificationsv1.NotificationsService_GetPreferences_FullMethodName:    {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	notificationsv1.NotificationsService_UpdatePreferences_FullMethodName: {jwt.RoleUser, jwt.RoleAdmin},
+}
+
+// UnaryServerInterceptor enforces methodRoles using the bearer token from the
+// authorization metadata. Plain users may only act on their own user_id.
+func UnaryServerInterceptor(secret []byte) grpc.UnaryServerInterceptor {
+	logger := slog.Default().With("service", "notifications-service", "component", "auth")
+	return userauth.UnaryServerInterceptor(secret, servicePrefix, methodRoles, logger)
+}
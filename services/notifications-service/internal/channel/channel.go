@@ -0,0 +1,48 @@
+// Package channel delivers rendered notifications over email, Telegram and
+// webhooks. Each channel is a Provider; the dispatcher picks one by the
+// channel name stored with the delivery.
+package channel
+
+import (
+	"context"
+	"errors"
+)
+
+// Channel names, as stored in notification_deliveries.channel.
+const (
+	Email    = "email"
+	Telegram = "telegram"
+	Webhook  = "webhook"
+)
+
+// Message is one delivery. ID is the event id, the same for every attempt, so
+// receivers can drop repeats.
+type Message struct {
+	ID        string
+	Recipient string
+	Subject   string
+	Body      string
+	Payload   []byte
+}
+
+// Provider sends messages over one channel.
+type Provider interface {
+	Send(ctx context.Context, m Message) error
+}
+
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one a retry cannot fix, e.g. an address the
+// provider rejects; the delivery is dead-lettered at once.
+func Permanent(err error) error {
+	return permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var p permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,151 @@
+package channel
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+func TestWebhooks(t *testing.T) {
+	keys, err := signature.ParseKeyring("k1:secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	status := http.StatusNoContent
+	var got *http.Request
+	var body []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer srv.Close()
+
+	// The test server listens on loopback, which the webhook client refuses;
+	// its own client only keeps the redirect policy.
+	client := srv.Client()
+	client.CheckRedirect = noRedirects
+	w := NewWebhooks(keys, client)
+	msg := Message{ID: "e-1", Recipient: srv.URL + "/hook", Payload: []byte(`{"id":"e-1"}`)}
+	if err := w.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got.Header.Get(WebhookIDHeader) != "e-1" || string(body) != `{"id":"e-1"}` {
+		t.Fatalf("request headers %v, body %s", got.Header, body)
+	}
+	if err := keys.Verify(got.Header.Get(signature.Header), body, time.Now(), time.Minute); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+
+	status = http.StatusGone
+	if err := w.Send(context.Background(), msg); !IsPermanent(err) {
+		t.Fatalf("410 = %v, want a permanent error", err)
+	}
+	status = http.StatusTooManyRequests
+	if err := w.Send(context.Background(), msg); err == nil || IsPermanent(err) {
+		t.Fatalf("429 = %v, want a retryable error", err)
+	}
+	status = http.StatusFound
+	if err := w.Send(context.Background(), msg); !IsPermanent(err) {
+		t.Fatalf("302 = %v, want a permanent error without following it", err)
+	}
+	for _, raw := range []string{"ftp://example.com", "http://example.com/hook"} {
+		msg.Recipient = raw
+		if err := w.Send(context.Background(), msg); !IsPermanent(err) {
+			t.Fatalf("%s = %v, want a permanent error", raw, err)
+		}
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	keys, err := signature.ParseKeyring("k1:secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hit := false
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hit = true
+	}))
+	defer srv.Close()
+
+	w := NewWebhooks(keys, NewWebhookClient(time.Second))
+	err = w.Send(context.Background(), Message{ID: "e-1", Recipient: srv.URL + "/hook", Payload: []byte(`{}`)})
+	if !errors.Is(err, ErrBlockedAddress) || !IsPermanent(err) || hit {
+		t.Fatalf("Send to %s = %v (hit %v), want a permanent ErrBlockedAddress", srv.URL, err, hit)
+	}
+
+	for _, addr := range []string{"10.0.0.1:443", "169.254.169.254:80", "[::1]:443", "0.0.0.0:443"} {
+		if err := publicAddressOnly("tcp", addr, nil); !errors.Is(err, ErrBlockedAddress) {
+			t.Fatalf("publicAddressOnly(%s) = %v, want ErrBlockedAddress", addr, err)
+		}
+	}
+	if err := publicAddressOnly("tcp", "93.184.216.34:443", nil); err != nil {
+		t.Fatalf("publicAddressOnly(public) = %v", err)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	status := http.StatusOK
+	var path string
+	var req map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+	}))
+	defer srv.Close()
+
+	tg := NewTelegramBot("123:abc", srv.Client())
+	tg.baseURL = srv.URL
+	msg := Message{ID: "e-1", Recipient: "42", Subject: "Payment succeeded", Body: "Paid."}
+	if err := tg.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if path != "/bot123:abc/sendMessage" || req["chat_id"] != "42" || req["text"] != "Payment succeeded\n\nPaid." {
+		t.Fatalf("request %s %v", path, req)
+	}
+
+	status = http.StatusBadRequest
+	if err := tg.Send(context.Background(), msg); !IsPermanent(err) || !strings.Contains(err.Error(), "chat not found") {
+		t.Fatalf("400 = %v, want a permanent error with the description", err)
+	}
+	status = http.StatusBadGateway
+	if err := tg.Send(context.Background(), msg); err == nil || IsPermanent(err) {
+		t.Fatalf("502 = %v, want a retryable error", err)
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	s := NewSMTP("mail:25", "noreply@example.com", "", "")
+	var to []string
+	var msg []byte
+	s.send = func(_ string, _ smtp.Auth, _ string, rcpt []string, m []byte) error {
+		to, msg = rcpt, m
+		return nil
+	}
+	err := s.Send(context.Background(), Message{ID: "e-1", Recipient: "Ann <ann@example.com>", Subject: "Платёж прошёл", Body: "Paid."})
+	if err != nil || len(to) != 1 || to[0] != "ann@example.com" {
+		t.Fatalf("Send = %v, to %v", err, to)
+	}
+	if !strings.Contains(string(msg), "Subject: =?utf-8?q?") || !strings.HasSuffix(string(msg), "\r\n\r\nPaid.\r\n") {
+		t.Fatalf("message:\n%s", msg)
+	}
+
+	s.send = func(string, smtp.Auth, string, []string, []byte) error { return errors.New("relay down") }
+	if err := s.Send(context.Background(), Message{Recipient: "ann@example.com"}); err == nil || IsPermanent(err) {
+		t.Fatalf("relay down = %v, want a retryable error", err)
+	}
+	if err := s.Send(context.Background(), Message{Recipient: "not an address"}); !IsPermanent(err) {
+		t.Fatalf("bad address = %v, want a permanent error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"time"
+)
+
+// SMTP sends plain-text email through a relay. Username empty means the
+// relay takes mail without auth.
+type SMTP struct {
+	addr     string
+	from     string
+	username string
+	password string
+
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTP(addr, from, username, password string) *SMTP {
+	return &SMTP{addr: addr, from: from, username: username, password: password, send: smtp.SendMail}
+}
+
+func (s *SMTP) Send(ctx context.Context, m Message) error {
+	to, err := mail.ParseAddress(m.Recipient)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid email address: %w", err))
+	}
+	var auth smtp.Auth
+	if s.username != "" {
+		host, _, _ := net.SplitHostPort(s.addr)
+		auth = smtp.PlainAuth("", s.username, s.password, host)
+	}
+	// net/smtp takes no context: a cancelled send is abandoned and finishes
+	// in the background.
+	done := make(chan error, 1)
+	go func() {
+		done <- s.send(s.addr, auth, s.from, []string{to.Address}, buildMail(s.from, to.Address, m, time.Now()))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildMail renders an RFC 5322 message with a UTF-8 plain-text body.
+func buildMail(from, to string, m Message, now time.Time) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	fmt.Fprintf(&b, "Message-ID: <%s@notifications>\r\n", m.ID)
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(m.Body)
+	b.WriteString("\r\n")
+	return b.Bytes()
+}
@@ -0,0 +1,57 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const telegramAPI = "https://api.telegram.org"
+
+// TelegramBot sends messages through a bot; the recipient is a chat id the user
+// got from the bot.
+type TelegramBot struct {
+	token   string
+	baseURL string
+	client  *http.Client
+}
+
+func NewTelegramBot(token string, client *http.Client) *TelegramBot {
+	return &TelegramBot{token: token, baseURL: telegramAPI, client: client}
+}
+
+func (t *TelegramBot) Send(ctx context.Context, m Message) error {
+	body, err := json.Marshal(map[string]string{
+		"chat_id": m.Recipient,
+		"text":    m.Subject + "\n\n" + m.Body,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/bot"+t.token+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	var apiErr struct {
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 4<<10)).Decode(&apiErr)
+	err = fmt.Errorf("telegram: %s: %s", resp.Status, apiErr.Description)
+	// 400 and 403 mean a bad chat id or a bot the user blocked.
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusForbidden {
+		return Permanent(err)
+	}
+	return err
+}
@@ -0,0 +1,108 @@
+package channel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+	"github.com/ilyaytrewq/payments-service/pkg/webhooksdk"
+)
+
+// WebhookIDHeader carries the event id, stable across redeliveries.
+const WebhookIDHeader = webhooksdk.IDHeader
+
+// ErrBlockedAddress is returned for webhook URLs that resolve to an address
+// of our own network.
+var ErrBlockedAddress = errors.New("webhook: address not allowed")
+
+// Webhooks POSTs the JSON payload to the user's URL, signed with keys in the
+// signature.Header format, so receivers can check it came from us. The URL
+// is user input, so client should come from NewWebhookClient.
+type Webhooks struct {
+	keys   *signature.Keyring
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewWebhooks(keys *signature.Keyring, client *http.Client) *Webhooks {
+	return &Webhooks{keys: keys, client: client, now: time.Now}
+}
+
+func (w *Webhooks) Send(ctx context.Context, m Message) error {
+	if err := ValidateWebhookURL(m.Recipient); err != nil {
+		return Permanent(err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Recipient, bytes.NewReader(m.Payload))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, m.ID)
+	req.Header.Set(signature.Header, w.keys.Sign(m.Payload, w.now()))
+	resp, err := w.client.Do(req)
+	if err != nil {
+		if errors.Is(err, ErrBlockedAddress) {
+			return Permanent(err)
+		}
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode/100 == 2 {
+		return nil
+	}
+	err = fmt.Errorf("webhook: %s", resp.Status)
+	// Redirects are not followed, and other 4xx will not change on retry;
+	// timeouts and throttling may.
+	if resp.StatusCode/100 == 3 || (resp.StatusCode/100 == 4 && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode != http.StatusTooManyRequests) {
+		return Permanent(err)
+	}
+	return err
+}
+
+// ValidateWebhookURL accepts absolute https URLs. Where they point is
+// checked when connecting, see NewWebhookClient.
+func ValidateWebhookURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("webhook url %q: want an absolute https url", raw)
+	}
+	return nil
+}
+
+// NewWebhookClient returns the client for user-supplied webhook URLs. It
+// does not follow redirects, goes through no proxy and refuses to connect
+// to private, loopback, link-local and unspecified addresses, so a webhook
+// cannot reach our own network. The check runs on the resolved address, so
+// DNS names pointing inside are refused too.
+func NewWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: publicAddressOnly}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{Timeout: timeout, Transport: transport, CheckRedirect: noRedirects}
+}
+
+func noRedirects(*http.Request, []*http.Request) error {
+	return http.ErrUseLastResponse
+}
+
+// publicAddressOnly is a net.Dialer Control refusing addresses of our own
+// network.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+// Package dispatch sends queued deliveries through the channel providers and
+// retries failed ones with backoff until they are sent or dead.
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+)
+
+var stats = expvar.NewMap("notifications")
+
+// RetryPolicy bounds redelivery of failed sends.
+type RetryPolicy struct {
+	// MaxAttempts is the number of sends before a delivery is dead; 0
+	// retries forever.
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Delay returns the wait after failed attempt number attempt (1-based):
+// Backoff doubled per previous attempt and capped at MaxBackoff.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// Dispatcher polls due deliveries. Several instances may run at once: a
+// claimed batch is hidden from the others for as long as sending it may take.
+type Dispatcher struct {
+	repo        repo.Repository
+	providers   map[string]channel.Provider
+	interval    time.Duration
+	batch       int
+	sendTimeout time.Duration
+	retry       RetryPolicy
+}
+
+// New builds the dispatcher. providers maps channel names to the configured
+// providers; deliveries of other channels are dead-lettered.
+func New(r repo.Repository, providers map[string]channel.Provider, interval time.Duration, batch int, sendTimeout time.Duration, retry RetryPolicy) *Dispatcher {
+	slog.Default().With("service", "notifications-service", "component", "dispatch").Info("dispatcher initialized", "interval", interval.String(), "batch", batch, "send_timeout", sendTimeout.String(), "max_attempts", retry.MaxAttempts)
+	return &Dispatcher{repo: r, providers: providers, interval: interval, batch: batch, sendTimeout: sendTimeout, retry: retry}
+}
+
+func (d *Dispatcher) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "notifications-service", "component", "dispatch")
+	logger.Info("dispatcher run start")
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		full, err := d.DispatchOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			logger.Error("dispatch cycle failed", "err", err)
+		}
+		if full && err == nil {
+			// More deliveries are due; do not wait for the next tick.
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("dispatcher context done")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchOnce sends one batch of due deliveries. full reports whether the
+// batch was full, i.e. more may be due.
+func (d *Dispatcher) DispatchOnce(ctx context.Context) (full bool, err error) {
+	logger := slog.Default().With("service", "notifications-service", "component", "dispatch")
+	rows, err := d.repo.Q().ClaimDueDeliveries(ctx, db.ClaimDueDeliveriesParams{
+		LeaseMs: (time.Duration(d.batch) * d.sendTimeout).Milliseconds(),
+		Limit:   int32(d.batch),
+	})
+	if err != nil {
+		return false, err
+	}
+	for _, row := range rows {
+		sendErr := d.send(ctx, row)
+		if sendErr == nil {
+			if err := d.repo.Q().MarkDeliverySent(ctx, row.ID); err != nil {
+				return false, err
+			}
+			stats.Add("sent", 1)
+			logger.Info("notification sent", "delivery_id", row.ID, "channel", row.Channel, "kind", row.Kind)
+			continue
+		}
+
+		attempt := int(row.Attempts) + 1
+		dead := channel.IsPermanent(sendErr) || (d.retry.MaxAttempts > 0 && attempt >= d.retry.MaxAttempts)
+		delay := d.retry.Delay(attempt)
+		if err := d.repo.Q().MarkDeliveryFailed(ctx, db.MarkDeliveryFailedParams{
+			ID:        row.ID,
+			LastError: pgtype.Text{String: sendErr.Error(), Valid: true},
+			Dead:      dead,
+			BackoffMs: delay.Milliseconds(),
+		}); err != nil {
+			return false, err
+		}
+		if dead {
+			stats.Add("dead", 1)
+			logger.Error("notification dead", "err", sendErr, "delivery_id", row.ID, "channel", row.Channel, "attempts", attempt)
+			continue
+		}
+		stats.Add("failed", 1)
+		logger.Warn("notification send failed, will retry", "err", sendErr, "delivery_id", row.ID, "channel", row.Channel, "attempts", attempt, "backoff", delay)
+	}
+	return len(rows) == d.batch, nil
+}
+
+func (d *Dispatcher) send(ctx context.Context, row db.ClaimDueDeliveriesRow) error {
+	p, ok := d.providers[row.Channel]
+	if !ok {
+		return channel.Permanent(errors.New("channel " + row.Channel + " is not configured"))
+	}
+	ctx, cancel := context.WithTimeout(ctx, d.sendTimeout)
+	defer cancel()
+	return p.Send(ctx, channel.Message{
+		ID:        row.EventID.String(),
+		Recipient: row.Recipient,
+		Subject:   row.Subject,
+		Body:      row.Body,
+		Payload:   row.Payload,
+	})
+}
@@ -0,0 +1,106 @@
+package dispatch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+)
+
+// fakeRepo hands out due rows once and records how each one ended.
+type fakeRepo struct {
+	db.Querier
+	due    []db.ClaimDueDeliveriesRow
+	sent   []int64
+	failed []db.MarkDeliveryFailedParams
+}
+
+func (f *fakeRepo) Q() db.Querier { return f }
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) ClaimDueDeliveries(_ context.Context, arg db.ClaimDueDeliveriesParams) ([]db.ClaimDueDeliveriesRow, error) {
+	n := min(int(arg.Limit), len(f.due))
+	rows := f.due[:n]
+	f.due = f.due[n:]
+	return rows, nil
+}
+
+func (f *fakeRepo) MarkDeliverySent(_ context.Context, id int64) error {
+	f.sent = append(f.sent, id)
+	return nil
+}
+
+func (f *fakeRepo) MarkDeliveryFailed(_ context.Context, arg db.MarkDeliveryFailedParams) error {
+	f.failed = append(f.failed, arg)
+	return nil
+}
+
+type providerFunc func(ctx context.Context, m channel.Message) error
+
+func (f providerFunc) Send(ctx context.Context, m channel.Message) error { return f(ctx, m) }
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	for attempt, want := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 5 * time.Second, 30: 5 * time.Second} {
+		if got := p.Delay(attempt); got != want {
+			t.Fatalf("Delay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+}
+
+func TestDispatchOnce(t *testing.T) {
+	repo := &fakeRepo{due: []db.ClaimDueDeliveriesRow{
+		{ID: 1, Channel: channel.Email, Recipient: "ok@example.com"},
+		{ID: 2, Channel: channel.Email, Recipient: "flaky@example.com", Attempts: 1},
+		{ID: 3, Channel: channel.Email, Recipient: "gone@example.com"},
+		{ID: 4, Channel: channel.Email, Recipient: "flaky@example.com", Attempts: 4},
+		{ID: 5, Channel: channel.Telegram, Recipient: "1"},
+	}}
+	email := providerFunc(func(_ context.Context, m channel.Message) error {
+		switch m.Recipient {
+		case "flaky@example.com":
+			return errors.New("connection refused")
+		case "gone@example.com":
+			return channel.Permanent(errors.New("mailbox unavailable"))
+		}
+		return nil
+	})
+	d := New(repo, map[string]channel.Provider{channel.Email: email}, time.Second, 5, time.Second, RetryPolicy{MaxAttempts: 5, Backoff: time.Second, MaxBackoff: time.Minute})
+
+	full, err := d.DispatchOnce(context.Background())
+	if err != nil || !full {
+		t.Fatalf("DispatchOnce = (%v, %v), want a full batch", full, err)
+	}
+	if len(repo.sent) != 1 || repo.sent[0] != 1 {
+		t.Fatalf("sent = %v, want [1]", repo.sent)
+	}
+	want := map[int64]struct {
+		dead    bool
+		backoff int64
+	}{
+		2: {false, 2000}, // second attempt fails: retried after 2s
+		3: {true, 1000},  // permanent error
+		4: {true, 16000}, // fifth attempt of five
+		5: {true, 1000},  // no Telegram provider
+	}
+	if len(repo.failed) != len(want) {
+		t.Fatalf("failed = %+v", repo.failed)
+	}
+	for _, f := range repo.failed {
+		w := want[f.ID]
+		if f.Dead != w.dead || !f.LastError.Valid {
+			t.Fatalf("delivery %d: dead = %v, last error %q; want dead %v", f.ID, f.Dead, f.LastError.String, w.dead)
+		}
+		if !f.Dead && f.BackoffMs != w.backoff {
+			t.Fatalf("delivery %d: backoff = %dms, want %dms", f.ID, f.BackoffMs, w.backoff)
+		}
+	}
+
+	if full, err := d.DispatchOnce(context.Background()); err != nil || full {
+		t.Fatalf("DispatchOnce(empty) = (%v, %v)", full, err)
+	}
+}
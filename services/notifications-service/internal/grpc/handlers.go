@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/mail"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/notify"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+const kindPrefix = "NOTIFICATION_KIND_"
+
+type Handlers struct {
+	notificationsv1.UnimplementedNotificationsServiceServer
+
+	repo   repo.Repository
+	logger *slog.Logger
+}
+
+func NewHandlers(r repo.Repository) *Handlers {
+	return &Handlers{repo: r, logger: slog.Default().With("service", "notifications-service", "component", "grpc")}
+}
+
+func (h *Handlers) GetPreferences(ctx context.Context, req *notificationsv1.GetPreferencesRequest) (*notificationsv1.GetPreferencesResponse, error) {
+	if strings.TrimSpace(req.GetUserId()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	row, err := h.repo.Q().GetPreferences(ctx, req.GetUserId())
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return &notificationsv1.GetPreferencesResponse{Preferences: &notificationsv1.Preferences{UserId: req.GetUserId()}}, nil
+		}
+		h.logger.ErrorContext(ctx, "get preferences failed", "err", err)
+		return nil, status.Error(codes.Internal, "failed to get preferences")
+	}
+	return &notificationsv1.GetPreferencesResponse{Preferences: mapPreferences(row)}, nil
+}
+
+func (h *Handlers) UpdatePreferences(ctx context.Context, req *notificationsv1.UpdatePreferencesRequest) (*notificationsv1.UpdatePreferencesResponse, error) {
+	p := req.GetPreferences()
+	if strings.TrimSpace(p.GetUserId()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	// The interceptor only sees top-level user ids; a plain user may only
+	// change their own preferences.
+	if claims, ok := userauth.FromContext(ctx); ok && !claims.Privileged() && p.GetUserId() != claims.Subject {
+		h.logger.WarnContext(ctx, "user id does not match token subject", "sub", claims.Subject)
+		return nil, status.Error(codes.PermissionDenied, "user_id does not match token")
+	}
+	params := db.UpsertPreferencesParams{
+		UserID:         p.GetUserId(),
+		Email:          strings.TrimSpace(p.GetEmail()),
+		TelegramChatID: strings.TrimSpace(p.GetTelegramChatId()),
+		WebhookUrl:     strings.TrimSpace(p.GetWebhookUrl()),
+		Kinds:          []string{},
+	}
+	if params.Email != "" {
+		addr, err := mail.ParseAddress(params.Email)
+		if err != nil || addr.Address != params.Email {
+			return nil, status.Error(codes.InvalidArgument, "invalid email")
+		}
+	}
+	if params.TelegramChatID != "" {
+		if _, err := strconv.ParseInt(params.TelegramChatID, 10, 64); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "telegram_chat_id must be a numeric chat id")
+		}
+	}
+	if params.WebhookUrl != "" {
+		if err := channel.ValidateWebhookURL(params.WebhookUrl); err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid webhook_url: want an absolute https url")
+		}
+	}
+	for _, k := range p.GetKinds() {
+		if k == notificationsv1.NotificationKind_NOTIFICATION_KIND_UNSPECIFIED || !slices.Contains(notify.Kinds, kindFromProto(k)) {
+			return nil, status.Errorf(codes.InvalidArgument, "unknown notification kind %v", k)
+		}
+		if name := string(kindFromProto(k)); !slices.Contains(params.Kinds, name) {
+			params.Kinds = append(params.Kinds, name)
+		}
+	}
+
+	row, err := h.repo.Q().UpsertPreferences(ctx, params)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "update preferences failed", "err", err)
+		return nil, status.Error(codes.Internal, "failed to update preferences")
+	}
+	h.logger.InfoContext(ctx, "preferences updated", "email", row.Email != "", "telegram", row.TelegramChatID != "", "webhook", row.WebhookUrl != "", "kinds", row.Kinds)
+	return &notificationsv1.UpdatePreferencesResponse{Preferences: mapPreferences(row)}, nil
+}
+
+func mapPreferences(row db.NotificationPreference) *notificationsv1.Preferences {
+	p := &notificationsv1.Preferences{
+		UserId:         row.UserID,
+		Email:          row.Email,
+		TelegramChatId: row.TelegramChatID,
+		WebhookUrl:     row.WebhookUrl,
+		UpdatedAt:      timestamppb.New(row.UpdatedAt.Time),
+	}
+	for _, k := range row.Kinds {
+		p.Kinds = append(p.Kinds, notificationsv1.NotificationKind(notificationsv1.NotificationKind_value[kindPrefix+k]))
+	}
+	return p
+}
+
+func kindFromProto(k notificationsv1.NotificationKind) notify.Kind {
+	return notify.Kind(strings.TrimPrefix(k.String(), kindPrefix))
+}
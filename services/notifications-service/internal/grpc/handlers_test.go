@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	notificationsv1 "github.com/ilyaytrewq/payments-service/gen/go/notifications/v1"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// fakeRepo keeps preferences in memory. Queries the handlers do not use fall
+// through to the nil embedded Querier and panic.
+type fakeRepo struct {
+	db.Querier
+	prefs map[string]db.NotificationPreference
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{prefs: map[string]db.NotificationPreference{}}
+}
+
+func (f *fakeRepo) Q() db.Querier { return f }
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) GetPreferences(_ context.Context, userID string) (db.NotificationPreference, error) {
+	p, ok := f.prefs[userID]
+	if !ok {
+		return db.NotificationPreference{}, pgx.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakeRepo) UpsertPreferences(_ context.Context, arg db.UpsertPreferencesParams) (db.NotificationPreference, error) {
+	p := db.NotificationPreference{
+		UserID:         arg.UserID,
+		Email:          arg.Email,
+		TelegramChatID: arg.TelegramChatID,
+		WebhookUrl:     arg.WebhookUrl,
+		Kinds:          arg.Kinds,
+		UpdatedAt:      pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.prefs[arg.UserID] = p
+	return p, nil
+}
+
+func wantCode(t *testing.T, err error, want codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Fatalf("code = %v (%v), want %v", got, err, want)
+	}
+}
+
+func TestGetPreferencesDefaults(t *testing.T) {
+	h := NewHandlers(newFakeRepo())
+
+	_, err := h.GetPreferences(context.Background(), &notificationsv1.GetPreferencesRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+
+	resp, err := h.GetPreferences(context.Background(), &notificationsv1.GetPreferencesRequest{UserId: "u-1"})
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if p := resp.GetPreferences(); p.GetUserId() != "u-1" || p.GetEmail() != "" || len(p.GetKinds()) != 0 {
+		t.Fatalf("preferences = %v, want empty ones of u-1", p)
+	}
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo)
+	ctx := context.Background()
+
+	for _, p := range []*notificationsv1.Preferences{
+		{Email: "a@example.com"},
+		{UserId: "u-1", Email: "not an email"},
+		{UserId: "u-1", Email: "Alice <a@example.com>"},
+		{UserId: "u-1", TelegramChatId: "@alice"},
+		{UserId: "u-1", WebhookUrl: "ftp://example.com/hook"},
+		{UserId: "u-1", WebhookUrl: "/hook"},
+		{UserId: "u-1", WebhookUrl: "http://example.com/hook"},
+		{UserId: "u-1", Kinds: []notificationsv1.NotificationKind{notificationsv1.NotificationKind_NOTIFICATION_KIND_UNSPECIFIED}},
+		{UserId: "u-1", Kinds: []notificationsv1.NotificationKind{42}},
+	} {
+		_, err := h.UpdatePreferences(ctx, &notificationsv1.UpdatePreferencesRequest{Preferences: p})
+		wantCode(t, err, codes.InvalidArgument)
+	}
+	if len(repo.prefs) != 0 {
+		t.Fatalf("rejected updates stored %v", repo.prefs)
+	}
+	_, err := h.UpdatePreferences(ctx, &notificationsv1.UpdatePreferencesRequest{Preferences: &notificationsv1.Preferences{UserId: "u-1", WebhookUrl: "http://example.com/hook"}})
+	if msg := status.Convert(err).Message(); msg != "invalid webhook_url: want an absolute https url" {
+		t.Fatalf("plain http webhook error = %q", msg)
+	}
+
+	resp, err := h.UpdatePreferences(ctx, &notificationsv1.UpdatePreferencesRequest{Preferences: &notificationsv1.Preferences{
+		UserId:         "u-1",
+		Email:          " a@example.com ",
+		TelegramChatId: "-100123",
+		WebhookUrl:     "https://example.com/hook",
+		Kinds: []notificationsv1.NotificationKind{
+			notificationsv1.NotificationKind_NOTIFICATION_KIND_PAYMENT_FAILED,
+			notificationsv1.NotificationKind_NOTIFICATION_KIND_ORDER_STATUS_CHANGED,
+			notificationsv1.NotificationKind_NOTIFICATION_KIND_PAYMENT_FAILED,
+		},
+	}})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	stored := repo.prefs["u-1"]
+	if stored.Email != "a@example.com" || stored.TelegramChatID != "-100123" || stored.WebhookUrl != "https://example.com/hook" {
+		t.Fatalf("stored = %+v", stored)
+	}
+	if !slices.Equal(stored.Kinds, []string{"PAYMENT_FAILED", "ORDER_STATUS_CHANGED"}) {
+		t.Fatalf("stored kinds = %v, want deduplicated names", stored.Kinds)
+	}
+	if got := resp.GetPreferences().GetKinds(); len(got) != 2 || got[1] != notificationsv1.NotificationKind_NOTIFICATION_KIND_ORDER_STATUS_CHANGED {
+		t.Fatalf("response kinds = %v", got)
+	}
+
+	got, err := h.GetPreferences(ctx, &notificationsv1.GetPreferencesRequest{UserId: "u-1"})
+	if err != nil || got.GetPreferences().GetEmail() != "a@example.com" || got.GetPreferences().GetUpdatedAt() == nil {
+		t.Fatalf("GetPreferences = (%v, %v)", got, err)
+	}
+}
+
+func TestUpdatePreferencesOfAnotherUser(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo)
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "u-1", Roles: []jwt.Role{jwt.RoleUser}})
+	req := &notificationsv1.UpdatePreferencesRequest{Preferences: &notificationsv1.Preferences{UserId: "u-2", WebhookUrl: "https://attacker.example/hook"}}
+
+	_, err := h.UpdatePreferences(ctx, req)
+	wantCode(t, err, codes.PermissionDenied)
+	if len(repo.prefs) != 0 {
+		t.Fatalf("cross-user update stored %v", repo.prefs)
+	}
+
+	admin := userauth.NewContext(context.Background(), jwt.Claims{Subject: "a-1", Roles: []jwt.Role{jwt.RoleAdmin}})
+	if _, err := h.UpdatePreferences(admin, req); err != nil {
+		t.Fatalf("admin UpdatePreferences: %v", err)
+	}
+}
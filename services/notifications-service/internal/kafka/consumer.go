@@ -0,0 +1,200 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/notify"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+)
+
+// Decoder renders the value of a consumed message; ok false skips it.
+type Decoder func(value []byte) (n notify.Notification, ok bool, err error)
+
+// PaymentResults decodes the payments.payment_result topic.
+func PaymentResults(currency money.Currency) Decoder {
+	return func(value []byte) (notify.Notification, bool, error) {
+		var ev eventsv1.PaymentResult
+		if err := events.Unmarshal(value, &ev); err != nil {
+			return notify.Notification{}, false, err
+		}
+		return notify.FromPaymentResult(&ev, currency)
+	}
+}
+
+// OrderStatusChanges decodes the orders.order_status_changed topic.
+func OrderStatusChanges(currency money.Currency) Decoder {
+	return func(value []byte) (notify.Notification, bool, error) {
+		var ev eventsv1.OrderStatusChanged
+		if err := events.Unmarshal(value, &ev); err != nil {
+			return notify.Notification{}, false, err
+		}
+		n, err := notify.FromOrderStatusChanged(&ev, currency)
+		return n, err == nil, err
+	}
+}
+
+// PaymentChallenges decodes the payments.payment_challenge_required topic.
+func PaymentChallenges(currency money.Currency) Decoder {
+	return func(value []byte) (notify.Notification, bool, error) {
+		var ev eventsv1.PaymentChallengeRequired
+		if err := events.Unmarshal(value, &ev); err != nil {
+			return notify.Notification{}, false, err
+		}
+		n, err := notify.FromPaymentChallengeRequired(&ev, currency)
+		return n, err == nil, err
+	}
+}
+
+// Consumer queues a delivery per enabled channel for every event of one
+// topic. Deliveries are unique per event and channel, so redelivered
+// messages need no inbox.
+type Consumer struct {
+	repo     repo.Repository
+	reader   *kafka.Reader
+	decode   Decoder
+	channels []string
+
+	handlerTimeout time.Duration
+}
+
+// NewConsumer builds the consumer. channels are the channels that have a
+// provider configured; addresses for the others are ignored.
+func NewConsumer(r repo.Repository, reader *kafka.Reader, decode Decoder, channels []string, handlerTimeout time.Duration) *Consumer {
+	slog.Default().With("service", "notifications-service", "component", "kafka").Info("notification consumer initialized", "topic", reader.Config().Topic, "channels", channels)
+	return &Consumer{repo: r, reader: reader, decode: decode, channels: channels, handlerTimeout: handlerTimeout}
+}
+
+func (c *Consumer) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "notifications-service", "component", "kafka", "topic", c.reader.Config().Topic)
+	logger.Info("notification consumer run start")
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("notification consumer context done")
+				return nil
+			}
+			logger.Error("notification fetch failed", "err", err)
+			return err
+		}
+
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.handleMessage(hctx, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				logger.Error("notification version not supported, stopping", "err", err, "offset", m.Offset)
+				return err
+			}
+			logger.Error("notification handle error", "err", err, "offset", m.Offset)
+			// offset НЕ коммитим => Kafka доставит снова
+			continue
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			logger.Error("notification commit failed", "err", err, "offset", m.Offset)
+			return err
+		}
+	}
+}
+
+func (c *Consumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	logger := slog.Default().With("service", "notifications-service", "component", "kafka")
+	n, ok, err := c.decode(m.Value)
+	if err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
+		// плохое сообщение лучше “проглотить” и закоммитить, иначе будет бесконечный цикл
+		logger.Error("notification unmarshal failed", "err", err, "topic", m.Topic, "offset", m.Offset)
+		return nil
+	}
+	if !ok {
+		return nil
+	}
+	ctx = logging.WithUserID(ctx, n.UserID)
+	queued, err := Enqueue(ctx, c.repo, n, c.channels)
+	if err != nil {
+		return err
+	}
+	logger.InfoContext(ctx, "notification queued", "event_id", n.EventID, "kind", n.Kind, "deliveries", queued)
+	return nil
+}
+
+// Enqueue stores a delivery of n for each of channels the user has an
+// address for, unless the user opted out of n's kind. It returns how many
+// deliveries are new.
+func Enqueue(ctx context.Context, r repo.Repository, n notify.Notification, channels []string) (int, error) {
+	logger := slog.Default().With("service", "notifications-service", "component", "kafka")
+	eventID, err := uuid.Parse(n.EventID)
+	if err != nil || n.UserID == "" {
+		logger.ErrorContext(ctx, "notification without event or user id", "event_id", n.EventID)
+		return 0, nil
+	}
+	queued := 0
+	err = r.InTx(ctx, func(q db.Querier) error {
+		prefs, err := q.GetPreferences(ctx, n.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil
+			}
+			return err
+		}
+		if !notify.Wanted(prefs.Kinds, n.Kind) {
+			return nil
+		}
+		for _, ch := range channels {
+			recipient := Recipient(prefs, ch)
+			if recipient == "" {
+				continue
+			}
+			inserted, err := q.InsertDelivery(ctx, db.InsertDeliveryParams{
+				EventID:   pgtype.UUID{Bytes: eventID, Valid: true},
+				UserID:    n.UserID,
+				Kind:      string(n.Kind),
+				Channel:   ch,
+				Recipient: recipient,
+				Subject:   n.Subject,
+				Body:      n.Body,
+				Payload:   n.Payload,
+			})
+			if err != nil {
+				return err
+			}
+			queued += int(inserted)
+		}
+		return nil
+	})
+	return queued, err
+}
+
+// Recipient is the user's address on ch; empty when the channel is off.
+func Recipient(p db.NotificationPreference, ch string) string {
+	switch ch {
+	case channel.Email:
+		return p.Email
+	case channel.Telegram:
+		return p.TelegramChatID
+	case channel.Webhook:
+		return p.WebhookUrl
+	}
+	return ""
+}
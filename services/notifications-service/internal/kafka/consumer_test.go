@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/channel"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/notify"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+)
+
+// fakeRepo keeps preferences and deliveries in memory; InsertDelivery
+// ignores a repeated (event_id, channel) like the unique index does.
+type fakeRepo struct {
+	db.Querier
+	prefs      map[string]db.NotificationPreference
+	deliveries []db.InsertDeliveryParams
+}
+
+func (f *fakeRepo) Q() db.Querier { return f }
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) GetPreferences(_ context.Context, userID string) (db.NotificationPreference, error) {
+	p, ok := f.prefs[userID]
+	if !ok {
+		return db.NotificationPreference{}, pgx.ErrNoRows
+	}
+	return p, nil
+}
+
+func (f *fakeRepo) InsertDelivery(_ context.Context, arg db.InsertDeliveryParams) (int64, error) {
+	for _, d := range f.deliveries {
+		if d.EventID == arg.EventID && d.Channel == arg.Channel {
+			return 0, nil
+		}
+	}
+	f.deliveries = append(f.deliveries, arg)
+	return 1, nil
+}
+
+func TestEnqueue(t *testing.T) {
+	repo := &fakeRepo{prefs: map[string]db.NotificationPreference{
+		"u-1": {UserID: "u-1", Email: "a@example.com", WebhookUrl: "https://example.com/hook"},
+		"u-2": {UserID: "u-2", Email: "b@example.com", Kinds: []string{string(notify.KindPaymentFailed)}},
+	}}
+	ctx := context.Background()
+	all := []string{channel.Email, channel.Telegram, channel.Webhook}
+	n := notify.Notification{EventID: uuid.NewString(), UserID: "u-1", Kind: notify.KindPaymentSucceeded, Subject: "s", Body: "b", Payload: []byte(`{}`)}
+
+	// No Telegram chat id: email and webhook only.
+	queued, err := Enqueue(ctx, repo, n, all)
+	if err != nil || queued != 2 {
+		t.Fatalf("Enqueue = (%d, %v), want 2 deliveries", queued, err)
+	}
+	if repo.deliveries[0].Recipient != "a@example.com" || repo.deliveries[1].Channel != channel.Webhook {
+		t.Fatalf("deliveries = %+v", repo.deliveries)
+	}
+
+	// The same event again is a duplicate.
+	if queued, err := Enqueue(ctx, repo, n, all); err != nil || queued != 0 {
+		t.Fatalf("Enqueue(duplicate) = (%d, %v), want 0", queued, err)
+	}
+
+	// Only the configured channels are used.
+	n.EventID = uuid.NewString()
+	if queued, err := Enqueue(ctx, repo, n, []string{channel.Email}); err != nil || queued != 1 {
+		t.Fatalf("Enqueue(email only) = (%d, %v), want 1", queued, err)
+	}
+
+	// u-2 only wants failed payments; u-3 has no preferences.
+	for _, u := range []string{"u-2", "u-3"} {
+		n.EventID, n.UserID = uuid.NewString(), u
+		if queued, err := Enqueue(ctx, repo, n, all); err != nil || queued != 0 {
+			t.Fatalf("Enqueue(%s) = (%d, %v), want 0", u, queued, err)
+		}
+	}
+	n.EventID, n.UserID, n.Kind = uuid.NewString(), "u-2", notify.KindPaymentFailed
+	if queued, err := Enqueue(ctx, repo, n, all); err != nil || queued != 1 {
+		t.Fatalf("Enqueue(u-2, failed) = (%d, %v), want 1", queued, err)
+	}
+
+	// A notification without a valid event id is dropped.
+	n.EventID = "nope"
+	if queued, err := Enqueue(ctx, repo, n, all); err != nil || queued != 0 {
+		t.Fatalf("Enqueue(bad event id) = (%d, %v), want 0", queued, err)
+	}
+}
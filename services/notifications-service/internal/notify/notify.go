@@ -0,0 +1,217 @@
+// Package notify turns payment and order events into the notifications
+// their users are sent.
+package notify
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/webhooksdk"
+)
+
+// Kind is what a notification is about; users choose the kinds they get.
+type Kind string
+
+const (
+	KindPaymentSucceeded         Kind = "PAYMENT_SUCCEEDED"
+	KindPaymentFailed            Kind = "PAYMENT_FAILED"
+	KindOrderStatusChanged       Kind = "ORDER_STATUS_CHANGED"
+	KindPaymentChallengeRequired Kind = "PAYMENT_CHALLENGE_REQUIRED"
+)
+
+// Kinds lists every kind, in the order of the API enum.
+var Kinds = []Kind{KindPaymentSucceeded, KindPaymentFailed, KindOrderStatusChanged, KindPaymentChallengeRequired}
+
+// Wanted reports whether a user subscribed to kinds gets k; no kinds means
+// all of them.
+func Wanted(kinds []string, k Kind) bool {
+	return len(kinds) == 0 || slices.Contains(kinds, string(k))
+}
+
+// Webhook event types, the "type" of a Webhook.
+const (
+	TypePaymentSucceeded         = webhooksdk.TypePaymentSucceeded
+	TypePaymentFailed            = webhooksdk.TypePaymentFailed
+	TypeOrderStatusChanged       = webhooksdk.TypeOrderStatusChanged
+	TypePaymentChallengeRequired = webhooksdk.TypePaymentChallengeRequired
+)
+
+// Webhook is the JSON body POSTed to a user's webhook. ID is the event id and
+// stays the same across redeliveries.
+type Webhook struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// The Data of webhooks is defined by pkg/webhooksdk, which receivers decode
+// it with.
+type (
+	PaymentData   = webhooksdk.PaymentData
+	OrderData     = webhooksdk.OrderData
+	ChallengeData = webhooksdk.ChallengeData
+)
+
+// Notification is what a user is told about one event, the same on every
+// channel: Subject and Body for people, Payload for webhooks.
+type Notification struct {
+	EventID string
+	UserID  string
+	Kind    Kind
+	Subject string
+	Body    string
+	Payload []byte
+}
+
+// FromPaymentResult renders a payment outcome. ok is false for results the
+// user is not told about: FAIL_INTERNAL is retried by orders-service, and if
+// the retries run out the order is cancelled, which is notified instead.
+func FromPaymentResult(ev *eventsv1.PaymentResult, currency money.Currency) (n Notification, ok bool, err error) {
+	data := PaymentData{
+		OrderID:   ev.GetOrderId(),
+		PaymentID: ev.GetPaymentId(),
+		Amount:    money.Amount(ev.GetAmount()),
+		Fee:       money.Amount(ev.GetFee()),
+		Currency:  currency,
+	}
+	amount := money.New(ev.GetAmount(), currency)
+	n = Notification{EventID: ev.GetEventId(), UserID: ev.GetUserId()}
+	typ := TypePaymentSucceeded
+	switch ev.GetStatus() {
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS:
+		n.Kind = KindPaymentSucceeded
+		n.Subject = "Payment succeeded"
+		n.Body = fmt.Sprintf("Payment of %s for order %s succeeded.", amount, ev.GetOrderId())
+		if ev.GetFee() > 0 {
+			n.Body += fmt.Sprintf(" Fee: %s.", money.New(ev.GetFee(), currency))
+		}
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_UNSPECIFIED,
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_INTERNAL:
+		return Notification{}, false, nil
+	default:
+		typ = TypePaymentFailed
+		data.Reason = failureReason(ev.GetStatus())
+		n.Kind = KindPaymentFailed
+		n.Subject = "Payment failed"
+		n.Body = fmt.Sprintf("Payment of %s for order %s failed: %s.", amount, ev.GetOrderId(), data.Reason)
+	}
+	n.Payload, err = json.Marshal(Webhook{ID: ev.GetEventId(), Type: typ, CreatedAt: ev.GetOccurredAt().AsTime(), Data: data})
+	return n, err == nil, err
+}
+
+// FromPaymentChallengeRequired renders a request to confirm a payment.
+func FromPaymentChallengeRequired(ev *eventsv1.PaymentChallengeRequired, currency money.Currency) (Notification, error) {
+	expiresAt := ev.GetExpiresAt().AsTime()
+	n := Notification{
+		EventID: ev.GetEventId(),
+		UserID:  ev.GetUserId(),
+		Kind:    KindPaymentChallengeRequired,
+		Subject: "Payment confirmation required",
+		Body: fmt.Sprintf("Confirm the payment of %s for order %s by %s, or the order will be cancelled.",
+			money.New(ev.GetAmount(), currency), ev.GetOrderId(), expiresAt.UTC().Format("2006-01-02 15:04 UTC")),
+	}
+	var err error
+	n.Payload, err = json.Marshal(Webhook{
+		ID:        ev.GetEventId(),
+		Type:      TypePaymentChallengeRequired,
+		CreatedAt: ev.GetOccurredAt().AsTime(),
+		Data: ChallengeData{
+			OrderID:       ev.GetOrderId(),
+			PaymentID:     ev.GetPaymentId(),
+			Amount:        money.Amount(ev.GetAmount()),
+			Currency:      currency,
+			PaymentMethod: ev.GetPaymentMethod(),
+			ExpiresAt:     expiresAt,
+		},
+	})
+	return n, err
+}
+
+// FromOrderStatusChanged renders a change of an order's status.
+func FromOrderStatusChanged(ev *eventsv1.OrderStatusChanged, currency money.Currency) (Notification, error) {
+	n := Notification{
+		EventID: ev.GetEventId(),
+		UserID:  ev.GetUserId(),
+		Kind:    KindOrderStatusChanged,
+		Subject: "Order " + statusText(ev.GetStatus()),
+	}
+	switch {
+	case ev.GetPreviousStatus() == "SCHEDULED" && ev.GetStatus() == "NEW":
+		n.Subject = "Scheduled payment started"
+		n.Body = fmt.Sprintf("The scheduled payment of %s for order %s has started.", money.New(ev.GetAmount(), currency), ev.GetOrderId())
+	case ev.GetPreviousStatus() == "CANCELLED" && ev.GetStatus() == "NEW":
+		n.Subject = "Payment retried"
+		n.Body = fmt.Sprintf("The payment of %s for order %s is being retried.", money.New(ev.GetAmount(), currency), ev.GetOrderId())
+	case ev.GetStatus() == "PARTIALLY_PAID":
+		n.Body = fmt.Sprintf("Order %s is partially paid: %s of %s.", ev.GetOrderId(), money.New(ev.GetPaidAmount(), currency), money.New(ev.GetAmount(), currency))
+	case ev.GetStatus() == "CANCELLED":
+		n.Body = fmt.Sprintf("Order %s was cancelled.", ev.GetOrderId())
+		if ev.GetReason() != "" {
+			n.Body = fmt.Sprintf("Order %s was cancelled: %s.", ev.GetOrderId(), ev.GetReason())
+		}
+	case ev.GetStatus() == "REFUNDED":
+		n.Body = fmt.Sprintf("The payment for order %s was refunded to your balance.", ev.GetOrderId())
+	default:
+		n.Body = fmt.Sprintf("Order %s is %s.", ev.GetOrderId(), statusText(ev.GetStatus()))
+	}
+	var err error
+	n.Payload, err = json.Marshal(Webhook{
+		ID:        ev.GetEventId(),
+		Type:      TypeOrderStatusChanged,
+		CreatedAt: ev.GetOccurredAt().AsTime(),
+		Data: OrderData{
+			OrderID:        ev.GetOrderId(),
+			PreviousStatus: ev.GetPreviousStatus(),
+			Status:         ev.GetStatus(),
+			Amount:         money.Amount(ev.GetAmount()),
+			PaidAmount:     money.Amount(ev.GetPaidAmount()),
+			Currency:       currency,
+			Reason:         ev.GetReason(),
+		},
+	})
+	return n, err
+}
+
+// failureReason is the user-facing reason of a failed payment. The reason
+// text of the event is meant for operators and is not passed on.
+func failureReason(s eventsv1.PaymentResultStatus) string {
+	switch s {
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT:
+		return "no account"
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS:
+		return "not enough funds"
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED,
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED:
+		return "declined"
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED:
+		return "limit exceeded"
+	case eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED:
+		return "not confirmed in time"
+	}
+	return "error"
+}
+
+func statusText(status string) string {
+	switch status {
+	case "SCHEDULED":
+		return "scheduled"
+	case "NEW":
+		return "new"
+	case "PARTIALLY_PAID":
+		return "partially paid"
+	case "FINISHED":
+		return "paid"
+	case "CANCELLED":
+		return "cancelled"
+	case "DISPUTED":
+		return "disputed"
+	case "REFUNDED":
+		return "refunded"
+	}
+	return status
+}
@@ -0,0 +1,125 @@
+package notify
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestFromPaymentResult(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ev := &eventsv1.PaymentResult{
+		EventId:    "e-1",
+		OccurredAt: timestamppb.New(at),
+		OrderId:    "o-1",
+		UserId:     "u-1",
+		Status:     eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS,
+		Amount:     150050,
+		Fee:        100,
+	}
+	n, ok, err := FromPaymentResult(ev, money.RUB)
+	if err != nil || !ok {
+		t.Fatalf("FromPaymentResult = (%v, %v)", ok, err)
+	}
+	if n.Kind != KindPaymentSucceeded || n.UserID != "u-1" || n.Body != "Payment of 1500.50 RUB for order o-1 succeeded. Fee: 1.00 RUB." {
+		t.Fatalf("notification = %+v", n)
+	}
+	want := `{"id":"e-1","type":"payment.succeeded","created_at":"2026-01-02T03:04:05Z","data":{"order_id":"o-1","amount":"150050","fee":"100","currency":"RUB"}}`
+	if string(n.Payload) != want {
+		t.Fatalf("payload = %s\nwant %s", n.Payload, want)
+	}
+
+	ev.Status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED
+	ev.Reason = "velocity rule 3"
+	n, ok, err = FromPaymentResult(ev, money.RUB)
+	if err != nil || !ok || n.Kind != KindPaymentFailed || n.Body != "Payment of 1500.50 RUB for order o-1 failed: declined." {
+		t.Fatalf("fraud declined = (%+v, %v, %v)", n, ok, err)
+	}
+	var hook struct {
+		Type string
+		Data PaymentData
+	}
+	if err := json.Unmarshal(n.Payload, &hook); err != nil || hook.Type != TypePaymentFailed || hook.Data.Reason != "declined" {
+		t.Fatalf("failed payload = %s (%v)", n.Payload, err)
+	}
+
+	ev.Status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_INTERNAL
+	if _, ok, err := FromPaymentResult(ev, money.RUB); ok || err != nil {
+		t.Fatalf("FAIL_INTERNAL = (%v, %v), want skipped", ok, err)
+	}
+}
+
+func TestFromOrderStatusChanged(t *testing.T) {
+	ev := &eventsv1.OrderStatusChanged{
+		EventId:        "e-2",
+		OccurredAt:     timestamppb.Now(),
+		OrderId:        "o-1",
+		UserId:         "u-1",
+		PreviousStatus: "NEW",
+		Status:         "PARTIALLY_PAID",
+		Amount:         30000,
+		PaidAmount:     10000,
+	}
+	n, err := FromOrderStatusChanged(ev, money.RUB)
+	if err != nil || n.Kind != KindOrderStatusChanged || n.Subject != "Order partially paid" || n.Body != "Order o-1 is partially paid: 100.00 RUB of 300.00 RUB." {
+		t.Fatalf("partially paid = (%+v, %v)", n, err)
+	}
+
+	ev.Status, ev.Reason = "CANCELLED", "not enough funds"
+	n, err = FromOrderStatusChanged(ev, money.RUB)
+	if err != nil || n.Body != "Order o-1 was cancelled: not enough funds." {
+		t.Fatalf("cancelled = (%+v, %v)", n, err)
+	}
+
+	ev.PreviousStatus, ev.Status, ev.Reason = "SCHEDULED", "NEW", ""
+	n, err = FromOrderStatusChanged(ev, money.RUB)
+	if err != nil || n.Subject != "Scheduled payment started" || n.Body != "The scheduled payment of 300.00 RUB for order o-1 has started." {
+		t.Fatalf("scheduled payment = (%+v, %v)", n, err)
+	}
+
+	ev.PreviousStatus, ev.Status = "CANCELLED", "NEW"
+	n, err = FromOrderStatusChanged(ev, money.RUB)
+	if err != nil || n.Subject != "Payment retried" || n.Body != "The payment of 300.00 RUB for order o-1 is being retried." {
+		t.Fatalf("payment retry = (%+v, %v)", n, err)
+	}
+}
+
+func TestFromPaymentChallengeRequired(t *testing.T) {
+	at := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ev := &eventsv1.PaymentChallengeRequired{
+		EventId:       "e-3",
+		OccurredAt:    timestamppb.New(at),
+		OrderId:       "o-1",
+		UserId:        "u-1",
+		Amount:        30000,
+		PaymentMethod: "card",
+		ExpiresAt:     timestamppb.New(at.Add(15 * time.Minute)),
+	}
+	n, err := FromPaymentChallengeRequired(ev, money.RUB)
+	if err != nil || n.Kind != KindPaymentChallengeRequired || n.Body != "Confirm the payment of 300.00 RUB for order o-1 by 2026-01-02 03:19 UTC, or the order will be cancelled." {
+		t.Fatalf("challenge = (%+v, %v)", n, err)
+	}
+	want := `{"id":"e-3","type":"payment.challenge_required","created_at":"2026-01-02T03:04:05Z","data":{"order_id":"o-1","amount":"30000","currency":"RUB","payment_method":"card","expires_at":"2026-01-02T03:19:05Z"}}`
+	if string(n.Payload) != want {
+		t.Fatalf("payload = %s\nwant %s", n.Payload, want)
+	}
+
+	expired := &eventsv1.PaymentResult{OrderId: "o-1", Amount: 30000, Status: eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED}
+	if n, ok, err := FromPaymentResult(expired, money.RUB); err != nil || !ok || n.Body != "Payment of 300.00 RUB for order o-1 failed: not confirmed in time." {
+		t.Fatalf("challenge expired = (%+v, %v, %v)", n, ok, err)
+	}
+}
+
+func TestWanted(t *testing.T) {
+	if !Wanted(nil, KindPaymentFailed) {
+		t.Fatal("no kinds should mean all kinds")
+	}
+	if Wanted([]string{"PAYMENT_SUCCEEDED"}, KindPaymentFailed) || !Wanted([]string{"PAYMENT_SUCCEEDED"}, KindPaymentSucceeded) {
+		t.Fatal("Wanted ignores the chosen kinds")
+	}
+}
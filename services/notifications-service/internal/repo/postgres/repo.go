@@ -0,0 +1,38 @@
+package postgres
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+)
+
+var _ repo.Repository = (*Repo)(nil)
+
+type Repo struct {
+	pool *pgxpool.Pool
+	q    *db.Queries
+	tx   *pgtx.Manager
+}
+
+// NewRepo returns a Repo on pool; tx configures the retries of its
+// transactions.
+func NewRepo(pool *pgxpool.Pool, tx pgtx.Config) *Repo {
+	logger := slog.Default().With("service", "notifications-service", "component", "repo")
+	return &Repo{pool: pool, q: db.New(pool), tx: pgtx.New(pool, tx, logger)}
+}
+
+func (r *Repo) Q() db.Querier {
+	return r.q
+}
+
+func (r *Repo) InTx(ctx context.Context, fn func(q db.Querier) error) error {
+	return r.tx.Do(ctx, func(_ context.Context, tx pgx.Tx) error {
+		return fn(db.New(tx))
+	})
+}
@@ -0,0 +1,14 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/notifications-service/internal/repo/postgres/db"
+)
+
+// Repository is the storage the handlers, the consumer and the dispatcher
+// depend on. The Postgres implementation is postgres.Repo.
+type Repository interface {
+	Q() db.Querier
+	InTx(ctx context.Context, fn func(q db.Querier) error) error
+}
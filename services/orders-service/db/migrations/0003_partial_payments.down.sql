@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_payments;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check CHECK (status IN ('NEW', 'FINISHED', 'CANCELLED'));
+ALTER TABLE orders DROP COLUMN IF EXISTS paid_amount;
@@ -0,0 +1,22 @@
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS paid_amount bigint NOT NULL DEFAULT 0 CHECK (paid_amount >= 0);
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders
+    ADD CONSTRAINT orders_status_check CHECK (status IN ('NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED'));
+
+CREATE TABLE IF NOT EXISTS order_payments (
+                                              payment_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id uuid NOT NULL REFERENCES orders (order_id),
+    amount bigint NOT NULL CHECK (amount > 0),
+    status text NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SUCCEEDED', 'FAILED')),
+    failure_reason text NULL,
+    idempotency_key text NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS order_payments_order_idx
+    ON order_payments (order_id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS order_payments_order_idem_idx
+    ON order_payments (order_id, idempotency_key);
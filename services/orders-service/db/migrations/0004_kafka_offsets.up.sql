@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS kafka_offsets (
+    topic text NOT NULL,
+    partition int NOT NULL,
+    last_offset bigint NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    PRIMARY KEY (topic, partition)
+    );
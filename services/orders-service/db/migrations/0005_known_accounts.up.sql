@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS known_accounts (
+    user_id text PRIMARY KEY,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
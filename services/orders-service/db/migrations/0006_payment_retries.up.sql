@@ -0,0 +1,17 @@
+-- PaymentRequested re-publishes scheduled after FAIL_INTERNAL results.
+-- retry_key is the installment payment_id, or the order_id for up-front payments.
+CREATE TABLE IF NOT EXISTS payment_retries (
+    retry_key text PRIMARY KEY,
+    order_id uuid NOT NULL,
+    payment_id uuid,
+    user_id text NOT NULL,
+    amount bigint NOT NULL,
+    attempts int NOT NULL,
+    next_attempt_at timestamptz,
+    last_reason text,
+    updated_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS payment_retries_due_idx
+    ON payment_retries (next_attempt_at)
+    WHERE next_attempt_at IS NOT NULL;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_tags_idx;
+ALTER TABLE orders DROP COLUMN IF EXISTS tags;
+ALTER TABLE orders DROP COLUMN IF EXISTS metadata;
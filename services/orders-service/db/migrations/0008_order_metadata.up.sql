@@ -0,0 +1,8 @@
+-- Integrator-supplied metadata (string -> string) and tags; size limits are
+-- enforced by the service.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS metadata jsonb NOT NULL DEFAULT '{}'::jsonb,
+    ADD COLUMN IF NOT EXISTS tags text[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS orders_tags_idx
+    ON orders USING gin (tags);
@@ -0,0 +1,15 @@
+-- Shared pkg/idempotency table: one row per (operation, user, key) with the
+-- request hash and the response replayed on retries. Replaces
+-- orders.idempotency_key and order_payments.idempotency_key, which are no
+-- longer written.
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope text NOT NULL,
+    user_id text NOT NULL,
+    idempotency_key text NOT NULL,
+    request_hash bytea NOT NULL,
+    status text NOT NULL CHECK (status IN ('IN_PROGRESS', 'COMPLETED')),
+    response bytea NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    completed_at timestamptz NULL,
+    PRIMARY KEY (scope, user_id, idempotency_key)
+    );
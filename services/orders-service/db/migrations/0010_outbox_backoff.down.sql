@@ -0,0 +1,9 @@
+DROP INDEX IF EXISTS outbox_dead_idx;
+DROP INDEX IF EXISTS outbox_unsent_key_idx;
+
+UPDATE outbox SET status = 'FAILED' WHERE status = 'DEAD';
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_status_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_status_check
+    CHECK (status IN ('PENDING', 'SENT', 'FAILED'));
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS next_retry_at;
@@ -0,0 +1,15 @@
+-- Per-row backoff for failed publishes and a dead-letter state for rows that
+-- keep failing.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS next_retry_at timestamptz NULL;
+
+ALTER TABLE outbox DROP CONSTRAINT IF EXISTS outbox_status_check;
+ALTER TABLE outbox ADD CONSTRAINT outbox_status_check
+    CHECK (status IN ('PENDING', 'SENT', 'FAILED', 'DEAD'));
+
+CREATE INDEX IF NOT EXISTS outbox_unsent_key_idx
+    ON outbox (kafka_key, id)
+    WHERE sent_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS outbox_dead_idx
+    ON outbox (id)
+    WHERE status = 'DEAD';
@@ -0,0 +1,7 @@
+-- version grows with every change of the row; UpdateOrder uses it for
+-- optimistic concurrency.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1,
+    ADD COLUMN IF NOT EXISTS updated_at timestamptz NOT NULL DEFAULT now();
+
+UPDATE orders SET updated_at = created_at;
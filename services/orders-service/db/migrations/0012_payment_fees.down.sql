@@ -0,0 +1,2 @@
+ALTER TABLE order_payments DROP COLUMN IF EXISTS fee;
+ALTER TABLE orders DROP COLUMN IF EXISTS fee_amount;
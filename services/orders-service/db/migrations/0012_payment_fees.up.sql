@@ -0,0 +1,7 @@
+-- Fees charged by payments-service on top of each successful payment;
+-- orders.fee_amount is their sum over the order.
+ALTER TABLE orders
+    ADD COLUMN IF NOT EXISTS fee_amount bigint NOT NULL DEFAULT 0 CHECK (fee_amount >= 0);
+
+ALTER TABLE order_payments
+    ADD COLUMN IF NOT EXISTS fee bigint NOT NULL DEFAULT 0 CHECK (fee >= 0);
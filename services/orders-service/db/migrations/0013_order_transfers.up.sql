@@ -0,0 +1,15 @@
+-- Offers to move an order to another user. The order changes owner only
+-- when the recipient accepts; at most one offer per order is pending.
+CREATE TABLE IF NOT EXISTS order_transfers (
+    transfer_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    order_id uuid NOT NULL REFERENCES orders (order_id),
+    from_user_id text NOT NULL,
+    to_user_id text NOT NULL CHECK (to_user_id <> from_user_id),
+    status text NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'ACCEPTED', 'CANCELLED')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz NULL
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS order_transfers_pending_idx
+    ON order_transfers (order_id)
+    WHERE status = 'PENDING';
@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS order_status_changed_notify ON orders;
+DROP FUNCTION IF EXISTS notify_order_status_changed();
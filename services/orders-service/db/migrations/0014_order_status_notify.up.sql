@@ -0,0 +1,15 @@
+-- Announces every order status change to the status bus that WaitOrder
+-- long polls on; the payload is the order id.
+CREATE OR REPLACE FUNCTION notify_order_status_changed() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('order_status_changed', NEW.order_id::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS order_status_changed_notify ON orders;
+CREATE TRIGGER order_status_changed_notify
+    AFTER UPDATE OF status ON orders
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_order_status_changed();
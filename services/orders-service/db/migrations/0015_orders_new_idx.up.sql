@@ -0,0 +1,4 @@
+-- Serves the per-user quota of NEW orders checked by CreateOrder.
+CREATE INDEX IF NOT EXISTS orders_user_new_idx
+    ON orders (user_id)
+    WHERE status = 'NEW';
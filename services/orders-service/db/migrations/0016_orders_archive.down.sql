@@ -0,0 +1,12 @@
+INSERT INTO orders (order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at)
+SELECT order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at
+FROM orders_archive
+ON CONFLICT (order_id) DO NOTHING;
+
+ALTER TABLE order_payments
+    ADD CONSTRAINT order_payments_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (order_id);
+ALTER TABLE order_transfers
+    ADD CONSTRAINT order_transfers_order_id_fkey FOREIGN KEY (order_id) REFERENCES orders (order_id);
+
+DROP INDEX IF EXISTS orders_done_updated_idx;
+DROP TABLE IF EXISTS orders_archive;
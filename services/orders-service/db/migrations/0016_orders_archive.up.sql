@@ -0,0 +1,36 @@
+-- Cold storage for finished and cancelled orders, filled by the archiver
+-- (internal/archive). Same columns as orders plus archived_at; columns added
+-- to orders must be added here and to the ArchiveOrders query too.
+CREATE TABLE IF NOT EXISTS orders_archive (
+    order_id uuid PRIMARY KEY,
+    user_id text NOT NULL,
+    amount bigint NOT NULL,
+    description text NOT NULL,
+    idempotency_key text NULL,
+    status text NOT NULL CHECK (status IN ('FINISHED', 'CANCELLED')),
+    created_at timestamptz NOT NULL,
+    payment_failure_reason text NULL,
+    paid_amount bigint NOT NULL,
+    fee_amount bigint NOT NULL,
+    metadata jsonb NOT NULL,
+    tags text[] NOT NULL,
+    version bigint NOT NULL,
+    updated_at timestamptz NOT NULL,
+    archived_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS orders_archive_user_created_idx
+    ON orders_archive (user_id, created_at DESC, order_id DESC);
+
+CREATE INDEX IF NOT EXISTS orders_archive_tags_idx
+    ON orders_archive USING gin (tags);
+
+-- The archiver picks terminal orders by the time of their last change.
+CREATE INDEX IF NOT EXISTS orders_done_updated_idx
+    ON orders (updated_at)
+    WHERE status IN ('FINISHED', 'CANCELLED');
+
+-- Payments and transfers of an archived order stay where they are, so they
+-- can no longer reference orders.
+ALTER TABLE order_payments DROP CONSTRAINT IF EXISTS order_payments_order_id_fkey;
+ALTER TABLE order_transfers DROP CONSTRAINT IF EXISTS order_transfers_order_id_fkey;
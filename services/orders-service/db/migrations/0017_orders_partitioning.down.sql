@@ -0,0 +1,52 @@
+ALTER TABLE orders RENAME TO orders_partitioned;
+ALTER TABLE orders_partitioned DROP CONSTRAINT orders_pkey;
+DROP INDEX IF EXISTS orders_user_created_idx;
+DROP INDEX IF EXISTS orders_tags_idx;
+DROP INDEX IF EXISTS orders_user_new_idx;
+DROP INDEX IF EXISTS orders_done_updated_idx;
+
+CREATE TABLE orders (
+    order_id uuid PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id text NOT NULL,
+    amount bigint NOT NULL CHECK (amount > 0),
+    description text NOT NULL,
+    idempotency_key text NULL,
+    status text NOT NULL CONSTRAINT orders_status_check CHECK (status IN ('NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    payment_failure_reason text NULL,
+    paid_amount bigint NOT NULL DEFAULT 0 CHECK (paid_amount >= 0),
+    metadata jsonb NOT NULL DEFAULT '{}'::jsonb,
+    tags text[] NOT NULL DEFAULT '{}',
+    version bigint NOT NULL DEFAULT 1,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    fee_amount bigint NOT NULL DEFAULT 0 CHECK (fee_amount >= 0)
+    );
+
+INSERT INTO orders (order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at)
+SELECT order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at
+FROM orders_partitioned;
+
+DROP TABLE orders_partitioned;
+
+CREATE INDEX IF NOT EXISTS orders_user_created_idx
+    ON orders (user_id, created_at DESC, order_id DESC);
+
+CREATE UNIQUE INDEX IF NOT EXISTS orders_user_idem_idx
+    ON orders (user_id, idempotency_key);
+
+CREATE INDEX IF NOT EXISTS orders_tags_idx
+    ON orders USING gin (tags);
+
+CREATE INDEX IF NOT EXISTS orders_user_new_idx
+    ON orders (user_id)
+    WHERE status = 'NEW';
+
+CREATE INDEX IF NOT EXISTS orders_done_updated_idx
+    ON orders (updated_at)
+    WHERE status IN ('FINISHED', 'CANCELLED');
+
+CREATE TRIGGER order_status_changed_notify
+    AFTER UPDATE OF status ON orders
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_order_status_changed();
@@ -0,0 +1,68 @@
+-- orders becomes range-partitioned by month on created_at. Partitions are
+-- named orders_pYYYYMM; the partition maintainer (pkg/partition) creates the
+-- coming months ahead of time.
+-- The primary key must contain the partition key, so order_id is only unique
+-- together with created_at; ids come from gen_random_uuid. The unused
+-- orders_user_idem_idx (see 0009) is not carried over for the same reason.
+ALTER TABLE orders RENAME TO orders_unpartitioned;
+
+CREATE TABLE orders (
+    order_id uuid NOT NULL DEFAULT gen_random_uuid(),
+    user_id text NOT NULL,
+    amount bigint NOT NULL CHECK (amount > 0),
+    description text NOT NULL,
+    idempotency_key text NULL,
+    status text NOT NULL CONSTRAINT orders_status_check CHECK (status IN ('NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    payment_failure_reason text NULL,
+    paid_amount bigint NOT NULL DEFAULT 0 CHECK (paid_amount >= 0),
+    metadata jsonb NOT NULL DEFAULT '{}'::jsonb,
+    tags text[] NOT NULL DEFAULT '{}',
+    version bigint NOT NULL DEFAULT 1,
+    updated_at timestamptz NOT NULL DEFAULT now(),
+    fee_amount bigint NOT NULL DEFAULT 0 CHECK (fee_amount >= 0)
+    ) PARTITION BY RANGE (created_at);
+
+-- Every month with orders and the next two get a partition of their own.
+-- orders_default catches anything outside of them.
+DO $$
+DECLARE
+    m timestamp := date_trunc('month', COALESCE((SELECT min(created_at) FROM orders_unpartitioned), now()) AT TIME ZONE 'UTC');
+    until_month timestamp := date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months';
+BEGIN
+    WHILE m <= until_month LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF orders FOR VALUES FROM (%L) TO (%L)',
+                       'orders_p' || to_char(m, 'YYYYMM'), m AT TIME ZONE 'UTC', (m + interval '1 month') AT TIME ZONE 'UTC');
+        m := m + interval '1 month';
+    END LOOP;
+    EXECUTE 'CREATE TABLE IF NOT EXISTS orders_default PARTITION OF orders DEFAULT';
+END $$;
+
+INSERT INTO orders (order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at)
+SELECT order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at
+FROM orders_unpartitioned;
+
+-- Drops the old indexes and the status trigger along with the table.
+DROP TABLE orders_unpartitioned;
+
+ALTER TABLE orders ADD CONSTRAINT orders_pkey PRIMARY KEY (order_id, created_at);
+
+CREATE INDEX IF NOT EXISTS orders_user_created_idx
+    ON orders (user_id, created_at DESC, order_id DESC);
+
+CREATE INDEX IF NOT EXISTS orders_tags_idx
+    ON orders USING gin (tags);
+
+CREATE INDEX IF NOT EXISTS orders_user_new_idx
+    ON orders (user_id)
+    WHERE status = 'NEW';
+
+CREATE INDEX IF NOT EXISTS orders_done_updated_idx
+    ON orders (updated_at)
+    WHERE status IN ('FINISHED', 'CANCELLED');
+
+CREATE TRIGGER order_status_changed_notify
+    AFTER UPDATE OF status ON orders
+    FOR EACH ROW
+    WHEN (OLD.status IS DISTINCT FROM NEW.status)
+    EXECUTE FUNCTION notify_order_status_changed();
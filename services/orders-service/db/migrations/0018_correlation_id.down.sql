@@ -0,0 +1,3 @@
+ALTER TABLE payment_retries DROP COLUMN IF EXISTS correlation_id;
+ALTER TABLE inbox DROP COLUMN IF EXISTS correlation_id;
+ALTER TABLE outbox DROP COLUMN IF EXISTS correlation_id;
@@ -0,0 +1,6 @@
+-- Request id of the API call behind an event, so support can follow one
+-- request through the async flow. Empty when no request started it.
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS correlation_id text NOT NULL DEFAULT '';
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS correlation_id text NOT NULL DEFAULT '';
+-- A retry keeps the correlation id of the payment it repeats.
+ALTER TABLE payment_retries ADD COLUMN IF NOT EXISTS correlation_id text NOT NULL DEFAULT '';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS payment_retries_order_idx;
+DROP INDEX IF EXISTS outbox_key_idx;
+DROP TABLE IF EXISTS admin_audit_log;
@@ -0,0 +1,22 @@
+-- Changes made by operators through the admin RPCs that bypass the normal
+-- flow, e.g. ForceOrderStatus. target is what was changed (an order id).
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id bigserial PRIMARY KEY,
+    operator text NOT NULL,
+    action text NOT NULL,
+    target text NOT NULL,
+    reason text NOT NULL,
+    details jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_idx
+    ON admin_audit_log (target, id);
+
+-- InspectOrder reads all events of an order, sent ones included, and its
+-- scheduled retries.
+CREATE INDEX IF NOT EXISTS outbox_key_idx
+    ON outbox (kafka_key, id);
+
+CREATE INDEX IF NOT EXISTS payment_retries_order_idx
+    ON payment_retries (order_id);
@@ -0,0 +1,15 @@
+DROP INDEX IF EXISTS orders_scheduled_pay_at_idx;
+
+-- Scheduled orders have not been paid yet; without the scheduler they
+-- would never be.
+UPDATE orders
+SET status = 'CANCELLED', payment_failure_reason = 'scheduled payments removed', version = version + 1, updated_at = now()
+WHERE status = 'SCHEDULED';
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_scheduled_pay_at_check;
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED'));
+
+ALTER TABLE orders_archive DROP COLUMN IF EXISTS pay_at;
+ALTER TABLE orders DROP COLUMN IF EXISTS pay_at;
@@ -0,0 +1,15 @@
+-- Orders created with pay_at wait in SCHEDULED until the scheduler requests
+-- their payment and moves them to NEW. pay_at stays set afterwards.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pay_at timestamptz NULL;
+ALTER TABLE orders_archive ADD COLUMN IF NOT EXISTS pay_at timestamptz NULL;
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('SCHEDULED', 'NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED'));
+ALTER TABLE orders ADD CONSTRAINT orders_scheduled_pay_at_check
+    CHECK (status <> 'SCHEDULED' OR pay_at IS NOT NULL);
+
+-- The scheduler picks due orders by pay_at.
+CREATE INDEX IF NOT EXISTS orders_scheduled_pay_at_idx
+    ON orders (pay_at)
+    WHERE status = 'SCHEDULED';
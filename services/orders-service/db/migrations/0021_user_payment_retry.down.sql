@@ -0,0 +1,4 @@
+ALTER TABLE orders_archive DROP COLUMN IF EXISTS payment_retry_count;
+ALTER TABLE orders_archive DROP COLUMN IF EXISTS payment_failure_code;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_retry_count;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_failure_code;
@@ -0,0 +1,8 @@
+-- RetryPayment may request the payment of an order cancelled for
+-- insufficient funds again. payment_failure_code records why the payment
+-- failed (the PaymentResult status without its prefix, e.g.
+-- NOT_ENOUGH_FUNDS), payment_retry_count how often the user retried it.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_failure_code text NULL;
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_retry_count int NOT NULL DEFAULT 0;
+ALTER TABLE orders_archive ADD COLUMN IF NOT EXISTS payment_failure_code text NULL;
+ALTER TABLE orders_archive ADD COLUMN IF NOT EXISTS payment_retry_count int NOT NULL DEFAULT 0;
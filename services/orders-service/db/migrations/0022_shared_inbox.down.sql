@@ -0,0 +1,10 @@
+-- Unprocessed claims never committed any effects.
+DELETE FROM inbox WHERE processed_at IS NULL;
+
+ALTER TABLE inbox DROP CONSTRAINT IF EXISTS inbox_pkey;
+ALTER TABLE inbox ADD CONSTRAINT inbox_pkey PRIMARY KEY (message_id);
+
+ALTER TABLE inbox ALTER COLUMN processed_at SET DEFAULT now();
+ALTER TABLE inbox ALTER COLUMN processed_at SET NOT NULL;
+ALTER TABLE inbox DROP COLUMN IF EXISTS received_at;
+ALTER TABLE inbox DROP COLUMN IF EXISTS consumer;
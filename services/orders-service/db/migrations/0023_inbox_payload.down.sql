@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS inbox_payload_received_at_idx;
+
+ALTER TABLE inbox DROP COLUMN IF EXISTS headers;
+ALTER TABLE inbox DROP COLUMN IF EXISTS payload;
+ALTER TABLE inbox DROP COLUMN IF EXISTS message_key;
+ALTER TABLE inbox DROP COLUMN IF EXISTS kafka_offset;
+ALTER TABLE inbox DROP COLUMN IF EXISTS kafka_partition;
+ALTER TABLE inbox DROP COLUMN IF EXISTS topic;
@@ -0,0 +1,11 @@
+-- Where each message was read, and optionally the record itself so an
+-- operator can run it through its consumer again. Payloads are purged after
+-- a retention period; the rows stay for deduplication.
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS topic text NULL;
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS kafka_partition int NULL;
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS kafka_offset bigint NULL;
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS message_key bytea NULL;
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS payload bytea NULL;
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS headers jsonb NULL;
+
+CREATE INDEX IF NOT EXISTS inbox_payload_received_at_idx ON inbox (received_at) WHERE payload IS NOT NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS receipts;
+DROP SEQUENCE IF EXISTS receipt_number_seq;
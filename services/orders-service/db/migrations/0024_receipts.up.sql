@@ -0,0 +1,18 @@
+-- Receipts of finished orders, one per order. Numbers come from
+-- receipt_number_seq: unique and increasing, but not gapless.
+-- order_id has no foreign key: orders are partitioned and archived, the
+-- receipt stays.
+CREATE SEQUENCE IF NOT EXISTS receipt_number_seq;
+
+CREATE TABLE IF NOT EXISTS receipts (
+    order_id uuid PRIMARY KEY,
+    number text NOT NULL UNIQUE,
+    user_id text NOT NULL,
+    items jsonb NOT NULL,
+    amount bigint NOT NULL,
+    fee_amount bigint NOT NULL,
+    total_amount bigint NOT NULL,
+    order_created_at timestamptz NOT NULL,
+    paid_at timestamptz NOT NULL,
+    issued_at timestamptz NOT NULL DEFAULT now()
+    );
@@ -0,0 +1,35 @@
+-- Promo codes taken off the amount of an order on CreateOrder. A code gives
+-- either percent_off or amount_off and never makes an order free; NULL
+-- limits and bounds mean none.
+-- redeemed_count counts the redemptions not released by a cancellation.
+CREATE TABLE IF NOT EXISTS promo_codes (
+    code text PRIMARY KEY,
+    percent_off int NULL CHECK (percent_off BETWEEN 1 AND 99),
+    amount_off bigint NULL CHECK (amount_off > 0),
+    max_redemptions int NULL CHECK (max_redemptions > 0),
+    max_redemptions_per_user int NULL CHECK (max_redemptions_per_user > 0),
+    redeemed_count int NOT NULL DEFAULT 0 CHECK (redeemed_count >= 0),
+    starts_at timestamptz NULL,
+    expires_at timestamptz NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CHECK ((percent_off IS NULL) <> (amount_off IS NULL)),
+    CHECK (expires_at IS NULL OR starts_at IS NULL OR expires_at > starts_at)
+    );
+
+-- One row per order created with a code; amount is the order amount before
+-- the discount. Cancelling the order sets released_at and gives the
+-- redemption back, the row stays for reporting. order_id has no foreign
+-- key: orders are partitioned and archived.
+CREATE TABLE IF NOT EXISTS promo_redemptions (
+    order_id uuid PRIMARY KEY,
+    code text NOT NULL REFERENCES promo_codes (code),
+    user_id text NOT NULL,
+    amount bigint NOT NULL,
+    discount bigint NOT NULL CHECK (discount >= 0),
+    redeemed_at timestamptz NOT NULL DEFAULT now(),
+    released_at timestamptz NULL
+    );
+
+CREATE INDEX IF NOT EXISTS promo_redemptions_code_user_idx
+    ON promo_redemptions (code, user_id)
+    WHERE released_at IS NULL;
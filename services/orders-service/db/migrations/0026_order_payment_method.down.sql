@@ -0,0 +1,2 @@
+ALTER TABLE orders_archive DROP COLUMN IF EXISTS payment_method;
+ALTER TABLE orders DROP COLUMN IF EXISTS payment_method;
@@ -0,0 +1,4 @@
+-- The method every payment of the order is requested with, e.g. 'card';
+-- payments-service pays bonus grants first and the rest with it.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS payment_method text NOT NULL DEFAULT 'balance';
+ALTER TABLE orders_archive ADD COLUMN IF NOT EXISTS payment_method text NOT NULL DEFAULT 'balance';
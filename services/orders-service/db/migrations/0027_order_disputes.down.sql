@@ -0,0 +1,20 @@
+DROP INDEX IF EXISTS orders_done_updated_idx;
+CREATE INDEX IF NOT EXISTS orders_done_updated_idx
+    ON orders (updated_at)
+    WHERE status IN ('FINISHED', 'CANCELLED');
+
+-- Disputed and refunded orders were paid for in full.
+UPDATE orders SET status = 'FINISHED', version = version + 1, updated_at = now()
+WHERE status IN ('DISPUTED', 'REFUNDED');
+UPDATE orders_archive SET status = 'FINISHED', version = version + 1, updated_at = now()
+WHERE status IN ('DISPUTED', 'REFUNDED');
+
+ALTER TABLE orders_archive DROP CONSTRAINT IF EXISTS orders_archive_status_check;
+ALTER TABLE orders_archive ADD CONSTRAINT orders_archive_status_check
+    CHECK (status IN ('FINISHED', 'CANCELLED'));
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('SCHEDULED', 'NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED'));
+
+DROP TABLE IF EXISTS order_disputes;
@@ -0,0 +1,26 @@
+-- Disputes of finished orders, opened and resolved in payments-service and
+-- mirrored here from PaymentDisputeChanged. A disputed order is DISPUTED
+-- until the dispute is resolved; then it is REFUNDED, or FINISHED again
+-- when the payments are upheld. Archived orders are disputed in place.
+CREATE TABLE IF NOT EXISTS order_disputes (
+    order_id uuid PRIMARY KEY,
+    status text NOT NULL CHECK (status IN ('OPEN', 'REFUNDED', 'UPHELD')),
+    amount bigint NOT NULL,
+    reason text NOT NULL,
+    opened_at timestamptz NOT NULL,
+    resolved_at timestamptz NULL
+    );
+
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS orders_status_check;
+ALTER TABLE orders ADD CONSTRAINT orders_status_check
+    CHECK (status IN ('SCHEDULED', 'NEW', 'PARTIALLY_PAID', 'FINISHED', 'CANCELLED', 'DISPUTED', 'REFUNDED'));
+
+ALTER TABLE orders_archive DROP CONSTRAINT IF EXISTS orders_archive_status_check;
+ALTER TABLE orders_archive ADD CONSTRAINT orders_archive_status_check
+    CHECK (status IN ('FINISHED', 'CANCELLED', 'DISPUTED', 'REFUNDED'));
+
+-- REFUNDED is terminal and archived like FINISHED and CANCELLED.
+DROP INDEX IF EXISTS orders_done_updated_idx;
+CREATE INDEX IF NOT EXISTS orders_done_updated_idx
+    ON orders (updated_at)
+    WHERE status IN ('FINISHED', 'CANCELLED', 'REFUNDED');
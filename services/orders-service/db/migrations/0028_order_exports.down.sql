@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS orders_archive_updated_order_idx;
+DROP INDEX IF EXISTS orders_updated_order_idx;
+DROP TABLE IF EXISTS export_watermarks;
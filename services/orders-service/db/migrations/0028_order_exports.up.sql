@@ -0,0 +1,19 @@
+-- Position of the orders export (internal/export): the last exported change
+-- as (updated_at, order_id). The exporter locks the row for the whole batch,
+-- so only one replica exports at a time.
+CREATE TABLE IF NOT EXISTS export_watermarks (
+    name text PRIMARY KEY,
+    updated_at timestamptz NOT NULL DEFAULT '-infinity',
+    order_id uuid NOT NULL DEFAULT '00000000-0000-0000-0000-000000000000',
+    exported_rows bigint NOT NULL DEFAULT 0,
+    exported_at timestamptz NULL
+    );
+
+INSERT INTO export_watermarks (name) VALUES ('orders') ON CONFLICT (name) DO NOTHING;
+
+-- Changes are read in (updated_at, order_id) order from both tables.
+CREATE INDEX IF NOT EXISTS orders_updated_order_idx
+    ON orders (updated_at, order_id);
+
+CREATE INDEX IF NOT EXISTS orders_archive_updated_order_idx
+    ON orders_archive (updated_at, order_id);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS projection_replay_offsets;
+DROP TABLE IF EXISTS projection_replays;
+DROP TABLE IF EXISTS order_status_history;
@@ -0,0 +1,52 @@
+-- Status changes of orders as published to orders.order_status_changed.v1,
+-- one row per event. A projection (internal/projection): kept up to date by
+-- its consumer and rebuilt from the topic by a replay.
+CREATE TABLE IF NOT EXISTS order_status_history (
+    event_id uuid PRIMARY KEY,
+    order_id uuid NOT NULL,
+    user_id text NOT NULL,
+    previous_status text NOT NULL,
+    status text NOT NULL,
+    amount bigint NOT NULL,
+    paid_amount bigint NOT NULL,
+    reason text NOT NULL DEFAULT '',
+    occurred_at timestamptz NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS order_status_history_order_idx
+    ON order_status_history (order_id, occurred_at);
+
+-- Rebuilds of projections started with StartProjectionReplay. A replay
+-- empties the projection and reads its topic from the earliest offset up to
+-- the end offsets taken then; owner is the replica running it, which bumps
+-- heartbeat_at with every batch so another replica can take over a replay
+-- whose replica died. At most one replay of a projection runs at a time.
+CREATE TABLE IF NOT EXISTS projection_replays (
+    id bigserial PRIMARY KEY,
+    projection text NOT NULL,
+    topic text NOT NULL,
+    status text NOT NULL DEFAULT 'RUNNING' CHECK (status IN ('RUNNING', 'STOPPED', 'COMPLETED', 'FAILED')),
+    rate_limit integer NOT NULL DEFAULT 0 CHECK (rate_limit >= 0),
+    applied bigint NOT NULL DEFAULT 0,
+    error text NOT NULL DEFAULT '',
+    requested_by text NOT NULL,
+    reason text NOT NULL,
+    owner uuid NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    heartbeat_at timestamptz NULL,
+    finished_at timestamptz NULL
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS projection_replays_running_idx
+    ON projection_replays (projection) WHERE status = 'RUNNING';
+
+-- Progress of a replay per partition: next_offset moves from start_offset
+-- to end_offset in the transaction that applies the messages.
+CREATE TABLE IF NOT EXISTS projection_replay_offsets (
+    replay_id bigint NOT NULL REFERENCES projection_replays (id) ON DELETE CASCADE,
+    partition integer NOT NULL,
+    start_offset bigint NOT NULL,
+    next_offset bigint NOT NULL,
+    end_offset bigint NOT NULL,
+    PRIMARY KEY (replay_id, partition)
+    );
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS orders_read;
+DROP VIEW IF EXISTS orders_read_source;
@@ -0,0 +1,64 @@
+-- What an order looks like in orders_read, computed from the hot table, the
+-- archive, its latest installment and its receipt. last_payment_reason is
+-- the failure reason of the latest installment, or of the order's own
+-- payment for orders without installments; item_count is the number of
+-- receipt lines, counted as receipt.Items would for orders without a
+-- receipt yet.
+CREATE OR REPLACE VIEW orders_read_source AS
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       CASE WHEN lp.payment_id IS NULL THEN o.payment_failure_reason ELSE lp.failure_reason END AS last_payment_reason,
+       COALESCE(r.lines, CASE WHEN o.fee_amount > 0 THEN 2 ELSE 1 END)::integer AS item_count
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+) o
+LEFT JOIN LATERAL (
+    SELECT p.payment_id, p.failure_reason
+    FROM order_payments p
+    WHERE p.order_id = o.order_id
+    ORDER BY p.created_at DESC
+    LIMIT 1
+) lp ON true
+LEFT JOIN LATERAL (
+    SELECT jsonb_array_length(rc.items) AS lines
+    FROM receipts rc
+    WHERE rc.order_id = o.order_id
+) r ON true;
+
+-- Denormalized orders, hot and archived, that ListOrders reads. A projection
+-- (internal/projection) of orders.order_changed.v1: its consumer copies the
+-- order named by each event from orders_read_source; the archiver sets
+-- archived in the transaction that moves the order. A row never goes back
+-- to a lower version, nor from archived to not archived.
+CREATE TABLE IF NOT EXISTS orders_read (
+    order_id uuid PRIMARY KEY,
+    user_id text NOT NULL,
+    amount bigint NOT NULL,
+    description text NOT NULL,
+    status text NOT NULL,
+    created_at timestamptz NOT NULL,
+    payment_failure_reason text NULL,
+    paid_amount bigint NOT NULL,
+    fee_amount bigint NOT NULL,
+    metadata jsonb NOT NULL,
+    tags text[] NOT NULL,
+    version bigint NOT NULL,
+    updated_at timestamptz NOT NULL,
+    pay_at timestamptz NULL,
+    archived boolean NOT NULL,
+    last_payment_reason text NULL,
+    item_count integer NOT NULL
+    );
+
+CREATE INDEX IF NOT EXISTS orders_read_user_idx
+    ON orders_read (user_id, created_at DESC, order_id DESC);
+
+CREATE INDEX IF NOT EXISTS orders_read_tags_idx
+    ON orders_read USING gin (tags);
+
+INSERT INTO orders_read
+SELECT * FROM orders_read_source
+ON CONFLICT (order_id) DO NOTHING;
@@ -0,0 +1,2 @@
+ALTER TABLE orders_archive DROP COLUMN IF EXISTS pay_in_installments;
+ALTER TABLE orders DROP COLUMN IF EXISTS pay_in_installments;
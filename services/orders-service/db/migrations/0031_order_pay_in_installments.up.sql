@@ -0,0 +1,14 @@
+-- Whether the order was created with pay_in_installments; only those are
+-- paid with PayOrder, every other order requests its full payment itself.
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS pay_in_installments boolean NOT NULL DEFAULT false;
+ALTER TABLE orders_archive ADD COLUMN IF NOT EXISTS pay_in_installments boolean NOT NULL DEFAULT false;
+
+-- Payable orders created before the column: an installment order has
+-- installments or, if none were requested yet, no PaymentRequested of its own
+-- in the outbox. Archived orders are final and keep false.
+UPDATE orders o
+SET pay_in_installments = true
+WHERE o.status IN ('NEW', 'PARTIALLY_PAID')
+  AND o.pay_at IS NULL
+  AND (EXISTS (SELECT 1 FROM order_payments p WHERE p.order_id = o.order_id)
+       OR NOT EXISTS (SELECT 1 FROM outbox x WHERE x.topic = 'payments.payment_requested.v1' AND x.kafka_key = o.order_id::text));
@@ -0,0 +1,5 @@
+-- Unsent rows by publisher slot, hash(kafka_key) % 64; the expression must
+-- stay the one LockUnsentOutbox filters on.
+CREATE INDEX IF NOT EXISTS outbox_unsent_slot_idx
+    ON outbox ((mod(abs(hashtext(kafka_key)::bigint), 64)), id)
+    WHERE sent_at IS NULL AND status <> 'DEAD';
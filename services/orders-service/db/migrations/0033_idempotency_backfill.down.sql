@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idempotency_keys_created_idx;
+DELETE FROM idempotency_keys WHERE request_hash = ''::bytea;
@@ -0,0 +1,21 @@
+-- 0009_idempotency_keys did not carry over the keys of orders and payments
+-- created before it, so a retry with such a key made a second order or
+-- payment. They are copied here without a request hash or response: the
+-- library rejects any reuse of them instead of replaying. The old columns
+-- are no longer written, so running this after 0009 copies the same keys.
+INSERT INTO idempotency_keys (scope, user_id, idempotency_key, request_hash, status, created_at, completed_at)
+SELECT 'orders.CreateOrder', user_id, idempotency_key, ''::bytea, 'COMPLETED', created_at, created_at
+FROM orders
+WHERE idempotency_key IS NOT NULL AND idempotency_key <> ''
+    ON CONFLICT (scope, user_id, idempotency_key) DO NOTHING;
+
+INSERT INTO idempotency_keys (scope, user_id, idempotency_key, request_hash, status, created_at, completed_at)
+SELECT 'orders.PayOrder', o.user_id, p.idempotency_key, ''::bytea, 'COMPLETED', p.created_at, p.created_at
+FROM order_payments p
+JOIN orders o ON o.order_id = p.order_id
+WHERE p.idempotency_key IS NOT NULL AND p.idempotency_key <> ''
+    ON CONFLICT (scope, user_id, idempotency_key) DO NOTHING;
+
+-- For the retention sweep.
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_idx
+    ON idempotency_keys (created_at);
@@ -0,0 +1,12 @@
+-- The catalog items of orders created from items, priced when the order was
+-- created; receipts list them instead of one line for the whole order.
+-- order_id has no foreign key: orders are partitioned and archived, the
+-- items stay.
+CREATE TABLE IF NOT EXISTS order_items (
+    order_id uuid NOT NULL,
+    line int NOT NULL,
+    product_id text NOT NULL,
+    quantity bigint NOT NULL CHECK (quantity > 0),
+    unit_price bigint NOT NULL CHECK (unit_price > 0),
+    PRIMARY KEY (order_id, line)
+);
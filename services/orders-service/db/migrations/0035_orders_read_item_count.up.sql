@@ -0,0 +1,30 @@
+-- item_count in orders_read is the number of items the order was created
+-- from (order_items), or 1 for an order created without items, instead of
+-- the number of its receipt lines. The rows already projected are
+-- recounted.
+CREATE OR REPLACE VIEW orders_read_source AS
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       CASE WHEN lp.payment_id IS NULL THEN o.payment_failure_reason ELSE lp.failure_reason END AS last_payment_reason,
+       GREATEST(COALESCE(i.items, 0), 1)::integer AS item_count
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+) o
+LEFT JOIN LATERAL (
+    SELECT p.payment_id, p.failure_reason
+    FROM order_payments p
+    WHERE p.order_id = o.order_id
+    ORDER BY p.created_at DESC
+    LIMIT 1
+) lp ON true
+LEFT JOIN LATERAL (
+    SELECT COUNT(*) AS items
+    FROM order_items oi
+    WHERE oi.order_id = o.order_id
+) i ON true;
+
+UPDATE orders_read r
+SET item_count = GREATEST((SELECT COUNT(*) FROM order_items oi WHERE oi.order_id = r.order_id), 1);
@@ -0,0 +1,54 @@
+-- Запросы операторских RPC (OrdersAdminService)
+
+-- Заказ любого пользователя, архивные тоже, со спором, если он был
+-- name: GetOrderByID :one
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       d.status AS dispute_status, d.amount AS dispute_amount, d.reason AS dispute_reason, d.opened_at AS dispute_opened_at, d.resolved_at AS dispute_resolved_at
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    WHERE order_id = sqlc.arg(order_id)::uuid
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+    WHERE order_id = sqlc.arg(order_id)::uuid
+        LIMIT 1
+) o
+LEFT JOIN order_disputes d ON d.order_id = o.order_id;
+
+-- name: ListOrderPayments :many
+SELECT payment_id, amount, status, failure_reason, fee, created_at
+FROM order_payments
+WHERE order_id = $1
+ORDER BY created_at, payment_id;
+
+-- name: ListOrderPaymentRetries :many
+SELECT retry_key, attempts, next_attempt_at, last_reason
+FROM payment_retries
+WHERE order_id = $1
+ORDER BY updated_at;
+
+-- Последние события по ключу заказа, новые первыми
+-- name: ListOutboxByKey :many
+SELECT id, topic, status, attempts, last_error, created_at, sent_at, correlation_id
+FROM outbox
+WHERE kafka_key = sqlc.arg(kafka_key)
+ORDER BY id DESC
+    LIMIT sqlc.arg('limit');
+
+-- Статус ставится как есть, минуя оплаты; архивные заказы не меняются
+-- name: ForceOrderStatus :one
+UPDATE orders o
+SET status = sqlc.arg(status), version = o.version + 1, updated_at = now()
+FROM (
+    SELECT order_id, created_at, status
+    FROM orders
+    WHERE order_id = sqlc.arg(order_id)::uuid
+        FOR UPDATE
+) prev
+WHERE o.order_id = prev.order_id AND o.created_at = prev.created_at
+    RETURNING o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, prev.status AS previous_status;
+
+-- name: InsertAdminAudit :exec
+INSERT INTO admin_audit_log (operator, action, target, reason, details)
+VALUES ($1, $2, $3, $4, $5);
@@ -0,0 +1,32 @@
+-- Позиция выгрузки; пустой результат — выгрузку сейчас ведёт другая реплика
+-- name: LockExportWatermark :one
+SELECT name, updated_at, order_id, exported_rows, exported_at
+FROM export_watermarks
+WHERE name = $1
+    FOR UPDATE SKIP LOCKED;
+
+-- Заказы (и архивные), изменённые после позиции и раньше until, по порядку (updated_at, order_id)
+-- name: ListOrderChanges :many
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.payment_failure_code, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.payment_method, o.archived
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, payment_failure_code, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_method, false AS archived
+    FROM orders
+    WHERE (updated_at, order_id) > (sqlc.arg(after_updated_at)::timestamptz, sqlc.arg(after_order_id)::uuid)
+      AND updated_at < sqlc.arg(until)::timestamptz
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, payment_failure_code, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_method, true AS archived
+    FROM orders_archive
+    WHERE (updated_at, order_id) > (sqlc.arg(after_updated_at)::timestamptz, sqlc.arg(after_order_id)::uuid)
+      AND updated_at < sqlc.arg(until)::timestamptz
+) o
+ORDER BY o.updated_at, o.order_id
+    LIMIT sqlc.arg(batch_size)::int;
+
+-- Сдвигает позицию выгрузки на последнее выгруженное изменение
+-- name: AdvanceExportWatermark :exec
+UPDATE export_watermarks
+SET updated_at = sqlc.arg(updated_at),
+    order_id = sqlc.arg(order_id),
+    exported_rows = exported_rows + sqlc.arg(rows)::bigint,
+    exported_at = now()
+WHERE name = sqlc.arg(name);
@@ -0,0 +1,15 @@
+-- Таблица pkg/idempotency; строки пишутся в транзакции самой операции
+-- name: ClaimIdempotencyKey :execrows
+INSERT INTO idempotency_keys (scope, user_id, idempotency_key, request_hash, status)
+VALUES ($1, $2, $3, $4, 'IN_PROGRESS')
+    ON CONFLICT (scope, user_id, idempotency_key) DO NOTHING;
+
+-- name: GetIdempotencyKey :one
+SELECT request_hash, status, response
+FROM idempotency_keys
+WHERE scope = $1 AND user_id = $2 AND idempotency_key = $3;
+
+-- name: CompleteIdempotencyKey :exec
+UPDATE idempotency_keys
+SET status = 'COMPLETED', response = $4, completed_at = now()
+WHERE scope = $1 AND user_id = $2 AND idempotency_key = $3;
@@ -0,0 +1,7 @@
+-- name: UpsertKnownAccount :exec
+INSERT INTO known_accounts (user_id)
+VALUES ($1)
+    ON CONFLICT (user_id) DO NOTHING;
+
+-- name: KnownAccountExists :one
+SELECT EXISTS(SELECT 1 FROM known_accounts WHERE user_id = $1) AS exists;
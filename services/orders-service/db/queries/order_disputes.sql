@@ -0,0 +1,28 @@
+-- Споры по заказам из PaymentDisputeChanged. Открытый спор можно только
+-- закрыть; повторно доставленное событие ничего не меняет (0 строк)
+-- name: UpsertOrderDispute :execrows
+INSERT INTO order_disputes (order_id, status, amount, reason, opened_at, resolved_at)
+VALUES (sqlc.arg(order_id), sqlc.arg(status), sqlc.arg(amount), sqlc.arg(reason), sqlc.arg(opened_at), sqlc.narg(resolved_at))
+    ON CONFLICT (order_id) DO UPDATE
+SET status = EXCLUDED.status, resolved_at = EXCLUDED.resolved_at
+WHERE order_disputes.status = 'OPEN' AND EXCLUDED.status <> 'OPEN';
+
+-- Переводит заказ из from_status в status там, где он лежит: в горячей
+-- таблице или в архиве
+-- name: SetOrderDisputeStatus :one
+WITH hot AS (
+UPDATE orders
+SET status = sqlc.arg(status)::text, version = version + 1, updated_at = now()
+WHERE order_id = sqlc.arg(order_id)::uuid AND status = sqlc.arg(from_status)::text
+    RETURNING order_id, user_id, amount, paid_amount
+),
+cold AS (
+UPDATE orders_archive
+SET status = sqlc.arg(status)::text, version = version + 1, updated_at = now()
+WHERE order_id = sqlc.arg(order_id)::uuid AND status = sqlc.arg(from_status)::text
+    RETURNING order_id, user_id, amount, paid_amount
+)
+SELECT order_id, user_id, amount, paid_amount FROM hot
+UNION ALL
+SELECT order_id, user_id, amount, paid_amount FROM cold
+    LIMIT 1;
@@ -0,0 +1,12 @@
+-- Позиции заказов из каталога
+
+-- name: InsertOrderItem :exec
+INSERT INTO order_items (order_id, line, product_id, quantity, unit_price)
+VALUES (sqlc.arg(order_id)::uuid, sqlc.arg(line), sqlc.arg(product_id), sqlc.arg(quantity), sqlc.arg(unit_price));
+
+-- Позиции в порядке, в котором они были в запросе; пусто для заказов без items
+-- name: ListOrderItems :many
+SELECT product_id, quantity, unit_price
+FROM order_items
+WHERE order_id = sqlc.arg(order_id)::uuid
+ORDER BY line;
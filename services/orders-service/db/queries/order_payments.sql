@@ -0,0 +1,16 @@
+-- name: CreateOrderPayment :one
+INSERT INTO order_payments (order_id, amount)
+VALUES ($1, $2)
+RETURNING payment_id, order_id, amount, status, created_at;
+
+-- name: SumPendingOrderPayments :one
+SELECT COALESCE(SUM(amount), 0)::bigint AS pending
+FROM order_payments
+WHERE order_id = $1 AND status = 'PENDING';
+
+-- Результат платежа применяем только один раз: PENDING -> SUCCEEDED/FAILED
+-- name: ResolveOrderPayment :one
+UPDATE order_payments
+SET status = $2, failure_reason = $3, fee = $4
+WHERE payment_id = $1 AND status = 'PENDING'
+RETURNING payment_id, order_id, amount;
@@ -0,0 +1,40 @@
+-- Новое предложение заменяет висящее: старое переводим в CANCELLED
+-- name: CancelPendingOrderTransfer :exec
+UPDATE order_transfers
+SET status = 'CANCELLED', resolved_at = now()
+WHERE order_id = $1 AND status = 'PENDING';
+
+-- name: CreateOrderTransfer :one
+INSERT INTO order_transfers (order_id, from_user_id, to_user_id)
+VALUES ($1, $2, $3)
+    RETURNING transfer_id, order_id, from_user_id, to_user_id, status, created_at;
+
+-- name: GetPendingOrderTransferForUpdate :one
+SELECT transfer_id, order_id, from_user_id, to_user_id, status, created_at
+FROM order_transfers
+WHERE order_id = $1 AND to_user_id = $2 AND status = 'PENDING'
+    FOR UPDATE;
+
+-- name: AcceptOrderTransfer :exec
+UPDATE order_transfers
+SET status = 'ACCEPTED', resolved_at = now()
+WHERE transfer_id = $1 AND status = 'PENDING';
+
+-- Платёж «в полёте»: PENDING-частичка, запланированный повтор или ещё не
+-- обработанная оплата заказа целиком (у такого NEW-заказа нет order_payments,
+-- но есть PaymentRequested в outbox)
+-- name: OrderHasPaymentInFlight :one
+SELECT (EXISTS(SELECT 1 FROM order_payments p WHERE p.order_id = o.order_id AND p.status = 'PENDING')
+    OR EXISTS(SELECT 1 FROM payment_retries r WHERE r.order_id = o.order_id)
+    OR (o.status = 'NEW'
+        AND NOT EXISTS(SELECT 1 FROM order_payments p WHERE p.order_id = o.order_id)
+        AND EXISTS(SELECT 1 FROM outbox x WHERE x.kafka_key = o.order_id::text AND x.topic = 'payments.payment_requested.v1')))::boolean AS in_flight
+FROM orders o
+WHERE o.order_id = $1;
+
+-- Вызывающий держит строку через GetOrderForUpdate
+-- name: SetOrderOwner :one
+UPDATE orders
+SET user_id = sqlc.arg(to_user_id), version = version + 1, updated_at = now()
+WHERE order_id = sqlc.arg(order_id) AND user_id = sqlc.arg(from_user_id)
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at;
@@ -1,33 +1,196 @@
+-- Заказ с pay_at создаётся в SCHEDULED, без него — сразу NEW
 -- name: CreateOrder :one
-INSERT INTO orders (user_id, amount, description, status)
-VALUES ($1, $2, $3, 'NEW')
-    RETURNING order_id, user_id, amount, description, status, created_at;
-
--- name: CreateOrderIdempotent :one
-INSERT INTO orders (user_id, amount, description, status, idempotency_key)
-VALUES ($1, $2, $3, 'NEW', $4)
-    ON CONFLICT (user_id, idempotency_key) DO NOTHING
-RETURNING order_id, user_id, amount, description, status, created_at, idempotency_key;
-
--- name: GetOrderByIdempotency :one
-SELECT order_id, user_id, amount, description, status, created_at, idempotency_key
-FROM orders
-WHERE user_id = $1 AND idempotency_key = $2;
+INSERT INTO orders (user_id, amount, description, status, metadata, tags, pay_at, payment_method, pay_in_installments)
+VALUES ($1, $2, $3, CASE WHEN sqlc.narg(pay_at)::timestamptz IS NULL THEN 'NEW' ELSE 'SCHEDULED' END, $4, $5, sqlc.narg(pay_at), sqlc.arg(payment_method), sqlc.arg(pay_in_installments))
+    RETURNING order_id, user_id, amount, description, status, created_at, metadata, tags, version, updated_at, pay_at, payment_method;
 
+-- Архивные заказы тоже находятся; горячая таблица проверяется первой.
+-- Спор по заказу, если он был, приходит в колонках dispute_*
 -- name: GetOrder :one
-SELECT order_id, user_id, amount, description, status, created_at
-FROM orders
-WHERE order_id = $1 AND user_id = $2;
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       d.status AS dispute_status, d.amount AS dispute_amount, d.reason AS dispute_reason, d.opened_at AS dispute_opened_at, d.resolved_at AS dispute_resolved_at
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    WHERE order_id = sqlc.arg(order_id)::uuid AND user_id = sqlc.arg(user_id)::text
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+    WHERE order_id = sqlc.arg(order_id)::uuid AND user_id = sqlc.arg(user_id)::text
+        LIMIT 1
+) o
+LEFT JOIN order_disputes d ON d.order_id = o.order_id;
 
+-- GetOrder для нескольких заказов одного пользователя за один запрос;
+-- чужие и несуществующие просто не возвращаются. Заказ, найденный и в
+-- горячей таблице, и в архиве, берётся из горячей
+-- name: GetOrders :many
+SELECT DISTINCT ON (o.order_id)
+       o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       d.status AS dispute_status, d.amount AS dispute_amount, d.reason AS dispute_reason, d.opened_at AS dispute_opened_at, d.resolved_at AS dispute_resolved_at
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    WHERE order_id = ANY(sqlc.arg(order_ids)::uuid[]) AND user_id = sqlc.arg(user_id)::text
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+    WHERE order_id = ANY(sqlc.arg(order_ids)::uuid[]) AND user_id = sqlc.arg(user_id)::text
+) o
+LEFT JOIN order_disputes d ON d.order_id = o.order_id
+ORDER BY o.order_id, o.archived;
+
+-- Читает проекцию orders_read, а не orders: без UNION с архивом и с
+-- last_payment_reason и item_count. Пустой tag — без фильтра; @> вместо = ANY,
+-- чтобы работал GIN-индекс по tags. Архивные заказы — только с include_archived
 -- name: ListOrders :many
-SELECT order_id, user_id, amount, description, status, created_at
-FROM orders
-WHERE user_id = $1
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count
+FROM orders_read
+WHERE user_id = sqlc.arg(user_id)::text
+  AND (sqlc.arg(include_archived)::bool OR NOT archived)
+  AND (sqlc.arg(tag)::text = '' OR tags @> ARRAY[sqlc.arg(tag)::text])
 ORDER BY created_at DESC, order_id DESC
-    LIMIT $2 OFFSET $3;
+    LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- Важно для consumer: обновляем статус только если он ещё NEW (идемпотентно)
--- name: UpdateOrderStatusIfNew :exec
+-- name: UpdateOrderStatusIfNew :one
 UPDATE orders
-SET status = $2
-WHERE order_id = $1 AND status = 'NEW';
+SET status = $2, payment_failure_reason = $3, fee_amount = $4, payment_failure_code = $5, version = version + 1, updated_at = now()
+WHERE order_id = $1 AND status = 'NEW'
+    RETURNING user_id, amount, paid_amount, status;
+
+-- name: GetOrderForUpdate :one
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_method, pay_in_installments
+FROM orders
+WHERE order_id = $1 AND user_id = $2
+    FOR UPDATE;
+
+-- Засчитываем успешный платёж-частичку; статус NEW/PARTIALLY_PAID -> PARTIALLY_PAID/FINISHED
+-- name: ApplyOrderPayment :one
+UPDATE orders o
+SET paid_amount = o.paid_amount + sqlc.arg(paid_amount),
+    fee_amount = o.fee_amount + sqlc.arg(fee_amount),
+    status = CASE WHEN o.paid_amount + sqlc.arg(paid_amount) >= o.amount THEN 'FINISHED' ELSE 'PARTIALLY_PAID' END,
+    version = o.version + 1,
+    updated_at = now()
+FROM (
+    SELECT order_id, created_at, status
+    FROM orders
+    WHERE order_id = sqlc.arg(order_id)::uuid
+        FOR UPDATE
+) prev
+WHERE o.order_id = prev.order_id AND o.created_at = prev.created_at AND prev.status IN ('NEW', 'PARTIALLY_PAID')
+    RETURNING o.user_id, o.amount, o.paid_amount, o.status, prev.status AS previous_status;
+
+-- Правка описания, metadata и tags; вызывающий держит строку через GetOrderForUpdate
+-- name: UpdateOrderDetails :one
+UPDATE orders
+SET description = sqlc.arg(description),
+    metadata = sqlc.arg(metadata),
+    tags = sqlc.arg(tags),
+    version = version + 1,
+    updated_at = now()
+WHERE order_id = sqlc.arg(order_id) AND user_id = sqlc.arg(user_id)
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at;
+
+-- Сериализует CreateOrder одного пользователя до конца транзакции, чтобы квоту NEW-заказов и проверку дублей не обошли параллельные запросы
+-- name: LockUserOrderCreate :exec
+SELECT pg_advisory_xact_lock(hashtext('orders_create'), hashtext(sqlc.arg(user_id)::text));
+
+-- name: CountNewOrders :one
+SELECT count(*)
+FROM orders
+WHERE user_id = $1 AND status = 'NEW';
+
+-- Последний неотменённый заказ пользователя с той же суммой и описанием не старше since
+-- name: FindRecentDuplicateOrder :one
+SELECT order_id
+FROM orders
+WHERE user_id = $1
+  AND amount = $2
+  AND description = $3
+  AND status <> 'CANCELLED'
+  AND created_at >= sqlc.arg(since)::timestamptz
+ORDER BY created_at DESC
+    LIMIT 1;
+
+-- Переносит пачку завершённых/отменённых/возвращённых заказов, не менявшихся с before, в orders_archive.
+-- Событий о переносе нет, поэтому archived в orders_read ставим здесь же;
+-- перенесённые заказы возвращаются, чтобы сбросить их в кэше
+-- name: ArchiveOrders :many
+WITH moved AS (
+    DELETE FROM orders
+    WHERE order_id IN (
+        SELECT order_id
+        FROM orders
+        WHERE status IN ('FINISHED', 'CANCELLED', 'REFUNDED')
+          AND updated_at < sqlc.arg(before)::timestamptz
+        ORDER BY updated_at
+        LIMIT sqlc.arg(batch_size)::int
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments
+),
+marked AS (
+    UPDATE orders_read r
+    SET archived = true
+    FROM moved
+    WHERE r.order_id = moved.order_id
+)
+INSERT INTO orders_archive (order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments)
+SELECT order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments
+FROM moved
+    RETURNING order_id, user_id;
+
+-- Заказы, чей pay_at наступил; планировщик переводит их в NEW в той же транзакции
+-- name: LockDueScheduledOrders :many
+SELECT order_id, created_at, user_id, amount, pay_at, payment_method
+FROM orders
+WHERE status = 'SCHEDULED' AND pay_at <= now()
+ORDER BY pay_at
+    LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: ReleaseScheduledOrder :exec
+UPDATE orders
+SET status = 'NEW', version = version + 1, updated_at = now()
+WHERE order_id = $1 AND created_at = $2 AND status = 'SCHEDULED';
+
+-- Отмена до срока; оплаченные или уже запрошенные заказы не трогаем
+-- name: CancelScheduledOrder :one
+UPDATE orders
+SET status = 'CANCELLED', payment_failure_reason = sqlc.arg(reason), version = version + 1, updated_at = now()
+WHERE order_id = sqlc.arg(order_id) AND user_id = sqlc.arg(user_id) AND status = 'SCHEDULED'
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at;
+
+-- Повтор оплаты пользователем: только заказ, отменённый из-за нехватки средств,
+-- и не больше max_retries раз
+-- name: RetryOrderPayment :one
+UPDATE orders
+SET status = 'NEW', payment_failure_reason = NULL, payment_failure_code = NULL,
+    payment_retry_count = payment_retry_count + 1, version = version + 1, updated_at = now()
+WHERE order_id = sqlc.arg(order_id) AND user_id = sqlc.arg(user_id)
+  AND status = 'CANCELLED' AND payment_failure_code = 'NOT_ENOUGH_FUNDS'
+  AND payment_retry_count < sqlc.arg(max_retries)::int
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_retry_count, payment_method;
+
+-- Почему RetryOrderPayment не нашёл заказ
+-- name: GetOrderPaymentRetry :one
+SELECT status, payment_failure_code, payment_retry_count
+FROM orders
+WHERE order_id = $1 AND user_id = $2;
+
+-- Число заказов в горячей таблице по статусам (orders_archive не считается)
+-- name: CountOrdersByStatus :many
+SELECT status, count(*)::bigint AS orders
+FROM orders
+GROUP BY status
+ORDER BY status;
+
+-- Самый ранний момент, с которого заказ ждёт в NEW: отложенные — с pay_at, NULL если таких нет.
+-- Заказы в рассрочку не считаются: они ждут в NEW, пока пользователь не оплатит первую часть, и это не зависание саги
+-- name: OldestNewOrderSince :one
+SELECT min(GREATEST(created_at, COALESCE(pay_at, created_at)))::timestamptz AS since
+FROM orders
+WHERE status = 'NEW'
+  AND NOT pay_in_installments;
@@ -0,0 +1,32 @@
+-- name: GetPaymentRetryAttempts :one
+SELECT attempts
+FROM payment_retries
+WHERE retry_key = $1;
+
+-- name: SchedulePaymentRetry :exec
+INSERT INTO payment_retries (retry_key, order_id, payment_id, user_id, amount, attempts, next_attempt_at, last_reason, correlation_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    ON CONFLICT (retry_key) DO UPDATE
+    SET attempts = EXCLUDED.attempts,
+        next_attempt_at = EXCLUDED.next_attempt_at,
+        last_reason = EXCLUDED.last_reason,
+        correlation_id = EXCLUDED.correlation_id,
+        updated_at = now();
+
+-- name: LockDuePaymentRetries :many
+SELECT retry_key, order_id, payment_id, user_id, amount, attempts, correlation_id,
+    COALESCE((SELECT o.payment_method FROM orders o WHERE o.order_id = payment_retries.order_id), 'balance')::text AS payment_method
+FROM payment_retries
+WHERE next_attempt_at <= now()
+ORDER BY next_attempt_at
+    LIMIT $1
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkPaymentRetryPublished :exec
+UPDATE payment_retries
+SET next_attempt_at = NULL, updated_at = now()
+WHERE retry_key = $1;
+
+-- name: DeletePaymentRetry :exec
+DELETE FROM payment_retries
+WHERE retry_key = $1;
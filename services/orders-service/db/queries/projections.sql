@@ -0,0 +1,120 @@
+-- Смена статуса заказа в проекции; повторное событие ничего не меняет
+-- name: InsertOrderStatusHistory :exec
+INSERT INTO order_status_history (event_id, order_id, user_id, previous_status, status, amount, paid_amount, reason, occurred_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+    ON CONFLICT (event_id) DO NOTHING;
+
+-- Очищает проекцию перед перестроением
+-- name: TruncateOrderStatusHistory :exec
+TRUNCATE order_status_history;
+
+-- История статусов заказа по проекции, по времени
+-- name: ListOrderStatusHistory :many
+SELECT event_id, order_id, user_id, previous_status, status, amount, paid_amount, reason, occurred_at
+FROM order_status_history
+WHERE order_id = $1
+ORDER BY occurred_at, event_id
+    LIMIT sqlc.arg(max_rows)::int;
+
+-- Переписывает строку orders_read текущим состоянием заказа. Строку не
+-- откатываем на меньшую version и из архивной обратно в горячую: запоздавшее
+-- чтение ничего не испортит. Неизвестный заказ ничего не меняет
+-- name: RefreshOrderRead :exec
+INSERT INTO orders_read (order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count)
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count
+FROM orders_read_source s
+WHERE s.order_id = $1
+    LIMIT 1
+ON CONFLICT (order_id) DO UPDATE
+SET user_id = EXCLUDED.user_id,
+    amount = EXCLUDED.amount,
+    description = EXCLUDED.description,
+    status = EXCLUDED.status,
+    payment_failure_reason = EXCLUDED.payment_failure_reason,
+    paid_amount = EXCLUDED.paid_amount,
+    fee_amount = EXCLUDED.fee_amount,
+    metadata = EXCLUDED.metadata,
+    tags = EXCLUDED.tags,
+    version = EXCLUDED.version,
+    updated_at = EXCLUDED.updated_at,
+    pay_at = EXCLUDED.pay_at,
+    archived = EXCLUDED.archived,
+    last_payment_reason = EXCLUDED.last_payment_reason,
+    item_count = EXCLUDED.item_count
+WHERE (orders_read.version, orders_read.archived) <= (EXCLUDED.version, EXCLUDED.archived);
+
+-- Очищает orders_read перед перестроением
+-- name: TruncateOrdersRead :exec
+TRUNCATE orders_read;
+
+-- Заполняет пустую orders_read всеми заказами, включая те, чьих событий в
+-- топике уже нет
+-- name: SeedOrdersRead :execrows
+INSERT INTO orders_read (order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count)
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count
+FROM orders_read_source
+    ON CONFLICT (order_id) DO NOTHING;
+
+-- Новое перестроение; пустой результат — проекция уже перестраивается
+-- name: CreateProjectionReplay :one
+INSERT INTO projection_replays (projection, topic, rate_limit, requested_by, reason)
+VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (projection) WHERE status = 'RUNNING' DO NOTHING
+RETURNING id, projection, topic, status, rate_limit, applied, error, requested_by, reason, owner, created_at, heartbeat_at, finished_at;
+
+-- name: GetProjectionReplay :one
+SELECT id, projection, topic, status, rate_limit, applied, error, requested_by, reason, owner, created_at, heartbeat_at, finished_at
+FROM projection_replays
+WHERE id = $1;
+
+-- Забирает перестроение, которое никто не ведёт: новое или с реплики, не отмечавшейся дольше stale_ms
+-- name: ClaimProjectionReplay :one
+UPDATE projection_replays
+SET owner = sqlc.arg(owner),
+    heartbeat_at = now()
+WHERE id = (
+    SELECT id
+    FROM projection_replays
+    WHERE status = 'RUNNING'
+      AND (heartbeat_at IS NULL OR heartbeat_at < now() - sqlc.arg(stale_ms)::bigint * interval '1 millisecond')
+    ORDER BY id
+    LIMIT 1
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING id, projection, topic, status, rate_limit, applied, error, requested_by, reason, owner, created_at, heartbeat_at, finished_at;
+
+-- Засчитывает применённые сообщения; 0 строк — перестроение остановлено или забрано другой репликой
+-- name: AdvanceProjectionReplay :execrows
+UPDATE projection_replays
+SET applied = applied + sqlc.arg(applied)::bigint,
+    heartbeat_at = now()
+WHERE id = sqlc.arg(id)
+  AND status = 'RUNNING'
+  AND owner = sqlc.arg(owner);
+
+-- Завершает перестроение: COMPLETED, FAILED или STOPPED; пустой результат — оно уже не идёт
+-- name: FinishProjectionReplay :one
+UPDATE projection_replays
+SET status = sqlc.arg(status),
+    error = sqlc.arg(error),
+    finished_at = now()
+WHERE id = sqlc.arg(id)
+  AND status = 'RUNNING'
+RETURNING id, projection, topic, status, rate_limit, applied, error, requested_by, reason, owner, created_at, heartbeat_at, finished_at;
+
+-- name: InsertProjectionReplayOffset :exec
+INSERT INTO projection_replay_offsets (replay_id, partition, start_offset, next_offset, end_offset)
+VALUES ($1, $2, $3, $3, $4);
+
+-- Прогресс перестроения по партициям
+-- name: ListProjectionReplayOffsets :many
+SELECT replay_id, partition, start_offset, next_offset, end_offset
+FROM projection_replay_offsets
+WHERE replay_id = $1
+ORDER BY partition;
+
+-- name: SetProjectionReplayOffset :exec
+UPDATE projection_replay_offsets
+SET next_offset = sqlc.arg(next_offset)
+WHERE replay_id = sqlc.arg(replay_id)
+  AND partition = sqlc.arg(partition);
@@ -0,0 +1,79 @@
+-- Промокоды и их погашения
+
+-- Существующий код не перезаписывается: тогда строк нет
+-- name: CreatePromoCode :one
+INSERT INTO promo_codes (code, percent_off, amount_off, max_redemptions, max_redemptions_per_user, starts_at, expires_at)
+VALUES (sqlc.arg(code), sqlc.narg(percent_off), sqlc.narg(amount_off), sqlc.narg(max_redemptions), sqlc.narg(max_redemptions_per_user),
+        sqlc.narg(starts_at), sqlc.narg(expires_at))
+ON CONFLICT (code) DO NOTHING
+    RETURNING code, percent_off, amount_off, max_redemptions, max_redemptions_per_user, redeemed_count, starts_at, expires_at, created_at;
+
+-- name: GetPromoCode :one
+SELECT code, percent_off, amount_off, max_redemptions, max_redemptions_per_user, redeemed_count, starts_at, expires_at, created_at
+FROM promo_codes
+WHERE code = $1;
+
+-- Держит строку до конца транзакции, чтобы параллельные заказы не превысили max_redemptions
+-- name: GetPromoCodeForUpdate :one
+SELECT code, percent_off, amount_off, max_redemptions, max_redemptions_per_user, redeemed_count, starts_at, expires_at, created_at
+FROM promo_codes
+WHERE code = $1
+    FOR UPDATE;
+
+-- name: CountUserPromoRedemptions :one
+SELECT count(*)
+FROM promo_redemptions
+WHERE code = sqlc.arg(code) AND user_id = sqlc.arg(user_id) AND released_at IS NULL;
+
+-- Вызывающий держит строку промокода через GetPromoCodeForUpdate
+-- name: InsertPromoRedemption :exec
+WITH counted AS (
+    UPDATE promo_codes SET redeemed_count = redeemed_count + 1 WHERE code = sqlc.arg(code)
+)
+INSERT INTO promo_redemptions (order_id, code, user_id, amount, discount)
+VALUES (sqlc.arg(order_id)::uuid, sqlc.arg(code), sqlc.arg(user_id), sqlc.arg(amount), sqlc.arg(discount));
+
+-- Отмена заказа возвращает погашение; повторная отмена ничего не меняет
+-- name: ReleasePromoRedemption :execrows
+WITH released AS (
+    UPDATE promo_redemptions SET released_at = now()
+    WHERE order_id = sqlc.arg(order_id)::uuid AND released_at IS NULL
+    RETURNING code
+)
+UPDATE promo_codes p
+SET redeemed_count = p.redeemed_count - 1
+FROM released r
+WHERE p.code = r.code;
+
+-- Возвращённое отменой погашение заказа: RetryPayment снова его занимает
+-- name: GetReleasedPromoRedemption :one
+SELECT code, user_id
+FROM promo_redemptions
+WHERE order_id = $1 AND released_at IS NOT NULL;
+
+-- Вызывающий держит строку промокода через GetPromoCodeForUpdate и проверил лимиты
+-- name: ReclaimPromoRedemption :execrows
+WITH reclaimed AS (
+    UPDATE promo_redemptions SET released_at = NULL
+    WHERE order_id = sqlc.arg(order_id)::uuid AND released_at IS NOT NULL
+    RETURNING code
+)
+UPDATE promo_codes p
+SET redeemed_count = p.redeemed_count + 1
+FROM reclaimed r
+WHERE p.code = r.code;
+
+-- Действующее погашение заказа для строки скидки в чеке
+-- name: GetPromoRedemption :one
+SELECT code, amount, discount
+FROM promo_redemptions
+WHERE order_id = $1 AND released_at IS NULL;
+
+-- Отчёт по промокоду: действующие и возвращённые погашения, сумма скидок и заказов до скидки
+-- name: GetPromoCodeStats :one
+SELECT count(*) FILTER (WHERE released_at IS NULL)::bigint AS redemptions,
+       count(*) FILTER (WHERE released_at IS NOT NULL)::bigint AS released,
+       coalesce(sum(discount) FILTER (WHERE released_at IS NULL), 0)::bigint AS discount_total,
+       coalesce(sum(amount) FILTER (WHERE released_at IS NULL), 0)::bigint AS amount_total
+FROM promo_redemptions
+WHERE code = $1;
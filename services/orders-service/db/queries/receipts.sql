@@ -0,0 +1,15 @@
+-- Чеки завершённых заказов
+
+-- Номер вида R-20260115-000042; повторный чек заказа не выписывается
+-- name: InsertReceipt :execrows
+INSERT INTO receipts (order_id, number, user_id, items, amount, fee_amount, total_amount, order_created_at, paid_at)
+VALUES (sqlc.arg(order_id)::uuid,
+        'R-' || to_char(now() AT TIME ZONE 'UTC', 'YYYYMMDD') || '-' || lpad(nextval('receipt_number_seq')::text, 6, '0'),
+        sqlc.arg(user_id), sqlc.arg(items), sqlc.arg(amount), sqlc.arg(fee_amount), sqlc.arg(total_amount),
+        sqlc.arg(order_created_at), sqlc.arg(paid_at))
+ON CONFLICT (order_id) DO NOTHING;
+
+-- name: GetReceipt :one
+SELECT order_id, number, user_id, items, amount, fee_amount, total_amount, order_created_at, paid_at, issued_at
+FROM receipts
+WHERE order_id = sqlc.arg(order_id)::uuid AND user_id = sqlc.arg(user_id)::text;
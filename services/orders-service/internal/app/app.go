@@ -2,46 +2,149 @@ package app
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 
 	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/archive"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/auth"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/catalog"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/export"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/orderstats"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/projection"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/rest"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/statusbus"
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+	"github.com/ilyaytrewq/payments-service/pkg/deadline"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+	"github.com/ilyaytrewq/payments-service/pkg/loadshed"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/partition"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/segmentio/kafka-go"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 
 	grpcsvc "github.com/ilyaytrewq/payments-service/order-service/internal/grpc"
 	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
 )
 
 func Run(ctx context.Context, cfg config.Config) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "orders-service", "component", "app")
-	logger.Info("orders service starting", "grpc_addr", cfg.GRPCAddr, "redis_addr", cfg.RedisAddr != "", "kafka_brokers", len(cfg.KafkaBrokers))
+	logger.Info("orders service starting", "grpc_addr", cfg.GRPCAddr, "redis_addr", cfg.RedisAddr != "", "kafka_brokers", len(cfg.KafkaBrokers), "http_addr", cfg.HTTPAddr, "reflection", cfg.EnableReflection, "admin_api", cfg.EnableAdminAPI)
 
-	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	currency, err := money.ParseCurrency(cfg.Currency)
+	if err != nil {
+		logger.Error("invalid currency", "err", err)
+		return err
+	}
+	serviceAuth, err := svcauth.ParseConfig(cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	if err != nil {
+		logger.Error("invalid service auth config", "err", err)
+		return err
+	}
+
+	kafkaSecurity := kafkaio.Security{
+		TLS:           cfg.KafkaTLS,
+		CAFile:        cfg.KafkaTLSCAFile,
+		CertFile:      cfg.KafkaTLSCertFile,
+		KeyFile:       cfg.KafkaTLSKeyFile,
+		SASLMechanism: cfg.KafkaSASLMechanism,
+		SASLUsername:  cfg.KafkaSASLUsername,
+		SASLPassword:  cfg.KafkaSASLPassword,
+	}
+	kafkaTransport, err := kafkaSecurity.Transport()
+	if err != nil {
+		logger.Error("failed to configure kafka transport", "err", err)
+		return err
+	}
+	kafkaDialer, err := kafkaSecurity.Dialer()
+	if err != nil {
+		logger.Error("failed to configure kafka dialer", "err", err)
+		return err
+	}
+	logger.Info("kafka security configured", "tls", cfg.KafkaTLS, "sasl_mechanism", cfg.KafkaSASLMechanism)
+
+	if err := waitForDependencies(ctx, cfg, kafkaDialer, logger); err != nil {
+		return err
+	}
+
+	dbLogger := slog.Default().With("service", "orders-service", "component", "repo")
+	pool, err := pgtx.NewPool(ctx, cfg.DatabaseURL, pgtx.PoolOptions{
+		MaxConns:        int32(cfg.DBMaxConns),
+		MinConns:        int32(cfg.DBMinConns),
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		ConnectRetries:  cfg.DBConnectRetries,
+		ConnectBackoff:  cfg.DBConnectBackoff,
+
+		SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+		StatementTimeout:   cfg.DBStatementTimeout,
+	}, dbLogger)
 	if err != nil {
 		logger.Error("failed to create db pool", "err", err)
 		return err
 	}
 	defer pool.Close()
 
-	repo := postgres.NewRepo(pool)
+	var replica *pgxpool.Pool
+	if cfg.ReplicaURL != "" {
+		// A replica that is down at startup is not fatal: reads use the primary.
+		replica, err = pgtx.NewPool(ctx, cfg.ReplicaURL, pgtx.PoolOptions{
+			MaxConns:           int32(cfg.DBMaxConns),
+			MinConns:           int32(cfg.DBMinConns),
+			MaxConnLifetime:    cfg.DBMaxConnLifetime,
+			SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+			StatementTimeout:   cfg.DBStatementTimeout,
+		}, dbLogger)
+		if err != nil {
+			logger.Warn("read replica unavailable, reading from primary", "err", err)
+			replica = nil
+		} else {
+			defer replica.Close()
+		}
+	}
+
+	dbFaults, kafkaFaults, cacheFaults, err := chaos.Targets(cfg.ChaosEnabled, cfg.ChaosDB, cfg.ChaosKafka, cfg.ChaosCache, logger)
+	if err != nil {
+		logger.Error("invalid chaos config", "err", err)
+		return err
+	}
+
+	repo := postgres.NewRepo(pool, replica, pgtx.Config{Retries: cfg.DBTxRetries, Backoff: cfg.DBTxRetryBackoff})
+	repo.InjectFaults(dbFaults)
 
-	writer := &kafka.Writer{
-		Addr:         kafka.TCP(cfg.KafkaBrokers...),
-		Topic:        cfg.TopicPaymentRequested,
-		RequiredAcks: kafka.RequireAll,
-		Balancer:     &kafka.Hash{},
-		BatchTimeout: 50 * time.Millisecond,
+	writer, err := kafkaio.NewWriter(cfg.KafkaBrokers, kafkaTransport, kafkaio.WriterOptions{
+		Compression:  cfg.KafkaWriterCompression,
+		BatchSize:    cfg.KafkaWriterBatchSize,
+		BatchBytes:   int64(cfg.KafkaWriterBatchBytes),
+		BatchTimeout: cfg.KafkaWriterBatchTimeout,
+		MaxAttempts:  cfg.KafkaWriterMaxAttempts,
+	})
+	if err != nil {
+		logger.Error("invalid kafka writer config", "err", err)
+		return err
 	}
 	defer func() {
 		if err := writer.Close(); err != nil {
@@ -49,34 +152,261 @@ func Run(ctx context.Context, cfg config.Config) error {
 		}
 	}()
 
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        cfg.KafkaBrokers,
-		Topic:          cfg.TopicPaymentResult,
-		GroupID:        cfg.ConsumerGroupID,
-		MinBytes:       1e3,
-		MaxBytes:       10e6,
-		StartOffset:    kafka.FirstOffset,
-		CommitInterval: 0,
-	})
+	readerOpts := kafkaio.ReaderOptions{
+		StartOffset:       cfg.KafkaConsumerStartOffset,
+		MaxWait:           cfg.KafkaConsumerMaxWait,
+		QueueCapacity:     cfg.KafkaConsumerQueueCapacity,
+		RebalanceTimeout:  cfg.KafkaConsumerRebalanceTimeout,
+		SessionTimeout:    cfg.KafkaConsumerSessionTimeout,
+		HeartbeatInterval: cfg.KafkaConsumerHeartbeatInterval,
+	}
+	reader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicPaymentResult, cfg.ConsumerGroupID, readerOpts)
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
 	defer reader.Close()
 
-	outbox := kafkasvc.NewOutboxPublisher(repo, writer, cfg.OutboxPollInterval, cfg.OutboxBatchSize)
-	consumer := kafkasvc.NewPaymentResultConsumer(repo, reader)
+	accountReader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicAccountCreated, cfg.AccountsGroupID, readerOpts)
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
+	defer accountReader.Close()
 
-	var cacheClient *redis.Client
-	if cfg.RedisAddr != "" {
-		cacheClient = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+	disputeReader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicPaymentDispute, cfg.DisputesGroupID, readerOpts)
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
+	defer disputeReader.Close()
+
+	projectionReader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicStatusChanged, cfg.ProjectionsGroupID, readerOpts)
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
+	defer projectionReader.Close()
+
+	ordersReadReader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicOrderChanged, cfg.OrdersReadGroupID, readerOpts)
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
+	defer ordersReadReader.Close()
+
+	outbox := kafkasvc.NewOutboxPublisher(repo, chaos.WithWriterFaults(writer, kafkaFaults), cfg.OutboxPollInterval, cfg.OutboxBatchSize, cfg.OutboxWorkers, cfg.OutboxListen, kafkasvc.RetryPolicy{
+		MaxAttempts: cfg.OutboxMaxAttempts,
+		Backoff:     cfg.OutboxRetryBackoff,
+		MaxBackoff:  cfg.OutboxRetryMaxBackoff,
+	}, map[string]string{
+		"payments.payment_requested.v1":  cfg.TopicPaymentRequested,
+		grpcsvc.TopicOrderTransferred:    cfg.TopicOrderTransferred,
+		kafkasvc.TopicOrderStatusChanged: cfg.TopicStatusChanged,
+		kafkasvc.TopicOrderChanged:       cfg.TopicOrderChanged,
+	})
+	if cfg.OutboxTransactional {
+		outbox.UseTransactions(func(partition int) (kafkasvc.TxProducer, error) {
+			txCfg, err := kafkaSecurity.TxConfig(cfg.KafkaBrokers, fmt.Sprintf("%s-%d", cfg.OutboxTransactionalID, partition))
+			if err != nil {
+				return nil, err
+			}
+			txCfg.Compression = cfg.KafkaWriterCompression
+			producer, err := kafkatx.NewProducer(txCfg)
+			if err != nil {
+				return nil, err
+			}
+			return producer, nil
+		})
+		logger.Info("outbox publishes in kafka transactions", "transactional_id", cfg.OutboxTransactionalID)
+	}
+	accountConsumer := kafkasvc.NewAccountCreatedConsumer(repo, accountReader, cfg.KafkaHandlerTimeout)
+	lagReporter := kafkaio.NewLagReporter(cfg.KafkaBrokers, kafkaTransport, cfg.ConsumerGroupID, cfg.TopicPaymentResult, cfg.LagReportInterval, int64(cfg.LagThreshold), slog.Default().With("service", "orders-service", "component", "kafka"))
+
+	backend := cfg.CacheBackend
+	if backend == "" {
+		backend = "memory"
+		if cfg.RedisAddr != "" {
+			backend = "redis"
+		}
+	}
+	var orderCache cache.OrderCache
+	switch backend {
+	case "redis":
+		if cfg.RedisAddr == "" {
+			err := errors.New("ORDERS_CACHE_BACKEND=redis requires ORDERS_REDIS_ADDR")
+			logger.Error("invalid cache config", "err", err)
+			return err
+		}
+		cacheClient := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
 		defer func() {
 			if err := cacheClient.Close(); err != nil {
 				logger.Error("failed to close redis client", "err", err)
 			}
 		}()
+		orderCache = cache.NewRedisOrderCache(cacheClient, cfg.CacheTTL, cfg.CacheBreakerThreshold, cfg.CacheBreakerCooldown)
+	case "memory":
+		orderCache = cache.NewMemoryOrderCache(cfg.CacheMemorySize, cfg.CacheTTL)
+	case "none":
+		logger.Info("order cache disabled")
+	default:
+		err := fmt.Errorf("unknown cache backend %q", cfg.CacheBackend)
+		logger.Error("invalid cache config", "err", err)
+		return err
 	}
-	orderCache := cache.NewOrderCache(cacheClient, cfg.CacheTTL)
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpcUnaryLogger()))
-	ordersv1.RegisterOrdersServiceServer(grpcServer, grpcsvc.NewHandlers(repo, orderCache))
-	reflection.Register(grpcServer)
+	orderCache = cache.WithFaults(orderCache, cacheFaults)
+
+	var onOrderChanged kafkasvc.OrderChangedFunc
+	if orderCache != nil {
+		onOrderChanged = func(ctx context.Context, userID, _ string) {
+			if err := orderCache.InvalidateList(ctx, userID); err != nil {
+				logger.Error("failed to invalidate order list cache", "err", err, "user_id", userID)
+			}
+		}
+	}
+	retryPolicy := kafkasvc.RetryPolicy{
+		MaxAttempts: cfg.PaymentRetryMaxAttempts,
+		Backoff:     cfg.PaymentRetryBackoff,
+		MaxBackoff:  cfg.PaymentRetryMaxBackoff,
+	}
+	consumer := kafkasvc.NewPaymentResultConsumer(repo, reader, cfg.TxOffsets, onOrderChanged, retryPolicy, cfg.KafkaHandlerTimeout)
+	consumer.StorePayloads(cfg.InboxStorePayloads)
+	disputeConsumer := kafkasvc.NewDisputeConsumer(repo, disputeReader, onOrderChanged, cfg.KafkaHandlerTimeout)
+	statusHistory := projection.NewOrderStatusHistory(cfg.TopicStatusChanged)
+	projectionConsumer := projection.NewConsumer(repo, projectionReader, statusHistory, cfg.KafkaHandlerTimeout)
+	ordersRead := projection.NewOrdersRead(cfg.TopicOrderChanged, onOrderChanged)
+	ordersReadConsumer := projection.NewConsumer(repo, ordersReadReader, ordersRead, cfg.KafkaHandlerTimeout)
+	retrier := kafkasvc.NewPaymentRetrier(repo, cfg.TopicPaymentRequested, cfg.PaymentRetryPollInterval, cfg.OutboxBatchSize)
+	scheduler := kafkasvc.NewPaymentScheduler(repo, cfg.TopicPaymentRequested, cfg.ScheduledPaymentsPollInterval, cfg.OutboxBatchSize, onOrderChanged)
+
+	// The known accounts check asks payments about users whose
+	// AccountCreated has not arrived yet.
+	var payments paymentsv1.PaymentsServiceClient
+	if cfg.AccountPrecheck || cfg.KnownAccountsCheck {
+		clientInterceptors := []grpc.UnaryClientInterceptor{userauth.ForwardToken(), logging.UnaryClientInterceptor()}
+		if serviceAuth.Enabled() {
+			creds, err := auth.ServiceCredentials(serviceAuth)
+			if err != nil {
+				logger.Error("invalid service auth config", "err", err)
+				return err
+			}
+			clientInterceptors = append(clientInterceptors, creds)
+		}
+		paymentsConn, err := grpc.DialContext(ctx, cfg.PaymentsGRPCAddr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithChainUnaryInterceptor(clientInterceptors...),
+		)
+		if err != nil {
+			logger.Error("failed to dial payments grpc", "err", err, "addr", cfg.PaymentsGRPCAddr)
+			return err
+		}
+		defer paymentsConn.Close()
+		payments = paymentsv1.NewPaymentsServiceClient(paymentsConn)
+		logger.Info("account precheck enabled", "payments_grpc_addr", cfg.PaymentsGRPCAddr, "known_accounts", cfg.KnownAccountsCheck)
+	}
+
+	var prices catalog.PriceResolver
+	if cfg.CatalogURL != "" {
+		prices = catalog.NewHTTPResolver(cfg.CatalogURL, cfg.CatalogTimeout)
+	} else {
+		table, err := catalog.ParsePrices(cfg.CatalogPrices)
+		if err != nil {
+			logger.Error("failed to parse catalog prices", "err", err)
+			return err
+		}
+		prices = catalog.NewStaticResolver(table)
+	}
+
+	var exporter *export.Exporter
+	if cfg.ExportInterval > 0 {
+		var up export.Uploader
+		switch {
+		case cfg.ExportS3Bucket != "":
+			up, err = export.NewS3Uploader(export.S3Options{
+				Endpoint:  cfg.ExportS3Endpoint,
+				Bucket:    cfg.ExportS3Bucket,
+				Region:    cfg.ExportS3Region,
+				AccessKey: cfg.ExportS3AccessKey,
+				SecretKey: cfg.ExportS3SecretKey,
+				Timeout:   cfg.ExportUploadTimeout,
+			})
+		case cfg.ExportDir != "":
+			up = export.NewDirUploader(cfg.ExportDir)
+		default:
+			err = errors.New("ORDERS_EXPORT_S3_BUCKET or ORDERS_EXPORT_DIR is required")
+		}
+		if err == nil {
+			exporter, err = export.New(repo, up, export.Options{
+				Interval:  cfg.ExportInterval,
+				Lag:       cfg.ExportLag,
+				BatchSize: cfg.ExportBatchSize,
+				Format:    cfg.ExportFormat,
+				Columns:   cfg.ExportColumns,
+				Prefix:    cfg.ExportPrefix,
+			})
+		}
+		if err != nil {
+			logger.Error("invalid export config", "err", err)
+			return err
+		}
+	}
+
+	statusBus := statusbus.New(repo.Pool())
+
+	interceptors := []grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(), grpcUnaryLogger(), deadline.UnaryServerInterceptor(cfg.GRPCDefaultDeadline)}
+	shedConfig := loadshed.Config{MinLimit: cfg.LoadShedMinLimit, MaxLimit: cfg.LoadShedMaxLimit}
+	if shedConfig.Enabled() {
+		routes, err := loadshed.ParseRoutes(cfg.LoadShedRoutes)
+		if err != nil {
+			logger.Error("invalid ORDERS_LOADSHED_ROUTES", "err", err)
+			return err
+		}
+		interceptors = append(interceptors, loadshed.UnaryServerInterceptor(loadshed.NewSet("orders-service", shedConfig, routes).Exempt("WaitOrder")))
+		logger.Info("load shedding enabled", "max_limit", cfg.LoadShedMaxLimit, "min_limit", cfg.LoadShedMinLimit, "routes", len(routes))
+	}
+	if serviceAuth.Enabled() {
+		interceptors = append(interceptors, auth.ServiceInterceptor(serviceAuth))
+		logger.Info("service auth enabled", "mode", serviceAuth.Mode)
+	} else {
+		logger.Warn("SERVICE_AUTH_MODE is empty, service auth disabled")
+	}
+	if cfg.JWTSecret != "" {
+		interceptors = append(interceptors, auth.UnaryServerInterceptor([]byte(cfg.JWTSecret)))
+		logger.Info("rbac enabled")
+	} else {
+		logger.Warn("JWT_SECRET is empty, rbac disabled")
+	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	handlers := grpcsvc.NewHandlers(repo, orderCache, payments, prices, cfg.KnownAccountsCheck, currency, cfg.MaxNewOrders, cfg.DuplicateOrderWindow, cfg.MaxPaymentRetries, statusBus)
+	handlers.LimitWaits(cfg.MaxOrderWaits)
+	if cfg.ReceiptPDF {
+		handlers.UseReceiptRenderer("pdf", receipt.PDF{})
+	}
+	ordersv1.RegisterOrdersServiceServer(grpcServer, handlers)
+	if cfg.EnableAdminAPI {
+		admin := grpcsvc.NewAdminHandlers(repo, orderCache, cfg.OutboxReplayMaxEvents, currency)
+		admin.UseInbox(consumer)
+		admin.UseProjections(statusHistory, ordersRead)
+		ordersv1.RegisterOrdersAdminServiceServer(grpcServer, admin)
+		if cfg.JWTSecret == "" {
+			logger.Warn("admin api enabled without JWT_SECRET, it is not access-controlled")
+		}
+		logger.Info("admin api enabled")
+	}
+	if cfg.EnableReflection {
+		reflection.Register(grpcServer)
+		logger.Info("grpc reflection enabled")
+	}
+
+	var archiver *archive.Archiver
+	if cfg.ArchiveAfter > 0 {
+		archiver, err = archive.New(repo, orderCache, cfg.ArchiveAfter, cfg.ArchiveInterval, cfg.ArchiveBatchSize)
+		if err != nil {
+			logger.Error("invalid archive config", "err", err)
+			return err
+		}
+	}
 
 	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
@@ -98,6 +428,57 @@ func Run(ctx context.Context, cfg config.Config) error {
 		return nil
 	})
 
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		mux.Handle("/metrics", promhttp.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		g.Go(func() error {
+			logger.Info("metrics listening", "metrics_addr", cfg.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return metricsServer.Shutdown(shutdownCtx)
+		})
+	}
+
+	if cfg.HTTPAddr != "" {
+		restHandler, err := rest.NewHandler(ctx, cfg.GRPCAddr)
+		if err != nil {
+			logger.Error("failed to create rest handler", "err", err)
+			return err
+		}
+		httpServer := &http.Server{
+			Addr:              cfg.HTTPAddr,
+			Handler:           restHandler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		g.Go(func() error {
+			logger.Info("rest listening", "http_addr", cfg.HTTPAddr)
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			logger.Info("rest shutting down")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return httpServer.Shutdown(shutdownCtx)
+		})
+	}
+
 	g.Go(func() error {
 		err := outbox.Run(ctx)
 		if err != nil {
@@ -113,6 +494,111 @@ func Run(ctx context.Context, cfg config.Config) error {
 		return err
 	})
 
+	if retryPolicy.Enabled() {
+		g.Go(func() error {
+			return retrier.Run(ctx)
+		})
+	}
+
+	g.Go(func() error {
+		return scheduler.Run(ctx)
+	})
+
+	g.Go(func() error {
+		err := accountConsumer.Run(ctx)
+		if err != nil {
+			logger.Error("account created consumer stopped with error", "err", err)
+		}
+		return err
+	})
+
+	g.Go(func() error {
+		err := disputeConsumer.Run(ctx)
+		if err != nil {
+			logger.Error("dispute consumer stopped with error", "err", err)
+		}
+		return err
+	})
+
+	g.Go(func() error {
+		err := projectionConsumer.Run(ctx)
+		if err != nil {
+			logger.Error("projection consumer stopped with error", "err", err)
+		}
+		return err
+	})
+
+	g.Go(func() error {
+		err := ordersReadConsumer.Run(ctx)
+		if err != nil {
+			logger.Error("orders_read consumer stopped with error", "err", err)
+		}
+		return err
+	})
+
+	if cfg.ReplayPollInterval > 0 {
+		replayer := projection.NewReplayer(repo, projection.NewKafkaLog(cfg.KafkaBrokers, kafkaDialer, kafkaTransport), []projection.Projection{statusHistory, ordersRead}, projection.ReplayOptions{
+			PollInterval: cfg.ReplayPollInterval,
+			BatchSize:    cfg.ReplayBatchSize,
+			StaleAfter:   cfg.ReplayStaleAfter,
+			IdleTimeout:  cfg.ReplayIdleTimeout,
+			IdleRetries:  cfg.ReplayIdleRetries,
+		})
+		g.Go(func() error {
+			return replayer.Run(ctx)
+		})
+	}
+
+	g.Go(func() error {
+		return lagReporter.Run(ctx)
+	})
+
+	g.Go(func() error {
+		return statusBus.Run(ctx)
+	})
+
+	if archiver != nil {
+		g.Go(func() error {
+			return archiver.Run(ctx)
+		})
+	}
+
+	if exporter != nil {
+		g.Go(func() error {
+			return exporter.Run(ctx)
+		})
+	}
+
+	if cfg.InboxPayloadRetention > 0 {
+		purger := inbox.NewPurger(pool, cfg.InboxPayloadRetention, "orders-service")
+		g.Go(func() error {
+			return purger.Run(ctx)
+		})
+	}
+
+	if cfg.IdempotencyKeyRetention > 0 {
+		sweeper := idempotency.NewSweeper(pool, cfg.IdempotencyKeyRetention, "orders-service")
+		g.Go(func() error {
+			return sweeper.Run(ctx)
+		})
+	}
+
+	if cfg.OrderStatsInterval > 0 {
+		stats := orderstats.New(repo, cfg.OrderStatsInterval)
+		g.Go(func() error {
+			return stats.Run(ctx)
+		})
+	}
+
+	if cfg.PartitionInterval > 0 {
+		partitions := partition.New(partition.NewPgStore(pool), cfg.PartitionMonthsAhead, cfg.PartitionInterval,
+			slog.Default().With("service", "orders-service", "component", "partition"),
+			partition.Table{Name: "orders", Retention: cfg.PartitionRetention, KeepRows: true})
+		g.Go(func() error {
+			return partitions.Run(ctx)
+		})
+	}
+
 	err = g.Wait()
 	if err != nil {
 		logger.Error("orders service stopped with error", "err", err, "duration", time.Since(start))
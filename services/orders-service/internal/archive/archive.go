@@ -0,0 +1,111 @@
+// Package archive moves finished and cancelled orders out of the hot orders
+// table into orders_archive, where GetOrder and ListOrders (with
+// include_archived) still find them.
+package archive
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Archiver periodically archives orders that reached FINISHED or CANCELLED
+// and have not changed for After. Batches are taken with SKIP LOCKED, so
+// every replica may run one.
+type Archiver struct {
+	repo     repo.OrdersRepository
+	cache    cache.OrderCache
+	after    time.Duration
+	interval time.Duration
+	batch    int
+	logger   *slog.Logger
+}
+
+// New returns the archiver. interval and batch must be positive: a zero
+// batch would never finish a pass and a zero interval cannot tick. cache may
+// be nil; otherwise archived orders and their owners' first list pages are
+// dropped from it, so they show up as archived right away.
+func New(repo repo.OrdersRepository, orderCache cache.OrderCache, after, interval time.Duration, batch int) (*Archiver, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("ORDERS_ARCHIVE_INTERVAL must be positive, got %s", interval)
+	}
+	if batch <= 0 {
+		return nil, fmt.Errorf("ORDERS_ARCHIVE_BATCH_SIZE must be positive, got %d", batch)
+	}
+	logger := slog.Default().With("service", "orders-service", "component", "archive")
+	logger.Info("archiver initialized", "after", after.String(), "interval", interval.String(), "batch", batch)
+	return &Archiver{repo: repo, cache: orderCache, after: after, interval: interval, batch: batch, logger: logger}, nil
+}
+
+func (a *Archiver) Run(ctx context.Context) error {
+	t := time.NewTicker(a.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.logger.Info("archiver stopped")
+			return nil
+		case <-t.C:
+			n, err := a.ArchiveOnce(ctx)
+			if err != nil {
+				a.logger.Error("archive cycle failed", "err", err, "archived", n)
+				continue
+			}
+			if n > 0 {
+				a.logger.Info("orders archived", "archived", n)
+			}
+		}
+	}
+}
+
+// ArchiveOnce archives everything due, one transaction per batch, and
+// returns the number of orders moved.
+func (a *Archiver) ArchiveOnce(ctx context.Context) (int64, error) {
+	before := pgtype.Timestamptz{Time: time.Now().Add(-a.after), Valid: true}
+	var total int64
+	for {
+		var moved []db.ArchiveOrdersRow
+		err := a.repo.InTx(ctx, func(q db.Querier) error {
+			var err error
+			moved, err = q.ArchiveOrders(ctx, db.ArchiveOrdersParams{Before: before, BatchSize: int32(a.batch)})
+			return err
+		})
+		if err != nil {
+			return total, err
+		}
+		a.invalidate(ctx, moved)
+		total += int64(len(moved))
+		if len(moved) < a.batch || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// invalidate drops the moved orders from the cache after their batch
+// committed. A failure only leaves an entry stale until its TTL, so it is
+// logged and the pass goes on.
+func (a *Archiver) invalidate(ctx context.Context, moved []db.ArchiveOrdersRow) {
+	if a.cache == nil {
+		return
+	}
+	users := make(map[string]bool)
+	for _, r := range moved {
+		if err := a.cache.Invalidate(ctx, r.OrderID.String()); err != nil {
+			a.logger.Error("failed to invalidate archived order", "err", err, "order_id", r.OrderID.String())
+		}
+		users[r.UserID] = true
+	}
+	for userID := range users {
+		if err := a.cache.InvalidateList(ctx, userID); err != nil {
+			a.logger.Error("failed to invalidate order list cache", "err", err, "user_id", userID)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+package archive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// fakeRepo holds due orders and archives up to BatchSize of them per call.
+type fakeRepo struct {
+	db.Querier
+	due    int64
+	calls  int
+	before time.Time
+	fail   bool
+	seq    byte
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) ArchiveOrders(_ context.Context, arg db.ArchiveOrdersParams) ([]db.ArchiveOrdersRow, error) {
+	f.calls++
+	f.before = arg.Before.Time
+	if f.fail {
+		return nil, errors.New("boom")
+	}
+	n := min(f.due, int64(arg.BatchSize))
+	f.due -= n
+	rows := make([]db.ArchiveOrdersRow, n)
+	for i := range rows {
+		f.seq++
+		rows[i] = db.ArchiveOrdersRow{OrderID: pgtype.UUID{Bytes: [16]byte{15: f.seq}, Valid: true}, UserID: fmt.Sprintf("u-%d", f.seq%2)}
+	}
+	return rows, nil
+}
+
+func newArchiver(t *testing.T, repo *fakeRepo, orderCache cache.OrderCache) *Archiver {
+	t.Helper()
+	a, err := New(repo, orderCache, 30*24*time.Hour, time.Hour, 10)
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	return a
+}
+
+func TestArchiveOnceDrainsInBatches(t *testing.T) {
+	repo := &fakeRepo{due: 25}
+	a := newArchiver(t, repo, nil)
+
+	n, err := a.ArchiveOnce(context.Background())
+	if err != nil {
+		t.Fatalf("ArchiveOnce() error: %v", err)
+	}
+	if n != 25 || repo.calls != 3 {
+		t.Fatalf("ArchiveOnce() = %d in %d calls, want 25 in 3", n, repo.calls)
+	}
+	if age := time.Since(repo.before); age < 30*24*time.Hour || age > 30*24*time.Hour+time.Minute {
+		t.Fatalf("before is %s ago, want 30 days", age)
+	}
+
+	// Nothing due: one empty batch.
+	repo.calls = 0
+	if n, err := a.ArchiveOnce(context.Background()); err != nil || n != 0 || repo.calls != 1 {
+		t.Fatalf("ArchiveOnce() with nothing due = %d, %v in %d calls", n, err, repo.calls)
+	}
+}
+
+func TestArchiveOnceError(t *testing.T) {
+	repo := &fakeRepo{due: 5, fail: true}
+	if _, err := newArchiver(t, repo, nil).ArchiveOnce(context.Background()); err == nil {
+		t.Fatal("ArchiveOnce() error = nil, want the query error")
+	}
+}
+
+func TestArchiveOnceInvalidatesCache(t *testing.T) {
+	ctx := context.Background()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	repo := &fakeRepo{due: 1}
+	id := pgtype.UUID{Bytes: [16]byte{15: 1}, Valid: true}.String()
+	if err := orderCache.Set(ctx, cache.Order{OrderID: id, UserID: "u-1", Status: "FINISHED"}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	if err := orderCache.SetList(ctx, "u-1", cache.OrderList{Limit: 50, Orders: []cache.Order{{OrderID: id}}}); err != nil {
+		t.Fatalf("SetList() error: %v", err)
+	}
+
+	if n, err := newArchiver(t, repo, orderCache).ArchiveOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("ArchiveOnce() = %d, %v; want 1 order", n, err)
+	}
+	if got, _ := orderCache.Get(ctx, id); got != nil {
+		t.Fatalf("archived order still cached: %+v", got)
+	}
+	if got, _ := orderCache.GetList(ctx, "u-1", 50); got != nil {
+		t.Fatalf("owner's first page still cached: %+v", got)
+	}
+}
+
+func TestNewRejectsZeroIntervalAndBatch(t *testing.T) {
+	if _, err := New(&fakeRepo{}, nil, time.Hour, 0, 10); err == nil {
+		t.Fatal("New() with a zero interval: error = nil")
+	}
+	if _, err := New(&fakeRepo{}, nil, time.Hour, time.Hour, 0); err == nil {
+		t.Fatal("New() with a zero batch: error = nil")
+	}
+}
@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+var secret = []byte("test-secret")
+
+func mustSign(t *testing.T, c jwt.Claims) string {
+	t.Helper()
+	if c.ExpiresAt == 0 {
+		c.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	}
+	token, err := jwt.Sign(c, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	return token
+}
+
+func call(t *testing.T, method, token string, req interface{}) codes.Code {
+	t.Helper()
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(userauth.MetadataKey, "Bearer "+token))
+	}
+	intercept := UnaryServerInterceptor(secret)
+	_, err := intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := userauth.FromContext(ctx); !ok && strings.HasPrefix(method, servicePrefix) {
+			t.Fatal("handler called without claims in context")
+		}
+		return nil, nil
+	})
+	return status.Code(err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user := mustSign(t, jwt.Claims{Subject: "u-1", Roles: []jwt.Role{jwt.RoleUser}})
+	support := mustSign(t, jwt.Claims{Subject: "s-1", Roles: []jwt.Role{jwt.RoleSupport}})
+	admin := mustSign(t, jwt.Claims{Subject: "a-1", Roles: []jwt.Role{jwt.RoleAdmin}})
+	create := ordersv1.OrdersService_CreateOrder_FullMethodName
+	get := ordersv1.OrdersService_GetOrder_FullMethodName
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		req    interface{}
+		want   codes.Code
+	}{
+		{"missing token", create, "", &ordersv1.CreateOrderRequest{UserId: "u-1"}, codes.Unauthenticated},
+		{"garbage token", create, "x.y.z", &ordersv1.CreateOrderRequest{UserId: "u-1"}, codes.Unauthenticated},
+		{"own order", create, user, &ordersv1.CreateOrderRequest{UserId: "u-1"}, codes.OK},
+		{"other user", get, user, &ordersv1.GetOrderRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"support reads any user", get, support, &ordersv1.GetOrderRequest{UserId: "u-2"}, codes.OK},
+		{"support waits on any user", ordersv1.OrdersService_WaitOrder_FullMethodName, support, &ordersv1.WaitOrderRequest{UserId: "u-2"}, codes.OK},
+		{"support cannot create", create, support, &ordersv1.CreateOrderRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"own dry run", ordersv1.OrdersService_ValidateOrder_FullMethodName, user, &ordersv1.CreateOrderRequest{UserId: "u-1"}, codes.OK},
+		{"support cannot dry run", ordersv1.OrdersService_ValidateOrder_FullMethodName, support, &ordersv1.CreateOrderRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"support cannot update", ordersv1.OrdersService_UpdateOrder_FullMethodName, support, &ordersv1.UpdateOrderRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"accept for another user", ordersv1.OrdersService_AcceptOrderTransfer_FullMethodName, user, &ordersv1.AcceptOrderTransferRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"support cannot transfer", ordersv1.OrdersService_TransferOrder_FullMethodName, support, &ordersv1.TransferOrderRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"unlisted rpc", "/orders.v1.OrdersService/Drop", user, nil, codes.PermissionDenied},
+		{"replay needs admin", ordersv1.OrdersAdminService_ReplayOutbox_FullMethodName, support, &ordersv1.ReplayOutboxRequest{}, codes.PermissionDenied},
+		{"admin replays", ordersv1.OrdersAdminService_ReplayOutbox_FullMethodName, admin, &ordersv1.ReplayOutboxRequest{}, codes.OK},
+		{"dead outbox needs admin", ordersv1.OrdersAdminService_ListDeadOutbox_FullMethodName, support, &ordersv1.ListDeadOutboxRequest{}, codes.PermissionDenied},
+		{"admin lists dead outbox", ordersv1.OrdersAdminService_ListDeadOutbox_FullMethodName, admin, &ordersv1.ListDeadOutboxRequest{}, codes.OK},
+		{"support browses outbox", ordersv1.OrdersAdminService_ListOutbox_FullMethodName, support, &ordersv1.ListOutboxRequest{}, codes.OK},
+		{"support inspects orders", ordersv1.OrdersAdminService_InspectOrder_FullMethodName, support, &ordersv1.InspectOrderRequest{}, codes.OK},
+		{"force status needs admin", ordersv1.OrdersAdminService_ForceOrderStatus_FullMethodName, support, &ordersv1.ForceOrderStatusRequest{}, codes.PermissionDenied},
+		{"health is not covered", "/grpc.health.v1.Health/Check", "", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		if got := call(t, tt.method, tt.token, tt.req); got != tt.want {
+			t.Fatalf("%s: code = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMethodCallers(t *testing.T) {
+	for method := range methodRoles {
+		if len(methodCallers[method]) == 0 {
+			t.Errorf("%s has roles but no allowed services", method)
+		}
+	}
+	for method := range methodCallers {
+		if _, ok := methodRoles[method]; !ok {
+			t.Errorf("%s has allowed services but no roles", method)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"google.golang.org/grpc"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+// Service identities of the callers, as named in SERVICE_AUTH_TOKENS or in
+// the SPIFFE ids of their tokens.
+const (
+	serviceName    = "orders-service"
+	callerGW       = "api-gateway"
+	callerPayments = "payments-service"
+	callerCtl      = "paymentsctl"
+)
+
+// methodCallers lists the services allowed to call each RPC when service
+// authentication is on. RPCs missing from this table are denied to every
+// service, orders-service included.
+var methodCallers = map[string][]string{
+	ordersv1.OrdersService_CreateOrder_FullMethodName:         {callerGW},
+	ordersv1.OrdersService_ValidateOrder_FullMethodName:       {callerGW},
+	ordersv1.OrdersService_PayOrder_FullMethodName:            {callerGW},
+	ordersv1.OrdersService_UpdateOrder_FullMethodName:         {callerGW},
+	ordersv1.OrdersService_TransferOrder_FullMethodName:       {callerGW},
+	ordersv1.OrdersService_AcceptOrderTransfer_FullMethodName: {callerGW},
+	ordersv1.OrdersService_CancelOrder_FullMethodName:         {callerGW},
+	ordersv1.OrdersService_RetryPayment_FullMethodName:        {callerGW},
+	ordersv1.OrdersService_ListOrders_FullMethodName:          {callerGW},
+	ordersv1.OrdersService_GetOrder_FullMethodName:            {callerGW, callerPayments},
+	ordersv1.OrdersService_GetOrders_FullMethodName:           {callerGW},
+	ordersv1.OrdersService_WaitOrder_FullMethodName:           {callerGW},
+	ordersv1.OrdersService_GetOrderReceipt_FullMethodName:     {callerGW},
+
+	ordersv1.OrdersAdminService_ReplayOutbox_FullMethodName:          {callerCtl},
+	ordersv1.OrdersAdminService_ListDeadOutbox_FullMethodName:        {callerCtl},
+	ordersv1.OrdersAdminService_ListOutbox_FullMethodName:            {callerCtl},
+	ordersv1.OrdersAdminService_RequeueDeadOutbox_FullMethodName:     {callerCtl},
+	ordersv1.OrdersAdminService_InspectOrder_FullMethodName:          {callerCtl},
+	ordersv1.OrdersAdminService_ForceOrderStatus_FullMethodName:      {callerCtl},
+	ordersv1.OrdersAdminService_DryRunInboxMessage_FullMethodName:    {callerCtl},
+	ordersv1.OrdersAdminService_CreatePromoCode_FullMethodName:       {callerCtl},
+	ordersv1.OrdersAdminService_GetPromoCode_FullMethodName:          {callerCtl},
+	ordersv1.OrdersAdminService_StartProjectionReplay_FullMethodName: {callerCtl},
+	ordersv1.OrdersAdminService_StopProjectionReplay_FullMethodName:  {callerCtl},
+	ordersv1.OrdersAdminService_GetProjectionReplay_FullMethodName:   {callerCtl},
+}
+
+// ServiceInterceptor checks the service token of every call against
+// methodCallers.
+func ServiceInterceptor(cfg svcauth.Config) grpc.UnaryServerInterceptor {
+	return svcauth.UnaryServerInterceptor(cfg, serviceName, servicePrefix, methodCallers)
+}
+
+// ServiceCredentials attaches the token of orders-service to its calls of
+// other services.
+func ServiceCredentials(cfg svcauth.Config) (grpc.UnaryClientInterceptor, error) {
+	return svcauth.UnaryClientInterceptor(cfg, serviceName)
+}
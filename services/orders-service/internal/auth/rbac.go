@@ -0,0 +1,53 @@
+package auth
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// servicePrefix marks the RPCs covered by methodRoles; anything else (health,
+// reflection) is not subject to RBAC.
+const servicePrefix = "/orders.v1."
+
+// methodRoles lists the roles allowed to call each RPC. RPCs of the service
+// missing from this table are denied.
+var methodRoles = map[string][]jwt.Role{
+	ordersv1.OrdersService_CreateOrder_FullMethodName:         {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_ValidateOrder_FullMethodName:       {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_PayOrder_FullMethodName:            {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_UpdateOrder_FullMethodName:         {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_TransferOrder_FullMethodName:       {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_AcceptOrderTransfer_FullMethodName: {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_CancelOrder_FullMethodName:         {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_RetryPayment_FullMethodName:        {jwt.RoleUser, jwt.RoleAdmin},
+	ordersv1.OrdersService_ListOrders_FullMethodName:          {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersService_GetOrder_FullMethodName:            {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersService_GetOrders_FullMethodName:           {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersService_WaitOrder_FullMethodName:           {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersService_GetOrderReceipt_FullMethodName:     {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+
+	ordersv1.OrdersAdminService_ReplayOutbox_FullMethodName:          {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_ListDeadOutbox_FullMethodName:        {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_ListOutbox_FullMethodName:            {jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_RequeueDeadOutbox_FullMethodName:     {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_InspectOrder_FullMethodName:          {jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_ForceOrderStatus_FullMethodName:      {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_DryRunInboxMessage_FullMethodName:    {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_CreatePromoCode_FullMethodName:       {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_GetPromoCode_FullMethodName:          {jwt.RoleSupport, jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_StartProjectionReplay_FullMethodName: {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_StopProjectionReplay_FullMethodName:  {jwt.RoleAdmin},
+	ordersv1.OrdersAdminService_GetProjectionReplay_FullMethodName:   {jwt.RoleSupport, jwt.RoleAdmin},
+}
+
+// UnaryServerInterceptor enforces methodRoles using the bearer token from the
+// authorization metadata. Plain users may only act on their own user_id.
+func UnaryServerInterceptor(secret []byte) grpc.UnaryServerInterceptor {
+	logger := slog.Default().With("service", "orders-service", "component", "auth")
+	return userauth.UnaryServerInterceptor(secret, servicePrefix, methodRoles, logger)
+}
@@ -0,0 +1,104 @@
+package cache
+
+import (
+	"context"
+	"expvar"
+	"time"
+)
+
+// OrderCache caches single orders by id and the first ListOrders page by
+// user id. Implementations treat a miss as (nil, nil) and never fail the
+// caller's request on their own.
+type OrderCache interface {
+	Get(ctx context.Context, orderID string) (*Order, error)
+	// GetMany looks up several entries in one round trip; misses are absent
+	// from the result.
+	GetMany(ctx context.Context, orderIDs []string) (map[string]*Order, error)
+	Set(ctx context.Context, order Order) error
+	// Invalidate drops the order, for changes made outside the handlers
+	// that keep it up to date.
+	Invalidate(ctx context.Context, orderID string) error
+
+	// GetList returns the cached first page only if it was stored for the
+	// same limit.
+	GetList(ctx context.Context, userID string, limit int32) (*OrderList, error)
+	SetList(ctx context.Context, userID string, list OrderList) error
+	InvalidateList(ctx context.Context, userID string) error
+}
+
+type Order struct {
+	OrderID     string    `json:"order_id"`
+	UserID      string    `json:"user_id"`
+	Amount      int64     `json:"amount"`
+	Description string    `json:"description"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	PaymentFailureReason string `json:"payment_failure_reason,omitempty"`
+	PaidAmount           int64  `json:"paid_amount,omitempty"`
+	FeeAmount            int64  `json:"fee_amount,omitempty"`
+
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Tags     []string          `json:"tags,omitempty"`
+
+	Version   int64      `json:"version,omitempty"`
+	UpdatedAt time.Time  `json:"updated_at"`
+	PayAt     *time.Time `json:"pay_at,omitempty"`
+
+	Archived bool `json:"archived,omitempty"`
+
+	// Only set in cached ListOrders pages.
+	LastPaymentReason string `json:"last_payment_reason,omitempty"`
+	ItemCount         int32  `json:"item_count,omitempty"`
+
+	Dispute *OrderDispute `json:"dispute,omitempty"`
+}
+
+// OrderDispute is the payment dispute of a cached order.
+type OrderDispute struct {
+	Status     string     `json:"status"`
+	Amount     int64      `json:"amount"`
+	Reason     string     `json:"reason"`
+	OpenedAt   time.Time  `json:"opened_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+}
+
+// OrderList is the first ListOrders page of a user.
+type OrderList struct {
+	Limit         int32   `json:"limit"`
+	Orders        []Order `json:"orders"`
+	NextPageToken string  `json:"next_page_token,omitempty"`
+}
+
+var listMetrics = expvar.NewMap("order_list_cache")
+
+func init() {
+	listMetrics.Set("hit_rate", expvar.Func(func() any {
+		hits, misses := metricValue("hits"), metricValue("misses")
+		if hits+misses == 0 {
+			return 0.0
+		}
+		return float64(hits) / float64(hits+misses)
+	}))
+}
+
+func metricValue(name string) int64 {
+	if v, ok := listMetrics.Get(name).(*expvar.Int); ok {
+		return v.Value()
+	}
+	return 0
+}
+
+func recordListLookup(hit bool) {
+	if hit {
+		listMetrics.Add("hits", 1)
+		return
+	}
+	listMetrics.Add("misses", 1)
+}
+
+var (
+	_ OrderCache = (*RedisOrderCache)(nil)
+	_ OrderCache = (*MemoryOrderCache)(nil)
+	_ OrderCache = (*faultyCache)(nil)
+)
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+)
+
+// WithFaults returns c delaying or failing calls as f decides, the way an
+// unreachable Redis would; callers already treat cache errors as misses. A
+// nil f returns c as is.
+func WithFaults(c OrderCache, f *chaos.Injector) OrderCache {
+	if c == nil || f == nil {
+		return c
+	}
+	return &faultyCache{c: c, faults: f}
+}
+
+type faultyCache struct {
+	c      OrderCache
+	faults *chaos.Injector
+}
+
+func (fc *faultyCache) Get(ctx context.Context, orderID string) (*Order, error) {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return fc.c.Get(ctx, orderID)
+}
+
+func (fc *faultyCache) GetMany(ctx context.Context, orderIDs []string) (map[string]*Order, error) {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return fc.c.GetMany(ctx, orderIDs)
+}
+
+func (fc *faultyCache) Set(ctx context.Context, order Order) error {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fc.c.Set(ctx, order)
+}
+
+func (fc *faultyCache) Invalidate(ctx context.Context, orderID string) error {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fc.c.Invalidate(ctx, orderID)
+}
+
+func (fc *faultyCache) GetList(ctx context.Context, userID string, limit int32) (*OrderList, error) {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return fc.c.GetList(ctx, userID, limit)
+}
+
+func (fc *faultyCache) SetList(ctx context.Context, userID string, list OrderList) error {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fc.c.SetList(ctx, userID, list)
+}
+
+func (fc *faultyCache) InvalidateList(ctx context.Context, userID string) error {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fc.c.InvalidateList(ctx, userID)
+}
@@ -0,0 +1,35 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+)
+
+func TestWithFaultsNilInjectorKeepsCache(t *testing.T) {
+	c := NewMemoryOrderCache(10, time.Minute)
+	if got := WithFaults(c, nil); got != OrderCache(c) {
+		t.Fatal("WithFaults(c, nil) wrapped the cache")
+	}
+	if got := WithFaults(nil, chaos.New("cache", chaos.Config{ErrorRate: 1})); got != nil {
+		t.Fatal("WithFaults(nil, f) returned a cache")
+	}
+}
+
+func TestWithFaultsFailsCalls(t *testing.T) {
+	ctx := context.Background()
+	inner := NewMemoryOrderCache(10, time.Minute)
+	if err := inner.Set(ctx, Order{OrderID: "o1", UserID: "u1"}); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	c := WithFaults(inner, chaos.New("cache", chaos.Config{ErrorRate: 1}))
+	if _, err := c.Get(ctx, "o1"); !errors.Is(err, chaos.ErrInjected) {
+		t.Fatalf("Get() error = %v, want ErrInjected", err)
+	}
+	if err := c.InvalidateList(ctx, "u1"); !errors.Is(err, chaos.ErrInjected) {
+		t.Fatalf("InvalidateList() error = %v, want ErrInjected", err)
+	}
+}
@@ -0,0 +1,71 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/lru"
+)
+
+// MemoryOrderCache is an in-process OrderCache used when Redis is not
+// configured. Each instance has its own copy, so entries are only as fresh
+// as the TTL allows.
+type MemoryOrderCache struct {
+	entries *lru.Cache[Order]
+	lists   *lru.Cache[OrderList]
+}
+
+func NewMemoryOrderCache(size int, ttl time.Duration) *MemoryOrderCache {
+	slog.Default().With("service", "orders-service", "component", "cache").Info("in-memory order cache initialized", "size", size, "ttl", ttl.String())
+	return &MemoryOrderCache{entries: lru.New[Order](size, ttl), lists: lru.New[OrderList](size, ttl)}
+}
+
+func (c *MemoryOrderCache) Get(_ context.Context, orderID string) (*Order, error) {
+	order, ok := c.entries.Get(orderID)
+	if !ok {
+		return nil, nil
+	}
+	return &order, nil
+}
+
+func (c *MemoryOrderCache) GetMany(_ context.Context, orderIDs []string) (map[string]*Order, error) {
+	out := make(map[string]*Order, len(orderIDs))
+	for _, id := range orderIDs {
+		if v, ok := c.entries.Get(id); ok {
+			out[id] = &v
+		}
+	}
+	return out, nil
+}
+
+func (c *MemoryOrderCache) Set(_ context.Context, order Order) error {
+	c.entries.Set(order.OrderID, order)
+	return nil
+}
+
+func (c *MemoryOrderCache) Invalidate(_ context.Context, orderID string) error {
+	c.entries.Remove(orderID)
+	return nil
+}
+
+func (c *MemoryOrderCache) GetList(_ context.Context, userID string, limit int32) (*OrderList, error) {
+	list, ok := c.lists.Get(userID)
+	if !ok || list.Limit != limit {
+		recordListLookup(false)
+		return nil, nil
+	}
+	recordListLookup(true)
+	return &list, nil
+}
+
+func (c *MemoryOrderCache) SetList(_ context.Context, userID string, list OrderList) error {
+	c.lists.Set(userID, list)
+	return nil
+}
+
+func (c *MemoryOrderCache) InvalidateList(_ context.Context, userID string) error {
+	listMetrics.Add("invalidations", 1)
+	c.lists.Remove(userID)
+	return nil
+}
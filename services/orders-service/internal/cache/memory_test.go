@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryOrderCache(t *testing.T) {
+	c := NewMemoryOrderCache(10, time.Minute)
+	ctx := context.Background()
+	if got, err := c.Get(ctx, "order-1"); err != nil || got != nil {
+		t.Fatalf("Get() on empty cache = (%v, %v), want (nil, nil)", got, err)
+	}
+	if err := c.Set(ctx, Order{OrderID: "order-1", Amount: 10}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	got, err := c.Get(ctx, "order-1")
+	if err != nil || got == nil || got.Amount != 10 {
+		t.Fatalf("Get() = (%v, %v), want order with amount 10", got, err)
+	}
+}
+
+func TestMemoryOrderCacheList(t *testing.T) {
+	c := NewMemoryOrderCache(10, time.Minute)
+	ctx := context.Background()
+	list := OrderList{Limit: 2, Orders: []Order{{OrderID: "o-2"}, {OrderID: "o-1"}}, NextPageToken: "2"}
+	if err := c.SetList(ctx, "u-1", list); err != nil {
+		t.Fatalf("SetList() error: %v", err)
+	}
+
+	got, err := c.GetList(ctx, "u-1", 2)
+	if err != nil || got == nil || len(got.Orders) != 2 || got.NextPageToken != "2" {
+		t.Fatalf("GetList() = (%v, %v), want the stored page", got, err)
+	}
+	if got, _ := c.GetList(ctx, "u-1", 50); got != nil {
+		t.Fatalf("GetList() with another limit = %v, want miss", got)
+	}
+
+	if err := c.InvalidateList(ctx, "u-1"); err != nil {
+		t.Fatalf("InvalidateList() error: %v", err)
+	}
+	if got, _ := c.GetList(ctx, "u-1", 2); got != nil {
+		t.Fatalf("GetList() after invalidate = %v, want miss", got)
+	}
+}
+
+func TestMemoryOrderCacheGetMany(t *testing.T) {
+	c := NewMemoryOrderCache(10, time.Minute)
+	ctx := context.Background()
+	if err := c.Set(ctx, Order{OrderID: "a", Amount: 1}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	got, err := c.GetMany(ctx, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("GetMany() error: %v", err)
+	}
+	if len(got) != 1 || got["a"] == nil || got["a"].OrderID != "a" {
+		t.Fatalf("GetMany() = %v, want only a", got)
+	}
+
+	if err := c.Invalidate(ctx, "a"); err != nil {
+		t.Fatalf("Invalidate() error: %v", err)
+	}
+	if got, _ := c.Get(ctx, "a"); got != nil {
+		t.Fatalf("Get() after invalidate = %v, want miss", got)
+	}
+}
@@ -7,61 +7,133 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
-)
 
-type OrderCache struct {
-	client *redis.Client
-	ttl    time.Duration
-}
+	"github.com/ilyaytrewq/payments-service/pkg/breaker"
+)
 
-type Order struct {
-	OrderID     string    `json:"order_id"`
-	UserID      string    `json:"user_id"`
-	Amount      int64     `json:"amount"`
-	Description string    `json:"description"`
-	Status      string    `json:"status"`
-	CreatedAt   time.Time `json:"created_at"`
+// RedisOrderCache is the OrderCache shared by all instances through Redis.
+type RedisOrderCache struct {
+	client  *redis.Client
+	ttl     time.Duration
+	breaker *breaker.Breaker
+	// pending holds the keys whose write or delete was skipped or failed;
+	// they are deleted before the first call once Redis is back.
+	pending *breaker.Pending
 }
 
-func NewOrderCache(client *redis.Client, ttl time.Duration) *OrderCache {
+func NewRedisOrderCache(client *redis.Client, ttl time.Duration, breakerThreshold int, breakerCooldown time.Duration) *RedisOrderCache {
 	if client == nil {
 		slog.Default().With("service", "orders-service", "component", "cache").Info("order cache disabled")
 		return nil
 	}
 	slog.Default().With("service", "orders-service", "component", "cache").Info("order cache initialized", "ttl", ttl.String())
-	return &OrderCache{client: client, ttl: ttl}
+	return &RedisOrderCache{
+		client:  client,
+		ttl:     ttl,
+		breaker: breaker.New("order", breakerThreshold, breakerCooldown, slog.Default().With("service", "orders-service", "component", "cache")),
+		pending: breaker.NewPending("order", ttl),
+	}
+}
+
+// allow reports whether Redis may be called. Keys left stale while it was
+// unreachable are deleted first, so no call can read them; if that fails,
+// Redis is still treated as down.
+func (c *RedisOrderCache) allow(ctx context.Context) bool {
+	if !c.breaker.Allow() {
+		return false
+	}
+	keys := c.pending.Take()
+	if len(keys) == 0 {
+		return true
+	}
+	logger := slog.Default().With("service", "orders-service", "component", "cache")
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.breaker.Failure()
+		for _, k := range keys {
+			c.pending.Add(k)
+		}
+		logger.Error("order cache pending invalidations failed", "keys", len(keys), "err", err)
+		return false
+	}
+	logger.Info("order cache pending invalidations applied", "keys", len(keys))
+	return true
 }
 
-func (c *OrderCache) Get(ctx context.Context, orderID string) (*Order, error) {
+func (c *RedisOrderCache) Get(ctx context.Context, orderID string) (*Order, error) {
 	start := time.Now()
 	logger := slog.Default().With("service", "orders-service", "component", "cache")
 	if c == nil {
-		logger.Info("order cache get skipped (nil cache)", "order_id", orderID)
+		logger.Debug("order cache get skipped (nil cache)", "order_id", orderID)
+		return nil, nil
+	}
+	if !c.allow(ctx) {
+		logger.Debug("order cache get skipped (circuit open)", "order_id", orderID)
 		return nil, nil
 	}
 	val, err := c.client.Get(ctx, key(orderID)).Result()
 	if err == redis.Nil {
-		logger.Info("order cache miss", "order_id", orderID, "duration", time.Since(start))
+		c.breaker.Success()
+		logger.Debug("order cache miss", "order_id", orderID, "duration", time.Since(start))
 		return nil, nil
 	}
 	if err != nil {
+		c.breaker.Failure()
 		logger.Error("order cache get failed", "order_id", orderID, "err", err, "duration", time.Since(start))
 		return nil, err
 	}
+	c.breaker.Success()
 	var cached Order
 	if err := json.Unmarshal([]byte(val), &cached); err != nil {
 		logger.Error("order cache unmarshal failed", "order_id", orderID, "err", err, "duration", time.Since(start))
 		return nil, err
 	}
-	logger.Info("order cache hit", "order_id", orderID, "duration", time.Since(start))
+	logger.Debug("order cache hit", "order_id", orderID, "duration", time.Since(start))
 	return &cached, nil
 }
 
-func (c *OrderCache) Set(ctx context.Context, order Order) error {
+func (c *RedisOrderCache) GetMany(ctx context.Context, orderIDs []string) (map[string]*Order, error) {
+	start := time.Now()
+	logger := slog.Default().With("service", "orders-service", "component", "cache")
+	out := make(map[string]*Order, len(orderIDs))
+	if c == nil || len(orderIDs) == 0 {
+		return out, nil
+	}
+	if !c.allow(ctx) {
+		logger.Debug("order cache mget skipped (circuit open)", "keys", len(orderIDs))
+		return out, nil
+	}
+	keys := make([]string, len(orderIDs))
+	for i, id := range orderIDs {
+		keys[i] = key(id)
+	}
+	vals, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.breaker.Failure()
+		logger.Error("order cache mget failed", "keys", len(orderIDs), "err", err, "duration", time.Since(start))
+		return out, err
+	}
+	c.breaker.Success()
+	for i, v := range vals {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var cached Order
+		if err := json.Unmarshal([]byte(raw), &cached); err != nil {
+			logger.Error("order cache unmarshal failed", "order_id", orderIDs[i], "err", err)
+			continue
+		}
+		out[orderIDs[i]] = &cached
+	}
+	logger.Debug("order cache mget", "keys", len(orderIDs), "hits", len(out), "duration", time.Since(start))
+	return out, nil
+}
+
+func (c *RedisOrderCache) Set(ctx context.Context, order Order) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "orders-service", "component", "cache")
 	if c == nil {
-		logger.Info("order cache set skipped (nil cache)", "order_id", order.OrderID)
+		logger.Debug("order cache set skipped (nil cache)", "order_id", order.OrderID)
 		return nil
 	}
 	data, err := json.Marshal(order)
@@ -69,15 +141,128 @@ func (c *OrderCache) Set(ctx context.Context, order Order) error {
 		logger.Error("order cache marshal failed", "order_id", order.OrderID, "err", err, "duration", time.Since(start))
 		return err
 	}
+	if !c.allow(ctx) {
+		c.pending.Add(key(order.OrderID))
+		logger.Debug("order cache set skipped (circuit open)", "order_id", order.OrderID)
+		return nil
+	}
 	if err := c.client.Set(ctx, key(order.OrderID), data, c.ttl).Err(); err != nil {
+		c.breaker.Failure()
+		c.pending.Add(key(order.OrderID))
 		logger.Error("order cache set failed", "order_id", order.OrderID, "err", err, "duration", time.Since(start))
 		return err
 	}
-	logger.Info("order cache set", "order_id", order.OrderID, "duration", time.Since(start))
+	c.breaker.Success()
+	logger.Debug("order cache set", "order_id", order.OrderID, "duration", time.Since(start))
+	return nil
+}
+
+func (c *RedisOrderCache) GetList(ctx context.Context, userID string, limit int32) (*OrderList, error) {
+	start := time.Now()
+	logger := slog.Default().With("service", "orders-service", "component", "cache")
+	if c == nil {
+		return nil, nil
+	}
+	if !c.allow(ctx) {
+		logger.Debug("order list cache get skipped (circuit open)", "user_id", userID)
+		return nil, nil
+	}
+	val, err := c.client.Get(ctx, listKey(userID)).Result()
+	if err == redis.Nil {
+		c.breaker.Success()
+		recordListLookup(false)
+		logger.Debug("order list cache miss", "user_id", userID, "duration", time.Since(start))
+		return nil, nil
+	}
+	if err != nil {
+		c.breaker.Failure()
+		logger.Error("order list cache get failed", "user_id", userID, "err", err, "duration", time.Since(start))
+		return nil, err
+	}
+	c.breaker.Success()
+	var cached OrderList
+	if err := json.Unmarshal([]byte(val), &cached); err != nil {
+		logger.Error("order list cache unmarshal failed", "user_id", userID, "err", err, "duration", time.Since(start))
+		return nil, err
+	}
+	if cached.Limit != limit {
+		recordListLookup(false)
+		logger.Debug("order list cache miss (limit)", "user_id", userID, "cached_limit", cached.Limit, "limit", limit)
+		return nil, nil
+	}
+	recordListLookup(true)
+	logger.Debug("order list cache hit", "user_id", userID, "duration", time.Since(start))
+	return &cached, nil
+}
+
+func (c *RedisOrderCache) SetList(ctx context.Context, userID string, list OrderList) error {
+	start := time.Now()
+	logger := slog.Default().With("service", "orders-service", "component", "cache")
+	if c == nil {
+		return nil
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		logger.Error("order list cache marshal failed", "user_id", userID, "err", err)
+		return err
+	}
+	if !c.allow(ctx) {
+		c.pending.Add(listKey(userID))
+		logger.Debug("order list cache set skipped (circuit open)", "user_id", userID)
+		return nil
+	}
+	if err := c.client.Set(ctx, listKey(userID), data, c.ttl).Err(); err != nil {
+		c.breaker.Failure()
+		c.pending.Add(listKey(userID))
+		logger.Error("order list cache set failed", "user_id", userID, "err", err, "duration", time.Since(start))
+		return err
+	}
+	c.breaker.Success()
+	logger.Debug("order list cache set", "user_id", userID, "orders", len(list.Orders), "duration", time.Since(start))
+	return nil
+}
+
+// Invalidate deletes the entry of orderID. While the circuit is open, or
+// when the delete fails, the key is kept pending and deleted on recovery.
+func (c *RedisOrderCache) Invalidate(ctx context.Context, orderID string) error {
+	if c == nil {
+		return nil
+	}
+	return c.invalidate(ctx, key(orderID), "order cache", "order_id", orderID)
+}
+
+// InvalidateList deletes the cached first page of userID the same way as
+// Invalidate.
+func (c *RedisOrderCache) InvalidateList(ctx context.Context, userID string) error {
+	if c == nil {
+		return nil
+	}
+	listMetrics.Add("invalidations", 1)
+	return c.invalidate(ctx, listKey(userID), "order list cache", "user_id", userID)
+}
+
+func (c *RedisOrderCache) invalidate(ctx context.Context, k, what, idKey, id string) error {
+	logger := slog.Default().With("service", "orders-service", "component", "cache")
+	if !c.allow(ctx) {
+		c.pending.Add(k)
+		logger.Debug(what+" invalidate deferred (circuit open)", idKey, id)
+		return nil
+	}
+	if err := c.client.Del(ctx, k).Err(); err != nil {
+		c.breaker.Failure()
+		c.pending.Add(k)
+		logger.Error(what+" invalidate failed", idKey, id, "err", err)
+		return err
+	}
+	c.breaker.Success()
+	logger.Debug(what+" invalidated", idKey, id)
 	return nil
 }
 
 func key(orderID string) string {
-	slog.Default().With("service", "orders-service", "component", "cache").Info("order cache key generated", "order_id", orderID)
 	return "orders:order:" + orderID
 }
+
+func listKey(userID string) string {
+	return "orders:list:" + userID
+}
@@ -2,23 +2,29 @@ package cache
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-func TestNewOrderCacheNilClient(t *testing.T) {
-	if got := NewOrderCache(nil, time.Second); got != nil {
-		t.Fatal("NewOrderCache(nil) should return nil")
+func TestNewRedisOrderCacheNilClient(t *testing.T) {
+	if got := NewRedisOrderCache(nil, time.Second, 5, time.Second); got != nil {
+		t.Fatal("NewRedisOrderCache(nil) should return nil")
 	}
 }
 
-func TestOrderCacheNilReceiver(t *testing.T) {
-	var c *OrderCache
+func TestRedisOrderCacheNilReceiver(t *testing.T) {
+	var c *RedisOrderCache
 	if got, err := c.Get(context.Background(), "order-1"); err != nil || got != nil {
-		t.Fatalf("OrderCache.Get(nil) = (%v, %v), want (nil, nil)", got, err)
+		t.Fatalf("RedisOrderCache.Get(nil) = (%v, %v), want (nil, nil)", got, err)
+	}
+	if got, err := c.GetMany(context.Background(), []string{"a"}); err != nil || len(got) != 0 {
+		t.Fatalf("RedisOrderCache.GetMany(nil) = (%v, %v), want empty", got, err)
 	}
 	if err := c.Set(context.Background(), Order{OrderID: "order-1"}); err != nil {
-		t.Fatalf("OrderCache.Set(nil) error: %v", err)
+		t.Fatalf("RedisOrderCache.Set(nil) error: %v", err)
 	}
 }
 
@@ -27,3 +33,33 @@ func TestOrderCacheKey(t *testing.T) {
 		t.Fatalf("key() = %q, want %q", got, "orders:order:order-123")
 	}
 }
+
+func TestOrderListCacheKey(t *testing.T) {
+	if got := listKey("user-1"); got != "orders:list:user-1" {
+		t.Fatalf("listKey() = %q, want %q", got, "orders:list:user-1")
+	}
+}
+
+func TestRedisOrderCacheInvalidateWhileDown(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+	c := NewRedisOrderCache(client, time.Minute, 1, time.Minute)
+	ctx := context.Background()
+
+	if err := c.Invalidate(ctx, "order-1"); err == nil {
+		t.Fatal("Invalidate() with Redis down: want an error")
+	}
+	// The circuit is open now: the delete is not attempted, only recorded.
+	start := time.Now()
+	if err := c.InvalidateList(ctx, "user-1"); err != nil {
+		t.Fatalf("InvalidateList() with the circuit open: %v", err)
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Fatalf("InvalidateList() with the circuit open took %v", d)
+	}
+	got := c.pending.Take()
+	slices.Sort(got)
+	if want := []string{listKey("user-1"), key("order-1")}; !slices.Equal(got, want) {
+		t.Fatalf("pending = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,118 @@
+package catalog
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnknownProduct is returned when the catalog has no price for a product.
+var ErrUnknownProduct = errors.New("unknown product")
+
+// PriceResolver looks up the unit price of a product in minimal currency units.
+type PriceResolver interface {
+	Price(ctx context.Context, productID string) (int64, error)
+}
+
+// StaticResolver serves prices from an in-memory table.
+type StaticResolver struct {
+	prices map[string]int64
+}
+
+func NewStaticResolver(prices map[string]int64) *StaticResolver {
+	logger := slog.Default().With("service", "orders-service", "component", "catalog")
+	logger.Info("static price resolver initialized", "products", len(prices))
+	return &StaticResolver{prices: prices}
+}
+
+func (r *StaticResolver) Price(_ context.Context, productID string) (int64, error) {
+	price, ok := r.prices[productID]
+	if !ok {
+		return 0, ErrUnknownProduct
+	}
+	return price, nil
+}
+
+// ParsePrices parses a "product=price,product=price" table as used by
+// ORDERS_CATALOG_PRICES.
+func ParsePrices(s string) (map[string]int64, error) {
+	prices := make(map[string]int64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, raw, ok := strings.Cut(pair, "=")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			return nil, fmt.Errorf("invalid catalog entry %q", pair)
+		}
+		price, err := strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("invalid price for product %q", id)
+		}
+		prices[id] = price
+	}
+	return prices, nil
+}
+
+// HTTPResolver asks a catalog service for prices via GET {baseURL}/products/{id},
+// which is expected to answer with {"price": <int64>}.
+type HTTPResolver struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewHTTPResolver(baseURL string, timeout time.Duration) *HTTPResolver {
+	logger := slog.Default().With("service", "orders-service", "component", "catalog")
+	logger.Info("http price resolver initialized", "base_url", baseURL, "timeout", timeout)
+	return &HTTPResolver{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (r *HTTPResolver) Price(ctx context.Context, productID string) (int64, error) {
+	logger := slog.Default().With("service", "orders-service", "component", "catalog")
+	start := time.Now()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, r.baseURL+"/products/"+url.PathEscape(productID), nil)
+	if err != nil {
+		return 0, err
+	}
+	resp, err := r.client.Do(req)
+	if err != nil {
+		logger.Error("catalog request failed", "err", err, "product_id", productID, "duration", time.Since(start))
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return 0, ErrUnknownProduct
+	case resp.StatusCode != http.StatusOK:
+		err = fmt.Errorf("catalog returned status %d", resp.StatusCode)
+		logger.Error("catalog request failed", "err", err, "product_id", productID, "duration", time.Since(start))
+		return 0, err
+	}
+
+	var body struct {
+		Price int64 `json:"price"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		logger.Error("catalog response decode failed", "err", err, "product_id", productID)
+		return 0, err
+	}
+	if body.Price <= 0 {
+		return 0, fmt.Errorf("catalog returned invalid price %d for product %q", body.Price, productID)
+	}
+	logger.Debug("catalog price resolved", "product_id", productID, "price", body.Price, "duration", time.Since(start))
+	return body.Price, nil
+}
@@ -0,0 +1,67 @@
+package catalog
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestParsePrices(t *testing.T) {
+	prices, err := ParsePrices(" sku-1=100, sku-2=250 ,")
+	if err != nil {
+		t.Fatalf("ParsePrices() error: %v", err)
+	}
+	if len(prices) != 2 || prices["sku-1"] != 100 || prices["sku-2"] != 250 {
+		t.Fatalf("ParsePrices() = %v, want map[sku-1:100 sku-2:250]", prices)
+	}
+
+	if prices, err := ParsePrices(""); err != nil || len(prices) != 0 {
+		t.Fatalf("ParsePrices(\"\") = (%v, %v), want empty map", prices, err)
+	}
+
+	for _, in := range []string{"sku-1", "=100", "sku-1=abc", "sku-1=0"} {
+		if _, err := ParsePrices(in); err == nil {
+			t.Fatalf("ParsePrices(%q) expected error", in)
+		}
+	}
+}
+
+func TestStaticResolver(t *testing.T) {
+	r := NewStaticResolver(map[string]int64{"sku-1": 100})
+	price, err := r.Price(context.Background(), "sku-1")
+	if err != nil || price != 100 {
+		t.Fatalf("Price(sku-1) = (%d, %v), want (100, nil)", price, err)
+	}
+	if _, err := r.Price(context.Background(), "missing"); !errors.Is(err, ErrUnknownProduct) {
+		t.Fatalf("Price(missing) error = %v, want ErrUnknownProduct", err)
+	}
+}
+
+func TestHTTPResolver(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/products/sku-1":
+			_, _ = w.Write([]byte(`{"price":150}`))
+		case "/products/broken":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewHTTPResolver(srv.URL+"/", time.Second)
+	price, err := r.Price(context.Background(), "sku-1")
+	if err != nil || price != 150 {
+		t.Fatalf("Price(sku-1) = (%d, %v), want (150, nil)", price, err)
+	}
+	if _, err := r.Price(context.Background(), "missing"); !errors.Is(err, ErrUnknownProduct) {
+		t.Fatalf("Price(missing) error = %v, want ErrUnknownProduct", err)
+	}
+	if _, err := r.Price(context.Background(), "broken"); err == nil || errors.Is(err, ErrUnknownProduct) {
+		t.Fatalf("Price(broken) error = %v, want backend error", err)
+	}
+}
@@ -0,0 +1,107 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// column is one exported field of an order. value returns a string, int64,
+// bool or time.Time, or nil for a missing optional value.
+type column struct {
+	name  string
+	node  parquet.Node
+	value func(r db.ListOrderChangesRow) any
+}
+
+// columns are all exportable fields, in the default order. metadata and tags
+// are exported as JSON text.
+var columns = []column{
+	{"order_id", parquet.String(), func(r db.ListOrderChangesRow) any { return uuid.UUID(r.OrderID.Bytes).String() }},
+	{"user_id", parquet.String(), func(r db.ListOrderChangesRow) any { return r.UserID }},
+	{"status", parquet.String(), func(r db.ListOrderChangesRow) any { return r.Status }},
+	{"amount", parquet.Int(64), func(r db.ListOrderChangesRow) any { return r.Amount }},
+	{"paid_amount", parquet.Int(64), func(r db.ListOrderChangesRow) any { return r.PaidAmount }},
+	{"fee_amount", parquet.Int(64), func(r db.ListOrderChangesRow) any { return r.FeeAmount }},
+	{"description", parquet.String(), func(r db.ListOrderChangesRow) any { return r.Description }},
+	{"payment_method", parquet.String(), func(r db.ListOrderChangesRow) any { return r.PaymentMethod }},
+	{"payment_failure_reason", parquet.Optional(parquet.String()), func(r db.ListOrderChangesRow) any { return optionalText(r.PaymentFailureReason) }},
+	{"payment_failure_code", parquet.Optional(parquet.String()), func(r db.ListOrderChangesRow) any { return optionalText(r.PaymentFailureCode) }},
+	{"metadata", parquet.String(), func(r db.ListOrderChangesRow) any { return metadataJSON(r.Metadata) }},
+	{"tags", parquet.String(), func(r db.ListOrderChangesRow) any { return tagsJSON(r.Tags) }},
+	{"version", parquet.Int(64), func(r db.ListOrderChangesRow) any { return r.Version }},
+	{"created_at", parquet.Timestamp(parquet.Microsecond), func(r db.ListOrderChangesRow) any { return r.CreatedAt.Time.UTC() }},
+	{"updated_at", parquet.Timestamp(parquet.Microsecond), func(r db.ListOrderChangesRow) any { return r.UpdatedAt.Time.UTC() }},
+	{"pay_at", parquet.Optional(parquet.Timestamp(parquet.Microsecond)), func(r db.ListOrderChangesRow) any { return optionalTime(r.PayAt) }},
+	{"archived", parquet.Leaf(parquet.BooleanType), func(r db.ListOrderChangesRow) any { return r.Archived }},
+}
+
+// parseColumns picks the named columns in the given order; no names select
+// every column.
+func parseColumns(names []string) ([]column, error) {
+	if len(names) == 0 {
+		return columns, nil
+	}
+	byName := make(map[string]column, len(columns))
+	for _, c := range columns {
+		byName[c.name] = c
+	}
+	picked := make([]column, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		c, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown export column %q; known: %s", name, strings.Join(columnNames(columns), ", "))
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("export column %q listed twice", name)
+		}
+		seen[name] = true
+		picked = append(picked, c)
+	}
+	return picked, nil
+}
+
+func columnNames(cols []column) []string {
+	names := make([]string, len(cols))
+	for i, c := range cols {
+		names[i] = c.name
+	}
+	return names
+}
+
+func optionalText(t pgtype.Text) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.String
+}
+
+func optionalTime(t pgtype.Timestamptz) any {
+	if !t.Valid {
+		return nil
+	}
+	return t.Time.UTC()
+}
+
+func metadataJSON(b []byte) string {
+	if len(b) == 0 {
+		return "{}"
+	}
+	return string(b)
+}
+
+func tagsJSON(tags []string) string {
+	if tags == nil {
+		tags = []string{}
+	}
+	b, _ := json.Marshal(tags)
+	return string(b)
+}
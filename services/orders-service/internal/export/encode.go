@@ -0,0 +1,117 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Formats of the exported files.
+const (
+	FormatParquet = "parquet"
+	FormatCSV     = "csv"
+)
+
+// encoder writes a batch of orders as one file.
+type encoder interface {
+	encode(rows []db.ListOrderChangesRow) ([]byte, error)
+	extension() string
+	contentType() string
+}
+
+func newEncoder(format string, cols []column) (encoder, error) {
+	switch format {
+	case FormatParquet:
+		group := make(parquet.Group, len(cols))
+		for _, c := range cols {
+			group[c.name] = c.node
+		}
+		return parquetEncoder{cols: cols, schema: parquet.NewSchema("order", group)}, nil
+	case FormatCSV:
+		return csvEncoder{cols: cols}, nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q; want %s or %s", format, FormatParquet, FormatCSV)
+	}
+}
+
+// parquetEncoder writes Snappy-compressed Parquet. Parquet orders the
+// columns of a file by name; readers look them up by name anyway.
+type parquetEncoder struct {
+	cols   []column
+	schema *parquet.Schema
+}
+
+func (e parquetEncoder) encode(rows []db.ListOrderChangesRow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := parquet.NewWriter(&buf, e.schema, parquet.Compression(&parquet.Snappy))
+	for _, r := range rows {
+		row := make(map[string]any, len(e.cols))
+		for _, c := range e.cols {
+			row[c.name] = c.value(r)
+		}
+		if err := w.Write(row); err != nil {
+			return nil, fmt.Errorf("write parquet row: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, fmt.Errorf("close parquet writer: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (parquetEncoder) extension() string   { return "parquet" }
+func (parquetEncoder) contentType() string { return "application/vnd.apache.parquet" }
+
+// csvEncoder writes CSV with a header line, columns in the configured order.
+// Times are RFC 3339 in UTC and missing values are empty.
+type csvEncoder struct {
+	cols []column
+}
+
+func (e csvEncoder) encode(rows []db.ListOrderChangesRow) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columnNames(e.cols)); err != nil {
+		return nil, err
+	}
+	record := make([]string, len(e.cols))
+	for _, r := range rows {
+		for i, c := range e.cols {
+			record[i] = csvValue(c.value(r))
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (csvEncoder) extension() string   { return "csv" }
+func (csvEncoder) contentType() string { return "text/csv" }
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,201 @@
+// Package export periodically dumps new and changed orders to object
+// storage as Parquet or CSV files partitioned by day, so analytics reads
+// files instead of querying the production database.
+package export
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// watermarkName is the export_watermarks row of the orders export.
+const watermarkName = "orders"
+
+// metrics counts the exported rows, files and bytes and the failed cycles,
+// and holds watermark, the updated_at (unix seconds) exported up to.
+var metrics = expvar.NewMap("order_export")
+
+type Options struct {
+	Interval time.Duration
+	// Lag keeps changes younger than that out of the export, so that
+	// transactions still in flight, whose updated_at is their start time,
+	// commit before the watermark passes them.
+	Lag       time.Duration
+	BatchSize int
+	// Format is FormatParquet or FormatCSV.
+	Format string
+	// Columns are the exported columns in order; empty exports all.
+	Columns []string
+	// Prefix starts every key, e.g. "orders".
+	Prefix string
+}
+
+// Exporter exports orders changed since the last run, both hot and archived,
+// in (updated_at, order_id) order. Each batch is a transaction that holds
+// the export_watermarks row while its files are uploaded and moves it past
+// the batch once they are, so a replica that finds the row locked skips the
+// run and a failed upload is retried from the same position. A retried batch
+// gets the same keys and replaces its files; a change made after the first
+// attempt may still produce a row in a later file, so readers deduplicate by
+// (order_id, updated_at) or keep the latest version.
+type Exporter struct {
+	repo repo.OrdersRepository
+	up   Uploader
+	opts Options
+	enc  encoder
+	now  func() time.Time
+
+	logger *slog.Logger
+}
+
+func New(repo repo.OrdersRepository, up Uploader, opts Options) (*Exporter, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 10000
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "orders"
+	}
+	cols, err := parseColumns(opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+	enc, err := newEncoder(opts.Format, cols)
+	if err != nil {
+		return nil, err
+	}
+	logger := slog.Default().With("service", "orders-service", "component", "export")
+	logger.Info("order exporter initialized", "interval", opts.Interval.String(), "lag", opts.Lag.String(), "batch_size", opts.BatchSize, "format", opts.Format, "columns", columnNames(cols), "prefix", opts.Prefix)
+	return &Exporter{repo: repo, up: up, opts: opts, enc: enc, now: time.Now, logger: logger}, nil
+}
+
+func (e *Exporter) Run(ctx context.Context) error {
+	t := time.NewTicker(e.opts.Interval)
+	defer t.Stop()
+
+	for {
+		n, err := e.RunOnce(ctx)
+		if err != nil && ctx.Err() == nil {
+			metrics.Add("errors", 1)
+			e.logger.Error("order export failed", "err", err, "exported", n)
+		} else if n > 0 {
+			e.logger.Info("orders exported", "exported", n)
+		}
+		select {
+		case <-ctx.Done():
+			e.logger.Info("order exporter stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce exports every change older than the lag, one transaction per
+// batch, and returns the number of rows exported.
+func (e *Exporter) RunOnce(ctx context.Context) (int64, error) {
+	until := pgtype.Timestamptz{Time: e.now().Add(-e.opts.Lag), Valid: true}
+	var total int64
+	for {
+		var (
+			n    int
+			busy bool
+			last pgtype.Timestamptz
+		)
+		err := e.repo.InTx(ctx, func(q db.Querier) error {
+			n = 0
+			wm, err := q.LockExportWatermark(ctx, watermarkName)
+			if errors.Is(err, pgx.ErrNoRows) {
+				busy = true
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("lock export watermark: %w", err)
+			}
+			rows, err := q.ListOrderChanges(ctx, db.ListOrderChangesParams{
+				AfterUpdatedAt: wm.UpdatedAt,
+				AfterOrderID:   wm.OrderID,
+				Until:          until,
+				BatchSize:      int32(e.opts.BatchSize),
+			})
+			if err != nil {
+				return fmt.Errorf("list order changes: %w", err)
+			}
+			if len(rows) == 0 {
+				return nil
+			}
+			if err := e.upload(ctx, rows); err != nil {
+				return err
+			}
+			end := rows[len(rows)-1]
+			if err := q.AdvanceExportWatermark(ctx, db.AdvanceExportWatermarkParams{
+				UpdatedAt: end.UpdatedAt,
+				OrderID:   end.OrderID,
+				Rows:      int64(len(rows)),
+				Name:      watermarkName,
+			}); err != nil {
+				return fmt.Errorf("advance export watermark: %w", err)
+			}
+			n, last = len(rows), end.UpdatedAt
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+		if busy {
+			e.logger.Debug("order export running on another replica")
+			return total, nil
+		}
+		if n > 0 {
+			watermark := new(expvar.Int)
+			watermark.Set(last.Time.Unix())
+			metrics.Set("watermark", watermark)
+		}
+		total += int64(n)
+		if n < e.opts.BatchSize || ctx.Err() != nil {
+			return total, nil
+		}
+	}
+}
+
+// upload writes the rows of each UTC day of updated_at as one file under
+// <prefix>/dt=<day>/. The rows come sorted, so every day is one run of them.
+func (e *Exporter) upload(ctx context.Context, rows []db.ListOrderChangesRow) error {
+	for start := 0; start < len(rows); {
+		day := rows[start].UpdatedAt.Time.UTC().Format(time.DateOnly)
+		end := start + 1
+		for end < len(rows) && rows[end].UpdatedAt.Time.UTC().Format(time.DateOnly) == day {
+			end++
+		}
+		part := rows[start:end]
+		body, err := e.enc.encode(part)
+		if err != nil {
+			return fmt.Errorf("encode orders: %w", err)
+		}
+		key := fileKey(e.opts.Prefix, day, part[0], e.enc.extension())
+		if err := e.up.Upload(ctx, key, body, e.enc.contentType()); err != nil {
+			return fmt.Errorf("upload %s: %w", key, err)
+		}
+		metrics.Add("rows", int64(len(part)))
+		metrics.Add("files", 1)
+		metrics.Add("bytes", int64(len(body)))
+		e.logger.Info("order export file uploaded", "key", key, "rows", len(part), "bytes", len(body))
+		start = end
+	}
+	return nil
+}
+
+// fileKey names a file after its first row, so a retried batch replaces
+// the files of the failed attempt.
+func fileKey(prefix, day string, first db.ListOrderChangesRow, ext string) string {
+	return fmt.Sprintf("%s/dt=%s/part-%s-%s.%s", prefix, day, first.UpdatedAt.Time.UTC().Format("20060102T150405.000000Z"), uuid.UUID(first.OrderID.Bytes), ext)
+}
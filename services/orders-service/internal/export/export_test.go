@@ -0,0 +1,198 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/parquet-go/parquet-go"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// fakeRepo holds the changed orders sorted by (updated_at, order_id) and the
+// watermark; a failed transaction leaves the watermark where it was.
+type fakeRepo struct {
+	db.Querier
+	rows     []db.ListOrderChangesRow
+	wm       db.ExportWatermark
+	locked   bool
+	advanced []int64
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	wm := f.wm
+	if err := fn(f); err != nil {
+		f.wm = wm
+		return err
+	}
+	return nil
+}
+
+func (f *fakeRepo) LockExportWatermark(_ context.Context, name string) (db.ExportWatermark, error) {
+	if f.locked || name != watermarkName {
+		return db.ExportWatermark{}, pgx.ErrNoRows
+	}
+	return f.wm, nil
+}
+
+func (f *fakeRepo) ListOrderChanges(_ context.Context, arg db.ListOrderChangesParams) ([]db.ListOrderChangesRow, error) {
+	var out []db.ListOrderChangesRow
+	for _, r := range f.rows {
+		after := r.UpdatedAt.Time.After(arg.AfterUpdatedAt.Time) ||
+			(r.UpdatedAt.Time.Equal(arg.AfterUpdatedAt.Time) && bytes.Compare(r.OrderID.Bytes[:], arg.AfterOrderID.Bytes[:]) > 0)
+		if arg.AfterUpdatedAt.InfinityModifier == pgtype.NegativeInfinity {
+			after = true
+		}
+		if after && r.UpdatedAt.Time.Before(arg.Until.Time) && len(out) < int(arg.BatchSize) {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) AdvanceExportWatermark(_ context.Context, arg db.AdvanceExportWatermarkParams) error {
+	f.wm.UpdatedAt, f.wm.OrderID = arg.UpdatedAt, arg.OrderID
+	f.wm.ExportedRows += arg.Rows
+	f.advanced = append(f.advanced, arg.Rows)
+	return nil
+}
+
+type memUploader struct {
+	files map[string][]byte
+	keys  []string
+	fail  bool
+}
+
+func (u *memUploader) Upload(_ context.Context, key string, body []byte, _ string) error {
+	if u.fail {
+		return errors.New("storage down")
+	}
+	u.files[key] = body
+	u.keys = append(u.keys, key)
+	return nil
+}
+
+func orderRow(n byte, updated time.Time) db.ListOrderChangesRow {
+	return db.ListOrderChangesRow{
+		OrderID:   pgtype.UUID{Bytes: [16]byte{15: n}, Valid: true},
+		UserID:    "u-1",
+		Amount:    int64(n) * 100,
+		Status:    "FINISHED",
+		Tags:      []string{"gift"},
+		Metadata:  []byte(`{"channel":"web"}`),
+		CreatedAt: pgtype.Timestamptz{Time: updated.Add(-time.Minute), Valid: true},
+		UpdatedAt: pgtype.Timestamptz{Time: updated, Valid: true},
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	day1 := time.Date(2024, 5, 1, 23, 59, 0, 0, time.UTC)
+	day2 := time.Date(2024, 5, 2, 0, 1, 0, 0, time.UTC)
+	now := day2.Add(time.Hour)
+	repo := &fakeRepo{
+		rows: []db.ListOrderChangesRow{
+			orderRow(1, day1), orderRow(2, day1), orderRow(3, day2),
+			// Younger than the lag: left for the next run.
+			orderRow(4, now.Add(-time.Second)),
+		},
+		wm: db.ExportWatermark{Name: watermarkName, UpdatedAt: pgtype.Timestamptz{Valid: true, InfinityModifier: pgtype.NegativeInfinity}},
+	}
+	up := &memUploader{files: map[string][]byte{}}
+	e, err := New(repo, up, Options{Interval: time.Hour, Lag: time.Minute, BatchSize: 2, Format: FormatCSV, Columns: []string{"order_id", "amount", "updated_at", "tags", "pay_at"}})
+	if err != nil {
+		t.Fatalf("New() error: %v", err)
+	}
+	e.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	up.fail = true
+	if n, err := e.RunOnce(ctx); err == nil || n != 0 || repo.wm.ExportedRows != 0 {
+		t.Fatalf("RunOnce() with storage down = %d, %v, watermark %+v; want the error and no progress", n, err, repo.wm)
+	}
+	up.fail = false
+
+	n, err := e.RunOnce(ctx)
+	if err != nil || n != 3 {
+		t.Fatalf("RunOnce() = %d, %v, want 3", n, err)
+	}
+	if !slices.Equal(repo.advanced, []int64{2, 1}) || !repo.wm.UpdatedAt.Time.Equal(day2) {
+		t.Fatalf("watermark advanced by %v to %s, want [2 1] up to %s", repo.advanced, repo.wm.UpdatedAt.Time, day2)
+	}
+	wantKeys := []string{
+		"orders/dt=2024-05-01/part-20240501T235900.000000Z-" + uuid.UUID{15: 1}.String() + ".csv",
+		"orders/dt=2024-05-02/part-20240502T000100.000000Z-" + uuid.UUID{15: 3}.String() + ".csv",
+	}
+	if !slices.Equal(up.keys, wantKeys) {
+		t.Fatalf("keys = %q, want %q", up.keys, wantKeys)
+	}
+	wantCSV := "order_id,amount,updated_at,tags,pay_at\n" +
+		uuid.UUID{15: 1}.String() + ",100,2024-05-01T23:59:00Z,\"[\"\"gift\"\"]\",\n" +
+		uuid.UUID{15: 2}.String() + ",200,2024-05-01T23:59:00Z,\"[\"\"gift\"\"]\",\n"
+	if got := string(up.files[wantKeys[0]]); got != wantCSV {
+		t.Fatalf("first file =\n%s\nwant\n%s", got, wantCSV)
+	}
+
+	// Another replica holds the watermark.
+	repo.locked = true
+	if n, err := e.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("RunOnce() while locked = %d, %v, want 0, nil", n, err)
+	}
+}
+
+func TestParquet(t *testing.T) {
+	enc, err := newEncoder(FormatParquet, columns)
+	if err != nil {
+		t.Fatalf("newEncoder() error: %v", err)
+	}
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	row := orderRow(1, at)
+	row.PaymentFailureReason = pgtype.Text{String: "insufficient funds", Valid: true}
+	body, err := enc.encode([]db.ListOrderChangesRow{row, orderRow(2, at)})
+	if err != nil {
+		t.Fatalf("encode() error: %v", err)
+	}
+
+	f, err := parquet.OpenFile(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatalf("OpenFile() error: %v", err)
+	}
+	if f.NumRows() != 2 || len(f.Schema().Columns()) != len(columns) {
+		t.Fatalf("file has %d rows, %d columns; want 2 rows, %d columns", f.NumRows(), len(f.Schema().Columns()), len(columns))
+	}
+	r := parquet.NewReader(f)
+	got := map[string]any{}
+	if err := r.Read(&got); err != nil {
+		t.Fatalf("Read() error: %v", err)
+	}
+	if got["order_id"] != (uuid.UUID{15: 1}).String() || got["amount"] != int64(100) || got["payment_failure_reason"] != "insufficient funds" || got["pay_at"] != nil || got["tags"] != `["gift"]` {
+		t.Fatalf("first row = %v", got)
+	}
+}
+
+func TestParseColumns(t *testing.T) {
+	if cols, err := parseColumns(nil); err != nil || len(cols) != len(columns) {
+		t.Fatalf("parseColumns(nil) = %d columns, %v; want all", len(cols), err)
+	}
+	cols, err := parseColumns([]string{" Status", "order_id"})
+	if err != nil || !slices.Equal(columnNames(cols), []string{"status", "order_id"}) {
+		t.Fatalf("parseColumns() = %v, %v", columnNames(cols), err)
+	}
+	for _, bad := range [][]string{{"order_id", "secret"}, {"amount", "amount"}} {
+		if _, err := parseColumns(bad); err == nil {
+			t.Errorf("parseColumns(%q) error = nil", bad)
+		}
+	}
+	if _, err := newEncoder("xlsx", columns); err == nil || !strings.Contains(err.Error(), "xlsx") {
+		t.Fatalf("newEncoder(xlsx) error = %v", err)
+	}
+}
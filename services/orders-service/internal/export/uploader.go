@@ -0,0 +1,47 @@
+package export
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Uploader stores an exported file under key, a slash-separated path such
+// as orders/dt=2024-05-01/part-….parquet. Uploading the same key again
+// replaces the file.
+type Uploader interface {
+	Upload(ctx context.Context, key string, body []byte, contentType string) error
+}
+
+// DirUploader writes files below a local directory, for development and for
+// storage mounted into the container.
+type DirUploader struct {
+	dir string
+}
+
+func NewDirUploader(dir string) *DirUploader {
+	return &DirUploader{dir: dir}
+}
+
+// Upload writes to a temporary file first, so readers never see a partial
+// file.
+func (u *DirUploader) Upload(_ context.Context, key string, body []byte, _ string) error {
+	path := filepath.Join(u.dir, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,505 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/projection"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// Page size bounds of ListDeadOutbox and ListOutbox.
+const (
+	defaultDeadOutboxPageSize = 50
+	maxDeadOutboxPageSize     = 500
+)
+
+// inspectOrderEvents bounds the outbox events and status changes
+// InspectOrder returns.
+const inspectOrderEvents = 100
+
+// AdminHandlers serves the operator RPCs registered with ENABLE_ADMIN_API.
+type AdminHandlers struct {
+	ordersv1.UnimplementedOrdersAdminServiceServer
+	repo  repo.OrdersRepository
+	cache cache.OrderCache
+
+	maxReplayEvents int
+	currency        money.Currency
+
+	// inbox holds the consumers of DryRunInboxMessage by name.
+	inbox map[string]InboxReplayer
+	// projections are the projections StartProjectionReplay can rebuild.
+	projections []projection.Projection
+
+	logger *slog.Logger
+}
+
+// NewAdminHandlers builds the admin handlers. maxReplayEvents bounds a
+// single ReplayOutbox call. cache is optional; orders changed by
+// ForceOrderStatus are written through to it.
+func NewAdminHandlers(repo repo.OrdersRepository, cache cache.OrderCache, maxReplayEvents int, currency money.Currency) *AdminHandlers {
+	logger := slog.Default().With("service", "orders-service", "component", "grpc")
+	logger.Info("admin handlers initialized", "max_replay_events", maxReplayEvents)
+	return &AdminHandlers{repo: repo, cache: cache, maxReplayEvents: maxReplayEvents, currency: currency, logger: logger}
+}
+
+// operator is the subject of the caller's token, empty when roles are not
+// checked.
+func operator(ctx context.Context) string {
+	if claims, ok := userauth.FromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
+
+// ReplayOutbox inserts copies of the sent outbox events created in
+// [from, to). Counting and copying share a transaction, so the limit check
+// holds for what is actually copied.
+func (h *AdminHandlers) ReplayOutbox(ctx context.Context, req *ordersv1.ReplayOutboxRequest) (resp *ordersv1.ReplayOutboxResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "replay outbox start", "operator", operator, "from", req.GetFrom().AsTime(), "to", req.GetTo().AsTime(), "topic", req.GetTopic(), "dry_run", req.GetDryRun())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "replay outbox failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "replay outbox completed", "operator", operator, "matched", resp.GetMatched(), "replayed", resp.GetReplayed(), "dry_run", resp.GetDryRun(), "duration", time.Since(start))
+	}()
+
+	if req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	from, to := req.GetFrom().AsTime(), req.GetTo().AsTime()
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	rng := db.CountSentOutboxParams{
+		CreatedFrom: pgtype.Timestamptz{Time: from, Valid: true},
+		CreatedTo:   pgtype.Timestamptz{Time: to, Valid: true},
+		Topic:       req.GetTopic(),
+	}
+
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		matched, err := q.CountSentOutbox(ctx, rng)
+		if err != nil {
+			return err
+		}
+		resp = &ordersv1.ReplayOutboxResponse{Matched: matched, DryRun: req.GetDryRun()}
+		if req.GetDryRun() {
+			return nil
+		}
+		if matched > int64(h.maxReplayEvents) {
+			return status.Errorf(codes.FailedPrecondition, "range matches %d events, above the limit of %d; narrow it", matched, h.maxReplayEvents)
+		}
+		resp.Replayed, err = q.ReplaySentOutbox(ctx, db.ReplaySentOutboxParams(rng))
+		return err
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to replay outbox")
+	}
+	return resp, nil
+}
+
+// ListDeadOutbox pages through dead-lettered outbox events by id.
+func (h *AdminHandlers) ListDeadOutbox(ctx context.Context, req *ordersv1.ListDeadOutboxRequest) (*ordersv1.ListDeadOutboxResponse, error) {
+	start := time.Now()
+	pageSize := req.GetPageSize()
+	if pageSize < 0 || pageSize > maxDeadOutboxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", maxDeadOutboxPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultDeadOutboxPageSize
+	}
+	var rows []db.ListDeadOutboxRow
+	err := h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		rows, err = q.ListDeadOutbox(ctx, db.ListDeadOutboxParams{
+			AfterID:  req.GetAfterId(),
+			Topic:    req.GetTopic(),
+			PageSize: pageSize,
+		})
+		return err
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "list dead outbox failed", "err", err, "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to list dead outbox")
+	}
+
+	resp := &ordersv1.ListDeadOutboxResponse{Events: make([]*ordersv1.DeadOutboxEvent, 0, len(rows))}
+	for _, r := range rows {
+		resp.Events = append(resp.Events, &ordersv1.DeadOutboxEvent{
+			Id:        r.ID,
+			Topic:     r.Topic,
+			KafkaKey:  r.KafkaKey,
+			Attempts:  r.Attempts,
+			LastError: r.LastError.String,
+			CreatedAt: timestamppb.New(r.CreatedAt.Time),
+		})
+	}
+	if len(rows) == int(pageSize) {
+		resp.NextAfterId = rows[len(rows)-1].ID
+	}
+	h.logger.InfoContext(ctx, "list dead outbox completed", "topic", req.GetTopic(), "count", len(rows), "duration", time.Since(start))
+	return resp, nil
+}
+
+// outboxStatuses maps a ListOutbox state to the outbox statuses it covers.
+var outboxStatuses = map[ordersv1.OutboxState][]string{
+	ordersv1.OutboxState_OUTBOX_STATE_UNSENT: {"PENDING", "FAILED"},
+	ordersv1.OutboxState_OUTBOX_STATE_FAILED: {"FAILED"},
+	ordersv1.OutboxState_OUTBOX_STATE_DEAD:   {"DEAD"},
+}
+
+// ListOutbox pages through the outbox events in one state by id.
+func (h *AdminHandlers) ListOutbox(ctx context.Context, req *ordersv1.ListOutboxRequest) (*ordersv1.ListOutboxResponse, error) {
+	start := time.Now()
+	statuses, ok := outboxStatuses[req.GetState()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "state is required")
+	}
+	pageSize := req.GetPageSize()
+	if pageSize < 0 || pageSize > maxDeadOutboxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", maxDeadOutboxPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultDeadOutboxPageSize
+	}
+	arg := db.ListOutboxParams{
+		Statuses: statuses,
+		AfterID:  req.GetAfterId(),
+		Topic:    req.GetTopic(),
+		PageSize: pageSize,
+	}
+	if req.GetCreatedFrom() != nil {
+		arg.CreatedFrom = pgtype.Timestamptz{Time: req.GetCreatedFrom().AsTime(), Valid: true}
+	}
+	if req.GetCreatedTo() != nil {
+		arg.CreatedTo = pgtype.Timestamptz{Time: req.GetCreatedTo().AsTime(), Valid: true}
+	}
+	if arg.CreatedFrom.Valid && arg.CreatedTo.Valid && !arg.CreatedFrom.Time.Before(arg.CreatedTo.Time) {
+		return nil, status.Error(codes.InvalidArgument, "created_from must be before created_to")
+	}
+
+	var rows []db.ListOutboxRow
+	err := h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		rows, err = q.ListOutbox(ctx, arg)
+		return err
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "list outbox failed", "err", err, "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to list outbox")
+	}
+
+	resp := &ordersv1.ListOutboxResponse{Events: make([]*ordersv1.OutboxEvent, 0, len(rows))}
+	for _, r := range rows {
+		e := &ordersv1.OutboxEvent{
+			Id:            r.ID,
+			Topic:         r.Topic,
+			KafkaKey:      r.KafkaKey,
+			Status:        r.Status,
+			Attempts:      r.Attempts,
+			LastError:     r.LastError.String,
+			CreatedAt:     timestamppb.New(r.CreatedAt.Time),
+			CorrelationId: r.CorrelationID,
+		}
+		if r.NextRetryAt.Valid {
+			e.NextRetryAt = timestamppb.New(r.NextRetryAt.Time)
+		}
+		resp.Events = append(resp.Events, e)
+	}
+	if len(rows) == int(pageSize) {
+		resp.NextAfterId = rows[len(rows)-1].ID
+	}
+	h.logger.InfoContext(ctx, "list outbox completed", "operator", operator(ctx), "state", req.GetState().String(), "topic", req.GetTopic(), "count", len(rows), "duration", time.Since(start))
+	return resp, nil
+}
+
+// RequeueDeadOutbox resets dead-lettered events to PENDING; the publisher
+// picks them up on its next pass.
+func (h *AdminHandlers) RequeueDeadOutbox(ctx context.Context, req *ordersv1.RequeueDeadOutboxRequest) (resp *ordersv1.RequeueDeadOutboxResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "requeue dead outbox start", "operator", operator, "ids", req.GetIds(), "all", req.GetAll(), "topic", req.GetTopic())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "requeue dead outbox failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "requeue dead outbox completed", "operator", operator, "requeued", resp.GetRequeued(), "duration", time.Since(start))
+	}()
+
+	if !req.GetAll() && len(req.GetIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids or all is required")
+	}
+	var n int64
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		n, err = q.RequeueDeadOutbox(ctx, db.RequeueDeadOutboxParams{
+			AllEvents: req.GetAll(),
+			Ids:       req.GetIds(),
+			Topic:     req.GetTopic(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to requeue dead outbox")
+	}
+	return &ordersv1.RequeueDeadOutboxResponse{Requeued: n}, nil
+}
+
+// InspectOrder reads an order and its saga state in one snapshot.
+func (h *AdminHandlers) InspectOrder(ctx context.Context, req *ordersv1.InspectOrderRequest) (*ordersv1.InspectOrderResponse, error) {
+	start := time.Now()
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		order    db.GetOrderByIDRow
+		payments []db.ListOrderPaymentsRow
+		retries  []db.ListOrderPaymentRetriesRow
+		events   []db.ListOutboxByKeyRow
+		history  []db.OrderStatusHistory
+	)
+	err = h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		if order, err = q.GetOrderByID(ctx, orderUUID); err != nil {
+			return err
+		}
+		if payments, err = q.ListOrderPayments(ctx, orderUUID); err != nil {
+			return err
+		}
+		if retries, err = q.ListOrderPaymentRetries(ctx, orderUUID); err != nil {
+			return err
+		}
+		if events, err = q.ListOutboxByKey(ctx, db.ListOutboxByKeyParams{KafkaKey: oid.String(), Limit: inspectOrderEvents}); err != nil {
+			return err
+		}
+		history, err = q.ListOrderStatusHistory(ctx, db.ListOrderStatusHistoryParams{OrderID: orderUUID, MaxRows: inspectOrderEvents})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		h.logger.ErrorContext(ctx, "inspect order failed", "err", err, "order_id", req.GetOrderId(), "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to inspect order")
+	}
+
+	resp := &ordersv1.InspectOrderResponse{
+		Order: &ordersv1.Order{
+			OrderId:              order.OrderID.String(),
+			UserId:               order.UserID,
+			Amount:               order.Amount,
+			Description:          order.Description,
+			Status:               mapOrderStatus(order.Status),
+			CreatedAt:            timestamppb.New(order.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: order.PaymentFailureReason.String,
+			PaidAmount:           order.PaidAmount,
+			FeeAmount:            order.FeeAmount,
+			Metadata:             decodeMetadata(order.Metadata),
+			Tags:                 order.Tags,
+			Version:              order.Version,
+			UpdatedAt:            timestamppb.New(order.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(order.PayAt),
+			Archived:             order.Archived,
+			Dispute:              disputeToProto(orderDispute(order.DisputeStatus, order.DisputeAmount, order.DisputeReason, order.DisputeOpenedAt, order.DisputeResolvedAt)),
+		},
+	}
+	for _, p := range payments {
+		resp.Payments = append(resp.Payments, &ordersv1.OrderPaymentStep{
+			PaymentId:     p.PaymentID.String(),
+			Amount:        p.Amount,
+			Status:        p.Status,
+			FailureReason: p.FailureReason.String,
+			Fee:           p.Fee,
+			CreatedAt:     timestamppb.New(p.CreatedAt.Time),
+		})
+	}
+	for _, r := range retries {
+		step := &ordersv1.PaymentRetryStep{RetryKey: r.RetryKey, Attempts: r.Attempts, LastReason: r.LastReason.String}
+		if r.NextAttemptAt.Valid {
+			step.NextAttemptAt = timestamppb.New(r.NextAttemptAt.Time)
+		}
+		resp.Retries = append(resp.Retries, step)
+	}
+	// The query returns the newest events first so that the limit keeps
+	// the latest ones.
+	for i := len(events) - 1; i >= 0; i-- {
+		e := events[i]
+		step := &ordersv1.OutboxEventStep{
+			Id:            e.ID,
+			Topic:         e.Topic,
+			Status:        e.Status,
+			Attempts:      e.Attempts,
+			LastError:     e.LastError.String,
+			CreatedAt:     timestamppb.New(e.CreatedAt.Time),
+			CorrelationId: e.CorrelationID,
+		}
+		if e.SentAt.Valid {
+			step.SentAt = timestamppb.New(e.SentAt.Time)
+		}
+		resp.Events = append(resp.Events, step)
+	}
+	for _, c := range history {
+		resp.StatusHistory = append(resp.StatusHistory, &ordersv1.OrderStatusChange{
+			EventId:        c.EventID.String(),
+			PreviousStatus: c.PreviousStatus,
+			Status:         c.Status,
+			Reason:         c.Reason,
+			OccurredAt:     timestamppb.New(c.OccurredAt.Time),
+		})
+	}
+	h.logger.InfoContext(ctx, "inspect order completed", "operator", operator(ctx), "order_id", req.GetOrderId(), "duration", time.Since(start))
+	return resp, nil
+}
+
+// ForceOrderStatus overwrites the status of a hot order and records the
+// change in admin_audit_log in the same transaction. Payments, transfers and
+// retries of the order are left alone.
+func (h *AdminHandlers) ForceOrderStatus(ctx context.Context, req *ordersv1.ForceOrderStatusRequest) (resp *ordersv1.ForceOrderStatusResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "force order status start", "operator", operator, "order_id", req.GetOrderId(), "status", req.GetStatus(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "force order status failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "force order status completed", "operator", operator, "order_id", req.GetOrderId(), "previous_status", resp.GetPreviousStatus(), "status", resp.GetOrder().GetStatus(), "duration", time.Since(start))
+	}()
+
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	target := orderStatusName(req.GetStatus())
+	if mapOrderStatus(target) == ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		return nil, status.Error(codes.InvalidArgument, "status is required")
+	}
+	if req.GetStatus() == ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED {
+		// The scheduler needs a pay_at, which only CreateOrder sets.
+		return nil, status.Error(codes.InvalidArgument, "orders cannot be forced into SCHEDULED")
+	}
+	if req.GetStatus() == ordersv1.OrderStatus_ORDER_STATUS_DISPUTED || req.GetStatus() == ordersv1.OrderStatus_ORDER_STATUS_REFUNDED {
+		// Disputes are opened and resolved in payments, which moves the money.
+		return nil, status.Error(codes.InvalidArgument, "orders cannot be forced into DISPUTED or REFUNDED")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	var (
+		row    db.ForceOrderStatusRow
+		cached *cache.Order
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		row, err = q.ForceOrderStatus(ctx, db.ForceOrderStatusParams{Status: target, OrderID: pgtype.UUID{Bytes: oid, Valid: true}})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.NotFound, "order not found or archived")
+			}
+			return err
+		}
+		if row.PreviousStatus == target {
+			return status.Errorf(codes.FailedPrecondition, "order is already %s", target)
+		}
+		if row.PreviousStatus == "DISPUTED" || row.PreviousStatus == "REFUNDED" {
+			return status.Errorf(codes.FailedPrecondition, "order is %s; resolve its dispute in payments", row.PreviousStatus)
+		}
+		if cached, err = cachedOrder(ctx, q, h.cache, row.OrderID, row.UserID); err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]string{"user_id": row.UserID, "from": row.PreviousStatus, "to": target})
+		if err != nil {
+			return err
+		}
+		if err := q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "force_order_status",
+			Target:   oid.String(),
+			Reason:   req.GetReason(),
+			Details:  details,
+		}); err != nil {
+			return err
+		}
+		return kafkasvc.InsertStatusChanged(ctx, q, kafkasvc.StatusChange{
+			OrderID:    oid.String(),
+			UserID:     row.UserID,
+			From:       row.PreviousStatus,
+			To:         row.Status,
+			Amount:     row.Amount,
+			PaidAmount: row.PaidAmount,
+			Reason:     row.PaymentFailureReason.String,
+		})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to force order status")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, *cached); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", row.OrderID.String())
+		}
+		if err := h.cache.InvalidateList(ctx, row.UserID); err != nil {
+			h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", row.UserID)
+		}
+	}
+
+	return &ordersv1.ForceOrderStatusResponse{
+		Order: &ordersv1.Order{
+			OrderId:              row.OrderID.String(),
+			UserId:               row.UserID,
+			Amount:               row.Amount,
+			Description:          row.Description,
+			Status:               mapOrderStatus(row.Status),
+			CreatedAt:            timestamppb.New(row.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: row.PaymentFailureReason.String,
+			PaidAmount:           row.PaidAmount,
+			FeeAmount:            row.FeeAmount,
+			Metadata:             decodeMetadata(row.Metadata),
+			Tags:                 row.Tags,
+			Version:              row.Version,
+			UpdatedAt:            timestamppb.New(row.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(row.PayAt),
+		},
+		PreviousStatus: mapOrderStatus(row.PreviousStatus),
+	}, nil
+}
+
+// orderStatusName is the inverse of mapOrderStatus: ORDER_STATUS_FINISHED is
+// stored as FINISHED.
+func orderStatusName(s ordersv1.OrderStatus) string {
+	return strings.TrimPrefix(s.String(), "ORDER_STATUS_")
+}
@@ -0,0 +1,306 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+func TestReplayOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 2, money.RUB)
+	ctx := context.Background()
+	now := time.Now()
+	repo.sent = []fakeSentOutbox{
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-1", Payload: []byte("1")}, createdAt: now.Add(-2 * time.Hour)},
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-2", Payload: []byte("2")}, createdAt: now.Add(-30 * time.Minute)},
+		{row: db.InsertOutboxParams{Topic: "b", KafkaKey: "k-3", Payload: []byte("3")}, createdAt: now.Add(-20 * time.Minute)},
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-4", Payload: []byte("4")}, createdAt: now.Add(-10 * time.Minute)},
+	}
+	lastHour := &ordersv1.ReplayOutboxRequest{From: timestamppb.New(now.Add(-time.Hour)), To: timestamppb.New(now)}
+
+	_, err := h.ReplayOutbox(ctx, &ordersv1.ReplayOutboxRequest{To: timestamppb.New(now)})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ReplayOutbox(ctx, &ordersv1.ReplayOutboxRequest{From: timestamppb.New(now), To: timestamppb.New(now)})
+	wantCode(t, err, codes.InvalidArgument)
+
+	dry, err := h.ReplayOutbox(ctx, &ordersv1.ReplayOutboxRequest{From: lastHour.From, To: lastHour.To, DryRun: true})
+	if err != nil || dry.GetMatched() != 3 || dry.GetReplayed() != 0 || len(repo.outbox) != 0 {
+		t.Fatalf("ReplayOutbox(dry run) = (%v, %v), outbox %d; want 3 matched, nothing replayed", dry, err, len(repo.outbox))
+	}
+
+	// Three events exceed the limit of two.
+	_, err = h.ReplayOutbox(ctx, lastHour)
+	wantCode(t, err, codes.FailedPrecondition)
+	if len(repo.outbox) != 0 {
+		t.Fatalf("rejected replay left %d outbox rows", len(repo.outbox))
+	}
+
+	resp, err := h.ReplayOutbox(ctx, &ordersv1.ReplayOutboxRequest{From: lastHour.From, To: lastHour.To, Topic: "a"})
+	if err != nil || resp.GetMatched() != 2 || resp.GetReplayed() != 2 {
+		t.Fatalf("ReplayOutbox(topic a) = (%v, %v), want 2 matched and replayed", resp, err)
+	}
+	if len(repo.outbox) != 2 || repo.outbox[0].KafkaKey != "k-2" || repo.outbox[1].KafkaKey != "k-4" {
+		t.Fatalf("outbox = %v, want copies of k-2 and k-4 in order", repo.outbox)
+	}
+}
+
+func TestListDeadOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+	for i := int64(1); i <= 3; i++ {
+		repo.dead = append(repo.dead, db.ListDeadOutboxRow{ID: i, Topic: "a", KafkaKey: "k", Attempts: 20, LastError: pgtype.Text{String: "unknown topic", Valid: true}})
+	}
+	repo.dead = append(repo.dead, db.ListDeadOutboxRow{ID: 4, Topic: "b", KafkaKey: "k"})
+
+	_, err := h.ListDeadOutbox(ctx, &ordersv1.ListDeadOutboxRequest{PageSize: maxDeadOutboxPageSize + 1})
+	wantCode(t, err, codes.InvalidArgument)
+
+	page, err := h.ListDeadOutbox(ctx, &ordersv1.ListDeadOutboxRequest{Topic: "a", PageSize: 2})
+	if err != nil || len(page.GetEvents()) != 2 || page.GetNextAfterId() != 2 {
+		t.Fatalf("first page = (%v, %v), want 2 events and next_after_id 2", page, err)
+	}
+	if ev := page.GetEvents()[0]; ev.GetId() != 1 || ev.GetAttempts() != 20 || ev.GetLastError() != "unknown topic" {
+		t.Fatalf("event = %v", ev)
+	}
+	page, err = h.ListDeadOutbox(ctx, &ordersv1.ListDeadOutboxRequest{Topic: "a", PageSize: 2, AfterId: page.GetNextAfterId()})
+	if err != nil || len(page.GetEvents()) != 1 || page.GetEvents()[0].GetId() != 3 || page.GetNextAfterId() != 0 {
+		t.Fatalf("last page = (%v, %v), want event 3 and no next page", page, err)
+	}
+	all, err := h.ListDeadOutbox(ctx, &ordersv1.ListDeadOutboxRequest{})
+	if err != nil || len(all.GetEvents()) != 4 {
+		t.Fatalf("all = (%v, %v), want 4 events", all, err)
+	}
+}
+
+func TestListOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+	now := time.Now()
+	at := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: now.Add(d), Valid: true} }
+	repo.listed = []db.ListOutboxRow{
+		{ID: 1, Topic: "a", Status: "SENT", CreatedAt: at(-3 * time.Hour)},
+		{ID: 2, Topic: "a", Status: "DEAD", Attempts: 20, LastError: pgtype.Text{String: "unknown topic", Valid: true}, CreatedAt: at(-2 * time.Hour)},
+		{ID: 3, Topic: "a", Status: "FAILED", Attempts: 2, LastError: pgtype.Text{String: "broker down", Valid: true}, CreatedAt: at(-time.Hour), NextRetryAt: at(time.Second)},
+		{ID: 4, Topic: "b", Status: "PENDING", CreatedAt: at(-30 * time.Minute)},
+		{ID: 5, Topic: "a", Status: "PENDING", CreatedAt: at(-time.Minute)},
+	}
+
+	_, err := h.ListOutbox(ctx, &ordersv1.ListOutboxRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ListOutbox(ctx, &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedFrom: timestamppb.New(now), CreatedTo: timestamppb.New(now.Add(-time.Hour))})
+	wantCode(t, err, codes.InvalidArgument)
+
+	ids := func(resp *ordersv1.ListOutboxResponse) []int64 {
+		var ids []int64
+		for _, e := range resp.GetEvents() {
+			ids = append(ids, e.GetId())
+		}
+		return ids
+	}
+	for _, tc := range []struct {
+		name string
+		req  *ordersv1.ListOutboxRequest
+		want []int64
+	}{
+		{"unsent", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT}, []int64{3, 4, 5}},
+		{"failed", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_FAILED}, []int64{3}},
+		{"dead", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_DEAD}, []int64{2}},
+		{"unsent of topic", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, Topic: "a"}, []int64{3, 5}},
+		{"unsent created since", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedFrom: timestamppb.New(now.Add(-45 * time.Minute))}, []int64{4, 5}},
+		{"unsent created before", &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedTo: timestamppb.New(now.Add(-30 * time.Minute))}, []int64{3}},
+	} {
+		resp, err := h.ListOutbox(ctx, tc.req)
+		if err != nil || !slices.Equal(ids(resp), tc.want) {
+			t.Errorf("%s: ListOutbox = (%v, %v), want ids %v", tc.name, ids(resp), err, tc.want)
+		}
+	}
+
+	page, err := h.ListOutbox(ctx, &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, PageSize: 2})
+	if err != nil || page.GetNextAfterId() != 4 {
+		t.Fatalf("first page = (%v, %v), want next_after_id 4", page, err)
+	}
+	if ev := page.GetEvents()[0]; ev.GetStatus() != "FAILED" || ev.GetLastError() != "broker down" || ev.GetNextRetryAt() == nil {
+		t.Fatalf("event = %v", ev)
+	}
+	page, err = h.ListOutbox(ctx, &ordersv1.ListOutboxRequest{State: ordersv1.OutboxState_OUTBOX_STATE_UNSENT, PageSize: 2, AfterId: page.GetNextAfterId()})
+	if err != nil || !slices.Equal(ids(page), []int64{5}) || page.GetNextAfterId() != 0 {
+		t.Fatalf("last page = (%v, %v), want event 5 and no next page", page, err)
+	}
+}
+
+func TestRequeueDeadOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+	repo.dead = []db.ListDeadOutboxRow{{ID: 1, Topic: "a"}, {ID: 2, Topic: "b"}, {ID: 3, Topic: "a"}, {ID: 4, Topic: "a"}}
+
+	_, err := h.RequeueDeadOutbox(ctx, &ordersv1.RequeueDeadOutboxRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+
+	resp, err := h.RequeueDeadOutbox(ctx, &ordersv1.RequeueDeadOutboxRequest{Ids: []int64{2, 3, 99}})
+	if err != nil || resp.GetRequeued() != 2 {
+		t.Fatalf("RequeueDeadOutbox(ids) = (%v, %v), want 2 requeued", resp, err)
+	}
+	resp, err = h.RequeueDeadOutbox(ctx, &ordersv1.RequeueDeadOutboxRequest{All: true, Topic: "a"})
+	if err != nil || resp.GetRequeued() != 2 || len(repo.dead) != 0 {
+		t.Fatalf("RequeueDeadOutbox(all of a) = (%v, %v), %d left dead; want 2 requeued, none left", resp, err, len(repo.dead))
+	}
+	if !slices.Equal(repo.requeued, []int64{2, 3, 1, 4}) {
+		t.Fatalf("requeued = %v", repo.requeued)
+	}
+}
+
+func TestInspectOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+	order := repo.insertOrder("user-1", 300, "desk", nil, nil)
+	oid := order.OrderID.String()
+	repo.CreateOrderPayment(ctx, db.CreateOrderPaymentParams{OrderID: order.OrderID, Amount: 100})
+	repo.retries = []db.ListOrderPaymentRetriesRow{{RetryKey: oid, Attempts: 2, LastReason: pgtype.Text{String: "FAIL_INTERNAL", Valid: true}}}
+	for range inspectOrderEvents + 1 {
+		repo.InsertOutbox(ctx, db.InsertOutboxParams{Topic: "payments.requested", KafkaKey: oid})
+	}
+	repo.InsertOutbox(ctx, db.InsertOutboxParams{Topic: "payments.requested", KafkaKey: "other"})
+	repo.history = []db.OrderStatusHistory{
+		{EventID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, OrderID: order.OrderID, PreviousStatus: "NEW", Status: "PARTIALLY_PAID"},
+		{EventID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, OrderID: pgtype.UUID{Bytes: uuid.New(), Valid: true}, PreviousStatus: "NEW", Status: "CANCELLED"},
+	}
+
+	_, err := h.InspectOrder(ctx, &ordersv1.InspectOrderRequest{OrderId: "nope"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.InspectOrder(ctx, &ordersv1.InspectOrderRequest{OrderId: uuid.NewString()})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := h.InspectOrder(ctx, &ordersv1.InspectOrderRequest{OrderId: oid})
+	if err != nil {
+		t.Fatalf("InspectOrder: %v", err)
+	}
+	if resp.GetOrder().GetUserId() != "user-1" || len(resp.GetPayments()) != 1 || len(resp.GetRetries()) != 1 ||
+		len(resp.GetStatusHistory()) != 1 || resp.GetStatusHistory()[0].GetStatus() != "PARTIALLY_PAID" {
+		t.Fatalf("InspectOrder = %v", resp)
+	}
+	// The oldest event is cut off; the rest come oldest first.
+	events := resp.GetEvents()
+	if len(events) != inspectOrderEvents || events[0].GetId() != 2 || events[len(events)-1].GetId() != inspectOrderEvents+1 {
+		t.Fatalf("events = %d from %d to %d, want %d from 2", len(events), events[0].GetId(), events[len(events)-1].GetId(), inspectOrderEvents)
+	}
+}
+
+func TestForceOrderStatus(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+	order := repo.insertOrder("user-1", 300, "desk", nil, nil)
+	oid := order.OrderID.String()
+	finished := ordersv1.OrderStatus_ORDER_STATUS_FINISHED
+
+	_, err := h.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Reason: "settled by hand"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Status: finished, Reason: " "})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: uuid.NewString(), Status: finished, Reason: "settled by hand"})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := h.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Status: finished, Reason: "settled by hand"})
+	if err != nil || resp.GetOrder().GetStatus() != finished || resp.GetPreviousStatus() != ordersv1.OrderStatus_ORDER_STATUS_NEW || resp.GetOrder().GetVersion() != 2 {
+		t.Fatalf("ForceOrderStatus = (%v, %v), want FINISHED from NEW at version 2", resp, err)
+	}
+	if len(repo.audit) != 1 {
+		t.Fatalf("audit = %v, want one entry", repo.audit)
+	}
+	if a := repo.audit[0]; a.Operator != "alice" || a.Action != "force_order_status" || a.Target != oid || a.Reason != "settled by hand" || string(a.Details) != `{"from":"NEW","to":"FINISHED","user_id":"user-1"}` {
+		t.Fatalf("audit entry = %+v", a)
+	}
+	if len(repo.outbox) != 1 || repo.outbox[0].Topic != kafkasvc.TopicOrderStatusChanged || repo.outbox[0].KafkaKey != oid {
+		t.Fatalf("outbox = %+v, want one status change of the order", repo.outbox)
+	}
+	var changed eventsv1.OrderStatusChanged
+	if err := kafkasvc.UnmarshalEvent(repo.outbox[0].Payload, &changed); err != nil || changed.GetUserId() != "user-1" || changed.GetPreviousStatus() != "NEW" || changed.GetStatus() != "FINISHED" || changed.GetAmount() != 300 {
+		t.Fatalf("status changed event = (%v, %v)", &changed, err)
+	}
+	if len(repo.receipts) != 1 || repo.receipts[0].OrderID != order.OrderID || repo.receipts[0].TotalAmount != 300 {
+		t.Fatalf("receipts = %+v, want the receipt of the finished order", repo.receipts)
+	}
+
+	// Forcing the current status again is rejected and not audited.
+	_, err = h.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Status: finished, Reason: "again"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if len(repo.audit) != 1 || repo.orders[0].row.Status != "FINISHED" {
+		t.Fatalf("rejected force left audit %d, status %s", len(repo.audit), repo.orders[0].row.Status)
+	}
+}
+
+type fakeReplayer struct {
+	dry *kafkasvc.InboxDryRun
+	err error
+}
+
+func (fakeReplayer) Consumer() string { return "payment_result" }
+
+func (r fakeReplayer) DryRun(context.Context, string) (*kafkasvc.InboxDryRun, error) {
+	return r.dry, r.err
+}
+
+func TestDryRunInboxMessage(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.NewString()
+	h := NewAdminHandlers(newFakeRepo(), nil, 10, money.RUB)
+	req := &ordersv1.DryRunInboxMessageRequest{Consumer: "payment_result", MessageId: id}
+
+	// No consumers are registered yet.
+	_, err := h.DryRunInboxMessage(ctx, req)
+	wantCode(t, err, codes.InvalidArgument)
+
+	h.UseInbox(fakeReplayer{err: inbox.ErrNotFound})
+	_, err = h.DryRunInboxMessage(ctx, &ordersv1.DryRunInboxMessageRequest{Consumer: "payment_result", MessageId: "42"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.DryRunInboxMessage(ctx, req)
+	wantCode(t, err, codes.NotFound)
+	h.UseInbox(fakeReplayer{err: inbox.ErrPayloadNotStored})
+	_, err = h.DryRunInboxMessage(ctx, req)
+	wantCode(t, err, codes.FailedPrecondition)
+
+	received := time.Now()
+	h.UseInbox(fakeReplayer{dry: &kafkasvc.InboxDryRun{
+		Message: inbox.Stored{
+			Consumer:   "payment_result",
+			Message:    inbox.Message{ID: id, Topic: "payments.payment_result", Partition: 1, Offset: 7, Payload: []byte("{}"), Headers: []inbox.Header{{Key: "h", Value: []byte("v")}}},
+			ReceivedAt: received,
+		},
+		Event:  &eventsv1.PaymentResult{EventId: id},
+		Outbox: []db.ListTxOutboxRow{{ID: 5, Topic: "orders.status_changed", KafkaKey: "o-1", Payload: []byte("x")}},
+	}})
+	resp, err := h.DryRunInboxMessage(ctx, req)
+	if err != nil {
+		t.Fatalf("DryRunInboxMessage() error: %v", err)
+	}
+	m := resp.GetMessage()
+	if m.GetMessageId() != id || m.GetOffset() != 7 || len(m.GetHeaders()) != 1 || m.GetProcessedAt() != nil || !m.GetReceivedAt().AsTime().Equal(received) {
+		t.Fatalf("message = %v, want the stored one unprocessed", m)
+	}
+	if resp.GetEventJson() == "" || resp.GetSkipped() != "" || resp.GetError() != "" {
+		t.Fatalf("response = %v, want the decoded event and no failure", resp)
+	}
+	if len(resp.GetWouldPublish()) != 1 || resp.GetWouldPublish()[0].GetKafkaKey() != "o-1" {
+		t.Fatalf("would_publish = %v, want the o-1 event", resp.GetWouldPublish())
+	}
+}
@@ -0,0 +1,123 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	db "github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// maxOrdersBatch bounds the order ids of one GetOrders call.
+const maxOrdersBatch = 100
+
+// GetOrders is GetOrder for many orders of one user: one cache round trip
+// for all of them, then one query for the misses, which are cached in turn.
+func (h *Handlers) GetOrders(ctx context.Context, req *ordersv1.GetOrdersRequest) (resp *ordersv1.GetOrdersResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "get orders start", "user_id", req.GetUserId(), "order_ids", len(req.GetOrderIds()))
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get orders failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get orders completed", "found", len(resp.GetOrders()), "missing", len(resp.GetMissingOrderIds()), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	orderIDs, err := ordersBatchIDs(req.GetOrderIds())
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]cache.Order, len(orderIDs))
+	if h.cache != nil {
+		cached, err := h.cache.GetMany(ctx, orderIDs)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "cache mget failed", "err", err)
+		}
+		for id, o := range cached {
+			// Another user's order is a miss here, as in GetOrder; the query
+			// below does not return it either.
+			if o != nil && o.UserID == req.GetUserId() {
+				found[id] = *o
+			}
+		}
+	}
+
+	var misses []pgtype.UUID
+	for _, id := range orderIDs {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		if oid, err := uuid.Parse(id); err == nil {
+			misses = append(misses, pgtype.UUID{Bytes: oid, Valid: true})
+		}
+	}
+	h.logger.DebugContext(ctx, "get orders cache done", "hits", len(found), "misses", len(misses))
+	if len(misses) > 0 {
+		var rows []db.GetOrdersRow
+		err = h.repo.Read(ctx, func(q db.Querier) error {
+			var err error
+			rows, err = q.GetOrders(ctx, db.GetOrdersParams{OrderIds: misses, UserID: req.GetUserId()})
+			return err
+		})
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get orders query failed", "err", err, "order_ids", len(misses))
+			return nil, status.Error(codes.Internal, "failed to get orders")
+		}
+		for _, r := range rows {
+			o := cacheOrderFromRow(db.GetOrderRow(r))
+			found[o.OrderID] = o
+			if h.cache != nil {
+				if err := h.cache.Set(ctx, o); err != nil {
+					h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", o.OrderID)
+				}
+			}
+		}
+	}
+
+	resp = &ordersv1.GetOrdersResponse{}
+	for _, id := range orderIDs {
+		o, ok := found[id]
+		if !ok {
+			resp.MissingOrderIds = append(resp.MissingOrderIds, id)
+			continue
+		}
+		resp.Orders = append(resp.Orders, h.orderFromCache(o))
+	}
+	return resp, nil
+}
+
+// ordersBatchIDs validates the order ids of GetOrders and drops repeats,
+// keeping the first occurrence. Valid ids are returned in canonical form, as
+// the cache and the responses key them; ids that are not UUIDs cannot name
+// an order and end up missing rather than failing the whole batch.
+func ordersBatchIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "order_ids is required")
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := uuid.Parse(id); err == nil {
+			id = oid.String()
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	if len(out) > maxOrdersBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "order_ids: at most %d orders per call", maxOrdersBatch)
+	}
+	return out, nil
+}
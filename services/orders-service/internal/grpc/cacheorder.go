@@ -0,0 +1,73 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// cacheOrderFromRow is the cache entry of an order read with GetOrder. Every
+// order put in the cache is built here, so no path drops a column.
+func cacheOrderFromRow(r db.GetOrderRow) cache.Order {
+	return cache.Order{
+		OrderID:              r.OrderID.String(),
+		UserID:               r.UserID,
+		Amount:               r.Amount,
+		Description:          r.Description,
+		Status:               r.Status,
+		CreatedAt:            r.CreatedAt.Time,
+		PaymentFailureReason: r.PaymentFailureReason.String,
+		PaidAmount:           r.PaidAmount,
+		FeeAmount:            r.FeeAmount,
+		Metadata:             decodeMetadata(r.Metadata),
+		Tags:                 r.Tags,
+		Version:              r.Version,
+		UpdatedAt:            r.UpdatedAt.Time,
+		PayAt:                optionalTime(r.PayAt),
+		Archived:             r.Archived,
+		Dispute:              orderDispute(r.DisputeStatus, r.DisputeAmount, r.DisputeReason, r.DisputeOpenedAt, r.DisputeResolvedAt),
+	}
+}
+
+// cacheListOrderFromRow is the cache entry of a ListOrders row. The list has
+// no dispute columns but adds the last payment reason and the item count.
+func cacheListOrderFromRow(r db.OrdersRead) cache.Order {
+	o := cacheOrderFromRow(db.GetOrderRow{
+		OrderID:              r.OrderID,
+		UserID:               r.UserID,
+		Amount:               r.Amount,
+		Description:          r.Description,
+		Status:               r.Status,
+		CreatedAt:            r.CreatedAt,
+		PaymentFailureReason: r.PaymentFailureReason,
+		PaidAmount:           r.PaidAmount,
+		FeeAmount:            r.FeeAmount,
+		Metadata:             r.Metadata,
+		Tags:                 r.Tags,
+		Version:              r.Version,
+		UpdatedAt:            r.UpdatedAt,
+		PayAt:                r.PayAt,
+		Archived:             r.Archived,
+	})
+	o.LastPaymentReason = r.LastPaymentReason.String
+	o.ItemCount = r.ItemCount
+	return o
+}
+
+// cachedOrder reads back an order written in the transaction of q, so that
+// the cache gets every column, dispute included, and not just the ones the
+// write returned. It returns nil without a cache.
+func cachedOrder(ctx context.Context, q db.Querier, c cache.OrderCache, orderID pgtype.UUID, userID string) (*cache.Order, error) {
+	if c == nil {
+		return nil, nil
+	}
+	r, err := q.GetOrder(ctx, db.GetOrderParams{OrderID: orderID, UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+	o := cacheOrderFromRow(r)
+	return &o, nil
+}
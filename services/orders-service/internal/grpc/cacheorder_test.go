@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+func TestCacheOrderFromRow(t *testing.T) {
+	oid := uuid.New()
+	created := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	updated, payAt := created.Add(time.Hour), created.Add(2*time.Hour)
+	opened, resolved := created.Add(3*time.Hour), created.Add(4*time.Hour)
+	row := db.GetOrderRow{
+		OrderID:              pgtype.UUID{Bytes: oid, Valid: true},
+		UserID:               "u-1",
+		Amount:               300,
+		Description:          "desk",
+		Status:               "REFUNDED",
+		CreatedAt:            pgtype.Timestamptz{Time: created, Valid: true},
+		PaymentFailureReason: pgtype.Text{String: "card declined", Valid: true},
+		PaidAmount:           300,
+		FeeAmount:            10,
+		Metadata:             []byte(`{"k":"v"}`),
+		Tags:                 []string{"office"},
+		Version:              4,
+		UpdatedAt:            pgtype.Timestamptz{Time: updated, Valid: true},
+		PayAt:                pgtype.Timestamptz{Time: payAt, Valid: true},
+		Archived:             true,
+		DisputeStatus:        pgtype.Text{String: "WON", Valid: true},
+		DisputeAmount:        pgtype.Int8{Int64: 310, Valid: true},
+		DisputeReason:        pgtype.Text{String: "not delivered", Valid: true},
+		DisputeOpenedAt:      pgtype.Timestamptz{Time: opened, Valid: true},
+		DisputeResolvedAt:    pgtype.Timestamptz{Time: resolved, Valid: true},
+	}
+	want := cache.Order{
+		OrderID:              oid.String(),
+		UserID:               "u-1",
+		Amount:               300,
+		Description:          "desk",
+		Status:               "REFUNDED",
+		CreatedAt:            created,
+		PaymentFailureReason: "card declined",
+		PaidAmount:           300,
+		FeeAmount:            10,
+		Metadata:             map[string]string{"k": "v"},
+		Tags:                 []string{"office"},
+		Version:              4,
+		UpdatedAt:            updated,
+		PayAt:                &payAt,
+		Archived:             true,
+		Dispute:              &cache.OrderDispute{Status: "WON", Amount: 310, Reason: "not delivered", OpenedAt: opened, ResolvedAt: &resolved},
+	}
+
+	got := cacheOrderFromRow(row)
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("cacheOrderFromRow() = %+v, want %+v", got, want)
+	}
+	// A new cache.Order field has to be set here too; only the list ones stay
+	// empty for a single order.
+	v := reflect.ValueOf(got)
+	for i := range v.NumField() {
+		name := v.Type().Field(i).Name
+		if v.Field(i).IsZero() && name != "LastPaymentReason" && name != "ItemCount" {
+			t.Errorf("cacheOrderFromRow() leaves %s empty", name)
+		}
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("json.Marshal() error: %v", err)
+	}
+	var back cache.Order
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("json.Unmarshal() error: %v", err)
+	}
+	if !reflect.DeepEqual(back, want) {
+		t.Fatalf("cached order after JSON = %+v, want %+v", back, want)
+	}
+
+	list := cacheListOrderFromRow(db.OrdersRead{
+		OrderID:           row.OrderID,
+		UserID:            row.UserID,
+		Status:            "NEW",
+		Archived:          true,
+		LastPaymentReason: pgtype.Text{String: "insufficient funds", Valid: true},
+		ItemCount:         2,
+	})
+	if !list.Archived || list.LastPaymentReason != "insufficient funds" || list.ItemCount != 2 || list.Dispute != nil {
+		t.Fatalf("cacheListOrderFromRow() = %+v", list)
+	}
+}
@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+)
+
+// orderDispute is the dispute of an order read with its row, or nil when the
+// order was never disputed.
+func orderDispute(status pgtype.Text, amount pgtype.Int8, reason pgtype.Text, openedAt, resolvedAt pgtype.Timestamptz) *cache.OrderDispute {
+	if !status.Valid {
+		return nil
+	}
+	return &cache.OrderDispute{
+		Status:     status.String,
+		Amount:     amount.Int64,
+		Reason:     reason.String,
+		OpenedAt:   openedAt.Time,
+		ResolvedAt: optionalTime(resolvedAt),
+	}
+}
+
+func disputeToProto(d *cache.OrderDispute) *ordersv1.OrderDispute {
+	if d == nil {
+		return nil
+	}
+	return &ordersv1.OrderDispute{
+		Status:     mapDisputeStatus(d.Status),
+		Amount:     d.Amount,
+		Reason:     d.Reason,
+		OpenedAt:   timestamppb.New(d.OpenedAt),
+		ResolvedAt: cachedTimestamp(d.ResolvedAt),
+	}
+}
+
+func mapDisputeStatus(s string) ordersv1.DisputeStatus {
+	switch s {
+	case "OPEN":
+		return ordersv1.DisputeStatus_DISPUTE_STATUS_OPEN
+	case "REFUNDED":
+		return ordersv1.DisputeStatus_DISPUTE_STATUS_REFUNDED
+	case "UPHELD":
+		return ordersv1.DisputeStatus_DISPUTE_STATUS_UPHELD
+	default:
+		return ordersv1.DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+	}
+}
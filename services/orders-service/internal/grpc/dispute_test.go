@@ -0,0 +1,61 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/catalog"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+func TestOrderDispute(t *testing.T) {
+	repo := newFakeRepo()
+	order := repo.insertOrder("u-1", 300, "desk", nil, nil)
+	opened := time.Now().Add(-time.Hour).Truncate(time.Second)
+	row := &repo.orders[0].row
+	row.Status = "DISPUTED"
+	row.DisputeStatus = pgtype.Text{String: "OPEN", Valid: true}
+	row.DisputeAmount = pgtype.Int8{Int64: 310, Valid: true}
+	row.DisputeReason = pgtype.Text{String: "not delivered", Valid: true}
+	row.DisputeOpenedAt = pgtype.Timestamptz{Time: opened, Valid: true}
+	oid := order.OrderID.String()
+
+	h := NewHandlers(repo, cache.NewMemoryOrderCache(10, time.Minute), nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+	// The second read is served from the cache.
+	for range 2 {
+		resp, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: oid})
+		if err != nil {
+			t.Fatalf("GetOrder() error: %v", err)
+		}
+		d := resp.GetOrder().GetDispute()
+		if resp.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_DISPUTED || d.GetStatus() != ordersv1.DisputeStatus_DISPUTE_STATUS_OPEN ||
+			d.GetAmount() != 310 || d.GetReason() != "not delivered" || !d.GetOpenedAt().AsTime().Equal(opened) || d.GetResolvedAt() != nil {
+			t.Fatalf("GetOrder() order = %v, want DISPUTED with the open dispute", resp.GetOrder())
+		}
+	}
+
+	admin := NewAdminHandlers(repo, nil, 10, money.RUB)
+	actx := userauth.NewContext(ctx, jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+	inspected, err := admin.InspectOrder(actx, &ordersv1.InspectOrderRequest{OrderId: oid})
+	if err != nil || inspected.GetOrder().GetDispute().GetStatus() != ordersv1.DisputeStatus_DISPUTE_STATUS_OPEN {
+		t.Fatalf("InspectOrder() = (%v, %v), want the open dispute", inspected.GetOrder(), err)
+	}
+
+	// Disputes move orders only through payments.
+	_, err = admin.ForceOrderStatus(actx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Status: ordersv1.OrderStatus_ORDER_STATUS_REFUNDED, Reason: "refund"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = admin.ForceOrderStatus(actx, &ordersv1.ForceOrderStatusRequest{OrderId: oid, Status: ordersv1.OrderStatus_ORDER_STATUS_FINISHED, Reason: "close"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.orders[0].row.Status != "DISPUTED" || len(repo.audit) != 0 {
+		t.Fatalf("rejected force left status %s, audit %v", repo.orders[0].row.Status, repo.audit)
+	}
+}
@@ -0,0 +1,790 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// fakeRepo is an in-memory repo.OrdersRepository. Queries the handlers do not
+// use fall through to the nil embedded Querier and panic.
+type fakeRepo struct {
+	db.Querier
+
+	mu       sync.Mutex
+	orders   []fakeOrder
+	payments []fakePayment
+	outbox   []db.InsertOutboxParams
+	// changed are the OrderChanged events, kept apart from outbox so tests
+	// of the other events need not skip them.
+	changed []db.InsertOutboxParams
+	// sent are outbox rows already published, as seen by ReplayOutbox.
+	sent []fakeSentOutbox
+	// listed are outbox rows in any state, as seen by ListOutbox.
+	listed []db.ListOutboxRow
+	// dead are dead-lettered outbox rows, as seen by ListDeadOutbox.
+	dead      []db.ListDeadOutboxRow
+	requeued  []int64
+	retries   []db.ListOrderPaymentRetriesRow
+	audit     []db.InsertAdminAuditParams
+	known     map[string]bool
+	idem      map[db.GetIdempotencyKeyParams]db.GetIdempotencyKeyRow
+	transfers []db.CreateOrderTransferRow
+	receipts  []db.Receipt
+	items     []db.InsertOrderItemParams
+	promos    []db.PromoCode
+	redeemed  []db.PromoRedemption
+	history   []db.OrderStatusHistory
+	replays   []db.ProjectionReplay
+	offsets   []db.ProjectionReplayOffset
+
+	getOrdersCalls int
+}
+
+type fakeSentOutbox struct {
+	row       db.InsertOutboxParams
+	createdAt time.Time
+}
+
+type fakeOrder struct {
+	row db.GetOrderRow
+	// failureCode, retries, paymentMethod and installments mirror
+	// payment_failure_code, payment_retry_count, payment_method and
+	// pay_in_installments, which GetOrderRow does not carry.
+	failureCode   string
+	retries       int32
+	paymentMethod string
+	installments  bool
+}
+
+type fakePayment struct {
+	row db.CreateOrderPaymentRow
+}
+
+var _ repo.OrdersRepository = (*fakeRepo)(nil)
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{known: map[string]bool{}, idem: map[db.GetIdempotencyKeyParams]db.GetIdempotencyKeyRow{}}
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return fn(f)
+}
+
+// InTx restores the previous state when fn fails, like a rolled back tx.
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	orders := append([]fakeOrder(nil), f.orders...)
+	payments := append([]fakePayment(nil), f.payments...)
+	outbox := append([]db.InsertOutboxParams(nil), f.outbox...)
+	changed := append([]db.InsertOutboxParams(nil), f.changed...)
+	idem := maps.Clone(f.idem)
+	transfers := append([]db.CreateOrderTransferRow(nil), f.transfers...)
+	audit := append([]db.InsertAdminAuditParams(nil), f.audit...)
+	receipts := append([]db.Receipt(nil), f.receipts...)
+	items := append([]db.InsertOrderItemParams(nil), f.items...)
+	promos := append([]db.PromoCode(nil), f.promos...)
+	redeemed := append([]db.PromoRedemption(nil), f.redeemed...)
+	if err := fn(f); err != nil {
+		f.orders, f.payments, f.outbox, f.idem, f.transfers, f.audit, f.receipts = orders, payments, outbox, idem, transfers, audit, receipts
+		f.promos, f.redeemed, f.changed, f.items = promos, redeemed, changed, items
+		return err
+	}
+	return nil
+}
+
+func (f *fakeRepo) insertOrder(userID string, amount int64, description string, metadata []byte, tags []string) db.GetOrderRow {
+	row := db.GetOrderRow{
+		OrderID:     pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:      userID,
+		Amount:      amount,
+		Description: description,
+		Status:      "NEW",
+		CreatedAt:   pgtype.Timestamptz{Time: time.Now().Add(time.Duration(len(f.orders)) * time.Millisecond), Valid: true},
+		Metadata:    metadata,
+		Tags:        tags,
+		Version:     1,
+		UpdatedAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.orders = append(f.orders, fakeOrder{row: row, paymentMethod: defaultPaymentMethod})
+	return row
+}
+
+func (f *fakeRepo) findOrder(orderID pgtype.UUID, userID string) (db.GetOrderRow, error) {
+	for _, o := range f.orders {
+		if o.row.OrderID == orderID && o.row.UserID == userID {
+			return o.row, nil
+		}
+	}
+	return db.GetOrderRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) CreateOrder(_ context.Context, arg db.CreateOrderParams) (db.CreateOrderRow, error) {
+	r := f.insertOrder(arg.UserID, arg.Amount, arg.Description, arg.Metadata, arg.Tags)
+	f.orders[len(f.orders)-1].paymentMethod = arg.PaymentMethod
+	f.orders[len(f.orders)-1].installments = arg.PayInInstallments
+	if arg.PayAt.Valid {
+		o := &f.orders[len(f.orders)-1].row
+		o.Status, o.PayAt = "SCHEDULED", arg.PayAt
+		r = *o
+	}
+	return db.CreateOrderRow{OrderID: r.OrderID, UserID: r.UserID, Amount: r.Amount, Description: r.Description, Status: r.Status, CreatedAt: r.CreatedAt, Metadata: r.Metadata, Tags: r.Tags, Version: r.Version, UpdatedAt: r.UpdatedAt, PayAt: r.PayAt, PaymentMethod: arg.PaymentMethod}, nil
+}
+
+func (f *fakeRepo) CancelScheduledOrder(_ context.Context, arg db.CancelScheduledOrderParams) (db.CancelScheduledOrderRow, error) {
+	for i := range f.orders {
+		o := &f.orders[i].row
+		if o.OrderID == arg.OrderID && o.UserID == arg.UserID && o.Status == "SCHEDULED" {
+			o.Status, o.PaymentFailureReason = "CANCELLED", arg.Reason
+			o.Version++
+			return db.CancelScheduledOrderRow{OrderID: o.OrderID, UserID: o.UserID, Amount: o.Amount, Description: o.Description, Status: o.Status, CreatedAt: o.CreatedAt, PaymentFailureReason: o.PaymentFailureReason, Metadata: o.Metadata, Tags: o.Tags, Version: o.Version, UpdatedAt: o.UpdatedAt, PayAt: o.PayAt}, nil
+		}
+	}
+	return db.CancelScheduledOrderRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) RetryOrderPayment(_ context.Context, arg db.RetryOrderPaymentParams) (db.RetryOrderPaymentRow, error) {
+	for i := range f.orders {
+		fo := &f.orders[i]
+		o := &fo.row
+		if o.OrderID == arg.OrderID && o.UserID == arg.UserID && o.Status == "CANCELLED" && fo.failureCode == "NOT_ENOUGH_FUNDS" && fo.retries < arg.MaxRetries {
+			o.Status, o.PaymentFailureReason, fo.failureCode = "NEW", pgtype.Text{}, ""
+			fo.retries++
+			o.Version++
+			return db.RetryOrderPaymentRow{OrderID: o.OrderID, UserID: o.UserID, Amount: o.Amount, Description: o.Description, Status: o.Status, CreatedAt: o.CreatedAt, Metadata: o.Metadata, Tags: o.Tags, Version: o.Version, UpdatedAt: o.UpdatedAt, PayAt: o.PayAt, PaymentRetryCount: fo.retries, PaymentMethod: fo.paymentMethod}, nil
+		}
+	}
+	return db.RetryOrderPaymentRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) GetOrderPaymentRetry(_ context.Context, arg db.GetOrderPaymentRetryParams) (db.GetOrderPaymentRetryRow, error) {
+	for _, fo := range f.orders {
+		if fo.row.OrderID == arg.OrderID && fo.row.UserID == arg.UserID {
+			return db.GetOrderPaymentRetryRow{Status: fo.row.Status, PaymentFailureCode: pgtype.Text{String: fo.failureCode, Valid: fo.failureCode != ""}, PaymentRetryCount: fo.retries}, nil
+		}
+	}
+	return db.GetOrderPaymentRetryRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) GetOrder(_ context.Context, arg db.GetOrderParams) (db.GetOrderRow, error) {
+	return f.findOrder(arg.OrderID, arg.UserID)
+}
+
+func (f *fakeRepo) GetOrders(_ context.Context, arg db.GetOrdersParams) ([]db.GetOrdersRow, error) {
+	f.getOrdersCalls++
+	var rows []db.GetOrdersRow
+	for _, id := range arg.OrderIds {
+		if r, err := f.findOrder(id, arg.UserID); err == nil {
+			rows = append(rows, db.GetOrdersRow(r))
+		}
+	}
+	return rows, nil
+}
+
+// LockUserOrderCreate is a no-op: InTx already holds f.mu.
+func (f *fakeRepo) LockUserOrderCreate(context.Context, string) error {
+	return nil
+}
+
+func (f *fakeRepo) FindRecentDuplicateOrder(_ context.Context, arg db.FindRecentDuplicateOrderParams) (pgtype.UUID, error) {
+	for i := len(f.orders) - 1; i >= 0; i-- {
+		r := f.orders[i].row
+		if r.UserID == arg.UserID && r.Amount == arg.Amount && r.Description == arg.Description && r.Status != "CANCELLED" && !r.CreatedAt.Time.Before(arg.Since.Time) {
+			return r.OrderID, nil
+		}
+	}
+	return pgtype.UUID{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) CountNewOrders(_ context.Context, userID string) (int64, error) {
+	var n int64
+	for _, o := range f.orders {
+		if o.row.UserID == userID && o.row.Status == "NEW" {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeRepo) GetOrderForUpdate(_ context.Context, arg db.GetOrderForUpdateParams) (db.GetOrderForUpdateRow, error) {
+	for _, o := range f.orders {
+		if o.row.OrderID == arg.OrderID && o.row.UserID == arg.UserID && !o.row.Archived {
+			h := hotRow(o.row)
+			return db.GetOrderForUpdateRow{OrderID: h.OrderID, UserID: h.UserID, Amount: h.Amount, Description: h.Description, Status: h.Status, CreatedAt: h.CreatedAt, PaymentFailureReason: h.PaymentFailureReason, PaidAmount: h.PaidAmount, FeeAmount: h.FeeAmount, Metadata: h.Metadata, Tags: h.Tags, Version: h.Version, UpdatedAt: h.UpdatedAt, PayAt: o.row.PayAt, PaymentMethod: o.paymentMethod, PayInInstallments: o.installments}, nil
+		}
+	}
+	return db.GetOrderForUpdateRow{}, pgx.ErrNoRows
+}
+
+// hotRow drops the archived flag, which only queries reading the archive
+// return.
+func hotRow(r db.GetOrderRow) db.UpdateOrderDetailsRow {
+	return db.UpdateOrderDetailsRow{OrderID: r.OrderID, UserID: r.UserID, Amount: r.Amount, Description: r.Description, Status: r.Status, CreatedAt: r.CreatedAt, PaymentFailureReason: r.PaymentFailureReason, PaidAmount: r.PaidAmount, FeeAmount: r.FeeAmount, Metadata: r.Metadata, Tags: r.Tags, Version: r.Version, UpdatedAt: r.UpdatedAt}
+}
+
+func (f *fakeRepo) UpdateOrderDetails(_ context.Context, arg db.UpdateOrderDetailsParams) (db.UpdateOrderDetailsRow, error) {
+	for i, o := range f.orders {
+		if o.row.OrderID == arg.OrderID && o.row.UserID == arg.UserID {
+			r := &f.orders[i].row
+			r.Description, r.Metadata, r.Tags = arg.Description, arg.Metadata, arg.Tags
+			r.Version++
+			r.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			return hotRow(*r), nil
+		}
+	}
+	return db.UpdateOrderDetailsRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) ListOrders(_ context.Context, arg db.ListOrdersParams) ([]db.OrdersRead, error) {
+	var rows []db.OrdersRead
+	for i := len(f.orders) - 1; i >= 0; i-- {
+		if f.orders[i].row.UserID == arg.UserID && (arg.Tag == "" || slices.Contains(f.orders[i].row.Tags, arg.Tag)) && (arg.IncludeArchived || !f.orders[i].row.Archived) {
+			rows = append(rows, listRow(f.orders[i].row, f.itemCount(f.orders[i].row.OrderID)))
+		}
+	}
+	if int(arg.Offset) >= len(rows) {
+		return nil, nil
+	}
+	rows = rows[arg.Offset:]
+	if len(rows) > int(arg.Limit) {
+		rows = rows[:arg.Limit]
+	}
+	return rows, nil
+}
+
+// listRow is the orders_read row of r with items order items as the
+// projection would have it for an order without installments.
+func listRow(r db.GetOrderRow, items int32) db.OrdersRead {
+	return db.OrdersRead{
+		LastPaymentReason:    r.PaymentFailureReason,
+		ItemCount:            items,
+		OrderID:              r.OrderID,
+		UserID:               r.UserID,
+		Amount:               r.Amount,
+		Description:          r.Description,
+		Status:               r.Status,
+		CreatedAt:            r.CreatedAt,
+		PaymentFailureReason: r.PaymentFailureReason,
+		PaidAmount:           r.PaidAmount,
+		FeeAmount:            r.FeeAmount,
+		Metadata:             r.Metadata,
+		Tags:                 r.Tags,
+		Version:              r.Version,
+		UpdatedAt:            r.UpdatedAt,
+		PayAt:                r.PayAt,
+		Archived:             r.Archived,
+	}
+}
+
+func (f *fakeRepo) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	if arg.Topic == kafkasvc.TopicOrderChanged {
+		f.changed = append(f.changed, arg)
+		return int64(len(f.changed)), nil
+	}
+	f.outbox = append(f.outbox, arg)
+	return int64(len(f.outbox)), nil
+}
+
+func (f *fakeRepo) CreateOrderPayment(_ context.Context, arg db.CreateOrderPaymentParams) (db.CreateOrderPaymentRow, error) {
+	row := db.CreateOrderPaymentRow{
+		PaymentID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		OrderID:   arg.OrderID,
+		Amount:    arg.Amount,
+		Status:    "PENDING",
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.payments = append(f.payments, fakePayment{row: row})
+	return row, nil
+}
+
+func (f *fakeRepo) SumPendingOrderPayments(_ context.Context, orderID pgtype.UUID) (int64, error) {
+	var sum int64
+	for _, p := range f.payments {
+		if p.row.OrderID == orderID && p.row.Status == "PENDING" {
+			sum += p.row.Amount
+		}
+	}
+	return sum, nil
+}
+
+func (f *fakeRepo) KnownAccountExists(_ context.Context, userID string) (bool, error) {
+	return f.known[userID], nil
+}
+
+func (f *fakeRepo) UpsertKnownAccount(_ context.Context, userID string) error {
+	f.known[userID] = true
+	return nil
+}
+
+func (f *fakeRepo) ClaimIdempotencyKey(_ context.Context, arg db.ClaimIdempotencyKeyParams) (int64, error) {
+	key := db.GetIdempotencyKeyParams{Scope: arg.Scope, UserID: arg.UserID, IdempotencyKey: arg.IdempotencyKey}
+	if _, ok := f.idem[key]; ok {
+		return 0, nil
+	}
+	f.idem[key] = db.GetIdempotencyKeyRow{RequestHash: arg.RequestHash, Status: "IN_PROGRESS"}
+	return 1, nil
+}
+
+func (f *fakeRepo) GetIdempotencyKey(_ context.Context, arg db.GetIdempotencyKeyParams) (db.GetIdempotencyKeyRow, error) {
+	row, ok := f.idem[arg]
+	if !ok {
+		return db.GetIdempotencyKeyRow{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (f *fakeRepo) CompleteIdempotencyKey(_ context.Context, arg db.CompleteIdempotencyKeyParams) error {
+	key := db.GetIdempotencyKeyParams{Scope: arg.Scope, UserID: arg.UserID, IdempotencyKey: arg.IdempotencyKey}
+	row := f.idem[key]
+	row.Status, row.Response = "COMPLETED", arg.Response
+	f.idem[key] = row
+	return nil
+}
+
+func (f *fakeRepo) sentInRange(arg db.CountSentOutboxParams) []db.InsertOutboxParams {
+	var rows []db.InsertOutboxParams
+	for _, o := range f.sent {
+		if !o.createdAt.Before(arg.CreatedFrom.Time) && o.createdAt.Before(arg.CreatedTo.Time) && (arg.Topic == "" || o.row.Topic == arg.Topic) {
+			rows = append(rows, o.row)
+		}
+	}
+	return rows
+}
+
+func (f *fakeRepo) CountSentOutbox(_ context.Context, arg db.CountSentOutboxParams) (int64, error) {
+	return int64(len(f.sentInRange(arg))), nil
+}
+
+func (f *fakeRepo) ReplaySentOutbox(_ context.Context, arg db.ReplaySentOutboxParams) (int64, error) {
+	rows := f.sentInRange(db.CountSentOutboxParams(arg))
+	f.outbox = append(f.outbox, rows...)
+	return int64(len(rows)), nil
+}
+
+func (f *fakeRepo) ListOutbox(_ context.Context, arg db.ListOutboxParams) ([]db.ListOutboxRow, error) {
+	var rows []db.ListOutboxRow
+	for _, r := range f.listed {
+		if !slices.Contains(arg.Statuses, r.Status) || r.ID <= arg.AfterID || (arg.Topic != "" && r.Topic != arg.Topic) {
+			continue
+		}
+		if (arg.CreatedFrom.Valid && r.CreatedAt.Time.Before(arg.CreatedFrom.Time)) || (arg.CreatedTo.Valid && !r.CreatedAt.Time.Before(arg.CreatedTo.Time)) {
+			continue
+		}
+		if len(rows) < int(arg.PageSize) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeRepo) ListDeadOutbox(_ context.Context, arg db.ListDeadOutboxParams) ([]db.ListDeadOutboxRow, error) {
+	var rows []db.ListDeadOutboxRow
+	for _, r := range f.dead {
+		if r.ID > arg.AfterID && (arg.Topic == "" || r.Topic == arg.Topic) && len(rows) < int(arg.PageSize) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeRepo) CancelPendingOrderTransfer(_ context.Context, orderID pgtype.UUID) error {
+	for i, t := range f.transfers {
+		if t.OrderID == orderID && t.Status == "PENDING" {
+			f.transfers[i].Status = "CANCELLED"
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) CreateOrderTransfer(_ context.Context, arg db.CreateOrderTransferParams) (db.CreateOrderTransferRow, error) {
+	row := db.CreateOrderTransferRow{
+		TransferID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		OrderID:    arg.OrderID,
+		FromUserID: arg.FromUserID,
+		ToUserID:   arg.ToUserID,
+		Status:     "PENDING",
+		CreatedAt:  pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.transfers = append(f.transfers, row)
+	return row, nil
+}
+
+func (f *fakeRepo) GetPendingOrderTransferForUpdate(_ context.Context, arg db.GetPendingOrderTransferForUpdateParams) (db.GetPendingOrderTransferForUpdateRow, error) {
+	for _, t := range f.transfers {
+		if t.OrderID == arg.OrderID && t.ToUserID == arg.ToUserID && t.Status == "PENDING" {
+			return db.GetPendingOrderTransferForUpdateRow(t), nil
+		}
+	}
+	return db.GetPendingOrderTransferForUpdateRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) AcceptOrderTransfer(_ context.Context, transferID pgtype.UUID) error {
+	for i, t := range f.transfers {
+		if t.TransferID == transferID && t.Status == "PENDING" {
+			f.transfers[i].Status = "ACCEPTED"
+		}
+	}
+	return nil
+}
+
+// OrderHasPaymentInFlight mirrors the query: a pending installment, or a NEW
+// order paid up front whose PaymentRequested is in the outbox. Retries are
+// not modelled.
+func (f *fakeRepo) OrderHasPaymentInFlight(_ context.Context, orderID pgtype.UUID) (bool, error) {
+	hasPayments := false
+	for _, p := range f.payments {
+		if p.row.OrderID == orderID {
+			if p.row.Status == "PENDING" {
+				return true, nil
+			}
+			hasPayments = true
+		}
+	}
+	for _, o := range f.orders {
+		if o.row.OrderID != orderID || o.row.Status != "NEW" || hasPayments {
+			continue
+		}
+		for _, e := range f.outbox {
+			if e.KafkaKey == orderID.String() && e.Topic == "payments.payment_requested.v1" {
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+func (f *fakeRepo) SetOrderOwner(_ context.Context, arg db.SetOrderOwnerParams) (db.SetOrderOwnerRow, error) {
+	for i, o := range f.orders {
+		if o.row.OrderID == arg.OrderID && o.row.UserID == arg.FromUserID {
+			r := &f.orders[i].row
+			r.UserID = arg.ToUserID
+			r.Version++
+			r.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			return db.SetOrderOwnerRow(hotRow(*r)), nil
+		}
+	}
+	return db.SetOrderOwnerRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) GetOrderByID(_ context.Context, orderID pgtype.UUID) (db.GetOrderByIDRow, error) {
+	for _, o := range f.orders {
+		if o.row.OrderID == orderID {
+			return db.GetOrderByIDRow(o.row), nil
+		}
+	}
+	return db.GetOrderByIDRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) ListOrderPayments(_ context.Context, orderID pgtype.UUID) ([]db.ListOrderPaymentsRow, error) {
+	var rows []db.ListOrderPaymentsRow
+	for _, p := range f.payments {
+		if p.row.OrderID == orderID {
+			rows = append(rows, db.ListOrderPaymentsRow{PaymentID: p.row.PaymentID, Amount: p.row.Amount, Status: p.row.Status, CreatedAt: p.row.CreatedAt})
+		}
+	}
+	return rows, nil
+}
+
+// ListOrderPaymentRetries returns all of f.retries; tests only seed the
+// retries of one order.
+func (f *fakeRepo) ListOrderPaymentRetries(context.Context, pgtype.UUID) ([]db.ListOrderPaymentRetriesRow, error) {
+	return f.retries, nil
+}
+
+// ListOutboxByKey sees the unsent rows of f.outbox, newest first like the
+// query; their id is their position.
+func (f *fakeRepo) ListOutboxByKey(_ context.Context, arg db.ListOutboxByKeyParams) ([]db.ListOutboxByKeyRow, error) {
+	var rows []db.ListOutboxByKeyRow
+	for i := len(f.outbox) - 1; i >= 0 && len(rows) < int(arg.Limit); i-- {
+		if e := f.outbox[i]; e.KafkaKey == arg.KafkaKey {
+			rows = append(rows, db.ListOutboxByKeyRow{ID: int64(i + 1), Topic: e.Topic, Status: "PENDING", CorrelationID: e.CorrelationID})
+		}
+	}
+	return rows, nil
+}
+
+// RequeueDeadOutbox moves the matching rows of f.dead to f.requeued.
+func (f *fakeRepo) RequeueDeadOutbox(_ context.Context, arg db.RequeueDeadOutboxParams) (int64, error) {
+	var kept []db.ListDeadOutboxRow
+	var n int64
+	for _, r := range f.dead {
+		if (arg.AllEvents || slices.Contains(arg.Ids, r.ID)) && (arg.Topic == "" || r.Topic == arg.Topic) {
+			f.requeued = append(f.requeued, r.ID)
+			n++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	f.dead = kept
+	return n, nil
+}
+
+func (f *fakeRepo) ForceOrderStatus(_ context.Context, arg db.ForceOrderStatusParams) (db.ForceOrderStatusRow, error) {
+	for i, o := range f.orders {
+		if o.row.OrderID == arg.OrderID && !o.row.Archived {
+			r := &f.orders[i].row
+			previous := r.Status
+			r.Status = arg.Status
+			r.Version++
+			r.UpdatedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			h := hotRow(*r)
+			return db.ForceOrderStatusRow{OrderID: h.OrderID, UserID: h.UserID, Amount: h.Amount, Description: h.Description, Status: h.Status, CreatedAt: h.CreatedAt, PaymentFailureReason: h.PaymentFailureReason, PaidAmount: h.PaidAmount, FeeAmount: h.FeeAmount, Metadata: h.Metadata, Tags: h.Tags, Version: h.Version, UpdatedAt: h.UpdatedAt, PreviousStatus: previous}, nil
+		}
+	}
+	return db.ForceOrderStatusRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) InsertAdminAudit(_ context.Context, arg db.InsertAdminAuditParams) error {
+	f.audit = append(f.audit, arg)
+	return nil
+}
+
+// InsertReceipt numbers receipts by their position.
+func (f *fakeRepo) InsertReceipt(_ context.Context, arg db.InsertReceiptParams) (int64, error) {
+	for _, r := range f.receipts {
+		if r.OrderID == arg.OrderID {
+			return 0, nil
+		}
+	}
+	f.receipts = append(f.receipts, db.Receipt{
+		OrderID:        arg.OrderID,
+		Number:         fmt.Sprintf("R-%06d", len(f.receipts)+1),
+		UserID:         arg.UserID,
+		Items:          arg.Items,
+		Amount:         arg.Amount,
+		FeeAmount:      arg.FeeAmount,
+		TotalAmount:    arg.TotalAmount,
+		OrderCreatedAt: arg.OrderCreatedAt,
+		PaidAt:         arg.PaidAt,
+		IssuedAt:       pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	})
+	return 1, nil
+}
+
+func (f *fakeRepo) GetReceipt(_ context.Context, arg db.GetReceiptParams) (db.Receipt, error) {
+	for _, r := range f.receipts {
+		if r.OrderID == arg.OrderID && r.UserID == arg.UserID {
+			return r, nil
+		}
+	}
+	return db.Receipt{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) InsertOrderItem(_ context.Context, arg db.InsertOrderItemParams) error {
+	f.items = append(f.items, arg)
+	return nil
+}
+
+// itemCount is the item_count of the order, as in orders_read_source.
+func (f *fakeRepo) itemCount(orderID pgtype.UUID) int32 {
+	n := int32(0)
+	for _, it := range f.items {
+		if it.OrderID == orderID {
+			n++
+		}
+	}
+	return max(n, 1)
+}
+
+func (f *fakeRepo) ListOrderItems(_ context.Context, orderID pgtype.UUID) ([]db.ListOrderItemsRow, error) {
+	var out []db.ListOrderItemsRow
+	for _, it := range f.items {
+		if it.OrderID == orderID {
+			out = append(out, db.ListOrderItemsRow{ProductID: it.ProductID, Quantity: it.Quantity, UnitPrice: it.UnitPrice})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) CreatePromoCode(_ context.Context, arg db.CreatePromoCodeParams) (db.PromoCode, error) {
+	if _, err := f.GetPromoCode(context.Background(), arg.Code); err == nil {
+		return db.PromoCode{}, pgx.ErrNoRows
+	}
+	c := db.PromoCode{
+		Code:                  arg.Code,
+		PercentOff:            arg.PercentOff,
+		AmountOff:             arg.AmountOff,
+		MaxRedemptions:        arg.MaxRedemptions,
+		MaxRedemptionsPerUser: arg.MaxRedemptionsPerUser,
+		StartsAt:              arg.StartsAt,
+		ExpiresAt:             arg.ExpiresAt,
+		CreatedAt:             pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.promos = append(f.promos, c)
+	return c, nil
+}
+
+func (f *fakeRepo) GetPromoCode(_ context.Context, code string) (db.PromoCode, error) {
+	for _, c := range f.promos {
+		if c.Code == code {
+			return c, nil
+		}
+	}
+	return db.PromoCode{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) GetPromoCodeForUpdate(ctx context.Context, code string) (db.PromoCode, error) {
+	return f.GetPromoCode(ctx, code)
+}
+
+func (f *fakeRepo) CountUserPromoRedemptions(_ context.Context, arg db.CountUserPromoRedemptionsParams) (int64, error) {
+	var n int64
+	for _, r := range f.redeemed {
+		if r.Code == arg.Code && r.UserID == arg.UserID && !r.ReleasedAt.Valid {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeRepo) InsertPromoRedemption(_ context.Context, arg db.InsertPromoRedemptionParams) error {
+	for i := range f.promos {
+		if f.promos[i].Code == arg.Code {
+			f.promos[i].RedeemedCount++
+		}
+	}
+	f.redeemed = append(f.redeemed, db.PromoRedemption{OrderID: arg.OrderID, Code: arg.Code, UserID: arg.UserID, Amount: arg.Amount, Discount: arg.Discount})
+	return nil
+}
+
+func (f *fakeRepo) ReleasePromoRedemption(_ context.Context, orderID pgtype.UUID) (int64, error) {
+	for i := range f.redeemed {
+		r := &f.redeemed[i]
+		if r.OrderID != orderID || r.ReleasedAt.Valid {
+			continue
+		}
+		r.ReleasedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		for j := range f.promos {
+			if f.promos[j].Code == r.Code {
+				f.promos[j].RedeemedCount--
+			}
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (f *fakeRepo) GetReleasedPromoRedemption(_ context.Context, orderID pgtype.UUID) (db.GetReleasedPromoRedemptionRow, error) {
+	for _, r := range f.redeemed {
+		if r.OrderID == orderID && r.ReleasedAt.Valid {
+			return db.GetReleasedPromoRedemptionRow{Code: r.Code, UserID: r.UserID}, nil
+		}
+	}
+	return db.GetReleasedPromoRedemptionRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) ReclaimPromoRedemption(_ context.Context, orderID pgtype.UUID) (int64, error) {
+	for i := range f.redeemed {
+		r := &f.redeemed[i]
+		if r.OrderID != orderID || !r.ReleasedAt.Valid {
+			continue
+		}
+		r.ReleasedAt = pgtype.Timestamptz{}
+		for j := range f.promos {
+			if f.promos[j].Code == r.Code {
+				f.promos[j].RedeemedCount++
+			}
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func (f *fakeRepo) GetPromoRedemption(_ context.Context, orderID pgtype.UUID) (db.GetPromoRedemptionRow, error) {
+	for _, r := range f.redeemed {
+		if r.OrderID == orderID && !r.ReleasedAt.Valid {
+			return db.GetPromoRedemptionRow{Code: r.Code, Amount: r.Amount, Discount: r.Discount}, nil
+		}
+	}
+	return db.GetPromoRedemptionRow{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) GetPromoCodeStats(_ context.Context, code string) (db.GetPromoCodeStatsRow, error) {
+	var s db.GetPromoCodeStatsRow
+	for _, r := range f.redeemed {
+		switch {
+		case r.Code != code:
+		case r.ReleasedAt.Valid:
+			s.Released++
+		default:
+			s.Redemptions++
+			s.DiscountTotal += r.Discount
+			s.AmountTotal += r.Amount
+		}
+	}
+	return s, nil
+}
+
+func (f *fakeRepo) ListOrderStatusHistory(_ context.Context, arg db.ListOrderStatusHistoryParams) ([]db.OrderStatusHistory, error) {
+	var rows []db.OrderStatusHistory
+	for _, h := range f.history {
+		if h.OrderID == arg.OrderID && len(rows) < int(arg.MaxRows) {
+			rows = append(rows, h)
+		}
+	}
+	return rows, nil
+}
+
+// CreateProjectionReplay numbers replays by their position and refuses a
+// second running replay of a projection.
+func (f *fakeRepo) CreateProjectionReplay(_ context.Context, arg db.CreateProjectionReplayParams) (db.ProjectionReplay, error) {
+	for _, r := range f.replays {
+		if r.Projection == arg.Projection && r.Status == "RUNNING" {
+			return db.ProjectionReplay{}, pgx.ErrNoRows
+		}
+	}
+	r := db.ProjectionReplay{
+		ID:          int64(len(f.replays) + 1),
+		Projection:  arg.Projection,
+		Topic:       arg.Topic,
+		Status:      "RUNNING",
+		RateLimit:   arg.RateLimit,
+		RequestedBy: arg.RequestedBy,
+		Reason:      arg.Reason,
+		CreatedAt:   pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.replays = append(f.replays, r)
+	return r, nil
+}
+
+func (f *fakeRepo) GetProjectionReplay(_ context.Context, id int64) (db.ProjectionReplay, error) {
+	if id < 1 || id > int64(len(f.replays)) {
+		return db.ProjectionReplay{}, pgx.ErrNoRows
+	}
+	return f.replays[id-1], nil
+}
+
+func (f *fakeRepo) FinishProjectionReplay(_ context.Context, arg db.FinishProjectionReplayParams) (db.ProjectionReplay, error) {
+	r, err := f.GetProjectionReplay(context.Background(), arg.ID)
+	if err != nil || r.Status != "RUNNING" {
+		return db.ProjectionReplay{}, pgx.ErrNoRows
+	}
+	r.Status, r.Error = arg.Status, arg.Error
+	r.FinishedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	f.replays[arg.ID-1] = r
+	return r, nil
+}
+
+func (f *fakeRepo) ListProjectionReplayOffsets(_ context.Context, id int64) ([]db.ProjectionReplayOffset, error) {
+	var rows []db.ProjectionReplayOffset
+	for _, o := range f.offsets {
+		if o.ReplayID == id {
+			rows = append(rows, o)
+		}
+	}
+	return rows, nil
+}
@@ -10,210 +10,424 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/catalog"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/promo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
 
 	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
 
-	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
 )
 
 type Handlers struct {
 	ordersv1.UnimplementedOrdersServiceServer
-	repo  *postgres.Repo
-	cache *cache.OrderCache
+	repo     repo.OrdersRepository
+	cache    cache.OrderCache
+	payments paymentsv1.PaymentsServiceClient
+	prices   catalog.PriceResolver
+	bus      StatusBus
+
+	knownAccounts bool
+	currency      money.Currency
+	// maxNewOrders caps the NEW orders a user may hold; 0 is unlimited.
+	maxNewOrders int
+	// duplicateWindow is how far back CreateOrder looks for an identical
+	// order; 0 disables duplicate detection.
+	duplicateWindow time.Duration
+	// maxPaymentRetries is how often RetryPayment may re-request the payment
+	// of an order; 0 disables RetryPayment.
+	maxPaymentRetries int
+	// waits holds a slot per WaitOrder call in progress when LimitWaits set
+	// a cap.
+	waits chan struct{}
+	// renderers render receipts by format; see UseReceiptRenderer.
+	renderers map[string]receipt.Renderer
+
+	logger *slog.Logger
 }
 
-var logger = slog.Default().With("service", "orders-service", "component", "grpc")
+// DuplicateOrderReason is the google.rpc.ErrorInfo reason of CreateOrder
+// rejections for a likely duplicate; metadata.order_id holds the original.
+const DuplicateOrderReason = "DUPLICATE_ORDER"
 
-func NewHandlers(repo *postgres.Repo, cache *cache.OrderCache) *Handlers {
-	logger.Info("handlers initialized")
-	return &Handlers{repo: repo, cache: cache}
+// NewHandlers builds the orders gRPC handlers. payments is optional; when set,
+// CreateOrder checks that the user has a payment account before accepting.
+// prices resolves item prices for orders placed by product id. With
+// knownAccounts set, users missing from the known_accounts projection are
+// rejected unless payments confirms the account. All amounts are in currency.
+// maxNewOrders caps the unpaid (NEW) orders per user; 0 disables the quota.
+// An order with the amount and description of one the user created within
+// duplicateWindow is rejected unless forced; 0 disables the check.
+// maxPaymentRetries bounds RetryPayment per order; 0 disables it.
+// bus wakes WaitOrder calls; without it WaitOrder is unimplemented.
+func NewHandlers(repo repo.OrdersRepository, cache cache.OrderCache, payments paymentsv1.PaymentsServiceClient, prices catalog.PriceResolver, knownAccounts bool, currency money.Currency, maxNewOrders int, duplicateWindow time.Duration, maxPaymentRetries int, bus StatusBus) *Handlers {
+	logger := slog.Default().With("service", "orders-service", "component", "grpc")
+	logger.Info("handlers initialized", "currency", currency, "max_new_orders", maxNewOrders, "duplicate_window", duplicateWindow, "max_payment_retries", maxPaymentRetries)
+	return &Handlers{repo: repo, cache: cache, payments: payments, prices: prices, bus: bus, knownAccounts: knownAccounts, currency: currency, maxNewOrders: maxNewOrders, duplicateWindow: duplicateWindow, maxPaymentRetries: maxPaymentRetries, logger: logger}
 }
 
 func (h *Handlers) CreateOrder(ctx context.Context, req *ordersv1.CreateOrderRequest) (resp *ordersv1.CreateOrderResponse, err error) {
 	start := time.Now()
-	logger.Info("create order start", "user_id", req.GetUserId(), "amount", req.GetAmount(), "has_idempotency_key", req.GetIdempotencyKey() != "")
+	h.logger.DebugContext(ctx, "create order start", "user_id", req.GetUserId(), "amount", req.GetAmount(), "has_idempotency_key", req.GetIdempotencyKey() != "")
 	defer func() {
 		if err != nil {
-			logger.Error("create order failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "create order failed", "err", err, "duration", time.Since(start))
 			return
 		}
 		orderID := ""
 		if resp != nil && resp.Order != nil {
 			orderID = resp.Order.OrderId
 		}
-		logger.Info("create order completed", "order_id", orderID, "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "create order completed", "order_id", orderID, "duration", time.Since(start))
 	}()
 
-	if req.GetUserId() == "" {
-		err = status.Error(codes.InvalidArgument, "user_id is required")
-		logger.Error("create order validation failed", "err", err)
-		return nil, err
-	}
-	if req.GetAmount() <= 0 {
-		err = status.Error(codes.InvalidArgument, "amount must be > 0")
-		logger.Error("create order validation failed", "err", err)
-		return nil, err
-	}
-	if req.GetDescription() == "" {
-		err = status.Error(codes.InvalidArgument, "description is required")
-		logger.Error("create order validation failed", "err", err)
+	total, items, metadata, tags, err := h.validateCreate(ctx, req)
+	if err != nil {
 		return nil, err
 	}
 
-	err = h.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
-		idemKey := req.GetIdempotencyKey()
-		var (
-			orderID     string
-			userID      string
-			amount      int64
-			description string
-			statusText  string
-			createdAt   time.Time
-		)
-
-		if idemKey == "" {
-			row, err := q.CreateOrder(ctx, db.CreateOrderParams{
-				UserID:      req.GetUserId(),
-				Amount:      req.GetAmount(),
-				Description: req.GetDescription(),
-			})
-			if err != nil {
-				logger.Error("failed to create order", "err", err)
-				return err
-			}
-			orderID = row.OrderID.String()
-			userID = row.UserID
-			amount = row.Amount
-			description = row.Description
-			statusText = row.Status
-			createdAt = row.CreatedAt.Time
-		} else {
-			row, err := q.CreateOrderIdempotent(ctx, db.CreateOrderIdempotentParams{
-				UserID:      req.GetUserId(),
-				Amount:      req.GetAmount(),
-				Description: req.GetDescription(),
-				IdempotencyKey: pgtype.Text{
-					String: idemKey,
-					Valid:  true,
-				},
-			})
-			if err != nil {
-				if errors.Is(err, pgx.ErrNoRows) {
-					existing, err := q.GetOrderByIdempotency(ctx, db.GetOrderByIdempotencyParams{
-						UserID: req.GetUserId(),
-						IdempotencyKey: pgtype.Text{
-							String: idemKey,
-							Valid:  true,
-						},
-					})
-					if err != nil {
-						logger.Error("failed to load order by idempotency key", "err", err)
-						return err
-					}
-					if existing.Amount != req.GetAmount() || existing.Description != req.GetDescription() {
-						err = status.Error(codes.FailedPrecondition, "idempotency key reuse with different parameters")
-						logger.Error("idempotency key reuse with different parameters", "err", err)
-						return err
-					}
-					resp = &ordersv1.CreateOrderResponse{
-						Order: &ordersv1.Order{
-							OrderId:     existing.OrderID.String(),
-							UserId:      existing.UserID,
-							Amount:      existing.Amount,
-							Description: existing.Description,
-							Status:      mapOrderStatus(existing.Status),
-							CreatedAt:   timestamppb.New(existing.CreatedAt.Time),
-						},
-					}
-					return nil
-				}
-				logger.Error("failed to create order with idempotency key", "err", err)
-				return err
-			}
-			orderID = row.OrderID.String()
-			userID = row.UserID
-			amount = row.Amount
-			description = row.Description
-			statusText = row.Status
-			createdAt = row.CreatedAt.Time
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		create := func() (*ordersv1.CreateOrderResponse, error) {
+			return h.createOrder(ctx, q, req, total, items, metadata, tags)
+		}
+		if req.GetIdempotencyKey() == "" {
+			resp, err = create()
+			return err
+		}
+		var replayed bool
+		resp, replayed, err = idempotency.Do(ctx, repo.IdempotencyStore(q), idempotency.Key{
+			Scope:  "orders.CreateOrder",
+			UserID: req.GetUserId(),
+			Key:    req.GetIdempotencyKey(),
+		}, req, &ordersv1.CreateOrderResponse{}, create)
+		if replayed {
+			h.logger.InfoContext(ctx, "create order replayed", "order_id", resp.GetOrder().GetOrderId())
 		}
+		return err
+	})
 
-		ev := &eventsv1.PaymentRequested{
-			EventId:    uuid.NewString(),
-			OccurredAt: timestamppb.Now(),
-			OrderId:    orderID,
-			UserId:     req.GetUserId(),
-			Amount:     req.GetAmount(),
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			err = st.Err()
+			return nil, err
 		}
+		err = status.Error(codes.Internal, "failed to create order")
+		return nil, err
+	}
 
-		payload, err := proto.Marshal(ev)
+	if h.cache != nil {
+		if err := h.cache.InvalidateList(ctx, req.GetUserId()); err != nil {
+			h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", req.GetUserId())
+		}
+	}
+	return resp, nil
+}
+
+// ValidateOrder is a dry run of CreateOrder: the same checks, nothing stored.
+func (h *Handlers) ValidateOrder(ctx context.Context, req *ordersv1.CreateOrderRequest) (resp *ordersv1.ValidateOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "validate order start", "user_id", req.GetUserId(), "amount", req.GetAmount(), "items", len(req.GetItems()))
+	defer func() {
 		if err != nil {
-			err = status.Error(codes.Internal, "failed to marshal event")
-			logger.Error("failed to marshal payment requested event", "err", err)
-			return err
+			h.logger.InfoContext(ctx, "validate order rejected", "err", err, "duration", time.Since(start))
+			return
 		}
+		h.logger.InfoContext(ctx, "validate order completed", "amount", resp.GetAmount(), "duration", time.Since(start))
+	}()
 
-		_, err = q.InsertOutbox(ctx, db.InsertOutboxParams{
-			Topic:    "payments.payment_requested.v1",
-			KafkaKey: orderID,
-			Payload:  payload,
+	total, _, _, _, err := h.validateCreate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	var discount int64
+	if req.GetPromoCode() != "" {
+		err = h.repo.Read(ctx, func(q db.Querier) error {
+			var err error
+			discount, err = promo.Quote(ctx, q, req.GetPromoCode(), req.GetUserId(), total)
+			return err
 		})
 		if err != nil {
-			logger.Error("failed to insert outbox event", "err", err)
-			return err
+			return nil, promoError(err, req.GetPromoCode())
 		}
+	}
+	return &ordersv1.ValidateOrderResponse{Amount: total - discount, Currency: string(h.currency), DiscountAmount: discount}, nil
+}
 
-		resp = &ordersv1.CreateOrderResponse{
-			Order: &ordersv1.Order{
-				OrderId:     orderID,
-				UserId:      userID,
-				Amount:      amount,
-				Description: description,
-				Status:      mapOrderStatus(statusText),
-				CreatedAt:   timestamppb.New(createdAt),
-			},
+// validateCreate runs the checks of CreateOrder and returns the order total,
+// the priced items it was resolved from, if any, and the encoded metadata
+// and tags.
+func (h *Handlers) validateCreate(ctx context.Context, req *ordersv1.CreateOrderRequest) (total int64, items []pricedItem, metadata []byte, tags []string, err error) {
+	if req.GetUserId() == "" {
+		err = status.Error(codes.InvalidArgument, "user_id is required")
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	total = req.GetAmount()
+	if len(req.GetItems()) > 0 {
+		if total != 0 {
+			err = status.Error(codes.InvalidArgument, "amount must be empty when items are set")
+			h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+			return 0, nil, nil, nil, err
 		}
-		return nil
-	})
+		if total, items, err = h.resolveAmount(ctx, req.GetItems()); err != nil {
+			return 0, nil, nil, nil, err
+		}
+	}
+	if err = validateAmount(total); err != nil {
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	if err = h.checkCurrency(req.GetCurrency()); err != nil {
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	if err = validatePayAt(req, time.Now()); err != nil {
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	if _, err = paymentMethod(req.GetPaymentMethod()); err != nil {
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	if req.GetDescription() == "" {
+		err = status.Error(codes.InvalidArgument, "description is required")
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	if err = validateMetadata(req.GetMetadata(), req.GetTags()); err != nil {
+		h.logger.ErrorContext(ctx, "create order validation failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+	metadata, tags, err = encodeMetadata(req.GetMetadata(), req.GetTags())
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, "invalid metadata")
+		h.logger.ErrorContext(ctx, "create order metadata encode failed", "err", err)
+		return 0, nil, nil, nil, err
+	}
+
+	if err = h.checkAccount(ctx, req.GetUserId()); err != nil {
+		return 0, nil, nil, nil, err
+	}
+	return total, items, metadata, tags, nil
+}
 
+// createOrder inserts the order and, unless it is paid in installments or
+// scheduled, enqueues its PaymentRequested event. Scheduled orders get theirs
+// from kafka.PaymentScheduler once pay_at is due. A promo code is redeemed
+// with the order and its discount taken off total. items are stored with
+// the order for its receipt.
+func (h *Handlers) createOrder(ctx context.Context, q db.Querier, req *ordersv1.CreateOrderRequest, total int64, items []pricedItem, metadata []byte, tags []string) (*ordersv1.CreateOrderResponse, error) {
+	if h.maxNewOrders > 0 || h.duplicateWindow > 0 {
+		// Held until commit, so concurrent creates of the user cannot all
+		// pass the checks below.
+		if err := q.LockUserOrderCreate(ctx, req.GetUserId()); err != nil {
+			h.logger.ErrorContext(ctx, "failed to lock user order creation", "err", err, "user_id", req.GetUserId())
+			return nil, err
+		}
+	}
+	if err := h.checkNewOrdersQuota(ctx, q, req.GetUserId()); err != nil {
+		return nil, err
+	}
+	var discount int64
+	if req.GetPromoCode() != "" {
+		var err error
+		if discount, err = promo.Reserve(ctx, q, req.GetPromoCode(), req.GetUserId(), total); err != nil {
+			h.logger.WarnContext(ctx, "promo code rejected", "err", err, "user_id", req.GetUserId(), "promo_code", req.GetPromoCode())
+			return nil, promoError(err, req.GetPromoCode())
+		}
+	}
+	charged := total - discount
+	method, err := paymentMethod(req.GetPaymentMethod())
 	if err != nil {
-		if st, ok := status.FromError(err); ok {
-			err = st.Err()
+		return nil, err
+	}
+	// A request with an idempotency key is a deliberate new order: its
+	// retries replay the first answer, so only keyless submissions are
+	// suspected of being duplicates.
+	if !req.GetForce() && req.GetIdempotencyKey() == "" {
+		if err := h.checkDuplicate(ctx, q, req.GetUserId(), charged, req.GetDescription()); err != nil {
 			return nil, err
 		}
-		err = status.Error(codes.Internal, "failed to create order")
+	}
+	row, err := q.CreateOrder(ctx, db.CreateOrderParams{
+		UserID:            req.GetUserId(),
+		Amount:            charged,
+		Description:       req.GetDescription(),
+		Metadata:          metadata,
+		Tags:              tags,
+		PayAt:             pgtype.Timestamptz{Time: req.GetPayAt().AsTime(), Valid: req.PayAt != nil},
+		PaymentMethod:     method,
+		PayInInstallments: req.GetPayInInstallments(),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create order", "err", err)
 		return nil, err
 	}
-	return resp, nil
+	orderID := row.OrderID.String()
+	for i, item := range items {
+		if err := q.InsertOrderItem(ctx, db.InsertOrderItemParams{
+			OrderID:   row.OrderID,
+			Line:      int32(i + 1),
+			ProductID: item.productID,
+			Quantity:  item.quantity,
+			UnitPrice: item.unitPrice,
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "failed to insert order item", "err", err, "order_id", orderID, "product_id", item.productID)
+			return nil, err
+		}
+	}
+	if req.GetPromoCode() != "" {
+		if err := promo.Redeem(ctx, q, promo.Normalize(req.GetPromoCode()), row.OrderID, row.UserID, total, discount); err != nil {
+			h.logger.ErrorContext(ctx, "failed to redeem promo code", "err", err, "order_id", orderID)
+			return nil, err
+		}
+	}
+
+	// Installment orders are paid via PayOrder instead of up front.
+	if !req.GetPayInInstallments() && req.PayAt == nil {
+		payload, err := kafkasvc.MarshalEvent(&eventsv1.PaymentRequested{
+			EventId:       uuid.NewString(),
+			OccurredAt:    timestamppb.Now(),
+			OrderId:       orderID,
+			UserId:        req.GetUserId(),
+			Amount:        charged,
+			CorrelationId: logging.RequestID(ctx),
+			PaymentMethod: row.PaymentMethod,
+		})
+		if err != nil {
+			err = status.Error(codes.Internal, "failed to marshal event")
+			h.logger.ErrorContext(ctx, "failed to marshal payment requested event", "err", err)
+			return nil, err
+		}
+
+		if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+			Topic:         "payments.payment_requested.v1",
+			KafkaKey:      orderID,
+			Payload:       payload,
+			CorrelationID: logging.RequestID(ctx),
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "failed to insert outbox event", "err", err)
+			return nil, err
+		}
+	}
+	if err := kafkasvc.InsertOrderChanged(ctx, q, kafkasvc.OrderChange{OrderID: orderID, UserID: row.UserID}); err != nil {
+		h.logger.ErrorContext(ctx, "failed to insert order changed event", "err", err, "order_id", orderID)
+		return nil, err
+	}
+
+	return &ordersv1.CreateOrderResponse{
+		Order: &ordersv1.Order{
+			OrderId:     orderID,
+			UserId:      row.UserID,
+			Amount:      row.Amount,
+			Description: row.Description,
+			Status:      mapOrderStatus(row.Status),
+			CreatedAt:   timestamppb.New(row.CreatedAt.Time),
+			Currency:    string(h.currency),
+			Metadata:    req.GetMetadata(),
+			Tags:        req.GetTags(),
+			Version:     row.Version,
+			UpdatedAt:   timestamppb.New(row.UpdatedAt.Time),
+			PayAt:       optionalTimestamp(row.PayAt),
+		},
+		DiscountAmount: discount,
+	}, nil
+}
+
+// checkNewOrdersQuota rejects the order when the user already holds
+// maxNewOrders NEW orders.
+func (h *Handlers) checkNewOrdersQuota(ctx context.Context, q db.Querier, userID string) error {
+	if h.maxNewOrders <= 0 {
+		return nil
+	}
+	n, err := q.CountNewOrders(ctx, userID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to count new orders", "err", err, "user_id", userID)
+		return err
+	}
+	if n >= int64(h.maxNewOrders) {
+		h.logger.WarnContext(ctx, "new orders quota exceeded", "user_id", userID, "new_orders", n, "max", h.maxNewOrders)
+		return status.Errorf(codes.ResourceExhausted, "too many unpaid orders: at most %d orders may be NEW", h.maxNewOrders)
+	}
+	return nil
+}
+
+// checkDuplicate rejects the order when the user created one with the same
+// amount and description within duplicateWindow, most likely by submitting
+// the form twice. Cancelled orders do not count, so a failed order can be
+// placed again. The error carries the existing order id.
+func (h *Handlers) checkDuplicate(ctx context.Context, q db.Querier, userID string, amount int64, description string) error {
+	if h.duplicateWindow <= 0 {
+		return nil
+	}
+	existing, err := q.FindRecentDuplicateOrder(ctx, db.FindRecentDuplicateOrderParams{
+		UserID:      userID,
+		Amount:      amount,
+		Description: description,
+		Since:       pgtype.Timestamptz{Time: time.Now().Add(-h.duplicateWindow), Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to look up duplicate orders", "err", err, "user_id", userID)
+		return err
+	}
+	h.logger.WarnContext(ctx, "duplicate order rejected", "user_id", userID, "existing_order_id", existing.String())
+	st, err := status.New(codes.AlreadyExists, "order duplicates a recent one; set force to create it anyway").WithDetails(&errdetails.ErrorInfo{
+		Reason:   DuplicateOrderReason,
+		Domain:   "orders-service",
+		Metadata: map[string]string{"order_id": existing.String()},
+	})
+	if err != nil {
+		return status.Error(codes.Internal, "failed to build duplicate order error")
+	}
+	return st.Err()
 }
 
 func (h *Handlers) ListOrders(ctx context.Context, req *ordersv1.ListOrdersRequest) (resp *ordersv1.ListOrdersResponse, err error) {
 	start := time.Now()
-	logger.Info("list orders start", "user_id", req.GetUserId(), "limit", req.GetLimit(), "page_token", req.GetPageToken() != "")
+	h.logger.DebugContext(ctx, "list orders start", "user_id", req.GetUserId(), "limit", req.GetLimit(), "page_token", req.GetPageToken() != "", "tag", req.GetTag(), "include_archived", req.GetIncludeArchived())
 	defer func() {
 		if err != nil {
-			logger.Error("list orders failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "list orders failed", "err", err, "duration", time.Since(start))
 			return
 		}
 		count := 0
 		if resp != nil {
 			count = len(resp.Orders)
 		}
-		logger.Info("list orders completed", "orders_count", count, "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "list orders completed", "orders_count", count, "duration", time.Since(start))
 	}()
 
 	if req.GetUserId() == "" {
 		err = status.Error(codes.InvalidArgument, "user_id is required")
-		logger.Error("list orders validation failed", "err", err)
+		h.logger.ErrorContext(ctx, "list orders validation failed", "err", err)
+		return nil, err
+	}
+	if req.GetTag() != "" {
+		if err = validateTag(req.GetTag()); err != nil {
+			h.logger.ErrorContext(ctx, "list orders validation failed", "err", err)
+			return nil, err
+		}
+	}
+	trim, err := orderReadMask(req.GetReadMask())
+	if err != nil {
+		h.logger.ErrorContext(ctx, "list orders validation failed", "err", err)
 		return nil, err
 	}
 
@@ -226,32 +440,70 @@ func (h *Handlers) ListOrders(ctx context.Context, req *ordersv1.ListOrdersReque
 		n, err := decodeOffset(req.GetPageToken())
 		if err != nil {
 			err = status.Error(codes.InvalidArgument, "invalid page_token")
-			logger.Error("list orders invalid page token", "err", err)
+			h.logger.ErrorContext(ctx, "list orders invalid page token", "err", err)
 			return nil, err
 		}
 		offset = n
 	}
 
-	rows, err := h.repo.Q().ListOrders(ctx, db.ListOrdersParams{
-		UserID: req.GetUserId(),
-		Limit:  limit,
-		Offset: offset,
+	// Only the unfiltered first page is cached.
+	firstPage := offset == 0 && req.GetTag() == "" && !req.GetIncludeArchived() && h.cache != nil
+	if firstPage {
+		if cached, err := h.cache.GetList(ctx, req.GetUserId(), limit); err == nil && cached != nil {
+			h.logger.DebugContext(ctx, "list orders cache hit", "user_id", req.GetUserId())
+			out := make([]*ordersv1.Order, 0, len(cached.Orders))
+			for _, o := range cached.Orders {
+				order := h.orderFromCache(o)
+				trim(order)
+				out = append(out, order)
+			}
+			resp = &ordersv1.ListOrdersResponse{
+				Orders:        out,
+				NextPageToken: cached.NextPageToken,
+			}
+			return resp, nil
+		}
+	}
+
+	var rows []db.OrdersRead
+	err = h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		rows, err = q.ListOrders(ctx, db.ListOrdersParams{
+			UserID:          req.GetUserId(),
+			Tag:             req.GetTag(),
+			IncludeArchived: req.GetIncludeArchived(),
+			Limit:           limit,
+			Offset:          offset,
+		})
+		return err
 	})
 	if err != nil {
 		err = status.Error(codes.Internal, "failed to list orders")
-		logger.Error("list orders query failed", "err", err)
+		h.logger.ErrorContext(ctx, "list orders query failed", "err", err)
 		return nil, err
 	}
 
 	out := make([]*ordersv1.Order, 0, len(rows))
 	for _, r := range rows {
 		out = append(out, &ordersv1.Order{
-			OrderId:     r.OrderID.String(),
-			UserId:      r.UserID,
-			Amount:      r.Amount,
-			Description: r.Description,
-			Status:      mapOrderStatus(r.Status),
-			CreatedAt:   timestamppb.New(r.CreatedAt.Time),
+			OrderId:              r.OrderID.String(),
+			UserId:               r.UserID,
+			Amount:               r.Amount,
+			Description:          r.Description,
+			Status:               mapOrderStatus(r.Status),
+			CreatedAt:            timestamppb.New(r.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: r.PaymentFailureReason.String,
+			PaidAmount:           r.PaidAmount,
+			FeeAmount:            r.FeeAmount,
+			Metadata:             decodeMetadata(r.Metadata),
+			Tags:                 r.Tags,
+			Version:              r.Version,
+			UpdatedAt:            timestamppb.New(r.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(r.PayAt),
+			Archived:             r.Archived,
+			LastPaymentReason:    r.LastPaymentReason.String,
+			ItemCount:            r.ItemCount,
 		})
 	}
 
@@ -260,6 +512,19 @@ func (h *Handlers) ListOrders(ctx context.Context, req *ordersv1.ListOrdersReque
 		nextToken = encodeOffset(offset + limit)
 	}
 
+	if firstPage {
+		list := cache.OrderList{Limit: limit, Orders: make([]cache.Order, 0, len(rows)), NextPageToken: nextToken}
+		for _, r := range rows {
+			list.Orders = append(list.Orders, cacheListOrderFromRow(r))
+		}
+		if err := h.cache.SetList(ctx, req.GetUserId(), list); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order list cache", "err", err, "user_id", req.GetUserId())
+		}
+	}
+
+	for _, order := range out {
+		trim(order)
+	}
 	resp = &ordersv1.ListOrdersResponse{
 		Orders:        out,
 		NextPageToken: nextToken,
@@ -269,94 +534,521 @@ func (h *Handlers) ListOrders(ctx context.Context, req *ordersv1.ListOrdersReque
 
 func (h *Handlers) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (resp *ordersv1.GetOrderResponse, err error) {
 	start := time.Now()
-	logger.Info("get order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	h.logger.DebugContext(ctx, "get order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
 	defer func() {
 		if err != nil {
-			logger.Error("get order failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "get order failed", "err", err, "duration", time.Since(start))
 			return
 		}
-		logger.Info("get order completed", "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "get order completed", "duration", time.Since(start))
 	}()
 
 	if req.GetUserId() == "" || req.GetOrderId() == "" {
 		err = status.Error(codes.InvalidArgument, "user_id and order_id are required")
-		logger.Error("get order validation failed", "err", err)
+		h.logger.ErrorContext(ctx, "get order validation failed", "err", err)
 		return nil, err
 	}
 
 	oid, err := uuid.Parse(req.GetOrderId())
 	if err != nil {
 		err = status.Error(codes.InvalidArgument, "invalid order_id")
-		logger.Error("get order invalid order id", "err", err)
+		h.logger.ErrorContext(ctx, "get order invalid order id", "err", err)
+		return nil, err
+	}
+	trim, err := orderReadMask(req.GetReadMask())
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get order validation failed", "err", err)
 		return nil, err
 	}
 
-	if cached, err := h.cache.Get(ctx, req.GetOrderId()); err == nil && cached != nil {
-		logger.Info("get order cache hit", "order_id", req.GetOrderId())
-		if cached.UserID == req.GetUserId() {
-			resp = &ordersv1.GetOrderResponse{
-				Order: &ordersv1.Order{
-					OrderId:     cached.OrderID,
-					UserId:      cached.UserID,
-					Amount:      cached.Amount,
-					Description: cached.Description,
-					Status:      mapOrderStatus(cached.Status),
-					CreatedAt:   timestamppb.New(cached.CreatedAt),
-				},
+	if h.cache != nil {
+		if cached, err := h.cache.Get(ctx, req.GetOrderId()); err == nil && cached != nil {
+			h.logger.DebugContext(ctx, "get order cache hit", "order_id", req.GetOrderId())
+			if cached.UserID == req.GetUserId() {
+				resp = &ordersv1.GetOrderResponse{
+					Order: h.orderFromCache(*cached),
+				}
+				trim(resp.Order)
+				return resp, nil
 			}
-			return resp, nil
 		}
 	}
-	logger.Info("get order cache miss", "order_id", req.GetOrderId())
+	h.logger.DebugContext(ctx, "get order cache miss", "order_id", req.GetOrderId())
 
-	r, err := h.repo.Q().GetOrder(ctx, db.GetOrderParams{
-		OrderID: pgtype.UUID{
-			Bytes: oid,
-			Valid: true,
-		},
-		UserID: req.GetUserId(),
+	var r db.GetOrderRow
+	err = h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		r, err = q.GetOrder(ctx, db.GetOrderParams{
+			OrderID: pgtype.UUID{
+				Bytes: oid,
+				Valid: true,
+			},
+			UserID: req.GetUserId(),
+		})
+		return err
 	})
 	if err != nil {
 		err = status.Error(codes.NotFound, "order not found")
-		logger.Error("get order query failed", "err", err)
+		h.logger.ErrorContext(ctx, "get order query failed", "err", err)
 		return nil, err
 	}
 
 	if h.cache != nil {
-		if err := h.cache.Set(ctx, cache.Order{
-			OrderID:     r.OrderID.String(),
-			UserID:      r.UserID,
-			Amount:      r.Amount,
-			Description: r.Description,
-			Status:      r.Status,
-			CreatedAt:   r.CreatedAt.Time,
-		}); err != nil {
-			logger.Error("failed to set order cache", "err", err, "order_id", r.OrderID.String())
+		if err := h.cache.Set(ctx, cacheOrderFromRow(r)); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", r.OrderID.String())
 		}
 	}
 
 	resp = &ordersv1.GetOrderResponse{
 		Order: &ordersv1.Order{
-			OrderId:     r.OrderID.String(),
-			UserId:      r.UserID,
-			Amount:      r.Amount,
-			Description: r.Description,
-			Status:      mapOrderStatus(r.Status),
-			CreatedAt:   timestamppb.New(r.CreatedAt.Time),
+			OrderId:              r.OrderID.String(),
+			UserId:               r.UserID,
+			Amount:               r.Amount,
+			Description:          r.Description,
+			Status:               mapOrderStatus(r.Status),
+			CreatedAt:            timestamppb.New(r.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: r.PaymentFailureReason.String,
+			PaidAmount:           r.PaidAmount,
+			FeeAmount:            r.FeeAmount,
+			Metadata:             decodeMetadata(r.Metadata),
+			Tags:                 r.Tags,
+			Version:              r.Version,
+			UpdatedAt:            timestamppb.New(r.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(r.PayAt),
+			Archived:             r.Archived,
+			Dispute:              disputeToProto(orderDispute(r.DisputeStatus, r.DisputeAmount, r.DisputeReason, r.DisputeOpenedAt, r.DisputeResolvedAt)),
+		},
+	}
+	trim(resp.Order)
+	return resp, nil
+}
+
+func (h *Handlers) PayOrder(ctx context.Context, req *ordersv1.PayOrderRequest) (resp *ordersv1.PayOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "pay order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId(), "amount", req.GetAmount(), "has_idempotency_key", req.GetIdempotencyKey() != "")
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "pay order failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "pay order completed", "order_id", req.GetOrderId(), "payment_id", resp.GetPaymentId(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		err = status.Error(codes.InvalidArgument, "user_id and order_id are required")
+		h.logger.ErrorContext(ctx, "pay order validation failed", "err", err)
+		return nil, err
+	}
+	if err = validateAmount(req.GetAmount()); err != nil {
+		h.logger.ErrorContext(ctx, "pay order validation failed", "err", err)
+		return nil, err
+	}
+	if err = h.checkCurrency(req.GetCurrency()); err != nil {
+		h.logger.ErrorContext(ctx, "pay order validation failed", "err", err)
+		return nil, err
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		err = status.Error(codes.InvalidArgument, "invalid order_id")
+		h.logger.ErrorContext(ctx, "pay order invalid order id", "err", err)
+		return nil, err
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		pay := func() (*ordersv1.PayOrderResponse, error) {
+			return h.payOrder(ctx, q, req, orderUUID)
+		}
+		if req.GetIdempotencyKey() == "" {
+			resp, err = pay()
+			return err
+		}
+		var replayed bool
+		resp, replayed, err = idempotency.Do(ctx, repo.IdempotencyStore(q), idempotency.Key{
+			Scope:  "orders.PayOrder",
+			UserID: req.GetUserId(),
+			Key:    req.GetIdempotencyKey(),
+		}, req, &ordersv1.PayOrderResponse{}, pay)
+		if replayed {
+			h.logger.InfoContext(ctx, "pay order replayed", "order_id", req.GetOrderId(), "payment_id", resp.GetPaymentId())
+		}
+		return err
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			err = st.Err()
+			return nil, err
+		}
+		err = status.Error(codes.Internal, "failed to pay order")
+		return nil, err
+	}
+	return resp, nil
+}
+
+// payOrder locks the order, checks that it is paid in installments and the
+// amount against what is still payable, and enqueues PaymentRequested for a
+// new installment.
+func (h *Handlers) payOrder(ctx context.Context, q db.Querier, req *ordersv1.PayOrderRequest, orderUUID pgtype.UUID) (*ordersv1.PayOrderResponse, error) {
+	order, err := q.GetOrderForUpdate(ctx, db.GetOrderForUpdateParams{
+		OrderID: orderUUID,
+		UserID:  req.GetUserId(),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err = status.Error(codes.NotFound, "order not found")
+		}
+		h.logger.ErrorContext(ctx, "pay order load failed", "err", err, "order_id", req.GetOrderId())
+		return nil, err
+	}
+	out := &ordersv1.Order{
+		OrderId:              order.OrderID.String(),
+		UserId:               order.UserID,
+		Amount:               order.Amount,
+		Description:          order.Description,
+		Status:               mapOrderStatus(order.Status),
+		CreatedAt:            timestamppb.New(order.CreatedAt.Time),
+		Currency:             string(h.currency),
+		PaymentFailureReason: order.PaymentFailureReason.String,
+		PaidAmount:           order.PaidAmount,
+		FeeAmount:            order.FeeAmount,
+		Metadata:             decodeMetadata(order.Metadata),
+		Tags:                 order.Tags,
+		Version:              order.Version,
+		UpdatedAt:            timestamppb.New(order.UpdatedAt.Time),
+		PayAt:                optionalTimestamp(order.PayAt),
+	}
+
+	if order.Status != "NEW" && order.Status != "PARTIALLY_PAID" {
+		err = status.Error(codes.FailedPrecondition, "order is not payable")
+		h.logger.ErrorContext(ctx, "pay order invalid status", "err", err, "status", order.Status)
+		return nil, err
+	}
+	// Any other order already has its full payment requested.
+	if !order.PayInInstallments {
+		err = status.Error(codes.FailedPrecondition, "order is not paid in installments")
+		h.logger.ErrorContext(ctx, "pay order without installments", "err", err, "order_id", req.GetOrderId())
+		return nil, err
+	}
+	pending, err := q.SumPendingOrderPayments(ctx, orderUUID)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to sum pending order payments", "err", err)
+		return nil, err
+	}
+	if order.PaidAmount+pending+req.GetAmount() > order.Amount {
+		err = status.Error(codes.FailedPrecondition, "amount exceeds outstanding balance")
+		h.logger.ErrorContext(ctx, "pay order amount too large", "err", err, "paid", order.PaidAmount, "pending", pending)
+		return nil, err
+	}
+
+	payment, err := q.CreateOrderPayment(ctx, db.CreateOrderPaymentParams{
+		OrderID: orderUUID,
+		Amount:  req.GetAmount(),
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "failed to create order payment", "err", err)
+		return nil, err
+	}
+
+	payload, err := kafkasvc.MarshalEvent(&eventsv1.PaymentRequested{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       out.OrderId,
+		UserId:        req.GetUserId(),
+		Amount:        req.GetAmount(),
+		PaymentId:     payment.PaymentID.String(),
+		CorrelationId: logging.RequestID(ctx),
+		PaymentMethod: order.PaymentMethod,
+	})
+	if err != nil {
+		err = status.Error(codes.Internal, "failed to marshal event")
+		h.logger.ErrorContext(ctx, "failed to marshal payment requested event", "err", err)
+		return nil, err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         "payments.payment_requested.v1",
+		KafkaKey:      out.OrderId,
+		Payload:       payload,
+		CorrelationID: logging.RequestID(ctx),
+	}); err != nil {
+		h.logger.ErrorContext(ctx, "failed to insert outbox event", "err", err)
+		return nil, err
+	}
+	if err := kafkasvc.InsertOrderChanged(ctx, q, kafkasvc.OrderChange{OrderID: out.OrderId, UserID: req.GetUserId()}); err != nil {
+		h.logger.ErrorContext(ctx, "failed to insert order changed event", "err", err, "order_id", out.OrderId)
+		return nil, err
+	}
+
+	return &ordersv1.PayOrderResponse{Order: out, PaymentId: payment.PaymentID.String()}, nil
+}
+
+// UpdateOrder changes the description, metadata or tags of a NEW order. Only
+// the fields named in update_mask are touched. A non-zero version must match
+// the stored one, so concurrent editors do not overwrite each other.
+func (h *Handlers) UpdateOrder(ctx context.Context, req *ordersv1.UpdateOrderRequest) (resp *ordersv1.UpdateOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "update order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId(), "paths", req.GetUpdateMask().GetPaths(), "version", req.GetVersion())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "update order failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "update order completed", "order_id", req.GetOrderId(), "version", resp.GetOrder().GetVersion(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	paths := req.GetUpdateMask().GetPaths()
+	if len(paths) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_mask is required")
+	}
+	for _, p := range paths {
+		if p != "description" && p != "metadata" && p != "tags" {
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", p)
+		}
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		row    db.UpdateOrderDetailsRow
+		cached *cache.Order
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		order, err := q.GetOrderForUpdate(ctx, db.GetOrderForUpdateParams{
+			OrderID: orderUUID,
+			UserID:  req.GetUserId(),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.NotFound, "order not found")
+			}
+			return err
+		}
+		if order.Status != "NEW" {
+			return status.Error(codes.FailedPrecondition, "only NEW orders can be updated")
+		}
+		if req.GetVersion() != 0 && req.GetVersion() != order.Version {
+			return status.Errorf(codes.Aborted, "order version is %d, not %d", order.Version, req.GetVersion())
+		}
+
+		description, metadata, tags := order.Description, decodeMetadata(order.Metadata), order.Tags
+		for _, p := range paths {
+			switch p {
+			case "description":
+				description = req.GetDescription()
+			case "metadata":
+				metadata = req.GetMetadata()
+			case "tags":
+				tags = req.GetTags()
+			}
+		}
+		if description == "" {
+			return status.Error(codes.InvalidArgument, "description is required")
+		}
+		if err := validateMetadata(metadata, tags); err != nil {
+			return err
+		}
+		metadataJSON, tags, err := encodeMetadata(metadata, tags)
+		if err != nil {
+			return status.Error(codes.InvalidArgument, "invalid metadata")
+		}
+
+		row, err = q.UpdateOrderDetails(ctx, db.UpdateOrderDetailsParams{
+			Description: description,
+			Metadata:    metadataJSON,
+			Tags:        tags,
+			OrderID:     orderUUID,
+			UserID:      req.GetUserId(),
+		})
+		if err != nil {
+			return err
+		}
+		if cached, err = cachedOrder(ctx, q, h.cache, row.OrderID, row.UserID); err != nil {
+			return err
+		}
+		return kafkasvc.InsertOrderChanged(ctx, q, kafkasvc.OrderChange{OrderID: req.GetOrderId(), UserID: row.UserID})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to update order")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, *cached); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", row.OrderID.String())
+		}
+		if err := h.cache.InvalidateList(ctx, row.UserID); err != nil {
+			h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", row.UserID)
+		}
+	}
+
+	resp = &ordersv1.UpdateOrderResponse{
+		Order: &ordersv1.Order{
+			OrderId:              row.OrderID.String(),
+			UserId:               row.UserID,
+			Amount:               row.Amount,
+			Description:          row.Description,
+			Status:               mapOrderStatus(row.Status),
+			CreatedAt:            timestamppb.New(row.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: row.PaymentFailureReason.String,
+			PaidAmount:           row.PaidAmount,
+			FeeAmount:            row.FeeAmount,
+			Metadata:             decodeMetadata(row.Metadata),
+			Tags:                 row.Tags,
+			Version:              row.Version,
+			UpdatedAt:            timestamppb.New(row.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(row.PayAt),
 		},
 	}
 	return resp, nil
 }
 
+// pricedItem is an order item with the catalog price it was ordered at.
+type pricedItem struct {
+	productID string
+	quantity  int64
+	unitPrice int64
+}
+
+// resolveAmount prices order items via the catalog and returns their total
+// with the priced items.
+func (h *Handlers) resolveAmount(ctx context.Context, items []*ordersv1.OrderItem) (int64, []pricedItem, error) {
+	if h.prices == nil {
+		err := status.Error(codes.FailedPrecondition, "product catalog is not configured")
+		h.logger.ErrorContext(ctx, "resolve amount failed", "err", err)
+		return 0, nil, err
+	}
+	var total int64
+	priced := make([]pricedItem, 0, len(items))
+	for _, item := range items {
+		if item.GetProductId() == "" || item.GetQuantity() <= 0 {
+			err := status.Error(codes.InvalidArgument, "items require product_id and quantity > 0")
+			h.logger.ErrorContext(ctx, "resolve amount validation failed", "err", err)
+			return 0, nil, err
+		}
+		price, err := h.prices.Price(ctx, item.GetProductId())
+		if err != nil {
+			if errors.Is(err, catalog.ErrUnknownProduct) {
+				err = status.Errorf(codes.InvalidArgument, "unknown product %q", item.GetProductId())
+			} else {
+				err = status.Error(codes.Unavailable, "product catalog unavailable")
+			}
+			h.logger.ErrorContext(ctx, "resolve amount failed", "err", err, "product_id", item.GetProductId())
+			return 0, nil, err
+		}
+		line, err := money.MulAmount(price, item.GetQuantity())
+		if err == nil {
+			total, err = money.AddAmount(total, line)
+		}
+		if err != nil {
+			err = status.Errorf(codes.InvalidArgument, "order total must be <= %d", money.MaxAmount)
+			h.logger.ErrorContext(ctx, "resolve amount overflow", "err", err, "product_id", item.GetProductId(), "quantity", item.GetQuantity())
+			return 0, nil, err
+		}
+		priced = append(priced, pricedItem{productID: item.GetProductId(), quantity: item.GetQuantity(), unitPrice: price})
+	}
+	h.logger.DebugContext(ctx, "resolved order amount", "items", len(items), "amount", total)
+	return total, priced, nil
+}
+
+// checkAccount rejects the order up front when the user has no payment account,
+// instead of letting it fail asynchronously. The known_accounts projection is
+// consulted first; payments is only asked about users it has not seen yet,
+// which also covers accounts created before the projection existed. Payments
+// being unreachable is not treated as a rejection: the order falls back to
+// the regular async flow.
+func (h *Handlers) checkAccount(ctx context.Context, userID string) error {
+	if h.knownAccounts {
+		var known bool
+		err := h.repo.Read(ctx, func(q db.Querier) error {
+			var err error
+			known, err = q.KnownAccountExists(ctx, userID)
+			return err
+		})
+		if err != nil {
+			h.logger.WarnContext(ctx, "known accounts lookup failed", "err", err, "user_id", userID)
+		}
+		if known {
+			return nil
+		}
+		if err == nil && h.payments == nil {
+			err = status.Error(codes.FailedPrecondition, "payment account not found")
+			h.logger.ErrorContext(ctx, "create order unknown account", "err", err, "user_id", userID)
+			return err
+		}
+	}
+	if h.payments == nil {
+		return nil
+	}
+	_, err := h.payments.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: userID})
+	if err == nil {
+		if h.knownAccounts {
+			if err := h.repo.InTx(ctx, func(q db.Querier) error {
+				return q.UpsertKnownAccount(ctx, userID)
+			}); err != nil {
+				h.logger.WarnContext(ctx, "known account backfill failed", "err", err, "user_id", userID)
+			}
+		}
+		return nil
+	}
+	if status.Code(err) == codes.NotFound {
+		err = status.Error(codes.FailedPrecondition, "payment account not found")
+		h.logger.ErrorContext(ctx, "create order account precheck failed", "err", err, "user_id", userID)
+		return err
+	}
+	h.logger.WarnContext(ctx, "create order account precheck skipped", "err", err, "user_id", userID)
+	return nil
+}
+
+func (h *Handlers) orderFromCache(o cache.Order) *ordersv1.Order {
+	return &ordersv1.Order{
+		OrderId:              o.OrderID,
+		UserId:               o.UserID,
+		Amount:               o.Amount,
+		Description:          o.Description,
+		Status:               mapOrderStatus(o.Status),
+		CreatedAt:            timestamppb.New(o.CreatedAt),
+		Currency:             string(h.currency),
+		PaymentFailureReason: o.PaymentFailureReason,
+		PaidAmount:           o.PaidAmount,
+		FeeAmount:            o.FeeAmount,
+		Metadata:             o.Metadata,
+		Tags:                 o.Tags,
+		Version:              o.Version,
+		UpdatedAt:            timestamppb.New(o.UpdatedAt),
+		PayAt:                cachedTimestamp(o.PayAt),
+		Archived:             o.Archived,
+		Dispute:              disputeToProto(o.Dispute),
+		LastPaymentReason:    o.LastPaymentReason,
+		ItemCount:            o.ItemCount,
+	}
+}
+
 func mapOrderStatus(s string) ordersv1.OrderStatus {
-	logger.Info("map order status", "status", s)
+	slog.Debug("map order status", "status", s)
 	switch s {
 	case "NEW":
 		return ordersv1.OrderStatus_ORDER_STATUS_NEW
+	case "PARTIALLY_PAID":
+		return ordersv1.OrderStatus_ORDER_STATUS_PARTIALLY_PAID
 	case "FINISHED":
 		return ordersv1.OrderStatus_ORDER_STATUS_FINISHED
 	case "CANCELLED":
 		return ordersv1.OrderStatus_ORDER_STATUS_CANCELLED
+	case "SCHEDULED":
+		return ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED
+	case "DISPUTED":
+		return ordersv1.OrderStatus_ORDER_STATUS_DISPUTED
+	case "REFUNDED":
+		return ordersv1.OrderStatus_ORDER_STATUS_REFUNDED
 	default:
 		return ordersv1.OrderStatus_ORDER_STATUS_UNSPECIFIED
 	}
@@ -364,25 +1056,25 @@ func mapOrderStatus(s string) ordersv1.OrderStatus {
 
 func encodeOffset(n int32) string {
 	start := time.Now()
-	logger.Info("encode offset start", "offset", n)
+	slog.Debug("encode offset start", "offset", n)
 	encoded := base64.StdEncoding.EncodeToString([]byte(strconv.Itoa(int(n))))
-	logger.Info("encode offset completed", "duration", time.Since(start))
+	slog.Debug("encode offset completed", "duration", time.Since(start))
 	return encoded
 }
 
 func decodeOffset(s string) (int32, error) {
 	start := time.Now()
-	logger.Info("decode offset start", "has_value", s != "")
+	slog.Debug("decode offset start", "has_value", s != "")
 	b, err := base64.StdEncoding.DecodeString(s)
 	if err != nil {
-		logger.Error("decode offset failed", "err", err, "duration", time.Since(start))
+		slog.Error("decode offset failed", "err", err, "duration", time.Since(start))
 		return 0, err
 	}
 	n, err := strconv.Atoi(string(b))
 	if err != nil {
-		logger.Error("decode offset failed", "err", err, "duration", time.Since(start))
+		slog.Error("decode offset failed", "err", err, "duration", time.Since(start))
 		return 0, err
 	}
-	logger.Info("decode offset completed", "offset", n, "duration", time.Since(start))
+	slog.Debug("decode offset completed", "offset", n, "duration", time.Since(start))
 	return int32(n), nil
 }
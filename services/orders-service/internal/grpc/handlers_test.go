@@ -0,0 +1,951 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/catalog"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/statusbus"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func newTestHandlers(repo *fakeRepo) *Handlers {
+	return NewHandlers(repo, nil, nil, catalog.NewStaticResolver(map[string]int64{"sku-1": 100, "sku-2": 250}), false, money.RUB, 0, 0, 0, nil)
+}
+
+func wantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Fatalf("error code = %s (%v), want %s", got, err, code)
+	}
+}
+
+func decodeRequested(t *testing.T, payload []byte) *eventsv1.PaymentRequested {
+	t.Helper()
+	var ev eventsv1.PaymentRequested
+	if err := kafkasvc.UnmarshalEvent(payload, &ev); err != nil {
+		t.Fatalf("unmarshal outbox payload: %v", err)
+	}
+	return &ev
+}
+
+func TestCreateOrderValidation(t *testing.T) {
+	h := newTestHandlers(newFakeRepo())
+	ctx := context.Background()
+
+	_, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{Amount: 10, Description: "d"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Description: "d"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: money.MaxAmount + 1, Description: "d"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d", Currency: "USD"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d", PaymentMethod: "Card"})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestCreateOrderWritesOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+
+	resp, err := h.CreateOrder(context.Background(), &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if resp.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_NEW || resp.GetOrder().GetAmount() != 500 || resp.GetOrder().GetCurrency() != "RUB" {
+		t.Fatalf("CreateOrder() order = %v, want NEW with amount 500 RUB", resp.GetOrder())
+	}
+	if len(repo.outbox) != 1 {
+		t.Fatalf("outbox has %d events, want 1", len(repo.outbox))
+	}
+	ev := decodeRequested(t, repo.outbox[0].Payload)
+	if ev.GetOrderId() != resp.GetOrder().GetOrderId() || ev.GetAmount() != 500 || ev.GetUserId() != "u-1" || ev.GetPaymentMethod() != "balance" {
+		t.Fatalf("outbox event = %v, want order %s amount 500 paid from the balance", ev, resp.GetOrder().GetOrderId())
+	}
+
+	if _, err := h.CreateOrder(context.Background(), &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 700, Description: "lamp", PaymentMethod: "card"}); err != nil {
+		t.Fatalf("CreateOrder() by card error: %v", err)
+	}
+	if ev := decodeRequested(t, repo.outbox[1].Payload); ev.GetPaymentMethod() != "card" {
+		t.Fatalf("outbox event payment_method = %q, want card", ev.GetPaymentMethod())
+	}
+}
+
+func TestCreateOrderCarriesRequestID(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := logging.WithRequestID(context.Background(), "req-1")
+
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book"}); err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if len(repo.outbox) != 1 || repo.outbox[0].CorrelationID != "req-1" {
+		t.Fatalf("outbox = %+v, want one row with correlation id req-1", repo.outbox)
+	}
+	if ev := decodeRequested(t, repo.outbox[0].Payload); ev.GetCorrelationId() != "req-1" {
+		t.Fatalf("PaymentRequested correlation_id = %q, want req-1", ev.GetCorrelationId())
+	}
+}
+
+func TestCreateOrderIdempotentReplay(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+	req := &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book", IdempotencyKey: "k-1"}
+
+	first, err := h.CreateOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	second, err := h.CreateOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateOrder() replay error: %v", err)
+	}
+	if first.GetOrder().GetOrderId() != second.GetOrder().GetOrderId() {
+		t.Fatalf("replay order_id = %s, want %s", second.GetOrder().GetOrderId(), first.GetOrder().GetOrderId())
+	}
+	if len(repo.outbox) != 1 {
+		t.Fatalf("outbox has %d events after replay, want 1", len(repo.outbox))
+	}
+
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 900, Description: "book", IdempotencyKey: "k-1"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestCreateOrderFromItems(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	resp, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Description: "cart",
+		Items: []*ordersv1.OrderItem{
+			{ProductId: "sku-1", Quantity: 2},
+			{ProductId: "sku-2", Quantity: 1},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if resp.GetOrder().GetAmount() != 450 {
+		t.Fatalf("CreateOrder() amount = %d, want 450", resp.GetOrder().GetAmount())
+	}
+	// The receipt lists the products at the prices the order was created with.
+	repo.orders[0].row.Status, repo.orders[0].row.PaidAmount = "FINISHED", 450
+	rc, err := h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: resp.GetOrder().GetOrderId()})
+	if err != nil {
+		t.Fatalf("GetOrderReceipt() error: %v", err)
+	}
+	items := rc.GetReceipt().GetItems()
+	if len(items) != 2 || items[0].GetProductId() != "sku-1" || items[0].GetQuantity() != 2 || items[0].GetUnitPrice() != 100 || items[0].GetAmount() != 200 ||
+		items[1].GetProductId() != "sku-2" || items[1].GetKind() != receipt.KindProduct || items[1].GetAmount() != 250 {
+		t.Fatalf("receipt items = %v, want sku-1 x2 and sku-2", items)
+	}
+
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Description: "cart",
+		Items:       []*ordersv1.OrderItem{{ProductId: "missing", Quantity: 1}},
+	})
+	wantCode(t, err, codes.InvalidArgument)
+
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Amount:      10,
+		Description: "cart",
+		Items:       []*ordersv1.OrderItem{{ProductId: "sku-1", Quantity: 1}},
+	})
+	wantCode(t, err, codes.InvalidArgument)
+	// 250 * 2^62 wraps around in int64; the total must not.
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Description: "cart",
+		Items:       []*ordersv1.OrderItem{{ProductId: "sku-2", Quantity: 1 << 62}},
+	})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Description: "cart",
+		Items:       []*ordersv1.OrderItem{{ProductId: "sku-1", Quantity: money.MaxAmount / 100}, {ProductId: "sku-2", Quantity: 1}},
+	})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestCreateOrderKnownAccounts(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), true, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+	req := &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o"}
+
+	_, err := h.CreateOrder(ctx, req)
+	wantCode(t, err, codes.FailedPrecondition)
+
+	repo.known["u-1"] = true
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() for known account error: %v", err)
+	}
+
+	// An account whose AccountCreated has not arrived yet is confirmed by
+	// payments and added to the projection.
+	h = NewHandlers(repo, nil, fakePayments{accounts: map[string]bool{"u-2": true}}, catalog.NewStaticResolver(nil), true, money.RUB, 0, 0, 0, nil)
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-2", Amount: 10, Description: "o"}); err != nil {
+		t.Fatalf("CreateOrder() before AccountCreated error: %v", err)
+	}
+	if !repo.known["u-2"] {
+		t.Fatal("known_accounts was not backfilled from payments")
+	}
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-3", Amount: 10, Description: "o"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+// fakePayments answers GetBalance for the users in accounts.
+type fakePayments struct {
+	paymentsv1.PaymentsServiceClient
+	accounts map[string]bool
+}
+
+func (f fakePayments) GetBalance(_ context.Context, req *paymentsv1.GetBalanceRequest, _ ...grpc.CallOption) (*paymentsv1.GetBalanceResponse, error) {
+	if !f.accounts[req.GetUserId()] {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	return &paymentsv1.GetBalanceResponse{}, nil
+}
+
+func TestCreateOrderNewOrdersQuota(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 2, 0, 0, nil)
+	ctx := context.Background()
+	req := &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o"}
+
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() #1 error: %v", err)
+	}
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() #2 error: %v", err)
+	}
+	_, err := h.CreateOrder(ctx, req)
+	wantCode(t, err, codes.ResourceExhausted)
+
+	// Other users have their own quota.
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-2", Amount: 10, Description: "o"}); err != nil {
+		t.Fatalf("CreateOrder() for u-2 error: %v", err)
+	}
+
+	// A paid order frees its slot.
+	repo.orders[0].row.Status = "FINISHED"
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() after payment error: %v", err)
+	}
+}
+
+func TestCreateOrderDuplicate(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, time.Minute, 0, nil)
+	ctx := context.Background()
+	req := &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "book"}
+
+	first, err := h.CreateOrder(ctx, req)
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	_, err = h.CreateOrder(ctx, req)
+	wantCode(t, err, codes.AlreadyExists)
+	var info *errdetails.ErrorInfo
+	for _, d := range status.Convert(err).Details() {
+		if ei, ok := d.(*errdetails.ErrorInfo); ok {
+			info = ei
+		}
+	}
+	if info == nil || info.GetReason() != DuplicateOrderReason || info.GetMetadata()["order_id"] != first.GetOrder().GetOrderId() {
+		t.Fatalf("duplicate error details = %v, want %s of %s", info, DuplicateOrderReason, first.GetOrder().GetOrderId())
+	}
+
+	// A different description or amount, another user, force or an
+	// idempotency key all pass.
+	for _, r := range []*ordersv1.CreateOrderRequest{
+		{UserId: "u-1", Amount: 10, Description: "pen"},
+		{UserId: "u-1", Amount: 11, Description: "book"},
+		{UserId: "u-2", Amount: 10, Description: "book"},
+		{UserId: "u-1", Amount: 10, Description: "book", Force: true},
+		{UserId: "u-1", Amount: 10, Description: "book", IdempotencyKey: "k-1"},
+	} {
+		if _, err := h.CreateOrder(ctx, r); err != nil {
+			t.Fatalf("CreateOrder(%v) error: %v", r, err)
+		}
+	}
+
+	// Cancelled and old orders are not duplicates.
+	for i := range repo.orders {
+		if repo.orders[i].row.UserID == "u-1" && repo.orders[i].row.Description == "book" && repo.orders[i].row.Amount == 10 {
+			repo.orders[i].row.Status = "CANCELLED"
+		}
+	}
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() after cancellation error: %v", err)
+	}
+	repo.orders[len(repo.orders)-1].row.CreatedAt.Time = time.Now().Add(-2 * time.Minute)
+	if _, err := h.CreateOrder(ctx, req); err != nil {
+		t.Fatalf("CreateOrder() after the window error: %v", err)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	resp, err := h.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{
+		UserId:      "u-1",
+		Description: "cart",
+		Items:       []*ordersv1.OrderItem{{ProductId: "sku-1", Quantity: 2}, {ProductId: "sku-2", Quantity: 1}},
+	})
+	if err != nil {
+		t.Fatalf("ValidateOrder() error: %v", err)
+	}
+	if resp.GetAmount() != 450 || resp.GetCurrency() != "RUB" {
+		t.Fatalf("ValidateOrder() = %v, want 450 RUB", resp)
+	}
+	if len(repo.orders) != 0 || len(repo.outbox) != 0 {
+		t.Fatalf("ValidateOrder() stored %d orders and %d events, want none", len(repo.orders), len(repo.outbox))
+	}
+
+	_, err = h.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d", Currency: "USD"})
+	wantCode(t, err, codes.InvalidArgument)
+
+	strict := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), true, money.RUB, 0, 0, 0, nil)
+	_, err = strict.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestGetOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	// As left by a successful payment that was charged a fee.
+	repo.orders[0].row.Status, repo.orders[0].row.PaidAmount, repo.orders[0].row.FeeAmount = "FINISHED", 500, 5
+
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+	if got.GetOrder().GetOrderId() != orderID || got.GetOrder().GetDescription() != "book" {
+		t.Fatalf("GetOrder() = %v, want order %s", got.GetOrder(), orderID)
+	}
+	if got.GetOrder().GetPaidAmount() != 500 || got.GetOrder().GetFeeAmount() != 5 {
+		t.Fatalf("GetOrder() paid/fee = %d/%d, want 500/5", got.GetOrder().GetPaidAmount(), got.GetOrder().GetFeeAmount())
+	}
+
+	_, err = h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-2", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+	_, err = h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: "not-a-uuid"})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetOrders(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, cache.NewMemoryOrderCache(10, time.Minute), nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	var ids []string
+	for _, u := range []string{"u-1", "u-1", "u-2"} {
+		created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: u, Amount: 10, Description: "o"})
+		if err != nil {
+			t.Fatalf("CreateOrder() error: %v", err)
+		}
+		ids = append(ids, created.GetOrder().GetOrderId())
+	}
+	unknown := "7b0e4c9e-5f7a-4d1e-9c1a-3f2b8e6d4a10"
+	// The first order is cached, so only the second is queried.
+	if _, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: ids[0]}); err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+
+	got, err := h.GetOrders(ctx, &ordersv1.GetOrdersRequest{UserId: "u-1", OrderIds: []string{ids[1], ids[0], ids[2], unknown, strings.ToUpper(ids[1]), "not-a-uuid"}})
+	if err != nil {
+		t.Fatalf("GetOrders() error: %v", err)
+	}
+	if len(got.GetOrders()) != 2 || got.GetOrders()[0].GetOrderId() != ids[1] || got.GetOrders()[1].GetOrderId() != ids[0] {
+		t.Fatalf("GetOrders() orders = %v, want %s and %s in request order", got.GetOrders(), ids[1], ids[0])
+	}
+	if !slices.Equal(got.GetMissingOrderIds(), []string{ids[2], unknown, "not-a-uuid"}) {
+		t.Fatalf("GetOrders() missing = %v, want the other user's, the unknown and the invalid order", got.GetMissingOrderIds())
+	}
+	if repo.getOrdersCalls != 1 {
+		t.Fatalf("GetOrders queries = %d, want 1 for all misses", repo.getOrdersCalls)
+	}
+
+	_, err = h.GetOrders(ctx, &ordersv1.GetOrdersRequest{UserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.GetOrders(ctx, &ordersv1.GetOrdersRequest{OrderIds: []string{unknown}})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestArchivedOrders(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	var ids []string
+	for _, d := range []string{"old", "new"} {
+		created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: d})
+		if err != nil {
+			t.Fatalf("CreateOrder() error: %v", err)
+		}
+		ids = append(ids, created.GetOrder().GetOrderId())
+	}
+	// As moved by the archiver.
+	repo.orders[0].row.Status, repo.orders[0].row.Archived = "FINISHED", true
+
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: ids[0]})
+	if err != nil || !got.GetOrder().GetArchived() || got.GetOrder().GetDescription() != "old" {
+		t.Fatalf("GetOrder() of archived order = %v, %v; want it with archived set", got.GetOrder(), err)
+	}
+
+	list, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1"})
+	if err != nil || len(list.GetOrders()) != 1 || list.GetOrders()[0].GetOrderId() != ids[1] {
+		t.Fatalf("ListOrders() = %v, %v; want only the hot order", list.GetOrders(), err)
+	}
+	list, err = h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", IncludeArchived: true})
+	if err != nil || len(list.GetOrders()) != 2 || !list.GetOrders()[1].GetArchived() {
+		t.Fatalf("ListOrders(include_archived) = %v, %v; want both orders", list.GetOrders(), err)
+	}
+
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: ids[0], Description: "x", UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}}})
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestListOrdersPagination(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o"}); err != nil {
+			t.Fatalf("CreateOrder() error: %v", err)
+		}
+	}
+
+	page, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", Limit: 2})
+	if err != nil {
+		t.Fatalf("ListOrders() error: %v", err)
+	}
+	if len(page.GetOrders()) != 2 || page.GetNextPageToken() == "" {
+		t.Fatalf("ListOrders() = %d orders, token %q; want 2 and a next token", len(page.GetOrders()), page.GetNextPageToken())
+	}
+
+	page, err = h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", Limit: 2, PageToken: page.GetNextPageToken()})
+	if err != nil {
+		t.Fatalf("ListOrders() page 2 error: %v", err)
+	}
+	if len(page.GetOrders()) != 1 || page.GetNextPageToken() != "" {
+		t.Fatalf("ListOrders() page 2 = %d orders, token %q; want 1 and no token", len(page.GetOrders()), page.GetNextPageToken())
+	}
+}
+
+func TestListOrdersFirstPageCache(t *testing.T) {
+	repo := newFakeRepo()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	h := NewHandlers(repo, orderCache, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o"}); err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if _, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1"}); err != nil {
+		t.Fatalf("ListOrders() error: %v", err)
+	}
+	if cached, _ := orderCache.GetList(ctx, "u-1", 50); cached == nil || len(cached.Orders) != 1 {
+		t.Fatalf("first page not cached after ListOrders: %v", cached)
+	}
+
+	// Served from cache: a row written behind the handler's back stays invisible.
+	repo.insertOrder("u-1", 20, "direct", nil, nil)
+	page, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1"})
+	if err != nil || len(page.GetOrders()) != 1 {
+		t.Fatalf("ListOrders() = (%d orders, %v), want 1 cached order", len(page.GetOrders()), err)
+	}
+
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 30, Description: "o"}); err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	page, err = h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1"})
+	if err != nil || len(page.GetOrders()) != 3 {
+		t.Fatalf("ListOrders() after CreateOrder = (%d orders, %v), want 3", len(page.GetOrders()), err)
+	}
+}
+
+func TestListOrdersReadModelFields(t *testing.T) {
+	repo := newFakeRepo()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	h := NewHandlers(repo, orderCache, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	repo.insertOrder("u-1", 20, "declined", nil, nil)
+	repo.orders[0].row.Status = "CANCELLED"
+	repo.orders[0].row.PaymentFailureReason = pgtype.Text{String: "not enough funds", Valid: true}
+	repo.insertOrder("u-1", 30, "paid", nil, nil)
+	repo.orders[1].row.Status, repo.orders[1].row.FeeAmount = "FINISHED", 2
+	for line, product := range []string{"p-1", "p-2"} {
+		repo.items = append(repo.items, db.InsertOrderItemParams{OrderID: repo.orders[1].row.OrderID, Line: int32(line), ProductID: product, Quantity: 1, UnitPrice: 15})
+	}
+
+	// The second call is served from the cached first page.
+	for range 2 {
+		page, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1"})
+		if err != nil || len(page.GetOrders()) != 2 {
+			t.Fatalf("ListOrders() = (%v, %v), want 2 orders", page.GetOrders(), err)
+		}
+		paid, declined := page.GetOrders()[0], page.GetOrders()[1]
+		if paid.GetItemCount() != 2 || paid.GetLastPaymentReason() != "" {
+			t.Fatalf("paid order = %v, want its 2 items, no failure", paid)
+		}
+		if declined.GetItemCount() != 1 || declined.GetLastPaymentReason() != "not enough funds" {
+			t.Fatalf("declined order = %v, want one item and the failure reason", declined)
+		}
+	}
+}
+
+func TestOrderReadMask(t *testing.T) {
+	repo := newFakeRepo()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	h := NewHandlers(repo, orderCache, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book", Tags: []string{"gift"}})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	mask := &fieldmaskpb.FieldMask{Paths: []string{"order_id", "status"}}
+	trimmed := func(o *ordersv1.Order) bool {
+		return o.GetOrderId() == orderID && o.GetStatus() == ordersv1.OrderStatus_ORDER_STATUS_NEW &&
+			o.GetAmount() == 0 && o.GetDescription() == "" && o.GetTags() == nil && o.GetCreatedAt() == nil
+	}
+
+	// The second calls are cache hits; trimming them must not trim the cache.
+	for range 2 {
+		got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID, ReadMask: mask})
+		if err != nil || !trimmed(got.GetOrder()) {
+			t.Fatalf("GetOrder(read_mask) = %v, %v; want only order_id and status", got.GetOrder(), err)
+		}
+		list, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", ReadMask: mask})
+		if err != nil || len(list.GetOrders()) != 1 || !trimmed(list.GetOrders()[0]) {
+			t.Fatalf("ListOrders(read_mask) = %v, %v; want only order_id and status", list.GetOrders(), err)
+		}
+	}
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil || got.GetOrder().GetDescription() != "book" || got.GetOrder().GetAmount() != 500 {
+		t.Fatalf("GetOrder() without read_mask = %v, %v; want the whole order", got.GetOrder(), err)
+	}
+
+	for _, paths := range [][]string{{"items"}, {"created_at.seconds"}} {
+		_, err = h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID, ReadMask: &fieldmaskpb.FieldMask{Paths: paths}})
+		wantCode(t, err, codes.InvalidArgument)
+		_, err = h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", ReadMask: &fieldmaskpb.FieldMask{Paths: paths}})
+		wantCode(t, err, codes.InvalidArgument)
+	}
+}
+
+func TestCreateOrderMetadataValidation(t *testing.T) {
+	h := newTestHandlers(newFakeRepo())
+	ctx := context.Background()
+	base := func() *ordersv1.CreateOrderRequest {
+		return &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d"}
+	}
+
+	tooMany := map[string]string{}
+	for i := 0; i <= maxMetadataKeys; i++ {
+		tooMany[strings.Repeat("k", i+1)] = "v"
+	}
+	for name, mutate := range map[string]func(*ordersv1.CreateOrderRequest){
+		"too many keys": func(r *ordersv1.CreateOrderRequest) { r.Metadata = tooMany },
+		"empty key":     func(r *ordersv1.CreateOrderRequest) { r.Metadata = map[string]string{"": "v"} },
+		"long key": func(r *ordersv1.CreateOrderRequest) {
+			r.Metadata = map[string]string{strings.Repeat("k", maxMetadataKeyLen+1): "v"}
+		},
+		"long value": func(r *ordersv1.CreateOrderRequest) {
+			r.Metadata = map[string]string{"k": strings.Repeat("v", maxMetadataValueLen+1)}
+		},
+		"NUL in key":    func(r *ordersv1.CreateOrderRequest) { r.Metadata = map[string]string{"k\x00": "v"} },
+		"NUL in value":  func(r *ordersv1.CreateOrderRequest) { r.Metadata = map[string]string{"k": "a\u0000b"} },
+		"too many tags": func(r *ordersv1.CreateOrderRequest) { r.Tags = make([]string, maxTags+1) },
+		"NUL in tag":    func(r *ordersv1.CreateOrderRequest) { r.Tags = []string{"b2b\x00"} },
+		"empty tag":     func(r *ordersv1.CreateOrderRequest) { r.Tags = []string{""} },
+		"long tag":      func(r *ordersv1.CreateOrderRequest) { r.Tags = []string{strings.Repeat("t", maxTagLen+1)} },
+		"duplicate tag": func(r *ordersv1.CreateOrderRequest) { r.Tags = []string{"a", "a"} },
+	} {
+		req := base()
+		mutate(req)
+		_, err := h.CreateOrder(ctx, req)
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("%s: error = %v, want InvalidArgument", name, err)
+		}
+	}
+}
+
+func TestOrderMetadataAndTags(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+	md := map[string]string{"invoice": "INV-42"}
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o", Metadata: md, Tags: []string{"b2b", "q3"}, IdempotencyKey: "k-1"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 20, Description: "o"}); err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: created.GetOrder().GetOrderId()})
+	if err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+	if got.GetOrder().GetMetadata()["invoice"] != "INV-42" || len(got.GetOrder().GetTags()) != 2 {
+		t.Fatalf("GetOrder() = %v, want stored metadata and tags", got.GetOrder())
+	}
+
+	page, err := h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", Tag: "q3"})
+	if err != nil {
+		t.Fatalf("ListOrders() error: %v", err)
+	}
+	if len(page.GetOrders()) != 1 || page.GetOrders()[0].GetOrderId() != created.GetOrder().GetOrderId() {
+		t.Fatalf("ListOrders(tag=q3) = %v, want only the tagged order", page.GetOrders())
+	}
+	_, err = h.ListOrders(ctx, &ordersv1.ListOrdersRequest{UserId: "u-1", Tag: strings.Repeat("t", maxTagLen+1)})
+	wantCode(t, err, codes.InvalidArgument)
+
+	// Replaying the idempotency key with other metadata is a different request.
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "o", Metadata: map[string]string{"invoice": "INV-43"}, Tags: []string{"b2b", "q3"}, IdempotencyKey: "k-1"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestPayOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "tv", PayInInstallments: true, PaymentMethod: "card"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if len(repo.outbox) != 0 {
+		t.Fatalf("installment order wrote %d outbox events, want 0", len(repo.outbox))
+	}
+	orderID := created.GetOrder().GetOrderId()
+
+	paid, err := h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID, Amount: 60, IdempotencyKey: "p-1"})
+	if err != nil {
+		t.Fatalf("PayOrder() error: %v", err)
+	}
+	if paid.GetPaymentId() == "" || len(repo.outbox) != 1 {
+		t.Fatalf("PayOrder() payment_id %q, outbox %d; want id and 1 event", paid.GetPaymentId(), len(repo.outbox))
+	}
+	if ev := decodeRequested(t, repo.outbox[0].Payload); ev.GetPaymentId() != paid.GetPaymentId() || ev.GetAmount() != 60 || ev.GetPaymentMethod() != "card" {
+		t.Fatalf("outbox event = %v, want payment %s amount 60 by card", ev, paid.GetPaymentId())
+	}
+
+	replay, err := h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID, Amount: 60, IdempotencyKey: "p-1"})
+	if err != nil || replay.GetPaymentId() != paid.GetPaymentId() || len(repo.outbox) != 1 {
+		t.Fatalf("PayOrder() replay = (%v, %v), outbox %d; want same payment, no new event", replay, err, len(repo.outbox))
+	}
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID, Amount: 10, IdempotencyKey: "p-1"})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	// 60 is pending, so only 40 is still payable.
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID, Amount: 50})
+	wantCode(t, err, codes.FailedPrecondition)
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-2", OrderId: orderID, Amount: 10})
+	wantCode(t, err, codes.NotFound)
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.InvalidArgument)
+
+	// An order paid up front already has its PaymentRequested queued.
+	upfront, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "radio"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: upfront.GetOrder().GetOrderId(), Amount: 10})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestUpdateOrder(t *testing.T) {
+	repo := newFakeRepo()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	h := NewHandlers(repo, orderCache, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "old", Tags: []string{"a"}})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	if created.GetOrder().GetVersion() != 1 {
+		t.Fatalf("CreateOrder() version = %d, want 1", created.GetOrder().GetVersion())
+	}
+	// Warm the cache so the update has to refresh it.
+	if _, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID}); err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+
+	updated, err := h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{
+		UserId:      "u-1",
+		OrderId:     orderID,
+		UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+		Description: "new",
+		Tags:        []string{"ignored"},
+		Version:     1,
+	})
+	if err != nil {
+		t.Fatalf("UpdateOrder() error: %v", err)
+	}
+	if o := updated.GetOrder(); o.GetDescription() != "new" || o.GetVersion() != 2 || len(o.GetTags()) != 1 || o.GetTags()[0] != "a" {
+		t.Fatalf("UpdateOrder() = %v, want new description, version 2 and the old tags", o)
+	}
+	if len(repo.changed) != 2 || repo.changed[1].KafkaKey != orderID {
+		t.Fatalf("OrderChanged events = %v, want one for the creation and one for the update", repo.changed)
+	}
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil || got.GetOrder().GetDescription() != "new" || got.GetOrder().GetVersion() != 2 {
+		t.Fatalf("GetOrder() after update = (%v, %v), want the updated order", got.GetOrder(), err)
+	}
+
+	// A stale version loses.
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tags"}}, Version: 1})
+	wantCode(t, err, codes.Aborted)
+	// Version 0 skips the check.
+	if _, err := h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"tags"}}}); err != nil {
+		t.Fatalf("UpdateOrder() without version error: %v", err)
+	}
+}
+
+func TestUpdateOrderValidation(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 10, Description: "d"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	mask := func(paths ...string) *fieldmaskpb.FieldMask { return &fieldmaskpb.FieldMask{Paths: paths} }
+
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, Description: "x"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: mask("amount")})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: mask("description")})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: mask("tags"), Tags: []string{"a", "a"}})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-2", OrderId: orderID, UpdateMask: mask("description"), Description: "x"})
+	wantCode(t, err, codes.NotFound)
+
+	repo.orders[0].row.Status = "PAID"
+	_, err = h.UpdateOrder(ctx, &ordersv1.UpdateOrderRequest{UserId: "u-1", OrderId: orderID, UpdateMask: mask("description"), Description: "x"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestTransferOrder(t *testing.T) {
+	repo := newFakeRepo()
+	orderCache := cache.NewMemoryOrderCache(10, time.Minute)
+	h := NewHandlers(repo, orderCache, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, nil)
+	ctx := context.Background()
+
+	upFront, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "paid up front"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	_, err = h.TransferOrder(ctx, &ordersv1.TransferOrderRequest{UserId: "u-1", OrderId: upFront.GetOrder().GetOrderId(), ToUserId: "u-2"})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "gift", PayInInstallments: true})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	if _, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID}); err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+
+	_, err = h.TransferOrder(ctx, &ordersv1.TransferOrderRequest{UserId: "u-1", OrderId: orderID, ToUserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.TransferOrder(ctx, &ordersv1.TransferOrderRequest{UserId: "u-2", OrderId: orderID, ToUserId: "u-3"})
+	wantCode(t, err, codes.NotFound)
+
+	// A newer offer replaces the pending one.
+	if _, err := h.TransferOrder(ctx, &ordersv1.TransferOrderRequest{UserId: "u-1", OrderId: orderID, ToUserId: "u-3"}); err != nil {
+		t.Fatalf("TransferOrder() error: %v", err)
+	}
+	offer, err := h.TransferOrder(ctx, &ordersv1.TransferOrderRequest{UserId: "u-1", OrderId: orderID, ToUserId: "u-2"})
+	if err != nil {
+		t.Fatalf("TransferOrder() error: %v", err)
+	}
+	if tr := offer.GetTransfer(); tr.GetStatus() != ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_PENDING || tr.GetFromUserId() != "u-1" || tr.GetToUserId() != "u-2" {
+		t.Fatalf("TransferOrder() = %v, want a pending transfer u-1 -> u-2", tr)
+	}
+	_, err = h.AcceptOrderTransfer(ctx, &ordersv1.AcceptOrderTransferRequest{UserId: "u-3", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+
+	// Not transferable while an installment is pending; the offer survives.
+	if _, err := h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: orderID, Amount: 30}); err != nil {
+		t.Fatalf("PayOrder() error: %v", err)
+	}
+	_, err = h.AcceptOrderTransfer(ctx, &ordersv1.AcceptOrderTransferRequest{UserId: "u-2", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+	repo.payments[0].row.Status = "SUCCEEDED"
+	repo.orders[1].row.Status = "PARTIALLY_PAID"
+
+	accepted, err := h.AcceptOrderTransfer(ctx, &ordersv1.AcceptOrderTransferRequest{UserId: "u-2", OrderId: orderID})
+	if err != nil {
+		t.Fatalf("AcceptOrderTransfer() error: %v", err)
+	}
+	if o := accepted.GetOrder(); o.GetUserId() != "u-2" || o.GetVersion() != 2 {
+		t.Fatalf("AcceptOrderTransfer() = %v, want owner u-2 at version 2", o)
+	}
+	var ev eventsv1.OrderTransferred
+	if err := kafkasvc.UnmarshalEvent(repo.outbox[len(repo.outbox)-1].Payload, &ev); err != nil {
+		t.Fatalf("unmarshal outbox payload: %v", err)
+	}
+	if repo.outbox[len(repo.outbox)-1].Topic != TopicOrderTransferred || ev.GetFromUserId() != "u-1" || ev.GetToUserId() != "u-2" || ev.GetTransferId() != offer.GetTransfer().GetTransferId() {
+		t.Fatalf("outbox event = %v, want OrderTransferred u-1 -> u-2", &ev)
+	}
+	var changed eventsv1.OrderChanged
+	if err := kafkasvc.UnmarshalEvent(repo.changed[len(repo.changed)-1].Payload, &changed); err != nil || changed.GetUserId() != "u-2" || changed.GetPreviousUserId() != "u-1" {
+		t.Fatalf("OrderChanged = %v (%v), want the order moved from u-1 to u-2", &changed, err)
+	}
+
+	_, err = h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+	if got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-2", OrderId: orderID}); err != nil || got.GetOrder().GetUserId() != "u-2" {
+		t.Fatalf("GetOrder() by recipient = (%v, %v), want the order", got.GetOrder(), err)
+	}
+	paid, err := h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-2", OrderId: orderID, Amount: 70})
+	if err != nil {
+		t.Fatalf("PayOrder() by recipient error: %v", err)
+	}
+	if ev := decodeRequested(t, repo.outbox[len(repo.outbox)-1].Payload); ev.GetUserId() != "u-2" || ev.GetPaymentId() != paid.GetPaymentId() {
+		t.Fatalf("outbox event = %v, want a payment of u-2", ev)
+	}
+	_, err = h.AcceptOrderTransfer(ctx, &ordersv1.AcceptOrderTransferRequest{UserId: "u-2", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestWaitOrder(t *testing.T) {
+	repo := newFakeRepo()
+	bus := statusbus.New(nil)
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, bus)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "wait"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+
+	_, err = h.WaitOrder(ctx, &ordersv1.WaitOrderRequest{UserId: "u-1", OrderId: orderID, Timeout: durationpb.New(2 * time.Minute)})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.WaitOrder(ctx, &ordersv1.WaitOrderRequest{UserId: "u-2", OrderId: orderID, Timeout: durationpb.New(time.Second)})
+	wantCode(t, err, codes.NotFound)
+
+	timedOut, err := h.WaitOrder(ctx, &ordersv1.WaitOrderRequest{UserId: "u-1", OrderId: orderID, Timeout: durationpb.New(20 * time.Millisecond)})
+	if err != nil || !timedOut.GetTimedOut() || timedOut.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_NEW {
+		t.Fatalf("WaitOrder() = %v, %v; want a timed out NEW order", timedOut, err)
+	}
+
+	done := make(chan *ordersv1.WaitOrderResponse)
+	go func() {
+		resp, err := h.WaitOrder(ctx, &ordersv1.WaitOrderRequest{UserId: "u-1", OrderId: orderID, Timeout: durationpb.New(10 * time.Second)})
+		if err != nil {
+			t.Errorf("WaitOrder() error: %v", err)
+		}
+		done <- resp
+	}()
+	// A notification without a status change keeps the call waiting.
+	time.Sleep(20 * time.Millisecond)
+	bus.Publish(orderID)
+	time.Sleep(20 * time.Millisecond)
+	repo.mu.Lock()
+	repo.orders[0].row.Status = "FINISHED"
+	repo.mu.Unlock()
+	bus.Publish(orderID)
+
+	select {
+	case resp := <-done:
+		if resp.GetTimedOut() || resp.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_FINISHED {
+			t.Fatalf("WaitOrder() = %v, want the FINISHED order", resp)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitOrder() was not woken by the status change")
+	}
+
+	// A finished order answers at once.
+	resp, err := h.WaitOrder(ctx, &ordersv1.WaitOrderRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil || resp.GetTimedOut() {
+		t.Fatalf("WaitOrder() = %v, %v; want an immediate answer", resp, err)
+	}
+}
+
+func TestWaitOrderLimit(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 0, statusbus.New(nil))
+	h.LimitWaits(1)
+	created, err := h.CreateOrder(context.Background(), &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "wait"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	req := &ordersv1.WaitOrderRequest{UserId: "u-1", OrderId: created.GetOrder().GetOrderId(), Timeout: durationpb.New(10 * time.Second)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := h.WaitOrder(ctx, req)
+		done <- err
+	}()
+	for len(h.waits) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err = h.WaitOrder(context.Background(), req)
+	wantCode(t, err, codes.ResourceExhausted)
+
+	cancel()
+	wantCode(t, <-done, codes.Canceled)
+	// The slot is free again once the first wait returned.
+	req.Timeout = durationpb.New(10 * time.Millisecond)
+	if resp, err := h.WaitOrder(context.Background(), req); err != nil || !resp.GetTimedOut() {
+		t.Fatalf("WaitOrder() after the first returned = %v, %v; want a timed out answer", resp, err)
+	}
+}
@@ -0,0 +1,102 @@
+package grpc
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Limits on integrator-supplied order metadata and tags. They keep a single
+// order row small; anything bigger belongs in the integrator's own storage.
+const (
+	maxMetadataKeys     = 20
+	maxMetadataKeyLen   = 40
+	maxMetadataValueLen = 500
+	maxTags             = 10
+	maxTagLen           = 64
+)
+
+// validateMetadata checks metadata and tags against the size limits. Tags must
+// be non-empty and unique so that filtering by tag is unambiguous. Postgres
+// text and jsonb cannot hold NUL ("\u0000"), so it is rejected up front rather
+// than failing the insert.
+func validateMetadata(metadata map[string]string, tags []string) error {
+	if len(metadata) > maxMetadataKeys {
+		return status.Errorf(codes.InvalidArgument, "metadata must have at most %d keys", maxMetadataKeys)
+	}
+	for k, v := range metadata {
+		if k == "" || len(k) > maxMetadataKeyLen {
+			return status.Errorf(codes.InvalidArgument, "metadata keys must be 1..%d bytes", maxMetadataKeyLen)
+		}
+		if len(v) > maxMetadataValueLen {
+			return status.Errorf(codes.InvalidArgument, "metadata value for %q must be at most %d bytes", k, maxMetadataValueLen)
+		}
+		if hasNUL(k) || hasNUL(v) {
+			return status.Error(codes.InvalidArgument, "metadata must not contain NUL characters")
+		}
+	}
+	if len(tags) > maxTags {
+		return status.Errorf(codes.InvalidArgument, "at most %d tags are allowed", maxTags)
+	}
+	seen := make(map[string]bool, len(tags))
+	for _, t := range tags {
+		if err := validateTag(t); err != nil {
+			return err
+		}
+		if seen[t] {
+			return status.Errorf(codes.InvalidArgument, "duplicate tag %q", t)
+		}
+		seen[t] = true
+	}
+	return nil
+}
+
+func validateTag(tag string) error {
+	if tag == "" || len(tag) > maxTagLen {
+		return status.Errorf(codes.InvalidArgument, "tags must be 1..%d bytes", maxTagLen)
+	}
+	if hasNUL(tag) {
+		return status.Error(codes.InvalidArgument, "tags must not contain NUL characters")
+	}
+	return nil
+}
+
+func hasNUL(s string) bool {
+	return strings.IndexByte(s, 0) >= 0
+}
+
+// encodeMetadata returns the jsonb column value; the columns are NOT NULL, so
+// absent metadata and tags are stored empty rather than as NULL.
+func encodeMetadata(metadata map[string]string, tags []string) ([]byte, []string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	if len(metadata) == 0 {
+		return []byte("{}"), tags, nil
+	}
+	b, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, nil, err
+	}
+	return b, tags, nil
+}
+
+// decodeMetadata parses the jsonb column. The column is only written by
+// encodeMetadata, so a malformed value is logged and dropped.
+func decodeMetadata(b []byte) map[string]string {
+	if len(b) == 0 {
+		return nil
+	}
+	var md map[string]string
+	if err := json.Unmarshal(b, &md); err != nil {
+		slog.Error("decode order metadata failed", "err", err)
+		return nil
+	}
+	if len(md) == 0 {
+		return nil
+	}
+	return md
+}
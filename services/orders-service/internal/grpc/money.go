@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// validateAmount maps money.ValidateAmount to InvalidArgument.
+func validateAmount(amount int64) error {
+	switch err := money.ValidateAmount(amount); {
+	case err == nil:
+		return nil
+	case errors.Is(err, money.ErrTooLarge):
+		return status.Errorf(codes.InvalidArgument, "amount must be <= %d", money.MaxAmount)
+	default:
+		return status.Error(codes.InvalidArgument, "amount must be > 0")
+	}
+}
+
+// checkCurrency accepts an empty code (the service currency is implied) or
+// the service currency itself; orders use a single currency.
+func (h *Handlers) checkCurrency(code string) error {
+	if code == "" {
+		return nil
+	}
+	if c, err := money.ParseCurrency(code); err != nil || c != h.currency {
+		return status.Errorf(codes.InvalidArgument, "currency %q is not supported, use %s", code, h.currency)
+	}
+	return nil
+}
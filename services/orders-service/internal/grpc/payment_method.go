@@ -0,0 +1,30 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultPaymentMethod pays orders created without a payment_method: the
+// internal balance of payments-service.
+const defaultPaymentMethod = "balance"
+
+const maxPaymentMethodLen = 32
+
+// paymentMethod returns the method the payments of the order are requested
+// with. Which methods exist is up to payments-service, which declines the
+// others; only the shape of the name is checked here.
+func paymentMethod(name string) (string, error) {
+	if name == "" {
+		return defaultPaymentMethod, nil
+	}
+	if len(name) > maxPaymentMethodLen {
+		return "", status.Errorf(codes.InvalidArgument, "payment_method must be at most %d bytes", maxPaymentMethodLen)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return "", status.Error(codes.InvalidArgument, "payment_method must be lower-case letters, digits and underscores")
+		}
+	}
+	return name, nil
+}
@@ -0,0 +1,200 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/projection"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// UseProjections registers the projections StartProjectionReplay can
+// rebuild.
+func (h *AdminHandlers) UseProjections(projections ...projection.Projection) {
+	h.projections = append(h.projections, projections...)
+}
+
+// StartProjectionReplay records a replay of a projection; a replica's
+// projection.Replayer picks it up within its poll interval.
+func (h *AdminHandlers) StartProjectionReplay(ctx context.Context, req *ordersv1.StartProjectionReplayRequest) (resp *ordersv1.StartProjectionReplayResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "start projection replay start", "operator", operator, "projection", req.GetProjection(), "rate_limit", req.GetRateLimit(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "start projection replay failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "start projection replay completed", "operator", operator, "replay_id", resp.GetReplay().GetId(), "duration", time.Since(start))
+	}()
+
+	p, ok := projection.Lookup(h.projections, req.GetProjection())
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown projection %q", req.GetProjection())
+	}
+	if req.GetRateLimit() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "rate_limit must not be negative")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	var row db.ProjectionReplay
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		row, err = q.CreateProjectionReplay(ctx, db.CreateProjectionReplayParams{
+			Projection:  p.Name(),
+			Topic:       p.Topic(),
+			RateLimit:   req.GetRateLimit(),
+			RequestedBy: operator,
+			Reason:      req.GetReason(),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Errorf(codes.AlreadyExists, "projection %s is already being replayed", p.Name())
+		}
+		if err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]any{"replay_id": row.ID, "topic": row.Topic, "rate_limit": row.RateLimit})
+		if err != nil {
+			return err
+		}
+		return q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "start_projection_replay",
+			Target:   p.Name(),
+			Reason:   req.GetReason(),
+			Details:  details,
+		})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to start projection replay")
+	}
+	return &ordersv1.StartProjectionReplayResponse{Replay: replayToProto(row, nil)}, nil
+}
+
+// StopProjectionReplay marks a running replay STOPPED; its replica notices
+// with the next batch, which is rolled back.
+func (h *AdminHandlers) StopProjectionReplay(ctx context.Context, req *ordersv1.StopProjectionReplayRequest) (resp *ordersv1.StopProjectionReplayResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "stop projection replay start", "operator", operator, "replay_id", req.GetReplayId(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "stop projection replay failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "stop projection replay completed", "operator", operator, "replay_id", req.GetReplayId(), "applied", resp.GetReplay().GetApplied(), "duration", time.Since(start))
+	}()
+
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	var (
+		row     db.ProjectionReplay
+		offsets []db.ProjectionReplayOffset
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		current, err := q.GetProjectionReplay(ctx, req.GetReplayId())
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.NotFound, "projection replay not found")
+		}
+		if err != nil {
+			return err
+		}
+		row, err = q.FinishProjectionReplay(ctx, db.FinishProjectionReplayParams{Status: projection.ReplayStopped, ID: current.ID})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Errorf(codes.FailedPrecondition, "projection replay is already %s", current.Status)
+		}
+		if err != nil {
+			return err
+		}
+		if offsets, err = q.ListProjectionReplayOffsets(ctx, row.ID); err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]any{"replay_id": row.ID, "applied": row.Applied})
+		if err != nil {
+			return err
+		}
+		return q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "stop_projection_replay",
+			Target:   row.Projection,
+			Reason:   req.GetReason(),
+			Details:  details,
+		})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to stop projection replay")
+	}
+	return &ordersv1.StopProjectionReplayResponse{Replay: replayToProto(row, offsets)}, nil
+}
+
+// GetProjectionReplay returns a replay with its progress.
+func (h *AdminHandlers) GetProjectionReplay(ctx context.Context, req *ordersv1.GetProjectionReplayRequest) (*ordersv1.GetProjectionReplayResponse, error) {
+	var (
+		row     db.ProjectionReplay
+		offsets []db.ProjectionReplayOffset
+	)
+	err := h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		if row, err = q.GetProjectionReplay(ctx, req.GetReplayId()); err != nil {
+			return err
+		}
+		offsets, err = q.ListProjectionReplayOffsets(ctx, row.ID)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "projection replay not found")
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get projection replay failed", "err", err, "replay_id", req.GetReplayId())
+		return nil, status.Error(codes.Internal, "failed to get projection replay")
+	}
+	return &ordersv1.GetProjectionReplayResponse{Replay: replayToProto(row, offsets)}, nil
+}
+
+func replayToProto(r db.ProjectionReplay, offsets []db.ProjectionReplayOffset) *ordersv1.ProjectionReplay {
+	out := &ordersv1.ProjectionReplay{
+		Id:          r.ID,
+		Projection:  r.Projection,
+		Topic:       r.Topic,
+		Status:      ordersv1.ProjectionReplayStatus(ordersv1.ProjectionReplayStatus_value["PROJECTION_REPLAY_STATUS_"+r.Status]),
+		RateLimit:   r.RateLimit,
+		Applied:     r.Applied,
+		Error:       r.Error,
+		RequestedBy: r.RequestedBy,
+		Reason:      r.Reason,
+		CreatedAt:   timestamppb.New(r.CreatedAt.Time),
+		HeartbeatAt: optionalTimestamp(r.HeartbeatAt),
+		FinishedAt:  optionalTimestamp(r.FinishedAt),
+	}
+	for _, o := range offsets {
+		out.Partitions = append(out.Partitions, &ordersv1.ProjectionReplayPartition{
+			Partition:   o.Partition,
+			StartOffset: o.StartOffset,
+			NextOffset:  o.NextOffset,
+			EndOffset:   o.EndOffset,
+		})
+		if o.EndOffset > o.NextOffset {
+			out.Remaining += o.EndOffset - o.NextOffset
+		}
+	}
+	return out
+}
@@ -0,0 +1,70 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/projection"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+func TestProjectionReplay(t *testing.T) {
+	repo := newFakeRepo()
+	admin := NewAdminHandlers(repo, nil, 10, money.RUB)
+	admin.UseProjections(projection.NewOrderStatusHistory("orders.status"))
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+
+	_, err := admin.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: "orders_read", Reason: "bug"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = admin.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: projection.OrderStatusHistoryName})
+	wantCode(t, err, codes.InvalidArgument)
+
+	started, err := admin.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: projection.OrderStatusHistoryName, RateLimit: 100, Reason: "status bug"})
+	if err != nil {
+		t.Fatalf("StartProjectionReplay() error: %v", err)
+	}
+	r := started.GetReplay()
+	if r.GetStatus() != ordersv1.ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_RUNNING || r.GetTopic() != "orders.status" || r.GetRateLimit() != 100 || r.GetRequestedBy() != "alice" {
+		t.Fatalf("StartProjectionReplay() replay = %v", r)
+	}
+	if len(repo.audit) != 1 || repo.audit[0].Action != "start_projection_replay" || repo.audit[0].Target != projection.OrderStatusHistoryName {
+		t.Fatalf("audit = %v, want the start", repo.audit)
+	}
+	_, err = admin.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: projection.OrderStatusHistoryName, Reason: "again"})
+	wantCode(t, err, codes.AlreadyExists)
+
+	// A replica has taken the replay and read half of the topic.
+	repo.offsets = []db.ProjectionReplayOffset{
+		{ReplayID: r.GetId(), Partition: 0, StartOffset: 0, NextOffset: 40, EndOffset: 50},
+		{ReplayID: r.GetId(), Partition: 1, StartOffset: 10, NextOffset: 10, EndOffset: 60},
+	}
+	got, err := admin.GetProjectionReplay(ctx, &ordersv1.GetProjectionReplayRequest{ReplayId: r.GetId()})
+	if err != nil || got.GetReplay().GetRemaining() != 60 || len(got.GetReplay().GetPartitions()) != 2 {
+		t.Fatalf("GetProjectionReplay() = (%v, %v), want 60 remaining in 2 partitions", got.GetReplay(), err)
+	}
+	_, err = admin.GetProjectionReplay(ctx, &ordersv1.GetProjectionReplayRequest{ReplayId: 9})
+	wantCode(t, err, codes.NotFound)
+
+	_, err = admin.StopProjectionReplay(ctx, &ordersv1.StopProjectionReplayRequest{ReplayId: r.GetId()})
+	wantCode(t, err, codes.InvalidArgument)
+	stopped, err := admin.StopProjectionReplay(ctx, &ordersv1.StopProjectionReplayRequest{ReplayId: r.GetId(), Reason: "wrong build"})
+	if err != nil || stopped.GetReplay().GetStatus() != ordersv1.ProjectionReplayStatus_PROJECTION_REPLAY_STATUS_STOPPED || stopped.GetReplay().GetFinishedAt() == nil {
+		t.Fatalf("StopProjectionReplay() = (%v, %v), want STOPPED", stopped.GetReplay(), err)
+	}
+	_, err = admin.StopProjectionReplay(ctx, &ordersv1.StopProjectionReplayRequest{ReplayId: r.GetId(), Reason: "twice"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if len(repo.audit) != 2 || repo.audit[1].Action != "stop_projection_replay" {
+		t.Fatalf("audit = %v, want the start and the stop", repo.audit)
+	}
+
+	// The projection can be replayed again once stopped.
+	if _, err := admin.StartProjectionReplay(ctx, &ordersv1.StartProjectionReplayRequest{Projection: projection.OrderStatusHistoryName, Reason: "retry"}); err != nil {
+		t.Fatalf("StartProjectionReplay() after stop error: %v", err)
+	}
+}
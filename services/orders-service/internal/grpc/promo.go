@@ -0,0 +1,153 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/promo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// google.rpc.ErrorInfo reasons of promo codes rejected by CreateOrder and
+// ValidateOrder; metadata.promo_code holds the code as given.
+const (
+	PromoCodeNotFoundReason    = "PROMO_CODE_NOT_FOUND"
+	PromoCodeInactiveReason    = "PROMO_CODE_INACTIVE"
+	PromoCodeExhaustedReason   = "PROMO_CODE_EXHAUSTED"
+	PromoCodeAlreadyUsedReason = "PROMO_CODE_ALREADY_USED"
+)
+
+// promoError maps the errors of package promo to statuses; other errors are
+// returned as is.
+func promoError(err error, code string) error {
+	c, reason := codes.FailedPrecondition, ""
+	switch {
+	case errors.Is(err, promo.ErrNotFound):
+		c, reason = codes.InvalidArgument, PromoCodeNotFoundReason
+	case errors.Is(err, promo.ErrInactive):
+		reason = PromoCodeInactiveReason
+	case errors.Is(err, promo.ErrExhausted):
+		reason = PromoCodeExhaustedReason
+	case errors.Is(err, promo.ErrAlreadyUsed):
+		reason = PromoCodeAlreadyUsedReason
+	default:
+		return err
+	}
+	st, derr := status.New(c, err.Error()).WithDetails(&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   "orders-service",
+		Metadata: map[string]string{"promo_code": code},
+	})
+	if derr != nil {
+		return status.Error(c, err.Error())
+	}
+	return st.Err()
+}
+
+// CreatePromoCode stores a new promo code.
+func (h *AdminHandlers) CreatePromoCode(ctx context.Context, req *ordersv1.CreatePromoCodeRequest) (resp *ordersv1.CreatePromoCodeResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "create promo code start", "operator", operator, "code", req.GetPromoCode().GetCode())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "create promo code failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "create promo code completed", "operator", operator, "code", resp.GetPromoCode().GetCode(), "duration", time.Since(start))
+	}()
+
+	p := req.GetPromoCode()
+	params := db.CreatePromoCodeParams{
+		Code:                  promo.Normalize(p.GetCode()),
+		PercentOff:            pgtype.Int4{Int32: p.GetPercentOff(), Valid: p.GetPercentOff() != 0},
+		AmountOff:             pgtype.Int8{Int64: p.GetAmountOff(), Valid: p.GetAmountOff() != 0},
+		MaxRedemptions:        pgtype.Int4{Int32: p.GetMaxRedemptions(), Valid: p.GetMaxRedemptions() != 0},
+		MaxRedemptionsPerUser: pgtype.Int4{Int32: p.GetMaxRedemptionsPerUser(), Valid: p.GetMaxRedemptionsPerUser() != 0},
+		StartsAt:              pgtype.Timestamptz{Time: p.GetStartsAt().AsTime(), Valid: p.StartsAt != nil},
+		ExpiresAt:             pgtype.Timestamptz{Time: p.GetExpiresAt().AsTime(), Valid: p.ExpiresAt != nil},
+	}
+	if err := promo.Validate(params); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var row db.PromoCode
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		row, err = q.CreatePromoCode(ctx, params)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Errorf(codes.AlreadyExists, "promo code %s already exists", params.Code)
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to create promo code")
+	}
+	return &ordersv1.CreatePromoCodeResponse{PromoCode: promoToProto(row)}, nil
+}
+
+// GetPromoCode returns a promo code with the totals of its redemptions.
+func (h *AdminHandlers) GetPromoCode(ctx context.Context, req *ordersv1.GetPromoCodeRequest) (resp *ordersv1.GetPromoCodeResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "get promo code start", "operator", operator(ctx), "code", req.GetCode())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get promo code failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get promo code completed", "code", resp.GetPromoCode().GetCode(), "redemptions", resp.GetRedemptions(), "duration", time.Since(start))
+	}()
+
+	code := promo.Normalize(req.GetCode())
+	if code == "" {
+		return nil, status.Error(codes.InvalidArgument, "code is required")
+	}
+	var (
+		row   db.PromoCode
+		stats db.GetPromoCodeStatsRow
+	)
+	err = h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		if row, err = q.GetPromoCode(ctx, code); err != nil {
+			return err
+		}
+		stats, err = q.GetPromoCodeStats(ctx, code)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, status.Error(codes.NotFound, "promo code not found")
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get promo code")
+	}
+	return &ordersv1.GetPromoCodeResponse{
+		PromoCode:     promoToProto(row),
+		Redemptions:   stats.Redemptions,
+		Released:      stats.Released,
+		DiscountTotal: stats.DiscountTotal,
+		AmountTotal:   stats.AmountTotal,
+	}, nil
+}
+
+func promoToProto(c db.PromoCode) *ordersv1.PromoCode {
+	return &ordersv1.PromoCode{
+		Code:                  c.Code,
+		PercentOff:            c.PercentOff.Int32,
+		AmountOff:             c.AmountOff.Int64,
+		MaxRedemptions:        c.MaxRedemptions.Int32,
+		MaxRedemptionsPerUser: c.MaxRedemptionsPerUser.Int32,
+		StartsAt:              optionalTimestamp(c.StartsAt),
+		ExpiresAt:             optionalTimestamp(c.ExpiresAt),
+		RedeemedCount:         c.RedeemedCount,
+		CreatedAt:             timestamppb.New(c.CreatedAt.Time),
+	}
+}
@@ -0,0 +1,111 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func wantPromoReason(t *testing.T, err error, reason string) {
+	t.Helper()
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok && info.GetReason() == reason {
+			return
+		}
+	}
+	t.Fatalf("error = %v, want reason %s", err, reason)
+}
+
+func TestPromoCodes(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	admin := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+
+	for _, p := range []*ordersv1.PromoCode{
+		{Code: "spring10", PercentOff: 10, MaxRedemptions: 2, MaxRedemptionsPerUser: 1},
+		{Code: "FIXED", AmountOff: 1000},
+		{Code: "LATER", PercentOff: 5, StartsAt: timestamppb.New(time.Now().Add(time.Hour))},
+	} {
+		if _, err := admin.CreatePromoCode(ctx, &ordersv1.CreatePromoCodeRequest{PromoCode: p}); err != nil {
+			t.Fatalf("CreatePromoCode(%v) error: %v", p, err)
+		}
+	}
+	_, err := admin.CreatePromoCode(ctx, &ordersv1.CreatePromoCodeRequest{PromoCode: &ordersv1.PromoCode{Code: "Spring10", PercentOff: 20}})
+	wantCode(t, err, codes.AlreadyExists)
+	_, err = admin.CreatePromoCode(ctx, &ordersv1.CreatePromoCodeRequest{PromoCode: &ordersv1.PromoCode{Code: "BOTH", PercentOff: 20, AmountOff: 100}})
+	wantCode(t, err, codes.InvalidArgument)
+
+	quote, err := h.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 505, Description: "book", PromoCode: " spring10 "})
+	if err != nil || quote.GetAmount() != 455 || quote.GetDiscountAmount() != 50 {
+		t.Fatalf("ValidateOrder() = %v (%v), want 455 after a discount of 50", quote, err)
+	}
+	if repo.promos[0].RedeemedCount != 0 {
+		t.Fatal("ValidateOrder redeemed the code")
+	}
+
+	resp, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 505, Description: "book", PromoCode: "spring10"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	if resp.GetOrder().GetAmount() != 455 || resp.GetDiscountAmount() != 50 {
+		t.Fatalf("CreateOrder() = %v, want 455 after a discount of 50", resp)
+	}
+	if ev := decodeRequested(t, repo.outbox[0].Payload); ev.GetAmount() != 455 {
+		t.Fatalf("payment requested for %d, want 455", ev.GetAmount())
+	}
+	if len(repo.redeemed) != 1 || repo.redeemed[0].Code != "SPRING10" || repo.redeemed[0].Amount != 505 || repo.redeemed[0].Discount != 50 {
+		t.Fatalf("redemptions = %v, want SPRING10 taking 50 off 505", repo.redeemed)
+	}
+
+	// One use per user; the second user exhausts the code.
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "pen", PromoCode: "SPRING10"})
+	wantCode(t, err, codes.FailedPrecondition)
+	wantPromoReason(t, err, PromoCodeAlreadyUsedReason)
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-2", Amount: 100, Description: "pen", PromoCode: "SPRING10"}); err != nil {
+		t.Fatalf("CreateOrder() of u-2 error: %v", err)
+	}
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-3", Amount: 100, Description: "pen", PromoCode: "SPRING10"})
+	wantPromoReason(t, err, PromoCodeExhaustedReason)
+	if len(repo.orders) != 2 {
+		t.Fatalf("%d orders created, want the rejected ones rolled back", len(repo.orders))
+	}
+
+	// A fixed discount never makes the order free.
+	fixed, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 300, Description: "cup", PromoCode: "fixed"})
+	if err != nil || fixed.GetOrder().GetAmount() != 1 || fixed.GetDiscountAmount() != 299 {
+		t.Fatalf("CreateOrder(FIXED) = %v (%v), want 1 left to pay", fixed, err)
+	}
+
+	_, err = h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "pen", PromoCode: "LATER"})
+	wantPromoReason(t, err, PromoCodeInactiveReason)
+	_, err = h.ValidateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "pen", PromoCode: "NOPE"})
+	wantCode(t, err, codes.InvalidArgument)
+	wantPromoReason(t, err, PromoCodeNotFoundReason)
+
+	// Cancelling an order gives its redemption back.
+	if _, err := admin.ForceOrderStatus(ctx, &ordersv1.ForceOrderStatusRequest{OrderId: resp.GetOrder().GetOrderId(), Status: ordersv1.OrderStatus_ORDER_STATUS_CANCELLED, Reason: "test"}); err != nil {
+		t.Fatalf("ForceOrderStatus() error: %v", err)
+	}
+	report, err := admin.GetPromoCode(ctx, &ordersv1.GetPromoCodeRequest{Code: "spring10"})
+	if err != nil {
+		t.Fatalf("GetPromoCode() error: %v", err)
+	}
+	if report.GetPromoCode().GetRedeemedCount() != 1 || report.GetRedemptions() != 1 || report.GetReleased() != 1 || report.GetDiscountTotal() != 10 || report.GetAmountTotal() != 100 {
+		t.Fatalf("GetPromoCode() = %v, want one live redemption of 10 off 100 and one released", report)
+	}
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 100, Description: "pen", PromoCode: "SPRING10"}); err != nil {
+		t.Fatalf("CreateOrder() after cancellation error: %v", err)
+	}
+
+	_, err = admin.GetPromoCode(ctx, &ordersv1.GetPromoCodeRequest{Code: "NOPE"})
+	wantCode(t, err, codes.NotFound)
+}
@@ -0,0 +1,36 @@
+package grpc
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+)
+
+// orderReadMask validates the read_mask of GetOrder and ListOrders and
+// returns the function that trims an order down to it. Only top-level Order
+// fields may be selected; an empty mask keeps the whole order.
+func orderReadMask(mask *fieldmaskpb.FieldMask) (func(*ordersv1.Order), error) {
+	paths := mask.GetPaths()
+	if len(paths) == 0 {
+		return func(*ordersv1.Order) {}, nil
+	}
+	fields := (&ordersv1.Order{}).ProtoReflect().Descriptor().Fields()
+	keep := make(map[protoreflect.Name]bool, len(paths))
+	for _, p := range paths {
+		if fields.ByName(protoreflect.Name(p)) == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "read_mask: %q is not an order field", p)
+		}
+		keep[protoreflect.Name(p)] = true
+	}
+	return func(o *ordersv1.Order) {
+		m := o.ProtoReflect()
+		for i := 0; i < fields.Len(); i++ {
+			if f := fields.Get(i); !keep[f.Name()] {
+				m.Clear(f)
+			}
+		}
+	}, nil
+}
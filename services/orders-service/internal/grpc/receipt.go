@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// UseReceiptRenderer lets GetOrderReceipt render receipts in format, e.g.
+// "pdf", with r.
+func (h *Handlers) UseReceiptRenderer(format string, r receipt.Renderer) {
+	if h.renderers == nil {
+		h.renderers = make(map[string]receipt.Renderer)
+	}
+	h.renderers[format] = r
+}
+
+// GetOrderReceipt returns the receipt of a FINISHED order. Orders finished
+// before receipts existed get theirs issued here.
+func (h *Handlers) GetOrderReceipt(ctx context.Context, req *ordersv1.GetOrderReceiptRequest) (resp *ordersv1.GetOrderReceiptResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "get order receipt start", "user_id", req.GetUserId(), "order_id", req.GetOrderId(), "format", req.GetFormat())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get order receipt failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get order receipt completed", "order_id", req.GetOrderId(), "number", resp.GetReceipt().GetNumber(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	var renderer receipt.Renderer
+	if req.GetFormat() != "" {
+		var ok bool
+		if renderer, ok = h.renderers[req.GetFormat()]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "unsupported receipt format %q", req.GetFormat())
+		}
+	}
+
+	params := db.GetReceiptParams{OrderID: pgtype.UUID{Bytes: oid, Valid: true}, UserID: req.GetUserId()}
+	var row db.Receipt
+	err = h.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		row, err = q.GetReceipt(ctx, params)
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = h.repo.InTx(ctx, func(q db.Querier) error {
+			if _, err := q.GetOrder(ctx, db.GetOrderParams{OrderID: params.OrderID, UserID: params.UserID}); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return status.Error(codes.NotFound, "order not found")
+				}
+				return err
+			}
+			if err := receipt.Issue(ctx, q, oid); err != nil {
+				if errors.Is(err, receipt.ErrNotFinished) {
+					return status.Error(codes.FailedPrecondition, "order is not finished")
+				}
+				return err
+			}
+			row, err = q.GetReceipt(ctx, params)
+			return err
+		})
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to get receipt")
+	}
+
+	rc, err := receipt.FromRow(row, h.currency)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get receipt")
+	}
+	resp = &ordersv1.GetOrderReceiptResponse{Receipt: receiptToProto(rc)}
+	if renderer != nil {
+		if resp.Document, err = renderer.Render(rc); err != nil {
+			return nil, status.Error(codes.Internal, "failed to render receipt")
+		}
+		resp.ContentType = renderer.ContentType()
+	}
+	return resp, nil
+}
+
+func receiptToProto(r receipt.Receipt) *ordersv1.Receipt {
+	items := make([]*ordersv1.ReceiptItem, len(r.Items))
+	for i, it := range r.Items {
+		items[i] = &ordersv1.ReceiptItem{
+			Kind:        it.Kind,
+			ProductId:   it.ProductID,
+			Description: it.Description,
+			Quantity:    it.Quantity,
+			UnitPrice:   it.UnitPrice,
+			Amount:      it.Amount,
+		}
+	}
+	return &ordersv1.Receipt{
+		Number:         r.Number,
+		OrderId:        r.OrderID,
+		UserId:         r.UserID,
+		Currency:       string(r.Currency),
+		Items:          items,
+		Amount:         r.Amount,
+		FeeAmount:      r.FeeAmount,
+		TotalAmount:    r.TotalAmount,
+		OrderCreatedAt: timestamppb.New(r.OrderCreatedAt),
+		PaidAt:         timestamppb.New(r.PaidAt),
+		IssuedAt:       timestamppb.New(r.IssuedAt),
+	}
+}
@@ -0,0 +1,59 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
+)
+
+func TestGetOrderReceipt(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	h.UseReceiptRenderer("pdf", receipt.PDF{})
+	ctx := context.Background()
+	order := repo.insertOrder("u-1", 500, "book", nil, nil)
+	oid := order.OrderID.String()
+
+	_, err := h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: oid})
+	wantCode(t, err, codes.FailedPrecondition)
+	_, err = h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: "not-a-uuid"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: oid, Format: "html"})
+	wantCode(t, err, codes.InvalidArgument)
+
+	// Finished before receipts existed: the receipt is issued on first read.
+	repo.orders[0].row.Status, repo.orders[0].row.PaidAmount, repo.orders[0].row.FeeAmount = "FINISHED", 500, 5
+	resp, err := h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: oid})
+	if err != nil {
+		t.Fatalf("GetOrderReceipt() error: %v", err)
+	}
+	r := resp.GetReceipt()
+	if r.GetNumber() == "" || r.GetOrderId() != oid || r.GetCurrency() != "RUB" || r.GetAmount() != 500 || r.GetFeeAmount() != 5 || r.GetTotalAmount() != 505 {
+		t.Fatalf("receipt = %v, want 500 + 5 fee of order %s", r, oid)
+	}
+	if items := r.GetItems(); len(items) != 2 || items[0].GetDescription() != "book" || items[1].GetKind() != receipt.KindFee || items[1].GetAmount() != 5 {
+		t.Fatalf("receipt items = %v, want the order and its fee", items)
+	}
+	if len(resp.GetDocument()) != 0 {
+		t.Fatal("document rendered without a format")
+	}
+
+	again, err := h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-1", OrderId: oid, Format: "pdf"})
+	if err != nil {
+		t.Fatalf("GetOrderReceipt(pdf) error: %v", err)
+	}
+	if again.GetReceipt().GetNumber() != r.GetNumber() || len(repo.receipts) != 1 {
+		t.Fatalf("receipt reissued: %s after %s", again.GetReceipt().GetNumber(), r.GetNumber())
+	}
+	if again.GetContentType() != "application/pdf" || !bytes.HasPrefix(again.GetDocument(), []byte("%PDF-")) {
+		t.Fatalf("document = %q (%s), want a PDF", again.GetDocument(), again.GetContentType())
+	}
+
+	_, err = h.GetOrderReceipt(ctx, &ordersv1.GetOrderReceiptRequest{UserId: "u-2", OrderId: oid})
+	wantCode(t, err, codes.NotFound)
+}
@@ -0,0 +1,166 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/promo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+// notEnoughFundsCode is the payment_failure_code of orders cancelled for
+// insufficient funds, the only ones RetryPayment accepts.
+const notEnoughFundsCode = "NOT_ENOUGH_FUNDS"
+
+// RetryPayment moves an order cancelled for insufficient funds back to NEW
+// and requests its payment again, at most maxPaymentRetries times per order.
+// The amount stays discounted, so the promo code redemption the cancellation
+// gave back is taken again; a code used up or expired since fails the retry.
+func (h *Handlers) RetryPayment(ctx context.Context, req *ordersv1.RetryPaymentRequest) (resp *ordersv1.RetryPaymentResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "retry payment start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "retry payment failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "retry payment completed", "order_id", req.GetOrderId(), "retries_left", resp.GetRetriesLeft(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	if h.maxPaymentRetries <= 0 {
+		return nil, status.Error(codes.FailedPrecondition, "payment retries are disabled")
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		row    db.RetryOrderPaymentRow
+		cached *cache.Order
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		// The order becomes NEW again, so it counts against the quota.
+		if h.maxNewOrders > 0 {
+			if err := q.LockUserOrderCreate(ctx, req.GetUserId()); err != nil {
+				return err
+			}
+			if err := h.checkNewOrdersQuota(ctx, q, req.GetUserId()); err != nil {
+				return err
+			}
+		}
+		var err error
+		row, err = q.RetryOrderPayment(ctx, db.RetryOrderPaymentParams{
+			OrderID:    orderUUID,
+			UserID:     req.GetUserId(),
+			MaxRetries: int32(h.maxPaymentRetries),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return h.retryRejected(ctx, q, orderUUID, req.GetUserId())
+		}
+		if err != nil {
+			return err
+		}
+		if code, err := promo.Reclaim(ctx, q, orderUUID); err != nil {
+			return promoError(err, code)
+		}
+		if cached, err = cachedOrder(ctx, q, h.cache, row.OrderID, row.UserID); err != nil {
+			return err
+		}
+
+		payload, err := kafkasvc.MarshalEvent(&eventsv1.PaymentRequested{
+			EventId:       uuid.NewString(),
+			OccurredAt:    timestamppb.Now(),
+			OrderId:       oid.String(),
+			UserId:        row.UserID,
+			Amount:        row.Amount,
+			CorrelationId: logging.RequestID(ctx),
+			PaymentMethod: row.PaymentMethod,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+			Topic:         "payments.payment_requested.v1",
+			KafkaKey:      oid.String(),
+			Payload:       payload,
+			CorrelationID: logging.RequestID(ctx),
+		}); err != nil {
+			return err
+		}
+		return kafkasvc.InsertStatusChanged(ctx, q, kafkasvc.StatusChange{
+			OrderID: oid.String(),
+			UserID:  row.UserID,
+			From:    "CANCELLED",
+			To:      row.Status,
+			Amount:  row.Amount,
+		})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to retry payment")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, *cached); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", row.OrderID.String())
+		}
+		if err := h.cache.InvalidateList(ctx, row.UserID); err != nil {
+			h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", row.UserID)
+		}
+	}
+
+	return &ordersv1.RetryPaymentResponse{
+		Order: &ordersv1.Order{
+			OrderId:     row.OrderID.String(),
+			UserId:      row.UserID,
+			Amount:      row.Amount,
+			Description: row.Description,
+			Status:      mapOrderStatus(row.Status),
+			CreatedAt:   timestamppb.New(row.CreatedAt.Time),
+			Currency:    string(h.currency),
+			PaidAmount:  row.PaidAmount,
+			FeeAmount:   row.FeeAmount,
+			Metadata:    decodeMetadata(row.Metadata),
+			Tags:        row.Tags,
+			Version:     row.Version,
+			UpdatedAt:   timestamppb.New(row.UpdatedAt.Time),
+			PayAt:       optionalTimestamp(row.PayAt),
+		},
+		RetriesLeft: int32(h.maxPaymentRetries) - row.PaymentRetryCount,
+	}, nil
+}
+
+// retryRejected explains why RetryOrderPayment matched no order.
+func (h *Handlers) retryRejected(ctx context.Context, q db.Querier, orderID pgtype.UUID, userID string) error {
+	order, err := q.GetOrderPaymentRetry(ctx, db.GetOrderPaymentRetryParams{OrderID: orderID, UserID: userID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return status.Error(codes.NotFound, "order not found")
+	}
+	if err != nil {
+		return err
+	}
+	if order.Status != "CANCELLED" || order.PaymentFailureCode.String != notEnoughFundsCode {
+		return status.Error(codes.FailedPrecondition, "only orders cancelled for insufficient funds can be retried")
+	}
+	return status.Errorf(codes.FailedPrecondition, "payment retry limit of %d reached", h.maxPaymentRetries)
+}
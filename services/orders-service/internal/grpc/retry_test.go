@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/catalog"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestRetryPayment(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 2, nil)
+	ctx := context.Background()
+
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	cancel := func(code string) {
+		fo := &repo.orders[len(repo.orders)-1]
+		fo.row.Status, fo.row.PaymentFailureReason, fo.failureCode = "CANCELLED", pgtype.Text{String: "not enough funds", Valid: true}, code
+	}
+
+	_, err = h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+	cancel("NO_ACCOUNT")
+	_, err = h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	cancel("NOT_ENOUGH_FUNDS")
+	_, err = h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-2", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+
+	for want := int32(1); want >= 0; want-- {
+		repo.outbox = nil
+		resp, err := h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+		if err != nil {
+			t.Fatalf("RetryPayment() error: %v", err)
+		}
+		if resp.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_NEW || resp.GetOrder().GetPaymentFailureReason() != "" || resp.GetRetriesLeft() != want {
+			t.Fatalf("RetryPayment() = %v, want NEW with %d retries left", resp, want)
+		}
+		if len(repo.outbox) != 2 || repo.outbox[0].Topic != "payments.payment_requested.v1" || repo.outbox[1].Topic != kafkasvc.TopicOrderStatusChanged {
+			t.Fatalf("outbox = %v, want PaymentRequested and a status change", repo.outbox)
+		}
+		var ev eventsv1.PaymentRequested
+		if err := kafkasvc.UnmarshalEvent(repo.outbox[0].Payload, &ev); err != nil || ev.GetOrderId() != orderID || ev.GetAmount() != 500 {
+			t.Fatalf("payment requested = %v (%v), want order %s for 500", &ev, err, orderID)
+		}
+		cancel("NOT_ENOUGH_FUNDS")
+	}
+
+	_, err = h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	_, err = newTestHandlers(repo).RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestRetryPaymentReclaimsPromoCode(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, nil, catalog.NewStaticResolver(nil), false, money.RUB, 0, 0, 2, nil)
+	admin := NewAdminHandlers(repo, nil, 10, money.RUB)
+	ctx := context.Background()
+
+	if _, err := admin.CreatePromoCode(ctx, &ordersv1.CreatePromoCodeRequest{PromoCode: &ordersv1.PromoCode{Code: "ONCE", PercentOff: 10, MaxRedemptions: 1}}); err != nil {
+		t.Fatalf("CreatePromoCode() error: %v", err)
+	}
+	created, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book", PromoCode: "ONCE"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := created.GetOrder().GetOrderId()
+	// A cancellation for insufficient funds, which gives the redemption back.
+	cancel := func() {
+		fo := &repo.orders[0]
+		fo.row.Status, fo.failureCode = "CANCELLED", "NOT_ENOUGH_FUNDS"
+		if _, err := repo.ReleasePromoRedemption(ctx, fo.row.OrderID); err != nil {
+			t.Fatalf("ReleasePromoRedemption() error: %v", err)
+		}
+	}
+
+	cancel()
+	resp, err := h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil {
+		t.Fatalf("RetryPayment() error: %v", err)
+	}
+	if resp.GetOrder().GetAmount() != 450 || repo.redeemed[0].ReleasedAt.Valid || repo.promos[0].RedeemedCount != 1 {
+		t.Fatalf("RetryPayment() = %v, redemptions = %v, want 450 with the code taken again", resp, repo.redeemed)
+	}
+
+	// Another order takes the only use while this one is cancelled: the
+	// retry would charge a discount the code no longer gives.
+	cancel()
+	if _, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-2", Amount: 100, Description: "pen", PromoCode: "ONCE"}); err != nil {
+		t.Fatalf("CreateOrder() of u-2 error: %v", err)
+	}
+	_, err = h.RetryPayment(ctx, &ordersv1.RetryPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+	wantPromoReason(t, err, PromoCodeExhaustedReason)
+	if repo.orders[0].row.Status != "CANCELLED" || !repo.redeemed[0].ReleasedAt.Valid || repo.promos[0].RedeemedCount != 1 {
+		t.Fatalf("order %s, redemptions = %v after a rejected retry, want it left cancelled", repo.orders[0].row.Status, repo.redeemed)
+	}
+}
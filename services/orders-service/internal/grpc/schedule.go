@@ -0,0 +1,158 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// cancelledByUserReason is stored as the failure reason of orders cancelled
+// through CancelOrder.
+const cancelledByUserReason = "cancelled by user"
+
+// validatePayAt checks the optional pay_at of CreateOrder.
+func validatePayAt(req *ordersv1.CreateOrderRequest, now time.Time) error {
+	if req.PayAt == nil {
+		return nil
+	}
+	if err := req.GetPayAt().CheckValid(); err != nil {
+		return status.Error(codes.InvalidArgument, "invalid pay_at")
+	}
+	if req.GetPayInInstallments() {
+		return status.Error(codes.InvalidArgument, "pay_at cannot be combined with pay_in_installments")
+	}
+	if !req.GetPayAt().AsTime().After(now) {
+		return status.Error(codes.InvalidArgument, "pay_at must be in the future")
+	}
+	return nil
+}
+
+// CancelOrder cancels a SCHEDULED order. Once the scheduler has requested
+// the payment the order is NEW and the call fails with FAILED_PRECONDITION.
+func (h *Handlers) CancelOrder(ctx context.Context, req *ordersv1.CancelOrderRequest) (resp *ordersv1.CancelOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "cancel order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "cancel order failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "cancel order completed", "order_id", req.GetOrderId(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		row    db.CancelScheduledOrderRow
+		cached *cache.Order
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		row, err = q.CancelScheduledOrder(ctx, db.CancelScheduledOrderParams{
+			Reason:  pgtype.Text{String: cancelledByUserReason, Valid: true},
+			OrderID: orderUUID,
+			UserID:  req.GetUserId(),
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			if _, err := q.GetOrder(ctx, db.GetOrderParams{OrderID: orderUUID, UserID: req.GetUserId()}); err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return status.Error(codes.NotFound, "order not found")
+				}
+				return err
+			}
+			return status.Error(codes.FailedPrecondition, "only SCHEDULED orders can be cancelled")
+		}
+		if err != nil {
+			return err
+		}
+		if cached, err = cachedOrder(ctx, q, h.cache, row.OrderID, row.UserID); err != nil {
+			return err
+		}
+		return kafkasvc.InsertStatusChanged(ctx, q, kafkasvc.StatusChange{
+			OrderID:    oid.String(),
+			UserID:     row.UserID,
+			From:       "SCHEDULED",
+			To:         row.Status,
+			Amount:     row.Amount,
+			PaidAmount: row.PaidAmount,
+			Reason:     row.PaymentFailureReason.String,
+		})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to cancel order")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, *cached); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", row.OrderID.String())
+		}
+		if err := h.cache.InvalidateList(ctx, row.UserID); err != nil {
+			h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", row.UserID)
+		}
+	}
+
+	return &ordersv1.CancelOrderResponse{
+		Order: &ordersv1.Order{
+			OrderId:              row.OrderID.String(),
+			UserId:               row.UserID,
+			Amount:               row.Amount,
+			Description:          row.Description,
+			Status:               mapOrderStatus(row.Status),
+			CreatedAt:            timestamppb.New(row.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: row.PaymentFailureReason.String,
+			PaidAmount:           row.PaidAmount,
+			FeeAmount:            row.FeeAmount,
+			Metadata:             decodeMetadata(row.Metadata),
+			Tags:                 row.Tags,
+			Version:              row.Version,
+			UpdatedAt:            timestamppb.New(row.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(row.PayAt),
+		},
+	}, nil
+}
+
+// optionalTimestamp converts a nullable column such as pay_at; NULL stays
+// unset.
+func optionalTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+func optionalTime(t pgtype.Timestamptz) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	return &t.Time
+}
+
+func cachedTimestamp(t *time.Time) *timestamppb.Timestamp {
+	if t == nil {
+		return nil
+	}
+	return timestamppb.New(*t)
+}
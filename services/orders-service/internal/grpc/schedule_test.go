@@ -0,0 +1,94 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+)
+
+func TestCreateScheduledOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+	payAt := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	for _, req := range []*ordersv1.CreateOrderRequest{
+		{UserId: "u-1", Amount: 10, Description: "d", PayAt: timestamppb.New(time.Now().Add(-time.Minute))},
+		{UserId: "u-1", Amount: 10, Description: "d", PayAt: timestamppb.New(payAt), PayInInstallments: true},
+		{UserId: "u-1", Amount: 10, Description: "d", PayAt: &timestamppb.Timestamp{Nanos: -1}},
+	} {
+		_, err := h.CreateOrder(ctx, req)
+		wantCode(t, err, codes.InvalidArgument)
+	}
+
+	resp, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "rent", PayAt: timestamppb.New(payAt)})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	order := resp.GetOrder()
+	if order.GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED || !order.GetPayAt().AsTime().Equal(payAt) {
+		t.Fatalf("CreateOrder() order = %v, want SCHEDULED at %s", order, payAt)
+	}
+	if len(repo.outbox) != 0 {
+		t.Fatalf("outbox has %d events, want none before pay_at", len(repo.outbox))
+	}
+
+	got, err := h.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: "u-1", OrderId: order.GetOrderId()})
+	if err != nil {
+		t.Fatalf("GetOrder() error: %v", err)
+	}
+	if got.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_SCHEDULED || !got.GetOrder().GetPayAt().AsTime().Equal(payAt) {
+		t.Fatalf("GetOrder() order = %v, want SCHEDULED with pay_at", got.GetOrder())
+	}
+	_, err = h.PayOrder(ctx, &ordersv1.PayOrderRequest{UserId: "u-1", OrderId: order.GetOrderId(), Amount: 100})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestCancelOrder(t *testing.T) {
+	repo := newFakeRepo()
+	h := newTestHandlers(repo)
+	ctx := context.Background()
+
+	scheduled, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "rent", PayAt: timestamppb.New(time.Now().Add(time.Hour))})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	orderID := scheduled.GetOrder().GetOrderId()
+
+	_, err = h.CancelOrder(ctx, &ordersv1.CancelOrderRequest{UserId: "u-2", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+	_, err = h.CancelOrder(ctx, &ordersv1.CancelOrderRequest{UserId: "u-1", OrderId: "not-a-uuid"})
+	wantCode(t, err, codes.InvalidArgument)
+
+	resp, err := h.CancelOrder(ctx, &ordersv1.CancelOrderRequest{UserId: "u-1", OrderId: orderID})
+	if err != nil {
+		t.Fatalf("CancelOrder() error: %v", err)
+	}
+	if resp.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_CANCELLED || resp.GetOrder().GetPaymentFailureReason() != cancelledByUserReason {
+		t.Fatalf("CancelOrder() order = %v, want CANCELLED by user", resp.GetOrder())
+	}
+	if len(repo.outbox) != 1 || repo.outbox[0].Topic != kafkasvc.TopicOrderStatusChanged {
+		t.Fatalf("outbox = %v, want one status change", repo.outbox)
+	}
+	var ev eventsv1.OrderStatusChanged
+	if err := kafkasvc.UnmarshalEvent(repo.outbox[0].Payload, &ev); err != nil || ev.GetPreviousStatus() != "SCHEDULED" || ev.GetStatus() != "CANCELLED" {
+		t.Fatalf("status change = %v (%v), want SCHEDULED -> CANCELLED", &ev, err)
+	}
+
+	_, err = h.CancelOrder(ctx, &ordersv1.CancelOrderRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.FailedPrecondition)
+
+	paid, err := h.CreateOrder(ctx, &ordersv1.CreateOrderRequest{UserId: "u-1", Amount: 500, Description: "book"})
+	if err != nil {
+		t.Fatalf("CreateOrder() error: %v", err)
+	}
+	_, err = h.CancelOrder(ctx, &ordersv1.CancelOrderRequest{UserId: "u-1", OrderId: paid.GetOrder().GetOrderId()})
+	wantCode(t, err, codes.FailedPrecondition)
+}
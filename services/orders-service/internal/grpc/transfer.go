@@ -0,0 +1,241 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+// TopicOrderTransferred is the outbox topic of OrderTransferred events.
+const TopicOrderTransferred = "orders.order_transferred.v1"
+
+// TransferOrder offers an unfinished order to another user. The order keeps
+// its owner until the recipient accepts; a new offer cancels a pending one.
+func (h *Handlers) TransferOrder(ctx context.Context, req *ordersv1.TransferOrderRequest) (resp *ordersv1.TransferOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "transfer order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId(), "to_user_id", req.GetToUserId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "transfer order failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "transfer order completed", "order_id", req.GetOrderId(), "transfer_id", resp.GetTransfer().GetTransferId(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" || req.GetToUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id, order_id and to_user_id are required")
+	}
+	if req.GetToUserId() == req.GetUserId() {
+		return nil, status.Error(codes.InvalidArgument, "to_user_id must differ from user_id")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	if err = h.checkAccount(ctx, req.GetToUserId()); err != nil {
+		return nil, err
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var row db.CreateOrderTransferRow
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		if err := lockTransferable(ctx, q, orderUUID, req.GetUserId()); err != nil {
+			return err
+		}
+		if err := q.CancelPendingOrderTransfer(ctx, orderUUID); err != nil {
+			return err
+		}
+		row, err = q.CreateOrderTransfer(ctx, db.CreateOrderTransferParams{
+			OrderID:    orderUUID,
+			FromUserID: req.GetUserId(),
+			ToUserID:   req.GetToUserId(),
+		})
+		return err
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to transfer order")
+	}
+
+	return &ordersv1.TransferOrderResponse{
+		Transfer: &ordersv1.OrderTransfer{
+			TransferId: row.TransferID.String(),
+			OrderId:    row.OrderID.String(),
+			FromUserId: row.FromUserID,
+			ToUserId:   row.ToUserID,
+			Status:     mapTransferStatus(row.Status),
+			CreatedAt:  timestamppb.New(row.CreatedAt.Time),
+		},
+	}, nil
+}
+
+// AcceptOrderTransfer moves the order to the recipient of its pending
+// transfer and enqueues OrderTransferred. The order must still be
+// transferable at this point; the offer is not consumed otherwise.
+func (h *Handlers) AcceptOrderTransfer(ctx context.Context, req *ordersv1.AcceptOrderTransferRequest) (resp *ordersv1.AcceptOrderTransferResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "accept order transfer start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "accept order transfer failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "accept order transfer completed", "order_id", req.GetOrderId(), "user_id", req.GetUserId(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	orderUUID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		row      db.SetOrderOwnerRow
+		fromUser string
+		cached   *cache.Order
+	)
+	err = h.repo.InTx(ctx, func(q db.Querier) error {
+		transfer, err := q.GetPendingOrderTransferForUpdate(ctx, db.GetPendingOrderTransferForUpdateParams{
+			OrderID:  orderUUID,
+			ToUserID: req.GetUserId(),
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.NotFound, "no pending transfer of this order to user_id")
+			}
+			return err
+		}
+		fromUser = transfer.FromUserID
+		if err := lockTransferable(ctx, q, orderUUID, fromUser); err != nil {
+			return err
+		}
+		if err := q.AcceptOrderTransfer(ctx, transfer.TransferID); err != nil {
+			return err
+		}
+		row, err = q.SetOrderOwner(ctx, db.SetOrderOwnerParams{
+			ToUserID:   req.GetUserId(),
+			OrderID:    orderUUID,
+			FromUserID: fromUser,
+		})
+		if err != nil {
+			return err
+		}
+		if cached, err = cachedOrder(ctx, q, h.cache, row.OrderID, row.UserID); err != nil {
+			return err
+		}
+
+		payload, err := kafkasvc.MarshalEvent(&eventsv1.OrderTransferred{
+			EventId:    uuid.NewString(),
+			OccurredAt: timestamppb.Now(),
+			OrderId:    req.GetOrderId(),
+			FromUserId: fromUser,
+			ToUserId:   req.GetUserId(),
+			TransferId: transfer.TransferID.String(),
+		})
+		if err != nil {
+			return status.Error(codes.Internal, "failed to marshal event")
+		}
+		if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+			Topic:         TopicOrderTransferred,
+			KafkaKey:      req.GetOrderId(),
+			Payload:       payload,
+			CorrelationID: logging.RequestID(ctx),
+		}); err != nil {
+			return err
+		}
+		return kafkasvc.InsertOrderChanged(ctx, q, kafkasvc.OrderChange{OrderID: req.GetOrderId(), UserID: row.UserID, PreviousUserID: fromUser})
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to accept order transfer")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, *cached); err != nil {
+			h.logger.ErrorContext(ctx, "failed to set order cache", "err", err, "order_id", row.OrderID.String())
+		}
+		for _, userID := range []string{fromUser, row.UserID} {
+			if err := h.cache.InvalidateList(ctx, userID); err != nil {
+				h.logger.ErrorContext(ctx, "failed to invalidate order list cache", "err", err, "user_id", userID)
+			}
+		}
+	}
+
+	return &ordersv1.AcceptOrderTransferResponse{
+		Order: &ordersv1.Order{
+			OrderId:              row.OrderID.String(),
+			UserId:               row.UserID,
+			Amount:               row.Amount,
+			Description:          row.Description,
+			Status:               mapOrderStatus(row.Status),
+			CreatedAt:            timestamppb.New(row.CreatedAt.Time),
+			Currency:             string(h.currency),
+			PaymentFailureReason: row.PaymentFailureReason.String,
+			PaidAmount:           row.PaidAmount,
+			FeeAmount:            row.FeeAmount,
+			Metadata:             decodeMetadata(row.Metadata),
+			Tags:                 row.Tags,
+			Version:              row.Version,
+			UpdatedAt:            timestamppb.New(row.UpdatedAt.Time),
+			PayAt:                optionalTimestamp(row.PayAt),
+		},
+	}, nil
+}
+
+// lockTransferable locks the order of owner and checks that it can change
+// hands: it is unfinished and no payment of it is in flight, so every
+// payment result still lands on the account that was charged.
+func lockTransferable(ctx context.Context, q db.Querier, orderID pgtype.UUID, owner string) error {
+	order, err := q.GetOrderForUpdate(ctx, db.GetOrderForUpdateParams{OrderID: orderID, UserID: owner})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.NotFound, "order not found")
+		}
+		return err
+	}
+	if order.Status != "NEW" && order.Status != "PARTIALLY_PAID" {
+		return status.Error(codes.FailedPrecondition, "only unfinished orders can be transferred")
+	}
+	inFlight, err := q.OrderHasPaymentInFlight(ctx, orderID)
+	if err != nil {
+		return err
+	}
+	if inFlight {
+		return status.Error(codes.FailedPrecondition, "order has a payment in progress")
+	}
+	return nil
+}
+
+func mapTransferStatus(s string) ordersv1.OrderTransferStatus {
+	switch s {
+	case "PENDING":
+		return ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_PENDING
+	case "ACCEPTED":
+		return ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_ACCEPTED
+	case "CANCELLED":
+		return ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_CANCELLED
+	default:
+		return ordersv1.OrderTransferStatus_ORDER_TRANSFER_STATUS_UNSPECIFIED
+	}
+}
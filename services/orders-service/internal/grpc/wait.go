@@ -0,0 +1,147 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Bounds of the WaitOrder timeout.
+const (
+	defaultWaitTimeout = 30 * time.Second
+	maxWaitTimeout     = 60 * time.Second
+)
+
+// StatusBus wakes WaitOrder calls when an order's status changes;
+// statusbus.Bus implements it.
+type StatusBus interface {
+	Subscribe(orderID string) (changed <-chan struct{}, cancel func())
+	Done() <-chan struct{}
+}
+
+// LimitWaits caps the WaitOrder calls held at once at n; 0 leaves them
+// unbounded. Waits bypass load shedding, so without a cap every client
+// polling an unpaid order keeps a goroutine, a bus subscription and its
+// re-reads alive. It must be called before the handlers serve.
+func (h *Handlers) LimitWaits(n int) {
+	if n > 0 {
+		h.waits = make(chan struct{}, n)
+	}
+}
+
+// WaitOrder holds the call until the order leaves NEW or the timeout
+// expires, and answers with the order either way.
+func (h *Handlers) WaitOrder(ctx context.Context, req *ordersv1.WaitOrderRequest) (resp *ordersv1.WaitOrderResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "wait order start", "user_id", req.GetUserId(), "order_id", req.GetOrderId(), "timeout", req.GetTimeout().AsDuration())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "wait order failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "wait order completed", "order_id", req.GetOrderId(), "timed_out", resp.GetTimedOut(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" || req.GetOrderId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and order_id are required")
+	}
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	timeout := defaultWaitTimeout
+	if t := req.GetTimeout(); t != nil {
+		if t.CheckValid() != nil || t.AsDuration() <= 0 || t.AsDuration() > maxWaitTimeout {
+			return nil, status.Errorf(codes.InvalidArgument, "timeout must be in (0, %s]", maxWaitTimeout)
+		}
+		timeout = t.AsDuration()
+	}
+	if h.bus == nil {
+		return nil, status.Error(codes.Unimplemented, "order status bus is not configured")
+	}
+	if h.waits != nil {
+		select {
+		case h.waits <- struct{}{}:
+			defer func() { <-h.waits }()
+		default:
+			return nil, status.Error(codes.ResourceExhausted, "too many orders being waited on, retry later")
+		}
+	}
+
+	// Subscribe first: a change between the read and the wait still wakes us.
+	changed, cancel := h.bus.Subscribe(oid.String())
+	defer cancel()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		order, err := h.readOrderPrimary(ctx, oid, req.GetUserId())
+		if err != nil {
+			return nil, err
+		}
+		if order.GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_NEW {
+			return &ordersv1.WaitOrderResponse{Order: order}, nil
+		}
+		select {
+		case <-changed:
+		case <-timer.C:
+			return &ordersv1.WaitOrderResponse{Order: order, TimedOut: true}, nil
+		case <-h.bus.Done():
+			// Shutting down: let the client poll again elsewhere.
+			return &ordersv1.WaitOrderResponse{Order: order, TimedOut: true}, nil
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+}
+
+// readOrderPrimary reads the order from the primary: a replica may not have
+// caught up with the change that woke the waiter.
+func (h *Handlers) readOrderPrimary(ctx context.Context, oid uuid.UUID, userID string) (*ordersv1.Order, error) {
+	var r db.GetOrderRow
+	err := h.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		r, err = q.GetOrder(ctx, db.GetOrderParams{
+			OrderID: pgtype.UUID{Bytes: oid, Valid: true},
+			UserID:  userID,
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "order not found")
+		}
+		if ctx.Err() != nil {
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+		return nil, status.Error(codes.Internal, "failed to read order")
+	}
+	return &ordersv1.Order{
+		OrderId:              r.OrderID.String(),
+		UserId:               r.UserID,
+		Amount:               r.Amount,
+		Description:          r.Description,
+		Status:               mapOrderStatus(r.Status),
+		CreatedAt:            timestamppb.New(r.CreatedAt.Time),
+		Currency:             string(h.currency),
+		PaymentFailureReason: r.PaymentFailureReason.String,
+		PaidAmount:           r.PaidAmount,
+		FeeAmount:            r.FeeAmount,
+		Metadata:             decodeMetadata(r.Metadata),
+		Tags:                 r.Tags,
+		Version:              r.Version,
+		UpdatedAt:            timestamppb.New(r.UpdatedAt.Time),
+		PayAt:                optionalTimestamp(r.PayAt),
+		Archived:             r.Archived,
+	}, nil
+}
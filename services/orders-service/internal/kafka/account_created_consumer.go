@@ -0,0 +1,91 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+)
+
+// AccountCreatedConsumer maintains the known_accounts projection from the
+// payments account_created topic. The upsert is idempotent, so redelivered
+// messages need no inbox.
+type AccountCreatedConsumer struct {
+	repo   *postgres.Repo
+	reader *kafka.Reader
+
+	handlerTimeout time.Duration
+}
+
+// NewAccountCreatedConsumer builds the consumer. handlerTimeout bounds the
+// processing of each message; zero leaves it unbounded.
+func NewAccountCreatedConsumer(repo *postgres.Repo, r *kafka.Reader, handlerTimeout time.Duration) *AccountCreatedConsumer {
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("account created consumer initialized")
+	return &AccountCreatedConsumer{repo: repo, reader: r, handlerTimeout: handlerTimeout}
+}
+
+func (c *AccountCreatedConsumer) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	logger.Info("account created consumer run start")
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("account created consumer context done")
+				return nil
+			}
+			logger.Error("account created fetch failed", "err", err)
+			return err
+		}
+
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.handleMessage(hctx, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				logger.Error("account created version not supported, stopping", "err", err, "offset", m.Offset)
+				return err
+			}
+			logger.Error("account created handle error", "err", err, "offset", m.Offset)
+			continue
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			logger.Error("account created commit failed", "err", err, "offset", m.Offset)
+			return err
+		}
+		logger.Debug("account created message committed", "offset", m.Offset)
+	}
+}
+
+func (c *AccountCreatedConsumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	var ev eventsv1.AccountCreated
+	if err := UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
+		logger.Error("account created unmarshal failed", "err", err, "offset", m.Offset)
+		return nil
+	}
+	if ev.GetUserId() == "" {
+		logger.Error("account created without user id", "event_id", ev.GetEventId())
+		return nil
+	}
+	if err := c.repo.Q().UpsertKnownAccount(ctx, ev.GetUserId()); err != nil {
+		logger.Error("known account upsert failed", "err", err, "user_id", ev.GetUserId())
+		return err
+	}
+	logger.Info("known account recorded", "user_id", ev.GetUserId())
+	return nil
+}
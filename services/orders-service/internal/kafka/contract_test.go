@@ -0,0 +1,50 @@
+package kafka
+
+import (
+	"flag"
+	"path/filepath"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/contracttest"
+)
+
+// Contract tests of the events exchanged with payments-service, on the fixtures
+// shared by both services (see pkg/contracttest). After a compatible
+// change, regenerate with
+//
+//	go test ./internal/kafka -run Contract -update
+var update = flag.Bool("update", false, "rewrite the golden contract files")
+
+func contract() contracttest.Suite {
+	return contracttest.Suite{Dir: filepath.Join("..", "..", "..", "..", "api-files", "contract", "events", "v1"), Update: *update}
+}
+
+// contractMessages are the schemas pinned by fields.lock.
+var contractMessages = []protoreflect.MessageDescriptor{
+	(&eventsv1.EventEnvelope{}).ProtoReflect().Descriptor(),
+	(&eventsv1.PaymentRequested{}).ProtoReflect().Descriptor(),
+	(&eventsv1.PaymentResult{}).ProtoReflect().Descriptor(),
+}
+
+func TestContractProducesPaymentRequested(t *testing.T) {
+	var ev eventsv1.PaymentRequested
+	contract().Golden(t, "payment_requested", &ev)
+
+	b, err := MarshalEvent(&ev)
+	if err != nil {
+		t.Fatalf("MarshalEvent: %v", err)
+	}
+	contract().Produced(t, "payment_requested", b, func() proto.Message { return new(eventsv1.EventEnvelope) })
+}
+
+func TestContractConsumesPaymentResult(t *testing.T) {
+	contract().Consumes(t, "payment_result", func() proto.Message { return new(eventsv1.PaymentResult) }, UnmarshalEvent)
+}
+
+func TestContractFieldsLocked(t *testing.T) {
+	contract().FieldsLocked(t, contractMessages...)
+}
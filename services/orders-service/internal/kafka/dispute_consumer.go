@@ -0,0 +1,172 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// DisputeConsumer applies the PaymentDisputeChanged events of payments to
+// orders: an opened dispute moves a FINISHED order to DISPUTED, a refund
+// moves it on to REFUNDED and an upheld payment back to FINISHED. The
+// dispute itself is kept in order_disputes, whose upsert ignores
+// redelivered events, so no inbox is needed.
+type DisputeConsumer struct {
+	repo      *postgres.Repo
+	reader    *kafka.Reader
+	onChanged OrderChangedFunc
+
+	handlerTimeout time.Duration
+}
+
+// NewDisputeConsumer builds the consumer. onChanged may be nil;
+// handlerTimeout bounds the processing of each message, zero leaves it
+// unbounded.
+func NewDisputeConsumer(repo *postgres.Repo, r *kafka.Reader, onChanged OrderChangedFunc, handlerTimeout time.Duration) *DisputeConsumer {
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("dispute consumer initialized")
+	return &DisputeConsumer{repo: repo, reader: r, onChanged: onChanged, handlerTimeout: handlerTimeout}
+}
+
+func (c *DisputeConsumer) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	logger.Info("dispute consumer run start")
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("dispute consumer context done")
+				return nil
+			}
+			logger.Error("dispute fetch failed", "err", err)
+			return err
+		}
+
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.handleMessage(hctx, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				logger.Error("dispute version not supported, stopping", "err", err, "offset", m.Offset)
+				return err
+			}
+			logger.Error("dispute handle error", "err", err, "offset", m.Offset)
+			continue
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			logger.Error("dispute commit failed", "err", err, "offset", m.Offset)
+			return err
+		}
+		logger.Debug("dispute message committed", "offset", m.Offset)
+	}
+}
+
+// disputeTransition is the order_disputes status of a dispute event and the
+// order status change it makes.
+type disputeTransition struct {
+	dispute  string
+	from, to string
+}
+
+func transitionOf(s eventsv1.DisputeStatus) (disputeTransition, bool) {
+	switch s {
+	case eventsv1.DisputeStatus_DISPUTE_STATUS_OPEN:
+		return disputeTransition{dispute: "OPEN", from: "FINISHED", to: "DISPUTED"}, true
+	case eventsv1.DisputeStatus_DISPUTE_STATUS_REFUNDED:
+		return disputeTransition{dispute: "REFUNDED", from: "DISPUTED", to: "REFUNDED"}, true
+	case eventsv1.DisputeStatus_DISPUTE_STATUS_UPHELD:
+		return disputeTransition{dispute: "UPHELD", from: "DISPUTED", to: "FINISHED"}, true
+	default:
+		return disputeTransition{}, false
+	}
+}
+
+func (c *DisputeConsumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	var ev eventsv1.PaymentDisputeChanged
+	if err := UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
+		logger.Error("dispute unmarshal failed", "err", err, "offset", m.Offset)
+		return nil
+	}
+	oid, err := uuid.Parse(ev.GetOrderId())
+	if err != nil {
+		logger.Error("dispute with invalid order id", "event_id", ev.GetEventId(), "order_id", ev.GetOrderId())
+		return nil
+	}
+	tr, ok := transitionOf(ev.GetStatus())
+	if !ok {
+		logger.Error("dispute with unknown status", "event_id", ev.GetEventId(), "status", ev.GetStatus().String())
+		return nil
+	}
+	orderID := pgtype.UUID{Bytes: oid, Valid: true}
+	occurredAt := pgtype.Timestamptz{Time: ev.GetOccurredAt().AsTime(), Valid: true}
+	var resolvedAt pgtype.Timestamptz
+	if tr.dispute != "OPEN" {
+		resolvedAt = occurredAt
+	}
+
+	var changed bool
+	err = c.repo.InTx(ctx, func(q db.Querier) error {
+		n, err := q.UpsertOrderDispute(ctx, db.UpsertOrderDisputeParams{
+			OrderID:    orderID,
+			Status:     tr.dispute,
+			Amount:     ev.GetAmount(),
+			Reason:     ev.GetReason(),
+			OpenedAt:   occurredAt,
+			ResolvedAt: resolvedAt,
+		})
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			logger.Info("dispute event already applied", "event_id", ev.GetEventId(), "order_id", ev.GetOrderId(), "status", tr.dispute)
+			return nil
+		}
+		row, err := q.SetOrderDisputeStatus(ctx, db.SetOrderDisputeStatusParams{Status: tr.to, OrderID: orderID, FromStatus: tr.from})
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The dispute is still recorded; only FINISHED orders change status.
+			logger.Warn("dispute leaves order status unchanged", "order_id", ev.GetOrderId(), "expected_status", tr.from)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		changed = true
+		return InsertStatusChanged(ctx, q, StatusChange{
+			OrderID:    ev.GetOrderId(),
+			UserID:     row.UserID,
+			From:       tr.from,
+			To:         tr.to,
+			Amount:     row.Amount,
+			PaidAmount: row.PaidAmount,
+			Reason:     ev.GetReason(),
+		})
+	})
+	if err != nil {
+		logger.Error("dispute apply failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	if changed && c.onChanged != nil && ev.GetUserId() != "" {
+		c.onChanged(ctx, ev.GetUserId(), ev.GetOrderId())
+	}
+	logger.Info("dispute applied", "order_id", ev.GetOrderId(), "status", tr.dispute, "order_changed", changed)
+	return nil
+}
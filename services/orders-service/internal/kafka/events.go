@@ -0,0 +1,21 @@
+package kafka
+
+import (
+	"google.golang.org/protobuf/proto"
+
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+)
+
+// eventProducer is written to EventEnvelope.producer.
+const eventProducer = "orders-service"
+
+// MarshalEvent wraps ev in an EventEnvelope from this service; see
+// events.Marshal.
+func MarshalEvent(ev events.Event) ([]byte, error) {
+	return events.Marshal(eventProducer, ev)
+}
+
+// UnmarshalEvent is events.Unmarshal.
+func UnmarshalEvent(value []byte, ev proto.Message) error {
+	return events.Unmarshal(value, ev)
+}
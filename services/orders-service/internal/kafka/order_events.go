@@ -0,0 +1,51 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// TopicOrderChanged is the outbox topic of OrderChanged events.
+const TopicOrderChanged = "orders.order_changed.v1"
+
+// OrderChange is a change of an order made in the current transaction.
+type OrderChange struct {
+	OrderID string
+	UserID  string
+	// PreviousUserID is the owner the change took the order from, if any.
+	PreviousUserID string
+}
+
+// InsertOrderChanged queues an OrderChanged event for c in the transaction
+// of q. Every write of an order queues one, directly or through
+// InsertStatusChanged, so the orders_read projection sees it.
+func InsertOrderChanged(ctx context.Context, q db.Querier, c OrderChange) error {
+	payload, err := MarshalEvent(&eventsv1.OrderChanged{
+		EventId:        uuid.NewString(),
+		OccurredAt:     timestamppb.Now(),
+		OrderId:        c.OrderID,
+		UserId:         c.UserID,
+		PreviousUserId: c.PreviousUserID,
+		CorrelationId:  logging.RequestID(ctx),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         TopicOrderChanged,
+		KafkaKey:      c.OrderID,
+		Payload:       payload,
+		CorrelationID: logging.RequestID(ctx),
+	}); err != nil {
+		return fmt.Errorf("insert order changed: %w", err)
+	}
+	return nil
+}
@@ -3,6 +3,7 @@ package kafka
 import (
 	"context"
 	"log/slog"
+	"sync"
 	"time"
 
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
@@ -11,84 +12,369 @@ import (
 	"github.com/segmentio/kafka-go"
 
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
 )
 
+// OutboxChannel is the Postgres NOTIFY channel the outbox insert trigger
+// fires (migration 0007_outbox_notify).
+const OutboxChannel = "outbox_inserted"
+
+// listenRetry is the pause before re-establishing a failed LISTEN connection.
+const listenRetry = 5 * time.Second
+
+// OutboxSlots is the fixed number of slots the outbox is split into: a row
+// belongs to slot hash(kafka_key) % OutboxSlots. It does not depend on the
+// number of workers, so changing OUTBOX_WORKERS never moves a key to another
+// slot; LockUnsentOutbox and its index hard-code the same number.
+const OutboxSlots = 64
+
+// MessageWriter is the part of *kafka.Writer the outbox publisher uses.
+type MessageWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+}
+
+// OutboxPublisher runs one worker per partition of the slots: worker i
+// publishes the slots s with s % workers == i, each under an advisory lock,
+// so all events of a key go through one publisher at a time, in id order,
+// while different keys publish in parallel. Instances with different
+// OUTBOX_WORKERS, e.g. during a rolling change, still never publish one key
+// concurrently.
+//
+// With listen on, workers wake on OutboxChannel notifications and the ticker
+// is only a fallback sweep for missed notifications and failed messages.
 type OutboxPublisher struct {
 	repo     *postgres.Repo
-	w        *kafka.Writer
+	w        MessageWriter
 	interval time.Duration
 	batch    int
+	workers  int
+	listen   bool
+	retry    RetryPolicy
+	topics   map[string]string
+	wake     []chan struct{}
+
+	// openTx, when set, switches to transactional publishing with one
+	// producer per partition, opened on first use.
+	openTx    func(partition int) (TxProducer, error)
+	producers []TxProducer
+}
+
+// TxProducer is the part of *kafkatx.Producer the outbox publisher uses.
+type TxProducer interface {
+	Publish(ctx context.Context, msgs []kafkatx.Message, commit func() error) ([]error, error)
+	Close()
+}
+
+// NewOutboxPublisher builds the publisher. A row whose publish fails is
+// retried after retry.Delay(attempts) and dead-lettered after
+// retry.MaxAttempts failures; MaxAttempts 0 retries forever. w must not have
+// a Topic: every message goes to the Kafka topic topics maps the row's topic
+// to, or to the row's topic itself when it is not mapped.
+func NewOutboxPublisher(repo *postgres.Repo, w MessageWriter, interval time.Duration, batch, workers int, listen bool, retry RetryPolicy, topics map[string]string) *OutboxPublisher {
+	workers = min(max(workers, 1), OutboxSlots)
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("outbox publisher initialized", "interval", interval.String(), "batch", batch, "workers", workers, "listen", listen, "max_attempts", retry.MaxAttempts, "backoff", retry.Backoff.String(), "max_backoff", retry.MaxBackoff.String())
+	wake := make([]chan struct{}, workers)
+	for i := range wake {
+		wake[i] = make(chan struct{}, 1)
+	}
+	return &OutboxPublisher{repo: repo, w: w, interval: interval, batch: batch, workers: workers, listen: listen, retry: retry, topics: topics, wake: wake}
 }
 
-func NewOutboxPublisher(repo *postgres.Repo, w *kafka.Writer, interval time.Duration, batch int) *OutboxPublisher {
-	slog.Default().With("service", "orders-service", "component", "kafka").Info("outbox publisher initialized", "interval", interval.String(), "batch", batch)
-	return &OutboxPublisher{repo: repo, w: w, interval: interval, batch: batch}
+// UseTransactions makes the publisher write each batch in one Kafka
+// transaction that commits only after the rows are marked sent, so a crash
+// mid-batch publishes nothing; open returns the producer of a partition. It
+// must be called before Run.
+func (p *OutboxPublisher) UseTransactions(open func(partition int) (TxProducer, error)) {
+	p.openTx = open
+	p.producers = make([]TxProducer, p.workers)
+}
+
+// kafkaTopic returns the Kafka topic of an outbox row's topic.
+func (p *OutboxPublisher) kafkaTopic(topic string) string {
+	if t, ok := p.topics[topic]; ok && t != "" {
+		return t
+	}
+	return topic
 }
 
 func (p *OutboxPublisher) Run(ctx context.Context) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "orders-service", "component", "kafka")
-	logger.Info("outbox publisher run start", "interval", p.interval.String(), "batch", p.batch)
-	t := time.NewTicker(p.interval)
-	defer t.Stop()
+	logger.Info("outbox publisher run start", "interval", p.interval.String(), "batch", p.batch, "workers", p.workers)
 	defer func() {
 		logger.Info("outbox publisher stopped", "duration", time.Since(start))
 	}()
 
+	var wg sync.WaitGroup
+	if p.listen {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.runListener(ctx)
+		}()
+	}
+	for partition := 0; partition < p.workers; partition++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.runWorker(ctx, partition)
+		}()
+	}
+	wg.Wait()
+	for _, producer := range p.producers {
+		if producer != nil {
+			producer.Close()
+		}
+	}
+	logger.Info("outbox publisher context done")
+	return nil
+}
+
+func (p *OutboxPublisher) runWorker(ctx context.Context, partition int) {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka", "partition", partition)
+	t := time.NewTicker(p.interval)
+	defer t.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
-			logger.Info("outbox publisher context done")
-			return nil
+			return
 		case <-t.C:
-			if err := p.publishOnce(ctx); err != nil {
-				logger.Error("outbox publish error", "err", err)
+		case <-p.wake[partition]:
+		}
+		full, err := p.publishOnce(ctx, partition)
+		if err != nil {
+			logger.Error("outbox publish error", "err", err)
+		}
+		if full {
+			// More rows are likely waiting; go again without the ticker.
+			select {
+			case p.wake[partition] <- struct{}{}:
+			default:
 			}
 		}
 	}
 }
 
-func (p *OutboxPublisher) publishOnce(ctx context.Context) error {
-	start := time.Now()
+// wakeAll nudges every worker; a worker that is already due keeps its one
+// pending wake-up.
+func (p *OutboxPublisher) wakeAll() {
+	for _, ch := range p.wake {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// runListener keeps a LISTEN connection open, reconnecting after failures.
+// Publishing carries on by ticker while it is down.
+func (p *OutboxPublisher) runListener(ctx context.Context) {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	for {
+		err := p.listenOnce(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		logger.Warn("outbox listener failed, polling only", "err", err, "retry_in", listenRetry)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(listenRetry):
+		}
+	}
+}
+
+func (p *OutboxPublisher) listenOnce(ctx context.Context) error {
 	logger := slog.Default().With("service", "orders-service", "component", "kafka")
-	logger.Info("outbox publish cycle start")
-	return p.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
-		rows, err := q.LockUnsentOutbox(ctx, int32(p.batch))
+	pooled, err := p.repo.Pool().Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// A LISTENing connection must not be handed to other queries, so it is
+	// taken out of the pool for good.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+OutboxChannel); err != nil {
+		return err
+	}
+	logger.Info("outbox listener started", "channel", OutboxChannel)
+	// Rows inserted while the listener was down were not announced.
+	p.wakeAll()
+	for {
+		if _, err := conn.WaitForNotification(ctx); err != nil {
+			return err
+		}
+		p.wakeAll()
+	}
+}
+
+// slots returns the outbox slots of a partition.
+func (p *OutboxPublisher) slots(partition int) []int32 {
+	out := make([]int32, 0, OutboxSlots/p.workers+1)
+	for s := partition; s < OutboxSlots; s += p.workers {
+		out = append(out, int32(s))
+	}
+	return out
+}
+
+// publishOnce publishes one batch of the partition and reports whether the
+// batch was full.
+func (p *OutboxPublisher) publishOnce(ctx context.Context, partition int) (full bool, err error) {
+	start := time.Now()
+	logger := slog.Default().With("service", "orders-service", "component", "kafka", "partition", partition)
+	logger.Debug("outbox publish cycle start")
+	err = p.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
+		slots, err := q.TryLockOutboxSlots(ctx, p.slots(partition))
+		if err != nil {
+			logger.Error("failed to lock outbox slots", "err", err)
+			return err
+		}
+		if len(slots) == 0 {
+			logger.Debug("outbox slots busy in another instance")
+			return nil
+		}
+
+		rows, err := q.LockUnsentOutbox(ctx, db.LockUnsentOutboxParams{
+			Slots:     slots,
+			BatchSize: int32(p.batch),
+		})
 		if err != nil {
 			logger.Error("failed to lock unsent outbox rows", "err", err)
 			return err
 		}
 		if len(rows) == 0 {
-			logger.Info("outbox publish cycle empty", "duration", time.Since(start))
+			logger.Debug("outbox publish cycle empty", "duration", time.Since(start))
 			return nil
 		}
 
-		for _, r := range rows {
-			msg := kafka.Message{
-				Key:   []byte(r.KafkaKey),
-				Value: r.Payload,
+		var sent int
+		if p.openTx != nil {
+			sent, err = p.publishTx(ctx, q, partition, rows)
+			if err != nil {
+				return err
 			}
+		} else {
+			// A key whose message failed is held back for the rest of the
+			// batch so that its later messages are not published ahead of it.
+			failedKeys := map[string]bool{}
+			for _, r := range rows {
+				if failedKeys[r.KafkaKey] {
+					continue
+				}
+				// Only for the log lines: they carry the request id of the API
+				// call behind the event.
+				rctx := logging.WithRequestID(ctx, r.CorrelationID)
+				msg := kafka.Message{
+					Topic: p.kafkaTopic(r.Topic),
+					Key:   []byte(r.KafkaKey),
+					Value: r.Payload,
+				}
+
+				if err := p.w.WriteMessages(ctx, msg); err != nil {
+					failedKeys[r.KafkaKey] = true
+					p.markFailed(rctx, q, logger, r, err)
+					continue
+				}
 
-			if err := p.w.WriteMessages(ctx, msg); err != nil {
-				_ = q.MarkOutboxAttemptFailed(ctx, db.MarkOutboxAttemptFailedParams{
-					ID: r.ID,
-					LastError: pgtype.Text{
-						String: err.Error(),
-						Valid:  true,
-					},
-				})
-				logger.Error("failed to publish outbox message", "err", err, "outbox_id", r.ID, "kafka_key", r.KafkaKey)
-				continue
+				if err := q.MarkOutboxSent(ctx, r.ID); err != nil {
+					logger.Error("failed to mark outbox as sent", "err", err, "outbox_id", r.ID)
+					return err
+				}
+				sent++
+				logger.DebugContext(rctx, "outbox message published", "outbox_id", r.ID, "kafka_key", r.KafkaKey)
 			}
+		}
+
+		// Only a cleanly sent full batch suggests a backlog; with failures,
+		// going again at once would just spin on them.
+		full = sent == p.batch
+		logger.Info("outbox publish cycle completed", "count", len(rows), "sent", sent, "duration", time.Since(start))
+		return nil
+	})
+	return full && err == nil, err
+}
 
+// publishTx publishes rows in one Kafka transaction that commits only once
+// they are marked sent in q's transaction, and returns how many were sent:
+// all of them or none. Rows whose message failed back off as in plain mode;
+// the others of an aborted transaction stay due and go again in the next
+// cycle. An error leaves the Kafka transaction aborted or its outcome
+// unknown, and rolls back the marks.
+func (p *OutboxPublisher) publishTx(ctx context.Context, q *db.Queries, partition int, rows []db.LockUnsentOutboxRow) (int, error) {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka", "partition", partition)
+	producer := p.producers[partition]
+	if producer == nil {
+		var err error
+		if producer, err = p.openTx(partition); err != nil {
+			logger.Error("failed to open transactional producer", "err", err)
+			return 0, err
+		}
+		p.producers[partition] = producer
+	}
+
+	msgs := make([]kafkatx.Message, len(rows))
+	for i, r := range rows {
+		msgs[i] = kafkatx.Message{Topic: p.kafkaTopic(r.Topic), Key: []byte(r.KafkaKey), Value: r.Payload}
+	}
+	errs, err := producer.Publish(ctx, msgs, func() error {
+		for _, r := range rows {
 			if err := q.MarkOutboxSent(ctx, r.ID); err != nil {
 				logger.Error("failed to mark outbox as sent", "err", err, "outbox_id", r.ID)
 				return err
 			}
-			logger.Info("outbox message published", "outbox_id", r.ID, "kafka_key", r.KafkaKey)
 		}
-
-		logger.Info("outbox publish cycle completed", "count", len(rows), "duration", time.Since(start))
 		return nil
 	})
+	if err != nil {
+		// The producer may be fenced or stuck in a failed transaction; a
+		// new one resumes under the same transactional id.
+		producer.Close()
+		p.producers[partition] = nil
+		logger.Error("outbox transaction failed", "err", err, "count", len(rows))
+		return 0, err
+	}
+	if errs != nil {
+		for i, r := range rows {
+			if errs[i] != nil {
+				p.markFailed(logging.WithRequestID(ctx, r.CorrelationID), q, logger, r, errs[i])
+			}
+		}
+		logger.Warn("outbox transaction aborted", "count", len(rows))
+		return 0, nil
+	}
+	logger.Debug("outbox transaction committed", "count", len(rows))
+	return len(rows), nil
+}
+
+// markFailed records a failed publish of r: a retry after the policy's
+// delay, or the dead letter once its attempts are used up.
+func (p *OutboxPublisher) markFailed(ctx context.Context, q *db.Queries, logger *slog.Logger, r db.LockUnsentOutboxRow, err error) {
+	lastErr := pgtype.Text{String: err.Error(), Valid: true}
+	attempt := int(r.Attempts) + 1
+	delay, dead := outboxBackoff(p.retry, attempt)
+	if dead {
+		_ = q.MarkOutboxDead(ctx, db.MarkOutboxDeadParams{ID: r.ID, LastError: lastErr})
+		logger.WarnContext(ctx, "outbox message dead-lettered", "err", err, "outbox_id", r.ID, "kafka_key", r.KafkaKey, "attempts", attempt)
+		return
+	}
+	_ = q.MarkOutboxAttemptFailed(ctx, db.MarkOutboxAttemptFailedParams{
+		ID:        r.ID,
+		LastError: lastErr,
+		BackoffMs: delay.Milliseconds(),
+	})
+	logger.ErrorContext(ctx, "failed to publish outbox message", "err", err, "outbox_id", r.ID, "kafka_key", r.KafkaKey, "attempt", attempt, "retry_in", delay)
+}
+
+// outboxBackoff decides what follows failed publish number attempt (1-based):
+// dead-lettering once the policy's attempts are used up, otherwise a retry
+// after the policy's delay.
+func outboxBackoff(policy RetryPolicy, attempt int) (delay time.Duration, dead bool) {
+	if policy.MaxAttempts > 0 && attempt >= policy.MaxAttempts {
+		return 0, true
+	}
+	return policy.Delay(attempt), false
 }
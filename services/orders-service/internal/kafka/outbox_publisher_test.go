@@ -0,0 +1,170 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+)
+
+func TestOutboxPublisherWakeAll(t *testing.T) {
+	p := NewOutboxPublisher(nil, nil, time.Second, 10, 3, true, RetryPolicy{}, nil)
+	p.wakeAll()
+	p.wakeAll() // must not block on workers that already have a wake-up pending
+
+	for i, ch := range p.wake {
+		select {
+		case <-ch:
+		default:
+			t.Fatalf("worker %d was not woken", i)
+		}
+		select {
+		case <-ch:
+			t.Fatalf("worker %d got more than one pending wake-up", i)
+		default:
+		}
+	}
+}
+
+func TestNewOutboxPublisherAtLeastOneWorker(t *testing.T) {
+	p := NewOutboxPublisher(nil, nil, time.Second, 10, 0, false, RetryPolicy{}, nil)
+	if p.workers != 1 || len(p.wake) != 1 {
+		t.Fatalf("workers = %d, wake channels = %d; want 1 and 1", p.workers, len(p.wake))
+	}
+}
+
+func TestOutboxPublisherSlots(t *testing.T) {
+	for _, workers := range []int{1, 3, OutboxSlots, OutboxSlots + 1} {
+		p := NewOutboxPublisher(nil, nil, time.Second, 10, workers, false, RetryPolicy{}, nil)
+		seen := map[int32]int{}
+		for partition := 0; partition < p.workers; partition++ {
+			for _, s := range p.slots(partition) {
+				seen[s]++
+			}
+		}
+		if len(seen) != OutboxSlots {
+			t.Fatalf("workers = %d: %d slots covered, want %d", workers, len(seen), OutboxSlots)
+		}
+		for s, n := range seen {
+			if n != 1 {
+				t.Fatalf("workers = %d: slot %d owned by %d partitions", workers, s, n)
+			}
+		}
+	}
+}
+
+func TestOutboxPublisherKafkaTopic(t *testing.T) {
+	p := NewOutboxPublisher(nil, nil, time.Second, 10, 1, false, RetryPolicy{}, map[string]string{
+		"payments.payment_requested.v1": "staging.payment_requested",
+		"orders.order_transferred.v1":   "",
+	})
+	tests := map[string]string{
+		"payments.payment_requested.v1": "staging.payment_requested",
+		"orders.order_transferred.v1":   "orders.order_transferred.v1",
+		"custom.topic":                  "custom.topic",
+	}
+	for topic, want := range tests {
+		if got := p.kafkaTopic(topic); got != want {
+			t.Fatalf("kafkaTopic(%q) = %q, want %q", topic, got, want)
+		}
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 4, Backoff: time.Second, MaxBackoff: 3 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second}
+	for i, w := range want {
+		delay, dead := outboxBackoff(policy, i+1)
+		if dead || delay != w {
+			t.Fatalf("attempt %d: delay = %s, dead = %v; want %s, false", i+1, delay, dead, w)
+		}
+	}
+	if _, dead := outboxBackoff(policy, 4); !dead {
+		t.Fatal("attempt 4 of 4 is not dead-lettered")
+	}
+
+	forever := RetryPolicy{Backoff: time.Second, MaxBackoff: time.Minute}
+	if delay, dead := outboxBackoff(forever, 1000); dead || delay != time.Minute {
+		t.Fatalf("MaxAttempts 0: delay = %s, dead = %v; want 1m, false", delay, dead)
+	}
+}
+
+// execLog records the outbox updates as "<query name> <row id>".
+type execLog struct {
+	calls []string
+}
+
+func (l *execLog) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	name := strings.Fields(strings.TrimPrefix(sql, "-- name: "))[0]
+	l.calls = append(l.calls, fmt.Sprint(name, " ", args[len(args)-1]))
+	return pgconn.CommandTag{}, nil
+}
+
+func (l *execLog) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (l *execLog) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+// fakeTxProducer keeps the topics of its last batch and fails it when fail
+// is set.
+type fakeTxProducer struct {
+	topics []string
+	fail   bool
+}
+
+func (f *fakeTxProducer) Publish(_ context.Context, msgs []kafkatx.Message, commit func() error) ([]error, error) {
+	f.topics = nil
+	for _, m := range msgs {
+		f.topics = append(f.topics, m.Topic)
+	}
+	if f.fail {
+		errs := make([]error, len(msgs))
+		errs[0] = errors.New("broker unavailable")
+		return errs, nil
+	}
+	return nil, commit()
+}
+
+func (f *fakeTxProducer) Close() {}
+
+func TestOutboxPublisherPublishTx(t *testing.T) {
+	producer := &fakeTxProducer{}
+	p := NewOutboxPublisher(nil, nil, time.Second, 10, 1, false, RetryPolicy{Backoff: time.Second}, map[string]string{"orders.v1": "orders-blue"})
+	p.UseTransactions(func(int) (TxProducer, error) { return producer, nil })
+	rows := []db.LockUnsentOutboxRow{
+		{ID: 1, Topic: "orders.v1", KafkaKey: "a"},
+		{ID: 2, Topic: "other", KafkaKey: "b"},
+	}
+
+	log := &execLog{}
+	if sent, err := p.publishTx(context.Background(), db.New(log), 0, rows); err != nil || sent != 2 {
+		t.Fatalf("publishTx() = (%d, %v), want (2, nil)", sent, err)
+	}
+	if got := strings.Join(producer.topics, ","); got != "orders-blue,other" {
+		t.Fatalf("published topics = %q, want the mapped ones", got)
+	}
+	if got := strings.Join(log.calls, ","); got != "MarkOutboxSent 1,MarkOutboxSent 2" {
+		t.Fatalf("updates = %q, want both rows marked sent", got)
+	}
+
+	producer.fail = true
+	log = &execLog{}
+	if sent, err := p.publishTx(context.Background(), db.New(log), 0, rows); err != nil || sent != 0 {
+		t.Fatalf("aborted publishTx() = (%d, %v), want (0, nil)", sent, err)
+	}
+	if got := strings.Join(log.calls, ","); got != "MarkOutboxAttemptFailed 1" {
+		t.Fatalf("aborted batch updates = %q, want only row 1 backing off", got)
+	}
+}
@@ -2,33 +2,96 @@ package kafka
 
 import (
 	"context"
+	"errors"
 	"log/slog"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 
 	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
 
 	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/pkg/txoffset"
 )
 
+// OrderChangedFunc is called after a change of an order has been committed,
+// e.g. a payment result, to drop cached views of it.
+type OrderChangedFunc func(ctx context.Context, userID, orderID string)
+
+// paymentResultInbox is the inbox consumer name of payment results.
+const paymentResultInbox = "payment_result"
+
 type PaymentResultConsumer struct {
-	repo   *postgres.Repo
-	reader *kafka.Reader
+	repo      *postgres.Repo
+	reader    *kafka.Reader
+	txOffsets bool
+	onChanged OrderChangedFunc
+	retry     RetryPolicy
+	inbox     *inbox.Inbox
+
+	handlerTimeout time.Duration
 }
 
-func NewPaymentResultConsumer(repo *postgres.Repo, r *kafka.Reader) *PaymentResultConsumer {
-	slog.Default().With("service", "orders-service", "component", "kafka").Info("payment result consumer initialized")
-	return &PaymentResultConsumer{repo: repo, reader: r}
+// NewPaymentResultConsumer builds the consumer. With txOffsets set, processed
+// offsets are also recorded in the orders DB and replayed messages are skipped.
+// onChanged may be nil. FAIL_INTERNAL results are rescheduled per retry
+// instead of failing the payment until its attempts run out. handlerTimeout
+// bounds the processing of each message; zero leaves it unbounded.
+func NewPaymentResultConsumer(repo *postgres.Repo, r *kafka.Reader, txOffsets bool, onChanged OrderChangedFunc, retry RetryPolicy, handlerTimeout time.Duration) *PaymentResultConsumer {
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("payment result consumer initialized", "tx_offsets", txOffsets, "retry_max_attempts", retry.MaxAttempts)
+	return &PaymentResultConsumer{repo: repo, reader: r, txOffsets: txOffsets, onChanged: onChanged, retry: retry, inbox: inbox.New(paymentResultInbox), handlerTimeout: handlerTimeout}
+}
+
+// StorePayloads makes the inbox keep every message, so DryRun can run it
+// again later.
+func (c *PaymentResultConsumer) StorePayloads(on bool) {
+	c.inbox.StorePayloads(on)
+}
+
+// Consumer returns the inbox consumer name of payment results.
+func (c *PaymentResultConsumer) Consumer() string {
+	return c.inbox.Consumer()
+}
+
+// InboxDryRun is what the consumer did with a stored inbox message when it
+// was run again without committing anything.
+type InboxDryRun = inbox.DryRun[db.ListTxOutboxRow]
+
+// DryRun runs the stored inbox message messageID through the consumer again,
+// whether or not it was processed, and rolls back its effects. The inbox,
+// the stored offsets and onChanged are left alone.
+func (c *PaymentResultConsumer) DryRun(ctx context.Context, messageID string) (*InboxDryRun, error) {
+	stored, err := inbox.Load(ctx, c.repo.Pool(), c.inbox.Consumer(), messageID)
+	if err != nil {
+		return nil, err
+	}
+	m, err := inbox.KafkaMessage(stored)
+	if err != nil {
+		return nil, err
+	}
+	dry := &InboxDryRun{Message: stored}
+	dry.Err = c.handle(ctx, m, dry)
+	return dry, nil
 }
 
 func (c *PaymentResultConsumer) Run(ctx context.Context) error {
 	logger := slog.Default().With("service", "orders-service", "component", "kafka")
 	logger.Info("payment result consumer run start")
+	if c.txOffsets {
+		if err := txoffset.Seek(ctx, c.reader, []txoffset.Querier{c.repo.Pool()}, logger); err != nil {
+			// Not fatal: the inbox still deduplicates whatever gets redelivered.
+			logger.Error("payment result seek stored offsets failed", "err", err)
+		}
+	}
 	for {
 		m, err := c.reader.FetchMessage(ctx)
 		if err != nil {
@@ -40,7 +103,16 @@ func (c *PaymentResultConsumer) Run(ctx context.Context) error {
 			return err
 		}
 
-		if err := c.handleMessage(ctx, m); err != nil {
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.handleMessage(hctx, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				logger.Error("payment result version not supported, stopping", "err", err, "offset", m.Offset)
+				return err
+			}
 			logger.Error("payment result handle error", "err", err, "offset", m.Offset)
 			// offset НЕ коммитим => Kafka доставит снова
 			continue
@@ -50,68 +122,236 @@ func (c *PaymentResultConsumer) Run(ctx context.Context) error {
 			logger.Error("payment result commit failed", "err", err, "offset", m.Offset)
 			return err
 		}
-		logger.Info("payment result message committed", "offset", m.Offset)
+		logger.Debug("payment result message committed", "offset", m.Offset)
 	}
 }
 
 func (c *PaymentResultConsumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	return c.handle(ctx, m, nil)
+}
+
+// handle applies m. With dry set, nothing is committed and dry collects
+// what would have happened.
+func (c *PaymentResultConsumer) handle(ctx context.Context, m kafka.Message, dry *InboxDryRun) error {
 	logger := slog.Default().With("service", "orders-service", "component", "kafka")
-	logger.Info("payment result handle message start", "offset", m.Offset)
+	if dry != nil {
+		logger = logger.With("dry_run", true)
+	}
+	logger.Debug("payment result handle message start", "offset", m.Offset)
 	var ev eventsv1.PaymentResult
-	if err := proto.Unmarshal(m.Value, &ev); err != nil {
+	if err := UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
 		// плохое сообщение лучше “проглотить” и закоммитить, иначе будет бесконечный цикл
 		logger.Error("payment result unmarshal failed", "err", err, "offset", m.Offset)
+		dry.Skip("unmarshal: " + err.Error())
 		return nil
 	}
+	if dry != nil {
+		dry.Event = &ev
+	}
+	// The correlation id is the request id of the API call that started the
+	// payment, so the lines below are found together with that request's.
+	ctx = logging.WithRequestID(ctx, ev.GetCorrelationId())
+	ctx = logging.WithOrderID(logging.WithUserID(ctx, ev.GetUserId()), ev.GetOrderId())
 
 	msgID, err := uuid.Parse(ev.GetEventId())
 	if err != nil {
-		logger.Error("payment result invalid event id", "err", err, "event_id", ev.GetEventId())
+		logger.ErrorContext(ctx, "payment result invalid event id", "err", err, "event_id", ev.GetEventId())
+		dry.Skip("invalid event id: " + err.Error())
 		return nil
 	}
 
 	orderID, err := uuid.Parse(ev.GetOrderId())
 	if err != nil {
-		logger.Error("payment result invalid order id", "err", err, "order_id", ev.GetOrderId())
+		logger.ErrorContext(ctx, "payment result invalid order id", "err", err, "order_id", ev.GetOrderId())
+		dry.Skip("invalid order id: " + err.Error())
 		return nil
 	}
 
+	var paymentID uuid.UUID
+	if ev.GetPaymentId() != "" {
+		paymentID, err = uuid.Parse(ev.GetPaymentId())
+		if err != nil {
+			logger.ErrorContext(ctx, "payment result invalid payment id", "err", err, "payment_id", ev.GetPaymentId())
+			dry.Skip("invalid payment id: " + err.Error())
+			return nil
+		}
+	}
+
 	newStatus := "CANCELLED"
+	reason := failureReasonFor(&ev)
+	failureReason := pgtype.Text{String: reason, Valid: reason != ""}
+	failureCode := pgtype.Text{String: failureCodeFor(&ev), Valid: true}
+	// payments-service only charges a fee for a successful payment.
+	var fee int64
+	if ev.GetStatus() == eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED {
+		logger.WarnContext(ctx, "payment declined by fraud screening", "order_id", ev.GetOrderId(), "payment_id", ev.GetPaymentId(), "reason", ev.GetReason())
+	}
 	if ev.GetStatus() == eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS {
 		newStatus = "FINISHED"
+		failureReason = pgtype.Text{}
+		failureCode = pgtype.Text{}
+		fee = ev.GetFee()
 	}
 
-	err = c.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
-		inserted, err := q.InsertInboxCheck(ctx, pgtype.UUID{
-			Bytes: msgID,
-			Valid: true,
-		})
-		if err != nil {
-			logger.Error("payment result inbox insert failed", "err", err, "event_id", ev.GetEventId())
-			return err
+	process := func(q *db.Queries) error {
+		if c.retry.Enabled() {
+			if ev.GetStatus() == eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_INTERNAL {
+				scheduled, err := scheduleRetry(ctx, q, c.retry, &ev, orderID, paymentID)
+				if err != nil {
+					return err
+				}
+				if scheduled {
+					return nil
+				}
+			}
+			// The outcome is final, so the retry bookkeeping is no longer needed.
+			if err := q.DeletePaymentRetry(ctx, retryKey(orderID, paymentID)); err != nil {
+				logger.ErrorContext(ctx, "payment retry cleanup failed", "err", err, "order_id", ev.GetOrderId())
+				return err
+			}
 		}
-		if inserted == 0 {
-			logger.Info("payment result already processed", "event_id", ev.GetEventId())
-			return nil
+
+		if paymentID != uuid.Nil {
+			return applyInstallment(ctx, q, paymentID, orderID, ev.GetUserId(), ev.GetStatus() == eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS, failureReason, fee)
 		}
 
-		if err := q.UpdateOrderStatusIfNew(ctx, db.UpdateOrderStatusIfNewParams{
+		row, err := q.UpdateOrderStatusIfNew(ctx, db.UpdateOrderStatusIfNewParams{
 			OrderID: pgtype.UUID{
 				Bytes: orderID,
 				Valid: true,
 			},
-			Status: newStatus,
-		}); err != nil {
-			logger.Error("payment result update order failed", "err", err, "order_id", ev.GetOrderId(), "status", newStatus)
+			Status:               newStatus,
+			PaymentFailureReason: failureReason,
+			FeeAmount:            fee,
+			PaymentFailureCode:   failureCode,
+		})
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Already resolved, or unknown: nothing changed.
+				return nil
+			}
+			logger.ErrorContext(ctx, "payment result update order failed", "err", err, "order_id", ev.GetOrderId(), "status", newStatus)
 			return err
 		}
 
+		return InsertStatusChanged(ctx, q, StatusChange{
+			OrderID:    ev.GetOrderId(),
+			UserID:     row.UserID,
+			From:       "NEW",
+			To:         row.Status,
+			Amount:     row.Amount,
+			PaidAmount: row.PaidAmount,
+			Reason:     failureReason.String,
+		})
+	}
+	msg := inbox.FromKafka(m, msgID.String(), ev.GetCorrelationId())
+	apply := func(tx pgx.Tx, q *db.Queries) error {
+		if dry != nil {
+			return inbox.RunDry(ctx, q, dry, process)
+		}
+		fresh, err := c.inbox.Begin(ctx, tx, msg)
+		if err != nil {
+			logger.ErrorContext(ctx, "payment result inbox claim failed", "err", err, "event_id", ev.GetEventId())
+			return err
+		}
+		if !fresh {
+			logger.InfoContext(ctx, "payment result already processed", "event_id", ev.GetEventId())
+			return nil
+		}
+		if err := process(q); err != nil {
+			return err
+		}
+		return c.inbox.MarkProcessed(ctx, tx, msg)
+	}
+	err = c.repo.WithTx(ctx, txoffset.Wrap(ctx, c.txOffsets && dry == nil, m, apply, logger))
+	if dry != nil && errors.Is(err, inbox.ErrDryRun) {
+		logger.InfoContext(ctx, "payment result dry run completed", "order_id", ev.GetOrderId(), "outbox_events", len(dry.Outbox))
 		return nil
-	})
+	}
 	if err != nil {
-		logger.Error("payment result handle message failed", "err", err, "order_id", ev.GetOrderId())
+		logger.ErrorContext(ctx, "payment result handle message failed", "err", err, "order_id", ev.GetOrderId())
 		return err
 	}
-	logger.Info("payment result handle message completed", "order_id", ev.GetOrderId(), "status", newStatus)
+	if c.onChanged != nil && ev.GetUserId() != "" {
+		c.onChanged(ctx, ev.GetUserId(), ev.GetOrderId())
+	}
+	logger.InfoContext(ctx, "payment result handle message completed", "order_id", ev.GetOrderId(), "payment_id", ev.GetPaymentId(), "result", ev.GetStatus().String())
 	return nil
 }
+
+// applyInstallment resolves a single PayOrder installment. A failed installment
+// leaves the order payable; a successful one adds to paid_amount and its fee
+// to fee_amount, and moves the order to PARTIALLY_PAID or FINISHED.
+func applyInstallment(ctx context.Context, q *db.Queries, paymentID, orderID uuid.UUID, userID string, success bool, reason pgtype.Text, fee int64) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	status := "FAILED"
+	if success {
+		status = "SUCCEEDED"
+		reason = pgtype.Text{}
+	}
+
+	p, err := q.ResolveOrderPayment(ctx, db.ResolveOrderPaymentParams{
+		PaymentID:     pgtype.UUID{Bytes: paymentID, Valid: true},
+		Status:        status,
+		FailureReason: reason,
+		Fee:           fee,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WarnContext(ctx, "payment result for unknown or resolved installment", "payment_id", paymentID.String(), "order_id", orderID.String())
+			return nil
+		}
+		logger.ErrorContext(ctx, "payment result resolve installment failed", "err", err, "payment_id", paymentID.String())
+		return err
+	}
+	if !success {
+		logger.InfoContext(ctx, "payment installment failed", "payment_id", paymentID.String(), "order_id", orderID.String(), "reason", reason.String)
+		// The order is unchanged, but its latest payment reason is not.
+		return InsertOrderChanged(ctx, q, OrderChange{OrderID: orderID.String(), UserID: userID})
+	}
+
+	row, err := q.ApplyOrderPayment(ctx, db.ApplyOrderPaymentParams{
+		OrderID:    pgtype.UUID{Bytes: orderID, Valid: true},
+		PaidAmount: p.Amount,
+		FeeAmount:  fee,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			logger.WarnContext(ctx, "payment installment for an order that is no longer payable", "payment_id", paymentID.String(), "order_id", orderID.String())
+			return nil
+		}
+		logger.ErrorContext(ctx, "payment result apply installment failed", "err", err, "payment_id", paymentID.String(), "order_id", orderID.String())
+		return err
+	}
+	return InsertStatusChanged(ctx, q, StatusChange{
+		OrderID:    orderID.String(),
+		UserID:     row.UserID,
+		From:       row.PreviousStatus,
+		To:         row.Status,
+		Amount:     row.Amount,
+		PaidAmount: row.PaidAmount,
+	})
+}
+
+// fraudDeclinedReason is what the order shows for a payment rejected by fraud
+// screening; the rule that fired stays in the payments-service logs.
+const fraudDeclinedReason = "payment declined: fraud suspected"
+
+// failureReasonFor returns the reason stored on the order or installment.
+func failureReasonFor(ev *eventsv1.PaymentResult) string {
+	if ev.GetStatus() == eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED {
+		return fraudDeclinedReason
+	}
+	return ev.GetReason()
+}
+
+// failureCodeFor returns the payment_failure_code stored on an order whose
+// payment failed: the result status without the PAYMENT_RESULT_STATUS_FAIL_
+// prefix, e.g. NOT_ENOUGH_FUNDS. RetryPayment only accepts orders with that
+// code.
+func failureCodeFor(ev *eventsv1.PaymentResult) string {
+	return strings.TrimPrefix(strings.TrimPrefix(ev.GetStatus().String(), "PAYMENT_RESULT_STATUS_"), "FAIL_")
+}
@@ -0,0 +1,67 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+func TestFailureReasonFor(t *testing.T) {
+	cases := []struct {
+		status eventsv1.PaymentResultStatus
+		reason string
+		want   string
+	}{
+		{eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS, "not enough funds", "not enough funds"},
+		{eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED, "user is denylisted", fraudDeclinedReason},
+		{eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS, "", ""},
+	}
+	for _, tc := range cases {
+		got := failureReasonFor(&eventsv1.PaymentResult{Status: tc.status, Reason: tc.reason})
+		if got != tc.want {
+			t.Fatalf("failureReasonFor(%s, %q) = %q, want %q", tc.status, tc.reason, got, tc.want)
+		}
+	}
+}
+
+func TestFailureCodeFor(t *testing.T) {
+	cases := map[eventsv1.PaymentResultStatus]string{
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS:  "NOT_ENOUGH_FUNDS",
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT:        "NO_ACCOUNT",
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED:   "FRAUD_SUSPECTED",
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED:    "LIMIT_EXCEEDED",
+		eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED: "CHALLENGE_EXPIRED",
+	}
+	for status, want := range cases {
+		if got := failureCodeFor(&eventsv1.PaymentResult{Status: status}); got != want {
+			t.Fatalf("failureCodeFor(%s) = %q, want %q", status, got, want)
+		}
+	}
+}
+
+func TestPaymentResultLogsCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(slog.NewJSONHandler(&buf, nil))))
+	defer slog.SetDefault(prev)
+
+	payload, err := MarshalEvent(&eventsv1.PaymentResult{EventId: "not-a-uuid", OrderId: uuid.NewString(), UserId: "u-1", CorrelationId: "req-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The invalid event id is skipped before the database is touched.
+	if err := (&PaymentResultConsumer{}).handle(context.Background(), kafka.Message{Value: payload}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"request_id":"req-1"`) {
+		t.Fatalf("consumer logs lack the request id:\n%s", buf.String())
+	}
+}
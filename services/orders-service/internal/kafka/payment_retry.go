@@ -0,0 +1,167 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// RetryPolicy bounds how FAIL_INTERNAL payment results and failed outbox
+// publishes are retried. For payment results the zero value disables
+// retries, so an internal failure is terminal.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// Enabled reports whether FAIL_INTERNAL results are retried at all.
+func (p RetryPolicy) Enabled() bool {
+	return p.MaxAttempts > 0
+}
+
+// Delay returns the wait before retry number attempt (1-based): Backoff
+// doubled per previous attempt and capped at MaxBackoff.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	d := p.Backoff
+	for i := 1; i < attempt && (p.MaxBackoff <= 0 || d < p.MaxBackoff); i++ {
+		d *= 2
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+	return d
+}
+
+// retryKey identifies the payment being retried: the installment id, or the
+// order id for up-front payments.
+func retryKey(orderID, paymentID uuid.UUID) string {
+	if paymentID != uuid.Nil {
+		return paymentID.String()
+	}
+	return orderID.String()
+}
+
+// scheduleRetry records the next attempt for a FAIL_INTERNAL result. It
+// returns false once the policy's attempts are used up and the failure should
+// be treated as terminal.
+func scheduleRetry(ctx context.Context, q *db.Queries, policy RetryPolicy, ev *eventsv1.PaymentResult, orderID, paymentID uuid.UUID) (bool, error) {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	key := retryKey(orderID, paymentID)
+
+	attempts, err := q.GetPaymentRetryAttempts(ctx, key)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		logger.ErrorContext(ctx, "payment retry attempts lookup failed", "err", err, "order_id", ev.GetOrderId())
+		return false, err
+	}
+	if int(attempts) >= policy.MaxAttempts {
+		logger.WarnContext(ctx, "payment retries exhausted", "order_id", ev.GetOrderId(), "payment_id", ev.GetPaymentId(), "attempts", attempts)
+		return false, nil
+	}
+
+	next := int(attempts) + 1
+	delay := policy.Delay(next)
+	if err := q.SchedulePaymentRetry(ctx, db.SchedulePaymentRetryParams{
+		RetryKey:      key,
+		OrderID:       pgtype.UUID{Bytes: orderID, Valid: true},
+		PaymentID:     pgtype.UUID{Bytes: paymentID, Valid: paymentID != uuid.Nil},
+		UserID:        ev.GetUserId(),
+		Amount:        ev.GetAmount(),
+		Attempts:      int32(next),
+		NextAttemptAt: pgtype.Timestamptz{Time: time.Now().Add(delay), Valid: true},
+		LastReason:    pgtype.Text{String: ev.GetReason(), Valid: ev.GetReason() != ""},
+		CorrelationID: ev.GetCorrelationId(),
+	}); err != nil {
+		logger.ErrorContext(ctx, "payment retry schedule failed", "err", err, "order_id", ev.GetOrderId())
+		return false, err
+	}
+	logger.InfoContext(ctx, "payment retry scheduled", "order_id", ev.GetOrderId(), "payment_id", ev.GetPaymentId(), "attempt", next, "delay", delay)
+	return true, nil
+}
+
+// PaymentRetrier re-publishes PaymentRequested for retries that are due. The
+// events go through the outbox, so they share its delivery guarantees.
+type PaymentRetrier struct {
+	repo     *postgres.Repo
+	topic    string
+	interval time.Duration
+	batch    int
+}
+
+func NewPaymentRetrier(repo *postgres.Repo, topic string, interval time.Duration, batch int) *PaymentRetrier {
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("payment retrier initialized", "topic", topic, "interval", interval.String(), "batch", batch)
+	return &PaymentRetrier{repo: repo, topic: topic, interval: interval, batch: batch}
+}
+
+func (r *PaymentRetrier) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	logger.Info("payment retrier run start", "interval", r.interval.String())
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("payment retrier context done")
+			return nil
+		case <-t.C:
+			if err := r.retryOnce(ctx); err != nil {
+				logger.Error("payment retry cycle failed", "err", err)
+			}
+		}
+	}
+}
+
+func (r *PaymentRetrier) retryOnce(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	return r.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
+		rows, err := q.LockDuePaymentRetries(ctx, int32(r.batch))
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			orderID := uuid.UUID(row.OrderID.Bytes).String()
+			ev := &eventsv1.PaymentRequested{
+				EventId:       uuid.NewString(),
+				OccurredAt:    timestamppb.Now(),
+				OrderId:       orderID,
+				UserId:        row.UserID,
+				Amount:        row.Amount,
+				CorrelationId: row.CorrelationID,
+				PaymentMethod: row.PaymentMethod,
+			}
+			if row.PaymentID.Valid {
+				ev.PaymentId = uuid.UUID(row.PaymentID.Bytes).String()
+			}
+			payload, err := MarshalEvent(ev)
+			if err != nil {
+				return err
+			}
+			if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+				Topic:         r.topic,
+				KafkaKey:      orderID,
+				Payload:       payload,
+				CorrelationID: row.CorrelationID,
+			}); err != nil {
+				return err
+			}
+			if err := q.MarkPaymentRetryPublished(ctx, row.RetryKey); err != nil {
+				return err
+			}
+			logger.InfoContext(logging.WithRequestID(ctx, row.CorrelationID), "payment retry published", "order_id", orderID, "payment_id", ev.PaymentId, "attempt", row.Attempts)
+		}
+		return nil
+	})
+}
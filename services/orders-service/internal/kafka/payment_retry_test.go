@@ -0,0 +1,36 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{MaxAttempts: 5, Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Fatalf("Delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+
+	uncapped := RetryPolicy{MaxAttempts: 3, Backoff: time.Second}
+	if got := uncapped.Delay(4); got != 8*time.Second {
+		t.Fatalf("uncapped Delay(4) = %s, want %s", got, 8*time.Second)
+	}
+	if (RetryPolicy{}).Enabled() {
+		t.Fatal("zero RetryPolicy is enabled")
+	}
+}
+
+func TestRetryKey(t *testing.T) {
+	orderID, paymentID := uuid.New(), uuid.New()
+	if got := retryKey(orderID, uuid.Nil); got != orderID.String() {
+		t.Fatalf("retryKey(order) = %q, want %q", got, orderID)
+	}
+	if got := retryKey(orderID, paymentID); got != paymentID.String() {
+		t.Fatalf("retryKey(installment) = %q, want %q", got, paymentID)
+	}
+}
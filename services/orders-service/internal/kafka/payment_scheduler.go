@@ -0,0 +1,117 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// PaymentScheduler requests the payment of SCHEDULED orders once their
+// pay_at is due: the order moves to NEW and PaymentRequested goes to the
+// outbox in the same transaction, so from then on it is paid like any other
+// order. Due orders are locked with SKIP LOCKED, so several instances can
+// run it side by side.
+type PaymentScheduler struct {
+	repo      *postgres.Repo
+	topic     string
+	interval  time.Duration
+	batch     int
+	onChanged OrderChangedFunc
+}
+
+// NewPaymentScheduler builds the scheduler. onChanged may be nil.
+func NewPaymentScheduler(repo *postgres.Repo, topic string, interval time.Duration, batch int, onChanged OrderChangedFunc) *PaymentScheduler {
+	slog.Default().With("service", "orders-service", "component", "kafka").Info("payment scheduler initialized", "topic", topic, "interval", interval.String(), "batch", batch)
+	return &PaymentScheduler{repo: repo, topic: topic, interval: interval, batch: batch, onChanged: onChanged}
+}
+
+func (s *PaymentScheduler) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	logger.Info("payment scheduler run start", "interval", s.interval.String())
+	t := time.NewTicker(s.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("payment scheduler context done")
+			return nil
+		case <-t.C:
+			if _, err := s.releaseDue(ctx); err != nil {
+				logger.Error("payment scheduler cycle failed", "err", err)
+			}
+		}
+	}
+}
+
+// releaseDue requests the payment of one batch of due orders and returns
+// how many it released.
+func (s *PaymentScheduler) releaseDue(ctx context.Context) (int, error) {
+	logger := slog.Default().With("service", "orders-service", "component", "kafka")
+	var released []db.LockDueScheduledOrdersRow
+	err := s.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
+		rows, err := q.LockDueScheduledOrders(ctx, int32(s.batch))
+		if err != nil {
+			return err
+		}
+		for _, row := range rows {
+			if err := releaseScheduledOrder(ctx, q, s.topic, row); err != nil {
+				return err
+			}
+		}
+		released = rows
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range released {
+		orderID := row.OrderID.String()
+		logger.InfoContext(ctx, "scheduled payment requested", "order_id", orderID, "user_id", row.UserID, "pay_at", row.PayAt.Time, "delay", time.Since(row.PayAt.Time))
+		if s.onChanged != nil {
+			s.onChanged(ctx, row.UserID, orderID)
+		}
+	}
+	return len(released), nil
+}
+
+func releaseScheduledOrder(ctx context.Context, q db.Querier, topic string, row db.LockDueScheduledOrdersRow) error {
+	orderID := row.OrderID.String()
+	if err := q.ReleaseScheduledOrder(ctx, db.ReleaseScheduledOrderParams{OrderID: row.OrderID, CreatedAt: row.CreatedAt}); err != nil {
+		return err
+	}
+	payload, err := MarshalEvent(&eventsv1.PaymentRequested{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       orderID,
+		UserId:        row.UserID,
+		Amount:        row.Amount,
+		PaymentMethod: row.PaymentMethod,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:    topic,
+		KafkaKey: orderID,
+		Payload:  payload,
+	}); err != nil {
+		return err
+	}
+	return InsertStatusChanged(ctx, q, StatusChange{
+		OrderID: orderID,
+		UserID:  row.UserID,
+		From:    "SCHEDULED",
+		To:      "NEW",
+		Amount:  row.Amount,
+	})
+}
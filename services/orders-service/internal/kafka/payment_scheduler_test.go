@@ -0,0 +1,63 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+type schedulerQuerier struct {
+	db.Querier
+
+	released []db.ReleaseScheduledOrderParams
+	outbox   []db.InsertOutboxParams
+}
+
+func (q *schedulerQuerier) ReleaseScheduledOrder(_ context.Context, arg db.ReleaseScheduledOrderParams) error {
+	q.released = append(q.released, arg)
+	return nil
+}
+
+func (q *schedulerQuerier) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	q.outbox = append(q.outbox, arg)
+	return int64(len(q.outbox)), nil
+}
+
+func TestReleaseScheduledOrder(t *testing.T) {
+	q := &schedulerQuerier{}
+	row := db.LockDueScheduledOrdersRow{
+		OrderID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		CreatedAt:     pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		UserID:        "u-1",
+		Amount:        500,
+		PayAt:         pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		PaymentMethod: "card",
+	}
+	if err := releaseScheduledOrder(context.Background(), q, "payments.payment_requested.v1", row); err != nil {
+		t.Fatalf("releaseScheduledOrder() error: %v", err)
+	}
+	if len(q.released) != 1 || q.released[0].OrderID != row.OrderID {
+		t.Fatalf("released = %v, want the order", q.released)
+	}
+	if len(q.outbox) != 3 || q.outbox[0].Topic != "payments.payment_requested.v1" || q.outbox[1].Topic != TopicOrderChanged || q.outbox[2].Topic != TopicOrderStatusChanged {
+		t.Fatalf("outbox = %v, want PaymentRequested, OrderChanged and OrderStatusChanged", q.outbox)
+	}
+	var requested eventsv1.PaymentRequested
+	if err := UnmarshalEvent(q.outbox[0].Payload, &requested); err != nil {
+		t.Fatalf("unmarshal PaymentRequested: %v", err)
+	}
+	if requested.GetOrderId() != row.OrderID.String() || requested.GetUserId() != "u-1" || requested.GetAmount() != 500 || requested.GetPaymentId() != "" || requested.GetPaymentMethod() != "card" {
+		t.Fatalf("PaymentRequested = %v, want the whole order amount by card", &requested)
+	}
+	var changed eventsv1.OrderStatusChanged
+	if err := UnmarshalEvent(q.outbox[2].Payload, &changed); err != nil || changed.GetPreviousStatus() != "SCHEDULED" || changed.GetStatus() != "NEW" {
+		t.Fatalf("OrderStatusChanged = %v (%v), want SCHEDULED -> NEW", &changed, err)
+	}
+}
@@ -0,0 +1,85 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/promo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/receipt"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// TopicOrderStatusChanged is the outbox topic of OrderStatusChanged events.
+const TopicOrderStatusChanged = "orders.order_status_changed.v1"
+
+// StatusChange is a change of an order's status made in the current
+// transaction.
+type StatusChange struct {
+	OrderID    string
+	UserID     string
+	From       string
+	To         string
+	Amount     int64
+	PaidAmount int64
+	Reason     string
+}
+
+// InsertStatusChanged queues an OrderStatusChanged event for c in the
+// transaction of q, so the event is published only if the change commits.
+// The order was written either way, so an OrderChanged event is queued
+// first; a change that keeps the status writes nothing more. An order that
+// becomes FINISHED also gets its receipt in the same transaction; one that
+// is CANCELLED gives its promo code redemption back.
+func InsertStatusChanged(ctx context.Context, q db.Querier, c StatusChange) error {
+	if err := InsertOrderChanged(ctx, q, OrderChange{OrderID: c.OrderID, UserID: c.UserID}); err != nil {
+		return err
+	}
+	if c.From == c.To {
+		return nil
+	}
+	if c.To == "FINISHED" || c.To == "CANCELLED" {
+		id, err := uuid.Parse(c.OrderID)
+		if err != nil {
+			return fmt.Errorf("order status changed: %w", err)
+		}
+		if c.To == "FINISHED" {
+			err = receipt.Issue(ctx, q, id)
+		} else {
+			err = promo.Release(ctx, q, pgtype.UUID{Bytes: id, Valid: true})
+		}
+		if err != nil {
+			return err
+		}
+	}
+	payload, err := MarshalEvent(&eventsv1.OrderStatusChanged{
+		EventId:        uuid.NewString(),
+		OccurredAt:     timestamppb.Now(),
+		OrderId:        c.OrderID,
+		UserId:         c.UserID,
+		PreviousStatus: c.From,
+		Status:         c.To,
+		Amount:         c.Amount,
+		PaidAmount:     c.PaidAmount,
+		Reason:         c.Reason,
+		CorrelationId:  logging.RequestID(ctx),
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         TopicOrderStatusChanged,
+		KafkaKey:      c.OrderID,
+		Payload:       payload,
+		CorrelationID: logging.RequestID(ctx),
+	}); err != nil {
+		return fmt.Errorf("insert order status changed: %w", err)
+	}
+	return nil
+}
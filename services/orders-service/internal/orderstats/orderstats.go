@@ -0,0 +1,127 @@
+// Package orderstats publishes how many orders sit in each status and how
+// long the oldest NEW order has waited for its payment as Prometheus gauges,
+// so operators can see the saga stall without querying the database.
+package orderstats
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// The gauges of the last collection and the counter of failed ones, served
+// by the metrics server at /metrics.
+var (
+	ordersByStatus = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "orders_by_status",
+		Help: "Orders in the hot table per status.",
+	}, []string{"status"})
+	oldestNewSeconds = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_oldest_new_seconds",
+		Help: "How long the oldest NEW order, installment orders aside, has waited for its payment.",
+	})
+	collectedAt = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "orders_status_collected_timestamp_seconds",
+		Help: "Unix time of the last successful collection.",
+	})
+	collectErrors = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "orders_status_collect_errors_total",
+		Help: "Failed collections.",
+	})
+)
+
+func init() {
+	prometheus.MustRegister(ordersByStatus, oldestNewSeconds, collectedAt, collectErrors)
+}
+
+// statuses are published even when no order is in them, so a drained status
+// reads 0 rather than keeping its last count.
+var statuses = []string{"SCHEDULED", "NEW", "PARTIALLY_PAID", "FINISHED", "CANCELLED", "DISPUTED", "REFUNDED"}
+
+// Collector periodically counts the orders of the hot table by status. The
+// counts are read from the replica when there is one; archived orders are
+// not counted.
+type Collector struct {
+	repo     repo.OrdersRepository
+	interval time.Duration
+	now      func() time.Time
+}
+
+func New(repo repo.OrdersRepository, interval time.Duration) *Collector {
+	slog.Default().With("service", "orders-service", "component", "orderstats").Info("order stats collector initialized", "interval", interval.String())
+	return &Collector{repo: repo, interval: interval, now: time.Now}
+}
+
+func (c *Collector) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "orderstats")
+	c.collect(ctx, logger)
+	t := time.NewTicker(c.interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Info("order stats collector stopped")
+			return nil
+		case <-t.C:
+			c.collect(ctx, logger)
+		}
+	}
+}
+
+func (c *Collector) collect(ctx context.Context, logger *slog.Logger) {
+	if err := c.CollectOnce(ctx); err != nil && ctx.Err() == nil {
+		collectErrors.Inc()
+		logger.Error("order stats collection failed", "err", err)
+	}
+}
+
+// CollectOnce runs the aggregate queries and publishes their results.
+func (c *Collector) CollectOnce(ctx context.Context) error {
+	var (
+		rows  []db.CountOrdersByStatusRow
+		since time.Time
+	)
+	err := c.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		if rows, err = q.CountOrdersByStatus(ctx); err != nil {
+			return err
+		}
+		oldest, err := q.OldestNewOrderSince(ctx)
+		if err != nil {
+			return err
+		}
+		if oldest.Valid {
+			since = oldest.Time
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	counts := make(map[string]int64, len(statuses))
+	for _, s := range statuses {
+		counts[s] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Orders
+	}
+	for status, n := range counts {
+		ordersByStatus.WithLabelValues(status).Set(float64(n))
+	}
+	now := c.now()
+	var oldestNew int64
+	if !since.IsZero() && now.After(since) {
+		oldestNew = int64(now.Sub(since).Seconds())
+	}
+	oldestNewSeconds.Set(float64(oldestNew))
+	collectedAt.Set(float64(now.Unix()))
+	slog.Default().With("service", "orders-service", "component", "orderstats").Debug("order stats collected", "new", counts["NEW"], "oldest_new_seconds", oldestNew)
+	return nil
+}
@@ -0,0 +1,82 @@
+package orderstats
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// fakeRepo answers the aggregate queries with fixed results.
+type fakeRepo struct {
+	db.Querier
+	rows   []db.CountOrdersByStatusRow
+	oldest pgtype.Timestamptz
+	fail   bool
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) CountOrdersByStatus(context.Context) ([]db.CountOrdersByStatusRow, error) {
+	if f.fail {
+		return nil, errors.New("boom")
+	}
+	return f.rows, nil
+}
+
+func (f *fakeRepo) OldestNewOrderSince(context.Context) (pgtype.Timestamptz, error) {
+	return f.oldest, nil
+}
+
+// gaugeValue is the value of the status gauge, or of oldest_new_seconds for
+// "oldest_new_seconds".
+func gaugeValue(name string) float64 {
+	if name == "oldest_new_seconds" {
+		return testutil.ToFloat64(oldestNewSeconds)
+	}
+	return testutil.ToFloat64(ordersByStatus.WithLabelValues(name))
+}
+
+func TestCollectOnce(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{
+		rows:   []db.CountOrdersByStatusRow{{Status: "NEW", Orders: 3}, {Status: "FINISHED", Orders: 10}},
+		oldest: pgtype.Timestamptz{Time: now.Add(-90 * time.Second), Valid: true},
+	}
+	c := New(repo, time.Minute)
+	c.now = func() time.Time { return now }
+
+	if err := c.CollectOnce(context.Background()); err != nil {
+		t.Fatalf("CollectOnce() error: %v", err)
+	}
+	want := map[string]float64{"NEW": 3, "FINISHED": 10, "CANCELLED": 0, "SCHEDULED": 0, "PARTIALLY_PAID": 0, "oldest_new_seconds": 90}
+	for name, v := range want {
+		if got := gaugeValue(name); got != v {
+			t.Fatalf("%s = %v, want %v", name, got, v)
+		}
+	}
+
+	// The NEW orders got paid: their gauge drops to 0 instead of keeping 3.
+	repo.rows = []db.CountOrdersByStatusRow{{Status: "FINISHED", Orders: 13}}
+	repo.oldest = pgtype.Timestamptz{}
+	if err := c.CollectOnce(context.Background()); err != nil {
+		t.Fatalf("CollectOnce() error: %v", err)
+	}
+	if gaugeValue("NEW") != 0 || gaugeValue("FINISHED") != 13 || gaugeValue("oldest_new_seconds") != 0 {
+		t.Fatalf("after payment: NEW = %v, FINISHED = %v, oldest_new_seconds = %v", gaugeValue("NEW"), gaugeValue("FINISHED"), gaugeValue("oldest_new_seconds"))
+	}
+
+	repo.fail = true
+	if err := c.CollectOnce(context.Background()); err == nil {
+		t.Fatal("CollectOnce() error = nil, want the query error")
+	}
+	if gaugeValue("FINISHED") != 13 {
+		t.Fatalf("FINISHED = %v after a failed collection, want the last value", gaugeValue("FINISHED"))
+	}
+}
@@ -0,0 +1,76 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Consumer keeps a projection up to date: it applies each message of the
+// projection's topic in its own transaction and then commits the offset.
+type Consumer struct {
+	repo   repo.OrdersRepository
+	reader *kafka.Reader
+	p      Projection
+
+	handlerTimeout time.Duration
+	logger         *slog.Logger
+}
+
+// NewConsumer builds the consumer of p reading with r, a group reader of
+// p.Topic(). handlerTimeout bounds the processing of each message, zero
+// leaves it unbounded.
+func NewConsumer(repo repo.OrdersRepository, r *kafka.Reader, p Projection, handlerTimeout time.Duration) *Consumer {
+	logger := slog.Default().With("service", "orders-service", "component", "projection", "projection", p.Name())
+	logger.Info("projection consumer initialized", "topic", p.Topic())
+	return &Consumer{repo: repo, reader: r, p: p, handlerTimeout: handlerTimeout, logger: logger}
+}
+
+func (c *Consumer) Run(ctx context.Context) error {
+	c.logger.Info("projection consumer run start")
+	for {
+		m, err := c.reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.logger.Info("projection consumer context done")
+				return nil
+			}
+			c.logger.Error("projection fetch failed", "err", err)
+			return err
+		}
+
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.repo.InTx(hctx, func(q db.Querier) error {
+			return c.p.Apply(hctx, q, m)
+		})
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				c.logger.Error("projection event version not supported, stopping", "err", err, "partition", m.Partition, "offset", m.Offset)
+				return err
+			}
+			c.logger.Error("projection apply failed", "err", err, "partition", m.Partition, "offset", m.Offset)
+			continue
+		}
+		if n, ok := c.p.(Notifier); ok {
+			n.Committed(ctx, m)
+		}
+
+		if err := c.reader.CommitMessages(ctx, m); err != nil {
+			c.logger.Error("projection commit failed", "err", err, "offset", m.Offset)
+			return err
+		}
+		c.logger.Debug("projection message committed", "partition", m.Partition, "offset", m.Offset)
+	}
+}
@@ -0,0 +1,110 @@
+package projection
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// PartitionRange is the offsets of one partition of a topic: First is the
+// earliest retained message, End the offset the next committed message
+// will get.
+type PartitionRange struct {
+	Partition int
+	First     int64
+	End       int64
+}
+
+// MessageReader reads the messages of one partition in order.
+type MessageReader interface {
+	ReadMessage(ctx context.Context) (kafka.Message, error)
+	// ReadLag returns how far the partition's high watermark is past the
+	// offset after the last message read.
+	ReadLag(ctx context.Context) (int64, error)
+	Close() error
+}
+
+// Log is the part of Kafka a Replayer reads.
+type Log interface {
+	// Partitions returns the offsets of every partition of topic.
+	Partitions(ctx context.Context, topic string) ([]PartitionRange, error)
+	// Open starts reading partition of topic at offset.
+	Open(topic string, partition int, offset int64) (MessageReader, error)
+}
+
+// KafkaLog is the Log of a Kafka cluster. Only committed messages are
+// read, as by the consumers.
+type KafkaLog struct {
+	client  *kafka.Client
+	brokers []string
+	dialer  *kafka.Dialer
+}
+
+func NewKafkaLog(brokers []string, dialer *kafka.Dialer, transport kafka.RoundTripper) *KafkaLog {
+	return &KafkaLog{
+		client:  &kafka.Client{Addr: kafka.TCP(brokers...), Transport: transport, Timeout: 10 * time.Second},
+		brokers: brokers,
+		dialer:  dialer,
+	}
+}
+
+func (l *KafkaLog) Partitions(ctx context.Context, topic string) ([]PartitionRange, error) {
+	meta, err := l.client.Metadata(ctx, &kafka.MetadataRequest{Topics: []string{topic}})
+	if err != nil {
+		return nil, err
+	}
+	var requests []kafka.OffsetRequest
+	for _, t := range meta.Topics {
+		if t.Name != topic {
+			continue
+		}
+		if t.Error != nil {
+			return nil, t.Error
+		}
+		for _, p := range t.Partitions {
+			requests = append(requests, kafka.FirstOffsetOf(p.ID), kafka.LastOffsetOf(p.ID))
+		}
+	}
+	if len(requests) == 0 {
+		return nil, fmt.Errorf("topic %q has no partitions", topic)
+	}
+	offsets, err := l.client.ListOffsets(ctx, &kafka.ListOffsetsRequest{
+		Topics:         map[string][]kafka.OffsetRequest{topic: requests},
+		IsolationLevel: kafka.ReadCommitted,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ranges := make([]PartitionRange, 0, len(requests)/2)
+	for _, p := range offsets.Topics[topic] {
+		if p.Error != nil {
+			return nil, fmt.Errorf("partition %d offsets: %w", p.Partition, p.Error)
+		}
+		ranges = append(ranges, PartitionRange{Partition: p.Partition, First: p.FirstOffset, End: p.LastOffset})
+	}
+	return ranges, nil
+}
+
+func (l *KafkaLog) Open(topic string, partition int, offset int64) (MessageReader, error) {
+	cfg := kafka.ReaderConfig{
+		Brokers:        l.brokers,
+		Dialer:         l.dialer,
+		Topic:          topic,
+		Partition:      partition,
+		MinBytes:       1e3,
+		MaxBytes:       10e6,
+		MaxWait:        time.Second,
+		IsolationLevel: kafka.ReadCommitted,
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := kafka.NewReader(cfg)
+	if err := r.SetOffset(offset); err != nil {
+		r.Close()
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,72 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// OrderStatusHistoryName is the name of the OrderStatusHistory projection.
+const OrderStatusHistoryName = "order_status_history"
+
+// OrderStatusHistory records every OrderStatusChanged event in
+// order_status_history, keyed by event id.
+type OrderStatusHistory struct {
+	topic string
+}
+
+// NewOrderStatusHistory returns the projection of topic, the topic the
+// OrderStatusChanged events are published to.
+func NewOrderStatusHistory(topic string) *OrderStatusHistory {
+	return &OrderStatusHistory{topic: topic}
+}
+
+func (p *OrderStatusHistory) Name() string  { return OrderStatusHistoryName }
+func (p *OrderStatusHistory) Topic() string { return p.topic }
+
+func (p *OrderStatusHistory) Reset(ctx context.Context, q db.Querier) error {
+	return q.TruncateOrderStatusHistory(ctx)
+}
+
+func (p *OrderStatusHistory) Apply(ctx context.Context, q db.Querier, m kafka.Message) error {
+	logger := slog.Default().With("service", "orders-service", "component", "projection")
+	var ev eventsv1.OrderStatusChanged
+	if err := kafkasvc.UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
+		logger.Error("order status changed unmarshal failed", "err", err, "partition", m.Partition, "offset", m.Offset)
+		return nil
+	}
+	eventID, err := uuid.Parse(ev.GetEventId())
+	if err != nil {
+		logger.Error("order status changed with invalid event id", "event_id", ev.GetEventId(), "offset", m.Offset)
+		return nil
+	}
+	orderID, err := uuid.Parse(ev.GetOrderId())
+	if err != nil {
+		logger.Error("order status changed with invalid order id", "event_id", ev.GetEventId(), "order_id", ev.GetOrderId())
+		return nil
+	}
+	return q.InsertOrderStatusHistory(ctx, db.InsertOrderStatusHistoryParams{
+		EventID:        pgtype.UUID{Bytes: eventID, Valid: true},
+		OrderID:        pgtype.UUID{Bytes: orderID, Valid: true},
+		UserID:         ev.GetUserId(),
+		PreviousStatus: ev.GetPreviousStatus(),
+		Status:         ev.GetStatus(),
+		Amount:         ev.GetAmount(),
+		PaidAmount:     ev.GetPaidAmount(),
+		Reason:         ev.GetReason(),
+		OccurredAt:     pgtype.Timestamptz{Time: ev.GetOccurredAt().AsTime(), Valid: true},
+	})
+}
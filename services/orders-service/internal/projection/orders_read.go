@@ -0,0 +1,101 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// OrdersReadName is the name of the OrdersRead projection.
+const OrdersReadName = "orders_read"
+
+// OrdersRead keeps orders_read, the table ListOrders reads. An OrderChanged
+// event only names its order: Apply copies the order's current state, so
+// events applied twice or late leave the latest state in place. Reset fills
+// the table from the orders again, including those whose events are past
+// the topic's retention.
+type OrdersRead struct {
+	topic     string
+	onChanged kafkasvc.OrderChangedFunc
+}
+
+// NewOrdersRead returns the projection of topic, the topic the OrderChanged
+// events are published to. onChanged, if not nil, is called for the owners
+// of an order once its row is committed; it may be nil.
+func NewOrdersRead(topic string, onChanged kafkasvc.OrderChangedFunc) *OrdersRead {
+	return &OrdersRead{topic: topic, onChanged: onChanged}
+}
+
+func (p *OrdersRead) Name() string  { return OrdersReadName }
+func (p *OrdersRead) Topic() string { return p.topic }
+
+func (p *OrdersRead) Reset(ctx context.Context, q db.Querier) error {
+	if err := q.TruncateOrdersRead(ctx); err != nil {
+		return err
+	}
+	n, err := q.SeedOrdersRead(ctx)
+	if err != nil {
+		return fmt.Errorf("seed orders_read: %w", err)
+	}
+	slog.Default().With("service", "orders-service", "component", "projection").InfoContext(ctx, "orders_read seeded", "orders", n)
+	return nil
+}
+
+func (p *OrdersRead) Apply(ctx context.Context, q db.Querier, m kafka.Message) error {
+	ev, orderID, ok, err := decodeOrderChanged(m)
+	if !ok {
+		return err
+	}
+	if err := q.RefreshOrderRead(ctx, pgtype.UUID{Bytes: orderID, Valid: true}); err != nil {
+		return fmt.Errorf("refresh order %s: %w", ev.GetOrderId(), err)
+	}
+	return nil
+}
+
+// Committed hands the order's owners, before and after the change, to
+// onChanged.
+func (p *OrdersRead) Committed(ctx context.Context, m kafka.Message) {
+	if p.onChanged == nil {
+		return
+	}
+	ev, _, ok, _ := decodeOrderChanged(m)
+	if !ok {
+		return
+	}
+	for _, userID := range []string{ev.GetUserId(), ev.GetPreviousUserId()} {
+		if userID != "" {
+			p.onChanged(ctx, userID, ev.GetOrderId())
+		}
+	}
+}
+
+// decodeOrderChanged decodes m; a message that cannot be applied is not ok,
+// and err is set only when it must not be skipped either.
+func decodeOrderChanged(m kafka.Message) (*eventsv1.OrderChanged, uuid.UUID, bool, error) {
+	logger := slog.Default().With("service", "orders-service", "component", "projection")
+	var ev eventsv1.OrderChanged
+	if err := kafkasvc.UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return nil, uuid.Nil, false, err
+		}
+		logger.Error("order changed unmarshal failed", "err", err, "partition", m.Partition, "offset", m.Offset)
+		return nil, uuid.Nil, false, nil
+	}
+	orderID, err := uuid.Parse(ev.GetOrderId())
+	if err != nil {
+		logger.Error("order changed with invalid order id", "event_id", ev.GetEventId(), "order_id", ev.GetOrderId())
+		return nil, uuid.Nil, false, nil
+	}
+	return &ev, orderID, true, nil
+}
@@ -0,0 +1,95 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// ordersReadQuerier records the calls the OrdersRead projection makes.
+type ordersReadQuerier struct {
+	db.Querier
+	calls     []string
+	refreshed []pgtype.UUID
+}
+
+func (q *ordersReadQuerier) TruncateOrdersRead(context.Context) error {
+	q.calls = append(q.calls, "truncate")
+	return nil
+}
+
+func (q *ordersReadQuerier) SeedOrdersRead(context.Context) (int64, error) {
+	q.calls = append(q.calls, "seed")
+	return 3, nil
+}
+
+func (q *ordersReadQuerier) RefreshOrderRead(_ context.Context, orderID pgtype.UUID) error {
+	q.refreshed = append(q.refreshed, orderID)
+	return nil
+}
+
+func changedMessage(t *testing.T, orderID uuid.UUID, userID, previousUserID string) kafka.Message {
+	t.Helper()
+	value, err := kafkasvc.MarshalEvent(&eventsv1.OrderChanged{EventId: uuid.NewString(), OrderId: orderID.String(), UserId: userID, PreviousUserId: previousUserID})
+	if err != nil {
+		t.Fatalf("MarshalEvent() error: %v", err)
+	}
+	return kafka.Message{Value: value}
+}
+
+func TestOrdersReadApply(t *testing.T) {
+	q := &ordersReadQuerier{}
+	p := NewOrdersRead("orders.order_changed.v1", nil)
+	ctx := context.Background()
+
+	orderID := uuid.New()
+	if err := p.Apply(ctx, q, changedMessage(t, orderID, "u-1", "")); err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if err := p.Apply(ctx, q, kafka.Message{Value: []byte("not an event")}); err != nil {
+		t.Fatalf("Apply() of a bad message = %v, want it skipped", err)
+	}
+	payload, err := anypb.New(&eventsv1.OrderChanged{EventId: uuid.NewString(), OrderId: uuid.NewString()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer, _ := proto.Marshal(&eventsv1.EventEnvelope{Type: "events.v1.OrderChanged", Version: events.Version + 1, Payload: payload})
+	if err := p.Apply(ctx, q, kafka.Message{Value: newer}); !errors.Is(err, events.ErrNewerVersion) {
+		t.Fatalf("Apply() of a newer version = %v, want ErrNewerVersion so it is not committed", err)
+	}
+	if len(q.refreshed) != 1 || q.refreshed[0].Bytes != orderID {
+		t.Fatalf("refreshed = %v, want only order %s", q.refreshed, orderID)
+	}
+
+	if err := p.Reset(ctx, q); err != nil {
+		t.Fatalf("Reset() error: %v", err)
+	}
+	if !slices.Equal(q.calls, []string{"truncate", "seed"}) {
+		t.Fatalf("Reset() calls = %v, want truncate then seed", q.calls)
+	}
+}
+
+func TestOrdersReadCommitted(t *testing.T) {
+	var users []string
+	p := NewOrdersRead("orders.order_changed.v1", func(_ context.Context, userID, _ string) {
+		users = append(users, userID)
+	})
+	p.Committed(context.Background(), changedMessage(t, uuid.New(), "u-2", "u-1"))
+	p.Committed(context.Background(), changedMessage(t, uuid.New(), "u-3", ""))
+	if !slices.Equal(users, []string{"u-2", "u-1", "u-3"}) {
+		t.Fatalf("onChanged users = %v, want both owners of a transferred order", users)
+	}
+}
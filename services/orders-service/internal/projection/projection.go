@@ -0,0 +1,45 @@
+// Package projection keeps read models built from Kafka topics: tables that
+// can be thrown away and rebuilt from the events, e.g. after a bug in the
+// code that maintains them. A Consumer applies new events as they arrive; a
+// Replayer rebuilds a projection from the earliest retained offset.
+package projection
+
+import (
+	"context"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Projection is a read model of the events of one topic.
+type Projection interface {
+	// Name identifies the projection in replays, e.g. order_status_history.
+	Name() string
+	// Topic is the topic the projection is built from.
+	Topic() string
+	// Reset empties the projection before a replay. A projection that can
+	// be computed from other tables may fill it again right away.
+	Reset(ctx context.Context, q db.Querier) error
+	// Apply folds one message into the projection in the transaction of q.
+	// It must be idempotent: during a replay the consumer keeps applying
+	// new messages, and the replay may apply them again. A message that
+	// cannot be decoded is skipped with nil, as retrying cannot fix it.
+	Apply(ctx context.Context, q db.Querier, m kafka.Message) error
+}
+
+// Notifier is implemented by projections that act on a message once the
+// Consumer's transaction applying it committed, e.g. to drop cached reads.
+type Notifier interface {
+	Committed(ctx context.Context, m kafka.Message)
+}
+
+// Lookup returns the projection named name.
+func Lookup(projections []Projection, name string) (Projection, bool) {
+	for _, p := range projections {
+		if p.Name() == name {
+			return p, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,383 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Statuses of projection_replays.
+const (
+	ReplayRunning   = "RUNNING"
+	ReplayStopped   = "STOPPED"
+	ReplayCompleted = "COMPLETED"
+	ReplayFailed    = "FAILED"
+)
+
+// replayMetrics counts per projection the messages applied by replays
+// ("<projection>.applied") and the replays that failed ("<projection>.failed").
+var replayMetrics = expvar.NewMap("projection_replay")
+
+// errReplayLost rolls back a batch whose replay was stopped or taken over
+// by another replica.
+var errReplayLost = errors.New("replay stopped or taken over")
+
+type ReplayOptions struct {
+	// PollInterval is how often the replica looks for a replay to run.
+	PollInterval time.Duration
+	// BatchSize is the messages applied per transaction; default 500.
+	BatchSize int
+	// StaleAfter is how long a replay may go without progress before
+	// another replica takes it over; default 1m.
+	StaleAfter time.Duration
+	// IdleTimeout is how long a read waits for a message before the
+	// partition's high watermark is checked against its end offset; default
+	// 10s. The reader returns transaction markers and aborted messages too,
+	// so a partition only ends at its end offset.
+	IdleTimeout time.Duration
+	// IdleRetries is how many reads in a row may time out while the high
+	// watermark still covers the end offset before the replay fails;
+	// default 3.
+	IdleRetries int
+}
+
+// Replayer runs the replays started with StartProjectionReplay. A replay is
+// taken by one replica, which empties the projection, stores the offset
+// range of every partition of the topic, then reads the partitions from the
+// earliest offset to the end, applying each batch in a transaction with the
+// partition's new position. A replay interrupted by a restart continues
+// from there on whichever replica takes it over.
+//
+// The projection is emptied before the end offsets are read, so every
+// message the consumer applied before that is below the end and applied
+// again by the replay; the consumer keeps applying the messages after it.
+type Replayer struct {
+	repo        repo.OrdersRepository
+	log         Log
+	projections []Projection
+	opts        ReplayOptions
+	owner       pgtype.UUID
+
+	now    func() time.Time
+	logger *slog.Logger
+}
+
+func NewReplayer(repo repo.OrdersRepository, log Log, projections []Projection, opts ReplayOptions) *Replayer {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 500
+	}
+	if opts.StaleAfter <= 0 {
+		opts.StaleAfter = time.Minute
+	}
+	if opts.IdleTimeout <= 0 {
+		opts.IdleTimeout = 10 * time.Second
+	}
+	if opts.IdleRetries <= 0 {
+		opts.IdleRetries = 3
+	}
+	logger := slog.Default().With("service", "orders-service", "component", "projection")
+	logger.Info("projection replayer initialized", "poll_interval", opts.PollInterval.String(), "batch_size", opts.BatchSize, "stale_after", opts.StaleAfter.String())
+	return &Replayer{
+		repo:        repo,
+		log:         log,
+		projections: projections,
+		opts:        opts,
+		owner:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		now:         time.Now,
+		logger:      logger,
+	}
+}
+
+func (r *Replayer) Run(ctx context.Context) error {
+	r.logger.Info("projection replayer run start")
+	ticker := time.NewTicker(r.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			r.logger.Info("projection replayer stopped")
+			return nil
+		case <-ticker.C:
+			if _, err := r.RunOnce(ctx); err != nil && ctx.Err() == nil {
+				r.logger.Error("projection replay failed", "err", err)
+			}
+		}
+	}
+}
+
+// RunOnce takes a replay no replica is running, if there is one, and runs
+// it until it completes, fails, is stopped or ctx is done. It returns the
+// id of the replay, 0 when there was none.
+func (r *Replayer) RunOnce(ctx context.Context) (int64, error) {
+	var rp db.ProjectionReplay
+	err := r.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		rp, err = q.ClaimProjectionReplay(ctx, db.ClaimProjectionReplayParams{Owner: r.owner, StaleMs: r.opts.StaleAfter.Milliseconds()})
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	logger := r.logger.With("replay_id", rp.ID, "projection", rp.Projection)
+	logger.Info("projection replay taken", "topic", rp.Topic, "applied", rp.Applied)
+
+	p, ok := Lookup(r.projections, rp.Projection)
+	if !ok || p.Topic() != rp.Topic {
+		// Started by a replica that knew the projection differently.
+		return rp.ID, r.finish(ctx, rp.ID, ReplayFailed, fmt.Errorf("projection %s of topic %s is not known to this replica", rp.Projection, rp.Topic))
+	}
+	err = r.replay(ctx, rp, p, logger)
+	switch {
+	case errors.Is(err, errReplayLost):
+		logger.Info("projection replay stopped or taken over")
+		return rp.ID, nil
+	case ctx.Err() != nil:
+		// Left RUNNING: another replica continues after StaleAfter.
+		logger.Info("projection replay interrupted")
+		return rp.ID, nil
+	case err != nil:
+		replayMetrics.Add(rp.Projection+".failed", 1)
+		if ferr := r.finish(ctx, rp.ID, ReplayFailed, err); ferr != nil {
+			logger.Error("failed to mark projection replay failed", "err", ferr)
+		}
+		return rp.ID, err
+	}
+	if err := r.finish(ctx, rp.ID, ReplayCompleted, nil); err != nil {
+		return rp.ID, err
+	}
+	logger.Info("projection replay completed")
+	return rp.ID, nil
+}
+
+func (r *Replayer) finish(ctx context.Context, id int64, status string, cause error) error {
+	var msg string
+	if cause != nil {
+		msg = cause.Error()
+	}
+	err := r.repo.InTx(ctx, func(q db.Querier) error {
+		_, err := q.FinishProjectionReplay(ctx, db.FinishProjectionReplayParams{Status: status, Error: msg, ID: id})
+		return err
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Stopped in the meantime.
+		return nil
+	}
+	if cause != nil && err == nil {
+		return cause
+	}
+	return err
+}
+
+func (r *Replayer) replay(ctx context.Context, rp db.ProjectionReplay, p Projection, logger *slog.Logger) error {
+	var offsets []db.ProjectionReplayOffset
+	err := r.repo.Read(ctx, func(q db.Querier) error {
+		var err error
+		offsets, err = q.ListProjectionReplayOffsets(ctx, rp.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(offsets) == 0 {
+		if offsets, err = r.begin(ctx, rp, p); err != nil {
+			return err
+		}
+		logger.Info("projection reset", "partitions", len(offsets))
+	}
+
+	pace := newPacer(rp.RateLimit, r.now)
+	for _, o := range offsets {
+		if o.NextOffset >= o.EndOffset {
+			continue
+		}
+		if err := r.replayPartition(ctx, rp, p, o, pace); err != nil {
+			return fmt.Errorf("partition %d: %w", o.Partition, err)
+		}
+		logger.Info("projection replay partition done", "partition", o.Partition, "end_offset", o.EndOffset)
+	}
+	return nil
+}
+
+// begin empties the projection, then stores the offset ranges of the
+// topic's partitions.
+func (r *Replayer) begin(ctx context.Context, rp db.ProjectionReplay, p Projection) ([]db.ProjectionReplayOffset, error) {
+	err := r.repo.InTx(ctx, func(q db.Querier) error {
+		if err := r.heartbeat(ctx, q, rp.ID, 0); err != nil {
+			return err
+		}
+		return p.Reset(ctx, q)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ranges, err := r.log.Partitions(ctx, rp.Topic)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Partition < ranges[j].Partition })
+	offsets := make([]db.ProjectionReplayOffset, 0, len(ranges))
+	err = r.repo.InTx(ctx, func(q db.Querier) error {
+		if err := r.heartbeat(ctx, q, rp.ID, 0); err != nil {
+			return err
+		}
+		for _, pr := range ranges {
+			o := db.InsertProjectionReplayOffsetParams{ReplayID: rp.ID, Partition: int32(pr.Partition), StartOffset: pr.First, EndOffset: pr.End}
+			if err := q.InsertProjectionReplayOffset(ctx, o); err != nil {
+				return err
+			}
+			offsets = append(offsets, db.ProjectionReplayOffset{ReplayID: rp.ID, Partition: o.Partition, StartOffset: pr.First, NextOffset: pr.First, EndOffset: pr.End})
+		}
+		return nil
+	})
+	return offsets, err
+}
+
+func (r *Replayer) replayPartition(ctx context.Context, rp db.ProjectionReplay, p Projection, o db.ProjectionReplayOffset, pace *pacer) error {
+	reader, err := r.log.Open(rp.Topic, int(o.Partition), o.NextOffset)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	next := o.NextOffset
+	idle := 0
+	for next < o.EndOffset {
+		size := pace.batch(r.opts.BatchSize)
+		batch := make([]kafka.Message, 0, size)
+		for len(batch) < size && next < o.EndOffset {
+			rctx, cancel := context.WithTimeout(ctx, r.opts.IdleTimeout)
+			m, err := reader.ReadMessage(rctx)
+			cancel()
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				idle++
+				if err := r.checkIdle(ctx, reader, next, o.EndOffset, idle); err != nil {
+					return err
+				}
+				// Applies what was read, so the replay heartbeats while
+				// it waits.
+				break
+			}
+			if err != nil {
+				return err
+			}
+			idle = 0
+			if m.Offset >= o.EndOffset {
+				next = o.EndOffset
+				break
+			}
+			batch = append(batch, m)
+			next = m.Offset + 1
+		}
+
+		err := r.repo.InTx(ctx, func(q db.Querier) error {
+			for _, m := range batch {
+				if err := p.Apply(ctx, q, m); err != nil {
+					return fmt.Errorf("offset %d: %w", m.Offset, err)
+				}
+			}
+			if err := q.SetProjectionReplayOffset(ctx, db.SetProjectionReplayOffsetParams{NextOffset: next, ReplayID: rp.ID, Partition: o.Partition}); err != nil {
+				return err
+			}
+			return r.heartbeat(ctx, q, rp.ID, int64(len(batch)))
+		})
+		if err != nil {
+			return err
+		}
+		replayMetrics.Add(rp.Projection+".applied", int64(len(batch)))
+		if err := pace.wait(ctx, len(batch)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkIdle is called after the idle-th read in a row at offset next timed
+// out. It fails when the partition's high watermark is below end, so the
+// messages up to end are gone, or when the reads timed out IdleRetries
+// times; otherwise the messages are just slow to come.
+func (r *Replayer) checkIdle(ctx context.Context, reader MessageReader, next, end int64, idle int) error {
+	lctx, cancel := context.WithTimeout(ctx, r.opts.IdleTimeout)
+	lag, err := reader.ReadLag(lctx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("no message at offset %d for %s, read lag: %w", next, r.opts.IdleTimeout, err)
+	}
+	if next+lag < end {
+		return fmt.Errorf("high watermark %d is below the end offset %d", next+lag, end)
+	}
+	if idle >= r.opts.IdleRetries {
+		return fmt.Errorf("no message at offset %d, %d before the end offset, for %d reads of %s", next, end-next, idle, r.opts.IdleTimeout)
+	}
+	r.logger.Warn("projection replay waiting for messages", "offset", next, "end_offset", end, "lag", lag, "idle_reads", idle)
+	return nil
+}
+
+// heartbeat adds applied to the replay's count and fails with errReplayLost
+// when this replica no longer runs it.
+func (r *Replayer) heartbeat(ctx context.Context, q db.Querier, id, applied int64) error {
+	n, err := q.AdvanceProjectionReplay(ctx, db.AdvanceProjectionReplayParams{Applied: applied, ID: id, Owner: r.owner})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errReplayLost
+	}
+	return nil
+}
+
+// pacer holds a replay to its rate limit in messages per second.
+type pacer struct {
+	rate    int
+	start   time.Time
+	applied int
+	now     func() time.Time
+}
+
+func newPacer(rate int32, now func() time.Time) *pacer {
+	return &pacer{rate: int(rate), start: now(), now: now}
+}
+
+// batch caps size to a second's worth of messages, so that a limited replay
+// still heartbeats about once a second.
+func (p *pacer) batch(size int) int {
+	if p.rate > 0 && p.rate < size {
+		return p.rate
+	}
+	return size
+}
+
+// wait counts n more messages and sleeps until they are within the rate.
+func (p *pacer) wait(ctx context.Context, n int) error {
+	p.applied += n
+	if p.rate <= 0 {
+		return nil
+	}
+	due := p.start.Add(time.Duration(p.applied) * time.Second / time.Duration(p.rate))
+	d := due.Sub(p.now())
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,321 @@
+package projection
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+
+	kafkasvc "github.com/ilyaytrewq/payments-service/order-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// fakeRepo keeps one replay, its offsets and the order_status_history rows;
+// a failed transaction puts all of them back but the replay's status, which
+// stopAfter changes as if StopProjectionReplay did.
+type fakeRepo struct {
+	db.Querier
+	replay  db.ProjectionReplay
+	offsets []db.ProjectionReplayOffset
+	history map[uuid.UUID]db.InsertOrderStatusHistoryParams
+	resets  int
+	// stopAfter stops the replay in the transaction that has applied that
+	// many messages.
+	stopAfter int64
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error { return fn(f) }
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	replay, offsets, history := f.replay, slices.Clone(f.offsets), maps.Clone(f.history)
+	if err := fn(f); err != nil {
+		replay.Status = f.replay.Status
+		f.replay, f.offsets, f.history = replay, offsets, history
+		return err
+	}
+	return nil
+}
+
+func (f *fakeRepo) ClaimProjectionReplay(_ context.Context, arg db.ClaimProjectionReplayParams) (db.ProjectionReplay, error) {
+	if f.replay.Status != ReplayRunning || f.replay.HeartbeatAt.Valid {
+		return db.ProjectionReplay{}, pgx.ErrNoRows
+	}
+	f.replay.Owner = arg.Owner
+	f.replay.HeartbeatAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return f.replay, nil
+}
+
+func (f *fakeRepo) ListProjectionReplayOffsets(_ context.Context, id int64) ([]db.ProjectionReplayOffset, error) {
+	return slices.Clone(f.offsets), nil
+}
+
+func (f *fakeRepo) InsertProjectionReplayOffset(_ context.Context, arg db.InsertProjectionReplayOffsetParams) error {
+	f.offsets = append(f.offsets, db.ProjectionReplayOffset{ReplayID: arg.ReplayID, Partition: arg.Partition, StartOffset: arg.StartOffset, NextOffset: arg.StartOffset, EndOffset: arg.EndOffset})
+	return nil
+}
+
+func (f *fakeRepo) SetProjectionReplayOffset(_ context.Context, arg db.SetProjectionReplayOffsetParams) error {
+	for i := range f.offsets {
+		if f.offsets[i].Partition == arg.Partition {
+			f.offsets[i].NextOffset = arg.NextOffset
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) AdvanceProjectionReplay(_ context.Context, arg db.AdvanceProjectionReplayParams) (int64, error) {
+	if f.replay.Status != ReplayRunning || f.replay.Owner != arg.Owner {
+		return 0, nil
+	}
+	if f.stopAfter > 0 && f.replay.Applied+arg.Applied >= f.stopAfter {
+		f.replay.Status = ReplayStopped
+		return 0, nil
+	}
+	f.replay.Applied += arg.Applied
+	return 1, nil
+}
+
+func (f *fakeRepo) FinishProjectionReplay(_ context.Context, arg db.FinishProjectionReplayParams) (db.ProjectionReplay, error) {
+	if f.replay.Status != ReplayRunning {
+		return db.ProjectionReplay{}, pgx.ErrNoRows
+	}
+	f.replay.Status, f.replay.Error = arg.Status, arg.Error
+	return f.replay, nil
+}
+
+func (f *fakeRepo) TruncateOrderStatusHistory(context.Context) error {
+	f.resets++
+	clear(f.history)
+	return nil
+}
+
+func (f *fakeRepo) InsertOrderStatusHistory(_ context.Context, arg db.InsertOrderStatusHistoryParams) error {
+	if _, ok := f.history[arg.EventID.Bytes]; !ok {
+		f.history[arg.EventID.Bytes] = arg
+	}
+	return nil
+}
+
+// fakeLog holds the messages of each partition. The high watermark of a
+// partition is the offset after its last message unless set in hwms.
+type fakeLog struct {
+	partitions map[int][]kafka.Message
+	ends       map[int]int64
+	hwms       map[int]int64
+	opened     []int64
+}
+
+func (l *fakeLog) Partitions(context.Context, string) ([]PartitionRange, error) {
+	var out []PartitionRange
+	for p, msgs := range l.partitions {
+		out = append(out, PartitionRange{Partition: p, First: msgs[0].Offset, End: l.ends[p]})
+	}
+	return out, nil
+}
+
+func (l *fakeLog) Open(_ string, partition int, offset int64) (MessageReader, error) {
+	l.opened = append(l.opened, offset)
+	all := l.partitions[partition]
+	hwm, ok := l.hwms[partition]
+	if !ok {
+		hwm = all[len(all)-1].Offset + 1
+	}
+	var msgs []kafka.Message
+	for _, m := range all {
+		if m.Offset >= offset {
+			msgs = append(msgs, m)
+		}
+	}
+	return &sliceReader{msgs: msgs, next: offset, hwm: hwm}, nil
+}
+
+// sliceReader blocks like an idle partition once its messages are read.
+type sliceReader struct {
+	msgs      []kafka.Message
+	next, hwm int64
+}
+
+func (r *sliceReader) ReadMessage(ctx context.Context) (kafka.Message, error) {
+	if len(r.msgs) == 0 {
+		<-ctx.Done()
+		return kafka.Message{}, ctx.Err()
+	}
+	m := r.msgs[0]
+	r.msgs = r.msgs[1:]
+	r.next = m.Offset + 1
+	return m, nil
+}
+
+func (r *sliceReader) ReadLag(context.Context) (int64, error) { return r.hwm - r.next, nil }
+
+func (r *sliceReader) Close() error { return nil }
+
+func statusMessage(t *testing.T, partition int, offset int64, orderID, eventID uuid.UUID, status string) kafka.Message {
+	t.Helper()
+	value, err := kafkasvc.MarshalEvent(&eventsv1.OrderStatusChanged{
+		EventId:        eventID.String(),
+		OccurredAt:     timestamppb.New(time.Unix(1_700_000_000+offset, 0)),
+		OrderId:        orderID.String(),
+		UserId:         "u-1",
+		PreviousStatus: "NEW",
+		Status:         status,
+		Amount:         100,
+	})
+	if err != nil {
+		t.Fatalf("MarshalEvent() error: %v", err)
+	}
+	return kafka.Message{Partition: partition, Offset: offset, Value: value}
+}
+
+func newReplayTest(t *testing.T) (*fakeRepo, *fakeLog, *Replayer) {
+	t.Helper()
+	order, dup := uuid.New(), uuid.New()
+	log := &fakeLog{
+		partitions: map[int][]kafka.Message{
+			0: {
+				statusMessage(t, 0, 10, order, uuid.New(), "PARTIALLY_PAID"),
+				{Partition: 0, Offset: 11, Value: []byte("not an event")},
+				statusMessage(t, 0, 12, order, dup, "FINISHED"),
+				statusMessage(t, 0, 13, order, dup, "FINISHED"),
+				// After the end offsets: left to the consumer.
+				statusMessage(t, 0, 20, order, uuid.New(), "DISPUTED"),
+			},
+			1: {
+				statusMessage(t, 1, 0, uuid.New(), uuid.New(), "CANCELLED"),
+				// A transaction marker, returned like a message.
+				{Partition: 1, Offset: 1, Value: []byte{0, 0, 0, 1}},
+			},
+		},
+		ends: map[int]int64{0: 14, 1: 2},
+	}
+	repo := &fakeRepo{
+		replay: db.ProjectionReplay{ID: 7, Projection: OrderStatusHistoryName, Topic: "orders.order_status_changed.v1", Status: ReplayRunning},
+		history: map[uuid.UUID]db.InsertOrderStatusHistoryParams{
+			// Left by an earlier bug.
+			uuid.New(): {Status: "BOGUS"},
+		},
+	}
+	r := NewReplayer(repo, log, []Projection{NewOrderStatusHistory("orders.order_status_changed.v1")}, ReplayOptions{BatchSize: 2, IdleTimeout: 20 * time.Millisecond})
+	return repo, log, r
+}
+
+func TestReplayRunOnce(t *testing.T) {
+	repo, _, r := newReplayTest(t)
+	ctx := context.Background()
+
+	id, err := r.RunOnce(ctx)
+	if err != nil || id != 7 {
+		t.Fatalf("RunOnce() = (%d, %v), want (7, nil)", id, err)
+	}
+	if repo.replay.Status != ReplayCompleted || repo.replay.Applied != 6 || repo.resets != 1 {
+		t.Fatalf("replay = %s, %d applied, %d resets; want COMPLETED, 6, 1", repo.replay.Status, repo.replay.Applied, repo.resets)
+	}
+	if len(repo.history) != 3 {
+		t.Fatalf("history has %d rows, want the 3 distinct events", len(repo.history))
+	}
+	for _, h := range repo.history {
+		if h.Status == "BOGUS" || h.Status == "DISPUTED" {
+			t.Fatalf("history row %+v, want only the events up to the end offsets", h)
+		}
+	}
+	for _, o := range repo.offsets {
+		if o.NextOffset != o.EndOffset {
+			t.Fatalf("partition %d at %d, want the end %d", o.Partition, o.NextOffset, o.EndOffset)
+		}
+	}
+
+	id, err = r.RunOnce(ctx)
+	if err != nil || id != 0 {
+		t.Fatalf("second RunOnce() = (%d, %v), want nothing to do", id, err)
+	}
+}
+
+func TestReplayStopAndTakeOver(t *testing.T) {
+	repo, log, r := newReplayTest(t)
+	ctx := context.Background()
+
+	repo.stopAfter = 2
+	if _, err := r.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() error: %v", err)
+	}
+	if repo.replay.Status != ReplayStopped || repo.replay.Applied != 0 || repo.offsets[0].NextOffset != 10 {
+		t.Fatalf("stopped replay = %s, %d applied, partition 0 at %d; want the batch rolled back", repo.replay.Status, repo.replay.Applied, repo.offsets[0].NextOffset)
+	}
+
+	// A replay taken over after a restart continues from the stored offsets
+	// without emptying the projection again.
+	repo.replay.Status, repo.replay.HeartbeatAt, repo.stopAfter = ReplayRunning, pgtype.Timestamptz{}, 0
+	repo.offsets[0].NextOffset = 12
+	log.opened = nil
+	other := NewReplayer(repo, log, r.projections, r.opts)
+	if _, err := other.RunOnce(ctx); err != nil {
+		t.Fatalf("RunOnce() after take over error: %v", err)
+	}
+	if repo.replay.Status != ReplayCompleted || repo.resets != 1 || !slices.Equal(log.opened, []int64{12, 0}) {
+		t.Fatalf("taken over replay = %s, %d resets, opened at %v; want COMPLETED from offsets 12 and 0", repo.replay.Status, repo.resets, log.opened)
+	}
+}
+
+func TestReplayIdlePartition(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		hwm  int64
+		want string
+	}{
+		// Offset 2 is in the log but never comes.
+		{name: "stalled", hwm: 3, want: "for 3 reads"},
+		// The log lost the offsets up to the end.
+		{name: "truncated", hwm: 1, want: "below the end offset"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			repo, log, r := newReplayTest(t)
+			log.ends[1], log.hwms = 3, map[int]int64{1: tc.hwm}
+			if _, err := r.RunOnce(context.Background()); err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("RunOnce() error = %v, want %q", err, tc.want)
+			}
+			if repo.replay.Status != ReplayFailed {
+				t.Fatalf("replay = %s, want FAILED", repo.replay.Status)
+			}
+		})
+	}
+}
+
+func TestReplayUnknownProjection(t *testing.T) {
+	repo, _, r := newReplayTest(t)
+	repo.replay.Projection = "missing"
+	if _, err := r.RunOnce(context.Background()); err == nil {
+		t.Fatal("RunOnce() error = nil, want the unknown projection")
+	}
+	if repo.replay.Status != ReplayFailed || repo.replay.Error == "" || repo.resets != 0 {
+		t.Fatalf("replay = %s %q, %d resets; want FAILED without a reset", repo.replay.Status, repo.replay.Error, repo.resets)
+	}
+}
+
+func TestPacer(t *testing.T) {
+	now := time.Unix(0, 0)
+	p := newPacer(100, func() time.Time { return now })
+	if p.batch(500) != 100 || newPacer(0, time.Now).batch(500) != 500 {
+		t.Fatal("batch() does not cap to the rate")
+	}
+	// 100 messages at once are due after a second.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.wait(ctx, 100); !errors.Is(err, context.Canceled) {
+		t.Fatalf("wait() = %v, want to sleep until canceled", err)
+	}
+	now = now.Add(time.Second)
+	if err := p.wait(context.Background(), 0); err != nil {
+		t.Fatalf("wait() when due = %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+// Package promo validates promo codes and records their redemptions.
+//
+// A code is redeemed in the transaction that creates its order: the code
+// row is locked, its bounds and limits are checked, and the redemption is
+// written next to the order, so concurrent orders cannot exceed the limits.
+// Cancelling the order gives the redemption back; retrying its payment takes
+// it again, within the limits of the time.
+package promo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// Reasons a code is not accepted.
+var (
+	ErrNotFound     = errors.New("unknown promo code")
+	ErrInactive     = errors.New("promo code is not active")
+	ErrExhausted    = errors.New("promo code is used up")
+	ErrAlreadyUsed  = errors.New("promo code was already used by the user")
+	ErrInvalidRules = errors.New("invalid promo code")
+)
+
+// MaxCodeLength bounds the length of a code.
+const MaxCodeLength = 64
+
+// Normalize trims a code and upper-cases it; codes are matched
+// case-insensitively and stored normalized.
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Validate checks the rules of a new code: a normalized code of at most
+// MaxCodeLength characters, exactly one of a percent (1-99) or a positive
+// fixed discount, positive limits and expiry after start.
+func Validate(p db.CreatePromoCodeParams) error {
+	switch {
+	case p.Code == "" || p.Code != Normalize(p.Code) || len(p.Code) > MaxCodeLength:
+		return fmt.Errorf("%w: code must be 1-%d characters without surrounding spaces", ErrInvalidRules, MaxCodeLength)
+	case p.PercentOff.Valid == p.AmountOff.Valid:
+		return fmt.Errorf("%w: exactly one of percent_off and amount_off is required", ErrInvalidRules)
+	case p.PercentOff.Valid && (p.PercentOff.Int32 < 1 || p.PercentOff.Int32 > 99):
+		return fmt.Errorf("%w: percent_off must be 1-99", ErrInvalidRules)
+	case p.AmountOff.Valid && p.AmountOff.Int64 <= 0:
+		return fmt.Errorf("%w: amount_off must be > 0", ErrInvalidRules)
+	case p.MaxRedemptions.Valid && p.MaxRedemptions.Int32 <= 0,
+		p.MaxRedemptionsPerUser.Valid && p.MaxRedemptionsPerUser.Int32 <= 0:
+		return fmt.Errorf("%w: limits must be > 0", ErrInvalidRules)
+	case p.StartsAt.Valid && p.ExpiresAt.Valid && !p.ExpiresAt.Time.After(p.StartsAt.Time):
+		return fmt.Errorf("%w: expires_at must be after starts_at", ErrInvalidRules)
+	}
+	return nil
+}
+
+// Discount is what c takes off amount, rounded down. It leaves at least one
+// minimal unit to pay: an order without an amount has no payment to
+// request.
+func Discount(c db.PromoCode, amount int64) int64 {
+	var d int64
+	switch {
+	case c.PercentOff.Valid:
+		d = amount * int64(c.PercentOff.Int32) / 100
+	case c.AmountOff.Valid:
+		d = c.AmountOff.Int64
+	}
+	return max(min(d, amount-1), 0)
+}
+
+// Check reports whether c may be redeemed at now by a user who holds
+// userRedemptions unreleased redemptions of it.
+func Check(c db.PromoCode, now time.Time, userRedemptions int64) error {
+	if (c.StartsAt.Valid && now.Before(c.StartsAt.Time)) || (c.ExpiresAt.Valid && !now.Before(c.ExpiresAt.Time)) {
+		return ErrInactive
+	}
+	if c.MaxRedemptions.Valid && c.RedeemedCount >= c.MaxRedemptions.Int32 {
+		return ErrExhausted
+	}
+	if c.MaxRedemptionsPerUser.Valid && userRedemptions >= int64(c.MaxRedemptionsPerUser.Int32) {
+		return ErrAlreadyUsed
+	}
+	return nil
+}
+
+// Quote returns the discount code would give userID on amount, without
+// redeeming it, e.g. for ValidateOrder.
+func Quote(ctx context.Context, q db.Querier, code, userID string, amount int64) (int64, error) {
+	return discount(ctx, q, q.GetPromoCode, code, userID, amount)
+}
+
+// Reserve locks code in the transaction of q and returns the discount it
+// gives userID on amount. The caller creates the order and then calls
+// Redeem in the same transaction.
+func Reserve(ctx context.Context, q db.Querier, code, userID string, amount int64) (int64, error) {
+	return discount(ctx, q, q.GetPromoCodeForUpdate, code, userID, amount)
+}
+
+// Redeem records that order orderID of userID was created with code and
+// discount off amount, and counts it against the limits of the code.
+func Redeem(ctx context.Context, q db.Querier, code string, orderID pgtype.UUID, userID string, amount, discount int64) error {
+	if err := q.InsertPromoRedemption(ctx, db.InsertPromoRedemptionParams{
+		Code:     code,
+		OrderID:  orderID,
+		UserID:   userID,
+		Amount:   amount,
+		Discount: discount,
+	}); err != nil {
+		return fmt.Errorf("insert promo redemption: %w", err)
+	}
+	return nil
+}
+
+// Release gives back the redemption of order orderID, if it has one that is
+// not released yet.
+func Release(ctx context.Context, q db.Querier, orderID pgtype.UUID) error {
+	if _, err := q.ReleasePromoRedemption(ctx, orderID); err != nil {
+		return fmt.Errorf("release promo redemption: %w", err)
+	}
+	return nil
+}
+
+// Reclaim takes back the redemption order orderID gave back when it was
+// cancelled, before its payment is retried at the discounted amount. The
+// code is locked and checked as on Redeem, so a code that was used up or
+// expired meanwhile fails the retry. An order without a released redemption
+// has nothing to reclaim. code is the reclaimed code, also on failure.
+func Reclaim(ctx context.Context, q db.Querier, orderID pgtype.UUID) (code string, err error) {
+	r, err := q.GetReleasedPromoRedemption(ctx, orderID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("load released promo redemption: %w", err)
+	}
+	c, err := q.GetPromoCodeForUpdate(ctx, r.Code)
+	if err != nil {
+		return r.Code, fmt.Errorf("load promo code: %w", err)
+	}
+	if err := check(ctx, q, c, r.UserID); err != nil {
+		return r.Code, err
+	}
+	if _, err := q.ReclaimPromoRedemption(ctx, orderID); err != nil {
+		return r.Code, fmt.Errorf("reclaim promo redemption: %w", err)
+	}
+	return r.Code, nil
+}
+
+func discount(ctx context.Context, q db.Querier, get func(context.Context, string) (db.PromoCode, error), code, userID string, amount int64) (int64, error) {
+	c, err := get(ctx, Normalize(code))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("load promo code: %w", err)
+	}
+	if err := check(ctx, q, c, userID); err != nil {
+		return 0, err
+	}
+	return Discount(c, amount), nil
+}
+
+// check is Check with the unreleased redemptions of userID counted in q.
+func check(ctx context.Context, q db.Querier, c db.PromoCode, userID string) error {
+	var used int64
+	if c.MaxRedemptionsPerUser.Valid {
+		var err error
+		if used, err = q.CountUserPromoRedemptions(ctx, db.CountUserPromoRedemptionsParams{Code: c.Code, UserID: userID}); err != nil {
+			return fmt.Errorf("count promo redemptions: %w", err)
+		}
+	}
+	return Check(c, time.Now(), used)
+}
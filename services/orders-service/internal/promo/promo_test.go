@@ -0,0 +1,79 @@
+package promo
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+func TestDiscount(t *testing.T) {
+	percent := db.PromoCode{PercentOff: pgtype.Int4{Int32: 15, Valid: true}}
+	fixed := db.PromoCode{AmountOff: pgtype.Int8{Int64: 500, Valid: true}}
+	for _, tc := range []struct {
+		code   db.PromoCode
+		amount int64
+		want   int64
+	}{
+		{percent, 1000, 150},
+		{percent, 999, 149},
+		{percent, 1, 0},
+		{fixed, 1000, 500},
+		{fixed, 500, 499},
+		{fixed, 1, 0},
+	} {
+		if got := Discount(tc.code, tc.amount); got != tc.want {
+			t.Errorf("Discount(%+v, %d) = %d, want %d", tc.code, tc.amount, got, tc.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: now.Add(d), Valid: true} }
+	limit := func(n int32) pgtype.Int4 { return pgtype.Int4{Int32: n, Valid: true} }
+	for _, tc := range []struct {
+		name string
+		code db.PromoCode
+		used int64
+		want error
+	}{
+		{"unbounded", db.PromoCode{RedeemedCount: 1000}, 5, nil},
+		{"in window", db.PromoCode{StartsAt: at(-time.Hour), ExpiresAt: at(time.Hour)}, 0, nil},
+		{"not started", db.PromoCode{StartsAt: at(time.Second)}, 0, ErrInactive},
+		{"expired", db.PromoCode{ExpiresAt: at(0)}, 0, ErrInactive},
+		{"used up", db.PromoCode{MaxRedemptions: limit(3), RedeemedCount: 3}, 0, ErrExhausted},
+		{"used by the user", db.PromoCode{MaxRedemptionsPerUser: limit(2)}, 2, ErrAlreadyUsed},
+	} {
+		if err := Check(tc.code, now, tc.used); !errors.Is(err, tc.want) {
+			t.Errorf("%s: Check() = %v, want %v", tc.name, err, tc.want)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	ok := db.CreatePromoCodeParams{Code: "SPRING", PercentOff: pgtype.Int4{Int32: 10, Valid: true}}
+	if err := Validate(ok); err != nil {
+		t.Fatalf("Validate(%+v) error: %v", ok, err)
+	}
+	for name, mutate := range map[string]func(p *db.CreatePromoCodeParams){
+		"lower case":    func(p *db.CreatePromoCodeParams) { p.Code = "spring" },
+		"no discount":   func(p *db.CreatePromoCodeParams) { p.PercentOff.Valid = false },
+		"two discounts": func(p *db.CreatePromoCodeParams) { p.AmountOff = pgtype.Int8{Int64: 100, Valid: true} },
+		"free":          func(p *db.CreatePromoCodeParams) { p.PercentOff.Int32 = 100 },
+		"zero limit":    func(p *db.CreatePromoCodeParams) { p.MaxRedemptions = pgtype.Int4{Valid: true} },
+		"expires first": func(p *db.CreatePromoCodeParams) {
+			p.StartsAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+			p.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+		},
+	} {
+		p := ok
+		mutate(&p)
+		if err := Validate(p); !errors.Is(err, ErrInvalidRules) {
+			t.Errorf("%s: Validate() = %v, want ErrInvalidRules", name, err)
+		}
+	}
+}
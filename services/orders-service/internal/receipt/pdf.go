@@ -0,0 +1,89 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// PDF renders a receipt as a one-page A4 PDF in the standard Helvetica
+// font, so nothing has to be embedded. Helvetica only covers Latin-1:
+// other characters, Cyrillic included, come out as '?'. Deployments whose
+// order descriptions need them plug in a Renderer with an embedded font.
+type PDF struct{}
+
+func (PDF) ContentType() string {
+	return "application/pdf"
+}
+
+func (PDF) Render(r Receipt) ([]byte, error) {
+	amount := func(v int64) string {
+		return money.New(v, r.Currency).String()
+	}
+	lines := []string{
+		"Order: " + r.OrderID,
+		"Customer: " + r.UserID,
+		"Ordered: " + r.OrderCreatedAt.UTC().Format(time.RFC3339),
+		"Paid: " + r.PaidAt.UTC().Format(time.RFC3339),
+		"Issued: " + r.IssuedAt.UTC().Format(time.RFC3339),
+		"",
+	}
+	for _, it := range r.Items {
+		lines = append(lines, fmt.Sprintf("%s: %d x %s = %s", it.Description, it.Quantity, amount(it.UnitPrice), amount(it.Amount)))
+	}
+	lines = append(lines, "", "Total: "+amount(r.TotalAmount))
+
+	var content bytes.Buffer
+	fmt.Fprintf(&content, "BT\n/F1 16 Tf\n56 780 Td\n(%s) Tj\n/F1 11 Tf\n16 TL\nT*\nT*\n", pdfText("Receipt "+r.Number))
+	for _, l := range lines {
+		fmt.Fprintf(&content, "(%s) Tj\nT*\n", pdfText(l))
+	}
+	content.WriteString("ET")
+	return pdfDocument(content.Bytes()), nil
+}
+
+// pdfDocument wraps a content stream into a single-page document.
+func pdfDocument(content []byte) []byte {
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 595 842] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+// pdfText escapes s for a PDF string literal in WinAnsiEncoding, which
+// matches Latin-1 outside 0x80-0x9F.
+func pdfText(s string) []byte {
+	out := make([]byte, 0, len(s))
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			out = append(out, '\\', byte(r))
+		case r < 0x20:
+			out = append(out, ' ')
+		case r < 0x80 || (r >= 0xA0 && r <= 0xFF):
+			out = append(out, byte(r))
+		default:
+			out = append(out, '?')
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package receipt
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestPDF(t *testing.T) {
+	at := time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)
+	doc, err := PDF{}.Render(Receipt{
+		Number:         "R-20260115-000042",
+		OrderID:        "o-1",
+		UserID:         "u-1",
+		Currency:       money.RUB,
+		Items:          Items("Книга (paper)", 150050, 100, nil, db.GetPromoRedemptionRow{}),
+		Amount:         150050,
+		FeeAmount:      100,
+		TotalAmount:    150150,
+		OrderCreatedAt: at,
+		PaidAt:         at,
+		IssuedAt:       at,
+	})
+	if err != nil {
+		t.Fatalf("Render() error: %v", err)
+	}
+	for _, want := range []string{"%PDF-1.4", "(Receipt R-20260115-000042) Tj", `(????? \(paper\): 1 x 1500.50 RUB = 1500.50 RUB) Tj`, "(Payment fee: 1 x 1.00 RUB = 1.00 RUB) Tj", "(Total: 1501.50 RUB) Tj"} {
+		if !bytes.Contains(doc, []byte(want)) {
+			t.Errorf("document lacks %q", want)
+		}
+	}
+
+	// startxref must point at the cross-reference table.
+	s := string(doc)
+	i := strings.LastIndex(s, "startxref\n")
+	off, err := strconv.Atoi(strings.SplitN(s[i+len("startxref\n"):], "\n", 2)[0])
+	if err != nil || !strings.HasPrefix(s[off:], "xref\n") {
+		t.Fatalf("startxref = %d (%v), want the offset of xref", off, err)
+	}
+}
+
+func TestItems(t *testing.T) {
+	if items := Items("book", 500, 0, nil, db.GetPromoRedemptionRow{}); len(items) != 1 || items[0] != (Item{Kind: KindOrder, Description: "book", Quantity: 1, UnitPrice: 500, Amount: 500}) {
+		t.Fatalf("Items() without fee = %+v", items)
+	}
+	items := Items("cart", 700, 7, []db.ListOrderItemsRow{{ProductID: "sku-1", Quantity: 3, UnitPrice: 200}, {ProductID: "sku-2", Quantity: 1, UnitPrice: 100}}, db.GetPromoRedemptionRow{})
+	if len(items) != 3 || items[0] != (Item{Kind: KindProduct, ProductID: "sku-1", Description: "sku-1", Quantity: 3, UnitPrice: 200, Amount: 600}) || items[2].Kind != KindFee {
+		t.Fatalf("Items() of products = %+v", items)
+	}
+	// The order line is the amount before the discount; the lines add up to
+	// the charged amount.
+	items = Items("book", 450, 0, nil, db.GetPromoRedemptionRow{Code: "SALE10", Amount: 500, Discount: 50})
+	if len(items) != 2 || items[0].Amount != 500 || items[1] != (Item{Kind: KindDiscount, Description: "Promo code SALE10", Quantity: 1, UnitPrice: -50, Amount: -50}) {
+		t.Fatalf("Items() with discount = %+v", items)
+	}
+}
@@ -0,0 +1,151 @@
+// Package receipt issues the receipts of finished orders and renders them
+// as documents for customers.
+//
+// A receipt is written in the transaction that moves its order to FINISHED
+// and never changes afterwards. Orders finished before receipts existed get
+// theirs the first time it is asked for.
+package receipt
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// ErrNotFinished is returned by Issue for orders that are not FINISHED.
+var ErrNotFinished = errors.New("order is not finished")
+
+// Kinds of Item.
+const (
+	KindOrder    = "order"
+	KindProduct  = "product"
+	KindDiscount = "discount"
+	KindFee      = "fee"
+)
+
+// Item is one line of a receipt. Amounts are in minimal currency units and
+// negative on the discount line; ProductID is set on product lines.
+type Item struct {
+	Kind        string `json:"kind"`
+	ProductID   string `json:"product_id,omitempty"`
+	Description string `json:"description"`
+	Quantity    int64  `json:"quantity"`
+	UnitPrice   int64  `json:"unit_price"`
+	Amount      int64  `json:"amount"`
+}
+
+// Receipt of a finished order. TotalAmount is Amount plus FeeAmount, what
+// the customer was charged in all.
+type Receipt struct {
+	Number         string
+	OrderID        string
+	UserID         string
+	Currency       money.Currency
+	Items          []Item
+	Amount         int64
+	FeeAmount      int64
+	TotalAmount    int64
+	OrderCreatedAt time.Time
+	PaidAt         time.Time
+	IssuedAt       time.Time
+}
+
+// Renderer turns a receipt into a document, e.g. a PDF to send to the
+// customer.
+type Renderer interface {
+	// ContentType is the media type of the documents, e.g. application/pdf.
+	ContentType() string
+	Render(r Receipt) ([]byte, error)
+}
+
+// Items lists what an order was charged for: the catalog products it was
+// created from at the prices of the time, or the order itself when it was
+// created with an amount, the promo code discount and the payment fee if
+// there were any. amount is the charged amount, after the discount; the
+// lines add up to it plus fee.
+func Items(description string, amount, fee int64, products []db.ListOrderItemsRow, redemption db.GetPromoRedemptionRow) []Item {
+	var items []Item
+	for _, p := range products {
+		items = append(items, Item{Kind: KindProduct, ProductID: p.ProductID, Description: p.ProductID, Quantity: p.Quantity, UnitPrice: p.UnitPrice, Amount: p.Quantity * p.UnitPrice})
+	}
+	if len(items) == 0 {
+		full := amount + redemption.Discount
+		items = []Item{{Kind: KindOrder, Description: description, Quantity: 1, UnitPrice: full, Amount: full}}
+	}
+	if d := redemption.Discount; d > 0 {
+		items = append(items, Item{Kind: KindDiscount, Description: "Promo code " + redemption.Code, Quantity: 1, UnitPrice: -d, Amount: -d})
+	}
+	if fee > 0 {
+		items = append(items, Item{Kind: KindFee, Description: "Payment fee", Quantity: 1, UnitPrice: fee, Amount: fee})
+	}
+	return items
+}
+
+// Issue writes the receipt of order orderID in the transaction of q. An
+// order that already has a receipt keeps it; one that is not FINISHED gets
+// ErrNotFinished, and an unknown one pgx.ErrNoRows.
+func Issue(ctx context.Context, q db.Querier, orderID uuid.UUID) error {
+	o, err := q.GetOrderByID(ctx, pgtype.UUID{Bytes: orderID, Valid: true})
+	if err != nil {
+		return fmt.Errorf("load order for receipt: %w", err)
+	}
+	if o.Status != "FINISHED" {
+		return ErrNotFinished
+	}
+	products, err := q.ListOrderItems(ctx, o.OrderID)
+	if err != nil {
+		return fmt.Errorf("load order items for receipt: %w", err)
+	}
+	redemption, err := q.GetPromoRedemption(ctx, o.OrderID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("load promo redemption for receipt: %w", err)
+	}
+	items, err := json.Marshal(Items(o.Description, o.Amount, o.FeeAmount, products, redemption))
+	if err != nil {
+		return err
+	}
+	if _, err := q.InsertReceipt(ctx, db.InsertReceiptParams{
+		OrderID:        o.OrderID,
+		UserID:         o.UserID,
+		Items:          items,
+		Amount:         o.Amount,
+		FeeAmount:      o.FeeAmount,
+		TotalAmount:    o.Amount + o.FeeAmount,
+		OrderCreatedAt: o.CreatedAt,
+		PaidAt:         o.UpdatedAt,
+	}); err != nil {
+		return fmt.Errorf("insert receipt: %w", err)
+	}
+	return nil
+}
+
+// FromRow converts a stored receipt; its amounts are in currency.
+func FromRow(r db.Receipt, currency money.Currency) (Receipt, error) {
+	var items []Item
+	if err := json.Unmarshal(r.Items, &items); err != nil {
+		return Receipt{}, fmt.Errorf("decode receipt items: %w", err)
+	}
+	return Receipt{
+		Number:         r.Number,
+		OrderID:        r.OrderID.String(),
+		UserID:         r.UserID,
+		Currency:       currency,
+		Items:          items,
+		Amount:         r.Amount,
+		FeeAmount:      r.FeeAmount,
+		TotalAmount:    r.TotalAmount,
+		OrderCreatedAt: r.OrderCreatedAt.Time,
+		PaidAt:         r.PaidAt.Time,
+		IssuedAt:       r.IssuedAt.Time,
+	}, nil
+}
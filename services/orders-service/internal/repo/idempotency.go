@@ -0,0 +1,49 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+)
+
+// IdempotencyStore adapts q to idempotency.Store, so keys are written in the
+// same transaction as the operation they guard.
+func IdempotencyStore(q db.Querier) idempotency.Store {
+	return idempotencyStore{q: q}
+}
+
+type idempotencyStore struct {
+	q db.Querier
+}
+
+func (s idempotencyStore) Claim(ctx context.Context, key idempotency.Key, requestHash []byte) (bool, error) {
+	n, err := s.q.ClaimIdempotencyKey(ctx, db.ClaimIdempotencyKeyParams{
+		Scope:          key.Scope,
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+		RequestHash:    requestHash,
+	})
+	return n > 0, err
+}
+
+func (s idempotencyStore) Get(ctx context.Context, key idempotency.Key) (idempotency.Record, error) {
+	row, err := s.q.GetIdempotencyKey(ctx, db.GetIdempotencyKeyParams{
+		Scope:          key.Scope,
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+	})
+	if err != nil {
+		return idempotency.Record{}, err
+	}
+	return idempotency.Record{RequestHash: row.RequestHash, Status: idempotency.Status(row.Status), Response: row.Response}, nil
+}
+
+func (s idempotencyStore) Complete(ctx context.Context, key idempotency.Key, response []byte) error {
+	return s.q.CompleteIdempotencyKey(ctx, db.CompleteIdempotencyKeyParams{
+		Scope:          key.Scope,
+		UserID:         key.UserID,
+		IdempotencyKey: key.Key,
+		Response:       response,
+	})
+}
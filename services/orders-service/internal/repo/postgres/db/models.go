@@ -8,29 +8,278 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AdminAuditLog struct {
+	ID        int64              `json:"id"`
+	Operator  string             `json:"operator"`
+	Action    string             `json:"action"`
+	Target    string             `json:"target"`
+	Reason    string             `json:"reason"`
+	Details   []byte             `json:"details"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExportWatermark struct {
+	Name         string             `json:"name"`
+	UpdatedAt    pgtype.Timestamptz `json:"updated_at"`
+	OrderID      pgtype.UUID        `json:"order_id"`
+	ExportedRows int64              `json:"exported_rows"`
+	ExportedAt   pgtype.Timestamptz `json:"exported_at"`
+}
+
+type IdempotencyKey struct {
+	Scope          string             `json:"scope"`
+	UserID         string             `json:"user_id"`
+	IdempotencyKey string             `json:"idempotency_key"`
+	RequestHash    []byte             `json:"request_hash"`
+	Status         string             `json:"status"`
+	Response       []byte             `json:"response"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
+}
+
 type Inbox struct {
-	MessageID   pgtype.UUID        `json:"message_id"`
-	ProcessedAt pgtype.Timestamptz `json:"processed_at"`
+	MessageID      pgtype.UUID        `json:"message_id"`
+	ProcessedAt    pgtype.Timestamptz `json:"processed_at"`
+	CorrelationID  string             `json:"correlation_id"`
+	Consumer       string             `json:"consumer"`
+	ReceivedAt     pgtype.Timestamptz `json:"received_at"`
+	Topic          pgtype.Text        `json:"topic"`
+	KafkaPartition pgtype.Int4        `json:"kafka_partition"`
+	KafkaOffset    pgtype.Int8        `json:"kafka_offset"`
+	MessageKey     []byte             `json:"message_key"`
+	Payload        []byte             `json:"payload"`
+	Headers        []byte             `json:"headers"`
+}
+
+type KafkaOffset struct {
+	Topic      string             `json:"topic"`
+	Partition  int32              `json:"partition"`
+	LastOffset int64              `json:"last_offset"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type KnownAccount struct {
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Order struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	IdempotencyKey       pgtype.Text        `json:"idempotency_key"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	FeeAmount            int64              `json:"fee_amount"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	PaymentFailureCode   pgtype.Text        `json:"payment_failure_code"`
+	PaymentRetryCount    int32              `json:"payment_retry_count"`
+	PaymentMethod        string             `json:"payment_method"`
+	PayInInstallments    bool               `json:"pay_in_installments"`
+}
+
+type OrderDispute struct {
+	OrderID    pgtype.UUID        `json:"order_id"`
+	Status     string             `json:"status"`
+	Amount     int64              `json:"amount"`
+	Reason     string             `json:"reason"`
+	OpenedAt   pgtype.Timestamptz `json:"opened_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type OrderItem struct {
+	OrderID   pgtype.UUID `json:"order_id"`
+	Line      int32       `json:"line"`
+	ProductID string      `json:"product_id"`
+	Quantity  int64       `json:"quantity"`
+	UnitPrice int64       `json:"unit_price"`
+}
+
+type OrderPayment struct {
+	PaymentID      pgtype.UUID        `json:"payment_id"`
 	OrderID        pgtype.UUID        `json:"order_id"`
-	UserID         string             `json:"user_id"`
 	Amount         int64              `json:"amount"`
-	Description    string             `json:"description"`
-	IdempotencyKey pgtype.Text        `json:"idempotency_key"`
 	Status         string             `json:"status"`
+	FailureReason  pgtype.Text        `json:"failure_reason"`
+	IdempotencyKey pgtype.Text        `json:"idempotency_key"`
 	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Fee            int64              `json:"fee"`
+}
+
+type OrderStatusHistory struct {
+	EventID        pgtype.UUID        `json:"event_id"`
+	OrderID        pgtype.UUID        `json:"order_id"`
+	UserID         string             `json:"user_id"`
+	PreviousStatus string             `json:"previous_status"`
+	Status         string             `json:"status"`
+	Amount         int64              `json:"amount"`
+	PaidAmount     int64              `json:"paid_amount"`
+	Reason         string             `json:"reason"`
+	OccurredAt     pgtype.Timestamptz `json:"occurred_at"`
+}
+
+type OrderTransfer struct {
+	TransferID pgtype.UUID        `json:"transfer_id"`
+	OrderID    pgtype.UUID        `json:"order_id"`
+	FromUserID string             `json:"from_user_id"`
+	ToUserID   string             `json:"to_user_id"`
+	Status     string             `json:"status"`
+	CreatedAt  pgtype.Timestamptz `json:"created_at"`
+	ResolvedAt pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type OrdersArchive struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	IdempotencyKey       pgtype.Text        `json:"idempotency_key"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	ArchivedAt           pgtype.Timestamptz `json:"archived_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	PaymentFailureCode   pgtype.Text        `json:"payment_failure_code"`
+	PaymentRetryCount    int32              `json:"payment_retry_count"`
+	PaymentMethod        string             `json:"payment_method"`
+	PayInInstallments    bool               `json:"pay_in_installments"`
+}
+
+type OrdersRead struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	Archived             bool               `json:"archived"`
+	LastPaymentReason    pgtype.Text        `json:"last_payment_reason"`
+	ItemCount            int32              `json:"item_count"`
+}
+
+type OrdersReadSource struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	Archived             bool               `json:"archived"`
+	LastPaymentReason    interface{}        `json:"last_payment_reason"`
+	ItemCount            int32              `json:"item_count"`
 }
 
 type Outbox struct {
-	ID        int64              `json:"id"`
-	Topic     string             `json:"topic"`
-	KafkaKey  string             `json:"kafka_key"`
-	Payload   []byte             `json:"payload"`
-	Status    string             `json:"status"`
-	Attempts  int32              `json:"attempts"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	SentAt    pgtype.Timestamptz `json:"sent_at"`
-	LastError pgtype.Text        `json:"last_error"`
+	ID            int64              `json:"id"`
+	Topic         string             `json:"topic"`
+	KafkaKey      string             `json:"kafka_key"`
+	Payload       []byte             `json:"payload"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	NextRetryAt   pgtype.Timestamptz `json:"next_retry_at"`
+	CorrelationID string             `json:"correlation_id"`
+}
+
+type PaymentRetry struct {
+	RetryKey      string             `json:"retry_key"`
+	OrderID       pgtype.UUID        `json:"order_id"`
+	PaymentID     pgtype.UUID        `json:"payment_id"`
+	UserID        string             `json:"user_id"`
+	Amount        int64              `json:"amount"`
+	Attempts      int32              `json:"attempts"`
+	NextAttemptAt pgtype.Timestamptz `json:"next_attempt_at"`
+	LastReason    pgtype.Text        `json:"last_reason"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	CorrelationID string             `json:"correlation_id"`
+}
+
+type ProjectionReplay struct {
+	ID          int64              `json:"id"`
+	Projection  string             `json:"projection"`
+	Topic       string             `json:"topic"`
+	Status      string             `json:"status"`
+	RateLimit   int32              `json:"rate_limit"`
+	Applied     int64              `json:"applied"`
+	Error       string             `json:"error"`
+	RequestedBy string             `json:"requested_by"`
+	Reason      string             `json:"reason"`
+	Owner       pgtype.UUID        `json:"owner"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	HeartbeatAt pgtype.Timestamptz `json:"heartbeat_at"`
+	FinishedAt  pgtype.Timestamptz `json:"finished_at"`
+}
+
+type ProjectionReplayOffset struct {
+	ReplayID    int64 `json:"replay_id"`
+	Partition   int32 `json:"partition"`
+	StartOffset int64 `json:"start_offset"`
+	NextOffset  int64 `json:"next_offset"`
+	EndOffset   int64 `json:"end_offset"`
+}
+
+type PromoCode struct {
+	Code                  string             `json:"code"`
+	PercentOff            pgtype.Int4        `json:"percent_off"`
+	AmountOff             pgtype.Int8        `json:"amount_off"`
+	MaxRedemptions        pgtype.Int4        `json:"max_redemptions"`
+	MaxRedemptionsPerUser pgtype.Int4        `json:"max_redemptions_per_user"`
+	RedeemedCount         int32              `json:"redeemed_count"`
+	StartsAt              pgtype.Timestamptz `json:"starts_at"`
+	ExpiresAt             pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt             pgtype.Timestamptz `json:"created_at"`
+}
+
+type PromoRedemption struct {
+	OrderID    pgtype.UUID        `json:"order_id"`
+	Code       string             `json:"code"`
+	UserID     string             `json:"user_id"`
+	Amount     int64              `json:"amount"`
+	Discount   int64              `json:"discount"`
+	RedeemedAt pgtype.Timestamptz `json:"redeemed_at"`
+	ReleasedAt pgtype.Timestamptz `json:"released_at"`
+}
+
+type Receipt struct {
+	OrderID        pgtype.UUID        `json:"order_id"`
+	Number         string             `json:"number"`
+	UserID         string             `json:"user_id"`
+	Items          []byte             `json:"items"`
+	Amount         int64              `json:"amount"`
+	FeeAmount      int64              `json:"fee_amount"`
+	TotalAmount    int64              `json:"total_amount"`
+	OrderCreatedAt pgtype.Timestamptz `json:"order_created_at"`
+	PaidAt         pgtype.Timestamptz `json:"paid_at"`
+	IssuedAt       pgtype.Timestamptz `json:"issued_at"`
 }
@@ -11,30 +11,144 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-const createOrder = `-- name: CreateOrder :one
-INSERT INTO orders (user_id, amount, description, status)
-VALUES ($1, $2, $3, 'NEW')
-    RETURNING order_id, user_id, amount, description, status, created_at
+const applyOrderPayment = `-- name: ApplyOrderPayment :one
+UPDATE orders o
+SET paid_amount = o.paid_amount + $1,
+    fee_amount = o.fee_amount + $2,
+    status = CASE WHEN o.paid_amount + $1 >= o.amount THEN 'FINISHED' ELSE 'PARTIALLY_PAID' END,
+    version = o.version + 1,
+    updated_at = now()
+FROM (
+    SELECT order_id, created_at, status
+    FROM orders
+    WHERE order_id = $3::uuid
+        FOR UPDATE
+) prev
+WHERE o.order_id = prev.order_id AND o.created_at = prev.created_at AND prev.status IN ('NEW', 'PARTIALLY_PAID')
+    RETURNING o.user_id, o.amount, o.paid_amount, o.status, prev.status AS previous_status
 `
 
-type CreateOrderParams struct {
-	UserID      string `json:"user_id"`
-	Amount      int64  `json:"amount"`
-	Description string `json:"description"`
+type ApplyOrderPaymentParams struct {
+	PaidAmount int64       `json:"paid_amount"`
+	FeeAmount  int64       `json:"fee_amount"`
+	OrderID    pgtype.UUID `json:"order_id"`
 }
 
-type CreateOrderRow struct {
-	OrderID     pgtype.UUID        `json:"order_id"`
-	UserID      string             `json:"user_id"`
-	Amount      int64              `json:"amount"`
-	Description string             `json:"description"`
-	Status      string             `json:"status"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+type ApplyOrderPaymentRow struct {
+	UserID         string `json:"user_id"`
+	Amount         int64  `json:"amount"`
+	PaidAmount     int64  `json:"paid_amount"`
+	Status         string `json:"status"`
+	PreviousStatus string `json:"previous_status"`
 }
 
-func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (CreateOrderRow, error) {
-	row := q.db.QueryRow(ctx, createOrder, arg.UserID, arg.Amount, arg.Description)
-	var i CreateOrderRow
+// Засчитываем успешный платёж-частичку; статус NEW/PARTIALLY_PAID -> PARTIALLY_PAID/FINISHED
+func (q *Queries) ApplyOrderPayment(ctx context.Context, arg ApplyOrderPaymentParams) (ApplyOrderPaymentRow, error) {
+	row := q.db.QueryRow(ctx, applyOrderPayment, arg.PaidAmount, arg.FeeAmount, arg.OrderID)
+	var i ApplyOrderPaymentRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Amount,
+		&i.PaidAmount,
+		&i.Status,
+		&i.PreviousStatus,
+	)
+	return i, err
+}
+
+const archiveOrders = `-- name: ArchiveOrders :many
+WITH moved AS (
+    DELETE FROM orders
+    WHERE order_id IN (
+        SELECT order_id
+        FROM orders
+        WHERE status IN ('FINISHED', 'CANCELLED', 'REFUNDED')
+          AND updated_at < $1::timestamptz
+        ORDER BY updated_at
+        LIMIT $2::int
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments
+),
+marked AS (
+    UPDATE orders_read r
+    SET archived = true
+    FROM moved
+    WHERE r.order_id = moved.order_id
+)
+INSERT INTO orders_archive (order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments)
+SELECT order_id, user_id, amount, description, idempotency_key, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_failure_code, payment_retry_count, payment_method, pay_in_installments
+FROM moved
+    RETURNING order_id, user_id
+`
+
+type ArchiveOrdersParams struct {
+	Before    pgtype.Timestamptz `json:"before"`
+	BatchSize int32              `json:"batch_size"`
+}
+
+type ArchiveOrdersRow struct {
+	OrderID pgtype.UUID `json:"order_id"`
+	UserID  string      `json:"user_id"`
+}
+
+// Переносит пачку завершённых/отменённых/возвращённых заказов, не менявшихся с before, в orders_archive.
+// Событий о переносе нет, поэтому archived в orders_read ставим здесь же;
+// перенесённые заказы возвращаются, чтобы сбросить их в кэше
+func (q *Queries) ArchiveOrders(ctx context.Context, arg ArchiveOrdersParams) ([]ArchiveOrdersRow, error) {
+	rows, err := q.db.Query(ctx, archiveOrders, arg.Before, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ArchiveOrdersRow
+	for rows.Next() {
+		var i ArchiveOrdersRow
+		if err := rows.Scan(&i.OrderID, &i.UserID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const cancelScheduledOrder = `-- name: CancelScheduledOrder :one
+UPDATE orders
+SET status = 'CANCELLED', payment_failure_reason = $1, version = version + 1, updated_at = now()
+WHERE order_id = $2 AND user_id = $3 AND status = 'SCHEDULED'
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at
+`
+
+type CancelScheduledOrderParams struct {
+	Reason  pgtype.Text `json:"reason"`
+	OrderID pgtype.UUID `json:"order_id"`
+	UserID  string      `json:"user_id"`
+}
+
+type CancelScheduledOrderRow struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+}
+
+// Отмена до срока; оплаченные или уже запрошенные заказы не трогаем
+func (q *Queries) CancelScheduledOrder(ctx context.Context, arg CancelScheduledOrderParams) (CancelScheduledOrderRow, error) {
+	row := q.db.QueryRow(ctx, cancelScheduledOrder, arg.Reason, arg.OrderID, arg.UserID)
+	var i CancelScheduledOrderRow
 	err := row.Scan(
 		&i.OrderID,
 		&i.UserID,
@@ -42,42 +156,109 @@ func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (Creat
 		&i.Description,
 		&i.Status,
 		&i.CreatedAt,
+		&i.PaymentFailureReason,
+		&i.PaidAmount,
+		&i.FeeAmount,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
 	)
 	return i, err
 }
 
-const createOrderIdempotent = `-- name: CreateOrderIdempotent :one
-INSERT INTO orders (user_id, amount, description, status, idempotency_key)
-VALUES ($1, $2, $3, 'NEW', $4)
-    ON CONFLICT (user_id, idempotency_key) DO NOTHING
-RETURNING order_id, user_id, amount, description, status, created_at, idempotency_key
+const countNewOrders = `-- name: CountNewOrders :one
+SELECT count(*)
+FROM orders
+WHERE user_id = $1 AND status = 'NEW'
+`
+
+func (q *Queries) CountNewOrders(ctx context.Context, userID string) (int64, error) {
+	row := q.db.QueryRow(ctx, countNewOrders, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countOrdersByStatus = `-- name: CountOrdersByStatus :many
+SELECT status, count(*)::bigint AS orders
+FROM orders
+GROUP BY status
+ORDER BY status
+`
+
+type CountOrdersByStatusRow struct {
+	Status string `json:"status"`
+	Orders int64  `json:"orders"`
+}
+
+// Число заказов в горячей таблице по статусам (orders_archive не считается)
+func (q *Queries) CountOrdersByStatus(ctx context.Context) ([]CountOrdersByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countOrdersByStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountOrdersByStatusRow
+	for rows.Next() {
+		var i CountOrdersByStatusRow
+		if err := rows.Scan(&i.Status, &i.Orders); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createOrder = `-- name: CreateOrder :one
+INSERT INTO orders (user_id, amount, description, status, metadata, tags, pay_at, payment_method, pay_in_installments)
+VALUES ($1, $2, $3, CASE WHEN $6::timestamptz IS NULL THEN 'NEW' ELSE 'SCHEDULED' END, $4, $5, $6, $7, $8)
+    RETURNING order_id, user_id, amount, description, status, created_at, metadata, tags, version, updated_at, pay_at, payment_method
 `
 
-type CreateOrderIdempotentParams struct {
-	UserID         string      `json:"user_id"`
-	Amount         int64       `json:"amount"`
-	Description    string      `json:"description"`
-	IdempotencyKey pgtype.Text `json:"idempotency_key"`
+type CreateOrderParams struct {
+	UserID            string             `json:"user_id"`
+	Amount            int64              `json:"amount"`
+	Description       string             `json:"description"`
+	Metadata          []byte             `json:"metadata"`
+	Tags              []string           `json:"tags"`
+	PayAt             pgtype.Timestamptz `json:"pay_at"`
+	PaymentMethod     string             `json:"payment_method"`
+	PayInInstallments bool               `json:"pay_in_installments"`
 }
 
-type CreateOrderIdempotentRow struct {
-	OrderID        pgtype.UUID        `json:"order_id"`
-	UserID         string             `json:"user_id"`
-	Amount         int64              `json:"amount"`
-	Description    string             `json:"description"`
-	Status         string             `json:"status"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	IdempotencyKey pgtype.Text        `json:"idempotency_key"`
+type CreateOrderRow struct {
+	OrderID       pgtype.UUID        `json:"order_id"`
+	UserID        string             `json:"user_id"`
+	Amount        int64              `json:"amount"`
+	Description   string             `json:"description"`
+	Status        string             `json:"status"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	Metadata      []byte             `json:"metadata"`
+	Tags          []string           `json:"tags"`
+	Version       int64              `json:"version"`
+	UpdatedAt     pgtype.Timestamptz `json:"updated_at"`
+	PayAt         pgtype.Timestamptz `json:"pay_at"`
+	PaymentMethod string             `json:"payment_method"`
 }
 
-func (q *Queries) CreateOrderIdempotent(ctx context.Context, arg CreateOrderIdempotentParams) (CreateOrderIdempotentRow, error) {
-	row := q.db.QueryRow(ctx, createOrderIdempotent,
+// Заказ с pay_at создаётся в SCHEDULED, без него — сразу NEW
+func (q *Queries) CreateOrder(ctx context.Context, arg CreateOrderParams) (CreateOrderRow, error) {
+	row := q.db.QueryRow(ctx, createOrder,
 		arg.UserID,
 		arg.Amount,
 		arg.Description,
-		arg.IdempotencyKey,
+		arg.Metadata,
+		arg.Tags,
+		arg.PayAt,
+		arg.PaymentMethod,
+		arg.PayInInstallments,
 	)
-	var i CreateOrderIdempotentRow
+	var i CreateOrderRow
 	err := row.Scan(
 		&i.OrderID,
 		&i.UserID,
@@ -85,15 +266,62 @@ func (q *Queries) CreateOrderIdempotent(ctx context.Context, arg CreateOrderIdem
 		&i.Description,
 		&i.Status,
 		&i.CreatedAt,
-		&i.IdempotencyKey,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
+		&i.PaymentMethod,
 	)
 	return i, err
 }
 
-const getOrder = `-- name: GetOrder :one
-SELECT order_id, user_id, amount, description, status, created_at
+const findRecentDuplicateOrder = `-- name: FindRecentDuplicateOrder :one
+SELECT order_id
 FROM orders
-WHERE order_id = $1 AND user_id = $2
+WHERE user_id = $1
+  AND amount = $2
+  AND description = $3
+  AND status <> 'CANCELLED'
+  AND created_at >= $4::timestamptz
+ORDER BY created_at DESC
+    LIMIT 1
+`
+
+type FindRecentDuplicateOrderParams struct {
+	UserID      string             `json:"user_id"`
+	Amount      int64              `json:"amount"`
+	Description string             `json:"description"`
+	Since       pgtype.Timestamptz `json:"since"`
+}
+
+// Последний неотменённый заказ пользователя с той же суммой и описанием не старше since
+func (q *Queries) FindRecentDuplicateOrder(ctx context.Context, arg FindRecentDuplicateOrderParams) (pgtype.UUID, error) {
+	row := q.db.QueryRow(ctx, findRecentDuplicateOrder,
+		arg.UserID,
+		arg.Amount,
+		arg.Description,
+		arg.Since,
+	)
+	var order_id pgtype.UUID
+	err := row.Scan(&order_id)
+	return order_id, err
+}
+
+const getOrder = `-- name: GetOrder :one
+SELECT o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       d.status AS dispute_status, d.amount AS dispute_amount, d.reason AS dispute_reason, d.opened_at AS dispute_opened_at, d.resolved_at AS dispute_resolved_at
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    WHERE order_id = $1::uuid AND user_id = $2::text
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+    WHERE order_id = $1::uuid AND user_id = $2::text
+        LIMIT 1
+) o
+LEFT JOIN order_disputes d ON d.order_id = o.order_id
 `
 
 type GetOrderParams struct {
@@ -102,14 +330,30 @@ type GetOrderParams struct {
 }
 
 type GetOrderRow struct {
-	OrderID     pgtype.UUID        `json:"order_id"`
-	UserID      string             `json:"user_id"`
-	Amount      int64              `json:"amount"`
-	Description string             `json:"description"`
-	Status      string             `json:"status"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	Archived             bool               `json:"archived"`
+	DisputeStatus        pgtype.Text        `json:"dispute_status"`
+	DisputeAmount        pgtype.Int8        `json:"dispute_amount"`
+	DisputeReason        pgtype.Text        `json:"dispute_reason"`
+	DisputeOpenedAt      pgtype.Timestamptz `json:"dispute_opened_at"`
+	DisputeResolvedAt    pgtype.Timestamptz `json:"dispute_resolved_at"`
 }
 
+// Архивные заказы тоже находятся; горячая таблица проверяется первой.
+// Спор по заказу, если он был, приходит в колонках dispute_*
 func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (GetOrderRow, error) {
 	row := q.db.QueryRow(ctx, getOrder, arg.OrderID, arg.UserID)
 	var i GetOrderRow
@@ -120,34 +364,58 @@ func (q *Queries) GetOrder(ctx context.Context, arg GetOrderParams) (GetOrderRow
 		&i.Description,
 		&i.Status,
 		&i.CreatedAt,
+		&i.PaymentFailureReason,
+		&i.PaidAmount,
+		&i.FeeAmount,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
+		&i.Archived,
+		&i.DisputeStatus,
+		&i.DisputeAmount,
+		&i.DisputeReason,
+		&i.DisputeOpenedAt,
+		&i.DisputeResolvedAt,
 	)
 	return i, err
 }
 
-const getOrderByIdempotency = `-- name: GetOrderByIdempotency :one
-SELECT order_id, user_id, amount, description, status, created_at, idempotency_key
+const getOrderForUpdate = `-- name: GetOrderForUpdate :one
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_method, pay_in_installments
 FROM orders
-WHERE user_id = $1 AND idempotency_key = $2
+WHERE order_id = $1 AND user_id = $2
+    FOR UPDATE
 `
 
-type GetOrderByIdempotencyParams struct {
-	UserID         string      `json:"user_id"`
-	IdempotencyKey pgtype.Text `json:"idempotency_key"`
+type GetOrderForUpdateParams struct {
+	OrderID pgtype.UUID `json:"order_id"`
+	UserID  string      `json:"user_id"`
 }
 
-type GetOrderByIdempotencyRow struct {
-	OrderID        pgtype.UUID        `json:"order_id"`
-	UserID         string             `json:"user_id"`
-	Amount         int64              `json:"amount"`
-	Description    string             `json:"description"`
-	Status         string             `json:"status"`
-	CreatedAt      pgtype.Timestamptz `json:"created_at"`
-	IdempotencyKey pgtype.Text        `json:"idempotency_key"`
+type GetOrderForUpdateRow struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	PaymentMethod        string             `json:"payment_method"`
+	PayInInstallments    bool               `json:"pay_in_installments"`
 }
 
-func (q *Queries) GetOrderByIdempotency(ctx context.Context, arg GetOrderByIdempotencyParams) (GetOrderByIdempotencyRow, error) {
-	row := q.db.QueryRow(ctx, getOrderByIdempotency, arg.UserID, arg.IdempotencyKey)
-	var i GetOrderByIdempotencyRow
+func (q *Queries) GetOrderForUpdate(ctx context.Context, arg GetOrderForUpdateParams) (GetOrderForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getOrderForUpdate, arg.OrderID, arg.UserID)
+	var i GetOrderForUpdateRow
 	err := row.Scan(
 		&i.OrderID,
 		&i.UserID,
@@ -155,43 +423,170 @@ func (q *Queries) GetOrderByIdempotency(ctx context.Context, arg GetOrderByIdemp
 		&i.Description,
 		&i.Status,
 		&i.CreatedAt,
-		&i.IdempotencyKey,
+		&i.PaymentFailureReason,
+		&i.PaidAmount,
+		&i.FeeAmount,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
+		&i.PaymentMethod,
+		&i.PayInInstallments,
 	)
 	return i, err
 }
 
-const listOrders = `-- name: ListOrders :many
-SELECT order_id, user_id, amount, description, status, created_at
+const getOrderPaymentRetry = `-- name: GetOrderPaymentRetry :one
+SELECT status, payment_failure_code, payment_retry_count
 FROM orders
-WHERE user_id = $1
-ORDER BY created_at DESC, order_id DESC
-    LIMIT $2 OFFSET $3
+WHERE order_id = $1 AND user_id = $2
 `
 
-type ListOrdersParams struct {
-	UserID string `json:"user_id"`
-	Limit  int32  `json:"limit"`
-	Offset int32  `json:"offset"`
+type GetOrderPaymentRetryParams struct {
+	OrderID pgtype.UUID `json:"order_id"`
+	UserID  string      `json:"user_id"`
 }
 
-type ListOrdersRow struct {
-	OrderID     pgtype.UUID        `json:"order_id"`
-	UserID      string             `json:"user_id"`
-	Amount      int64              `json:"amount"`
-	Description string             `json:"description"`
-	Status      string             `json:"status"`
-	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+type GetOrderPaymentRetryRow struct {
+	Status             string      `json:"status"`
+	PaymentFailureCode pgtype.Text `json:"payment_failure_code"`
+	PaymentRetryCount  int32       `json:"payment_retry_count"`
+}
+
+// Почему RetryOrderPayment не нашёл заказ
+func (q *Queries) GetOrderPaymentRetry(ctx context.Context, arg GetOrderPaymentRetryParams) (GetOrderPaymentRetryRow, error) {
+	row := q.db.QueryRow(ctx, getOrderPaymentRetry, arg.OrderID, arg.UserID)
+	var i GetOrderPaymentRetryRow
+	err := row.Scan(&i.Status, &i.PaymentFailureCode, &i.PaymentRetryCount)
+	return i, err
+}
+
+const getOrders = `-- name: GetOrders :many
+SELECT DISTINCT ON (o.order_id)
+       o.order_id, o.user_id, o.amount, o.description, o.status, o.created_at, o.payment_failure_reason, o.paid_amount, o.fee_amount, o.metadata, o.tags, o.version, o.updated_at, o.pay_at, o.archived,
+       d.status AS dispute_status, d.amount AS dispute_amount, d.reason AS dispute_reason, d.opened_at AS dispute_opened_at, d.resolved_at AS dispute_resolved_at
+FROM (
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, false AS archived
+    FROM orders
+    WHERE order_id = ANY($1::uuid[]) AND user_id = $2::text
+    UNION ALL
+    SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, true AS archived
+    FROM orders_archive
+    WHERE order_id = ANY($1::uuid[]) AND user_id = $2::text
+) o
+LEFT JOIN order_disputes d ON d.order_id = o.order_id
+ORDER BY o.order_id, o.archived
+`
+
+type GetOrdersParams struct {
+	OrderIds []pgtype.UUID `json:"order_ids"`
+	UserID   string        `json:"user_id"`
+}
+
+type GetOrdersRow struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	Archived             bool               `json:"archived"`
+	DisputeStatus        pgtype.Text        `json:"dispute_status"`
+	DisputeAmount        pgtype.Int8        `json:"dispute_amount"`
+	DisputeReason        pgtype.Text        `json:"dispute_reason"`
+	DisputeOpenedAt      pgtype.Timestamptz `json:"dispute_opened_at"`
+	DisputeResolvedAt    pgtype.Timestamptz `json:"dispute_resolved_at"`
+}
+
+// GetOrder для нескольких заказов одного пользователя за один запрос;
+// чужие и несуществующие просто не возвращаются. Заказ, найденный и в
+// горячей таблице, и в архиве, берётся из горячей
+func (q *Queries) GetOrders(ctx context.Context, arg GetOrdersParams) ([]GetOrdersRow, error) {
+	rows, err := q.db.Query(ctx, getOrders, arg.OrderIds, arg.UserID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetOrdersRow
+	for rows.Next() {
+		var i GetOrdersRow
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.UserID,
+			&i.Amount,
+			&i.Description,
+			&i.Status,
+			&i.CreatedAt,
+			&i.PaymentFailureReason,
+			&i.PaidAmount,
+			&i.FeeAmount,
+			&i.Metadata,
+			&i.Tags,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.PayAt,
+			&i.Archived,
+			&i.DisputeStatus,
+			&i.DisputeAmount,
+			&i.DisputeReason,
+			&i.DisputeOpenedAt,
+			&i.DisputeResolvedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListOrdersRow, error) {
-	rows, err := q.db.Query(ctx, listOrders, arg.UserID, arg.Limit, arg.Offset)
+const listOrders = `-- name: ListOrders :many
+SELECT order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, archived, last_payment_reason, item_count
+FROM orders_read
+WHERE user_id = $1::text
+  AND ($2::bool OR NOT archived)
+  AND ($3::text = '' OR tags @> ARRAY[$3::text])
+ORDER BY created_at DESC, order_id DESC
+    LIMIT $5 OFFSET $4
+`
+
+type ListOrdersParams struct {
+	UserID          string `json:"user_id"`
+	IncludeArchived bool   `json:"include_archived"`
+	Tag             string `json:"tag"`
+	Offset          int32  `json:"offset"`
+	Limit           int32  `json:"limit"`
+}
+
+// Читает проекцию orders_read, а не orders: без UNION с архивом и с
+// last_payment_reason и item_count. Пустой tag — без фильтра; @> вместо = ANY,
+// чтобы работал GIN-индекс по tags. Архивные заказы — только с include_archived
+func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]OrdersRead, error) {
+	rows, err := q.db.Query(ctx, listOrders,
+		arg.UserID,
+		arg.IncludeArchived,
+		arg.Tag,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListOrdersRow
+	var items []OrdersRead
 	for rows.Next() {
-		var i ListOrdersRow
+		var i OrdersRead
 		if err := rows.Scan(
 			&i.OrderID,
 			&i.UserID,
@@ -199,6 +594,63 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListO
 			&i.Description,
 			&i.Status,
 			&i.CreatedAt,
+			&i.PaymentFailureReason,
+			&i.PaidAmount,
+			&i.FeeAmount,
+			&i.Metadata,
+			&i.Tags,
+			&i.Version,
+			&i.UpdatedAt,
+			&i.PayAt,
+			&i.Archived,
+			&i.LastPaymentReason,
+			&i.ItemCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockDueScheduledOrders = `-- name: LockDueScheduledOrders :many
+SELECT order_id, created_at, user_id, amount, pay_at, payment_method
+FROM orders
+WHERE status = 'SCHEDULED' AND pay_at <= now()
+ORDER BY pay_at
+    LIMIT $1
+FOR UPDATE SKIP LOCKED
+`
+
+type LockDueScheduledOrdersRow struct {
+	OrderID       pgtype.UUID        `json:"order_id"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	UserID        string             `json:"user_id"`
+	Amount        int64              `json:"amount"`
+	PayAt         pgtype.Timestamptz `json:"pay_at"`
+	PaymentMethod string             `json:"payment_method"`
+}
+
+// Заказы, чей pay_at наступил; планировщик переводит их в NEW в той же транзакции
+func (q *Queries) LockDueScheduledOrders(ctx context.Context, limit int32) ([]LockDueScheduledOrdersRow, error) {
+	rows, err := q.db.Query(ctx, lockDueScheduledOrders, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LockDueScheduledOrdersRow
+	for rows.Next() {
+		var i LockDueScheduledOrdersRow
+		if err := rows.Scan(
+			&i.OrderID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.Amount,
+			&i.PayAt,
+			&i.PaymentMethod,
 		); err != nil {
 			return nil, err
 		}
@@ -210,19 +662,211 @@ func (q *Queries) ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListO
 	return items, nil
 }
 
-const updateOrderStatusIfNew = `-- name: UpdateOrderStatusIfNew :exec
+const lockUserOrderCreate = `-- name: LockUserOrderCreate :exec
+SELECT pg_advisory_xact_lock(hashtext('orders_create'), hashtext($1::text))
+`
+
+// Сериализует CreateOrder одного пользователя до конца транзакции, чтобы квоту NEW-заказов и проверку дублей не обошли параллельные запросы
+func (q *Queries) LockUserOrderCreate(ctx context.Context, userID string) error {
+	_, err := q.db.Exec(ctx, lockUserOrderCreate, userID)
+	return err
+}
+
+const oldestNewOrderSince = `-- name: OldestNewOrderSince :one
+SELECT min(GREATEST(created_at, COALESCE(pay_at, created_at)))::timestamptz AS since
+FROM orders
+WHERE status = 'NEW'
+  AND NOT pay_in_installments
+`
+
+// Самый ранний момент, с которого заказ ждёт в NEW: отложенные — с pay_at, NULL если таких нет.
+// Заказы в рассрочку не считаются: они ждут в NEW, пока пользователь не оплатит первую часть, и это не зависание саги
+func (q *Queries) OldestNewOrderSince(ctx context.Context) (pgtype.Timestamptz, error) {
+	row := q.db.QueryRow(ctx, oldestNewOrderSince)
+	var since pgtype.Timestamptz
+	err := row.Scan(&since)
+	return since, err
+}
+
+const releaseScheduledOrder = `-- name: ReleaseScheduledOrder :exec
+UPDATE orders
+SET status = 'NEW', version = version + 1, updated_at = now()
+WHERE order_id = $1 AND created_at = $2 AND status = 'SCHEDULED'
+`
+
+type ReleaseScheduledOrderParams struct {
+	OrderID   pgtype.UUID        `json:"order_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ReleaseScheduledOrder(ctx context.Context, arg ReleaseScheduledOrderParams) error {
+	_, err := q.db.Exec(ctx, releaseScheduledOrder, arg.OrderID, arg.CreatedAt)
+	return err
+}
+
+const retryOrderPayment = `-- name: RetryOrderPayment :one
+UPDATE orders
+SET status = 'NEW', payment_failure_reason = NULL, payment_failure_code = NULL,
+    payment_retry_count = payment_retry_count + 1, version = version + 1, updated_at = now()
+WHERE order_id = $1 AND user_id = $2
+  AND status = 'CANCELLED' AND payment_failure_code = 'NOT_ENOUGH_FUNDS'
+  AND payment_retry_count < $3::int
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at, payment_retry_count, payment_method
+`
+
+type RetryOrderPaymentParams struct {
+	OrderID    pgtype.UUID `json:"order_id"`
+	UserID     string      `json:"user_id"`
+	MaxRetries int32       `json:"max_retries"`
+}
+
+type RetryOrderPaymentRow struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+	PaymentRetryCount    int32              `json:"payment_retry_count"`
+	PaymentMethod        string             `json:"payment_method"`
+}
+
+// Повтор оплаты пользователем: только заказ, отменённый из-за нехватки средств,
+// и не больше max_retries раз
+func (q *Queries) RetryOrderPayment(ctx context.Context, arg RetryOrderPaymentParams) (RetryOrderPaymentRow, error) {
+	row := q.db.QueryRow(ctx, retryOrderPayment, arg.OrderID, arg.UserID, arg.MaxRetries)
+	var i RetryOrderPaymentRow
+	err := row.Scan(
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.PaymentFailureReason,
+		&i.PaidAmount,
+		&i.FeeAmount,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
+		&i.PaymentRetryCount,
+		&i.PaymentMethod,
+	)
+	return i, err
+}
+
+const updateOrderDetails = `-- name: UpdateOrderDetails :one
+UPDATE orders
+SET description = $1,
+    metadata = $2,
+    tags = $3,
+    version = version + 1,
+    updated_at = now()
+WHERE order_id = $4 AND user_id = $5
+    RETURNING order_id, user_id, amount, description, status, created_at, payment_failure_reason, paid_amount, fee_amount, metadata, tags, version, updated_at, pay_at
+`
+
+type UpdateOrderDetailsParams struct {
+	Description string      `json:"description"`
+	Metadata    []byte      `json:"metadata"`
+	Tags        []string    `json:"tags"`
+	OrderID     pgtype.UUID `json:"order_id"`
+	UserID      string      `json:"user_id"`
+}
+
+type UpdateOrderDetailsRow struct {
+	OrderID              pgtype.UUID        `json:"order_id"`
+	UserID               string             `json:"user_id"`
+	Amount               int64              `json:"amount"`
+	Description          string             `json:"description"`
+	Status               string             `json:"status"`
+	CreatedAt            pgtype.Timestamptz `json:"created_at"`
+	PaymentFailureReason pgtype.Text        `json:"payment_failure_reason"`
+	PaidAmount           int64              `json:"paid_amount"`
+	FeeAmount            int64              `json:"fee_amount"`
+	Metadata             []byte             `json:"metadata"`
+	Tags                 []string           `json:"tags"`
+	Version              int64              `json:"version"`
+	UpdatedAt            pgtype.Timestamptz `json:"updated_at"`
+	PayAt                pgtype.Timestamptz `json:"pay_at"`
+}
+
+// Правка описания, metadata и tags; вызывающий держит строку через GetOrderForUpdate
+func (q *Queries) UpdateOrderDetails(ctx context.Context, arg UpdateOrderDetailsParams) (UpdateOrderDetailsRow, error) {
+	row := q.db.QueryRow(ctx, updateOrderDetails,
+		arg.Description,
+		arg.Metadata,
+		arg.Tags,
+		arg.OrderID,
+		arg.UserID,
+	)
+	var i UpdateOrderDetailsRow
+	err := row.Scan(
+		&i.OrderID,
+		&i.UserID,
+		&i.Amount,
+		&i.Description,
+		&i.Status,
+		&i.CreatedAt,
+		&i.PaymentFailureReason,
+		&i.PaidAmount,
+		&i.FeeAmount,
+		&i.Metadata,
+		&i.Tags,
+		&i.Version,
+		&i.UpdatedAt,
+		&i.PayAt,
+	)
+	return i, err
+}
+
+const updateOrderStatusIfNew = `-- name: UpdateOrderStatusIfNew :one
 UPDATE orders
-SET status = $2
+SET status = $2, payment_failure_reason = $3, fee_amount = $4, payment_failure_code = $5, version = version + 1, updated_at = now()
 WHERE order_id = $1 AND status = 'NEW'
+    RETURNING user_id, amount, paid_amount, status
 `
 
 type UpdateOrderStatusIfNewParams struct {
-	OrderID pgtype.UUID `json:"order_id"`
-	Status  string      `json:"status"`
+	OrderID              pgtype.UUID `json:"order_id"`
+	Status               string      `json:"status"`
+	PaymentFailureReason pgtype.Text `json:"payment_failure_reason"`
+	FeeAmount            int64       `json:"fee_amount"`
+	PaymentFailureCode   pgtype.Text `json:"payment_failure_code"`
+}
+
+type UpdateOrderStatusIfNewRow struct {
+	UserID     string `json:"user_id"`
+	Amount     int64  `json:"amount"`
+	PaidAmount int64  `json:"paid_amount"`
+	Status     string `json:"status"`
 }
 
 // Важно для consumer: обновляем статус только если он ещё NEW (идемпотентно)
-func (q *Queries) UpdateOrderStatusIfNew(ctx context.Context, arg UpdateOrderStatusIfNewParams) error {
-	_, err := q.db.Exec(ctx, updateOrderStatusIfNew, arg.OrderID, arg.Status)
-	return err
+func (q *Queries) UpdateOrderStatusIfNew(ctx context.Context, arg UpdateOrderStatusIfNewParams) (UpdateOrderStatusIfNewRow, error) {
+	row := q.db.QueryRow(ctx, updateOrderStatusIfNew,
+		arg.OrderID,
+		arg.Status,
+		arg.PaymentFailureReason,
+		arg.FeeAmount,
+		arg.PaymentFailureCode,
+	)
+	var i UpdateOrderStatusIfNewRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Amount,
+		&i.PaidAmount,
+		&i.Status,
+	)
+	return i, err
 }
@@ -0,0 +1,16 @@
+package repo
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/order-service/internal/repo/postgres/db"
+)
+
+// OrdersRepository is the storage the gRPC handlers depend on. The Postgres
+// implementation is postgres.Repo.
+type OrdersRepository interface {
+	// Read runs read-only queries, on a replica when one is configured.
+	Read(ctx context.Context, fn func(q db.Querier) error) error
+	// InTx runs fn in a single transaction that commits when fn returns nil.
+	InTx(ctx context.Context, fn func(q db.Querier) error) error
+}
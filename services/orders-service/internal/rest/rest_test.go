@@ -0,0 +1,121 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+type fakeOrders struct {
+	ordersv1.UnimplementedOrdersServiceServer
+	auth, serviceToken string
+}
+
+func (f *fakeOrders) GetOrder(ctx context.Context, req *ordersv1.GetOrderRequest) (*ordersv1.GetOrderResponse, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if v := md.Get("authorization"); len(v) > 0 {
+		f.auth = v[0]
+	}
+	if v := md.Get(svcauth.MetadataKey); len(v) > 0 {
+		f.serviceToken = v[0]
+	}
+	if req.GetOrderId() != "o-1" {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &ordersv1.GetOrderResponse{Order: &ordersv1.Order{
+		OrderId:  "o-1",
+		UserId:   req.GetUserId(),
+		Amount:   500,
+		Currency: "RUB",
+		Status:   ordersv1.OrderStatus_ORDER_STATUS_NEW,
+	}}, nil
+}
+
+func newTestServer(t *testing.T, srv ordersv1.OrdersServiceServer) http.Handler {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	ordersv1.RegisterOrdersServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	h, err := NewHandler(ctx, lis.Addr().String())
+	if err != nil {
+		t.Fatalf("NewHandler() error: %v", err)
+	}
+	return h
+}
+
+func get(t *testing.T, h http.Handler, path string) (int, string) {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set("Authorization", "Bearer t")
+	req.Header.Set(svcauth.HTTPHeader, "gw-secret")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	body, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(body)
+}
+
+func TestGetOrder(t *testing.T) {
+	orders := &fakeOrders{}
+	h := newTestServer(t, orders)
+
+	code, body := get(t, h, "/v1/users/u-1/orders/o-1")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", code, body)
+	}
+	for _, want := range []string{`"order_id":"o-1"`, `"user_id":"u-1"`, `"amount":"500"`, `"currency":"RUB"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body %s does not contain %s", body, want)
+		}
+	}
+	if orders.auth != "Bearer t" {
+		t.Fatalf("forwarded authorization = %q, want %q", orders.auth, "Bearer t")
+	}
+	if orders.serviceToken != "gw-secret" {
+		t.Fatalf("forwarded service token = %q, want the caller's", orders.serviceToken)
+	}
+
+	code, body = get(t, h, "/v1/users/u-1/orders/missing")
+	if code != http.StatusNotFound || body != `{"user_id":"u-1","error":"order not found","code":"not_found"}`+"\n" {
+		t.Fatalf("missing order: status = %d, body = %s", code, body)
+	}
+
+	code, _ = get(t, h, "/v1/nowhere")
+	if code != http.StatusNotFound {
+		t.Fatalf("unknown route: status = %d, want 404", code)
+	}
+}
+
+func TestLoopback(t *testing.T) {
+	tests := map[string]string{
+		":9001":          "localhost:9001",
+		"0.0.0.0:9001":   "localhost:9001",
+		"10.0.0.5:9001":  "10.0.0.5:9001",
+		"orders:9001":    "orders:9001",
+		"not-an-address": "not-an-address",
+	}
+	for in, want := range tests {
+		if got := loopback(in); got != want {
+			t.Fatalf("loopback(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Package statusbus tells waiters that an order's status has changed.
+//
+// Changes are announced by Postgres: migration 0014_order_status_notify
+// fires NOTIFY on Channel with the order id whenever orders.status changes,
+// so a replica hears about updates committed by any other one, including
+// those made by the Kafka consumers.
+package statusbus
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel is the Postgres NOTIFY channel of status changes.
+const Channel = "order_status_changed"
+
+// listenRetry is the pause before re-establishing a failed LISTEN connection.
+const listenRetry = 5 * time.Second
+
+// Bus fans status notifications out to the waiters of each order. The zero
+// value is not usable; see New.
+type Bus struct {
+	pool *pgxpool.Pool
+
+	mu      sync.Mutex
+	waiters map[string]map[chan struct{}]struct{}
+	done    chan struct{}
+}
+
+func New(pool *pgxpool.Pool) *Bus {
+	return &Bus{pool: pool, waiters: make(map[string]map[chan struct{}]struct{}), done: make(chan struct{})}
+}
+
+// Subscribe returns a channel that receives a value after each change of the
+// order's status, coalescing changes the waiter has not consumed yet.
+// Subscribe before reading the status, so a change in between is not lost.
+// cancel must be called once the waiter is done.
+func (b *Bus) Subscribe(orderID string) (changed <-chan struct{}, cancel func()) {
+	ch := make(chan struct{}, 1)
+	b.mu.Lock()
+	set, ok := b.waiters[orderID]
+	if !ok {
+		set = make(map[chan struct{}]struct{})
+		b.waiters[orderID] = set
+	}
+	set[ch] = struct{}{}
+	b.mu.Unlock()
+
+	return ch, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.waiters[orderID], ch)
+		if len(b.waiters[orderID]) == 0 {
+			delete(b.waiters, orderID)
+		}
+	}
+}
+
+// Done is closed once Run has returned; waiters should answer with what they
+// have instead of holding calls the bus can no longer wake.
+func (b *Bus) Done() <-chan struct{} {
+	return b.done
+}
+
+// Publish wakes the waiters of orderID.
+func (b *Bus) Publish(orderID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.waiters[orderID] {
+		wake(ch)
+	}
+}
+
+// publishAll wakes every waiter, so that each re-reads its order after
+// notifications may have been missed.
+func (b *Bus) publishAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, set := range b.waiters {
+		for ch := range set {
+			wake(ch)
+		}
+	}
+}
+
+func wake(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
+
+// Run keeps a LISTEN connection open until ctx is done, reconnecting after
+// failures. Waiters are only woken by their timeouts while it is down.
+func (b *Bus) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "statusbus")
+	defer close(b.done)
+	for {
+		err := b.listenOnce(ctx)
+		if ctx.Err() != nil {
+			logger.Info("status bus stopped")
+			return nil
+		}
+		logger.Warn("status bus listener failed", "err", err, "retry_in", listenRetry)
+		select {
+		case <-ctx.Done():
+			logger.Info("status bus stopped")
+			return nil
+		case <-time.After(listenRetry):
+		}
+	}
+}
+
+func (b *Bus) listenOnce(ctx context.Context) error {
+	logger := slog.Default().With("service", "orders-service", "component", "statusbus")
+	pooled, err := b.pool.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// A LISTENing connection must not be handed to other queries, so it is
+	// taken out of the pool for good.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+Channel); err != nil {
+		return err
+	}
+	logger.Info("status bus listening", "channel", Channel)
+	// Changes committed while the listener was down were not announced.
+	b.publishAll()
+	for {
+		n, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		b.Publish(n.Payload)
+	}
+}
@@ -0,0 +1,37 @@
+package statusbus
+
+import "testing"
+
+func TestSubscribePublish(t *testing.T) {
+	b := New(nil)
+	a, cancelA := b.Subscribe("o-1")
+	other, cancelOther := b.Subscribe("o-2")
+	defer cancelOther()
+
+	// Changes the waiter has not consumed yet are coalesced.
+	b.Publish("o-1")
+	b.Publish("o-1")
+	select {
+	case <-a:
+	default:
+		t.Fatal("subscriber of o-1 not woken")
+	}
+	select {
+	case <-a:
+		t.Fatal("pending changes not coalesced")
+	case <-other:
+		t.Fatal("subscriber of o-2 woken by o-1")
+	default:
+	}
+
+	b.publishAll()
+	if len(a) != 1 || len(other) != 1 {
+		t.Fatal("publishAll must wake every waiter")
+	}
+
+	cancelA()
+	if _, ok := b.waiters["o-1"]; ok {
+		t.Fatal("waiters of o-1 kept after the last cancel")
+	}
+	b.Publish("o-1")
+}
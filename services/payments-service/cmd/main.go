@@ -9,18 +9,28 @@ import (
 
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/app"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
 )
 
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo})).
-		With("service", "payments-service")
-	slog.SetDefault(logger)
-
 	cfg := config.MustLoad()
 
+	logger, level, err := logging.NewLogger("payments-service", cfg.LogLevel, cfg.LogFormat, logging.Redaction{
+		Mode:   cfg.LogRedact,
+		Fields: cfg.LogRedactFields,
+		Salt:   cfg.LogRedactSalt,
+	}, cfg.LogDev)
+	if err != nil {
+		slog.Error("invalid log config", "err", err)
+		os.Exit(1)
+	}
+	slog.SetDefault(logger)
+
 	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 
+	go logging.WatchLogLevel(ctx, "payments-service", level)
+
 	if err := app.Run(ctx, cfg); err != nil {
 		slog.Error("payments service stopped with error", "err", err)
 		os.Exit(1)
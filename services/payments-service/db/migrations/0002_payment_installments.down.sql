@@ -0,0 +1,8 @@
+-- Fails once an order has been paid in more than one installment.
+DROP INDEX IF EXISTS account_ops_order_idx;
+DROP INDEX IF EXISTS account_ops_payment_idx;
+
+ALTER TABLE account_ops DROP COLUMN IF EXISTS payment_id;
+ALTER TABLE account_ops ADD CONSTRAINT account_ops_pkey PRIMARY KEY (order_id);
+
+ALTER TABLE inbox ADD CONSTRAINT inbox_order_id_key UNIQUE (order_id);
@@ -0,0 +1,13 @@
+-- An order may now be paid in several installments, each with its own payment_id.
+ALTER TABLE inbox DROP CONSTRAINT IF EXISTS inbox_order_id_key;
+
+ALTER TABLE account_ops
+    ADD COLUMN IF NOT EXISTS payment_id uuid;
+UPDATE account_ops SET payment_id = order_id WHERE payment_id IS NULL;
+ALTER TABLE account_ops ALTER COLUMN payment_id SET NOT NULL;
+ALTER TABLE account_ops DROP CONSTRAINT IF EXISTS account_ops_pkey;
+
+CREATE UNIQUE INDEX IF NOT EXISTS account_ops_payment_idx
+    ON account_ops (payment_id);
+CREATE INDEX IF NOT EXISTS account_ops_order_idx
+    ON account_ops (order_id);
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS topup_events_user_created_idx;
+DROP TABLE IF EXISTS topup_events;
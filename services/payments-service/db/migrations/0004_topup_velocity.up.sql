@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS topup_events (
+    id bigserial PRIMARY KEY,
+    user_id text NOT NULL,
+    amount bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS topup_events_user_created_idx ON topup_events (user_id, created_at);
@@ -0,0 +1,26 @@
+-- Every balance change, so the balance can be reconstructed at any moment.
+CREATE TABLE IF NOT EXISTS balance_ledger (
+    id bigserial PRIMARY KEY,
+    user_id text NOT NULL,
+    delta bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_created_idx
+    ON balance_ledger (user_id, created_at);
+
+-- History starts here: existing balances become opening entries.
+INSERT INTO balance_ledger (user_id, delta)
+SELECT user_id, balance
+FROM accounts
+WHERE balance <> 0
+  AND NOT EXISTS (SELECT 1 FROM balance_ledger);
+
+-- Daily balances materialized from the ledger; GetBalanceAt starts from the
+-- latest snapshot and adds the deltas after it.
+CREATE TABLE IF NOT EXISTS balance_snapshots (
+    user_id text NOT NULL,
+    snapshot_at timestamptz NOT NULL,
+    balance bigint NOT NULL,
+    PRIMARY KEY (user_id, snapshot_at)
+    );
@@ -0,0 +1,15 @@
+-- Wakes the outbox publisher as soon as a transaction that wrote to the
+-- outbox commits. One notification per statement; Postgres also folds
+-- identical notifications within a transaction.
+CREATE OR REPLACE FUNCTION notify_outbox_inserted() RETURNS trigger AS $$
+BEGIN
+    PERFORM pg_notify('outbox_inserted', '');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS outbox_inserted_notify ON outbox;
+CREATE TRIGGER outbox_inserted_notify
+    AFTER INSERT ON outbox
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION notify_outbox_inserted();
@@ -0,0 +1,3 @@
+-- topup_idempotency was kept but not written since; keys taken in between
+-- are lost.
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,3 @@
+-- Account version for optimistic concurrency: every balance change bumps it,
+-- and a top-up may require the version it was based on.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS version bigint NOT NULL DEFAULT 1;
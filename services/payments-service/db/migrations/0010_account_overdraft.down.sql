@@ -0,0 +1,5 @@
+-- Fails while an account is still in overdraft.
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= 0);
+
+ALTER TABLE accounts DROP COLUMN IF EXISTS overdraft_limit;
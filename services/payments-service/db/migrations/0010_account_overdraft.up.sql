@@ -0,0 +1,6 @@
+-- Per-account overdraft: deductions may take the balance down to
+-- -overdraft_limit. The default keeps consumer accounts at balance >= 0.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS overdraft_limit bigint NOT NULL DEFAULT 0 CHECK (overdraft_limit >= 0);
+
+ALTER TABLE accounts DROP CONSTRAINT IF EXISTS accounts_balance_check;
+ALTER TABLE accounts ADD CONSTRAINT accounts_balance_check CHECK (balance >= -overdraft_limit);
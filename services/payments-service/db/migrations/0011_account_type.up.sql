@@ -0,0 +1,4 @@
+-- Account tiers. Per-type limits, overdraft and fees live in the service
+-- config (internal/policy); the column only records which one applies.
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS account_type text NOT NULL DEFAULT 'BASIC'
+    CHECK (account_type IN ('BASIC', 'PREMIUM', 'BUSINESS'));
@@ -0,0 +1,4 @@
+-- Fee entries stay in the ledger as plain balance changes.
+ALTER TABLE balance_ledger DROP COLUMN IF EXISTS payment_id;
+ALTER TABLE balance_ledger DROP COLUMN IF EXISTS kind;
+ALTER TABLE account_ops DROP COLUMN IF EXISTS fee;
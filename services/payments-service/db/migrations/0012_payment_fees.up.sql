@@ -0,0 +1,7 @@
+-- Payment fees. The fee is deducted with the payment but booked as its own
+-- ledger entry, linked to the payment it was charged for.
+ALTER TABLE account_ops ADD COLUMN IF NOT EXISTS fee bigint NOT NULL DEFAULT 0 CHECK (fee >= 0);
+
+ALTER TABLE balance_ledger ADD COLUMN IF NOT EXISTS kind text NOT NULL DEFAULT 'balance'
+    CHECK (kind IN ('balance', 'fee'));
+ALTER TABLE balance_ledger ADD COLUMN IF NOT EXISTS payment_id uuid NULL;
@@ -0,0 +1,9 @@
+-- Bonus entries never moved the main balance.
+DELETE FROM balance_ledger WHERE kind = 'bonus';
+ALTER TABLE balance_ledger DROP CONSTRAINT IF EXISTS balance_ledger_kind_check;
+ALTER TABLE balance_ledger ADD CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee'));
+
+ALTER TABLE account_ops DROP COLUMN IF EXISTS bonus;
+
+DROP INDEX IF EXISTS bonus_grants_user_active_idx;
+DROP TABLE IF EXISTS bonus_grants;
@@ -0,0 +1,22 @@
+-- Promotional credits. Payments spend active grants, earliest expiry first,
+-- before the main balance; what is left of a grant after expires_at is
+-- simply no longer counted.
+CREATE TABLE IF NOT EXISTS bonus_grants (
+    id bigserial PRIMARY KEY,
+    user_id text NOT NULL REFERENCES accounts (user_id),
+    amount bigint NOT NULL CHECK (amount > 0),
+    remaining bigint NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+    expires_at timestamptz NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS bonus_grants_user_active_idx
+    ON bonus_grants (user_id, expires_at) WHERE remaining > 0;
+
+-- The bonus part of each payment, next to its delta and fee.
+ALTER TABLE account_ops ADD COLUMN IF NOT EXISTS bonus bigint NOT NULL DEFAULT 0 CHECK (bonus >= 0);
+
+-- Grants and bonus spending are ledger entries of their own kind; the main
+-- balance is reconstructed from the other kinds.
+ALTER TABLE balance_ledger DROP CONSTRAINT IF EXISTS balance_ledger_kind_check;
+ALTER TABLE balance_ledger ADD CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus'));
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS balance_ledger_user_id_idx;
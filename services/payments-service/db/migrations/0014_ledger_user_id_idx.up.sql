@@ -0,0 +1,3 @@
+-- ListTransactions pages through an account's ledger by id.
+CREATE INDEX IF NOT EXISTS balance_ledger_user_id_idx
+    ON balance_ledger (user_id, id DESC);
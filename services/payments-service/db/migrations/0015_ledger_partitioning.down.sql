@@ -0,0 +1,29 @@
+-- The monthly partitions are dropped with the partitioned table.
+ALTER TABLE balance_ledger RENAME TO balance_ledger_partitioned;
+ALTER TABLE balance_ledger_partitioned DROP CONSTRAINT balance_ledger_pkey;
+DROP INDEX IF EXISTS balance_ledger_user_created_idx;
+DROP INDEX IF EXISTS balance_ledger_user_id_idx;
+ALTER SEQUENCE balance_ledger_id_seq OWNED BY NONE;
+
+CREATE TABLE balance_ledger (
+    id bigint PRIMARY KEY DEFAULT nextval('balance_ledger_id_seq'),
+    user_id text NOT NULL,
+    delta bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    kind text NOT NULL DEFAULT 'balance' CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus')),
+    payment_id uuid NULL
+    );
+
+ALTER SEQUENCE balance_ledger_id_seq OWNED BY balance_ledger.id;
+
+INSERT INTO balance_ledger (id, user_id, delta, created_at, kind, payment_id)
+SELECT id, user_id, delta, created_at, kind, payment_id
+FROM balance_ledger_partitioned;
+
+DROP TABLE balance_ledger_partitioned;
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_created_idx
+    ON balance_ledger (user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_id_idx
+    ON balance_ledger (user_id, id DESC);
@@ -0,0 +1,47 @@
+-- balance_ledger becomes range-partitioned by month on created_at, with
+-- partitions named balance_ledger_pYYYYMM kept up by the partition
+-- maintainer (pkg/partition). The primary key must contain the partition key,
+-- so it is (id, created_at); ids still come from the same sequence.
+ALTER TABLE balance_ledger RENAME TO balance_ledger_unpartitioned;
+ALTER SEQUENCE balance_ledger_id_seq OWNED BY NONE;
+
+CREATE TABLE balance_ledger (
+    id bigint NOT NULL DEFAULT nextval('balance_ledger_id_seq'),
+    user_id text NOT NULL,
+    delta bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    kind text NOT NULL DEFAULT 'balance' CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus')),
+    payment_id uuid NULL
+    ) PARTITION BY RANGE (created_at);
+
+ALTER SEQUENCE balance_ledger_id_seq OWNED BY balance_ledger.id;
+
+-- Every month with entries and the next two get a partition of their own.
+-- balance_ledger_default catches anything outside of them.
+DO $$
+DECLARE
+    m timestamp := date_trunc('month', COALESCE((SELECT min(created_at) FROM balance_ledger_unpartitioned), now()) AT TIME ZONE 'UTC');
+    until_month timestamp := date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months';
+BEGIN
+    WHILE m <= until_month LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF balance_ledger FOR VALUES FROM (%L) TO (%L)',
+                       'balance_ledger_p' || to_char(m, 'YYYYMM'), m AT TIME ZONE 'UTC', (m + interval '1 month') AT TIME ZONE 'UTC');
+        m := m + interval '1 month';
+    END LOOP;
+    EXECUTE 'CREATE TABLE IF NOT EXISTS balance_ledger_default PARTITION OF balance_ledger DEFAULT';
+END $$;
+
+INSERT INTO balance_ledger (id, user_id, delta, created_at, kind, payment_id)
+SELECT id, user_id, delta, created_at, kind, payment_id
+FROM balance_ledger_unpartitioned;
+
+-- Drops the old indexes along with the table.
+DROP TABLE balance_ledger_unpartitioned;
+
+ALTER TABLE balance_ledger ADD CONSTRAINT balance_ledger_pkey PRIMARY KEY (id, created_at);
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_created_idx
+    ON balance_ledger (user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_id_idx
+    ON balance_ledger (user_id, id DESC);
@@ -0,0 +1,2 @@
+ALTER TABLE inbox DROP COLUMN IF EXISTS correlation_id;
+ALTER TABLE outbox DROP COLUMN IF EXISTS correlation_id;
@@ -0,0 +1,7 @@
+-- Adjustments moved the main balance, so they stay as plain balance entries.
+UPDATE balance_ledger SET kind = 'balance' WHERE kind = 'adjustment';
+ALTER TABLE balance_ledger DROP CONSTRAINT IF EXISTS balance_ledger_kind_check;
+ALTER TABLE balance_ledger ADD CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus'));
+
+DROP INDEX IF EXISTS admin_audit_log_target_idx;
+DROP TABLE IF EXISTS admin_audit_log;
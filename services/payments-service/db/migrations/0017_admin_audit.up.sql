@@ -0,0 +1,18 @@
+-- Changes made by operators through the admin RPCs that bypass the normal
+-- flow, e.g. AdjustBalance. target is what was changed (a user id).
+CREATE TABLE IF NOT EXISTS admin_audit_log (
+    id bigserial PRIMARY KEY,
+    operator text NOT NULL,
+    action text NOT NULL,
+    target text NOT NULL,
+    reason text NOT NULL,
+    details jsonb NOT NULL DEFAULT '{}'::jsonb,
+    created_at timestamptz NOT NULL DEFAULT now()
+    );
+
+CREATE INDEX IF NOT EXISTS admin_audit_log_target_idx
+    ON admin_audit_log (target, id);
+
+-- AdjustBalance entries move the main balance like top-ups and payments.
+ALTER TABLE balance_ledger DROP CONSTRAINT IF EXISTS balance_ledger_kind_check;
+ALTER TABLE balance_ledger ADD CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus', 'adjustment'));
@@ -0,0 +1,12 @@
+-- Unprocessed claims never committed any effects.
+DELETE FROM inbox WHERE processed_at IS NULL;
+
+ALTER TABLE inbox DROP CONSTRAINT IF EXISTS inbox_pkey;
+ALTER TABLE inbox ADD CONSTRAINT inbox_pkey PRIMARY KEY (message_id);
+
+ALTER TABLE inbox ALTER COLUMN processed_at SET DEFAULT now();
+ALTER TABLE inbox ALTER COLUMN processed_at SET NOT NULL;
+ALTER TABLE inbox DROP COLUMN IF EXISTS received_at;
+ALTER TABLE inbox DROP COLUMN IF EXISTS consumer;
+-- The order ids of existing rows are gone.
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS order_id uuid NULL;
@@ -0,0 +1,18 @@
+-- The inbox follows pkg/inbox: rows are keyed by consumer, and a claim
+-- stays unprocessed (processed_at NULL) until the consumer marks it done.
+-- Existing rows belong to the only consumer so far. order_id was only
+-- written, never read.
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS consumer text NOT NULL DEFAULT 'payment_requested';
+ALTER TABLE inbox ALTER COLUMN consumer DROP DEFAULT;
+
+ALTER TABLE inbox ADD COLUMN IF NOT EXISTS received_at timestamptz;
+UPDATE inbox SET received_at = processed_at WHERE received_at IS NULL;
+ALTER TABLE inbox ALTER COLUMN received_at SET DEFAULT now();
+ALTER TABLE inbox ALTER COLUMN received_at SET NOT NULL;
+ALTER TABLE inbox ALTER COLUMN processed_at DROP NOT NULL;
+ALTER TABLE inbox ALTER COLUMN processed_at DROP DEFAULT;
+
+ALTER TABLE inbox DROP COLUMN IF EXISTS order_id;
+
+ALTER TABLE inbox DROP CONSTRAINT IF EXISTS inbox_pkey;
+ALTER TABLE inbox ADD CONSTRAINT inbox_pkey PRIMARY KEY (consumer, message_id);
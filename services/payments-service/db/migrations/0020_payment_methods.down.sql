@@ -0,0 +1 @@
+ALTER TABLE account_ops DROP COLUMN IF EXISTS method;
@@ -0,0 +1,4 @@
+-- The provider that paid each operation beyond its bonus part. Only
+-- 'balance' operations move the main balance; the others are paid outside
+-- of it and are recorded for idempotency and reporting.
+ALTER TABLE account_ops ADD COLUMN IF NOT EXISTS method text NOT NULL DEFAULT 'balance';
@@ -0,0 +1,27 @@
+-- Payments handed to an external payment service provider (PSP) whose
+-- outcome arrives later, by webhook or by polling. The bonus part is held
+-- when the charge is created: the spends are kept here to give them back if
+-- the charge fails. The PaymentResult is published when status leaves
+-- 'pending'.
+CREATE TABLE IF NOT EXISTS external_charges (
+    payment_id uuid PRIMARY KEY,
+    order_id uuid NOT NULL,
+    user_id text NOT NULL,
+    method text NOT NULL,
+    amount bigint NOT NULL CHECK (amount > 0),
+    bonus bigint NOT NULL DEFAULT 0 CHECK (bonus >= 0),
+    fee bigint NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    bonus_grant_ids bigint[] NOT NULL DEFAULT '{}',
+    bonus_grant_amounts bigint[] NOT NULL DEFAULT '{}',
+    correlation_id text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'succeeded', 'failed')),
+    provider_charge_id text NULL UNIQUE,
+    failure_reason text NULL,
+    attempts int NOT NULL DEFAULT 0,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    checked_at timestamptz NULL,
+    finished_at timestamptz NULL
+    );
+
+CREATE INDEX IF NOT EXISTS external_charges_pending_idx
+    ON external_charges (created_at) WHERE status = 'pending';
@@ -0,0 +1,25 @@
+-- Payments that wait for the payer's confirmation (3-D Secure and the
+-- like). The bonus part is held when the challenge is created; the spends
+-- are kept here to give them back if it expires.
+CREATE TABLE IF NOT EXISTS payment_challenges (
+    payment_id uuid PRIMARY KEY,
+    order_id uuid NOT NULL,
+    user_id text NOT NULL,
+    method text NOT NULL,
+    amount bigint NOT NULL CHECK (amount > 0),
+    bonus bigint NOT NULL DEFAULT 0 CHECK (bonus >= 0),
+    fee bigint NOT NULL DEFAULT 0 CHECK (fee >= 0),
+    bonus_grant_ids bigint[] NOT NULL DEFAULT '{}',
+    bonus_grant_amounts bigint[] NOT NULL DEFAULT '{}',
+    correlation_id text NOT NULL DEFAULT '',
+    status text NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed', 'expired')),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    expires_at timestamptz NOT NULL,
+    finished_at timestamptz NULL
+    );
+
+CREATE INDEX IF NOT EXISTS payment_challenges_order_idx
+    ON payment_challenges (order_id) WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS payment_challenges_expiry_idx
+    ON payment_challenges (expires_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS payment_disputes;
+ALTER TABLE account_ops DROP COLUMN IF EXISTS frozen;
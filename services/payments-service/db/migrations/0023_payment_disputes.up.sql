@@ -0,0 +1,17 @@
+-- Disputes (chargebacks) of orders, opened and resolved by operators; an
+-- order is disputed at most once. Opening one freezes the order's
+-- operations; upholding it unfreezes them, while a refund credits amount
+-- back to the balance and leaves them frozen, so they are never refunded
+-- twice.
+ALTER TABLE account_ops ADD COLUMN IF NOT EXISTS frozen boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS payment_disputes (
+    order_id uuid PRIMARY KEY,
+    user_id text NOT NULL,
+    amount bigint NOT NULL CHECK (amount >= 0),
+    status text NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'refunded', 'upheld')),
+    reason text NOT NULL,
+    resolution_reason text NULL,
+    opened_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz NULL
+    );
@@ -0,0 +1,47 @@
+-- Back to balance_ledger: one row per posting to a user's account, with the
+-- posting's id. Entries between system accounts are lost.
+CREATE SEQUENCE IF NOT EXISTS balance_ledger_id_seq;
+CREATE TABLE balance_ledger (
+    id bigint NOT NULL DEFAULT nextval('balance_ledger_id_seq'),
+    user_id text NOT NULL,
+    delta bigint NOT NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    kind text NOT NULL DEFAULT 'balance' CONSTRAINT balance_ledger_kind_check CHECK (kind IN ('balance', 'fee', 'bonus', 'adjustment')),
+    payment_id uuid NULL,
+    CONSTRAINT balance_ledger_pkey PRIMARY KEY (id, created_at)
+    ) PARTITION BY RANGE (created_at);
+ALTER SEQUENCE balance_ledger_id_seq OWNED BY balance_ledger.id;
+
+DO $$
+DECLARE
+    m timestamp := date_trunc('month', COALESCE((SELECT min(created_at) FROM postings), now()) AT TIME ZONE 'UTC');
+    until_month timestamp := date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months';
+BEGIN
+    WHILE m <= until_month LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF balance_ledger FOR VALUES FROM (%L) TO (%L)',
+                       'balance_ledger_p' || to_char(m, 'YYYYMM'), m AT TIME ZONE 'UTC', (m + interval '1 month') AT TIME ZONE 'UTC');
+        m := m + interval '1 month';
+    END LOOP;
+    EXECUTE 'CREATE TABLE IF NOT EXISTS balance_ledger_default PARTITION OF balance_ledger DEFAULT';
+END $$;
+
+INSERT INTO balance_ledger (id, user_id, delta, created_at, kind, payment_id)
+SELECT p.id, a.user_id, p.amount, p.created_at, e.kind, e.payment_id
+FROM postings p
+JOIN ledger_accounts a ON a.code = p.account
+JOIN journal_entries e ON e.id = p.entry_id AND e.created_at = p.created_at
+WHERE a.user_id IS NOT NULL;
+
+SELECT setval('balance_ledger_id_seq', GREATEST((SELECT max(id) FROM postings), 1));
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_created_idx
+    ON balance_ledger (user_id, created_at);
+
+CREATE INDEX IF NOT EXISTS balance_ledger_user_id_idx
+    ON balance_ledger (user_id, id DESC);
+
+DROP TABLE IF EXISTS postings;
+DROP TABLE IF EXISTS journal_entries;
+DROP TRIGGER IF EXISTS accounts_open_ledger_accounts ON accounts;
+DROP FUNCTION IF EXISTS open_ledger_accounts();
+DROP TABLE IF EXISTS ledger_accounts;
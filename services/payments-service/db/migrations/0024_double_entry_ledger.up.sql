@@ -0,0 +1,137 @@
+-- Double-entry ledger. Every money movement is a journal entry with postings
+-- that move it between ledger accounts and sum to zero. Each payment account
+-- has a balance and a bonus ledger account; the system accounts are the
+-- other side: money from outside (funding), what was paid for orders, fees,
+-- promotions, operator corrections and external payment methods.
+--
+-- accounts.balance stays the materialized balance of the user's balance
+-- account, updated in the statement that books the entry, and
+-- bonus_grants.remaining that of the bonus account, less what expired. The
+-- entries replace balance_ledger: its rows become entries with the same ids.
+CREATE TABLE IF NOT EXISTS ledger_accounts (
+    code text PRIMARY KEY,
+    type text NOT NULL CHECK (type IN ('balance', 'bonus', 'system')),
+    user_id text NULL REFERENCES accounts (user_id),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT ledger_accounts_owner_check CHECK ((type = 'system') = (user_id IS NULL))
+    );
+
+INSERT INTO ledger_accounts (code, type)
+VALUES ('system:funding', 'system'),
+       ('system:orders', 'system'),
+       ('system:fees', 'system'),
+       ('system:promotions', 'system'),
+       ('system:adjustments', 'system'),
+       ('system:external', 'system')
+    ON CONFLICT (code) DO NOTHING;
+
+INSERT INTO ledger_accounts (code, type, user_id)
+SELECT 'balance:' || user_id, 'balance', user_id FROM accounts
+UNION ALL
+SELECT 'bonus:' || user_id, 'bonus', user_id FROM accounts
+    ON CONFLICT (code) DO NOTHING;
+
+-- A new payment account gets its ledger accounts with it.
+CREATE OR REPLACE FUNCTION open_ledger_accounts() RETURNS trigger AS $$
+BEGIN
+    INSERT INTO ledger_accounts (code, type, user_id)
+    VALUES ('balance:' || NEW.user_id, 'balance', NEW.user_id),
+           ('bonus:' || NEW.user_id, 'bonus', NEW.user_id)
+        ON CONFLICT (code) DO NOTHING;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS accounts_open_ledger_accounts ON accounts;
+CREATE TRIGGER accounts_open_ledger_accounts
+    AFTER INSERT ON accounts
+    FOR EACH ROW
+    EXECUTE FUNCTION open_ledger_accounts();
+
+-- kind is what the entry was for and the kind of the statement line of its
+-- user's posting: 'external' entries move money between system accounts
+-- only. Writers book each entry as legs of (from, to, amount) and post
+-- -amount to from and amount to to, so the postings of an entry always
+-- balance.
+CREATE SEQUENCE IF NOT EXISTS journal_entries_id_seq;
+CREATE TABLE journal_entries (
+    id bigint NOT NULL DEFAULT nextval('journal_entries_id_seq'),
+    kind text NOT NULL CONSTRAINT journal_entries_kind_check CHECK (kind IN ('balance', 'fee', 'bonus', 'adjustment', 'external')),
+    user_id text NOT NULL,
+    payment_id uuid NULL,
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT journal_entries_pkey PRIMARY KEY (id, created_at)
+    ) PARTITION BY RANGE (created_at);
+ALTER SEQUENCE journal_entries_id_seq OWNED BY journal_entries.id;
+
+-- A posting has the created_at of its entry.
+CREATE SEQUENCE IF NOT EXISTS postings_id_seq;
+CREATE TABLE postings (
+    id bigint NOT NULL DEFAULT nextval('postings_id_seq'),
+    entry_id bigint NOT NULL,
+    account text NOT NULL REFERENCES ledger_accounts (code),
+    amount bigint NOT NULL CHECK (amount <> 0),
+    created_at timestamptz NOT NULL DEFAULT now(),
+    CONSTRAINT postings_pkey PRIMARY KEY (id, created_at)
+    ) PARTITION BY RANGE (created_at);
+ALTER SEQUENCE postings_id_seq OWNED BY postings.id;
+
+-- Monthly partitions as for balance_ledger in 0015_ledger_partitioning,
+-- kept up by the partition maintainer.
+DO $$
+DECLARE
+    first_month timestamp := date_trunc('month', COALESCE((SELECT min(created_at) FROM balance_ledger), now()) AT TIME ZONE 'UTC');
+    until_month timestamp := date_trunc('month', now() AT TIME ZONE 'UTC') + interval '2 months';
+    m timestamp;
+    t text;
+BEGIN
+    FOREACH t IN ARRAY ARRAY['journal_entries', 'postings'] LOOP
+        m := first_month;
+        WHILE m <= until_month LOOP
+            EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I FOR VALUES FROM (%L) TO (%L)',
+                           t || '_p' || to_char(m, 'YYYYMM'), t, m AT TIME ZONE 'UTC', (m + interval '1 month') AT TIME ZONE 'UTC');
+            m := m + interval '1 month';
+        END LOOP;
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF %I DEFAULT', t || '_default', t);
+    END LOOP;
+END $$;
+
+CREATE INDEX IF NOT EXISTS postings_account_created_idx
+    ON postings (account, created_at);
+
+CREATE INDEX IF NOT EXISTS postings_account_id_idx
+    ON postings (account, id DESC);
+
+CREATE INDEX IF NOT EXISTS postings_entry_idx
+    ON postings (entry_id);
+
+-- balance_ledger rows become entries with their ids. The user's posting
+-- keeps the id too, so statement pages stay valid; the other side goes to
+-- the system account the writers use for that kind of entry.
+INSERT INTO journal_entries (id, kind, user_id, payment_id, created_at)
+SELECT id, kind, user_id, payment_id, created_at
+FROM balance_ledger;
+
+INSERT INTO postings (id, entry_id, account, amount, created_at)
+SELECT id, id, CASE WHEN kind = 'bonus' THEN 'bonus:' ELSE 'balance:' END || user_id, delta, created_at
+FROM balance_ledger
+WHERE delta <> 0;
+
+SELECT setval('journal_entries_id_seq', GREATEST((SELECT max(id) FROM balance_ledger), 1));
+SELECT setval('postings_id_seq', GREATEST((SELECT max(id) FROM balance_ledger), 1));
+
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT id,
+       CASE
+           WHEN kind = 'fee' THEN 'system:fees'
+           WHEN kind = 'adjustment' THEN 'system:adjustments'
+           WHEN kind = 'bonus' AND delta > 0 THEN 'system:promotions'
+           WHEN delta < 0 OR payment_id IS NOT NULL THEN 'system:orders'
+           ELSE 'system:funding'
+       END,
+       -delta, created_at
+FROM balance_ledger
+WHERE delta <> 0;
+
+-- Drops its partitions and sequence along with it.
+DROP TABLE balance_ledger;
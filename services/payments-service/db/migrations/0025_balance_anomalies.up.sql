@@ -0,0 +1,18 @@
+-- Discrepancies between the stored balance of an account and the sum of the
+-- postings of its balance ledger account, found by the balance checker. An
+-- account has at most one open anomaly, refreshed while the discrepancy
+-- persists and resolved by RematerializeBalance.
+CREATE TABLE IF NOT EXISTS balance_anomalies (
+    id bigserial PRIMARY KEY,
+    user_id text NOT NULL REFERENCES accounts (user_id),
+    stored_balance bigint NOT NULL,
+    ledger_balance bigint NOT NULL,
+    detected_at timestamptz NOT NULL DEFAULT now(),
+    last_seen_at timestamptz NOT NULL DEFAULT now(),
+    resolved_at timestamptz NULL,
+    resolved_by text NULL
+    );
+
+CREATE UNIQUE INDEX IF NOT EXISTS balance_anomalies_open_idx
+    ON balance_anomalies (user_id)
+    WHERE resolved_at IS NULL;
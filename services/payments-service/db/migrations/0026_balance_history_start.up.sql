@@ -0,0 +1,13 @@
+-- The journal only knows balances from 0005_balance_history on: accounts
+-- that existed then got one opening entry with their balance at migration
+-- time. started_at is when the journal starts; GetBalanceAt refuses earlier
+-- moments instead of answering 0 for them.
+CREATE TABLE IF NOT EXISTS balance_history_start (
+    id boolean PRIMARY KEY DEFAULT true CHECK (id),
+    started_at timestamptz NOT NULL
+    );
+
+INSERT INTO balance_history_start (started_at)
+SELECT COALESCE(min(created_at), now())
+FROM journal_entries
+    ON CONFLICT (id) DO NOTHING;
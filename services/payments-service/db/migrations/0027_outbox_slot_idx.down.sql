@@ -0,0 +1 @@
+DROP INDEX IF EXISTS outbox_unsent_slot_idx;
@@ -0,0 +1,13 @@
+-- 0007_idempotency_keys did not carry over topup_idempotency, so a retry
+-- with a key issued before it topped up again. The keys are copied here
+-- without a request hash or response: the library rejects any reuse of them
+-- instead of replaying. topup_idempotency is no longer written, so running
+-- this after 0007 copies the same keys.
+INSERT INTO idempotency_keys (scope, user_id, idempotency_key, request_hash, status, created_at, completed_at)
+SELECT 'payments.TopUp', user_id, idempotency_key, ''::bytea, 'COMPLETED', created_at, created_at
+FROM topup_idempotency
+    ON CONFLICT (scope, user_id, idempotency_key) DO NOTHING;
+
+-- For the retention sweep.
+CREATE INDEX IF NOT EXISTS idempotency_keys_created_idx
+    ON idempotency_keys (created_at);
@@ -0,0 +1,9 @@
+-- Rates seen from the provider, so statements convert each entry at the rate
+-- of its time rather than today's. price is what one unit of currency costs
+-- in the service currency; as_of is when the provider table was taken.
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    currency text NOT NULL,
+    as_of timestamptz NOT NULL,
+    price numeric NOT NULL CHECK (price > 0),
+    PRIMARY KEY (currency, as_of)
+);
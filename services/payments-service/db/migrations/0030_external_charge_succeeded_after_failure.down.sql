@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS external_charges_succeeded_after_failure_idx;
+ALTER TABLE external_charges DROP COLUMN IF EXISTS provider_succeeded_at;
@@ -0,0 +1,11 @@
+-- A charge settled as failed (e.g. canceled at the timeout while the
+-- provider still had it pending) that the provider reports as succeeded
+-- afterwards: the customer paid for a payment the order never got. It is
+-- not settled again; provider_succeeded_at marks it for an operator to
+-- refund at the provider.
+ALTER TABLE external_charges
+    ADD COLUMN IF NOT EXISTS provider_succeeded_at timestamptz NULL;
+
+CREATE INDEX IF NOT EXISTS external_charges_succeeded_after_failure_idx
+    ON external_charges (provider_succeeded_at)
+    WHERE status = 'failed' AND provider_succeeded_at IS NOT NULL;
@@ -0,0 +1,10 @@
+DELETE FROM balance_anomalies WHERE account <> 'balance';
+
+DROP INDEX IF EXISTS balance_anomalies_open_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS balance_anomalies_open_idx
+    ON balance_anomalies (user_id)
+    WHERE resolved_at IS NULL;
+
+ALTER TABLE balance_anomalies DROP COLUMN IF EXISTS account;
+ALTER TABLE balance_snapshots DROP COLUMN IF EXISTS bonus;
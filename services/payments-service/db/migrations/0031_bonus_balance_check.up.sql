@@ -0,0 +1,24 @@
+-- The balance checker also compares the bonus account with its ledger
+-- account. Snapshots carry the ledger bonus balance, so the check does not
+-- depend on partitions dropped by retention; the existing ones are filled
+-- from the postings they cover. An anomaly names the account it was found
+-- on, and each account of a user has at most one open anomaly.
+ALTER TABLE balance_snapshots
+    ADD COLUMN IF NOT EXISTS bonus bigint NOT NULL DEFAULT 0;
+
+UPDATE balance_snapshots bs
+SET bonus = COALESCE((
+    SELECT SUM(p.amount)
+    FROM postings p
+    WHERE p.account = 'bonus:' || bs.user_id
+      AND p.created_at <= bs.snapshot_at
+), 0);
+
+ALTER TABLE balance_anomalies
+    ADD COLUMN IF NOT EXISTS account text NOT NULL DEFAULT 'balance' CHECK (account IN ('balance', 'bonus'));
+
+DROP INDEX IF EXISTS balance_anomalies_open_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS balance_anomalies_open_idx
+    ON balance_anomalies (user_id, account)
+    WHERE resolved_at IS NULL;
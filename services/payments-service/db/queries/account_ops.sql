@@ -1,5 +1,10 @@
 -- name: InsertAccountOp :one
-INSERT INTO account_ops (order_id, user_id, delta)
-VALUES ($1, $2, $3)
-    ON CONFLICT (order_id) DO NOTHING
-RETURNING order_id;
+INSERT INTO account_ops (payment_id, order_id, user_id, delta)
+VALUES ($1, $2, $3, $4)
+    ON CONFLICT (payment_id) DO NOTHING
+RETURNING payment_id;
+
+-- name: CountRecentDebits :one
+SELECT COUNT(*)::bigint
+FROM account_ops
+WHERE user_id = $1 AND delta < 0 AND created_at > $2;
@@ -2,22 +2,119 @@
 INSERT INTO accounts (user_id, balance)
 VALUES ($1, 0)
     ON CONFLICT (user_id) DO NOTHING
-RETURNING user_id, balance;
+RETURNING user_id, balance, version, account_type;
 
 -- name: CreateAccountIdempotent :one
-INSERT INTO accounts (user_id, balance)
+INSERT INTO accounts AS a (user_id, balance)
 VALUES ($1, 0)
     ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
-RETURNING user_id, balance;
+RETURNING a.user_id, a.balance, a.version, a.overdraft_limit, a.account_type,
+    COALESCE((
+        SELECT SUM(b.remaining)
+        FROM bonus_grants b
+        WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+    ), 0)::bigint AS bonus_balance,
+    (a.xmax = 0)::boolean AS created;
 
 -- name: GetBalance :one
-SELECT balance FROM accounts WHERE user_id = $1;
+SELECT a.balance, a.version, a.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM accounts a
+WHERE a.user_id = $1;
+
+-- GetBalances is GetBalance for several accounts in one round trip; missing
+-- accounts are simply absent.
+-- name: GetBalances :many
+SELECT a.user_id, a.balance, a.version, a.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM accounts a
+WHERE a.user_id = ANY(sqlc.arg(user_ids)::text[]);
 
+-- TopUp is a compare-and-swap when expected_version is non-zero: no row is
+-- returned if the account is missing or its version moved on. The money
+-- comes from system:funding.
 -- name: TopUp :one
+WITH upd AS (
 UPDATE accounts
-SET balance = balance + $2
-WHERE user_id = $1
-    RETURNING user_id, balance;
+SET balance = accounts.balance + sqlc.arg(balance),
+    version = accounts.version + 1
+WHERE accounts.user_id = sqlc.arg(user_id)
+  AND (sqlc.arg(expected_version)::bigint = 0 OR accounts.version = sqlc.arg(expected_version)::bigint)
+    RETURNING accounts.user_id, accounts.balance, accounts.version, accounts.overdraft_limit, accounts.account_type
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id)
+SELECT 'balance', upd.user_id FROM upd
+    RETURNING id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, 'system:funding', -sqlc.arg(balance)::bigint, ent.created_at FROM ent
+UNION ALL
+SELECT ent.id, 'balance:' || sqlc.arg(user_id)::text, sqlc.arg(balance)::bigint, ent.created_at FROM ent
+)
+SELECT upd.user_id, upd.balance, upd.version, upd.overdraft_limit, upd.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = upd.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM upd;
+
+-- Operator correction (admin AdjustBalance), booked as its own entry kind
+-- against system:adjustments.
+-- The balance check constraint rejects a debit below the overdraft limit.
+-- name: AdjustBalance :one
+WITH upd AS (
+UPDATE accounts
+SET balance = accounts.balance + sqlc.arg(delta),
+    version = accounts.version + 1
+WHERE accounts.user_id = sqlc.arg(user_id)
+    RETURNING accounts.user_id, accounts.balance, accounts.version, accounts.overdraft_limit, accounts.account_type
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id)
+SELECT 'adjustment', upd.user_id FROM upd
+    RETURNING id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, 'system:adjustments', -sqlc.arg(delta)::bigint, ent.created_at FROM ent
+UNION ALL
+SELECT ent.id, 'balance:' || sqlc.arg(user_id)::text, sqlc.arg(delta)::bigint, ent.created_at FROM ent
+)
+SELECT upd.user_id, upd.balance, upd.version, upd.overdraft_limit, upd.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = upd.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM upd;
 
 -- name: AccountExists :one
 SELECT EXISTS(SELECT 1 FROM accounts WHERE user_id = $1) AS exists;
+
+-- name: SetOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE user_id = sqlc.arg(user_id)
+    RETURNING user_id, balance, version, overdraft_limit, account_type;
+
+-- name: GetAccountType :one
+SELECT account_type FROM accounts WHERE user_id = $1;
+
+-- SetAccountType also applies the overdraft limit of the new type.
+-- name: SetAccountType :one
+UPDATE accounts
+SET account_type = sqlc.arg(account_type),
+    overdraft_limit = sqlc.arg(overdraft_limit)
+WHERE user_id = sqlc.arg(user_id)
+    RETURNING user_id, balance, version, overdraft_limit, account_type;
@@ -0,0 +1,3 @@
+-- name: InsertAdminAudit :exec
+INSERT INTO admin_audit_log (operator, action, target, reason, details)
+VALUES ($1, $2, $3, $4, $5);
@@ -0,0 +1,122 @@
+-- A batch of accounts after after_user_id with their stored balances and the
+-- balances of their ledger accounts: the latest snapshot plus the postings
+-- after it, so partitions dropped by retention do not count. Both come from
+-- the snapshot of one statement, and a balance changes in the statement that
+-- books its postings, so they differ only when the two went apart. The
+-- stored bonus is what the grants have left, expired or not (expiry is not
+-- booked), plus what pending external charges and challenges hold: their
+-- bonus leaves the bonus account only when the payment succeeds.
+-- name: CheckLedgerBalances :many
+SELECT a.user_id, a.balance AS stored_balance,
+       (COALESCE(s.balance, 0) + COALESCE((
+           SELECT SUM(p.amount)
+           FROM postings p
+           WHERE p.account = 'balance:' || a.user_id
+             AND p.created_at > COALESCE(s.snapshot_at, '-infinity'::timestamptz)
+       ), 0))::bigint AS ledger_balance,
+       (COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = a.user_id
+       ), 0) + COALESCE((
+           SELECT SUM(c.bonus)
+           FROM external_charges c
+           WHERE c.user_id = a.user_id AND c.status = 'pending'
+       ), 0) + COALESCE((
+           SELECT SUM(c.bonus)
+           FROM payment_challenges c
+           WHERE c.user_id = a.user_id AND c.status = 'pending'
+       ), 0))::bigint AS stored_bonus,
+       (COALESCE(s.bonus, 0) + COALESCE((
+           SELECT SUM(p.amount)
+           FROM postings p
+           WHERE p.account = 'bonus:' || a.user_id
+             AND p.created_at > COALESCE(s.snapshot_at, '-infinity'::timestamptz)
+       ), 0))::bigint AS ledger_bonus
+FROM accounts a
+LEFT JOIN LATERAL (
+    SELECT bs.balance, bs.bonus, bs.snapshot_at
+    FROM balance_snapshots bs
+    WHERE bs.user_id = a.user_id
+    ORDER BY bs.snapshot_at DESC
+    LIMIT 1
+    ) s ON true
+WHERE a.user_id > sqlc.arg(after_user_id)::text
+ORDER BY a.user_id
+    LIMIT sqlc.arg(batch_size);
+
+-- GetLedgerBalance is the ledger balance of CheckLedgerBalances for one
+-- account.
+-- name: GetLedgerBalance :one
+WITH snap AS (
+SELECT balance, snapshot_at
+FROM balance_snapshots
+WHERE user_id = sqlc.arg(user_id)
+ORDER BY snapshot_at DESC
+    LIMIT 1
+    )
+SELECT (
+    COALESCE((SELECT balance FROM snap), 0) +
+    COALESCE((
+        SELECT SUM(p.amount)
+        FROM postings p
+        WHERE p.account = 'balance:' || sqlc.arg(user_id)::text
+          AND p.created_at > COALESCE((SELECT snapshot_at FROM snap), '-infinity'::timestamptz)
+    ), 0)
+)::bigint AS balance;
+
+-- Opens an anomaly for the account ('balance' or 'bonus') of the user or
+-- refreshes its open one; created is false for a discrepancy already
+-- recorded.
+-- name: RecordBalanceAnomaly :one
+INSERT INTO balance_anomalies AS b (user_id, account, stored_balance, ledger_balance)
+VALUES (sqlc.arg(user_id), sqlc.arg(account), sqlc.arg(stored_balance), sqlc.arg(ledger_balance))
+    ON CONFLICT (user_id, account) WHERE resolved_at IS NULL DO UPDATE
+SET stored_balance = EXCLUDED.stored_balance,
+    ledger_balance = EXCLUDED.ledger_balance,
+    last_seen_at = now()
+RETURNING (b.xmax = 0)::boolean AS created;
+
+-- name: CountOpenBalanceAnomalies :one
+SELECT COUNT(*) FROM balance_anomalies WHERE resolved_at IS NULL;
+
+-- Sets the stored balance to the ledger balance read under the account lock
+-- (admin RematerializeBalance). No entry is booked: the ledger is what the
+-- balance is corrected to.
+-- name: SetMaterializedBalance :one
+UPDATE accounts a
+SET balance = sqlc.arg(balance),
+    version = a.version + 1
+WHERE a.user_id = sqlc.arg(user_id)
+    RETURNING a.user_id, a.balance, a.version, a.overdraft_limit, a.account_type,
+    COALESCE((
+        SELECT SUM(b.remaining)
+        FROM bonus_grants b
+        WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+    ), 0)::bigint AS bonus_balance;
+
+-- name: ResolveBalanceAnomalies :execrows
+UPDATE balance_anomalies
+SET resolved_at = now(),
+    resolved_by = sqlc.arg(resolved_by)
+WHERE user_id = sqlc.arg(user_id) AND account = 'balance' AND resolved_at IS NULL;
+
+-- Bonus grants are not rematerialized, so the checker resolves the open
+-- bonus anomalies of the users whose bonus account matches again.
+-- name: ResolveBonusAnomalies :execrows
+UPDATE balance_anomalies
+SET resolved_at = now(),
+    resolved_by = 'balancecheck'
+WHERE user_id = ANY(sqlc.arg(user_ids)::text[]) AND account = 'bonus' AND resolved_at IS NULL;
+
+-- Journal entries booked after since whose postings do not sum to zero. The
+-- postings of an entry are inserted by one statement and share its
+-- created_at.
+-- name: ListUnbalancedEntries :many
+SELECT p.entry_id, SUM(p.amount)::bigint AS total
+FROM postings p
+WHERE p.created_at > sqlc.arg(since)::timestamptz
+GROUP BY p.entry_id
+HAVING SUM(p.amount) <> 0
+ORDER BY p.entry_id
+    LIMIT sqlc.arg(max_entries);
@@ -0,0 +1,68 @@
+-- name: GetAccountCreatedAt :one
+SELECT created_at FROM accounts WHERE user_id = $1;
+
+-- name: GetBalanceHistoryStart :one
+SELECT COALESCE((SELECT started_at FROM balance_history_start), '-infinity'::timestamptz)::timestamptz AS started_at;
+
+-- name: GetBalanceAt :one
+WITH snap AS (
+SELECT balance, snapshot_at
+FROM balance_snapshots
+WHERE user_id = sqlc.arg(user_id) AND snapshot_at <= sqlc.arg(at)::timestamptz
+ORDER BY snapshot_at DESC
+    LIMIT 1
+    )
+SELECT (
+    COALESCE((SELECT balance FROM snap), 0) +
+    COALESCE((
+        SELECT SUM(p.amount)
+        FROM postings p
+        WHERE p.account = 'balance:' || sqlc.arg(user_id)::text
+          AND p.created_at <= sqlc.arg(at)::timestamptz
+          AND p.created_at > COALESCE((SELECT snapshot_at FROM snap), '-infinity'::timestamptz)
+    ), 0)
+)::bigint AS balance;
+
+-- name: SnapshotBalances :execrows
+INSERT INTO balance_snapshots (user_id, snapshot_at, balance, bonus)
+SELECT a.user_id, sqlc.arg(at)::timestamptz,
+       COALESCE(s.balance, 0) + COALESCE((
+           SELECT SUM(p.amount)
+           FROM postings p
+           WHERE p.account = 'balance:' || a.user_id
+             AND p.created_at <= sqlc.arg(at)::timestamptz
+             AND p.created_at > COALESCE(s.snapshot_at, '-infinity'::timestamptz)
+       ), 0),
+       COALESCE(s.bonus, 0) + COALESCE((
+           SELECT SUM(p.amount)
+           FROM postings p
+           WHERE p.account = 'bonus:' || a.user_id
+             AND p.created_at <= sqlc.arg(at)::timestamptz
+             AND p.created_at > COALESCE(s.snapshot_at, '-infinity'::timestamptz)
+       ), 0)
+FROM accounts a
+LEFT JOIN LATERAL (
+    SELECT bs.balance, bs.bonus, bs.snapshot_at
+    FROM balance_snapshots bs
+    WHERE bs.user_id = a.user_id AND bs.snapshot_at < sqlc.arg(at)::timestamptz
+    ORDER BY bs.snapshot_at DESC
+    LIMIT 1
+    ) s ON true
+WHERE a.created_at <= sqlc.arg(at)::timestamptz
+    ON CONFLICT (user_id, snapshot_at) DO NOTHING;
+
+-- name: LatestSnapshotAt :one
+SELECT COALESCE(MAX(snapshot_at), '-infinity'::timestamptz)::timestamptz AS snapshot_at
+FROM balance_snapshots;
+
+-- Выписка по счёту: проводки по основному и бонусному счетам пользователя,
+-- новые первыми, страница по id проводки
+-- name: ListTransactions :many
+SELECT p.id, p.amount AS delta, e.kind, e.payment_id, ao.order_id, p.created_at
+FROM postings p
+JOIN journal_entries e ON e.id = p.entry_id AND e.created_at = p.created_at
+LEFT JOIN account_ops ao ON ao.payment_id = e.payment_id
+WHERE p.account IN ('balance:' || sqlc.arg(user_id)::text, 'bonus:' || sqlc.arg(user_id)::text)
+  AND (sqlc.arg(before_id)::bigint = 0 OR p.id < sqlc.arg(before_id)::bigint)
+ORDER BY p.id DESC
+    LIMIT sqlc.arg(page_size);
@@ -0,0 +1,43 @@
+-- No row is returned when the account does not exist. The grant is booked
+-- from system:promotions to the bonus account.
+-- name: GrantBonus :one
+WITH g AS (
+INSERT INTO bonus_grants (user_id, amount, remaining, expires_at)
+SELECT a.user_id, sqlc.arg(amount)::bigint, sqlc.arg(amount)::bigint, sqlc.arg(expires_at)::timestamptz
+FROM accounts a
+WHERE a.user_id = sqlc.arg(user_id)
+    RETURNING id, user_id, amount, remaining, expires_at, created_at
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id)
+SELECT 'bonus', g.user_id FROM g
+    RETURNING id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, 'system:promotions', -sqlc.arg(amount)::bigint, ent.created_at FROM ent
+UNION ALL
+SELECT ent.id, 'bonus:' || sqlc.arg(user_id)::text, sqlc.arg(amount)::bigint, ent.created_at FROM ent
+)
+SELECT id, user_id, amount, remaining, expires_at, created_at FROM g;
+
+-- Spending order of the active grants; the caller holds them until commit.
+-- name: LockActiveBonusGrants :many
+SELECT id, remaining
+FROM bonus_grants
+WHERE user_id = $1 AND remaining > 0 AND expires_at > now()
+ORDER BY expires_at, id
+    FOR UPDATE;
+
+-- name: SpendBonusGrant :exec
+UPDATE bonus_grants
+SET remaining = remaining - sqlc.arg(spent)::bigint
+WHERE id = sqlc.arg(id);
+
+-- Gives back what a failed external charge held; amounts match ids by
+-- position.
+-- name: RestoreBonusGrants :exec
+UPDATE bonus_grants g
+SET remaining = g.remaining + s.amount
+FROM (SELECT unnest(sqlc.arg(ids)::bigint[]) AS id, unnest(sqlc.arg(amounts)::bigint[]) AS amount) s
+WHERE g.id = s.id;
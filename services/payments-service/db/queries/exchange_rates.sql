@@ -0,0 +1,20 @@
+-- Recording the same provider table again changes nothing.
+-- name: RecordExchangeRate :exec
+INSERT INTO exchange_rates (currency, as_of, price)
+VALUES (sqlc.arg(currency), sqlc.arg(as_of), sqlc.arg(price)::text::numeric)
+    ON CONFLICT (currency, as_of) DO NOTHING;
+
+-- The rates of currency in effect between since and until: the latest one
+-- recorded at since, if any, and every later one up to until, oldest first.
+-- name: ListExchangeRates :many
+SELECT r.as_of, r.price::text AS price
+FROM exchange_rates r
+WHERE r.currency = sqlc.arg(currency)
+  AND r.as_of <= sqlc.arg(until)
+  AND r.as_of >= COALESCE((
+      SELECT max(er.as_of)
+      FROM exchange_rates er
+      WHERE er.currency = sqlc.arg(currency)
+        AND er.as_of <= sqlc.arg(since)
+  ), sqlc.arg(since))
+ORDER BY r.as_of;
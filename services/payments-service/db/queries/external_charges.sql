@@ -0,0 +1,49 @@
+-- Returns 0 when the payment_id already has a charge.
+-- name: InsertExternalCharge :execrows
+INSERT INTO external_charges (payment_id, order_id, user_id, method, amount, bonus, fee, bonus_grant_ids, bonus_grant_amounts, correlation_id)
+VALUES (sqlc.arg(payment_id), sqlc.arg(order_id), sqlc.arg(user_id), sqlc.arg(method), sqlc.arg(amount), sqlc.arg(bonus), sqlc.arg(fee), sqlc.arg(bonus_grant_ids)::bigint[], sqlc.arg(bonus_grant_amounts)::bigint[], sqlc.arg(correlation_id))
+    ON CONFLICT (payment_id) DO NOTHING;
+
+-- Pending charges to submit (no provider_charge_id yet) or to check on
+-- again (last checked before checked_before), oldest first.
+-- name: ListPendingExternalCharges :many
+SELECT *
+FROM external_charges
+WHERE status = 'pending'
+  AND (provider_charge_id IS NULL OR checked_at IS NULL OR checked_at <= sqlc.arg(checked_before)::timestamptz)
+ORDER BY created_at
+    LIMIT sqlc.arg(batch_size)::int;
+
+-- name: SetExternalChargeSubmitted :exec
+UPDATE external_charges
+SET provider_charge_id = sqlc.arg(provider_charge_id)::text, attempts = attempts + 1, checked_at = now()
+WHERE payment_id = sqlc.arg(payment_id) AND status = 'pending';
+
+-- Records a submission or check that did not settle the charge.
+-- name: TouchExternalCharge :exec
+UPDATE external_charges
+SET attempts = attempts + 1, checked_at = now()
+WHERE payment_id = $1 AND status = 'pending';
+
+-- No row is returned once the charge is settled.
+-- name: LockPendingExternalCharge :one
+SELECT *
+FROM external_charges
+WHERE payment_id = $1 AND status = 'pending'
+    FOR UPDATE;
+
+-- name: FinishExternalCharge :exec
+UPDATE external_charges
+SET status = sqlc.arg(status)::text,
+    provider_charge_id = COALESCE(provider_charge_id, sqlc.narg(provider_charge_id)::text),
+    failure_reason = sqlc.narg(failure_reason)::text,
+    finished_at = now()
+WHERE payment_id = sqlc.arg(payment_id);
+
+-- The provider reports a charge settled here as failed as succeeded; only
+-- the first report counts.
+-- name: MarkExternalChargeSucceededAfterFailure :execrows
+UPDATE external_charges
+SET provider_succeeded_at = now(),
+    provider_charge_id = COALESCE(provider_charge_id, sqlc.narg(provider_charge_id)::text)
+WHERE payment_id = sqlc.arg(payment_id) AND status = 'failed' AND provider_succeeded_at IS NULL;
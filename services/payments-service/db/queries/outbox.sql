@@ -1,22 +1,112 @@
 -- name: InsertOutbox :one
-INSERT INTO outbox (topic, kafka_key, payload)
-VALUES ($1, $2, $3)
+INSERT INTO outbox (topic, kafka_key, payload, correlation_id)
+VALUES ($1, $2, $3, $4)
     RETURNING id;
 
+-- name: TryLockOutboxSlots :many
+-- Returns the slots no other publisher holds and locks them until the
+-- transaction ends, so one publisher at a time, across all instances and
+-- whatever their OUTBOX_WORKERS, works on a slot and keys stay in order.
+SELECT slot::int AS slot
+FROM unnest(sqlc.arg(slots)::int[]) AS slot
+WHERE pg_try_advisory_xact_lock(hashtext('outbox_slot'), slot);
+
 -- name: LockUnsentOutbox :many
-SELECT id, topic, kafka_key, payload, attempts
-FROM outbox
-WHERE sent_at IS NULL
-ORDER BY id
-    LIMIT $1
-FOR UPDATE SKIP LOCKED;
+-- Rows of the given slots. A key's slot is hash(kafka_key) % 64 whatever the
+-- number of workers (kafka.OutboxSlots; outbox_unsent_slot_idx has the same
+-- expression). A key waits while its earliest unsent row backs off, so later
+-- rows never overtake it; dead-lettered rows no longer block.
+SELECT o.id, o.topic, o.kafka_key, o.payload, o.attempts, o.correlation_id
+FROM outbox o
+WHERE o.sent_at IS NULL
+  AND o.status <> 'DEAD'
+  AND mod(abs(hashtext(o.kafka_key)::bigint), 64) = ANY(sqlc.arg(slots)::int[])
+  AND NOT EXISTS (
+      SELECT 1
+      FROM outbox b
+      WHERE b.kafka_key = o.kafka_key
+        AND b.id <= o.id
+        AND b.sent_at IS NULL
+        AND b.status <> 'DEAD'
+        AND b.next_retry_at > now()
+  )
+ORDER BY o.id
+    LIMIT sqlc.arg(batch_size)
+FOR UPDATE OF o SKIP LOCKED;
 
 -- name: MarkOutboxSent :exec
 UPDATE outbox
-SET sent_at = now(), status = 'SENT'
+SET sent_at = now(), status = 'SENT', next_retry_at = NULL
 WHERE id = $1;
 
 -- name: MarkOutboxAttemptFailed :exec
 UPDATE outbox
-SET attempts = attempts + 1, last_error = $2, status = 'FAILED'
+SET attempts = attempts + 1,
+    last_error = sqlc.arg(last_error),
+    status = 'FAILED',
+    next_retry_at = now() + sqlc.arg(backoff_ms)::bigint * interval '1 millisecond'
+WHERE id = sqlc.arg(id);
+
+-- name: MarkOutboxDead :exec
+-- Dead-lettered rows are never picked up again; they stay for the admin
+-- ListDeadOutbox RPC.
+UPDATE outbox
+SET attempts = attempts + 1, last_error = $2, status = 'DEAD', next_retry_at = NULL
 WHERE id = $1;
+
+-- name: ListDeadOutbox :many
+SELECT id, topic, kafka_key, attempts, last_error, created_at
+FROM outbox
+WHERE status = 'DEAD'
+  AND id > sqlc.arg(after_id)
+  AND (sqlc.arg(topic)::text = '' OR topic = sqlc.arg(topic)::text)
+ORDER BY id
+    LIMIT sqlc.arg(page_size);
+
+-- Просмотр outbox для админки (ListOutbox): statuses задаёт состояние, границы
+-- created_from/created_to необязательны
+-- name: ListOutbox :many
+SELECT id, topic, kafka_key, status, attempts, last_error, created_at, next_retry_at, correlation_id
+FROM outbox
+WHERE status = ANY(sqlc.arg(statuses)::text[])
+  AND id > sqlc.arg(after_id)
+  AND (sqlc.arg(topic)::text = '' OR topic = sqlc.arg(topic)::text)
+  AND (sqlc.narg(created_from)::timestamptz IS NULL OR created_at >= sqlc.narg(created_from)::timestamptz)
+  AND (sqlc.narg(created_to)::timestamptz IS NULL OR created_at < sqlc.narg(created_to)::timestamptz)
+ORDER BY id
+    LIMIT sqlc.arg(page_size);
+
+-- Возвращает мёртвые события в очередь с нуля попыток; с all — все, иначе перечисленные
+-- name: RequeueDeadOutbox :execrows
+UPDATE outbox
+SET status = 'PENDING', attempts = 0, next_retry_at = NULL
+WHERE status = 'DEAD'
+  AND (sqlc.arg(all_events)::bool OR id = ANY(sqlc.arg(ids)::bigint[]))
+  AND (sqlc.arg(topic)::text = '' OR topic = sqlc.arg(topic)::text);
+
+-- Повтор уже отправленных событий (admin ReplayOutbox): копии встают в очередь
+-- как новые, исходные строки остаются историей
+-- name: CountSentOutbox :one
+SELECT count(*)
+FROM outbox
+WHERE sent_at IS NOT NULL
+  AND created_at >= sqlc.arg(created_from) AND created_at < sqlc.arg(created_to)
+  AND (sqlc.arg(topic)::text = '' OR topic = sqlc.arg(topic)::text);
+
+-- name: ReplaySentOutbox :execrows
+INSERT INTO outbox (topic, kafka_key, payload, correlation_id)
+SELECT o.topic, o.kafka_key, o.payload, o.correlation_id
+FROM outbox o
+WHERE o.sent_at IS NOT NULL
+  AND o.created_at >= sqlc.arg(created_from) AND o.created_at < sqlc.arg(created_to)
+  AND (sqlc.arg(topic)::text = '' OR o.topic = sqlc.arg(topic)::text)
+ORDER BY o.id;
+
+-- Строки outbox, вставленные текущей транзакцией (dry-run повтор сообщения
+-- из inbox): xmin строки совпадает с xid транзакции
+-- name: ListTxOutbox :many
+SELECT id, topic, kafka_key, payload
+FROM outbox
+WHERE sent_at IS NULL
+  AND xmin::text::bigint = txid_current() % 4294967296
+ORDER BY id;
@@ -0,0 +1,33 @@
+-- Returns 0 when the payment_id already has a challenge.
+-- name: InsertPaymentChallenge :execrows
+INSERT INTO payment_challenges (payment_id, order_id, user_id, method, amount, bonus, fee, bonus_grant_ids, bonus_grant_amounts, correlation_id, expires_at)
+VALUES (sqlc.arg(payment_id), sqlc.arg(order_id), sqlc.arg(user_id), sqlc.arg(method), sqlc.arg(amount), sqlc.arg(bonus), sqlc.arg(fee), sqlc.arg(bonus_grant_ids)::bigint[], sqlc.arg(bonus_grant_amounts)::bigint[], sqlc.arg(correlation_id), sqlc.arg(expires_at))
+    ON CONFLICT (payment_id) DO NOTHING;
+
+-- The oldest unexpired challenge of the user's order, or of one of its
+-- payments when payment_id is set.
+-- name: LockPendingChallenge :one
+SELECT *
+FROM payment_challenges
+WHERE user_id = sqlc.arg(user_id)
+  AND order_id = sqlc.arg(order_id)
+  AND (sqlc.narg(payment_id)::uuid IS NULL OR payment_id = sqlc.narg(payment_id)::uuid)
+  AND status = 'pending'
+  AND expires_at > now()
+ORDER BY created_at
+    LIMIT 1
+    FOR UPDATE;
+
+-- Challenges other replicas are expiring are skipped.
+-- name: LockExpiredChallenges :many
+SELECT *
+FROM payment_challenges
+WHERE status = 'pending' AND expires_at <= now()
+ORDER BY expires_at
+    LIMIT sqlc.arg(batch_size)::int
+    FOR UPDATE SKIP LOCKED;
+
+-- name: FinishPaymentChallenge :exec
+UPDATE payment_challenges
+SET status = sqlc.arg(status)::text, finished_at = now()
+WHERE payment_id = sqlc.arg(payment_id);
@@ -0,0 +1,102 @@
+-- name: ListOrderOps :many
+SELECT payment_id, user_id, delta, fee, bonus, frozen
+FROM account_ops
+WHERE order_id = $1
+ORDER BY created_at, payment_id;
+
+-- Freezes the user's operations of the order and records the dispute over
+-- what they paid: the balance or card part and the fee, not the bonus.
+-- Returns no row when the order was disputed before or has no operations.
+-- name: OpenPaymentDispute :one
+WITH ops AS (
+UPDATE account_ops
+SET frozen = true
+WHERE order_id = sqlc.arg(order_id)
+  AND user_id = sqlc.arg(user_id)
+  AND NOT frozen
+  AND NOT EXISTS (SELECT 1 FROM payment_disputes d WHERE d.order_id = sqlc.arg(order_id))
+    RETURNING -delta - bonus + fee AS paid
+)
+INSERT INTO payment_disputes (order_id, user_id, amount, reason)
+SELECT sqlc.arg(order_id), sqlc.arg(user_id), COALESCE(SUM(paid), 0)::bigint, sqlc.arg(reason)
+FROM ops
+HAVING COUNT(*) > 0
+    ON CONFLICT (order_id) DO NOTHING
+RETURNING *;
+
+-- name: GetPaymentDispute :one
+SELECT *
+FROM payment_disputes
+WHERE order_id = $1;
+
+-- name: LockOpenDispute :one
+SELECT *
+FROM payment_disputes
+WHERE order_id = $1 AND status = 'open'
+    FOR UPDATE;
+
+-- name: ResolvePaymentDispute :one
+UPDATE payment_disputes
+SET status = sqlc.arg(status),
+    resolution_reason = sqlc.arg(resolution_reason),
+    resolved_at = now()
+WHERE order_id = sqlc.arg(order_id) AND status = 'open'
+    RETURNING *;
+
+-- name: UnfreezeOrderOps :exec
+UPDATE account_ops
+SET frozen = false
+WHERE order_id = $1 AND user_id = $2 AND frozen;
+
+-- Refunds the frozen operations of the order, booked against each payment:
+-- the paid part from system:orders and the fee from system:fees. Payments
+-- from the balance are credited back to it; those by an external method go
+-- back through system:external, as they were paid, and leave the balance
+-- alone. The operations stay frozen.
+-- name: RefundDisputedOps :one
+WITH ops AS (
+SELECT payment_id, method, -delta - bonus AS paid, fee
+FROM account_ops
+WHERE order_id = sqlc.arg(order_id) AND user_id = sqlc.arg(user_id)::text AND frozen
+),
+upd AS (
+UPDATE accounts
+SET balance = accounts.balance + (SELECT COALESCE(SUM(paid + fee), 0) FROM ops WHERE method = 'balance')::bigint,
+    version = accounts.version + 1
+WHERE accounts.user_id = sqlc.arg(user_id)::text
+    RETURNING accounts.user_id, accounts.balance, accounts.version, accounts.overdraft_limit, accounts.account_type
+),
+legs AS (
+SELECT CASE WHEN method = 'balance' THEN 'balance' ELSE 'external' END AS kind, payment_id,
+       'system:orders' AS from_account,
+       CASE WHEN method = 'balance' THEN 'balance:' || sqlc.arg(user_id)::text ELSE 'system:external' END AS to_account,
+       paid AS amount
+FROM ops
+UNION ALL
+SELECT 'fee', payment_id, 'system:fees',
+       CASE WHEN method = 'balance' THEN 'balance:' || sqlc.arg(user_id)::text ELSE 'system:external' END,
+       fee
+FROM ops
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id, payment_id)
+SELECT legs.kind, sqlc.arg(user_id)::text, legs.payment_id
+FROM legs
+WHERE legs.amount > 0 AND EXISTS (SELECT 1 FROM upd)
+    RETURNING id, kind, payment_id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, legs.from_account, -legs.amount, ent.created_at
+FROM ent JOIN legs ON legs.kind = ent.kind AND legs.payment_id = ent.payment_id
+UNION ALL
+SELECT ent.id, legs.to_account, legs.amount, ent.created_at
+FROM ent JOIN legs ON legs.kind = ent.kind AND legs.payment_id = ent.payment_id
+)
+SELECT upd.user_id, upd.balance, upd.version, upd.overdraft_limit, upd.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = upd.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM upd;
@@ -1,19 +1,77 @@
+-- The payment and its fee are deducted in one update but booked as separate
+-- entries to system:orders and system:fees; fee is 0 when no fee rule
+-- applies. bonus is the part of amount paid from bonus grants, booked from
+-- the bonus account, which the caller spends when op_inserted.
 -- name: TryDeductOnce :one
 WITH upd AS (
 UPDATE accounts
-SET balance = accounts.balance - $3
-WHERE accounts.user_id = $2
-  AND accounts.balance >= $3
-  AND NOT EXISTS (SELECT 1 FROM account_ops ao WHERE ao.order_id = $1)
+SET balance = accounts.balance - (sqlc.arg(amount)::bigint - sqlc.arg(bonus)::bigint) - sqlc.arg(fee)::bigint,
+    version = accounts.version + 1
+WHERE accounts.user_id = sqlc.arg(user_id)
+  AND accounts.balance - (sqlc.arg(amount)::bigint - sqlc.arg(bonus)::bigint) - sqlc.arg(fee)::bigint >= -accounts.overdraft_limit
+  AND NOT EXISTS (SELECT 1 FROM account_ops ao WHERE ao.payment_id = sqlc.arg(payment_id))
     RETURNING balance
 ),
 ins AS (
-INSERT INTO account_ops (order_id, user_id, delta)
-SELECT $1, $2, -$3
+INSERT INTO account_ops (payment_id, order_id, user_id, delta, fee, bonus)
+SELECT sqlc.arg(payment_id), sqlc.arg(order_id), sqlc.arg(user_id), -sqlc.arg(amount)::bigint, sqlc.arg(fee)::bigint, sqlc.arg(bonus)::bigint
 WHERE EXISTS (SELECT 1 FROM upd)
-ON CONFLICT (order_id) DO NOTHING
+ON CONFLICT (payment_id) DO NOTHING
     RETURNING 1 AS inserted
+    ),
+legs AS (
+SELECT 'balance' AS kind, 'balance:' || sqlc.arg(user_id)::text AS from_account, 'system:orders' AS to_account, sqlc.arg(amount)::bigint - sqlc.arg(bonus)::bigint AS amount
+UNION ALL
+SELECT 'fee', 'balance:' || sqlc.arg(user_id)::text, 'system:fees', sqlc.arg(fee)::bigint
+UNION ALL
+SELECT 'bonus', 'bonus:' || sqlc.arg(user_id)::text, 'system:orders', sqlc.arg(bonus)::bigint
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id, payment_id)
+SELECT legs.kind, sqlc.arg(user_id), sqlc.arg(payment_id)
+FROM legs
+WHERE legs.amount > 0 AND EXISTS (SELECT 1 FROM ins)
+    RETURNING id, kind, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, legs.from_account, -legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+UNION ALL
+SELECT ent.id, legs.to_account, legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
     )
 SELECT
     COALESCE((SELECT balance FROM upd), 0)::bigint AS new_balance,
     COALESCE((SELECT inserted FROM ins), 0)::bigint AS op_inserted;
+
+-- A payment made by an external method leaves the balance alone: its bonus
+-- part is booked from the bonus account, the rest and the fee from
+-- system:external. op_inserted is 0 when the payment_id is already
+-- recorded.
+-- name: RecordExternalPayment :one
+WITH ins AS (
+INSERT INTO account_ops (payment_id, order_id, user_id, delta, fee, bonus, method)
+VALUES (sqlc.arg(payment_id), sqlc.arg(order_id), sqlc.arg(user_id), -sqlc.arg(amount)::bigint, sqlc.arg(fee)::bigint, sqlc.arg(bonus)::bigint, sqlc.arg(method)::text)
+ON CONFLICT (payment_id) DO NOTHING
+    RETURNING 1 AS inserted
+    ),
+legs AS (
+SELECT 'bonus' AS kind, 'bonus:' || sqlc.arg(user_id)::text AS from_account, 'system:orders' AS to_account, sqlc.arg(bonus)::bigint AS amount
+UNION ALL
+SELECT 'external', 'system:external', 'system:orders', sqlc.arg(amount)::bigint - sqlc.arg(bonus)::bigint
+UNION ALL
+SELECT 'fee', 'system:external', 'system:fees', sqlc.arg(fee)::bigint
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id, payment_id)
+SELECT legs.kind, sqlc.arg(user_id), sqlc.arg(payment_id)
+FROM legs
+WHERE legs.amount > 0 AND EXISTS (SELECT 1 FROM ins)
+    RETURNING id, kind, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, legs.from_account, -legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+UNION ALL
+SELECT ent.id, legs.to_account, legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+    )
+SELECT COALESCE((SELECT inserted FROM ins), 0)::bigint AS op_inserted;
@@ -0,0 +1,18 @@
+-- name: LockAccount :one
+SELECT user_id FROM accounts WHERE user_id = $1 FOR UPDATE;
+
+-- name: TopupVelocity :one
+SELECT
+    COUNT(*) FILTER (WHERE created_at > sqlc.arg(count_since)::timestamptz)::bigint AS recent_count,
+    COALESCE(SUM(amount) FILTER (WHERE created_at > sqlc.arg(amount_since)::timestamptz), 0)::bigint AS recent_amount
+FROM topup_events
+WHERE user_id = sqlc.arg(user_id)
+  AND created_at > LEAST(sqlc.arg(count_since)::timestamptz, sqlc.arg(amount_since)::timestamptz);
+
+-- name: InsertTopupEvent :exec
+INSERT INTO topup_events (user_id, amount)
+VALUES ($1, $2);
+
+-- name: DeleteTopupEventsBefore :exec
+DELETE FROM topup_events
+WHERE user_id = $1 AND created_at < $2;
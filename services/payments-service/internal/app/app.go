@@ -2,47 +2,176 @@ package app
 
 import (
 	"context"
+	"errors"
+	"expvar"
+	"fmt"
 	"log/slog"
 	"net"
+	"net/http"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/segmentio/kafka-go"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/auth"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/balancecheck"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/challenge"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/fees"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/fraud"
 	grpcsvc "github.com/ilyaytrewq/payments-service/payments-service/internal/grpc"
 	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/psp"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rates"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rest"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/snapshot"
 
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
 	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+	"github.com/ilyaytrewq/payments-service/pkg/deadline"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+	"github.com/ilyaytrewq/payments-service/pkg/loadshed"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/partition"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
 )
 
 func Run(ctx context.Context, cfg config.Config) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "payments-service", "component", "app")
-	logger.Info("payments service starting", "grpc_addr", cfg.GRPCAddr, "redis_addr", cfg.RedisAddr != "", "kafka_brokers", len(cfg.KafkaBrokers))
+	logger.Info("payments service starting", "grpc_addr", cfg.GRPCAddr, "redis_addr", cfg.RedisAddr != "", "kafka_brokers", len(cfg.KafkaBrokers), "http_addr", cfg.HTTPAddr, "reflection", cfg.EnableReflection, "admin_api", cfg.EnableAdminAPI)
 
-	pool, err := pgxpool.New(ctx, cfg.DatabaseURL)
+	currency, err := money.ParseCurrency(cfg.Currency)
+	if err != nil {
+		logger.Error("invalid currency", "err", err)
+		return err
+	}
+	serviceAuth, err := svcauth.ParseConfig(cfg.ServiceAuthMode, cfg.ServiceAuthTokens, cfg.ServiceAuthJWTSecret, cfg.ServiceAuthTrustDomain)
+	if err != nil {
+		logger.Error("invalid service auth config", "err", err)
+		return err
+	}
+
+	kafkaSecurity := kafkaio.Security{
+		TLS:           cfg.KafkaTLS,
+		CAFile:        cfg.KafkaTLSCAFile,
+		CertFile:      cfg.KafkaTLSCertFile,
+		KeyFile:       cfg.KafkaTLSKeyFile,
+		SASLMechanism: cfg.KafkaSASLMechanism,
+		SASLUsername:  cfg.KafkaSASLUsername,
+		SASLPassword:  cfg.KafkaSASLPassword,
+	}
+	kafkaTransport, err := kafkaSecurity.Transport()
+	if err != nil {
+		logger.Error("failed to configure kafka transport", "err", err)
+		return err
+	}
+	kafkaDialer, err := kafkaSecurity.Dialer()
+	if err != nil {
+		logger.Error("failed to configure kafka dialer", "err", err)
+		return err
+	}
+	logger.Info("kafka security configured", "tls", cfg.KafkaTLS, "sasl_mechanism", cfg.KafkaSASLMechanism)
+
+	if err := waitForDependencies(ctx, cfg, kafkaDialer, logger); err != nil {
+		return err
+	}
+
+	dbLogger := slog.Default().With("service", "payments-service", "component", "repo")
+	poolOpts := pgtx.PoolOptions{
+		MaxConns:        int32(cfg.DBMaxConns),
+		MinConns:        int32(cfg.DBMinConns),
+		MaxConnLifetime: cfg.DBMaxConnLifetime,
+		ConnectRetries:  cfg.DBConnectRetries,
+		ConnectBackoff:  cfg.DBConnectBackoff,
+
+		SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+		StatementTimeout:   cfg.DBStatementTimeout,
+	}
+	pool, err := pgtx.NewPool(ctx, cfg.DatabaseURL, poolOpts, dbLogger)
 	if err != nil {
 		logger.Error("failed to create db pool", "err", err)
 		return err
 	}
 	defer pool.Close()
 
-	repo := postgres.NewRepo(pool)
+	var replica *pgxpool.Pool
+	if cfg.ReplicaURL != "" && len(cfg.ShardURLs) > 0 {
+		logger.Warn("read replica ignored with sharded accounts")
+	} else if cfg.ReplicaURL != "" {
+		// A replica that is down at startup is not fatal: reads use the primary.
+		replica, err = pgtx.NewPool(ctx, cfg.ReplicaURL, pgtx.PoolOptions{
+			MaxConns:           int32(cfg.DBMaxConns),
+			MinConns:           int32(cfg.DBMinConns),
+			MaxConnLifetime:    cfg.DBMaxConnLifetime,
+			SlowQueryThreshold: cfg.DBSlowQueryThreshold,
+			StatementTimeout:   cfg.DBStatementTimeout,
+		}, dbLogger)
+		if err != nil {
+			logger.Warn("read replica unavailable, reading from primary", "err", err)
+			replica = nil
+		} else {
+			defer replica.Close()
+		}
+	}
+
+	dbFaults, kafkaFaults, cacheFaults, err := chaos.Targets(cfg.ChaosEnabled, cfg.ChaosDB, cfg.ChaosKafka, cfg.ChaosCache, logger)
+	if err != nil {
+		logger.Error("invalid chaos config", "err", err)
+		return err
+	}
+
+	txCfg := pgtx.Config{Retries: cfg.DBTxRetries, Backoff: cfg.DBTxRetryBackoff}
+	deductIsolation, err := pgtx.ParseIsolation(cfg.DeductIsolation)
+	if err != nil {
+		logger.Error("invalid deduct isolation", "err", err)
+		return err
+	}
+
+	repos := []*postgres.Repo{postgres.NewRepo(0, pool, replica, txCfg)}
+	for i, url := range cfg.ShardURLs {
+		shardPool, err := pgtx.NewPool(ctx, url, poolOpts, dbLogger)
+		if err != nil {
+			logger.Error("failed to create shard db pool", "err", err, "shard", i+1)
+			return err
+		}
+		defer shardPool.Close()
+		repos = append(repos, postgres.NewRepo(i+1, shardPool, nil, txCfg))
+	}
+	for _, repo := range repos {
+		repo.InjectFaults(dbFaults)
+	}
+	shards := postgres.NewShards(repos...)
+	logger.Info("account shards configured", "shards", len(repos))
 
-	writer := &kafka.Writer{
-		Addr:         kafka.TCP(cfg.KafkaBrokers...),
-		Topic:        cfg.TopicPaymentResult,
-		Balancer:     &kafka.Hash{},
-		RequiredAcks: kafka.RequireAll,
-		BatchTimeout: 50 * time.Millisecond,
+	writer, err := kafkaio.NewWriter(cfg.KafkaBrokers, kafkaTransport, kafkaio.WriterOptions{
+		Compression:  cfg.KafkaWriterCompression,
+		BatchSize:    cfg.KafkaWriterBatchSize,
+		BatchBytes:   int64(cfg.KafkaWriterBatchBytes),
+		BatchTimeout: cfg.KafkaWriterBatchTimeout,
+		MaxAttempts:  cfg.KafkaWriterMaxAttempts,
+	})
+	if err != nil {
+		logger.Error("invalid kafka writer config", "err", err)
+		return err
 	}
 	defer func() {
 		if err := writer.Close(); err != nil {
@@ -50,37 +179,209 @@ func Run(ctx context.Context, cfg config.Config) error {
 		}
 	}()
 
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers:        cfg.KafkaBrokers,
-		Topic:          cfg.TopicPaymentRequested,
-		GroupID:        cfg.ConsumerGroupID,
-		MinBytes:       1e3,
-		MaxBytes:       10e6,
-		CommitInterval: 0,
+	reader, err := kafkaio.NewReader(cfg.KafkaBrokers, kafkaDialer, cfg.TopicPaymentRequested, cfg.ConsumerGroupID, kafkaio.ReaderOptions{
+		StartOffset:       cfg.KafkaConsumerStartOffset,
+		MaxWait:           cfg.KafkaConsumerMaxWait,
+		QueueCapacity:     cfg.KafkaConsumerQueueCapacity,
+		RebalanceTimeout:  cfg.KafkaConsumerRebalanceTimeout,
+		SessionTimeout:    cfg.KafkaConsumerSessionTimeout,
+		HeartbeatInterval: cfg.KafkaConsumerHeartbeatInterval,
 	})
+	if err != nil {
+		logger.Error("invalid kafka consumer config", "err", err)
+		return err
+	}
 	defer func() {
 		if err := reader.Close(); err != nil {
 			logger.Error("failed to close kafka reader", "err", err)
 		}
 	}()
 
-	outbox := kafkasvc.NewOutboxPublisher(repo, writer, cfg.OutboxPollInterval, cfg.OutboxBatchSize)
-	consumer := kafkasvc.NewPaymentRequestedConsumer(repo, reader, cfg.TopicPaymentResult)
+	// Every shard has its own outbox table and publisher.
+	outboxes := make([]*kafkasvc.OutboxPublisher, 0, len(repos))
+	for _, repo := range repos {
+		outbox := kafkasvc.NewOutboxPublisher(repo, chaos.WithWriterFaults(writer, kafkaFaults), cfg.OutboxPollInterval, cfg.OutboxBatchSize, cfg.OutboxWorkers, cfg.OutboxListen, kafkasvc.RetryPolicy{
+			MaxAttempts: cfg.OutboxMaxAttempts,
+			Backoff:     cfg.OutboxRetryBackoff,
+			MaxBackoff:  cfg.OutboxRetryMaxBackoff,
+		})
+		if cfg.OutboxTransactional {
+			outbox.UseTransactions(func(partition int) (kafkasvc.TxProducer, error) {
+				txCfg, err := kafkaSecurity.TxConfig(cfg.KafkaBrokers, fmt.Sprintf("%s-%d-%d", cfg.OutboxTransactionalID, repo.Shard(), partition))
+				if err != nil {
+					return nil, err
+				}
+				txCfg.Compression = cfg.KafkaWriterCompression
+				producer, err := kafkatx.NewProducer(txCfg)
+				if err != nil {
+					return nil, err
+				}
+				return producer, nil
+			})
+		}
+		outboxes = append(outboxes, outbox)
+	}
+	if cfg.OutboxTransactional {
+		logger.Info("outbox publishes in kafka transactions", "transactional_id", cfg.OutboxTransactionalID)
+	}
+	policies, err := policy.Parse(cfg.AccountPolicies)
+	if err != nil {
+		logger.Error("invalid account policies", "err", err)
+		return err
+	}
+	schedule, err := fees.Parse(cfg.FeeRules)
+	if err != nil {
+		logger.Error("invalid fee rules", "err", err)
+		return err
+	}
+	var rateProvider rates.Provider
+	switch {
+	case cfg.RatesURL != "":
+		rateProvider = rates.NewHTTP(cfg.RatesURL, currency, &http.Client{Timeout: 10 * time.Second}, cfg.RatesTTL, cfg.RatesMaxStale)
+		logger.Info("exchange rates from http feed", "ttl", cfg.RatesTTL, "max_stale", cfg.RatesMaxStale)
+	case cfg.Rates != "":
+		static, err := rates.ParseStatic(currency, cfg.Rates)
+		if err != nil {
+			logger.Error("invalid PAYMENTS_RATES", "err", err)
+			return err
+		}
+		rateProvider = static
+		logger.Info("exchange rates from static table")
+	}
+	var checker fraud.Checker = fraud.AllowAll{}
+	if rules := fraud.NewRules(cfg.FraudMaxAmount, cfg.FraudMaxPaymentsPerHour, cfg.FraudDenylist); rules.Enabled() {
+		checker = rules
+		logger.Info("fraud screening enabled", "max_amount", cfg.FraudMaxAmount, "max_payments_per_hour", cfg.FraudMaxPaymentsPerHour)
+	}
+	consumer := kafkasvc.NewPaymentRequestedConsumer(shards, reader, cfg.TopicPaymentResult, cfg.TopicAccountCreated, cfg.AutoCreateAccounts, cfg.TxOffsets, checker, policies, schedule, pgx.TxOptions{IsoLevel: deductIsolation}, cfg.KafkaHandlerTimeout)
+	consumer.StorePayloads(cfg.InboxStorePayloads)
+	methods := method.NewRegistry()
+	if cfg.MockCardEnabled {
+		methods.Register(method.Card, method.MockCard{DeclineAbove: cfg.MockCardDeclineAbove, ChallengeAbove: cfg.MockCardChallengeAbove})
+		logger.Warn("mock card payment method enabled", "decline_above", cfg.MockCardDeclineAbove, "challenge_above", cfg.MockCardChallengeAbove)
+	}
+	var (
+		pspClient  *psp.Client
+		pspWebhook *psp.Webhook
+	)
+	if cfg.PSPURL != "" {
+		pspClient = psp.NewClient(cfg.PSPURL, cfg.PSPAPIKey, currency, &http.Client{Timeout: 10 * time.Second})
+		methods.Register(psp.Method, psp.Provider{})
+		if cfg.PSPWebhookKeys != "" && cfg.HTTPAddr != "" {
+			keys, err := signature.ParseKeyring(cfg.PSPWebhookKeys)
+			if err != nil {
+				logger.Error("invalid PAYMENTS_PSP_WEBHOOK_KEYS", "err", err)
+				return err
+			}
+			pspWebhook = psp.NewWebhook(shards, keys, cfg.TopicPaymentResult)
+		} else {
+			logger.Warn("psp webhooks need PAYMENTS_PSP_WEBHOOK_KEYS and PAYMENTS_HTTP_ADDR, external charges settle by reconciliation only")
+		}
+		logger.Info("psp payment method enabled", "reconcile_after", cfg.PSPReconcileAfter, "charge_timeout", cfg.PSPChargeTimeout, "webhooks", pspWebhook != nil)
+	}
+	consumer.UseMethods(methods)
+	consumer.UseChallenges(cfg.TopicPaymentChallengeRequired, cfg.ChallengeTTL)
+	logger.Info("payment methods registered", "methods", methods.Names())
+	lagReporter := kafkaio.NewLagReporter(cfg.KafkaBrokers, kafkaTransport, cfg.ConsumerGroupID, cfg.TopicPaymentRequested, cfg.LagReportInterval, int64(cfg.LagThreshold), slog.Default().With("service", "payments-service", "component", "kafka"))
 
-	var cacheClient *redis.Client
-	if cfg.RedisAddr != "" {
-		cacheClient = redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
+	backend := cfg.CacheBackend
+	if backend == "" {
+		backend = "memory"
+		if cfg.RedisAddr != "" {
+			backend = "redis"
+		}
+	}
+	var balanceCache cache.BalanceCache
+	switch backend {
+	case "redis":
+		if cfg.RedisAddr == "" {
+			err := errors.New("PAYMENTS_CACHE_BACKEND=redis requires PAYMENTS_REDIS_ADDR")
+			logger.Error("invalid cache config", "err", err)
+			return err
+		}
+		cacheClient := redis.NewClient(&redis.Options{Addr: cfg.RedisAddr})
 		defer func() {
 			if err := cacheClient.Close(); err != nil {
 				logger.Error("failed to close redis client", "err", err)
 			}
 		}()
+		balanceCache = cache.NewRedisBalanceCache(cacheClient, cfg.CacheTTL, cfg.CacheBreakerThreshold, cfg.CacheBreakerCooldown)
+	case "memory":
+		balanceCache = cache.NewMemoryBalanceCache(cfg.CacheMemorySize, cfg.CacheTTL)
+	case "none":
+		logger.Info("balance cache disabled")
+	default:
+		err := fmt.Errorf("unknown cache backend %q", cfg.CacheBackend)
+		logger.Error("invalid cache config", "err", err)
+		return err
 	}
-	balanceCache := cache.NewBalanceCache(cacheClient, cfg.CacheTTL)
 
-	grpcServer := grpc.NewServer(grpc.UnaryInterceptor(grpcUnaryLogger()))
-	paymentsv1.RegisterPaymentsServiceServer(grpcServer, grpcsvc.NewHandlers(repo, balanceCache))
-	reflection.Register(grpcServer)
+	balanceCache = cache.WithFaults(balanceCache, cacheFaults)
+
+	interceptors := []grpc.UnaryServerInterceptor{logging.UnaryServerInterceptor(), grpcUnaryLogger(), deadline.UnaryServerInterceptor(cfg.GRPCDefaultDeadline)}
+	shedConfig := loadshed.Config{MinLimit: cfg.LoadShedMinLimit, MaxLimit: cfg.LoadShedMaxLimit}
+	if shedConfig.Enabled() {
+		routes, err := loadshed.ParseRoutes(cfg.LoadShedRoutes)
+		if err != nil {
+			logger.Error("invalid PAYMENTS_LOADSHED_ROUTES", "err", err)
+			return err
+		}
+		interceptors = append(interceptors, loadshed.UnaryServerInterceptor(loadshed.NewSet("payments-service", shedConfig, routes)))
+		logger.Info("load shedding enabled", "max_limit", cfg.LoadShedMaxLimit, "min_limit", cfg.LoadShedMinLimit, "routes", len(routes))
+	}
+	if serviceAuth.Enabled() {
+		interceptors = append(interceptors, auth.ServiceInterceptor(serviceAuth))
+		logger.Info("service auth enabled", "mode", serviceAuth.Mode)
+	} else {
+		logger.Warn("SERVICE_AUTH_MODE is empty, service auth disabled")
+	}
+	if cfg.JWTSecret != "" {
+		interceptors = append(interceptors, auth.UnaryServerInterceptor([]byte(cfg.JWTSecret)))
+		logger.Info("rbac enabled")
+	} else {
+		logger.Warn("JWT_SECRET is empty, rbac disabled")
+	}
+	grpcServer := grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+	handlers := grpcsvc.NewHandlers(shards, balanceCache, cfg.TopicAccountCreated, grpcsvc.TopUpLimits{
+		MaxPerMinute:     cfg.TopUpMaxPerMinute,
+		MaxAmountPerHour: cfg.TopUpMaxAmountPerHour,
+	}, currency, rateProvider)
+	handlers.UseChallenges(challenge.NewConfirmer(methods, cfg.TopicPaymentResult))
+	paymentsv1.RegisterPaymentsServiceServer(grpcServer, handlers)
+	if cfg.EnableAdminAPI {
+		admin := grpcsvc.NewAdminHandlers(shards, balanceCache, cfg.OutboxReplayMaxEvents, currency, policies)
+		admin.UseInbox(consumer)
+		// Disputes are opened only over orders orders-service reports as
+		// FINISHED; it is asked with the operator's token.
+		clientInterceptors := []grpc.UnaryClientInterceptor{userauth.ForwardToken(), logging.UnaryClientInterceptor()}
+		if serviceAuth.Enabled() {
+			creds, err := auth.ServiceCredentials(serviceAuth)
+			if err != nil {
+				logger.Error("invalid service auth config", "err", err)
+				return err
+			}
+			clientInterceptors = append(clientInterceptors, creds)
+		}
+		ordersConn, err := grpc.DialContext(ctx, cfg.OrdersGRPCAddr,
+			grpc.WithTransportCredentials(insecure.NewCredentials()),
+			grpc.WithChainUnaryInterceptor(clientInterceptors...),
+		)
+		if err != nil {
+			logger.Error("failed to dial orders grpc", "err", err, "addr", cfg.OrdersGRPCAddr)
+			return err
+		}
+		defer ordersConn.Close()
+		admin.UseDisputes(cfg.TopicPaymentDisputeChanged, ordersv1.NewOrdersServiceClient(ordersConn))
+		paymentsv1.RegisterPaymentsAdminServiceServer(grpcServer, admin)
+		if cfg.JWTSecret == "" {
+			logger.Warn("admin api enabled without JWT_SECRET, it is not access-controlled")
+		}
+		logger.Info("admin api enabled")
+	}
+	if cfg.EnableReflection {
+		reflection.Register(grpcServer)
+		logger.Info("grpc reflection enabled")
+	}
 
 	lis, err := net.Listen("tcp", cfg.GRPCAddr)
 	if err != nil {
@@ -102,13 +403,72 @@ func Run(ctx context.Context, cfg config.Config) error {
 		return nil
 	})
 
-	g.Go(func() error {
-		err := outbox.Run(ctx)
+	if cfg.MetricsAddr != "" {
+		mux := http.NewServeMux()
+		mux.Handle("/debug/vars", expvar.Handler())
+		metricsServer := &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		g.Go(func() error {
+			logger.Info("metrics listening", "metrics_addr", cfg.MetricsAddr)
+			if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return metricsServer.Shutdown(shutdownCtx)
+		})
+	}
+
+	if cfg.HTTPAddr != "" {
+		restHandler, err := rest.NewHandler(ctx, cfg.GRPCAddr)
 		if err != nil {
-			logger.Error("outbox publisher stopped with error", "err", err)
+			logger.Error("failed to create rest handler", "err", err)
+			return err
 		}
-		return err
-	})
+		var handler http.Handler = restHandler
+		if pspWebhook != nil {
+			mux := http.NewServeMux()
+			mux.Handle("/psp/webhook", pspWebhook)
+			mux.Handle("/", restHandler)
+			handler = mux
+		}
+		httpServer := &http.Server{
+			Addr:              cfg.HTTPAddr,
+			Handler:           handler,
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		g.Go(func() error {
+			logger.Info("rest listening", "http_addr", cfg.HTTPAddr)
+			if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				return err
+			}
+			return nil
+		})
+		g.Go(func() error {
+			<-ctx.Done()
+			logger.Info("rest shutting down")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return httpServer.Shutdown(shutdownCtx)
+		})
+	}
+
+	for _, outbox := range outboxes {
+		g.Go(func() error {
+			err := outbox.Run(ctx)
+			if err != nil {
+				logger.Error("outbox publisher stopped with error", "err", err)
+			}
+			return err
+		})
+	}
 	g.Go(func() error {
 		err := consumer.Run(ctx)
 		if err != nil {
@@ -117,6 +477,69 @@ func Run(ctx context.Context, cfg config.Config) error {
 		return err
 	})
 
+	g.Go(func() error {
+		return lagReporter.Run(ctx)
+	})
+
+	for _, repo := range repos {
+		if cfg.SnapshotInterval > 0 {
+			snapshots := snapshot.NewJob(repo.Q(), cfg.SnapshotInterval, cfg.SnapshotGrace)
+			g.Go(func() error {
+				return snapshots.Run(ctx)
+			})
+		}
+
+		if rateProvider != nil && cfg.RatesRecordInterval > 0 {
+			recorder := rates.NewRecorder(repo.Q(), rateProvider, currency, cfg.RatesRecordInterval)
+			g.Go(func() error {
+				return recorder.Run(ctx)
+			})
+		}
+
+		if cfg.BalanceCheckInterval > 0 {
+			checker := balancecheck.NewJob(repo.Q(), repo.Shard(), cfg.BalanceCheckInterval, cfg.BalanceCheckBatchSize)
+			g.Go(func() error {
+				return checker.Run(ctx)
+			})
+		}
+
+		expirer := challenge.NewExpirer(repo, cfg.TopicPaymentResult, cfg.ChallengeExpiryInterval)
+		g.Go(func() error {
+			return expirer.Run(ctx)
+		})
+
+		if pspClient != nil {
+			worker := psp.NewWorker(repo, pspClient, cfg.TopicPaymentResult, cfg.PSPPollInterval, cfg.PSPReconcileAfter, cfg.PSPChargeTimeout)
+			g.Go(func() error {
+				return worker.Run(ctx)
+			})
+		}
+
+		if cfg.InboxPayloadRetention > 0 {
+			purger := inbox.NewPurger(repo.Pool(), cfg.InboxPayloadRetention, "payments-service")
+			g.Go(func() error {
+				return purger.Run(ctx)
+			})
+		}
+
+		if cfg.IdempotencyKeyRetention > 0 {
+			sweeper := idempotency.NewSweeper(repo.Pool(), cfg.IdempotencyKeyRetention, "payments-service")
+			g.Go(func() error {
+				return sweeper.Run(ctx)
+			})
+		}
+
+		if cfg.PartitionInterval > 0 {
+			partitions := partition.New(partition.NewPgStore(repo.Pool()), cfg.PartitionMonthsAhead, cfg.PartitionInterval,
+				slog.Default().With("service", "payments-service", "component", "partition", "shard", repo.Shard()),
+				partition.Table{Name: "journal_entries", Retention: cfg.LedgerRetention},
+				partition.Table{Name: "postings", Retention: cfg.LedgerRetention})
+			g.Go(func() error {
+				return partitions.Run(ctx)
+			})
+		}
+	}
+
 	err = g.Wait()
 	if err != nil {
 		logger.Error("payments service stopped with error", "err", err, "duration", time.Since(start))
@@ -16,9 +16,9 @@ func grpcUnaryLogger() grpc.UnaryServerInterceptor {
 		code := status.Code(err)
 		logger := slog.Default().With("service", "payments-service", "component", "grpc")
 		if err != nil {
-			logger.Error("grpc request failed", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start), "err", err)
+			logger.ErrorContext(ctx, "grpc request failed", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start), "err", err)
 		} else {
-			logger.Info("grpc request completed", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start))
+			logger.InfoContext(ctx, "grpc request completed", "method", info.FullMethod, "code", code.String(), "duration", time.Since(start))
 		}
 		return resp, err
 	}
@@ -0,0 +1,25 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/segmentio/kafka-go"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/config"
+	"github.com/ilyaytrewq/payments-service/pkg/startup"
+)
+
+// waitForDependencies holds the service back until Postgres, every account
+// shard and Kafka answer or cfg.StartupWaitTimeout runs out.
+func waitForDependencies(ctx context.Context, cfg config.Config, dialer *kafka.Dialer, logger *slog.Logger) error {
+	deps := []startup.Dependency{
+		startup.Postgres("postgres", cfg.DatabaseURL),
+		startup.Kafka("kafka", dialer, cfg.KafkaBrokers),
+	}
+	for i, url := range cfg.ShardURLs {
+		deps = append(deps, startup.Postgres(fmt.Sprintf("postgres-shard-%d", i+1), url))
+	}
+	return startup.Wait(ctx, startup.Config{Timeout: cfg.StartupWaitTimeout, CheckTimeout: cfg.StartupCheckTimeout}, logger, deps...)
+}
@@ -0,0 +1,107 @@
+package auth
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+var secret = []byte("test-secret")
+
+func mustSign(t *testing.T, c jwt.Claims) string {
+	t.Helper()
+	if c.ExpiresAt == 0 {
+		c.ExpiresAt = time.Now().Add(time.Minute).Unix()
+	}
+	token, err := jwt.Sign(c, secret)
+	if err != nil {
+		t.Fatalf("Sign() error: %v", err)
+	}
+	return token
+}
+
+func call(t *testing.T, method, token string, req interface{}) codes.Code {
+	t.Helper()
+	ctx := context.Background()
+	if token != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(userauth.MetadataKey, "Bearer "+token))
+	}
+	intercept := UnaryServerInterceptor(secret)
+	_, err := intercept(ctx, req, &grpc.UnaryServerInfo{FullMethod: method}, func(ctx context.Context, req interface{}) (interface{}, error) {
+		if _, ok := userauth.FromContext(ctx); !ok && strings.HasPrefix(method, servicePrefix) {
+			t.Fatal("handler called without claims in context")
+		}
+		return nil, nil
+	})
+	return status.Code(err)
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	user := mustSign(t, jwt.Claims{Subject: "u-1", Roles: []jwt.Role{jwt.RoleUser}})
+	support := mustSign(t, jwt.Claims{Subject: "s-1", Roles: []jwt.Role{jwt.RoleSupport}})
+	admin := mustSign(t, jwt.Claims{Subject: "a-1", Roles: []jwt.Role{jwt.RoleAdmin}})
+	topUp := paymentsv1.PaymentsService_TopUp_FullMethodName
+	balance := paymentsv1.PaymentsService_GetBalance_FullMethodName
+
+	tests := []struct {
+		name   string
+		method string
+		token  string
+		req    interface{}
+		want   codes.Code
+	}{
+		{"missing token", topUp, "", &paymentsv1.TopUpRequest{UserId: "u-1"}, codes.Unauthenticated},
+		{"garbage token", topUp, "x.y.z", &paymentsv1.TopUpRequest{UserId: "u-1"}, codes.Unauthenticated},
+		{"own account", topUp, user, &paymentsv1.TopUpRequest{UserId: "u-1"}, codes.OK},
+		{"other user", balance, user, &paymentsv1.GetBalanceRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"support reads any balance", balance, support, &paymentsv1.GetBalanceRequest{UserId: "u-2"}, codes.OK},
+		{"support cannot top up", topUp, support, &paymentsv1.TopUpRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"own transactions", paymentsv1.PaymentsService_ListTransactions_FullMethodName, user, &paymentsv1.ListTransactionsRequest{UserId: "u-1"}, codes.OK},
+		{"other user transactions", paymentsv1.PaymentsService_ListTransactions_FullMethodName, user, &paymentsv1.ListTransactionsRequest{UserId: "u-2"}, codes.PermissionDenied},
+		{"unlisted rpc", "/payments.v1.PaymentsService/Drop", user, nil, codes.PermissionDenied},
+		{"replay needs admin", paymentsv1.PaymentsAdminService_ReplayOutbox_FullMethodName, support, &paymentsv1.ReplayOutboxRequest{}, codes.PermissionDenied},
+		{"admin replays", paymentsv1.PaymentsAdminService_ReplayOutbox_FullMethodName, admin, &paymentsv1.ReplayOutboxRequest{}, codes.OK},
+		{"dead outbox needs admin", paymentsv1.PaymentsAdminService_ListDeadOutbox_FullMethodName, support, &paymentsv1.ListDeadOutboxRequest{}, codes.PermissionDenied},
+		{"admin lists dead outbox", paymentsv1.PaymentsAdminService_ListDeadOutbox_FullMethodName, admin, &paymentsv1.ListDeadOutboxRequest{}, codes.OK},
+		{"support browses outbox", paymentsv1.PaymentsAdminService_ListOutbox_FullMethodName, support, &paymentsv1.ListOutboxRequest{}, codes.OK},
+		{"overdraft needs admin", paymentsv1.PaymentsAdminService_SetOverdraftLimit_FullMethodName, support, &paymentsv1.SetOverdraftLimitRequest{}, codes.PermissionDenied},
+		{"admin sets overdraft", paymentsv1.PaymentsAdminService_SetOverdraftLimit_FullMethodName, admin, &paymentsv1.SetOverdraftLimitRequest{}, codes.OK},
+		{"account type needs admin", paymentsv1.PaymentsAdminService_SetAccountType_FullMethodName, support, &paymentsv1.SetAccountTypeRequest{}, codes.PermissionDenied},
+		{"admin sets account type", paymentsv1.PaymentsAdminService_SetAccountType_FullMethodName, admin, &paymentsv1.SetAccountTypeRequest{}, codes.OK},
+		{"bonus needs admin", paymentsv1.PaymentsAdminService_GrantBonus_FullMethodName, support, &paymentsv1.GrantBonusRequest{}, codes.PermissionDenied},
+		{"admin grants bonus", paymentsv1.PaymentsAdminService_GrantBonus_FullMethodName, admin, &paymentsv1.GrantBonusRequest{}, codes.OK},
+		{"adjustment needs admin", paymentsv1.PaymentsAdminService_AdjustBalance_FullMethodName, support, &paymentsv1.AdjustBalanceRequest{UserId: "u-1"}, codes.PermissionDenied},
+		{"admin adjusts any balance", paymentsv1.PaymentsAdminService_AdjustBalance_FullMethodName, admin, &paymentsv1.AdjustBalanceRequest{UserId: "u-1"}, codes.OK},
+		{"disputes need admin", paymentsv1.PaymentsAdminService_OpenDispute_FullMethodName, support, &paymentsv1.OpenDisputeRequest{}, codes.PermissionDenied},
+		{"admin resolves disputes", paymentsv1.PaymentsAdminService_ResolveDispute_FullMethodName, admin, &paymentsv1.ResolveDisputeRequest{}, codes.OK},
+		{"health is not covered", "/grpc.health.v1.Health/Check", "", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		if got := call(t, tt.method, tt.token, tt.req); got != tt.want {
+			t.Fatalf("%s: code = %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestMethodCallers(t *testing.T) {
+	for method := range methodRoles {
+		if len(methodCallers[method]) == 0 {
+			t.Errorf("%s has roles but no allowed services", method)
+		}
+	}
+	for method := range methodCallers {
+		if _, ok := methodRoles[method]; !ok {
+			t.Errorf("%s has allowed services but no roles", method)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package auth
+
+import (
+	"google.golang.org/grpc"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+// Service identities of the callers, as named in SERVICE_AUTH_TOKENS or in
+// the SPIFFE ids of their tokens.
+const (
+	serviceName  = "payments-service"
+	callerGW     = "api-gateway"
+	callerOrders = "orders-service"
+	callerCtl    = "paymentsctl"
+)
+
+// methodCallers lists the services allowed to call each RPC when service
+// authentication is on. RPCs missing from this table are denied to every
+// service, payments-service included.
+var methodCallers = map[string][]string{
+	paymentsv1.PaymentsService_CreateAccount_FullMethodName:    {callerGW},
+	paymentsv1.PaymentsService_TopUp_FullMethodName:            {callerGW},
+	paymentsv1.PaymentsService_GetBalance_FullMethodName:       {callerGW, callerOrders},
+	paymentsv1.PaymentsService_GetBalances_FullMethodName:      {callerGW, callerCtl},
+	paymentsv1.PaymentsService_GetBalanceAt_FullMethodName:     {callerGW},
+	paymentsv1.PaymentsService_ListTransactions_FullMethodName: {callerGW},
+	paymentsv1.PaymentsService_GetRates_FullMethodName:         {callerGW},
+	paymentsv1.PaymentsService_ConfirmPayment_FullMethodName:   {callerGW},
+
+	paymentsv1.PaymentsAdminService_ReplayOutbox_FullMethodName:         {callerCtl},
+	paymentsv1.PaymentsAdminService_ListDeadOutbox_FullMethodName:       {callerCtl},
+	paymentsv1.PaymentsAdminService_ListOutbox_FullMethodName:           {callerCtl},
+	paymentsv1.PaymentsAdminService_RequeueDeadOutbox_FullMethodName:    {callerCtl},
+	paymentsv1.PaymentsAdminService_SetOverdraftLimit_FullMethodName:    {callerCtl},
+	paymentsv1.PaymentsAdminService_SetAccountType_FullMethodName:       {callerCtl},
+	paymentsv1.PaymentsAdminService_GrantBonus_FullMethodName:           {callerCtl},
+	paymentsv1.PaymentsAdminService_AdjustBalance_FullMethodName:        {callerCtl},
+	paymentsv1.PaymentsAdminService_RematerializeBalance_FullMethodName: {callerCtl},
+	paymentsv1.PaymentsAdminService_OpenDispute_FullMethodName:          {callerCtl},
+	paymentsv1.PaymentsAdminService_ResolveDispute_FullMethodName:       {callerCtl},
+	paymentsv1.PaymentsAdminService_DryRunInboxMessage_FullMethodName:   {callerCtl},
+}
+
+// ServiceInterceptor checks the service token of every call against
+// methodCallers.
+func ServiceInterceptor(cfg svcauth.Config) grpc.UnaryServerInterceptor {
+	return svcauth.UnaryServerInterceptor(cfg, serviceName, servicePrefix, methodCallers)
+}
+
+// ServiceCredentials attaches the token of payments-service to its calls of
+// other services.
+func ServiceCredentials(cfg svcauth.Config) (grpc.UnaryClientInterceptor, error) {
+	return svcauth.UnaryClientInterceptor(cfg, serviceName)
+}
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"log/slog"
+
+	"google.golang.org/grpc"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// servicePrefix marks the RPCs covered by methodRoles; anything else (health,
+// reflection) is not subject to RBAC.
+const servicePrefix = "/payments.v1."
+
+// methodRoles lists the roles allowed to call each RPC. RPCs of the service
+// missing from this table are denied.
+var methodRoles = map[string][]jwt.Role{
+	paymentsv1.PaymentsService_CreateAccount_FullMethodName:    {jwt.RoleUser, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_TopUp_FullMethodName:            {jwt.RoleUser, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_GetBalance_FullMethodName:       {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_GetBalances_FullMethodName:      {jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_GetBalanceAt_FullMethodName:     {jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_ListTransactions_FullMethodName: {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_GetRates_FullMethodName:         {jwt.RoleUser, jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsService_ConfirmPayment_FullMethodName:   {jwt.RoleUser, jwt.RoleAdmin},
+
+	paymentsv1.PaymentsAdminService_ReplayOutbox_FullMethodName:         {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_ListDeadOutbox_FullMethodName:       {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_ListOutbox_FullMethodName:           {jwt.RoleSupport, jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_RequeueDeadOutbox_FullMethodName:    {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_SetOverdraftLimit_FullMethodName:    {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_SetAccountType_FullMethodName:       {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_GrantBonus_FullMethodName:           {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_AdjustBalance_FullMethodName:        {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_RematerializeBalance_FullMethodName: {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_OpenDispute_FullMethodName:          {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_ResolveDispute_FullMethodName:       {jwt.RoleAdmin},
+	paymentsv1.PaymentsAdminService_DryRunInboxMessage_FullMethodName:   {jwt.RoleAdmin},
+}
+
+// UnaryServerInterceptor enforces methodRoles using the bearer token from the
+// authorization metadata. Plain users may only act on their own user_id.
+func UnaryServerInterceptor(secret []byte) grpc.UnaryServerInterceptor {
+	logger := slog.Default().With("service", "payments-service", "component", "auth")
+	return userauth.UnaryServerInterceptor(secret, servicePrefix, methodRoles, logger)
+}
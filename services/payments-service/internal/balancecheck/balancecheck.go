@@ -0,0 +1,185 @@
+// Package balancecheck verifies the stored balances of accounts against the
+// double-entry ledger and that the ledger itself balances.
+package balancecheck
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// metrics counts, per shard, the accounts checked, the discrepancies and
+// the unbalanced journal entries found, and holds the open anomalies after
+// the latest pass.
+var metrics = expvar.NewMap("balance_check")
+
+// defaultBatchSize replaces a batch size that is not positive.
+const defaultBatchSize = 500
+
+// entryOverlap is how far before the start of the previous pass the entry
+// check resumes: an entry is dated by the start of the transaction that
+// books it, which may commit after that pass has read the postings.
+const entryOverlap = time.Hour
+
+// Ledger accounts of a user, as recorded in balance_anomalies.
+const (
+	accountBalance = "balance"
+	accountBonus   = "bonus"
+)
+
+// Job compares the stored balance and bonus balance of every account of one
+// shard with the balances of its balance: and bonus: ledger accounts once
+// per interval. A discrepancy is logged at error level and recorded in
+// balance_anomalies; it is not corrected, that is left to the
+// RematerializeBalance admin RPC for the balance, while a bonus anomaly is
+// resolved by the job once the bonus account matches again. Recording is
+// idempotent, so several replicas may run the job at once.
+//
+// The job also looks for journal entries whose postings do not sum to zero,
+// among those booked since shortly before its previous pass; the first pass
+// reads the whole journal. They are logged at error level.
+type Job struct {
+	q         db.Querier
+	shard     string
+	interval  time.Duration
+	batchSize int32
+	logger    *slog.Logger
+	// entriesSince is where the entry check of the next pass starts.
+	entriesSince pgtype.Timestamptz
+}
+
+// NewJob builds a job for the accounts of shard, read batchSize at a time.
+func NewJob(q db.Querier, shard int, interval time.Duration, batchSize int) *Job {
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	logger := slog.Default().With("service", "payments-service", "component", "balancecheck", "shard", shard)
+	logger.Info("balance checker initialized", "interval", interval.String(), "batch_size", batchSize)
+	return &Job{
+		q:            q,
+		shard:        strconv.Itoa(shard),
+		interval:     interval,
+		batchSize:    int32(batchSize),
+		logger:       logger,
+		entriesSince: pgtype.Timestamptz{InfinityModifier: pgtype.NegativeInfinity, Valid: true},
+	}
+}
+
+// Result is what one pass over the accounts found.
+type Result struct {
+	Checked       int
+	Discrepancies int
+	// New counts the discrepancies that had no open anomaly yet.
+	New int
+	// Open is the number of open anomalies after the pass, including those
+	// of accounts that are consistent again but not rematerialized.
+	Open int64
+	// UnbalancedEntries counts the journal entries whose postings do not
+	// sum to zero, at most one batch per pass.
+	UnbalancedEntries int
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	t := time.NewTicker(j.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			j.logger.Error("balance check failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			j.logger.Info("balance checker stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce checks every account in user_id order, one batch per query, and
+// then the journal entries booked since the previous pass.
+func (j *Job) RunOnce(ctx context.Context) (Result, error) {
+	start := time.Now()
+	var res Result
+	after := ""
+	for {
+		rows, err := j.q.CheckLedgerBalances(ctx, db.CheckLedgerBalancesParams{AfterUserID: after, BatchSize: j.batchSize})
+		if err != nil {
+			return res, fmt.Errorf("check ledger balances: %w", err)
+		}
+		var bonusOK []string
+		for _, r := range rows {
+			res.Checked++
+			if err := j.compare(ctx, &res, r.UserID, accountBalance, r.StoredBalance, r.LedgerBalance); err != nil {
+				return res, err
+			}
+			if r.StoredBonus == r.LedgerBonus {
+				bonusOK = append(bonusOK, r.UserID)
+				continue
+			}
+			if err := j.compare(ctx, &res, r.UserID, accountBonus, r.StoredBonus, r.LedgerBonus); err != nil {
+				return res, err
+			}
+		}
+		if len(bonusOK) > 0 {
+			if _, err := j.q.ResolveBonusAnomalies(ctx, bonusOK); err != nil {
+				return res, fmt.Errorf("resolve bonus anomalies: %w", err)
+			}
+		}
+		if len(rows) < int(j.batchSize) {
+			break
+		}
+		after = rows[len(rows)-1].UserID
+	}
+
+	entries, err := j.q.ListUnbalancedEntries(ctx, db.ListUnbalancedEntriesParams{Since: j.entriesSince, MaxEntries: j.batchSize})
+	if err != nil {
+		return res, fmt.Errorf("list unbalanced entries: %w", err)
+	}
+	for _, e := range entries {
+		j.logger.ErrorContext(ctx, "unbalanced journal entry", "entry_id", e.EntryID, "total", e.Total)
+	}
+	res.UnbalancedEntries = len(entries)
+	j.entriesSince = pgtype.Timestamptz{Time: start.Add(-entryOverlap), Valid: true}
+
+	open, err := j.q.CountOpenBalanceAnomalies(ctx)
+	if err != nil {
+		return res, fmt.Errorf("count open balance anomalies: %w", err)
+	}
+	res.Open = open
+
+	metrics.Add(j.shard+".checked", int64(res.Checked))
+	metrics.Add(j.shard+".discrepancies", int64(res.Discrepancies))
+	metrics.Add(j.shard+".unbalanced_entries", int64(res.UnbalancedEntries))
+	gauge := new(expvar.Int)
+	gauge.Set(open)
+	metrics.Set(j.shard+".open_anomalies", gauge)
+
+	j.logger.Info("balance check completed", "checked", res.Checked, "discrepancies", res.Discrepancies, "new", res.New, "open_anomalies", open, "unbalanced_entries", res.UnbalancedEntries, "duration", time.Since(start))
+	return res, nil
+}
+
+// compare records an anomaly for the account of the user when its stored
+// balance differs from the ledger one.
+func (j *Job) compare(ctx context.Context, res *Result, userID, account string, stored, ledger int64) error {
+	if stored == ledger {
+		return nil
+	}
+	created, err := j.q.RecordBalanceAnomaly(ctx, db.RecordBalanceAnomalyParams{UserID: userID, Account: account, StoredBalance: stored, LedgerBalance: ledger})
+	if err != nil {
+		return fmt.Errorf("record balance anomaly: %w", err)
+	}
+	res.Discrepancies++
+	if created {
+		res.New++
+	}
+	j.logger.ErrorContext(ctx, "balance discrepancy", "user_id", userID, "account", account, "stored_balance", stored, "ledger_balance", ledger, "difference", stored-ledger, "new", created)
+	return nil
+}
@@ -0,0 +1,139 @@
+package balancecheck
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+type fakeQuerier struct {
+	db.Querier
+
+	// balances are the accounts in user_id order.
+	balances []db.CheckLedgerBalancesRow
+	// open holds the open anomalies by user and account.
+	open map[string]db.RecordBalanceAnomalyParams
+	// afters are the after_user_id of each batch read.
+	afters []string
+	// entries are the unbalanced journal entries; sinces are the since of
+	// each read.
+	entries []db.ListUnbalancedEntriesRow
+	sinces  []pgtype.Timestamptz
+}
+
+func (f *fakeQuerier) CheckLedgerBalances(_ context.Context, arg db.CheckLedgerBalancesParams) ([]db.CheckLedgerBalancesRow, error) {
+	f.afters = append(f.afters, arg.AfterUserID)
+	var rows []db.CheckLedgerBalancesRow
+	for _, b := range f.balances {
+		if b.UserID > arg.AfterUserID && len(rows) < int(arg.BatchSize) {
+			rows = append(rows, b)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeQuerier) RecordBalanceAnomaly(_ context.Context, arg db.RecordBalanceAnomalyParams) (bool, error) {
+	key := arg.UserID + "/" + arg.Account
+	_, seen := f.open[key]
+	f.open[key] = arg
+	return !seen, nil
+}
+
+func (f *fakeQuerier) ResolveBonusAnomalies(_ context.Context, userIDs []string) (int64, error) {
+	var n int64
+	for _, id := range userIDs {
+		if _, ok := f.open[id+"/bonus"]; ok {
+			delete(f.open, id+"/bonus")
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (f *fakeQuerier) ListUnbalancedEntries(_ context.Context, arg db.ListUnbalancedEntriesParams) ([]db.ListUnbalancedEntriesRow, error) {
+	f.sinces = append(f.sinces, arg.Since)
+	return f.entries, nil
+}
+
+func (f *fakeQuerier) CountOpenBalanceAnomalies(context.Context) (int64, error) {
+	return int64(len(f.open)), nil
+}
+
+func TestRunOnce(t *testing.T) {
+	q := &fakeQuerier{
+		balances: []db.CheckLedgerBalancesRow{
+			{UserID: "u-1", StoredBalance: 100, LedgerBalance: 100},
+			{UserID: "u-2", StoredBalance: 50, LedgerBalance: 40},
+			{UserID: "u-3", StoredBalance: -20, LedgerBalance: -20},
+			{UserID: "u-4", StoredBalance: 0, LedgerBalance: 5},
+		},
+		open: map[string]db.RecordBalanceAnomalyParams{},
+	}
+	j := NewJob(q, 0, time.Hour, 2)
+	ctx := context.Background()
+
+	res, err := j.RunOnce(ctx)
+	if err != nil || res != (Result{Checked: 4, Discrepancies: 2, New: 2, Open: 2}) {
+		t.Fatalf("RunOnce() = %+v, %v, want 4 checked and 2 new discrepancies", res, err)
+	}
+	// Two full batches, then an empty one ends the pass.
+	if want := []string{"", "u-2", "u-4"}; !slices.Equal(q.afters, want) {
+		t.Fatalf("batches after %q, want %q", q.afters, want)
+	}
+	if a := q.open["u-2/balance"]; a.StoredBalance != 50 || a.LedgerBalance != 40 {
+		t.Fatalf("anomaly of u-2 = %+v, want stored 50, ledger 40", a)
+	}
+
+	// A persisting discrepancy refreshes its anomaly instead of opening a
+	// new one.
+	q.balances[3].StoredBalance = 5
+	q.balances[1].StoredBalance = 45
+	res, err = j.RunOnce(ctx)
+	if err != nil || res != (Result{Checked: 4, Discrepancies: 1, New: 0, Open: 2}) {
+		t.Fatalf("second RunOnce() = %+v, %v, want one known discrepancy", res, err)
+	}
+	if a := q.open["u-2/balance"]; a.StoredBalance != 45 {
+		t.Fatalf("anomaly of u-2 = %+v, want stored 45", a)
+	}
+}
+
+func TestRunOnceBonusAndEntries(t *testing.T) {
+	q := &fakeQuerier{
+		balances: []db.CheckLedgerBalancesRow{
+			{UserID: "u-1", StoredBalance: 100, LedgerBalance: 100, StoredBonus: 30, LedgerBonus: 30},
+			{UserID: "u-2", StoredBalance: 50, LedgerBalance: 50, StoredBonus: 20, LedgerBonus: 25},
+		},
+		open:    map[string]db.RecordBalanceAnomalyParams{},
+		entries: []db.ListUnbalancedEntriesRow{{EntryID: 7, Total: 5}},
+	}
+	j := NewJob(q, 0, time.Hour, 10)
+	ctx := context.Background()
+
+	res, err := j.RunOnce(ctx)
+	if err != nil || res != (Result{Checked: 2, Discrepancies: 1, New: 1, Open: 1, UnbalancedEntries: 1}) {
+		t.Fatalf("RunOnce() = %+v, %v, want one bonus discrepancy and one unbalanced entry", res, err)
+	}
+	if a := q.open["u-2/bonus"]; a.StoredBalance != 20 || a.LedgerBalance != 25 {
+		t.Fatalf("bonus anomaly of u-2 = %+v, want stored 20, ledger 25", a)
+	}
+	if s := q.sinces[0]; s.InfinityModifier != pgtype.NegativeInfinity {
+		t.Fatalf("first entry check since %+v, want the whole journal", s)
+	}
+
+	// A bonus account that matches again resolves its anomaly, and the
+	// entry check goes on from the previous pass.
+	q.balances[1].StoredBonus = 25
+	q.entries = nil
+	res, err = j.RunOnce(ctx)
+	if err != nil || res != (Result{Checked: 2}) {
+		t.Fatalf("second RunOnce() = %+v, %v, want no discrepancies", res, err)
+	}
+	if s := q.sinces[1]; s.InfinityModifier != pgtype.Finite || time.Since(s.Time) < entryOverlap {
+		t.Fatalf("second entry check since %+v, want before the previous pass", s)
+	}
+}
@@ -0,0 +1,29 @@
+package cache
+
+import "context"
+
+// BalanceCache caches account balances by user id. Implementations treat a
+// miss as (nil, nil) and never fail the caller's request on their own.
+type BalanceCache interface {
+	Get(ctx context.Context, userID string) (*Balance, error)
+	// GetMany looks up several entries in one round trip; misses are absent
+	// from the result.
+	GetMany(ctx context.Context, userIDs []string) (map[string]*Balance, error)
+	Set(ctx context.Context, balance Balance) error
+}
+
+type Balance struct {
+	UserID  string `json:"user_id"`
+	Balance int64  `json:"balance"`
+	Version int64  `json:"version,omitempty"`
+	// AccountType is the accounts.account_type value, e.g. "BASIC".
+	AccountType string `json:"account_type,omitempty"`
+	// BonusBalance is the unexpired bonus when the entry was cached.
+	BonusBalance int64 `json:"bonus_balance,omitempty"`
+}
+
+var (
+	_ BalanceCache = (*RedisBalanceCache)(nil)
+	_ BalanceCache = (*MemoryBalanceCache)(nil)
+	_ BalanceCache = (*faultyCache)(nil)
+)
@@ -0,0 +1,43 @@
+package cache
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+)
+
+// WithFaults returns c delaying or failing calls as f decides, the way an
+// unreachable Redis would; callers already treat cache errors as misses. A
+// nil f returns c as is.
+func WithFaults(c BalanceCache, f *chaos.Injector) BalanceCache {
+	if c == nil || f == nil {
+		return c
+	}
+	return &faultyCache{c: c, faults: f}
+}
+
+type faultyCache struct {
+	c      BalanceCache
+	faults *chaos.Injector
+}
+
+func (fc *faultyCache) Get(ctx context.Context, userID string) (*Balance, error) {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return fc.c.Get(ctx, userID)
+}
+
+func (fc *faultyCache) GetMany(ctx context.Context, userIDs []string) (map[string]*Balance, error) {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return nil, err
+	}
+	return fc.c.GetMany(ctx, userIDs)
+}
+
+func (fc *faultyCache) Set(ctx context.Context, balance Balance) error {
+	if err := fc.faults.Inject(ctx); err != nil {
+		return err
+	}
+	return fc.c.Set(ctx, balance)
+}
@@ -0,0 +1,44 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/lru"
+)
+
+// MemoryBalanceCache is an in-process BalanceCache used when Redis is not
+// configured. Each instance has its own copy, so entries are only as fresh
+// as the TTL allows.
+type MemoryBalanceCache struct {
+	entries *lru.Cache[Balance]
+}
+
+func NewMemoryBalanceCache(size int, ttl time.Duration) *MemoryBalanceCache {
+	slog.Default().With("service", "payments-service", "component", "cache").Info("in-memory balance cache initialized", "size", size, "ttl", ttl.String())
+	return &MemoryBalanceCache{entries: lru.New[Balance](size, ttl)}
+}
+
+func (c *MemoryBalanceCache) Get(_ context.Context, userID string) (*Balance, error) {
+	balance, ok := c.entries.Get(userID)
+	if !ok {
+		return nil, nil
+	}
+	return &balance, nil
+}
+
+func (c *MemoryBalanceCache) GetMany(_ context.Context, userIDs []string) (map[string]*Balance, error) {
+	out := make(map[string]*Balance, len(userIDs))
+	for _, id := range userIDs {
+		if v, ok := c.entries.Get(id); ok {
+			out[id] = &v
+		}
+	}
+	return out, nil
+}
+
+func (c *MemoryBalanceCache) Set(_ context.Context, balance Balance) error {
+	c.entries.Set(balance.UserID, balance)
+	return nil
+}
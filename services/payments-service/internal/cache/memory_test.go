@@ -0,0 +1,37 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryBalanceCache(t *testing.T) {
+	c := NewMemoryBalanceCache(10, time.Minute)
+	ctx := context.Background()
+	if got, err := c.Get(ctx, "user-1"); err != nil || got != nil {
+		t.Fatalf("Get() on empty cache = (%v, %v), want (nil, nil)", got, err)
+	}
+	if err := c.Set(ctx, Balance{UserID: "user-1", Balance: 10}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	got, err := c.Get(ctx, "user-1")
+	if err != nil || got == nil || got.Balance != 10 {
+		t.Fatalf("Get() = (%v, %v), want balance 10", got, err)
+	}
+}
+
+func TestMemoryBalanceCacheGetMany(t *testing.T) {
+	c := NewMemoryBalanceCache(10, time.Minute)
+	ctx := context.Background()
+	if err := c.Set(ctx, Balance{UserID: "a", Balance: 1}); err != nil {
+		t.Fatalf("Set() error: %v", err)
+	}
+	got, err := c.GetMany(ctx, []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("GetMany() error: %v", err)
+	}
+	if len(got) != 1 || got["a"] == nil || got["a"].UserID != "a" {
+		t.Fatalf("GetMany() = %v, want only a", got)
+	}
+}
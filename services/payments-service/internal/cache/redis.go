@@ -7,57 +7,133 @@ import (
 	"time"
 
 	"github.com/redis/go-redis/v9"
-)
 
-type BalanceCache struct {
-	client *redis.Client
-	ttl    time.Duration
-}
+	"github.com/ilyaytrewq/payments-service/pkg/breaker"
+)
 
-type Balance struct {
-	UserID  string `json:"user_id"`
-	Balance int64  `json:"balance"`
+// RedisBalanceCache is the BalanceCache shared by all instances through Redis.
+type RedisBalanceCache struct {
+	client  *redis.Client
+	ttl     time.Duration
+	breaker *breaker.Breaker
+	// pending holds the keys whose write was skipped or failed; they are
+	// deleted before the first call once Redis is back.
+	pending *breaker.Pending
 }
 
-func NewBalanceCache(client *redis.Client, ttl time.Duration) *BalanceCache {
+func NewRedisBalanceCache(client *redis.Client, ttl time.Duration, breakerThreshold int, breakerCooldown time.Duration) *RedisBalanceCache {
 	if client == nil {
 		slog.Default().With("service", "payments-service", "component", "cache").Info("balance cache disabled")
 		return nil
 	}
 	slog.Default().With("service", "payments-service", "component", "cache").Info("balance cache initialized", "ttl", ttl.String())
-	return &BalanceCache{client: client, ttl: ttl}
+	return &RedisBalanceCache{
+		client:  client,
+		ttl:     ttl,
+		breaker: breaker.New("balance", breakerThreshold, breakerCooldown, slog.Default().With("service", "payments-service", "component", "cache")),
+		pending: breaker.NewPending("balance", ttl),
+	}
+}
+
+// allow reports whether Redis may be called. Balances left stale while it
+// was unreachable are deleted first, so no call can read them; if that
+// fails, Redis is still treated as down.
+func (c *RedisBalanceCache) allow(ctx context.Context) bool {
+	if !c.breaker.Allow() {
+		return false
+	}
+	keys := c.pending.Take()
+	if len(keys) == 0 {
+		return true
+	}
+	logger := slog.Default().With("service", "payments-service", "component", "cache")
+	if err := c.client.Del(ctx, keys...).Err(); err != nil {
+		c.breaker.Failure()
+		for _, k := range keys {
+			c.pending.Add(k)
+		}
+		logger.Error("balance cache pending invalidations failed", "keys", len(keys), "err", err)
+		return false
+	}
+	logger.Info("balance cache pending invalidations applied", "keys", len(keys))
+	return true
 }
 
-func (c *BalanceCache) Get(ctx context.Context, userID string) (*Balance, error) {
+func (c *RedisBalanceCache) Get(ctx context.Context, userID string) (*Balance, error) {
 	start := time.Now()
 	logger := slog.Default().With("service", "payments-service", "component", "cache")
 	if c == nil {
-		logger.Info("balance cache get skipped (nil cache)", "user_id", userID)
+		logger.Debug("balance cache get skipped (nil cache)", "user_id", userID)
+		return nil, nil
+	}
+	if !c.allow(ctx) {
+		logger.Debug("balance cache get skipped (circuit open)", "user_id", userID)
 		return nil, nil
 	}
 	val, err := c.client.Get(ctx, key(userID)).Result()
 	if err == redis.Nil {
-		logger.Info("balance cache miss", "user_id", userID, "duration", time.Since(start))
+		c.breaker.Success()
+		logger.Debug("balance cache miss", "user_id", userID, "duration", time.Since(start))
 		return nil, nil
 	}
 	if err != nil {
+		c.breaker.Failure()
 		logger.Error("balance cache get failed", "user_id", userID, "err", err, "duration", time.Since(start))
 		return nil, err
 	}
+	c.breaker.Success()
 	var cached Balance
 	if err := json.Unmarshal([]byte(val), &cached); err != nil {
 		logger.Error("balance cache unmarshal failed", "user_id", userID, "err", err, "duration", time.Since(start))
 		return nil, err
 	}
-	logger.Info("balance cache hit", "user_id", userID, "duration", time.Since(start))
+	logger.Debug("balance cache hit", "user_id", userID, "duration", time.Since(start))
 	return &cached, nil
 }
 
-func (c *BalanceCache) Set(ctx context.Context, balance Balance) error {
+func (c *RedisBalanceCache) GetMany(ctx context.Context, userIDs []string) (map[string]*Balance, error) {
+	start := time.Now()
+	logger := slog.Default().With("service", "payments-service", "component", "cache")
+	out := make(map[string]*Balance, len(userIDs))
+	if c == nil || len(userIDs) == 0 {
+		return out, nil
+	}
+	if !c.allow(ctx) {
+		logger.Debug("balance cache mget skipped (circuit open)", "keys", len(userIDs))
+		return out, nil
+	}
+	keys := make([]string, len(userIDs))
+	for i, id := range userIDs {
+		keys[i] = key(id)
+	}
+	vals, err := c.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		c.breaker.Failure()
+		logger.Error("balance cache mget failed", "keys", len(userIDs), "err", err, "duration", time.Since(start))
+		return out, err
+	}
+	c.breaker.Success()
+	for i, v := range vals {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		var cached Balance
+		if err := json.Unmarshal([]byte(raw), &cached); err != nil {
+			logger.Error("balance cache unmarshal failed", "user_id", userIDs[i], "err", err)
+			continue
+		}
+		out[userIDs[i]] = &cached
+	}
+	logger.Debug("balance cache mget", "keys", len(userIDs), "hits", len(out), "duration", time.Since(start))
+	return out, nil
+}
+
+func (c *RedisBalanceCache) Set(ctx context.Context, balance Balance) error {
 	start := time.Now()
 	logger := slog.Default().With("service", "payments-service", "component", "cache")
 	if c == nil {
-		logger.Info("balance cache set skipped (nil cache)", "user_id", balance.UserID)
+		logger.Debug("balance cache set skipped (nil cache)", "user_id", balance.UserID)
 		return nil
 	}
 	data, err := json.Marshal(balance)
@@ -65,15 +141,24 @@ func (c *BalanceCache) Set(ctx context.Context, balance Balance) error {
 		logger.Error("balance cache marshal failed", "user_id", balance.UserID, "err", err, "duration", time.Since(start))
 		return err
 	}
+	// A balance that is not written leaves the previous one in Redis; it is
+	// deleted once Redis is back.
+	if !c.allow(ctx) {
+		c.pending.Add(key(balance.UserID))
+		logger.Debug("balance cache set skipped (circuit open)", "user_id", balance.UserID)
+		return nil
+	}
 	if err := c.client.Set(ctx, key(balance.UserID), data, c.ttl).Err(); err != nil {
+		c.breaker.Failure()
+		c.pending.Add(key(balance.UserID))
 		logger.Error("balance cache set failed", "user_id", balance.UserID, "err", err, "duration", time.Since(start))
 		return err
 	}
-	logger.Info("balance cache set", "user_id", balance.UserID, "duration", time.Since(start))
+	c.breaker.Success()
+	logger.Debug("balance cache set", "user_id", balance.UserID, "duration", time.Since(start))
 	return nil
 }
 
 func key(userID string) string {
-	slog.Default().With("service", "payments-service", "component", "cache").Info("balance cache key generated", "user_id", userID)
 	return "payments:balance:" + userID
 }
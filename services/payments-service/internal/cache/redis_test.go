@@ -2,23 +2,29 @@ package cache
 
 import (
 	"context"
+	"slices"
 	"testing"
 	"time"
+
+	"github.com/redis/go-redis/v9"
 )
 
-func TestNewBalanceCacheNilClient(t *testing.T) {
-	if got := NewBalanceCache(nil, time.Second); got != nil {
-		t.Fatal("NewBalanceCache(nil) should return nil")
+func TestNewRedisBalanceCacheNilClient(t *testing.T) {
+	if got := NewRedisBalanceCache(nil, time.Second, 5, time.Second); got != nil {
+		t.Fatal("NewRedisBalanceCache(nil) should return nil")
 	}
 }
 
-func TestBalanceCacheNilReceiver(t *testing.T) {
-	var c *BalanceCache
+func TestRedisBalanceCacheNilReceiver(t *testing.T) {
+	var c *RedisBalanceCache
 	if got, err := c.Get(context.Background(), "user-1"); err != nil || got != nil {
-		t.Fatalf("BalanceCache.Get(nil) = (%v, %v), want (nil, nil)", got, err)
+		t.Fatalf("RedisBalanceCache.Get(nil) = (%v, %v), want (nil, nil)", got, err)
+	}
+	if got, err := c.GetMany(context.Background(), []string{"a"}); err != nil || len(got) != 0 {
+		t.Fatalf("RedisBalanceCache.GetMany(nil) = (%v, %v), want empty", got, err)
 	}
 	if err := c.Set(context.Background(), Balance{UserID: "user-1", Balance: 10}); err != nil {
-		t.Fatalf("BalanceCache.Set(nil) error: %v", err)
+		t.Fatalf("RedisBalanceCache.Set(nil) error: %v", err)
 	}
 }
 
@@ -27,3 +33,23 @@ func TestBalanceCacheKey(t *testing.T) {
 		t.Fatalf("key() = %q, want %q", got, "payments:balance:user-123")
 	}
 }
+
+func TestRedisBalanceCacheSetWhileDown(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+	c := NewRedisBalanceCache(client, time.Minute, 1, time.Minute)
+	ctx := context.Background()
+
+	if err := c.Set(ctx, Balance{UserID: "user-1", Balance: 10}); err == nil {
+		t.Fatal("Set() with Redis down: want an error")
+	}
+	// The circuit is open now: the write is not attempted, only recorded.
+	if err := c.Set(ctx, Balance{UserID: "user-2", Balance: 20}); err != nil {
+		t.Fatalf("Set() with the circuit open: %v", err)
+	}
+	got := c.pending.Take()
+	slices.Sort(got)
+	if want := []string{key("user-1"), key("user-2")}; !slices.Equal(got, want) {
+		t.Fatalf("pending = %v, want %v", got, want)
+	}
+}
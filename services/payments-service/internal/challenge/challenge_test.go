@@ -0,0 +1,230 @@
+package challenge
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// fakeRepo is an in-memory single-shard repository of payment challenges.
+// Queries the package does not use fall through to the nil embedded Querier
+// and panic.
+type fakeRepo struct {
+	db.Querier
+
+	challenges []*db.PaymentChallenge
+	// grants are the remaining amounts of bonus grants by id.
+	grants map[int64]int64
+	ops    []db.RecordExternalPaymentParams
+	outbox []db.InsertOutboxParams
+}
+
+var _ repo.PaymentsRepository = (*fakeRepo)(nil)
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{grants: map[int64]int64{}}
+}
+
+func (f *fakeRepo) Q() db.Querier { return f }
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error {
+	return fn(f)
+}
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	return fn(f)
+}
+
+// add records a pending challenge of amount for order, half of it held from
+// bonus grant 7, expiring after ttl.
+func (f *fakeRepo) add(order uuid.UUID, amount int64, ttl time.Duration) *db.PaymentChallenge {
+	now := time.Now()
+	ch := &db.PaymentChallenge{
+		PaymentID:         pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		OrderID:           pgtype.UUID{Bytes: order, Valid: true},
+		UserID:            "u-1",
+		Method:            method.Card,
+		Amount:            amount,
+		Bonus:             amount / 2,
+		Fee:               10,
+		BonusGrantIds:     []int64{7},
+		BonusGrantAmounts: []int64{amount / 2},
+		CorrelationID:     "req-1",
+		Status:            "pending",
+		CreatedAt:         pgtype.Timestamptz{Time: now, Valid: true},
+		ExpiresAt:         pgtype.Timestamptz{Time: now.Add(ttl), Valid: true},
+	}
+	f.challenges = append(f.challenges, ch)
+	return ch
+}
+
+func (f *fakeRepo) LockPendingChallenge(_ context.Context, arg db.LockPendingChallengeParams) (db.PaymentChallenge, error) {
+	for _, ch := range f.challenges {
+		if ch.UserID == arg.UserID && ch.OrderID == arg.OrderID && (!arg.PaymentID.Valid || ch.PaymentID == arg.PaymentID) &&
+			ch.Status == "pending" && ch.ExpiresAt.Time.After(time.Now()) {
+			return *ch, nil
+		}
+	}
+	return db.PaymentChallenge{}, pgx.ErrNoRows
+}
+
+func (f *fakeRepo) LockExpiredChallenges(_ context.Context, batchSize int32) ([]db.PaymentChallenge, error) {
+	var out []db.PaymentChallenge
+	for _, ch := range f.challenges {
+		if ch.Status == "pending" && !ch.ExpiresAt.Time.After(time.Now()) && len(out) < int(batchSize) {
+			out = append(out, *ch)
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) FinishPaymentChallenge(_ context.Context, arg db.FinishPaymentChallengeParams) error {
+	for _, ch := range f.challenges {
+		if ch.PaymentID == arg.PaymentID {
+			ch.Status = arg.Status
+			ch.FinishedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) RecordExternalPayment(_ context.Context, arg db.RecordExternalPaymentParams) (int64, error) {
+	f.ops = append(f.ops, arg)
+	return 1, nil
+}
+
+func (f *fakeRepo) RestoreBonusGrants(_ context.Context, arg db.RestoreBonusGrantsParams) error {
+	for i, id := range arg.Ids {
+		f.grants[id] += arg.Amounts[i]
+	}
+	return nil
+}
+
+func (f *fakeRepo) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	f.outbox = append(f.outbox, arg)
+	return int64(len(f.outbox)), nil
+}
+
+// results decodes the PaymentResults in the outbox.
+func (f *fakeRepo) results(t *testing.T) []*eventsv1.PaymentResult {
+	t.Helper()
+	var out []*eventsv1.PaymentResult
+	for _, row := range f.outbox {
+		var r eventsv1.PaymentResult
+		if err := kafka.UnmarshalEvent(row.Payload, &r); err != nil {
+			t.Fatalf("unmarshal payment result: %v", err)
+		}
+		out = append(out, &r)
+	}
+	return out
+}
+
+func newConfirmer() *Confirmer {
+	methods := method.NewRegistry()
+	methods.Register(method.Card, method.MockCard{DeclineAbove: 1000, ChallengeAbove: 100})
+	return NewConfirmer(methods, "results")
+}
+
+func TestConfirm(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	c := newConfirmer()
+	order := uuid.New()
+	orderID := pgtype.UUID{Bytes: order, Valid: true}
+	first := f.add(order, 500, time.Hour)
+	second := f.add(order, 300, time.Hour)
+
+	// The payment id picks the challenge; without it the oldest goes first.
+	out, err := c.Confirm(ctx, f, "u-1", orderID, second.PaymentID)
+	if err != nil || out.Status != Succeeded || out.PaymentID != uuid.UUID(second.PaymentID.Bytes).String() {
+		t.Fatalf("Confirm(second) = %+v, %v; want the second payment succeeded", out, err)
+	}
+	out, err = c.Confirm(ctx, f, "u-1", orderID, pgtype.UUID{})
+	if err != nil || out.Status != Succeeded || out.PaymentID != uuid.UUID(first.PaymentID.Bytes).String() {
+		t.Fatalf("Confirm() = %+v, %v; want the first payment succeeded", out, err)
+	}
+	if _, err := c.Confirm(ctx, f, "u-1", orderID, pgtype.UUID{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Confirm() again error = %v, want ErrNotFound", err)
+	}
+	if _, err := c.Confirm(ctx, f, "u-2", pgtype.UUID{Bytes: uuid.New(), Valid: true}, pgtype.UUID{}); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Confirm() of another order error = %v, want ErrNotFound", err)
+	}
+
+	if len(f.ops) != 2 || f.ops[0].Bonus != 150 || f.ops[0].Fee != 10 {
+		t.Fatalf("ops = %+v, want two card payments", f.ops)
+	}
+	results := f.results(t)
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want two", results)
+	}
+	r := results[0]
+	if r.GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS || r.GetOrderId() != order.String() ||
+		r.GetPaymentId() != uuid.UUID(second.PaymentID.Bytes).String() || r.GetFee() != 10 || r.GetCorrelationId() != "req-1" {
+		t.Fatalf("result = %v, want the second payment succeeded", r)
+	}
+	if f.outbox[0].KafkaKey != order.String() || f.outbox[0].Topic != "results" {
+		t.Fatalf("outbox row = %+v, want keyed by the order on results", f.outbox[0])
+	}
+	if len(f.grants) != 0 {
+		t.Fatalf("grants = %v, want none given back", f.grants)
+	}
+}
+
+func TestConfirmDeclined(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	order := uuid.New()
+	ch := f.add(order, 4000, time.Hour)
+
+	// 2000 + 10 is above the card limit.
+	out, err := newConfirmer().Confirm(ctx, f, "u-1", pgtype.UUID{Bytes: order, Valid: true}, pgtype.UUID{})
+	if err != nil || out.Status != Failed || out.Reason == "" {
+		t.Fatalf("Confirm() = %+v, %v; want failed with a reason", out, err)
+	}
+	if ch.Status != "confirmed" || len(f.ops) != 0 || f.grants[7] != 2000 {
+		t.Fatalf("challenge %s, ops %v, grant 7 got %d back; want confirmed, none, 2000", ch.Status, f.ops, f.grants[7])
+	}
+	results := f.results(t)
+	if len(results) != 1 || results[0].GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED || results[0].GetFee() != 0 {
+		t.Fatalf("results = %v, want one declined", results)
+	}
+}
+
+func TestExpirer(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	// The initial payment of an order has the order's id.
+	order := uuid.New()
+	expired := f.add(order, 500, -time.Second)
+	expired.PaymentID = expired.OrderID
+	live := f.add(uuid.New(), 500, time.Hour)
+
+	e := NewExpirer(f, "results", time.Second)
+	for want := 1; want >= 0; want-- {
+		if n, err := e.RunOnce(ctx); err != nil || n != want {
+			t.Fatalf("RunOnce() = %d, %v; want %d, nil", n, err, want)
+		}
+	}
+	if expired.Status != "expired" || live.Status != "pending" {
+		t.Fatalf("statuses = %s, %s; want expired, pending", expired.Status, live.Status)
+	}
+	if f.grants[7] != 250 {
+		t.Fatalf("grant 7 got %d back, want 250", f.grants[7])
+	}
+	results := f.results(t)
+	if len(results) != 1 || results[0].GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED ||
+		results[0].GetOrderId() != order.String() || results[0].GetPaymentId() != "" || results[0].GetReason() != expiredReason {
+		t.Fatalf("results = %v, want the order's challenge expired", results)
+	}
+}
@@ -0,0 +1,161 @@
+// Package challenge finishes payments that wait for the payer's
+// confirmation, like 3-D Secure.
+//
+// A payment method that answers method.ErrChallenge leaves the payment in
+// payment_challenges with its bonus part held, and PaymentChallengeRequired
+// goes out. Confirm makes the payment again with Confirmed set and writes
+// its PaymentResult; the Expirer fails the challenges not confirmed in time,
+// which cancels their orders.
+package challenge
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// ErrNotFound means the order has no challenge waiting for confirmation:
+// there never was one, it was confirmed already or it expired.
+var ErrNotFound = errors.New("no payment waits for confirmation")
+
+// Status is how a confirmed payment went.
+type Status int
+
+const (
+	// Succeeded means the payment was made.
+	Succeeded Status = iota + 1
+	// Failed means the payment method declined it.
+	Failed
+	// Pending means the payment method settles it later and reports the
+	// result itself.
+	Pending
+)
+
+// Outcome is the result of a confirmation.
+type Outcome struct {
+	PaymentID string
+	Status    Status
+	// Reason says why a failed payment failed.
+	Reason string
+}
+
+// Confirmer makes the payments their payers confirmed; the PaymentResults
+// go to topic.
+type Confirmer struct {
+	methods *method.Registry
+	topic   string
+}
+
+func NewConfirmer(methods *method.Registry, topic string) *Confirmer {
+	return &Confirmer{methods: methods, topic: topic}
+}
+
+// Confirm makes the oldest payment of the user's order waiting for
+// confirmation, or paymentID when it is valid. Run it in a transaction.
+func (c *Confirmer) Confirm(ctx context.Context, q db.Querier, userID string, orderID, paymentID pgtype.UUID) (Outcome, error) {
+	logger := slog.Default().With("service", "payments-service", "component", "challenge")
+	ch, err := q.LockPendingChallenge(ctx, db.LockPendingChallengeParams{UserID: userID, OrderID: orderID, PaymentID: paymentID})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Outcome{}, ErrNotFound
+	}
+	if err != nil {
+		return Outcome{}, fmt.Errorf("lock payment challenge: %w", err)
+	}
+	out := Outcome{PaymentID: uuid.UUID(ch.PaymentID.Bytes).String()}
+
+	provider, err := c.methods.Lookup(ch.Method)
+	if err != nil {
+		return Outcome{}, err
+	}
+	spends := make([]method.BonusSpend, len(ch.BonusGrantIds))
+	for i, id := range ch.BonusGrantIds {
+		spends[i] = method.BonusSpend{GrantID: id, Amount: ch.BonusGrantAmounts[i]}
+	}
+	err = provider.Pay(ctx, q, method.Charge{
+		PaymentID:     ch.PaymentID,
+		OrderID:       ch.OrderID,
+		UserID:        ch.UserID,
+		Amount:        ch.Amount,
+		Bonus:         ch.Bonus,
+		Fee:           ch.Fee,
+		BonusSpends:   spends,
+		CorrelationID: ch.CorrelationID,
+		Confirmed:     true,
+	})
+	status := eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS
+	fee := ch.Fee
+	switch {
+	case err == nil:
+		out.Status = Succeeded
+	case errors.Is(err, method.ErrPending):
+		// The bonus stays held; the provider settles the charge.
+		out.Status = Pending
+	case errors.Is(err, method.ErrDeclined), errors.Is(err, method.ErrNotEnoughFunds):
+		out.Status, out.Reason = Failed, err.Error()
+		status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED
+		if errors.Is(err, method.ErrNotEnoughFunds) {
+			status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS
+		}
+		fee = 0
+		if err := restoreBonus(ctx, q, ch); err != nil {
+			return Outcome{}, err
+		}
+	default:
+		return Outcome{}, fmt.Errorf("pay confirmed charge: %w", err)
+	}
+
+	if err := q.FinishPaymentChallenge(ctx, db.FinishPaymentChallengeParams{Status: "confirmed", PaymentID: ch.PaymentID}); err != nil {
+		return Outcome{}, fmt.Errorf("finish payment challenge: %w", err)
+	}
+	if out.Status != Pending {
+		if err := enqueueResult(ctx, q, c.topic, ch, status, out.Reason, fee); err != nil {
+			return Outcome{}, err
+		}
+	}
+	logger.InfoContext(ctx, "payment confirmed", "order_id", uuid.UUID(ch.OrderID.Bytes).String(), "user_id", ch.UserID, "payment_method", ch.Method, "status", int(out.Status), "reason", out.Reason)
+	return out, nil
+}
+
+// restoreBonus gives the bonus held by ch back to its grants.
+func restoreBonus(ctx context.Context, q db.Querier, ch db.PaymentChallenge) error {
+	if len(ch.BonusGrantIds) == 0 {
+		return nil
+	}
+	if err := q.RestoreBonusGrants(ctx, db.RestoreBonusGrantsParams{Ids: ch.BonusGrantIds, Amounts: ch.BonusGrantAmounts}); err != nil {
+		return fmt.Errorf("restore bonus grants: %w", err)
+	}
+	return nil
+}
+
+// enqueueResult writes the PaymentResult of ch to topic through the outbox.
+func enqueueResult(ctx context.Context, q db.Querier, topic string, ch db.PaymentChallenge, status eventsv1.PaymentResultStatus, reason string, fee int64) error {
+	// The initial full payment of an order is keyed by the order id and
+	// reported without a payment id, as in PaymentRequested.
+	var paymentID string
+	if ch.PaymentID.Bytes != ch.OrderID.Bytes {
+		paymentID = uuid.UUID(ch.PaymentID.Bytes).String()
+	}
+	return kafka.EnqueuePaymentResult(ctx, q, topic, &eventsv1.PaymentResult{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       uuid.UUID(ch.OrderID.Bytes).String(),
+		UserId:        ch.UserID,
+		Status:        status,
+		Reason:        reason,
+		PaymentId:     paymentID,
+		Amount:        ch.Amount,
+		Fee:           fee,
+		CorrelationId: ch.CorrelationID,
+	})
+}
@@ -0,0 +1,80 @@
+package challenge
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+const expireBatchSize = 100
+
+// expiredReason is the PaymentResult reason of an expired challenge.
+const expiredReason = "payment not confirmed in time"
+
+// Expirer fails the challenges of one shard that were not confirmed before
+// they expired: the held bonus goes back and a FAIL_CHALLENGE_EXPIRED
+// PaymentResult is written to topic. Several replicas may run it at once.
+type Expirer struct {
+	repo     repo.PaymentsRepository
+	topic    string
+	interval time.Duration
+}
+
+func NewExpirer(r repo.PaymentsRepository, topic string, interval time.Duration) *Expirer {
+	return &Expirer{repo: r, topic: topic, interval: interval}
+}
+
+func (e *Expirer) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "payments-service", "component", "challenge")
+	logger.Info("challenge expirer started", "interval", e.interval.String())
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := e.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("challenge expiry failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("challenge expirer stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce expires one batch of challenges in a transaction and returns how
+// many it expired.
+func (e *Expirer) RunOnce(ctx context.Context) (int, error) {
+	logger := slog.Default().With("service", "payments-service", "component", "challenge")
+	var n int
+	err := e.repo.InTx(ctx, func(q db.Querier) error {
+		n = 0
+		expired, err := q.LockExpiredChallenges(ctx, expireBatchSize)
+		if err != nil {
+			return fmt.Errorf("lock expired challenges: %w", err)
+		}
+		for _, ch := range expired {
+			if err := restoreBonus(ctx, q, ch); err != nil {
+				return err
+			}
+			if err := q.FinishPaymentChallenge(ctx, db.FinishPaymentChallengeParams{Status: "expired", PaymentID: ch.PaymentID}); err != nil {
+				return fmt.Errorf("finish payment challenge: %w", err)
+			}
+			if err := enqueueResult(ctx, q, e.topic, ch, eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_CHALLENGE_EXPIRED, expiredReason, 0); err != nil {
+				return err
+			}
+			logger.InfoContext(ctx, "payment challenge expired", "order_id", uuid.UUID(ch.OrderID.Bytes).String(), "user_id", ch.UserID)
+			n++
+		}
+		return nil
+	})
+	return n, err
+}
@@ -0,0 +1,114 @@
+// Package fees computes the fee charged on top of a payment.
+package fees
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// maxBPS caps the percentage part at 100% of the payment.
+const maxBPS = 10000
+
+// Rule is one line of the fee schedule.
+type Rule struct {
+	// AccountType limits the rule to one account type; "" matches any.
+	AccountType policy.Type
+	// MinAmount and MaxAmount bound the payment amounts the rule covers,
+	// both inclusive; MaxAmount 0 means no upper bound.
+	MinAmount int64
+	MaxAmount int64
+	// BPS is the percentage part in basis points of the amount, rounded up
+	// to a whole minor unit; Fixed is added on top of it.
+	BPS   int64
+	Fixed int64
+}
+
+func (r Rule) matches(t policy.Type, amount int64) bool {
+	if r.AccountType != "" && r.AccountType != t {
+		return false
+	}
+	return amount >= r.MinAmount && (r.MaxAmount == 0 || amount <= r.MaxAmount)
+}
+
+// fee is computed in two parts so that amount*BPS cannot overflow.
+func (r Rule) fee(amount int64) int64 {
+	pct := amount/maxBPS*r.BPS + (amount%maxBPS*r.BPS+maxBPS-1)/maxBPS
+	return pct + r.Fixed
+}
+
+// Schedule is an ordered list of rules. The first rule matching a payment
+// sets its fee; a payment no rule matches is free.
+type Schedule []Rule
+
+// Fee returns the fee for a payment of amount from an account of type t.
+func (s Schedule) Fee(t policy.Type, amount int64) int64 {
+	for _, r := range s {
+		if r.matches(t, amount) {
+			return r.fee(amount)
+		}
+	}
+	return 0
+}
+
+// Parse reads a schedule in the PAYMENTS_FEE_RULES format: semicolon
+// separated rules of comma separated key=value pairs, for example
+// "type=PREMIUM;type=BUSINESS,bps=50;min=100000,bps=100,fixed=1000".
+// Keys are type, min, max, bps and fixed; omitted keys are 0 or any type.
+// A rule without bps and fixed exempts the payments it matches.
+func Parse(s string) (Schedule, error) {
+	var schedule Schedule
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		var r Rule
+		for _, kv := range strings.Split(entry, ",") {
+			kv = strings.TrimSpace(kv)
+			if kv == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("fee rule %q: %q is not key=value", entry, kv)
+			}
+			k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+			if k == "type" {
+				t, ok := policy.ParseType(v)
+				if !ok {
+					return nil, fmt.Errorf("fee rule %q: unknown account type %q", entry, v)
+				}
+				r.AccountType = t
+				continue
+			}
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil || n < 0 || n > money.MaxAmount {
+				return nil, fmt.Errorf("fee rule %q: %s must be 0..%d", entry, k, money.MaxAmount)
+			}
+			switch k {
+			case "min":
+				r.MinAmount = n
+			case "max":
+				r.MaxAmount = n
+			case "bps":
+				if n > maxBPS {
+					return nil, fmt.Errorf("fee rule %q: bps must be 0..%d", entry, maxBPS)
+				}
+				r.BPS = n
+			case "fixed":
+				r.Fixed = n
+			default:
+				return nil, fmt.Errorf("fee rule %q: unknown key %q", entry, k)
+			}
+		}
+		if r.MaxAmount != 0 && r.MaxAmount < r.MinAmount {
+			return nil, fmt.Errorf("fee rule %q: max is below min", entry)
+		}
+		schedule = append(schedule, r)
+	}
+	return schedule, nil
+}
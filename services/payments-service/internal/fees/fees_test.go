@@ -0,0 +1,61 @@
+package fees
+
+import (
+	"testing"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestScheduleFee(t *testing.T) {
+	s, err := Parse(" type=PREMIUM ; type=BUSINESS,bps=50 ; min=100000,bps=100,fixed=1000; max=99999,fixed=10;")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if len(s) != 4 {
+		t.Fatalf("Parse() = %+v, want 4 rules", s)
+	}
+	tests := []struct {
+		name   string
+		typ    policy.Type
+		amount int64
+		want   int64
+	}{
+		{"premium is exempt", policy.Premium, 500000, 0},
+		{"business percentage", policy.Business, 10000, 50},
+		{"business rounds up", policy.Business, 10001, 51},
+		{"basic large band", policy.Basic, 100000, 2000},
+		{"basic small band", policy.Basic, 99999, 10},
+	}
+	for _, tt := range tests {
+		if got := s.Fee(tt.typ, tt.amount); got != tt.want {
+			t.Errorf("%s: Fee(%s, %d) = %d, want %d", tt.name, tt.typ, tt.amount, got, tt.want)
+		}
+	}
+
+	if got := (Schedule{{MinAmount: 100}}).Fee(policy.Basic, 50); got != 0 {
+		t.Errorf("Fee() with no matching rule = %d, want 0", got)
+	}
+	if got := (Schedule{{BPS: maxBPS}}).Fee(policy.Basic, money.MaxAmount); got != money.MaxAmount {
+		t.Errorf("Fee(100%%, MaxAmount) = %d, want %d", got, money.MaxAmount)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if s, err := Parse(""); err != nil || len(s) != 0 {
+		t.Fatalf("Parse(\"\") = %v, %v; want no rules", s, err)
+	}
+	for _, bad := range []string{
+		"bps",
+		"type=GOLD",
+		"bps=-1",
+		"bps=10001",
+		"fixed=x",
+		"rate=1",
+		"min=10,max=5",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// Package fraud screens payments before funds are deducted.
+package fraud
+
+import (
+	"context"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// Payment is what the checker gets to see about a PaymentRequested event.
+type Payment struct {
+	UserID    string
+	OrderID   string
+	PaymentID string
+	Amount    int64
+}
+
+// Verdict is the screening outcome. Reason names the rule that declined the
+// payment and is meant for logs and support, not for end users.
+type Verdict struct {
+	Allow  bool
+	Reason string
+}
+
+// Checker decides whether a payment may proceed. It runs inside the payment
+// transaction, so q sees the same state the deduction will; an error aborts
+// the transaction and the event is redelivered.
+type Checker interface {
+	Check(ctx context.Context, q db.Querier, p Payment) (Verdict, error)
+}
+
+// AllowAll lets every payment through.
+type AllowAll struct{}
+
+func (AllowAll) Check(context.Context, db.Querier, Payment) (Verdict, error) {
+	return Verdict{Allow: true}, nil
+}
@@ -0,0 +1,68 @@
+package fraud
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// Rules is a config-driven Checker. Zero values disable the matching rule.
+type Rules struct {
+	// MaxAmount declines single payments above it.
+	MaxAmount int64
+	// MaxPaymentsPerHour declines a payment when the user already has that
+	// many debits in the last hour.
+	MaxPaymentsPerHour int
+	// Denylist declines every payment of the listed users.
+	Denylist map[string]bool
+
+	now func() time.Time
+}
+
+// NewRules builds Rules; denylist is a comma separated list of user ids.
+func NewRules(maxAmount int64, maxPaymentsPerHour int, denylist string) *Rules {
+	r := &Rules{
+		MaxAmount:          maxAmount,
+		MaxPaymentsPerHour: maxPaymentsPerHour,
+		Denylist:           map[string]bool{},
+		now:                time.Now,
+	}
+	for _, id := range strings.Split(denylist, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			r.Denylist[id] = true
+		}
+	}
+	return r
+}
+
+// Enabled reports whether any rule is configured.
+func (r *Rules) Enabled() bool {
+	return r.MaxAmount > 0 || r.MaxPaymentsPerHour > 0 || len(r.Denylist) > 0
+}
+
+func (r *Rules) Check(ctx context.Context, q db.Querier, p Payment) (Verdict, error) {
+	if r.Denylist[p.UserID] {
+		return Verdict{Reason: "user is denylisted"}, nil
+	}
+	if r.MaxAmount > 0 && p.Amount > r.MaxAmount {
+		return Verdict{Reason: fmt.Sprintf("amount %d exceeds limit %d", p.Amount, r.MaxAmount)}, nil
+	}
+	if r.MaxPaymentsPerHour > 0 {
+		n, err := q.CountRecentDebits(ctx, db.CountRecentDebitsParams{
+			UserID:    p.UserID,
+			CreatedAt: pgtype.Timestamptz{Time: r.now().Add(-time.Hour), Valid: true},
+		})
+		if err != nil {
+			return Verdict{}, err
+		}
+		if n >= int64(r.MaxPaymentsPerHour) {
+			return Verdict{Reason: fmt.Sprintf("%d payments in the last hour, limit %d", n, r.MaxPaymentsPerHour)}, nil
+		}
+	}
+	return Verdict{Allow: true}, nil
+}
@@ -0,0 +1,64 @@
+package fraud
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+type fakeHistory struct {
+	db.Querier
+	debits map[string]int64
+	since  time.Time
+}
+
+func (f *fakeHistory) CountRecentDebits(_ context.Context, arg db.CountRecentDebitsParams) (int64, error) {
+	f.since = arg.CreatedAt.Time
+	return f.debits[arg.UserID], nil
+}
+
+func TestAllowAll(t *testing.T) {
+	v, err := AllowAll{}.Check(context.Background(), nil, Payment{UserID: "u", Amount: 1 << 40})
+	if err != nil || !v.Allow {
+		t.Fatalf("AllowAll.Check() = %+v, %v; want allow", v, err)
+	}
+}
+
+func TestRules(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	r := NewRules(1000, 3, " bad-1, ,bad-2")
+	r.now = func() time.Time { return now }
+	if !r.Enabled() || len(r.Denylist) != 2 {
+		t.Fatalf("NewRules() = %+v, want enabled with 2 denylisted users", r)
+	}
+	h := &fakeHistory{debits: map[string]int64{"busy": 3, "calm": 2}}
+
+	tests := []struct {
+		name  string
+		p     Payment
+		allow bool
+	}{
+		{"denylisted", Payment{UserID: "bad-2", Amount: 1}, false},
+		{"amount over limit", Payment{UserID: "calm", Amount: 1001}, false},
+		{"velocity", Payment{UserID: "busy", Amount: 10}, false},
+		{"ok", Payment{UserID: "calm", Amount: 1000}, true},
+	}
+	for _, tt := range tests {
+		v, err := r.Check(context.Background(), h, tt.p)
+		if err != nil {
+			t.Fatalf("%s: Check() error: %v", tt.name, err)
+		}
+		if v.Allow != tt.allow || (!v.Allow && v.Reason == "") {
+			t.Fatalf("%s: Check() = %+v, want allow=%v with reason on decline", tt.name, v, tt.allow)
+		}
+	}
+	if !h.since.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("velocity window starts at %s, want %s", h.since, now.Add(-time.Hour))
+	}
+
+	if NewRules(0, 0, "").Enabled() {
+		t.Fatal("NewRules with zero config is enabled")
+	}
+}
@@ -0,0 +1,41 @@
+package grpc
+
+import (
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+)
+
+// accountTypeToProto maps an accounts.account_type value. The column check
+// only admits known types, so anything else maps to UNSPECIFIED.
+func accountTypeToProto(t string) paymentsv1.AccountType {
+	switch policy.Type(t) {
+	case policy.Basic:
+		return paymentsv1.AccountType_ACCOUNT_TYPE_BASIC
+	case policy.Premium:
+		return paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM
+	case policy.Business:
+		return paymentsv1.AccountType_ACCOUNT_TYPE_BUSINESS
+	}
+	return paymentsv1.AccountType_ACCOUNT_TYPE_UNSPECIFIED
+}
+
+// accountTypeFromProto is the inverse of accountTypeToProto; UNSPECIFIED and
+// unknown values are rejected.
+func accountTypeFromProto(t paymentsv1.AccountType) (policy.Type, bool) {
+	switch t {
+	case paymentsv1.AccountType_ACCOUNT_TYPE_BASIC:
+		return policy.Basic, true
+	case paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM:
+		return policy.Premium, true
+	case paymentsv1.AccountType_ACCOUNT_TYPE_BUSINESS:
+		return policy.Business, true
+	}
+	return "", false
+}
+
+// accountTypeName returns the accounts.account_type value of t, or "" for
+// UNSPECIFIED, e.g. in responses replayed from before accounts had types.
+func accountTypeName(t paymentsv1.AccountType) string {
+	typ, _ := accountTypeFromProto(t)
+	return string(typ)
+}
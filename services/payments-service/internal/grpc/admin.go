@@ -0,0 +1,659 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+// checkViolation is the SQLSTATE of a failed CHECK constraint.
+const checkViolation = "23514"
+
+// Page size bounds of ListDeadOutbox and ListOutbox.
+const (
+	defaultDeadOutboxPageSize = 50
+	maxDeadOutboxPageSize     = 500
+)
+
+// AdminHandlers serves the operator RPCs registered with ENABLE_ADMIN_API.
+type AdminHandlers struct {
+	paymentsv1.UnimplementedPaymentsAdminServiceServer
+	repo  repo.ShardedRepository
+	cache cache.BalanceCache
+
+	maxReplayEvents int
+	currency        money.Currency
+	policies        policy.Policies
+
+	// inbox holds the consumers of DryRunInboxMessage by name.
+	inbox map[string]InboxReplayer
+	// disputeTopic receives PaymentDisputeChanged; disputes are disabled
+	// while it is empty.
+	disputeTopic string
+	// orders tells OpenDispute whether the order is FINISHED.
+	orders ordersv1.OrdersServiceClient
+
+	logger *slog.Logger
+}
+
+// NewAdminHandlers builds the admin handlers. cache is optional; balances
+// changed by AdjustBalance and RematerializeBalance are written through to
+// it. maxReplayEvents bounds
+// a single ReplayOutbox call; currency is reported in returned accounts;
+// policies give the overdraft limit applied by SetAccountType.
+func NewAdminHandlers(repo repo.ShardedRepository, cache cache.BalanceCache, maxReplayEvents int, currency money.Currency, policies policy.Policies) *AdminHandlers {
+	logger := slog.Default().With("service", "payments-service", "component", "grpc")
+	logger.Info("admin handlers initialized", "max_replay_events", maxReplayEvents)
+	return &AdminHandlers{repo: repo, cache: cache, maxReplayEvents: maxReplayEvents, currency: currency, policies: policies, logger: logger}
+}
+
+// operator is the subject of the caller's token, empty when roles are not
+// checked.
+func operator(ctx context.Context) string {
+	if claims, ok := userauth.FromContext(ctx); ok {
+		return claims.Subject
+	}
+	return ""
+}
+
+// ReplayOutbox inserts copies of the sent outbox events created in
+// [from, to). Counting and copying share a transaction, so the limit check
+// holds for what is actually copied.
+func (h *AdminHandlers) ReplayOutbox(ctx context.Context, req *paymentsv1.ReplayOutboxRequest) (resp *paymentsv1.ReplayOutboxResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "replay outbox start", "operator", operator, "from", req.GetFrom().AsTime(), "to", req.GetTo().AsTime(), "topic", req.GetTopic(), "dry_run", req.GetDryRun())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "replay outbox failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "replay outbox completed", "operator", operator, "matched", resp.GetMatched(), "replayed", resp.GetReplayed(), "dry_run", resp.GetDryRun(), "duration", time.Since(start))
+	}()
+
+	if req.GetFrom() == nil || req.GetTo() == nil {
+		return nil, status.Error(codes.InvalidArgument, "from and to are required")
+	}
+	from, to := req.GetFrom().AsTime(), req.GetTo().AsTime()
+	if !from.Before(to) {
+		return nil, status.Error(codes.InvalidArgument, "from must be before to")
+	}
+	rng := db.CountSentOutboxParams{
+		CreatedFrom: pgtype.Timestamptz{Time: from, Valid: true},
+		CreatedTo:   pgtype.Timestamptz{Time: to, Valid: true},
+		Topic:       req.GetTopic(),
+	}
+
+	// Every shard is counted before any is replayed, so a range above the
+	// limit is not replayed on some shards only. Each shard counts again in
+	// the transaction that copies, which keeps the limit exact.
+	shards := h.repo.All()
+	resp = &paymentsv1.ReplayOutboxResponse{DryRun: req.GetDryRun()}
+	for _, r := range shards {
+		var n int64
+		n, err = r.Q().CountSentOutbox(ctx, rng)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to replay outbox")
+		}
+		resp.Matched += n
+	}
+	if req.GetDryRun() {
+		return resp, nil
+	}
+	if resp.Matched > int64(h.maxReplayEvents) {
+		err = status.Errorf(codes.FailedPrecondition, "range matches %d events, above the limit of %d; narrow it", resp.Matched, h.maxReplayEvents)
+		return nil, err
+	}
+
+	resp.Matched = 0
+	for _, r := range shards {
+		err = r.InTx(ctx, func(q db.Querier) error {
+			matched, err := q.CountSentOutbox(ctx, rng)
+			if err != nil {
+				return err
+			}
+			if resp.Matched+matched > int64(h.maxReplayEvents) {
+				return status.Errorf(codes.FailedPrecondition, "range matches %d events, above the limit of %d; narrow it", resp.Matched+matched, h.maxReplayEvents)
+			}
+			replayed, err := q.ReplaySentOutbox(ctx, db.ReplaySentOutboxParams(rng))
+			if err != nil {
+				return err
+			}
+			resp.Matched += matched
+			resp.Replayed += replayed
+			return nil
+		})
+		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
+			return nil, status.Error(codes.Internal, "failed to replay outbox")
+		}
+	}
+	return resp, nil
+}
+
+// ListDeadOutbox pages through dead-lettered outbox events by id.
+func (h *AdminHandlers) ListDeadOutbox(ctx context.Context, req *paymentsv1.ListDeadOutboxRequest) (*paymentsv1.ListDeadOutboxResponse, error) {
+	start := time.Now()
+	pageSize := req.GetPageSize()
+	if pageSize < 0 || pageSize > maxDeadOutboxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", maxDeadOutboxPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultDeadOutboxPageSize
+	}
+	shards := h.repo.All()
+	if req.GetShard() < 0 || int(req.GetShard()) >= len(shards) {
+		return nil, status.Errorf(codes.InvalidArgument, "shard must be 0..%d", len(shards)-1)
+	}
+
+	var rows []db.ListDeadOutboxRow
+	err := shards[req.GetShard()].Read(ctx, func(q db.Querier) error {
+		var err error
+		rows, err = q.ListDeadOutbox(ctx, db.ListDeadOutboxParams{
+			AfterID:  req.GetAfterId(),
+			Topic:    req.GetTopic(),
+			PageSize: pageSize,
+		})
+		return err
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "list dead outbox failed", "err", err, "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to list dead outbox")
+	}
+
+	resp := &paymentsv1.ListDeadOutboxResponse{Events: make([]*paymentsv1.DeadOutboxEvent, 0, len(rows))}
+	for _, r := range rows {
+		resp.Events = append(resp.Events, &paymentsv1.DeadOutboxEvent{
+			Id:        r.ID,
+			Topic:     r.Topic,
+			KafkaKey:  r.KafkaKey,
+			Attempts:  r.Attempts,
+			LastError: r.LastError.String,
+			CreatedAt: timestamppb.New(r.CreatedAt.Time),
+		})
+	}
+	if len(rows) == int(pageSize) {
+		resp.NextAfterId = rows[len(rows)-1].ID
+	}
+	h.logger.InfoContext(ctx, "list dead outbox completed", "shard", req.GetShard(), "topic", req.GetTopic(), "count", len(rows), "duration", time.Since(start))
+	return resp, nil
+}
+
+// outboxStatuses maps a ListOutbox state to the outbox statuses it covers.
+var outboxStatuses = map[paymentsv1.OutboxState][]string{
+	paymentsv1.OutboxState_OUTBOX_STATE_UNSENT: {"PENDING", "FAILED"},
+	paymentsv1.OutboxState_OUTBOX_STATE_FAILED: {"FAILED"},
+	paymentsv1.OutboxState_OUTBOX_STATE_DEAD:   {"DEAD"},
+}
+
+// ListOutbox pages through the outbox events in one state by id, one shard at a time.
+func (h *AdminHandlers) ListOutbox(ctx context.Context, req *paymentsv1.ListOutboxRequest) (*paymentsv1.ListOutboxResponse, error) {
+	start := time.Now()
+	statuses, ok := outboxStatuses[req.GetState()]
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "state is required")
+	}
+	pageSize := req.GetPageSize()
+	if pageSize < 0 || pageSize > maxDeadOutboxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", maxDeadOutboxPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultDeadOutboxPageSize
+	}
+	arg := db.ListOutboxParams{
+		Statuses: statuses,
+		AfterID:  req.GetAfterId(),
+		Topic:    req.GetTopic(),
+		PageSize: pageSize,
+	}
+	if req.GetCreatedFrom() != nil {
+		arg.CreatedFrom = pgtype.Timestamptz{Time: req.GetCreatedFrom().AsTime(), Valid: true}
+	}
+	if req.GetCreatedTo() != nil {
+		arg.CreatedTo = pgtype.Timestamptz{Time: req.GetCreatedTo().AsTime(), Valid: true}
+	}
+	if arg.CreatedFrom.Valid && arg.CreatedTo.Valid && !arg.CreatedFrom.Time.Before(arg.CreatedTo.Time) {
+		return nil, status.Error(codes.InvalidArgument, "created_from must be before created_to")
+	}
+	shards := h.repo.All()
+	if req.GetShard() < 0 || int(req.GetShard()) >= len(shards) {
+		return nil, status.Errorf(codes.InvalidArgument, "shard must be 0..%d", len(shards)-1)
+	}
+
+	var rows []db.ListOutboxRow
+	err := shards[req.GetShard()].Read(ctx, func(q db.Querier) error {
+		var err error
+		rows, err = q.ListOutbox(ctx, arg)
+		return err
+	})
+	if err != nil {
+		h.logger.ErrorContext(ctx, "list outbox failed", "err", err, "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to list outbox")
+	}
+
+	resp := &paymentsv1.ListOutboxResponse{Events: make([]*paymentsv1.OutboxEvent, 0, len(rows))}
+	for _, r := range rows {
+		e := &paymentsv1.OutboxEvent{
+			Id:            r.ID,
+			Topic:         r.Topic,
+			KafkaKey:      r.KafkaKey,
+			Status:        r.Status,
+			Attempts:      r.Attempts,
+			LastError:     r.LastError.String,
+			CreatedAt:     timestamppb.New(r.CreatedAt.Time),
+			CorrelationId: r.CorrelationID,
+		}
+		if r.NextRetryAt.Valid {
+			e.NextRetryAt = timestamppb.New(r.NextRetryAt.Time)
+		}
+		resp.Events = append(resp.Events, e)
+	}
+	if len(rows) == int(pageSize) {
+		resp.NextAfterId = rows[len(rows)-1].ID
+	}
+	h.logger.InfoContext(ctx, "list outbox completed", "operator", operator(ctx), "shard", req.GetShard(), "state", req.GetState().String(), "topic", req.GetTopic(), "count", len(rows), "duration", time.Since(start))
+	return resp, nil
+}
+
+// RequeueDeadOutbox resets the dead-lettered events of one shard to PENDING;
+// the shard's publisher picks them up on its next pass.
+func (h *AdminHandlers) RequeueDeadOutbox(ctx context.Context, req *paymentsv1.RequeueDeadOutboxRequest) (resp *paymentsv1.RequeueDeadOutboxResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "requeue dead outbox start", "operator", operator, "shard", req.GetShard(), "ids", req.GetIds(), "all", req.GetAll(), "topic", req.GetTopic())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "requeue dead outbox failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "requeue dead outbox completed", "operator", operator, "shard", req.GetShard(), "requeued", resp.GetRequeued(), "duration", time.Since(start))
+	}()
+
+	if !req.GetAll() && len(req.GetIds()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids or all is required")
+	}
+	shards := h.repo.All()
+	if req.GetShard() < 0 || int(req.GetShard()) >= len(shards) {
+		return nil, status.Errorf(codes.InvalidArgument, "shard must be 0..%d", len(shards)-1)
+	}
+
+	var n int64
+	err = shards[req.GetShard()].InTx(ctx, func(q db.Querier) error {
+		var err error
+		n, err = q.RequeueDeadOutbox(ctx, db.RequeueDeadOutboxParams{
+			AllEvents: req.GetAll(),
+			Ids:       req.GetIds(),
+			Topic:     req.GetTopic(),
+		})
+		return err
+	})
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to requeue dead outbox")
+	}
+	return &paymentsv1.RequeueDeadOutboxResponse{Requeued: n}, nil
+}
+
+// SetOverdraftLimit changes how far below zero deductions may take the
+// account. The balance check constraint rejects a limit below the current
+// debt, which is reported as FailedPrecondition.
+func (h *AdminHandlers) SetOverdraftLimit(ctx context.Context, req *paymentsv1.SetOverdraftLimitRequest) (*paymentsv1.SetOverdraftLimitResponse, error) {
+	start := time.Now()
+	operator := operator(ctx)
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetOverdraftLimit() < 0 || req.GetOverdraftLimit() > money.MaxAmount {
+		return nil, status.Errorf(codes.InvalidArgument, "overdraft_limit must be 0..%d", money.MaxAmount)
+	}
+
+	var account db.SetOverdraftLimitRow
+	err := h.repo.For(req.GetUserId()).InTx(ctx, func(q db.Querier) error {
+		var err error
+		account, err = q.SetOverdraftLimit(ctx, db.SetOverdraftLimitParams{
+			UserID:         req.GetUserId(),
+			OverdraftLimit: req.GetOverdraftLimit(),
+		})
+		return err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "account not found")
+		case errors.As(err, &pgErr) && pgErr.Code == checkViolation:
+			return nil, status.Error(codes.FailedPrecondition, "balance is below the new overdraft limit")
+		}
+		h.logger.ErrorContext(ctx, "set overdraft limit failed", "err", err, "user_id", req.GetUserId(), "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to set overdraft limit")
+	}
+	h.logger.InfoContext(ctx, "set overdraft limit completed", "operator", operator, "user_id", req.GetUserId(), "overdraft_limit", account.OverdraftLimit, "duration", time.Since(start))
+	return &paymentsv1.SetOverdraftLimitResponse{
+		Account: &paymentsv1.Account{
+			UserId:         account.UserID,
+			Balance:        account.Balance,
+			Currency:       string(h.currency),
+			Version:        account.Version,
+			OverdraftLimit: account.OverdraftLimit,
+			AccountType:    accountTypeToProto(account.AccountType),
+		},
+	}, nil
+}
+
+// SetAccountType moves the account to another tier. The tier's overdraft
+// limit replaces the account's in the same update, so a downgrade of an
+// account in debt fails with FailedPrecondition like SetOverdraftLimit.
+func (h *AdminHandlers) SetAccountType(ctx context.Context, req *paymentsv1.SetAccountTypeRequest) (*paymentsv1.SetAccountTypeResponse, error) {
+	start := time.Now()
+	operator := operator(ctx)
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	typ, ok := accountTypeFromProto(req.GetAccountType())
+	if !ok {
+		return nil, status.Error(codes.InvalidArgument, "account_type is required")
+	}
+	pol := h.policies.For(typ)
+
+	var account db.SetAccountTypeRow
+	err := h.repo.For(req.GetUserId()).InTx(ctx, func(q db.Querier) error {
+		var err error
+		account, err = q.SetAccountType(ctx, db.SetAccountTypeParams{
+			UserID:         req.GetUserId(),
+			AccountType:    string(typ),
+			OverdraftLimit: pol.Overdraft,
+		})
+		return err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "account not found")
+		case errors.As(err, &pgErr) && pgErr.Code == checkViolation:
+			return nil, status.Errorf(codes.FailedPrecondition, "balance is below the %s overdraft limit", typ)
+		}
+		h.logger.ErrorContext(ctx, "set account type failed", "err", err, "user_id", req.GetUserId(), "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to set account type")
+	}
+	h.logger.InfoContext(ctx, "set account type completed", "operator", operator, "user_id", req.GetUserId(), "account_type", typ, "overdraft_limit", account.OverdraftLimit, "duration", time.Since(start))
+	return &paymentsv1.SetAccountTypeResponse{
+		Account: &paymentsv1.Account{
+			UserId:         account.UserID,
+			Balance:        account.Balance,
+			Currency:       string(h.currency),
+			Version:        account.Version,
+			OverdraftLimit: account.OverdraftLimit,
+			AccountType:    accountTypeToProto(account.AccountType),
+		},
+	}, nil
+}
+
+// GrantBonus credits a bonus grant to an existing account. The grant and its
+// ledger entry are written by one statement.
+func (h *AdminHandlers) GrantBonus(ctx context.Context, req *paymentsv1.GrantBonusRequest) (*paymentsv1.GrantBonusResponse, error) {
+	start := time.Now()
+	operator := operator(ctx)
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if err := validateAmount(req.GetAmount()); err != nil {
+		return nil, err
+	}
+	if req.GetExpiresAt() == nil || !req.GetExpiresAt().AsTime().After(time.Now()) {
+		return nil, status.Error(codes.InvalidArgument, "expires_at must be in the future")
+	}
+
+	var grant db.GrantBonusRow
+	err := h.repo.For(req.GetUserId()).InTx(ctx, func(q db.Querier) error {
+		var err error
+		grant, err = q.GrantBonus(ctx, db.GrantBonusParams{
+			UserID:    req.GetUserId(),
+			Amount:    req.GetAmount(),
+			ExpiresAt: pgtype.Timestamptz{Time: req.GetExpiresAt().AsTime(), Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.NotFound, "account not found")
+		}
+		h.logger.ErrorContext(ctx, "grant bonus failed", "err", err, "user_id", req.GetUserId(), "duration", time.Since(start))
+		return nil, status.Error(codes.Internal, "failed to grant bonus")
+	}
+	h.logger.InfoContext(ctx, "grant bonus completed", "operator", operator, "user_id", req.GetUserId(), "grant_id", grant.ID, "amount", grant.Amount, "expires_at", grant.ExpiresAt.Time, "duration", time.Since(start))
+	return &paymentsv1.GrantBonusResponse{
+		Grant: &paymentsv1.BonusGrant{
+			Id:        grant.ID,
+			UserId:    grant.UserID,
+			Amount:    grant.Amount,
+			Remaining: grant.Remaining,
+			ExpiresAt: timestamppb.New(grant.ExpiresAt.Time),
+			CreatedAt: timestamppb.New(grant.CreatedAt.Time),
+		},
+	}, nil
+}
+
+// AdjustBalance applies a signed correction to the balance and records it in
+// admin_audit_log in the same transaction. Like payments, a debit may use the
+// overdraft but not go past it; that is reported as FailedPrecondition. The
+// request id is the idempotency key of the adjustment, so a retried call
+// replays the first response instead of moving the balance twice, and the
+// operator must be known for the audit log.
+func (h *AdminHandlers) AdjustBalance(ctx context.Context, req *paymentsv1.AdjustBalanceRequest) (resp *paymentsv1.AdjustBalanceResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "adjust balance start", "operator", operator, "user_id", req.GetUserId(), "amount", req.GetAmount(), "reason", req.GetReason(), "idempotency_key", req.GetRequestId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "adjust balance failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "adjust balance completed", "operator", operator, "user_id", req.GetUserId(), "balance", resp.GetAccount().GetBalance(), "version", resp.GetAccount().GetVersion(), "duration", time.Since(start))
+	}()
+
+	if operator == "" {
+		return nil, status.Error(codes.Unauthenticated, "adjusting a balance needs an operator token")
+	}
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetAmount() == 0 || req.GetAmount() > money.MaxAmount || req.GetAmount() < -money.MaxAmount {
+		return nil, status.Errorf(codes.InvalidArgument, "amount must be non-zero and within ±%d", money.MaxAmount)
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+	if strings.TrimSpace(req.GetRequestId()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "request_id is required")
+	}
+
+	var replayed bool
+	err = h.repo.For(req.GetUserId()).InTx(ctx, func(q db.Querier) error {
+		resp, replayed, err = idempotency.Do(ctx, repo.IdempotencyStore(q), idempotency.Key{
+			Scope:  "payments.AdjustBalance",
+			UserID: req.GetUserId(),
+			Key:    req.GetRequestId(),
+		}, req, &paymentsv1.AdjustBalanceResponse{}, func() (*paymentsv1.AdjustBalanceResponse, error) {
+			account, err := q.AdjustBalance(ctx, db.AdjustBalanceParams{Delta: req.GetAmount(), UserID: req.GetUserId()})
+			if err != nil {
+				return nil, err
+			}
+			details, err := json.Marshal(map[string]int64{"amount": req.GetAmount(), "balance": account.Balance, "version": account.Version})
+			if err != nil {
+				return nil, err
+			}
+			if err := q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+				Operator: operator,
+				Action:   "adjust_balance",
+				Target:   req.GetUserId(),
+				Reason:   req.GetReason(),
+				Details:  details,
+			}); err != nil {
+				return nil, err
+			}
+			return &paymentsv1.AdjustBalanceResponse{
+				Account: &paymentsv1.Account{
+					UserId:         account.UserID,
+					Balance:        account.Balance,
+					Currency:       string(h.currency),
+					Version:        account.Version,
+					OverdraftLimit: account.OverdraftLimit,
+					AccountType:    accountTypeToProto(account.AccountType),
+					BonusBalance:   account.BonusBalance,
+				},
+			}, nil
+		})
+		return err
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "account not found")
+		case errors.As(err, &pgErr) && pgErr.Code == checkViolation:
+			return nil, status.Error(codes.FailedPrecondition, "balance would go below the overdraft limit")
+		}
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to adjust balance")
+	}
+
+	// A replay returns the balance right after the original adjustment; the
+	// current balance may differ, so it is not cached.
+	if !replayed && h.cache != nil {
+		a := resp.GetAccount()
+		if err := h.cache.Set(ctx, cache.Balance{
+			UserID:       a.GetUserId(),
+			Balance:      a.GetBalance(),
+			Version:      a.GetVersion(),
+			AccountType:  accountTypeName(a.GetAccountType()),
+			BonusBalance: a.GetBonusBalance(),
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", a.GetUserId())
+		}
+	}
+	return resp, nil
+}
+
+// RematerializeBalance replaces the stored balance of an account with the
+// balance of its ledger account and resolves the account's open balance
+// anomalies, recorded in admin_audit_log in the same transaction. The ledger
+// balance is read under the account lock, so no payment slips in between. A
+// ledger balance below the overdraft limit is reported as
+// FailedPrecondition.
+func (h *AdminHandlers) RematerializeBalance(ctx context.Context, req *paymentsv1.RematerializeBalanceRequest) (resp *paymentsv1.RematerializeBalanceResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "rematerialize balance start", "operator", operator, "user_id", req.GetUserId(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "rematerialize balance failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "rematerialize balance completed", "operator", operator, "user_id", req.GetUserId(), "previous_balance", resp.GetPreviousBalance(), "balance", resp.GetAccount().GetBalance(), "resolved_anomalies", resp.GetResolvedAnomalies(), "duration", time.Since(start))
+	}()
+
+	if req.GetUserId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+
+	var (
+		previous int64
+		resolved int64
+		account  db.SetMaterializedBalanceRow
+	)
+	err = h.repo.For(req.GetUserId()).InTx(ctx, func(q db.Querier) error {
+		if _, err := q.LockAccount(ctx, req.GetUserId()); err != nil {
+			return err
+		}
+		stored, err := q.GetBalance(ctx, req.GetUserId())
+		if err != nil {
+			return err
+		}
+		ledger, err := q.GetLedgerBalance(ctx, req.GetUserId())
+		if err != nil {
+			return err
+		}
+		account, err = q.SetMaterializedBalance(ctx, db.SetMaterializedBalanceParams{Balance: ledger, UserID: req.GetUserId()})
+		if err != nil {
+			return err
+		}
+		resolved, err = q.ResolveBalanceAnomalies(ctx, db.ResolveBalanceAnomaliesParams{ResolvedBy: pgtype.Text{String: operator, Valid: operator != ""}, UserID: req.GetUserId()})
+		if err != nil {
+			return err
+		}
+		previous = stored.Balance
+		details, err := json.Marshal(map[string]int64{"previous_balance": previous, "balance": account.Balance, "version": account.Version, "resolved_anomalies": resolved})
+		if err != nil {
+			return err
+		}
+		return q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "rematerialize_balance",
+			Target:   req.GetUserId(),
+			Reason:   req.GetReason(),
+			Details:  details,
+		})
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			return nil, status.Error(codes.NotFound, "account not found")
+		case errors.As(err, &pgErr) && pgErr.Code == checkViolation:
+			return nil, status.Error(codes.FailedPrecondition, "ledger balance is below the overdraft limit")
+		}
+		return nil, status.Error(codes.Internal, "failed to rematerialize balance")
+	}
+
+	if h.cache != nil {
+		if err := h.cache.Set(ctx, cache.Balance{
+			UserID:       account.UserID,
+			Balance:      account.Balance,
+			Version:      account.Version,
+			AccountType:  account.AccountType,
+			BonusBalance: account.BonusBalance,
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", account.UserID)
+		}
+	}
+	return &paymentsv1.RematerializeBalanceResponse{
+		Account: &paymentsv1.Account{
+			UserId:         account.UserID,
+			Balance:        account.Balance,
+			Currency:       string(h.currency),
+			Version:        account.Version,
+			OverdraftLimit: account.OverdraftLimit,
+			AccountType:    accountTypeToProto(account.AccountType),
+			BonusBalance:   account.BonusBalance,
+		},
+		PreviousBalance:   previous,
+		ResolvedAnomalies: resolved,
+	}, nil
+}
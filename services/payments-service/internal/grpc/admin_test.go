@@ -0,0 +1,490 @@
+package grpc
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/jwt"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+	"github.com/ilyaytrewq/payments-service/pkg/userauth"
+)
+
+func TestReplayOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 2, money.RUB, nil)
+	ctx := context.Background()
+	now := time.Now()
+	repo.sent = []fakeSentOutbox{
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-1", Payload: []byte("1")}, createdAt: now.Add(-2 * time.Hour)},
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-2", Payload: []byte("2")}, createdAt: now.Add(-30 * time.Minute)},
+		{row: db.InsertOutboxParams{Topic: "b", KafkaKey: "k-3", Payload: []byte("3")}, createdAt: now.Add(-20 * time.Minute)},
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-4", Payload: []byte("4")}, createdAt: now.Add(-10 * time.Minute)},
+	}
+	lastHour := &paymentsv1.ReplayOutboxRequest{From: timestamppb.New(now.Add(-time.Hour)), To: timestamppb.New(now)}
+
+	_, err := h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{To: timestamppb.New(now)})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: timestamppb.New(now), To: timestamppb.New(now)})
+	wantCode(t, err, codes.InvalidArgument)
+
+	dry, err := h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: lastHour.From, To: lastHour.To, DryRun: true})
+	if err != nil || dry.GetMatched() != 3 || dry.GetReplayed() != 0 || len(repo.outbox) != 0 {
+		t.Fatalf("ReplayOutbox(dry run) = (%v, %v), outbox %d; want 3 matched, nothing replayed", dry, err, len(repo.outbox))
+	}
+
+	// Three events exceed the limit of two.
+	_, err = h.ReplayOutbox(ctx, lastHour)
+	wantCode(t, err, codes.FailedPrecondition)
+	if len(repo.outbox) != 0 {
+		t.Fatalf("rejected replay left %d outbox rows", len(repo.outbox))
+	}
+
+	resp, err := h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: lastHour.From, To: lastHour.To, Topic: "a"})
+	if err != nil || resp.GetMatched() != 2 || resp.GetReplayed() != 2 {
+		t.Fatalf("ReplayOutbox(topic a) = (%v, %v), want 2 matched and replayed", resp, err)
+	}
+	if len(repo.outbox) != 2 || repo.outbox[0].KafkaKey != "k-2" || repo.outbox[1].KafkaKey != "k-4" {
+		t.Fatalf("outbox = %v, want copies of k-2 and k-4 in order", repo.outbox)
+	}
+}
+
+func TestReplayOutboxSharded(t *testing.T) {
+	shards := &fakeShards{shards: []*fakeRepo{newFakeRepo(), newFakeRepo()}}
+	h := NewAdminHandlers(shards, nil, 2, money.RUB, nil)
+	ctx := context.Background()
+	now := time.Now()
+	shards.shards[0].sent = []fakeSentOutbox{{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-1"}, createdAt: now.Add(-time.Minute)}}
+	shards.shards[1].sent = []fakeSentOutbox{
+		{row: db.InsertOutboxParams{Topic: "a", KafkaKey: "k-2"}, createdAt: now.Add(-time.Minute)},
+		{row: db.InsertOutboxParams{Topic: "b", KafkaKey: "k-3"}, createdAt: now.Add(-time.Minute)},
+	}
+	from, to := timestamppb.New(now.Add(-time.Hour)), timestamppb.New(now)
+
+	// Three events over both shards exceed the limit; no shard is replayed.
+	_, err := h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: from, To: to})
+	wantCode(t, err, codes.FailedPrecondition)
+	if len(shards.shards[0].outbox)+len(shards.shards[1].outbox) != 0 {
+		t.Fatal("rejected replay copied events")
+	}
+
+	resp, err := h.ReplayOutbox(ctx, &paymentsv1.ReplayOutboxRequest{From: from, To: to, Topic: "a"})
+	if err != nil || resp.GetMatched() != 2 || resp.GetReplayed() != 2 {
+		t.Fatalf("ReplayOutbox(topic a) = (%v, %v), want 2 matched and replayed", resp, err)
+	}
+	if len(shards.shards[0].outbox) != 1 || len(shards.shards[1].outbox) != 1 {
+		t.Fatalf("outbox sizes = %d, %d, want a copy on each shard", len(shards.shards[0].outbox), len(shards.shards[1].outbox))
+	}
+}
+
+func TestListDeadOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB, nil)
+	ctx := context.Background()
+	for i := int64(1); i <= 3; i++ {
+		repo.dead = append(repo.dead, db.ListDeadOutboxRow{ID: i, Topic: "a", KafkaKey: "k", Attempts: 20, LastError: pgtype.Text{String: "unknown topic", Valid: true}})
+	}
+	repo.dead = append(repo.dead, db.ListDeadOutboxRow{ID: 4, Topic: "b", KafkaKey: "k"})
+
+	_, err := h.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{PageSize: maxDeadOutboxPageSize + 1})
+	wantCode(t, err, codes.InvalidArgument)
+
+	page, err := h.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{Topic: "a", PageSize: 2})
+	if err != nil || len(page.GetEvents()) != 2 || page.GetNextAfterId() != 2 {
+		t.Fatalf("first page = (%v, %v), want 2 events and next_after_id 2", page, err)
+	}
+	if ev := page.GetEvents()[0]; ev.GetId() != 1 || ev.GetAttempts() != 20 || ev.GetLastError() != "unknown topic" {
+		t.Fatalf("event = %v", ev)
+	}
+	page, err = h.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{Topic: "a", PageSize: 2, AfterId: page.GetNextAfterId()})
+	if err != nil || len(page.GetEvents()) != 1 || page.GetEvents()[0].GetId() != 3 || page.GetNextAfterId() != 0 {
+		t.Fatalf("last page = (%v, %v), want event 3 and no next page", page, err)
+	}
+	all, err := h.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{})
+	if err != nil || len(all.GetEvents()) != 4 {
+		t.Fatalf("all = (%v, %v), want 4 events", all, err)
+	}
+	_, err = h.ListDeadOutbox(ctx, &paymentsv1.ListDeadOutboxRequest{Shard: 1})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestListOutbox(t *testing.T) {
+	shards := &fakeShards{shards: []*fakeRepo{newFakeRepo(), newFakeRepo()}}
+	h := NewAdminHandlers(shards, nil, 10, money.RUB, nil)
+	ctx := context.Background()
+	now := time.Now()
+	at := func(d time.Duration) pgtype.Timestamptz { return pgtype.Timestamptz{Time: now.Add(d), Valid: true} }
+	repo := shards.shards[1]
+	repo.listed = []db.ListOutboxRow{
+		{ID: 1, Topic: "a", Status: "SENT", CreatedAt: at(-3 * time.Hour)},
+		{ID: 2, Topic: "a", Status: "DEAD", Attempts: 20, LastError: pgtype.Text{String: "unknown topic", Valid: true}, CreatedAt: at(-2 * time.Hour)},
+		{ID: 3, Topic: "a", Status: "FAILED", Attempts: 2, LastError: pgtype.Text{String: "broker down", Valid: true}, CreatedAt: at(-time.Hour), NextRetryAt: at(time.Second)},
+		{ID: 4, Topic: "b", Status: "PENDING", CreatedAt: at(-30 * time.Minute)},
+		{ID: 5, Topic: "a", Status: "PENDING", CreatedAt: at(-time.Minute)},
+	}
+
+	_, err := h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{Shard: 1})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedFrom: timestamppb.New(now), CreatedTo: timestamppb.New(now.Add(-time.Hour)), Shard: 1})
+	wantCode(t, err, codes.InvalidArgument)
+
+	ids := func(resp *paymentsv1.ListOutboxResponse) []int64 {
+		var ids []int64
+		for _, e := range resp.GetEvents() {
+			ids = append(ids, e.GetId())
+		}
+		return ids
+	}
+	for _, tc := range []struct {
+		name string
+		req  *paymentsv1.ListOutboxRequest
+		want []int64
+	}{
+		{"unsent", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, Shard: 1}, []int64{3, 4, 5}},
+		{"failed", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_FAILED, Shard: 1}, []int64{3}},
+		{"dead", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_DEAD, Shard: 1}, []int64{2}},
+		{"unsent of topic", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, Topic: "a", Shard: 1}, []int64{3, 5}},
+		{"unsent created since", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedFrom: timestamppb.New(now.Add(-45 * time.Minute)), Shard: 1}, []int64{4, 5}},
+		{"unsent created before", &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, CreatedTo: timestamppb.New(now.Add(-30 * time.Minute)), Shard: 1}, []int64{3}},
+	} {
+		resp, err := h.ListOutbox(ctx, tc.req)
+		if err != nil || !slices.Equal(ids(resp), tc.want) {
+			t.Errorf("%s: ListOutbox = (%v, %v), want ids %v", tc.name, ids(resp), err, tc.want)
+		}
+	}
+
+	page, err := h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, PageSize: 2, Shard: 1})
+	if err != nil || page.GetNextAfterId() != 4 {
+		t.Fatalf("first page = (%v, %v), want next_after_id 4", page, err)
+	}
+	if ev := page.GetEvents()[0]; ev.GetStatus() != "FAILED" || ev.GetLastError() != "broker down" || ev.GetNextRetryAt() == nil {
+		t.Fatalf("event = %v", ev)
+	}
+	page, err = h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, PageSize: 2, AfterId: page.GetNextAfterId(), Shard: 1})
+	if err != nil || !slices.Equal(ids(page), []int64{5}) || page.GetNextAfterId() != 0 {
+		t.Fatalf("last page = (%v, %v), want event 5 and no next page", page, err)
+	}
+
+	if resp, err := h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT}); err != nil || len(resp.GetEvents()) != 0 {
+		t.Fatalf("shard 0 = (%v, %v), want no events", resp, err)
+	}
+	_, err = h.ListOutbox(ctx, &paymentsv1.ListOutboxRequest{State: paymentsv1.OutboxState_OUTBOX_STATE_UNSENT, Shard: 2})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestSetOverdraftLimit(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB, nil)
+	ctx := context.Background()
+	repo.accounts["u-1"] = 0
+
+	_, err := h.SetOverdraftLimit(ctx, &paymentsv1.SetOverdraftLimitRequest{OverdraftLimit: 100})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.SetOverdraftLimit(ctx, &paymentsv1.SetOverdraftLimitRequest{UserId: "u-1", OverdraftLimit: -1})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.SetOverdraftLimit(ctx, &paymentsv1.SetOverdraftLimitRequest{UserId: "u-2", OverdraftLimit: 100})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := h.SetOverdraftLimit(ctx, &paymentsv1.SetOverdraftLimitRequest{UserId: "u-1", OverdraftLimit: 100})
+	if err != nil || resp.GetAccount().GetOverdraftLimit() != 100 || resp.GetAccount().GetCurrency() != "RUB" {
+		t.Fatalf("SetOverdraftLimit = (%v, %v), want limit 100 in RUB", resp, err)
+	}
+
+	// An account already 50 in debt cannot have its limit cut below 50.
+	repo.accounts["u-1"] = -50
+	_, err = h.SetOverdraftLimit(ctx, &paymentsv1.SetOverdraftLimitRequest{UserId: "u-1", OverdraftLimit: 10})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.overdraft["u-1"] != 100 {
+		t.Fatalf("overdraft = %d after rejected change, want 100", repo.overdraft["u-1"])
+	}
+}
+
+func TestSetAccountType(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB, policy.Policies{policy.Premium: {Overdraft: 100}})
+	ctx := context.Background()
+	repo.accounts["u-1"] = 0
+
+	_, err := h.SetAccountType(ctx, &paymentsv1.SetAccountTypeRequest{AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.SetAccountType(ctx, &paymentsv1.SetAccountTypeRequest{UserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.SetAccountType(ctx, &paymentsv1.SetAccountTypeRequest{UserId: "u-2", AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := h.SetAccountType(ctx, &paymentsv1.SetAccountTypeRequest{UserId: "u-1", AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM})
+	if err != nil || resp.GetAccount().GetAccountType() != paymentsv1.AccountType_ACCOUNT_TYPE_PREMIUM || resp.GetAccount().GetOverdraftLimit() != 100 {
+		t.Fatalf("SetAccountType(PREMIUM) = (%v, %v), want PREMIUM with overdraft 100", resp, err)
+	}
+
+	// Back to BASIC takes the overdraft away, which an account in debt
+	// cannot have.
+	repo.accounts["u-1"] = -50
+	_, err = h.SetAccountType(ctx, &paymentsv1.SetAccountTypeRequest{UserId: "u-1", AccountType: paymentsv1.AccountType_ACCOUNT_TYPE_BASIC})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.types["u-1"] != "PREMIUM" {
+		t.Fatalf("account type = %q after rejected downgrade, want PREMIUM", repo.types["u-1"])
+	}
+}
+
+func TestGrantBonus(t *testing.T) {
+	repo := newFakeRepo()
+	admin := NewAdminHandlers(repo, nil, 10, money.RUB, nil)
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+	repo.accounts["u-1"] = 100
+	later := timestamppb.New(time.Now().Add(24 * time.Hour))
+
+	_, err := admin.GrantBonus(ctx, &paymentsv1.GrantBonusRequest{Amount: 10, ExpiresAt: later})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = admin.GrantBonus(ctx, &paymentsv1.GrantBonusRequest{UserId: "u-1", ExpiresAt: later})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = admin.GrantBonus(ctx, &paymentsv1.GrantBonusRequest{UserId: "u-1", Amount: 10, ExpiresAt: timestamppb.New(time.Now().Add(-time.Minute))})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = admin.GrantBonus(ctx, &paymentsv1.GrantBonusRequest{UserId: "u-2", Amount: 10, ExpiresAt: later})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := admin.GrantBonus(ctx, &paymentsv1.GrantBonusRequest{UserId: "u-1", Amount: 30, ExpiresAt: later})
+	if err != nil || resp.GetGrant().GetRemaining() != 30 || !resp.GetGrant().GetExpiresAt().AsTime().Equal(later.AsTime()) {
+		t.Fatalf("GrantBonus() = (%v, %v), want 30 remaining until %v", resp, err, later.AsTime())
+	}
+	// A grant that has already expired no longer counts.
+	repo.grants = append(repo.grants, db.GrantBonusRow{UserID: "u-1", Remaining: 5, ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}})
+
+	bal, err := h.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: "u-1"})
+	if err != nil || bal.GetBalance() != 100 || bal.GetBonusBalance() != 30 {
+		t.Fatalf("GetBalance() = (%v, %v), want balance 100 and bonus 30", bal, err)
+	}
+}
+
+func TestRequeueDeadOutbox(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewAdminHandlers(repo, nil, 10, money.RUB, nil)
+	ctx := context.Background()
+	repo.dead = []db.ListDeadOutboxRow{{ID: 1, Topic: "a"}, {ID: 2, Topic: "b"}, {ID: 3, Topic: "a"}}
+
+	_, err := h.RequeueDeadOutbox(ctx, &paymentsv1.RequeueDeadOutboxRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.RequeueDeadOutbox(ctx, &paymentsv1.RequeueDeadOutboxRequest{All: true, Shard: 1})
+	wantCode(t, err, codes.InvalidArgument)
+
+	resp, err := h.RequeueDeadOutbox(ctx, &paymentsv1.RequeueDeadOutboxRequest{Ids: []int64{2, 99}})
+	if err != nil || resp.GetRequeued() != 1 {
+		t.Fatalf("RequeueDeadOutbox(ids) = (%v, %v), want 1 requeued", resp, err)
+	}
+	resp, err = h.RequeueDeadOutbox(ctx, &paymentsv1.RequeueDeadOutboxRequest{All: true, Topic: "a"})
+	if err != nil || resp.GetRequeued() != 2 || len(repo.dead) != 0 || !slices.Equal(repo.requeued, []int64{2, 1, 3}) {
+		t.Fatalf("RequeueDeadOutbox(all of a) = (%v, %v), requeued %v; want 2 more", resp, err, repo.requeued)
+	}
+}
+
+func TestAdjustBalance(t *testing.T) {
+	repo := newFakeRepo()
+	balances := cache.NewMemoryBalanceCache(10, time.Minute)
+	h := NewAdminHandlers(repo, balances, 10, money.RUB, nil)
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+	repo.accounts["u-1"] = 100
+
+	_, err := h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Reason: "refund", RequestId: "r-0"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: 10, RequestId: "r-0"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: 10, Reason: "refund"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-2", Amount: 10, Reason: "refund", RequestId: "r-0"})
+	wantCode(t, err, codes.NotFound)
+	// Without RBAC nobody would be on record for the adjustment.
+	_, err = h.AdjustBalance(context.Background(), &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: 10, Reason: "refund", RequestId: "r-0"})
+	wantCode(t, err, codes.Unauthenticated)
+
+	req := &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: -40, Reason: "duplicate top-up", RequestId: "r-1"}
+	resp, err := h.AdjustBalance(ctx, req)
+	if err != nil || resp.GetAccount().GetBalance() != 60 || resp.GetAccount().GetVersion() != 2 {
+		t.Fatalf("AdjustBalance(-40) = (%v, %v), want balance 60 at version 2", resp, err)
+	}
+	// A retry replays the first response and moves nothing.
+	replay, err := h.AdjustBalance(ctx, req)
+	if err != nil || replay.GetAccount().GetBalance() != 60 || repo.accounts["u-1"] != 60 {
+		t.Fatalf("AdjustBalance() retry = (%v, %v), balance %d; want the first response and balance 60", replay, err, repo.accounts["u-1"])
+	}
+	_, err = h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: -30, Reason: "duplicate top-up", RequestId: "r-1"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if cached, err := balances.Get(ctx, "u-1"); err != nil || cached == nil || cached.Balance != 60 {
+		t.Fatalf("cached balance = (%v, %v), want 60", cached, err)
+	}
+	if len(repo.audit) != 1 {
+		t.Fatalf("audit = %v, want one entry", repo.audit)
+	}
+	if a := repo.audit[0]; a.Operator != "alice" || a.Action != "adjust_balance" || a.Target != "u-1" || a.Reason != "duplicate top-up" || string(a.Details) != `{"amount":-40,"balance":60,"version":2}` {
+		t.Fatalf("audit entry = %+v", a)
+	}
+
+	// Without overdraft the balance cannot go below zero; nothing is
+	// audited then.
+	_, err = h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: -61, Reason: "chargeback", RequestId: "r-2"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.accounts["u-1"] != 60 || len(repo.audit) != 1 {
+		t.Fatalf("rejected adjustment left balance %d, %d audit entries", repo.accounts["u-1"], len(repo.audit))
+	}
+}
+
+func TestRematerializeBalance(t *testing.T) {
+	repo := newFakeRepo()
+	balances := cache.NewMemoryBalanceCache(10, time.Minute)
+	h := NewAdminHandlers(repo, balances, 10, money.RUB, nil)
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+	repo.accounts["u-1"] = 0
+	if _, err := h.AdjustBalance(ctx, &paymentsv1.AdjustBalanceRequest{UserId: "u-1", Amount: 100, Reason: "opening", RequestId: "r-1"}); err != nil {
+		t.Fatalf("AdjustBalance() error: %v", err)
+	}
+	// The stored balance drifts from the ledger.
+	repo.accounts["u-1"] = 130
+	repo.anomalies["u-1"] = true
+
+	_, err := h.RematerializeBalance(ctx, &paymentsv1.RematerializeBalanceRequest{UserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.RematerializeBalance(ctx, &paymentsv1.RematerializeBalanceRequest{UserId: "u-2", Reason: "drift"})
+	wantCode(t, err, codes.NotFound)
+
+	resp, err := h.RematerializeBalance(ctx, &paymentsv1.RematerializeBalanceRequest{UserId: "u-1", Reason: "drift"})
+	if err != nil || resp.GetAccount().GetBalance() != 100 || resp.GetPreviousBalance() != 130 || resp.GetResolvedAnomalies() != 1 {
+		t.Fatalf("RematerializeBalance() = (%v, %v), want 130 replaced by 100 and one anomaly resolved", resp, err)
+	}
+	if cached, err := balances.Get(ctx, "u-1"); err != nil || cached == nil || cached.Balance != 100 {
+		t.Fatalf("cached balance = (%v, %v), want 100", cached, err)
+	}
+	if len(repo.audit) != 2 {
+		t.Fatalf("audit = %v, want the adjustment and the rematerialization", repo.audit)
+	}
+	if a := repo.audit[1]; a.Action != "rematerialize_balance" || a.Target != "u-1" || string(a.Details) != `{"balance":100,"previous_balance":130,"resolved_anomalies":1,"version":3}` {
+		t.Fatalf("audit entry = %+v", a)
+	}
+
+	// A ledger balance the overdraft does not cover is left alone.
+	repo.ledger = append(repo.ledger, fakeLedgerEntry{userID: "u-1", delta: -150, at: time.Now()})
+	_, err = h.RematerializeBalance(ctx, &paymentsv1.RematerializeBalanceRequest{UserId: "u-1", Reason: "drift"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.accounts["u-1"] != 100 || len(repo.audit) != 2 {
+		t.Fatalf("rejected rematerialization left balance %d, %d audit entries", repo.accounts["u-1"], len(repo.audit))
+	}
+}
+
+func TestDisputes(t *testing.T) {
+	repo := newFakeRepo()
+	balances := cache.NewMemoryBalanceCache(10, time.Minute)
+	h := NewAdminHandlers(repo, balances, 10, money.RUB, nil)
+	ctx := userauth.NewContext(context.Background(), jwt.Claims{Subject: "alice", Roles: []jwt.Role{jwt.RoleAdmin}})
+	repo.accounts["u-1"] = 100
+	order, upheld, shared, unfinished := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	op := func(order uuid.UUID, userID string, delta, fee, bonus int64) {
+		repo.ops = append(repo.ops, &fakeOp{
+			orderID:   pgtype.UUID{Bytes: order, Valid: true},
+			paymentID: pgtype.UUID{Bytes: uuid.New(), Valid: true},
+			userID:    userID, delta: delta, fee: fee, bonus: bonus,
+		})
+	}
+	// 200 from the balance with a fee of 10 and 100 of bonus, then 50, and
+	// 40 with a fee of 5 by card.
+	op(order, "u-1", -300, 10, 100)
+	op(order, "u-1", -50, 0, 0)
+	op(order, "u-1", -40, 5, 0)
+	repo.ops[2].method = "psp"
+	op(upheld, "u-1", -70, 0, 0)
+	op(unfinished, "u-1", -30, 0, 0)
+	op(shared, "u-1", -10, 0, 0)
+	op(shared, "u-2", -10, 0, 0)
+
+	open := &paymentsv1.OpenDisputeRequest{OrderId: order.String(), Reason: "chargeback"}
+	_, err := h.OpenDispute(ctx, open)
+	wantCode(t, err, codes.Unimplemented)
+	finished := ordersv1.OrderStatus_ORDER_STATUS_FINISHED
+	h.UseDisputes("disputes", fakeOrders{statuses: map[string]ordersv1.OrderStatus{
+		order.String(): finished, upheld.String(): finished, shared.String(): finished,
+		unfinished.String(): ordersv1.OrderStatus_ORDER_STATUS_NEW,
+	}})
+	_, err = h.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: order.String()})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: uuid.NewString(), Reason: "chargeback"})
+	wantCode(t, err, codes.NotFound)
+	_, err = h.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: shared.String(), Reason: "chargeback"})
+	wantCode(t, err, codes.FailedPrecondition)
+	// A part payment of an order still in progress is not disputed.
+	_, err = h.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: unfinished.String(), Reason: "chargeback"})
+	wantCode(t, err, codes.FailedPrecondition)
+	if repo.ops[4].frozen {
+		t.Fatal("OpenDispute() of an unfinished order froze its operation")
+	}
+
+	resp, err := h.OpenDispute(ctx, open)
+	if err != nil || resp.GetDispute().GetAmount() != 305 || resp.GetDispute().GetStatus() != paymentsv1.DisputeStatus_DISPUTE_STATUS_OPEN || resp.GetDispute().GetUserId() != "u-1" {
+		t.Fatalf("OpenDispute() = (%v, %v), want an open dispute over 305", resp, err)
+	}
+	if !repo.ops[0].frozen || !repo.ops[1].frozen || !repo.ops[2].frozen || repo.ops[3].frozen {
+		t.Fatal("OpenDispute() froze the wrong operations")
+	}
+	_, err = h.OpenDispute(ctx, open)
+	wantCode(t, err, codes.FailedPrecondition)
+
+	_, err = h.ResolveDispute(ctx, &paymentsv1.ResolveDisputeRequest{OrderId: order.String(), Reason: "card issuer agreed"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ResolveDispute(ctx, &paymentsv1.ResolveDisputeRequest{OrderId: upheld.String(), Resolution: paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_UPHOLD, Reason: "delivered"})
+	wantCode(t, err, codes.NotFound)
+
+	// The card payment goes back through the card, not to the balance.
+	refund := &paymentsv1.ResolveDisputeRequest{OrderId: order.String(), Resolution: paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_REFUND, Reason: "card issuer agreed"}
+	resolved, err := h.ResolveDispute(ctx, refund)
+	if err != nil || resolved.GetDispute().GetStatus() != paymentsv1.DisputeStatus_DISPUTE_STATUS_REFUNDED || resolved.GetDispute().GetResolvedAt() == nil || resolved.GetAccount().GetBalance() != 360 {
+		t.Fatalf("ResolveDispute(refund) = (%v, %v), want refunded with balance 360", resolved, err)
+	}
+	if cached, err := balances.Get(ctx, "u-1"); err != nil || cached == nil || cached.Balance != 360 {
+		t.Fatalf("cached balance = (%v, %v), want 360", cached, err)
+	}
+	_, err = h.ResolveDispute(ctx, refund)
+	wantCode(t, err, codes.FailedPrecondition)
+
+	if _, err := h.OpenDispute(ctx, &paymentsv1.OpenDisputeRequest{OrderId: upheld.String(), Reason: "not delivered"}); err != nil {
+		t.Fatalf("OpenDispute(upheld) error = %v", err)
+	}
+	uphold, err := h.ResolveDispute(ctx, &paymentsv1.ResolveDisputeRequest{OrderId: upheld.String(), Resolution: paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_UPHOLD, Reason: "delivered"})
+	if err != nil || uphold.GetDispute().GetStatus() != paymentsv1.DisputeStatus_DISPUTE_STATUS_UPHELD || uphold.GetAccount() != nil {
+		t.Fatalf("ResolveDispute(uphold) = (%v, %v), want upheld without an account", uphold, err)
+	}
+	if repo.ops[3].frozen || repo.accounts["u-1"] != 360 {
+		t.Fatalf("upheld dispute left the operation frozen %v, balance %d", repo.ops[3].frozen, repo.accounts["u-1"])
+	}
+
+	var statuses []eventsv1.DisputeStatus
+	for _, row := range repo.outbox {
+		var ev eventsv1.PaymentDisputeChanged
+		if err := kafkasvc.UnmarshalEvent(row.Payload, &ev); err != nil {
+			t.Fatalf("unmarshal dispute event: %v", err)
+		}
+		if row.Topic != "disputes" || row.KafkaKey != ev.GetOrderId() || ev.GetUserId() != "u-1" {
+			t.Fatalf("outbox row %+v carries %v", row, &ev)
+		}
+		statuses = append(statuses, ev.GetStatus())
+	}
+	want := []eventsv1.DisputeStatus{
+		eventsv1.DisputeStatus_DISPUTE_STATUS_OPEN, eventsv1.DisputeStatus_DISPUTE_STATUS_REFUNDED,
+		eventsv1.DisputeStatus_DISPUTE_STATUS_OPEN, eventsv1.DisputeStatus_DISPUTE_STATUS_UPHELD,
+	}
+	if !slices.Equal(statuses, want) {
+		t.Fatalf("dispute events = %v, want %v", statuses, want)
+	}
+	if len(repo.audit) != 4 || repo.audit[0].Action != "open_dispute" || repo.audit[1].Action != "resolve_dispute" || repo.audit[0].Target != order.String() {
+		t.Fatalf("audit = %+v, want four dispute entries", repo.audit)
+	}
+}
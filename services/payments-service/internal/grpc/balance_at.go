@@ -0,0 +1,85 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// GetBalanceAt returns the balance as of req.At: the latest daily snapshot
+// before it plus the ledger entries in between. The cache is not involved.
+// Moments before the journal started are FailedPrecondition: the balance
+// then is not known.
+func (h *Handlers) GetBalanceAt(ctx context.Context, req *paymentsv1.GetBalanceAtRequest) (resp *paymentsv1.GetBalanceAtResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "get balance at start", "user_id", req.GetUserId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get balance at failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get balance at completed", "duration", time.Since(start))
+	}()
+
+	userID := req.GetUserId()
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	if req.GetAt() == nil {
+		return nil, status.Error(codes.InvalidArgument, "at is required")
+	}
+	if err := req.GetAt().CheckValid(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, "at is invalid")
+	}
+	at := req.GetAt().AsTime()
+	if at.After(start) {
+		return nil, status.Error(codes.InvalidArgument, "at must not be in the future")
+	}
+
+	var balance int64
+	err = h.repo.For(userID).Read(ctx, func(q db.Querier) error {
+		createdAt, err := q.GetAccountCreatedAt(ctx, userID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.NotFound, "account not found")
+			}
+			return err
+		}
+		if at.Before(createdAt.Time) {
+			return status.Error(codes.NotFound, "account did not exist at that time")
+		}
+		historyStart, err := q.GetBalanceHistoryStart(ctx)
+		if err != nil {
+			return err
+		}
+		if at.Before(historyStart.Time) {
+			return status.Errorf(codes.FailedPrecondition, "balance history starts at %s", historyStart.Time.UTC().Format(time.RFC3339))
+		}
+		balance, err = q.GetBalanceAt(ctx, db.GetBalanceAtParams{
+			UserID: userID,
+			At:     pgtype.Timestamptz{Time: at, Valid: true},
+		})
+		return err
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to get balance")
+	}
+
+	return &paymentsv1.GetBalanceAtResponse{
+		Balance:  balance,
+		At:       timestamppb.New(at),
+		Currency: string(h.currency),
+	}, nil
+}
@@ -0,0 +1,128 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// maxBalancesBatch bounds the user ids of one GetBalances call.
+const maxBalancesBatch = 100
+
+// GetBalances is GetBalance for many accounts: one cache round trip for all
+// of them, then one query per shard for the misses, which are cached in turn.
+func (h *Handlers) GetBalances(ctx context.Context, req *paymentsv1.GetBalancesRequest) (resp *paymentsv1.GetBalancesResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "get balances start", "user_ids", len(req.GetUserIds()))
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get balances failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get balances completed", "found", len(resp.GetBalances()), "missing", len(resp.GetMissingUserIds()), "duration", time.Since(start))
+	}()
+
+	userIDs, err := balancesUserIDs(req.GetUserIds())
+	if err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]cache.Balance, len(userIDs))
+	if h.cache != nil {
+		cached, err := h.cache.GetMany(ctx, userIDs)
+		if err != nil {
+			h.logger.ErrorContext(ctx, "cache mget failed", "err", err)
+		}
+		for id, b := range cached {
+			// Entries cached before accounts had versions and types are
+			// treated as misses, as in GetBalance.
+			if b != nil && b.Version > 0 && b.AccountType != "" {
+				found[id] = *b
+			}
+		}
+	}
+
+	shards := make(map[repo.PaymentsRepository][]string)
+	for _, id := range userIDs {
+		if _, ok := found[id]; !ok {
+			r := h.repo.For(id)
+			shards[r] = append(shards[r], id)
+		}
+	}
+	h.logger.DebugContext(ctx, "get balances cache done", "hits", len(found), "shards", len(shards))
+	for r, ids := range shards {
+		var rows []db.GetBalancesRow
+		err = r.Read(ctx, func(q db.Querier) error {
+			var err error
+			rows, err = q.GetBalances(ctx, ids)
+			return err
+		})
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get balances query failed", "err", err, "user_ids", len(ids))
+			return nil, status.Error(codes.Internal, "failed to get balances")
+		}
+		for _, row := range rows {
+			b := cache.Balance{
+				UserID:       row.UserID,
+				Balance:      row.Balance,
+				Version:      row.Version,
+				AccountType:  row.AccountType,
+				BonusBalance: row.BonusBalance,
+			}
+			found[row.UserID] = b
+			if h.cache != nil {
+				if err := h.cache.Set(ctx, b); err != nil {
+					h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", row.UserID)
+				}
+			}
+		}
+	}
+
+	resp = &paymentsv1.GetBalancesResponse{}
+	for _, id := range userIDs {
+		b, ok := found[id]
+		if !ok {
+			resp.MissingUserIds = append(resp.MissingUserIds, id)
+			continue
+		}
+		resp.Balances = append(resp.Balances, &paymentsv1.AccountBalance{
+			UserId:       id,
+			Balance:      b.Balance,
+			Currency:     string(h.currency),
+			Version:      b.Version,
+			AccountType:  accountTypeToProto(b.AccountType),
+			BonusBalance: b.BonusBalance,
+		})
+	}
+	return resp, nil
+}
+
+// balancesUserIDs validates the user ids of GetBalances and drops repeats,
+// keeping the first occurrence.
+func balancesUserIDs(ids []string) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_ids is required")
+	}
+	seen := make(map[string]bool, len(ids))
+	out := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id == "" {
+			return nil, status.Error(codes.InvalidArgument, "user_ids must not contain empty ids")
+		}
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	if len(out) > maxBalancesBatch {
+		return nil, status.Errorf(codes.InvalidArgument, "user_ids: at most %d accounts per call", maxBalancesBatch)
+	}
+	return out, nil
+}
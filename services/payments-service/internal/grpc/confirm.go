@@ -0,0 +1,83 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/challenge"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// ConfirmPayment makes a payment of the order that waits for the payer's
+// confirmation: the one req.PaymentId names, or the oldest.
+func (h *Handlers) ConfirmPayment(ctx context.Context, req *paymentsv1.ConfirmPaymentRequest) (resp *paymentsv1.ConfirmPaymentResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "confirm payment start", "user_id", req.GetUserId(), "order_id", req.GetOrderId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "confirm payment failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "confirm payment completed", "order_id", resp.GetOrderId(), "payment_id", resp.GetPaymentId(), "status", resp.GetStatus().String(), "duration", time.Since(start))
+	}()
+
+	userID := req.GetUserId()
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	orderID, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "order_id must be a UUID")
+	}
+	var paymentID pgtype.UUID
+	if req.GetPaymentId() != "" {
+		id, err := uuid.Parse(req.GetPaymentId())
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "payment_id must be a UUID")
+		}
+		paymentID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	if h.challenges == nil {
+		return nil, status.Error(codes.Unimplemented, "payment confirmation is not enabled")
+	}
+
+	var out challenge.Outcome
+	err = h.repo.For(userID).InTx(ctx, func(q db.Querier) error {
+		var err error
+		out, err = h.challenges.Confirm(ctx, q, userID, pgtype.UUID{Bytes: orderID, Valid: true}, paymentID)
+		return err
+	})
+	switch {
+	case errors.Is(err, challenge.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "no payment of the order waits for confirmation")
+	case err != nil:
+		return nil, status.Error(codes.Internal, "failed to confirm payment")
+	}
+
+	return &paymentsv1.ConfirmPaymentResponse{
+		OrderId:   orderID.String(),
+		PaymentId: out.PaymentID,
+		Status:    confirmStatusToProto(out.Status),
+		Reason:    out.Reason,
+	}, nil
+}
+
+func confirmStatusToProto(s challenge.Status) paymentsv1.ConfirmPaymentStatus {
+	switch s {
+	case challenge.Succeeded:
+		return paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_SUCCEEDED
+	case challenge.Failed:
+		return paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_FAILED
+	case challenge.Pending:
+		return paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_PENDING
+	default:
+		return paymentsv1.ConfirmPaymentStatus_CONFIRM_PAYMENT_STATUS_UNSPECIFIED
+	}
+}
@@ -0,0 +1,307 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+// UseDisputes enables OpenDispute and ResolveDispute; PaymentDisputeChanged
+// events go to topic, and orders is asked for the status of disputed orders.
+func (h *AdminHandlers) UseDisputes(topic string, orders ordersv1.OrdersServiceClient) {
+	h.disputeTopic, h.orders = topic, orders
+}
+
+// OpenDispute freezes the operations of an order and records the dispute in
+// the shard of the user who paid for it. The shards are searched for the
+// order first: its id does not say which one owns it. Only FINISHED orders
+// can be disputed: a part payment of an order still in progress or a payment
+// of a cancelled one is not a completed purchase.
+func (h *AdminHandlers) OpenDispute(ctx context.Context, req *paymentsv1.OpenDisputeRequest) (resp *paymentsv1.OpenDisputeResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "open dispute start", "operator", operator, "order_id", req.GetOrderId(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "open dispute failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "open dispute completed", "operator", operator, "order_id", req.GetOrderId(), "user_id", resp.GetDispute().GetUserId(), "amount", resp.GetDispute().GetAmount(), "duration", time.Since(start))
+	}()
+
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+	if h.disputeTopic == "" {
+		return nil, status.Error(codes.Unimplemented, "disputes are not enabled")
+	}
+	orderID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var (
+		shard  repo.PaymentsRepository
+		userID string
+	)
+	for _, r := range h.repo.All() {
+		var ops []db.ListOrderOpsRow
+		if err := r.Read(ctx, func(q db.Querier) error {
+			var err error
+			ops, err = q.ListOrderOps(ctx, orderID)
+			return err
+		}); err != nil {
+			return nil, status.Error(codes.Internal, "failed to open dispute")
+		}
+		for _, op := range ops {
+			if userID != "" && op.UserID != userID {
+				return nil, status.Error(codes.FailedPrecondition, "order was paid by more than one user")
+			}
+			shard, userID = r, op.UserID
+		}
+	}
+	if shard == nil {
+		return nil, status.Error(codes.NotFound, "order has no payments")
+	}
+	order, err := h.orders.GetOrder(ctx, &ordersv1.GetOrderRequest{UserId: userID, OrderId: oid.String()})
+	if status.Code(err) == codes.NotFound {
+		return nil, status.Error(codes.FailedPrecondition, "order not found in orders-service")
+	}
+	if err != nil {
+		h.logger.ErrorContext(ctx, "get order for dispute failed", "err", err, "order_id", oid.String())
+		return nil, status.Error(codes.Unavailable, "failed to check the order status")
+	}
+	if order.GetOrder().GetStatus() != ordersv1.OrderStatus_ORDER_STATUS_FINISHED {
+		return nil, status.Errorf(codes.FailedPrecondition, "only finished orders can be disputed, order is %s", order.GetOrder().GetStatus())
+	}
+
+	var dispute db.PaymentDispute
+	err = shard.InTx(ctx, func(q db.Querier) error {
+		var err error
+		dispute, err = q.OpenPaymentDispute(ctx, db.OpenPaymentDisputeParams{OrderID: orderID, UserID: userID, Reason: req.GetReason()})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.FailedPrecondition, "order was disputed already")
+		}
+		if err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]any{"user_id": userID, "amount": dispute.Amount})
+		if err != nil {
+			return err
+		}
+		if err := q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "open_dispute",
+			Target:   oid.String(),
+			Reason:   req.GetReason(),
+			Details:  details,
+		}); err != nil {
+			return err
+		}
+		return h.enqueueDispute(ctx, q, dispute)
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to open dispute")
+	}
+	return &paymentsv1.OpenDisputeResponse{Dispute: disputeToProto(dispute)}, nil
+}
+
+// ResolveDispute settles an open dispute with a refund to the payer's
+// balance or by upholding the payments.
+func (h *AdminHandlers) ResolveDispute(ctx context.Context, req *paymentsv1.ResolveDisputeRequest) (resp *paymentsv1.ResolveDisputeResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "resolve dispute start", "operator", operator, "order_id", req.GetOrderId(), "resolution", req.GetResolution().String(), "reason", req.GetReason())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "resolve dispute failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "resolve dispute completed", "operator", operator, "order_id", req.GetOrderId(), "status", resp.GetDispute().GetStatus().String(), "duration", time.Since(start))
+	}()
+
+	oid, err := uuid.Parse(req.GetOrderId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_id")
+	}
+	var outcome string
+	switch req.GetResolution() {
+	case paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_REFUND:
+		outcome = "refunded"
+	case paymentsv1.DisputeResolution_DISPUTE_RESOLUTION_UPHOLD:
+		outcome = "upheld"
+	default:
+		return nil, status.Error(codes.InvalidArgument, "resolution is required")
+	}
+	if strings.TrimSpace(req.GetReason()) == "" {
+		return nil, status.Error(codes.InvalidArgument, "reason is required")
+	}
+	if h.disputeTopic == "" {
+		return nil, status.Error(codes.Unimplemented, "disputes are not enabled")
+	}
+	orderID := pgtype.UUID{Bytes: oid, Valid: true}
+
+	var shard repo.PaymentsRepository
+	for _, r := range h.repo.All() {
+		err := r.Read(ctx, func(q db.Querier) error {
+			_, err := q.GetPaymentDispute(ctx, orderID)
+			return err
+		})
+		if err == nil {
+			shard = r
+			break
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, status.Error(codes.Internal, "failed to resolve dispute")
+		}
+	}
+	if shard == nil {
+		return nil, status.Error(codes.NotFound, "dispute not found")
+	}
+
+	var (
+		dispute db.PaymentDispute
+		account *db.RefundDisputedOpsRow
+	)
+	err = shard.InTx(ctx, func(q db.Querier) error {
+		open, err := q.LockOpenDispute(ctx, orderID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.FailedPrecondition, "dispute is resolved already")
+		}
+		if err != nil {
+			return err
+		}
+		if outcome == "refunded" {
+			row, err := q.RefundDisputedOps(ctx, db.RefundDisputedOpsParams{OrderID: orderID, UserID: open.UserID})
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.FailedPrecondition, "the payer has no account to refund to")
+			}
+			if err != nil {
+				return err
+			}
+			account = &row
+		} else if err := q.UnfreezeOrderOps(ctx, db.UnfreezeOrderOpsParams{OrderID: orderID, UserID: open.UserID}); err != nil {
+			return err
+		}
+		dispute, err = q.ResolvePaymentDispute(ctx, db.ResolvePaymentDisputeParams{
+			Status:           outcome,
+			ResolutionReason: pgtype.Text{String: req.GetReason(), Valid: true},
+			OrderID:          orderID,
+		})
+		if err != nil {
+			return err
+		}
+		details, err := json.Marshal(map[string]any{"user_id": dispute.UserID, "resolution": outcome, "amount": dispute.Amount})
+		if err != nil {
+			return err
+		}
+		if err := q.InsertAdminAudit(ctx, db.InsertAdminAuditParams{
+			Operator: operator,
+			Action:   "resolve_dispute",
+			Target:   oid.String(),
+			Reason:   req.GetReason(),
+			Details:  details,
+		}); err != nil {
+			return err
+		}
+		return h.enqueueDispute(ctx, q, dispute)
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to resolve dispute")
+	}
+
+	resp = &paymentsv1.ResolveDisputeResponse{Dispute: disputeToProto(dispute)}
+	if account != nil {
+		if h.cache != nil {
+			if err := h.cache.Set(ctx, cache.Balance{
+				UserID:       account.UserID,
+				Balance:      account.Balance,
+				Version:      account.Version,
+				AccountType:  account.AccountType,
+				BonusBalance: account.BonusBalance,
+			}); err != nil {
+				h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", account.UserID)
+			}
+		}
+		resp.Account = &paymentsv1.Account{
+			UserId:         account.UserID,
+			Balance:        account.Balance,
+			Currency:       string(h.currency),
+			Version:        account.Version,
+			OverdraftLimit: account.OverdraftLimit,
+			AccountType:    accountTypeToProto(account.AccountType),
+			BonusBalance:   account.BonusBalance,
+		}
+	}
+	return resp, nil
+}
+
+// enqueueDispute writes the PaymentDisputeChanged of d through the outbox.
+func (h *AdminHandlers) enqueueDispute(ctx context.Context, q db.Querier, d db.PaymentDispute) error {
+	return kafkasvc.EnqueuePaymentDisputeChanged(ctx, q, h.disputeTopic, &eventsv1.PaymentDisputeChanged{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       uuid.UUID(d.OrderID.Bytes).String(),
+		UserId:        d.UserID,
+		Status:        eventsv1.DisputeStatus(disputeStatusToProto(d.Status)),
+		Amount:        d.Amount,
+		Reason:        d.Reason,
+		CorrelationId: logging.RequestID(ctx),
+	})
+}
+
+// disputeStatusToProto maps a stored dispute status; the events enum has
+// the same numbers.
+func disputeStatusToProto(s string) paymentsv1.DisputeStatus {
+	switch s {
+	case "open":
+		return paymentsv1.DisputeStatus_DISPUTE_STATUS_OPEN
+	case "refunded":
+		return paymentsv1.DisputeStatus_DISPUTE_STATUS_REFUNDED
+	case "upheld":
+		return paymentsv1.DisputeStatus_DISPUTE_STATUS_UPHELD
+	default:
+		return paymentsv1.DisputeStatus_DISPUTE_STATUS_UNSPECIFIED
+	}
+}
+
+func disputeToProto(d db.PaymentDispute) *paymentsv1.Dispute {
+	out := &paymentsv1.Dispute{
+		OrderId:          uuid.UUID(d.OrderID.Bytes).String(),
+		UserId:           d.UserID,
+		Status:           disputeStatusToProto(d.Status),
+		Amount:           d.Amount,
+		Reason:           d.Reason,
+		ResolutionReason: d.ResolutionReason.String,
+		OpenedAt:         timestamppb.New(d.OpenedAt.Time),
+	}
+	if d.ResolvedAt.Valid {
+		out.ResolvedAt = timestamppb.New(d.ResolvedAt.Time)
+	}
+	return out
+}
@@ -0,0 +1,682 @@
+package grpc
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	ordersv1 "github.com/ilyaytrewq/payments-service/gen/go/orders/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// fakeRepo is an in-memory repo.PaymentsRepository. Queries the handlers do
+// not use fall through to the nil embedded Querier and panic.
+type fakeRepo struct {
+	db.Querier
+
+	mu       sync.Mutex
+	accounts map[string]int64
+	// changes counts balance updates per account; its version is 1 + changes.
+	changes map[string]int64
+	// overdraft holds per-account overdraft limits; absent means 0.
+	overdraft map[string]int64
+	// types holds account types other than the default BASIC.
+	types map[string]string
+	// grants are bonus grants, as written by GrantBonus.
+	grants []db.GrantBonusRow
+	idem   map[db.GetIdempotencyKeyParams]db.GetIdempotencyKeyRow
+	outbox []db.InsertOutboxParams
+	// sent are outbox rows already published, as seen by ReplayOutbox.
+	sent []fakeSentOutbox
+	// listed are outbox rows in any state, as seen by ListOutbox.
+	listed []db.ListOutboxRow
+	// dead are dead-lettered outbox rows, as seen by ListDeadOutbox.
+	dead     []db.ListDeadOutboxRow
+	requeued []int64
+	audit    []db.InsertAdminAuditParams
+	events   []fakeTopupEvent
+	created  map[string]time.Time
+	ledger   []fakeLedgerEntry
+	// historyStart is when the balance journal starts; zero means always.
+	historyStart time.Time
+	// batchReads counts GetBalances queries.
+	batchReads int
+	// ops are account operations, as seen by the dispute queries.
+	ops      []*fakeOp
+	disputes map[pgtype.UUID]*db.PaymentDispute
+	// anomalies are the users with an open balance anomaly.
+	anomalies map[string]bool
+	// rates are recorded exchange rates, as seen by ListExchangeRates.
+	rates []db.RecordExchangeRateParams
+}
+
+type fakeOp struct {
+	orderID, paymentID pgtype.UUID
+	userID             string
+	delta, fee, bonus  int64
+	frozen             bool
+	// method is the payment method; empty means balance.
+	method string
+}
+
+type fakeSentOutbox struct {
+	row       db.InsertOutboxParams
+	createdAt time.Time
+}
+
+type fakeLedgerEntry struct {
+	userID string
+	delta  int64
+	at     time.Time
+}
+
+type fakeTopupEvent struct {
+	userID    string
+	amount    int64
+	createdAt time.Time
+}
+
+var (
+	_ repo.PaymentsRepository = (*fakeRepo)(nil)
+	_ repo.ShardedRepository  = (*fakeRepo)(nil)
+	_ repo.ShardedRepository  = (*fakeShards)(nil)
+)
+
+// For and All make a fakeRepo a single-shard repository.
+func (f *fakeRepo) For(string) repo.PaymentsRepository {
+	return f
+}
+
+func (f *fakeRepo) All() []repo.PaymentsRepository {
+	return []repo.PaymentsRepository{f}
+}
+
+// fakeShards routes the users in of to their shard and everyone else to
+// shard 0.
+type fakeShards struct {
+	shards []*fakeRepo
+	of     map[string]int
+}
+
+func (s *fakeShards) For(userID string) repo.PaymentsRepository {
+	return s.shards[s.of[userID]]
+}
+
+func (s *fakeShards) All() []repo.PaymentsRepository {
+	all := make([]repo.PaymentsRepository, len(s.shards))
+	for i, f := range s.shards {
+		all[i] = f
+	}
+	return all
+}
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{
+		accounts:  map[string]int64{},
+		changes:   map[string]int64{},
+		overdraft: map[string]int64{},
+		types:     map[string]string{},
+		idem:      map[db.GetIdempotencyKeyParams]db.GetIdempotencyKeyRow{},
+		created:   map[string]time.Time{},
+		disputes:  map[pgtype.UUID]*db.PaymentDispute{},
+		anomalies: map[string]bool{},
+	}
+}
+
+func (f *fakeRepo) Q() db.Querier {
+	return f
+}
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error {
+	return fn(f)
+}
+
+// InTx restores the previous state when fn fails, like a rolled back tx.
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	f.mu.Lock()
+	accounts := make(map[string]int64, len(f.accounts))
+	for k, v := range f.accounts {
+		accounts[k] = v
+	}
+	changes := maps.Clone(f.changes)
+	idem := maps.Clone(f.idem)
+	outbox := append([]db.InsertOutboxParams(nil), f.outbox...)
+	events := append([]fakeTopupEvent(nil), f.events...)
+	ledger := append([]fakeLedgerEntry(nil), f.ledger...)
+	audit := append([]db.InsertAdminAuditParams(nil), f.audit...)
+	f.mu.Unlock()
+
+	if err := fn(f); err != nil {
+		f.mu.Lock()
+		f.accounts, f.changes, f.idem, f.outbox, f.events, f.ledger, f.audit = accounts, changes, idem, outbox, events, ledger, audit
+		f.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (f *fakeRepo) CreateAccount(_ context.Context, userID string) (db.CreateAccountRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.accounts[userID]; ok {
+		return db.CreateAccountRow{}, pgx.ErrNoRows
+	}
+	f.accounts[userID] = 0
+	f.created[userID] = time.Now()
+	return db.CreateAccountRow{UserID: userID, Version: 1, AccountType: f.typeOf(userID)}, nil
+}
+
+func (f *fakeRepo) CreateAccountIdempotent(_ context.Context, userID string) (db.CreateAccountIdempotentRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[userID]
+	if !ok {
+		f.accounts[userID] = 0
+		f.created[userID] = time.Now()
+	}
+	return db.CreateAccountIdempotentRow{UserID: userID, Balance: balance, Version: 1 + f.changes[userID], AccountType: f.typeOf(userID), Created: !ok}, nil
+}
+
+func (f *fakeRepo) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.outbox = append(f.outbox, arg)
+	return int64(len(f.outbox)), nil
+}
+
+func (f *fakeRepo) GetBalance(_ context.Context, userID string) (db.GetBalanceRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[userID]
+	if !ok {
+		return db.GetBalanceRow{}, pgx.ErrNoRows
+	}
+	return db.GetBalanceRow{Balance: balance, Version: 1 + f.changes[userID], AccountType: f.typeOf(userID), BonusBalance: f.bonusOf(userID)}, nil
+}
+
+func (f *fakeRepo) GetBalances(_ context.Context, userIDs []string) ([]db.GetBalancesRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.batchReads++
+	var rows []db.GetBalancesRow
+	for _, id := range userIDs {
+		if balance, ok := f.accounts[id]; ok {
+			rows = append(rows, db.GetBalancesRow{UserID: id, Balance: balance, Version: 1 + f.changes[id], AccountType: f.typeOf(id), BonusBalance: f.bonusOf(id)})
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeRepo) TopUp(_ context.Context, arg db.TopUpParams) (db.TopUpRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[arg.UserID]
+	if !ok || (arg.ExpectedVersion != 0 && arg.ExpectedVersion != 1+f.changes[arg.UserID]) {
+		return db.TopUpRow{}, pgx.ErrNoRows
+	}
+	balance += arg.Balance
+	f.accounts[arg.UserID] = balance
+	f.changes[arg.UserID]++
+	f.ledger = append(f.ledger, fakeLedgerEntry{userID: arg.UserID, delta: arg.Balance, at: time.Now()})
+	return db.TopUpRow{UserID: arg.UserID, Balance: balance, Version: 1 + f.changes[arg.UserID], AccountType: f.typeOf(arg.UserID), BonusBalance: f.bonusOf(arg.UserID)}, nil
+}
+
+func (f *fakeRepo) GetAccountCreatedAt(_ context.Context, userID string) (pgtype.Timestamptz, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	created, ok := f.created[userID]
+	if !ok {
+		return pgtype.Timestamptz{}, pgx.ErrNoRows
+	}
+	return pgtype.Timestamptz{Time: created, Valid: true}, nil
+}
+
+func (f *fakeRepo) GetBalanceHistoryStart(context.Context) (pgtype.Timestamptz, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return pgtype.Timestamptz{Time: f.historyStart, Valid: true}, nil
+}
+
+// LockPendingChallenge finds nothing: the fake keeps no payment
+// challenges.
+func (f *fakeRepo) LockPendingChallenge(context.Context, db.LockPendingChallengeParams) (db.PaymentChallenge, error) {
+	return db.PaymentChallenge{}, pgx.ErrNoRows
+}
+
+// GetBalanceAt sums the whole ledger; the fake keeps no snapshots.
+func (f *fakeRepo) GetBalanceAt(_ context.Context, arg db.GetBalanceAtParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var balance int64
+	for _, e := range f.ledger {
+		if e.userID == arg.UserID && !e.at.After(arg.At.Time) {
+			balance += e.delta
+		}
+	}
+	return balance, nil
+}
+
+// ListTransactions numbers ledger entries from 1 in insertion order; the
+// fake ledger only holds top-ups.
+func (f *fakeRepo) ListTransactions(_ context.Context, arg db.ListTransactionsParams) ([]db.ListTransactionsRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var rows []db.ListTransactionsRow
+	for i := len(f.ledger) - 1; i >= 0 && len(rows) < int(arg.PageSize); i-- {
+		id := int64(i + 1)
+		e := f.ledger[i]
+		if e.userID != arg.UserID || (arg.BeforeID != 0 && id >= arg.BeforeID) {
+			continue
+		}
+		rows = append(rows, db.ListTransactionsRow{ID: id, Delta: e.delta, Kind: "balance", CreatedAt: pgtype.Timestamptz{Time: e.at, Valid: true}})
+	}
+	return rows, nil
+}
+
+// ListExchangeRates returns every rate recorded up to until; At picks the
+// right one regardless of since.
+func (f *fakeRepo) ListExchangeRates(_ context.Context, arg db.ListExchangeRatesParams) ([]db.ListExchangeRatesRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var rows []db.ListExchangeRatesRow
+	for _, r := range f.rates {
+		if r.Currency == arg.Currency && !r.AsOf.Time.After(arg.Until.Time) {
+			rows = append(rows, db.ListExchangeRatesRow{AsOf: r.AsOf, Price: r.Price})
+		}
+	}
+	slices.SortFunc(rows, func(a, b db.ListExchangeRatesRow) int { return a.AsOf.Time.Compare(b.AsOf.Time) })
+	return rows, nil
+}
+
+func (f *fakeRepo) LockAccount(_ context.Context, userID string) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.accounts[userID]; !ok {
+		return "", pgx.ErrNoRows
+	}
+	return userID, nil
+}
+
+func (f *fakeRepo) TopupVelocity(_ context.Context, arg db.TopupVelocityParams) (db.TopupVelocityRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var row db.TopupVelocityRow
+	for _, e := range f.events {
+		if e.userID != arg.UserID {
+			continue
+		}
+		if e.createdAt.After(arg.CountSince.Time) {
+			row.RecentCount++
+		}
+		if e.createdAt.After(arg.AmountSince.Time) {
+			row.RecentAmount += e.amount
+		}
+	}
+	return row, nil
+}
+
+func (f *fakeRepo) InsertTopupEvent(_ context.Context, arg db.InsertTopupEventParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.events = append(f.events, fakeTopupEvent{userID: arg.UserID, amount: arg.Amount, createdAt: time.Now()})
+	return nil
+}
+
+func (f *fakeRepo) DeleteTopupEventsBefore(_ context.Context, arg db.DeleteTopupEventsBeforeParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	kept := f.events[:0]
+	for _, e := range f.events {
+		if e.userID != arg.UserID || !e.createdAt.Before(arg.CreatedAt.Time) {
+			kept = append(kept, e)
+		}
+	}
+	f.events = kept
+	return nil
+}
+
+func (f *fakeRepo) ClaimIdempotencyKey(_ context.Context, arg db.ClaimIdempotencyKeyParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := db.GetIdempotencyKeyParams{Scope: arg.Scope, UserID: arg.UserID, IdempotencyKey: arg.IdempotencyKey}
+	if _, ok := f.idem[key]; ok {
+		return 0, nil
+	}
+	f.idem[key] = db.GetIdempotencyKeyRow{RequestHash: arg.RequestHash, Status: "IN_PROGRESS"}
+	return 1, nil
+}
+
+func (f *fakeRepo) GetIdempotencyKey(_ context.Context, arg db.GetIdempotencyKeyParams) (db.GetIdempotencyKeyRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	row, ok := f.idem[arg]
+	if !ok {
+		return db.GetIdempotencyKeyRow{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (f *fakeRepo) CompleteIdempotencyKey(_ context.Context, arg db.CompleteIdempotencyKeyParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := db.GetIdempotencyKeyParams{Scope: arg.Scope, UserID: arg.UserID, IdempotencyKey: arg.IdempotencyKey}
+	row := f.idem[key]
+	row.Status, row.Response = "COMPLETED", arg.Response
+	f.idem[key] = row
+	return nil
+}
+
+func (f *fakeRepo) sentInRange(arg db.CountSentOutboxParams) []db.InsertOutboxParams {
+	var rows []db.InsertOutboxParams
+	for _, o := range f.sent {
+		if !o.createdAt.Before(arg.CreatedFrom.Time) && o.createdAt.Before(arg.CreatedTo.Time) && (arg.Topic == "" || o.row.Topic == arg.Topic) {
+			rows = append(rows, o.row)
+		}
+	}
+	return rows
+}
+
+func (f *fakeRepo) CountSentOutbox(_ context.Context, arg db.CountSentOutboxParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return int64(len(f.sentInRange(arg))), nil
+}
+
+func (f *fakeRepo) ReplaySentOutbox(_ context.Context, arg db.ReplaySentOutboxParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rows := f.sentInRange(db.CountSentOutboxParams(arg))
+	f.outbox = append(f.outbox, rows...)
+	return int64(len(rows)), nil
+}
+
+func (f *fakeRepo) ListOutbox(_ context.Context, arg db.ListOutboxParams) ([]db.ListOutboxRow, error) {
+	var rows []db.ListOutboxRow
+	for _, r := range f.listed {
+		if !slices.Contains(arg.Statuses, r.Status) || r.ID <= arg.AfterID || (arg.Topic != "" && r.Topic != arg.Topic) {
+			continue
+		}
+		if (arg.CreatedFrom.Valid && r.CreatedAt.Time.Before(arg.CreatedFrom.Time)) || (arg.CreatedTo.Valid && !r.CreatedAt.Time.Before(arg.CreatedTo.Time)) {
+			continue
+		}
+		if len(rows) < int(arg.PageSize) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func (f *fakeRepo) ListDeadOutbox(_ context.Context, arg db.ListDeadOutboxParams) ([]db.ListDeadOutboxRow, error) {
+	var rows []db.ListDeadOutboxRow
+	for _, r := range f.dead {
+		if r.ID > arg.AfterID && (arg.Topic == "" || r.Topic == arg.Topic) && len(rows) < int(arg.PageSize) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+// SetOverdraftLimit mirrors the accounts balance check constraint.
+func (f *fakeRepo) SetOverdraftLimit(_ context.Context, arg db.SetOverdraftLimitParams) (db.SetOverdraftLimitRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[arg.UserID]
+	if !ok {
+		return db.SetOverdraftLimitRow{}, pgx.ErrNoRows
+	}
+	if balance < -arg.OverdraftLimit {
+		return db.SetOverdraftLimitRow{}, &pgconn.PgError{Code: checkViolation}
+	}
+	f.overdraft[arg.UserID] = arg.OverdraftLimit
+	return db.SetOverdraftLimitRow{UserID: arg.UserID, Balance: balance, Version: 1 + f.changes[arg.UserID], OverdraftLimit: arg.OverdraftLimit, AccountType: f.typeOf(arg.UserID)}, nil
+}
+
+func (f *fakeRepo) SetAccountType(_ context.Context, arg db.SetAccountTypeParams) (db.SetAccountTypeRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[arg.UserID]
+	if !ok {
+		return db.SetAccountTypeRow{}, pgx.ErrNoRows
+	}
+	if balance < -arg.OverdraftLimit {
+		return db.SetAccountTypeRow{}, &pgconn.PgError{Code: checkViolation}
+	}
+	f.types[arg.UserID] = arg.AccountType
+	f.overdraft[arg.UserID] = arg.OverdraftLimit
+	return db.SetAccountTypeRow{UserID: arg.UserID, Balance: balance, Version: 1 + f.changes[arg.UserID], OverdraftLimit: arg.OverdraftLimit, AccountType: arg.AccountType}, nil
+}
+
+// typeOf returns the account type of userID; the caller holds f.mu.
+func (f *fakeRepo) typeOf(userID string) string {
+	if t, ok := f.types[userID]; ok {
+		return t
+	}
+	return "BASIC"
+}
+
+func (f *fakeRepo) GrantBonus(_ context.Context, arg db.GrantBonusParams) (db.GrantBonusRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.accounts[arg.UserID]; !ok {
+		return db.GrantBonusRow{}, pgx.ErrNoRows
+	}
+	g := db.GrantBonusRow{
+		ID:        int64(len(f.grants) + 1),
+		UserID:    arg.UserID,
+		Amount:    arg.Amount,
+		Remaining: arg.Amount,
+		ExpiresAt: arg.ExpiresAt,
+		CreatedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	f.grants = append(f.grants, g)
+	return g, nil
+}
+
+// bonusOf sums the unexpired bonus of userID; the caller holds f.mu.
+func (f *fakeRepo) bonusOf(userID string) int64 {
+	var sum int64
+	for _, g := range f.grants {
+		if g.UserID == userID && g.ExpiresAt.Time.After(time.Now()) {
+			sum += g.Remaining
+		}
+	}
+	return sum
+}
+
+// RequeueDeadOutbox moves the matching rows of f.dead to f.requeued.
+func (f *fakeRepo) RequeueDeadOutbox(_ context.Context, arg db.RequeueDeadOutboxParams) (int64, error) {
+	var kept []db.ListDeadOutboxRow
+	var n int64
+	for _, r := range f.dead {
+		if (arg.AllEvents || slices.Contains(arg.Ids, r.ID)) && (arg.Topic == "" || r.Topic == arg.Topic) {
+			f.requeued = append(f.requeued, r.ID)
+			n++
+			continue
+		}
+		kept = append(kept, r)
+	}
+	f.dead = kept
+	return n, nil
+}
+
+// AdjustBalance mirrors the accounts balance check constraint.
+func (f *fakeRepo) AdjustBalance(_ context.Context, arg db.AdjustBalanceParams) (db.AdjustBalanceRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[arg.UserID]
+	if !ok {
+		return db.AdjustBalanceRow{}, pgx.ErrNoRows
+	}
+	balance += arg.Delta
+	if balance < -f.overdraft[arg.UserID] {
+		return db.AdjustBalanceRow{}, &pgconn.PgError{Code: checkViolation}
+	}
+	f.accounts[arg.UserID] = balance
+	f.changes[arg.UserID]++
+	f.ledger = append(f.ledger, fakeLedgerEntry{userID: arg.UserID, delta: arg.Delta, at: time.Now()})
+	return db.AdjustBalanceRow{UserID: arg.UserID, Balance: balance, Version: 1 + f.changes[arg.UserID], OverdraftLimit: f.overdraft[arg.UserID], AccountType: f.typeOf(arg.UserID), BonusBalance: f.bonusOf(arg.UserID)}, nil
+}
+
+// GetLedgerBalance sums the whole ledger, like GetBalanceAt.
+func (f *fakeRepo) GetLedgerBalance(_ context.Context, userID string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var balance int64
+	for _, e := range f.ledger {
+		if e.userID == userID {
+			balance += e.delta
+		}
+	}
+	return balance, nil
+}
+
+// SetMaterializedBalance mirrors the accounts balance check constraint.
+func (f *fakeRepo) SetMaterializedBalance(_ context.Context, arg db.SetMaterializedBalanceParams) (db.SetMaterializedBalanceRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.accounts[arg.UserID]; !ok {
+		return db.SetMaterializedBalanceRow{}, pgx.ErrNoRows
+	}
+	if arg.Balance < -f.overdraft[arg.UserID] {
+		return db.SetMaterializedBalanceRow{}, &pgconn.PgError{Code: checkViolation}
+	}
+	f.accounts[arg.UserID] = arg.Balance
+	f.changes[arg.UserID]++
+	return db.SetMaterializedBalanceRow{UserID: arg.UserID, Balance: arg.Balance, Version: 1 + f.changes[arg.UserID], OverdraftLimit: f.overdraft[arg.UserID], AccountType: f.typeOf(arg.UserID), BonusBalance: f.bonusOf(arg.UserID)}, nil
+}
+
+func (f *fakeRepo) ResolveBalanceAnomalies(_ context.Context, arg db.ResolveBalanceAnomaliesParams) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if !f.anomalies[arg.UserID] {
+		return 0, nil
+	}
+	delete(f.anomalies, arg.UserID)
+	return 1, nil
+}
+
+func (f *fakeRepo) InsertAdminAudit(_ context.Context, arg db.InsertAdminAuditParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.audit = append(f.audit, arg)
+	return nil
+}
+
+func (f *fakeRepo) ListOrderOps(_ context.Context, orderID pgtype.UUID) ([]db.ListOrderOpsRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var out []db.ListOrderOpsRow
+	for _, op := range f.ops {
+		if op.orderID == orderID {
+			out = append(out, db.ListOrderOpsRow{PaymentID: op.paymentID, UserID: op.userID, Delta: op.delta, Fee: op.fee, Bonus: op.bonus, Frozen: op.frozen})
+		}
+	}
+	return out, nil
+}
+
+func (f *fakeRepo) OpenPaymentDispute(_ context.Context, arg db.OpenPaymentDisputeParams) (db.PaymentDispute, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if _, ok := f.disputes[arg.OrderID]; ok {
+		return db.PaymentDispute{}, pgx.ErrNoRows
+	}
+	var amount int64
+	frozen := 0
+	for _, op := range f.ops {
+		if op.orderID == arg.OrderID && op.userID == arg.UserID && !op.frozen {
+			op.frozen = true
+			amount += -op.delta - op.bonus + op.fee
+			frozen++
+		}
+	}
+	if frozen == 0 {
+		return db.PaymentDispute{}, pgx.ErrNoRows
+	}
+	d := &db.PaymentDispute{OrderID: arg.OrderID, UserID: arg.UserID, Amount: amount, Status: "open", Reason: arg.Reason, OpenedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}
+	f.disputes[arg.OrderID] = d
+	return *d, nil
+}
+
+func (f *fakeRepo) GetPaymentDispute(_ context.Context, orderID pgtype.UUID) (db.PaymentDispute, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.disputes[orderID]
+	if !ok {
+		return db.PaymentDispute{}, pgx.ErrNoRows
+	}
+	return *d, nil
+}
+
+func (f *fakeRepo) LockOpenDispute(ctx context.Context, orderID pgtype.UUID) (db.PaymentDispute, error) {
+	d, err := f.GetPaymentDispute(ctx, orderID)
+	if err == nil && d.Status != "open" {
+		return db.PaymentDispute{}, pgx.ErrNoRows
+	}
+	return d, err
+}
+
+func (f *fakeRepo) ResolvePaymentDispute(_ context.Context, arg db.ResolvePaymentDisputeParams) (db.PaymentDispute, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	d, ok := f.disputes[arg.OrderID]
+	if !ok || d.Status != "open" {
+		return db.PaymentDispute{}, pgx.ErrNoRows
+	}
+	d.Status, d.ResolutionReason = arg.Status, arg.ResolutionReason
+	d.ResolvedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return *d, nil
+}
+
+func (f *fakeRepo) UnfreezeOrderOps(_ context.Context, arg db.UnfreezeOrderOpsParams) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, op := range f.ops {
+		if op.orderID == arg.OrderID && op.userID == arg.UserID {
+			op.frozen = false
+		}
+	}
+	return nil
+}
+
+func (f *fakeRepo) RefundDisputedOps(_ context.Context, arg db.RefundDisputedOpsParams) (db.RefundDisputedOpsRow, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	balance, ok := f.accounts[arg.UserID]
+	if !ok {
+		return db.RefundDisputedOpsRow{}, pgx.ErrNoRows
+	}
+	for _, op := range f.ops {
+		if op.orderID == arg.OrderID && op.userID == arg.UserID && op.frozen && (op.method == "" || op.method == "balance") {
+			balance += -op.delta - op.bonus + op.fee
+			f.ledger = append(f.ledger, fakeLedgerEntry{userID: arg.UserID, delta: -op.delta - op.bonus + op.fee, at: time.Now()})
+		}
+	}
+	f.accounts[arg.UserID] = balance
+	f.changes[arg.UserID]++
+	return db.RefundDisputedOpsRow{UserID: arg.UserID, Balance: balance, Version: 1 + f.changes[arg.UserID], AccountType: f.typeOf(arg.UserID), BonusBalance: f.bonusOf(arg.UserID)}, nil
+}
+
+// fakeOrders is the OrdersService of orders-service as OpenDispute asks it:
+// orders missing from statuses are not found.
+type fakeOrders struct {
+	ordersv1.OrdersServiceClient
+	statuses map[string]ordersv1.OrderStatus
+}
+
+func (f fakeOrders) GetOrder(_ context.Context, req *ordersv1.GetOrderRequest, _ ...grpc.CallOption) (*ordersv1.GetOrderResponse, error) {
+	st, ok := f.statuses[req.GetOrderId()]
+	if !ok {
+		return nil, status.Error(codes.NotFound, "order not found")
+	}
+	return &ordersv1.GetOrderResponse{Order: &ordersv1.Order{OrderId: req.GetOrderId(), UserId: req.GetUserId(), Status: st}}, nil
+}
@@ -12,210 +12,298 @@ import (
 
 	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
-	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/challenge"
+	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rates"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
 	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/idempotency"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
 )
 
 type Handlers struct {
 	paymentsv1.UnimplementedPaymentsServiceServer
-	repo  *postgres.Repo
-	cache *cache.BalanceCache
+	repo  repo.ShardedRepository
+	cache cache.BalanceCache
+
+	accountCreatedTopic string
+	limits              TopUpLimits
+	currency            money.Currency
+	// rates may be nil: GetRates and display currencies are then
+	// unavailable.
+	rates rates.Provider
+	// challenges may be nil: ConfirmPayment is then unavailable.
+	challenges *challenge.Confirmer
+
+	logger *slog.Logger
 }
 
-var logger = slog.Default().With("service", "payments-service", "component", "grpc")
+func NewHandlers(repo repo.ShardedRepository, cache cache.BalanceCache, accountCreatedTopic string, limits TopUpLimits, currency money.Currency, rateProvider rates.Provider) *Handlers {
+	logger := slog.Default().With("service", "payments-service", "component", "grpc")
+	logger.Info("handlers initialized", "currency", currency, "topup_max_per_minute", limits.MaxPerMinute, "topup_max_amount_per_hour", limits.MaxAmountPerHour)
+	return &Handlers{repo: repo, cache: cache, accountCreatedTopic: accountCreatedTopic, limits: limits, currency: currency, rates: rateProvider, logger: logger}
+}
 
-func NewHandlers(repo *postgres.Repo, cache *cache.BalanceCache) *Handlers {
-	logger.Info("handlers initialized")
-	return &Handlers{repo: repo, cache: cache}
+// UseChallenges serves ConfirmPayment with c.
+func (h *Handlers) UseChallenges(c *challenge.Confirmer) {
+	h.challenges = c
 }
 
 func (h *Handlers) CreateAccount(ctx context.Context, req *paymentsv1.CreateAccountRequest) (resp *paymentsv1.CreateAccountResponse, err error) {
 	start := time.Now()
-	logger.Info("create account start", "user_id", req.GetUserId(), "has_idempotency_key", req.GetIdempotencyKey() != "")
+	h.logger.DebugContext(ctx, "create account start", "user_id", req.GetUserId(), "has_idempotency_key", req.GetIdempotencyKey() != "")
 	defer func() {
 		if err != nil {
-			logger.Error("create account failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "create account failed", "err", err, "duration", time.Since(start))
 			return
 		}
-		logger.Info("create account completed", "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "create account completed", "duration", time.Since(start))
 	}()
 
 	userID := req.GetUserId()
 	if userID == "" {
 		err = status.Error(codes.InvalidArgument, "user_id is required")
-		logger.Error("create account validation failed", "err", err)
+		h.logger.ErrorContext(ctx, "create account validation failed", "err", err)
 		return nil, err
 	}
 
-	idemKey := req.GetIdempotencyKey()
+	var replayed bool
+	err = h.repo.For(userID).InTx(ctx, func(q db.Querier) error {
+		if req.GetIdempotencyKey() == "" {
+			resp, err = h.createAccount(ctx, q, userID, false)
+			return err
+		}
+		resp, replayed, err = idempotency.Do(ctx, repo.IdempotencyStore(q), idempotency.Key{
+			Scope:  "payments.CreateAccount",
+			UserID: userID,
+			Key:    req.GetIdempotencyKey(),
+		}, req, &paymentsv1.CreateAccountResponse{}, func() (*paymentsv1.CreateAccountResponse, error) {
+			return h.createAccount(ctx, q, userID, true)
+		})
+		return err
+	})
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			err = st.Err()
+			return nil, err
+		}
+		err = status.Error(codes.Internal, "failed to create account")
+		return nil, err
+	}
+
+	// A replayed response carries the balance at creation, which may be stale.
+	if h.cache != nil && !replayed {
+		if err := h.cache.Set(ctx, cache.Balance{
+			UserID:       resp.GetAccount().GetUserId(),
+			Balance:      resp.GetAccount().GetBalance(),
+			Version:      resp.GetAccount().GetVersion(),
+			AccountType:  accountTypeName(resp.GetAccount().GetAccountType()),
+			BonusBalance: resp.GetAccount().GetBonusBalance(),
+		}); err != nil {
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", resp.GetAccount().GetUserId())
+		}
+	}
+	return resp, nil
+}
+
+// createAccount creates the account and enqueues AccountCreated. With
+// existingOK an existing account is returned instead of AlreadyExists, which
+// is what a keyed request whose key is new expects.
+func (h *Handlers) createAccount(ctx context.Context, q db.Querier, userID string, existingOK bool) (*paymentsv1.CreateAccountResponse, error) {
 	var (
-		accountUserID  string
-		accountBalance int64
+		balance   int64
+		version   int64
+		overdraft int64
+		typ       string
+		bonus     int64
+		created   bool
 	)
-	if idemKey == "" {
-		account, err := h.repo.Q().CreateAccount(ctx, userID)
+	if !existingOK {
+		account, err := q.CreateAccount(ctx, userID)
 		if err != nil {
 			if errors.Is(err, pgx.ErrNoRows) {
 				err = status.Error(codes.AlreadyExists, "account already exists")
-				logger.Error("create account conflict", "err", err)
+				h.logger.ErrorContext(ctx, "create account conflict", "err", err)
 				return nil, err
 			}
-			err = status.Error(codes.Internal, "failed to create account")
-			logger.Error("create account failed", "err", err)
+			h.logger.ErrorContext(ctx, "create account failed", "err", err)
 			return nil, err
 		}
-		accountUserID = account.UserID
-		accountBalance = account.Balance
+		balance, version, typ, created = account.Balance, account.Version, account.AccountType, true
 	} else {
-		account, err := h.repo.Q().CreateAccountIdempotent(ctx, userID)
+		account, err := q.CreateAccountIdempotent(ctx, userID)
 		if err != nil {
-			err = status.Error(codes.Internal, "failed to create account")
-			logger.Error("create account idempotent failed", "err", err)
+			h.logger.ErrorContext(ctx, "create account idempotent failed", "err", err)
+			return nil, err
+		}
+		balance, version, overdraft, typ, bonus, created = account.Balance, account.Version, account.OverdraftLimit, account.AccountType, account.BonusBalance, account.Created
+	}
+	if created {
+		if err := kafkasvc.EnqueueAccountCreated(ctx, q, h.accountCreatedTopic, userID); err != nil {
 			return nil, err
 		}
-		accountUserID = account.UserID
-		accountBalance = account.Balance
 	}
+	return &paymentsv1.CreateAccountResponse{
+		Account: &paymentsv1.Account{
+			UserId:         userID,
+			Balance:        balance,
+			Currency:       string(h.currency),
+			Version:        version,
+			OverdraftLimit: overdraft,
+			AccountType:    accountTypeToProto(typ),
+			BonusBalance:   bonus,
+		},
+	}, nil
+}
 
+// topUp credits the account. With expected_version set the update is a
+// compare-and-swap; when it matches no row, the account is re-read to tell a
+// missing account (NotFound) from a lost race (Aborted). The re-read version
+// also refreshes the cache, so a client retrying after GetBalance does not
+// keep seeing the stale one.
+func (h *Handlers) topUp(ctx context.Context, q db.Querier, req *paymentsv1.TopUpRequest) (db.TopUpRow, error) {
+	account, err := q.TopUp(ctx, db.TopUpParams{
+		UserID:          req.GetUserId(),
+		Balance:         req.GetAmount(),
+		ExpectedVersion: req.GetExpectedVersion(),
+	})
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return account, err
+	}
+	current, err := q.GetBalance(ctx, req.GetUserId())
+	if errors.Is(err, pgx.ErrNoRows) {
+		err = status.Error(codes.NotFound, "account not found")
+		h.logger.ErrorContext(ctx, "top up account not found", "err", err)
+		return account, err
+	}
+	if err != nil {
+		return account, err
+	}
 	if h.cache != nil {
 		if err := h.cache.Set(ctx, cache.Balance{
-			UserID:  accountUserID,
-			Balance: accountBalance,
+			UserID:       req.GetUserId(),
+			Balance:      current.Balance,
+			Version:      current.Version,
+			AccountType:  current.AccountType,
+			BonusBalance: current.BonusBalance,
 		}); err != nil {
-			logger.Error("cache set failed", "err", err, "user_id", accountUserID)
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", req.GetUserId())
 		}
 	}
-
-	resp = &paymentsv1.CreateAccountResponse{
-		Account: &paymentsv1.Account{
-			UserId:  accountUserID,
-			Balance: accountBalance,
-		},
-	}
-	return resp, nil
+	err = status.Errorf(codes.Aborted, "account version is %d, not %d", current.Version, req.GetExpectedVersion())
+	h.logger.WarnContext(ctx, "top up version conflict", "err", err, "user_id", req.GetUserId())
+	return account, err
 }
 
 func (h *Handlers) TopUp(ctx context.Context, req *paymentsv1.TopUpRequest) (resp *paymentsv1.TopUpResponse, err error) {
 	start := time.Now()
-	logger.Info("top up start", "user_id", req.GetUserId(), "amount", req.GetAmount(), "has_idempotency_key", req.GetIdempotencyKey() != "")
+	h.logger.DebugContext(ctx, "top up start", "user_id", req.GetUserId(), "amount", req.GetAmount(), "has_idempotency_key", req.GetIdempotencyKey() != "")
 	defer func() {
 		if err != nil {
-			logger.Error("top up failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "top up failed", "err", err, "duration", time.Since(start))
 			return
 		}
-		logger.Info("top up completed", "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "top up completed", "duration", time.Since(start))
 	}()
 
 	userID := req.GetUserId()
 	if userID == "" {
 		err = status.Error(codes.InvalidArgument, "user_id is required")
-		logger.Error("top up validation failed", "err", err)
+		h.logger.ErrorContext(ctx, "top up validation failed", "err", err)
 		return nil, err
 	}
-	if req.GetAmount() <= 0 {
-		err = status.Error(codes.InvalidArgument, "amount must be > 0")
-		logger.Error("top up validation failed", "err", err)
+	if err = validateAmount(req.GetAmount()); err != nil {
+		h.logger.ErrorContext(ctx, "top up validation failed", "err", err)
+		return nil, err
+	}
+	if err = h.checkCurrency(req.GetCurrency()); err != nil {
+		h.logger.ErrorContext(ctx, "top up validation failed", "err", err)
 		return nil, err
 	}
 
 	idemKey := req.GetIdempotencyKey()
 	if idemKey == "" {
-		account, err := h.repo.Q().TopUp(ctx, db.TopUpParams{
-			UserID:  userID,
-			Balance: req.GetAmount(),
-		})
+		var account db.TopUpRow
+		topUp := func(q db.Querier) (err error) {
+			if err = h.checkVelocity(ctx, q, userID, req.GetAmount()); err != nil {
+				return err
+			}
+			account, err = h.topUp(ctx, q, req)
+			return err
+		}
+		if h.limits.enabled() {
+			err = h.repo.For(userID).InTx(ctx, topUp)
+		} else {
+			err = topUp(h.repo.For(userID).Q())
+		}
 		if err != nil {
+			if _, ok := status.FromError(err); ok {
+				return nil, err
+			}
 			if errors.Is(err, pgx.ErrNoRows) {
 				err = status.Error(codes.NotFound, "account not found")
-				logger.Error("top up account not found", "err", err)
+				h.logger.ErrorContext(ctx, "top up account not found", "err", err)
 				return nil, err
 			}
 			err = status.Error(codes.Internal, "failed to top up")
-			logger.Error("top up failed", "err", err)
+			h.logger.ErrorContext(ctx, "top up failed", "err", err)
 			return nil, err
 		}
 
 		if h.cache != nil {
 			if err := h.cache.Set(ctx, cache.Balance{
-				UserID:  account.UserID,
-				Balance: account.Balance,
+				UserID:       account.UserID,
+				Balance:      account.Balance,
+				Version:      account.Version,
+				AccountType:  account.AccountType,
+				BonusBalance: account.BonusBalance,
 			}); err != nil {
-				logger.Error("cache set failed", "err", err, "user_id", account.UserID)
+				h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", account.UserID)
 			}
 		}
 
 		resp = &paymentsv1.TopUpResponse{
 			Account: &paymentsv1.Account{
-				UserId:  account.UserID,
-				Balance: account.Balance,
+				UserId:         account.UserID,
+				Balance:        account.Balance,
+				Currency:       string(h.currency),
+				Version:        account.Version,
+				OverdraftLimit: account.OverdraftLimit,
+				AccountType:    accountTypeToProto(account.AccountType),
+				BonusBalance:   account.BonusBalance,
 			},
 		}
 		return resp, nil
 	}
 
-	var (
-		balance     int64
-		updateCache bool
-	)
-	err = h.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
-		inserted, err := q.InsertTopupIdempotency(ctx, db.InsertTopupIdempotencyParams{
-			UserID:         userID,
-			IdempotencyKey: idemKey,
-			Amount:         req.GetAmount(),
-		})
-		if err != nil {
-			logger.Error("insert topup idempotency failed", "err", err)
-			return err
-		}
-
-		if inserted == 0 {
-			existing, err := q.GetTopupIdempotency(ctx, db.GetTopupIdempotencyParams{
-				UserID:         userID,
-				IdempotencyKey: idemKey,
-			})
-			if err != nil {
-				logger.Error("get topup idempotency failed", "err", err)
-				return err
+	var replayed bool
+	err = h.repo.For(userID).InTx(ctx, func(q db.Querier) error {
+		resp, replayed, err = idempotency.Do(ctx, repo.IdempotencyStore(q), idempotency.Key{
+			Scope:  "payments.TopUp",
+			UserID: userID,
+			Key:    idemKey,
+		}, req, &paymentsv1.TopUpResponse{}, func() (*paymentsv1.TopUpResponse, error) {
+			if err := h.checkVelocity(ctx, q, userID, req.GetAmount()); err != nil {
+				return nil, err
 			}
-			if existing.Amount != req.GetAmount() {
-				err = status.Error(codes.FailedPrecondition, "idempotency key reuse with different parameters")
-				logger.Error("idempotency key reuse with different parameters", "err", err)
-				return err
+			account, err := h.topUp(ctx, q, req)
+			if err != nil {
+				h.logger.ErrorContext(ctx, "top up failed", "err", err)
+				return nil, err
 			}
-			balance = existing.BalanceAfter
-			return nil
-		}
-
-		account, err := q.TopUp(ctx, db.TopUpParams{
-			UserID:  userID,
-			Balance: req.GetAmount(),
+			return &paymentsv1.TopUpResponse{
+				Account: &paymentsv1.Account{
+					UserId:         account.UserID,
+					Balance:        account.Balance,
+					Currency:       string(h.currency),
+					Version:        account.Version,
+					OverdraftLimit: account.OverdraftLimit,
+					AccountType:    accountTypeToProto(account.AccountType),
+					BonusBalance:   account.BonusBalance,
+				},
+			}, nil
 		})
-		if err != nil {
-			_ = q.DeleteTopupIdempotency(ctx, db.DeleteTopupIdempotencyParams{
-				UserID:         userID,
-				IdempotencyKey: idemKey,
-			})
-			if errors.Is(err, pgx.ErrNoRows) {
-				err = status.Error(codes.NotFound, "account not found")
-				logger.Error("top up account not found", "err", err)
-				return err
-			}
-			logger.Error("top up failed", "err", err)
-			return err
-		}
-
-		if _, err := q.SetTopupIdempotencyBalance(ctx, db.SetTopupIdempotencyBalanceParams{
-			UserID:         userID,
-			IdempotencyKey: idemKey,
-			BalanceAfter:   account.Balance,
-		}); err != nil {
-			logger.Error("set topup idempotency balance failed", "err", err)
-			return err
-		}
-
-		balance = account.Balance
-		updateCache = true
-		return nil
+		return err
 	})
 	if err != nil {
 		if st, ok := status.FromError(err); ok {
@@ -226,74 +314,92 @@ func (h *Handlers) TopUp(ctx context.Context, req *paymentsv1.TopUpRequest) (res
 		return nil, err
 	}
 
-	if updateCache && h.cache != nil {
+	// A replay returns the balance right after the original top-up; the
+	// current balance may differ, so it is not cached.
+	if !replayed && h.cache != nil {
 		if err := h.cache.Set(ctx, cache.Balance{
-			UserID:  userID,
-			Balance: balance,
+			UserID:       userID,
+			Balance:      resp.GetAccount().GetBalance(),
+			Version:      resp.GetAccount().GetVersion(),
+			AccountType:  accountTypeName(resp.GetAccount().GetAccountType()),
+			BonusBalance: resp.GetAccount().GetBonusBalance(),
 		}); err != nil {
-			logger.Error("cache set failed", "err", err, "user_id", userID)
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", userID)
 		}
 	}
-
-	resp = &paymentsv1.TopUpResponse{
-		Account: &paymentsv1.Account{
-			UserId:  userID,
-			Balance: balance,
-		},
-	}
 	return resp, nil
 }
 
 func (h *Handlers) GetBalance(ctx context.Context, req *paymentsv1.GetBalanceRequest) (resp *paymentsv1.GetBalanceResponse, err error) {
 	start := time.Now()
-	logger.Info("get balance start", "user_id", req.GetUserId())
+	h.logger.DebugContext(ctx, "get balance start", "user_id", req.GetUserId())
 	defer func() {
 		if err != nil {
-			logger.Error("get balance failed", "err", err, "duration", time.Since(start))
+			h.logger.ErrorContext(ctx, "get balance failed", "err", err, "duration", time.Since(start))
 			return
 		}
-		logger.Info("get balance completed", "duration", time.Since(start))
+		h.logger.InfoContext(ctx, "get balance completed", "duration", time.Since(start))
 	}()
 
 	userID := req.GetUserId()
 	if userID == "" {
 		err = status.Error(codes.InvalidArgument, "user_id is required")
-		logger.Error("get balance validation failed", "err", err)
+		h.logger.ErrorContext(ctx, "get balance validation failed", "err", err)
 		return nil, err
 	}
 
-	if cached, err := h.cache.Get(ctx, userID); err == nil && cached != nil {
-		logger.Info("get balance cache hit", "user_id", userID)
-		resp = &paymentsv1.GetBalanceResponse{
-			Balance: cached.Balance,
+	if h.cache != nil {
+		// Entries cached before accounts had versions and types are treated
+		// as misses.
+		if cached, err := h.cache.Get(ctx, userID); err == nil && cached != nil && cached.Version > 0 && cached.AccountType != "" {
+			h.logger.DebugContext(ctx, "get balance cache hit", "user_id", userID)
+			resp = &paymentsv1.GetBalanceResponse{
+				Balance:      cached.Balance,
+				Currency:     string(h.currency),
+				Version:      cached.Version,
+				AccountType:  accountTypeToProto(cached.AccountType),
+				BonusBalance: cached.BonusBalance,
+			}
+			return resp, nil
 		}
-		return resp, nil
 	}
-	logger.Info("get balance cache miss", "user_id", userID)
+	h.logger.DebugContext(ctx, "get balance cache miss", "user_id", userID)
 
-	balance, err := h.repo.Q().GetBalance(ctx, userID)
+	var account db.GetBalanceRow
+	err = h.repo.For(userID).Read(ctx, func(q db.Querier) error {
+		var err error
+		account, err = q.GetBalance(ctx, userID)
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			err = status.Error(codes.NotFound, "account not found")
-			logger.Error("get balance account not found", "err", err)
+			h.logger.ErrorContext(ctx, "get balance account not found", "err", err)
 			return nil, err
 		}
 		err = status.Error(codes.Internal, "failed to get balance")
-		logger.Error("get balance failed", "err", err)
+		h.logger.ErrorContext(ctx, "get balance failed", "err", err)
 		return nil, err
 	}
 
 	if h.cache != nil {
 		if err := h.cache.Set(ctx, cache.Balance{
-			UserID:  userID,
-			Balance: balance,
+			UserID:       userID,
+			Balance:      account.Balance,
+			Version:      account.Version,
+			AccountType:  account.AccountType,
+			BonusBalance: account.BonusBalance,
 		}); err != nil {
-			logger.Error("cache set failed", "err", err, "user_id", userID)
+			h.logger.ErrorContext(ctx, "cache set failed", "err", err, "user_id", userID)
 		}
 	}
 
 	resp = &paymentsv1.GetBalanceResponse{
-		Balance: balance,
+		Balance:      account.Balance,
+		Currency:     string(h.currency),
+		Version:      account.Version,
+		AccountType:  accountTypeToProto(account.AccountType),
+		BonusBalance: account.BonusBalance,
 	}
 	return resp, nil
 }
@@ -0,0 +1,445 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/cache"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/challenge"
+	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func wantCode(t *testing.T, err error, code codes.Code) {
+	t.Helper()
+	if got := status.Code(err); got != code {
+		t.Fatalf("error code = %s (%v), want %s", got, err, code)
+	}
+}
+
+func TestCreateAccount(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+
+	resp, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"})
+	if err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	if resp.GetAccount().GetUserId() != "u-1" || resp.GetAccount().GetBalance() != 0 {
+		t.Fatalf("CreateAccount() = %v, want u-1 with zero balance", resp.GetAccount())
+	}
+
+	_, err = h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"})
+	wantCode(t, err, codes.AlreadyExists)
+
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1", IdempotencyKey: "k-1"}); err != nil {
+		t.Fatalf("CreateAccount() with idempotency key error: %v", err)
+	}
+	if len(repo.outbox) != 1 {
+		t.Fatalf("outbox has %d events, want 1 account created", len(repo.outbox))
+	}
+	var ev eventsv1.AccountCreated
+	if err := kafkasvc.UnmarshalEvent(repo.outbox[0].Payload, &ev); err != nil || ev.GetUserId() != "u-1" || repo.outbox[0].Topic != "accounts" {
+		t.Fatalf("outbox event = %v (%v) on %q, want u-1 on accounts", &ev, err, repo.outbox[0].Topic)
+	}
+
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-2", IdempotencyKey: "k-2"}); err != nil {
+		t.Fatalf("CreateAccount() with idempotency key error: %v", err)
+	}
+	if len(repo.outbox) != 2 {
+		t.Fatalf("outbox has %d events, want 2 account created", len(repo.outbox))
+	}
+}
+
+func TestAccountsRoutedToShard(t *testing.T) {
+	shards := &fakeShards{shards: []*fakeRepo{newFakeRepo(), newFakeRepo()}, of: map[string]int{"u-2": 1}}
+	h := NewHandlers(shards, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"u-1", "u-2"} {
+		if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: id}); err != nil {
+			t.Fatalf("CreateAccount(%s) error: %v", id, err)
+		}
+	}
+	if _, ok := shards.shards[1].accounts["u-2"]; !ok || len(shards.shards[1].accounts) != 1 || len(shards.shards[1].outbox) != 1 {
+		t.Fatalf("shard 1 has accounts %v and %d events, want u-2 and its event only", shards.shards[1].accounts, len(shards.shards[1].outbox))
+	}
+	if _, ok := shards.shards[0].accounts["u-2"]; ok {
+		t.Fatal("u-2 also created on shard 0")
+	}
+
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-2", Amount: 100}); err != nil {
+		t.Fatalf("TopUp() error: %v", err)
+	}
+	resp, err := h.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: "u-2"})
+	if err != nil || resp.GetBalance() != 100 {
+		t.Fatalf("GetBalance() = (%v, %v), want 100", resp, err)
+	}
+}
+
+func TestTopUpAndGetBalance(t *testing.T) {
+	h := NewHandlers(newFakeRepo(), nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100})
+	wantCode(t, err, codes.NotFound)
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: "u-1"})
+	wantCode(t, err, codes.NotFound)
+
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"}); err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100}); err != nil {
+		t.Fatalf("TopUp() error: %v", err)
+	}
+	resp, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 50})
+	if err != nil {
+		t.Fatalf("TopUp() error: %v", err)
+	}
+	if resp.GetAccount().GetBalance() != 150 {
+		t.Fatalf("TopUp() balance = %d, want 150", resp.GetAccount().GetBalance())
+	}
+	if resp.GetAccount().GetCurrency() != "RUB" {
+		t.Fatalf("TopUp() currency = %q, want RUB", resp.GetAccount().GetCurrency())
+	}
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 10, Currency: "USD"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: money.MaxAmount + 1})
+	wantCode(t, err, codes.InvalidArgument)
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 50, Currency: "rub"}); err != nil {
+		t.Fatalf("TopUp(rub) error: %v", err)
+	}
+
+	bal, err := h.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: "u-1"})
+	if err != nil {
+		t.Fatalf("GetBalance() error: %v", err)
+	}
+	if bal.GetBalance() != 200 || bal.GetCurrency() != "RUB" {
+		t.Fatalf("GetBalance() = %d %s, want 200 RUB", bal.GetBalance(), bal.GetCurrency())
+	}
+	if bal.GetAccountType() != paymentsv1.AccountType_ACCOUNT_TYPE_BASIC {
+		t.Fatalf("GetBalance() account type = %s, want BASIC", bal.GetAccountType())
+	}
+}
+
+func TestTopUpExpectedVersion(t *testing.T) {
+	repo := newFakeRepo()
+	balances := cache.NewMemoryBalanceCache(10, time.Minute)
+	h := NewHandlers(repo, balances, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 10, ExpectedVersion: 1})
+	wantCode(t, err, codes.NotFound)
+
+	created, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"})
+	if err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	if created.GetAccount().GetVersion() != 1 {
+		t.Fatalf("CreateAccount() version = %d, want 1", created.GetAccount().GetVersion())
+	}
+	resp, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 10, ExpectedVersion: 1})
+	if err != nil {
+		t.Fatalf("TopUp() error: %v", err)
+	}
+	if resp.GetAccount().GetVersion() != 2 {
+		t.Fatalf("TopUp() version = %d, want 2", resp.GetAccount().GetVersion())
+	}
+
+	// A concurrent change moves the account past the version the client read.
+	repo.changes["u-1"]++
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 10, ExpectedVersion: 2})
+	wantCode(t, err, codes.Aborted)
+	if repo.accounts["u-1"] != 10 {
+		t.Fatalf("balance = %d after conflict, want 10", repo.accounts["u-1"])
+	}
+	// The conflict refreshed the cache, so a re-read sees the new version.
+	bal, err := h.GetBalance(ctx, &paymentsv1.GetBalanceRequest{UserId: "u-1"})
+	if err != nil || bal.GetVersion() != 3 {
+		t.Fatalf("GetBalance() = (%v, %v), want version 3", bal, err)
+	}
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 10, ExpectedVersion: bal.GetVersion(), IdempotencyKey: "k-1"}); err != nil {
+		t.Fatalf("TopUp() retry error: %v", err)
+	}
+}
+
+func TestGetBalances(t *testing.T) {
+	shards := &fakeShards{shards: []*fakeRepo{newFakeRepo(), newFakeRepo()}, of: map[string]int{"u-2": 1, "u-3": 1}}
+	balances := cache.NewMemoryBalanceCache(10, time.Minute)
+	h := NewHandlers(shards, balances, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	for _, id := range []string{"u-1", "u-2"} {
+		if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: id}); err != nil {
+			t.Fatalf("CreateAccount(%s) error: %v", id, err)
+		}
+	}
+	// u-3 is not cached yet; u-1's cached balance is served as is.
+	shards.shards[1].accounts["u-3"] = 70
+	shards.shards[0].accounts["u-1"] = 5
+
+	resp, err := h.GetBalances(ctx, &paymentsv1.GetBalancesRequest{UserIds: []string{"u-3", "u-1", "missing", "u-3", "u-2"}})
+	if err != nil {
+		t.Fatalf("GetBalances() error: %v", err)
+	}
+	var got []string
+	for _, b := range resp.GetBalances() {
+		got = append(got, fmt.Sprintf("%s=%d", b.GetUserId(), b.GetBalance()))
+	}
+	if strings.Join(got, ",") != "u-3=70,u-1=0,u-2=0" || strings.Join(resp.GetMissingUserIds(), ",") != "missing" {
+		t.Fatalf("GetBalances() = %v, missing %v; want u-3=70,u-1=0,u-2=0 and missing", got, resp.GetMissingUserIds())
+	}
+	if shards.shards[0].batchReads != 1 || shards.shards[1].batchReads != 1 {
+		t.Fatalf("batch reads = %d, %d; want one per shard with misses", shards.shards[0].batchReads, shards.shards[1].batchReads)
+	}
+
+	// u-3 was cached by the first call; only the unknown account is queried.
+	if _, err := h.GetBalances(ctx, &paymentsv1.GetBalancesRequest{UserIds: []string{"u-3", "missing"}}); err != nil {
+		t.Fatalf("GetBalances() error: %v", err)
+	}
+	if shards.shards[0].batchReads != 2 || shards.shards[1].batchReads != 1 {
+		t.Fatalf("batch reads = %d, %d; want 2, 1", shards.shards[0].batchReads, shards.shards[1].batchReads)
+	}
+
+	_, err = h.GetBalances(ctx, &paymentsv1.GetBalancesRequest{})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.GetBalances(ctx, &paymentsv1.GetBalancesRequest{UserIds: []string{"u-1", ""}})
+	wantCode(t, err, codes.InvalidArgument)
+	tooMany := make([]string, maxBalancesBatch+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("u-%d", i)
+	}
+	_, err = h.GetBalances(ctx, &paymentsv1.GetBalancesRequest{UserIds: tooMany})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1", At: timestamppb.Now()})
+	wantCode(t, err, codes.NotFound)
+
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"}); err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	for _, amount := range []int64{100, 50} {
+		if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: amount}); err != nil {
+			t.Fatalf("TopUp() error: %v", err)
+		}
+	}
+	now := time.Now()
+	repo.created["u-1"] = now.Add(-3 * time.Hour)
+	repo.ledger[0].at = now.Add(-2 * time.Hour)
+	repo.ledger[1].at = now.Add(-time.Hour)
+
+	cases := []struct {
+		at   time.Time
+		want int64
+	}{
+		{now.Add(-150 * time.Minute), 0},
+		{now.Add(-90 * time.Minute), 100},
+		{now, 150},
+	}
+	for _, tc := range cases {
+		resp, err := h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1", At: timestamppb.New(tc.at)})
+		if err != nil {
+			t.Fatalf("GetBalanceAt(%s) error: %v", tc.at, err)
+		}
+		if resp.GetBalance() != tc.want {
+			t.Fatalf("GetBalanceAt(%s) = %d, want %d", tc.at, resp.GetBalance(), tc.want)
+		}
+	}
+
+	_, err = h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1", At: timestamppb.New(now.Add(-4 * time.Hour))})
+	wantCode(t, err, codes.NotFound)
+
+	repo.historyStart = now.Add(-2 * time.Hour)
+	_, err = h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1", At: timestamppb.New(now.Add(-150 * time.Minute))})
+	wantCode(t, err, codes.FailedPrecondition)
+	_, err = h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1", At: timestamppb.New(now.Add(time.Hour))})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.GetBalanceAt(ctx, &paymentsv1.GetBalanceAtRequest{UserId: "u-1"})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestConfirmPayment(t *testing.T) {
+	h := NewHandlers(newFakeRepo(), nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+	orderID := "0b0b64e2-5a55-4d5e-9d1e-3f0cbd7c1a01"
+
+	_, err := h.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.Unimplemented)
+
+	h.UseChallenges(challenge.NewConfirmer(method.NewRegistry(), "results"))
+	_, err = h.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{UserId: "u-1", OrderId: "order-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{UserId: "u-1", OrderId: orderID, PaymentId: "p-1"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{OrderId: orderID})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ConfirmPayment(ctx, &paymentsv1.ConfirmPaymentRequest{UserId: "u-1", OrderId: orderID})
+	wantCode(t, err, codes.NotFound)
+}
+
+func TestTopUpIdempotent(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100, IdempotencyKey: "k-1"})
+	wantCode(t, err, codes.NotFound)
+	if len(repo.idem) != 0 {
+		t.Fatalf("failed top up left %d idempotency rows, want 0", len(repo.idem))
+	}
+
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"}); err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	req := &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100, IdempotencyKey: "k-1"}
+	for i := 0; i < 2; i++ {
+		resp, err := h.TopUp(ctx, req)
+		if err != nil {
+			t.Fatalf("TopUp() attempt %d error: %v", i+1, err)
+		}
+		if resp.GetAccount().GetBalance() != 100 {
+			t.Fatalf("TopUp() attempt %d balance = %d, want 100", i+1, resp.GetAccount().GetBalance())
+		}
+	}
+	if repo.accounts["u-1"] != 100 {
+		t.Fatalf("stored balance = %d, want 100", repo.accounts["u-1"])
+	}
+
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 200, IdempotencyKey: "k-1"})
+	wantCode(t, err, codes.FailedPrecondition)
+}
+
+func TestTopUpVelocityLimits(t *testing.T) {
+	repo := newFakeRepo()
+	repo.accounts["u-1"] = 0
+	repo.accounts["u-2"] = 0
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{MaxPerMinute: 2, MaxAmountPerHour: 1000}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "missing", Amount: 1})
+	wantCode(t, err, codes.NotFound)
+
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100}); err != nil {
+		t.Fatalf("TopUp() #1 error: %v", err)
+	}
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100, IdempotencyKey: "k-1"}); err != nil {
+		t.Fatalf("TopUp() #2 error: %v", err)
+	}
+	// A replay is not a new top-up and must not be rejected.
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100, IdempotencyKey: "k-1"}); err != nil {
+		t.Fatalf("TopUp() replay error: %v", err)
+	}
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100, IdempotencyKey: "k-2"})
+	wantCode(t, err, codes.ResourceExhausted)
+	if repo.accounts["u-1"] != 200 {
+		t.Fatalf("balance = %d, want 200 after rejected top-up", repo.accounts["u-1"])
+	}
+	if _, ok := repo.idem[db.GetIdempotencyKeyParams{Scope: "payments.TopUp", UserID: "u-1", IdempotencyKey: "k-2"}]; ok {
+		t.Fatal("rejected top-up left its idempotency record behind")
+	}
+
+	// Amount limit: 900 fits into 1000, another 200 does not.
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-2", Amount: 900}); err != nil {
+		t.Fatalf("TopUp() u-2 error: %v", err)
+	}
+	_, err = h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-2", Amount: 200})
+	wantCode(t, err, codes.ResourceExhausted)
+
+	// Events older than the windows no longer count.
+	for i := range repo.events {
+		repo.events[i].createdAt = repo.events[i].createdAt.Add(-2 * time.Hour)
+	}
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 100}); err != nil {
+		t.Fatalf("TopUp() after window error: %v", err)
+	}
+	if len(repo.events) != 2 {
+		t.Fatalf("events = %d, want u-1's old events pruned (u-2's 1 + new 1)", len(repo.events))
+	}
+}
+
+func TestListTransactions(t *testing.T) {
+	repo := newFakeRepo()
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, nil)
+	ctx := context.Background()
+
+	_, err := h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1"})
+	wantCode(t, err, codes.NotFound)
+
+	for _, userID := range []string{"u-1", "u-2"} {
+		if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: userID}); err != nil {
+			t.Fatalf("CreateAccount() error: %v", err)
+		}
+	}
+	for _, top := range []struct {
+		userID string
+		amount int64
+	}{{"u-1", 100}, {"u-2", 5}, {"u-1", 50}, {"u-1", 20}} {
+		if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: top.userID, Amount: top.amount}); err != nil {
+			t.Fatalf("TopUp() error: %v", err)
+		}
+	}
+
+	first, err := h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListTransactions() error: %v", err)
+	}
+	if txs := first.GetTransactions(); len(txs) != 2 || txs[0].GetAmount() != 20 || txs[1].GetAmount() != 50 || first.GetNextBeforeId() != txs[1].GetId() {
+		t.Fatalf("ListTransactions() first page = %v, want 20 and 50 with a next page", first)
+	}
+	if kind := first.GetTransactions()[0].GetKind(); kind != paymentsv1.TransactionKind_TRANSACTION_KIND_TOP_UP || first.GetCurrency() != "RUB" {
+		t.Fatalf("ListTransactions() kind = %s, currency = %q; want TOP_UP in RUB", kind, first.GetCurrency())
+	}
+	rest, err := h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", PageSize: 2, BeforeId: first.GetNextBeforeId()})
+	if err != nil {
+		t.Fatalf("ListTransactions() error: %v", err)
+	}
+	if txs := rest.GetTransactions(); len(txs) != 1 || txs[0].GetAmount() != 100 || rest.GetNextBeforeId() != 0 {
+		t.Fatalf("ListTransactions() last page = %v, want only 100", rest)
+	}
+
+	_, err = h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", PageSize: 501})
+	wantCode(t, err, codes.InvalidArgument)
+}
+
+func TestTransactionKind(t *testing.T) {
+	cases := []struct {
+		kind       string
+		hasPayment bool
+		want       paymentsv1.TransactionKind
+	}{
+		{"balance", false, paymentsv1.TransactionKind_TRANSACTION_KIND_TOP_UP},
+		{"balance", true, paymentsv1.TransactionKind_TRANSACTION_KIND_PAYMENT},
+		{"fee", true, paymentsv1.TransactionKind_TRANSACTION_KIND_FEE},
+		{"bonus", false, paymentsv1.TransactionKind_TRANSACTION_KIND_BONUS},
+		{"other", false, paymentsv1.TransactionKind_TRANSACTION_KIND_UNSPECIFIED},
+	}
+	for _, tc := range cases {
+		if got := transactionKind(tc.kind, tc.hasPayment); got != tc.want {
+			t.Fatalf("transactionKind(%q, %v) = %s, want %s", tc.kind, tc.hasPayment, got, tc.want)
+		}
+	}
+}
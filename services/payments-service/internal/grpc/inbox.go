@@ -0,0 +1,113 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	kafkasvc "github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+)
+
+// InboxReplayer runs the stored inbox messages of one consumer again.
+type InboxReplayer interface {
+	Consumer() string
+	DryRun(ctx context.Context, messageID string) (*kafkasvc.InboxDryRun, error)
+}
+
+// UseInbox registers the consumers whose messages DryRunInboxMessage can
+// run again.
+func (h *AdminHandlers) UseInbox(replayers ...InboxReplayer) {
+	if h.inbox == nil {
+		h.inbox = make(map[string]InboxReplayer, len(replayers))
+	}
+	for _, r := range replayers {
+		h.inbox[r.Consumer()] = r
+	}
+}
+
+// DryRunInboxMessage runs a stored inbox message through its consumer
+// without committing anything.
+func (h *AdminHandlers) DryRunInboxMessage(ctx context.Context, req *paymentsv1.DryRunInboxMessageRequest) (resp *paymentsv1.DryRunInboxMessageResponse, err error) {
+	start := time.Now()
+	operator := operator(ctx)
+	h.logger.InfoContext(ctx, "dry run inbox message start", "operator", operator, "consumer", req.GetConsumer(), "message_id", req.GetMessageId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "dry run inbox message failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "dry run inbox message completed", "operator", operator, "consumer", req.GetConsumer(), "message_id", req.GetMessageId(), "skipped", resp.GetSkipped() != "", "failed", resp.GetError() != "", "would_publish", len(resp.GetWouldPublish()), "duration", time.Since(start))
+	}()
+
+	id, err := uuid.Parse(req.GetMessageId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "message_id must be a UUID")
+	}
+	r, ok := h.inbox[req.GetConsumer()]
+	if !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown inbox consumer %q; known: %s", req.GetConsumer(), strings.Join(h.inboxConsumers(), ", "))
+	}
+	dry, err := r.DryRun(ctx, id.String())
+	switch {
+	case errors.Is(err, inbox.ErrNotFound):
+		return nil, status.Error(codes.NotFound, "message not found in the inbox")
+	case errors.Is(err, inbox.ErrPayloadNotStored):
+		return nil, status.Error(codes.FailedPrecondition, "message payload was not stored or has been purged")
+	case err != nil:
+		return nil, status.Error(codes.Internal, "failed to run inbox message")
+	}
+	return inboxDryRunToProto(dry), nil
+}
+
+func (h *AdminHandlers) inboxConsumers() []string {
+	names := make([]string, 0, len(h.inbox))
+	for name := range h.inbox {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func inboxDryRunToProto(dry *kafkasvc.InboxDryRun) *paymentsv1.DryRunInboxMessageResponse {
+	s := dry.Message
+	msg := &paymentsv1.InboxMessage{
+		Consumer:      s.Consumer,
+		MessageId:     s.ID,
+		CorrelationId: s.CorrelationID,
+		Topic:         s.Topic,
+		Partition:     int32(s.Partition),
+		Offset:        s.Offset,
+		Key:           s.Key,
+		Payload:       s.Payload,
+		ReceivedAt:    timestamppb.New(s.ReceivedAt),
+	}
+	for _, hd := range s.Headers {
+		msg.Headers = append(msg.Headers, &paymentsv1.InboxMessageHeader{Key: hd.Key, Value: hd.Value})
+	}
+	if s.ProcessedAt != nil {
+		msg.ProcessedAt = timestamppb.New(*s.ProcessedAt)
+	}
+	resp := &paymentsv1.DryRunInboxMessageResponse{Message: msg, Skipped: dry.Skipped}
+	if dry.Event != nil {
+		if b, err := protojson.Marshal(dry.Event); err == nil {
+			resp.EventJson = string(b)
+		}
+	}
+	if dry.Err != nil {
+		resp.Error = dry.Err.Error()
+	}
+	for _, e := range dry.Outbox {
+		resp.WouldPublish = append(resp.WouldPublish, &paymentsv1.DryRunOutboxEvent{Topic: e.Topic, KafkaKey: e.KafkaKey, Payload: e.Payload})
+	}
+	return resp
+}
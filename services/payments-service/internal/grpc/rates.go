@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rates"
+)
+
+// GetRates lists the price of every known currency in the service currency.
+func (h *Handlers) GetRates(ctx context.Context, _ *paymentsv1.GetRatesRequest) (resp *paymentsv1.GetRatesResponse, err error) {
+	start := time.Now()
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "get rates failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "get rates completed", "count", len(resp.GetRates()), "duration", time.Since(start))
+	}()
+
+	table, err := h.rateTable(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resp = &paymentsv1.GetRatesResponse{Base: string(h.currency), AsOf: timestamppb.New(table.AsOf)}
+	for _, c := range table.Currencies() {
+		rate, err := table.Rate(c, h.currency)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to get rates")
+		}
+		resp.Rates = append(resp.Rates, &paymentsv1.ExchangeRate{Currency: string(c), Rate: rates.FormatRate(rate)})
+	}
+	return resp, nil
+}
+
+// rateTable returns the current rates as a gRPC error when there are none.
+func (h *Handlers) rateTable(ctx context.Context) (rates.Table, error) {
+	if h.rates == nil {
+		return rates.Table{}, status.Error(codes.Unavailable, "exchange rates are not configured")
+	}
+	table, err := h.rates.Table(ctx)
+	if err != nil {
+		h.logger.ErrorContext(ctx, "rates provider failed", "err", err)
+		return rates.Table{}, status.Error(codes.Unavailable, "exchange rates are unavailable")
+	}
+	// Rates against another base still convert, as long as they include
+	// the service currency.
+	if _, err := table.Rate(h.currency, h.currency); err != nil {
+		return rates.Table{}, status.Error(codes.Unavailable, "exchange rates do not cover "+string(h.currency))
+	}
+	return table, nil
+}
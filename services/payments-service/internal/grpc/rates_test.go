@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rates"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestGetRates(t *testing.T) {
+	ctx := context.Background()
+	_, err := NewHandlers(newFakeRepo(), nil, "accounts", TopUpLimits{}, money.RUB, nil).GetRates(ctx, &paymentsv1.GetRatesRequest{})
+	wantCode(t, err, codes.Unavailable)
+
+	static, err := rates.ParseStatic(money.RUB, "USD=90,EUR=97.5")
+	if err != nil {
+		t.Fatalf("ParseStatic() error: %v", err)
+	}
+	resp, err := NewHandlers(newFakeRepo(), nil, "accounts", TopUpLimits{}, money.RUB, static).GetRates(ctx, &paymentsv1.GetRatesRequest{})
+	if err != nil {
+		t.Fatalf("GetRates() error: %v", err)
+	}
+	got := resp.GetRates()
+	if resp.GetBase() != "RUB" || len(got) != 2 || got[0].GetCurrency() != "EUR" || got[0].GetRate() != "97.5" || got[1].GetCurrency() != "USD" || got[1].GetRate() != "90" {
+		t.Fatalf("GetRates() = %v, want EUR 97.5 and USD 90 in RUB", resp)
+	}
+}
+
+func TestListTransactionsDisplayCurrency(t *testing.T) {
+	static, err := rates.ParseStatic(money.RUB, "USD=100")
+	if err != nil {
+		t.Fatalf("ParseStatic() error: %v", err)
+	}
+	repo := newFakeRepo()
+	now := time.Now()
+	repo.rates = []db.RecordExchangeRateParams{{Currency: "USD", AsOf: pgtype.Timestamptz{Time: now.Add(-2 * time.Hour), Valid: true}, Price: "80"}}
+	h := NewHandlers(repo, nil, "accounts", TopUpLimits{}, money.RUB, static)
+	ctx := context.Background()
+	if _, err := h.CreateAccount(ctx, &paymentsv1.CreateAccountRequest{UserId: "u-1"}); err != nil {
+		t.Fatalf("CreateAccount() error: %v", err)
+	}
+	if _, err := h.TopUp(ctx, &paymentsv1.TopUpRequest{UserId: "u-1", Amount: 20000}); err != nil {
+		t.Fatalf("TopUp() error: %v", err)
+	}
+	// An entry from before the first recorded rate.
+	repo.ledger = append([]fakeLedgerEntry{{userID: "u-1", delta: 5000, at: now.Add(-3 * time.Hour)}}, repo.ledger...)
+
+	resp, err := h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", DisplayCurrency: "USD"})
+	if err != nil {
+		t.Fatalf("ListTransactions() error: %v", err)
+	}
+	txs := resp.GetTransactions()
+	if len(txs) != 2 || txs[0].GetAmount() != 20000 || txs[0].GetDisplayAmount() != 250 || txs[0].GetDisplayRate() != "0.0125" {
+		t.Fatalf("ListTransactions() = %v, want 200.00 RUB shown as 2.50 USD at the recorded 0.0125", resp)
+	}
+	if txs[1].GetDisplayAmount() != 0 || txs[1].GetDisplayRate() != "" {
+		t.Fatalf("entry before the recorded rates = %v, want it unconverted", txs[1])
+	}
+	if resp.GetDisplayCurrency() != "USD" || resp.GetDisplayRate() != "0.01" {
+		t.Fatalf("display currency = %q at %q, want USD at the current 0.01", resp.GetDisplayCurrency(), resp.GetDisplayRate())
+	}
+
+	_, err = h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", DisplayCurrency: "XXX"})
+	wantCode(t, err, codes.InvalidArgument)
+	_, err = h.ListTransactions(ctx, &paymentsv1.ListTransactionsRequest{UserId: "u-1", DisplayCurrency: "EUR"})
+	wantCode(t, err, codes.InvalidArgument)
+}
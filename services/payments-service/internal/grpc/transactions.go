@@ -0,0 +1,150 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/rates"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// Page size bounds of ListTransactions.
+const (
+	defaultTransactionsPageSize = 50
+	maxTransactionsPageSize     = 500
+)
+
+// ListTransactions pages through the account's ledger, newest entry first.
+// With a display currency every entry is converted at the rate recorded at
+// its time, not today's.
+func (h *Handlers) ListTransactions(ctx context.Context, req *paymentsv1.ListTransactionsRequest) (resp *paymentsv1.ListTransactionsResponse, err error) {
+	start := time.Now()
+	h.logger.DebugContext(ctx, "list transactions start", "user_id", req.GetUserId(), "page_size", req.GetPageSize(), "before_id", req.GetBeforeId())
+	defer func() {
+		if err != nil {
+			h.logger.ErrorContext(ctx, "list transactions failed", "err", err, "duration", time.Since(start))
+			return
+		}
+		h.logger.InfoContext(ctx, "list transactions completed", "count", len(resp.GetTransactions()), "duration", time.Since(start))
+	}()
+
+	userID := req.GetUserId()
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+	pageSize := req.GetPageSize()
+	if pageSize < 0 || pageSize > maxTransactionsPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page_size must be 0..%d", maxTransactionsPageSize)
+	}
+	if pageSize == 0 {
+		pageSize = defaultTransactionsPageSize
+	}
+	if req.GetBeforeId() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "before_id must not be negative")
+	}
+	var display money.Currency
+	if code := req.GetDisplayCurrency(); code != "" {
+		if display, err = money.ParseCurrency(code); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "display_currency %q is not supported", code)
+		}
+	}
+	var current *big.Rat
+	if display != "" {
+		table, err := h.rateTable(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if current, err = table.Rate(h.currency, display); err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "no exchange rate for %s", display)
+		}
+	}
+
+	var rows []db.ListTransactionsRow
+	var history rates.History
+	err = h.repo.For(userID).Read(ctx, func(q db.Querier) error {
+		if _, err := q.GetAccountCreatedAt(ctx, userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return status.Error(codes.NotFound, "account not found")
+			}
+			return err
+		}
+		var err error
+		rows, err = q.ListTransactions(ctx, db.ListTransactionsParams{
+			UserID:   userID,
+			BeforeID: req.GetBeforeId(),
+			PageSize: pageSize,
+		})
+		if err != nil || display == "" || len(rows) == 0 {
+			return err
+		}
+		// Rows are newest first.
+		history, err = rates.LoadHistory(ctx, q, h.currency, display, rows[len(rows)-1].CreatedAt.Time, rows[0].CreatedAt.Time)
+		return err
+	})
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		return nil, status.Error(codes.Internal, "failed to list transactions")
+	}
+
+	resp = &paymentsv1.ListTransactionsResponse{
+		Transactions: make([]*paymentsv1.Transaction, 0, len(rows)),
+		Currency:     string(h.currency),
+	}
+	if display != "" {
+		resp.DisplayCurrency, resp.DisplayRate = string(display), rates.FormatRate(current)
+	}
+	for _, r := range rows {
+		tx := &paymentsv1.Transaction{
+			Id:        r.ID,
+			Amount:    r.Delta,
+			Kind:      transactionKind(r.Kind, r.PaymentID.Valid),
+			PaymentId: r.PaymentID.String(),
+			OrderId:   r.OrderID.String(),
+			CreatedAt: timestamppb.New(r.CreatedAt.Time),
+		}
+		// Entries older than the recorded rates are not converted.
+		if at, ok := history.At(r.CreatedAt.Time); display != "" && ok {
+			amount, rate, err := at.Convert(r.Delta, h.currency, display)
+			if err != nil {
+				return nil, status.Error(codes.Internal, "failed to convert transactions")
+			}
+			tx.DisplayAmount, tx.DisplayRate = amount, rates.FormatRate(rate)
+		}
+		resp.Transactions = append(resp.Transactions, tx)
+	}
+	if len(rows) == int(pageSize) {
+		resp.NextBeforeId = rows[len(rows)-1].ID
+	}
+	return resp, nil
+}
+
+// transactionKind maps a ledger kind to the API one; plain balance entries
+// are payments when they carry a payment id and credits otherwise.
+func transactionKind(kind string, hasPayment bool) paymentsv1.TransactionKind {
+	switch kind {
+	case "fee":
+		return paymentsv1.TransactionKind_TRANSACTION_KIND_FEE
+	case "bonus":
+		return paymentsv1.TransactionKind_TRANSACTION_KIND_BONUS
+	case "adjustment":
+		return paymentsv1.TransactionKind_TRANSACTION_KIND_ADJUSTMENT
+	case "balance":
+		if hasPayment {
+			return paymentsv1.TransactionKind_TRANSACTION_KIND_PAYMENT
+		}
+		return paymentsv1.TransactionKind_TRANSACTION_KIND_TOP_UP
+	default:
+		return paymentsv1.TransactionKind_TRANSACTION_KIND_UNSPECIFIED
+	}
+}
@@ -0,0 +1,64 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// TopUpLimits caps how fast one user can top up. Zero disables a limit.
+type TopUpLimits struct {
+	MaxPerMinute     int
+	MaxAmountPerHour int64
+}
+
+func (l TopUpLimits) enabled() bool {
+	return l.MaxPerMinute > 0 || l.MaxAmountPerHour > 0
+}
+
+// checkVelocity records a top-up of amount for userID or rejects it with
+// ResourceExhausted. It locks the account row first, so concurrent top-ups of
+// the same user are counted one after another; it must run in the same
+// transaction as the balance update.
+func (h *Handlers) checkVelocity(ctx context.Context, q db.Querier, userID string, amount int64) error {
+	if !h.limits.enabled() {
+		return nil
+	}
+	if _, err := q.LockAccount(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return status.Error(codes.NotFound, "account not found")
+		}
+		return err
+	}
+
+	now := time.Now()
+	hourAgo := pgtype.Timestamptz{Time: now.Add(-time.Hour), Valid: true}
+	v, err := q.TopupVelocity(ctx, db.TopupVelocityParams{
+		UserID:      userID,
+		CountSince:  pgtype.Timestamptz{Time: now.Add(-time.Minute), Valid: true},
+		AmountSince: hourAgo,
+	})
+	if err != nil {
+		return err
+	}
+	if h.limits.MaxPerMinute > 0 && v.RecentCount >= int64(h.limits.MaxPerMinute) {
+		h.logger.WarnContext(ctx, "top up rate limit exceeded", "user_id", userID, "recent_count", v.RecentCount, "limit", h.limits.MaxPerMinute)
+		return status.Error(codes.ResourceExhausted, "too many top-ups, try again later")
+	}
+	if h.limits.MaxAmountPerHour > 0 && v.RecentAmount+amount > h.limits.MaxAmountPerHour {
+		h.logger.WarnContext(ctx, "top up amount limit exceeded", "user_id", userID, "recent_amount", v.RecentAmount, "amount", amount, "limit", h.limits.MaxAmountPerHour)
+		return status.Error(codes.ResourceExhausted, "hourly top-up amount limit exceeded")
+	}
+
+	if err := q.InsertTopupEvent(ctx, db.InsertTopupEventParams{UserID: userID, Amount: amount}); err != nil {
+		return err
+	}
+	return q.DeleteTopupEventsBefore(ctx, db.DeleteTopupEventsBeforeParams{UserID: userID, CreatedAt: hourAgo})
+}
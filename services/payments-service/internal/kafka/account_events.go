@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+// EnqueueAccountCreated writes an AccountCreated event to the outbox. Call it
+// in the transaction that created the account so the event is published iff
+// the account exists.
+func EnqueueAccountCreated(ctx context.Context, q db.Querier, topic, userID string) error {
+	logger := slog.Default().With("service", "payments-service", "component", "kafka")
+	payload, err := MarshalEvent(&eventsv1.AccountCreated{
+		EventId:    uuid.NewString(),
+		OccurredAt: timestamppb.Now(),
+		UserId:     userID,
+	})
+	if err != nil {
+		logger.Error("account created marshal failed", "err", err, "user_id", userID)
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         topic,
+		KafkaKey:      userID,
+		Payload:       payload,
+		CorrelationID: logging.RequestID(ctx),
+	}); err != nil {
+		logger.Error("account created outbox insert failed", "err", err, "user_id", userID)
+		return err
+	}
+	return nil
+}
+
+// EnqueuePaymentResult writes r to the outbox, keyed by its order. Call it in
+// the transaction that settled the payment.
+func EnqueuePaymentResult(ctx context.Context, q db.Querier, topic string, r *eventsv1.PaymentResult) error {
+	logger := slog.Default().With("service", "payments-service", "component", "kafka")
+	payload, err := MarshalEvent(r)
+	if err != nil {
+		logger.ErrorContext(ctx, "payment result marshal failed", "err", err, "order_id", r.GetOrderId())
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         topic,
+		KafkaKey:      r.GetOrderId(),
+		Payload:       payload,
+		CorrelationID: r.GetCorrelationId(),
+	}); err != nil {
+		logger.ErrorContext(ctx, "payment result outbox insert failed", "err", err, "order_id", r.GetOrderId())
+		return err
+	}
+	return nil
+}
+
+// EnqueuePaymentChallengeRequired writes ev to the outbox, keyed by its
+// order. Call it in the transaction that recorded the challenge.
+func EnqueuePaymentChallengeRequired(ctx context.Context, q db.Querier, topic string, ev *eventsv1.PaymentChallengeRequired) error {
+	logger := slog.Default().With("service", "payments-service", "component", "kafka")
+	payload, err := MarshalEvent(ev)
+	if err != nil {
+		logger.ErrorContext(ctx, "payment challenge marshal failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         topic,
+		KafkaKey:      ev.GetOrderId(),
+		Payload:       payload,
+		CorrelationID: ev.GetCorrelationId(),
+	}); err != nil {
+		logger.ErrorContext(ctx, "payment challenge outbox insert failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	return nil
+}
+
+// EnqueuePaymentDisputeChanged writes ev to the outbox, keyed by its order.
+// Call it in the transaction that opened or resolved the dispute.
+func EnqueuePaymentDisputeChanged(ctx context.Context, q db.Querier, topic string, ev *eventsv1.PaymentDisputeChanged) error {
+	logger := slog.Default().With("service", "payments-service", "component", "kafka")
+	payload, err := MarshalEvent(ev)
+	if err != nil {
+		logger.ErrorContext(ctx, "payment dispute marshal failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
+		Topic:         topic,
+		KafkaKey:      ev.GetOrderId(),
+		Payload:       payload,
+		CorrelationID: ev.GetCorrelationId(),
+	}); err != nil {
+		logger.ErrorContext(ctx, "payment dispute outbox insert failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	return nil
+}
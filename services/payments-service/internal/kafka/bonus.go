@@ -0,0 +1,40 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// planBonus takes from the grants, in the order given, until amount is
+// covered. It returns the total taken and the per-grant spends.
+func planBonus(grants []db.LockActiveBonusGrantsRow, amount int64) (int64, []method.BonusSpend) {
+	var (
+		total  int64
+		spends []method.BonusSpend
+	)
+	for _, g := range grants {
+		if total == amount {
+			break
+		}
+		take := min(g.Remaining, amount-total)
+		if take <= 0 {
+			continue
+		}
+		spends = append(spends, method.BonusSpend{GrantID: g.ID, Amount: take})
+		total += take
+	}
+	return total, spends
+}
+
+// spendBonus writes the spends of planBonus back to the grants, which the
+// caller locked with LockActiveBonusGrants in the same transaction.
+func spendBonus(ctx context.Context, q *db.Queries, spends []method.BonusSpend) error {
+	for _, s := range spends {
+		if err := q.SpendBonusGrant(ctx, db.SpendBonusGrantParams{ID: s.GrantID, Spent: s.Amount}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
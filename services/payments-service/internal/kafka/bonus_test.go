@@ -0,0 +1,32 @@
+package kafka
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+func TestPlanBonus(t *testing.T) {
+	grants := []db.LockActiveBonusGrantsRow{{ID: 1, Remaining: 30}, {ID: 2, Remaining: 50}}
+	tests := []struct {
+		name   string
+		amount int64
+		total  int64
+		spends []method.BonusSpend
+	}{
+		{"first grant covers it", 20, 20, []method.BonusSpend{{GrantID: 1, Amount: 20}}},
+		{"spills into the second", 60, 60, []method.BonusSpend{{GrantID: 1, Amount: 30}, {GrantID: 2, Amount: 30}}},
+		{"bonus runs out", 100, 80, []method.BonusSpend{{GrantID: 1, Amount: 30}, {GrantID: 2, Amount: 50}}},
+	}
+	for _, tt := range tests {
+		total, spends := planBonus(grants, tt.amount)
+		if total != tt.total || !reflect.DeepEqual(spends, tt.spends) {
+			t.Errorf("%s: planBonus(%d) = %d, %v; want %d, %v", tt.name, tt.amount, total, spends, tt.total, tt.spends)
+		}
+	}
+	if total, spends := planBonus(nil, 100); total != 0 || spends != nil {
+		t.Errorf("planBonus(no grants) = %d, %v; want nothing", total, spends)
+	}
+}
@@ -0,0 +1,148 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+
+	"github.com/ilyaytrewq/payments-service/pkg/kafkatx"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	p := RetryPolicy{Backoff: time.Second, MaxBackoff: 5 * time.Second}
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	for i, w := range want {
+		if got := p.Delay(i + 1); got != w {
+			t.Fatalf("Delay(%d) = %s, want %s", i+1, got, w)
+		}
+	}
+}
+
+func TestOutboxBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 3, Backoff: time.Second}
+	if delay, dead := outboxBackoff(policy, 2); dead || delay != 2*time.Second {
+		t.Fatalf("attempt 2: delay = %s, dead = %v; want 2s, false", delay, dead)
+	}
+	if _, dead := outboxBackoff(policy, 3); !dead {
+		t.Fatal("attempt 3 of 3 is not dead-lettered")
+	}
+	if _, dead := outboxBackoff(RetryPolicy{}, 100); dead {
+		t.Fatal("MaxAttempts 0 dead-lettered a row")
+	}
+}
+
+// execLog records the outbox updates as "<query name> <row id>".
+type execLog struct {
+	calls []string
+	fail  error
+}
+
+func (l *execLog) Exec(_ context.Context, sql string, args ...any) (pgconn.CommandTag, error) {
+	if l.fail != nil {
+		return pgconn.CommandTag{}, l.fail
+	}
+	name := strings.Fields(strings.TrimPrefix(sql, "-- name: "))[0]
+	for _, a := range args {
+		if id, ok := a.(int64); ok {
+			l.calls = append(l.calls, fmt.Sprint(name, " ", id))
+		}
+	}
+	return pgconn.CommandTag{}, nil
+}
+
+func (l *execLog) Query(context.Context, string, ...any) (pgx.Rows, error) {
+	return nil, errors.New("unexpected query")
+}
+
+func (l *execLog) QueryRow(context.Context, string, ...any) pgx.Row {
+	return nil
+}
+
+// fakeTxProducer fails the messages whose keys are in failKeys and counts
+// the batches it committed.
+type fakeTxProducer struct {
+	failKeys  map[string]bool
+	committed int
+	closed    bool
+}
+
+func (f *fakeTxProducer) Publish(_ context.Context, msgs []kafkatx.Message, commit func() error) ([]error, error) {
+	var errs []error
+	for i, m := range msgs {
+		if f.failKeys[string(m.Key)] {
+			if errs == nil {
+				errs = make([]error, len(msgs))
+			}
+			errs[i] = errors.New("broker unavailable")
+		}
+	}
+	if errs != nil {
+		return errs, nil
+	}
+	if err := commit(); err != nil {
+		return nil, err
+	}
+	f.committed++
+	return nil, nil
+}
+
+func (f *fakeTxProducer) Close() {
+	f.closed = true
+}
+
+func TestPublishTx(t *testing.T) {
+	producer := &fakeTxProducer{failKeys: map[string]bool{}}
+	opened := 0
+	p := NewOutboxPublisher(postgres.NewRepo(0, nil, nil, pgtx.Config{}), nil, time.Second, 10, 1, false, RetryPolicy{MaxAttempts: 3, Backoff: time.Second})
+	p.UseTransactions(func(int) (TxProducer, error) {
+		opened++
+		return producer, nil
+	})
+	rows := []db.LockUnsentOutboxRow{
+		{ID: 1, Topic: "t", KafkaKey: "a"},
+		{ID: 2, Topic: "t", KafkaKey: "b", Attempts: 2},
+		{ID: 3, Topic: "t", KafkaKey: "c"},
+	}
+
+	log := &execLog{}
+	if sent, err := p.publishTx(context.Background(), db.New(log), 0, rows); err != nil || sent != 3 {
+		t.Fatalf("publishTx() = (%d, %v), want (3, nil)", sent, err)
+	}
+	if got := strings.Join(log.calls, ","); got != "MarkOutboxSent 1,MarkOutboxSent 2,MarkOutboxSent 3" || producer.committed != 1 {
+		t.Fatalf("committed batch: updates %q, %d commits", got, producer.committed)
+	}
+
+	// One failed message aborts the batch: it alone backs off, here into
+	// the dead letter, and nothing is marked sent.
+	producer.failKeys["b"] = true
+	log = &execLog{}
+	if sent, err := p.publishTx(context.Background(), db.New(log), 0, rows); err != nil || sent != 0 {
+		t.Fatalf("aborted publishTx() = (%d, %v), want (0, nil)", sent, err)
+	}
+	if got := strings.Join(log.calls, ","); got != "MarkOutboxDead 2" {
+		t.Fatalf("aborted batch updates = %q, want only the dead letter of row 2", got)
+	}
+
+	// Failing to mark the rows fails the publish and replaces the producer.
+	delete(producer.failKeys, "b")
+	log = &execLog{fail: errors.New("connection reset")}
+	if _, err := p.publishTx(context.Background(), db.New(log), 0, rows); err == nil {
+		t.Fatal("publishTx() with failing updates succeeded")
+	}
+	if !producer.closed || producer.committed != 1 {
+		t.Fatalf("producer closed = %v, commits = %d; want closed after 1 commit", producer.closed, producer.committed)
+	}
+	if _, err := p.publishTx(context.Background(), db.New(&execLog{}), 0, rows); err != nil || opened != 2 {
+		t.Fatalf("publishTx() after failure = %v with %d producers opened, want a second producer", err, opened)
+	}
+}
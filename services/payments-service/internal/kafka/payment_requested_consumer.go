@@ -2,34 +2,144 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/fees"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/fraud"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/policy"
 	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres"
 	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+
+	"github.com/ilyaytrewq/payments-service/pkg/events"
+	"github.com/ilyaytrewq/payments-service/pkg/inbox"
+	"github.com/ilyaytrewq/payments-service/pkg/kafkaio"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/txoffset"
 )
 
+// paymentRequestedInbox is the inbox consumer name of payment requests.
+const paymentRequestedInbox = "payment_requested"
+
 type PaymentRequestedConsumer struct {
-	repo        *postgres.Repo
-	reader      *kafka.Reader
-	resultTopic string
+	shards       *postgres.Shards
+	reader       *kafka.Reader
+	resultTopic  string
+	accountTopic string
+	autoCreate   bool
+	txOffsets    bool
+	fraud        fraud.Checker
+	policies     policy.Policies
+	fees         fees.Schedule
+	methods      *method.Registry
+	inbox        *inbox.Inbox
+	deductTx     pgx.TxOptions
+
+	// challengeTopic and challengeTTL are where PaymentChallengeRequired
+	// goes and how long the payer has to confirm.
+	challengeTopic string
+	challengeTTL   time.Duration
+
+	handlerTimeout time.Duration
+}
+
+// NewPaymentRequestedConsumer builds the consumer. With autoCreate set, a
+// payment for a user without an account creates a zero-balance account and
+// fails with not enough funds instead of no account. With txOffsets set,
+// processed offsets are also recorded in the payments DB and replayed
+// messages are skipped. checker screens every new payment before the
+// deduction; nil means fraud.AllowAll. policies give the payment limit of
+// each account type and schedule the fee charged on top of each payment.
+// deductTx sets the isolation level of the transaction applying a message;
+// with SERIALIZABLE, serialization failures are retried by the repository.
+// handlerTimeout bounds the processing of each message; zero leaves it
+// unbounded. Each message is applied on the shard of its user.
+func NewPaymentRequestedConsumer(shards *postgres.Shards, r *kafka.Reader, resultTopic, accountTopic string, autoCreate, txOffsets bool, checker fraud.Checker, policies policy.Policies, schedule fees.Schedule, deductTx pgx.TxOptions, handlerTimeout time.Duration) *PaymentRequestedConsumer {
+	if checker == nil {
+		checker = fraud.AllowAll{}
+	}
+	slog.Default().With("service", "payments-service", "component", "kafka").Info("payment requested consumer initialized", "result_topic", resultTopic, "auto_create_accounts", autoCreate, "tx_offsets", txOffsets, "deduct_isolation", string(deductTx.IsoLevel))
+	return &PaymentRequestedConsumer{shards: shards, reader: r, resultTopic: resultTopic, accountTopic: accountTopic, autoCreate: autoCreate, txOffsets: txOffsets, fraud: checker, policies: policies, fees: schedule, methods: method.NewRegistry(), inbox: inbox.New(paymentRequestedInbox), deductTx: deductTx, handlerTimeout: handlerTimeout}
+}
+
+// UseMethods routes payments to the providers of r by their payment_method;
+// until then only the balance is supported.
+func (c *PaymentRequestedConsumer) UseMethods(r *method.Registry) {
+	c.methods = r
+}
+
+// UseChallenges lets payment methods ask the payer to confirm a payment:
+// the payment is held for ttl and PaymentChallengeRequired is written to
+// topic. Until then such payments are declined.
+func (c *PaymentRequestedConsumer) UseChallenges(topic string, ttl time.Duration) {
+	c.challengeTopic, c.challengeTTL = topic, ttl
+}
+
+// StorePayloads makes the inbox keep every message, so DryRun can run it
+// again later.
+func (c *PaymentRequestedConsumer) StorePayloads(on bool) {
+	c.inbox.StorePayloads(on)
 }
 
-func NewPaymentRequestedConsumer(repo *postgres.Repo, r *kafka.Reader, resultTopic string) *PaymentRequestedConsumer {
-	slog.Default().With("service", "payments-service", "component", "kafka").Info("payment requested consumer initialized", "result_topic", resultTopic)
-	return &PaymentRequestedConsumer{repo: repo, reader: r, resultTopic: resultTopic}
+// Consumer returns the inbox consumer name of payment requests.
+func (c *PaymentRequestedConsumer) Consumer() string {
+	return c.inbox.Consumer()
+}
+
+// InboxDryRun is what the consumer did with a stored inbox message when it
+// was run again without committing anything.
+type InboxDryRun = inbox.DryRun[db.ListTxOutboxRow]
+
+// DryRun runs the stored inbox message messageID through the consumer again,
+// whether or not it was processed, and rolls back its effects. The message
+// is looked up on every shard; the inbox and the stored offsets are left
+// alone.
+func (c *PaymentRequestedConsumer) DryRun(ctx context.Context, messageID string) (*InboxDryRun, error) {
+	var (
+		stored inbox.Stored
+		err    error
+	)
+	for _, r := range c.shards.Repos() {
+		stored, err = inbox.Load(ctx, r.Pool(), c.inbox.Consumer(), messageID)
+		if !errors.Is(err, inbox.ErrNotFound) {
+			break
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	m, err := inbox.KafkaMessage(stored)
+	if err != nil {
+		return nil, err
+	}
+	dry := &InboxDryRun{Message: stored}
+	dry.Err = c.handle(ctx, m, dry)
+	return dry, nil
 }
 
 func (c *PaymentRequestedConsumer) Run(ctx context.Context) error {
 	logger := slog.Default().With("service", "payments-service", "component", "kafka")
 	logger.Info("payment requested consumer run start")
+	if c.txOffsets {
+		var dbs []txoffset.Querier
+		for _, repo := range c.shards.Repos() {
+			dbs = append(dbs, repo.Pool())
+		}
+		if err := txoffset.Seek(ctx, c.reader, dbs, logger); err != nil {
+			// Not fatal: the inbox still deduplicates whatever gets redelivered.
+			logger.Error("payment requested seek stored offsets failed", "err", err)
+		}
+	}
 	for {
 		m, err := c.reader.FetchMessage(ctx)
 		if err != nil {
@@ -41,7 +151,16 @@ func (c *PaymentRequestedConsumer) Run(ctx context.Context) error {
 			return err
 		}
 
-		if err := c.handleMessage(ctx, m); err != nil {
+		hctx, cancel := kafkaio.WithHandlerTimeout(ctx, c.handlerTimeout)
+		err = c.handleMessage(hctx, m)
+		cancel()
+		if err != nil {
+			if errors.Is(err, events.ErrNewerVersion) {
+				// Committing a later offset would skip the event for good;
+				// stop until a build that reads its version is deployed.
+				logger.Error("payment requested version not supported, stopping", "err", err, "offset", m.Offset)
+				return err
+			}
 			logger.Error("payment requested handle error", "err", err, "offset", m.Offset)
 			// offset НЕ коммитим => Kafka доставит снова
 			continue
@@ -51,71 +170,183 @@ func (c *PaymentRequestedConsumer) Run(ctx context.Context) error {
 			logger.Error("payment requested commit failed", "err", err, "offset", m.Offset)
 			return err
 		}
-		logger.Info("payment requested message committed", "offset", m.Offset)
+		logger.Debug("payment requested message committed", "offset", m.Offset)
 	}
 }
 
 func (c *PaymentRequestedConsumer) handleMessage(ctx context.Context, m kafka.Message) error {
+	return c.handle(ctx, m, nil)
+}
+
+// handle applies m. With dry set, nothing is committed and dry collects
+// what would have happened.
+func (c *PaymentRequestedConsumer) handle(ctx context.Context, m kafka.Message, dry *InboxDryRun) error {
 	logger := slog.Default().With("service", "payments-service", "component", "kafka")
-	logger.Info("payment requested handle message start", "offset", m.Offset)
+	if dry != nil {
+		logger = logger.With("dry_run", true)
+	}
+	logger.Debug("payment requested handle message start", "offset", m.Offset)
 	var ev eventsv1.PaymentRequested
-	if err := proto.Unmarshal(m.Value, &ev); err != nil {
+	if err := UnmarshalEvent(m.Value, &ev); err != nil {
+		if errors.Is(err, events.ErrNewerVersion) {
+			return err
+		}
 		// плохое сообщение лучше “проглотить” и закоммитить
 		logger.Error("payment requested unmarshal failed", "err", err, "offset", m.Offset)
+		dry.Skip("unmarshal: " + err.Error())
 		return nil
 	}
+	if dry != nil {
+		dry.Event = &ev
+	}
+
+	// The correlation id is the request id of the API call that started the
+	// payment, so the lines below are found together with that request's.
+	ctx = logging.WithRequestID(ctx, ev.GetCorrelationId())
+	ctx = logging.WithOrderID(logging.WithUserID(ctx, ev.GetUserId()), ev.GetOrderId())
 
 	msgID, err := uuid.Parse(ev.GetEventId())
 	if err != nil {
-		logger.Error("payment requested invalid event id", "err", err, "event_id", ev.GetEventId())
+		logger.ErrorContext(ctx, "payment requested invalid event id", "err", err, "event_id", ev.GetEventId())
+		dry.Skip("invalid event id: " + err.Error())
 		return nil
 	}
 
 	orderID, err := uuid.Parse(ev.GetOrderId())
 	if err != nil {
-		logger.Error("payment requested invalid order id", "err", err, "order_id", ev.GetOrderId())
+		logger.ErrorContext(ctx, "payment requested invalid order id", "err", err, "order_id", ev.GetOrderId())
+		dry.Skip("invalid order id: " + err.Error())
 		return nil
 	}
 
+	// The order's initial full payment carries no payment_id and is keyed by order_id.
+	paymentID := orderID
+	if ev.GetPaymentId() != "" {
+		paymentID, err = uuid.Parse(ev.GetPaymentId())
+		if err != nil {
+			logger.ErrorContext(ctx, "payment requested invalid payment id", "err", err, "payment_id", ev.GetPaymentId())
+			dry.Skip("invalid payment id: " + err.Error())
+			return nil
+		}
+	}
+
 	if ev.GetUserId() == "" || ev.GetAmount() <= 0 {
-		logger.Error("payment requested invalid payload", "user_id", ev.GetUserId(), "amount", ev.GetAmount())
+		logger.ErrorContext(ctx, "payment requested invalid payload", "user_id", ev.GetUserId(), "amount", ev.GetAmount())
+		dry.Skip("invalid payload: no user id or a non-positive amount")
 		return nil
 	}
 
-	err = c.repo.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
-		inserted, err := q.InsertInboxCheck(ctx, db.InsertInboxCheckParams{
-			MessageID: pgtype.UUID{Bytes: msgID, Valid: true},
-			OrderID:   pgtype.UUID{Bytes: orderID, Valid: true},
+	process := func(q *db.Queries) error {
+		provider, err := c.methods.Lookup(ev.GetPaymentMethod())
+		if err != nil {
+			logger.WarnContext(ctx, "payment requested unsupported method", "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod())
+			return c.enqueueResult(ctx, q, &ev, orderID, eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED, err.Error(), 0)
+		}
+
+		verdict, err := c.fraud.Check(ctx, q, fraud.Payment{
+			UserID:    ev.GetUserId(),
+			OrderID:   orderID.String(),
+			PaymentID: paymentID.String(),
+			Amount:    ev.GetAmount(),
 		})
 		if err != nil {
-			logger.Error("payment requested inbox insert failed", "err", err)
+			logger.ErrorContext(ctx, "payment requested fraud check failed", "err", err, "order_id", ev.GetOrderId())
 			return err
 		}
-		if inserted == 0 {
-			logger.Info("payment requested already processed", "event_id", ev.GetEventId())
-			return nil
+		if !verdict.Allow {
+			logger.WarnContext(ctx, "payment declined by fraud screening", "order_id", ev.GetOrderId(), "user_id", ev.GetUserId(), "amount", ev.GetAmount(), "reason", verdict.Reason)
+			return c.enqueueResult(ctx, q, &ev, orderID, eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_FRAUD_SUSPECTED, verdict.Reason, 0)
 		}
 
-		res, err := q.TryDeductOnce(ctx, db.TryDeductOnceParams{
-			OrderID: pgtype.UUID{Bytes: orderID, Valid: true},
-			UserID:  ev.GetUserId(),
-			Balance: ev.GetAmount(),
-		})
+		// A missing account gets the zero policy; the deduction below then
+		// reports it as usual.
+		accountType, err := q.GetAccountType(ctx, ev.GetUserId())
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.ErrorContext(ctx, "payment requested account type lookup failed", "err", err, "user_id", ev.GetUserId())
+			return err
+		}
+		pol := c.policies.For(policy.Type(accountType))
+		if pol.MaxPayment > 0 && ev.GetAmount() > pol.MaxPayment {
+			logger.WarnContext(ctx, "payment above account type limit", "order_id", ev.GetOrderId(), "user_id", ev.GetUserId(), "account_type", accountType, "amount", ev.GetAmount(), "limit", pol.MaxPayment)
+			return c.enqueueResult(ctx, q, &ev, orderID, eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_LIMIT_EXCEEDED, fmt.Sprintf("amount exceeds the %s account limit of %d", accountType, pol.MaxPayment), 0)
+		}
+		fee := c.fees.Fee(policy.Type(accountType), ev.GetAmount())
+
+		// Bonus grants pay for the amount before the payment method; the fee
+		// always comes from the method.
+		grants, err := q.LockActiveBonusGrants(ctx, ev.GetUserId())
 		if err != nil {
-			logger.Error("payment requested deduct failed", "err", err, "order_id", ev.GetOrderId())
+			logger.ErrorContext(ctx, "payment requested bonus lookup failed", "err", err, "user_id", ev.GetUserId())
 			return err
 		}
+		bonus, spends := planBonus(grants, ev.GetAmount())
 
+		err = provider.Pay(ctx, q, method.Charge{
+			PaymentID:     pgtype.UUID{Bytes: paymentID, Valid: true},
+			OrderID:       pgtype.UUID{Bytes: orderID, Valid: true},
+			UserID:        ev.GetUserId(),
+			Amount:        ev.GetAmount(),
+			Bonus:         bonus,
+			Fee:           fee,
+			BonusSpends:   spends,
+			CorrelationID: ev.GetCorrelationId(),
+		})
 		status := eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_INTERNAL
 		reason := ""
-		if res.OpInserted == 1 {
+		switch {
+		case err == nil:
 			status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS
-		} else {
+			if err := spendBonus(ctx, q, spends); err != nil {
+				logger.ErrorContext(ctx, "payment requested bonus spend failed", "err", err, "order_id", ev.GetOrderId())
+				return err
+			}
+		case errors.Is(err, method.ErrPending):
+			// The provider publishes the result once the charge settles; the
+			// bonus is held until then.
+			if err := spendBonus(ctx, q, spends); err != nil {
+				logger.ErrorContext(ctx, "payment requested bonus spend failed", "err", err, "order_id", ev.GetOrderId())
+				return err
+			}
+			logger.InfoContext(ctx, "payment pending with method", "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod())
+			return nil
+		case errors.Is(err, method.ErrChallenge) && c.challengeTopic != "":
+			if err := spendBonus(ctx, q, spends); err != nil {
+				logger.ErrorContext(ctx, "payment requested bonus spend failed", "err", err, "order_id", ev.GetOrderId())
+				return err
+			}
+			return c.requireChallenge(ctx, q, &ev, orderID, paymentID, bonus, fee, spends)
+		case errors.Is(err, method.ErrChallenge):
+			fee = 0
+			status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED
+			reason = "payment confirmation is not supported"
+			logger.WarnContext(ctx, "payment challenge without challenges enabled", "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod())
+		case errors.Is(err, method.ErrDeclined):
+			fee = 0
+			status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED
+			reason = err.Error()
+			logger.WarnContext(ctx, "payment declined by method", "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod(), "reason", reason)
+		case errors.Is(err, method.ErrNotEnoughFunds):
+			fee = 0
 			exists, err := q.AccountExists(ctx, ev.GetUserId())
 			if err != nil {
-				logger.Error("payment requested account existence check failed", "err", err, "user_id", ev.GetUserId())
+				logger.ErrorContext(ctx, "payment requested account existence check failed", "err", err, "user_id", ev.GetUserId())
 				return err
 			}
+			if !exists && c.autoCreate {
+				// ErrNoRows means a concurrent request created it first; either way it exists now.
+				_, err := q.CreateAccount(ctx, ev.GetUserId())
+				switch {
+				case err == nil:
+					if err := EnqueueAccountCreated(ctx, q, c.accountTopic, ev.GetUserId()); err != nil {
+						return err
+					}
+					logger.InfoContext(ctx, "payment requested account auto-created", "user_id", ev.GetUserId())
+				case !errors.Is(err, pgx.ErrNoRows):
+					logger.ErrorContext(ctx, "payment requested account auto-create failed", "err", err, "user_id", ev.GetUserId())
+					return err
+				}
+				exists = true
+			}
 			if !exists {
 				status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NO_ACCOUNT
 				reason = "account not found"
@@ -123,38 +354,101 @@ func (c *PaymentRequestedConsumer) handleMessage(ctx context.Context, m kafka.Me
 				status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_NOT_ENOUGH_FUNDS
 				reason = "not enough funds"
 			}
+		default:
+			logger.ErrorContext(ctx, "payment requested charge failed", "err", err, "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod())
+			return err
 		}
 
-		result := &eventsv1.PaymentResult{
-			EventId:    uuid.NewString(),
-			OccurredAt: timestamppb.Now(),
-			OrderId:    orderID.String(),
-			UserId:     ev.GetUserId(),
-			Status:     status,
-			Reason:     reason,
+		return c.enqueueResult(ctx, q, &ev, orderID, status, reason, fee)
+	}
+	msg := inbox.FromKafka(m, msgID.String(), ev.GetCorrelationId())
+	apply := func(tx pgx.Tx, q *db.Queries) error {
+		if dry != nil {
+			return inbox.RunDry(ctx, q, dry, process)
 		}
-
-		payload, err := proto.Marshal(result)
+		fresh, err := c.inbox.Begin(ctx, tx, msg)
 		if err != nil {
-			logger.Error("payment result marshal failed", "err", err, "order_id", ev.GetOrderId())
+			logger.ErrorContext(ctx, "payment requested inbox claim failed", "err", err)
 			return err
 		}
-
-		if _, err := q.InsertOutbox(ctx, db.InsertOutboxParams{
-			Topic:    c.resultTopic,
-			KafkaKey: orderID.String(),
-			Payload:  payload,
-		}); err != nil {
-			logger.Error("payment result outbox insert failed", "err", err, "order_id", ev.GetOrderId())
+		if !fresh {
+			logger.InfoContext(ctx, "payment requested already processed", "event_id", ev.GetEventId())
+			return nil
+		}
+		if err := process(q); err != nil {
 			return err
 		}
-
+		return c.inbox.MarkProcessed(ctx, tx, msg)
+	}
+	repo := c.shards.Repo(ev.GetUserId())
+	err = repo.WithTxOptions(ctx, c.deductTx, txoffset.Wrap(ctx, c.txOffsets && dry == nil, m, apply, logger))
+	if dry != nil && errors.Is(err, inbox.ErrDryRun) {
+		logger.InfoContext(ctx, "payment requested dry run completed", "order_id", ev.GetOrderId(), "shard", repo.Shard(), "outbox_events", len(dry.Outbox))
 		return nil
-	})
+	}
 	if err != nil {
-		logger.Error("payment requested handle message failed", "err", err, "order_id", ev.GetOrderId())
+		logger.ErrorContext(ctx, "payment requested handle message failed", "err", err, "order_id", ev.GetOrderId(), "shard", repo.Shard())
 		return err
 	}
-	logger.Info("payment requested handle message completed", "order_id", ev.GetOrderId())
+	logger.InfoContext(ctx, "payment requested handle message completed", "order_id", ev.GetOrderId(), "shard", repo.Shard())
 	return nil
 }
+
+// requireChallenge records the payment of ev as waiting for the payer's
+// confirmation, holding the bonus spends, and writes
+// PaymentChallengeRequired to the outbox.
+func (c *PaymentRequestedConsumer) requireChallenge(ctx context.Context, q *db.Queries, ev *eventsv1.PaymentRequested, orderID, paymentID uuid.UUID, bonus, fee int64, spends []method.BonusSpend) error {
+	logger := slog.Default().With("service", "payments-service", "component", "kafka")
+	ids := make([]int64, 0, len(spends))
+	amounts := make([]int64, 0, len(spends))
+	for _, s := range spends {
+		ids = append(ids, s.GrantID)
+		amounts = append(amounts, s.Amount)
+	}
+	expiresAt := time.Now().Add(c.challengeTTL)
+	if _, err := q.InsertPaymentChallenge(ctx, db.InsertPaymentChallengeParams{
+		PaymentID:         pgtype.UUID{Bytes: paymentID, Valid: true},
+		OrderID:           pgtype.UUID{Bytes: orderID, Valid: true},
+		UserID:            ev.GetUserId(),
+		Method:            ev.GetPaymentMethod(),
+		Amount:            ev.GetAmount(),
+		Bonus:             bonus,
+		Fee:               fee,
+		BonusGrantIds:     ids,
+		BonusGrantAmounts: amounts,
+		CorrelationID:     ev.GetCorrelationId(),
+		ExpiresAt:         pgtype.Timestamptz{Time: expiresAt, Valid: true},
+	}); err != nil {
+		logger.ErrorContext(ctx, "payment challenge insert failed", "err", err, "order_id", ev.GetOrderId())
+		return err
+	}
+	logger.InfoContext(ctx, "payment waits for confirmation", "order_id", ev.GetOrderId(), "payment_method", ev.GetPaymentMethod(), "expires_at", expiresAt)
+	return EnqueuePaymentChallengeRequired(ctx, q, c.challengeTopic, &eventsv1.PaymentChallengeRequired{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       orderID.String(),
+		UserId:        ev.GetUserId(),
+		PaymentId:     ev.GetPaymentId(),
+		Amount:        ev.GetAmount(),
+		PaymentMethod: ev.GetPaymentMethod(),
+		ExpiresAt:     timestamppb.New(expiresAt),
+		CorrelationId: ev.GetCorrelationId(),
+	})
+}
+
+// enqueueResult writes the PaymentResult for ev to the outbox. fee is what
+// was charged on top of the amount, so 0 unless the payment succeeded.
+func (c *PaymentRequestedConsumer) enqueueResult(ctx context.Context, q db.Querier, ev *eventsv1.PaymentRequested, orderID uuid.UUID, status eventsv1.PaymentResultStatus, reason string, fee int64) error {
+	return EnqueuePaymentResult(ctx, q, c.resultTopic, &eventsv1.PaymentResult{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       orderID.String(),
+		UserId:        ev.GetUserId(),
+		Status:        status,
+		Reason:        reason,
+		PaymentId:     ev.GetPaymentId(),
+		Amount:        ev.GetAmount(),
+		Fee:           fee,
+		CorrelationId: ev.GetCorrelationId(),
+	})
+}
@@ -0,0 +1,68 @@
+package kafka
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+)
+
+// outboxQuerier records the outbox rows written through it.
+type outboxQuerier struct {
+	db.Querier
+	outbox []db.InsertOutboxParams
+}
+
+func (q *outboxQuerier) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	q.outbox = append(q.outbox, arg)
+	return int64(len(q.outbox)), nil
+}
+
+func TestPaymentResultCarriesCorrelationID(t *testing.T) {
+	q := &outboxQuerier{}
+	c := &PaymentRequestedConsumer{resultTopic: "payments.payment_result.v1"}
+	orderID := uuid.New()
+	ev := &eventsv1.PaymentRequested{OrderId: orderID.String(), UserId: "u-1", Amount: 100, CorrelationId: "req-1"}
+
+	if err := c.enqueueResult(context.Background(), q, ev, orderID, eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS, "", 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(q.outbox) != 1 || q.outbox[0].CorrelationID != "req-1" {
+		t.Fatalf("outbox = %+v, want one row with correlation id req-1", q.outbox)
+	}
+	var res eventsv1.PaymentResult
+	if err := UnmarshalEvent(q.outbox[0].Payload, &res); err != nil {
+		t.Fatal(err)
+	}
+	if res.GetCorrelationId() != "req-1" || res.GetOrderId() != orderID.String() {
+		t.Fatalf("result = %v, want correlation id req-1", &res)
+	}
+}
+
+func TestPaymentRequestedLogsCorrelationID(t *testing.T) {
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(slog.New(logging.NewHandler(slog.NewJSONHandler(&buf, nil))))
+	defer slog.SetDefault(prev)
+
+	payload, err := MarshalEvent(&eventsv1.PaymentRequested{EventId: "not-a-uuid", OrderId: uuid.NewString(), UserId: "u-1", CorrelationId: "req-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The invalid event id is skipped before the database is touched.
+	if err := (&PaymentRequestedConsumer{}).handle(context.Background(), kafka.Message{Value: payload}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"request_id":"req-1"`) {
+		t.Fatalf("consumer logs lack the request id:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,183 @@
+// Package method charges payments to the method they were requested with.
+//
+// Bonus grants always pay first; the provider of the payment method pays
+// the rest and the fee. The internal balance is always available; other
+// providers, such as the mock card, are registered at startup.
+package method
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// Names of the built-in methods, as given in PaymentRequested.payment_method.
+const (
+	Balance = "balance"
+	Card    = "card"
+)
+
+// Reasons a payment is not made.
+var (
+	// ErrNotEnoughFunds means the balance, overdraft included, does not
+	// cover the charge.
+	ErrNotEnoughFunds = errors.New("not enough funds")
+	// ErrDeclined means the provider refused the charge.
+	ErrDeclined = errors.New("payment declined")
+	// ErrUnsupported means no provider is registered for the method.
+	ErrUnsupported = errors.New("unsupported payment method")
+	// ErrPending means the provider took the charge but learns its outcome
+	// later; the bonus part stays held and the provider reports the result
+	// itself.
+	ErrPending = errors.New("payment pending")
+	// ErrChallenge means the payer has to confirm the charge before the
+	// provider makes it; the charge is made again with Confirmed set once
+	// they do.
+	ErrChallenge = errors.New("payment confirmation required")
+)
+
+// Charge is one payment as its provider gets it.
+type Charge struct {
+	PaymentID pgtype.UUID
+	OrderID   pgtype.UUID
+	UserID    string
+	// Amount is the whole payment; Bonus is the part of it bonus grants
+	// cover. Fee is charged on top of Amount.
+	Amount int64
+	Bonus  int64
+	Fee    int64
+	// BonusSpends are the grants Bonus is taken from, for providers that
+	// have to give them back when a pending charge fails.
+	BonusSpends []BonusSpend
+	// CorrelationID is the correlation id of the PaymentRequested, which
+	// the PaymentResult of a pending charge carries on.
+	CorrelationID string
+	// Confirmed is set once the payer confirmed a charge the provider
+	// answered with ErrChallenge.
+	Confirmed bool
+}
+
+// BonusSpend is what a payment takes from one bonus grant.
+type BonusSpend struct {
+	GrantID int64
+	Amount  int64
+}
+
+// Due is what the provider itself pays: the amount bonus grants do not
+// cover, and the fee.
+func (c Charge) Due() int64 {
+	return c.Amount - c.Bonus + c.Fee
+}
+
+// Provider pays the part of a payment that bonus grants do not cover. Pay
+// runs inside the payment transaction and records the operation, bonus part
+// included, in account_ops, so a payment id is never paid twice. A payment
+// that is not made is reported by ErrNotEnoughFunds or ErrDeclined, one
+// settled later by ErrPending and one the payer has to confirm first by
+// ErrChallenge, possibly wrapped; any other error aborts the transaction and
+// the event is redelivered.
+type Provider interface {
+	Pay(ctx context.Context, q db.Querier, c Charge) error
+}
+
+// Registry maps method names to providers.
+type Registry struct {
+	providers map[string]Provider
+}
+
+// NewRegistry returns a registry with the balance provider under Balance.
+func NewRegistry() *Registry {
+	return &Registry{providers: map[string]Provider{Balance: BalanceProvider{}}}
+}
+
+// Register makes p the provider of the method name, replacing any provider
+// registered before.
+func (r *Registry) Register(name string, p Provider) {
+	r.providers[name] = p
+}
+
+// Lookup returns the provider of the method name; "" is Balance.
+func (r *Registry) Lookup(name string) (Provider, error) {
+	if name == "" {
+		name = Balance
+	}
+	p, ok := r.providers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupported, name)
+	}
+	return p, nil
+}
+
+// Names returns the registered methods in sorted order.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// BalanceProvider pays from the main balance of the account, down to its
+// overdraft limit.
+type BalanceProvider struct{}
+
+func (BalanceProvider) Pay(ctx context.Context, q db.Querier, c Charge) error {
+	res, err := q.TryDeductOnce(ctx, db.TryDeductOnceParams{
+		PaymentID: c.PaymentID,
+		OrderID:   c.OrderID,
+		UserID:    c.UserID,
+		Amount:    c.Amount,
+		Fee:       c.Fee,
+		Bonus:     c.Bonus,
+	})
+	if err != nil {
+		return fmt.Errorf("deduct: %w", err)
+	}
+	// Nothing was deducted: the balance is short, the account is missing or
+	// the payment id is already taken.
+	if res.OpInserted != 1 {
+		return ErrNotEnoughFunds
+	}
+	return nil
+}
+
+// MockCard stands in for an external card processor: it approves charges
+// whose Due is at most DeclineAbove, or any charge when DeclineAbove is 0.
+// Charges whose Due is above ChallengeAbove, when it is set, need the
+// payer's confirmation first, like 3-D Secure. Approved payments leave the
+// balance alone.
+type MockCard struct {
+	DeclineAbove   int64
+	ChallengeAbove int64
+}
+
+func (m MockCard) Pay(ctx context.Context, q db.Querier, c Charge) error {
+	if m.DeclineAbove > 0 && c.Due() > m.DeclineAbove {
+		return fmt.Errorf("%w: card limit of %d exceeded", ErrDeclined, m.DeclineAbove)
+	}
+	if m.ChallengeAbove > 0 && c.Due() > m.ChallengeAbove && !c.Confirmed {
+		return ErrChallenge
+	}
+	inserted, err := q.RecordExternalPayment(ctx, db.RecordExternalPaymentParams{
+		PaymentID: c.PaymentID,
+		OrderID:   c.OrderID,
+		UserID:    c.UserID,
+		Amount:    c.Amount,
+		Fee:       c.Fee,
+		Bonus:     c.Bonus,
+		Method:    Card,
+	})
+	if err != nil {
+		return fmt.Errorf("record card payment: %w", err)
+	}
+	if inserted != 1 {
+		return fmt.Errorf("%w: payment already recorded", ErrDeclined)
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+package method
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// fakeOps keeps the operations written by the providers, keyed by payment
+// id, and one balance per user.
+type fakeOps struct {
+	db.Querier
+	balances map[string]int64
+	ops      map[pgtype.UUID]string
+}
+
+func (f *fakeOps) TryDeductOnce(_ context.Context, arg db.TryDeductOnceParams) (db.TryDeductOnceRow, error) {
+	due := arg.Amount - arg.Bonus + arg.Fee
+	if _, taken := f.ops[arg.PaymentID]; taken || f.balances[arg.UserID] < due {
+		return db.TryDeductOnceRow{}, nil
+	}
+	f.balances[arg.UserID] -= due
+	f.ops[arg.PaymentID] = Balance
+	return db.TryDeductOnceRow{NewBalance: f.balances[arg.UserID], OpInserted: 1}, nil
+}
+
+func (f *fakeOps) RecordExternalPayment(_ context.Context, arg db.RecordExternalPaymentParams) (int64, error) {
+	if _, taken := f.ops[arg.PaymentID]; taken {
+		return 0, nil
+	}
+	f.ops[arg.PaymentID] = arg.Method
+	return 1, nil
+}
+
+func paymentID(b byte) pgtype.UUID {
+	return pgtype.UUID{Bytes: [16]byte{b}, Valid: true}
+}
+
+func TestRegistry(t *testing.T) {
+	r := NewRegistry()
+	if p, err := r.Lookup(""); err != nil || p != (BalanceProvider{}) {
+		t.Fatalf("Lookup(\"\") = %v, %v; want the balance", p, err)
+	}
+	if _, err := r.Lookup(Card); !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("Lookup(card) error = %v, want ErrUnsupported before it is registered", err)
+	}
+	r.Register(Card, MockCard{})
+	if p, err := r.Lookup(Card); err != nil || p != (MockCard{}) {
+		t.Fatalf("Lookup(card) = %v, %v; want the mock card", p, err)
+	}
+	if names := r.Names(); len(names) != 2 || names[0] != Balance || names[1] != Card {
+		t.Fatalf("Names() = %v, want [balance card]", names)
+	}
+}
+
+func TestBalanceProvider(t *testing.T) {
+	ctx := context.Background()
+	q := &fakeOps{balances: map[string]int64{"u-1": 100}, ops: map[pgtype.UUID]string{}}
+
+	// The bonus part is not taken from the balance; the fee is.
+	c := Charge{PaymentID: paymentID(1), UserID: "u-1", Amount: 150, Bonus: 60, Fee: 10}
+	if err := (BalanceProvider{}).Pay(ctx, q, c); err != nil {
+		t.Fatalf("Pay() error: %v", err)
+	}
+	if q.balances["u-1"] != 0 {
+		t.Fatalf("balance = %d, want 0", q.balances["u-1"])
+	}
+	c.PaymentID = paymentID(2)
+	if err := (BalanceProvider{}).Pay(ctx, q, c); !errors.Is(err, ErrNotEnoughFunds) {
+		t.Fatalf("Pay() error = %v, want ErrNotEnoughFunds", err)
+	}
+}
+
+func TestMockCard(t *testing.T) {
+	ctx := context.Background()
+	q := &fakeOps{balances: map[string]int64{"u-1": 0}, ops: map[pgtype.UUID]string{}}
+	card := MockCard{DeclineAbove: 100}
+
+	// Due is 150 - 60 + 10 = 100, right at the limit.
+	c := Charge{PaymentID: paymentID(1), UserID: "u-1", Amount: 150, Bonus: 60, Fee: 10}
+	if err := card.Pay(ctx, q, c); err != nil {
+		t.Fatalf("Pay() error: %v", err)
+	}
+	if q.ops[c.PaymentID] != Card || q.balances["u-1"] != 0 {
+		t.Fatalf("ops = %v, balance = %d; want a card operation and the balance untouched", q.ops, q.balances["u-1"])
+	}
+	if err := card.Pay(ctx, q, c); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("second Pay() error = %v, want ErrDeclined", err)
+	}
+	c.PaymentID, c.Fee = paymentID(2), 11
+	if err := card.Pay(ctx, q, c); !errors.Is(err, ErrDeclined) {
+		t.Fatalf("Pay() above the limit error = %v, want ErrDeclined", err)
+	}
+	if _, ok := q.ops[c.PaymentID]; ok {
+		t.Fatal("declined payment was recorded")
+	}
+}
+
+func TestMockCardChallenge(t *testing.T) {
+	ctx := context.Background()
+	q := &fakeOps{balances: map[string]int64{}, ops: map[pgtype.UUID]string{}}
+	card := MockCard{ChallengeAbove: 100}
+
+	c := Charge{PaymentID: paymentID(1), UserID: "u-1", Amount: 101}
+	if err := card.Pay(ctx, q, c); !errors.Is(err, ErrChallenge) {
+		t.Fatalf("Pay() error = %v, want ErrChallenge", err)
+	}
+	if _, ok := q.ops[c.PaymentID]; ok {
+		t.Fatal("unconfirmed payment was recorded")
+	}
+	c.Confirmed = true
+	if err := card.Pay(ctx, q, c); err != nil {
+		t.Fatalf("confirmed Pay() error: %v", err)
+	}
+	if q.ops[c.PaymentID] != Card {
+		t.Fatalf("ops = %v, want the confirmed card payment", q.ops)
+	}
+}
@@ -0,0 +1,98 @@
+// Package policy resolves the limits and overdraft that apply to an account
+// from its type. Fees are per type too, but live in the fees package.
+package policy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// Type is an account tier as stored in accounts.account_type.
+type Type string
+
+const (
+	Basic    Type = "BASIC"
+	Premium  Type = "PREMIUM"
+	Business Type = "BUSINESS"
+)
+
+// ParseType returns the Type named s.
+func ParseType(s string) (Type, bool) {
+	switch t := Type(s); t {
+	case Basic, Premium, Business:
+		return t, true
+	}
+	return "", false
+}
+
+// Policy is what an account of a given type is allowed. The zero Policy
+// keeps the behaviour of accounts before tiers existed.
+type Policy struct {
+	// MaxPayment declines single payments above it; 0 means no limit.
+	MaxPayment int64
+	// Overdraft is the overdraft limit an account gets when it is switched
+	// to the type; the per-account limit can still be changed afterwards.
+	Overdraft int64
+}
+
+// Policies maps account types to their policy; absent types get the zero
+// Policy.
+type Policies map[Type]Policy
+
+// For returns the policy of t.
+func (p Policies) For(t Type) Policy {
+	return p[t]
+}
+
+// Parse reads policies in the PAYMENTS_ACCOUNT_POLICIES format:
+// semicolon separated TYPE:key=value,... entries, for example
+// "PREMIUM:overdraft=100000;BUSINESS:max_payment=5000000,overdraft=500000".
+// Keys are max_payment and overdraft; omitted keys are 0.
+func Parse(s string) (Policies, error) {
+	policies := Policies{}
+	for _, entry := range strings.Split(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		name, params, ok := strings.Cut(entry, ":")
+		if !ok {
+			return nil, fmt.Errorf("policy %q: want TYPE:key=value,...", entry)
+		}
+		t, ok := ParseType(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("policy %q: unknown account type %q", entry, name)
+		}
+		if _, dup := policies[t]; dup {
+			return nil, fmt.Errorf("policy for %s is set twice", t)
+		}
+		var p Policy
+		for _, kv := range strings.Split(params, ",") {
+			kv = strings.TrimSpace(kv)
+			if kv == "" {
+				continue
+			}
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("policy for %s: %q is not key=value", t, kv)
+			}
+			n, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+			if err != nil || n < 0 || n > money.MaxAmount {
+				return nil, fmt.Errorf("policy for %s: %s must be 0..%d", t, k, money.MaxAmount)
+			}
+			switch strings.TrimSpace(k) {
+			case "max_payment":
+				p.MaxPayment = n
+			case "overdraft":
+				p.Overdraft = n
+			default:
+				return nil, fmt.Errorf("policy for %s: unknown key %q", t, k)
+			}
+		}
+		policies[t] = p
+	}
+	return policies, nil
+}
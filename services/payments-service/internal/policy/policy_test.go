@@ -0,0 +1,38 @@
+package policy
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	p, err := Parse(" PREMIUM:overdraft=100000 ; BUSINESS:max_payment=5000000, overdraft=500000;")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := Policies{
+		Premium:  {Overdraft: 100000},
+		Business: {MaxPayment: 5000000, Overdraft: 500000},
+	}
+	if len(p) != len(want) || p[Premium] != want[Premium] || p[Business] != want[Business] {
+		t.Fatalf("Parse() = %+v, want %+v", p, want)
+	}
+	if got := p.For(Basic); got != (Policy{}) {
+		t.Fatalf("For(BASIC) = %+v, want the zero policy", got)
+	}
+
+	if p, err := Parse(""); err != nil || len(p) != 0 {
+		t.Fatalf("Parse(\"\") = %v, %v; want no policies", p, err)
+	}
+	for _, bad := range []string{
+		"PREMIUM",
+		"GOLD:overdraft=1",
+		"PREMIUM:overdraft",
+		"PREMIUM:overdraft=-1",
+		"PREMIUM:overdraft=x",
+		"PREMIUM:limit=1",
+		"PREMIUM:fee_bps=1",
+		"PREMIUM:overdraft=1;PREMIUM:max_payment=1",
+	} {
+		if _, err := Parse(bad); err == nil {
+			t.Errorf("Parse(%q) succeeded, want error", bad)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// Package psp pays through an external payment service provider (PSP) with a
+// Stripe-like HTTP API.
+//
+// A payment with the psp method only records a pending external charge in
+// the payment transaction. The Worker submits it to the provider and polls
+// the charges whose outcome did not arrive; the provider's webhooks settle
+// them sooner. Settling records the payment, or gives the held bonus back,
+// and writes the PaymentResult to the outbox. Charges still pending after
+// the timeout are canceled at the provider and fail.
+package psp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// Statuses of a provider charge. Only pending is not final.
+const (
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCanceled  = "canceled"
+)
+
+// ErrRejected means the provider refused to create the charge, e.g. for an
+// invalid or declined card; the charge will not succeed on a retry.
+var ErrRejected = errors.New("charge rejected by provider")
+
+// Charge is a charge as the provider reports it.
+type Charge struct {
+	ID             string            `json:"id"`
+	Status         string            `json:"status"`
+	Amount         int64             `json:"amount"`
+	Currency       string            `json:"currency"`
+	Reference      string            `json:"reference"`
+	FailureMessage string            `json:"failure_message,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Final reports whether the charge will not change any more.
+func (c Charge) Final() bool {
+	return c.Status == StatusSucceeded || c.Status == StatusFailed || c.Status == StatusCanceled
+}
+
+// chargeRequest is the body of POST /v1/charges.
+type chargeRequest struct {
+	Amount    int64             `json:"amount"`
+	Currency  string            `json:"currency"`
+	Reference string            `json:"reference"`
+	Metadata  map[string]string `json:"metadata"`
+}
+
+// apiError is the body of a non-2xx response.
+type apiError struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// responseError is a non-2xx response.
+type responseError struct {
+	op   string
+	code int
+	msg  string
+}
+
+func (e *responseError) Error() string {
+	return fmt.Sprintf("%s: %d %s", e.op, e.code, e.msg)
+}
+
+// Client calls the provider API at baseURL with a bearer API key.
+type Client struct {
+	baseURL  string
+	apiKey   string
+	currency money.Currency
+	http     *http.Client
+}
+
+// NewClient builds a client that charges in currency.
+func NewClient(baseURL, apiKey string, currency money.Currency, hc *http.Client) *Client {
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), apiKey: apiKey, currency: currency, http: hc}
+}
+
+// Create creates the charge of paymentID, which is also the idempotency key,
+// so creating it again returns the same charge.
+func (c *Client) Create(ctx context.Context, paymentID, orderID, userID string, amount int64) (Charge, error) {
+	body, err := json.Marshal(chargeRequest{
+		Amount:    amount,
+		Currency:  strings.ToLower(string(c.currency)),
+		Reference: paymentID,
+		Metadata:  map[string]string{"order_id": orderID, "user_id": userID},
+	})
+	if err != nil {
+		return Charge{}, err
+	}
+	ch, err := c.do(ctx, http.MethodPost, "/v1/charges", paymentID, body)
+	// Client errors other than conflicts and rate limits will not go away;
+	// the rest are retried.
+	var re *responseError
+	if errors.As(err, &re) && re.code/100 == 4 && re.code != http.StatusConflict && re.code != http.StatusTooManyRequests {
+		return Charge{}, fmt.Errorf("%w: %s", ErrRejected, re.msg)
+	}
+	return ch, err
+}
+
+// Get returns the charge id.
+func (c *Client) Get(ctx context.Context, id string) (Charge, error) {
+	return c.do(ctx, http.MethodGet, "/v1/charges/"+url.PathEscape(id), "", nil)
+}
+
+// Cancel cancels the charge id unless it is final, and returns it as it is
+// afterwards.
+func (c *Client) Cancel(ctx context.Context, id string) (Charge, error) {
+	return c.do(ctx, http.MethodPost, "/v1/charges/"+url.PathEscape(id)+"/cancel", "", nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path, idempotencyKey string, body []byte) (Charge, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return Charge{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return Charge{}, fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return Charge{}, fmt.Errorf("%s %s: read response: %w", method, path, err)
+	}
+	if resp.StatusCode/100 != 2 {
+		var e apiError
+		_ = json.Unmarshal(data, &e)
+		msg := e.Error.Message
+		if msg == "" {
+			msg = resp.Status
+		}
+		return Charge{}, &responseError{op: method + " " + path, code: resp.StatusCode, msg: msg}
+	}
+	var ch Charge
+	if err := json.Unmarshal(data, &ch); err != nil {
+		return Charge{}, fmt.Errorf("%s %s: decode charge: %w", method, path, err)
+	}
+	return ch, nil
+}
@@ -0,0 +1,243 @@
+package psp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// fakeRepo is an in-memory single-shard repository of external charges.
+// Queries the package does not use fall through to the nil embedded Querier
+// and panic.
+type fakeRepo struct {
+	db.Querier
+
+	charges map[pgtype.UUID]*db.ExternalCharge
+	// grants are the remaining amounts of bonus grants by id.
+	grants map[int64]int64
+	ops    []db.RecordExternalPaymentParams
+	outbox []db.InsertOutboxParams
+}
+
+var _ repo.ShardedRepository = (*fakeRepo)(nil)
+
+func newFakeRepo() *fakeRepo {
+	return &fakeRepo{charges: map[pgtype.UUID]*db.ExternalCharge{}, grants: map[int64]int64{}}
+}
+
+func (f *fakeRepo) For(string) repo.PaymentsRepository { return f }
+func (f *fakeRepo) All() []repo.PaymentsRepository     { return []repo.PaymentsRepository{f} }
+func (f *fakeRepo) Q() db.Querier                      { return f }
+
+func (f *fakeRepo) Read(_ context.Context, fn func(q db.Querier) error) error {
+	return fn(f)
+}
+
+func (f *fakeRepo) InTx(_ context.Context, fn func(q db.Querier) error) error {
+	return fn(f)
+}
+
+func (f *fakeRepo) InsertExternalCharge(_ context.Context, arg db.InsertExternalChargeParams) (int64, error) {
+	if _, ok := f.charges[arg.PaymentID]; ok {
+		return 0, nil
+	}
+	f.charges[arg.PaymentID] = &db.ExternalCharge{
+		PaymentID:         arg.PaymentID,
+		OrderID:           arg.OrderID,
+		UserID:            arg.UserID,
+		Method:            arg.Method,
+		Amount:            arg.Amount,
+		Bonus:             arg.Bonus,
+		Fee:               arg.Fee,
+		BonusGrantIds:     arg.BonusGrantIds,
+		BonusGrantAmounts: arg.BonusGrantAmounts,
+		CorrelationID:     arg.CorrelationID,
+		Status:            "pending",
+		CreatedAt:         pgtype.Timestamptz{Time: time.Now(), Valid: true},
+	}
+	return 1, nil
+}
+
+func (f *fakeRepo) ListPendingExternalCharges(_ context.Context, arg db.ListPendingExternalChargesParams) ([]db.ExternalCharge, error) {
+	var due []db.ExternalCharge
+	for _, ch := range f.charges {
+		if ch.Status == "pending" && (!ch.ProviderChargeID.Valid || !ch.CheckedAt.Valid || !ch.CheckedAt.Time.After(arg.CheckedBefore.Time)) {
+			due = append(due, *ch)
+		}
+	}
+	return due, nil
+}
+
+func (f *fakeRepo) SetExternalChargeSubmitted(_ context.Context, arg db.SetExternalChargeSubmittedParams) error {
+	ch := f.charges[arg.PaymentID]
+	ch.ProviderChargeID = pgtype.Text{String: arg.ProviderChargeID, Valid: true}
+	ch.Attempts++
+	ch.CheckedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (f *fakeRepo) TouchExternalCharge(_ context.Context, paymentID pgtype.UUID) error {
+	ch := f.charges[paymentID]
+	ch.Attempts++
+	ch.CheckedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (f *fakeRepo) LockPendingExternalCharge(_ context.Context, paymentID pgtype.UUID) (db.ExternalCharge, error) {
+	ch, ok := f.charges[paymentID]
+	if !ok || ch.Status != "pending" {
+		return db.ExternalCharge{}, pgx.ErrNoRows
+	}
+	return *ch, nil
+}
+
+func (f *fakeRepo) FinishExternalCharge(_ context.Context, arg db.FinishExternalChargeParams) error {
+	ch := f.charges[arg.PaymentID]
+	ch.Status = arg.Status
+	if !ch.ProviderChargeID.Valid {
+		ch.ProviderChargeID = arg.ProviderChargeID
+	}
+	ch.FailureReason = arg.FailureReason
+	ch.FinishedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return nil
+}
+
+func (f *fakeRepo) MarkExternalChargeSucceededAfterFailure(_ context.Context, arg db.MarkExternalChargeSucceededAfterFailureParams) (int64, error) {
+	ch, ok := f.charges[arg.PaymentID]
+	if !ok || ch.Status != "failed" || ch.ProviderSucceededAt.Valid {
+		return 0, nil
+	}
+	ch.ProviderSucceededAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	return 1, nil
+}
+
+func (f *fakeRepo) RecordExternalPayment(_ context.Context, arg db.RecordExternalPaymentParams) (int64, error) {
+	f.ops = append(f.ops, arg)
+	return 1, nil
+}
+
+func (f *fakeRepo) RestoreBonusGrants(_ context.Context, arg db.RestoreBonusGrantsParams) error {
+	for i, id := range arg.Ids {
+		f.grants[id] += arg.Amounts[i]
+	}
+	return nil
+}
+
+func (f *fakeRepo) InsertOutbox(_ context.Context, arg db.InsertOutboxParams) (int64, error) {
+	f.outbox = append(f.outbox, arg)
+	return int64(len(f.outbox)), nil
+}
+
+// results decodes the PaymentResults in the outbox.
+func (f *fakeRepo) results(t *testing.T) []*eventsv1.PaymentResult {
+	t.Helper()
+	var out []*eventsv1.PaymentResult
+	for _, row := range f.outbox {
+		var r eventsv1.PaymentResult
+		if err := kafka.UnmarshalEvent(row.Payload, &r); err != nil {
+			t.Fatalf("unmarshal payment result: %v", err)
+		}
+		out = append(out, &r)
+	}
+	return out
+}
+
+// fakePSP is the provider API. Charges above declineAbove are rejected on
+// creation; the others stay pending until the test changes their status.
+// While dropCreated is set, charges are created but answered with 503, as
+// when the response is lost.
+type fakePSP struct {
+	t            *testing.T
+	declineAbove int64
+	dropCreated  bool
+
+	mu      sync.Mutex
+	charges map[string]*Charge
+	// byKey maps idempotency keys to charge ids.
+	byKey    map[string]string
+	canceled []string
+}
+
+func newFakePSP(t *testing.T) (*fakePSP, *Client) {
+	p := &fakePSP{t: t, declineAbove: 1000, charges: map[string]*Charge{}, byKey: map[string]string{}}
+	srv := httptest.NewServer(p)
+	t.Cleanup(srv.Close)
+	return p, NewClient(srv.URL, "sk_test", "RUB", srv.Client())
+}
+
+func (p *fakePSP) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer sk_test" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	path := strings.TrimPrefix(r.URL.Path, "/v1/charges")
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			p.t.Error("charge created without an idempotency key")
+		}
+		if id, ok := p.byKey[key]; ok {
+			_ = json.NewEncoder(w).Encode(p.charges[id])
+			return
+		}
+		var req chargeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if req.Amount > p.declineAbove {
+			w.WriteHeader(http.StatusPaymentRequired)
+			_, _ = w.Write([]byte(`{"error":{"code":"card_declined","message":"card declined"}}`))
+			return
+		}
+		ch := &Charge{ID: fmt.Sprintf("ch_%d", len(p.charges)+1), Status: StatusPending, Amount: req.Amount, Currency: req.Currency, Reference: req.Reference, Metadata: req.Metadata}
+		p.charges[ch.ID], p.byKey[key] = ch, ch.ID
+		if p.dropCreated {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ch)
+	case strings.HasSuffix(path, "/cancel"):
+		ch, ok := p.charges[strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/cancel")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if ch.Status == StatusPending {
+			ch.Status = StatusCanceled
+		}
+		p.canceled = append(p.canceled, ch.ID)
+		_ = json.NewEncoder(w).Encode(ch)
+	default:
+		ch, ok := p.charges[strings.TrimPrefix(path, "/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(ch)
+	}
+}
+
+// set changes the status of the only charge with reference paymentID.
+func (p *fakePSP) set(paymentID, status string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.charges[p.byKey[paymentID]].Status = status
+}
@@ -0,0 +1,170 @@
+package psp
+
+import (
+	"context"
+	"errors"
+	"expvar"
+	"fmt"
+	"log/slog"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/kafka"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// Method is the payment_method name of payments through the provider.
+const Method = "psp"
+
+// metrics counts the charges the provider reported as succeeded after they
+// were settled here as failed.
+var metrics = expvar.NewMap("psp")
+
+// Provider records psp payments as pending external charges; the Worker and
+// the Webhook settle them.
+type Provider struct{}
+
+func (Provider) Pay(ctx context.Context, q db.Querier, c method.Charge) error {
+	ids := make([]int64, 0, len(c.BonusSpends))
+	amounts := make([]int64, 0, len(c.BonusSpends))
+	for _, s := range c.BonusSpends {
+		ids = append(ids, s.GrantID)
+		amounts = append(amounts, s.Amount)
+	}
+	n, err := q.InsertExternalCharge(ctx, db.InsertExternalChargeParams{
+		PaymentID:         c.PaymentID,
+		OrderID:           c.OrderID,
+		UserID:            c.UserID,
+		Method:            Method,
+		Amount:            c.Amount,
+		Bonus:             c.Bonus,
+		Fee:               c.Fee,
+		BonusGrantIds:     ids,
+		BonusGrantAmounts: amounts,
+		CorrelationID:     c.CorrelationID,
+	})
+	if err != nil {
+		return fmt.Errorf("record external charge: %w", err)
+	}
+	if n != 1 {
+		return fmt.Errorf("%w: payment already recorded", method.ErrDeclined)
+	}
+	return method.ErrPending
+}
+
+// outcome is how a charge ended at the provider.
+type outcome struct {
+	// chargeID is the provider's id of the charge, if known.
+	chargeID string
+	ok       bool
+	// reason says why a failed charge failed.
+	reason string
+}
+
+// outcomeOf returns the outcome of a final charge.
+func outcomeOf(ch Charge) outcome {
+	o := outcome{chargeID: ch.ID, ok: ch.Status == StatusSucceeded}
+	if !o.ok {
+		o.reason = ch.FailureMessage
+		if o.reason == "" {
+			o.reason = "charge " + ch.Status
+		}
+	}
+	return o
+}
+
+// settle finishes the pending external charge paymentID with o: a success
+// records the payment, a failure gives the held bonus back. Either way the
+// PaymentResult is written to topic through the outbox. Run it in a
+// transaction. It returns false when the charge was already settled; a
+// success of a charge settled as failed is then recorded and logged at error
+// level, as the customer paid for nothing and is owed a refund.
+func settle(ctx context.Context, q db.Querier, topic string, paymentID pgtype.UUID, o outcome) (bool, error) {
+	logger := slog.Default().With("service", "payments-service", "component", "psp")
+	ch, err := q.LockPendingExternalCharge(ctx, paymentID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if !o.ok {
+			return false, nil
+		}
+		n, err := q.MarkExternalChargeSucceededAfterFailure(ctx, db.MarkExternalChargeSucceededAfterFailureParams{
+			ProviderChargeID: pgtype.Text{String: o.chargeID, Valid: o.chargeID != ""},
+			PaymentID:        paymentID,
+		})
+		if err != nil {
+			return false, fmt.Errorf("mark external charge succeeded after failure: %w", err)
+		}
+		if n > 0 {
+			metrics.Add("succeeded_after_failure", 1)
+			logger.ErrorContext(ctx, "external charge succeeded at the provider after it failed, refund it", "payment_id", uuid.UUID(paymentID.Bytes).String(), "charge_id", o.chargeID)
+		}
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("lock external charge: %w", err)
+	}
+
+	finished := db.FinishExternalChargeParams{
+		Status:           "succeeded",
+		ProviderChargeID: pgtype.Text{String: o.chargeID, Valid: o.chargeID != ""},
+		PaymentID:        paymentID,
+	}
+	status := eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS
+	fee := ch.Fee
+	if o.ok {
+		// The operation is keyed by the payment id, so it is only ever
+		// recorded once.
+		if _, err := q.RecordExternalPayment(ctx, db.RecordExternalPaymentParams{
+			PaymentID: ch.PaymentID,
+			OrderID:   ch.OrderID,
+			UserID:    ch.UserID,
+			Amount:    ch.Amount,
+			Fee:       ch.Fee,
+			Bonus:     ch.Bonus,
+			Method:    ch.Method,
+		}); err != nil {
+			return false, fmt.Errorf("record external payment: %w", err)
+		}
+	} else {
+		if len(ch.BonusGrantIds) > 0 {
+			if err := q.RestoreBonusGrants(ctx, db.RestoreBonusGrantsParams{Ids: ch.BonusGrantIds, Amounts: ch.BonusGrantAmounts}); err != nil {
+				return false, fmt.Errorf("restore bonus grants: %w", err)
+			}
+		}
+		finished.Status = "failed"
+		finished.FailureReason = pgtype.Text{String: o.reason, Valid: true}
+		status = eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED
+		fee = 0
+	}
+	if err := q.FinishExternalCharge(ctx, finished); err != nil {
+		return false, fmt.Errorf("finish external charge: %w", err)
+	}
+
+	orderID := uuid.UUID(ch.OrderID.Bytes)
+	// The initial full payment of an order is keyed by the order id and
+	// reported without a payment id, as in PaymentRequested.
+	var paymentIDText string
+	if ch.PaymentID.Bytes != ch.OrderID.Bytes {
+		paymentIDText = uuid.UUID(ch.PaymentID.Bytes).String()
+	}
+	if err := kafka.EnqueuePaymentResult(ctx, q, topic, &eventsv1.PaymentResult{
+		EventId:       uuid.NewString(),
+		OccurredAt:    timestamppb.Now(),
+		OrderId:       orderID.String(),
+		UserId:        ch.UserID,
+		Status:        status,
+		Reason:        o.reason,
+		PaymentId:     paymentIDText,
+		Amount:        ch.Amount,
+		Fee:           fee,
+		CorrelationId: ch.CorrelationID,
+	}); err != nil {
+		return false, err
+	}
+	logger.InfoContext(ctx, "external charge settled", "order_id", orderID.String(), "user_id", ch.UserID, "succeeded", o.ok, "reason", o.reason)
+	return true, nil
+}
@@ -0,0 +1,103 @@
+package psp
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+// webhookTolerance bounds how old a webhook signature may be.
+const webhookTolerance = 5 * time.Minute
+
+// maxWebhookBody caps the webhook body read.
+const maxWebhookBody = 1 << 20
+
+// event is the body of a provider webhook. Charge events carry the charge
+// as data.object.
+type event struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object Charge `json:"object"`
+	} `json:"data"`
+}
+
+// Webhook settles external charges from the provider's charge.* events. The
+// body must be signed with one of keys in the signature.Header header.
+// Events the provider should not send again are answered 200, including
+// those of charges not yet final or already settled; a failure to settle is
+// answered 500 so the provider retries.
+type Webhook struct {
+	shards repo.ShardedRepository
+	keys   *signature.Keyring
+	topic  string
+	now    func() time.Time
+}
+
+// NewWebhook builds the webhook handler; PaymentResults go to topic.
+func NewWebhook(shards repo.ShardedRepository, keys *signature.Keyring, topic string) *Webhook {
+	return &Webhook{shards: shards, keys: keys, topic: topic, now: time.Now}
+}
+
+func (h *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := slog.Default().With("service", "payments-service", "component", "psp")
+	ctx := r.Context()
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err := h.keys.Verify(r.Header.Get(signature.Header), body, h.now(), webhookTolerance); err != nil {
+		logger.WarnContext(ctx, "psp webhook signature rejected", "err", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	var ev event
+	if err := json.Unmarshal(body, &ev); err != nil {
+		http.Error(w, "invalid event", http.StatusBadRequest)
+		return
+	}
+	ch := ev.Data.Object
+	if !strings.HasPrefix(ev.Type, "charge.") || !ch.Final() {
+		logger.DebugContext(ctx, "psp webhook ignored", "event_id", ev.ID, "type", ev.Type, "status", ch.Status)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	paymentID, err := uuid.Parse(ch.Reference)
+	userID := ch.Metadata["user_id"]
+	if err != nil || userID == "" {
+		logger.WarnContext(ctx, "psp webhook charge without reference", "event_id", ev.ID, "charge_id", ch.ID)
+		http.Error(w, "charge has no payment reference", http.StatusBadRequest)
+		return
+	}
+
+	var settled bool
+	err = h.shards.For(userID).InTx(ctx, func(q db.Querier) error {
+		var err error
+		settled, err = settle(ctx, q, h.topic, pgtype.UUID{Bytes: paymentID, Valid: true}, outcomeOf(ch))
+		return err
+	})
+	if err != nil {
+		logger.ErrorContext(ctx, "psp webhook settle failed", "err", err, "event_id", ev.ID, "payment_id", paymentID.String())
+		http.Error(w, "internal error", http.StatusInternalServerError)
+		return
+	}
+	logger.InfoContext(ctx, "psp webhook handled", "event_id", ev.ID, "type", ev.Type, "payment_id", paymentID.String(), "settled", settled)
+	w.WriteHeader(http.StatusOK)
+}
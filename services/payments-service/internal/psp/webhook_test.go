@@ -0,0 +1,94 @@
+package psp
+
+import (
+	"expvar"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+
+	"github.com/ilyaytrewq/payments-service/pkg/signature"
+)
+
+func TestWebhook(t *testing.T) {
+	f := newFakeRepo()
+	keys, err := signature.ParseKeyring("k1:whsec")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewWebhook(f, keys, "results")
+	id := pay(t, f, 500)
+
+	send := func(body, sig string) int {
+		req := httptest.NewRequest(http.MethodPost, "/psp/webhook", strings.NewReader(body))
+		req.Header.Set(signature.Header, sig)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	event := func(status string) string {
+		return fmt.Sprintf(`{"id":"evt_1","type":"charge.%s","data":{"object":{"id":"ch_9","status":%q,"reference":%q,"failure_message":"insufficient funds","metadata":{"user_id":"u-1"}}}}`, status, status, id)
+	}
+	signed := func(body string) string { return keys.Sign([]byte(body), time.Now()) }
+
+	failed := event(StatusFailed)
+	if code := send(failed, "t=1,kid=k1,v1=00"); code != http.StatusUnauthorized {
+		t.Fatalf("forged webhook answered %d, want 401", code)
+	}
+	if code := send(`{"type":"charge.failed","data":{"object":{"status":"failed"}}}`, signed(`{"type":"charge.failed","data":{"object":{"status":"failed"}}}`)); code != http.StatusBadRequest {
+		t.Fatalf("webhook without a reference answered %d, want 400", code)
+	}
+	if code := send(event(StatusPending), signed(event(StatusPending))); code != http.StatusOK || len(f.outbox) != 0 {
+		t.Fatalf("pending webhook answered %d with %d results, want 200 and none", code, len(f.outbox))
+	}
+
+	// Redelivery of a settled charge is acknowledged and changes nothing.
+	for range 2 {
+		if code := send(failed, signed(failed)); code != http.StatusOK {
+			t.Fatalf("failed webhook answered %d, want 200", code)
+		}
+	}
+	results := f.results(t)
+	if len(results) != 1 || results[0].GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED || results[0].GetReason() != "insufficient funds" {
+		t.Fatalf("results = %v, want one declined with the provider's message", results)
+	}
+	for _, ch := range f.charges {
+		if ch.Status != "failed" || ch.ProviderChargeID.String != "ch_9" {
+			t.Fatalf("charge = %+v, want failed as ch_9", ch)
+		}
+	}
+	if f.grants[7] != 250 {
+		t.Fatalf("grant 7 got %d back, want 250", f.grants[7])
+	}
+
+	// A success of the failed charge is not settled again but recorded for
+	// a refund, once.
+	late := func() int64 {
+		if v, ok := metrics.Get("succeeded_after_failure").(*expvar.Int); ok {
+			return v.Value()
+		}
+		return 0
+	}
+	before := late()
+	succeeded := event(StatusSucceeded)
+	for range 2 {
+		if code := send(succeeded, signed(succeeded)); code != http.StatusOK {
+			t.Fatalf("succeeded webhook answered %d, want 200", code)
+		}
+	}
+	if len(f.outbox) != 1 || len(f.ops) != 0 {
+		t.Fatalf("%d results and %d payments after the late success, want it not settled", len(f.outbox), len(f.ops))
+	}
+	for _, ch := range f.charges {
+		if ch.Status != "failed" || !ch.ProviderSucceededAt.Valid {
+			t.Fatalf("charge = %+v, want failed and marked as succeeded at the provider", ch)
+		}
+	}
+	if got := late(); got != before+1 {
+		t.Fatalf("succeeded_after_failure = %d, want %d", got, before+1)
+	}
+}
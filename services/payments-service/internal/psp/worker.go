@@ -0,0 +1,161 @@
+package psp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+const workerBatchSize = 100
+
+// Worker submits the pending external charges of one shard to the provider
+// and reconciles those whose outcome did not arrive by webhook: a charge
+// still pending reconcileAfter after it was last checked is looked up, and
+// one pending timeout after it was created is canceled and fails. A charge
+// whose creation got no answer is created again until the provider answers,
+// however long that takes, so it is never failed while the provider may
+// hold it. Several replicas may run it at once: creating a charge is
+// idempotent and settling locks it.
+type Worker struct {
+	repo           repo.PaymentsRepository
+	client         *Client
+	topic          string
+	interval       time.Duration
+	reconcileAfter time.Duration
+	timeout        time.Duration
+	now            func() time.Time
+}
+
+// NewWorker builds a worker that writes the PaymentResults to topic and
+// looks for work every interval.
+func NewWorker(r repo.PaymentsRepository, c *Client, topic string, interval, reconcileAfter, timeout time.Duration) *Worker {
+	return &Worker{repo: r, client: c, topic: topic, interval: interval, reconcileAfter: reconcileAfter, timeout: timeout, now: time.Now}
+}
+
+func (w *Worker) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "payments-service", "component", "psp")
+	logger.Info("external charge worker started", "interval", w.interval.String(), "reconcile_after", w.reconcileAfter.String(), "timeout", w.timeout.String())
+	t := time.NewTicker(w.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := w.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("external charge sweep failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("external charge worker stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce goes through one batch of due charges and returns how many of
+// them it settled. A charge the provider cannot be asked about is only
+// logged and retried on the next run.
+func (w *Worker) RunOnce(ctx context.Context) (int, error) {
+	logger := slog.Default().With("service", "payments-service", "component", "psp")
+	now := w.now()
+	charges, err := w.repo.Q().ListPendingExternalCharges(ctx, db.ListPendingExternalChargesParams{
+		CheckedBefore: pgtype.Timestamptz{Time: now.Add(-w.reconcileAfter), Valid: true},
+		BatchSize:     workerBatchSize,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("list pending external charges: %w", err)
+	}
+	settled := 0
+	for _, ch := range charges {
+		done, err := w.process(ctx, ch, now)
+		if err != nil {
+			if ctx.Err() != nil {
+				return settled, ctx.Err()
+			}
+			logger.WarnContext(ctx, "external charge check failed", "err", err, "payment_id", uuid.UUID(ch.PaymentID.Bytes).String(), "attempts", ch.Attempts)
+			if err := w.repo.Q().TouchExternalCharge(ctx, ch.PaymentID); err != nil {
+				return settled, fmt.Errorf("touch external charge: %w", err)
+			}
+			continue
+		}
+		if done {
+			settled++
+		}
+	}
+	return settled, nil
+}
+
+// process moves ch along and reports whether it was settled.
+func (w *Worker) process(ctx context.Context, ch db.ExternalCharge, now time.Time) (bool, error) {
+	expired := now.Sub(ch.CreatedAt.Time) >= w.timeout
+
+	var (
+		pc  Charge
+		err error
+	)
+	switch {
+	case !ch.ProviderChargeID.Valid:
+		pc, err = w.client.Create(ctx, uuid.UUID(ch.PaymentID.Bytes).String(), uuid.UUID(ch.OrderID.Bytes).String(), ch.UserID, ch.Amount-ch.Bonus+ch.Fee)
+		if errors.Is(err, ErrRejected) {
+			return w.settle(ctx, ch.PaymentID, outcome{ok: false, reason: err.Error()})
+		}
+		// The provider may have created the charge without answering, so
+		// it is not failed here even after the timeout: creating it again
+		// with the same idempotency key returns it, and it is canceled then.
+		if err != nil {
+			return false, err
+		}
+		if err := w.repo.Q().SetExternalChargeSubmitted(ctx, db.SetExternalChargeSubmittedParams{ProviderChargeID: pc.ID, PaymentID: ch.PaymentID}); err != nil {
+			return false, fmt.Errorf("mark external charge submitted: %w", err)
+		}
+		if expired && !pc.Final() {
+			return w.cancel(ctx, ch.PaymentID, pc.ID)
+		}
+	case expired:
+		return w.cancel(ctx, ch.PaymentID, ch.ProviderChargeID.String)
+	default:
+		pc, err = w.client.Get(ctx, ch.ProviderChargeID.String)
+		if err != nil {
+			return false, err
+		}
+	}
+
+	if !pc.Final() {
+		return false, w.repo.Q().TouchExternalCharge(ctx, ch.PaymentID)
+	}
+	return w.settle(ctx, ch.PaymentID, outcomeOf(pc))
+}
+
+// cancel cancels the timed out charge chargeID at the provider and fails
+// it, unless it became final in the meantime.
+func (w *Worker) cancel(ctx context.Context, paymentID pgtype.UUID, chargeID string) (bool, error) {
+	pc, err := w.client.Cancel(ctx, chargeID)
+	if err != nil {
+		// A charge that became final in the meantime cannot be canceled.
+		if pc, gerr := w.client.Get(ctx, chargeID); gerr == nil && pc.Final() {
+			return w.settle(ctx, paymentID, outcomeOf(pc))
+		}
+		return false, err
+	}
+	if pc.Status == StatusCanceled || !pc.Final() {
+		return w.settle(ctx, paymentID, outcome{chargeID: chargeID, reason: fmt.Sprintf("no outcome from the payment provider within %s", w.timeout)})
+	}
+	return w.settle(ctx, paymentID, outcomeOf(pc))
+}
+
+func (w *Worker) settle(ctx context.Context, paymentID pgtype.UUID, o outcome) (bool, error) {
+	var done bool
+	err := w.repo.InTx(ctx, func(q db.Querier) error {
+		var err error
+		done, err = settle(ctx, q, w.topic, paymentID, o)
+		return err
+	})
+	return done, err
+}
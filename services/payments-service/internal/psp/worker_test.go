@@ -0,0 +1,156 @@
+package psp
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	eventsv1 "github.com/ilyaytrewq/payments-service/gen/go/events/v1"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/method"
+)
+
+// pay records a pending psp charge of amount, half of it paid by bonus grant
+// 7, and returns its payment id.
+func pay(t *testing.T, f *fakeRepo, amount int64) string {
+	t.Helper()
+	id := uuid.New()
+	err := (Provider{}).Pay(context.Background(), f, method.Charge{
+		PaymentID:     pgtype.UUID{Bytes: id, Valid: true},
+		OrderID:       pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		UserID:        "u-1",
+		Amount:        amount,
+		Bonus:         amount / 2,
+		Fee:           10,
+		BonusSpends:   []method.BonusSpend{{GrantID: 7, Amount: amount / 2}},
+		CorrelationID: "req-1",
+	})
+	if !errors.Is(err, method.ErrPending) {
+		t.Fatalf("Pay() error = %v, want ErrPending", err)
+	}
+	return id.String()
+}
+
+func TestProviderPay(t *testing.T) {
+	f := newFakeRepo()
+	id := uuid.MustParse(pay(t, f, 500))
+	ch := f.charges[pgtype.UUID{Bytes: id, Valid: true}]
+	if ch.Method != Method || ch.Bonus != 250 || len(ch.BonusGrantIds) != 1 || ch.BonusGrantAmounts[0] != 250 {
+		t.Fatalf("charge = %+v, want a psp charge holding 250 of grant 7", ch)
+	}
+	err := (Provider{}).Pay(context.Background(), f, method.Charge{PaymentID: ch.PaymentID, OrderID: ch.OrderID, UserID: "u-1", Amount: 500})
+	if !errors.Is(err, method.ErrDeclined) {
+		t.Fatalf("second Pay() error = %v, want ErrDeclined", err)
+	}
+}
+
+func TestWorkerSettlesOnPoll(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	psp, client := newFakePSP(t)
+	w := NewWorker(f, client, "results", time.Second, time.Minute, time.Hour)
+	id := pay(t, f, 500)
+
+	// Submitted for the due amount and still pending.
+	if n, err := w.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("RunOnce() = %d, %v; want 0, nil", n, err)
+	}
+	if ch := psp.charges["ch_1"]; ch == nil || ch.Amount != 260 || ch.Reference != id || ch.Metadata["user_id"] != "u-1" {
+		t.Fatalf("provider charge = %+v, want 260 referencing %s", ch, id)
+	}
+	psp.set(id, StatusSucceeded)
+
+	// Not checked again before reconcileAfter.
+	if n, err := w.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("RunOnce() before reconcileAfter = %d, %v; want 0, nil", n, err)
+	}
+	w.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RunOnce() after reconcileAfter = %d, %v; want 1, nil", n, err)
+	}
+	if len(f.ops) != 1 || f.ops[0].Method != Method || f.ops[0].Bonus != 250 || f.ops[0].Fee != 10 {
+		t.Fatalf("ops = %+v, want one psp payment", f.ops)
+	}
+	results := f.results(t)
+	if len(results) != 1 || results[0].GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_SUCCESS ||
+		results[0].GetPaymentId() != id || results[0].GetFee() != 10 || results[0].GetCorrelationId() != "req-1" {
+		t.Fatalf("results = %v, want one success", results)
+	}
+	if f.outbox[0].KafkaKey != results[0].GetOrderId() || f.outbox[0].Topic != "results" {
+		t.Fatalf("outbox row = %+v, want keyed by the order on results", f.outbox[0])
+	}
+}
+
+func TestWorkerFailsCharges(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	psp, client := newFakePSP(t)
+	w := NewWorker(f, client, "results", time.Second, time.Minute, time.Hour)
+
+	// Rejected on creation: 2000/2 + 10 is above the provider's limit.
+	rejected := pay(t, f, 2000)
+	stuck := pay(t, f, 500)
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RunOnce() = %d, %v; want the rejected charge settled", n, err)
+	}
+
+	// Still pending at the timeout: canceled at the provider.
+	w.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RunOnce() after the timeout = %d, %v; want 1, nil", n, err)
+	}
+	if len(psp.canceled) != 1 {
+		t.Fatalf("canceled = %v, want the stuck charge", psp.canceled)
+	}
+	if len(f.ops) != 0 {
+		t.Fatalf("ops = %+v, want no payments", f.ops)
+	}
+	if f.grants[7] != 1000+250 {
+		t.Fatalf("grant 7 got %d back, want 1250", f.grants[7])
+	}
+	results := f.results(t)
+	if len(results) != 2 {
+		t.Fatalf("results = %v, want two", results)
+	}
+	for i, id := range []string{rejected, stuck} {
+		r := results[i]
+		if r.GetPaymentId() != id || r.GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED || r.GetFee() != 0 || r.GetReason() == "" {
+			t.Fatalf("result %d = %v, want %s declined", i, r, id)
+		}
+	}
+}
+
+func TestWorkerDoesNotFailUnansweredCreate(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRepo()
+	psp, client := newFakePSP(t)
+	w := NewWorker(f, client, "results", time.Second, time.Minute, time.Hour)
+	id := pay(t, f, 500)
+
+	// The provider creates the charge but the answer is lost, also past the
+	// timeout: the charge may be paid, so it is not failed.
+	psp.dropCreated = true
+	w.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if n, err := w.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("RunOnce() without an answer = %d, %v; want 0, nil", n, err)
+	}
+	if len(f.outbox) != 0 || f.grants[7] != 0 {
+		t.Fatalf("charge settled without an answer from the provider: %d results, grant 7 got %d back", len(f.outbox), f.grants[7])
+	}
+
+	// Created again under the same key, the charge is found and canceled.
+	psp.dropCreated = false
+	if n, err := w.RunOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RunOnce() once answered = %d, %v; want 1, nil", n, err)
+	}
+	if len(psp.charges) != 1 || len(psp.canceled) != 1 || psp.canceled[0] != "ch_1" {
+		t.Fatalf("provider charges = %v, canceled = %v, want ch_1 created once and canceled", psp.charges, psp.canceled)
+	}
+	results := f.results(t)
+	if len(results) != 1 || results[0].GetPaymentId() != id || results[0].GetStatus() != eventsv1.PaymentResultStatus_PAYMENT_RESULT_STATUS_FAIL_DECLINED {
+		t.Fatalf("results = %v, want %s declined", results, id)
+	}
+}
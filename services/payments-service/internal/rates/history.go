@@ -0,0 +1,128 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// Recorder keeps exchange_rates up to date with the provider, so statements
+// can convert past entries at the rates of their time. Every provider table
+// is recorded once, under its AsOf, so several replicas may run it at once.
+type Recorder struct {
+	q        db.Querier
+	provider Provider
+	base     money.Currency
+	interval time.Duration
+}
+
+// NewRecorder builds a recorder that stores the prices of provider in base
+// every interval.
+func NewRecorder(q db.Querier, provider Provider, base money.Currency, interval time.Duration) *Recorder {
+	slog.Default().With("service", "payments-service", "component", "rates").Info("rates recorder initialized", "interval", interval.String())
+	return &Recorder{q: q, provider: provider, base: base, interval: interval}
+}
+
+func (r *Recorder) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "payments-service", "component", "rates")
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := r.RecordOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("rates recording failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("rates recorder stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RecordOnce stores the price of every currency of the current table and
+// returns how many it stored.
+func (r *Recorder) RecordOnce(ctx context.Context) (int, error) {
+	table, err := r.provider.Table(ctx)
+	if err != nil {
+		return 0, err
+	}
+	asOf := pgtype.Timestamptz{Time: table.AsOf, Valid: true}
+	currencies := table.Currencies()
+	if table.Base != r.base {
+		currencies = append(currencies, table.Base)
+	}
+	n := 0
+	for _, c := range currencies {
+		if c == r.base {
+			continue
+		}
+		price, err := table.Rate(c, r.base)
+		if err != nil {
+			return n, err
+		}
+		err = r.q.RecordExchangeRate(ctx, db.RecordExchangeRateParams{Currency: string(c), AsOf: asOf, Price: price.FloatString(12)})
+		if err != nil {
+			return n, fmt.Errorf("record %s rate: %w", c, err)
+		}
+		n++
+	}
+	return n, nil
+}
+
+// History is the recorded price of one currency in base over a span of
+// time, oldest first.
+type History struct {
+	base     money.Currency
+	currency money.Currency
+	asOf     []time.Time
+	prices   []*big.Rat
+}
+
+// LoadHistory reads the prices of currency in base in effect from since to
+// until. Converting to base itself needs no history.
+func LoadHistory(ctx context.Context, q db.Querier, base, currency money.Currency, since, until time.Time) (History, error) {
+	h := History{base: base, currency: currency}
+	if currency == base {
+		return h, nil
+	}
+	rows, err := q.ListExchangeRates(ctx, db.ListExchangeRatesParams{
+		Currency: string(currency),
+		Since:    pgtype.Timestamptz{Time: since, Valid: true},
+		Until:    pgtype.Timestamptz{Time: until, Valid: true},
+	})
+	if err != nil {
+		return History{}, err
+	}
+	for _, row := range rows {
+		p, err := parsePrice(row.Price)
+		if err != nil {
+			return History{}, fmt.Errorf("recorded %s rate: %w", currency, err)
+		}
+		h.asOf = append(h.asOf, row.AsOf.Time)
+		h.prices = append(h.prices, p)
+	}
+	return h, nil
+}
+
+// At returns the table in effect at t: the latest recorded price at or
+// before t. ok is false when t is older than the history.
+func (h History) At(t time.Time) (table Table, ok bool) {
+	if h.currency == h.base {
+		return Table{Base: h.base, Prices: map[money.Currency]*big.Rat{}, AsOf: t}, true
+	}
+	i := sort.Search(len(h.asOf), func(i int) bool { return h.asOf[i].After(t) })
+	if i == 0 {
+		return Table{}, false
+	}
+	return Table{Base: h.base, Prices: map[money.Currency]*big.Rat{h.currency: h.prices[i-1]}, AsOf: h.asOf[i-1]}, true
+}
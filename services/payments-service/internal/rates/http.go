@@ -0,0 +1,159 @@
+package rates
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// feed is the response of the rates URL, in the format of the common
+// open rate APIs (frankfurter, exchangerate.host): units of each currency
+// per one unit of base.
+type feed struct {
+	Base  string                 `json:"base"`
+	Rates map[string]json.Number `json:"rates"`
+}
+
+// HTTP fetches rates from a URL and caches them for ttl. When a refresh
+// fails the last table is served until it is maxStale old, so a flaky feed
+// does not take conversions down. One call at a time fetches; the others
+// are served the cached table meanwhile.
+type HTTP struct {
+	url      string
+	base     money.Currency
+	client   *http.Client
+	ttl      time.Duration
+	maxStale time.Duration
+	now      func() time.Time
+
+	mu        sync.Mutex
+	table     Table
+	fetchedAt time.Time
+	// refresh is the fetch in flight, if any.
+	refresh *refresh
+
+	logger *slog.Logger
+}
+
+// refresh is one fetch of the feed; err is set before done is closed when
+// the fetch failed and there was no table to fall back on.
+type refresh struct {
+	done chan struct{}
+	err  error
+}
+
+func NewHTTP(url string, base money.Currency, client *http.Client, ttl, maxStale time.Duration) *HTTP {
+	return &HTTP{
+		url:      url,
+		base:     base,
+		client:   client,
+		ttl:      ttl,
+		maxStale: maxStale,
+		now:      time.Now,
+		logger:   slog.Default().With("service", "payments-service", "component", "rates"),
+	}
+}
+
+func (h *HTTP) Table(ctx context.Context) (Table, error) {
+	for {
+		h.mu.Lock()
+		now := h.now()
+		if !h.fetchedAt.IsZero() && now.Sub(h.fetchedAt) < h.ttl {
+			t := h.table
+			h.mu.Unlock()
+			return t, nil
+		}
+		r := h.refresh
+		if r == nil {
+			r = &refresh{done: make(chan struct{})}
+			h.refresh = r
+			h.mu.Unlock()
+			return h.fetchAndStore(ctx, r, now)
+		}
+		// Another call is fetching. A table within maxStale is good enough
+		// meanwhile; without one, wait for the fetch.
+		if !h.fetchedAt.IsZero() && now.Sub(h.fetchedAt) < h.maxStale {
+			t := h.table
+			h.mu.Unlock()
+			return t, nil
+		}
+		h.mu.Unlock()
+		select {
+		case <-r.done:
+			if r.err != nil {
+				return Table{}, r.err
+			}
+		case <-ctx.Done():
+			return Table{}, ctx.Err()
+		}
+	}
+}
+
+// fetchAndStore runs the refresh r without holding mu, so a slow feed does
+// not block calls that can be served from the cache. The fetch outlives the
+// cancellation of ctx, as other calls may be waiting on it; the client
+// timeout bounds it.
+func (h *HTTP) fetchAndStore(ctx context.Context, r *refresh, now time.Time) (Table, error) {
+	t, err := h.fetch(context.WithoutCancel(ctx))
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	defer close(r.done)
+	h.refresh = nil
+	if err != nil {
+		if !h.fetchedAt.IsZero() && now.Sub(h.fetchedAt) < h.maxStale {
+			h.logger.WarnContext(ctx, "rates refresh failed, serving cached rates", "err", err, "as_of", h.table.AsOf)
+			return h.table, nil
+		}
+		r.err = err
+		return Table{}, err
+	}
+	h.table, h.fetchedAt = t, now
+	h.logger.InfoContext(ctx, "rates refreshed", "currencies", len(t.Prices))
+	return t, nil
+}
+
+func (h *HTTP) fetch(ctx context.Context) (Table, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, h.url, nil)
+	if err != nil {
+		return Table{}, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return Table{}, fmt.Errorf("fetch rates: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Table{}, fmt.Errorf("fetch rates: %s", resp.Status)
+	}
+	var f feed
+	if err := json.NewDecoder(resp.Body).Decode(&f); err != nil {
+		return Table{}, fmt.Errorf("decode rates: %w", err)
+	}
+	if base, err := money.ParseCurrency(f.Base); err != nil || base != h.base {
+		return Table{}, fmt.Errorf("rates feed base %q, want %s", f.Base, h.base)
+	}
+
+	t := Table{Base: h.base, Prices: map[money.Currency]*big.Rat{}, AsOf: h.now()}
+	for code, v := range f.Rates {
+		// Currencies money does not know cannot be converted anyway.
+		c, err := money.ParseCurrency(code)
+		if err != nil || c == h.base {
+			continue
+		}
+		perBase, err := parsePrice(v.String())
+		if err != nil {
+			return Table{}, fmt.Errorf("rates feed %s: %w", code, err)
+		}
+		t.Prices[c] = perBase.Inv(perBase)
+	}
+	return t, nil
+}
@@ -0,0 +1,121 @@
+// Package rates provides currency exchange rates and converts amounts with
+// them. Rates come from a Provider: a static table from config or an HTTP
+// feed cached in memory. A Recorder keeps their history in the database for
+// converting past amounts.
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+var ErrNoRate = errors.New("no exchange rate")
+
+// Table is a snapshot of exchange rates against one base currency.
+type Table struct {
+	Base money.Currency
+	// Prices is what one major unit of each currency costs in major units
+	// of Base. Base itself is implied at 1.
+	Prices map[money.Currency]*big.Rat
+	AsOf   time.Time
+}
+
+// Provider returns the current rates.
+type Provider interface {
+	Table(ctx context.Context) (Table, error)
+}
+
+// Currencies lists the currencies of t other than the base, sorted.
+func (t Table) Currencies() []money.Currency {
+	out := make([]money.Currency, 0, len(t.Prices))
+	for c := range t.Prices {
+		if c != t.Base {
+			out = append(out, c)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
+
+func (t Table) price(c money.Currency) (*big.Rat, bool) {
+	if c == t.Base {
+		return big.NewRat(1, 1), true
+	}
+	p, ok := t.Prices[c]
+	return p, ok
+}
+
+// Rate is how many major units of to one major unit of from buys.
+func (t Table) Rate(from, to money.Currency) (*big.Rat, error) {
+	pf, ok := t.price(from)
+	if !ok {
+		return nil, fmt.Errorf("%w for %s", ErrNoRate, from)
+	}
+	pt, ok := t.price(to)
+	if !ok {
+		return nil, fmt.Errorf("%w for %s", ErrNoRate, to)
+	}
+	return new(big.Rat).Quo(pf, pt), nil
+}
+
+// Convert converts amount minor units of from into minor units of to,
+// rounding half away from zero. It also returns the rate used.
+func (t Table) Convert(amount int64, from, to money.Currency) (int64, *big.Rat, error) {
+	rate, err := t.Rate(from, to)
+	if err != nil {
+		return 0, nil, err
+	}
+	if from.Exponent() < 0 || to.Exponent() < 0 {
+		return 0, nil, fmt.Errorf("%w: %s to %s", money.ErrUnknownCurrency, from, to)
+	}
+	v := new(big.Rat).Mul(new(big.Rat).SetInt64(amount), rate)
+	v.Mul(v, new(big.Rat).SetFrac(pow10(to.Exponent()), pow10(from.Exponent())))
+	converted, ok := round(v)
+	if !ok {
+		return 0, nil, fmt.Errorf("convert %d %s to %s: %w", amount, from, to, money.ErrTooLarge)
+	}
+	return converted, rate, nil
+}
+
+// FormatRate renders a rate as a decimal with up to 8 fractional digits and
+// no trailing zeros.
+func FormatRate(r *big.Rat) string {
+	return strings.TrimSuffix(strings.TrimRight(r.FloatString(8), "0"), ".")
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// round rounds v half away from zero; ok is false when it does not fit in
+// int64.
+func round(v *big.Rat) (int64, bool) {
+	num := new(big.Int).Abs(v.Num())
+	q, r := new(big.Int).QuoRem(num, v.Denom(), new(big.Int))
+	if r.Lsh(r, 1).Cmp(v.Denom()) >= 0 {
+		q.Add(q, big.NewInt(1))
+	}
+	if v.Sign() < 0 {
+		q.Neg(q)
+	}
+	if !q.IsInt64() {
+		return 0, false
+	}
+	return q.Int64(), true
+}
+
+// parsePrice parses a positive decimal rate.
+func parsePrice(s string) (*big.Rat, error) {
+	p, ok := new(big.Rat).SetString(s)
+	if !ok || p.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q: want a positive decimal", s)
+	}
+	return p, nil
+}
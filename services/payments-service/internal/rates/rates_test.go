@@ -0,0 +1,236 @@
+package rates
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+func TestConvert(t *testing.T) {
+	s, err := ParseStatic(money.RUB, " USD=90 , EUR=100,JPY=0.6")
+	if err != nil {
+		t.Fatalf("ParseStatic: %v", err)
+	}
+	table, _ := s.Table(context.Background())
+
+	tests := []struct {
+		amount   int64
+		from, to money.Currency
+		want     int64
+		rate     string
+	}{
+		{10000, money.USD, money.RUB, 900000, "90"},         // $100 = 9000 RUB
+		{900000, money.RUB, money.USD, 10000, "0.01111111"}, // and back
+		{100, money.RUB, money.USD, 1, "0.01111111"},        // 1 RUB = 1.11 cents, rounded down
+		{150, money.RUB, money.USD, 2, "0.01111111"},        // 1.67 cents, rounded up
+		{-150, money.RUB, money.USD, -2, "0.01111111"},      // half away from zero
+		{10000, money.USD, money.JPY, 15000, "150"},         // JPY has no minor units
+		{1000, money.JPY, money.EUR, 600, "0.006"},
+		{500, money.RUB, money.RUB, 500, "1"},
+	}
+	for _, tt := range tests {
+		got, rate, err := table.Convert(tt.amount, tt.from, tt.to)
+		if err != nil || got != tt.want || FormatRate(rate) != tt.rate {
+			t.Fatalf("Convert(%d %s -> %s) = (%d, %v, %v), want (%d, %s)", tt.amount, tt.from, tt.to, got, rate, err, tt.want, tt.rate)
+		}
+	}
+
+	only, _ := ParseStatic(money.RUB, "USD=90")
+	table, _ = only.Table(context.Background())
+	if _, _, err := table.Convert(100, money.EUR, money.RUB); !errors.Is(err, ErrNoRate) {
+		t.Fatalf("Convert(EUR) err = %v, want ErrNoRate", err)
+	}
+	if got := table.Currencies(); len(got) != 1 || got[0] != money.USD {
+		t.Fatalf("Currencies() = %v", got)
+	}
+}
+
+func TestParseStaticErrors(t *testing.T) {
+	for _, spec := range []string{"USD", "XXX=1", "USD=0", "USD=-1", "USD=abc", "RUB=1"} {
+		if _, err := ParseStatic(money.RUB, spec); err == nil {
+			t.Fatalf("ParseStatic(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestHTTPCaches(t *testing.T) {
+	var calls atomic.Int32
+	var fail atomic.Bool
+	var body atomic.Value
+	body.Store(`{"base":"RUB","date":"2026-10-16","rates":{"USD":"0.0125","EUR":0.01,"XAU":0.0001}}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if fail.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		fmt.Fprint(w, body.Load())
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	h := NewHTTP(srv.URL, money.RUB, srv.Client(), time.Minute, time.Hour)
+	h.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	table, err := h.Table(ctx)
+	if err != nil {
+		t.Fatalf("Table: %v", err)
+	}
+	if rate, err := table.Rate(money.USD, money.RUB); err != nil || FormatRate(rate) != "80" {
+		t.Fatalf("USD rate = %v, %v; want 80", rate, err)
+	}
+	if len(table.Prices) != 2 {
+		t.Fatalf("prices = %v, want unknown XAU skipped", table.Prices)
+	}
+
+	now = now.Add(30 * time.Second)
+	if _, err := h.Table(ctx); err != nil || calls.Load() != 1 {
+		t.Fatalf("fresh Table: err %v, %d calls; want served from cache", err, calls.Load())
+	}
+
+	// A failed refresh serves the cached table until maxStale.
+	fail.Store(true)
+	now = now.Add(time.Minute)
+	if _, err := h.Table(ctx); err != nil || calls.Load() != 2 {
+		t.Fatalf("stale Table: err %v, %d calls; want cached table after one refresh", err, calls.Load())
+	}
+	now = now.Add(time.Hour)
+	if _, err := h.Table(ctx); err == nil {
+		t.Fatal("Table past maxStale succeeded, want the refresh error")
+	}
+
+	fail.Store(false)
+	body.Store(`{"base":"USD","rates":{"RUB":80}}`)
+	if _, err := h.Table(ctx); err == nil {
+		t.Fatal("Table with a foreign base succeeded, want an error")
+	}
+}
+
+func TestHTTPRefreshDoesNotBlockCachedReads(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) > 1 {
+			<-release
+		}
+		fmt.Fprint(w, `{"base":"RUB","rates":{"USD":0.0125}}`)
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var mu sync.Mutex
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	h := NewHTTP(srv.URL, money.RUB, srv.Client(), time.Minute, time.Hour)
+	h.now = func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	}
+	ctx := context.Background()
+	if _, err := h.Table(ctx); err != nil {
+		t.Fatalf("Table: %v", err)
+	}
+
+	mu.Lock()
+	now = now.Add(2 * time.Minute)
+	mu.Unlock()
+	go h.Table(ctx)
+	for calls.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The refresh hangs on the feed; the cached table is still served.
+	done := make(chan error, 1)
+	go func() {
+		_, err := h.Table(ctx)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil || calls.Load() != 2 {
+			t.Fatalf("Table during refresh: err %v, %d calls; want the cached table", err, calls.Load())
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Table blocked behind the refresh in flight")
+	}
+}
+
+// rateStore is an in-memory exchange_rates.
+type rateStore struct {
+	db.Querier
+	rows []db.RecordExchangeRateParams
+}
+
+func (s *rateStore) RecordExchangeRate(_ context.Context, arg db.RecordExchangeRateParams) error {
+	for _, r := range s.rows {
+		if r.Currency == arg.Currency && r.AsOf.Time.Equal(arg.AsOf.Time) {
+			return nil
+		}
+	}
+	s.rows = append(s.rows, arg)
+	return nil
+}
+
+func (s *rateStore) ListExchangeRates(_ context.Context, arg db.ListExchangeRatesParams) ([]db.ListExchangeRatesRow, error) {
+	var out []db.ListExchangeRatesRow
+	for _, r := range s.rows {
+		if r.Currency == arg.Currency && !r.AsOf.Time.After(arg.Until.Time) {
+			out = append(out, db.ListExchangeRatesRow{AsOf: r.AsOf, Price: r.Price})
+		}
+	}
+	slices.SortFunc(out, func(a, b db.ListExchangeRatesRow) int { return a.AsOf.Time.Compare(b.AsOf.Time) })
+	return out, nil
+}
+
+// tableAt is a provider whose table changes with the test.
+type tableAt struct{ table Table }
+
+func (p *tableAt) Table(context.Context) (Table, error) { return p.table, nil }
+
+func TestRecorderHistory(t *testing.T) {
+	day1 := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.Add(24 * time.Hour)
+	store := &rateStore{}
+	provider := &tableAt{Table{Base: money.RUB, Prices: map[money.Currency]*big.Rat{money.USD: big.NewRat(80, 1)}, AsOf: day1}}
+	r := NewRecorder(store, provider, money.RUB, time.Minute)
+	ctx := context.Background()
+
+	if n, err := r.RecordOnce(ctx); err != nil || n != 1 {
+		t.Fatalf("RecordOnce = %d, %v; want 1 rate", n, err)
+	}
+	r.RecordOnce(ctx)
+	provider.table = Table{Base: money.RUB, Prices: map[money.Currency]*big.Rat{money.USD: big.NewRat(100, 1)}, AsOf: day2}
+	r.RecordOnce(ctx)
+	if len(store.rows) != 2 {
+		t.Fatalf("recorded %v, want one row per provider table", store.rows)
+	}
+
+	h, err := LoadHistory(ctx, store, money.RUB, money.USD, day1, day2.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("LoadHistory: %v", err)
+	}
+	if _, ok := h.At(day1.Add(-time.Second)); ok {
+		t.Fatal("At before the history succeeded, want no rate")
+	}
+	for at, want := range map[time.Time]int64{day1: 125, day2.Add(-time.Second): 125, day2: 100, day2.Add(time.Hour): 100} {
+		table, ok := h.At(at)
+		if !ok {
+			t.Fatalf("At(%s) found no rate", at)
+		}
+		if got, _, err := table.Convert(10000, money.RUB, money.USD); err != nil || got != want {
+			t.Fatalf("100 RUB at %s = %d USD cents, %v; want %d", at, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package rates
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ilyaytrewq/payments-service/pkg/money"
+)
+
+// Static serves a fixed table from config.
+type Static struct {
+	table Table
+}
+
+// ParseStatic parses "USD=92.5,EUR=100.1": the price of one major unit of
+// each currency in base.
+func ParseStatic(base money.Currency, spec string) (*Static, error) {
+	t := Table{Base: base, Prices: map[money.Currency]*big.Rat{}, AsOf: time.Now()}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		code, value, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("rate %q: want CURRENCY=price", item)
+		}
+		c, err := money.ParseCurrency(code)
+		if err != nil {
+			return nil, fmt.Errorf("rate %q: %w", item, err)
+		}
+		if c == base {
+			return nil, fmt.Errorf("rate %q: %s is the base currency", item, c)
+		}
+		p, err := parsePrice(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("rate %q: %w", item, err)
+		}
+		t.Prices[c] = p
+	}
+	return &Static{table: t}, nil
+}
+
+func (s *Static) Table(context.Context) (Table, error) {
+	return s.table, nil
+}
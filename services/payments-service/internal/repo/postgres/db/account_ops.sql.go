@@ -11,22 +11,46 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countRecentDebits = `-- name: CountRecentDebits :one
+SELECT COUNT(*)::bigint
+FROM account_ops
+WHERE user_id = $1 AND delta < 0 AND created_at > $2
+`
+
+type CountRecentDebitsParams struct {
+	UserID    string             `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) CountRecentDebits(ctx context.Context, arg CountRecentDebitsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countRecentDebits, arg.UserID, arg.CreatedAt)
+	var column_1 int64
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const insertAccountOp = `-- name: InsertAccountOp :one
-INSERT INTO account_ops (order_id, user_id, delta)
-VALUES ($1, $2, $3)
-    ON CONFLICT (order_id) DO NOTHING
-RETURNING order_id
+INSERT INTO account_ops (payment_id, order_id, user_id, delta)
+VALUES ($1, $2, $3, $4)
+    ON CONFLICT (payment_id) DO NOTHING
+RETURNING payment_id
 `
 
 type InsertAccountOpParams struct {
-	OrderID pgtype.UUID `json:"order_id"`
-	UserID  string      `json:"user_id"`
-	Delta   int64       `json:"delta"`
+	PaymentID pgtype.UUID `json:"payment_id"`
+	OrderID   pgtype.UUID `json:"order_id"`
+	UserID    string      `json:"user_id"`
+	Delta     int64       `json:"delta"`
 }
 
 func (q *Queries) InsertAccountOp(ctx context.Context, arg InsertAccountOpParams) (pgtype.UUID, error) {
-	row := q.db.QueryRow(ctx, insertAccountOp, arg.OrderID, arg.UserID, arg.Delta)
-	var order_id pgtype.UUID
-	err := row.Scan(&order_id)
-	return order_id, err
+	row := q.db.QueryRow(ctx, insertAccountOp,
+		arg.PaymentID,
+		arg.OrderID,
+		arg.UserID,
+		arg.Delta,
+	)
+	var payment_id pgtype.UUID
+	err := row.Scan(&payment_id)
+	return payment_id, err
 }
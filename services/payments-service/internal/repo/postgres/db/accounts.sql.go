@@ -20,75 +20,343 @@ func (q *Queries) AccountExists(ctx context.Context, userID string) (bool, error
 	return exists, err
 }
 
+const adjustBalance = `-- name: AdjustBalance :one
+WITH upd AS (
+UPDATE accounts
+SET balance = accounts.balance + $1,
+    version = accounts.version + 1
+WHERE accounts.user_id = $2
+    RETURNING accounts.user_id, accounts.balance, accounts.version, accounts.overdraft_limit, accounts.account_type
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id)
+SELECT 'adjustment', upd.user_id FROM upd
+    RETURNING id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, 'system:adjustments', -$1::bigint, ent.created_at FROM ent
+UNION ALL
+SELECT ent.id, 'balance:' || $2::text, $1::bigint, ent.created_at FROM ent
+)
+SELECT upd.user_id, upd.balance, upd.version, upd.overdraft_limit, upd.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = upd.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM upd
+`
+
+type AdjustBalanceParams struct {
+	Delta  int64  `json:"delta"`
+	UserID string `json:"user_id"`
+}
+
+type AdjustBalanceRow struct {
+	UserID         string `json:"user_id"`
+	Balance        int64  `json:"balance"`
+	Version        int64  `json:"version"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	AccountType    string `json:"account_type"`
+	BonusBalance   int64  `json:"bonus_balance"`
+}
+
+// Operator correction (admin AdjustBalance), booked as its own entry kind
+// against system:adjustments.
+// The balance check constraint rejects a debit below the overdraft limit.
+func (q *Queries) AdjustBalance(ctx context.Context, arg AdjustBalanceParams) (AdjustBalanceRow, error) {
+	row := q.db.QueryRow(ctx, adjustBalance, arg.Delta, arg.UserID)
+	var i AdjustBalanceRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.OverdraftLimit,
+		&i.AccountType,
+		&i.BonusBalance,
+	)
+	return i, err
+}
+
 const createAccount = `-- name: CreateAccount :one
 INSERT INTO accounts (user_id, balance)
 VALUES ($1, 0)
     ON CONFLICT (user_id) DO NOTHING
-RETURNING user_id, balance
+RETURNING user_id, balance, version, account_type
 `
 
 type CreateAccountRow struct {
-	UserID  string `json:"user_id"`
-	Balance int64  `json:"balance"`
+	UserID      string `json:"user_id"`
+	Balance     int64  `json:"balance"`
+	Version     int64  `json:"version"`
+	AccountType string `json:"account_type"`
 }
 
 func (q *Queries) CreateAccount(ctx context.Context, userID string) (CreateAccountRow, error) {
 	row := q.db.QueryRow(ctx, createAccount, userID)
 	var i CreateAccountRow
-	err := row.Scan(&i.UserID, &i.Balance)
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.AccountType,
+	)
 	return i, err
 }
 
 const createAccountIdempotent = `-- name: CreateAccountIdempotent :one
-INSERT INTO accounts (user_id, balance)
+INSERT INTO accounts AS a (user_id, balance)
 VALUES ($1, 0)
     ON CONFLICT (user_id) DO UPDATE SET user_id = EXCLUDED.user_id
-RETURNING user_id, balance
+RETURNING a.user_id, a.balance, a.version, a.overdraft_limit, a.account_type,
+    COALESCE((
+        SELECT SUM(b.remaining)
+        FROM bonus_grants b
+        WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+    ), 0)::bigint AS bonus_balance,
+    (a.xmax = 0)::boolean AS created
 `
 
 type CreateAccountIdempotentRow struct {
-	UserID  string `json:"user_id"`
-	Balance int64  `json:"balance"`
+	UserID         string `json:"user_id"`
+	Balance        int64  `json:"balance"`
+	Version        int64  `json:"version"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	AccountType    string `json:"account_type"`
+	BonusBalance   int64  `json:"bonus_balance"`
+	Created        bool   `json:"created"`
 }
 
 func (q *Queries) CreateAccountIdempotent(ctx context.Context, userID string) (CreateAccountIdempotentRow, error) {
 	row := q.db.QueryRow(ctx, createAccountIdempotent, userID)
 	var i CreateAccountIdempotentRow
-	err := row.Scan(&i.UserID, &i.Balance)
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.OverdraftLimit,
+		&i.AccountType,
+		&i.BonusBalance,
+		&i.Created,
+	)
 	return i, err
 }
 
+const getAccountType = `-- name: GetAccountType :one
+SELECT account_type FROM accounts WHERE user_id = $1
+`
+
+func (q *Queries) GetAccountType(ctx context.Context, userID string) (string, error) {
+	row := q.db.QueryRow(ctx, getAccountType, userID)
+	var account_type string
+	err := row.Scan(&account_type)
+	return account_type, err
+}
+
 const getBalance = `-- name: GetBalance :one
-SELECT balance FROM accounts WHERE user_id = $1
+SELECT a.balance, a.version, a.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM accounts a
+WHERE a.user_id = $1
 `
 
-func (q *Queries) GetBalance(ctx context.Context, userID string) (int64, error) {
+type GetBalanceRow struct {
+	Balance      int64  `json:"balance"`
+	Version      int64  `json:"version"`
+	AccountType  string `json:"account_type"`
+	BonusBalance int64  `json:"bonus_balance"`
+}
+
+func (q *Queries) GetBalance(ctx context.Context, userID string) (GetBalanceRow, error) {
 	row := q.db.QueryRow(ctx, getBalance, userID)
-	var balance int64
-	err := row.Scan(&balance)
-	return balance, err
+	var i GetBalanceRow
+	err := row.Scan(
+		&i.Balance,
+		&i.Version,
+		&i.AccountType,
+		&i.BonusBalance,
+	)
+	return i, err
+}
+
+const getBalances = `-- name: GetBalances :many
+SELECT a.user_id, a.balance, a.version, a.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = a.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM accounts a
+WHERE a.user_id = ANY($1::text[])
+`
+
+type GetBalancesRow struct {
+	UserID       string `json:"user_id"`
+	Balance      int64  `json:"balance"`
+	Version      int64  `json:"version"`
+	AccountType  string `json:"account_type"`
+	BonusBalance int64  `json:"bonus_balance"`
+}
+
+// GetBalances is GetBalance for several accounts in one round trip; missing
+// accounts are simply absent.
+func (q *Queries) GetBalances(ctx context.Context, userIds []string) ([]GetBalancesRow, error) {
+	rows, err := q.db.Query(ctx, getBalances, userIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetBalancesRow
+	for rows.Next() {
+		var i GetBalancesRow
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Balance,
+			&i.Version,
+			&i.AccountType,
+			&i.BonusBalance,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setAccountType = `-- name: SetAccountType :one
+UPDATE accounts
+SET account_type = $1,
+    overdraft_limit = $2
+WHERE user_id = $3
+    RETURNING user_id, balance, version, overdraft_limit, account_type
+`
+
+type SetAccountTypeParams struct {
+	AccountType    string `json:"account_type"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	UserID         string `json:"user_id"`
+}
+
+type SetAccountTypeRow struct {
+	UserID         string `json:"user_id"`
+	Balance        int64  `json:"balance"`
+	Version        int64  `json:"version"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	AccountType    string `json:"account_type"`
+}
+
+// SetAccountType also applies the overdraft limit of the new type.
+func (q *Queries) SetAccountType(ctx context.Context, arg SetAccountTypeParams) (SetAccountTypeRow, error) {
+	row := q.db.QueryRow(ctx, setAccountType, arg.AccountType, arg.OverdraftLimit, arg.UserID)
+	var i SetAccountTypeRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.OverdraftLimit,
+		&i.AccountType,
+	)
+	return i, err
+}
+
+const setOverdraftLimit = `-- name: SetOverdraftLimit :one
+UPDATE accounts
+SET overdraft_limit = $1
+WHERE user_id = $2
+    RETURNING user_id, balance, version, overdraft_limit, account_type
+`
+
+type SetOverdraftLimitParams struct {
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	UserID         string `json:"user_id"`
+}
+
+type SetOverdraftLimitRow struct {
+	UserID         string `json:"user_id"`
+	Balance        int64  `json:"balance"`
+	Version        int64  `json:"version"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	AccountType    string `json:"account_type"`
+}
+
+func (q *Queries) SetOverdraftLimit(ctx context.Context, arg SetOverdraftLimitParams) (SetOverdraftLimitRow, error) {
+	row := q.db.QueryRow(ctx, setOverdraftLimit, arg.OverdraftLimit, arg.UserID)
+	var i SetOverdraftLimitRow
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.OverdraftLimit,
+		&i.AccountType,
+	)
+	return i, err
 }
 
 const topUp = `-- name: TopUp :one
+WITH upd AS (
 UPDATE accounts
-SET balance = balance + $2
-WHERE user_id = $1
-    RETURNING user_id, balance
+SET balance = accounts.balance + $1,
+    version = accounts.version + 1
+WHERE accounts.user_id = $2
+  AND ($3::bigint = 0 OR accounts.version = $3::bigint)
+    RETURNING accounts.user_id, accounts.balance, accounts.version, accounts.overdraft_limit, accounts.account_type
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id)
+SELECT 'balance', upd.user_id FROM upd
+    RETURNING id, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, 'system:funding', -$1::bigint, ent.created_at FROM ent
+UNION ALL
+SELECT ent.id, 'balance:' || $2::text, $1::bigint, ent.created_at FROM ent
+)
+SELECT upd.user_id, upd.balance, upd.version, upd.overdraft_limit, upd.account_type,
+       COALESCE((
+           SELECT SUM(b.remaining)
+           FROM bonus_grants b
+           WHERE b.user_id = upd.user_id AND b.remaining > 0 AND b.expires_at > now()
+       ), 0)::bigint AS bonus_balance
+FROM upd
 `
 
 type TopUpParams struct {
-	UserID  string `json:"user_id"`
-	Balance int64  `json:"balance"`
+	Balance         int64  `json:"balance"`
+	UserID          string `json:"user_id"`
+	ExpectedVersion int64  `json:"expected_version"`
 }
 
 type TopUpRow struct {
-	UserID  string `json:"user_id"`
-	Balance int64  `json:"balance"`
+	UserID         string `json:"user_id"`
+	Balance        int64  `json:"balance"`
+	Version        int64  `json:"version"`
+	OverdraftLimit int64  `json:"overdraft_limit"`
+	AccountType    string `json:"account_type"`
+	BonusBalance   int64  `json:"bonus_balance"`
 }
 
+// TopUp is a compare-and-swap when expected_version is non-zero: no row is
+// returned if the account is missing or its version moved on. The money
+// comes from system:funding.
 func (q *Queries) TopUp(ctx context.Context, arg TopUpParams) (TopUpRow, error) {
-	row := q.db.QueryRow(ctx, topUp, arg.UserID, arg.Balance)
+	row := q.db.QueryRow(ctx, topUp, arg.Balance, arg.UserID, arg.ExpectedVersion)
 	var i TopUpRow
-	err := row.Scan(&i.UserID, &i.Balance)
+	err := row.Scan(
+		&i.UserID,
+		&i.Balance,
+		&i.Version,
+		&i.OverdraftLimit,
+		&i.AccountType,
+		&i.BonusBalance,
+	)
 	return i, err
 }
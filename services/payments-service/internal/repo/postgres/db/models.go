@@ -9,9 +9,12 @@ import (
 )
 
 type Account struct {
-	UserID    string             `json:"user_id"`
-	Balance   int64              `json:"balance"`
-	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	UserID         string             `json:"user_id"`
+	Balance        int64              `json:"balance"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	Version        int64              `json:"version"`
+	OverdraftLimit int64              `json:"overdraft_limit"`
+	AccountType    string             `json:"account_type"`
 }
 
 type AccountOp struct {
@@ -19,24 +22,185 @@ type AccountOp struct {
 	UserID    string             `json:"user_id"`
 	Delta     int64              `json:"delta"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
+	PaymentID pgtype.UUID        `json:"payment_id"`
+	Fee       int64              `json:"fee"`
+	Bonus     int64              `json:"bonus"`
+	Method    string             `json:"method"`
+	Frozen    bool               `json:"frozen"`
+}
+
+type AdminAuditLog struct {
+	ID        int64              `json:"id"`
+	Operator  string             `json:"operator"`
+	Action    string             `json:"action"`
+	Target    string             `json:"target"`
+	Reason    string             `json:"reason"`
+	Details   []byte             `json:"details"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type BalanceAnomaly struct {
+	ID            int64              `json:"id"`
+	UserID        string             `json:"user_id"`
+	StoredBalance int64              `json:"stored_balance"`
+	LedgerBalance int64              `json:"ledger_balance"`
+	DetectedAt    pgtype.Timestamptz `json:"detected_at"`
+	LastSeenAt    pgtype.Timestamptz `json:"last_seen_at"`
+	ResolvedAt    pgtype.Timestamptz `json:"resolved_at"`
+	ResolvedBy    pgtype.Text        `json:"resolved_by"`
+	Account       string             `json:"account"`
+}
+
+type BalanceHistoryStart struct {
+	ID        bool               `json:"id"`
+	StartedAt pgtype.Timestamptz `json:"started_at"`
+}
+
+type BalanceSnapshot struct {
+	UserID     string             `json:"user_id"`
+	SnapshotAt pgtype.Timestamptz `json:"snapshot_at"`
+	Balance    int64              `json:"balance"`
+	Bonus      int64              `json:"bonus"`
+}
+
+type BonusGrant struct {
+	ID        int64              `json:"id"`
+	UserID    string             `json:"user_id"`
+	Amount    int64              `json:"amount"`
+	Remaining int64              `json:"remaining"`
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type ExchangeRate struct {
+	Currency string             `json:"currency"`
+	AsOf     pgtype.Timestamptz `json:"as_of"`
+	Price    pgtype.Numeric     `json:"price"`
+}
+
+type ExternalCharge struct {
+	PaymentID           pgtype.UUID        `json:"payment_id"`
+	OrderID             pgtype.UUID        `json:"order_id"`
+	UserID              string             `json:"user_id"`
+	Method              string             `json:"method"`
+	Amount              int64              `json:"amount"`
+	Bonus               int64              `json:"bonus"`
+	Fee                 int64              `json:"fee"`
+	BonusGrantIds       []int64            `json:"bonus_grant_ids"`
+	BonusGrantAmounts   []int64            `json:"bonus_grant_amounts"`
+	CorrelationID       string             `json:"correlation_id"`
+	Status              string             `json:"status"`
+	ProviderChargeID    pgtype.Text        `json:"provider_charge_id"`
+	FailureReason       pgtype.Text        `json:"failure_reason"`
+	Attempts            int32              `json:"attempts"`
+	CreatedAt           pgtype.Timestamptz `json:"created_at"`
+	CheckedAt           pgtype.Timestamptz `json:"checked_at"`
+	FinishedAt          pgtype.Timestamptz `json:"finished_at"`
+	ProviderSucceededAt pgtype.Timestamptz `json:"provider_succeeded_at"`
+}
+
+type IdempotencyKey struct {
+	Scope          string             `json:"scope"`
+	UserID         string             `json:"user_id"`
+	IdempotencyKey string             `json:"idempotency_key"`
+	RequestHash    []byte             `json:"request_hash"`
+	Status         string             `json:"status"`
+	Response       []byte             `json:"response"`
+	CreatedAt      pgtype.Timestamptz `json:"created_at"`
+	CompletedAt    pgtype.Timestamptz `json:"completed_at"`
 }
 
 type Inbox struct {
-	MessageID   pgtype.UUID        `json:"message_id"`
-	OrderID     pgtype.UUID        `json:"order_id"`
-	ProcessedAt pgtype.Timestamptz `json:"processed_at"`
+	MessageID      pgtype.UUID        `json:"message_id"`
+	ProcessedAt    pgtype.Timestamptz `json:"processed_at"`
+	CorrelationID  string             `json:"correlation_id"`
+	Consumer       string             `json:"consumer"`
+	ReceivedAt     pgtype.Timestamptz `json:"received_at"`
+	Topic          pgtype.Text        `json:"topic"`
+	KafkaPartition pgtype.Int4        `json:"kafka_partition"`
+	KafkaOffset    pgtype.Int8        `json:"kafka_offset"`
+	MessageKey     []byte             `json:"message_key"`
+	Payload        []byte             `json:"payload"`
+	Headers        []byte             `json:"headers"`
+}
+
+type JournalEntry struct {
+	ID        int64              `json:"id"`
+	Kind      string             `json:"kind"`
+	UserID    string             `json:"user_id"`
+	PaymentID pgtype.UUID        `json:"payment_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type KafkaOffset struct {
+	Topic      string             `json:"topic"`
+	Partition  int32              `json:"partition"`
+	LastOffset int64              `json:"last_offset"`
+	UpdatedAt  pgtype.Timestamptz `json:"updated_at"`
+}
+
+type LedgerAccount struct {
+	Code      string             `json:"code"`
+	Type      string             `json:"type"`
+	UserID    pgtype.Text        `json:"user_id"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
 }
 
 type Outbox struct {
+	ID            int64              `json:"id"`
+	Topic         string             `json:"topic"`
+	KafkaKey      string             `json:"kafka_key"`
+	Payload       []byte             `json:"payload"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+	LastError     pgtype.Text        `json:"last_error"`
+	NextRetryAt   pgtype.Timestamptz `json:"next_retry_at"`
+	CorrelationID string             `json:"correlation_id"`
+}
+
+type PaymentChallenge struct {
+	PaymentID         pgtype.UUID        `json:"payment_id"`
+	OrderID           pgtype.UUID        `json:"order_id"`
+	UserID            string             `json:"user_id"`
+	Method            string             `json:"method"`
+	Amount            int64              `json:"amount"`
+	Bonus             int64              `json:"bonus"`
+	Fee               int64              `json:"fee"`
+	BonusGrantIds     []int64            `json:"bonus_grant_ids"`
+	BonusGrantAmounts []int64            `json:"bonus_grant_amounts"`
+	CorrelationID     string             `json:"correlation_id"`
+	Status            string             `json:"status"`
+	CreatedAt         pgtype.Timestamptz `json:"created_at"`
+	ExpiresAt         pgtype.Timestamptz `json:"expires_at"`
+	FinishedAt        pgtype.Timestamptz `json:"finished_at"`
+}
+
+type PaymentDispute struct {
+	OrderID          pgtype.UUID        `json:"order_id"`
+	UserID           string             `json:"user_id"`
+	Amount           int64              `json:"amount"`
+	Status           string             `json:"status"`
+	Reason           string             `json:"reason"`
+	ResolutionReason pgtype.Text        `json:"resolution_reason"`
+	OpenedAt         pgtype.Timestamptz `json:"opened_at"`
+	ResolvedAt       pgtype.Timestamptz `json:"resolved_at"`
+}
+
+type Posting struct {
+	ID        int64              `json:"id"`
+	EntryID   int64              `json:"entry_id"`
+	Account   string             `json:"account"`
+	Amount    int64              `json:"amount"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+type TopupEvent struct {
 	ID        int64              `json:"id"`
-	Topic     string             `json:"topic"`
-	KafkaKey  string             `json:"kafka_key"`
-	Payload   []byte             `json:"payload"`
-	Status    string             `json:"status"`
-	Attempts  int32              `json:"attempts"`
+	UserID    string             `json:"user_id"`
+	Amount    int64              `json:"amount"`
 	CreatedAt pgtype.Timestamptz `json:"created_at"`
-	SentAt    pgtype.Timestamptz `json:"sent_at"`
-	LastError pgtype.Text        `json:"last_error"`
 }
 
 type TopupIdempotency struct {
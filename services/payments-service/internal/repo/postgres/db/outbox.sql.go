@@ -11,44 +11,260 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countSentOutbox = `-- name: CountSentOutbox :one
+SELECT count(*)
+FROM outbox
+WHERE sent_at IS NOT NULL
+  AND created_at >= $1 AND created_at < $2
+  AND ($3::text = '' OR topic = $3::text)
+`
+
+type CountSentOutboxParams struct {
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	Topic       string             `json:"topic"`
+}
+
+// Повтор уже отправленных событий (admin ReplayOutbox): копии встают в очередь
+// как новые, исходные строки остаются историей
+func (q *Queries) CountSentOutbox(ctx context.Context, arg CountSentOutboxParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSentOutbox, arg.CreatedFrom, arg.CreatedTo, arg.Topic)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const insertOutbox = `-- name: InsertOutbox :one
-INSERT INTO outbox (topic, kafka_key, payload)
-VALUES ($1, $2, $3)
+INSERT INTO outbox (topic, kafka_key, payload, correlation_id)
+VALUES ($1, $2, $3, $4)
     RETURNING id
 `
 
 type InsertOutboxParams struct {
-	Topic    string `json:"topic"`
-	KafkaKey string `json:"kafka_key"`
-	Payload  []byte `json:"payload"`
+	Topic         string `json:"topic"`
+	KafkaKey      string `json:"kafka_key"`
+	Payload       []byte `json:"payload"`
+	CorrelationID string `json:"correlation_id"`
 }
 
 func (q *Queries) InsertOutbox(ctx context.Context, arg InsertOutboxParams) (int64, error) {
-	row := q.db.QueryRow(ctx, insertOutbox, arg.Topic, arg.KafkaKey, arg.Payload)
+	row := q.db.QueryRow(ctx, insertOutbox,
+		arg.Topic,
+		arg.KafkaKey,
+		arg.Payload,
+		arg.CorrelationID,
+	)
 	var id int64
 	err := row.Scan(&id)
 	return id, err
 }
 
-const lockUnsentOutbox = `-- name: LockUnsentOutbox :many
-SELECT id, topic, kafka_key, payload, attempts
+const listDeadOutbox = `-- name: ListDeadOutbox :many
+SELECT id, topic, kafka_key, attempts, last_error, created_at
+FROM outbox
+WHERE status = 'DEAD'
+  AND id > $1
+  AND ($2::text = '' OR topic = $2::text)
+ORDER BY id
+    LIMIT $3
+`
+
+type ListDeadOutboxParams struct {
+	AfterID  int64  `json:"after_id"`
+	Topic    string `json:"topic"`
+	PageSize int32  `json:"page_size"`
+}
+
+type ListDeadOutboxRow struct {
+	ID        int64              `json:"id"`
+	Topic     string             `json:"topic"`
+	KafkaKey  string             `json:"kafka_key"`
+	Attempts  int32              `json:"attempts"`
+	LastError pgtype.Text        `json:"last_error"`
+	CreatedAt pgtype.Timestamptz `json:"created_at"`
+}
+
+func (q *Queries) ListDeadOutbox(ctx context.Context, arg ListDeadOutboxParams) ([]ListDeadOutboxRow, error) {
+	rows, err := q.db.Query(ctx, listDeadOutbox, arg.AfterID, arg.Topic, arg.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDeadOutboxRow
+	for rows.Next() {
+		var i ListDeadOutboxRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.KafkaKey,
+			&i.Attempts,
+			&i.LastError,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOutbox = `-- name: ListOutbox :many
+SELECT id, topic, kafka_key, status, attempts, last_error, created_at, next_retry_at, correlation_id
+FROM outbox
+WHERE status = ANY($1::text[])
+  AND id > $2
+  AND ($3::text = '' OR topic = $3::text)
+  AND ($4::timestamptz IS NULL OR created_at >= $4::timestamptz)
+  AND ($5::timestamptz IS NULL OR created_at < $5::timestamptz)
+ORDER BY id
+    LIMIT $6
+`
+
+type ListOutboxParams struct {
+	Statuses    []string           `json:"statuses"`
+	AfterID     int64              `json:"after_id"`
+	Topic       string             `json:"topic"`
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	PageSize    int32              `json:"page_size"`
+}
+
+type ListOutboxRow struct {
+	ID            int64              `json:"id"`
+	Topic         string             `json:"topic"`
+	KafkaKey      string             `json:"kafka_key"`
+	Status        string             `json:"status"`
+	Attempts      int32              `json:"attempts"`
+	LastError     pgtype.Text        `json:"last_error"`
+	CreatedAt     pgtype.Timestamptz `json:"created_at"`
+	NextRetryAt   pgtype.Timestamptz `json:"next_retry_at"`
+	CorrelationID string             `json:"correlation_id"`
+}
+
+// Просмотр outbox для админки (ListOutbox): statuses задаёт состояние, границы
+// created_from/created_to необязательны
+func (q *Queries) ListOutbox(ctx context.Context, arg ListOutboxParams) ([]ListOutboxRow, error) {
+	rows, err := q.db.Query(ctx, listOutbox,
+		arg.Statuses,
+		arg.AfterID,
+		arg.Topic,
+		arg.CreatedFrom,
+		arg.CreatedTo,
+		arg.PageSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListOutboxRow
+	for rows.Next() {
+		var i ListOutboxRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.KafkaKey,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.CreatedAt,
+			&i.NextRetryAt,
+			&i.CorrelationID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTxOutbox = `-- name: ListTxOutbox :many
+SELECT id, topic, kafka_key, payload
 FROM outbox
 WHERE sent_at IS NULL
+  AND xmin::text::bigint = txid_current() % 4294967296
 ORDER BY id
-    LIMIT $1
-FOR UPDATE SKIP LOCKED
 `
 
-type LockUnsentOutboxRow struct {
+type ListTxOutboxRow struct {
 	ID       int64  `json:"id"`
 	Topic    string `json:"topic"`
 	KafkaKey string `json:"kafka_key"`
 	Payload  []byte `json:"payload"`
-	Attempts int32  `json:"attempts"`
 }
 
-func (q *Queries) LockUnsentOutbox(ctx context.Context, limit int32) ([]LockUnsentOutboxRow, error) {
-	rows, err := q.db.Query(ctx, lockUnsentOutbox, limit)
+// Строки outbox, вставленные текущей транзакцией (dry-run повтор сообщения
+// из inbox): xmin строки совпадает с xid транзакции
+func (q *Queries) ListTxOutbox(ctx context.Context) ([]ListTxOutboxRow, error) {
+	rows, err := q.db.Query(ctx, listTxOutbox)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTxOutboxRow
+	for rows.Next() {
+		var i ListTxOutboxRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Topic,
+			&i.KafkaKey,
+			&i.Payload,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUnsentOutbox = `-- name: LockUnsentOutbox :many
+SELECT o.id, o.topic, o.kafka_key, o.payload, o.attempts, o.correlation_id
+FROM outbox o
+WHERE o.sent_at IS NULL
+  AND o.status <> 'DEAD'
+  AND mod(abs(hashtext(o.kafka_key)::bigint), 64) = ANY($1::int[])
+  AND NOT EXISTS (
+      SELECT 1
+      FROM outbox b
+      WHERE b.kafka_key = o.kafka_key
+        AND b.id <= o.id
+        AND b.sent_at IS NULL
+        AND b.status <> 'DEAD'
+        AND b.next_retry_at > now()
+  )
+ORDER BY o.id
+    LIMIT $2
+FOR UPDATE OF o SKIP LOCKED
+`
+
+type LockUnsentOutboxParams struct {
+	Slots     []int32 `json:"slots"`
+	BatchSize int32   `json:"batch_size"`
+}
+
+type LockUnsentOutboxRow struct {
+	ID            int64  `json:"id"`
+	Topic         string `json:"topic"`
+	KafkaKey      string `json:"kafka_key"`
+	Payload       []byte `json:"payload"`
+	Attempts      int32  `json:"attempts"`
+	CorrelationID string `json:"correlation_id"`
+}
+
+// Rows of the given slots. A key's slot is hash(kafka_key) % 64 whatever the
+// number of workers (kafka.OutboxSlots; outbox_unsent_slot_idx has the same
+// expression). A key waits while its earliest unsent row backs off, so later
+// rows never overtake it; dead-lettered rows no longer block.
+func (q *Queries) LockUnsentOutbox(ctx context.Context, arg LockUnsentOutboxParams) ([]LockUnsentOutboxRow, error) {
+	rows, err := q.db.Query(ctx, lockUnsentOutbox, arg.Slots, arg.BatchSize)
 	if err != nil {
 		return nil, err
 	}
@@ -62,6 +278,7 @@ func (q *Queries) LockUnsentOutbox(ctx context.Context, limit int32) ([]LockUnse
 			&i.KafkaKey,
 			&i.Payload,
 			&i.Attempts,
+			&i.CorrelationID,
 		); err != nil {
 			return nil, err
 		}
@@ -75,23 +292,45 @@ func (q *Queries) LockUnsentOutbox(ctx context.Context, limit int32) ([]LockUnse
 
 const markOutboxAttemptFailed = `-- name: MarkOutboxAttemptFailed :exec
 UPDATE outbox
-SET attempts = attempts + 1, last_error = $2, status = 'FAILED'
-WHERE id = $1
+SET attempts = attempts + 1,
+    last_error = $1,
+    status = 'FAILED',
+    next_retry_at = now() + $2::bigint * interval '1 millisecond'
+WHERE id = $3
 `
 
 type MarkOutboxAttemptFailedParams struct {
-	ID        int64       `json:"id"`
 	LastError pgtype.Text `json:"last_error"`
+	BackoffMs int64       `json:"backoff_ms"`
+	ID        int64       `json:"id"`
 }
 
 func (q *Queries) MarkOutboxAttemptFailed(ctx context.Context, arg MarkOutboxAttemptFailedParams) error {
-	_, err := q.db.Exec(ctx, markOutboxAttemptFailed, arg.ID, arg.LastError)
+	_, err := q.db.Exec(ctx, markOutboxAttemptFailed, arg.LastError, arg.BackoffMs, arg.ID)
+	return err
+}
+
+const markOutboxDead = `-- name: MarkOutboxDead :exec
+UPDATE outbox
+SET attempts = attempts + 1, last_error = $2, status = 'DEAD', next_retry_at = NULL
+WHERE id = $1
+`
+
+type MarkOutboxDeadParams struct {
+	ID        int64       `json:"id"`
+	LastError pgtype.Text `json:"last_error"`
+}
+
+// Dead-lettered rows are never picked up again; they stay for the admin
+// ListDeadOutbox RPC.
+func (q *Queries) MarkOutboxDead(ctx context.Context, arg MarkOutboxDeadParams) error {
+	_, err := q.db.Exec(ctx, markOutboxDead, arg.ID, arg.LastError)
 	return err
 }
 
 const markOutboxSent = `-- name: MarkOutboxSent :exec
 UPDATE outbox
-SET sent_at = now(), status = 'SENT'
+SET sent_at = now(), status = 'SENT', next_retry_at = NULL
 WHERE id = $1
 `
 
@@ -99,3 +338,79 @@ func (q *Queries) MarkOutboxSent(ctx context.Context, id int64) error {
 	_, err := q.db.Exec(ctx, markOutboxSent, id)
 	return err
 }
+
+const replaySentOutbox = `-- name: ReplaySentOutbox :execrows
+INSERT INTO outbox (topic, kafka_key, payload, correlation_id)
+SELECT o.topic, o.kafka_key, o.payload, o.correlation_id
+FROM outbox o
+WHERE o.sent_at IS NOT NULL
+  AND o.created_at >= $1 AND o.created_at < $2
+  AND ($3::text = '' OR o.topic = $3::text)
+ORDER BY o.id
+`
+
+type ReplaySentOutboxParams struct {
+	CreatedFrom pgtype.Timestamptz `json:"created_from"`
+	CreatedTo   pgtype.Timestamptz `json:"created_to"`
+	Topic       string             `json:"topic"`
+}
+
+func (q *Queries) ReplaySentOutbox(ctx context.Context, arg ReplaySentOutboxParams) (int64, error) {
+	result, err := q.db.Exec(ctx, replaySentOutbox, arg.CreatedFrom, arg.CreatedTo, arg.Topic)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const requeueDeadOutbox = `-- name: RequeueDeadOutbox :execrows
+UPDATE outbox
+SET status = 'PENDING', attempts = 0, next_retry_at = NULL
+WHERE status = 'DEAD'
+  AND ($1::bool OR id = ANY($2::bigint[]))
+  AND ($3::text = '' OR topic = $3::text)
+`
+
+type RequeueDeadOutboxParams struct {
+	AllEvents bool    `json:"all_events"`
+	Ids       []int64 `json:"ids"`
+	Topic     string  `json:"topic"`
+}
+
+// Возвращает мёртвые события в очередь с нуля попыток; с all — все, иначе перечисленные
+func (q *Queries) RequeueDeadOutbox(ctx context.Context, arg RequeueDeadOutboxParams) (int64, error) {
+	result, err := q.db.Exec(ctx, requeueDeadOutbox, arg.AllEvents, arg.Ids, arg.Topic)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const tryLockOutboxSlots = `-- name: TryLockOutboxSlots :many
+SELECT slot::int AS slot
+FROM unnest($1::int[]) AS slot
+WHERE pg_try_advisory_xact_lock(hashtext('outbox_slot'), slot)
+`
+
+// Returns the slots no other publisher holds and locks them until the
+// transaction ends, so one publisher at a time, across all instances and
+// whatever their OUTBOX_WORKERS, works on a slot and keys stay in order.
+func (q *Queries) TryLockOutboxSlots(ctx context.Context, slots []int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, tryLockOutboxSlots, slots)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var slot int32
+		if err := rows.Scan(&slot); err != nil {
+			return nil, err
+		}
+		items = append(items, slot)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,21 +11,101 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const recordExternalPayment = `-- name: RecordExternalPayment :one
+WITH ins AS (
+INSERT INTO account_ops (payment_id, order_id, user_id, delta, fee, bonus, method)
+VALUES ($1, $2, $3, -$4::bigint, $5::bigint, $6::bigint, $7::text)
+ON CONFLICT (payment_id) DO NOTHING
+    RETURNING 1 AS inserted
+    ),
+legs AS (
+SELECT 'bonus' AS kind, 'bonus:' || $3::text AS from_account, 'system:orders' AS to_account, $6::bigint AS amount
+UNION ALL
+SELECT 'external', 'system:external', 'system:orders', $4::bigint - $6::bigint
+UNION ALL
+SELECT 'fee', 'system:external', 'system:fees', $5::bigint
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id, payment_id)
+SELECT legs.kind, $3, $1
+FROM legs
+WHERE legs.amount > 0 AND EXISTS (SELECT 1 FROM ins)
+    RETURNING id, kind, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, legs.from_account, -legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+UNION ALL
+SELECT ent.id, legs.to_account, legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+    )
+SELECT COALESCE((SELECT inserted FROM ins), 0)::bigint AS op_inserted
+`
+
+type RecordExternalPaymentParams struct {
+	PaymentID pgtype.UUID `json:"payment_id"`
+	OrderID   pgtype.UUID `json:"order_id"`
+	UserID    string      `json:"user_id"`
+	Amount    int64       `json:"amount"`
+	Fee       int64       `json:"fee"`
+	Bonus     int64       `json:"bonus"`
+	Method    string      `json:"method"`
+}
+
+// A payment made by an external method leaves the balance alone: its bonus
+// part is booked from the bonus account, the rest and the fee from
+// system:external. op_inserted is 0 when the payment_id is already
+// recorded.
+func (q *Queries) RecordExternalPayment(ctx context.Context, arg RecordExternalPaymentParams) (int64, error) {
+	row := q.db.QueryRow(ctx, recordExternalPayment,
+		arg.PaymentID,
+		arg.OrderID,
+		arg.UserID,
+		arg.Amount,
+		arg.Fee,
+		arg.Bonus,
+		arg.Method,
+	)
+	var op_inserted int64
+	err := row.Scan(&op_inserted)
+	return op_inserted, err
+}
+
 const tryDeductOnce = `-- name: TryDeductOnce :one
 WITH upd AS (
 UPDATE accounts
-SET balance = accounts.balance - $3
-WHERE accounts.user_id = $2
-  AND accounts.balance >= $3
-  AND NOT EXISTS (SELECT 1 FROM account_ops ao WHERE ao.order_id = $1)
+SET balance = accounts.balance - ($1::bigint - $2::bigint) - $3::bigint,
+    version = accounts.version + 1
+WHERE accounts.user_id = $4
+  AND accounts.balance - ($1::bigint - $2::bigint) - $3::bigint >= -accounts.overdraft_limit
+  AND NOT EXISTS (SELECT 1 FROM account_ops ao WHERE ao.payment_id = $5)
     RETURNING balance
 ),
 ins AS (
-INSERT INTO account_ops (order_id, user_id, delta)
-SELECT $1, $2, -$3
+INSERT INTO account_ops (payment_id, order_id, user_id, delta, fee, bonus)
+SELECT $5, $6, $4, -$1::bigint, $3::bigint, $2::bigint
 WHERE EXISTS (SELECT 1 FROM upd)
-ON CONFLICT (order_id) DO NOTHING
+ON CONFLICT (payment_id) DO NOTHING
     RETURNING 1 AS inserted
+    ),
+legs AS (
+SELECT 'balance' AS kind, 'balance:' || $4::text AS from_account, 'system:orders' AS to_account, $1::bigint - $2::bigint AS amount
+UNION ALL
+SELECT 'fee', 'balance:' || $4::text, 'system:fees', $3::bigint
+UNION ALL
+SELECT 'bonus', 'bonus:' || $4::text, 'system:orders', $2::bigint
+),
+ent AS (
+INSERT INTO journal_entries (kind, user_id, payment_id)
+SELECT legs.kind, $4, $5
+FROM legs
+WHERE legs.amount > 0 AND EXISTS (SELECT 1 FROM ins)
+    RETURNING id, kind, created_at
+),
+pst AS (
+INSERT INTO postings (entry_id, account, amount, created_at)
+SELECT ent.id, legs.from_account, -legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
+UNION ALL
+SELECT ent.id, legs.to_account, legs.amount, ent.created_at FROM ent JOIN legs ON legs.kind = ent.kind
     )
 SELECT
     COALESCE((SELECT balance FROM upd), 0)::bigint AS new_balance,
@@ -33,9 +113,12 @@ SELECT
 `
 
 type TryDeductOnceParams struct {
-	OrderID pgtype.UUID `json:"order_id"`
-	UserID  string      `json:"user_id"`
-	Balance int64       `json:"balance"`
+	Amount    int64       `json:"amount"`
+	Bonus     int64       `json:"bonus"`
+	Fee       int64       `json:"fee"`
+	UserID    string      `json:"user_id"`
+	PaymentID pgtype.UUID `json:"payment_id"`
+	OrderID   pgtype.UUID `json:"order_id"`
 }
 
 type TryDeductOnceRow struct {
@@ -43,8 +126,19 @@ type TryDeductOnceRow struct {
 	OpInserted int64 `json:"op_inserted"`
 }
 
+// The payment and its fee are deducted in one update but booked as separate
+// entries to system:orders and system:fees; fee is 0 when no fee rule
+// applies. bonus is the part of amount paid from bonus grants, booked from
+// the bonus account, which the caller spends when op_inserted.
 func (q *Queries) TryDeductOnce(ctx context.Context, arg TryDeductOnceParams) (TryDeductOnceRow, error) {
-	row := q.db.QueryRow(ctx, tryDeductOnce, arg.OrderID, arg.UserID, arg.Balance)
+	row := q.db.QueryRow(ctx, tryDeductOnce,
+		arg.Amount,
+		arg.Bonus,
+		arg.Fee,
+		arg.UserID,
+		arg.PaymentID,
+		arg.OrderID,
+	)
 	var i TryDeductOnceRow
 	err := row.Scan(&i.NewBalance, &i.OpInserted)
 	return i, err
@@ -2,63 +2,112 @@ package postgres
 
 import (
 	"context"
-	"errors"
 	"log/slog"
-	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
 	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+	"github.com/ilyaytrewq/payments-service/pkg/chaos"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
 )
 
+var _ repo.PaymentsRepository = (*Repo)(nil)
+
 type Repo struct {
-	pool *pgxpool.Pool
-	q    *db.Queries
+	shard int
+	pool  *pgxpool.Pool
+	q     *db.Queries
+
+	// replicaQ is nil when no read replica is configured.
+	replicaQ *db.Queries
+
+	tx *pgtx.Manager
+
+	// faults is nil unless chaos testing is on.
+	faults *chaos.Injector
 }
 
-func NewRepo(pool *pgxpool.Pool) *Repo {
-	slog.Default().With("service", "payments-service", "component", "repo").Info("repository initialized")
-	return &Repo{
-		pool: pool,
-		q:    db.New(pool),
+// NewRepo builds the repository of shard, 0 when unsharded. replica is
+// optional; when set, Read runs against it and falls back to pool if the
+// replica cannot be reached. tx configures the retries of transactions; its
+// BeforeCommit is replaced by the fault injection of InjectFaults.
+func NewRepo(shard int, pool, replica *pgxpool.Pool, tx pgtx.Config) *Repo {
+	r := &Repo{
+		shard: shard,
+		pool:  pool,
+		q:     db.New(pool),
+	}
+	tx.BeforeCommit = func(ctx context.Context) error {
+		return r.faults.Inject(ctx)
+	}
+	r.tx = pgtx.New(pool, tx, r.logger())
+	r.logger().Info("repository initialized", "replica", replica != nil, "tx_retries", tx.Retries)
+	if replica != nil {
+		r.replicaQ = db.New(replica)
+	}
+	return r
+}
+
+// InjectFaults makes WithTx delay or roll back transactions as f decides,
+// after fn has done its work and just before the commit. Only for chaos
+// testing; call it before the repository is used.
+func (r *Repo) InjectFaults(f *chaos.Injector) {
+	r.faults = f
+}
+
+// Read runs a read-only fn on the replica when configured. Connection-level
+// failures are retried once on the primary; query errors such as
+// pgx.ErrNoRows are returned as is.
+func (r *Repo) Read(ctx context.Context, fn func(q db.Querier) error) error {
+	if r.replicaQ == nil {
+		return fn(r.q)
+	}
+	err := fn(r.replicaQ)
+	if err == nil || ctx.Err() != nil || !pgtx.IsConnError(err) {
+		return err
 	}
+	r.logger().Warn("replica read failed, falling back to primary", "err", err)
+	return fn(r.q)
 }
 
-func (r *Repo) Q() *db.Queries {
-	slog.Default().With("service", "payments-service", "component", "repo").Info("repository queries accessed")
+// Shard returns the id of the shard the repository runs on.
+func (r *Repo) Shard() int {
+	return r.shard
+}
+
+func (r *Repo) logger() *slog.Logger {
+	return slog.Default().With("service", "payments-service", "component", "repo", "shard", r.shard)
+}
+
+func (r *Repo) Q() db.Querier {
+	r.logger().Debug("repository queries accessed")
 	return r.q
 }
 
 func (r *Repo) Pool() *pgxpool.Pool {
-	slog.Default().With("service", "payments-service", "component", "repo").Info("repository pool accessed")
+	r.logger().Debug("repository pool accessed")
 	return r.pool
 }
 
-func (r *Repo) WithTx(ctx context.Context, fn func(tx pgx.Tx, q *db.Queries) error) (err error) {
-	start := time.Now()
-	logger := slog.Default().With("service", "payments-service", "component", "repo")
-	logger.Info("transaction start")
-	tx, err := r.pool.Begin(ctx)
-	if err != nil {
-		logger.Error("transaction begin failed", "err", err)
-		return err
-	}
-	defer func() {
-		if err != nil {
-			if rbErr := tx.Rollback(ctx); rbErr != nil && !errors.Is(rbErr, pgx.ErrTxClosed) {
-				logger.Error("transaction rollback failed", "err", rbErr)
-			}
-			logger.Error("transaction failed", "err", err, "duration", time.Since(start))
-		} else {
-			logger.Info("transaction completed", "duration", time.Since(start))
-		}
-	}()
-
-	qtx := db.New(tx)
-	if err = fn(tx, qtx); err != nil {
-		logger.Error("transaction function failed", "err", err)
-		return err
-	}
-	return tx.Commit(ctx)
+// WithTx runs fn in a transaction of the primary; see pgtx.Manager for
+// retries of serialization failures and deadlocks.
+func (r *Repo) WithTx(ctx context.Context, fn func(tx pgx.Tx, q *db.Queries) error) error {
+	return r.WithTxOptions(ctx, pgx.TxOptions{}, fn)
+}
+
+// WithTxOptions is WithTx with explicit options, e.g. a SERIALIZABLE
+// isolation level for the deduction.
+func (r *Repo) WithTxOptions(ctx context.Context, opts pgx.TxOptions, fn func(tx pgx.Tx, q *db.Queries) error) error {
+	return r.tx.DoWith(ctx, opts, func(_ context.Context, tx pgx.Tx) error {
+		return fn(tx, db.New(tx))
+	})
+}
+
+// InTx is WithTx for callers that only need the queries.
+func (r *Repo) InTx(ctx context.Context, fn func(q db.Querier) error) error {
+	return r.WithTx(ctx, func(_ pgx.Tx, q *db.Queries) error {
+		return fn(q)
+	})
 }
@@ -0,0 +1,71 @@
+package postgres
+
+import (
+	"expvar"
+	"strconv"
+	"sync"
+
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/repo"
+	"github.com/ilyaytrewq/payments-service/payments-service/internal/shard"
+	"github.com/ilyaytrewq/payments-service/pkg/pgtx"
+)
+
+var _ repo.ShardedRepository = (*Shards)(nil)
+
+// shardRoutes counts the operations routed to each shard, keyed by shard id.
+var shardRoutes = expvar.NewMap("db_shard_routes")
+
+// Shards routes account operations to one of several Postgres databases by
+// consistent hash of the user id. Every shard holds the full schema: an
+// account, its ledger and payments, and the outbox events they produce live
+// on the shard of the user.
+type Shards struct {
+	ring  *shard.Ring
+	repos []*Repo
+}
+
+var publishShardsOnce sync.Once
+
+// NewShards builds the router over repos; repos[i] must be shard i. With
+// more than one shard the pool statistics of every shard are exported as the
+// expvar "db_pool_shards", keyed by shard id; "db_pool" keeps showing the
+// first pool created.
+func NewShards(repos ...*Repo) *Shards {
+	s := &Shards{ring: shard.NewRing(len(repos)), repos: repos}
+	if len(repos) > 1 {
+		publishShardsOnce.Do(func() {
+			expvar.Publish("db_pool_shards", expvar.Func(func() any {
+				stats := make(map[string]map[string]int64, len(s.repos))
+				for _, r := range s.repos {
+					stats[strconv.Itoa(r.shard)] = pgtx.PoolStats(r.pool)
+				}
+				return stats
+			}))
+		})
+	}
+	return s
+}
+
+func (s *Shards) For(userID string) repo.PaymentsRepository {
+	return s.Repo(userID)
+}
+
+// Repo is For for callers that need the Postgres repository itself.
+func (s *Shards) Repo(userID string) *Repo {
+	id := s.ring.Shard(userID)
+	shardRoutes.Add(strconv.Itoa(id), 1)
+	return s.repos[id]
+}
+
+func (s *Shards) All() []repo.PaymentsRepository {
+	all := make([]repo.PaymentsRepository, len(s.repos))
+	for i, r := range s.repos {
+		all[i] = r
+	}
+	return all
+}
+
+// Repos returns the Postgres repositories of all shards, in shard id order.
+func (s *Shards) Repos() []*Repo {
+	return s.repos
+}
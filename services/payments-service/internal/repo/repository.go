@@ -0,0 +1,40 @@
+package repo
+
+import (
+	"context"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+// PaymentsRepository is the storage the gRPC handlers depend on. The Postgres
+// implementation is postgres.Repo.
+type PaymentsRepository interface {
+	// Q returns queries that run on the primary outside a transaction.
+	Q() db.Querier
+	// Read runs read-only queries, on a replica when one is configured.
+	Read(ctx context.Context, fn func(q db.Querier) error) error
+	// InTx runs fn in a single transaction that commits when fn returns nil.
+	InTx(ctx context.Context, fn func(q db.Querier) error) error
+}
+
+// ShardedRepository routes to the shard that owns a user's account. Account
+// data of one user never spans shards.
+type ShardedRepository interface {
+	// For returns the repository of the shard that owns userID.
+	For(userID string) PaymentsRepository
+	// All returns every shard, in shard id order.
+	All() []PaymentsRepository
+}
+
+// Single is a ShardedRepository of one shard.
+func Single(r PaymentsRepository) ShardedRepository {
+	return single{r: r}
+}
+
+type single struct {
+	r PaymentsRepository
+}
+
+func (s single) For(string) PaymentsRepository { return s.r }
+
+func (s single) All() []PaymentsRepository { return []PaymentsRepository{s.r} }
@@ -0,0 +1,84 @@
+// Package rest serves PaymentsService over REST/JSON through grpc-gateway, for
+// deployments that run without the separate api-gateway. Routes come from the
+// google.api.http options in payments.proto.
+package rest
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+	"github.com/ilyaytrewq/payments-service/pkg/httperr"
+	"github.com/ilyaytrewq/payments-service/pkg/logging"
+	"github.com/ilyaytrewq/payments-service/pkg/svcauth"
+)
+
+// NewHandler returns a handler that turns REST calls into calls to the gRPC
+// server at grpcAddr. Going through the server rather than calling the
+// handlers directly keeps its interceptors (logging, RBAC, service auth) in
+// the path; the Authorization and X-Service-Token headers are forwarded as
+// gRPC metadata, and so are the request and trace ids (X-Request-Id,
+// traceparent). The proxy adds no service token of its own, so with service
+// auth on a REST call is allowed what its caller's token allows.
+func NewHandler(ctx context.Context, grpcAddr string) (http.Handler, error) {
+	mux := newServeMux()
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor()),
+	}
+	if err := paymentsv1.RegisterPaymentsServiceHandlerFromEndpoint(ctx, mux, loopback(grpcAddr), opts); err != nil {
+		return nil, err
+	}
+	return logging.Middleware(mux), nil
+}
+
+// newServeMux renders JSON with the proto field names (user_id, not userId)
+// and errors in the same shape as the api-gateway.
+func newServeMux() *runtime.ServeMux {
+	return runtime.NewServeMux(
+		runtime.WithIncomingHeaderMatcher(svcauth.HeaderMatcher(runtime.DefaultHeaderMatcher)),
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions: protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+		}),
+		runtime.WithErrorHandler(func(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+			userID := userIDFromPath(r.URL.Path)
+			slog.Default().With("service", "payments-service", "component", "rest").DebugContext(r.Context(), "rest call failed", "err", err, "path", r.URL.Path, "user_id", userID)
+			httperr.WriteGRPC(w, userID, err)
+		}),
+		runtime.WithRoutingErrorHandler(func(_ context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, _ *http.Request, code int) {
+			httperr.Write(w, "", code, strings.ToLower(http.StatusText(code)))
+		}),
+	)
+}
+
+// loopback turns a listen address such as ":9002" into one to dial.
+func loopback(addr string) string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, port)
+}
+
+// userIDFromPath returns the {user_id} segment of a /v1/users/{user_id}/...
+// route, for the error body.
+func userIDFromPath(path string) string {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == "users" {
+			return parts[i+1]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,108 @@
+package rest
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	paymentsv1 "github.com/ilyaytrewq/payments-service/gen/go/payments/v1"
+)
+
+type fakePayments struct {
+	paymentsv1.UnimplementedPaymentsServiceServer
+}
+
+func (fakePayments) GetBalanceAt(_ context.Context, req *paymentsv1.GetBalanceAtRequest) (*paymentsv1.GetBalanceAtResponse, error) {
+	if req.GetUserId() != "u-1" {
+		return nil, status.Error(codes.NotFound, "account not found")
+	}
+	return &paymentsv1.GetBalanceAtResponse{Balance: 1200, Currency: "RUB", At: req.GetAt()}, nil
+}
+
+func (fakePayments) TopUp(_ context.Context, req *paymentsv1.TopUpRequest) (*paymentsv1.TopUpResponse, error) {
+	if req.GetAmount() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "amount must be > 0")
+	}
+	return &paymentsv1.TopUpResponse{Account: &paymentsv1.Account{UserId: req.GetUserId(), Balance: req.GetAmount(), Currency: "RUB"}}, nil
+}
+
+func newTestServer(t *testing.T) http.Handler {
+	t.Helper()
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := grpc.NewServer()
+	paymentsv1.RegisterPaymentsServiceServer(s, fakePayments{})
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	h, err := NewHandler(ctx, lis.Addr().String())
+	if err != nil {
+		t.Fatalf("NewHandler() error: %v", err)
+	}
+	return h
+}
+
+func serve(h http.Handler, method, path, body string) (int, string) {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	b, _ := io.ReadAll(rec.Body)
+	return rec.Code, string(b)
+}
+
+func TestGetBalanceAt(t *testing.T) {
+	h := newTestServer(t)
+	at := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	code, body := serve(h, http.MethodGet, "/v1/support/users/u-1/balance?at="+at.Format(time.RFC3339), "")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", code, body)
+	}
+	for _, want := range []string{`"balance":"1200"`, `"currency":"RUB"`, `"at":"2025-03-01T12:00:00Z"`} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("body %s does not contain %s", body, want)
+		}
+	}
+
+	code, body = serve(h, http.MethodGet, "/v1/support/users/u-2/balance?at="+at.Format(time.RFC3339), "")
+	if code != http.StatusNotFound || body != `{"user_id":"u-2","error":"account not found","code":"not_found"}`+"\n" {
+		t.Fatalf("missing account: status = %d, body = %s", code, body)
+	}
+}
+
+func TestTopUp(t *testing.T) {
+	h := newTestServer(t)
+
+	code, body := serve(h, http.MethodPost, "/v1/users/u-1/account/topup", `{"amount":"500"}`)
+	if code != http.StatusOK || !strings.Contains(body, `"balance":"500"`) {
+		t.Fatalf("status = %d, body = %s; want 200 with balance 500", code, body)
+	}
+
+	code, body = serve(h, http.MethodPost, "/v1/users/u-1/account/topup", `{"amount":"0"}`)
+	if code != http.StatusBadRequest || !strings.Contains(body, "amount must be") {
+		t.Fatalf("zero amount: status = %d, body = %s", code, body)
+	}
+
+	code, _ = serve(h, http.MethodPost, "/v1/users/u-1/account/topup", `{"amount":`)
+	if code != http.StatusBadRequest {
+		t.Fatalf("malformed body: status = %d, want 400", code)
+	}
+
+	code, _ = serve(h, http.MethodDelete, "/v1/users/u-1/account/topup", "")
+	if code != http.StatusMethodNotAllowed {
+		t.Fatalf("wrong method: status = %d, want 405", code)
+	}
+}
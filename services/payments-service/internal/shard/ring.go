@@ -0,0 +1,72 @@
+// Package shard maps user ids to database shards.
+package shard
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// vnodes is the number of points every shard takes on the ring; more points
+// spread users more evenly.
+const vnodes = 128
+
+// Ring is a consistent hash ring over shards 0..n-1. Adding shard n moves
+// only the users that land on its points, about 1/(n+1) of them; the rest
+// keep their shard.
+type Ring struct {
+	points []point
+	n      int
+}
+
+type point struct {
+	hash  uint64
+	shard int
+}
+
+// NewRing builds a ring of n shards; n below 1 is treated as 1.
+func NewRing(n int) *Ring {
+	if n < 1 {
+		n = 1
+	}
+	r := &Ring{points: make([]point, 0, n*vnodes), n: n}
+	for s := 0; s < n; s++ {
+		for v := 0; v < vnodes; v++ {
+			r.points = append(r.points, point{hash: hash("shard-" + strconv.Itoa(s) + "-" + strconv.Itoa(v)), shard: s})
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i].hash < r.points[j].hash })
+	return r
+}
+
+// Len returns the number of shards.
+func (r *Ring) Len() int {
+	return r.n
+}
+
+// Shard returns the shard that owns userID.
+func (r *Ring) Shard(userID string) int {
+	if r.n == 1 {
+		return 0
+	}
+	h := hash(userID)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.points[i].shard
+}
+
+// hash is FNV-1a followed by the splitmix64 finalizer; plain FNV leaves
+// similar short strings such as sequential ids close together on the ring.
+func hash(s string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(s))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,55 @@
+package shard
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestRingSingleShard(t *testing.T) {
+	r := NewRing(0)
+	if r.Len() != 1 || r.Shard("u1") != 0 {
+		t.Fatalf("Len = %d, Shard = %d, want 1, 0", r.Len(), r.Shard("u1"))
+	}
+}
+
+func TestRingSpreadsUsers(t *testing.T) {
+	r := NewRing(4)
+	counts := make([]int, 4)
+	for i := 0; i < 10000; i++ {
+		counts[r.Shard("user-"+strconv.Itoa(i))]++
+	}
+	for s, n := range counts {
+		if n < 1500 || n > 3500 {
+			t.Fatalf("shard %d got %d of 10000 users: %v", s, n, counts)
+		}
+	}
+}
+
+func TestRingIsStable(t *testing.T) {
+	a, b := NewRing(3), NewRing(3)
+	for i := 0; i < 1000; i++ {
+		id := "user-" + strconv.Itoa(i)
+		if a.Shard(id) != b.Shard(id) {
+			t.Fatalf("%s routed to %d and %d", id, a.Shard(id), b.Shard(id))
+		}
+	}
+}
+
+func TestRingGrowthMovesFewUsers(t *testing.T) {
+	before, after := NewRing(4), NewRing(5)
+	moved := 0
+	for i := 0; i < 10000; i++ {
+		id := "user-" + strconv.Itoa(i)
+		from, to := before.Shard(id), after.Shard(id)
+		if from != to {
+			if to != 4 {
+				t.Fatalf("%s moved from %d to %d, not to the new shard", id, from, to)
+			}
+			moved++
+		}
+	}
+	// About a fifth of the users belong to the new shard.
+	if moved < 1000 || moved > 3000 {
+		t.Fatalf("moved %d of 10000 users", moved)
+	}
+}
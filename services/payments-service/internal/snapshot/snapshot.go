@@ -0,0 +1,77 @@
+// Package snapshot materializes daily account balances from the ledger so
+// point-in-time balance queries only sum the deltas of a single day.
+package snapshot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+const day = 24 * time.Hour
+
+// Job takes a snapshot at every UTC midnight once grace has passed, leaving
+// time for transactions that started before midnight to commit. Snapshots are
+// idempotent, so several replicas may run the job at once.
+type Job struct {
+	q        db.Querier
+	interval time.Duration
+	grace    time.Duration
+	now      func() time.Time
+}
+
+// NewJob builds a job that checks for a due snapshot every interval.
+func NewJob(q db.Querier, interval, grace time.Duration) *Job {
+	slog.Default().With("service", "payments-service", "component", "snapshot").Info("balance snapshot job initialized", "interval", interval.String(), "grace", grace.String())
+	return &Job{q: q, interval: interval, grace: grace, now: time.Now}
+}
+
+// Cutoff returns the latest midnight (UTC) that is at least grace old.
+func Cutoff(now time.Time, grace time.Duration) time.Time {
+	return now.Add(-grace).UTC().Truncate(day)
+}
+
+func (j *Job) Run(ctx context.Context) error {
+	logger := slog.Default().With("service", "payments-service", "component", "snapshot")
+	t := time.NewTicker(j.interval)
+	defer t.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("balance snapshot failed", "err", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("balance snapshot job stopped")
+			return nil
+		case <-t.C:
+		}
+	}
+}
+
+// RunOnce snapshots all accounts at the current cutoff unless that snapshot
+// already exists, and returns the number of rows written.
+func (j *Job) RunOnce(ctx context.Context) (int64, error) {
+	logger := slog.Default().With("service", "payments-service", "component", "snapshot")
+	cutoff := Cutoff(j.now(), j.grace)
+
+	latest, err := j.q.LatestSnapshotAt(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if latest.InfinityModifier == pgtype.Finite && !latest.Time.Before(cutoff) {
+		return 0, nil
+	}
+
+	start := time.Now()
+	n, err := j.q.SnapshotBalances(ctx, pgtype.Timestamptz{Time: cutoff, Valid: true})
+	if err != nil {
+		return 0, err
+	}
+	logger.Info("balance snapshot taken", "snapshot_at", cutoff, "accounts", n, "duration", time.Since(start))
+	return n, nil
+}
@@ -0,0 +1,63 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	db "github.com/ilyaytrewq/payments-service/payments-service/internal/repo/postgres/db"
+)
+
+type fakeQuerier struct {
+	db.Querier
+
+	latest pgtype.Timestamptz
+	taken  []time.Time
+}
+
+func (f *fakeQuerier) LatestSnapshotAt(context.Context) (pgtype.Timestamptz, error) {
+	return f.latest, nil
+}
+
+func (f *fakeQuerier) SnapshotBalances(_ context.Context, at pgtype.Timestamptz) (int64, error) {
+	f.taken = append(f.taken, at.Time)
+	f.latest = at
+	return 3, nil
+}
+
+func TestCutoff(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 3, 0, 0, time.UTC)
+	if got, want := Cutoff(now, 5*time.Minute), time.Date(2026, 3, 9, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Cutoff() within grace = %s, want %s", got, want)
+	}
+	if got, want := Cutoff(now.Add(5*time.Minute), 5*time.Minute), time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Cutoff() after grace = %s, want %s", got, want)
+	}
+}
+
+func TestRunOnce(t *testing.T) {
+	q := &fakeQuerier{latest: pgtype.Timestamptz{Valid: true, InfinityModifier: pgtype.NegativeInfinity}}
+	j := NewJob(q, time.Hour, 5*time.Minute)
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	j.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	n, err := j.RunOnce(ctx)
+	if err != nil || n != 3 {
+		t.Fatalf("RunOnce() = %d, %v, want 3, nil", n, err)
+	}
+	if n, err := j.RunOnce(ctx); err != nil || n != 0 {
+		t.Fatalf("repeated RunOnce() = %d, %v, want 0, nil", n, err)
+	}
+
+	now = now.Add(day)
+	if _, err := j.RunOnce(ctx); err != nil {
+		t.Fatalf("next day RunOnce() error: %v", err)
+	}
+	want := []time.Time{time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)}
+	if len(q.taken) != len(want) || !q.taken[0].Equal(want[0]) || !q.taken[1].Equal(want[1]) {
+		t.Fatalf("snapshots = %v, want %v", q.taken, want)
+	}
+}
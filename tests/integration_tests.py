@@ -84,7 +84,7 @@ class IntegrationTests(unittest.TestCase):
         self.assertEqual(status, 201, msg=f"body={body!r}")
         resp = parse_json(body)
         self.assertEqual(resp.get("user_id"), user_id)
-        self.assertEqual(resp.get("balance"), 0)
+        self.assertEqual(resp.get("balance"), "0")
 
         amount = 500
         idem_topup = str(uuid.uuid4())
@@ -97,7 +97,7 @@ class IntegrationTests(unittest.TestCase):
         self.assertEqual(status, 200, msg=f"body={body!r}")
         resp = parse_json(body)
         self.assertEqual(resp.get("user_id"), user_id)
-        self.assertEqual(resp.get("balance"), amount)
+        self.assertEqual(resp.get("balance"), str(amount))
 
         status, body, _ = request(
             "GET",
@@ -107,7 +107,7 @@ class IntegrationTests(unittest.TestCase):
         self.assertEqual(status, 200, msg=f"body={body!r}")
         resp = parse_json(body)
         self.assertEqual(resp.get("user_id"), user_id)
-        self.assertEqual(resp.get("balance"), amount)
+        self.assertEqual(resp.get("balance"), str(amount))
 
         status, body, _ = request("GET", "/payments/account/balance")
         self.assertEqual(status, 400, msg=f"body={body!r}")
@@ -146,7 +146,7 @@ class IntegrationTests(unittest.TestCase):
         order_id = order.get("order_id")
         self.assertTrue(order_id)
         self.assertEqual(order.get("user_id"), user_id)
-        self.assertEqual(order.get("amount"), amount)
+        self.assertEqual(order.get("amount"), str(amount))
 
         status, body, _ = request(
             "GET",